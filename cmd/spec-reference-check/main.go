@@ -16,16 +16,36 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
 )
 
 type SpecReference struct {
 	File string
 	Line int
 	Path string
+
+	// Feature is the ID from the closest preceding "Feature:" comment in
+	// the same file, or empty when there is none.
+	Feature string
+}
+
+// specDoc is what references are checked against in a spec file.
+type specDoc struct {
+	// Features lists the front-matter "feature" followed by any extra IDs
+	// in "features" (for specs shared by several features).
+	Features []string
+
+	// Anchors holds the GitHub-style anchor of every heading.
+	Anchors map[string]bool
 }
 
 type SpecError struct {
@@ -49,6 +69,7 @@ func run(root string) error {
 	}
 
 	var specErrors []SpecError
+	docs := make(map[string]*specDoc)
 
 	for _, f := range files {
 		content, err := os.ReadFile(f) //nolint:gosec // G304: file path is from walkGoFiles, safe
@@ -75,7 +96,8 @@ func run(root string) error {
 			}
 
 			// Resolve spec path relative to root directory
-			specPath := filepath.Join(root, r.Path)
+			specRel, fragment, _ := strings.Cut(r.Path, "#")
+			specPath := filepath.Join(root, specRel)
 			if _, err := os.Stat(specPath); err != nil {
 				if os.IsNotExist(err) {
 					specErrors = append(specErrors, SpecError{
@@ -92,6 +114,43 @@ func run(root string) error {
 						Msg:  fmt.Sprintf("checking spec file: %v", err),
 					})
 				}
+				continue
+			}
+
+			doc, ok := docs[specPath]
+			if !ok {
+				doc, err = loadSpecDoc(specPath)
+				if err != nil {
+					specErrors = append(specErrors, SpecError{
+						File: r.File,
+						Line: r.Line,
+						Path: r.Path,
+						Msg:  fmt.Sprintf("reading spec file: %v", err),
+					})
+					continue
+				}
+				docs[specPath] = doc
+			}
+
+			if fragment != "" && !doc.Anchors[fragment] {
+				specErrors = append(specErrors, SpecError{
+					File: r.File,
+					Line: r.Line,
+					Path: r.Path,
+					Msg:  fmt.Sprintf("no heading with anchor #%s in spec", fragment),
+				})
+			}
+
+			// The reverse link: the spec must claim the referencing feature.
+			// Specs without front-matter features are not checked.
+			if r.Feature != "" && len(doc.Features) > 0 && !slices.Contains(doc.Features, r.Feature) {
+				specErrors = append(specErrors, SpecError{
+					File: r.File,
+					Line: r.Line,
+					Path: r.Path,
+					Msg: fmt.Sprintf("spec front-matter does not list feature %s (lists %s)",
+						r.Feature, strings.Join(doc.Features, ", ")),
+				})
 			}
 		}
 	}
@@ -158,6 +217,7 @@ func extractSpecReferences(filePath string, content []byte) []SpecReference {
 
 	scanner := bufio.NewScanner(strings.NewReader(string(content)))
 	lineNum := 0
+	feature := ""
 
 	for scanner.Scan() {
 		lineNum++
@@ -177,6 +237,12 @@ func extractSpecReferences(filePath string, content []byte) []SpecReference {
 
 		// Case-insensitive "Spec:" prefix
 		lower := strings.ToLower(trimmed)
+		if strings.HasPrefix(lower, "feature:") {
+			if fields := strings.Fields(trimmed[len("feature:"):]); len(fields) > 0 {
+				feature = fields[0]
+			}
+			continue
+		}
 		const prefix = "spec:"
 		if !strings.HasPrefix(lower, prefix) {
 			continue
@@ -186,17 +252,19 @@ func extractSpecReferences(filePath string, content []byte) []SpecReference {
 		pathPart := strings.TrimSpace(trimmed[len(prefix):])
 		if pathPart == "" {
 			refs = append(refs, SpecReference{
-				File: filePath,
-				Line: lineNum,
-				Path: "",
+				File:    filePath,
+				Line:    lineNum,
+				Path:    "",
+				Feature: feature,
 			})
 			continue
 		}
 
 		refs = append(refs, SpecReference{
-			File: filePath,
-			Line: lineNum,
-			Path: pathPart,
+			File:    filePath,
+			Line:    lineNum,
+			Path:    pathPart,
+			Feature: feature,
 		})
 	}
 
@@ -208,13 +276,18 @@ func validateSpecPath(path string) error {
 		return fmt.Errorf("empty path")
 	}
 
+	path, fragment, hasFragment := strings.Cut(path, "#")
+	if hasFragment && fragment == "" {
+		return fmt.Errorf("empty anchor after '#'")
+	}
+
 	if !strings.HasPrefix(path, "spec/") {
 		return fmt.Errorf("must start with 'spec/' (got %q)", path)
 	}
 
 	// Check for invalid characters before checking suffix
 	// (since invalid chars might include newlines/tabs that affect suffix check)
-	if strings.ContainsAny(path, " \t\r\n{}\"") {
+	if strings.ContainsAny(path+fragment, " \t\r\n{}\"#") {
 		return fmt.Errorf("contains invalid characters (spaces, control chars, { } \")")
 	}
 
@@ -225,3 +298,75 @@ func validateSpecPath(path string) error {
 	// Normalized, simple rule set is enough for now.
 	return nil
 }
+
+// loadSpecDoc reads the front-matter features and heading anchors of the
+// spec at path.
+func loadSpecDoc(path string) (*specDoc, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // G304: path is a validated spec/ reference
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &specDoc{Anchors: make(map[string]bool)}
+
+	body := content
+	if rest, ok := bytes.CutPrefix(content, []byte("---\n")); ok {
+		if frontMatter, after, found := bytes.Cut(rest, []byte("\n---\n")); found {
+			var fm struct {
+				Feature  string   `yaml:"feature"`
+				Features []string `yaml:"features"`
+			}
+			if err := yaml.Unmarshal(frontMatter, &fm); err != nil {
+				return nil, fmt.Errorf("parsing front-matter: %w", err)
+			}
+			if fm.Feature != "" {
+				doc.Features = append(doc.Features, fm.Feature)
+			}
+			doc.Features = append(doc.Features, fm.Features...)
+			body = after
+		}
+	}
+
+	seen := make(map[string]int)
+	inFence := false
+	for _, line := range strings.Split(string(body), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		text := strings.TrimLeft(line, "#")
+		if len(line)-len(text) > 6 || (text != "" && text[0] != ' ' && text[0] != '\t') {
+			continue
+		}
+
+		anchor := headingAnchor(text)
+		if n := seen[anchor]; n > 0 {
+			doc.Anchors[anchor+"-"+strconv.Itoa(n)] = true
+		} else {
+			doc.Anchors[anchor] = true
+		}
+		seen[anchor]++
+	}
+
+	return doc, nil
+}
+
+// headingAnchor returns the anchor GitHub generates for a heading:
+// lowercased, spaces as hyphens, and punctuation other than '-' and '_'
+// dropped.
+func headingAnchor(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
@@ -471,3 +471,124 @@ func test() {
 		t.Errorf("run(%q) = %v, want nil (testdata should be ignored)", tmpDir, err)
 	}
 }
+
+func TestExtractSpecReferences_RecordsFeature(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`package main
+
+// Spec: spec/core/orphan.md
+
+// Feature: CLI_DEPLOY
+// Spec: spec/commands/deploy.md
+
+// Feature: CORE_STATE
+// Spec: spec/core/state.md
+`)
+
+	refs := extractSpecReferences("test.go", content)
+	want := []string{"", "CLI_DEPLOY", "CORE_STATE"}
+	if len(refs) != len(want) {
+		t.Fatalf("expected %d references, got %d", len(want), len(refs))
+	}
+	for i, feature := range want {
+		if refs[i].Feature != feature {
+			t.Errorf("refs[%d].Feature = %q, want %q", i, refs[i].Feature, feature)
+		}
+	}
+}
+
+func TestValidateSpecPath_Fragments(t *testing.T) {
+	t.Parallel()
+
+	if err := validateSpecPath("spec/commands/deploy.md#exit-codes"); err != nil {
+		t.Errorf("validateSpecPath() with anchor = %v, want nil", err)
+	}
+	if err := validateSpecPath("spec/commands/deploy.md#"); err == nil || !strings.Contains(err.Error(), "empty anchor") {
+		t.Errorf("validateSpecPath() with empty anchor = %v, want error about empty anchor", err)
+	}
+	if err := validateSpecPath("spec/commands/deploy#exit.md"); err == nil {
+		t.Error("validateSpecPath() with anchor before suffix = nil, want error")
+	}
+}
+
+func TestHeadingAnchor(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		" Exit Codes":       "exit-codes",
+		" 2. Bundle Format": "2-bundle-format",
+		" `stagecraft diff` – Compare Two Envs": "stagecraft-diff--compare-two-envs",
+		" DEPLOY_BUNDLE - Deploy Bundles":       "deploy_bundle---deploy-bundles",
+		" Tracing (OpenTelemetry), v1.0!":       "tracing-opentelemetry-v10",
+	}
+	for heading, want := range tests {
+		if got := headingAnchor(heading); got != want {
+			t.Errorf("headingAnchor(%q) = %q, want %q", heading, got, want)
+		}
+	}
+}
+
+// writeSpecProject writes spec/commands/deploy.md with the given content
+// and main.go referencing it as CLI_DEPLOY with spec reference ref.
+func writeSpecProject(t *testing.T, spec, ref string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	specDir := filepath.Join(tmpDir, "spec", "commands")
+	if err := os.MkdirAll(specDir, 0o700); err != nil {
+		t.Fatalf("failed to create spec dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "deploy.md"), []byte(spec), 0o600); err != nil {
+		t.Fatalf("failed to create spec file: %v", err)
+	}
+
+	goContent := "package main\n\n// Feature: CLI_DEPLOY\n// Spec: " + ref + "\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(goContent), 0o600); err != nil {
+		t.Fatalf("failed to create go file: %v", err)
+	}
+	return tmpDir
+}
+
+const deploySpec = "---\nfeature: CLI_DEPLOY\nstatus: done\n---\n# Deploy\n\n## Exit Codes\n\n```md\n## Not A Heading\n```\n\n## Exit Codes\n"
+
+func TestRun_ValidAnchors(t *testing.T) {
+	t.Parallel()
+
+	for _, ref := range []string{
+		"spec/commands/deploy.md#deploy",
+		"spec/commands/deploy.md#exit-codes",
+		"spec/commands/deploy.md#exit-codes-1",
+	} {
+		if err := run(writeSpecProject(t, deploySpec, ref)); err != nil {
+			t.Errorf("run() with %s = %v, want nil", ref, err)
+		}
+	}
+}
+
+func TestRun_MissingAnchor(t *testing.T) {
+	t.Parallel()
+
+	for _, ref := range []string{
+		"spec/commands/deploy.md#errors",
+		"spec/commands/deploy.md#not-a-heading",
+	} {
+		if err := run(writeSpecProject(t, deploySpec, ref)); err == nil {
+			t.Errorf("run() with %s = nil, want error", ref)
+		}
+	}
+}
+
+func TestRun_SpecDoesNotListFeature(t *testing.T) {
+	t.Parallel()
+
+	spec := "---\nfeature: CLI_PLAN\n---\n# Plan\n"
+	if err := run(writeSpecProject(t, spec, "spec/commands/deploy.md")); err == nil {
+		t.Error("run() = nil, want error for a spec bound to another feature")
+	}
+
+	shared := "---\nfeature: CLI_PLAN\nfeatures:\n  - CLI_DEPLOY\n---\n# Plan\n"
+	if err := run(writeSpecProject(t, shared, "spec/commands/deploy.md")); err != nil {
+		t.Errorf("run() = %v, want nil for a spec listing the feature in features", err)
+	}
+}
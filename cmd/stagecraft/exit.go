@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/commands"
+	"stagecraft/internal/failure"
+)
+
+// Feature: CORE_FAILURE_LENS
+// Spec: spec/core/failure-lens.md
+
+// exitCoder is implemented by errors that carry their own exit code, e.g.
+// from governance commands that already reported the failure.
+type exitCoder interface {
+	ExitCode() int
+}
+
+// exitCodeFor maps a command error to the process exit code: the error's
+// own code when it has one, otherwise the code governed for its failure
+// class (see GOV_CLI_EXIT_CODES).
+func exitCodeFor(err error) int {
+	var ec exitCoder
+	if errors.As(err, &ec) {
+		return ec.ExitCode()
+	}
+	return failure.Classify(err).ExitCode()
+}
+
+// reportFailure writes err with its failure lens diagnosis to w, in the
+// --error-format resolved for cmd, and returns the exit code. cmd is nil
+// when the failing command is unknown. Errors with their own exit code are
+// not reported again.
+func reportFailure(w io.Writer, cmd *cobra.Command, err error) int {
+	code := exitCodeFor(err)
+	var ec exitCoder
+	if errors.As(err, &ec) {
+		return code
+	}
+
+	d := failure.Classify(err)
+
+	subsystem := "stagecraft"
+	formatName := ""
+	if cmd != nil {
+		if flags, resolveErr := commands.ResolveFlags(cmd, nil); resolveErr == nil {
+			formatName = flags.ErrorFormat
+		}
+		if cmd.HasParent() {
+			subsystem = strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+		}
+	}
+	if formatName == "" {
+		formatName = os.Getenv("STAGECRAFT_ERROR_FORMAT")
+	}
+
+	format, formatErr := failure.ParseFormat(formatName)
+	if formatErr != nil {
+		_, _ = fmt.Fprintf(w, "warning: %v; reporting as text\n", formatErr)
+		format = failure.FormatText
+	}
+
+	if format == failure.FormatJSON {
+		_ = failure.WriteJSON(w, failure.NewEnvelope(subsystem, err, d))
+	} else {
+		failure.Report(w, err, d)
+	}
+	return code
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"stagecraft/internal/cli"
+	"stagecraft/internal/failure"
+	"stagecraft/internal/providers/cloud/digitalocean"
+	"stagecraft/pkg/config"
+)
+
+// Feature: CORE_FAILURE_LENS
+// Spec: spec/core/failure-lens.md
+
+type governedError struct{ code int }
+
+func (e governedError) Error() string { return "checks failed" }
+func (e governedError) ExitCode() int { return e.code }
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"user input", failure.Errorf(failure.ClassUserInput, "bad --limit"), 1},
+		{"config", fmt.Errorf("plan: %w", config.ErrConfigNotFound), 1},
+		{"provider", fmt.Errorf("hosts: %w: boom", digitalocean.ErrAPIError), 2},
+		{"transient", fmt.Errorf("waiting: %w", context.DeadlineExceeded), 2},
+		{"internal", failure.Wrap(failure.ClassInternalInvariant, errors.New("nil plan")), 3},
+		{"internal wins", fmt.Errorf("%w: %w", failure.ErrUserInput, failure.ErrInternalInvariant), 3},
+		{"unclassified", errors.New("something odd"), 3},
+		{"own exit code", fmt.Errorf("gov: %w", governedError{code: 4}), 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeFor(tt.err); got != tt.want {
+				t.Errorf("exitCodeFor(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportFailure_JSON(t *testing.T) {
+	root := cli.NewRootCommand()
+	root.SetArgs([]string{"compose", "diff", "--error-format", "json"})
+	cmd, _ := root.ExecuteC()
+
+	var buf bytes.Buffer
+	code := reportFailure(&buf, cmd, fmt.Errorf("compose diff: %w", digitalocean.ErrRateLimit))
+	if code != 2 {
+		t.Errorf("reportFailure() = %d, want 2", code)
+	}
+
+	var env failure.Envelope
+	if err := json.Unmarshal(buf.Bytes(), &env); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if env.Subsystem != "compose diff" || env.FailureClass != failure.ClassTransientEnvironment {
+		t.Errorf("envelope = %+v, want compose diff, transient_environment", env)
+	}
+}
+
+func TestReportFailure_OwnExitCodeNotReported(t *testing.T) {
+	var buf bytes.Buffer
+	if code := reportFailure(&buf, nil, governedError{code: 4}); code != 4 {
+		t.Errorf("reportFailure() = %d, want 4", code)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
@@ -16,24 +16,49 @@ See https://www.gnu.org/licenses/ for license details.
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"stagecraft/internal/buildinfo"
 	"stagecraft/internal/cli"
+	"stagecraft/internal/failure"
+	"stagecraft/internal/telemetry"
 )
 
+// tracingShutdownTimeout bounds how long exiting waits to flush spans.
+const tracingShutdownTimeout = 5 * time.Second
+
 func main() {
-	rootCmd := cli.NewRootCommand()
+	os.Exit(run())
+}
 
-	if err := rootCmd.Execute(); err != nil {
-		// Prefer explicit exit codes when available (e.g. governance commands).
-		type exitCoder interface {
-			ExitCode() int
+func run() (code int) {
+	shutdown, err := telemetry.Setup(context.Background(), buildinfo.Version())
+	if err != nil {
+		// Tracing is best-effort; never block the command on it.
+		fmt.Fprintf(os.Stderr, "warning: tracing disabled: %v\n", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: flushing traces: %v\n", err)
 		}
-		if ec, ok := err.(exitCoder); ok {
-			os.Exit(ec.ExitCode())
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			code = reportFailure(os.Stderr, nil, failure.Errorf(failure.ClassInternalInvariant, "internal error: panic: %v", r))
 		}
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	}()
+
+	rootCmd := cli.NewRootCommand()
+
+	if cmd, err := rootCmd.ExecuteC(); err != nil {
+		return reportFailure(os.Stderr, cmd, err)
 	}
+
+	return failure.ExitSuccess
 }
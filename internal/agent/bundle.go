@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package agent
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"stagecraft/internal/buildinfo"
+	"stagecraft/internal/plansign"
+	"stagecraft/pkg/engine"
+)
+
+// Feature: AGENT_DAEMON
+// Spec: spec/engine/agent-daemon.md
+
+// SecretEnv is the environment variable holding the shared secret used to
+// sign and verify plan bundles.
+const SecretEnv = "STAGECRAFT_AGENT_SECRET"
+
+// BundleRequiresVersion is the oldest Stagecraft release whose agent
+// executes the bundles this build sends. Raise it with every change to
+// host plans or bundles older agents would misread.
+const BundleRequiresVersion = "0.1.0"
+
+// ErrBadSignature is returned when a bundle's signature does not verify.
+var ErrBadSignature = errors.New("plan bundle signature is invalid")
+
+// Bundle is a HostPlan together with its signature, as sent to an agent.
+type Bundle struct {
+	HostPlan engine.HostPlan `json:"hostPlan"`
+	// Signature is "hmac-sha256:<hex>" over the HostPlan's JSON encoding.
+	Signature string `json:"signature"`
+	// PlanSignature is an optional ed25519 signature over the HostPlan's
+	// content address (engine.HashHostPlan).
+	PlanSignature *engine.PlanSignature `json:"planSignature,omitempty"`
+
+	// StagecraftVersion is the Stagecraft that sent the bundle, and
+	// RequiresStagecraft the oldest agent that may execute it.
+	StagecraftVersion  string `json:"stagecraftVersion,omitempty"`
+	RequiresStagecraft string `json:"requiresStagecraft,omitempty"`
+}
+
+// SignBundle signs plan with secret.
+// nolint:gocritic // passed by value intentionally; treated as immutable and keeps call sites simple.
+func SignBundle(plan engine.HostPlan, secret []byte) (*Bundle, error) {
+	sig, err := bundleSignature(plan, secret)
+	if err != nil {
+		return nil, err
+	}
+	return &Bundle{
+		HostPlan:           plan,
+		Signature:          sig,
+		StagecraftVersion:  buildinfo.Version(),
+		RequiresStagecraft: BundleRequiresVersion,
+	}, nil
+}
+
+// CheckVersion fails with buildinfo.ErrVersionSkew when the bundle in data
+// requires a newer agent than this one. It only reads the version fields,
+// so it reports skew before fields this build does not know break
+// decoding or the signature.
+func CheckVersion(data []byte) error {
+	var stamp struct {
+		StagecraftVersion  string `json:"stagecraftVersion"`
+		RequiresStagecraft string `json:"requiresStagecraft"`
+	}
+	if json.Unmarshal(data, &stamp) != nil {
+		return nil
+	}
+	return buildinfo.CheckRequired("plan bundle", stamp.StagecraftVersion, stamp.RequiresStagecraft)
+}
+
+// Verify checks the bundle's signature against secret.
+func (b *Bundle) Verify(secret []byte) error {
+	want, err := bundleSignature(b.HostPlan, secret)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(want), []byte(b.Signature)) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// SignPlan attaches an ed25519 signature over the HostPlan's content address.
+func (b *Bundle) SignPlan(key ed25519.PrivateKey) error {
+	digest, err := engine.HashHostPlan(&b.HostPlan)
+	if err != nil {
+		return fmt.Errorf("hashing host plan: %w", err)
+	}
+	b.PlanSignature = plansign.Sign(digest, key)
+	return nil
+}
+
+// VerifyPlan checks the bundle's plan signature against key. It returns
+// plansign.ErrUnsigned when the bundle carries no plan signature.
+func (b *Bundle) VerifyPlan(key ed25519.PublicKey) error {
+	digest, err := engine.HashHostPlan(&b.HostPlan)
+	if err != nil {
+		return fmt.Errorf("hashing host plan: %w", err)
+	}
+	return plansign.Verify(digest, b.PlanSignature, key)
+}
+
+// nolint:gocritic // passed by value intentionally; treated as immutable and keeps call sites simple.
+func bundleSignature(plan engine.HostPlan, secret []byte) (string, error) {
+	if len(secret) == 0 {
+		return "", fmt.Errorf("agent secret is empty; set %s", SecretEnv)
+	}
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return "", fmt.Errorf("encoding host plan: %w", err)
+	}
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(data)
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stagecraft/internal/compose"
+	"stagecraft/pkg/engine"
+	"stagecraft/pkg/engine/inputs"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: AGENT_DAEMON
+// Spec: spec/engine/agent-daemon.md
+
+// ComposeExecutor runs apply_compose steps with `docker compose up` on the
+// local host.
+type ComposeExecutor struct {
+	// Runner executes docker. Nil uses executil.NewRunner().
+	Runner executil.Runner
+	// WorkDir is the directory compose paths resolve against.
+	WorkDir string
+}
+
+// Execute applies the compose file named in the step inputs.
+// nolint:gocritic // passed by value intentionally; treated as immutable and keeps call sites simple.
+func (c *ComposeExecutor) Execute(ctx context.Context, step engine.HostPlanStep, inputsJSON []byte) error {
+	// Decoding normalizes paths, rejecting absolute and ".." paths so steps
+	// stay inside WorkDir.
+	in, err := inputs.DecodeApplyCompose(inputsJSON)
+	if err != nil {
+		return err
+	}
+
+	composePath := filepath.Join(c.WorkDir, filepath.FromSlash(in.ComposePath))
+
+	if in.ExpectedComposeHash != "" {
+		if err := verifyComposeHash(composePath, in.ExpectedComposeHash); err != nil {
+			return err
+		}
+	}
+
+	args := []string{"compose", "-f", composePath, "-p", in.ProjectName, "up"}
+	if *in.Detach {
+		args = append(args, "-d")
+	}
+	if *in.Pull {
+		args = append(args, "--pull", "always")
+	}
+	args = append(args, in.Services...)
+
+	runner := c.Runner
+	if runner == nil {
+		runner = executil.NewRunner()
+	}
+	cmd := executil.NewCommand("docker", args...)
+	cmd.Dir = c.WorkDir
+
+	result, err := runner.Run(ctx, cmd)
+	if err != nil {
+		var stderr string
+		if result != nil {
+			stderr = strings.TrimSpace(string(result.Stderr))
+		}
+		if stderr != "" {
+			err = fmt.Errorf("docker compose up: %w: %s", err, stderr)
+		} else {
+			err = fmt.Errorf("docker compose up: %w", err)
+		}
+		// A missing docker binary or expired deadline keeps its own class.
+		if engine.ClassOf(err) == engine.FailureUnclassified {
+			err = engine.Classify(engine.FailureProviderFailure, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// verifyComposeHash checks the file at path against a sha256 hex digest of
+// its content, ignoring the config-hash header Stagecraft writes.
+func verifyComposeHash(path, want string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // path comes from a verified plan
+	if err != nil {
+		return fmt.Errorf("reading compose file: %w", err)
+	}
+	if got := compose.ContentHash(data); got != want {
+		return fmt.Errorf("compose file %s hash mismatch: expected %s, got %s", path, want, got)
+	}
+	return nil
+}
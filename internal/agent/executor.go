@@ -15,8 +15,14 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
+	"stagecraft/internal/telemetry"
 	"stagecraft/pkg/engine"
 )
 
@@ -24,6 +30,15 @@ import (
 type Executor struct {
 	// executors maps StepAction to action-specific executors
 	executors map[engine.StepAction]StepExecutor
+
+	// store records succeeded steps; nil disables idempotency skipping
+	store IdempotencyStore
+
+	// timeouts bounds each attempt of a step; zero means no limit
+	timeouts engine.StepTimeouts
+
+	// sleep waits between retry attempts; replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
 }
 
 // StepExecutor executes a single step.
@@ -35,6 +50,7 @@ type StepExecutor interface {
 func NewExecutor() *Executor {
 	return &Executor{
 		executors: make(map[engine.StepAction]StepExecutor),
+		sleep:     sleepContext,
 	}
 }
 
@@ -43,16 +59,50 @@ func (e *Executor) RegisterExecutor(action engine.StepAction, executor StepExecu
 	e.executors[action] = executor
 }
 
+// SetIdempotencyStore enables skipping steps whose idempotency key the store
+// has already recorded as succeeded, and records each newly succeeded step.
+func (e *Executor) SetIdempotencyStore(store IdempotencyStore) {
+	e.store = store
+}
+
+// SetStepTimeouts bounds how long each attempt of a step may run. A step
+// whose last attempt times out is reported as timed out.
+func (e *Executor) SetStepTimeouts(timeouts engine.StepTimeouts) {
+	e.timeouts = timeouts
+}
+
 // ExecuteHostPlan executes a HostPlan step by step, respecting dependencies.
 // Steps are executed in topological order based on DependsOn relationships.
+// The plan and each step are traced as spans (see spec/core/tracing.md).
 // nolint:gocritic // passed by value intentionally; treated as immutable and keeps call sites simple.
-func (e *Executor) ExecuteHostPlan(ctx context.Context, plan engine.HostPlan) (*engine.ExecutionReport, error) {
-	report := &engine.ExecutionReport{
+func (e *Executor) ExecuteHostPlan(ctx context.Context, plan engine.HostPlan) (report *engine.ExecutionReport, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "hostplan",
+		telemetry.AttrPlanID.String(plan.PlanID),
+		telemetry.AttrHost.String(plan.Host.LogicalID),
+	)
+	defer func() {
+		if err == nil && report.Status == engine.ExecStatusFailed {
+			telemetry.EndSpan(span, errors.New("host plan failed"))
+			return
+		}
+		telemetry.EndSpan(span, err)
+	}()
+
+	report = &engine.ExecutionReport{
 		PlanID: plan.PlanID,
 		Status: engine.ExecStatusSucceeded,
 		Steps:  make([]engine.StepExecution, 0, len(plan.Steps)),
 	}
 
+	for i := range plan.Steps {
+		if plan.Steps[i].Retry == nil {
+			continue
+		}
+		if err := plan.Steps[i].Retry.Validate(); err != nil {
+			return nil, fmt.Errorf("step %q has invalid retry policy: %w", plan.Steps[i].ID, err)
+		}
+	}
+
 	// Track completed steps
 	completed := make(map[string]bool, len(plan.Steps))
 	stepMap := make(map[string]engine.HostPlanStep, len(plan.Steps))
@@ -77,6 +127,10 @@ func (e *Executor) ExecuteHostPlan(ctx context.Context, plan engine.HostPlan) (*
 			Status: engine.StepStatusRunning,
 		}
 
+		stepCtx, stepSpan := telemetry.StartSpan(ctx, "step "+string(step.Action),
+			telemetry.AttrStepID.String(step.ID),
+			telemetry.AttrAction.String(string(step.Action)),
+		)
 		executor, ok := e.executors[step.Action]
 		if !ok {
 			stepExec.Status = engine.StepStatusSkipped
@@ -85,28 +139,111 @@ func (e *Executor) ExecuteHostPlan(ctx context.Context, plan engine.HostPlan) (*
 				Message: fmt.Sprintf("no executor registered for action %q", step.Action),
 			}
 			report.Status = engine.ExecStatusPartial
-		} else {
-			err := executor.Execute(ctx, step, step.Inputs)
-			if err != nil {
-				stepExec.Status = engine.StepStatusFailed
-				stepExec.Error = &engine.ExecutionError{
-					Code:    "EXECUTION_ERROR",
-					Message: err.Error(),
-				}
-				report.Status = engine.ExecStatusFailed
-			} else {
-				stepExec.Status = engine.StepStatusSucceeded
-			}
+		} else if err := e.executeStep(stepCtx, plan.PlanID, step, executor, &stepExec); err != nil {
+			telemetry.EndSpan(stepSpan, err)
+			return nil, err
+		}
+		endStepSpan(stepSpan, &stepExec)
+		stepFailed := stepExec.Status == engine.StepStatusFailed || stepExec.Status == engine.StepStatusTimedOut
+		if stepFailed {
+			report.Status = engine.ExecStatusFailed
 		}
 
 		completed[step.ID] = true
 		report.Steps = append(report.Steps, stepExec)
 
 		// If step failed and we're in strict mode, stop execution
-		if stepExec.Status == engine.StepStatusFailed {
+		if stepFailed {
 			break
 		}
 	}
 
 	return report, nil
 }
+
+// executeStep runs step with its retry policy and records the outcome in
+// stepExec. Steps already recorded as succeeded in the idempotency store are
+// skipped. Only idempotency store failures are returned as errors.
+// nolint:gocritic // passed by value intentionally; treated as immutable and keeps call sites simple.
+func (e *Executor) executeStep(ctx context.Context, planID string, step engine.HostPlanStep, executor StepExecutor, stepExec *engine.StepExecution) error {
+	key := engine.IdempotencyKey(planID, step)
+	stepExec.Meta = map[string]string{"idempotencyKey": key}
+
+	if e.store != nil {
+		done, err := e.store.Succeeded(ctx, key)
+		if err != nil {
+			return fmt.Errorf("checking idempotency key for step %q: %w", step.ID, err)
+		}
+		if done {
+			stepExec.Status = engine.StepStatusSkipped
+			stepExec.Meta["skipReason"] = "already_succeeded"
+			return nil
+		}
+	}
+
+	attempts := step.Retry.Attempts()
+	timeout := e.timeouts.For(step.Action)
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		err = engine.RunWithTimeout(ctx, timeout, func(ctx context.Context) error {
+			return executor.Execute(ctx, step, step.Inputs)
+		})
+		if err == nil || attempt >= attempts || !step.Retry.Retryable(engine.ClassOf(err)) {
+			break
+		}
+		if sleepErr := e.sleep(ctx, step.Retry.Backoff(attempt)); sleepErr != nil {
+			break
+		}
+	}
+	stepExec.Meta["attempts"] = strconv.Itoa(attempt)
+
+	if err != nil {
+		stepExec.Status = engine.StepStatusFailed
+		code := "EXECUTION_ERROR"
+		if engine.IsTimeout(err) {
+			stepExec.Status = engine.StepStatusTimedOut
+			code = "TIMEOUT"
+		}
+		stepExec.Error = &engine.ExecutionError{
+			Code:    code,
+			Class:   engine.ClassOf(err),
+			Message: err.Error(),
+		}
+		return nil
+	}
+
+	stepExec.Status = engine.StepStatusSucceeded
+	if e.store != nil {
+		if err := e.store.RecordSuccess(ctx, key, StepRecord{PlanID: planID, StepID: step.ID}); err != nil {
+			return fmt.Errorf("recording idempotency key for step %q: %w", step.ID, err)
+		}
+	}
+	return nil
+}
+
+// endStepSpan records the step's outcome on span and ends it. Failed and
+// timed-out steps end with the step's error.
+func endStepSpan(span trace.Span, stepExec *engine.StepExecution) {
+	span.SetAttributes(telemetry.AttrStepStatus.String(string(stepExec.Status)))
+	if attempts, err := strconv.Atoi(stepExec.Meta["attempts"]); err == nil {
+		span.SetAttributes(telemetry.AttrAttempts.Int(attempts))
+	}
+	var err error
+	if stepExec.Status == engine.StepStatusFailed || stepExec.Status == engine.StepStatusTimedOut {
+		err = errors.New(stepExec.Error.Message)
+	}
+	telemetry.EndSpan(span, err)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"stagecraft/internal/telemetry"
+	"stagecraft/pkg/engine"
+)
+
+// Feature: ENGINE_STEP_RETRY
+// Spec: spec/engine/step-retry.md
+
+// flakyExecutor fails with err for the first failures calls.
+type flakyExecutor struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyExecutor) Execute(context.Context, engine.HostPlanStep, []byte) error { //nolint:gocritic // matches StepExecutor
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func retryPlan(policy *engine.RetryPolicy) engine.HostPlan {
+	return engine.HostPlan{
+		Version: engine.HostPlanSchemaVersion,
+		PlanID:  "plan-1",
+		Host:    engine.HostRef{LogicalID: "host-a"},
+		Steps: []engine.HostPlanStep{{
+			ID:     "apply",
+			Action: engine.StepActionApplyCompose,
+			Inputs: json.RawMessage(`{}`),
+			Retry:  policy,
+		}},
+	}
+}
+
+func newRetryExecutor(step StepExecutor) (*Executor, *[]time.Duration) {
+	var waits []time.Duration
+	e := NewExecutor()
+	e.RegisterExecutor(engine.StepActionApplyCompose, step)
+	e.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return e, &waits
+}
+
+func TestExecutor_RetriesRetryableFailures(t *testing.T) {
+	step := &flakyExecutor{failures: 2, err: engine.Classify(engine.FailureTransientEnvironment, errors.New("timeout"))}
+	e, waits := newRetryExecutor(step)
+
+	report, err := e.ExecuteHostPlan(context.Background(), retryPlan(&engine.RetryPolicy{MaxAttempts: 3, InitialBackoffMillis: 10}))
+	if err != nil {
+		t.Fatalf("ExecuteHostPlan() error = %v", err)
+	}
+	if report.Status != engine.ExecStatusSucceeded {
+		t.Fatalf("expected success after retries, got %+v", report.Steps)
+	}
+	if step.calls != 3 || report.Steps[0].Meta["attempts"] != "3" {
+		t.Errorf("expected 3 attempts, got calls=%d meta=%v", step.calls, report.Steps[0].Meta)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 10*time.Millisecond || (*waits)[1] != 20*time.Millisecond {
+		t.Errorf("unexpected backoff waits %v", *waits)
+	}
+}
+
+func TestExecutor_DoesNotRetryNonRetryableClass(t *testing.T) {
+	step := &flakyExecutor{failures: 5, err: engine.Classify(engine.FailureConfigInvalid, errors.New("bad config"))}
+	e, _ := newRetryExecutor(step)
+
+	report, err := e.ExecuteHostPlan(context.Background(), retryPlan(&engine.RetryPolicy{MaxAttempts: 3}))
+	if err != nil {
+		t.Fatalf("ExecuteHostPlan() error = %v", err)
+	}
+	if step.calls != 1 {
+		t.Errorf("expected a single attempt, got %d", step.calls)
+	}
+	stepErr := report.Steps[0].Error
+	if report.Status != engine.ExecStatusFailed || stepErr == nil || stepErr.Class != engine.FailureConfigInvalid {
+		t.Errorf("expected classified failure, got status=%s error=%+v", report.Status, stepErr)
+	}
+}
+
+func TestExecutor_StopsAfterMaxAttempts(t *testing.T) {
+	step := &flakyExecutor{failures: 10, err: engine.Classify(engine.FailureTransientEnvironment, errors.New("timeout"))}
+	e, _ := newRetryExecutor(step)
+
+	report, err := e.ExecuteHostPlan(context.Background(), retryPlan(&engine.RetryPolicy{MaxAttempts: 2}))
+	if err != nil {
+		t.Fatalf("ExecuteHostPlan() error = %v", err)
+	}
+	if step.calls != 2 || report.Status != engine.ExecStatusFailed {
+		t.Errorf("expected 2 failed attempts, got calls=%d status=%s", step.calls, report.Status)
+	}
+}
+
+func TestExecutor_RejectsInvalidRetryPolicy(t *testing.T) {
+	e, _ := newRetryExecutor(&flakyExecutor{})
+	if _, err := e.ExecuteHostPlan(context.Background(), retryPlan(&engine.RetryPolicy{MaxAttempts: -1})); err == nil {
+		t.Fatal("expected invalid retry policy error")
+	}
+}
+
+func TestExecutor_SkipsStepsAlreadySucceeded(t *testing.T) {
+	store := NewFileIdempotencyStore(filepath.Join(t.TempDir(), "state", "agent-state.json"))
+	step := &flakyExecutor{}
+	e, _ := newRetryExecutor(step)
+	e.SetIdempotencyStore(store)
+
+	plan := retryPlan(nil)
+	if _, err := e.ExecuteHostPlan(context.Background(), plan); err != nil {
+		t.Fatalf("first run error = %v", err)
+	}
+
+	// A fresh executor sharing the store simulates an agent restart.
+	e2, _ := newRetryExecutor(step)
+	e2.SetIdempotencyStore(NewFileIdempotencyStore(store.path))
+	report, err := e2.ExecuteHostPlan(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("second run error = %v", err)
+	}
+
+	if step.calls != 1 {
+		t.Errorf("expected step to run once, ran %d times", step.calls)
+	}
+	got := report.Steps[0]
+	if got.Status != engine.StepStatusSkipped || got.Meta["skipReason"] != "already_succeeded" {
+		t.Errorf("expected skipped step, got %+v", got)
+	}
+	if report.Status != engine.ExecStatusSucceeded {
+		t.Errorf("expected succeeded report, got %s", report.Status)
+	}
+
+	// Changed inputs produce a new key and run again.
+	plan.Steps[0].Inputs = json.RawMessage(`{"changed":true}`)
+	if _, err := e2.ExecuteHostPlan(context.Background(), plan); err != nil {
+		t.Fatalf("third run error = %v", err)
+	}
+	if step.calls != 2 {
+		t.Errorf("expected changed step to run again, calls=%d", step.calls)
+	}
+}
+
+func TestExecutor_DoesNotRecordFailedSteps(t *testing.T) {
+	store := NewFileIdempotencyStore(filepath.Join(t.TempDir(), "agent-state.json"))
+	step := &flakyExecutor{failures: 1, err: errors.New("boom")}
+	e, _ := newRetryExecutor(step)
+	e.SetIdempotencyStore(store)
+
+	plan := retryPlan(nil)
+	if _, err := e.ExecuteHostPlan(context.Background(), plan); err != nil {
+		t.Fatalf("first run error = %v", err)
+	}
+	report, err := e.ExecuteHostPlan(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("second run error = %v", err)
+	}
+	if step.calls != 2 || report.Steps[0].Status != engine.StepStatusSucceeded {
+		t.Errorf("expected failed step to re-run, calls=%d status=%s", step.calls, report.Steps[0].Status)
+	}
+}
+
+// blockingExecutor waits for its context to end and returns its error.
+type blockingExecutor struct{ calls int }
+
+func (b *blockingExecutor) Execute(ctx context.Context, _ engine.HostPlanStep, _ []byte) error { //nolint:gocritic // matches StepExecutor
+	b.calls++
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestExecutor_TimesOutSteps(t *testing.T) {
+	step := &blockingExecutor{}
+	e, _ := newRetryExecutor(step)
+	e.SetStepTimeouts(engine.StepTimeouts{
+		Default:   time.Hour,
+		PerAction: map[engine.StepAction]time.Duration{engine.StepActionApplyCompose: 10 * time.Millisecond},
+	})
+
+	report, err := e.ExecuteHostPlan(context.Background(), retryPlan(&engine.RetryPolicy{MaxAttempts: 2}))
+	if err != nil {
+		t.Fatalf("ExecuteHostPlan() error = %v", err)
+	}
+	if report.Status != engine.ExecStatusFailed {
+		t.Errorf("expected failed report, got %s", report.Status)
+	}
+	got := report.Steps[0]
+	if got.Status != engine.StepStatusTimedOut || got.Error == nil || got.Error.Code != "TIMEOUT" {
+		t.Errorf("expected timed out step, got status=%s error=%+v", got.Status, got.Error)
+	}
+	if step.calls != 2 {
+		t.Errorf("timeouts are transient and should be retried, got %d attempts", step.calls)
+	}
+}
+
+// Feature: CORE_TRACING
+// Spec: spec/core/tracing.md
+func TestExecutor_EmitsStepSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	step := &flakyExecutor{failures: 1, err: engine.Classify(engine.FailureTransientEnvironment, errors.New("timeout"))}
+	e, _ := newRetryExecutor(step)
+	if _, err := e.ExecuteHostPlan(context.Background(), retryPlan(&engine.RetryPolicy{MaxAttempts: 2})); err != nil {
+		t.Fatalf("ExecuteHostPlan() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "step apply_compose" || spans[1].Name() != "hostplan" {
+		t.Fatalf("expected step and hostplan spans, got %d spans", len(spans))
+	}
+	stepSpan, planSpan := spans[0], spans[1]
+	if stepSpan.Parent().SpanID() != planSpan.SpanContext().SpanID() {
+		t.Error("expected step span to be a child of hostplan")
+	}
+	attrs := make(map[string]string)
+	for _, kv := range stepSpan.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs[string(telemetry.AttrStepID)] != "apply" || attrs[string(telemetry.AttrStepStatus)] != "succeeded" || attrs[string(telemetry.AttrAttempts)] != "2" {
+		t.Errorf("unexpected step span attributes: %v", attrs)
+	}
+	if stepSpan.Status().Code != codes.Ok {
+		t.Errorf("expected Ok status, got %v", stepSpan.Status())
+	}
+}
+
+func TestExecutor_FailedStepSpanRecordsError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	e, _ := newRetryExecutor(&flakyExecutor{failures: 1, err: errors.New("compose up failed")})
+	if _, err := e.ExecuteHostPlan(context.Background(), retryPlan(nil)); err != nil {
+		t.Fatalf("ExecuteHostPlan() error = %v", err)
+	}
+
+	for _, span := range recorder.Ended() {
+		if span.Status().Code != codes.Error {
+			t.Errorf("expected span %q to have Error status, got %v", span.Name(), span.Status())
+		}
+	}
+	if got := recorder.Ended()[0].Status().Description; got != "compose up failed" {
+		t.Errorf("step span status = %q, want the step error", got)
+	}
+}
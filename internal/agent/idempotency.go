@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Feature: ENGINE_STEP_RETRY
+// Spec: spec/engine/step-retry.md
+
+// idempotencyStateVersion is the schema version of the agent state file.
+const idempotencyStateVersion = 1
+
+// IdempotencyStore records which steps have already succeeded, keyed by
+// engine.IdempotencyKey.
+type IdempotencyStore interface {
+	// Succeeded reports whether key has been recorded.
+	Succeeded(ctx context.Context, key string) (bool, error)
+	// RecordSuccess records key as succeeded.
+	RecordSuccess(ctx context.Context, key string, rec StepRecord) error
+}
+
+// StepRecord describes a succeeded step in the idempotency store.
+type StepRecord struct {
+	PlanID      string `json:"planId"`
+	StepID      string `json:"stepId"`
+	CompletedAt string `json:"completedAt"`
+}
+
+type idempotencyState struct {
+	Version int                   `json:"version"`
+	Steps   map[string]StepRecord `json:"steps"`
+}
+
+// FileIdempotencyStore persists step records as JSON in a single file.
+// Writes go through a temp file and rename, so a crash never leaves a
+// partially written state file.
+type FileIdempotencyStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileIdempotencyStore returns a store backed by the file at path. The
+// file and its directory are created on first write.
+func NewFileIdempotencyStore(path string) *FileIdempotencyStore {
+	return &FileIdempotencyStore{path: path}
+}
+
+// Succeeded reports whether key has been recorded.
+func (s *FileIdempotencyStore) Succeeded(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	_, ok := st.Steps[key]
+	return ok, nil
+}
+
+// RecordSuccess records key as succeeded. An empty CompletedAt is filled
+// with the current UTC time.
+func (s *FileIdempotencyStore) RecordSuccess(_ context.Context, key string, rec StepRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	if rec.CompletedAt == "" {
+		rec.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	st.Steps[key] = rec
+	return s.save(st)
+}
+
+func (s *FileIdempotencyStore) load() (*idempotencyState, error) {
+	st := &idempotencyState{Version: idempotencyStateVersion, Steps: map[string]StepRecord{}}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading agent state %s: %w", s.path, err)
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("parsing agent state %s: %w", s.path, err)
+	}
+	if st.Version != idempotencyStateVersion {
+		return nil, fmt.Errorf("agent state %s has unsupported version %d", s.path, st.Version)
+	}
+	if st.Steps == nil {
+		st.Steps = map[string]StepRecord{}
+	}
+	return st, nil
+}
+
+func (s *FileIdempotencyStore) save(st *idempotencyState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding agent state: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating agent state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".agent-state-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temporary agent state: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing temporary agent state: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("syncing temporary agent state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing temporary agent state: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("renaming agent state: %w", err)
+	}
+	return nil
+}
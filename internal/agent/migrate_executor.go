@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"stagecraft/pkg/engine"
+	"stagecraft/pkg/engine/inputs"
+	"stagecraft/pkg/providers/migration"
+)
+
+// Feature: AGENT_DAEMON
+// Spec: spec/engine/agent-daemon.md
+
+// MigrateExecutor runs migrate steps with a registered migration engine on
+// the local host.
+type MigrateExecutor struct {
+	// WorkDir is the directory migration paths resolve against.
+	WorkDir string
+	// Lookup returns the engine for an ID. Nil uses migration.Get.
+	Lookup func(id string) (migration.Engine, error)
+}
+
+// Execute runs all pending "up" migrations described by the step inputs.
+// nolint:gocritic // passed by value intentionally; treated as immutable and keeps call sites simple.
+func (m *MigrateExecutor) Execute(ctx context.Context, step engine.HostPlanStep, inputsJSON []byte) error {
+	// Decoding normalizes paths, rejecting absolute and ".." paths so steps
+	// stay inside WorkDir.
+	in, err := inputs.DecodeMigrate(inputsJSON)
+	if err != nil {
+		return err
+	}
+
+	lookup := m.Lookup
+	if lookup == nil {
+		lookup = migration.Get
+	}
+	eng, err := lookup(in.Engine)
+	if err != nil {
+		return fmt.Errorf("migration engine %q: %w", in.Engine, err)
+	}
+
+	if in.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(in.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	if err := eng.Run(ctx, migration.RunOptions{
+		MigrationPath: filepath.Join(m.WorkDir, filepath.FromSlash(in.Path)),
+		ConnectionEnv: in.ConnEnv,
+		WorkDir:       m.WorkDir,
+		Direction:     "up",
+	}); err != nil {
+		return fmt.Errorf("running %s migrations for %s: %w", in.Engine, in.Database, err)
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"stagecraft/internal/buildinfo"
+	"stagecraft/pkg/engine"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: AGENT_DAEMON
+// Spec: spec/engine/agent-daemon.md
+
+// ErrAgentBusy is returned by Client.Submit when the agent is already
+// executing another plan.
+var ErrAgentBusy = errors.New("agent is busy")
+
+// maxBundleBytes bounds the request body accepted by POST /v1/plans.
+const maxBundleBytes = 8 << 20
+
+// tailnetPrefixes are the address ranges Tailscale assigns to nodes.
+var tailnetPrefixes = []*net.IPNet{
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("fd7a:115c:a1e0::/48"),
+}
+
+// Server accepts signed plan bundles over HTTP and executes them locally.
+type Server struct {
+	// Executor runs verified host plans.
+	Executor *Executor
+	// Secret verifies bundle signatures.
+	Secret []byte
+	// VerifyKey, when set, requires every bundle to carry a plan signature
+	// that verifies against it.
+	VerifyKey ed25519.PublicKey
+	// HostID is this host's logical ID; bundles for other hosts are rejected.
+	HostID string
+	// TailnetOnly rejects peers outside the Tailscale address ranges.
+	// Loopback peers are always accepted.
+	TailnetOnly bool
+	// Logger receives request logs. Nil disables logging.
+	Logger logging.Logger
+
+	mu      sync.Mutex
+	busy    bool
+	reports map[string]*engine.ExecutionReport
+}
+
+// Handler returns the agent's HTTP API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/health", s.handleHealth)
+	mux.HandleFunc("POST /v1/plans", s.handleSubmit)
+	mux.HandleFunc("GET /v1/plans/{id}", s.handleReport)
+	return s.peerFilter(mux)
+}
+
+// Serve starts the agent API on addr and returns the bound address. The
+// server shuts down when ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, addr string) (string, error) {
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("listening on %s: %w", addr, err)
+	}
+
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	return ln.Addr().String(), nil
+}
+
+func (s *Server) peerFilter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.TailnetOnly && !allowedPeer(r.RemoteAddr) {
+			s.log("Rejected request from non-tailnet peer", logging.NewField("remote", r.RemoteAddr))
+			writeError(w, http.StatusForbidden, "peer is not on the tailnet")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "hostId": s.HostID, "version": buildinfo.Version()})
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBundleBytes+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading request body: "+err.Error())
+		return
+	}
+	if len(data) > maxBundleBytes {
+		writeError(w, http.StatusRequestEntityTooLarge, "plan bundle is too large")
+		return
+	}
+
+	if err := CheckVersion(data); err != nil {
+		s.log("Rejected plan bundle", logging.NewField("error", err.Error()))
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		writeError(w, http.StatusBadRequest, "decoding plan bundle: "+err.Error())
+		return
+	}
+	if err := bundle.Verify(s.Secret); err != nil {
+		s.log("Rejected plan bundle", logging.NewField("plan_id", bundle.HostPlan.PlanID), logging.NewField("error", err.Error()))
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if s.VerifyKey != nil {
+		if err := bundle.VerifyPlan(s.VerifyKey); err != nil {
+			s.log("Rejected plan bundle", logging.NewField("plan_id", bundle.HostPlan.PlanID), logging.NewField("error", err.Error()))
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+	}
+	if bundle.HostPlan.Host.LogicalID != s.HostID {
+		writeError(w, http.StatusUnprocessableEntity,
+			fmt.Sprintf("plan targets host %q, this agent is %q", bundle.HostPlan.Host.LogicalID, s.HostID))
+		return
+	}
+
+	if !s.acquire() {
+		writeError(w, http.StatusConflict, "another plan is executing")
+		return
+	}
+	defer s.release()
+
+	s.log("Executing plan", logging.NewField("plan_id", bundle.HostPlan.PlanID), logging.NewField("steps", len(bundle.HostPlan.Steps)))
+	report, err := s.Executor.ExecuteHostPlan(r.Context(), bundle.HostPlan)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "executing host plan: "+err.Error())
+		return
+	}
+	s.storeReport(report)
+	s.log("Plan finished", logging.NewField("plan_id", report.PlanID), logging.NewField("status", string(report.Status)))
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	report, ok := s.reports[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "no report for plan")
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Server) acquire() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.busy {
+		return false
+	}
+	s.busy = true
+	return true
+}
+
+func (s *Server) release() {
+	s.mu.Lock()
+	s.busy = false
+	s.mu.Unlock()
+}
+
+func (s *Server) storeReport(report *engine.ExecutionReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reports == nil {
+		s.reports = make(map[string]*engine.ExecutionReport)
+	}
+	s.reports[report.PlanID] = report
+}
+
+func (s *Server) log(msg string, fields ...logging.Field) {
+	if s.Logger != nil {
+		s.Logger.Info(msg, fields...)
+	}
+}
+
+// allowedPeer reports whether remoteAddr is loopback or a Tailscale address.
+func allowedPeer(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, prefix := range tailnetPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// Client submits plan bundles to a remote agent.
+type Client struct {
+	// BaseURL is the agent's address, e.g. "http://100.101.102.103:7420".
+	BaseURL string
+	// HTTPClient sends requests. Nil uses http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Submit sends bundle to the agent and returns its execution report.
+func (c *Client) Submit(ctx context.Context, bundle *Bundle) (*engine.ExecutionReport, error) {
+	body, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("encoding plan bundle: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.BaseURL, "/")+"/v1/plans", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending plan to agent: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBundleBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading agent response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		return nil, ErrAgentBusy
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e errorResponse
+		if json.Unmarshal(data, &e) == nil && e.Error != "" {
+			return nil, fmt.Errorf("agent returned %d: %s", resp.StatusCode, e.Error)
+		}
+		return nil, fmt.Errorf("agent returned %d", resp.StatusCode)
+	}
+
+	var report engine.ExecutionReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("decoding execution report: %w", err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,327 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/buildinfo"
+	"stagecraft/internal/plansign"
+	"stagecraft/pkg/engine"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/providers/migration"
+)
+
+// Feature: AGENT_DAEMON
+// Spec: spec/engine/agent-daemon.md
+
+var testSecret = []byte("s3cret")
+
+type recordingRunner struct {
+	commands []executil.Command
+	err      error
+}
+
+func (r *recordingRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // matches executil.Runner
+	r.commands = append(r.commands, cmd)
+	if r.err != nil {
+		return &executil.Result{ExitCode: 1, Stderr: []byte("boom")}, r.err
+	}
+	return &executil.Result{}, nil
+}
+
+func (r *recordingRunner) RunStream(_ context.Context, _ executil.Command, _ io.Writer) error { //nolint:gocritic // matches executil.Runner
+	return nil
+}
+
+type recordingEngine struct {
+	opts migration.RunOptions
+}
+
+func (e *recordingEngine) ID() string { return "fake" }
+
+func (e *recordingEngine) Plan(context.Context, migration.PlanOptions) ([]migration.Migration, error) {
+	return nil, nil
+}
+
+func (e *recordingEngine) Run(_ context.Context, opts migration.RunOptions) error {
+	e.opts = opts
+	return nil
+}
+
+func composePlan(hostID string) engine.HostPlan {
+	return engine.HostPlan{
+		Version: engine.HostPlanSchemaVersion,
+		PlanID:  "plan-1",
+		Host:    engine.HostRef{LogicalID: hostID},
+		Steps: []engine.HostPlanStep{{
+			ID:     "apply",
+			Action: engine.StepActionApplyCompose,
+			Inputs: json.RawMessage(`{"environment":"prod","compose_path":"compose.yaml","project_name":"demo","pull":true,"detach":true,"services":["api"]}`),
+		}},
+	}
+}
+
+func TestBundle_SignAndVerify(t *testing.T) {
+	bundle, err := SignBundle(composePlan("host-a"), testSecret)
+	if err != nil {
+		t.Fatalf("SignBundle() error = %v", err)
+	}
+	if !strings.HasPrefix(bundle.Signature, "hmac-sha256:") {
+		t.Fatalf("unexpected signature format %q", bundle.Signature)
+	}
+	if err := bundle.Verify(testSecret); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := bundle.Verify([]byte("other")); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("expected ErrBadSignature for wrong secret, got %v", err)
+	}
+
+	bundle.HostPlan.PlanID = "tampered"
+	if err := bundle.Verify(testSecret); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("expected ErrBadSignature for tampered plan, got %v", err)
+	}
+}
+
+func TestBundle_EmptySecret(t *testing.T) {
+	if _, err := SignBundle(composePlan("host-a"), nil); err == nil {
+		t.Fatal("expected error for empty secret")
+	}
+}
+
+func newTestServer(t *testing.T, runner *recordingRunner) *httptest.Server {
+	t.Helper()
+	executor := NewExecutor()
+	executor.RegisterExecutor(engine.StepActionApplyCompose, &ComposeExecutor{Runner: runner, WorkDir: "/srv/app"})
+	srv := httptest.NewServer((&Server{
+		Executor:    executor,
+		Secret:      testSecret,
+		HostID:      "host-a",
+		TailnetOnly: true,
+	}).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestServer_ExecutesSignedPlan(t *testing.T) {
+	runner := &recordingRunner{}
+	srv := newTestServer(t, runner)
+
+	bundle, err := SignBundle(composePlan("host-a"), testSecret)
+	if err != nil {
+		t.Fatalf("SignBundle() error = %v", err)
+	}
+
+	report, err := (&Client{BaseURL: srv.URL}).Submit(context.Background(), bundle)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if report.Status != engine.ExecStatusSucceeded {
+		t.Fatalf("expected succeeded report, got %+v", report)
+	}
+
+	if len(runner.commands) != 1 {
+		t.Fatalf("expected one docker invocation, got %d", len(runner.commands))
+	}
+	got := strings.Join(runner.commands[0].Args, " ")
+	want := "compose -f /srv/app/compose.yaml -p demo up -d --pull always api"
+	if got != want {
+		t.Errorf("docker args = %q, want %q", got, want)
+	}
+
+	resp, err := http.Get(srv.URL + "/v1/plans/plan-1")
+	if err != nil {
+		t.Fatalf("GET report: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected stored report, got status %d", resp.StatusCode)
+	}
+}
+
+func TestServer_ReportsStepFailure(t *testing.T) {
+	srv := newTestServer(t, &recordingRunner{err: errors.New("exit status 1")})
+
+	bundle, _ := SignBundle(composePlan("host-a"), testSecret)
+	report, err := (&Client{BaseURL: srv.URL}).Submit(context.Background(), bundle)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if report.Status != engine.ExecStatusFailed {
+		t.Fatalf("expected failed report, got %s", report.Status)
+	}
+	if msg := report.Steps[0].Error.Message; !strings.Contains(msg, "boom") {
+		t.Errorf("expected stderr in step error, got %q", msg)
+	}
+}
+
+func TestServer_RejectsBadBundles(t *testing.T) {
+	srv := newTestServer(t, &recordingRunner{})
+	client := &Client{BaseURL: srv.URL}
+
+	wrongSecret, _ := SignBundle(composePlan("host-a"), []byte("other"))
+	if _, err := client.Submit(context.Background(), wrongSecret); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected 401 for bad signature, got %v", err)
+	}
+
+	wrongHost, _ := SignBundle(composePlan("host-b"), testSecret)
+	if _, err := client.Submit(context.Background(), wrongHost); err == nil || !strings.Contains(err.Error(), "host-b") {
+		t.Errorf("expected host mismatch error, got %v", err)
+	}
+}
+
+func TestServer_RejectsBundlesFromNewerStagecraft(t *testing.T) {
+	t.Setenv(buildinfo.VersionEnv, "1.4.0")
+	runner := &recordingRunner{}
+	srv := newTestServer(t, runner)
+
+	bundle, _ := SignBundle(composePlan("host-a"), testSecret)
+	if bundle.StagecraftVersion != "1.4.0" || bundle.RequiresStagecraft != BundleRequiresVersion {
+		t.Fatalf("bundle versions = %q / %q", bundle.StagecraftVersion, bundle.RequiresStagecraft)
+	}
+
+	bundle.StagecraftVersion, bundle.RequiresStagecraft = "1.6.0", "1.6.0"
+	_, err := (&Client{BaseURL: srv.URL}).Submit(context.Background(), bundle)
+	if err == nil || !strings.Contains(err.Error(), "422") || !strings.Contains(err.Error(), "built by v1.6.0, requires >= v1.6.0 (running v1.4.0)") {
+		t.Errorf("expected version skew rejection, got %v", err)
+	}
+	if len(runner.commands) != 0 {
+		t.Fatalf("expected no docker invocations for rejected plans, got %d", len(runner.commands))
+	}
+}
+
+func TestServer_RequiresPlanSignatureWithVerifyKey(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{9}, ed25519.SeedSize))
+	runner := &recordingRunner{}
+	executor := NewExecutor()
+	executor.RegisterExecutor(engine.StepActionApplyCompose, &ComposeExecutor{Runner: runner, WorkDir: "/srv/app"})
+	srv := httptest.NewServer((&Server{
+		Executor:  executor,
+		Secret:    testSecret,
+		HostID:    "host-a",
+		VerifyKey: plansign.PublicKey(key),
+	}).Handler())
+	t.Cleanup(srv.Close)
+	client := &Client{BaseURL: srv.URL}
+
+	unsigned, _ := SignBundle(composePlan("host-a"), testSecret)
+	if _, err := client.Submit(context.Background(), unsigned); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Errorf("expected unsigned plan to be rejected, got %v", err)
+	}
+
+	// Tampering after signing invalidates the plan signature even when the
+	// shared-secret HMAC is recomputed.
+	signed, _ := SignBundle(composePlan("host-a"), testSecret)
+	if err := signed.SignPlan(key); err != nil {
+		t.Fatalf("SignPlan() error = %v", err)
+	}
+	tampered, _ := SignBundle(composePlan("host-a"), testSecret)
+	tampered.HostPlan.Steps[0].Inputs = json.RawMessage(`{"compose_path":"evil.yaml","project_name":"demo"}`)
+	tampered, _ = SignBundle(tampered.HostPlan, testSecret)
+	tampered.PlanSignature = signed.PlanSignature
+	if _, err := client.Submit(context.Background(), tampered); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected tampered plan to be rejected, got %v", err)
+	}
+	if len(runner.commands) != 0 {
+		t.Fatalf("expected no docker invocations for rejected plans, got %d", len(runner.commands))
+	}
+
+	report, err := client.Submit(context.Background(), signed)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if report.Status != engine.ExecStatusSucceeded {
+		t.Fatalf("expected succeeded report, got %s", report.Status)
+	}
+}
+
+func TestAllowedPeer(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:5000":           true,
+		"[::1]:5000":               true,
+		"100.101.102.103:5000":     true,
+		"[fd7a:115c:a1e0::1]:5000": true,
+		"192.168.1.10:5000":        false,
+		"[2001:db8::1]:5000":       false,
+		"not-an-address":           false,
+	}
+	for addr, want := range tests {
+		if got := allowedPeer(addr); got != want {
+			t.Errorf("allowedPeer(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestComposeExecutor_VerifiesComposeHash(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte("services: {}\n"), 0o600); err != nil {
+		t.Fatalf("writing compose file: %v", err)
+	}
+
+	step := engine.HostPlanStep{ID: "apply", Action: engine.StepActionApplyCompose}
+	in := `{"environment":"prod","compose_path":"compose.yaml","project_name":"demo","pull":false,"detach":true,` +
+		`"expected_compose_hash_alg":"sha256","expected_compose_hash":"` + strings.Repeat("0", 64) + `"}`
+
+	runner := &recordingRunner{}
+	err := (&ComposeExecutor{Runner: runner, WorkDir: dir}).Execute(context.Background(), step, []byte(in))
+	if err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Fatalf("expected hash mismatch, got %v", err)
+	}
+	if len(runner.commands) != 0 {
+		t.Fatalf("expected docker not to run on hash mismatch")
+	}
+}
+
+func TestComposeExecutor_RejectsEscapingPath(t *testing.T) {
+	step := engine.HostPlanStep{ID: "apply", Action: engine.StepActionApplyCompose}
+	in := `{"environment":"prod","compose_path":"../etc/compose.yaml","project_name":"demo","pull":false,"detach":true}`
+
+	err := (&ComposeExecutor{Runner: &recordingRunner{}, WorkDir: t.TempDir()}).Execute(context.Background(), step, []byte(in))
+	if err == nil {
+		t.Fatal("expected error for path escaping workdir")
+	}
+}
+
+func TestMigrateExecutor_RunsEngine(t *testing.T) {
+	eng := &recordingEngine{}
+	exec := &MigrateExecutor{
+		WorkDir: "/srv/app",
+		Lookup: func(id string) (migration.Engine, error) {
+			if id != "fake" {
+				t.Fatalf("unexpected engine %q", id)
+			}
+			return eng, nil
+		},
+	}
+
+	step := engine.HostPlanStep{ID: "migrate", Action: engine.StepActionMigrate}
+	in := `{"database":"main","strategy":"pre_deploy","engine":"fake","path":"migrations","conn_env":"DATABASE_URL"}`
+	if err := exec.Execute(context.Background(), step, []byte(in)); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if eng.opts.MigrationPath != "/srv/app/migrations" || eng.opts.ConnectionEnv != "DATABASE_URL" || eng.opts.Direction != "up" {
+		t.Errorf("unexpected run options: %+v", eng.opts)
+	}
+}
@@ -28,75 +28,17 @@ type StubExecutor struct{}
 // nolint:gocritic // passed by value intentionally; treated as immutable and keeps call sites simple.
 func (s *StubExecutor) Execute(ctx context.Context, step engine.HostPlanStep, inputsJSON []byte) error {
 	// Strict decode and validate inputs based on action
-	switch step.Action {
-	case engine.StepActionBuild:
-		var in inputs.BuildInputs
-		if err := inputs.UnmarshalStrict(inputsJSON, &in); err != nil {
-			return fmt.Errorf("invalid build inputs: %w", err)
-		}
-		if err := in.Validate(); err != nil {
-			return fmt.Errorf("build inputs validation failed: %w", err)
-		}
-		// Stub: log that we would build
-		return nil
-
-	case engine.StepActionMigrate:
-		var in inputs.MigrateInputs
-		if err := inputs.UnmarshalStrict(inputsJSON, &in); err != nil {
-			return fmt.Errorf("invalid migrate inputs: %w", err)
-		}
-		if err := in.Validate(); err != nil {
-			return fmt.Errorf("migrate inputs validation failed: %w", err)
-		}
-		// Stub: log that we would migrate
-		return nil
-
-	case engine.StepActionApplyCompose:
-		var in inputs.ApplyComposeInputs
-		if err := inputs.UnmarshalStrict(inputsJSON, &in); err != nil {
-			return fmt.Errorf("invalid apply_compose inputs: %w", err)
-		}
-		if err := in.Validate(); err != nil {
-			return fmt.Errorf("apply_compose inputs validation failed: %w", err)
-		}
-		// Stub: log that we would apply compose
-		return nil
-
-	case engine.StepActionHealthCheck:
-		var in inputs.HealthCheckInputs
-		if err := inputs.UnmarshalStrict(inputsJSON, &in); err != nil {
-			return fmt.Errorf("invalid health_check inputs: %w", err)
-		}
-		if err := in.Validate(); err != nil {
-			return fmt.Errorf("health_check inputs validation failed: %w", err)
-		}
-		// Stub: log that we would health check
-		return nil
-
-	case engine.StepActionRenderCompose:
-		var in inputs.RenderComposeInputs
-		if err := inputs.UnmarshalStrict(inputsJSON, &in); err != nil {
-			return fmt.Errorf("invalid render_compose inputs: %w", err)
-		}
-		if err := in.Validate(); err != nil {
-			return fmt.Errorf("render_compose inputs validation failed: %w", err)
-		}
-		// Stub: log that we would render compose
-		return nil
-
-	case engine.StepActionRollout:
-		var in inputs.RolloutInputs
-		if err := inputs.UnmarshalStrict(inputsJSON, &in); err != nil {
-			return fmt.Errorf("invalid rollout inputs: %w", err)
-		}
-		if err := in.Validate(); err != nil {
-			return fmt.Errorf("rollout inputs validation failed: %w", err)
-		}
-		// Stub: log that we would rollout
-		return nil
-
-	default:
-		// Unknown action - just validate JSON is valid
+	in, ok := inputs.ForAction(step.Action)
+	if !ok {
+		// Unknown action - nothing to validate
 		return nil
 	}
+	if err := inputs.UnmarshalStrict(inputsJSON, in); err != nil {
+		return fmt.Errorf("invalid %s inputs: %w", step.Action, err)
+	}
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("%s inputs validation failed: %w", step.Action, err)
+	}
+	// Stub: we would perform the action here
+	return nil
 }
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package buildinfo reports which Stagecraft release is running and
+// compares release versions.
+package buildinfo
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Feature: CLI_SELF_UPDATE
+// Spec: spec/commands/self-update.md
+
+// version is the release this binary was built from, set at build time:
+//
+//	go build -ldflags "-X stagecraft/internal/buildinfo.version=1.4.0" ./cmd/stagecraft
+var version = defaultVersion
+
+// defaultVersion identifies development builds.
+const defaultVersion = "0.0.0-dev"
+
+// VersionEnv overrides the built-in version, e.g. to test upgrades.
+const VersionEnv = "STAGECRAFT_VERSION"
+
+// Version returns the running Stagecraft version: $STAGECRAFT_VERSION when
+// set, otherwise the version set at build time.
+func Version() string {
+	if v := os.Getenv(VersionEnv); v != "" {
+		return v
+	}
+	return version
+}
+
+// Compare compares two semantic versions and returns -1, 0 or +1. A
+// leading "v" and build metadata are ignored, missing minor and patch
+// numbers count as 0, and a pre-release sorts before its release
+// (1.2.0-rc.1 < 1.2.0). Versions that do not parse sort before those that
+// do and compare as strings among themselves.
+func Compare(a, b string) int {
+	pa, okA := parse(a)
+	pb, okB := parse(b)
+	switch {
+	case !okA && !okB:
+		return strings.Compare(a, b)
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+
+	for i := range pa.core {
+		if c := compareInt(pa.core[i], pb.core[i]); c != 0 {
+			return c
+		}
+	}
+	return comparePrerelease(pa.pre, pb.pre)
+}
+
+// Valid reports whether v is a semantic version Compare understands.
+func Valid(v string) bool {
+	_, ok := parse(v)
+	return ok
+}
+
+type semver struct {
+	core [3]int
+	pre  []string
+}
+
+func parse(v string) (semver, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "+")
+	v, pre, hasPre := strings.Cut(v, "-")
+
+	var s semver
+	parts := strings.Split(v, ".")
+	if len(parts) > 3 {
+		return semver{}, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		s.core[i] = n
+	}
+	if hasPre {
+		if pre == "" {
+			return semver{}, false
+		}
+		s.pre = strings.Split(pre, ".")
+	}
+	return s, true
+}
+
+// comparePrerelease orders pre-release identifiers per semver: no
+// pre-release sorts last, numeric identifiers compare numerically and
+// before alphanumeric ones, and a shorter list of equal identifiers first.
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		na, errA := strconv.Atoi(a[i])
+		nb, errB := strconv.Atoi(b[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = compareInt(na, nb)
+		case errA == nil:
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(a), len(b))
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package buildinfo
+
+import "testing"
+
+// Feature: CLI_SELF_UPDATE
+// Spec: spec/commands/self-update.md
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.2.3+build.5", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.2.0-rc.1", "1.2.0", -1},
+		{"1.2.0-rc.2", "1.2.0-rc.10", -1},
+		{"1.2.0-1", "1.2.0-alpha", -1},
+		{"1.2.0-alpha", "1.2.0-alpha.1", -1},
+		{"0.0.0-dev", "0.1.0", -1},
+		{"garbage", "0.0.1", -1},
+		{"1.0.0", "garbage", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, v := range []string{"1", "1.2", "v1.2.3", "1.2.3-rc.1+abc"} {
+		if !Valid(v) {
+			t.Errorf("Valid(%q) = false", v)
+		}
+	}
+	for _, v := range []string{"", "1.2.3.4", "1.x", "1.2.3-", "-1.0"} {
+		if Valid(v) {
+			t.Errorf("Valid(%q) = true", v)
+		}
+	}
+}
+
+func TestVersion_EnvOverride(t *testing.T) {
+	t.Setenv(VersionEnv, "9.9.9")
+	if got := Version(); got != "9.9.9" {
+		t.Errorf("Version() = %q, want 9.9.9", got)
+	}
+
+	t.Setenv(VersionEnv, "")
+	if got := Version(); got != version {
+		t.Errorf("Version() = %q, want %q", got, version)
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package buildinfo
+
+import (
+	"fmt"
+	"strings"
+
+	"stagecraft/internal/failure"
+)
+
+// Feature: CORE_VERSION_SKEW
+// Spec: spec/core/version-skew.md
+
+// ErrVersionSkew is returned when a plan, state file or agent bundle
+// requires a newer Stagecraft than the running one.
+var ErrVersionSkew = failure.New(failure.ClassConfigInvalid, "written by an incompatible Stagecraft version")
+
+// Development reports whether the running binary is a development build,
+// which has no release version to compare and skips skew checks.
+func Development() bool {
+	v := Version()
+	return v == defaultVersion || !Valid(v)
+}
+
+// CheckRequired returns an error wrapping ErrVersionSkew when what, written
+// by Stagecraft builtBy, requires a Stagecraft newer than the running one.
+// Documents without a requirement predate version stamps and always pass,
+// as does everything for development builds.
+func CheckRequired(what, builtBy, requires string) error {
+	if requires == "" || Development() {
+		return nil
+	}
+	current := Version()
+	if Compare(current, requires) >= 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: %w: built by %s, requires >= %s (running %s); run `stagecraft self-update`",
+		what, ErrVersionSkew, Display(builtBy), Display(requires), Display(current))
+}
+
+// Display formats a version for messages, e.g. "v1.4.0". Empty versions
+// display as "an unknown version".
+func Display(v string) string {
+	switch {
+	case v == "":
+		return "an unknown version"
+	case Valid(v) && !strings.HasPrefix(v, "v"):
+		return "v" + v
+	}
+	return v
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package buildinfo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/failure"
+)
+
+// Feature: CORE_VERSION_SKEW
+// Spec: spec/core/version-skew.md
+
+func TestCheckRequired(t *testing.T) {
+	t.Setenv(VersionEnv, "1.4.0")
+
+	for _, requires := range []string{"", "1.4.0", "v1.3.9", "1.4.0-rc.1"} {
+		if err := CheckRequired("plan", "1.4.0", requires); err != nil {
+			t.Errorf("CheckRequired(requires %q) = %v, want nil", requires, err)
+		}
+	}
+
+	err := CheckRequired("plan artifact plan.json", "1.6.0", "1.5.0")
+	if !errors.Is(err, ErrVersionSkew) {
+		t.Fatalf("CheckRequired() = %v, want ErrVersionSkew", err)
+	}
+	want := "plan artifact plan.json: written by an incompatible Stagecraft version: built by v1.6.0, requires >= v1.5.0 (running v1.4.0)"
+	if !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("error = %q, want prefix %q", err, want)
+	}
+
+	d := failure.Classify(err)
+	if d.Class != failure.ClassConfigInvalid || !strings.Contains(d.Cause, "newer Stagecraft") {
+		t.Errorf("Classify() = %+v", d)
+	}
+
+	err = CheckRequired("state file", "", "2.0.0")
+	if err == nil || !strings.Contains(err.Error(), "built by an unknown version, requires >= v2.0.0") {
+		t.Errorf("error without a writer version = %v", err)
+	}
+}
+
+func TestCheckRequired_DevelopmentBuildsSkip(t *testing.T) {
+	for _, v := range []string{"", "0.0.0-dev", "main-abc123"} {
+		t.Setenv(VersionEnv, v)
+		if !Development() {
+			t.Errorf("Development() = false for %q", v)
+		}
+		if err := CheckRequired("plan", "9.0.0", "9.0.0"); err != nil {
+			t.Errorf("CheckRequired() for %q = %v, want nil", v, err)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package bundle packs everything a deployment rolls out — the generated
+// compose files, the plan, and an env template — into a deterministic
+// archive, so a release can be re-deployed byte-identically later.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feature: DEPLOY_BUNDLE
+// Spec: spec/deploy/bundle.md
+
+// SchemaVersion is the current bundle manifest schema version.
+const SchemaVersion = 1
+
+// Archive entry names.
+const (
+	ManifestName    = "bundle.json"
+	PlanName        = "plan.json"
+	EnvTemplateName = "env.template"
+	filesDir        = "files/"
+)
+
+// maxEntrySize bounds a single archive entry when decoding.
+const maxEntrySize = 64 << 20
+
+// ErrDigestMismatch is returned when a bundle does not match the digest
+// recorded for it.
+var ErrDigestMismatch = errors.New("deploy bundle digest mismatch")
+
+// Manifest describes a bundle.
+type Manifest struct {
+	SchemaVersion int    `json:"schema_version"`
+	Environment   string `json:"environment"`
+	Version       string `json:"version"`
+	CommitSHA     string `json:"commit_sha,omitempty"`
+
+	// ComposeFile is the compose file to roll out, relative to the
+	// project root.
+	ComposeFile string `json:"compose_file"`
+
+	// Images maps backend services to the image references the compose
+	// files roll out.
+	Images map[string]string `json:"images"`
+}
+
+// Bundle is the decoded content of a deploy bundle.
+type Bundle struct {
+	Manifest Manifest
+
+	// Plan is the plan artifact JSON (see spec/deploy/plan-artifact.md).
+	Plan []byte
+
+	// EnvTemplate lists the env_file's variable names with empty values.
+	EnvTemplate []byte
+
+	// Files holds the generated files keyed by slash-separated path
+	// relative to the project root.
+	Files map[string][]byte
+}
+
+// FileName returns the archive name a release's bundle is stored under.
+func FileName(releaseID string) string {
+	return releaseID + ".tar.gz"
+}
+
+// Encode returns the bundle as a gzipped tar archive. Entries are sorted
+// and carry no timestamps or ownership, so equal bundles encode to equal
+// bytes.
+func (b *Bundle) Encode() ([]byte, error) {
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding bundle manifest: %w", err)
+	}
+
+	entries := map[string][]byte{
+		ManifestName:    append(manifest, '\n'),
+		PlanName:        b.Plan,
+		EnvTemplateName: b.EnvTemplate,
+	}
+	for name, data := range b.Files {
+		if err := checkRelPath(name); err != nil {
+			return nil, err
+		}
+		entries[filesDir+name] = data
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		data := entries[name]
+		hdr := &tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+			ModTime:  time.Unix(0, 0),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("writing bundle entry %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return nil, fmt.Errorf("writing bundle entry %s: %w", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode parses an archive produced by Encode.
+func Decode(data []byte) (*Bundle, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading deploy bundle: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	b := &Bundle{Files: make(map[string][]byte)}
+	var manifest []byte
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading deploy bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("deploy bundle entry %s is not a regular file", hdr.Name)
+		}
+		if hdr.Size > maxEntrySize {
+			return nil, fmt.Errorf("deploy bundle entry %s is too large (%d bytes)", hdr.Name, hdr.Size)
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading deploy bundle entry %s: %w", hdr.Name, err)
+		}
+
+		switch name := hdr.Name; {
+		case name == ManifestName:
+			manifest = content
+		case name == PlanName:
+			b.Plan = content
+		case name == EnvTemplateName:
+			b.EnvTemplate = content
+		case strings.HasPrefix(name, filesDir):
+			rel := strings.TrimPrefix(name, filesDir)
+			if err := checkRelPath(rel); err != nil {
+				return nil, err
+			}
+			b.Files[rel] = content
+		default:
+			return nil, fmt.Errorf("unexpected deploy bundle entry %s", name)
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("deploy bundle has no %s", ManifestName)
+	}
+	if err := json.Unmarshal(manifest, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ManifestName, err)
+	}
+	if b.Manifest.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("deploy bundle: unsupported schema version %d (expected %d)", b.Manifest.SchemaVersion, SchemaVersion)
+	}
+	if _, ok := b.Files[b.Manifest.ComposeFile]; !ok {
+		return nil, fmt.Errorf("deploy bundle is missing its compose file %s", b.Manifest.ComposeFile)
+	}
+	return b, nil
+}
+
+// Digest returns "sha256:<hex>" over an encoded bundle.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Verify checks an encoded bundle against its recorded digest.
+func Verify(data []byte, digest string) error {
+	if got := Digest(data); got != digest {
+		return fmt.Errorf("%w (recorded %s, got %s)", ErrDigestMismatch, digest, got)
+	}
+	return nil
+}
+
+// EnvTemplate returns the variable names of vars as a dotenv file with
+// empty values, sorted by name. Values are never included.
+func EnvTemplate(vars map[string]string) []byte {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		buf.WriteString(name)
+		buf.WriteString("=\n")
+	}
+	return buf.Bytes()
+}
+
+// WriteFiles writes the bundle's files under root and returns the path of
+// the compose file to roll out, with the permissions the compose
+// generator uses.
+func (b *Bundle) WriteFiles(root string) (string, error) {
+	names := make([]string, 0, len(b.Files))
+	for name := range b.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		dest := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+			return "", fmt.Errorf("creating directory for %s: %w", name, err)
+		}
+		// #nosec G306 -- compose files are read by docker compose
+		if err := os.WriteFile(dest, b.Files[name], 0o644); err != nil {
+			return "", fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	return filepath.Join(root, filepath.FromSlash(b.Manifest.ComposeFile)), nil
+}
+
+// checkRelPath rejects file names that would escape the project root.
+func checkRelPath(name string) error {
+	if name == "" || path.IsAbs(name) || path.Clean(name) != name || name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("invalid deploy bundle file path %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Feature: DEPLOY_BUNDLE
+// Spec: spec/deploy/bundle.md
+
+func testBundle() *Bundle {
+	return &Bundle{
+		Manifest: Manifest{
+			SchemaVersion: SchemaVersion,
+			Environment:   "prod",
+			Version:       "v1.2.3",
+			CommitSHA:     "abc123",
+			ComposeFile:   ".stagecraft/rendered/prod/docker-compose.yml",
+			Images:        map[string]string{"api": "ghcr.io/org/api:v1.2.3"},
+		},
+		Plan:        []byte("{\"plan_id\": \"p\"}\n"),
+		EnvTemplate: EnvTemplate(map[string]string{"DATABASE_URL": "postgres://x", "API_TOKEN": "secret"}),
+		Files: map[string][]byte{
+			".stagecraft/rendered/prod/docker-compose.yml": []byte("services: {}\n"),
+			".stagecraft/rendered/prod/static/nginx.conf":  []byte("server {}\n"),
+		},
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	b := testBundle()
+
+	data, err := b.Encode()
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, b) {
+		t.Fatalf("round trip mismatch:\n got  %+v\n want %+v", decoded, b)
+	}
+}
+
+func TestEncode_Deterministic(t *testing.T) {
+	first, err := testBundle().Encode()
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	second, err := testBundle().Encode()
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatal("expected equal bundles to encode to equal bytes")
+	}
+	if Digest(first) != Digest(second) {
+		t.Fatal("expected equal digests")
+	}
+}
+
+func TestEnvTemplate_OmitsValues(t *testing.T) {
+	got := string(EnvTemplate(map[string]string{"B": "2", "A": "secret"}))
+	if got != "A=\nB=\n" {
+		t.Fatalf("unexpected env template %q", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data, err := testBundle().Encode()
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if err := Verify(data, Digest(data)); err != nil {
+		t.Fatalf("expected digest to verify, got %v", err)
+	}
+	if err := Verify(append(data, 0), Digest(data)); !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("expected ErrDigestMismatch, got %v", err)
+	}
+}
+
+func TestEncode_RejectsEscapingPaths(t *testing.T) {
+	for _, name := range []string{"../etc/passwd", "/etc/passwd", "a/../../b", ""} {
+		b := testBundle()
+		b.Files[name] = []byte("x")
+		if _, err := b.Encode(); err == nil || !strings.Contains(err.Error(), "invalid deploy bundle file path") {
+			t.Errorf("Encode with %q: expected invalid path error, got %v", name, err)
+		}
+	}
+}
+
+func TestDecode_RejectsEscapingPaths(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	content := []byte("x")
+	if err := tw.WriteHeader(&tar.Header{Name: "files/../../evil", Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = tw.Write(content)
+	_ = tw.Close()
+	_ = gz.Close()
+
+	if _, err := Decode(buf.Bytes()); err == nil || !strings.Contains(err.Error(), "invalid deploy bundle file path") {
+		t.Fatalf("expected invalid path error, got %v", err)
+	}
+}
+
+func TestDecode_MissingComposeFile(t *testing.T) {
+	b := testBundle()
+	b.Manifest.ComposeFile = "docker-compose.yml"
+	data, err := b.Encode()
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if _, err := Decode(data); err == nil || !strings.Contains(err.Error(), "missing its compose file") {
+		t.Fatalf("expected missing compose file error, got %v", err)
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	root := t.TempDir()
+	b := testBundle()
+
+	composePath, err := b.WriteFiles(root)
+	if err != nil {
+		t.Fatalf("WriteFiles failed: %v", err)
+	}
+	if composePath != filepath.Join(root, ".stagecraft", "rendered", "prod", "docker-compose.yml") {
+		t.Fatalf("unexpected compose path %s", composePath)
+	}
+	for name, want := range b.Files {
+		got, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("reading %s: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
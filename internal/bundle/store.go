@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+)
+
+// DefaultDir is where bundles are stored when artifacts.store is not set.
+const DefaultDir = ".stagecraft/artifacts"
+
+// Store saves and loads encoded bundles.
+type Store interface {
+	// Put stores data under name and returns its location.
+	Put(ctx context.Context, name string, data []byte) (string, error)
+
+	// Get loads the bundle at a location returned by Put.
+	Get(ctx context.Context, location string) ([]byte, error)
+}
+
+// NewStore returns the store configured by artifacts, relative to workdir.
+// runner runs the aws CLI for S3 stores.
+func NewStore(artifacts *config.ArtifactsConfig, workdir string, runner executil.Runner) Store {
+	var store, endpoint string
+	if artifacts != nil {
+		store, endpoint = artifacts.Store, artifacts.Endpoint
+	}
+
+	if rest, ok := strings.CutPrefix(store, config.S3StorePrefix); ok {
+		bucket, prefix, _ := strings.Cut(strings.Trim(rest, "/"), "/")
+		return &S3Store{Bucket: bucket, Prefix: prefix, Endpoint: endpoint, runner: runner}
+	}
+
+	if store == "" {
+		store = DefaultDir
+	}
+	if !filepath.IsAbs(store) {
+		store = filepath.Join(workdir, store)
+	}
+	return &DirStore{Dir: store}
+}
+
+// Fetch loads the bundle at a location returned by a store's Put. The
+// location picks the store; artifacts only supplies the S3 endpoint.
+func Fetch(ctx context.Context, location string, artifacts *config.ArtifactsConfig, runner executil.Runner) ([]byte, error) {
+	if strings.HasPrefix(location, config.S3StorePrefix) {
+		store := &S3Store{runner: runner}
+		if artifacts != nil {
+			store.Endpoint = artifacts.Endpoint
+		}
+		return store.Get(ctx, location)
+	}
+	return (&DirStore{}).Get(ctx, location)
+}
+
+// DirStore keeps bundles in a local directory.
+type DirStore struct {
+	Dir string
+}
+
+// Put writes data to Dir/name. Bundles hold resolved environment values,
+// so they are readable by the owner only.
+func (s *DirStore) Put(_ context.Context, name string, data []byte) (string, error) {
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return "", fmt.Errorf("creating artifacts directory: %w", err)
+	}
+	path := filepath.Join(s.Dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("writing deploy bundle: %w", err)
+	}
+	return path, nil
+}
+
+// Get reads the bundle at path.
+func (s *DirStore) Get(_ context.Context, location string) ([]byte, error) {
+	// #nosec G304 -- location was recorded by Put
+	data, err := os.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("reading deploy bundle: %w", err)
+	}
+	return data, nil
+}
+
+// S3Store keeps bundles in S3-compatible object storage through the aws
+// CLI, which reads credentials from its usual sources.
+type S3Store struct {
+	Bucket   string
+	Prefix   string
+	Endpoint string
+
+	runner executil.Runner
+}
+
+// URL returns the object URL a bundle named name is stored at.
+func (s *S3Store) URL(name string) string {
+	key := name
+	if s.Prefix != "" {
+		key = s.Prefix + "/" + name
+	}
+	return config.S3StorePrefix + s.Bucket + "/" + key
+}
+
+// Put uploads data with `aws s3 cp - <url>`.
+func (s *S3Store) Put(ctx context.Context, name string, data []byte) (string, error) {
+	url := s.URL(name)
+	cmd := s.command("-", url)
+	cmd.Stdin = bytes.NewReader(data)
+	if _, err := s.run(ctx, cmd); err != nil {
+		return "", fmt.Errorf("uploading deploy bundle to %s: %w", url, err)
+	}
+	return url, nil
+}
+
+// Get downloads the object at location with `aws s3 cp <url> -`.
+func (s *S3Store) Get(ctx context.Context, location string) ([]byte, error) {
+	data, err := s.run(ctx, s.command(location, "-"))
+	if err != nil {
+		return nil, fmt.Errorf("downloading deploy bundle from %s: %w", location, err)
+	}
+	return data, nil
+}
+
+func (s *S3Store) command(src, dst string) executil.Command {
+	args := []string{"s3", "cp", "--only-show-errors", src, dst}
+	if s.Endpoint != "" {
+		args = append(args, "--endpoint-url", s.Endpoint)
+	}
+	return executil.NewCommand("aws", args...)
+}
+
+func (s *S3Store) run(ctx context.Context, cmd executil.Command) ([]byte, error) {
+	result, err := s.runner.Run(ctx, cmd)
+	if err == nil && result.ExitCode != 0 {
+		err = fmt.Errorf("aws exited with code %d", result.ExitCode)
+	}
+	if err != nil {
+		if result != nil && len(result.Stderr) > 0 {
+			return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(result.Stderr)))
+		}
+		return nil, err
+	}
+	return result.Stdout, nil
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEPLOY_BUNDLE
+// Spec: spec/deploy/bundle.md
+
+type recordingRunner struct {
+	commands []executil.Command
+	stdin    []byte
+	stdout   []byte
+	fail     bool
+}
+
+func (r *recordingRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	r.commands = append(r.commands, cmd)
+	if cmd.Stdin != nil {
+		r.stdin, _ = io.ReadAll(cmd.Stdin)
+	}
+	if r.fail {
+		return &executil.Result{ExitCode: 1, Stderr: []byte("AccessDenied\n")}, errors.New("command failed with exit code 1")
+	}
+	return &executil.Result{Stdout: r.stdout}, nil
+}
+
+func (r *recordingRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return nil
+}
+
+func TestNewStore(t *testing.T) {
+	workdir := t.TempDir()
+
+	if s, ok := NewStore(nil, workdir, nil).(*DirStore); !ok || s.Dir != filepath.Join(workdir, DefaultDir) {
+		t.Errorf("expected default dir store, got %#v", s)
+	}
+	if s, ok := NewStore(&config.ArtifactsConfig{Store: "bundles"}, workdir, nil).(*DirStore); !ok || s.Dir != filepath.Join(workdir, "bundles") {
+		t.Errorf("expected relative dir store, got %#v", s)
+	}
+
+	s, ok := NewStore(&config.ArtifactsConfig{Store: "s3://releases/app/prod/", Endpoint: "https://minio:9000"}, workdir, nil).(*S3Store)
+	if !ok || s.Bucket != "releases" || s.Prefix != "app/prod" || s.Endpoint != "https://minio:9000" {
+		t.Fatalf("unexpected s3 store %#v", s)
+	}
+	if got := s.URL("rel-1.tar.gz"); got != "s3://releases/app/prod/rel-1.tar.gz" {
+		t.Errorf("unexpected object URL %s", got)
+	}
+}
+
+func TestDirStore_PutGet(t *testing.T) {
+	store := &DirStore{Dir: filepath.Join(t.TempDir(), "artifacts")}
+
+	location, err := store.Put(context.Background(), "rel-1.tar.gz", []byte("bundle"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	data, err := store.Get(context.Background(), location)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if string(data) != "bundle" {
+		t.Fatalf("unexpected data %q", data)
+	}
+}
+
+func TestS3Store_PutGet(t *testing.T) {
+	runner := &recordingRunner{stdout: []byte("bundle")}
+	store := &S3Store{Bucket: "releases", Endpoint: "https://minio:9000", runner: runner}
+
+	location, err := store.Put(context.Background(), "rel-1.tar.gz", []byte("bundle"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if location != "s3://releases/rel-1.tar.gz" {
+		t.Fatalf("unexpected location %s", location)
+	}
+	if !bytes.Equal(runner.stdin, []byte("bundle")) {
+		t.Errorf("expected bundle on stdin, got %q", runner.stdin)
+	}
+
+	data, err := store.Get(context.Background(), location)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if string(data) != "bundle" {
+		t.Fatalf("unexpected data %q", data)
+	}
+
+	want := [][]string{
+		{"s3", "cp", "--only-show-errors", "-", "s3://releases/rel-1.tar.gz", "--endpoint-url", "https://minio:9000"},
+		{"s3", "cp", "--only-show-errors", "s3://releases/rel-1.tar.gz", "-", "--endpoint-url", "https://minio:9000"},
+	}
+	for i, cmd := range runner.commands {
+		if cmd.Name != "aws" || !reflect.DeepEqual(cmd.Args, want[i]) {
+			t.Errorf("command %d: got %s %v, want aws %v", i, cmd.Name, cmd.Args, want[i])
+		}
+	}
+}
+
+func TestS3Store_Failure(t *testing.T) {
+	store := &S3Store{Bucket: "releases", runner: &recordingRunner{fail: true}}
+
+	_, err := store.Put(context.Background(), "rel-1.tar.gz", []byte("bundle"))
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Fatalf("expected error with aws stderr, got %v", err)
+	}
+}
@@ -0,0 +1,393 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package cache manages Stagecraft's cache directory, which holds data
+// fetched from providers that can be fetched again: host inventories and
+// price lists. Deleting any of it is always safe.
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feature: CORE_CACHE
+// Spec: spec/core/cache.md
+
+// DirEnv names the environment variable that overrides the cache directory.
+const DirEnv = "STAGECRAFT_CACHE_DIR"
+
+// Buckets group entries by what they hold.
+const (
+	// BucketHosts holds host inventories, keyed by project and environment.
+	BucketHosts = "hosts"
+
+	// BucketPricing holds price lists fetched from provider APIs, keyed by
+	// provider ID.
+	BucketPricing = "pricing"
+)
+
+// Default TTLs of the buckets: how long an entry is reused before it is
+// fetched again.
+const (
+	HostsTTL   = 5 * time.Minute
+	PricingTTL = 24 * time.Hour
+)
+
+// TTL returns the default TTL of bucket, or zero for buckets this build
+// does not know. Prune keeps entries of such buckets.
+func TTL(bucket string) time.Duration {
+	switch bucket {
+	case BucketHosts:
+		return HostsTTL
+	case BucketPricing:
+		return PricingTTL
+	default:
+		return 0
+	}
+}
+
+// DefaultDir returns the cache directory: $STAGECRAFT_CACHE_DIR, else
+// "stagecraft" in the user cache directory (~/.cache/stagecraft on Linux).
+func DefaultDir() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locating the user cache directory (set %s): %w", DirEnv, err)
+	}
+	return filepath.Join(base, "stagecraft"), nil
+}
+
+// Cache stores entries as files under dir/<bucket>/<key>. An entry's
+// modification time is when it was stored. A nil *Cache caches nothing:
+// Get finds no entries and Put discards them.
+type Cache struct {
+	dir string
+	now func() time.Time
+}
+
+// New returns a cache rooted at dir. The directory is created on first Put.
+func New(dir string) *Cache {
+	return &Cache{dir: dir, now: time.Now}
+}
+
+// Default returns the cache in DefaultDir, or nil when no cache directory
+// can be determined.
+func Default() *Cache {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil
+	}
+	return New(dir)
+}
+
+// Dir returns the cache's root directory.
+func (c *Cache) Dir() string {
+	if c == nil {
+		return ""
+	}
+	return c.dir
+}
+
+// Entry is a cached value and when it was stored.
+type Entry struct {
+	Data     []byte
+	StoredAt time.Time
+}
+
+// Fresh reports whether the entry was stored within ttl of now. A nil
+// entry or non-positive ttl is never fresh.
+func (e *Entry) Fresh(now time.Time, ttl time.Duration) bool {
+	if e == nil || ttl <= 0 {
+		return false
+	}
+	return now.Sub(e.StoredAt) < ttl
+}
+
+// Decode unmarshals the entry's JSON data into v.
+func (e *Entry) Decode(v any) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("decoding cache entry: %w", err)
+	}
+	return nil
+}
+
+// Get returns the entry stored under key in bucket, or nil when there is
+// none.
+func (c *Cache) Get(bucket, key string) (*Entry, error) {
+	if c == nil {
+		return nil, nil
+	}
+	path, err := c.path(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache entry %s/%s: %w", bucket, key, err)
+	}
+	// #nosec G304 -- path is built from a validated bucket and escaped key
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading cache entry %s/%s: %w", bucket, key, err)
+	}
+	return &Entry{Data: data, StoredAt: info.ModTime()}, nil
+}
+
+// Put stores data under key in bucket, stamped with the current time,
+// replacing any previous entry. Entries are readable by the owner only.
+func (c *Cache) Put(bucket, key string, data []byte) error {
+	if c == nil {
+		return nil
+	}
+	path, err := c.path(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	// Write to a temporary file and rename it, so readers never see a
+	// partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("writing cache entry %s/%s: %w", bucket, key, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		now := c.now()
+		err = os.Chtimes(tmp.Name(), now, now)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("writing cache entry %s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
+
+// PutJSON stores v as JSON under key in bucket.
+func (c *Cache) PutJSON(bucket, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding cache entry %s/%s: %w", bucket, key, err)
+	}
+	return c.Put(bucket, key, data)
+}
+
+// BucketInfo summarizes the entries of one bucket.
+type BucketInfo struct {
+	Name    string
+	Entries int
+	Bytes   int64
+
+	// Expired counts entries older than the bucket's TTL.
+	Expired int
+
+	// Oldest and Newest are when the oldest and newest entries were
+	// stored; zero for an empty bucket.
+	Oldest time.Time
+	Newest time.Time
+}
+
+// Info summarizes every bucket in the cache directory, sorted by name. A
+// missing directory has no buckets.
+func (c *Cache) Info() ([]BucketInfo, error) {
+	buckets, err := c.buckets()
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	infos := make([]BucketInfo, 0, len(buckets))
+	for _, bucket := range buckets {
+		info := BucketInfo{Name: bucket}
+		ttl := TTL(bucket)
+		err := c.walk(bucket, func(_ string, fi fs.FileInfo) error {
+			stored := fi.ModTime()
+			info.Entries++
+			info.Bytes += fi.Size()
+			if ttl > 0 && now.Sub(stored) >= ttl {
+				info.Expired++
+			}
+			if info.Oldest.IsZero() || stored.Before(info.Oldest) {
+				info.Oldest = stored
+			}
+			if stored.After(info.Newest) {
+				info.Newest = stored
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// Clear removes the given buckets, or every bucket when none are given,
+// and returns the number of entries removed.
+func (c *Cache) Clear(buckets ...string) (int, error) {
+	if len(buckets) == 0 {
+		all, err := c.buckets()
+		if err != nil {
+			return 0, err
+		}
+		buckets = all
+	}
+
+	removed := 0
+	for _, bucket := range buckets {
+		if err := validSegment(bucket); err != nil {
+			return removed, fmt.Errorf("invalid cache bucket %q: %w", bucket, err)
+		}
+		n := 0
+		if err := c.walk(bucket, func(string, fs.FileInfo) error { n++; return nil }); err != nil {
+			return removed, err
+		}
+		if err := os.RemoveAll(filepath.Join(c.dir, bucket)); err != nil {
+			return removed, fmt.Errorf("clearing cache bucket %s: %w", bucket, err)
+		}
+		removed += n
+	}
+	return removed, nil
+}
+
+// Prune removes entries older than their bucket's TTL and returns the
+// number removed.
+func (c *Cache) Prune() (int, error) {
+	buckets, err := c.buckets()
+	if err != nil {
+		return 0, err
+	}
+
+	now := c.now()
+	removed := 0
+	for _, bucket := range buckets {
+		ttl := TTL(bucket)
+		if ttl <= 0 {
+			continue
+		}
+		err := c.walk(bucket, func(path string, fi fs.FileInfo) error {
+			if now.Sub(fi.ModTime()) < ttl {
+				return nil
+			}
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("pruning cache entry: %w", err)
+			}
+			removed++
+			return nil
+		})
+		if err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// buckets returns the bucket directories present in the cache, sorted.
+func (c *Cache) buckets() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	var buckets []string
+	for _, e := range entries {
+		if e.IsDir() && validSegment(e.Name()) == nil {
+			buckets = append(buckets, e.Name())
+		}
+	}
+	sort.Strings(buckets)
+	return buckets, nil
+}
+
+// walk calls fn for every entry of bucket. A missing bucket has none.
+func (c *Cache) walk(bucket string, fn func(path string, fi fs.FileInfo) error) error {
+	root := filepath.Join(c.dir, bucket)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, os.ErrNotExist) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(path, fi)
+	})
+	if err != nil {
+		return fmt.Errorf("reading cache bucket %s: %w", bucket, err)
+	}
+	return nil
+}
+
+// path returns the file of key in bucket. Keys are slash-separated; each
+// segment is escaped, so any project or environment name is a valid key.
+func (c *Cache) path(bucket, key string) (string, error) {
+	if err := validSegment(bucket); err != nil {
+		return "", fmt.Errorf("invalid cache bucket %q: %w", bucket, err)
+	}
+	parts := []string{c.dir, bucket}
+	for _, segment := range strings.Split(key, "/") {
+		escaped := url.QueryEscape(segment)
+		if err := validSegment(escaped); err != nil {
+			return "", fmt.Errorf("invalid cache key %q: %w", key, err)
+		}
+		parts = append(parts, escaped)
+	}
+	return filepath.Join(parts...), nil
+}
+
+// validSegment checks that s is usable as a single path element.
+func validSegment(s string) error {
+	switch {
+	case s == "":
+		return errors.New("empty path segment")
+	case strings.HasPrefix(s, "."):
+		return errors.New("path segment must not start with a dot")
+	case strings.ContainsAny(s, `/\`):
+		return errors.New("path segment must not contain a separator")
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Feature: CORE_CACHE
+// Spec: spec/core/cache.md
+
+// newTestCache returns a cache in a temporary directory whose clock reads
+// *now.
+func newTestCache(t *testing.T, now *time.Time) *Cache {
+	t.Helper()
+	c := New(filepath.Join(t.TempDir(), "cache"))
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestCache_PutGet(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newTestCache(t, &now)
+
+	if got, err := c.Get(BucketHosts, "myapp/prod"); err != nil || got != nil {
+		t.Fatalf("Get() on empty cache = %v, %v; want nil, nil", got, err)
+	}
+
+	type hosts struct{ Names []string }
+	if err := c.PutJSON(BucketHosts, "myapp/prod", hosts{Names: []string{"app-1"}}); err != nil {
+		t.Fatalf("PutJSON() error = %v", err)
+	}
+
+	entry, err := c.Get(BucketHosts, "myapp/prod")
+	if err != nil || entry == nil {
+		t.Fatalf("Get() = %v, %v; want the stored entry", entry, err)
+	}
+	if !entry.StoredAt.Equal(now) {
+		t.Errorf("StoredAt = %v, want %v", entry.StoredAt, now)
+	}
+	var got hosts
+	if err := entry.Decode(&got); err != nil || len(got.Names) != 1 || got.Names[0] != "app-1" {
+		t.Errorf("Decode() = %+v, %v", got, err)
+	}
+
+	info, err := os.Stat(filepath.Join(c.Dir(), "hosts", "myapp", "prod"))
+	if err != nil {
+		t.Fatalf("entry file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("entry mode = %v, want 0600", perm)
+	}
+}
+
+func TestCache_KeysAreEscaped(t *testing.T) {
+	now := time.Now()
+	c := newTestCache(t, &now)
+
+	if err := c.Put(BucketHosts, "my app/../prod", []byte("x")); err == nil {
+		t.Error("expected a .. key segment to be rejected")
+	}
+	if err := c.Put("../hosts", "prod", []byte("x")); err == nil {
+		t.Error("expected an invalid bucket to be rejected")
+	}
+
+	if err := c.Put(BucketHosts, `my app\x/prod`, []byte("x")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(c.Dir(), "hosts", `my+app%5Cx`, "prod")); err != nil {
+		t.Errorf("expected escaped entry path: %v", err)
+	}
+}
+
+func TestEntry_Fresh(t *testing.T) {
+	stored := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := &Entry{StoredAt: stored}
+
+	if !entry.Fresh(stored.Add(time.Minute), 5*time.Minute) {
+		t.Error("expected entry to be fresh within TTL")
+	}
+	if entry.Fresh(stored.Add(5*time.Minute), 5*time.Minute) {
+		t.Error("expected entry to be stale at TTL")
+	}
+	if entry.Fresh(stored, 0) {
+		t.Error("expected zero TTL to disable the cache")
+	}
+
+	var missing *Entry
+	if missing.Fresh(stored, time.Hour) {
+		t.Error("expected nil entry to be stale")
+	}
+}
+
+func TestCache_InfoPruneClear(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	c := newTestCache(t, &now)
+
+	put := func(bucket, key string) {
+		t.Helper()
+		if err := c.Put(bucket, key, []byte("12345")); err != nil {
+			t.Fatalf("Put(%s, %s) error = %v", bucket, key, err)
+		}
+	}
+	put(BucketHosts, "myapp/staging")
+	put(BucketPricing, "digitalocean")
+	now = start.Add(time.Hour)
+	put(BucketHosts, "myapp/prod")
+	put("custom", "entry")
+
+	infos, err := c.Info()
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if len(infos) != 3 || infos[0].Name != "custom" || infos[1].Name != BucketHosts || infos[2].Name != BucketPricing {
+		t.Fatalf("Info() = %+v, want custom, hosts and pricing", infos)
+	}
+	hosts := infos[1]
+	if hosts.Entries != 2 || hosts.Bytes != 10 || hosts.Expired != 1 || !hosts.Oldest.Equal(start) || !hosts.Newest.Equal(now) {
+		t.Errorf("hosts bucket = %+v", hosts)
+	}
+	if infos[2].Expired != 0 {
+		t.Errorf("pricing entry expired after an hour: %+v", infos[2])
+	}
+
+	removed, err := c.Prune()
+	if err != nil || removed != 1 {
+		t.Fatalf("Prune() = %d, %v; want the stale host inventory removed", removed, err)
+	}
+	if entry, _ := c.Get(BucketHosts, "myapp/staging"); entry != nil {
+		t.Error("expected the stale entry to be pruned")
+	}
+	if entry, _ := c.Get("custom", "entry"); entry == nil {
+		t.Error("expected entries of buckets without a TTL to be kept")
+	}
+
+	removed, err = c.Clear(BucketPricing)
+	if err != nil || removed != 1 {
+		t.Fatalf("Clear(pricing) = %d, %v", removed, err)
+	}
+	removed, err = c.Clear()
+	if err != nil || removed != 2 {
+		t.Fatalf("Clear() = %d, %v; want the remaining 2 entries", removed, err)
+	}
+	if infos, err := c.Info(); err != nil || len(infos) != 0 {
+		t.Errorf("Info() after Clear() = %+v, %v; want no buckets", infos, err)
+	}
+}
+
+func TestCache_Nil(t *testing.T) {
+	var c *Cache
+	if err := c.Put(BucketHosts, "myapp/prod", []byte("x")); err != nil {
+		t.Errorf("nil Put() error = %v", err)
+	}
+	if entry, err := c.Get(BucketHosts, "myapp/prod"); entry != nil || err != nil {
+		t.Errorf("nil Get() = %v, %v; want nil, nil", entry, err)
+	}
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv(DirEnv, "/tmp/stagecraft-cache")
+	if dir, err := DefaultDir(); err != nil || dir != "/tmp/stagecraft-cache" {
+		t.Errorf("DefaultDir() = %q, %v; want $%s", dir, err, DirEnv)
+	}
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+	t.Setenv(DirEnv, "")
+	t.Setenv("XDG_CACHE_HOME", "/tmp/xdg")
+	t.Setenv("HOME", "/tmp/home")
+	want := filepath.Join("/tmp/xdg", "stagecraft")
+	if dir, err := DefaultDir(); err != nil || dir != want {
+		t.Errorf("DefaultDir() = %q, %v; want %q", dir, err, want)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"stagecraft/internal/agent"
+	"stagecraft/internal/cli/output"
 	"stagecraft/pkg/engine"
 )
 
@@ -30,10 +31,12 @@ func NewAgentCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "agent",
 		Short: "Agent commands for executing HostPlans",
-		Long:  "Commands for running HostPlans locally (for testing the CLI → Engine → Agent pipeline)",
+		Long:  "Commands for running HostPlans locally, serving them as a daemon on deploy targets, and sending them to a remote agent",
 	}
 
 	cmd.AddCommand(NewAgentRunCommand())
+	cmd.AddCommand(NewAgentServeCommand())
+	cmd.AddCommand(NewAgentSendCommand())
 
 	return cmd
 }
@@ -48,7 +51,7 @@ func NewAgentRunCommand() *cobra.Command {
 	}
 
 	cmd.Flags().String("hostplan", "", "Path to HostPlan JSON file (required)")
-	cmd.Flags().String("output", "", "Path to write execution report JSON (default: stdout)")
+	cmd.Flags().String("report", "", "Path to write execution report JSON (default: stdout)")
 	_ = cmd.MarkFlagRequired("hostplan")
 
 	return cmd
@@ -56,32 +59,22 @@ func NewAgentRunCommand() *cobra.Command {
 
 func runAgentRun(cmd *cobra.Command, args []string) error {
 	hostplanPath, _ := cmd.Flags().GetString("hostplan")
-	outputPath, _ := cmd.Flags().GetString("output")
-
-	// Load HostPlan with strict validation
-	// #nosec G304 // path is user/config selected; intentional.
-	data, err := os.ReadFile(filepath.Clean(hostplanPath))
-	if err != nil {
-		return fmt.Errorf("reading host plan file %q: %w", hostplanPath, err)
-	}
-
-	var hostPlan engine.HostPlan
-	// Try to extract planID from JSON for better error context (best-effort)
-	var planID string
-	if tempPlan := struct {
-		PlanID string `json:"planId"`
-	}{}; json.Unmarshal(data, &tempPlan) == nil {
-		planID = tempPlan.PlanID
-	}
-
-	if err := engine.UnmarshalStrictHostPlan(data, &hostPlan, planID); err != nil {
-		// Wrap error with file path context for debugging
-		return fmt.Errorf("unmarshaling host plan from %q: %w", hostplanPath, err)
+	reportPath, _ := cmd.Flags().GetString("report")
+	if reportPath == "" {
+		// --output used to be a local flag taking the report path. The
+		// global --output now reaches this command, so a value that is not
+		// an output format is still taken as that path, with a warning.
+		if legacy, _ := cmd.Flags().GetString("output"); legacy != "" {
+			if _, err := output.ParseFormat(legacy); err != nil {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Flag --output as a report path is deprecated, use --report instead")
+				reportPath = legacy
+			}
+		}
 	}
 
-	// Validate HostPlan has non-empty LogicalID (required for HostPlans)
-	if hostPlan.Host.LogicalID == "" {
-		return fmt.Errorf("host plan from %q has empty host.logicalId (required for HostPlans)", hostplanPath)
+	hostPlan, err := loadHostPlanFile(hostplanPath)
+	if err != nil {
+		return err
 	}
 
 	// Create executor with stub executors for all actions
@@ -107,7 +100,44 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("executing host plan: %w", err)
 	}
 
-	// Output report
+	return writeExecutionReport(cmd, report, reportPath)
+}
+
+// loadHostPlanFile reads and strictly decodes a HostPlan JSON file. The plan
+// must carry a non-empty host.logicalId.
+func loadHostPlanFile(path string) (engine.HostPlan, error) {
+	var hostPlan engine.HostPlan
+
+	// #nosec G304 // path is user/config selected; intentional.
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return hostPlan, fmt.Errorf("reading host plan file %q: %w", path, err)
+	}
+
+	// Try to extract planID from JSON for better error context (best-effort)
+	var planID string
+	if tempPlan := struct {
+		PlanID string `json:"planId"`
+	}{}; json.Unmarshal(data, &tempPlan) == nil {
+		planID = tempPlan.PlanID
+	}
+
+	if err := engine.UnmarshalStrictHostPlan(data, &hostPlan, planID); err != nil {
+		// Wrap error with file path context for debugging
+		return hostPlan, fmt.Errorf("unmarshaling host plan from %q: %w", path, err)
+	}
+
+	// Validate HostPlan has non-empty LogicalID (required for HostPlans)
+	if hostPlan.Host.LogicalID == "" {
+		return hostPlan, fmt.Errorf("host plan from %q has empty host.logicalId (required for HostPlans)", path)
+	}
+
+	return hostPlan, nil
+}
+
+// writeExecutionReport prints report as JSON to stdout, or writes it to
+// outputPath when set.
+func writeExecutionReport(cmd *cobra.Command, report *engine.ExecutionReport, outputPath string) error {
 	reportJSON, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling execution report: %w", err)
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/agent"
+	"stagecraft/internal/plansign"
+	"stagecraft/pkg/engine"
+)
+
+// Feature: AGENT_DAEMON
+// Spec: spec/engine/agent-daemon.md
+
+// defaultAgentListenAddr is the address `stagecraft agent serve` binds by default.
+const defaultAgentListenAddr = ":7420"
+
+// NewAgentServeCommand returns the `stagecraft agent serve` command.
+func NewAgentServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the agent daemon on a deploy target",
+		Long: `Runs a long-lived agent that accepts signed HostPlan bundles over HTTP,
+executes apply_compose and migrate steps locally, and returns an execution
+report for each plan.
+
+Bundles are signed with the shared secret in ` + agent.SecretEnv + `. When
+` + plansign.VerifyKeyEnv + ` or ` + plansign.VerifyKeyFileEnv + ` is set, bundles must
+also carry an ed25519 plan signature that verifies against that public key.
+By default only loopback and Tailscale (100.64.0.0/10, fd7a:115c:a1e0::/48)
+peers are accepted.`,
+		Args: cobra.NoArgs,
+		RunE: runAgentServe,
+	}
+
+	cmd.Flags().String("listen", defaultAgentListenAddr, "Address to listen on")
+	cmd.Flags().String("host-id", "", "Logical host ID this agent serves (required)")
+	cmd.Flags().String("workdir", ".", "Directory relative compose and migration paths resolve against")
+	cmd.Flags().String("state-file", "", "Path recording succeeded steps for idempotent re-execution (default: <workdir>/.stagecraft/agent-state.json)")
+	cmd.Flags().Duration("step-timeout", 0, "Cancel a step attempt that runs longer than this (0 for no limit)")
+	cmd.Flags().Bool("tailnet-only", true, "Reject peers outside loopback and the Tailscale address ranges")
+	_ = cmd.MarkFlagRequired("host-id")
+
+	return cmd
+}
+
+func runAgentServe(cmd *cobra.Command, args []string) error {
+	listen, _ := cmd.Flags().GetString("listen")
+	hostID, _ := cmd.Flags().GetString("host-id")
+	workDir, _ := cmd.Flags().GetString("workdir")
+	stateFile, _ := cmd.Flags().GetString("state-file")
+	tailnetOnly, _ := cmd.Flags().GetBool("tailnet-only")
+	stepTimeout, _ := cmd.Flags().GetDuration("step-timeout")
+
+	secret := os.Getenv(agent.SecretEnv)
+	if secret == "" {
+		return fmt.Errorf("agent serve: %s must be set", agent.SecretEnv)
+	}
+
+	verifyKey, _, err := plansign.LoadVerifyKey()
+	if err != nil {
+		return fmt.Errorf("agent serve: loading plan verify key: %w", err)
+	}
+
+	absWorkDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return fmt.Errorf("agent serve: resolving workdir: %w", err)
+	}
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("agent serve: %w", err)
+	}
+	logger, err := newLogger(flags)
+	if err != nil {
+		return fmt.Errorf("agent serve: %w", err)
+	}
+
+	executor := agent.NewExecutor()
+	for action, stepExecutor := range localStepExecutors(absWorkDir) {
+		executor.RegisterExecutor(action, stepExecutor)
+	}
+	if stateFile == "" {
+		stateFile = filepath.Join(absWorkDir, ".stagecraft", "agent-state.json")
+	}
+	executor.SetIdempotencyStore(agent.NewFileIdempotencyStore(stateFile))
+	executor.SetStepTimeouts(engine.StepTimeouts{Default: stepTimeout})
+
+	server := &agent.Server{
+		Executor:    executor,
+		Secret:      []byte(secret),
+		VerifyKey:   verifyKey,
+		HostID:      hostID,
+		TailnetOnly: tailnetOnly,
+		Logger:      logger,
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	addr, err := server.Serve(ctx, listen)
+	if err != nil {
+		return fmt.Errorf("agent serve: %w", err)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Agent for host %s listening on %s\n", hostID, addr)
+
+	<-ctx.Done()
+	return nil
+}
+
+// NewAgentSendCommand returns the `stagecraft agent send` command.
+func NewAgentSendCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send a HostPlan to a remote agent",
+		Long: `Signs a HostPlan with the shared secret in ` + agent.SecretEnv + `, submits it to
+the agent at --addr, and prints the execution report the agent returns.
+When ` + plansign.SigningKeyEnv + ` or ` + plansign.SigningKeyFileEnv + ` is set, the
+plan is also signed with that ed25519 key. Exits non-zero when any step fails.`,
+		Args: cobra.NoArgs,
+		RunE: runAgentSend,
+	}
+
+	cmd.Flags().String("addr", "", "Agent base URL, e.g. http://host.tailnet.ts.net:7420 (required)")
+	cmd.Flags().String("hostplan", "", "Path to HostPlan JSON file (required)")
+	cmd.Flags().String("report", "", "Path to write execution report JSON (default: stdout)")
+	cmd.Flags().Duration("timeout", 30*time.Minute, "Maximum time to wait for the agent to finish the plan")
+	_ = cmd.MarkFlagRequired("addr")
+	_ = cmd.MarkFlagRequired("hostplan")
+
+	return cmd
+}
+
+func runAgentSend(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	hostplanPath, _ := cmd.Flags().GetString("hostplan")
+	reportPath, _ := cmd.Flags().GetString("report")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	secret := os.Getenv(agent.SecretEnv)
+	if secret == "" {
+		return fmt.Errorf("agent send: %s must be set", agent.SecretEnv)
+	}
+
+	hostPlan, err := loadHostPlanFile(hostplanPath)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := agent.SignBundle(hostPlan, []byte(secret))
+	if err != nil {
+		return fmt.Errorf("agent send: %w", err)
+	}
+
+	signingKey, sign, err := plansign.LoadSigningKey()
+	if err != nil {
+		return fmt.Errorf("agent send: loading plan signing key: %w", err)
+	}
+	if sign {
+		if err := bundle.SignPlan(signingKey); err != nil {
+			return fmt.Errorf("agent send: %w", err)
+		}
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	client := &agent.Client{BaseURL: addr, HTTPClient: &http.Client{Timeout: timeout}}
+	report, err := client.Submit(ctx, bundle)
+	if err != nil {
+		return fmt.Errorf("agent send: %w", err)
+	}
+
+	if err := writeExecutionReport(cmd, report, reportPath); err != nil {
+		return err
+	}
+	if report.Status == engine.ExecStatusFailed {
+		return fmt.Errorf("agent send: plan %s failed on host %s", report.PlanID, hostPlan.Host.LogicalID)
+	}
+	return nil
+}
+
+// localStepExecutors returns the executors for the step actions that run on
+// the local host, with relative paths resolving against workDir. Shared by
+// `agent serve` and `engine run`; replaced in tests.
+var localStepExecutors = func(workDir string) map[engine.StepAction]agent.StepExecutor {
+	return map[engine.StepAction]agent.StepExecutor{
+		engine.StepActionApplyCompose: &agent.ComposeExecutor{WorkDir: workDir},
+		engine.StepActionMigrate:      &agent.MigrateExecutor{WorkDir: workDir},
+	}
+}
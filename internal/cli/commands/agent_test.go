@@ -13,12 +13,17 @@ See https://www.gnu.org/licenses/ for license details.
 package commands
 
 import (
+	"bytes"
 	"encoding/json"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/agent"
 	"stagecraft/pkg/engine"
 )
 
@@ -134,10 +139,10 @@ func TestRunAgentRun_AcceptsValidHostPlan(t *testing.T) {
 		t.Fatalf("failed to write test hostplan: %v", err)
 	}
 
-	// Create a command with the hostplan flag set and output to temp file
-	outputPath := filepath.Join(tmpDir, "report.json")
+	// Create a command with the hostplan flag set and report to temp file
+	reportPath := filepath.Join(tmpDir, "report.json")
 	cmd := NewAgentRunCommand()
-	cmd.SetArgs([]string{"--hostplan", hostplanPath, "--output", outputPath})
+	cmd.SetArgs([]string{"--hostplan", hostplanPath, "--report", reportPath})
 
 	// Execute - should succeed
 	err = cmd.Execute()
@@ -146,7 +151,139 @@ func TestRunAgentRun_AcceptsValidHostPlan(t *testing.T) {
 	}
 
 	// Verify report was written
-	if _, err := os.Stat(outputPath); err != nil {
-		t.Fatalf("expected execution report to be written to %s: %v", outputPath, err)
+	if _, err := os.Stat(reportPath); err != nil {
+		t.Fatalf("expected execution report to be written to %s: %v", reportPath, err)
+	}
+}
+
+func TestRunAgentRun_GlobalOutputFlag(t *testing.T) {
+	tmpDir := t.TempDir()
+	hostplanPath := filepath.Join(tmpDir, "test-hostplan.json")
+	plan := `{"version": "v1", "planId": "test-plan", "host": {"logicalId": "host-a"}, "steps": []}`
+	if err := os.WriteFile(hostplanPath, []byte(plan), 0o600); err != nil {
+		t.Fatalf("failed to write test hostplan: %v", err)
+	}
+	t.Chdir(tmpDir)
+
+	execute := func(args ...string) (*cobra.Command, string, string) {
+		t.Helper()
+		root := newTestRootCommand()
+		runCmd := NewAgentRunCommand()
+		agentCmd := &cobra.Command{Use: "agent"}
+		agentCmd.AddCommand(runCmd)
+		root.AddCommand(agentCmd)
+		var stdout, stderr bytes.Buffer
+		root.SetOut(&stdout)
+		root.SetErr(&stderr)
+		root.SetArgs(append([]string{"agent", "run", "--hostplan", hostplanPath}, args...))
+		if err := root.Execute(); err != nil {
+			t.Fatalf("agent run %v: %v", args, err)
+		}
+		return runCmd, stdout.String(), stderr.String()
+	}
+
+	// -o selects the output format; it is not taken as a report path.
+	runCmd, stdout, _ := execute("-o", "json")
+	flags, err := ResolveFlags(runCmd, nil)
+	if err != nil {
+		t.Fatalf("resolving flags: %v", err)
+	}
+	if flags.Output != "json" {
+		t.Errorf("expected output format json, got %q", flags.Output)
+	}
+	var report engine.ExecutionReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("expected JSON report on stdout, got %q: %v", stdout, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "json")); !os.IsNotExist(err) {
+		t.Errorf("expected no report file named json, got %v", err)
+	}
+
+	// A non-format --output value is still the deprecated report path.
+	_, stdout, stderr := execute("--output", "legacy.json")
+	if _, err := os.Stat(filepath.Join(tmpDir, "legacy.json")); err != nil {
+		t.Errorf("expected report written to legacy.json: %v", err)
+	}
+	if !strings.Contains(stdout, "Execution report written to legacy.json") {
+		t.Errorf("expected report path on stdout, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "use --report instead") {
+		t.Errorf("expected deprecation warning, got %q", stderr)
+	}
+}
+
+func TestRunAgentSend_SubmitsSignedPlan(t *testing.T) {
+	t.Setenv(agent.SecretEnv, "s3cret")
+
+	executor := agent.NewExecutor()
+	executor.RegisterExecutor(engine.StepActionBuild, &agent.StubExecutor{})
+	srv := httptest.NewServer((&agent.Server{
+		Executor: executor,
+		Secret:   []byte("s3cret"),
+		HostID:   "host-a",
+	}).Handler())
+	defer srv.Close()
+
+	tmpDir := t.TempDir()
+	hostplanPath := filepath.Join(tmpDir, "test-hostplan.json")
+	hostPlan := engine.HostPlan{
+		Version: engine.HostPlanSchemaVersion,
+		PlanID:  "test-plan",
+		Host:    engine.HostRef{LogicalID: "host-a"},
+		Steps: []engine.HostPlanStep{
+			{
+				ID:     "step-1",
+				Action: engine.StepActionBuild,
+				Target: engine.ResourceRef{Kind: "image", Name: "test", Provider: "stagecraft"},
+				Inputs: json.RawMessage(`{"provider": "generic", "workdir": "apps/backend", "dockerfile": "Dockerfile", "context": "."}`),
+			},
+		},
+	}
+	jsonBytes, err := json.Marshal(hostPlan)
+	if err != nil {
+		t.Fatalf("failed to marshal hostplan: %v", err)
+	}
+	if err := os.WriteFile(hostplanPath, jsonBytes, 0o600); err != nil {
+		t.Fatalf("failed to write test hostplan: %v", err)
+	}
+
+	var out bytes.Buffer
+	cmd := NewAgentSendCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--addr", srv.URL, "--hostplan", hostplanPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error sending hostplan: %v", err)
+	}
+
+	var report engine.ExecutionReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("expected JSON report on stdout, got %q: %v", out.String(), err)
+	}
+	if report.PlanID != "test-plan" || report.Status != engine.ExecStatusSucceeded {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	reportPath := filepath.Join(tmpDir, "report.json")
+	cmd = NewAgentSendCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--addr", srv.URL, "--hostplan", hostplanPath, "--report", reportPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error sending hostplan with --report: %v", err)
+	}
+	if _, err := os.Stat(reportPath); err != nil {
+		t.Errorf("expected report file: %v", err)
+	}
+}
+
+func TestRunAgentSend_RequiresSecret(t *testing.T) {
+	t.Setenv(agent.SecretEnv, "")
+
+	cmd := NewAgentSendCommand()
+	cmd.SetArgs([]string{"--addr", "http://127.0.0.1:1", "--hostplan", "plan.json"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), agent.SecretEnv) {
+		t.Fatalf("expected missing secret error, got %v", err)
 	}
 }
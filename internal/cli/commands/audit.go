@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/core/audit"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: CORE_AUDIT_LOG
+// Spec: spec/core/audit.md
+
+// newAuditLog constructs the audit backend; tests may override it.
+var newAuditLog = func() audit.Backend {
+	return audit.NewDefaultLog()
+}
+
+// NewAuditCommand returns the `stagecraft audit` command group.
+func NewAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit log of mutating commands",
+		Long:  "View the append-only audit log recording who ran deploy, rollback and other mutating commands",
+	}
+
+	cmd.AddCommand(newAuditListCommand())
+
+	return cmd
+}
+
+func newAuditListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List audit log entries, newest first",
+		Args:  cobra.NoArgs,
+		RunE:  runAuditList,
+	}
+
+	cmd.Flags().String("command", "", "only show entries for this command (e.g. deploy)")
+	cmd.Flags().Int("limit", 0, "maximum number of entries to show (0 shows all)")
+	// --env flag inherited from root filters by environment when set
+
+	return cmd
+}
+
+// auditListView is the structured (json/yaml) form of `audit list`.
+type auditListView struct {
+	Entries []audit.Entry `json:"entries" yaml:"entries"`
+}
+
+func runAuditList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("resolving flags: %w", err)
+	}
+
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	filter := audit.Filter{}
+	if cmd.Flags().Changed("env") {
+		filter.Environment = flags.Env
+	}
+	filter.Command, _ = cmd.Flags().GetString("command")
+	filter.Limit, _ = cmd.Flags().GetInt("limit")
+	if filter.Limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	entries, err := newAuditLog().List(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("reading audit log: %w", err)
+	}
+
+	// Newest first, matching `releases list`.
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	return output.Render(cmd.OutOrStdout(), format, auditListView{Entries: entries}, func(w io.Writer) error {
+		return displayAuditList(w, entries)
+	})
+}
+
+func displayAuditList(out io.Writer, entries []audit.Entry) error {
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintf(out, "No audit entries found\n")
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "%-19s %-12s %-10s %-12s %-24s %s\n", "TIMESTAMP", "ACTOR", "COMMAND", "ENVIRONMENT", "RELEASE ID", "OUTCOME")
+	for _, e := range entries {
+		releaseID := e.ReleaseID
+		if releaseID == "" {
+			releaseID = "-"
+		}
+		_, _ = fmt.Fprintf(out, "%-19s %-12s %-10s %-12s %-24s %s\n",
+			formatTimestamp(e.Timestamp), e.Actor, e.Command, e.Environment, releaseID, e.Outcome)
+	}
+
+	return nil
+}
+
+// recordAudit appends an audit entry for a mutating command. Failures to
+// write the audit log are reported as warnings and never change the
+// command's result.
+func recordAudit(ctx context.Context, cmd *cobra.Command, logger logging.Logger, env, releaseID string, runErr error) {
+	entry := audit.Entry{
+		Timestamp:   time.Now().UTC(),
+		Actor:       audit.ResolveActor(),
+		Command:     auditCommandName(cmd),
+		Environment: env,
+		Flags:       changedFlags(cmd),
+		ReleaseID:   releaseID,
+		Outcome:     audit.OutcomeSuccess,
+	}
+	if runErr != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = runErr.Error()
+	}
+
+	if err := newAuditLog().Append(ctx, entry); err != nil {
+		logger.Warn("Failed to write audit log", logging.NewField("error", err.Error()))
+	}
+}
+
+// auditCommandName returns the command path without the root command name,
+// e.g. "deploy" or "hosts apply".
+func auditCommandName(cmd *cobra.Command) string {
+	path := cmd.CommandPath()
+	if cmd.HasParent() {
+		rootName := cmd.Root().Name()
+		if len(path) > len(rootName)+1 {
+			return path[len(rootName)+1:]
+		}
+	}
+	return path
+}
+
+// changedFlags returns the flags explicitly set on the command line, local
+// and inherited, keyed by name.
+func changedFlags(cmd *cobra.Command) map[string]string {
+	set := map[string]string{}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		set[f.Name] = f.Value.String()
+	})
+	if len(set) == 0 {
+		return nil
+	}
+	return set
+}
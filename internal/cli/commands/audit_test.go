@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/audit"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: CORE_AUDIT_LOG
+// Spec: spec/core/audit.md
+
+func writeAuditTestConfig(t *testing.T, dir string) {
+	t.Helper()
+	configContent := `project:
+  name: test-app
+backend:
+  provider: generic
+  providers:
+    generic:
+      build:
+        dockerfile: "./Dockerfile"
+        context: "."
+environments:
+  staging:
+    driver: local
+`
+	if err := os.WriteFile(filepath.Join(dir, "stagecraft.yml"), []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+}
+
+func TestDeployCommand_RecordsAuditEntry(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	writeAuditTestConfig(t, env.TempDir)
+	t.Setenv("STAGECRAFT_ACTOR", "alice")
+
+	noop := func(ctx context.Context, plan *core.Plan, logger logging.Logger) error { return nil }
+	fns := PhaseFns{Build: noop, Push: noop, MigratePre: noop, Rollout: noop, MigratePost: noop, Finalize: noop}
+
+	if err := executeDeployWithPhases(fns, "deploy", "--env", "staging", "--version", "v1.2.3"); err != nil {
+		t.Fatalf("deploy failed: %v", err)
+	}
+
+	entries, err := audit.NewDefaultLog().List(env.Ctx, audit.Filter{})
+	if err != nil {
+		t.Fatalf("listing audit entries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(entries))
+	}
+
+	releases, err := env.Manager.ListReleases(env.Ctx, "staging")
+	if err != nil || len(releases) != 1 {
+		t.Fatalf("expected one release, got %d (err=%v)", len(releases), err)
+	}
+
+	e := entries[0]
+	if e.Actor != "alice" || e.Command != "deploy" || e.Environment != "staging" {
+		t.Errorf("unexpected audit entry: %+v", e)
+	}
+	if e.ReleaseID != releases[0].ID {
+		t.Errorf("expected release ID %q, got %q", releases[0].ID, e.ReleaseID)
+	}
+	if e.Outcome != audit.OutcomeSuccess {
+		t.Errorf("expected success outcome, got %q", e.Outcome)
+	}
+	if e.Flags["version"] != "v1.2.3" || e.Flags["env"] != "staging" {
+		t.Errorf("expected explicit flags to be recorded, got %v", e.Flags)
+	}
+}
+
+func TestDeployCommand_DryRunNotAudited(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	writeAuditTestConfig(t, env.TempDir)
+
+	if err := executeDeployWithPhases(defaultPhaseFns, "deploy", "--env", "staging", "--dry-run"); err != nil {
+		t.Fatalf("dry-run deploy failed: %v", err)
+	}
+
+	entries, err := audit.NewDefaultLog().List(env.Ctx, audit.Filter{})
+	if err != nil {
+		t.Fatalf("listing audit entries: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected dry-run to leave no audit entries, got %+v", entries)
+	}
+}
+
+func TestStateRepair_RecordsAuditEntry(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	if _, err := env.Manager.CreateRelease(env.Ctx, "prod", "v1", "sha1"); err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+	t.Setenv("STAGECRAFT_ACTOR", "alice")
+
+	root := newTestRootCommand()
+	root.AddCommand(NewStateCommand())
+	if out, err := executeCommandForGolden(root, "state", "repair"); err != nil {
+		t.Fatalf("state repair failed: %v\n%s", err, out)
+	}
+
+	entries, err := audit.NewDefaultLog().List(env.Ctx, audit.Filter{})
+	if err != nil {
+		t.Fatalf("listing audit entries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(entries))
+	}
+	if e := entries[0]; e.Actor != "alice" || e.Command != "state repair" || e.Outcome != audit.OutcomeSuccess {
+		t.Errorf("unexpected audit entry: %+v", e)
+	}
+}
+
+func TestAuditList_FiltersAndFormats(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+
+	log := audit.NewDefaultLog()
+	for _, e := range []audit.Entry{
+		{Actor: "alice", Command: "deploy", Environment: "staging", ReleaseID: "rel-1", Outcome: audit.OutcomeSuccess},
+		{Actor: "bob", Command: "rollback", Environment: "staging", ReleaseID: "rel-2", Outcome: audit.OutcomeFailure, Error: "boom"},
+		{Actor: "carol", Command: "deploy", Environment: "prod", ReleaseID: "rel-3", Outcome: audit.OutcomeSuccess},
+	} {
+		if err := log.Append(env.Ctx, e); err != nil {
+			t.Fatalf("appending audit entry: %v", err)
+		}
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewAuditCommand())
+
+	out, err := executeCommandForGolden(root, "audit", "list", "--env", "staging")
+	if err != nil {
+		t.Fatalf("audit list failed: %v", err)
+	}
+	if !strings.Contains(out, "ACTOR") || strings.Contains(out, "carol") {
+		t.Errorf("expected staging-only table, got:\n%s", out)
+	}
+	if strings.Index(out, "bob") > strings.Index(out, "alice") {
+		t.Errorf("expected newest entry first, got:\n%s", out)
+	}
+
+	root = newTestRootCommand()
+	root.AddCommand(NewAuditCommand())
+
+	out, err = executeCommandForGolden(root, "audit", "list", "--command", "deploy", "--limit", "1", "-o", "json")
+	if err != nil {
+		t.Fatalf("audit list failed: %v", err)
+	}
+
+	var view auditListView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("expected JSON output, got %v:\n%s", err, out)
+	}
+	if len(view.Entries) != 1 || view.Entries[0].ReleaseID != "rel-3" {
+		t.Errorf("expected newest deploy entry only, got %+v", view.Entries)
+	}
+}
+
+func TestAuditList_Empty(t *testing.T) {
+	setupIsolatedStateTestEnv(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewAuditCommand())
+
+	out, err := executeCommandForGolden(root, "audit", "list")
+	if err != nil {
+		t.Fatalf("audit list failed: %v", err)
+	}
+	if !strings.Contains(out, "No audit entries found") {
+		t.Errorf("expected empty message, got:\n%s", out)
+	}
+}
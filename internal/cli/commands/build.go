@@ -19,14 +19,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"stagecraft/internal/containerruntime"
 	"stagecraft/internal/core"
 	"stagecraft/internal/core/state"
 	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
 	"stagecraft/pkg/logging"
+	backendproviders "stagecraft/pkg/providers/backend"
 )
 
 // Feature: CLI_BUILD
@@ -43,7 +48,13 @@ func NewBuildCommand() *cobra.Command {
 
 	cmd.Flags().String("version", "", "Explicit image version/tag to use")
 	cmd.Flags().Bool("push", false, "Push images to registry after successful build")
+	flagNeedsNetwork(cmd, "push")
 	cmd.Flags().String("services", "", "Comma-separated list of services to build")
+	cmd.Flags().StringArray("service", nil, "Service to build (repeatable; combined with --services)")
+	_ = cmd.RegisterFlagCompletionFunc("services", CompleteServiceList)
+	_ = cmd.RegisterFlagCompletionFunc("service", CompleteServices)
+	cmd.Flags().Bool("no-cache", false, "Build without using the layer cache")
+	cmd.Flags().String("platform", "", "Target platform(s), e.g. linux/arm64 or linux/amd64,linux/arm64 (overrides the environment's build settings)")
 
 	// Global flags (--config, --env, --verbose, --dry-run) are inherited from root
 
@@ -100,17 +111,29 @@ func runBuildWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 	}
 
 	// Initialize logger
-	logger := logging.NewLogger(flags.Verbose)
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
 
 	// Parse build-specific flags
 	versionFlag, _ := cmd.Flags().GetString("version")
 	pushFlag, _ := cmd.Flags().GetBool("push")
 	servicesFlag, _ := cmd.Flags().GetString("services")
+	serviceFlags, _ := cmd.Flags().GetStringArray("service")
+	noCacheFlag, _ := cmd.Flags().GetBool("no-cache")
+	platformFlag, _ := cmd.Flags().GetString("platform")
+
+	platforms, err := parsePlatformList(platformFlag)
+	if err != nil {
+		return err
+	}
 
-	// Parse services list if provided
-	var services []string
-	if servicesFlag != "" {
-		services = parseServicesList(servicesFlag)
+	// Merge --services and --service, then check them against the backend services
+	services := append(parseServicesList(servicesFlag), serviceFlags...)
+	services, err = resolveBuildServices(cfg, services)
+	if err != nil {
+		return err
 	}
 
 	// Resolve version (same logic as deploy)
@@ -131,6 +154,14 @@ func runBuildWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 	plan.Metadata["config_path"] = absPath
 	plan.Metadata["workdir"], _ = os.Getwd()
 	plan.Metadata["push"] = pushFlag
+	if len(platforms) == 0 {
+		platforms = cfg.Environments[flags.Env].Build.TargetPlatforms()
+	}
+	plan.Metadata["build_options"] = buildOptions{
+		Services:  services,
+		NoCache:   noCacheFlag,
+		Platforms: platforms,
+	}
 
 	// Handle dry-run mode
 	if flags.DryRun {
@@ -165,7 +196,8 @@ func runBuildWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 	}
 
 	// Execute build phases using shared helper
-	err = executeBuildPhases(ctx, stateMgr, release.ID, plan, logger, fns, buildPhases)
+	err = executeBuildPhases(ctx, stateMgr, release.ID, plan, logger, withPhaseTimeouts(fns, cfg.Engine), buildPhases)
+	recordBuiltImages(ctx, stateMgr, release.ID, plan, logger)
 	if err != nil {
 		return fmt.Errorf("build failed: %w", err)
 	}
@@ -193,6 +225,173 @@ func parseServicesList(servicesFlag string) []string {
 	return services
 }
 
+// buildOptions carries build-only settings from the build command to the
+// build phase via plan.Metadata["build_options"]. Deploy builds use the zero
+// value: every service, with the environment's build settings.
+type buildOptions struct {
+	Services  []string // sorted; empty builds every backend service
+	NoCache   bool
+	Platforms []string // overrides the environment's build.platform(s)
+}
+
+// buildOptionsFromPlan returns the build options stored in plan metadata.
+func buildOptionsFromPlan(plan *core.Plan) buildOptions {
+	opts, _ := plan.Metadata["build_options"].(buildOptions)
+	return opts
+}
+
+// parsePlatformList parses the comma-separated --platform value.
+func parsePlatformList(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var platforms []string
+	for _, platform := range strings.Split(value, ",") {
+		platform = strings.TrimSpace(platform)
+		if config.ValidatePlatform(platform) != nil {
+			return nil, fmt.Errorf("build: --platform must be os/arch, got %q", platform)
+		}
+		if !slices.Contains(platforms, platform) {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms, nil
+}
+
+// buildPlatforms returns the platforms to build for: the command's
+// --platform, else the environment's build settings. More than one
+// platform makes a multi-platform build.
+func buildPlatforms(envBuild *config.BuildConfig, opts buildOptions) []string {
+	if len(opts.Platforms) > 0 {
+		return opts.Platforms
+	}
+	return envBuild.TargetPlatforms()
+}
+
+// platformImageTag returns the per-platform tag of a multi-platform build,
+// e.g. "app:v1" for linux/arm64 becomes "app:v1-linux-arm64".
+func platformImageTag(tag, platform string) string {
+	suffix := strings.ReplaceAll(platform, "/", "-")
+	if i := strings.LastIndex(tag, ":"); i > strings.LastIndex(tag, "/") {
+		return tag + "-" + suffix
+	}
+	return tag + ":" + suffix
+}
+
+// resolveBuildServices deduplicates and sorts the requested services and
+// checks that each one is a backend service.
+func resolveBuildServices(cfg *config.Config, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]bool)
+	for _, svc := range cfg.Backend.ServiceList() {
+		known[svc.Name] = true
+	}
+
+	seen := make(map[string]bool, len(requested))
+	services := make([]string, 0, len(requested))
+	for _, name := range requested {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if !known[name] {
+			return nil, fmt.Errorf("unknown service in build plan: %s", name)
+		}
+		seen[name] = true
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// selectBuildServices returns the services named in selected, or all of
+// them when selected is empty.
+func selectBuildServices(all []config.BackendService, selected []string) []config.BackendService {
+	if len(selected) == 0 {
+		return all
+	}
+	want := make(map[string]bool, len(selected))
+	for _, name := range selected {
+		want[name] = true
+	}
+	var result []config.BackendService
+	for _, svc := range all {
+		if want[svc.Name] {
+			result = append(result, svc)
+		}
+	}
+	return result
+}
+
+// resolveBuildDockerOptions merges the environment's cache settings, build
+// args, target stage, and secrets with the command's flags. The platform
+// (see buildPlatforms) and the provider-specific fields are filled per
+// image.
+func resolveBuildDockerOptions(envBuild *config.BuildConfig, opts buildOptions) backendproviders.BuildDockerOptions {
+	result := backendproviders.BuildDockerOptions{NoCache: opts.NoCache}
+	if envBuild != nil {
+		result.CacheFrom = envBuild.CacheFrom
+		result.CacheTo = envBuild.CacheTo
+		result.BuildArgs = envBuild.Args
+		result.Target = envBuild.Target
+		for _, secret := range envBuild.Secrets {
+			result.Secrets = append(result.Secrets, backendproviders.BuildSecret(secret))
+		}
+	}
+	return result
+}
+
+// inspectImageID returns the local image ID of a built image, or "" when
+// the runtime cannot report it (e.g. a provider that pushed instead of
+// loading the image).
+func inspectImageID(ctx context.Context, runner executil.Runner, runtime containerruntime.Runtime, image string, logger logging.Logger) string {
+	result, err := runner.Run(ctx, runtime.Command("image", "inspect", "--format", "{{.Id}}", image))
+	if err != nil {
+		logger.Debug("Failed to inspect built image",
+			logging.NewField("image", image),
+			logging.NewField("error", err.Error()),
+		)
+		return ""
+	}
+	return strings.TrimSpace(string(result.Stdout))
+}
+
+// recordBuiltImages stores the images built for a release, with the IDs
+// and registry digests collected by the build and push phases. Failures are
+// logged, not fatal: the build itself already succeeded or failed.
+func recordBuiltImages(ctx context.Context, stateMgr *state.Manager, releaseID string, plan *core.Plan, logger logging.Logger) {
+	names, byService := builtImagesFromPlan(plan)
+	if len(names) == 0 {
+		return
+	}
+	ids, _ := plan.Metadata["image_ids"].(map[string]string)
+	digests, _ := plan.Metadata["image_digests"].(map[string]string)
+	platformImages, _ := plan.Metadata["platform_images"].(map[string][]state.PlatformImage)
+	sbomPaths, _ := plan.Metadata["sbom_paths"].(map[string]string)
+
+	images := make([]state.BuiltImage, 0, len(names))
+	for _, name := range names {
+		images = append(images, state.BuiltImage{
+			Service:   name,
+			Tag:       byService[name],
+			ID:        ids[name],
+			Digest:    digests[name],
+			SBOM:      sbomPaths[name],
+			Platforms: platformImages[name],
+		})
+	}
+
+	if err := stateMgr.RecordImages(ctx, releaseID, images); err != nil {
+		logger.Warn("Failed to record built images",
+			logging.NewField("release_id", releaseID),
+			logging.NewField("error", err.Error()),
+		)
+	}
+}
+
 // renderBuildPlan renders the build plan for dry-run mode.
 func renderBuildPlan(cmd *cobra.Command, plan *core.Plan, env, version string, services []string) error {
 	out := cmd.OutOrStdout()
@@ -213,6 +412,17 @@ func renderBuildPlan(cmd *cobra.Command, plan *core.Plan, env, version string, s
 	_, _ = fmt.Fprintf(out, "Version: %s\n", version)
 	_, _ = fmt.Fprintf(out, "Provider: %s\n", providerID)
 
+	opts := buildOptionsFromPlan(plan)
+	switch {
+	case len(opts.Platforms) > 1:
+		_, _ = fmt.Fprintf(out, "Platforms: %s (manifest list)\n", strings.Join(opts.Platforms, ", "))
+	case len(opts.Platforms) == 1:
+		_, _ = fmt.Fprintf(out, "Platform: %s\n", opts.Platforms[0])
+	}
+	if opts.NoCache {
+		_, _ = fmt.Fprintf(out, "Cache: disabled (--no-cache)\n")
+	}
+
 	if len(services) > 0 {
 		_, _ = fmt.Fprintf(out, "Services:\n")
 		for _, svc := range services {
@@ -259,8 +469,8 @@ func executeBuildPhases(
 		// Execute phase
 		err = phaseFn(ctx, plan, logger)
 		if err != nil {
-			// Mark current phase as failed
-			if updateErr := stateMgr.UpdatePhase(ctx, releaseID, phase, state.StatusFailed); updateErr != nil {
+			// Mark current phase as failed, or timed out
+			if updateErr := stateMgr.UpdatePhase(ctx, releaseID, phase, phaseFailureStatus(err)); updateErr != nil {
 				logger.Debug("Failed to update phase status", logging.NewField("error", updateErr.Error()))
 			}
 
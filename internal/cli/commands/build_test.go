@@ -15,10 +15,19 @@ See https://www.gnu.org/licenses/ for license details.
 package commands
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"stagecraft/internal/containerruntime"
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+	backendproviders "stagecraft/pkg/providers/backend"
 )
 
 // Feature: CLI_BUILD
@@ -145,90 +154,248 @@ environments:
 	}
 }
 
-func TestBuildSubsetServices(t *testing.T) {
-	t.Parallel()
-
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "stagecraft.yml")
-
-	configContent := `project:
+// buildMultiServiceConfig declares api, worker and cron backend services.
+const buildMultiServiceConfig = `project:
   name: test-app
 backend:
-  provider: generic
-  providers:
-    generic:
-      build:
-        dockerfile: "./Dockerfile"
-        context: "."
+  services:
+    api:
+      provider: generic
+      providers:
+        generic:
+          dev:
+            command: ["npm", "start"]
+    cron:
+      provider: generic
+      role: worker
+      providers:
+        generic:
+          dev:
+            command: ["npm", "run", "cron"]
+    worker:
+      provider: generic
+      role: worker
+      providers:
+        generic:
+          dev:
+            command: ["npm", "run", "worker"]
 environments:
   dev:
     driver: local
 `
-	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+
+// writeBuildConfig writes content to a temp stagecraft.yml and returns its absolute path.
+func writeBuildConfig(t *testing.T, content string) string {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "stagecraft.yml")
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
 		t.Fatalf("failed to write config file: %v", err)
 	}
-
-	// Get absolute path to avoid working directory race conditions in parallel tests
 	absConfigPath, err := filepath.Abs(configPath)
 	if err != nil {
 		t.Fatalf("failed to get absolute config path: %v", err)
 	}
+	return absConfigPath
+}
+
+func TestBuildSubsetServices(t *testing.T) {
+	t.Parallel()
+
+	absConfigPath := writeBuildConfig(t, buildMultiServiceConfig)
 
 	root := newTestRootCommand()
 	root.AddCommand(NewBuildCommand())
 
-	out, err := executeCommandForGolden(root, "build", "--env=dev", "--config", absConfigPath, "--services=api,worker", "--dry-run")
+	out, err := executeCommandForGolden(root, "build", "--env=dev", "--config", absConfigPath, "--services=worker,api", "--dry-run")
 	if err != nil {
 		t.Fatalf("expected dry-run subset build to succeed, got error: %v", err)
 	}
 
-	// Note: Service filtering may not be fully implemented in v1, but the flag should be accepted
-	// This test verifies the command accepts the flag without error
-	if !strings.Contains(out, "api") && !strings.Contains(out, "worker") {
-		// In v1, service filtering may not be fully implemented, so we just verify no error
-		t.Logf("Service filtering may not be fully implemented in v1")
+	if !strings.Contains(out, "Services:\n - api\n - worker\n") {
+		t.Fatalf("expected selected services in lexicographic order, got: %s", out)
+	}
+	if strings.Contains(out, "cron") {
+		t.Fatalf("expected unselected service to be omitted, got: %s", out)
 	}
 }
 
-func TestBuildExplicitVersionIsReflected(t *testing.T) {
+func TestBuildServiceFlagCombinesWithServices(t *testing.T) {
 	t.Parallel()
 
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "stagecraft.yml")
+	absConfigPath := writeBuildConfig(t, buildMultiServiceConfig)
 
-	configContent := `project:
-  name: test-app
-backend:
-  provider: generic
-  providers:
-    generic:
-      build:
-        dockerfile: "./Dockerfile"
-        context: "."
-environments:
-  dev:
-    driver: local
-`
-	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
-		t.Fatalf("failed to write config file: %v", err)
-	}
+	root := newTestRootCommand()
+	root.AddCommand(NewBuildCommand())
 
-	// Get absolute path to avoid working directory race conditions in parallel tests
-	absConfigPath, err := filepath.Abs(configPath)
+	out, err := executeCommandForGolden(root, "build", "--env=dev", "--config", absConfigPath,
+		"--service", "worker", "--services=cron", "--service", "worker",
+		"--no-cache", "--platform", "linux/amd64,linux/arm64", "--dry-run")
 	if err != nil {
-		t.Fatalf("failed to get absolute config path: %v", err)
+		t.Fatalf("expected dry-run to succeed, got error: %v", err)
+	}
+
+	for _, want := range []string{
+		"Platforms: linux/amd64, linux/arm64 (manifest list)\n",
+		"Cache: disabled (--no-cache)\n",
+		"Services:\n - cron\n - worker\nNo images",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %s", want, out)
+		}
 	}
+}
+
+func TestBuildUnknownServiceFails(t *testing.T) {
+	t.Parallel()
+
+	absConfigPath := writeBuildConfig(t, buildMultiServiceConfig)
 
 	root := newTestRootCommand()
 	root.AddCommand(NewBuildCommand())
 
-	out, err := executeCommandForGolden(root, "build", "--env=dev", "--config", absConfigPath, "--version=v1.2.3", "--dry-run")
+	_, err := executeCommandForGolden(root, "build", "--env=dev", "--config", absConfigPath, "--service", "billing", "--dry-run")
+	if err == nil || err.Error() != "unknown service in build plan: billing" {
+		t.Fatalf("expected unknown service error, got: %v", err)
+	}
+}
+
+func TestBuildInvalidPlatformFails(t *testing.T) {
+	t.Parallel()
+
+	absConfigPath := writeBuildConfig(t, buildMultiServiceConfig)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewBuildCommand())
+
+	_, err := executeCommandForGolden(root, "build", "--env=dev", "--config", absConfigPath, "--platform", "arm64", "--dry-run")
+	if err == nil || !strings.Contains(err.Error(), "--platform must be os/arch") {
+		t.Fatalf("expected invalid platform error, got: %v", err)
+	}
+}
+
+func TestResolveBuildDockerOptions(t *testing.T) {
+	envBuild := &config.BuildConfig{
+		Platform:  "linux/amd64",
+		CacheFrom: []string{"type=registry,ref=r/app:cache"},
+		CacheTo:   []string{"type=registry,ref=r/app:cache,mode=max"},
+	}
+
+	got := resolveBuildDockerOptions(envBuild, buildOptions{NoCache: true})
+	if !got.NoCache || len(got.CacheFrom) != 1 || len(got.CacheTo) != 1 {
+		t.Errorf("expected no-cache and environment caches, got %+v", got)
+	}
+
+	envBuild = &config.BuildConfig{
+		Args:    map[string]string{"NODE_ENV": "production"},
+		Target:  "runtime",
+		Secrets: []config.BuildSecret{{ID: "npmrc", Src: ".npmrc"}},
+	}
+	got = resolveBuildDockerOptions(envBuild, buildOptions{})
+	wantSecrets := []backendproviders.BuildSecret{{ID: "npmrc", Src: ".npmrc"}}
+	if got.Target != "runtime" || got.BuildArgs["NODE_ENV"] != "production" || !reflect.DeepEqual(got.Secrets, wantSecrets) {
+		t.Errorf("expected environment build args, target, and secrets, got %+v", got)
+	}
+	if got := resolveBuildDockerOptions(nil, buildOptions{}); got.NoCache || got.CacheTo != nil {
+		t.Errorf("expected zero options without config, got %+v", got)
+	}
+}
+
+func TestBuildPlatforms(t *testing.T) {
+	envBuild := &config.BuildConfig{Platforms: []string{"linux/amd64", "linux/arm64"}}
+
+	if got := buildPlatforms(envBuild, buildOptions{Platforms: []string{"linux/arm64"}}); !reflect.DeepEqual(got, []string{"linux/arm64"}) {
+		t.Errorf("expected --platform to override build.platforms, got %v", got)
+	}
+	if got := buildPlatforms(envBuild, buildOptions{}); len(got) != 2 {
+		t.Errorf("expected build.platforms without flag, got %v", got)
+	}
+	if got := buildPlatforms(nil, buildOptions{}); got != nil {
+		t.Errorf("expected host platform without config, got %v", got)
+	}
+
+	platforms, err := parsePlatformList("linux/amd64, linux/arm64,linux/amd64")
+	if err != nil || !reflect.DeepEqual(platforms, []string{"linux/amd64", "linux/arm64"}) {
+		t.Errorf("parsePlatformList() = %v, %v", platforms, err)
+	}
+}
+
+func TestPlatformImageTag(t *testing.T) {
+	tests := map[string]string{
+		"app:v1":                     "app:v1-linux-arm64",
+		"ghcr.io/org/app:v1":         "ghcr.io/org/app:v1-linux-arm64",
+		"localhost:5000/app":         "localhost:5000/app:linux-arm64",
+		"localhost:5000/org/app:rel": "localhost:5000/org/app:rel-linux-arm64",
+	}
+	for tag, want := range tests {
+		if got := platformImageTag(tag, "linux/arm64"); got != want {
+			t.Errorf("platformImageTag(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestPushManifestList_RecordsPlatformDigests(t *testing.T) {
+	amd64 := "sha256:" + strings.Repeat("a1", 32)
+	arm64 := "sha256:" + strings.Repeat("b2", 32)
+	list := "sha256:" + strings.Repeat("c3", 32)
+	runner := &scriptedRunner{outputs: map[string]string{
+		"docker push app:v1-linux-amd64":                                              "v1-linux-amd64: digest: " + amd64 + " size: 1\n",
+		"docker push app:v1-linux-arm64":                                              "v1-linux-arm64: digest: " + arm64 + " size: 1\n",
+		"docker manifest create --amend app:v1 app:v1-linux-amd64 app:v1-linux-arm64": "Created manifest list docker.io/library/app:v1\n",
+		"docker manifest push --purge app:v1":                                         list + "\n",
+	}}
+	images := []state.PlatformImage{
+		{Platform: "linux/amd64", Tag: "app:v1-linux-amd64"},
+		{Platform: "linux/arm64", Tag: "app:v1-linux-arm64"},
+	}
+
+	digest, err := pushManifestList(context.Background(), runner, containerruntime.Docker, "app:v1", images, logging.NewLogger(false))
 	if err != nil {
-		t.Fatalf("expected dry-run with explicit version to succeed, got error: %v", err)
+		t.Fatalf("pushManifestList failed: %v", err)
+	}
+	if digest != list {
+		t.Errorf("expected manifest list digest %q, got %q", list, digest)
+	}
+	if images[0].Digest != amd64 || images[1].Digest != arm64 {
+		t.Errorf("expected per-platform digests to be recorded, got %+v", images)
 	}
+}
+
+func TestRecordBuiltImages_StoresIDsAndDigests(t *testing.T) {
+	ctx := context.Background()
+	stateMgr := state.NewManager(filepath.Join(t.TempDir(), "releases.json"))
+	release, err := stateMgr.CreateRelease(ctx, "staging", "v1", "abc")
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	runner := &scriptedRunner{outputs: map[string]string{
+		"docker push app-api:v1": "v1: digest: " + digest + " size: 1234\n",
+	}}
+	pushed, err := pushImage(ctx, runner, containerruntime.Docker, "app-api:v1", logging.NewLogger(false))
+	if err != nil {
+		t.Fatalf("pushImage failed: %v", err)
+	}
+	if pushed != digest {
+		t.Fatalf("expected digest %q from push output, got %q", digest, pushed)
+	}
+
+	plan := &core.Plan{Metadata: map[string]interface{}{
+		"built_images":  map[string]string{"worker": "app-worker:v1", "api": "app-api:v1"},
+		"image_ids":     map[string]string{"api": "sha256:11", "worker": "sha256:22"},
+		"image_digests": map[string]string{"api": pushed},
+	}}
+	recordBuiltImages(ctx, stateMgr, release.ID, plan, logging.NewLogger(false))
 
-	// Check for version in structured log output
-	if !strings.Contains(out, "v1.2.3") {
-		t.Fatalf("expected version v1.2.3 in output, got: %s", out)
+	loaded, err := stateMgr.GetRelease(ctx, release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	want := []state.BuiltImage{
+		{Service: "api", Tag: "app-api:v1", ID: "sha256:11", Digest: digest},
+		{Service: "worker", Tag: "app-worker:v1", ID: "sha256:22"},
+	}
+	if !reflect.DeepEqual(loaded.Images, want) {
+		t.Errorf("recorded images = %+v, want %+v", loaded.Images, want)
 	}
 }
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cache"
+	"stagecraft/internal/cli/output"
+)
+
+// Feature: CLI_CACHE
+// Spec: spec/commands/cache.md
+
+// NewCacheCommand returns the `stagecraft cache` command group.
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and clear the cache directory",
+		Long: `Stagecraft caches data fetched from providers, host inventories and
+price lists, in ~/.cache/stagecraft (or $STAGECRAFT_CACHE_DIR). Entries are
+fetched again once older than their bucket's TTL. Clearing the cache is
+always safe.`,
+	}
+
+	cmd.AddCommand(newCacheInfoCommand())
+	cmd.AddCommand(newCacheClearCommand())
+
+	return cmd
+}
+
+func newCacheInfoCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "info",
+		Short: "Show the cache directory and what each bucket holds",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheInfo,
+	}
+}
+
+func newCacheClearCommand() *cobra.Command {
+	var expired bool
+
+	cmd := &cobra.Command{
+		Use:   "clear [bucket...]",
+		Short: "Remove cached entries",
+		Long: `Remove every cached entry, or only those of the given buckets. With
+--expired, only remove entries older than their bucket's TTL.`,
+		ValidArgs: []string{cache.BucketHosts, cache.BucketPricing},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCacheClear(cmd, args, expired)
+		},
+	}
+
+	cmd.Flags().BoolVar(&expired, "expired", false, "only remove entries older than their bucket's TTL")
+
+	return cmd
+}
+
+// cacheInfoView is the structured (--output json|yaml) form of `cache info`.
+type cacheInfoView struct {
+	Dir     string            `json:"dir" yaml:"dir"`
+	Buckets []cacheBucketView `json:"buckets" yaml:"buckets"`
+}
+
+type cacheBucketView struct {
+	Name    string `json:"name" yaml:"name"`
+	Entries int    `json:"entries" yaml:"entries"`
+	Bytes   int64  `json:"bytes" yaml:"bytes"`
+	Expired int    `json:"expired" yaml:"expired"`
+
+	// TTL is empty for buckets this build does not know, which never expire
+	TTL    string `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	Oldest string `json:"oldest,omitempty" yaml:"oldest,omitempty"`
+	Newest string `json:"newest,omitempty" yaml:"newest,omitempty"`
+}
+
+func runCacheInfo(cmd *cobra.Command, _ []string) error {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("cache info: resolving flags: %w", err)
+	}
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return fmt.Errorf("cache info: %w", err)
+	}
+	infos, err := cache.New(dir).Info()
+	if err != nil {
+		return fmt.Errorf("cache info: %w", err)
+	}
+
+	view := cacheInfoView{Dir: dir, Buckets: make([]cacheBucketView, 0, len(infos))}
+	for _, info := range infos {
+		b := cacheBucketView{Name: info.Name, Entries: info.Entries, Bytes: info.Bytes, Expired: info.Expired}
+		if ttl := cache.TTL(info.Name); ttl > 0 {
+			b.TTL = ttl.String()
+		}
+		if info.Entries > 0 {
+			b.Oldest = info.Oldest.UTC().Format(time.RFC3339)
+			b.Newest = info.Newest.UTC().Format(time.RFC3339)
+		}
+		view.Buckets = append(view.Buckets, b)
+	}
+
+	return output.Render(cmd.OutOrStdout(), format, view, func(w io.Writer) error {
+		displayCacheInfo(w, view)
+		return nil
+	})
+}
+
+// displayCacheInfo displays the cache summary in table format.
+func displayCacheInfo(w io.Writer, view cacheInfoView) {
+	_, _ = fmt.Fprintf(w, "Cache directory: %s\n", view.Dir)
+	if len(view.Buckets) == 0 {
+		_, _ = fmt.Fprintf(w, "Cache is empty\n")
+		return
+	}
+
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintf(w, "%-10s %-8s %-10s %-8s %-8s %s\n", "BUCKET", "ENTRIES", "SIZE", "TTL", "EXPIRED", "NEWEST")
+	for _, b := range view.Buckets {
+		_, _ = fmt.Fprintf(w, "%-10s %-8d %-10s %-8s %-8d %s\n",
+			b.Name, b.Entries, formatByteSize(b.Bytes), dashIfEmpty(b.TTL), b.Expired, dashIfEmpty(b.Newest))
+	}
+}
+
+func runCacheClear(cmd *cobra.Command, buckets []string, expired bool) error {
+	if expired && len(buckets) > 0 {
+		return fmt.Errorf("cache clear: --expired cannot be combined with bucket names")
+	}
+
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return fmt.Errorf("cache clear: %w", err)
+	}
+	c := cache.New(dir)
+
+	var removed int
+	if expired {
+		removed, err = c.Prune()
+	} else {
+		removed, err = c.Clear(buckets...)
+	}
+	if err != nil {
+		return fmt.Errorf("cache clear: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Removed %d cache entries from %s\n", removed, dir)
+	return nil
+}
+
+// formatByteSize formats n bytes with a binary unit, e.g. "1.5 KiB".
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
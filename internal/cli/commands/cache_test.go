@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/cache"
+)
+
+// Feature: CLI_CACHE
+// Spec: spec/commands/cache.md
+
+func executeCache(args ...string) (string, error) {
+	root := newTestRootCommand()
+	root.AddCommand(NewCacheCommand())
+	return executeCommandForGolden(root, append([]string{"cache"}, args...)...)
+}
+
+func TestCacheInfoCommand(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+
+	out, err := executeCache("info")
+	if err != nil {
+		t.Fatalf("cache info: %v", err)
+	}
+	if !strings.Contains(out, "Cache directory: "+env.TempDir) || !strings.Contains(out, "Cache is empty") {
+		t.Errorf("output for an empty cache =\n%s", out)
+	}
+
+	seedHostCache(t, "staging", []cachedHost{{Name: "app-1", Status: "active"}})
+	seedHostCache(t, "prod", []cachedHost{{Name: "app-1", Status: "active"}})
+
+	out, err = executeCache("info", "--output", "json")
+	if err != nil {
+		t.Fatalf("cache info: %v", err)
+	}
+	var view cacheInfoView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(view.Buckets) != 1 {
+		t.Fatalf("buckets = %+v, want hosts only", view.Buckets)
+	}
+	hosts := view.Buckets[0]
+	if hosts.Name != cache.BucketHosts || hosts.Entries != 2 || hosts.Bytes == 0 || hosts.TTL != "5m0s" || hosts.Expired != 0 || hosts.Newest == "" {
+		t.Errorf("hosts bucket = %+v", hosts)
+	}
+}
+
+func TestCacheClearCommand(t *testing.T) {
+	setupIsolatedStateTestEnv(t)
+	seedHostCache(t, "staging", []cachedHost{{Name: "app-1", Status: "active"}})
+	if err := cache.Default().Put(cache.BucketPricing, "digitalocean", []byte("{}")); err != nil {
+		t.Fatalf("seeding pricing: %v", err)
+	}
+
+	// Fresh entries are not expired
+	out, err := executeCache("clear", "--expired")
+	if err != nil || !strings.Contains(out, "Removed 0 cache entries") {
+		t.Fatalf("cache clear --expired = %q, %v", out, err)
+	}
+
+	out, err = executeCache("clear", "pricing")
+	if err != nil || !strings.Contains(out, "Removed 1 cache entries") {
+		t.Fatalf("cache clear pricing = %q, %v", out, err)
+	}
+	if hosts := readHostCache(t, "staging"); len(hosts) != 1 {
+		t.Errorf("expected the host inventory to be kept, got %+v", hosts)
+	}
+
+	out, err = executeCache("clear")
+	if err != nil || !strings.Contains(out, "Removed 1 cache entries") {
+		t.Fatalf("cache clear = %q, %v", out, err)
+	}
+	if entry, err := cache.Default().Get(cache.BucketHosts, "test-project/staging"); err != nil || entry != nil {
+		t.Errorf("expected the cache to be empty, got %v, %v", entry, err)
+	}
+
+	if _, err := executeCache("clear", "--expired", "hosts"); err == nil {
+		t.Error("expected --expired with bucket names to be rejected")
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := map[int64]string{
+		0:           "0 B",
+		1023:        "1023 B",
+		1536:        "1.5 KiB",
+		5 << 20:     "5.0 MiB",
+		3 << 30 / 2: "1.5 GiB",
+	}
+	for n, want := range tests {
+		if got := formatByteSize(n); got != want {
+			t.Errorf("formatByteSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cache"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+)
+
+// Feature: CLI_COMPLETION
+// Spec: spec/commands/completion.md
+
+// completionShells lists supported shells in lexicographic order.
+var completionShells = []string{"bash", "fish", "powershell", "zsh"}
+
+// NewCompletionCommand returns the `stagecraft completion` command.
+func NewCompletionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion [bash|fish|powershell|zsh]",
+		Short: "Generate shell completion scripts",
+		Long: `Generate a shell completion script for Stagecraft.
+
+Load completions for the current shell session, for example:
+
+  bash:       source <(stagecraft completion bash)
+  zsh:        source <(stagecraft completion zsh)
+  fish:       stagecraft completion fish | source
+  powershell: stagecraft completion powershell | Out-String | Invoke-Expression
+
+Completions for --env and --service read the config file, completions
+for --host read the cached host inventory and the state file, and
+completions for --to-release and --retry-host read the state file.`,
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             completionShells,
+		DisableFlagsInUseLine: true,
+		RunE:                  runCompletion,
+	}
+
+	return cmd
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	root := cmd.Root()
+	out := cmd.OutOrStdout()
+
+	switch args[0] {
+	case "bash":
+		return root.GenBashCompletionV2(out, true)
+	case "fish":
+		return root.GenFishCompletion(out, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(out)
+	case "zsh":
+		return root.GenZshCompletion(out)
+	default:
+		return fmt.Errorf("unsupported shell %q; supported shells: %s", args[0], strings.Join(completionShells, ", "))
+	}
+}
+
+// CompleteEnvironments completes --env with the environment names defined
+// in the resolved config file. Errors yield no suggestions rather than
+// failing the shell.
+func CompleteEnvironments(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := make([]string, 0, len(cfg.Environments))
+	for name := range cfg.Environments {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteReleaseIDs completes release IDs for the resolved environment
+// from the state file, newest first. Each suggestion carries the release
+// version as its description.
+func CompleteReleaseIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	releases, err := state.NewDefaultManager().ListReleases(ctx, flags.Env)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ids := make([]string, 0, len(releases))
+	for _, r := range releases {
+		if strings.HasPrefix(r.ID, toComplete) {
+			ids = append(ids, r.ID+"\t"+r.Version)
+		}
+	}
+
+	return ids, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// CompleteServices completes --service with the backend service names of
+// the resolved config.
+func CompleteServices(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, _, ok := completionConfig(cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return matchingNames(serviceNames(cfg), "", toComplete, nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteServiceList completes the last entry of a comma-separated
+// --services list, skipping services already listed.
+func CompleteServiceList(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, _, ok := completionConfig(cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	listed, last := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		listed, last = toComplete[:i+1], toComplete[i+1:]
+	}
+	skip := make(map[string]bool)
+	for _, name := range strings.Split(listed, ",") {
+		skip[strings.TrimSpace(name)] = true
+	}
+
+	// NoSpace lets the user add a comma and keep completing
+	return matchingNames(serviceNames(cfg), listed, last, skip), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// CompleteHosts completes --host with the hosts of the resolved
+// environment: those in the cached host inventory, described by role, and
+// those the latest release rolled out to. It never queries the provider.
+func CompleteHosts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, flags, ok := completionConfig(cmd)
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	roles := make(map[string]string)
+	if entry, err := cache.Default().Get(cache.BucketHosts, cfg.Project.Name+"/"+flags.Env); err == nil && entry != nil {
+		var stored hostCacheEntry
+		if entry.Decode(&stored) == nil {
+			for _, h := range stored.Hosts {
+				roles[h.Name] = h.Role
+			}
+		}
+	}
+	if release, err := state.NewDefaultManager().GetCurrentRelease(commandContext(cmd), flags.Env); err == nil {
+		for _, hosts := range release.HostPhases {
+			for host := range hosts {
+				if _, ok := roles[host]; !ok {
+					roles[host] = ""
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if role := roles[name]; role != "" {
+			names[i] = name + "\t" + role
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteFailedHosts completes --retry-host with the hosts the resolved
+// environment's latest release failed to roll out to.
+func CompleteFailedHosts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	release, err := state.NewDefaultManager().GetCurrentRelease(commandContext(cmd), flags.Env)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return matchingNames(release.FailedHosts(state.PhaseRollout), "", toComplete, nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionConfig loads the config and resolves flags against it, so the
+// environment falls back to the config's default. ok is false when either
+// fails.
+func completionConfig(cmd *cobra.Command) (*config.Config, *ResolvedFlags, bool) {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return nil, nil, false
+	}
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		return nil, nil, false
+	}
+	flags, err = ResolveFlags(cmd, cfg)
+	if err != nil {
+		return nil, nil, false
+	}
+	return cfg, flags, true
+}
+
+// serviceNames returns the names of the config's backend services.
+func serviceNames(cfg *config.Config) []string {
+	services := cfg.Backend.ServiceList()
+	names := make([]string, 0, len(services))
+	for _, svc := range services {
+		names = append(names, svc.Name)
+	}
+	return names
+}
+
+// matchingNames returns prefix+name for every name starting with
+// toComplete and not in skip, sorted.
+func matchingNames(names []string, prefix, toComplete string, skip map[string]bool) []string {
+	matches := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) && !skip[name] {
+			matches = append(matches, prefix+name)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// registerEnvCompletion wires CompleteEnvironments to a command's local --env flag.
+func registerEnvCompletion(cmd *cobra.Command) {
+	_ = cmd.RegisterFlagCompletionFunc("env", CompleteEnvironments)
+}
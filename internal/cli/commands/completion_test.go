@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core/state"
+)
+
+// Feature: CLI_COMPLETION
+// Spec: spec/commands/completion.md
+
+func TestCompletionCommand_GeneratesScriptPerShell(t *testing.T) {
+	for _, shell := range completionShells {
+		t.Run(shell, func(t *testing.T) {
+			root := newTestRootCommand()
+			root.AddCommand(NewCompletionCommand())
+
+			out, err := executeCommandForGolden(root, "completion", shell)
+			if err != nil {
+				t.Fatalf("completion %s: unexpected error: %v", shell, err)
+			}
+			if !strings.Contains(out, "stagecraft") {
+				t.Fatalf("completion %s: expected script mentioning stagecraft, got %d bytes", shell, len(out))
+			}
+		})
+	}
+}
+
+func TestCompletionCommand_RejectsUnknownShell(t *testing.T) {
+	root := newTestRootCommand()
+	root.AddCommand(NewCompletionCommand())
+
+	if _, err := executeCommandForGolden(root, "completion", "tcsh"); err == nil {
+		t.Fatalf("expected error for unsupported shell")
+	}
+}
+
+func TestCompleteEnvironments_ReadsConfig(t *testing.T) {
+	_ = setupIsolatedStateTestEnv(t)
+
+	cfg := "project:\n  name: demo\nenvironments:\n  staging:\n    driver: local\n  dev:\n    driver: local\n  prod:\n    driver: local\n"
+	if err := os.WriteFile("stagecraft.yml", []byte(cfg), 0o600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewRollbackCommand())
+	_ = root.RegisterFlagCompletionFunc("env", CompleteEnvironments)
+
+	out, err := executeCommandForGolden(root, "__complete", "rollback", "--env", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	want := []string{"dev", "prod", "staging"}
+	if len(lines) < len(want) {
+		t.Fatalf("expected at least %d lines, got:\n%s", len(want), out)
+	}
+	for i, name := range want {
+		if lines[i] != name {
+			t.Errorf("completion[%d] = %q, want %q", i, lines[i], name)
+		}
+	}
+}
+
+func TestCompleteReleaseIDs_ReadsState(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+
+	rel, err := env.Manager.CreateRelease(env.Ctx, "staging", "v1.2.3", "abc123")
+	if err != nil {
+		t.Fatalf("creating release: %v", err)
+	}
+	if _, err := env.Manager.CreateRelease(env.Ctx, "prod", "v9.9.9", "def456"); err != nil {
+		t.Fatalf("creating release: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewRollbackCommand())
+
+	out, err := executeCommandForGolden(root, "__complete", "rollback", "--env", "staging", "--to-release", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out, rel.ID+"\tv1.2.3") {
+		t.Fatalf("expected staging release %s in completions, got:\n%s", rel.ID, out)
+	}
+	if strings.Contains(out, "v9.9.9") {
+		t.Fatalf("expected prod releases to be excluded, got:\n%s", out)
+	}
+}
+
+func TestCompleteServices_ReadsConfig(t *testing.T) {
+	_ = setupIsolatedStateTestEnv(t)
+	if err := os.WriteFile("stagecraft.yml", []byte(buildMultiServiceConfig), 0o600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewBuildCommand())
+
+	out, err := executeCommandForGolden(root, "__complete", "build", "--service", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "api\ncron\nworker\n") {
+		t.Errorf("--service completions =\n%s", out)
+	}
+
+	// --services completes the last entry and skips listed services
+	out, err = executeCommandForGolden(root, "__complete", "build", "--services", "worker,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "worker,api\nworker,cron\n:") {
+		t.Errorf("--services completions =\n%s", out)
+	}
+}
+
+func TestCompleteHosts_ReadsInventoryAndState(t *testing.T) {
+	t.Setenv("STAGECRAFT_CONFIG", writeHostsConfig(t, "fake-hosts-completion"))
+	mgr, ctx := state.NewDefaultManager(), context.Background()
+
+	seedHostCache(t, "staging", []cachedHost{{Name: "app-1", Role: "app"}, {Name: "db-1", Role: "db"}})
+	rel, err := mgr.CreateRelease(ctx, "staging", "v1", "abc123")
+	if err != nil {
+		t.Fatalf("creating release: %v", err)
+	}
+	for host, status := range map[string]state.PhaseStatus{"app-1": state.StatusCompleted, "edge-1": state.StatusFailed} {
+		if err := mgr.UpdateHostPhase(ctx, rel.ID, state.PhaseRollout, host, state.HostPhaseStatus{Status: status}); err != nil {
+			t.Fatalf("recording host phase: %v", err)
+		}
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewNetworkCommand())
+	root.AddCommand(NewDeployCommand())
+
+	out, err := executeCommandForGolden(root, "__complete", "network", "reauth", "--env", "staging", "--host", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "app-1\tapp\ndb-1\tdb\nedge-1\n:") {
+		t.Errorf("--host completions =\n%s", out)
+	}
+
+	// --retry-host only offers hosts whose rollout failed
+	out, err = executeCommandForGolden(root, "__complete", "deploy", "--env", "staging", "--retry-host", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "edge-1\n:") {
+		t.Errorf("--retry-host completions =\n%s", out)
+	}
+}
@@ -0,0 +1,252 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/compose"
+	"stagecraft/internal/deploy"
+	"stagecraft/internal/dev"
+	devmkcert "stagecraft/internal/dev/mkcert"
+	"stagecraft/internal/textdiff"
+	"stagecraft/pkg/config"
+)
+
+// Feature: CLI_COMPOSE_RENDER
+// Spec: spec/commands/compose.md
+
+// composeRenderOptions selects what to render.
+type composeRenderOptions struct {
+	Version   string // deploy environments: image version (default: git SHA)
+	AssetsDir string // deploy environments: frontend_static build directory
+	NoHTTPS   bool   // local environments: as `dev --no-https`
+	NoTraefik bool   // local environments: as `dev --no-traefik`
+}
+
+// renderedCompose is the compose file a generator would write.
+type renderedCompose struct {
+	// Path is where dev or deploy writes the file.
+	Path string
+	Data []byte
+
+	// Files holds every file deploy would write, including Path, by path.
+	// Nil for local environments.
+	Files map[string][]byte
+}
+
+// NewComposeCommand returns the `stagecraft compose` command group.
+func NewComposeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compose",
+		Short: "Preview the compose files Stagecraft generates",
+		Long: `Render the compose file that dev (local environments) or deploy (other
+environments) would write, without writing it or touching anything else,
+and compare it to the file last written.`,
+	}
+
+	cmd.AddCommand(newComposeRenderCommand())
+	cmd.AddCommand(newComposeDiffCommand())
+
+	return cmd
+}
+
+func newComposeRenderCommand() *cobra.Command {
+	var opts composeRenderOptions
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Print the compose YAML the generator would write",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rendered, err := renderCompose(cmd, opts)
+			if err != nil {
+				return fmt.Errorf("compose render: %w", err)
+			}
+
+			if out == "-" {
+				_, err := cmd.OutOrStdout().Write(rendered.Data)
+				return err
+			}
+			// #nosec G306 -- compose files are read by docker compose
+			if err := os.WriteFile(out, rendered.Data, 0o644); err != nil {
+				return fmt.Errorf("compose render: writing %s: %w", out, err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Wrote %s\n", out)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&out, "out", "-", "file to write the compose YAML to, or - for stdout")
+	addComposeRenderFlags(cmd, &opts)
+
+	return cmd
+}
+
+func newComposeDiffCommand() *cobra.Command {
+	var opts composeRenderOptions
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Diff the compose YAML the generator would write against the last written file",
+		Long: `Show a unified diff from the compose file dev or deploy last wrote to
+the one it would write now. A file that was never written diffs against
+an empty file. Prints nothing when there are no changes.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rendered, err := renderCompose(cmd, opts)
+			if err != nil {
+				return fmt.Errorf("compose diff: %w", err)
+			}
+
+			// #nosec G304 -- path is the generator's own output location
+			current, err := os.ReadFile(rendered.Path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("compose diff: reading %s: %w", rendered.Path, err)
+			}
+
+			name := displayPath(rendered.Path)
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), textdiff.Unified(name, name+" (rendered)", string(current), string(rendered.Data)))
+			return nil
+		},
+	}
+
+	addComposeRenderFlags(cmd, &opts)
+
+	return cmd
+}
+
+func addComposeRenderFlags(cmd *cobra.Command, opts *composeRenderOptions) {
+	cmd.Flags().StringVar(&opts.Version, "version", "", "image version for deploy environments (defaults to git SHA)")
+	cmd.Flags().StringVar(&opts.AssetsDir, "assets-dir", "", "frontend build directory for frontend_static environments")
+	cmd.Flags().BoolVar(&opts.NoHTTPS, devFlagNoHTTPS, false, "render local environments as dev --no-https")
+	cmd.Flags().BoolVar(&opts.NoTraefik, devFlagNoTraefik, false, "render local environments as dev --no-traefik")
+	registerEnvCompletion(cmd)
+}
+
+// renderCompose renders the compose file for the resolved environment:
+// the dev topology for local environments, the deploy compose file
+// otherwise. Nothing is written to disk.
+func renderCompose(cmd *cobra.Command, opts composeRenderOptions) (*renderedCompose, error) {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return nil, fmt.Errorf("resolving flags: %w", err)
+	}
+
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			return nil, fmt.Errorf("stagecraft config not found at %s", flags.Config)
+		}
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	flags, err = ResolveFlags(cmd, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("resolving flags: %w", err)
+	}
+
+	envCfg, ok := cfg.Environments[flags.Env]
+	if !ok {
+		return nil, fmt.Errorf("environment %q not found in config", flags.Env)
+	}
+
+	if envCfg.Driver == "local" {
+		return renderDevCompose(cfg, flags.Env, opts)
+	}
+
+	logger, err := newLogger(flags)
+	if err != nil {
+		return nil, err
+	}
+	version, _ := resolveVersion(commandContext(cmd), opts.Version, logger)
+
+	return renderDeployCompose(cfg, flags.Env, version, opts)
+}
+
+// renderDevCompose builds the topology exactly as dev does, with planned
+// certificate paths in place of running mkcert.
+func renderDevCompose(cfg *config.Config, env string, opts composeRenderOptions) (*renderedCompose, error) {
+	domains, err := dev.ComputeDomains(cfg, env)
+	if err != nil {
+		return nil, fmt.Errorf("computing dev domains: %w", err)
+	}
+
+	certCfg := devmkcert.PlannedCertificates(devmkcert.Options{
+		DevDir:      dev.DefaultDir,
+		Domains:     []string{domains.Frontend, domains.Backend},
+		EnableHTTPS: !opts.NoHTTPS,
+	})
+
+	topology, err := buildDevTopology(cfg, env, domains, certCfg, opts.NoTraefik)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := topology.Compose.ToYAML()
+	if err != nil {
+		return nil, fmt.Errorf("marshaling compose yaml: %w", err)
+	}
+	data, _ := compose.WithHashHeader(body)
+
+	return &renderedCompose{Path: filepath.Join(dev.DefaultDir, dev.ComposeFileName), Data: data}, nil
+}
+
+// renderDeployCompose runs the deploy compose generator with writes
+// discarded, using the image tags deploy would build.
+func renderDeployCompose(cfg *config.Config, env, version string, opts composeRenderOptions) (*renderedCompose, error) {
+	workdir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting working directory: %w", err)
+	}
+
+	baseComposePath := filepath.Join(workdir, "docker-compose.yml")
+	if _, err := os.Stat(baseComposePath); err != nil {
+		return nil, fmt.Errorf("docker-compose.yml not found at %s: %w", baseComposePath, err)
+	}
+
+	builtImages := make(map[string]string)
+	for _, svc := range cfg.Backend.ServiceList() {
+		builtImages[svc.Name] = deployServiceImageTag(cfg, svc.Name, version)
+	}
+
+	generator := deploy.NewComposeGeneratorWithFS(
+		func(string, []byte, os.FileMode) error { return nil },
+		func(string, os.FileMode) error { return nil },
+	).WithStaticAssets(opts.AssetsDir).WithServiceImages(builtImages)
+
+	outputPath, _, err := generator.Generate(cfg, env, baseComposePath, primaryImage(cfg, builtImages), workdir)
+	if err != nil {
+		return nil, fmt.Errorf("generating compose file: %w", err)
+	}
+
+	files := generator.RenderedFiles()
+	return &renderedCompose{Path: outputPath, Data: files[outputPath], Files: files}, nil
+}
+
+// displayPath returns path relative to the working directory when possible.
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil || !filepath.IsAbs(path) {
+		return path
+	}
+	return relPath(wd, path)
+}
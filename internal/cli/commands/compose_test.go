@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: CLI_COMPOSE_RENDER
+// Spec: spec/commands/compose.md
+
+// setupComposeProject writes a project with a local and a remote
+// environment into a temp dir and changes into it.
+func setupComposeProject(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	files := map[string]string{
+		"stagecraft.yml": `project:
+  name: test-app
+backend:
+  provider: generic
+  providers:
+    generic:
+      dev:
+        command: ["echo", "backend"]
+        env:
+          PORT: "4000"
+environments:
+  dev:
+    driver: local
+  staging:
+    driver: digitalocean
+`,
+		"docker-compose.yml": `services:
+  backend:
+    build: .
+    environment:
+      LOG_LEVEL: info
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+}
+
+func TestComposeRender_DevPrintsWithoutWriting(t *testing.T) {
+	setupComposeProject(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewComposeCommand())
+
+	out, err := executeCommandForGolden(root, "compose", "render", "--env", "dev", "--out", "-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"services:", "backend:", "traefik:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected rendered compose to contain %q, got:\n%s", want, out)
+		}
+	}
+	if _, err := os.Stat(".stagecraft"); !os.IsNotExist(err) {
+		t.Errorf("expected render to write nothing, stat .stagecraft: %v", err)
+	}
+}
+
+func TestComposeRender_NoTraefik(t *testing.T) {
+	setupComposeProject(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewComposeCommand())
+
+	out, err := executeCommandForGolden(root, "compose", "render", "--env", "dev", "--no-traefik")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out, "traefik:") {
+		t.Errorf("expected no traefik service, got:\n%s", out)
+	}
+}
+
+func TestComposeRender_DeployUsesVersionedImage(t *testing.T) {
+	setupComposeProject(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewComposeCommand())
+
+	out, err := executeCommandForGolden(root, "compose", "render", "--env", "staging", "--version", "v1.2.3", "--out", "staging.yml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Wrote staging.yml") {
+		t.Errorf("expected confirmation, got %q", out)
+	}
+
+	data, err := os.ReadFile("staging.yml")
+	if err != nil {
+		t.Fatalf("reading rendered file: %v", err)
+	}
+	if !strings.Contains(string(data), "image: test-app:v1.2.3") {
+		t.Errorf("expected versioned image, got:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(".stagecraft", "rendered")); !os.IsNotExist(err) {
+		t.Errorf("expected deploy render to write nothing under .stagecraft, stat: %v", err)
+	}
+}
+
+func TestComposeDiff_AgainstLastWrittenFile(t *testing.T) {
+	setupComposeProject(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewComposeCommand())
+
+	rendered, err := executeCommandForGolden(root, "compose", "render", "--env", "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Never written: everything is an addition.
+	out, err := executeCommandForGolden(root, "compose", "diff", "--env", "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "+++ .stagecraft/dev/compose.yaml (rendered)") || !strings.Contains(out, "+services:") {
+		t.Errorf("expected additions against missing file, got:\n%s", out)
+	}
+
+	// Up to date: no output.
+	composePath := filepath.Join(".stagecraft", "dev", "compose.yaml")
+	if err := os.MkdirAll(filepath.Dir(composePath), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(composePath, []byte(rendered), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out, err = executeCommandForGolden(root, "compose", "diff", "--env", "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "" {
+		t.Errorf("expected no diff, got:\n%s", out)
+	}
+
+	// Stale: the old line is removed.
+	stale := strings.Replace(rendered, "services:", "services:\n  old:\n    image: old", 1)
+	if err := os.WriteFile(composePath, []byte(stale), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out, err = executeCommandForGolden(root, "compose", "diff", "--env", "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "-  old:") {
+		t.Errorf("expected removal of stale service, got:\n%s", out)
+	}
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/prompt"
+)
+
+// Feature: CLI_CONFIRM_PROMPT
+// Spec: spec/commands/confirm-prompt.md
+
+// flagYes approves a destructive command's confirmations up front.
+const flagYes = "yes"
+
+// isInteractive reports whether a command's stdin can answer prompts.
+// Tests replace it to answer prompts from a string.
+var isInteractive = prompt.Interactive
+
+// addYesFlag registers --yes on a destructive command.
+func addYesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP(flagYes, "y", false, "skip confirmation prompts (required when stdin is not a terminal)")
+}
+
+// newConfirmer returns the confirmer for a destructive command. Prompts go
+// to stderr so structured output on stdout stays parseable. approved
+// reports --yes or an equivalent command-specific flag.
+func newConfirmer(cmd *cobra.Command, approved bool) *prompt.Confirmer {
+	yes, _ := cmd.Flags().GetBool(flagYes)
+	in := cmd.InOrStdin()
+	return prompt.New(in, cmd.ErrOrStderr(), isInteractive(in), yes || approved)
+}
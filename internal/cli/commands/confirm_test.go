@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/cli/prompt"
+	"stagecraft/internal/core/state"
+)
+
+// Feature: CLI_CONFIRM_PROMPT
+// Spec: spec/commands/confirm-prompt.md
+
+// answerPrompts makes confirmation prompts read the command's stdin as if
+// it were a terminal, so tests can answer them with root.SetIn.
+func answerPrompts(t *testing.T) {
+	t.Helper()
+	orig := isInteractive
+	isInteractive = func(io.Reader) bool { return true }
+	t.Cleanup(func() { isInteractive = orig })
+}
+
+func TestDestructiveCommands_NonInteractiveRequireYes(t *testing.T) {
+	t.Run("destroy", func(t *testing.T) {
+		env := setupDestroyTest(t, "non-interactive")
+		isInteractive = func(io.Reader) bool { return false }
+
+		// Answers on a non-terminal stdin are never read
+		out, err := executeDestroy("staging\ndestroy\n", "--env", "staging")
+		if !errors.Is(err, prompt.ErrNotInteractive) {
+			t.Fatalf("destroy error = %v, want ErrNotInteractive", err)
+		}
+		if env.cloud.applied != nil || len(env.runner.commands) != 0 {
+			t.Errorf("destroy changed something without --yes:\n%s", out)
+		}
+
+		if _, err := executeDestroy("", "--env", "staging", "--yes"); err != nil {
+			t.Fatalf("destroy --yes: %v", err)
+		}
+		if env.cloud.applied == nil {
+			t.Error("destroy --yes did not delete hosts")
+		}
+	})
+
+	t.Run("hosts apply", func(t *testing.T) {
+		configPath := writeHostsConfig(t, "test-cloud-hosts-apply-non-interactive")
+		fake := newFakePlanningProvider("test-cloud-hosts-apply-non-interactive", true)
+
+		_, err := executeHosts("yes\n", "apply", "--config", configPath, "--env", "staging")
+		if !errors.Is(err, prompt.ErrNotInteractive) {
+			t.Fatalf("hosts apply error = %v, want ErrNotInteractive", err)
+		}
+		if len(fake.applied) != 0 {
+			t.Error("hosts apply applied deletions without --yes")
+		}
+
+		if _, err := executeHosts("", "apply", "--config", configPath, "--env", "staging", "-y"); err != nil {
+			t.Fatalf("hosts apply -y: %v", err)
+		}
+		if len(fake.applied) != 1 {
+			t.Errorf("hosts apply -y applied %d plan(s), want 1", len(fake.applied))
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		env := setupIsolatedStateTestEnv(t)
+		configContent := "project:\n  name: test-app\nenvironments:\n  staging:\n    driver: local\n"
+		if err := os.WriteFile(filepath.Join(env.TempDir, "stagecraft.yml"), []byte(configContent), 0o600); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+		for _, version := range []string{"v1", "v2"} {
+			release, err := env.Manager.CreateRelease(env.Ctx, "staging", version, "abc")
+			if err != nil {
+				t.Fatalf("CreateRelease: %v", err)
+			}
+			for _, phase := range []state.ReleasePhase{
+				state.PhaseBuild, state.PhasePush, state.PhaseMigratePre,
+				state.PhaseRollout, state.PhaseMigratePost, state.PhaseFinalize,
+			} {
+				if err := env.Manager.UpdatePhase(env.Ctx, release.ID, phase, state.StatusCompleted); err != nil {
+					t.Fatalf("UpdatePhase: %v", err)
+				}
+			}
+		}
+
+		root := newTestRootCommand()
+		root.AddCommand(NewRollbackCommand())
+		root.SetIn(strings.NewReader("y\n"))
+		_, err := executeCommandForGolden(root, "rollback", "--env", "staging", "--to-previous")
+		if !errors.Is(err, prompt.ErrNotInteractive) {
+			t.Fatalf("rollback error = %v, want ErrNotInteractive", err)
+		}
+
+		if releases, _ := env.Manager.ListReleases(env.Ctx, "staging"); len(releases) != 2 {
+			t.Errorf("rollback without --yes created a release: %d releases", len(releases))
+		}
+	})
+}
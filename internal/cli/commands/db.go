@@ -0,0 +1,395 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cache"
+	"stagecraft/internal/core/env"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CLI_DB
+// Spec: spec/commands/db.md
+
+// dbTunnelTimeout bounds how long to wait for the SSH tunnel to accept connections.
+const dbTunnelTimeout = 15 * time.Second
+
+// dbHostRole is the host role whose host is used to tunnel to the database.
+const dbHostRole = "db"
+
+// startDBTunnel opens an SSH tunnel for remote databases. Tests replace it.
+var startDBTunnel = startSSHTunnel
+
+// dbTarget is a resolved database connection for an environment.
+type dbTarget struct {
+	Database string
+	Env      string
+	URL      *url.URL
+
+	// Remote is true for environments whose database is reached through a host.
+	Remote bool
+}
+
+// NewDBCommand returns the `stagecraft db` command group.
+func NewDBCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Connect to an environment's database",
+		Long: `Commands that resolve an environment's database connection and run
+PostgreSQL client tools against it.
+
+The connection URL is read from the variable named by the database's
+connection_env, looked up in the process environment and then in the
+environment's env_file. For environments that are not local, psql and dump
+connect through an SSH tunnel to the environment's db host (or --host),
+which goes over the mesh network when one is configured.`,
+	}
+
+	cmd.AddCommand(newDBURLCommand())
+	cmd.AddCommand(newDBPsqlCommand())
+	cmd.AddCommand(newDBDumpCommand())
+
+	return cmd
+}
+
+func newDBURLCommand() *cobra.Command {
+	var database string
+	var redacted bool
+
+	cmd := &cobra.Command{
+		Use:   "url",
+		Short: "Print the database connection URL",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, _, err := resolveDBTarget(cmd, database)
+			if err != nil {
+				return fmt.Errorf("db url: %w", err)
+			}
+
+			u := target.URL.String()
+			if redacted {
+				u = target.URL.Redacted()
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), u)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&database, "database", "main", "database name from config")
+	cmd.Flags().BoolVar(&redacted, "redacted", false, "mask the password")
+
+	return cmd
+}
+
+func newDBPsqlCommand() *cobra.Command {
+	var database, host string
+
+	cmd := &cobra.Command{
+		Use:   "psql [-- psql args...]",
+		Short: "Open psql against the environment's database",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDBClient(cmd, database, host, func(u string) executil.Command {
+				return executil.NewCommand("psql", append([]string{u}, args...)...)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&database, "database", "main", "database name from config")
+	cmd.Flags().StringVar(&host, "host", "", "host to tunnel through (default: the environment's db host)")
+	_ = cmd.RegisterFlagCompletionFunc("host", CompleteHosts)
+
+	return needsNetwork(cmd)
+}
+
+func newDBDumpCommand() *cobra.Command {
+	var database, host, output string
+
+	cmd := &cobra.Command{
+		Use:   "dump [-- pg_dump args...]",
+		Short: "Dump the environment's database with pg_dump",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output == "" {
+				flags, err := ResolveFlags(cmd, nil)
+				if err != nil {
+					return fmt.Errorf("db dump: resolving flags: %w", err)
+				}
+				output = fmt.Sprintf("%s-%s.sql", database, flags.Env)
+			}
+
+			err := runDBClient(cmd, database, host, func(u string) executil.Command {
+				pgArgs := append([]string{"--dbname", u, "--file", output}, args...)
+				return executil.NewCommand("pg_dump", pgArgs...)
+			})
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "✓ Wrote %s\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&database, "database", "main", "database name from config")
+	cmd.Flags().StringVar(&host, "host", "", "host to tunnel through (default: the environment's db host)")
+	_ = cmd.RegisterFlagCompletionFunc("host", CompleteHosts)
+	cmd.Flags().StringVar(&output, "file", "", "dump file (default: <database>-<env>.sql)")
+
+	return needsNetwork(cmd)
+}
+
+// runDBClient resolves the database, opens a tunnel for remote environments
+// and runs the client built by build with the (possibly rewritten) URL.
+func runDBClient(cmd *cobra.Command, database, host string, build func(u string) executil.Command) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	name := cmd.CommandPath()
+
+	target, cfg, err := resolveDBTarget(cmd, database)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	connURL := target.URL
+	if target.Remote {
+		addr, err := resolveDBHostAddress(ctx, cfg, target.Env, host)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		localURL, stop, err := openDBTunnel(ctx, addr, target.URL)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		defer stop()
+		connURL = localURL
+	}
+
+	client := build(connURL.String())
+	client.Stdin = cmd.InOrStdin()
+	if err := newRunner().RunStream(ctx, client, cmd.OutOrStdout()); err != nil {
+		return fmt.Errorf("%s: running %s: %w", name, client.Name, err)
+	}
+	return nil
+}
+
+// resolveDBTarget loads config and resolves the database connection URL.
+func resolveDBTarget(cmd *cobra.Command, database string) (*dbTarget, *config.Config, error) {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving flags: %w", err)
+	}
+
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			return nil, nil, fmt.Errorf("stagecraft config not found at %s", flags.Config)
+		}
+		return nil, nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	flags, err = ResolveFlags(cmd, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving flags: %w", err)
+	}
+
+	dbCfg, ok := cfg.Databases[database]
+	if !ok {
+		names := getDatabaseNames(cfg)
+		sort.Strings(names)
+		return nil, nil, fmt.Errorf("database %q not found in config; available: %v", database, names)
+	}
+	if dbCfg.ConnectionEnv == "" {
+		return nil, nil, fmt.Errorf("database %q has no connection_env configured", database)
+	}
+
+	resolver := env.NewResolver(cfg)
+	resolver.SetWorkDir(filepath.Dir(flags.Config))
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	envCtx, err := resolver.Resolve(ctx, flags.Env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw := envCtx.Variables[dbCfg.ConnectionEnv]
+	if raw == "" {
+		return nil, nil, fmt.Errorf("%s is not set in the environment or in the env_file of %q", dbCfg.ConnectionEnv, flags.Env)
+	}
+
+	u, err := parsePostgresURL(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", dbCfg.ConnectionEnv, err)
+	}
+
+	return &dbTarget{
+		Database: database,
+		Env:      flags.Env,
+		URL:      u,
+		Remote:   envCtx.Config.Driver != "local",
+	}, cfg, nil
+}
+
+// parsePostgresURL parses a PostgreSQL connection URL. Errors never echo the
+// URL, since it usually carries a password.
+func parsePostgresURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection URL")
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return nil, fmt.Errorf("unsupported scheme %q; only PostgreSQL URLs are supported", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("connection URL has no host")
+	}
+	return u, nil
+}
+
+// resolveDBHostAddress returns the address to SSH to: host when given,
+// otherwise the environment's host with the db role. Mesh FQDNs are
+// preferred over public IPs.
+func resolveDBHostAddress(ctx context.Context, cfg *config.Config, envName, host string) (string, error) {
+	inventory, err := resolveHostInventory(ctx, cfg, envName, cache.Default(), hostInventoryOptions{
+		CacheTTL: defaultHostCacheTTL,
+	})
+	if err != nil {
+		if host != "" {
+			return host, nil
+		}
+		return "", fmt.Errorf("resolving db host: %w (use --host to choose one)", err)
+	}
+
+	var names []string
+	for _, h := range inventory.Hosts {
+		names = append(names, h.Name)
+		if (host != "" && h.Name == host) || (host == "" && h.Role == dbHostRole) {
+			return hostSSHAddress(h), nil
+		}
+	}
+	if host != "" {
+		return host, nil
+	}
+	return "", fmt.Errorf("no host with role %q in environment %q (hosts: %v); use --host to choose one", dbHostRole, envName, names)
+}
+
+// hostSSHAddress prefers the mesh FQDN, then the public IP, then the name.
+func hostSSHAddress(h cachedHost) string {
+	switch {
+	case h.FQDN != "":
+		return h.FQDN
+	case h.PublicIP != "":
+		return h.PublicIP
+	default:
+		return h.Name
+	}
+}
+
+// openDBTunnel forwards a free local port to the database address as seen
+// from sshHost and returns the URL rewritten to use the local end.
+func openDBTunnel(ctx context.Context, sshHost string, target *url.URL) (*url.URL, func(), error) {
+	localPort, err := freeLocalPort()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	remotePort := target.Port()
+	if remotePort == "" {
+		remotePort = "5432"
+	}
+	remote := net.JoinHostPort(target.Hostname(), remotePort)
+	local := net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort))
+
+	stop, err := startDBTunnel(ctx, sshHost, local, remote)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening ssh tunnel to %s: %w", sshHost, err)
+	}
+
+	rewritten := *target
+	rewritten.Host = local
+	return &rewritten, stop, nil
+}
+
+// startSSHTunnel runs `ssh -N -L local:remote host` until stop is called and
+// waits for the local end to accept connections.
+func startSSHTunnel(ctx context.Context, sshHost, local, remote string) (func(), error) {
+	tunnelCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+
+	cmd := executil.NewCommand("ssh",
+		"-N",
+		"-o", "ExitOnForwardFailure=yes",
+		"-L", local+":"+remote,
+		sshHost,
+	)
+	go func() {
+		_, err := newRunner().Run(tunnelCtx, cmd)
+		done <- err
+	}()
+
+	stop := func() {
+		cancel()
+		<-done
+	}
+
+	deadline := time.Now().Add(dbTunnelTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-done:
+			cancel()
+			if err == nil {
+				err = errors.New("ssh exited before the tunnel was ready")
+			}
+			return nil, err
+		default:
+		}
+
+		conn, err := net.DialTimeout("tcp", local, 200*time.Millisecond)
+		if err == nil {
+			_ = conn.Close()
+			return stop, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	stop()
+	return nil, fmt.Errorf("tunnel not ready after %s", dbTunnelTimeout)
+}
+
+// freeLocalPort asks the kernel for an unused loopback port.
+func freeLocalPort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("finding a free local port: %w", err)
+	}
+	defer func() { _ = l.Close() }()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
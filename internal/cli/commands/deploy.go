@@ -20,17 +20,28 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"stagecraft/internal/bundle"
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/compose"
+	"stagecraft/internal/containerruntime"
 	"stagecraft/internal/core"
+	coreplan "stagecraft/internal/core/plan"
 	"stagecraft/internal/core/state"
 	"stagecraft/internal/deploy"
+	"stagecraft/internal/notify"
+	"stagecraft/internal/telemetry"
 	"stagecraft/pkg/config"
 	"stagecraft/pkg/executil"
 	"stagecraft/pkg/logging"
 	backendproviders "stagecraft/pkg/providers/backend"
+	frontendproviders "stagecraft/pkg/providers/frontend"
 )
 
 // Feature: CLI_DEPLOY
@@ -52,10 +63,14 @@ func NewDeployCommand() *cobra.Command {
 	}
 
 	cmd.Flags().String("version", "", "Version to deploy (defaults to git SHA)")
+	addDeployPlanFlags(cmd)
+	addDeployBundleFlag(cmd)
+	addDeployRetryHostFlag(cmd)
+	addDeployExplainFlag(cmd)
 
 	// Global flags (--config, --env, --verbose, --dry-run) are inherited from root
 
-	return cmd
+	return needsNetwork(cmd)
 }
 
 // runDeploy is the public entry point that uses default phase functions.
@@ -66,6 +81,14 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 // runDeployWithPhases is the internal implementation that accepts PhaseFns for dependency injection.
 // This allows tests to inject custom phase functions without using global state.
 func runDeployWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
+	return runDeployWithOutputDir(cmd, fns, "")
+}
+
+// runDeployWithOutputDir runs a deploy that keeps its persistent outputs
+// (the deploy bundle and SBOMs) under outputDir rather than the working
+// directory; empty means the working directory. reconcile builds from a
+// temporary worktree but keeps those outputs in the repository.
+func runDeployWithOutputDir(cmd *cobra.Command, fns PhaseFns, outputDir string) (err error) {
 	ctx := cmd.Context()
 	if ctx == nil {
 		ctx = context.Background()
@@ -97,25 +120,83 @@ func runDeployWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 		return fmt.Errorf("environment is required; use --env flag")
 	}
 
+	ctx, span := telemetry.StartSpan(ctx, "deploy", telemetry.AttrEnvironment.String(flags.Env))
+	defer func() {
+		telemetry.EndSpan(span, err)
+	}()
+
 	absPath, err := filepath.Abs(flags.Config)
 	if err != nil {
 		return fmt.Errorf("resolving config path: %w", err)
 	}
 
 	// Initialize logger
-	logger := logging.NewLogger(flags.Verbose)
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
 
-	// Resolve version
-	versionFlag, _ := cmd.Flags().GetString("version")
-	version, commitSHA := resolveVersion(ctx, versionFlag, logger)
+	planFlags, err := parseDeployPlanFlags(cmd)
+	if err != nil {
+		return err
+	}
+	bundleRef, err := parseDeployBundleFlag(cmd, planFlags)
+	if err != nil {
+		return err
+	}
+	bundleRef, err = resolveDeployRetryHost(ctx, cmd, state.NewDefaultManager(), flags.Env, planFlags, bundleRef)
+	if err != nil {
+		return err
+	}
+	explain, err := parseDeployExplainFlag(cmd, flags.DryRun)
+	if err != nil {
+		return err
+	}
+
+	// Resolve version, or take it from the plan or bundle being applied
+	var applied *coreplan.Artifact
+	var redeploy *bundle.Bundle
+	var redeployRef state.BundleRef
+	var version, commitSHA string
+	switch {
+	case planFlags.ApplyPlan != "":
+		applied, err = loadDeployPlan(ctx, planFlags.ApplyPlan, flags.Env, flags.Config, logger)
+		if err != nil {
+			return err
+		}
+		version, commitSHA = applied.Version, applied.CommitSHA
+	case bundleRef != "":
+		redeploy, redeployRef, err = loadDeployBundle(ctx, state.NewDefaultManager(), cfg, bundleRef, flags.Env, logger)
+		if err != nil {
+			return err
+		}
+		if applied, err = bundledPlan(redeploy); err != nil {
+			return err
+		}
+		version, commitSHA = redeploy.Manifest.Version, redeploy.Manifest.CommitSHA
+	default:
+		versionFlag, _ := cmd.Flags().GetString("version")
+		version, commitSHA = resolveVersion(ctx, versionFlag, logger)
+	}
+	span.SetAttributes(telemetry.AttrVersion.String(version))
+
+	// Plan-only mode writes the artifact for review and stops
+	if planFlags.PlanOnly {
+		return writeDeployPlan(cmd, cfg, flags.Env, flags.Config, planFlags.Out, version, commitSHA)
+	}
+
+	notifier := newLifecycleNotifier(cmd, cfg, flags.Env, version, commitSHA, flags.DryRun, logger)
+	hookRunner := newPhaseHookRunner(cfg, auditCommandName(cmd), flags.Env, version, commitSHA, flags.DryRun, logger)
 
 	// Check for dry-run mode
 	if flags.DryRun {
 		// Generate plan to show what would be deployed
-		planner := core.NewPlanner(cfg)
-		plan, err := planner.PlanDeploy(flags.Env)
-		if err != nil {
-			return fmt.Errorf("generating deployment plan: %w", err)
+		plan := appliedPlan(applied)
+		if plan == nil {
+			plan, err = core.NewPlanner(cfg).PlanDeploy(flags.Env)
+			if err != nil {
+				return fmt.Errorf("generating deployment plan: %w", err)
+			}
 		}
 
 		logger.Info("Dry-run mode: would deploy application",
@@ -125,11 +206,39 @@ func runDeployWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 			logging.NewField("config", absPath),
 			logging.NewField("operations", len(plan.Operations)),
 		)
+		if explain {
+			format, err := output.ParseFormat(flags.Output)
+			if err != nil {
+				return err
+			}
+			return explainDeploy(ctx, cmd.OutOrStdout(), format, &explainDeployRun{
+				cmd:        cmd,
+				cfg:        cfg,
+				env:        flags.Env,
+				version:    version,
+				commitSHA:  commitSHA,
+				configPath: absPath,
+				logger:     logger,
+			}, plan)
+		}
+		if err := renderDeployPlanDiff(ctx, cmd.OutOrStdout(), state.NewDefaultManager(), flags.Env, plan); err != nil {
+			return err
+		}
+		notifier.send(ctx, notify.EventStarted, "", nil)
+		logPhaseHooks(ctx, hookRunner)
 		// Dry-run does not create or modify state file
 		// It only shows what would happen
 		return nil
 	}
 
+	// Record the attempt in the audit log once past dry-run, including
+	// failures; the release ID is filled in once the release exists.
+	var releaseID string
+	defer func() {
+		recordAudit(ctx, cmd, logger, flags.Env, releaseID, err)
+		notifier.finish(ctx, notify.EventSucceeded, releaseID, err)
+	}()
+
 	// Initialize state manager
 	stateMgr := state.NewDefaultManager()
 
@@ -147,10 +256,18 @@ func runDeployWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 	logger.Info("Release created",
 		logging.NewField("release_id", release.ID),
 	)
+	releaseID = release.ID
+	span.SetAttributes(telemetry.AttrReleaseID.String(release.ID))
+	notifier.send(ctx, notify.EventStarted, release.ID, nil)
+	hookRunner.SetReleaseID(release.ID)
 
-	// Generate deployment plan
-	planner := core.NewPlanner(cfg)
-	plan, err := planner.PlanDeploy(flags.Env)
+	// Generate deployment plan, unless executing a reviewed plan as-is
+	plan := appliedPlan(applied)
+	if plan == nil {
+		_, planSpan := telemetry.StartSpan(ctx, "plan", telemetry.AttrEnvironment.String(flags.Env))
+		plan, err = core.NewPlanner(cfg).PlanDeploy(flags.Env)
+		telemetry.EndSpan(planSpan, err)
+	}
 	if err != nil {
 		// Mark all phases as failed if plan generation fails
 		markAllPhasesFailedCommon(ctx, stateMgr, release.ID, logger)
@@ -164,14 +281,68 @@ func runDeployWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 	plan.Metadata["release_id"] = release.ID
 	plan.Metadata["version"] = version
 	plan.Metadata["config_path"] = absPath
-	plan.Metadata["workdir"], _ = os.Getwd()
+	// workdir is the source tree (build contexts, compose files); output_dir
+	// is where persistent outputs go. They differ only under reconcile.
+	workdir, _ := os.Getwd()
+	plan.Metadata["workdir"] = workdir
+	if outputDir == "" {
+		outputDir = workdir
+	}
+	plan.Metadata["output_dir"] = outputDir
 
 	logger.Debug("Deployment plan generated",
 		logging.NewField("operations", len(plan.Operations)),
 	)
 
-	// Execute deployment phases using shared helper
-	err = executePhasesCommon(ctx, stateMgr, release.ID, plan, logger, fns)
+	// Render the compose file at plan time. DEPLOY_TEMPLATES: a broken
+	// template fails the deploy before anything is built. DEPLOY_ROLLOUT:
+	// fresh plans carry each service's compose fragment hash.
+	if redeploy == nil {
+		rendered, renderErr := renderDeployCompose(cfg, flags.Env, version, composeRenderOptions{})
+		if renderErr != nil && len(cfg.TemplatesFor(flags.Env)) > 0 {
+			markAllPhasesFailedCommon(ctx, stateMgr, release.ID, logger)
+			return fmt.Errorf("rendering templates: %w", renderErr)
+		}
+		if renderErr == nil && applied == nil {
+			addComposeHashes(plan, rendered.Data, logger)
+		}
+	}
+
+	recordDeployPlan(ctx, stateMgr, release.ID, plan, logger)
+	recordDeployMigrations(ctx, stateMgr, release.ID, cfg, workdir, logger)
+
+	// DEPLOY_BUNDLE: capture what this release rolls out, or roll out a
+	// stored bundle as-is
+	if redeploy != nil {
+		plan.Metadata["bundle"] = redeploy
+		plan.Metadata["from_bundle"] = true
+	} else if b, err := newDeployBundle(ctx, cfg, flags.Env, absPath, workdir, version, commitSHA, plan); err != nil {
+		logger.Warn("Failed to create deploy bundle",
+			logging.NewField("error", err.Error()),
+		)
+	} else {
+		plan.Metadata["bundle"] = b
+	}
+
+	// DEPLOY_ROLLOUT: services recorded by the last completed rollout, for
+	// rollout.skip_unchanged
+	plan.Metadata["previous_services"] = previousServiceRollouts(ctx, stateMgr, flags.Env, release.ID)
+
+	// Execute deployment phases using shared helper, with configured
+	// pre/post hooks and step timeouts around each phase
+	err = executePhasesCommon(ctx, stateMgr, release.ID, plan, logger, withPhaseHooks(withPhaseTimeouts(fns, cfg.Engine), hookRunner))
+	recordBuiltImages(ctx, stateMgr, release.ID, plan, logger)
+	recordServiceRollouts(ctx, stateMgr, release.ID, plan, logger)
+	if redeploy != nil {
+		if recErr := stateMgr.RecordBundle(ctx, release.ID, redeployRef); recErr != nil {
+			logger.Warn("Failed to record deploy bundle",
+				logging.NewField("release_id", release.ID),
+				logging.NewField("error", recErr.Error()),
+			)
+		}
+	} else {
+		storeDeployBundle(ctx, stateMgr, cfg, outputDir, release.ID, deployBundleFromPlan(plan), logger)
+	}
 	if err != nil {
 		return fmt.Errorf("deployment failed: %w", err)
 	}
@@ -279,6 +450,54 @@ func getDeployContext(plan *core.Plan) (configPath, version, workdir string, err
 	return configPath, version, workdir, nil
 }
 
+// deployOutputDir returns the directory the deploy's persistent outputs
+// are written under: plan metadata "output_dir" when set, else workdir.
+func deployOutputDir(plan *core.Plan, workdir string) string {
+	if dir, ok := plan.Metadata["output_dir"].(string); ok && dir != "" {
+		return dir
+	}
+	return workdir
+}
+
+// deployImageTag returns the image tag a deploy builds.
+// For v1, use simple format: <project-name>:<version>
+// TODO: Add registry support when project.registry is added to config
+func deployImageTag(cfg *config.Config, version string) string {
+	return fmt.Sprintf("%s:%s", cfg.Project.Name, version)
+}
+
+// deployServiceImageTag returns the image tag for a backend service. The
+// single-service shorthand keeps deployImageTag; named services use
+// <project-name>-<service>:<version>.
+func deployServiceImageTag(cfg *config.Config, service, version string) string {
+	if !cfg.Backend.IsMultiService() {
+		return deployImageTag(cfg, version)
+	}
+	return fmt.Sprintf("%s-%s:%s", cfg.Project.Name, service, version)
+}
+
+// pushCommand returns the command that pushes image.
+func pushCommand(runtime containerruntime.Runtime, image string) executil.Command {
+	return runtime.Command("push", image)
+}
+
+// composeUpCommand returns the command that starts the rendered compose file
+// when rollout is disabled, limited to services when any are given.
+func composeUpCommand(runtime containerruntime.Runtime, renderedPath string, services ...string) executil.Command {
+	args := append([]string{"-f", renderedPath, "up", "-d"}, services...)
+	return runtime.ComposeCommand(args...)
+}
+
+// deployRuntime resolves the container runtime for the config at
+// configPath, falling back to detection when it cannot be loaded.
+func deployRuntime(configPath string) containerruntime.Runtime {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return containerruntime.Detect()
+	}
+	return containerruntime.ForConfig(cfg)
+}
+
 // executeBuildPhase builds Docker images using the configured backend provider.
 func executeBuildPhase(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
 	configPath, version, workdir, err := getDeployContext(plan)
@@ -299,59 +518,229 @@ func executeBuildPhase(ctx context.Context, plan *core.Plan, logger logging.Logg
 		return fmt.Errorf("no backend configuration found")
 	}
 
-	// Get backend provider
-	providerID := cfg.Backend.Provider
+	// DEPLOY_BUNDLE: a re-deploy rolls out the images the bundle recorded
+	if b := redeployedBundle(plan); b != nil {
+		useBundledImages(plan, cfg, b, logger)
+		return nil
+	}
+
+	// Provider output is scoped so it can be filtered with --log-level provider=...
+	logger = logger.Named(logging.SubsystemProvider)
+
+	// CLI_BUILD: --service/--no-cache/--platform and per-environment cache settings
+	buildOpts := buildOptionsFromPlan(plan)
+	envCfg, hasEnv := cfg.Environments[plan.Environment]
+	base := resolveBuildDockerOptions(envCfg.Build, buildOpts)
+
+	platforms := buildPlatforms(envCfg.Build, buildOpts)
+
+	// CORE_BACKEND_SERVICES: build every backend service in name order.
+	runner := newRunner()
+	runtime := containerruntime.ForConfig(cfg)
+	builtImages := make(map[string]string)
+	imageIDs := make(map[string]string)
+	platformImages := make(map[string][]state.PlatformImage)
+	for _, svc := range selectBuildServices(cfg.Backend.ServiceList(), buildOpts.Services) {
+		imageTag := deployServiceImageTag(cfg, svc.Name, version)
+
+		if len(platforms) > 1 {
+			// Multi-platform: one image per platform, combined into a
+			// manifest list under imageTag by the push phase.
+			for _, platform := range platforms {
+				opts := base
+				opts.Platform = platform
+				builtImage, err := buildBackendService(ctx, cfg, svc, platformImageTag(imageTag, platform), workdir, opts, logger)
+				if err != nil {
+					return err
+				}
+				platformImages[svc.Name] = append(platformImages[svc.Name], state.PlatformImage{
+					Platform: platform,
+					Tag:      builtImage,
+					ID:       inspectImageID(ctx, runner, runtime, builtImage, logger),
+				})
+			}
+			builtImages[svc.Name] = imageTag
+			continue
+		}
+
+		opts := base
+		if len(platforms) == 1 {
+			opts.Platform = platforms[0]
+		}
+		builtImage, err := buildBackendService(ctx, cfg, svc, imageTag, workdir, opts, logger)
+		if err != nil {
+			return err
+		}
+		builtImages[svc.Name] = builtImage
+		if id := inspectImageID(ctx, runner, runtime, builtImage, logger); id != "" {
+			imageIDs[svc.Name] = id
+		}
+	}
+
+	// Store built image tags in plan metadata for push and rollout phases
+	if plan.Metadata == nil {
+		plan.Metadata = make(map[string]interface{})
+	}
+	plan.Metadata["built_image"] = primaryImage(cfg, builtImages)
+	plan.Metadata["built_images"] = builtImages
+	plan.Metadata["image_ids"] = imageIDs
+	plan.Metadata["platform_images"] = platformImages
+
+	// DEPLOY_IMAGE_SCAN: optional SBOM and vulnerability scan of the built images
+	if err := scanBuiltImages(ctx, runner, runtime, envCfg.Build, plan, deployOutputDir(plan, workdir), logger); err != nil {
+		return err
+	}
+
+	// DEPLOY_FRONTEND_STATIC: build the frontend assets served by rollout.
+	// A service-filtered build only builds the selected backend services.
+	if hasEnv && envCfg.FrontendStatic != nil && len(buildOpts.Services) == 0 {
+		assetsDir, err := buildFrontendStatic(ctx, cfg, workdir, logger)
+		if err != nil {
+			return err
+		}
+		plan.Metadata["frontend_assets"] = assetsDir
+	}
+
+	return nil
+}
+
+// buildBackendService builds one backend service's image with its provider.
+func buildBackendService(
+	ctx context.Context,
+	cfg *config.Config,
+	svc config.BackendService,
+	imageTag, workdir string,
+	opts backendproviders.BuildDockerOptions,
+	logger logging.Logger,
+) (string, error) {
+	providerID := svc.Provider
 	provider, err := backendproviders.Get(providerID)
 	if err != nil {
-		return fmt.Errorf("getting backend provider %q: %w", providerID, err)
+		return "", fmt.Errorf("getting backend provider %q: %w", providerID, err)
 	}
 
 	// Get provider config
-	providerCfg, err := cfg.Backend.GetProviderConfig()
+	providerCfg, err := svc.GetProviderConfig()
 	if err != nil {
-		return fmt.Errorf("getting provider config: %w", err)
+		return "", fmt.Errorf("getting provider config: %w", err)
 	}
 
-	// Construct image tag
-	// For v1, use simple format: <project-name>:<version>
-	// If registry is configured, prepend it
-	imageTag := fmt.Sprintf("%s:%s", cfg.Project.Name, version)
-	// TODO: Add registry support when project.registry is added to config
+	serviceDir := backendServiceWorkDir(svc, workdir)
 
 	logger.Info("Building Docker image",
+		logging.NewField("service", svc.Name),
 		logging.NewField("provider", providerID),
 		logging.NewField("image", imageTag),
-		logging.NewField("workdir", workdir),
+		logging.NewField("platform", opts.Platform),
+		logging.NewField("workdir", serviceDir),
 	)
 
 	// Build image
-	opts := backendproviders.BuildDockerOptions{
-		Config:   providerCfg,
-		ImageTag: imageTag,
-		WorkDir:  workdir,
-	}
+	opts.Config = providerCfg
+	opts.ImageTag = imageTag
+	opts.WorkDir = serviceDir
 
-	builtImage, err := provider.BuildDocker(ctx, opts)
+	buildCtx, buildSpan := telemetry.StartSpan(ctx, "build.docker", telemetry.AttrProvider.String(providerID))
+	builtImage, err := provider.BuildDocker(buildCtx, opts)
+	telemetry.EndSpan(buildSpan, err)
 	if err != nil {
-		return fmt.Errorf("building Docker image: %w", err)
+		if cfg.Backend.IsMultiService() {
+			return "", fmt.Errorf("building Docker image for service %q: %w", svc.Name, err)
+		}
+		return "", fmt.Errorf("building Docker image: %w", err)
 	}
 
 	logger.Info("Docker image built successfully",
+		logging.NewField("service", svc.Name),
 		logging.NewField("image", builtImage),
 	)
 
-	// Store built image tag in plan metadata for push phase
-	if plan.Metadata == nil {
-		plan.Metadata = make(map[string]interface{})
+	return builtImage, nil
+}
+
+// primaryImage returns the primary backend service's image, or the first
+// service's image when every service is a worker.
+func primaryImage(cfg *config.Config, builtImages map[string]string) string {
+	if primary, ok := cfg.Backend.PrimaryService(); ok {
+		return builtImages[primary.Name]
 	}
-	plan.Metadata["built_image"] = builtImage
+	services := cfg.Backend.ServiceList()
+	if len(services) == 0 {
+		return ""
+	}
+	return builtImages[services[0].Name]
+}
 
-	return nil
+// backendServiceWorkDir resolves a service's workdir against the project root.
+func backendServiceWorkDir(svc config.BackendService, workdir string) string {
+	if svc.WorkDir == "" {
+		return workdir
+	}
+	if filepath.IsAbs(svc.WorkDir) {
+		return svc.WorkDir
+	}
+	return filepath.Join(workdir, svc.WorkDir)
+}
+
+// builtImagesFromPlan returns the per-service images recorded by the build
+// phase, sorted by service name. Plans built before multi-service support
+// only carry built_image.
+func builtImagesFromPlan(plan *core.Plan) ([]string, map[string]string) {
+	images, _ := plan.Metadata["built_images"].(map[string]string)
+	if len(images) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, images
+}
+
+// buildFrontendStatic runs the frontend provider's static build and returns
+// the asset directory.
+func buildFrontendStatic(ctx context.Context, cfg *config.Config, workdir string, logger logging.Logger) (string, error) {
+	providerID := cfg.Frontend.Provider
+	provider, err := frontendproviders.Get(providerID)
+	if err != nil {
+		return "", fmt.Errorf("getting frontend provider %q: %w", providerID, err)
+	}
+
+	builder, ok := provider.(frontendproviders.StaticBuilder)
+	if !ok {
+		return "", fmt.Errorf("frontend provider %q does not support static builds", providerID)
+	}
+
+	providerCfg, err := cfg.Frontend.GetProviderConfig()
+	if err != nil {
+		return "", fmt.Errorf("getting frontend provider config: %w", err)
+	}
+
+	logger.Info("Building frontend static assets",
+		logging.NewField("provider", providerID),
+		logging.NewField("workdir", workdir),
+	)
+
+	buildCtx, buildSpan := telemetry.StartSpan(ctx, "build.frontend", telemetry.AttrProvider.String(providerID))
+	assetsDir, err := builder.Build(buildCtx, frontendproviders.BuildOptions{
+		Config:  providerCfg,
+		WorkDir: workdir,
+	})
+	telemetry.EndSpan(buildSpan, err)
+	if err != nil {
+		return "", fmt.Errorf("building frontend static assets: %w", err)
+	}
+
+	logger.Info("Frontend static assets built successfully",
+		logging.NewField("assets", assetsDir),
+	)
+	return assetsDir, nil
 }
 
 // executePushPhase pushes the built Docker image to the registry.
 func executePushPhase(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
-	_, _, _, err := getDeployContext(plan)
+	configPath, _, workdir, err := getDeployContext(plan)
 	if err != nil {
 		return fmt.Errorf("getting deployment context: %w", err)
 	}
@@ -361,32 +750,128 @@ func executePushPhase(ctx context.Context, plan *core.Plan, logger logging.Logge
 		return fmt.Errorf("plan metadata is missing")
 	}
 
+	// DEPLOY_BUNDLE: a re-deployed bundle's images are already in the registry
+	if redeployedBundle(plan) != nil {
+		logger.Info("Re-deploying a bundle; skipping push")
+		return nil
+	}
+
 	builtImage, ok := plan.Metadata["built_image"].(string)
-	if !ok || builtImage == "" {
-		return fmt.Errorf("built image not found in plan metadata (build phase may have failed)")
+	names, byService := builtImagesFromPlan(plan)
+	if len(names) == 0 {
+		if !ok || builtImage == "" {
+			return fmt.Errorf("built image not found in plan metadata (build phase may have failed)")
+		}
+		names, byService = []string{""}, map[string]string{"": builtImage}
+	}
+
+	runner := executil.NewRunner()
+	runtime := deployRuntime(configPath)
+	platformImages, _ := plan.Metadata["platform_images"].(map[string][]state.PlatformImage)
+	digests := make(map[string]string)
+	for _, name := range names {
+		var digest string
+		if images := platformImages[name]; len(images) > 0 {
+			digest, err = pushManifestList(ctx, runner, runtime, byService[name], images, logger)
+		} else {
+			digest, err = pushImage(ctx, runner, runtime, byService[name], logger)
+		}
+		if err != nil {
+			return err
+		}
+		if digest != "" && name != "" {
+			digests[name] = digest
+		}
+	}
+	plan.Metadata["image_digests"] = digests
+
+	// DEPLOY_IMAGE_SIGNING: sign what was just pushed
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
 	}
+	return signPushedImages(ctx, runner, cfg, plan, workdir, logger)
+}
+
+// pushManifestList pushes the per-platform images of a multi-platform
+// build, recording each digest in images, then publishes the manifest list
+// that references them under list. It returns the manifest list digest.
+func pushManifestList(
+	ctx context.Context,
+	runner executil.Runner,
+	runtime containerruntime.Runtime,
+	list string,
+	images []state.PlatformImage,
+	logger logging.Logger,
+) (string, error) {
+	tags := make([]string, 0, len(images))
+	for i := range images {
+		digest, err := pushImage(ctx, runner, runtime, images[i].Tag, logger)
+		if err != nil {
+			return "", err
+		}
+		images[i].Digest = digest
+		tags = append(tags, images[i].Tag)
+	}
+
+	logger.Info("Publishing manifest list",
+		logging.NewField("image", list),
+		logging.NewField("platforms", len(images)),
+	)
+
+	if _, err := runner.Run(ctx, runtime.ManifestCreateCommand(list, tags...)); err != nil {
+		return "", fmt.Errorf("creating manifest list %q: %w", list, err)
+	}
+	result, err := runner.Run(ctx, runtime.ManifestPushCommand(list))
+	if err != nil {
+		return "", fmt.Errorf("pushing manifest list %q: %w", list, err)
+	}
+
+	digest := manifestDigestPattern.FindString(string(result.Stdout))
+	logger.Info("Manifest list pushed successfully",
+		logging.NewField("image", list),
+		logging.NewField("digest", digest),
+	)
+	return digest, nil
+}
 
+// pushDigestPattern matches the digest line docker and podman print after
+// a successful push.
+var pushDigestPattern = regexp.MustCompile(`digest: (sha256:[0-9a-f]{64})`)
+
+// manifestDigestPattern matches the digest `manifest push` prints.
+var manifestDigestPattern = regexp.MustCompile(`sha256:[0-9a-f]{64}`)
+
+// pushImage pushes a single image using the container runtime's CLI and
+// returns the registry digest when the output reports one.
+func pushImage(ctx context.Context, runner executil.Runner, runtime containerruntime.Runtime, image string, logger logging.Logger) (string, error) {
 	logger.Info("Pushing Docker image",
-		logging.NewField("image", builtImage),
+		logging.NewField("image", image),
 	)
 
-	// Push image using docker CLI
-	runner := executil.NewRunner()
-	cmd := executil.NewCommand("docker", "push", builtImage)
-	result, err := runner.Run(ctx, cmd)
+	cmd := pushCommand(runtime, image)
+	pushCtx, pushSpan := telemetry.StartSpan(ctx, "docker.push")
+	result, err := runner.Run(pushCtx, cmd)
+	telemetry.EndSpan(pushSpan, err)
 	if err != nil {
-		return fmt.Errorf("pushing image %q: %w", builtImage, err)
+		return "", fmt.Errorf("pushing image %q: %w", image, err)
 	}
 
 	if result.ExitCode != 0 {
-		return fmt.Errorf("docker push failed with exit code %d: %s", result.ExitCode, string(result.Stderr))
+		return "", fmt.Errorf("%s push failed with exit code %d: %s", runtime.Binary(), result.ExitCode, string(result.Stderr))
+	}
+
+	digest := ""
+	if m := pushDigestPattern.FindSubmatch(result.Stdout); m != nil {
+		digest = string(m[1])
 	}
 
 	logger.Info("Docker image pushed successfully",
-		logging.NewField("image", builtImage),
+		logging.NewField("image", image),
+		logging.NewField("digest", digest),
 	)
 
-	return nil
+	return digest, nil
 }
 
 // executeMigratePrePhase is a placeholder for pre-deployment migrations.
@@ -397,6 +882,47 @@ func executeMigratePrePhase(ctx context.Context, plan *core.Plan, logger logging
 	return nil
 }
 
+// rolloutServices runs docker-rollout for the rendered compose file. When the
+// project declares worker services, docker-rollout is limited to the other
+// services and workers are recreated afterwards (see ExecuteWithWorkers).
+// Service names are read from listPath, normally the rendered file itself.
+// A non-nil only limits the rollout to those services.
+func rolloutServices(ctx context.Context, executor *deploy.RolloutExecutor, cfg *config.Config, listPath, renderedPath string, only []string) error {
+	workers := cfg.Backend.WorkerServices()
+	if len(workers) == 0 && only == nil {
+		return executor.Execute(ctx, renderedPath)
+	}
+
+	composeFile, err := compose.NewLoader().Load(listPath)
+	if err != nil {
+		return fmt.Errorf("loading compose file: %w", err)
+	}
+
+	isWorker := make(map[string]bool, len(workers))
+	for _, name := range workers {
+		isWorker[name] = true
+	}
+
+	selected := make(map[string]bool, len(only))
+	for _, name := range only {
+		selected[name] = true
+	}
+
+	var services, present []string
+	for _, name := range composeFile.GetServices() {
+		if only != nil && !selected[name] {
+			continue
+		}
+		if isWorker[name] {
+			present = append(present, name)
+			continue
+		}
+		services = append(services, name)
+	}
+
+	return executor.ExecuteWithWorkers(ctx, renderedPath, services, present)
+}
+
 // executeRolloutPhase deploys the application using Docker Compose.
 func executeRolloutPhase(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
 	configPath, _, workdir, err := getDeployContext(plan)
@@ -425,35 +951,57 @@ func executeRolloutPhase(ctx context.Context, plan *core.Plan, logger logging.Lo
 		logging.NewField("image", builtImage),
 	)
 
-	// DEPLOY_COMPOSE_GEN: Generate compose file with image tag injected
-	baseComposePath := filepath.Join(workdir, "docker-compose.yml")
-	if _, err := os.Stat(baseComposePath); err != nil {
-		return fmt.Errorf("docker-compose.yml not found at %s: %w", baseComposePath, err)
+	// DEPLOY_BUNDLE: a re-deploy rolls out the bundled files byte for byte
+	var renderedPath, composeHash string
+	if b := redeployedBundle(plan); b != nil {
+		renderedPath, composeHash, err = writeBundledCompose(b, workdir)
+	} else {
+		renderedPath, composeHash, err = generateRolloutCompose(ctx, cfg, plan, workdir, builtImage, logger)
 	}
-
-	generator := newComposeGenerator()
-	renderedPath, composeHash, err := generator.Generate(
-		cfg,
-		plan.Environment,
-		baseComposePath,
-		builtImage,
-		workdir,
-	)
 	if err != nil {
-		return fmt.Errorf("generating compose file: %w", err)
+		return err
 	}
 
-	logger.Debug("Compose file generated",
+	logger.Named(logging.SubsystemCompose).Debug("Compose file generated",
 		logging.NewField("path", renderedPath),
 		logging.NewField("hash", composeHash),
 	)
 
+	// DEPLOY_ROLLOUT: with rollout.skip_unchanged, restart only the services
+	// whose compose fragment or image changed since the last rollout
+	services := selectRolloutServices(cfg, plan, renderedPath, logger)
+
+	// DEPLOY_HOST_STATUS: record the outcome on the target host
+	started := time.Now()
+	err = rolloutCompose(ctx, cfg, plan.Environment, renderedPath, composeHash, services, logger)
+	recordHostResult(ctx, plan, deployTargetHost(), started, err)
+	return err
+}
+
+// rolloutCompose rolls out the rendered compose file, with docker-rollout
+// when the environment enables it and docker compose up otherwise. A
+// non-nil services limits the rollout to those services; an empty one
+// rolls out nothing.
+func rolloutCompose(ctx context.Context, cfg *config.Config, env, renderedPath, composeHash string, services []string, logger logging.Logger) error {
+	if services != nil && len(services) == 0 {
+		logger.Info("No services changed; skipping rollout",
+			logging.NewField("environment", env),
+			logging.NewField("compose_hash", composeHash),
+		)
+		return nil
+	}
+
 	// Check if rollout is enabled
-	rolloutEnabled := cfg.Environments[plan.Environment].Rollout != nil &&
-		cfg.Environments[plan.Environment].Rollout.Enabled
+	rolloutEnabled := cfg.Environments[env].Rollout != nil &&
+		cfg.Environments[env].Rollout.Enabled
+
+	runtime := containerruntime.ForConfig(cfg)
 
 	if rolloutEnabled {
-		executor := deploy.NewRolloutExecutor()
+		if runtime != containerruntime.Docker {
+			return fmt.Errorf("rollout.enabled requires the docker runtime; %s was detected (set container_runtime or disable rollout)", runtime)
+		}
+		executor := deploy.NewRolloutExecutor().WithRuntime(runtime)
 		available, err := executor.IsAvailable(ctx)
 		if err != nil {
 			return fmt.Errorf("checking docker-rollout availability: %w", err)
@@ -462,28 +1010,33 @@ func executeRolloutPhase(ctx context.Context, plan *core.Plan, logger logging.Lo
 			return fmt.Errorf("%s", deploy.RolloutNotInstalledMessage)
 		}
 
-		if err := executor.Execute(ctx, renderedPath); err != nil {
+		rolloutCtx, rolloutSpan := telemetry.StartSpan(ctx, "rollout.execute")
+		err = rolloutServices(rolloutCtx, executor, cfg, renderedPath, renderedPath, services)
+		telemetry.EndSpan(rolloutSpan, err)
+		if err != nil {
 			return fmt.Errorf("rollout failed: %w", err)
 		}
 
 		logger.Info("Rollout completed successfully",
-			logging.NewField("environment", plan.Environment),
+			logging.NewField("environment", env),
 		)
 	} else {
 		// Fallback to docker compose up (existing behavior)
 		runner := newRunner()
-		cmd := executil.NewCommand("docker", "compose", "-f", renderedPath, "up", "-d")
-		result, err := runner.Run(ctx, cmd)
+		cmd := composeUpCommand(runtime, renderedPath, services...)
+		upCtx, upSpan := telemetry.StartSpan(ctx, "compose.up")
+		result, err := runner.Run(upCtx, cmd)
+		telemetry.EndSpan(upSpan, err)
 		if err != nil {
-			return fmt.Errorf("running docker compose up: %w", err)
+			return fmt.Errorf("running %s compose up: %w", runtime, err)
 		}
 
 		if result.ExitCode != 0 {
-			return fmt.Errorf("docker compose up failed with exit code %d: %s", result.ExitCode, string(result.Stderr))
+			return fmt.Errorf("%s compose up failed with exit code %d: %s", runtime, result.ExitCode, string(result.Stderr))
 		}
 
 		logger.Info("Deployment rolled out successfully",
-			logging.NewField("environment", plan.Environment),
+			logging.NewField("environment", env),
 			logging.NewField("compose_hash", composeHash),
 		)
 	}
@@ -491,6 +1044,49 @@ func executeRolloutPhase(ctx context.Context, plan *core.Plan, logger logging.Lo
 	return nil
 }
 
+// generateRolloutCompose generates the compose file to roll out, with the
+// built images injected, and records the generated files in the deploy's
+// bundle.
+func generateRolloutCompose(
+	ctx context.Context,
+	cfg *config.Config,
+	plan *core.Plan,
+	workdir, builtImage string,
+	logger logging.Logger,
+) (renderedPath, composeHash string, err error) {
+	// DEPLOY_COMPOSE_GEN: Generate compose file with image tag injected
+	baseComposePath := filepath.Join(workdir, "docker-compose.yml")
+	if _, err := os.Stat(baseComposePath); err != nil {
+		return "", "", fmt.Errorf("docker-compose.yml not found at %s: %w", baseComposePath, err)
+	}
+
+	assetsDir, _ := plan.Metadata["frontend_assets"].(string)
+	_, serviceImages := builtImagesFromPlan(plan)
+
+	// DEPLOY_IMAGE_SIGNING: only verified images, pinned by digest, are rolled out
+	serviceImages, builtImage, err = verifySignedImages(ctx, newRunner(), cfg, plan, workdir, serviceImages, builtImage, logger)
+	if err != nil {
+		return "", "", err
+	}
+
+	generator := newComposeGenerator().WithStaticAssets(assetsDir).WithServiceImages(serviceImages)
+	_, genSpan := telemetry.StartSpan(ctx, "compose.generate")
+	renderedPath, composeHash, err = generator.Generate(
+		cfg,
+		plan.Environment,
+		baseComposePath,
+		builtImage,
+		workdir,
+	)
+	telemetry.EndSpan(genSpan, err)
+	if err != nil {
+		return "", "", fmt.Errorf("generating compose file: %w", err)
+	}
+
+	refreshDeployBundle(plan, workdir, renderedPath, generator.RenderedFiles(), serviceImages, logger)
+	return renderedPath, composeHash, nil
+}
+
 // executeMigratePostPhase is a placeholder for post-deployment migrations.
 // In v1, this is a no-op. Future implementation will integrate with migration engines.
 func executeMigratePostPhase(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
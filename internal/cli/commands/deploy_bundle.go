@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/bundle"
+	"stagecraft/internal/compose"
+	"stagecraft/internal/core"
+	coreplan "stagecraft/internal/core/plan"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_BUNDLE
+// Spec: spec/deploy/bundle.md
+
+// addDeployBundleFlag registers --from-bundle on a deploy command.
+func addDeployBundleFlag(cmd *cobra.Command) {
+	cmd.Flags().String("from-bundle", "", "Re-deploy a stored bundle (release ID or bundle file) without building or pushing")
+}
+
+// parseDeployBundleFlag reads --from-bundle and rejects the flags that
+// would change what the bundle rolls out.
+func parseDeployBundleFlag(cmd *cobra.Command, planFlags deployPlanFlags) (string, error) {
+	ref, _ := cmd.Flags().GetString("from-bundle")
+	if ref == "" {
+		return "", nil
+	}
+	if planFlags.PlanOnly || planFlags.ApplyPlan != "" {
+		return "", fmt.Errorf("--from-bundle cannot be used with --plan-only or --apply-plan; the bundle records its plan")
+	}
+	if cmd.Flags().Changed("version") {
+		return "", fmt.Errorf("--version cannot be used with --from-bundle; the bundle records its version")
+	}
+	return ref, nil
+}
+
+// newDeployBundle packs the plan and the files deploy is about to roll out,
+// rendered with the image tags the build phase will produce. The rollout
+// phase replaces the files with the ones it actually writes.
+func newDeployBundle(
+	ctx context.Context,
+	cfg *config.Config,
+	env, configPath, workdir, version, commitSHA string,
+	plan *core.Plan,
+) (*bundle.Bundle, error) {
+	configHash, err := coreplan.HashFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	artifact, err := coreplan.NewArtifact(plan, version, commitSHA, configHash)
+	if err != nil {
+		return nil, fmt.Errorf("building plan artifact: %w", err)
+	}
+	planJSON, err := artifact.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := renderDeployCompose(cfg, env, version, composeRenderOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	images := make(map[string]string)
+	for _, svc := range cfg.Backend.ServiceList() {
+		images[svc.Name] = deployServiceImageTag(cfg, svc.Name, version)
+	}
+
+	var envVars map[string]string
+	if path := cfg.Environments[env].EnvFile; path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workdir, path)
+		}
+		if envVars, err = readOptionalEnvFile(ctx, path); err != nil {
+			return nil, err
+		}
+	}
+
+	b := &bundle.Bundle{
+		Manifest: bundle.Manifest{
+			SchemaVersion: bundle.SchemaVersion,
+			Environment:   env,
+			Version:       version,
+			CommitSHA:     commitSHA,
+		},
+		Plan:        planJSON,
+		EnvTemplate: bundle.EnvTemplate(envVars),
+	}
+	if err := setBundleFiles(b, workdir, rendered.Path, rendered.Files, images); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// setBundleFiles replaces the bundle's files, compose file, and images.
+// Paths are stored relative to workdir.
+func setBundleFiles(b *bundle.Bundle, workdir, composePath string, files map[string][]byte, images map[string]string) error {
+	b.Files = make(map[string][]byte, len(files))
+	for path, data := range files {
+		name, err := bundlePath(workdir, path)
+		if err != nil {
+			return err
+		}
+		b.Files[name] = data
+	}
+
+	name, err := bundlePath(workdir, composePath)
+	if err != nil {
+		return err
+	}
+	b.Manifest.ComposeFile = name
+
+	b.Manifest.Images = make(map[string]string, len(images))
+	for svc, image := range images {
+		b.Manifest.Images[svc] = image
+	}
+	return nil
+}
+
+// bundlePath returns path relative to workdir in slash form. Files outside
+// workdir cannot be re-deployed from a bundle.
+func bundlePath(workdir, path string) (string, error) {
+	rel, err := filepath.Rel(workdir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("bundling %s: file is outside the project directory", path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// deployBundleFromPlan returns the bundle carried in plan metadata, or nil.
+func deployBundleFromPlan(plan *core.Plan) *bundle.Bundle {
+	b, _ := plan.Metadata["bundle"].(*bundle.Bundle)
+	return b
+}
+
+// redeployedBundle returns the bundle being re-deployed with
+// --from-bundle, or nil for a regular deploy.
+func redeployedBundle(plan *core.Plan) *bundle.Bundle {
+	if redeploy, _ := plan.Metadata["from_bundle"].(bool); !redeploy {
+		return nil
+	}
+	return deployBundleFromPlan(plan)
+}
+
+// refreshDeployBundle replaces the files of the deploy's bundle with the
+// ones the rollout phase wrote, which may pin verified digests or serve
+// frontend assets that were unknown at plan time. Failures only warn.
+func refreshDeployBundle(plan *core.Plan, workdir, composePath string, files map[string][]byte, images map[string]string, logger logging.Logger) {
+	b := deployBundleFromPlan(plan)
+	if b == nil || redeployedBundle(plan) != nil {
+		return
+	}
+	if err := setBundleFiles(b, workdir, composePath, files, images); err != nil {
+		logger.Warn("Failed to update deploy bundle",
+			logging.NewField("error", err.Error()),
+		)
+	}
+}
+
+// storeDeployBundle stores the release's bundle and records it on the
+// release. Failures are logged, not fatal, like the plan fingerprint.
+func storeDeployBundle(
+	ctx context.Context,
+	stateMgr *state.Manager,
+	cfg *config.Config,
+	workdir, releaseID string,
+	b *bundle.Bundle,
+	logger logging.Logger,
+) {
+	if b == nil {
+		return
+	}
+
+	data, err := b.Encode()
+	var location string
+	if err == nil {
+		location, err = bundle.NewStore(cfg.Artifacts, workdir, newRunner()).Put(ctx, bundle.FileName(releaseID), data)
+	}
+	ref := state.BundleRef{Location: location, Digest: bundle.Digest(data)}
+	if err == nil {
+		err = stateMgr.RecordBundle(ctx, releaseID, ref)
+	}
+	if err != nil {
+		logger.Warn("Failed to store deploy bundle",
+			logging.NewField("release_id", releaseID),
+			logging.NewField("error", err.Error()),
+		)
+		return
+	}
+
+	logger.Info("Deploy bundle stored",
+		logging.NewField("location", ref.Location),
+		logging.NewField("digest", ref.Digest),
+	)
+}
+
+// loadDeployBundle reads the bundle ref names — a bundle file, or the ID of
+// a release with a recorded bundle — and refuses it if it does not match
+// the digest recorded for the release or targets a different environment.
+func loadDeployBundle(
+	ctx context.Context,
+	stateMgr *state.Manager,
+	cfg *config.Config,
+	ref, env string,
+	logger logging.Logger,
+) (*bundle.Bundle, state.BundleRef, error) {
+	var data []byte
+	var recorded state.BundleRef
+
+	if _, statErr := os.Stat(ref); statErr == nil {
+		// #nosec G304 -- path is provided by the operator
+		read, err := os.ReadFile(ref)
+		if err != nil {
+			return nil, recorded, fmt.Errorf("reading deploy bundle: %w", err)
+		}
+		location, _ := filepath.Abs(ref)
+		data, recorded = read, state.BundleRef{Location: location, Digest: bundle.Digest(read)}
+	} else {
+		release, err := stateMgr.GetRelease(ctx, ref)
+		if errors.Is(err, state.ErrReleaseNotFound) {
+			return nil, recorded, fmt.Errorf("--from-bundle %s: no such bundle file or release", ref)
+		}
+		if err != nil {
+			return nil, recorded, fmt.Errorf("loading release %s: %w", ref, err)
+		}
+		if release.Bundle == nil {
+			return nil, recorded, fmt.Errorf("release %s has no recorded deploy bundle", ref)
+		}
+		recorded = *release.Bundle
+
+		data, err = bundle.Fetch(ctx, recorded.Location, cfg.Artifacts, newRunner())
+		if err != nil {
+			return nil, recorded, err
+		}
+		if err := bundle.Verify(data, recorded.Digest); err != nil {
+			return nil, recorded, fmt.Errorf("refusing to re-deploy %s: %w", ref, err)
+		}
+	}
+
+	b, err := bundle.Decode(data)
+	if err != nil {
+		return nil, recorded, err
+	}
+	if b.Manifest.Environment != env {
+		return nil, recorded, fmt.Errorf("bundle %s targets environment %q, not %q", ref, b.Manifest.Environment, env)
+	}
+
+	logger.Info("Re-deploying bundle",
+		logging.NewField("bundle", recorded.Location),
+		logging.NewField("digest", recorded.Digest),
+		logging.NewField("version", b.Manifest.Version),
+	)
+	return b, recorded, nil
+}
+
+// bundledPlan returns the plan artifact recorded in b.
+func bundledPlan(b *bundle.Bundle) (*coreplan.Artifact, error) {
+	return coreplan.ParseArtifact(b.Plan, bundle.PlanName)
+}
+
+// useBundledImages stands in for the build phase of a re-deploy: the
+// bundle's images were built and pushed by the original release.
+func useBundledImages(plan *core.Plan, cfg *config.Config, b *bundle.Bundle, logger logging.Logger) {
+	images := make(map[string]string, len(b.Manifest.Images))
+	for svc, image := range b.Manifest.Images {
+		images[svc] = image
+	}
+	plan.Metadata["built_image"] = primaryImage(cfg, images)
+	plan.Metadata["built_images"] = images
+
+	logger.Info("Using images from deploy bundle; skipping build",
+		logging.NewField("images", len(images)),
+	)
+}
+
+// writeBundledCompose writes the bundle's files verbatim and returns the
+// compose file to roll out and its content hash.
+func writeBundledCompose(b *bundle.Bundle, workdir string) (string, string, error) {
+	path, err := b.WriteFiles(workdir)
+	if err != nil {
+		return "", "", fmt.Errorf("writing bundled compose files: %w", err)
+	}
+	return path, compose.ContentHash(b.Files[b.Manifest.ComposeFile]), nil
+}
@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/bundle"
+	"stagecraft/internal/core"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_BUNDLE
+// Spec: spec/deploy/bundle.md
+
+func setupBundleTestEnv(t *testing.T) *isolatedStateTestEnv {
+	t.Helper()
+	env := setupPlanArtifactTestEnv(t)
+	files := map[string]string{
+		"docker-compose.yml": "services:\n  backend:\n    build: .\n",
+		".env.staging":       "DATABASE_URL=postgres://app:secret@db/app\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(env.TempDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	config := planArtifactConfig + "    env_file: .env.staging\n"
+	if err := os.WriteFile(filepath.Join(env.TempDir, "stagecraft.yml"), []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return env
+}
+
+func TestDeployCommand_StoresBundle(t *testing.T) {
+	env := setupBundleTestEnv(t)
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0"); err != nil {
+		t.Fatalf("deploy failed: %v", err)
+	}
+
+	release, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+	if release.Bundle == nil {
+		t.Fatal("expected the release to record its bundle")
+	}
+	wantLocation := filepath.Join(env.TempDir, bundle.DefaultDir, bundle.FileName(release.ID))
+	if release.Bundle.Location != wantLocation {
+		t.Errorf("expected bundle at %s, got %s", wantLocation, release.Bundle.Location)
+	}
+
+	data, err := os.ReadFile(release.Bundle.Location)
+	if err != nil {
+		t.Fatalf("reading bundle: %v", err)
+	}
+	if err := bundle.Verify(data, release.Bundle.Digest); err != nil {
+		t.Fatalf("recorded digest does not match: %v", err)
+	}
+	b, err := bundle.Decode(data)
+	if err != nil {
+		t.Fatalf("decoding bundle: %v", err)
+	}
+
+	if b.Manifest.Version != "v1.0.0" || b.Manifest.Environment != "staging" {
+		t.Errorf("unexpected manifest: %+v", b.Manifest)
+	}
+	if b.Manifest.ComposeFile != ".stagecraft/rendered/staging/docker-compose.yml" {
+		t.Errorf("unexpected compose file %s", b.Manifest.ComposeFile)
+	}
+	if !strings.Contains(string(b.Files[b.Manifest.ComposeFile]), "image: test-app:v1.0.0") {
+		t.Errorf("expected the built image in the bundled compose file, got:\n%s", b.Files[b.Manifest.ComposeFile])
+	}
+	if string(b.EnvTemplate) != "DATABASE_URL=\n" {
+		t.Errorf("expected env template without values, got %q", b.EnvTemplate)
+	}
+	if _, err := bundledPlan(b); err != nil {
+		t.Errorf("expected a valid plan artifact in the bundle: %v", err)
+	}
+}
+
+func TestDeployCommand_FromBundleRollsOutBundledFiles(t *testing.T) {
+	env := setupBundleTestEnv(t)
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0"); err != nil {
+		t.Fatalf("deploy failed: %v", err)
+	}
+	original, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+	data, err := os.ReadFile(original.Bundle.Location)
+	if err != nil {
+		t.Fatalf("reading bundle: %v", err)
+	}
+	b, err := bundle.Decode(data)
+	if err != nil {
+		t.Fatalf("decoding bundle: %v", err)
+	}
+
+	// The base compose file changes after the release; the re-deploy must
+	// still roll out the bundled file.
+	if err := os.WriteFile(filepath.Join(env.TempDir, "docker-compose.yml"), []byte("services:\n  other:\n    image: x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var builtImage string
+	var rolledOut []byte
+	fns := noopPhaseFns()
+	fns.Build = buildPhaseFn
+	fns.Push = pushPhaseFn
+	fns.Rollout = func(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
+		builtImage, _ = plan.Metadata["built_image"].(string)
+		path, _, err := writeBundledCompose(redeployedBundle(plan), env.TempDir)
+		if err != nil {
+			return err
+		}
+		rolledOut, err = os.ReadFile(path)
+		return err
+	}
+
+	if err := executeDeployWithPhases(fns, "deploy", "--env", "staging", "--from-bundle", original.ID); err != nil {
+		t.Fatalf("re-deploy failed: %v", err)
+	}
+
+	if builtImage != "test-app:v1.0.0" {
+		t.Errorf("expected the bundled image, got %q", builtImage)
+	}
+	if !bytes.Equal(rolledOut, b.Files[b.Manifest.ComposeFile]) {
+		t.Errorf("expected the bundled compose file byte for byte, got:\n%s", rolledOut)
+	}
+
+	current, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+	if current.ID == original.ID || current.Version != "v1.0.0" {
+		t.Errorf("expected a new release of v1.0.0, got %+v", current)
+	}
+	if current.Bundle == nil || *current.Bundle != *original.Bundle {
+		t.Errorf("expected the re-deploy to record the original bundle, got %+v", current.Bundle)
+	}
+}
+
+func TestDeployCommand_FromBundleRefusesTamperedBundle(t *testing.T) {
+	env := setupBundleTestEnv(t)
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0"); err != nil {
+		t.Fatalf("deploy failed: %v", err)
+	}
+	release, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+
+	tampered, err := (&bundle.Bundle{
+		Manifest: bundle.Manifest{SchemaVersion: bundle.SchemaVersion, Environment: "staging", ComposeFile: "c.yml"},
+		Files:    map[string][]byte{"c.yml": []byte("services: {}\n")},
+	}).Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(release.Bundle.Location, tampered, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err = executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--from-bundle", release.ID)
+	if !errors.Is(err, bundle.ErrDigestMismatch) {
+		t.Fatalf("expected digest mismatch, got %v", err)
+	}
+}
+
+func TestDeployCommand_FromBundleFlagConflicts(t *testing.T) {
+	setupBundleTestEnv(t)
+
+	tests := map[string][]string{
+		"--version cannot be used with --from-bundle": {"--from-bundle", "rel-1", "--version", "v2"},
+		"--from-bundle cannot be used with":           {"--from-bundle", "rel-1", "--plan-only"},
+		"no such bundle file or release":              {"--from-bundle", "rel-missing"},
+	}
+	for want, flags := range tests {
+		args := append([]string{"deploy", "--env", "staging"}, flags...)
+		err := executeDeployWithPhases(noopPhaseFns(), args...)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%v: expected error containing %q, got %v", flags, want, err)
+		}
+	}
+}
+
+func TestRefreshDeployBundle_ReplacesRenderedFiles(t *testing.T) {
+	workdir := t.TempDir()
+	composePath := filepath.Join(workdir, ".stagecraft", "rendered", "prod", "docker-compose.yml")
+	b := &bundle.Bundle{Files: map[string][]byte{"old.yml": []byte("old")}}
+	plan := &core.Plan{Metadata: map[string]interface{}{"bundle": b}}
+
+	refreshDeployBundle(plan, workdir, composePath, map[string][]byte{composePath: []byte("new")},
+		map[string]string{"api": "app@sha256:1"}, logging.NewLogger(false))
+
+	if len(b.Files) != 1 || string(b.Files[".stagecraft/rendered/prod/docker-compose.yml"]) != "new" {
+		t.Errorf("expected rendered files to replace the bundle's, got %v", b.Files)
+	}
+	if b.Manifest.Images["api"] != "app@sha256:1" {
+		t.Errorf("expected rolled-out images, got %v", b.Manifest.Images)
+	}
+
+	// A re-deployed bundle is never rewritten.
+	plan.Metadata["from_bundle"] = true
+	refreshDeployBundle(plan, workdir, composePath, map[string][]byte{composePath: []byte("changed")}, nil, logging.NewLogger(false))
+	if string(b.Files[".stagecraft/rendered/prod/docker-compose.yml"]) != "new" {
+		t.Error("expected a re-deployed bundle to stay unchanged")
+	}
+}
+
+func TestBundlePath_RejectsOutsideWorkdir(t *testing.T) {
+	workdir := t.TempDir()
+	if _, err := bundlePath(workdir, filepath.Join(filepath.Dir(workdir), "elsewhere.yml")); err == nil {
+		t.Fatal("expected files outside the project directory to be rejected")
+	}
+	got, err := bundlePath(workdir, filepath.Join(workdir, "a", "b.yml"))
+	if err != nil || got != "a/b.yml" {
+		t.Fatalf("bundlePath() = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"stagecraft/internal/core"
+	coreplan "stagecraft/internal/core/plan"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: CORE_PLAN_DIFF
+// Spec: spec/core/plan-diff.md
+
+// renderDeployPlanDiff writes the difference between plan and the plan
+// recorded for env's current release to w.
+func renderDeployPlanDiff(ctx context.Context, w io.Writer, stateMgr *state.Manager, env string, plan *core.Plan) error {
+	next, err := coreplan.Fingerprint(plan)
+	if err != nil {
+		return fmt.Errorf("fingerprinting deployment plan: %w", err)
+	}
+
+	current, err := stateMgr.GetCurrentRelease(ctx, env)
+	switch {
+	case errors.Is(err, state.ErrReleaseNotFound):
+		_, _ = fmt.Fprintf(w, "Plan changes (no previous release for %s):\n", env)
+	case err != nil:
+		return fmt.Errorf("loading current release: %w", err)
+	case len(current.Plan) == 0:
+		_, _ = fmt.Fprintf(w, "Plan changes (release %s has no recorded plan):\n", current.ID)
+	default:
+		_, _ = fmt.Fprintf(w, "Plan changes since release %s:\n", current.ID)
+	}
+
+	var prev []state.PlanStep
+	if current != nil {
+		prev = current.Plan
+	}
+	return coreplan.Compare(prev, next).Render(w)
+}
+
+// recordDeployPlan stores plan's fingerprint on the release so the next
+// deploy can diff against it. Failures are logged, not fatal: the record
+// only feeds later dry-run output.
+func recordDeployPlan(ctx context.Context, stateMgr *state.Manager, releaseID string, plan *core.Plan, logger logging.Logger) {
+	steps, err := coreplan.Fingerprint(plan)
+	if err == nil {
+		err = stateMgr.RecordPlan(ctx, releaseID, steps)
+	}
+	if err != nil {
+		logger.Warn("Failed to record deployment plan",
+			logging.NewField("release_id", releaseID),
+			logging.NewField("error", err.Error()),
+		)
+	}
+}
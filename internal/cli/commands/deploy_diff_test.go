@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core/state"
+)
+
+// Feature: CORE_PLAN_DIFF
+// Spec: spec/core/plan-diff.md
+
+func executeDeployDryRun(t *testing.T) string {
+	t.Helper()
+	root := newTestRootCommand()
+	root.AddCommand(setupDeployCommand(noopPhaseFns()))
+	out, err := executeCommandForGolden(root, "deploy", "--env", "staging", "--dry-run")
+	if err != nil {
+		t.Fatalf("dry-run deploy failed: %v", err)
+	}
+	return out
+}
+
+func TestDeployCommand_DryRunWithoutPreviousRelease(t *testing.T) {
+	setupPlanArtifactTestEnv(t)
+
+	out := executeDeployDryRun(t)
+	if !strings.Contains(out, "no previous release for staging") {
+		t.Errorf("expected no-previous-release header, got:\n%s", out)
+	}
+	if !strings.Contains(out, "  + ") || !strings.Contains(out, "0 removed, 0 changed, 0 unchanged") {
+		t.Errorf("expected every step to be added, got:\n%s", out)
+	}
+}
+
+func TestDeployCommand_RecordsPlanAndDiffsAgainstIt(t *testing.T) {
+	env := setupPlanArtifactTestEnv(t)
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0"); err != nil {
+		t.Fatalf("deploy failed: %v", err)
+	}
+
+	release, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("loading release: %v", err)
+	}
+	if len(release.Plan) == 0 {
+		t.Fatalf("expected deploy to record its plan on release %s", release.ID)
+	}
+
+	out := executeDeployDryRun(t)
+	if !strings.Contains(out, "Plan changes since release "+release.ID) {
+		t.Errorf("expected diff header for %s, got:\n%s", release.ID, out)
+	}
+	if !strings.Contains(out, "0 added, 0 removed, 0 changed") {
+		t.Errorf("expected an unchanged plan, got:\n%s", out)
+	}
+
+	// Rewrite the recorded plan: drop the first step, alter the second,
+	// and add one the new plan no longer has.
+	if len(release.Plan) < 2 {
+		t.Fatalf("expected at least two plan steps, got %v", release.Plan)
+	}
+	recorded := append([]state.PlanStep(nil), release.Plan[1:]...)
+	recorded[0].InputsHash = "sha256:stale"
+	recorded = append(recorded, state.PlanStep{ID: "retired-step", Action: "build", InputsHash: "sha256:old"})
+	if err := env.Manager.RecordPlan(context.Background(), release.ID, recorded); err != nil {
+		t.Fatalf("rewriting recorded plan: %v", err)
+	}
+
+	out = executeDeployDryRun(t)
+	for _, want := range []string{
+		"  + " + release.Plan[0].ID,
+		"  - retired-step (build)",
+		"  ~ " + release.Plan[1].ID + " (" + release.Plan[1].Action + ", inputs changed)",
+		"1 added, 1 removed, 1 changed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in diff, got:\n%s", want, out)
+		}
+	}
+
+	// Dry-run must not record anything.
+	after, err := env.Manager.GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("loading release: %v", err)
+	}
+	if len(after.Plan) != len(recorded) {
+		t.Errorf("expected dry-run to leave the recorded plan alone, got %v", after.Plan)
+	}
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/containerruntime"
+	"stagecraft/internal/core"
+	"stagecraft/internal/deploy"
+	"stagecraft/internal/hooks"
+	"stagecraft/internal/notify"
+	"stagecraft/internal/sandbox"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+	backendproviders "stagecraft/pkg/providers/backend"
+)
+
+// Feature: DEPLOY_EXPLAIN
+// Spec: spec/deploy/explain.md
+
+// addDeployExplainFlag registers --explain on a deploy command.
+func addDeployExplainFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("explain", false, "With --dry-run, list every command, file write, and API call the deploy would make, per host")
+}
+
+// parseDeployExplainFlag reads --explain, which is only valid with --dry-run.
+func parseDeployExplainFlag(cmd *cobra.Command, dryRun bool) (bool, error) {
+	explain, _ := cmd.Flags().GetBool("explain")
+	if explain && !dryRun {
+		return false, fmt.Errorf("--explain requires --dry-run")
+	}
+	return explain, nil
+}
+
+// deployExplainView is the json/yaml form of a deploy transcript.
+type deployExplainView struct {
+	Environment string           `json:"environment" yaml:"environment"`
+	Version     string           `json:"version" yaml:"version"`
+	Hosts       []explainHostRun `json:"hosts" yaml:"hosts"`
+}
+
+type explainHostRun struct {
+	Host    string           `json:"host" yaml:"host"`
+	Effects []sandbox.Effect `json:"effects" yaml:"effects"`
+}
+
+// explainDeployRun holds what a sandboxed deploy needs besides the plan.
+type explainDeployRun struct {
+	cmd        *cobra.Command
+	cfg        *config.Config
+	env        string
+	version    string
+	commitSHA  string
+	configPath string
+	logger     logging.Logger
+}
+
+// explainDeploy runs every deploy phase, with its hooks and notifications,
+// against a sandbox and writes the recorded side effects to w.
+func explainDeploy(ctx context.Context, w io.Writer, format output.Format, run *explainDeployRun, plan *core.Plan) error {
+	transcript, err := sandboxDeploy(ctx, run, plan)
+	if err != nil {
+		return err
+	}
+
+	view := deployExplainView{Environment: run.env, Version: run.version, Hosts: []explainHostRun{}}
+	for _, host := range transcript.Hosts() {
+		view.Hosts = append(view.Hosts, explainHostRun{Host: host, Effects: transcript.ForHost(host)})
+	}
+
+	return output.Render(w, format, view, func(w io.Writer) error {
+		_, _ = fmt.Fprintf(w, "Deploy %s to %s would:\n\n", run.version, run.env)
+		return transcript.Render(w)
+	})
+}
+
+// sandboxDeploy executes the deploy in a sandbox and returns its transcript.
+// Phase failures that the real deploy would also hit, such as a missing
+// backend config or compose file, are returned.
+func sandboxDeploy(ctx context.Context, run *explainDeployRun, plan *core.Plan) (*sandbox.Transcript, error) {
+	transcript := sandbox.New()
+
+	workdir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting working directory: %w", err)
+	}
+	sandboxed := &core.Plan{
+		Environment: plan.Environment,
+		Operations:  plan.Operations,
+		Metadata: map[string]interface{}{
+			"version":     run.version,
+			"config_path": run.configPath,
+			"workdir":     workdir,
+		},
+	}
+
+	hookRunner := newPhaseHookRunnerWithOptions(run.cfg, auditCommandName(run.cmd), run.env, run.version, run.commitSHA, hooks.Options{
+		Runner: transcript.Runner(),
+		Logger: run.logger.Named(logging.SubsystemEngine),
+	})
+	notifier := newLifecycleNotifierWithOptions(run.cmd, run.cfg, run.env, run.version, run.commitSHA, notify.Options{
+		Logger:     run.logger,
+		HTTPClient: transcript.HTTPClient(),
+		Runner:     transcript.Runner(),
+	})
+
+	transcript.Enter(sandbox.LocalHost, "notify")
+	notifier.send(ctx, notify.EventStarted, "", nil)
+
+	fns := withPhaseHooks(sandboxPhaseFns(transcript), hookRunner)
+	for _, phase := range allPhasesCommon() {
+		fn, err := phaseFnFor(phase, fns)
+		if err != nil {
+			return nil, err
+		}
+		transcript.Enter(sandbox.LocalHost, string(phase))
+		if err := fn(ctx, sandboxed, run.logger); err != nil {
+			return nil, fmt.Errorf("explaining %s phase: %w", phase, err)
+		}
+	}
+
+	transcript.Enter(sandbox.LocalHost, "notify")
+	notifier.finish(ctx, notify.EventSucceeded, "", nil)
+
+	return transcript, nil
+}
+
+// sandboxPhaseFns mirrors the default phase functions, recording their
+// side effects in t instead of performing them. Providers that build
+// through their own tooling contribute their Plan steps.
+func sandboxPhaseFns(t *sandbox.Transcript) PhaseFns {
+	noop := func(context.Context, *core.Plan, logging.Logger) error { return nil }
+
+	return PhaseFns{
+		Build: func(ctx context.Context, plan *core.Plan, _ logging.Logger) error {
+			configPath, version, workdir, err := getDeployContext(plan)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(configPath)
+			if err != nil {
+				return fmt.Errorf("loading config: %w", err)
+			}
+			if cfg.Backend == nil {
+				return fmt.Errorf("no backend configuration found")
+			}
+
+			builtImages := make(map[string]string)
+			for _, svc := range cfg.Backend.ServiceList() {
+				provider, err := backendproviders.Get(svc.Provider)
+				if err != nil {
+					return fmt.Errorf("getting backend provider %q: %w", svc.Provider, err)
+				}
+				providerCfg, err := svc.GetProviderConfig()
+				if err != nil {
+					return fmt.Errorf("getting provider config: %w", err)
+				}
+
+				imageTag := deployServiceImageTag(cfg, svc.Name, version)
+				providerPlan, err := provider.Plan(ctx, backendproviders.PlanOptions{
+					Config:   providerCfg,
+					ImageTag: imageTag,
+					WorkDir:  backendServiceWorkDir(svc, workdir),
+				})
+				if err != nil {
+					return fmt.Errorf("planning build: %w", err)
+				}
+				for _, step := range providerPlan.Steps {
+					t.Record(sandbox.Effect{
+						Kind:        sandbox.KindProviderStep,
+						Description: fmt.Sprintf("%s %s: %s", providerPlan.Provider, step.Name, step.Description),
+					})
+				}
+				builtImages[svc.Name] = imageTag
+			}
+
+			plan.Metadata["built_image"] = primaryImage(cfg, builtImages)
+			plan.Metadata["built_images"] = builtImages
+			return nil
+		},
+		Push: func(ctx context.Context, plan *core.Plan, _ logging.Logger) error {
+			configPath, _, _, err := getDeployContext(plan)
+			if err != nil {
+				return err
+			}
+			runtime := deployRuntime(configPath)
+			image, _ := plan.Metadata["built_image"].(string)
+			names, byService := builtImagesFromPlan(plan)
+			if len(names) == 0 {
+				_, err := t.Runner().Run(ctx, pushCommand(runtime, image))
+				return err
+			}
+			for _, name := range names {
+				if _, err := t.Runner().Run(ctx, pushCommand(runtime, byService[name])); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		MigratePre: noop,
+		Rollout: func(ctx context.Context, plan *core.Plan, _ logging.Logger) error {
+			configPath, _, workdir, err := getDeployContext(plan)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(configPath)
+			if err != nil {
+				return fmt.Errorf("loading config: %w", err)
+			}
+			image, _ := plan.Metadata["built_image"].(string)
+
+			baseComposePath := filepath.Join(workdir, "docker-compose.yml")
+			if _, err := os.Stat(baseComposePath); err != nil {
+				return fmt.Errorf("docker-compose.yml not found at %s: %w", baseComposePath, err)
+			}
+			_, serviceImages := builtImagesFromPlan(plan)
+			generator := deploy.NewComposeGeneratorWithFS(t.WriteFile, t.MkdirAll).WithServiceImages(serviceImages)
+			renderedPath, _, err := generator.Generate(cfg, plan.Environment, baseComposePath, image, workdir)
+			if err != nil {
+				return fmt.Errorf("generating compose file: %w", err)
+			}
+
+			if rollout := cfg.Environments[plan.Environment].Rollout; rollout != nil && rollout.Enabled {
+				// The rendered file only exists in the sandbox, so list services from the base file.
+				return rolloutServices(ctx, deploy.NewRolloutExecutorWithRunner(t.Runner()), cfg, baseComposePath, renderedPath, nil)
+			}
+			_, err = t.Runner().Run(ctx, composeUpCommand(containerruntime.ForConfig(cfg), renderedPath))
+			return err
+		},
+		MigratePost: noop,
+		Finalize:    noop,
+	}
+}
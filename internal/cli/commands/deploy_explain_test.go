@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/sandbox"
+)
+
+// Feature: DEPLOY_EXPLAIN
+// Spec: spec/deploy/explain.md
+
+const explainConfig = `project:
+  name: test-app
+backend:
+  provider: generic
+  providers:
+    generic:
+      build:
+        dockerfile: "./Dockerfile"
+        context: "."
+environments:
+  staging:
+    driver: local
+    notifications:
+      - type: webhook
+        url: "https://hooks.example.com/secret-token"
+        events: ["deploy.succeeded"]
+hooks:
+  pre_rollout:
+    - command: ["./scripts/backup.sh"]
+  post_finalize:
+    - command: ["systemctl", "reload", "nginx"]
+      host: deploy@web-1
+`
+
+func setupExplainTestEnv(t *testing.T) *isolatedStateTestEnv {
+	t.Helper()
+	env := setupIsolatedStateTestEnv(t)
+	files := map[string]string{
+		"stagecraft.yml":     explainConfig,
+		"docker-compose.yml": "services:\n  api:\n    build: .\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(env.TempDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	return env
+}
+
+func executeDeployExplain(t *testing.T, args ...string) string {
+	t.Helper()
+	root := newTestRootCommand()
+	root.AddCommand(setupDeployCommand(noopPhaseFns()))
+	out, err := executeCommandForGolden(root, append([]string{"deploy", "--env", "staging", "--version", "v1.0.0", "--dry-run", "--explain"}, args...)...)
+	if err != nil {
+		t.Fatalf("explain deploy failed: %v", err)
+	}
+	return out
+}
+
+func TestDeployCommand_ExplainRecordsSideEffectsPerHost(t *testing.T) {
+	env := setupExplainTestEnv(t)
+
+	out := executeDeployExplain(t)
+
+	rendered := filepath.Join(env.TempDir, ".stagecraft", "rendered", "staging", "docker-compose.yml")
+	for _, want := range []string{
+		"Host local:",
+		"[build]",
+		"generic BuildImage: Would build Docker image: test-app:v1.0.0",
+		"docker push test-app:v1.0.0",
+		"./scripts/backup.sh",
+		"file_write",
+		rendered,
+		"docker compose -f " + rendered + " up -d",
+		"POST https://hooks.example.com/…",
+		"Host deploy@web-1:",
+		"'systemctl' 'reload' 'nginx'",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in transcript, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "secret-token") {
+		t.Errorf("expected webhook path to be redacted, got:\n%s", out)
+	}
+	if strings.Index(out, "./scripts/backup.sh") > strings.Index(out, "docker compose") {
+		t.Errorf("expected pre_rollout hook before compose up, got:\n%s", out)
+	}
+
+	if _, err := os.Stat(rendered); !os.IsNotExist(err) {
+		t.Errorf("expected explain to write no compose file, stat err = %v", err)
+	}
+	releases, _ := env.Manager.ListReleases(context.Background(), "staging")
+	if len(releases) != 0 {
+		t.Fatalf("expected explain to create no releases, got %d", len(releases))
+	}
+}
+
+func TestDeployCommand_ExplainJSON(t *testing.T) {
+	setupExplainTestEnv(t)
+
+	out := executeDeployExplain(t, "--output", "json")
+
+	var view deployExplainView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("decoding explain output: %v\n%s", err, out)
+	}
+	if view.Environment != "staging" || view.Version != "v1.0.0" {
+		t.Errorf("unexpected view header %+v", view)
+	}
+	if len(view.Hosts) != 2 || view.Hosts[0].Host != sandbox.LocalHost || view.Hosts[1].Host != "deploy@web-1" {
+		t.Fatalf("expected local then deploy@web-1, got %+v", view.Hosts)
+	}
+
+	kinds := map[sandbox.Kind]int{}
+	for _, e := range view.Hosts[0].Effects {
+		kinds[e.Kind]++
+	}
+	if kinds[sandbox.KindCommand] < 3 || kinds[sandbox.KindFileWrite] != 1 || kinds[sandbox.KindAPICall] != 1 || kinds[sandbox.KindProviderStep] == 0 {
+		t.Errorf("unexpected effect kinds %v", kinds)
+	}
+}
+
+func TestDeployCommand_ExplainRequiresDryRun(t *testing.T) {
+	setupExplainTestEnv(t)
+
+	err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--explain")
+	if err == nil || !strings.Contains(err.Error(), "--explain requires --dry-run") {
+		t.Fatalf("expected --explain/--dry-run error, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_HOST_STATUS
+// Spec: spec/deploy/host-status.md
+
+// planHostResultsKey is the plan metadata key under which a running phase
+// records per-host outcomes; executePhasesCommon stores them on the release.
+const planHostResultsKey = "host_results"
+
+// localTargetHost names the local Docker daemon as a rollout target.
+const localTargetHost = "local"
+
+// recordHostResult records host's outcome for the phase being executed.
+// A phase that ran past its deadline is recorded as timed out.
+func recordHostResult(ctx context.Context, plan *core.Plan, host string, started time.Time, err error) {
+	hs := state.HostPhaseStatus{
+		Status:     state.StatusCompleted,
+		DurationMS: time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		hs.Status = state.StatusFailed
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			hs.Status = state.StatusTimedOut
+		}
+		hs.Message = err.Error()
+	}
+
+	if plan.Metadata == nil {
+		plan.Metadata = make(map[string]interface{})
+	}
+	results, _ := plan.Metadata[planHostResultsKey].(map[string]state.HostPhaseStatus)
+	if results == nil {
+		results = make(map[string]state.HostPhaseStatus)
+		plan.Metadata[planHostResultsKey] = results
+	}
+	results[host] = hs
+}
+
+// storeHostResults moves the per-host outcomes the phase recorded from the
+// plan to the release. Failing to store them is logged, not fatal: the
+// phase's overall status is recorded separately.
+func storeHostResults(
+	ctx context.Context,
+	stateMgr *state.Manager,
+	releaseID string,
+	phase state.ReleasePhase,
+	plan *core.Plan,
+	logger logging.Logger,
+) {
+	if plan == nil || plan.Metadata == nil {
+		return
+	}
+	results, _ := plan.Metadata[planHostResultsKey].(map[string]state.HostPhaseStatus)
+	delete(plan.Metadata, planHostResultsKey)
+
+	hosts := make([]string, 0, len(results))
+	for host := range results {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	for _, host := range hosts {
+		if err := stateMgr.UpdateHostPhase(ctx, releaseID, phase, host, results[host]); err != nil {
+			logger.Debug("Failed to record host phase status",
+				logging.NewField("phase", string(phase)),
+				logging.NewField("host", host),
+				logging.NewField("error", err.Error()),
+			)
+		}
+	}
+}
+
+// deployTargetHost names the host deploy rolls out to: the host of
+// DOCKER_HOST, or "local" for the local Docker daemon.
+func deployTargetHost() string {
+	raw := os.Getenv("DOCKER_HOST")
+	if raw == "" {
+		return localTargetHost
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if u.Scheme == "unix" || u.Scheme == "npipe" || u.Hostname() == "" {
+		return localTargetHost
+	}
+	return u.Hostname()
+}
+
+// addDeployRetryHostFlag registers --retry-host on a deploy command.
+func addDeployRetryHostFlag(cmd *cobra.Command) {
+	cmd.Flags().String("retry-host", "", "Re-deploy the latest release's bundle to a host whose rollout failed")
+	_ = cmd.RegisterFlagCompletionFunc("retry-host", CompleteFailedHosts)
+}
+
+// resolveDeployRetryHost turns --retry-host into the release whose bundle
+// is re-deployed: the environment's latest release, which must have failed
+// to roll out to the host. Without the flag it returns bundleRef unchanged.
+func resolveDeployRetryHost(
+	ctx context.Context,
+	cmd *cobra.Command,
+	stateMgr *state.Manager,
+	env string,
+	planFlags deployPlanFlags,
+	bundleRef string,
+) (string, error) {
+	host, _ := cmd.Flags().GetString("retry-host")
+	if host == "" {
+		return bundleRef, nil
+	}
+	switch {
+	case bundleRef != "":
+		return "", fmt.Errorf("--retry-host cannot be used with --from-bundle; it re-deploys the failed release's bundle")
+	case planFlags.PlanOnly || planFlags.ApplyPlan != "":
+		return "", fmt.Errorf("--retry-host cannot be used with --plan-only or --apply-plan; the bundle records its plan")
+	case cmd.Flags().Changed("version"):
+		return "", fmt.Errorf("--version cannot be used with --retry-host; the bundle records its version")
+	}
+
+	if target := deployTargetHost(); target != host {
+		return "", fmt.Errorf("--retry-host %s: deploy rolls out to %s; point DOCKER_HOST at %s to retry it", host, target, host)
+	}
+
+	release, err := stateMgr.GetCurrentRelease(ctx, env)
+	if err != nil {
+		return "", fmt.Errorf("--retry-host %s: finding the latest %s release: %w", host, env, err)
+	}
+	hs, ok := release.HostPhases[state.PhaseRollout][host]
+	if !ok {
+		return "", fmt.Errorf("--retry-host %s: release %s did not roll out to %s", host, release.ID, host)
+	}
+	if hs.Status != state.StatusFailed && hs.Status != state.StatusTimedOut {
+		return "", fmt.Errorf("--retry-host %s: rollout to %s in release %s is %s, not failed", host, host, release.ID, hs.Status)
+	}
+	return release.ID, nil
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_HOST_STATUS
+// Spec: spec/deploy/host-status.md
+
+// hostRolloutFns returns phase functions whose rollout records an outcome
+// for the target host, failing with rolloutErr.
+func hostRolloutFns(rolloutErr error) PhaseFns {
+	fns := noopPhaseFns()
+	fns.Rollout = func(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
+		recordHostResult(ctx, plan, deployTargetHost(), time.Now(), rolloutErr)
+		return rolloutErr
+	}
+	return fns
+}
+
+func TestDeployTargetHost(t *testing.T) {
+	tests := map[string]string{
+		"":                            "local",
+		"unix:///var/run/docker.sock": "local",
+		"ssh://deploy@web-1":          "web-1",
+		"tcp://10.0.0.5:2376":         "10.0.0.5",
+	}
+	for dockerHost, want := range tests {
+		t.Setenv("DOCKER_HOST", dockerHost)
+		if got := deployTargetHost(); got != want {
+			t.Errorf("DOCKER_HOST=%q: expected %q, got %q", dockerHost, want, got)
+		}
+	}
+}
+
+func TestDeployCommand_RecordsRolloutHostStatus(t *testing.T) {
+	env := setupBundleTestEnv(t)
+	t.Setenv("DOCKER_HOST", "ssh://deploy@web-2")
+
+	err := executeDeployWithPhases(hostRolloutFns(errors.New("connection refused")), "deploy", "--env", "staging", "--version", "v1.0.0")
+	if err == nil {
+		t.Fatal("expected the deploy to fail")
+	}
+
+	release, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+	hs := release.HostPhases[state.PhaseRollout]["web-2"]
+	if hs.Status != state.StatusFailed || hs.Message != "connection refused" {
+		t.Errorf("expected a failed rollout on web-2, got %+v", hs)
+	}
+	if got := release.FailedHosts(state.PhaseRollout); len(got) != 1 || got[0] != "web-2" {
+		t.Errorf("expected failed hosts [web-2], got %v", got)
+	}
+}
+
+func TestDeployCommand_RetryHostRedeploysFailedRelease(t *testing.T) {
+	env := setupBundleTestEnv(t)
+	t.Setenv("DOCKER_HOST", "ssh://deploy@web-2")
+
+	if err := executeDeployWithPhases(hostRolloutFns(errors.New("connection refused")), "deploy", "--env", "staging", "--version", "v1.0.0"); err == nil {
+		t.Fatal("expected the deploy to fail")
+	}
+	failed, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+
+	if err := executeDeployWithPhases(hostRolloutFns(nil), "deploy", "--env", "staging", "--retry-host", "web-2"); err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+
+	retried, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+	if retried.ID == failed.ID || retried.Version != "v1.0.0" {
+		t.Errorf("expected a new release of v1.0.0, got %+v", retried)
+	}
+	if retried.Bundle == nil || failed.Bundle == nil || *retried.Bundle != *failed.Bundle {
+		t.Errorf("expected the retry to re-deploy the failed release's bundle, got %+v", retried.Bundle)
+	}
+	if hs := retried.HostPhases[state.PhaseRollout]["web-2"]; hs.Status != state.StatusCompleted {
+		t.Errorf("expected a completed rollout on web-2, got %+v", hs)
+	}
+
+	// The latest release rolled out fine, so there is nothing to retry
+	err = executeDeployWithPhases(hostRolloutFns(nil), "deploy", "--env", "staging", "--retry-host", "web-2")
+	if err == nil || !strings.Contains(err.Error(), "is completed, not failed") {
+		t.Errorf("expected a completed host to be refused, got %v", err)
+	}
+}
+
+func TestDeployCommand_RetryHostFlagConflicts(t *testing.T) {
+	setupBundleTestEnv(t)
+	t.Setenv("DOCKER_HOST", "ssh://deploy@web-2")
+
+	tests := map[string][]string{
+		"--retry-host cannot be used with --from-bundle": {"--retry-host", "web-2", "--from-bundle", "rel-1"},
+		"--retry-host cannot be used with --plan-only":   {"--retry-host", "web-2", "--plan-only"},
+		"--version cannot be used with --retry-host":     {"--retry-host", "web-2", "--version", "v2"},
+		"point DOCKER_HOST at web-1":                     {"--retry-host", "web-1"},
+		"finding the latest staging release":             {"--retry-host", "web-2"},
+	}
+	for want, flags := range tests {
+		args := append([]string{"deploy", "--env", "staging"}, flags...)
+		err := executeDeployWithPhases(noopPhaseFns(), args...)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%v: expected error containing %q, got %v", flags, want, err)
+		}
+	}
+}
+
+func TestReleasesShow_ListsHostStatus(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+
+	release, err := env.Manager.CreateRelease(env.Ctx, "prod", "v1.0.0", "commit1")
+	if err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+	for host, hs := range map[string]state.HostPhaseStatus{
+		"web-1": {Status: state.StatusCompleted, DurationMS: 1200},
+		"web-2": {Status: state.StatusFailed, Message: "connection refused", DurationMS: 3400},
+	} {
+		if err := env.Manager.UpdateHostPhase(env.Ctx, release.ID, state.PhaseRollout, host, hs); err != nil {
+			t.Fatalf("UpdateHostPhase failed: %v", err)
+		}
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewReleasesCommand())
+
+	out, err := executeCommandForGolden(root, "releases", "show", release.ID)
+	if err != nil {
+		t.Fatalf("releases show failed: %v", err)
+	}
+	for _, want := range []string{
+		"    web-1         completed  1.2s",
+		"    web-2         failed     3.4s  connection refused",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/core"
+	coreplan "stagecraft/internal/core/plan"
+	"stagecraft/internal/plansign"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_PLAN_ARTIFACT
+// Spec: spec/deploy/plan-artifact.md
+
+// deployPlanFlags holds the CI plan/apply flags of `stagecraft deploy`.
+type deployPlanFlags struct {
+	PlanOnly  bool
+	Out       string
+	ApplyPlan string
+}
+
+// addDeployPlanFlags registers the plan/apply flags on a deploy command.
+func addDeployPlanFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("plan-only", false, "Write the deployment plan to --out without deploying")
+	cmd.Flags().String("out", "plan.json", "Plan artifact path for --plan-only")
+	cmd.Flags().String("apply-plan", "", "Deploy exactly the plan in this artifact, refusing if config or git HEAD drifted")
+}
+
+// parseDeployPlanFlags reads and validates the plan/apply flags.
+func parseDeployPlanFlags(cmd *cobra.Command) (deployPlanFlags, error) {
+	planOnly, _ := cmd.Flags().GetBool("plan-only")
+	out, _ := cmd.Flags().GetString("out")
+	applyPlan, _ := cmd.Flags().GetString("apply-plan")
+
+	if planOnly && applyPlan != "" {
+		return deployPlanFlags{}, fmt.Errorf("--plan-only and --apply-plan cannot be used together")
+	}
+	if cmd.Flags().Changed("out") && !planOnly {
+		return deployPlanFlags{}, fmt.Errorf("--out requires --plan-only")
+	}
+	if applyPlan != "" && cmd.Flags().Changed("version") {
+		return deployPlanFlags{}, fmt.Errorf("--version cannot be used with --apply-plan; the plan records its version")
+	}
+
+	return deployPlanFlags{PlanOnly: planOnly, Out: out, ApplyPlan: applyPlan}, nil
+}
+
+// writeDeployPlan plans env and writes the artifact to out without
+// touching state.
+func writeDeployPlan(cmd *cobra.Command, cfg *config.Config, env, configPath, out, version, commitSHA string) error {
+	p, err := core.NewPlanner(cfg).PlanDeploy(env)
+	if err != nil {
+		return fmt.Errorf("generating deployment plan: %w", err)
+	}
+
+	configHash, err := coreplan.HashFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	artifact, err := coreplan.NewArtifact(p, version, commitSHA, configHash)
+	if err != nil {
+		return fmt.Errorf("building plan artifact: %w", err)
+	}
+
+	key, sign, err := plansign.LoadSigningKey()
+	if err != nil {
+		return fmt.Errorf("loading plan signing key: %w", err)
+	}
+	if sign {
+		artifact.Sign(key)
+	}
+
+	if err := artifact.WriteFile(out); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Wrote plan for %s (%d operations) to %s\n  digest: %s\n",
+		env, len(artifact.Operations), out, artifact.Digest)
+	if artifact.Signature != nil {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  signed: %s key %s\n", artifact.Signature.Algorithm, artifact.Signature.KeyID)
+	}
+	return nil
+}
+
+// loadDeployPlan reads the artifact at path and refuses it if it targets a
+// different environment, if config or git HEAD drifted since planning, or
+// if a verify key is configured and the artifact's signature does not
+// verify against it.
+func loadDeployPlan(ctx context.Context, path, env, configPath string, logger logging.Logger) (*coreplan.Artifact, error) {
+	artifact, err := coreplan.ReadArtifact(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, verify, err := plansign.LoadVerifyKey()
+	if err != nil {
+		return nil, fmt.Errorf("loading plan verify key: %w", err)
+	}
+	if verify {
+		if err := artifact.VerifySignature(key); err != nil {
+			return nil, fmt.Errorf("refusing to apply plan %s: %w", path, err)
+		}
+	} else if artifact.Signature != nil {
+		logger.Warn("Plan is signed but no verify key is configured; signature not checked",
+			logging.NewField("plan", path),
+			logging.NewField("key_id", artifact.Signature.KeyID),
+		)
+	}
+
+	if artifact.Environment != env {
+		return nil, fmt.Errorf("plan %s targets environment %q, not %q", path, artifact.Environment, env)
+	}
+
+	configHash, err := coreplan.HashFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := artifact.CheckDrift(configHash, getGitCommitSHA(ctx, logger)); err != nil {
+		return nil, fmt.Errorf("refusing to apply plan %s: %w", path, err)
+	}
+
+	logger.Info("Applying plan",
+		logging.NewField("plan", path),
+		logging.NewField("plan_id", artifact.PlanID),
+		logging.NewField("digest", artifact.Digest),
+	)
+	return artifact, nil
+}
+
+// appliedPlan returns the artifact's plan, or nil when not applying one.
+func appliedPlan(artifact *coreplan.Artifact) *core.Plan {
+	if artifact == nil {
+		return nil
+	}
+	return artifact.CorePlan()
+}
@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core"
+	coreplan "stagecraft/internal/core/plan"
+	"stagecraft/internal/plansign"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_PLAN_ARTIFACT
+// Spec: spec/deploy/plan-artifact.md
+
+const planArtifactConfig = `project:
+  name: test-app
+backend:
+  provider: generic
+  providers:
+    generic:
+      build:
+        dockerfile: "./Dockerfile"
+        context: "."
+environments:
+  staging:
+    driver: local
+`
+
+func setupPlanArtifactTestEnv(t *testing.T) *isolatedStateTestEnv {
+	t.Helper()
+	env := setupIsolatedStateTestEnv(t)
+	if err := os.WriteFile(filepath.Join(env.TempDir, "stagecraft.yml"), []byte(planArtifactConfig), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return env
+}
+
+func TestDeployCommand_PlanOnlyWritesArtifact(t *testing.T) {
+	env := setupPlanArtifactTestEnv(t)
+	out := filepath.Join(env.TempDir, "plan.json")
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0", "--plan-only", "--out", out); err != nil {
+		t.Fatalf("plan-only deploy failed: %v", err)
+	}
+
+	artifact, err := coreplan.ReadArtifact(out)
+	if err != nil {
+		t.Fatalf("reading artifact: %v", err)
+	}
+	if artifact.Environment != "staging" || artifact.Version != "v1.0.0" || len(artifact.Operations) == 0 {
+		t.Errorf("unexpected artifact: %+v", artifact)
+	}
+
+	releases, err := env.Manager.ListReleases(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("listing releases: %v", err)
+	}
+	if len(releases) != 0 {
+		t.Fatalf("expected plan-only to create no releases, got %d", len(releases))
+	}
+
+	// Planning again produces the same bytes.
+	first, _ := os.ReadFile(out)
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0", "--plan-only", "--out", out); err != nil {
+		t.Fatalf("second plan-only deploy failed: %v", err)
+	}
+	second, _ := os.ReadFile(out)
+	if string(first) != string(second) {
+		t.Fatalf("expected deterministic plan artifact")
+	}
+}
+
+func TestDeployCommand_ApplyPlanExecutesRecordedPlan(t *testing.T) {
+	env := setupPlanArtifactTestEnv(t)
+	out := filepath.Join(env.TempDir, "plan.json")
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0", "--plan-only", "--out", out); err != nil {
+		t.Fatalf("plan-only deploy failed: %v", err)
+	}
+
+	var executed []string
+	fns := noopPhaseFns()
+	fns.Build = func(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
+		for _, op := range plan.Operations {
+			executed = append(executed, op.ID)
+		}
+		return nil
+	}
+
+	if err := executeDeployWithPhases(fns, "deploy", "--env", "staging", "--apply-plan", out); err != nil {
+		t.Fatalf("apply-plan deploy failed: %v", err)
+	}
+
+	artifact, _ := coreplan.ReadArtifact(out)
+	if len(executed) != len(artifact.Operations) || executed[0] != artifact.Operations[0].ID {
+		t.Errorf("expected artifact operations %v, executed %v", artifact.Operations, executed)
+	}
+
+	current, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+	if current.Version != "v1.0.0" {
+		t.Errorf("expected version from plan, got %q", current.Version)
+	}
+}
+
+func TestDeployCommand_ApplyPlanRefusesConfigDrift(t *testing.T) {
+	env := setupPlanArtifactTestEnv(t)
+	out := filepath.Join(env.TempDir, "plan.json")
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--plan-only", "--out", out); err != nil {
+		t.Fatalf("plan-only deploy failed: %v", err)
+	}
+
+	changed := planArtifactConfig + "  prod:\n    driver: local\n"
+	if err := os.WriteFile(filepath.Join(env.TempDir, "stagecraft.yml"), []byte(changed), 0o600); err != nil {
+		t.Fatalf("rewriting config: %v", err)
+	}
+
+	err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--apply-plan", out)
+	if !errors.Is(err, coreplan.ErrConfigDrift) {
+		t.Fatalf("expected config drift error, got %v", err)
+	}
+
+	releases, _ := env.Manager.ListReleases(context.Background(), "staging")
+	if len(releases) != 0 {
+		t.Fatalf("expected refused apply to create no releases, got %d", len(releases))
+	}
+}
+
+func TestDeployCommand_ApplyPlanRefusesOtherEnvironment(t *testing.T) {
+	env := setupPlanArtifactTestEnv(t)
+	out := filepath.Join(env.TempDir, "plan.json")
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--plan-only", "--out", out); err != nil {
+		t.Fatalf("plan-only deploy failed: %v", err)
+	}
+
+	err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "prod", "--apply-plan", out)
+	if err == nil {
+		t.Fatalf("expected error applying staging plan to prod")
+	}
+}
+
+func TestDeployCommand_PlanFlagConflicts(t *testing.T) {
+	setupPlanArtifactTestEnv(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"plan-only with apply-plan", []string{"--plan-only", "--apply-plan", "plan.json"}, "cannot be used together"},
+		{"out without plan-only", []string{"--out", "plan.json"}, "--out requires --plan-only"},
+		{"version with apply-plan", []string{"--apply-plan", "plan.json", "--version", "v2"}, "--version cannot be used with --apply-plan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"deploy", "--env", "staging"}, tt.args...)
+			err := executeDeployWithPhases(noopPhaseFns(), args...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDeployCommand_SignedPlanVerifiedOnApply(t *testing.T) {
+	env := setupPlanArtifactTestEnv(t)
+	out := filepath.Join(env.TempDir, "plan.json")
+
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{5}, ed25519.SeedSize))
+	other := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{6}, ed25519.SeedSize))
+	t.Setenv(plansign.SigningKeyEnv, base64.StdEncoding.EncodeToString(key.Seed()))
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0", "--plan-only", "--out", out); err != nil {
+		t.Fatalf("plan-only deploy failed: %v", err)
+	}
+	artifact, err := coreplan.ReadArtifact(out)
+	if err != nil {
+		t.Fatalf("reading artifact: %v", err)
+	}
+	if artifact.Signature == nil || artifact.Signature.KeyID != plansign.KeyID(plansign.PublicKey(key)) {
+		t.Fatalf("expected artifact signed by the configured key, got %+v", artifact.Signature)
+	}
+
+	t.Setenv(plansign.VerifyKeyEnv, base64.StdEncoding.EncodeToString(plansign.PublicKey(other)))
+	err = executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--apply-plan", out)
+	if !errors.Is(err, plansign.ErrBadSignature) {
+		t.Fatalf("expected bad signature error for a different verify key, got %v", err)
+	}
+
+	t.Setenv(plansign.VerifyKeyEnv, base64.StdEncoding.EncodeToString(plansign.PublicKey(key)))
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--apply-plan", out); err != nil {
+		t.Fatalf("apply-plan deploy failed: %v", err)
+	}
+}
+
+func TestDeployCommand_ApplyPlanRefusesUnsignedWithVerifyKey(t *testing.T) {
+	env := setupPlanArtifactTestEnv(t)
+	out := filepath.Join(env.TempDir, "plan.json")
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--plan-only", "--out", out); err != nil {
+		t.Fatalf("plan-only deploy failed: %v", err)
+	}
+
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{5}, ed25519.SeedSize))
+	t.Setenv(plansign.VerifyKeyEnv, base64.StdEncoding.EncodeToString(plansign.PublicKey(key)))
+
+	err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--apply-plan", out)
+	if !errors.Is(err, plansign.ErrUnsigned) {
+		t.Fatalf("expected unsigned plan error, got %v", err)
+	}
+
+	releases, _ := env.Manager.ListReleases(context.Background(), "staging")
+	if len(releases) != 0 {
+		t.Fatalf("expected refused apply to create no releases, got %d", len(releases))
+	}
+}
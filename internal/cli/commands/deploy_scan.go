@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"stagecraft/internal/containerruntime"
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/internal/imagescan"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_IMAGE_SCAN
+// Spec: spec/deploy/image-scan.md
+
+// sbomDir is where SBOMs are written, relative to the project root; each
+// release gets its own subdirectory.
+const sbomDir = ".stagecraft/sbom"
+
+// scanTarget is one image to generate an SBOM for and scan.
+type scanTarget struct {
+	service string
+	image   string
+	// name is the SBOM file name without extension.
+	name string
+	// platform points into plan metadata for multi-platform images.
+	platform *state.PlatformImage
+}
+
+// scanBuiltImages runs the environment's post-build steps over every image
+// the build phase produced: SBOM generation first, so SBOM paths are
+// recorded even when the scan then fails the build. SBOMs are written under
+// outputDir, the project root their recorded paths are relative to.
+func scanBuiltImages(
+	ctx context.Context,
+	runner executil.Runner,
+	runtime containerruntime.Runtime,
+	build *config.BuildConfig,
+	plan *core.Plan,
+	outputDir string,
+	logger logging.Logger,
+) error {
+	if build == nil || (!build.SBOM && build.Scan == nil) {
+		return nil
+	}
+
+	targets := scanTargets(plan)
+
+	if build.SBOM {
+		releaseID, _ := plan.Metadata["release_id"].(string)
+		if releaseID == "" {
+			releaseID, _ = plan.Metadata["version"].(string)
+		}
+
+		sbomPaths := make(map[string]string)
+		for _, target := range targets {
+			rel := filepath.ToSlash(filepath.Join(sbomDir, releaseID, target.name+".spdx.json"))
+			if err := imagescan.GenerateSBOM(ctx, runner, runtime, target.image, filepath.Join(outputDir, rel)); err != nil {
+				return fmt.Errorf("generating SBOM: %w", err)
+			}
+			logger.Info("SBOM generated",
+				logging.NewField("image", target.image),
+				logging.NewField("path", rel),
+			)
+			if target.platform != nil {
+				target.platform.SBOM = rel
+			} else {
+				sbomPaths[target.service] = rel
+			}
+		}
+		plan.Metadata["sbom_paths"] = sbomPaths
+	}
+
+	if build.Scan == nil {
+		return nil
+	}
+
+	var failed []string
+	for _, target := range targets {
+		summary, err := imagescan.Scan(ctx, runner, build.Scan.Scanner, runtime, target.image)
+		if err != nil {
+			return fmt.Errorf("scanning image: %w", err)
+		}
+		logger.Info("Image scanned",
+			logging.NewField("image", target.image),
+			logging.NewField("scanner", build.Scan.Scanner),
+			logging.NewField("vulnerabilities", summary.String()),
+		)
+
+		if build.Scan.FailOn == "" {
+			continue
+		}
+		if n := summary.AtOrAbove(imagescan.Severity(build.Scan.FailOn)); n > 0 {
+			failed = append(failed, fmt.Sprintf("%s: %d at or above %s (%s)", target.image, n, build.Scan.FailOn, summary))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("vulnerability scan failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// scanTargets lists the built images in service order, expanding
+// multi-platform builds to their per-platform images.
+func scanTargets(plan *core.Plan) []scanTarget {
+	names, byService := builtImagesFromPlan(plan)
+	platformImages, _ := plan.Metadata["platform_images"].(map[string][]state.PlatformImage)
+
+	var targets []scanTarget
+	for _, name := range names {
+		images := platformImages[name]
+		if len(images) == 0 {
+			targets = append(targets, scanTarget{service: name, image: byService[name], name: name})
+			continue
+		}
+		for i := range images {
+			targets = append(targets, scanTarget{
+				service:  name,
+				image:    images[i].Tag,
+				name:     name + "-" + strings.ReplaceAll(images[i].Platform, "/", "-"),
+				platform: &images[i],
+			})
+		}
+	}
+	return targets
+}
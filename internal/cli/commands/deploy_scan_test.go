@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/containerruntime"
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_IMAGE_SCAN
+// Spec: spec/deploy/image-scan.md
+
+const grypeHighReport = `{"matches": [{"vulnerability": {"severity": "High"}}, {"vulnerability": {"severity": "Low"}}]}`
+
+func TestScanBuiltImages_SBOMThenFailOnSeverity(t *testing.T) {
+	workdir := t.TempDir()
+	runner := &scriptedRunner{outputs: map[string]string{
+		"syft docker:shop-api:v1 -o spdx-json -q":    `{"spdxVersion": "SPDX-2.3"}`,
+		"syft docker:shop-worker:v1 -o spdx-json -q": `{"spdxVersion": "SPDX-2.3"}`,
+		"grype docker:shop-api:v1 -o json -q":        grypeHighReport,
+		"grype docker:shop-worker:v1 -o json -q":     `{"matches": []}`,
+	}}
+	plan := &core.Plan{Metadata: map[string]interface{}{
+		"release_id":   "rel-1",
+		"built_images": map[string]string{"api": "shop-api:v1", "worker": "shop-worker:v1"},
+	}}
+	build := &config.BuildConfig{SBOM: true, Scan: &config.ScanConfig{Scanner: config.ScannerGrype, FailOn: "high"}}
+
+	err := scanBuiltImages(context.Background(), runner, containerruntime.Docker, build, plan, workdir, logging.NewLogger(false))
+	if err == nil || !strings.Contains(err.Error(), "shop-api:v1: 1 at or above high (high=1 low=1)") {
+		t.Fatalf("expected scan failure for shop-api, got %v", err)
+	}
+	if strings.Contains(err.Error(), "shop-worker") {
+		t.Errorf("expected clean image not to be reported, got %v", err)
+	}
+
+	paths, _ := plan.Metadata["sbom_paths"].(map[string]string)
+	if paths["api"] != ".stagecraft/sbom/rel-1/api.spdx.json" {
+		t.Fatalf("expected SBOM path to be recorded before the scan, got %v", paths)
+	}
+	if _, err := os.Stat(filepath.Join(workdir, paths["worker"])); err != nil {
+		t.Errorf("expected worker SBOM to be written: %v", err)
+	}
+}
+
+func TestScanBuiltImages_ReportOnlyAndMultiPlatform(t *testing.T) {
+	runner := &scriptedRunner{outputs: map[string]string{
+		"syft docker:app:v1-linux-amd64 -o spdx-json -q":                          `{}`,
+		"syft docker:app:v1-linux-arm64 -o spdx-json -q":                          `{}`,
+		"trivy image --image-src docker --format json --quiet app:v1-linux-amd64": `{"Results": [{"Vulnerabilities": [{"Severity": "CRITICAL"}]}]}`,
+		"trivy image --image-src docker --format json --quiet app:v1-linux-arm64": `{"Results": []}`,
+	}}
+	platformImages := map[string][]state.PlatformImage{"backend": {
+		{Platform: "linux/amd64", Tag: "app:v1-linux-amd64"},
+		{Platform: "linux/arm64", Tag: "app:v1-linux-arm64"},
+	}}
+	plan := &core.Plan{Metadata: map[string]interface{}{
+		"release_id":      "rel-2",
+		"built_images":    map[string]string{"backend": "app:v1"},
+		"platform_images": platformImages,
+	}}
+	build := &config.BuildConfig{SBOM: true, Scan: &config.ScanConfig{Scanner: config.ScannerTrivy}}
+
+	if err := scanBuiltImages(context.Background(), runner, containerruntime.Docker, build, plan, t.TempDir(), logging.NewLogger(false)); err != nil {
+		t.Fatalf("expected report-only scan to pass, got %v", err)
+	}
+	if got := platformImages["backend"][1].SBOM; got != ".stagecraft/sbom/rel-2/backend-linux-arm64.spdx.json" {
+		t.Errorf("expected per-platform SBOM path, got %q", got)
+	}
+}
+
+func TestScanBuiltImages_DisabledRunsNothing(t *testing.T) {
+	plan := &core.Plan{Metadata: map[string]interface{}{
+		"built_images": map[string]string{"api": "shop-api:v1"},
+	}}
+	if err := scanBuiltImages(context.Background(), &scriptedRunner{}, containerruntime.Docker, &config.BuildConfig{}, plan, t.TempDir(), logging.NewLogger(false)); err != nil {
+		t.Fatalf("expected no-op without sbom or scan, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/internal/imagesign"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_IMAGE_SIGNING
+// Spec: spec/deploy/image-signing.md
+
+// signPushedImages signs every image the push phase published, by digest,
+// when the environment configures a signing key.
+func signPushedImages(ctx context.Context, runner executil.Runner, cfg *config.Config, plan *core.Plan, workdir string, logger logging.Logger) error {
+	signing := cfg.Environments[plan.Environment].Signing
+	if signing == nil || signing.Key == "" {
+		return nil
+	}
+
+	keyEnv, err := imagesign.KeyEnv(signing.Key, signingLookup(ctx, cfg, plan.Environment, workdir))
+	if err != nil {
+		return fmt.Errorf("signing images: %w", err)
+	}
+
+	names, byService := builtImagesFromPlan(plan)
+	digests, _ := plan.Metadata["image_digests"].(map[string]string)
+	platformImages, _ := plan.Metadata["platform_images"].(map[string][]state.PlatformImage)
+	for _, name := range names {
+		digest := digests[name]
+		if digest == "" {
+			return fmt.Errorf("signing %s: the push did not report a digest", byService[name])
+		}
+
+		ref := imagesign.DigestRef(byService[name], digest)
+		if err := imagesign.Sign(ctx, runner, signing.Key, ref, len(platformImages[name]) > 0, keyEnv); err != nil {
+			return fmt.Errorf("signing images: %w", err)
+		}
+		logger.Info("Image signed", logging.NewField("image", ref))
+	}
+	return nil
+}
+
+// verifySignedImages checks the signature of every image about to be
+// rolled out when the environment configures a public key, and returns the
+// service images pinned to their verified digests, so hosts pull exactly
+// the images that were verified. primary is pinned the same way.
+func verifySignedImages(
+	ctx context.Context,
+	runner executil.Runner,
+	cfg *config.Config,
+	plan *core.Plan,
+	workdir string,
+	serviceImages map[string]string,
+	primary string,
+	logger logging.Logger,
+) (map[string]string, string, error) {
+	signing := cfg.Environments[plan.Environment].Signing
+	if signing == nil || signing.PublicKey == "" {
+		return serviceImages, primary, nil
+	}
+	if len(serviceImages) == 0 {
+		return nil, "", fmt.Errorf("verifying signatures: no built images in plan metadata")
+	}
+
+	keyEnv, err := imagesign.KeyEnv(signing.PublicKey, signingLookup(ctx, cfg, plan.Environment, workdir))
+	if err != nil {
+		return nil, "", fmt.Errorf("verifying signatures: %w", err)
+	}
+
+	names, _ := builtImagesFromPlan(plan)
+	digests, _ := plan.Metadata["image_digests"].(map[string]string)
+	pinned := make(map[string]string, len(serviceImages))
+	pinnedPrimary := primary
+	for _, name := range names {
+		image := serviceImages[name]
+		digest := digests[name]
+		if digest == "" {
+			return nil, "", fmt.Errorf("verifying %s: no pushed digest to verify", image)
+		}
+
+		ref := imagesign.DigestRef(image, digest)
+		if err := imagesign.Verify(ctx, runner, signing.PublicKey, ref, keyEnv); err != nil {
+			return nil, "", fmt.Errorf("image signature verification failed: %w", err)
+		}
+		logger.Info("Image signature verified", logging.NewField("image", ref))
+
+		pinned[name] = ref
+		if image == primary {
+			pinnedPrimary = ref
+		}
+	}
+	return pinned, pinnedPrimary, nil
+}
+
+// signingLookup resolves key variables from the shell, then from the
+// environment's env_file (decrypting age/SOPS files).
+func signingLookup(ctx context.Context, cfg *config.Config, env, workdir string) func(string) (string, bool) {
+	var fileVars map[string]string
+	loaded := false
+
+	return func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+		if !loaded {
+			loaded = true
+			if path := cfg.Environments[env].EnvFile; path != "" {
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(workdir, path)
+				}
+				fileVars, _ = readOptionalEnvFile(ctx, path)
+			}
+		}
+		value, ok := fileVars[name]
+		return value, ok
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_IMAGE_SIGNING
+// Spec: spec/deploy/image-signing.md
+
+func signingTestPlan() *core.Plan {
+	return &core.Plan{
+		Environment: "prod",
+		Metadata: map[string]interface{}{
+			"built_images":  map[string]string{"api": "ghcr.io/org/shop-api:v1", "worker": "ghcr.io/org/shop-worker:v1"},
+			"image_digests": map[string]string{"api": "sha256:aa", "worker": "sha256:bb"},
+		},
+	}
+}
+
+func signingTestConfig(signing *config.SigningConfig, envFile string) *config.Config {
+	return &config.Config{Environments: map[string]config.EnvironmentConfig{
+		"prod": {Driver: "digitalocean", EnvFile: envFile, Signing: signing},
+	}}
+}
+
+func TestSignPushedImages_SignsByDigestWithKeyFromEnvFile(t *testing.T) {
+	workdir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workdir, ".env.prod"), []byte("STAGECRAFT_TEST_COSIGN_KEY=secret-key\n"), 0o600); err != nil {
+		t.Fatalf("writing env file: %v", err)
+	}
+
+	runner := &scriptedRunner{outputs: map[string]string{
+		"cosign sign --yes --key env://STAGECRAFT_TEST_COSIGN_KEY ghcr.io/org/shop-api@sha256:aa":    "",
+		"cosign sign --yes --key env://STAGECRAFT_TEST_COSIGN_KEY ghcr.io/org/shop-worker@sha256:bb": "",
+	}}
+	cfg := signingTestConfig(&config.SigningConfig{Key: "env://STAGECRAFT_TEST_COSIGN_KEY"}, ".env.prod")
+
+	if err := signPushedImages(context.Background(), runner, cfg, signingTestPlan(), workdir, logging.NewLogger(false)); err != nil {
+		t.Fatalf("signPushedImages failed: %v", err)
+	}
+}
+
+func TestSignPushedImages_MissingKeyVariable(t *testing.T) {
+	cfg := signingTestConfig(&config.SigningConfig{Key: "env://STAGECRAFT_TEST_UNSET_KEY"}, "")
+
+	err := signPushedImages(context.Background(), &scriptedRunner{}, cfg, signingTestPlan(), t.TempDir(), logging.NewLogger(false))
+	if err == nil || !strings.Contains(err.Error(), "STAGECRAFT_TEST_UNSET_KEY is not set") {
+		t.Fatalf("expected missing key variable error, got %v", err)
+	}
+}
+
+func TestVerifySignedImages_PinsVerifiedDigests(t *testing.T) {
+	runner := &scriptedRunner{outputs: map[string]string{
+		"cosign verify --key cosign.pub ghcr.io/org/shop-api@sha256:aa":    "[]",
+		"cosign verify --key cosign.pub ghcr.io/org/shop-worker@sha256:bb": "[]",
+	}}
+	plan := signingTestPlan()
+	_, images := builtImagesFromPlan(plan)
+	cfg := signingTestConfig(&config.SigningConfig{PublicKey: "cosign.pub"}, "")
+
+	pinned, primary, err := verifySignedImages(context.Background(), runner, cfg, plan, t.TempDir(), images, images["api"], logging.NewLogger(false))
+	if err != nil {
+		t.Fatalf("verifySignedImages failed: %v", err)
+	}
+	if pinned["worker"] != "ghcr.io/org/shop-worker@sha256:bb" || primary != "ghcr.io/org/shop-api@sha256:aa" {
+		t.Errorf("expected digest-pinned images, got %v (primary %q)", pinned, primary)
+	}
+}
+
+func TestVerifySignedImages_UnsignedImageFails(t *testing.T) {
+	runner := &scriptedRunner{outputs: map[string]string{
+		"cosign verify --key cosign.pub ghcr.io/org/shop-api@sha256:aa": "[]",
+	}}
+	plan := signingTestPlan()
+	_, images := builtImagesFromPlan(plan)
+	cfg := signingTestConfig(&config.SigningConfig{PublicKey: "cosign.pub"}, "")
+
+	_, _, err := verifySignedImages(context.Background(), runner, cfg, plan, t.TempDir(), images, images["api"], logging.NewLogger(false))
+	if err == nil || !strings.Contains(err.Error(), "image signature verification failed: cosign verify ghcr.io/org/shop-worker@sha256:bb") {
+		t.Fatalf("expected verification failure for worker, got %v", err)
+	}
+}
+
+func TestVerifySignedImages_WithoutPublicKeyIsNoop(t *testing.T) {
+	images := map[string]string{"api": "shop-api:v1"}
+	pinned, primary, err := verifySignedImages(context.Background(), &scriptedRunner{}, signingTestConfig(nil, ""), signingTestPlan(), t.TempDir(), images, "shop-api:v1", logging.NewLogger(false))
+	if err != nil || pinned["api"] != "shop-api:v1" || primary != "shop-api:v1" {
+		t.Fatalf("expected images unchanged, got %v %q %v", pinned, primary, err)
+	}
+}
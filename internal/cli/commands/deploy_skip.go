@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"os"
+
+	"stagecraft/internal/compose"
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_ROLLOUT
+// Spec: spec/deploy/rollout.md
+
+// addComposeHashes records each service's compose fragment hash (see
+// compose.ServiceHashes) in the inputs of the plan's deploy step, so plan
+// diffs show which services' configuration changed.
+func addComposeHashes(plan *core.Plan, rendered []byte, logger logging.Logger) {
+	hashes, err := compose.ServiceHashes(rendered)
+	if err != nil {
+		logger.Debug("Failed to hash compose services",
+			logging.NewField("error", err.Error()),
+		)
+		return
+	}
+	for i := range plan.Operations {
+		op := &plan.Operations[i]
+		if op.Type != core.OpTypeDeploy {
+			continue
+		}
+		if op.Metadata == nil {
+			op.Metadata = make(map[string]interface{})
+		}
+		op.Metadata["compose_hashes"] = hashes
+	}
+}
+
+// previousServiceRollouts returns the services recorded by the newest
+// release of env, other than releaseID, whose rollout completed. Nil when
+// there is none or it recorded no services.
+func previousServiceRollouts(ctx context.Context, stateMgr *state.Manager, env, releaseID string) []state.ServiceRollout {
+	releases, err := stateMgr.ListReleases(ctx, env)
+	if err != nil {
+		return nil
+	}
+	for _, r := range releases {
+		if r.ID != releaseID && r.Phases[state.PhaseRollout] == state.StatusCompleted {
+			return r.Services
+		}
+	}
+	return nil
+}
+
+// selectRolloutServices records how each service of the rendered compose
+// file is rolled out in the plan's "service_rollouts" metadata and
+// returns the services to restart. Nil means all of them; it is always
+// nil unless rollout.skip_unchanged is set.
+func selectRolloutServices(cfg *config.Config, plan *core.Plan, renderedPath string, logger logging.Logger) []string {
+	records, err := serviceRollouts(plan, renderedPath)
+	if err != nil {
+		logger.Warn("Failed to hash compose services; rolling out all services",
+			logging.NewField("error", err.Error()),
+		)
+		return nil
+	}
+	plan.Metadata["service_rollouts"] = records
+
+	rollout := cfg.Environments[plan.Environment].Rollout
+	if rollout == nil || !rollout.SkipUnchanged {
+		return nil
+	}
+
+	previous, _ := plan.Metadata["previous_services"].([]state.ServiceRollout)
+	changed := markUnchangedServices(records, previous)
+	for _, r := range records {
+		if r.Skipped {
+			logger.Info("Skipping unchanged service",
+				logging.NewField("service", r.Service),
+				logging.NewField("config_hash", r.ConfigHash),
+			)
+		}
+	}
+	if len(changed) == len(records) {
+		return nil
+	}
+	return changed
+}
+
+// serviceRollouts returns a record per service of the rendered compose
+// file, sorted by service. Built services are identified by the digest or
+// image ID the build and push phases reported, other services by their
+// image reference.
+func serviceRollouts(plan *core.Plan, renderedPath string) ([]state.ServiceRollout, error) {
+	// #nosec G304 -- path is the compose file rollout just generated
+	data, err := os.ReadFile(renderedPath)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := compose.ServiceHashes(data)
+	if err != nil {
+		return nil, err
+	}
+	composeFile, err := compose.NewLoader().Load(renderedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	digests, _ := plan.Metadata["image_digests"].(map[string]string)
+	ids, _ := plan.Metadata["image_ids"].(map[string]string)
+
+	var records []state.ServiceRollout
+	for _, name := range composeFile.GetServices() {
+		image, _ := composeFile.GetServiceData(name)["image"].(string)
+		switch {
+		case digests[name] != "":
+			image = digests[name]
+		case ids[name] != "":
+			image = ids[name]
+		}
+		records = append(records, state.ServiceRollout{Service: name, ConfigHash: hashes[name], Image: image})
+	}
+	return records, nil
+}
+
+// markUnchangedServices marks the records whose config hash and image
+// equal the previous rollout's as skipped, and returns the other services.
+// The result is non-nil even when every service is unchanged.
+func markUnchangedServices(records, previous []state.ServiceRollout) []string {
+	before := make(map[string]state.ServiceRollout, len(previous))
+	for _, p := range previous {
+		before[p.Service] = p
+	}
+
+	changed := []string{}
+	for i := range records {
+		r := &records[i]
+		p, ok := before[r.Service]
+		if ok && r.Image != "" && p.ConfigHash == r.ConfigHash && p.Image == r.Image {
+			r.Skipped = true
+			continue
+		}
+		changed = append(changed, r.Service)
+	}
+	return changed
+}
+
+// recordServiceRollouts stores the service records of the rollout phase.
+// Failures are logged, not fatal.
+func recordServiceRollouts(ctx context.Context, stateMgr *state.Manager, releaseID string, plan *core.Plan, logger logging.Logger) {
+	records, _ := plan.Metadata["service_rollouts"].([]state.ServiceRollout)
+	if len(records) == 0 {
+		return
+	}
+	if err := stateMgr.RecordServices(ctx, releaseID, records); err != nil {
+		logger.Warn("Failed to record rolled-out services",
+			logging.NewField("release_id", releaseID),
+			logging.NewField("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/containerruntime"
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_ROLLOUT
+// Spec: spec/deploy/rollout.md
+
+func writeRenderedCompose(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing compose file: %v", err)
+	}
+	return path
+}
+
+func TestSelectRolloutServices(t *testing.T) {
+	renderedPath := writeRenderedCompose(t, `services:
+  api:
+    image: app-api:v2
+  db:
+    image: postgres:16
+  web:
+    image: app-web:v2
+`)
+	plan := &core.Plan{
+		Environment: "prod",
+		Metadata: map[string]interface{}{
+			"image_digests": map[string]string{"api": "sha256:aa", "web": "sha256:new"},
+		},
+	}
+	records, err := serviceRollouts(plan, renderedPath)
+	if err != nil {
+		t.Fatalf("serviceRollouts() error = %v", err)
+	}
+	// The previous rollout ran the same api and db, and an older web image.
+	previous := append([]state.ServiceRollout(nil), records...)
+	previous[2].Image = "sha256:old"
+	plan.Metadata["previous_services"] = previous
+
+	cfg := &config.Config{Environments: map[string]config.EnvironmentConfig{
+		"prod": {Rollout: &config.RolloutConfig{SkipUnchanged: true}},
+	}}
+	got := selectRolloutServices(cfg, plan, renderedPath, logging.NewLogger(false))
+	if !reflect.DeepEqual(got, []string{"web"}) {
+		t.Errorf("selectRolloutServices() = %v, want [web]", got)
+	}
+
+	recorded, _ := plan.Metadata["service_rollouts"].([]state.ServiceRollout)
+	if len(recorded) != 3 || !recorded[0].Skipped || !recorded[1].Skipped || recorded[2].Skipped {
+		t.Errorf("service_rollouts = %+v, want api and db skipped", recorded)
+	}
+	if recorded[0].Image != "sha256:aa" || recorded[1].Image != "postgres:16" {
+		t.Errorf("service_rollouts images = %q, %q, want digest and image reference", recorded[0].Image, recorded[1].Image)
+	}
+
+	cfg.Environments["prod"] = config.EnvironmentConfig{}
+	if got := selectRolloutServices(cfg, plan, renderedPath, logging.NewLogger(false)); got != nil {
+		t.Errorf("selectRolloutServices() without skip_unchanged = %v, want nil", got)
+	}
+}
+
+func TestMarkUnchangedServices_AllUnchanged(t *testing.T) {
+	records := []state.ServiceRollout{{Service: "api", ConfigHash: "h", Image: "sha256:aa"}}
+	previous := []state.ServiceRollout{{Service: "api", ConfigHash: "h", Image: "sha256:aa"}}
+
+	changed := markUnchangedServices(records, previous)
+	if changed == nil || len(changed) != 0 || !records[0].Skipped {
+		t.Errorf("markUnchangedServices() = %#v, records %+v, want empty and api skipped", changed, records)
+	}
+
+	if got := markUnchangedServices([]state.ServiceRollout{{Service: "api", ConfigHash: "h"}}, previous); len(got) != 1 {
+		t.Errorf("a service without an image identity must roll out, got %v", got)
+	}
+}
+
+func TestRolloutCompose_NothingChanged(t *testing.T) {
+	cfg := &config.Config{ContainerRuntime: string(containerruntime.Docker)}
+
+	origRunner := newRunner
+	newRunner = func() executil.Runner { return failingRunner{t} }
+	t.Cleanup(func() { newRunner = origRunner })
+
+	if err := rolloutCompose(context.Background(), cfg, "prod", "compose.yml", "hash", []string{}, logging.NewLogger(false)); err != nil {
+		t.Fatalf("rolloutCompose() error = %v", err)
+	}
+
+	cmd := composeUpCommand(containerruntime.Docker, "compose.yml", "web")
+	if got := strings.Join(cmd.Args, " "); !strings.HasSuffix(got, "-f compose.yml up -d web") {
+		t.Errorf("composeUpCommand() args = %q, want to end with -f compose.yml up -d web", got)
+	}
+}
+
+// failingRunner fails the test when any command runs.
+type failingRunner struct{ t *testing.T }
+
+func (r failingRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	r.t.Errorf("unexpected command: %s %v", cmd.Name, cmd.Args)
+	return &executil.Result{}, nil
+}
+
+func (r failingRunner) RunStream(_ context.Context, cmd executil.Command, _ io.Writer) error { //nolint:gocritic // Runner interface
+	r.t.Errorf("unexpected command: %s %v", cmd.Name, cmd.Args)
+	return nil
+}
@@ -23,9 +23,13 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"stagecraft/internal/core"
 	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
 	"stagecraft/pkg/logging"
 )
 
@@ -418,3 +422,99 @@ func TestMarkAllPhasesFailed_SetsAllPhasesToFailed(t *testing.T) {
 		}
 	}
 }
+
+// Feature: CORE_TRACING
+// Spec: spec/core/tracing.md
+func TestDeployCommand_EmitsPhaseSpans(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	configPath := filepath.Join(env.TempDir, "stagecraft.yml")
+
+	configContent := `project:
+  name: test-app
+backend:
+  provider: generic
+  providers:
+    generic:
+      build:
+        dockerfile: "./Dockerfile"
+        context: "."
+environments:
+  staging:
+    driver: local
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	noop := func(ctx context.Context, plan *core.Plan, logger logging.Logger) error { return nil }
+	fns := PhaseFns{Build: noop, Push: noop, MigratePre: noop, Rollout: noop, MigratePost: noop, Finalize: noop}
+
+	if err := executeDeployWithPhases(fns, "deploy", "--env", "staging"); err != nil {
+		t.Fatalf("deploy failed: %v", err)
+	}
+
+	var names []string
+	var root sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+		if span.Name() == "deploy" {
+			root = span
+		}
+	}
+
+	want := []string{"plan", "phase build", "phase push", "phase migrate_pre", "phase rollout", "phase migrate_post", "phase finalize", "deploy"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected spans:\n got: %v\nwant: %v", names, want)
+	}
+
+	for _, span := range recorder.Ended() {
+		if span != root && span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("expected span %q to be a child of deploy", span.Name())
+		}
+	}
+}
+
+func TestDeployServiceImageTag(t *testing.T) {
+	single := &config.Config{
+		Project: config.ProjectConfig{Name: "shop"},
+		Backend: &config.BackendConfig{Provider: "generic"},
+	}
+	if got := deployServiceImageTag(single, config.DefaultBackendService, "v1"); got != "shop:v1" {
+		t.Errorf("single-service tag = %q, want shop:v1", got)
+	}
+
+	multi := &config.Config{
+		Project: config.ProjectConfig{Name: "shop"},
+		Backend: &config.BackendConfig{Services: map[string]config.BackendServiceConfig{
+			"api": {Provider: "generic", WorkDir: "services/api"},
+		}},
+	}
+	if got := deployServiceImageTag(multi, "api", "v1"); got != "shop-api:v1" {
+		t.Errorf("multi-service tag = %q, want shop-api:v1", got)
+	}
+
+	svc := multi.Backend.ServiceList()[0]
+	if got := backendServiceWorkDir(svc, "/repo"); got != filepath.Join("/repo", "services/api") {
+		t.Errorf("backendServiceWorkDir() = %q", got)
+	}
+}
+
+func TestBuiltImagesFromPlan_SortedByService(t *testing.T) {
+	plan := &core.Plan{Metadata: map[string]interface{}{
+		"built_images": map[string]string{"worker": "w:v1", "api": "a:v1"},
+	}}
+
+	names, images := builtImagesFromPlan(plan)
+	if strings.Join(names, ",") != "api,worker" || images["worker"] != "w:v1" {
+		t.Fatalf("builtImagesFromPlan() = %v, %v", names, images)
+	}
+
+	if names, _ := builtImagesFromPlan(&core.Plan{Metadata: map[string]interface{}{}}); names != nil {
+		t.Errorf("expected no images for legacy plan, got %v", names)
+	}
+}
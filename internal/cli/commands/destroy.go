@@ -0,0 +1,317 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/prompt"
+	"stagecraft/internal/containerruntime"
+	"stagecraft/internal/core/state"
+	"stagecraft/internal/deploy"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+	"stagecraft/pkg/providers/cloud"
+	"stagecraft/pkg/providers/network"
+)
+
+// Feature: CLI_DESTROY
+// Spec: spec/commands/destroy.md
+
+// destroyPlan is everything `stagecraft destroy` tears down for one
+// environment.
+type destroyPlan struct {
+	Env string
+
+	// ComposeFile is the deploy compose file to stop; empty when deploy
+	// never wrote one.
+	ComposeFile string
+
+	// Hosts are the environment's provisioned hosts; nil without a cloud
+	// provider.
+	Hosts []cloud.Host
+
+	// Leaver removes hosts from the mesh network; nil when the network
+	// provider cannot, or none is configured.
+	Leaver network.Leaver
+
+	// Addresses are the public and reserved IPs DNS records may point at.
+	Addresses []string
+
+	// LeftBehind are the reserved IPs and volumes destroy does not release
+	// or delete, described for the operator (e.g., "volume data on app-1").
+	LeftBehind []string
+
+	// Releases is the number of releases recorded for the environment.
+	Releases int
+
+	project       string
+	cloudProvider cloud.CloudProvider
+	cloudConfig   any
+	networkConfig any
+	runtime       containerruntime.Runtime
+}
+
+// NewDestroyCommand returns the `stagecraft destroy` command.
+func NewDestroyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "destroy",
+		Short: "Tear down an environment",
+		Long: `Tear down an environment: stop its compose stack, remove its hosts from the
+mesh network, delete its hosts through the cloud provider, and archive its
+releases and infrastructure records out of the state file.
+
+The plan is printed first. With --dry-run nothing else happens; otherwise
+you must type the environment name and then "destroy" to proceed, or pass
+--yes.`,
+		Args: cobra.NoArgs,
+		RunE: runDestroy,
+	}
+
+	addYesFlag(cmd)
+
+	return needsNetwork(cmd)
+}
+
+func runDestroy(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("destroy: resolving flags: %w", err)
+	}
+
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			return fmt.Errorf("destroy: stagecraft config not found at %s", flags.Config)
+		}
+		return fmt.Errorf("destroy: failed to load config: %w", err)
+	}
+
+	flags, err = ResolveFlags(cmd, cfg)
+	if err != nil {
+		return fmt.Errorf("destroy: resolving flags: %w", err)
+	}
+	if !cmd.Flags().Changed("env") {
+		return fmt.Errorf("destroy: --env is required")
+	}
+	if envCfg := cfg.Environments[flags.Env]; envCfg.Driver == "local" {
+		return fmt.Errorf("destroy: %q is a local environment; stop it with dev instead", flags.Env)
+	}
+
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
+
+	stateMgr := state.NewDefaultManager()
+	plan, err := planDestroy(ctx, cfg, flags.Env, stateMgr)
+	if err != nil {
+		return fmt.Errorf("destroy: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	plan.render(out)
+
+	if flags.DryRun {
+		_, _ = fmt.Fprintln(out, "Dry run: nothing was changed.")
+		return nil
+	}
+
+	if err := confirmDestroy(newConfirmer(cmd, false), flags.Env); err != nil {
+		return fmt.Errorf("destroy: %w; nothing was changed", err)
+	}
+
+	err = plan.execute(ctx, out, stateMgr, logger)
+	recordAudit(ctx, cmd, logger, flags.Env, "", err)
+	if err != nil {
+		return fmt.Errorf("destroy: %w", err)
+	}
+	return nil
+}
+
+// planDestroy collects what destroying env removes. It changes nothing.
+func planDestroy(ctx context.Context, cfg *config.Config, env string, stateMgr *state.Manager) (*destroyPlan, error) {
+	plan := &destroyPlan{Env: env, project: cfg.Project.Name, runtime: containerruntime.ForConfig(cfg)}
+
+	workdir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting working directory: %w", err)
+	}
+	composePath := deploy.RenderedComposePath(workdir, env)
+	if _, err := os.Stat(composePath); err == nil {
+		plan.ComposeFile = composePath
+	}
+
+	if cfg.Cloud != nil && cfg.Cloud.Provider != "" {
+		provider, err := cloud.Get(cfg.Cloud.Provider)
+		if err != nil {
+			return nil, fmt.Errorf("cloud provider %q not found: %w", cfg.Cloud.Provider, err)
+		}
+		plan.cloudProvider = provider
+		plan.cloudConfig = cfg.Cloud.Providers[cfg.Cloud.Provider]
+
+		plan.Hosts, err = provider.Hosts(ctx, cloud.HostsOptions{Config: plan.cloudConfig, Environment: env, Project: plan.project})
+		if err != nil {
+			return nil, fmt.Errorf("listing hosts: %w", err)
+		}
+	}
+
+	if cfg.Network != nil && cfg.Network.Provider != "" && len(plan.Hosts) > 0 {
+		provider, err := network.Get(cfg.Network.Provider)
+		if err != nil {
+			return nil, fmt.Errorf("network provider %q not found: %w", cfg.Network.Provider, err)
+		}
+		if leaver, ok := provider.(network.Leaver); ok {
+			plan.Leaver = leaver
+			plan.networkConfig = cfg.Network.Providers[cfg.Network.Provider]
+		}
+	}
+
+	reserved, err := stateMgr.ReservedIPs(ctx, env)
+	if err != nil {
+		return nil, fmt.Errorf("reading reserved IPs from state: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, h := range plan.Hosts {
+		if h.PublicIP != "" && !seen[h.PublicIP] {
+			seen[h.PublicIP] = true
+			plan.Addresses = append(plan.Addresses, h.PublicIP)
+		}
+	}
+	for _, h := range plan.Hosts {
+		for _, v := range h.Volumes {
+			plan.LeftBehind = append(plan.LeftBehind, fmt.Sprintf("volume %s on %s", v.Name, h.Name))
+		}
+	}
+	for _, ip := range reserved {
+		plan.LeftBehind = append(plan.LeftBehind, "reserved IP "+ip.Address)
+		if !seen[ip.Address] {
+			seen[ip.Address] = true
+			plan.Addresses = append(plan.Addresses, ip.Address)
+		}
+	}
+
+	releases, err := stateMgr.ListReleases(ctx, env)
+	if err != nil {
+		return nil, fmt.Errorf("listing releases: %w", err)
+	}
+	plan.Releases = len(releases)
+
+	return plan, nil
+}
+
+// render writes the plan, one line per teardown step, in execution order.
+func (p *destroyPlan) render(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "Destroy plan for environment %q:\n", p.Env)
+	if p.ComposeFile != "" {
+		_, _ = fmt.Fprintf(w, "  - stop compose stack %s\n", displayPath(p.ComposeFile))
+	}
+	if p.Leaver != nil {
+		for _, h := range p.Hosts {
+			_, _ = fmt.Fprintf(w, "  - remove %s from the %s network\n", h.Name, p.Leaver.ID())
+		}
+	}
+	for _, h := range p.Hosts {
+		_, _ = fmt.Fprintf(w, "  - delete host %s (%s %s, %s)\n", h.Name, p.cloudProvider.ID(), h.ID, orNone(h.PublicIP))
+	}
+	for _, addr := range p.Addresses {
+		_, _ = fmt.Fprintf(w, "  - remove DNS records pointing at %s (manual: Stagecraft does not manage DNS)\n", addr)
+	}
+	for _, item := range p.LeftBehind {
+		_, _ = fmt.Fprintf(w, "  - release or delete %s (manual: destroy leaves it in the cloud account)\n", item)
+	}
+	_, _ = fmt.Fprintf(w, "  - archive %d release(s) and infrastructure records from state\n", p.Releases)
+}
+
+// confirmDestroy asks for the environment name and then for "destroy".
+func confirmDestroy(confirmer *prompt.Confirmer, env string) error {
+	if err := confirmer.ConfirmTyped(fmt.Sprintf("Type the environment name (%s) to confirm:", env), env); err != nil {
+		return err
+	}
+	return confirmer.ConfirmTyped(`This cannot be undone. Type "destroy" to proceed:`, "destroy")
+}
+
+// execute runs the teardown in plan order and stops at the first failure,
+// except that hosts which cannot leave the network are reported and still
+// deleted. Every step tolerates work already done, so a failed destroy can
+// be run again.
+func (p *destroyPlan) execute(ctx context.Context, out io.Writer, stateMgr *state.Manager, logger logging.Logger) error {
+	if p.ComposeFile != "" {
+		result, err := newRunner().Run(ctx, p.runtime.ComposeCommand("-f", p.ComposeFile, "down", "--remove-orphans"))
+		if err != nil {
+			return fmt.Errorf("stopping compose stack: %w", err)
+		}
+		if result.ExitCode != 0 {
+			return fmt.Errorf("%s compose down failed with exit code %d: %s", p.runtime, result.ExitCode, strings.TrimSpace(string(result.Stderr)))
+		}
+		_, _ = fmt.Fprintf(out, "✓ Stopped compose stack %s\n", displayPath(p.ComposeFile))
+	}
+
+	if p.Leaver != nil {
+		for _, h := range p.Hosts {
+			if err := p.Leaver.Leave(ctx, network.LeaveOptions{Config: p.networkConfig, Host: h.Name}); err != nil {
+				logger.Warn("Host could not leave the network; remove it manually",
+					logging.NewField("host", h.Name),
+					logging.NewField("error", err.Error()),
+				)
+				_, _ = fmt.Fprintf(out, "! %s did not leave the %s network: %v\n", h.Name, p.Leaver.ID(), err)
+				continue
+			}
+			_, _ = fmt.Fprintf(out, "✓ Removed %s from the %s network\n", h.Name, p.Leaver.ID())
+		}
+	}
+
+	if len(p.Hosts) > 0 {
+		specs := make([]cloud.HostSpec, 0, len(p.Hosts))
+		for _, h := range p.Hosts {
+			specs = append(specs, cloud.HostSpec{Name: h.Name, Role: h.Role, Region: h.Region})
+		}
+		if err := p.cloudProvider.Apply(ctx, cloud.ApplyOptions{
+			Config:      p.cloudConfig,
+			Environment: p.Env,
+			Project:     p.project,
+			Plan:        cloud.InfraPlan{ToDelete: specs},
+		}); err != nil {
+			return fmt.Errorf("deleting hosts: %w", err)
+		}
+		_, _ = fmt.Fprintf(out, "✓ Deleted %d host(s)\n", len(specs))
+	}
+
+	path, err := stateMgr.ArchiveEnvironment(ctx, p.Env)
+	if err != nil {
+		return fmt.Errorf("archiving state: %w", err)
+	}
+	_, _ = fmt.Fprintf(out, "✓ Archived state to %s\n", displayPath(path))
+
+	if len(p.Addresses) > 0 {
+		_, _ = fmt.Fprintf(out, "Remove DNS records pointing at: %s\n", strings.Join(p.Addresses, ", "))
+	}
+	if len(p.LeftBehind) > 0 {
+		_, _ = fmt.Fprintf(out, "Left behind in the cloud account: %s\n", strings.Join(p.LeftBehind, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core/audit"
+	"stagecraft/internal/core/state"
+	"stagecraft/internal/deploy"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/providers/cloud"
+	"stagecraft/pkg/providers/network"
+)
+
+// Feature: CLI_DESTROY
+// Spec: spec/commands/destroy.md
+
+// runRecorder records commands passed to Run.
+type runRecorder struct {
+	commands []executil.Command
+}
+
+func (r *runRecorder) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	r.commands = append(r.commands, cmd)
+	return &executil.Result{}, nil
+}
+
+func (r *runRecorder) RunStream(context.Context, executil.Command, io.Writer) error {
+	return nil
+}
+
+// fakeDestroyCloudProvider records the plan passed to Apply.
+type fakeDestroyCloudProvider struct {
+	fakeCloudProvider
+
+	applied *cloud.InfraPlan
+}
+
+//nolint:gocritic // hugeParam: opts matches CloudProvider interface signature
+func (f *fakeDestroyCloudProvider) Apply(ctx context.Context, opts cloud.ApplyOptions) error {
+	f.applied = &opts.Plan
+	return f.applyErr
+}
+
+// fakeLeaverNetworkProvider adds Leaver to fakeNetworkProviderForCLI.
+type fakeLeaverNetworkProvider struct {
+	fakeNetworkProviderForCLI
+
+	id       string
+	left     []string
+	leaveErr map[string]error
+}
+
+func (f *fakeLeaverNetworkProvider) ID() string {
+	return f.id
+}
+
+func (f *fakeLeaverNetworkProvider) Leave(ctx context.Context, opts network.LeaveOptions) error {
+	if err := f.leaveErr[opts.Host]; err != nil {
+		return err
+	}
+	f.left = append(f.left, opts.Host)
+	return nil
+}
+
+type destroyTestEnv struct {
+	*isolatedStateTestEnv
+
+	cloud   *fakeDestroyCloudProvider
+	network *fakeLeaverNetworkProvider
+	runner  *runRecorder
+}
+
+// setupDestroyTest writes a config using fake cloud and network providers
+// registered under ids unique to the test, a rendered compose file, and a
+// release with a reserved IP for staging.
+func setupDestroyTest(t *testing.T, suffix string) *destroyTestEnv {
+	t.Helper()
+	env := setupIsolatedStateTestEnv(t)
+
+	cloudID := "test-destroy-cloud-" + suffix
+	networkID := "test-destroy-network-" + suffix
+	configContent := `project:
+  name: test-app
+cloud:
+  provider: ` + cloudID + `
+  providers:
+    ` + cloudID + `: {}
+network:
+  provider: ` + networkID + `
+  providers:
+    ` + networkID + `: {}
+environments:
+  staging:
+    driver: docker
+  prod:
+    driver: docker
+  dev:
+    driver: local
+`
+	if err := os.WriteFile(filepath.Join(env.TempDir, "stagecraft.yml"), []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	fakeCloud := &fakeDestroyCloudProvider{fakeCloudProvider: fakeCloudProvider{
+		id: cloudID,
+		hosts: []cloud.Host{
+			{ID: "101", Name: "app-1", Role: "app", PublicIP: "192.0.2.1", Region: "fra1", Volumes: []cloud.HostVolume{{Name: "data"}}},
+			{ID: "102", Name: "db-1", Role: "db", PublicIP: "192.0.2.2", Region: "fra1"},
+		},
+	}}
+	cloud.Register(fakeCloud)
+	fakeNetwork := &fakeLeaverNetworkProvider{id: networkID}
+	network.Register(fakeNetwork)
+
+	composePath := deploy.RenderedComposePath(env.TempDir, "staging")
+	if err := os.MkdirAll(filepath.Dir(composePath), 0o750); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(composePath, []byte("services: {}\n"), 0o600); err != nil {
+		t.Fatalf("writing compose file: %v", err)
+	}
+
+	for _, e := range []string{"staging", "prod"} {
+		if _, err := env.Manager.CreateRelease(env.Ctx, e, "v1", "abc"); err != nil {
+			t.Fatalf("CreateRelease: %v", err)
+		}
+	}
+	if err := env.Manager.SetReservedIPs(env.Ctx, "staging", []state.ReservedIP{{Host: "app-1", Address: "203.0.113.10"}}); err != nil {
+		t.Fatalf("SetReservedIPs: %v", err)
+	}
+
+	answerPrompts(t)
+
+	recorder := &runRecorder{}
+	origRunner := newRunner
+	newRunner = func() executil.Runner { return recorder }
+	t.Cleanup(func() { newRunner = origRunner })
+
+	return &destroyTestEnv{isolatedStateTestEnv: env, cloud: fakeCloud, network: fakeNetwork, runner: recorder}
+}
+
+func executeDestroy(stdin string, args ...string) (string, error) {
+	root := newTestRootCommand()
+	root.AddCommand(NewDestroyCommand())
+	root.SetIn(strings.NewReader(stdin))
+	return executeCommandForGolden(root, append([]string{"destroy"}, args...)...)
+}
+
+func TestDestroyCommand_DryRunChangesNothing(t *testing.T) {
+	env := setupDestroyTest(t, "dry-run")
+
+	out, err := executeDestroy("", "--env", "staging", "--dry-run")
+	if err != nil {
+		t.Fatalf("destroy --dry-run: %v", err)
+	}
+
+	want := `Destroy plan for environment "staging":
+  - stop compose stack .stagecraft/rendered/staging/docker-compose.yml
+  - remove app-1 from the test-destroy-network-dry-run network
+  - remove db-1 from the test-destroy-network-dry-run network
+  - delete host app-1 (test-destroy-cloud-dry-run 101, 192.0.2.1)
+  - delete host db-1 (test-destroy-cloud-dry-run 102, 192.0.2.2)
+  - remove DNS records pointing at 192.0.2.1 (manual: Stagecraft does not manage DNS)
+  - remove DNS records pointing at 192.0.2.2 (manual: Stagecraft does not manage DNS)
+  - remove DNS records pointing at 203.0.113.10 (manual: Stagecraft does not manage DNS)
+  - release or delete volume data on app-1 (manual: destroy leaves it in the cloud account)
+  - release or delete reserved IP 203.0.113.10 (manual: destroy leaves it in the cloud account)
+  - archive 1 release(s) and infrastructure records from state
+Dry run: nothing was changed.
+`
+	if out != want {
+		t.Errorf("output =\n%s\nwant:\n%s", out, want)
+	}
+
+	if env.cloud.applied != nil || len(env.network.left) != 0 || len(env.runner.commands) != 0 {
+		t.Errorf("dry run changed something: applied=%v left=%v commands=%v", env.cloud.applied, env.network.left, env.runner.commands)
+	}
+	if releases, _ := env.Manager.ListReleases(env.Ctx, "staging"); len(releases) != 1 {
+		t.Errorf("staging releases = %d, want 1", len(releases))
+	}
+	if entries, _ := audit.NewDefaultLog().List(env.Ctx, audit.Filter{}); len(entries) != 0 {
+		t.Errorf("dry run recorded %d audit entries", len(entries))
+	}
+}
+
+func TestDestroyCommand_ConfirmationMismatch(t *testing.T) {
+	tests := map[string]string{
+		"wrong environment": "prod\ndestroy\n",
+		"second prompt":     "staging\nyes\n",
+		"no input":          "",
+	}
+	for name, stdin := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := setupDestroyTest(t, "mismatch-"+strings.ReplaceAll(name, " ", "-"))
+
+			_, err := executeDestroy(stdin, "--env", "staging")
+			if err == nil || !strings.Contains(err.Error(), "confirmation declined; nothing was changed") {
+				t.Fatalf("expected confirmation error, got %v", err)
+			}
+			if env.cloud.applied != nil || len(env.runner.commands) != 0 {
+				t.Errorf("mismatch changed something: applied=%v commands=%v", env.cloud.applied, env.runner.commands)
+			}
+		})
+	}
+}
+
+func TestDestroyCommand_TearsDownEnvironment(t *testing.T) {
+	env := setupDestroyTest(t, "full")
+	env.network.leaveErr = map[string]error{"db-1": errors.New("host unreachable")}
+
+	out, err := executeDestroy("staging\ndestroy\n", "--env", "staging")
+	if err != nil {
+		t.Fatalf("destroy: %v\n%s", err, out)
+	}
+
+	if len(env.runner.commands) != 1 {
+		t.Fatalf("commands = %v, want one compose down", env.runner.commands)
+	}
+	if args := strings.Join(env.runner.commands[0].Args, " "); !strings.HasSuffix(args, "docker-compose.yml down --remove-orphans") {
+		t.Errorf("compose command args = %q", args)
+	}
+
+	if got := strings.Join(env.network.left, ","); got != "app-1" {
+		t.Errorf("left = %q, want app-1", got)
+	}
+	if !strings.Contains(out, "! db-1 did not leave the test-destroy-network-full network: host unreachable") {
+		t.Errorf("output missing leave failure:\n%s", out)
+	}
+
+	if env.cloud.applied == nil || len(env.cloud.applied.ToDelete) != 2 || len(env.cloud.applied.ToCreate) != 0 {
+		t.Fatalf("applied plan = %+v, want both hosts deleted", env.cloud.applied)
+	}
+
+	if releases, _ := env.Manager.ListReleases(env.Ctx, "staging"); len(releases) != 0 {
+		t.Errorf("staging releases after destroy = %d, want 0", len(releases))
+	}
+	if releases, _ := env.Manager.ListReleases(env.Ctx, "prod"); len(releases) != 1 {
+		t.Errorf("prod releases after destroy = %d, want 1", len(releases))
+	}
+	archives, _ := filepath.Glob(filepath.Join(env.TempDir, ".stagecraft", "archive", "staging-*.json"))
+	if len(archives) != 1 {
+		t.Errorf("archives = %v, want one staging archive", archives)
+	}
+	if !strings.Contains(out, "Remove DNS records pointing at: 192.0.2.1, 192.0.2.2, 203.0.113.10") {
+		t.Errorf("output missing DNS reminder:\n%s", out)
+	}
+	if !strings.Contains(out, "Left behind in the cloud account: volume data on app-1, reserved IP 203.0.113.10") {
+		t.Errorf("output missing left-behind reminder:\n%s", out)
+	}
+
+	entries, err := audit.NewDefaultLog().List(env.Ctx, audit.Filter{})
+	if err != nil {
+		t.Fatalf("listing audit entries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Command != "destroy" || entries[0].Environment != "staging" || entries[0].Outcome != audit.OutcomeSuccess {
+		t.Errorf("audit entries = %+v, want one successful destroy", entries)
+	}
+}
+
+func TestDestroyCommand_HostDeletionFailureKeepsState(t *testing.T) {
+	env := setupDestroyTest(t, "apply-fails")
+	env.cloud.applyErr = errors.New("api down")
+
+	_, err := executeDestroy("staging\ndestroy\n", "--env", "staging")
+	if err == nil || !strings.Contains(err.Error(), "deleting hosts: api down") {
+		t.Fatalf("expected host deletion error, got %v", err)
+	}
+	if releases, _ := env.Manager.ListReleases(env.Ctx, "staging"); len(releases) != 1 {
+		t.Errorf("staging releases = %d, want state kept", len(releases))
+	}
+
+	entries, err := audit.NewDefaultLog().List(env.Ctx, audit.Filter{})
+	if err != nil {
+		t.Fatalf("listing audit entries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Outcome != audit.OutcomeFailure || !strings.Contains(entries[0].Error, "api down") {
+		t.Errorf("audit entries = %+v, want one failed destroy", entries)
+	}
+}
+
+func TestDestroyCommand_RequiresExplicitEnv(t *testing.T) {
+	setupDestroyTest(t, "no-env")
+
+	_, err := executeDestroy("", "--dry-run")
+	if err == nil || !strings.Contains(err.Error(), "--env is required") {
+		t.Fatalf("expected --env error, got %v", err)
+	}
+}
+
+func TestDestroyCommand_RejectsLocalEnvironment(t *testing.T) {
+	setupDestroyTest(t, "local")
+
+	_, err := executeDestroy("", "--env", "dev", "--dry-run")
+	if err == nil || !strings.Contains(err.Error(), "local environment") {
+		t.Fatalf("expected local environment error, got %v", err)
+	}
+}
@@ -7,16 +7,21 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
+	"stagecraft/internal/containerruntime"
 	dev "stagecraft/internal/dev"
 	devcompose "stagecraft/internal/dev/compose"
 	devhosts "stagecraft/internal/dev/hosts"
+	devmetrics "stagecraft/internal/dev/metrics"
 	devmkcert "stagecraft/internal/dev/mkcert"
 	devprocess "stagecraft/internal/dev/process"
 
 	"github.com/spf13/cobra"
 
 	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
 )
 
 // Feature: CLI_DEV
@@ -30,6 +35,8 @@ const (
 	devFlagNoTraefik = "no-traefik"
 	devFlagDetach    = "detach"
 	devFlagVerbose   = "verbose"
+	devFlagMetrics   = "metrics-addr"
+	devFlagTUI       = "tui"
 )
 
 // NewDevCommand returns the `stagecraft dev` command.
@@ -59,6 +66,8 @@ validates flags and prepares the execution context.`,
 	cmd.Flags().Bool(devFlagNoTraefik, false, "Skip Traefik setup (providers must expose ports directly)")
 	cmd.Flags().Bool(devFlagDetach, false, "Run dev stack in the background and return immediately")
 	cmd.Flags().Bool(devFlagVerbose, false, "Enable verbose output for debugging")
+	cmd.Flags().String(devFlagMetrics, "", "Serve Prometheus metrics on this loopback address (e.g. 127.0.0.1:9464)")
+	cmd.Flags().Bool(devFlagTUI, false, "Show an interactive dashboard instead of plain compose output")
 
 	return cmd
 }
@@ -75,6 +84,10 @@ type devOptions struct {
 	NoTraefik bool
 	Detach    bool
 	Verbose   bool
+	// MetricsAddr enables the DEV_METRICS endpoint when non-empty.
+	MetricsAddr string
+	// TUI shows the DEV_DASHBOARD instead of plain compose output.
+	TUI bool
 }
 
 // runDevCommand is the Cobra entry point. It parses flags and delegates
@@ -115,14 +128,26 @@ func runDevCommand(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("dev: get %s flag: %w", devFlagVerbose, err)
 	}
 
+	metricsAddr, err := cmd.Flags().GetString(devFlagMetrics)
+	if err != nil {
+		return fmt.Errorf("dev: get %s flag: %w", devFlagMetrics, err)
+	}
+
+	tui, err := cmd.Flags().GetBool(devFlagTUI)
+	if err != nil {
+		return fmt.Errorf("dev: get %s flag: %w", devFlagTUI, err)
+	}
+
 	opts := devOptions{
-		Env:       env,
-		Config:    configPath,
-		NoHTTPS:   noHTTPS,
-		NoHosts:   noHosts,
-		NoTraefik: noTraefik,
-		Detach:    detach,
-		Verbose:   verbose,
+		Env:         env,
+		Config:      configPath,
+		NoHTTPS:     noHTTPS,
+		NoHosts:     noHosts,
+		NoTraefik:   noTraefik,
+		Detach:      detach,
+		Verbose:     verbose,
+		MetricsAddr: metricsAddr,
+		TUI:         tui,
 	}
 
 	return runDevWithOptions(cmd.Context(), opts)
@@ -134,11 +159,42 @@ func runDevWithOptions(ctx context.Context, opts devOptions) error {
 		return fmt.Errorf("dev: --%s must not be empty", devFlagEnv)
 	}
 
+	// DEV_METRICS: the endpoint only makes sense while dev stays attached.
+	var registry *devmetrics.Registry
+	if opts.MetricsAddr != "" {
+		if opts.Detach {
+			return fmt.Errorf("dev: --%s cannot be combined with --%s", devFlagMetrics, devFlagDetach)
+		}
+		if err := devmetrics.ValidateAddr(opts.MetricsAddr); err != nil {
+			return fmt.Errorf("dev: %w", err)
+		}
+		registry = devmetrics.NewRegistry(time.Now())
+	}
+
+	// DEV_DASHBOARD: the dashboard needs a terminal and an attached stack.
+	if opts.TUI {
+		if opts.Detach {
+			return fmt.Errorf("dev: --%s cannot be combined with --%s", devFlagTUI, devFlagDetach)
+		}
+		if !isInteractive(os.Stdin) {
+			return fmt.Errorf("dev: --%s requires an interactive terminal", devFlagTUI)
+		}
+	}
+
 	// 1. Load config
 	cfg, err := loadConfigForEnv(opts.Config, opts.Env)
 	if err != nil {
 		return fmt.Errorf("dev: load config: %w", err)
 	}
+	runtime := containerruntime.ForConfig(cfg)
+
+	var dockerContext string
+	if runtime == containerruntime.Docker {
+		dockerContext, err = checkDockerContext(ctx, cfg, opts)
+		if err != nil {
+			return err
+		}
+	}
 
 	// 2. Compute dev domains (config-driven with defaults).
 	domains, err := dev.ComputeDomains(cfg, opts.Env)
@@ -164,7 +220,7 @@ func runDevWithOptions(ctx context.Context, opts devOptions) error {
 	}
 
 	// 4. DEV_MKCERT: ensure certificates when HTTPS is enabled.
-	devDir := ".stagecraft/dev" // relative to project root.
+	devDir := dev.DefaultDir // relative to project root.
 	certGen := devmkcert.NewGenerator()
 
 	certCfg, err := certGen.EnsureCertificates(ctx, cfg, devmkcert.Options{
@@ -178,60 +234,144 @@ func runDevWithOptions(ctx context.Context, opts devOptions) error {
 		return fmt.Errorf("dev: ensure HTTPS certificates: %w", err)
 	}
 
-	// 5. Resolve providers and extract service definitions
+	// 5-6. Resolve providers and build the topology.
+	composeStart := time.Now()
+	topology, err := buildDevTopology(cfg, opts.Env, domains, certCfg, opts.NoTraefik)
+	if err != nil {
+		return err
+	}
+
+	// 7. Persist dev config files.
+
+	if _, err := dev.WriteFiles(devDir, topology); err != nil {
+		return fmt.Errorf("dev: write dev files: %w", err)
+	}
+	if registry != nil {
+		registry.ObserveComposeGeneration(time.Since(composeStart))
+	}
+
+	// DEV_METRICS: serve metrics and sample compose state for the lifetime
+	// of the foreground stack.
+	if registry != nil {
+		metricsCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		addr, err := devmetrics.Serve(metricsCtx, opts.MetricsAddr, registry)
+		if err != nil {
+			return fmt.Errorf("dev: start metrics endpoint: %w", err)
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "dev: metrics available at http://%s/metrics\n", addr)
+
+		poller := &devmetrics.Poller{
+			Registry:      registry,
+			Runner:        executil.NewRunner(),
+			ComposePath:   filepath.Join(devDir, dev.ComposeFileName),
+			Runtime:       runtime,
+			DockerContext: dockerContext,
+		}
+		go poller.Run(metricsCtx)
+	}
+
+	// 8. Start processes via DEV_PROCESS_MGMT.
+	procOpts := devprocess.Options{
+		DevDir:        devDir,
+		NoTraefik:     opts.NoTraefik,
+		Detach:        opts.Detach,
+		Verbose:       opts.Verbose,
+		Runtime:       runtime,
+		DockerContext: dockerContext,
+	}
+
+	runner := devprocess.NewRunner()
+
+	if opts.TUI {
+		return runDevDashboard(ctx, runner, procOpts, opts.Env, topology, !opts.NoHTTPS)
+	}
+
+	if err := runner.Run(ctx, procOpts); err != nil {
+		return fmt.Errorf("dev: start processes: %w", err)
+	}
+
+	return nil
+}
+
+// checkDockerContext resolves the Docker context dev runs against and warns
+// when it would break bind mounts or published ports. It returns the pinned
+// dev.docker_context, or "" to leave the active context in place. An
+// unreadable active context is not an error; docker compose reports it.
+func checkDockerContext(ctx context.Context, cfg *config.Config, opts devOptions) (string, error) {
+	var pinned string
+	if cfg.Dev != nil {
+		pinned = cfg.Dev.DockerContext
+	}
+
+	dockerCtx, err := containerruntime.ActiveDockerContext(ctx, newRunner(), pinned)
+	if err != nil {
+		if pinned != "" {
+			return "", fmt.Errorf("dev: docker context %q from dev.docker_context: %w", pinned, err)
+		}
+		return "", nil
+	}
+
+	if opts.Verbose {
+		_, _ = fmt.Fprintf(os.Stderr, "dev: using docker context %q (%s, %s)\n", dockerCtx.Name, dockerCtx.Kind(), dockerCtx.Host)
+	}
+
+	projectDir, err := filepath.Abs(filepath.Dir(opts.Config))
+	if err != nil {
+		return "", fmt.Errorf("dev: resolving project directory: %w", err)
+	}
+	home, _ := os.UserHomeDir()
+	for _, warning := range dockerCtx.DevWarnings(projectDir, home) {
+		_, _ = fmt.Fprintf(os.Stderr, "dev: warning: %s\n", warning)
+	}
+
+	return pinned, nil
+}
+
+// buildDevTopology resolves backend and frontend service definitions and
+// builds the dev topology. CLI_COMPOSE_RENDER shares it so rendered output
+// matches what dev writes.
+func buildDevTopology(
+	cfg *config.Config,
+	env string,
+	domains dev.Domains,
+	certCfg *devmkcert.CertConfig,
+	noTraefik bool,
+) (*dev.Topology, error) {
 	builder := dev.NewDefaultBuilder()
 
-	backendSvc, frontendSvc, err := builder.ResolveServiceDefinitions(cfg, opts.Env)
+	backendSvcs, frontendSvc, err := builder.ResolveServices(cfg, env)
 	if err != nil {
-		return fmt.Errorf("dev: resolve service definitions: %w", err)
+		return nil, fmt.Errorf("dev: resolve service definitions: %w", err)
 	}
 
 	// Backend is required for v1
-	if backendSvc == nil {
-		return fmt.Errorf("dev: backend provider is required")
+	if len(backendSvcs) == 0 {
+		return nil, fmt.Errorf("dev: backend provider is required")
 	}
 
-	// 6. Conditionally include Traefik based on --no-traefik flag
+	// Conditionally include Traefik based on --no-traefik flag
 	var traefikSvc *devcompose.ServiceDefinition
-	if !opts.NoTraefik {
+	if !noTraefik {
 		traefikSvc = &devcompose.ServiceDefinition{
 			Name: "traefik",
 		}
 	}
 
-	topology, err := builder.Build(
+	topology, err := builder.BuildServices(
 		cfg,
 		domains,
-		backendSvc,
+		backendSvcs,
 		frontendSvc,
 		traefikSvc,
 		certCfg, // CertConfig from DEV_MKCERT
 	)
 	if err != nil {
-		return fmt.Errorf("dev: build topology: %w", err)
-	}
-
-	// 7. Persist dev config files.
-
-	if _, err := dev.WriteFiles(devDir, topology); err != nil {
-		return fmt.Errorf("dev: write dev files: %w", err)
+		return nil, fmt.Errorf("dev: build topology: %w", err)
 	}
 
-	// 8. Start processes via DEV_PROCESS_MGMT.
-	procOpts := devprocess.Options{
-		DevDir:    devDir,
-		NoTraefik: opts.NoTraefik,
-		Detach:    opts.Detach,
-		Verbose:   opts.Verbose,
-	}
-
-	runner := devprocess.NewRunner()
-
-	if err := runner.Run(ctx, procOpts); err != nil {
-		return fmt.Errorf("dev: start processes: %w", err)
-	}
-
-	return nil
+	return topology, nil
 }
 
 // loadConfigForEnv loads the Stagecraft config for the given env.
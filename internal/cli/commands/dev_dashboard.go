@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	dev "stagecraft/internal/dev"
+	devdashboard "stagecraft/internal/dev/dashboard"
+	devprocess "stagecraft/internal/dev/process"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEV_DASHBOARD
+// Spec: spec/dev/dashboard.md
+
+// runDevDashboard runs the stack in the foreground behind the dashboard.
+// Quitting the dashboard tears the stack down as Ctrl+C does in plain mode.
+func runDevDashboard(
+	ctx context.Context,
+	runner *devprocess.Runner,
+	procOpts devprocess.Options,
+	env string,
+	topology *dev.Topology,
+	https bool,
+) error {
+	stackCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	board := devdashboard.New(stackCtx, devdashboard.Options{
+		Title:    env,
+		Services: dashboardServices(topology, procOpts.NoTraefik, https),
+		Actions: &devdashboard.ComposeActions{
+			Runner:        executil.NewRunner(),
+			Runtime:       procOpts.Runtime,
+			ComposePath:   filepath.Join(procOpts.DevDir, dev.ComposeFileName),
+			DockerContext: procOpts.DockerContext,
+		},
+	})
+
+	logs := board.LogWriter()
+	procOpts.Stdout = logs
+	procOpts.Stderr = logs
+
+	stackErr := make(chan error, 1)
+	go func() {
+		err := runner.Run(stackCtx, procOpts)
+		board.StackExited(err)
+		stackErr <- err
+	}()
+
+	boardErr := board.Run()
+	cancel()
+	err := <-stackErr
+
+	if boardErr != nil {
+		return fmt.Errorf("dev: dashboard: %w", boardErr)
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("dev: start processes: %w", err)
+	}
+	return nil
+}
+
+// dashboardServices lists the compose services with the URLs Traefik
+// routes to them. Without Traefik no service has a URL.
+func dashboardServices(topology *dev.Topology, noTraefik, https bool) []devdashboard.Service {
+	scheme := "http"
+	if https {
+		scheme = "https"
+	}
+
+	urls := make(map[string]string)
+	if !noTraefik {
+		if topology.Backend != nil {
+			urls[topology.Backend.Name] = scheme + "://" + topology.Domains.Backend
+		}
+		if topology.Frontend != nil {
+			urls[topology.Frontend.Name] = scheme + "://" + topology.Domains.Frontend
+		}
+	}
+
+	names := topology.Compose.GetServices()
+	services := make([]devdashboard.Service, 0, len(names))
+	for _, name := range names {
+		services = append(services, devdashboard.Service{Name: name, URL: urls[name]})
+	}
+	return services
+}
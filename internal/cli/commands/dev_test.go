@@ -4,10 +4,13 @@ package commands
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	dev "stagecraft/internal/dev"
 )
 
 // Feature: CLI_DEV
@@ -32,6 +35,8 @@ func TestNewDevCommand_HasExpectedFlags(t *testing.T) {
 		{name: "no-traefik bool flag", flagName: devFlagNoTraefik, expectedType: "bool"},
 		{name: "detach bool flag", flagName: devFlagDetach, expectedType: "bool"},
 		{name: "verbose bool flag", flagName: devFlagVerbose, expectedType: "bool"},
+		{name: "metrics-addr string flag", flagName: devFlagMetrics, expectedType: "string"},
+		{name: "tui bool flag", flagName: devFlagTUI, expectedType: "bool"},
 	}
 
 	for _, tt := range tests {
@@ -138,6 +143,118 @@ func TestRunDevWithOptions_EmptyEnvFails(t *testing.T) {
 	}
 }
 
+// Feature: DEV_METRICS
+// Spec: spec/dev/metrics.md
+func TestRunDevWithOptions_MetricsAddrValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    devOptions
+		wantErr string
+	}{
+		{
+			name:    "detach is rejected",
+			opts:    devOptions{Env: "dev", Detach: true, MetricsAddr: "127.0.0.1:9464"},
+			wantErr: "cannot be combined",
+		},
+		{
+			name:    "non-loopback is rejected",
+			opts:    devOptions{Env: "dev", MetricsAddr: "0.0.0.0:9464"},
+			wantErr: "loopback",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runDevWithOptions(context.Background(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("runDevWithOptions() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunDevWithOptions_TUIValidation(t *testing.T) {
+	orig := isInteractive
+	t.Cleanup(func() { isInteractive = orig })
+
+	tests := []struct {
+		name        string
+		opts        devOptions
+		interactive bool
+		wantErr     string
+	}{
+		{
+			name:        "detach is rejected",
+			opts:        devOptions{Env: "dev", Detach: true, TUI: true},
+			interactive: true,
+			wantErr:     "--tui cannot be combined with --detach",
+		},
+		{
+			name:    "non-interactive is rejected",
+			opts:    devOptions{Env: "dev", TUI: true},
+			wantErr: "--tui requires an interactive terminal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isInteractive = func(io.Reader) bool { return tt.interactive }
+			err := runDevWithOptions(context.Background(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("runDevWithOptions() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDashboardServices(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "stagecraft.yml")
+	configContent := `project:
+  name: test-app
+backend:
+  provider: generic
+  providers:
+    generic:
+      dev:
+        command: ["echo", "backend"]
+environments:
+  dev:
+    driver: local
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	cfg, err := loadConfigForEnv(configPath, "dev")
+	if err != nil {
+		t.Fatalf("loadConfigForEnv() error = %v", err)
+	}
+	domains, err := dev.ComputeDomains(cfg, "dev")
+	if err != nil {
+		t.Fatalf("ComputeDomains() error = %v", err)
+	}
+	topology, err := buildDevTopology(cfg, "dev", domains, nil, false)
+	if err != nil {
+		t.Fatalf("buildDevTopology() error = %v", err)
+	}
+
+	urls := make(map[string]string)
+	for _, svc := range dashboardServices(topology, false, true) {
+		urls[svc.Name] = svc.URL
+	}
+	if got, want := urls[topology.Backend.Name], "https://"+domains.Backend; got != want {
+		t.Errorf("backend URL = %q, want %q", got, want)
+	}
+	if url, ok := urls["traefik"]; !ok || url != "" {
+		t.Errorf("traefik = %q (listed %v), want listed without URL", url, ok)
+	}
+
+	for _, svc := range dashboardServices(topology, true, false) {
+		if svc.URL != "" {
+			t.Errorf("%s URL = %q without traefik, want none", svc.Name, svc.URL)
+		}
+	}
+}
+
 func TestRunDevWithOptions_BuildsTopology(t *testing.T) {
 	t.Helper()
 
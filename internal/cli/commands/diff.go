@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/core/env"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_DIFF
+// Spec: spec/commands/diff.md
+
+// diffImageVersion stands in for the version in compared image tags; both
+// environments deploy the same version.
+const diffImageVersion = "<version>"
+
+// envDiffView is the structured (json/yaml) form of `diff`. Values are
+// redacted exactly as in the table output.
+type envDiffView struct {
+	Left      string                `json:"left" yaml:"left"`
+	Right     string                `json:"right" yaml:"right"`
+	OnlyLeft  []envDiffSetting      `json:"only_left" yaml:"only_left"`
+	OnlyRight []envDiffSetting      `json:"only_right" yaml:"only_right"`
+	Changed   []envDiffSettingDelta `json:"changed" yaml:"changed"`
+	Unchanged int                   `json:"unchanged" yaml:"unchanged"`
+}
+
+type envDiffSetting struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value" yaml:"value"`
+	Secret bool   `json:"secret" yaml:"secret"`
+}
+
+type envDiffSettingDelta struct {
+	Key    string `json:"key" yaml:"key"`
+	Left   string `json:"left" yaml:"left"`
+	Right  string `json:"right" yaml:"right"`
+	Secret bool   `json:"secret" yaml:"secret"`
+}
+
+// NewDiffCommand returns the `stagecraft diff` command.
+func NewDiffCommand() *cobra.Command {
+	var envs []string
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the resolved configuration of two environments",
+		Long: `Compare two environments' resolved configuration: the environment's
+config block, backend service images and replicas, env_file variables, and
+the hosts the cloud provider declares for each.
+
+Pass --env twice, e.g. ` + "`stagecraft diff --env staging --env prod`" + `. Every
+setting that only one environment defines or whose values differ is listed.
+Values of secret-looking settings are redacted, as are passwords in URLs;
+differing secrets are still reported.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEnvDiff(cmd, envs)
+		},
+	}
+
+	// Shadows the global --env so it can be given twice.
+	cmd.Flags().StringArrayVarP(&envs, "env", "e", nil, "environment to compare; pass exactly twice")
+	registerEnvCompletion(cmd)
+
+	return cmd
+}
+
+func runEnvDiff(cmd *cobra.Command, envs []string) error {
+	if len(envs) != 2 {
+		return fmt.Errorf("diff: exactly two environments are required, e.g. --env staging --env prod (got %d)", len(envs))
+	}
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("diff: resolving flags: %w", err)
+	}
+
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			return fmt.Errorf("diff: stagecraft config not found at %s", flags.Config)
+		}
+		return fmt.Errorf("diff: loading config: %w", err)
+	}
+
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	workDir := filepath.Dir(flags.Config)
+	ctx := commandContext(cmd)
+	var settings [2][]env.Setting
+	for i, name := range envs {
+		if _, ok := cfg.Environments[name]; !ok {
+			return fmt.Errorf("diff: %w: %q", env.ErrEnvironmentNotFound, name)
+		}
+		settings[i], err = environmentSettings(ctx, cfg, name, workDir)
+		if err != nil {
+			return fmt.Errorf("diff: environment %q: %w", name, err)
+		}
+	}
+
+	view := newEnvDiffView(envs[0], envs[1], env.CompareSettings(settings[0], settings[1]))
+	return output.Render(cmd.OutOrStdout(), format, view, func(w io.Writer) error {
+		return displayEnvDiff(w, view)
+	})
+}
+
+// environmentSettings resolves everything diff compares for envName.
+func environmentSettings(ctx context.Context, cfg *config.Config, envName, workDir string) ([]env.Setting, error) {
+	envCfg := cfg.Environments[envName]
+	byKey := make(map[string]env.Setting)
+	add := func(key, value string, secret bool) {
+		byKey[key] = env.Setting{Key: key, Value: value, Secret: secret || env.IsSecretKey(lastKeySegment(key))}
+	}
+
+	if err := flattenEnvironmentConfig(envCfg, add); err != nil {
+		return nil, err
+	}
+
+	for _, svc := range cfg.Backend.ServiceList() {
+		prefix := "services." + svc.Name
+		add(prefix+".image", deployServiceImageTag(cfg, svc.Name, diffImageVersion), false)
+		add(prefix+".replicas", strconv.Itoa(envCfg.ReplicasFor(svc.Name)), false)
+	}
+	if static := envCfg.FrontendStatic; static != nil {
+		add("services."+static.ServiceName()+".image", static.ServerImage(), false)
+	}
+
+	if path := envCfg.EnvFile; path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workDir, path)
+		}
+		vars, err := readOptionalEnvFile(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range vars {
+			add("env."+key, value, false)
+		}
+	}
+
+	hosts, err := declaredHosts(ctx, cfg, envName)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hosts {
+		add("hosts."+h.Name, fmt.Sprintf("role=%s size=%s region=%s", h.Role, h.Size, h.Region), false)
+	}
+
+	settings := make([]env.Setting, 0, len(byKey))
+	for _, s := range byKey {
+		settings = append(settings, s)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings, nil
+}
+
+// flattenEnvironmentConfig adds every field set in the environment's
+// config block as a dotted key, e.g. "rollout.enabled". Lists of scalars
+// are joined with commas; other lists are indexed ("notifications[0].url").
+// Notification URLs and headers carry credentials and are secret.
+func flattenEnvironmentConfig(envCfg config.EnvironmentConfig, add func(key, value string, secret bool)) error {
+	data, err := yaml.Marshal(envCfg)
+	if err != nil {
+		return fmt.Errorf("encoding environment config: %w", err)
+	}
+	var tree map[string]any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return fmt.Errorf("encoding environment config: %w", err)
+	}
+
+	var walk func(key string, node any)
+	walk = func(key string, node any) {
+		switch v := node.(type) {
+		case map[string]any:
+			for k, child := range v {
+				walk(key+"."+k, child)
+			}
+		case []any:
+			if scalars, ok := scalarList(v); ok {
+				add(key, strings.Join(scalars, ","), false)
+				return
+			}
+			for i, child := range v {
+				walk(fmt.Sprintf("%s[%d]", key, i), child)
+			}
+		default:
+			secret := strings.HasPrefix(key, "notifications[") &&
+				(strings.HasSuffix(key, ".url") || strings.Contains(key, ".headers."))
+			add(key, fmt.Sprint(v), secret)
+		}
+	}
+	for k, child := range tree {
+		walk(k, child)
+	}
+	return nil
+}
+
+// scalarList returns the string form of list when it holds only scalars.
+func scalarList(list []any) ([]string, bool) {
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		switch item.(type) {
+		case map[string]any, []any:
+			return nil, false
+		}
+		values = append(values, fmt.Sprint(item))
+	}
+	return values, true
+}
+
+// lastKeySegment returns the last dotted segment of key.
+func lastKeySegment(key string) string {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[i+1:]
+	}
+	return key
+}
+
+// declaredHosts returns the hosts the cloud provider's config declares for
+// env, without calling the cloud API. Projects without a cloud provider, or
+// whose provider cannot report declared hosts, have none.
+func declaredHosts(ctx context.Context, cfg *config.Config, envName string) ([]cloud.HostSpec, error) {
+	if cfg.Cloud == nil || cfg.Cloud.Provider == "" {
+		return nil, nil
+	}
+
+	provider, err := cloud.Get(cfg.Cloud.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("cloud provider %q not found: %w", cfg.Cloud.Provider, err)
+	}
+	desired, ok := provider.(cloud.DesiredHostsProvider)
+	if !ok {
+		return nil, nil
+	}
+
+	var providerCfg any
+	if cfg.Cloud.Providers != nil {
+		providerCfg = cfg.Cloud.Providers[cfg.Cloud.Provider]
+	}
+	hosts, err := desired.DesiredHosts(ctx, cloud.HostsOptions{Config: providerCfg, Environment: envName})
+	if err != nil {
+		return nil, fmt.Errorf("reading declared hosts: %w", err)
+	}
+	return hosts, nil
+}
+
+func newEnvDiffView(left, right string, d *env.SettingsDiff) envDiffView {
+	view := envDiffView{
+		Left:      left,
+		Right:     right,
+		OnlyLeft:  []envDiffSetting{},
+		OnlyRight: []envDiffSetting{},
+		Changed:   []envDiffSettingDelta{},
+		Unchanged: d.Unchanged,
+	}
+	for _, s := range d.OnlyLeft {
+		view.OnlyLeft = append(view.OnlyLeft, envDiffSetting{Key: s.Key, Value: env.RedactSetting(s.Value, s.Secret), Secret: s.Secret})
+	}
+	for _, s := range d.OnlyRight {
+		view.OnlyRight = append(view.OnlyRight, envDiffSetting{Key: s.Key, Value: env.RedactSetting(s.Value, s.Secret), Secret: s.Secret})
+	}
+	for _, c := range d.Changed {
+		view.Changed = append(view.Changed, envDiffSettingDelta{
+			Key:    c.Key,
+			Left:   env.RedactSetting(c.Left, c.Secret),
+			Right:  env.RedactSetting(c.Right, c.Secret),
+			Secret: c.Secret,
+		})
+	}
+	return view
+}
+
+// displayEnvDiff prints one line per setting only in the left (-) or right
+// (+) environment or changed between them (~), followed by a count line.
+func displayEnvDiff(out io.Writer, view envDiffView) error {
+	_, _ = fmt.Fprintf(out, "Comparing %s (-) with %s (+)\n\n", view.Left, view.Right)
+
+	keyWidth := len("KEY")
+	for _, s := range view.OnlyLeft {
+		keyWidth = max(keyWidth, len(s.Key))
+	}
+	for _, s := range view.OnlyRight {
+		keyWidth = max(keyWidth, len(s.Key))
+	}
+	for _, c := range view.Changed {
+		keyWidth = max(keyWidth, len(c.Key))
+	}
+
+	for _, s := range view.OnlyLeft {
+		_, _ = fmt.Fprintf(out, "  - %-*s %s\n", keyWidth, s.Key, s.Value)
+	}
+	for _, s := range view.OnlyRight {
+		_, _ = fmt.Fprintf(out, "  + %-*s %s\n", keyWidth, s.Key, s.Value)
+	}
+	for _, c := range view.Changed {
+		if c.Left == c.Right {
+			_, _ = fmt.Fprintf(out, "  ~ %-*s %s (values differ)\n", keyWidth, c.Key, c.Left)
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "  ~ %-*s %s -> %s\n", keyWidth, c.Key, c.Left, c.Right)
+	}
+
+	_, _ = fmt.Fprintf(out, "  %d only in %s, %d only in %s, %d changed, %d unchanged\n",
+		len(view.OnlyLeft), view.Left, len(view.OnlyRight), view.Right, len(view.Changed), view.Unchanged)
+	return nil
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: CLI_DIFF
+// Spec: spec/commands/diff.md
+
+// writeEnvDiffProject writes a project with a staging and a prod environment.
+func writeEnvDiffProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"stagecraft.yml": `project:
+  name: test-app
+backend:
+  provider: generic
+  providers:
+    generic:
+      dev:
+        command: ["npm", "run", "dev"]
+environments:
+  staging:
+    driver: local
+    env_file: .env.staging
+  prod:
+    driver: digitalocean
+    env_file: .env.prod
+`,
+		".env.staging": "LOG_LEVEL=debug\nJWT_SECRET=staging-secret\nSHARED=same\n",
+		".env.prod":    "JWT_SECRET=prod-secret\nSHARED=same\nSENTRY_DSN=https://sentry.example\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	return filepath.Join(dir, "stagecraft.yml")
+}
+
+func TestDiff_ComparesEnvironments(t *testing.T) {
+	path := writeEnvDiffProject(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewDiffCommand())
+
+	out, err := executeCommandForGolden(root, "diff", "--config", path, "--env", "staging", "--env", "prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"Comparing staging (-) with prod (+)",
+		"- env.LOG_LEVEL",
+		"+ env.SENTRY_DSN",
+		"~ driver",
+		"local -> digitalocean",
+		"~ env.JWT_SECRET",
+		"<redacted> (values differ)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"staging-secret", "prod-secret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("output leaks secret %q:\n%s", secret, out)
+		}
+	}
+	if strings.Contains(out, "env.SHARED") {
+		t.Errorf("unchanged setting listed:\n%s", out)
+	}
+}
+
+func TestDiff_JSON(t *testing.T) {
+	path := writeEnvDiffProject(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewDiffCommand())
+
+	out, err := executeCommandForGolden(root, "diff", "--config", path, "--env", "staging", "--env", "prod", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var view envDiffView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if view.Left != "staging" || view.Right != "prod" {
+		t.Errorf("environments = %q, %q; want staging, prod", view.Left, view.Right)
+	}
+
+	var secret *envDiffSettingDelta
+	for i := range view.Changed {
+		if view.Changed[i].Key == "env.JWT_SECRET" {
+			secret = &view.Changed[i]
+		}
+	}
+	if secret == nil {
+		t.Fatalf("env.JWT_SECRET not reported as changed: %+v", view.Changed)
+	}
+	if !secret.Secret || secret.Left != "<redacted>" || secret.Right != "<redacted>" {
+		t.Errorf("env.JWT_SECRET not redacted: %+v", *secret)
+	}
+}
+
+func TestDiff_RequiresTwoEnvironments(t *testing.T) {
+	path := writeEnvDiffProject(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewDiffCommand())
+
+	_, err := executeCommandForGolden(root, "diff", "--config", path, "--env", "staging")
+	if err == nil || !strings.Contains(err.Error(), "exactly two environments") {
+		t.Fatalf("expected two-environment error, got %v", err)
+	}
+}
+
+func TestDiff_UnknownEnvironment(t *testing.T) {
+	path := writeEnvDiffProject(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewDiffCommand())
+
+	_, err := executeCommandForGolden(root, "diff", "--config", path, "--env", "staging", "--env", "qa")
+	if err == nil || !strings.Contains(err.Error(), `"qa"`) {
+		t.Fatalf("expected unknown environment error, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/mangen"
+)
+
+// Feature: CLI_COMPLETION
+// Spec: spec/commands/completion.md
+
+// NewDocsCommand returns the `stagecraft docs` command group.
+func NewDocsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate reference documentation for the Stagecraft CLI",
+	}
+
+	cmd.AddCommand(newDocsManCommand())
+
+	return cmd
+}
+
+func newDocsManCommand() *cobra.Command {
+	var dir string
+
+	cmd := &cobra.Command{
+		Use:   "man",
+		Short: "Generate man pages for every Stagecraft command",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// #nosec G301 -- man page directory is meant to be world-readable
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("creating man page directory: %w", err)
+			}
+
+			written, err := mangen.GenerateTree(cmd.Root(), dir, mangen.DefaultHeader())
+			if err != nil {
+				return fmt.Errorf("generating man pages: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Wrote %d man pages to %s\n", len(written), dir)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "man", "output directory for generated man pages")
+
+	return cmd
+}
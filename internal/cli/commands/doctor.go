@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/containerruntime"
+	"stagecraft/pkg/config"
+)
+
+// Feature: CLI_DOCTOR
+// Spec: spec/commands/doctor.md
+
+// Doctor check statuses.
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// devTraefikPort is the lowest host port the dev Traefik service publishes.
+const devTraefikPort = 80
+
+// unprivilegedPortStart is a package-level var for tests.
+var unprivilegedPortStart = containerruntime.UnprivilegedPortStart
+
+// doctorView is the structured (json/yaml) form of `doctor`.
+type doctorView struct {
+	Runtime       string        `json:"runtime" yaml:"runtime"`
+	RuntimeSource string        `json:"runtime_source" yaml:"runtime_source"`
+	Rootless      bool          `json:"rootless" yaml:"rootless"`
+	Checks        []doctorCheck `json:"checks" yaml:"checks"`
+}
+
+type doctorCheck struct {
+	Name   string `json:"name" yaml:"name"`
+	Status string `json:"status" yaml:"status"`
+	Detail string `json:"detail" yaml:"detail"`
+	Hint   string `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+func (v *doctorView) add(name, status, detail, hint string) {
+	v.Checks = append(v.Checks, doctorCheck{Name: name, Status: status, Detail: detail, Hint: hint})
+}
+
+// NewDoctorCommand returns the `stagecraft doctor` command.
+func NewDoctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the local container runtime setup",
+		Long: `Check that the container runtime (docker or podman) and its compose
+command work, detect rootless setups and explain how to publish the ports
+` + "`stagecraft dev`" + ` needs.
+
+The runtime comes from container_runtime in the config when one is
+present, otherwise from PATH. Exits non-zero when a check fails.`,
+		Args: cobra.NoArgs,
+		RunE: runDoctor,
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("doctor: resolving flags: %w", err)
+	}
+
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	view := &doctorView{Checks: []doctorCheck{}}
+
+	cfg, err := config.Load(flags.Config)
+	switch {
+	case errors.Is(err, config.ErrConfigNotFound):
+		cfg = nil
+	case err != nil:
+		view.add("config", doctorFail, err.Error(), "fix the config, or run doctor outside the project to check the runtime alone")
+		cfg = nil
+	}
+
+	runtime := containerruntime.ForConfig(cfg)
+	view.Runtime = string(runtime)
+	view.RuntimeSource = "detected"
+	if cfg != nil && cfg.ContainerRuntime != "" {
+		view.RuntimeSource = "config"
+	}
+
+	runDoctorChecks(cmd, view, runtime, cfg, filepath.Dir(flags.Config))
+
+	if err := output.Render(cmd.OutOrStdout(), format, view, func(w io.Writer) error {
+		return displayDoctor(w, view)
+	}); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, c := range view.Checks {
+		if c.Status == doctorFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("doctor: %d check(s) failed", failed)
+	}
+	return nil
+}
+
+func runDoctorChecks(cmd *cobra.Command, view *doctorView, runtime containerruntime.Runtime, cfg *config.Config, projectDir string) {
+	ctx := commandContext(cmd)
+	runner := newRunner()
+
+	version, err := runtime.Version(ctx, runner)
+	if err != nil {
+		view.add("runtime", doctorFail, err.Error(), "install Docker or Podman, or set container_runtime in stagecraft.yml")
+		return
+	}
+	view.add("runtime", doctorOK, version, "")
+
+	if version, err := runtime.ComposeVersion(ctx, runner); err != nil {
+		hint := "install the Docker Compose plugin"
+		if runtime == containerruntime.Podman {
+			hint = "install podman-compose or docker-compose; `podman compose` runs whichever is installed"
+		}
+		view.add("compose", doctorFail, err.Error(), hint)
+	} else {
+		view.add("compose", doctorOK, version, "")
+	}
+
+	rootless, err := runtime.Rootless(ctx, runner)
+	switch {
+	case err != nil:
+		view.add("rootless", doctorWarn, err.Error(), fmt.Sprintf("check that the %s daemon or socket is running", runtime))
+	case rootless:
+		view.Rootless = true
+		view.add("rootless", doctorOK, fmt.Sprintf("%s runs rootless", runtime), "")
+		view.add(doctorPortsCheck(runtime))
+	default:
+		view.add("rootless", doctorOK, fmt.Sprintf("%s runs as root", runtime), "")
+	}
+
+	if runtime == containerruntime.Docker {
+		view.add(doctorContextCheck(cmd, cfg, projectDir))
+	}
+
+	if cfg != nil && runtime != containerruntime.Docker {
+		view.add(doctorRolloutCheck(runtime, cfg))
+	}
+}
+
+// doctorContextCheck reports the Docker context dev would use and whether
+// it breaks bind mounts or published ports.
+func doctorContextCheck(cmd *cobra.Command, cfg *config.Config, projectDir string) (name, status, detail, hint string) {
+	var pinned string
+	if cfg != nil && cfg.Dev != nil {
+		pinned = cfg.Dev.DockerContext
+	}
+
+	dockerCtx, err := containerruntime.ActiveDockerContext(commandContext(cmd), newRunner(), pinned)
+	if err != nil {
+		if pinned != "" {
+			return "context", doctorFail, err.Error(), "create the context or change dev.docker_context"
+		}
+		return "context", doctorWarn, err.Error(), ""
+	}
+
+	detail = fmt.Sprintf("%s (%s, %s)", dockerCtx.Name, dockerCtx.Kind(), dockerCtx.Host)
+	if abs, err := filepath.Abs(projectDir); err == nil {
+		projectDir = abs
+	}
+	home, _ := os.UserHomeDir()
+	if warnings := dockerCtx.DevWarnings(projectDir, home); len(warnings) > 0 {
+		return "context", doctorWarn, detail, strings.Join(warnings, "; ")
+	}
+	return "context", doctorOK, detail, ""
+}
+
+// doctorPortsCheck reports whether a rootless runtime can publish the dev
+// Traefik ports.
+func doctorPortsCheck(runtime containerruntime.Runtime) (name, status, detail, hint string) {
+	start := unprivilegedPortStart()
+	if start <= devTraefikPort {
+		return "ports", doctorOK, fmt.Sprintf("unprivileged ports start at %d; dev can publish 80 and 443", start), ""
+	}
+	return "ports", doctorWarn,
+		fmt.Sprintf("rootless %s cannot publish ports below %d; dev's Traefik publishes 80 and 443", runtime, start),
+		"run `sudo sysctl net.ipv4.ip_unprivileged_port_start=80` (persist it in /etc/sysctl.d), or use `stagecraft dev --no-traefik`"
+}
+
+// doctorRolloutCheck flags environments that enable docker-rollout, which
+// only works with Docker.
+func doctorRolloutCheck(runtime containerruntime.Runtime, cfg *config.Config) (name, status, detail, hint string) {
+	var envs []string
+	for envName, envCfg := range cfg.Environments {
+		if envCfg.Rollout != nil && envCfg.Rollout.Enabled {
+			envs = append(envs, envName)
+		}
+	}
+	if len(envs) == 0 {
+		return "rollout", doctorOK, "no environment enables rollout", ""
+	}
+	sort.Strings(envs)
+	return "rollout", doctorFail,
+		fmt.Sprintf("rollout.enabled in %v needs docker-rollout, which does not support %s", envs, runtime),
+		"disable rollout for these environments, or deploy with Docker"
+}
+
+func displayDoctor(out io.Writer, view *doctorView) error {
+	_, _ = fmt.Fprintf(out, "Container runtime: %s (%s)\n\n", view.Runtime, view.RuntimeSource)
+	displayChecks(out, view.Checks)
+	return nil
+}
+
+// displayChecks prints one line per check, with its hint indented below.
+func displayChecks(out io.Writer, checks []doctorCheck) {
+	nameWidth := 0
+	for _, c := range checks {
+		nameWidth = max(nameWidth, len(c.Name))
+	}
+
+	for _, c := range checks {
+		mark := "✓"
+		switch c.Status {
+		case doctorWarn:
+			mark = "!"
+		case doctorFail:
+			mark = "✗"
+		}
+		_, _ = fmt.Fprintf(out, "%s %-*s  %s\n", mark, nameWidth, c.Name, c.Detail)
+		if c.Hint != "" {
+			_, _ = fmt.Fprintf(out, "  %-*s  hint: %s\n", nameWidth, "", c.Hint)
+		}
+	}
+}
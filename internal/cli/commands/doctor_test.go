@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CLI_DOCTOR
+// Spec: spec/commands/doctor.md
+
+// scriptedRunner answers commands by their full command line; unknown
+// commands fail as if the binary were missing.
+type scriptedRunner struct {
+	outputs map[string]string
+}
+
+func (r *scriptedRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	line := strings.Join(append([]string{cmd.Name}, cmd.Args...), " ")
+	out, ok := r.outputs[line]
+	if !ok {
+		return &executil.Result{ExitCode: -1}, errors.New("executable file not found")
+	}
+	return &executil.Result{Stdout: []byte(out)}, nil
+}
+
+func (r *scriptedRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return nil
+}
+
+func setupDoctorTest(t *testing.T, settings string, outputs map[string]string, portStart int) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+	cfg := `project:
+  name: test-app
+` + settings + `
+environments:
+  dev:
+    driver: local
+`
+	if err := os.WriteFile(path, []byte(cfg), 0o600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	origRunner, origPorts := newRunner, unprivilegedPortStart
+	newRunner = func() executil.Runner { return &scriptedRunner{outputs: outputs} }
+	unprivilegedPortStart = func() int { return portStart }
+	t.Cleanup(func() { newRunner, unprivilegedPortStart = origRunner, origPorts })
+
+	return path
+}
+
+func TestDoctor_RootlessPodmanWarnsAboutLowPorts(t *testing.T) {
+	path := setupDoctorTest(t, "container_runtime: podman", map[string]string{
+		"podman --version":                                 "podman version 5.0.2\n",
+		"podman compose version":                           "docker-compose version 2.27.0\n",
+		"podman info --format {{.Host.Security.Rootless}}": "true\n",
+	}, 1024)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewDoctorCommand())
+
+	out, err := executeCommandForGolden(root, "doctor", "--config", path, "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var view doctorView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("decoding output: %v\n%s", err, out)
+	}
+	if view.Runtime != "podman" || view.RuntimeSource != "config" || !view.Rootless {
+		t.Errorf("unexpected runtime summary: %+v", view)
+	}
+
+	statuses := map[string]string{}
+	for _, c := range view.Checks {
+		statuses[c.Name] = c.Status
+	}
+	want := map[string]string{"runtime": doctorOK, "compose": doctorOK, "rootless": doctorOK, "ports": doctorWarn}
+	for name, status := range want {
+		if statuses[name] != status {
+			t.Errorf("check %s = %q, want %q (all: %v)", name, statuses[name], status, statuses)
+		}
+	}
+}
+
+func TestDoctor_LoweredPortStartPasses(t *testing.T) {
+	path := setupDoctorTest(t, "container_runtime: podman", map[string]string{
+		"podman --version":                                 "podman version 5.0.2\n",
+		"podman compose version":                           "docker-compose version 2.27.0\n",
+		"podman info --format {{.Host.Security.Rootless}}": "true\n",
+	}, 80)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewDoctorCommand())
+
+	out, err := executeCommandForGolden(root, "doctor", "--config", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "✓ ports") || strings.Contains(out, "hint:") {
+		t.Errorf("expected passing ports check without hints:\n%s", out)
+	}
+}
+
+func TestDoctor_MissingComposeFails(t *testing.T) {
+	path := setupDoctorTest(t, "container_runtime: podman", map[string]string{
+		"podman --version": "podman version 5.0.2\n",
+		"podman info --format {{.Host.Security.Rootless}}": "false\n",
+	}, 1024)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewDoctorCommand())
+
+	out, err := executeCommandForGolden(root, "doctor", "--config", path)
+	if err == nil || !strings.Contains(err.Error(), "1 check(s) failed") {
+		t.Fatalf("expected one failed check, got: %v", err)
+	}
+	if !strings.Contains(out, "✗ compose") || !strings.Contains(out, "podman-compose") {
+		t.Errorf("expected compose failure with podman hint:\n%s", out)
+	}
+	if strings.Contains(out, "ports") {
+		t.Errorf("rootful runtime should not get a ports check:\n%s", out)
+	}
+}
+
+func TestDoctor_DockerContextOutsideShare(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("HOME", filepath.Join(string(filepath.Separator), "nonexistent-home"))
+
+	path := setupDoctorTest(t, "container_runtime: docker\ndev:\n  docker_context: colima", map[string]string{
+		"docker --version":                                                  "Docker version 27.0.3\n",
+		"docker compose version":                                            "Docker Compose version v2.28.1\n",
+		"docker info --format {{json .SecurityOptions}}":                    `["name=seccomp,profile=builtin"]`,
+		"docker context inspect colima --format {{.Endpoints.docker.Host}}": "unix:///Users/dev/.colima/default/docker.sock\n",
+	}, 1024)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewDoctorCommand())
+
+	out, err := executeCommandForGolden(root, "doctor", "--config", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "! context   colima (colima, unix:///Users/dev/.colima/default/docker.sock)") {
+		t.Errorf("expected context warning:\n%s", out)
+	}
+	if !strings.Contains(out, "colima start --mount") {
+		t.Errorf("expected colima mount hint:\n%s", out)
+	}
+}
+
+func TestDoctor_MissingPinnedContextFails(t *testing.T) {
+	path := setupDoctorTest(t, "container_runtime: docker\ndev:\n  docker_context: missing", map[string]string{
+		"docker --version":                               "Docker version 27.0.3\n",
+		"docker compose version":                         "Docker Compose version v2.28.1\n",
+		"docker info --format {{json .SecurityOptions}}": `["name=rootless"]`,
+	}, 80)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewDoctorCommand())
+
+	out, err := executeCommandForGolden(root, "doctor", "--config", path)
+	if err == nil {
+		t.Fatalf("expected failure for missing pinned context:\n%s", out)
+	}
+	if !strings.Contains(out, "✗ context") || !strings.Contains(out, "dev.docker_context") {
+		t.Errorf("expected context failure with hint:\n%s", out)
+	}
+}
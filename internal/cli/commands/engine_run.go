@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/agent"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/engine"
+	"stagecraft/pkg/engine/inputs"
+)
+
+// Feature: ENGINE_RUN
+// Spec: spec/engine/engine-run.md
+
+const (
+	// engineRunPlanID and engineRunStepID identify the single-step plan
+	// `engine run` executes, in its report and idempotency key.
+	engineRunPlanID = "engine-run"
+	engineRunStepID = "engine-run"
+
+	// engineRunHost is the logical host the step runs on.
+	engineRunHost = "local"
+)
+
+// NewEngineCommand returns the hidden `stagecraft engine` command group,
+// for developing and debugging step implementations.
+func NewEngineCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "engine",
+		Short:  "Developer tools for engine steps",
+		Long:   "Commands for running individual engine steps outside a plan, for developing and debugging step implementations",
+		Hidden: true,
+	}
+
+	cmd.AddCommand(NewEngineRunCommand())
+
+	return cmd
+}
+
+// NewEngineRunCommand returns the `stagecraft engine run` command.
+func NewEngineRunCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Execute a single step locally",
+		Long: `Decodes the typed inputs for a step action, validates them, and executes
+the step on this machine with the same executor the agent uses. Prints the
+execution report.
+
+With --dry-run the inputs are only validated, and the normalized inputs are
+printed instead.`,
+		Example: `  stagecraft engine run --action apply_compose --inputs step.json
+  echo '{...}' | stagecraft engine run --action migrate --inputs -`,
+		Args: cobra.NoArgs,
+		RunE: runEngineRun,
+	}
+
+	cmd.Flags().String("action", "", "Step action to execute, e.g. apply_compose (required)")
+	cmd.Flags().String("inputs", "", "Path to the step inputs JSON file, or - for stdin (required)")
+	cmd.Flags().String("workdir", ".", "Directory relative compose and migration paths resolve against")
+	cmd.Flags().String("report", "", "Path to write execution report JSON (default: stdout)")
+	_ = cmd.MarkFlagRequired("action")
+	_ = cmd.MarkFlagRequired("inputs")
+
+	_ = cmd.RegisterFlagCompletionFunc("action", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return actionNames(inputs.Actions()), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+func runEngineRun(cmd *cobra.Command, args []string) error {
+	actionName, _ := cmd.Flags().GetString("action")
+	inputsPath, _ := cmd.Flags().GetString("inputs")
+	workDir, _ := cmd.Flags().GetString("workdir")
+	reportPath, _ := cmd.Flags().GetString("report")
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("engine run: %w", err)
+	}
+
+	action := engine.StepAction(actionName)
+	if _, ok := inputs.ForAction(action); !ok {
+		return fmt.Errorf("engine run: unknown action %q (known actions: %s)", actionName, joinActions(inputs.Actions()))
+	}
+
+	data, err := readEngineRunInputs(cmd, inputsPath)
+	if err != nil {
+		return fmt.Errorf("engine run: %w", err)
+	}
+
+	in, err := inputs.Decode(action, data)
+	if err != nil {
+		return fmt.Errorf("engine run: %w", err)
+	}
+
+	if flags.DryRun {
+		normalized, err := json.MarshalIndent(in, "", "  ")
+		if err != nil {
+			return fmt.Errorf("engine run: marshaling inputs: %w", err)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", normalized)
+		return nil
+	}
+
+	absWorkDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return fmt.Errorf("engine run: resolving workdir: %w", err)
+	}
+
+	executors := localStepExecutors(absWorkDir)
+	stepExecutor, ok := executors[action]
+	if !ok {
+		local := make([]engine.StepAction, 0, len(executors))
+		for a := range executors {
+			local = append(local, a)
+		}
+		sort.Slice(local, func(i, j int) bool { return local[i] < local[j] })
+		return fmt.Errorf("engine run: inputs are valid, but action %q has no local executor (local actions: %s)", action, joinActions(local))
+	}
+
+	timeouts, err := engineRunStepTimeouts(flags.Config)
+	if err != nil {
+		return fmt.Errorf("engine run: %w", err)
+	}
+
+	executor := agent.NewExecutor()
+	executor.RegisterExecutor(action, stepExecutor)
+	executor.SetStepTimeouts(timeouts)
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	report, err := executor.ExecuteHostPlan(ctx, engineRunPlan(action, data))
+	if err != nil {
+		return fmt.Errorf("engine run: %w", err)
+	}
+
+	if err := writeExecutionReport(cmd, report, reportPath); err != nil {
+		return fmt.Errorf("engine run: %w", err)
+	}
+
+	if report.Status == engine.ExecStatusFailed {
+		return fmt.Errorf("engine run: step %s failed", action)
+	}
+	return nil
+}
+
+// engineRunPlan wraps a single step in a host plan for the local host.
+func engineRunPlan(action engine.StepAction, data []byte) engine.HostPlan {
+	return engine.HostPlan{
+		Version: engine.HostPlanSchemaVersion,
+		PlanID:  engineRunPlanID,
+		Host:    engine.HostRef{LogicalID: engineRunHost},
+		Steps: []engine.HostPlanStep{{
+			ID:     engineRunStepID,
+			Action: action,
+			Inputs: json.RawMessage(data),
+		}},
+	}
+}
+
+// engineRunStepTimeouts returns the step timeouts from the config at
+// path. Without a config file steps run without a limit.
+func engineRunStepTimeouts(path string) (engine.StepTimeouts, error) {
+	cfg, err := config.Load(path)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		return engine.StepTimeouts{}, nil
+	}
+	if err != nil {
+		return engine.StepTimeouts{}, fmt.Errorf("loading config: %w", err)
+	}
+	return engineStepTimeouts(cfg.Engine), nil
+}
+
+// readEngineRunInputs reads the inputs JSON from path, or from stdin when
+// path is "-".
+func readEngineRunInputs(cmd *cobra.Command, path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("reading inputs from stdin: %w", err)
+		}
+		return data, nil
+	}
+
+	// #nosec G304 // path is user selected; intentional.
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading inputs file %q: %w", path, err)
+	}
+	return data, nil
+}
+
+// actionNames returns the names of actions.
+func actionNames(actions []engine.StepAction) []string {
+	names := make([]string, 0, len(actions))
+	for _, action := range actions {
+		names = append(names, string(action))
+	}
+	return names
+}
+
+// joinActions returns actions as a comma-separated list.
+func joinActions(actions []engine.StepAction) string {
+	return strings.Join(actionNames(actions), ", ")
+}
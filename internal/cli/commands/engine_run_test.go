@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/agent"
+	"stagecraft/pkg/engine"
+)
+
+// Feature: ENGINE_RUN
+// Spec: spec/engine/engine-run.md
+
+// recordingStepExecutor records the steps it executes and returns err.
+type recordingStepExecutor struct {
+	steps []engine.HostPlanStep
+	err   error
+}
+
+//nolint:gocritic // matches agent.StepExecutor
+func (r *recordingStepExecutor) Execute(_ context.Context, step engine.HostPlanStep, _ []byte) error {
+	r.steps = append(r.steps, step)
+	return r.err
+}
+
+// stubLocalStepExecutors replaces the local executors with exec for the
+// duration of the test.
+func stubLocalStepExecutors(t *testing.T, action engine.StepAction, exec agent.StepExecutor) {
+	t.Helper()
+	orig := localStepExecutors
+	localStepExecutors = func(string) map[engine.StepAction]agent.StepExecutor {
+		return map[engine.StepAction]agent.StepExecutor{action: exec}
+	}
+	t.Cleanup(func() { localStepExecutors = orig })
+}
+
+const validApplyComposeInputs = `{
+	"environment": " staging ",
+	"compose_path": "deploy\\compose.yml",
+	"project_name": "app",
+	"pull": true,
+	"detach": true
+}`
+
+func writeEngineRunInputs(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "inputs.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write inputs: %v", err)
+	}
+	return path
+}
+
+func TestEngineRun_ExecutesStep(t *testing.T) {
+	exec := &recordingStepExecutor{}
+	stubLocalStepExecutors(t, engine.StepActionApplyCompose, exec)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewEngineCommand())
+	out, err := executeCommandForGolden(root, "engine", "run",
+		"--action", "apply_compose", "--inputs", writeEngineRunInputs(t, validApplyComposeInputs))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(exec.steps) != 1 {
+		t.Fatalf("expected 1 executed step, got %d", len(exec.steps))
+	}
+	if exec.steps[0].Action != engine.StepActionApplyCompose {
+		t.Errorf("expected action apply_compose, got %q", exec.steps[0].Action)
+	}
+
+	var report engine.ExecutionReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("report is not JSON: %v\n%s", err, out)
+	}
+	if report.Status != engine.ExecStatusSucceeded {
+		t.Errorf("expected status succeeded, got %q", report.Status)
+	}
+	if len(report.Steps) != 1 || report.Steps[0].Host.LogicalID != engineRunHost {
+		t.Errorf("unexpected report steps: %+v", report.Steps)
+	}
+}
+
+func TestEngineRun_WritesReportFile(t *testing.T) {
+	stubLocalStepExecutors(t, engine.StepActionApplyCompose, &recordingStepExecutor{})
+
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	root := newTestRootCommand()
+	root.AddCommand(NewEngineCommand())
+	out, err := executeCommandForGolden(root, "--output", "json", "engine", "run",
+		"--action", "apply_compose", "--inputs", writeEngineRunInputs(t, validApplyComposeInputs), "--report", reportPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Execution report written to "+reportPath) {
+		t.Errorf("expected report path on stdout, got:\n%s", out)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("reading report: %v", err)
+	}
+	var report engine.ExecutionReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not JSON: %v\n%s", err, data)
+	}
+	if report.Status != engine.ExecStatusSucceeded {
+		t.Errorf("expected status succeeded, got %q", report.Status)
+	}
+}
+
+func TestEngineRun_ReadsInputsFromStdin(t *testing.T) {
+	exec := &recordingStepExecutor{}
+	stubLocalStepExecutors(t, engine.StepActionApplyCompose, exec)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewEngineCommand())
+	root.SetIn(strings.NewReader(validApplyComposeInputs))
+	if _, err := executeCommandForGolden(root, "engine", "run", "--action", "apply_compose", "--inputs", "-"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exec.steps) != 1 {
+		t.Fatalf("expected 1 executed step, got %d", len(exec.steps))
+	}
+}
+
+func TestEngineRun_FailedStepReturnsError(t *testing.T) {
+	stubLocalStepExecutors(t, engine.StepActionApplyCompose, &recordingStepExecutor{err: errors.New("compose up failed")})
+
+	root := newTestRootCommand()
+	root.AddCommand(NewEngineCommand())
+	out, err := executeCommandForGolden(root, "engine", "run",
+		"--action", "apply_compose", "--inputs", writeEngineRunInputs(t, validApplyComposeInputs))
+	if err == nil {
+		t.Fatal("expected error for failed step")
+	}
+	if !strings.Contains(err.Error(), "step apply_compose failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "compose up failed") {
+		t.Errorf("report should include the step error, got:\n%s", out)
+	}
+}
+
+func TestEngineRun_InvalidInputs(t *testing.T) {
+	exec := &recordingStepExecutor{}
+	stubLocalStepExecutors(t, engine.StepActionApplyCompose, exec)
+
+	tests := []struct {
+		name    string
+		inputs  string
+		wantErr string
+	}{
+		{
+			name:    "missing field",
+			inputs:  `{"environment": "staging", "compose_path": "compose.yml", "project_name": "app", "pull": true}`,
+			wantErr: "detach is required",
+		},
+		{
+			name:    "unknown field",
+			inputs:  `{"environment": "staging", "compose_path": "compose.yml", "project_name": "app", "pull": true, "detach": true, "extra": 1}`,
+			wantErr: "extra",
+		},
+		{
+			name:    "path escapes workdir",
+			inputs:  `{"environment": "staging", "compose_path": "../compose.yml", "project_name": "app", "pull": true, "detach": true}`,
+			wantErr: "compose_path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newTestRootCommand()
+			root.AddCommand(NewEngineCommand())
+			_, err := executeCommandForGolden(root, "engine", "run",
+				"--action", "apply_compose", "--inputs", writeEngineRunInputs(t, tt.inputs))
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if len(exec.steps) != 0 {
+		t.Errorf("invalid inputs must not execute, got %d steps", len(exec.steps))
+	}
+}
+
+func TestEngineRun_UnknownAction(t *testing.T) {
+	root := newTestRootCommand()
+	root.AddCommand(NewEngineCommand())
+	_, err := executeCommandForGolden(root, "engine", "run",
+		"--action", "deploy_everything", "--inputs", writeEngineRunInputs(t, "{}"))
+	if err == nil {
+		t.Fatal("expected error for unknown action")
+	}
+	if !strings.Contains(err.Error(), `unknown action "deploy_everything"`) || !strings.Contains(err.Error(), "apply_compose") {
+		t.Errorf("error should name the action and list known actions, got: %v", err)
+	}
+}
+
+func TestEngineRun_ActionWithoutLocalExecutor(t *testing.T) {
+	stubLocalStepExecutors(t, engine.StepActionMigrate, &recordingStepExecutor{})
+
+	root := newTestRootCommand()
+	root.AddCommand(NewEngineCommand())
+	_, err := executeCommandForGolden(root, "engine", "run",
+		"--action", "apply_compose", "--inputs", writeEngineRunInputs(t, validApplyComposeInputs))
+	if err == nil {
+		t.Fatal("expected error for action without local executor")
+	}
+	if !strings.Contains(err.Error(), "no local executor") || !strings.Contains(err.Error(), "migrate") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestEngineRun_DryRunPrintsNormalizedInputs(t *testing.T) {
+	exec := &recordingStepExecutor{}
+	stubLocalStepExecutors(t, engine.StepActionApplyCompose, exec)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewEngineCommand())
+	out, err := executeCommandForGolden(root, "--dry-run", "engine", "run",
+		"--action", "apply_compose", "--inputs", writeEngineRunInputs(t, validApplyComposeInputs))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exec.steps) != 0 {
+		t.Errorf("dry run must not execute, got %d steps", len(exec.steps))
+	}
+
+	var normalized map[string]any
+	if err := json.Unmarshal([]byte(out), &normalized); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if normalized["environment"] != "staging" {
+		t.Errorf("expected trimmed environment, got %v", normalized["environment"])
+	}
+	if normalized["compose_path"] != "deploy/compose.yml" {
+		t.Errorf("expected normalized compose_path, got %v", normalized["compose_path"])
+	}
+}
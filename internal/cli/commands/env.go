@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/envcrypt"
+)
+
+// Feature: CORE_ENV_ENCRYPTION
+// Spec: spec/core/env-encryption.md
+
+// newEnvCrypter returns the crypter used by env commands. Tests replace it.
+var newEnvCrypter = envcrypt.New
+
+// NewEnvCommand returns the `stagecraft env` command group.
+func NewEnvCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Inspect and encrypt environment variables",
+	}
+
+	cmd.AddCommand(newEnvEncryptCommand())
+	cmd.AddCommand(newEnvDecryptCommand())
+	cmd.AddCommand(newEnvExplainCommand())
+
+	return cmd
+}
+
+func newEnvEncryptCommand() *cobra.Command {
+	var format string
+	var recipients []string
+
+	cmd := &cobra.Command{
+		Use:   "encrypt <file>",
+		Short: "Encrypt an env file in place with age or SOPS",
+		Long: `Encrypt a dotenv file in place so it can be committed.
+
+With --format sops (the default) keys stay readable and values are
+encrypted; recipients come from --recipient or from .sops.yaml. With
+--format age the whole file is encrypted and --recipient is required.
+
+Stagecraft decrypts these files transparently wherever it reads env files,
+given an age identity in $STAGECRAFT_AGE_KEY_FILE, $SOPS_AGE_KEY_FILE or
+~/.config/sops/age/keys.txt.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			encrypted, err := newEnvCrypter().Encrypt(commandContext(cmd), path, envcrypt.EncryptOptions{
+				Format:     envcrypt.Format(format),
+				Recipients: recipients,
+			})
+			if err != nil {
+				return fmt.Errorf("env encrypt: %w", err)
+			}
+			if err := replaceFile(path, encrypted); err != nil {
+				return fmt.Errorf("env encrypt: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Encrypted %s with %s\n", path, format)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", string(envcrypt.FormatSOPS), "encryption format: sops or age")
+	cmd.Flags().StringArrayVar(&recipients, "recipient", nil, "age public key to encrypt to (repeatable)")
+
+	return cmd
+}
+
+func newEnvDecryptCommand() *cobra.Command {
+	var inPlace bool
+
+	cmd := &cobra.Command{
+		Use:   "decrypt <file>",
+		Short: "Decrypt an age or SOPS env file",
+		Long:  "Decrypt an env file to stdout, or in place with --in-place.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			plaintext, err := newEnvCrypter().ReadFile(commandContext(cmd), path)
+			if err != nil {
+				return fmt.Errorf("env decrypt: %w", err)
+			}
+
+			if !inPlace {
+				_, err := cmd.OutOrStdout().Write(plaintext)
+				return err
+			}
+			if err := replaceFile(path, plaintext); err != nil {
+				return fmt.Errorf("env decrypt: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "✓ Decrypted %s\n", path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&inPlace, "in-place", false, "overwrite the file with its plaintext")
+
+	return cmd
+}
+
+// replaceFile atomically replaces path with data, keeping it private.
+func replaceFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return fmt.Errorf("setting permissions: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing %s: %w", path, err)
+	}
+	return nil
+}
+
+// commandContext returns the command's context, or Background when unset.
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
@@ -0,0 +1,382 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/compose"
+	"stagecraft/internal/core/env"
+	"stagecraft/internal/dev"
+	devmkcert "stagecraft/internal/dev/mkcert"
+	"stagecraft/pkg/config"
+)
+
+// Feature: CORE_ENV_EXPLAIN
+// Spec: spec/core/env-explain.md
+
+// envExplainView is the structured (json/yaml) form of `env explain`.
+// Values are redacted exactly as in the table output.
+type envExplainView struct {
+	Environment string               `json:"environment" yaml:"environment"`
+	Service     string               `json:"service" yaml:"service"`
+	Variables   []envExplainVariable `json:"variables" yaml:"variables"`
+}
+
+type envExplainVariable struct {
+	Key       string               `json:"key" yaml:"key"`
+	Value     string               `json:"value" yaml:"value"`
+	Source    string               `json:"source" yaml:"source"`
+	Origin    string               `json:"origin" yaml:"origin"`
+	Secret    bool                 `json:"secret" yaml:"secret"`
+	Overrides []envExplainOverride `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+}
+
+type envExplainOverride struct {
+	Source string `json:"source" yaml:"source"`
+	Origin string `json:"origin" yaml:"origin"`
+	Value  string `json:"value" yaml:"value"`
+}
+
+func newEnvExplainCommand() *cobra.Command {
+	var service string
+
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Show every env var a service receives and where it came from",
+		Long: `Print the environment variables a service will receive in an environment,
+the source that set each one (provider default, env_file, config, secret
+backend or cli) and any lower-precedence values it overrode.
+
+For local environments this follows what ` + "`stagecraft dev`" + ` generates from the
+backend provider's dev config. For other environments it follows the
+rendered deploy compose file: the service's environment in
+docker-compose.yml wins over the environment's env_file. References like
+${VAR} are shown as docker compose resolves them from the current shell.
+
+Values of secret-looking variables are redacted, as are passwords in URLs.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEnvExplain(cmd, service)
+		},
+	}
+
+	cmd.Flags().StringVar(&service, "service", config.DefaultBackendService, "service to explain")
+	_ = cmd.RegisterFlagCompletionFunc("service", CompleteServices)
+
+	return cmd
+}
+
+func runEnvExplain(cmd *cobra.Command, service string) error {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("env explain: resolving flags: %w", err)
+	}
+
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			return fmt.Errorf("env explain: stagecraft config not found at %s", flags.Config)
+		}
+		return fmt.Errorf("env explain: loading config: %w", err)
+	}
+
+	flags, err = ResolveFlags(cmd, cfg)
+	if err != nil {
+		return fmt.Errorf("env explain: resolving flags: %w", err)
+	}
+
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	envCfg, ok := cfg.Environments[flags.Env]
+	if !ok {
+		return fmt.Errorf("env explain: %w: %q", env.ErrEnvironmentNotFound, flags.Env)
+	}
+
+	workDir := filepath.Dir(flags.Config)
+	ctx := commandContext(cmd)
+
+	var layers []env.Layer
+	if envCfg.Driver == "local" {
+		layers, err = devEnvLayers(ctx, cfg, flags.Env, service, workDir)
+	} else {
+		layers, err = deployEnvLayers(ctx, envCfg, service, workDir)
+	}
+	if err != nil {
+		return fmt.Errorf("env explain: %w", err)
+	}
+
+	vars := env.InterpolateFromShell(env.Explain(layers), os.LookupEnv)
+
+	view := newEnvExplainView(flags.Env, service, vars)
+	return output.Render(cmd.OutOrStdout(), format, view, func(w io.Writer) error {
+		return displayEnvExplain(w, view)
+	})
+}
+
+// devEnvLayers mirrors the dev topology: DEV_PROXY_ENV URLs are defaults
+// that the backend provider's dev config overrides. Providers with a
+// dev.env_file (encore-ts) contribute it below the config, and keys listed
+// in dev.encore_secrets.from_env are delivered through Encore's secret store.
+func devEnvLayers(ctx context.Context, cfg *config.Config, envName, service, workDir string) ([]env.Layer, error) {
+	svc, ok := findBackendService(cfg, service)
+	if !ok {
+		return nil, unknownServiceError(cfg, service)
+	}
+
+	domains, err := dev.ComputeDomains(cfg, envName)
+	if err != nil {
+		return nil, fmt.Errorf("computing dev domains: %w", err)
+	}
+	// `stagecraft dev` enables HTTPS unless --no-https is passed.
+	frontendURL, backendURL := dev.PublicURLs(domains, &devmkcert.CertConfig{Enabled: true})
+
+	layers := []env.Layer{{
+		Source: env.SourceProviderDefault,
+		Origin: "dev proxy",
+		Vars: map[string]string{
+			dev.EnvFrontendURL: frontendURL,
+			dev.EnvBackendURL:  backendURL,
+		},
+	}}
+
+	providerCfg, err := svc.GetProviderConfig()
+	if err != nil {
+		return nil, err
+	}
+	devCfg, _ := mapValue(providerCfg, "dev").(map[string]any)
+
+	var fileVars map[string]string
+	if path, _ := devCfg["env_file"].(string); path != "" {
+		serviceDir := workDir
+		if svc.WorkDir != "" {
+			serviceDir = filepath.Join(workDir, svc.WorkDir)
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(serviceDir, path)
+		}
+		fileVars, err = readOptionalEnvFile(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, env.Layer{Source: env.SourceEnvFile, Origin: relPath(workDir, path), Vars: fileVars})
+	}
+
+	if vars := stringMap(devCfg["env"]); len(vars) > 0 {
+		layers = append(layers, env.Layer{
+			Source: env.SourceConfig,
+			Origin: fmt.Sprintf("%s.providers.%s.dev.env", serviceField(cfg, svc.Name), svc.Provider),
+			Vars:   vars,
+		})
+	}
+
+	secrets, _ := mapValue(devCfg["encore_secrets"], "from_env").([]any)
+	if len(secrets) > 0 {
+		vars := make(map[string]string, len(secrets))
+		for _, s := range secrets {
+			key, _ := s.(string)
+			if value, ok := fileVars[key]; ok {
+				vars[key] = value
+			} else if value, ok := os.LookupEnv(key); ok {
+				vars[key] = value
+			}
+		}
+		layers = append(layers, env.Layer{Source: env.SourceSecretBackend, Origin: "encore secrets", Vars: vars, Secret: true})
+	}
+
+	return layers, nil
+}
+
+// deployEnvLayers mirrors DEPLOY_COMPOSE_GEN: env_file variables fill in
+// keys the service's compose environment does not set.
+func deployEnvLayers(ctx context.Context, envCfg config.EnvironmentConfig, service, workDir string) ([]env.Layer, error) {
+	composePath := filepath.Join(workDir, "docker-compose.yml")
+	composeFile, err := compose.NewLoader().Load(composePath)
+	if err != nil {
+		return nil, fmt.Errorf("loading compose file: %w", err)
+	}
+
+	svcData := composeFile.GetServiceData(service)
+	if svcData == nil {
+		return nil, fmt.Errorf("service %q not found in %s; available: %v", service, relPath(workDir, composePath), composeFile.GetServices())
+	}
+
+	var layers []env.Layer
+	if envCfg.EnvFile != "" {
+		path := envCfg.EnvFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workDir, path)
+		}
+		vars, err := readOptionalEnvFile(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, env.Layer{Source: env.SourceEnvFile, Origin: relPath(workDir, path), Vars: vars})
+	}
+
+	if vars := composeEnvironment(svcData["environment"]); len(vars) > 0 {
+		layers = append(layers, env.Layer{Source: env.SourceConfig, Origin: relPath(workDir, composePath), Vars: vars})
+	}
+
+	return layers, nil
+}
+
+// readOptionalEnvFile reads an env file, treating a missing file as empty
+// like the dev and deploy paths do.
+func readOptionalEnvFile(ctx context.Context, path string) (map[string]string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+	vars, err := env.ReadEnvFile(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("loading env_file %s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// composeEnvironment returns a compose service environment in either map
+// or list form. List entries without '=' take their value from the shell
+// and are left out.
+func composeEnvironment(raw any) map[string]string {
+	vars := map[string]string{}
+	switch e := raw.(type) {
+	case map[string]any:
+		for k, v := range e {
+			if v == nil {
+				continue
+			}
+			vars[k] = fmt.Sprint(v)
+		}
+	case []any:
+		for _, item := range e {
+			s, _ := item.(string)
+			if k, v, ok := strings.Cut(s, "="); ok && k != "" {
+				vars[k] = v
+			}
+		}
+	}
+	return vars
+}
+
+func findBackendService(cfg *config.Config, name string) (config.BackendService, bool) {
+	for _, svc := range cfg.Backend.ServiceList() {
+		if svc.Name == name {
+			return svc, true
+		}
+	}
+	return config.BackendService{}, false
+}
+
+func unknownServiceError(cfg *config.Config, name string) error {
+	var names []string
+	for _, svc := range cfg.Backend.ServiceList() {
+		names = append(names, svc.Name)
+	}
+	return fmt.Errorf("backend service %q not found in config; available: %v", name, names)
+}
+
+// serviceField returns the config path of a backend service.
+func serviceField(cfg *config.Config, name string) string {
+	if cfg.Backend.IsMultiService() {
+		return "backend.services." + name
+	}
+	return "backend"
+}
+
+func mapValue(m any, key string) any {
+	if typed, ok := m.(map[string]any); ok {
+		return typed[key]
+	}
+	return nil
+}
+
+func stringMap(raw any) map[string]string {
+	m, ok := raw.(map[string]any)
+	if !ok {
+		return nil
+	}
+	vars := make(map[string]string, len(m))
+	for k, v := range m {
+		if s, ok := v.(string); ok {
+			vars[k] = s
+		}
+	}
+	return vars
+}
+
+func relPath(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func newEnvExplainView(envName, service string, vars []env.Variable) envExplainView {
+	view := envExplainView{Environment: envName, Service: service, Variables: []envExplainVariable{}}
+	for _, v := range vars {
+		item := envExplainVariable{
+			Key:    v.Key,
+			Value:  env.Redact(v),
+			Source: string(v.Source),
+			Origin: v.Origin,
+			Secret: v.Secret,
+		}
+		for _, o := range v.Overrides {
+			item.Overrides = append(item.Overrides, envExplainOverride{
+				Source: string(o.Source),
+				Origin: o.Origin,
+				Value:  env.RedactOverride(v, o),
+			})
+		}
+		view.Variables = append(view.Variables, item)
+	}
+	return view
+}
+
+func displayEnvExplain(out io.Writer, view envExplainView) error {
+	_, _ = fmt.Fprintf(out, "Environment: %s\nService:     %s\n\n", view.Environment, view.Service)
+
+	if len(view.Variables) == 0 {
+		_, _ = fmt.Fprintf(out, "No environment variables set\n")
+		return nil
+	}
+
+	keyWidth := len("KEY")
+	for _, v := range view.Variables {
+		keyWidth = max(keyWidth, len(v.Key))
+	}
+
+	_, _ = fmt.Fprintf(out, "%-*s %-16s %-32s %s\n", keyWidth, "KEY", "SOURCE", "ORIGIN", "VALUE")
+	for _, v := range view.Variables {
+		_, _ = fmt.Fprintf(out, "%-*s %-16s %-32s %s\n", keyWidth, v.Key, v.Source, v.Origin, v.Value)
+		for _, o := range v.Overrides {
+			_, _ = fmt.Fprintf(out, "%-*s   overrides %s (%s): %s\n", keyWidth, "", o.Source, o.Origin, o.Value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: CORE_ENV_EXPLAIN
+// Spec: spec/core/env-explain.md
+
+// writeEnvExplainProject writes a project with a dev and a staging environment.
+func writeEnvExplainProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"stagecraft.yml": `project:
+  name: test-app
+backend:
+  provider: generic
+  providers:
+    generic:
+      dev:
+        command: ["npm", "run", "dev"]
+        env:
+          PORT: "4000"
+          STAGECRAFT_BACKEND_URL: "http://localhost:4000"
+environments:
+  dev:
+    driver: local
+  staging:
+    driver: digitalocean
+    env_file: .env.staging
+`,
+		"docker-compose.yml": `services:
+  backend:
+    image: app
+    environment:
+      LOG_LEVEL: info
+      RELEASE: ${EXPLAIN_TEST_RELEASE}
+`,
+		".env.staging": "LOG_LEVEL=debug\nJWT_SECRET=supersecret\nDATABASE_URL=postgres://app:hunter2@db:5432/app\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	return filepath.Join(dir, "stagecraft.yml")
+}
+
+func TestEnvExplain_DeployEnvironment(t *testing.T) {
+	path := writeEnvExplainProject(t)
+	t.Setenv("EXPLAIN_TEST_RELEASE", "2025.1")
+
+	root := newTestRootCommand()
+	root.AddCommand(NewEnvCommand())
+
+	out, err := executeCommandForGolden(root, "env", "explain", "--config", path, "--env", "staging", "--service", "backend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"overrides env_file (.env.staging): debug",
+		"postgres://app:xxxxx@db:5432/app",
+		"shell $EXPLAIN_TEST_RELEASE",
+		"2025.1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "supersecret") || strings.Contains(out, "hunter2") {
+		t.Errorf("expected secrets to be redacted, got:\n%s", out)
+	}
+}
+
+func TestEnvExplain_DevEnvironmentJSON(t *testing.T) {
+	path := writeEnvExplainProject(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewEnvCommand())
+
+	out, err := executeCommandForGolden(root, "env", "explain", "--config", path, "--env", "dev", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var view envExplainView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("decoding output: %v\n%s", err, out)
+	}
+
+	byKey := map[string]envExplainVariable{}
+	for _, v := range view.Variables {
+		byKey[v.Key] = v
+	}
+
+	backendURL := byKey["STAGECRAFT_BACKEND_URL"]
+	if backendURL.Source != "config" || backendURL.Value != "http://localhost:4000" {
+		t.Errorf("STAGECRAFT_BACKEND_URL = %+v, want config value", backendURL)
+	}
+	if len(backendURL.Overrides) != 1 || backendURL.Overrides[0].Source != "provider default" {
+		t.Errorf("STAGECRAFT_BACKEND_URL overrides = %+v, want the dev proxy default", backendURL.Overrides)
+	}
+	if frontendURL := byKey["STAGECRAFT_FRONTEND_URL"]; frontendURL.Source != "provider default" {
+		t.Errorf("STAGECRAFT_FRONTEND_URL = %+v, want provider default", frontendURL)
+	}
+}
+
+func TestEnvExplain_UnknownService(t *testing.T) {
+	path := writeEnvExplainProject(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewEnvCommand())
+
+	_, err := executeCommandForGolden(root, "env", "explain", "--config", path, "--env", "staging", "--service", "worker")
+	if err == nil || !strings.Contains(err.Error(), `service "worker" not found`) {
+		t.Fatalf("expected unknown service error, got %v", err)
+	}
+}
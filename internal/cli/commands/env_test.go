@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/envcrypt"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CORE_ENV_ENCRYPTION
+// Spec: spec/core/env-encryption.md
+
+// cryptRunner answers every command with fixed stdout.
+type cryptRunner struct {
+	stdout string
+	args   []string
+}
+
+func (r *cryptRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	r.args = append([]string{cmd.Name}, cmd.Args...)
+	return &executil.Result{Stdout: []byte(r.stdout)}, nil
+}
+
+func (r *cryptRunner) RunStream(context.Context, executil.Command, io.Writer) error { //nolint:gocritic // Runner interface
+	return nil
+}
+
+func stubEnvCrypter(t *testing.T, runner *cryptRunner) {
+	t.Helper()
+	orig := newEnvCrypter
+	newEnvCrypter = func() *envcrypt.Crypter { return envcrypt.NewWithRunner(runner) }
+	t.Cleanup(func() { newEnvCrypter = orig })
+}
+
+func TestEnvEncrypt_ReplacesFileInPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env.dev")
+	if err := os.WriteFile(path, []byte("API_KEY=abc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &cryptRunner{stdout: "API_KEY=ENC[x]\nsops_version=3.9.0\n"}
+	stubEnvCrypter(t, runner)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewEnvCommand())
+
+	out, err := executeCommandForGolden(root, "env", "encrypt", path, "--recipient", "age1abc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Encrypted") {
+		t.Errorf("expected confirmation, got %q", out)
+	}
+	if got := strings.Join(runner.args, " "); got != "sops --encrypt --input-type dotenv --output-type dotenv --age age1abc "+path {
+		t.Errorf("command = %q", got)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envcrypt.Detect(data) != envcrypt.FormatSOPS {
+		t.Errorf("file was not replaced with ciphertext: %q", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestEnvDecrypt_PrintsPlaintext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env.dev")
+	if err := os.WriteFile(path, []byte("-----BEGIN AGE ENCRYPTED FILE-----\nx\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envcrypt.KeyFileEnv, "/keys/dev.txt")
+
+	stubEnvCrypter(t, &cryptRunner{stdout: "API_KEY=abc\n"})
+
+	root := newTestRootCommand()
+	root.AddCommand(NewEnvCommand())
+
+	out, err := executeCommandForGolden(root, "env", "decrypt", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "API_KEY=abc\n" {
+		t.Errorf("output = %q", out)
+	}
+
+	data, _ := os.ReadFile(path)
+	if envcrypt.Detect(data) != envcrypt.FormatAge {
+		t.Error("decrypt without --in-place must not modify the file")
+	}
+}
@@ -17,21 +17,32 @@ See https://www.gnu.org/licenses/ for license details.
 package commands
 
 import (
-	"fmt"
 	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
 
+	"stagecraft/internal/failure"
 	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
 )
 
 // ResolvedFlags contains the resolved values for all global flags.
 type ResolvedFlags struct {
-	Env     string
-	Config  string
-	Verbose bool
-	DryRun  bool
+	Env       string
+	Config    string
+	Verbose   bool
+	DryRun    bool
+	Output    string
+	LogFormat string
+	LogLevel  string
+
+	// Offline makes commands use cached provider data instead of calling
+	// provider APIs (see CheckOffline).
+	Offline bool
+
+	// ErrorFormat selects how a failed command is reported: text or json.
+	ErrorFormat string
 }
 
 // ResolveFlags resolves global flags with the following precedence:
@@ -57,7 +68,7 @@ func ResolveFlags(cmd *cobra.Command, cfg *config.Config) (*ResolvedFlags, error
 				for name := range cfg.Environments {
 					available = append(available, name)
 				}
-				return nil, fmt.Errorf("invalid environment %q; available environments: %v", flags.Env, available)
+				return nil, failure.Errorf(failure.ClassUserInput, "invalid environment %q; available environments: %v", flags.Env, available)
 			}
 		}
 	}
@@ -86,6 +97,30 @@ func ResolveFlags(cmd *cobra.Command, cfg *config.Config) (*ResolvedFlags, error
 
 	flags.DryRun = resolveBool(dryRunFlag, dryRunEnv, dryRunDefault)
 
+	// Resolve --offline flag
+	offlineFlag, _ := cmd.Flags().GetBool("offline")
+	flags.Offline = resolveBool(offlineFlag, parseBoolEnv(os.Getenv("STAGECRAFT_OFFLINE")), false)
+
+	// Resolve --output flag. Commands that support structured output
+	// validate the value via output.ParseFormat.
+	outputFlag, _ := cmd.Flags().GetString("output")
+	outputEnv := os.Getenv("STAGECRAFT_OUTPUT")
+	outputDefault := "table" // Built-in default
+
+	flags.Output = resolveString(outputFlag, outputEnv, outputDefault)
+
+	// Resolve --log-format and --log-level. Values are validated when the
+	// logger is constructed (see newLogger).
+	logFormatFlag, _ := cmd.Flags().GetString("log-format")
+	flags.LogFormat = resolveString(logFormatFlag, os.Getenv("STAGECRAFT_LOG_FORMAT"), "text")
+
+	logLevelFlag, _ := cmd.Flags().GetString("log-level")
+	flags.LogLevel = resolveString(logLevelFlag, os.Getenv("STAGECRAFT_LOG_LEVEL"), "")
+
+	// Resolve --error-format. main validates it when reporting a failure.
+	errorFormatFlag, _ := cmd.Flags().GetString("error-format")
+	flags.ErrorFormat = resolveString(errorFormatFlag, os.Getenv("STAGECRAFT_ERROR_FORMAT"), "text")
+
 	return flags, nil
 }
 
@@ -123,3 +158,31 @@ func parseBoolEnv(value string) bool {
 	}
 	return parsed
 }
+
+// newLogger builds the command logger from resolved global flags and installs
+// the same options as the process default so that providers log consistently.
+// --verbose lowers the default level to debug unless --log-level sets one.
+func newLogger(flags *ResolvedFlags) (logging.Logger, error) {
+	format, err := logging.ParseFormat(flags.LogFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := logging.LevelInfo
+	if flags.Verbose {
+		fallback = logging.LevelDebug
+	}
+	level, subsystems, err := logging.ParseLevels(flags.LogLevel, fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := logging.Options{
+		Level:           level,
+		SubsystemLevels: subsystems,
+		Format:          format,
+	}
+	logging.SetDefault(opts)
+
+	return logging.New(opts), nil
+}
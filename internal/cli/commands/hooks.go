@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/internal/hooks"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_PHASE_HOOKS
+// Spec: spec/deploy/hooks.md
+
+type phaseFn = func(context.Context, *core.Plan, logging.Logger) error
+
+// newPhaseHookRunner returns a hook runner for the configured hooks with
+// the release context known before the release is created.
+func newPhaseHookRunner(cfg *config.Config, command, env, version, commitSHA string, dryRun bool, logger logging.Logger) *hooks.Runner {
+	return newPhaseHookRunnerWithOptions(cfg, command, env, version, commitSHA, hooks.Options{
+		DryRun: dryRun,
+		Logger: logger.Named(logging.SubsystemEngine),
+		OnRun:  recordHookEvents(logger),
+	})
+}
+
+// recordHookEvents returns a hook observer that adds each hook that ran to
+// its release's timeline (RELEASE_TIMELINE). Failing to record is logged,
+// not fatal.
+func recordHookEvents(logger logging.Logger) func(context.Context, hooks.Execution) {
+	return func(ctx context.Context, exec hooks.Execution) {
+		if exec.ReleaseID == "" {
+			return
+		}
+
+		message := fmt.Sprintf("%s `%s`", exec.Hook, strings.Join(exec.Command, " "))
+		if exec.Host != "" {
+			message += " on " + exec.Host
+		}
+		if exec.Err != nil {
+			message += " failed: " + exec.Err.Error()
+		} else {
+			message += " succeeded"
+		}
+		message += fmt.Sprintf(" (%s)", exec.Duration.Round(time.Millisecond))
+
+		event := state.ReleaseEvent{
+			Type:    state.EventHookExecuted,
+			Phase:   state.ReleasePhase(exec.Phase),
+			Message: message,
+		}
+		if err := state.NewDefaultManager().AddEvent(ctx, exec.ReleaseID, event); err != nil {
+			logger.Debug("Failed to record hook event",
+				logging.NewField("hook", exec.Hook),
+				logging.NewField("error", err.Error()),
+			)
+		}
+	}
+}
+
+// newPhaseHookRunnerWithOptions is newPhaseHookRunner with explicit runner
+// options, e.g. a sandbox runner for `deploy --dry-run --explain`.
+func newPhaseHookRunnerWithOptions(cfg *config.Config, command, env, version, commitSHA string, opts hooks.Options) *hooks.Runner {
+	rc := hooks.ReleaseContext{
+		Project:     cfg.Project.Name,
+		Environment: env,
+		Command:     command,
+		Version:     version,
+		CommitSHA:   commitSHA,
+	}
+	return hooks.NewRunner(cfg.Hooks, rc, opts)
+}
+
+// withPhaseHooks wraps each phase function so that its pre_ hooks run
+// before it and its post_ hooks run after it succeeds. Hook failures fail
+// the phase, so downstream phases are skipped as for any phase error.
+func withPhaseHooks(fns PhaseFns, runner *hooks.Runner) PhaseFns {
+	wrap := func(phase state.ReleasePhase, fn phaseFn) phaseFn {
+		name := string(phase)
+		return func(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
+			if err := runner.Run(ctx, "pre", name); err != nil {
+				return err
+			}
+			if err := fn(ctx, plan, logger); err != nil {
+				return err
+			}
+			return runner.Run(ctx, "post", name)
+		}
+	}
+
+	return PhaseFns{
+		Build:       wrap(state.PhaseBuild, fns.Build),
+		Push:        wrap(state.PhasePush, fns.Push),
+		MigratePre:  wrap(state.PhaseMigratePre, fns.MigratePre),
+		Rollout:     wrap(state.PhaseRollout, fns.Rollout),
+		MigratePost: wrap(state.PhaseMigratePost, fns.MigratePost),
+		Finalize:    wrap(state.PhaseFinalize, fns.Finalize),
+	}
+}
+
+// logPhaseHooks reports every hook a run would execute, in phase order.
+// The runner must be in dry-run mode, where Run only logs.
+func logPhaseHooks(ctx context.Context, runner *hooks.Runner) {
+	for _, phase := range allPhasesCommon() {
+		_ = runner.Run(ctx, "pre", string(phase))
+		_ = runner.Run(ctx, "post", string(phase))
+	}
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core/state"
+	"stagecraft/internal/hooks"
+)
+
+// Feature: DEPLOY_PHASE_HOOKS
+// Spec: spec/deploy/hooks.md
+
+func writeHooksConfig(t *testing.T, dir, hooksYAML string) {
+	t.Helper()
+	configContent := `project:
+  name: test-app
+environments:
+  staging:
+    driver: local
+hooks:
+` + hooksYAML
+	if err := os.WriteFile(filepath.Join(dir, "stagecraft.yml"), []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+}
+
+func TestDeployCommand_RunsPhaseHooks(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	writeHooksConfig(t, env.TempDir, `  pre_rollout:
+    - command: ["sh", "-c", "echo \"$STAGECRAFT_HOOK $STAGECRAFT_RELEASE_ID\" >> hooks.log"]
+  post_finalize:
+    - command: ["sh", "-c", "echo \"$STAGECRAFT_HOOK $STAGECRAFT_RELEASE_VERSION\" >> hooks.log"]
+`)
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0"); err != nil {
+		t.Fatalf("deploy failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(env.TempDir, "hooks.log"))
+	if err != nil {
+		t.Fatalf("reading hook log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 hook runs, got %q", data)
+	}
+	if !strings.HasPrefix(lines[0], "pre_rollout rel-") {
+		t.Errorf("expected pre_rollout with release ID, got %q", lines[0])
+	}
+	if lines[1] != "post_finalize v1.0.0" {
+		t.Errorf("expected post_finalize with version, got %q", lines[1])
+	}
+}
+
+func TestDeployCommand_FailingHookFailsPhase(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	writeHooksConfig(t, env.TempDir, `  pre_rollout:
+    - command: ["sh", "-c", "echo draining failed >&2; exit 4"]
+`)
+
+	err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging")
+
+	var hookErr *hooks.Error
+	if !errors.As(err, &hookErr) {
+		t.Fatalf("expected hook error, got %v", err)
+	}
+	if hookErr.Kind != hooks.FailureExit || hookErr.ExitCode != 4 {
+		t.Errorf("unexpected classification: %+v", hookErr)
+	}
+	if !strings.Contains(err.Error(), `phase "rollout" failed`) || !strings.Contains(err.Error(), "draining failed") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+
+	releases, listErr := env.Manager.ListReleases(context.Background(), "staging")
+	if listErr != nil || len(releases) != 1 {
+		t.Fatalf("expected one release, got %v (err %v)", releases, listErr)
+	}
+	phases := releases[0].Phases
+	if phases[state.PhaseRollout] != state.StatusFailed {
+		t.Errorf("expected rollout failed, got %q", phases[state.PhaseRollout])
+	}
+	if phases[state.PhaseFinalize] != state.StatusSkipped {
+		t.Errorf("expected finalize skipped, got %q", phases[state.PhaseFinalize])
+	}
+}
+
+func TestDeployCommand_DryRunDoesNotRunHooks(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	writeHooksConfig(t, env.TempDir, `  pre_build:
+    - command: ["touch", "hook-ran"]
+`)
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--dry-run"); err != nil {
+		t.Fatalf("dry-run deploy failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(env.TempDir, "hook-ran")); !os.IsNotExist(err) {
+		t.Fatalf("expected hook not to run in dry-run, stat err = %v", err)
+	}
+}
@@ -0,0 +1,367 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cache"
+	"stagecraft/internal/cli/output"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/providers/cloud"
+	"stagecraft/pkg/providers/network"
+)
+
+// Feature: CLI_HOSTS
+// Spec: spec/commands/hosts.md
+
+// defaultHostCacheTTL is how long a cached host inventory is reused before
+// the provider is queried again.
+const defaultHostCacheTTL = cache.HostsTTL
+
+// hostStatusMissing marks a host declared in config that the provider does not report.
+const hostStatusMissing = "missing"
+
+// NewHostsCommand returns the `stagecraft hosts` command group.
+func NewHostsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hosts",
+		Short: "Inspect and provision infrastructure hosts",
+		Long:  "Commands for inspecting, planning, provisioning and importing the hosts of deployment environments",
+	}
+
+	cmd.AddCommand(NewHostsListCommand())
+	cmd.AddCommand(newHostsPlanCommand())
+	cmd.AddCommand(newHostsApplyCommand())
+	cmd.AddCommand(newHostsImportCommand())
+
+	return cmd
+}
+
+// NewHostsListCommand returns `stagecraft hosts list`.
+func NewHostsListCommand() *cobra.Command {
+	var refresh bool
+	var cacheTTL time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List hosts for an environment",
+		Long: `List the hosts declared in config for an environment, merged with live
+provider data (public IP, region, status) and the mesh network FQDN.
+
+The merged inventory is cached in the cache directory (see ` + "`stagecraft cache`" + `).
+Later runs reuse the cache until it is older than --cache-ttl; pass
+--refresh to query the provider.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHostsList(cmd, refresh, cacheTTL)
+		},
+	}
+
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "ignore the cached inventory and query the provider")
+	flagNeedsNetwork(cmd, "refresh")
+	cmd.Flags().DurationVar(&cacheTTL, "cache-ttl", defaultHostCacheTTL, "maximum age of a cached inventory before it is refreshed")
+
+	return cmd
+}
+
+func runHostsList(cmd *cobra.Command, refresh bool, cacheTTL time.Duration) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("hosts list: resolving flags: %w", err)
+	}
+
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		if err == config.ErrConfigNotFound {
+			return fmt.Errorf("hosts list: stagecraft config not found at %s", flags.Config)
+		}
+		return fmt.Errorf("hosts list: failed to load config: %w", err)
+	}
+
+	flags, err = ResolveFlags(cmd, cfg)
+	if err != nil {
+		return fmt.Errorf("hosts list: resolving flags: %w", err)
+	}
+
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	inventory, err := resolveHostInventory(ctx, cfg, flags.Env, cache.Default(), hostInventoryOptions{
+		Refresh:  refresh,
+		CacheTTL: cacheTTL,
+		Offline:  flags.Offline,
+	})
+	if err != nil {
+		return fmt.Errorf("hosts list: %w", err)
+	}
+
+	view := newHostsListView(flags.Env, inventory)
+	return output.Render(cmd.OutOrStdout(), format, view, func(out io.Writer) error {
+		return displayHostsList(out, inventory)
+	})
+}
+
+// hostInventoryOptions controls how resolveHostInventory uses the cache.
+type hostInventoryOptions struct {
+	// Refresh skips the cache and always queries the provider.
+	Refresh bool
+
+	// CacheTTL is the maximum age of a reusable cache; zero disables the cache.
+	CacheTTL time.Duration
+
+	// Offline returns the cache regardless of its age and fails without one.
+	Offline bool
+}
+
+// hostInventory is an environment's merged host list.
+type hostInventory struct {
+	FetchedAt time.Time
+	Cached    bool
+	Hosts     []cachedHost
+}
+
+// cachedHost is a single host of a cached inventory.
+type cachedHost struct {
+	Name     string `json:"name"`
+	Role     string `json:"role,omitempty"`
+	ID       string `json:"id,omitempty"`
+	PublicIP string `json:"public_ip,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Size     string `json:"size,omitempty"`
+	Status   string `json:"status"`
+	FQDN     string `json:"fqdn,omitempty"`
+}
+
+// hostCacheEntry is a host inventory as stored in the cache directory. The
+// entry's timestamp is when it was fetched.
+type hostCacheEntry struct {
+	Hosts []cachedHost `json:"hosts"`
+}
+
+// resolveHostInventory returns the host inventory for env. A fresh cache is
+// returned as-is; otherwise desired hosts from config are merged with live
+// provider data and the result is written back to the cache.
+func resolveHostInventory(ctx context.Context, cfg *config.Config, env string, hostCache *cache.Cache, opts hostInventoryOptions) (*hostInventory, error) {
+	key := cfg.Project.Name + "/" + env
+
+	if opts.Offline || !opts.Refresh {
+		entry, err := hostCache.Get(cache.BucketHosts, key)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case opts.Offline && entry == nil:
+			return nil, fmt.Errorf("no cached host inventory for %q; fetching it %w", env, cloud.ErrOffline)
+		case opts.Offline || entry.Fresh(time.Now(), opts.CacheTTL):
+			var stored hostCacheEntry
+			if err := entry.Decode(&stored); err != nil {
+				return nil, fmt.Errorf("reading cached host inventory (clear it with `stagecraft cache clear hosts`): %w", err)
+			}
+			return &hostInventory{FetchedAt: entry.StoredAt, Cached: true, Hosts: stored.Hosts}, nil
+		}
+	}
+
+	if cfg.Cloud == nil || cfg.Cloud.Provider == "" {
+		return nil, fmt.Errorf("cloud provider is not configured")
+	}
+
+	providerID := cfg.Cloud.Provider
+	provider, err := cloud.Get(providerID)
+	if err != nil {
+		return nil, fmt.Errorf("cloud provider %q not found: %w", providerID, err)
+	}
+
+	var providerCfg any
+	if cfg.Cloud.Providers != nil {
+		providerCfg = cfg.Cloud.Providers[providerID]
+	}
+	hostsOpts := cloud.HostsOptions{Config: providerCfg, Environment: env, Project: cfg.Project.Name}
+
+	var desired []cloud.HostSpec
+	if desiredProvider, ok := provider.(cloud.DesiredHostsProvider); ok {
+		desired, err = desiredProvider.DesiredHosts(ctx, hostsOpts)
+		if err != nil {
+			return nil, fmt.Errorf("reading desired hosts: %w", err)
+		}
+	}
+
+	fetchedAt := time.Now()
+	live, err := provider.Hosts(ctx, hostsOpts)
+	if err != nil {
+		return nil, fmt.Errorf("listing hosts: %w", err)
+	}
+
+	hosts := mergeHostInventory(desired, live)
+
+	if err := resolveHostFQDNs(cfg, hosts); err != nil {
+		return nil, err
+	}
+
+	if err := hostCache.PutJSON(cache.BucketHosts, key, hostCacheEntry{Hosts: hosts}); err != nil {
+		return nil, fmt.Errorf("caching host inventory: %w", err)
+	}
+	return &hostInventory{FetchedAt: fetchedAt, Hosts: hosts}, nil
+}
+
+// mergeHostInventory combines desired hosts with live provider hosts, sorted
+// by name. Desired hosts the provider does not report are marked missing;
+// live hosts absent from config are kept so drift is visible.
+func mergeHostInventory(desired []cloud.HostSpec, live []cloud.Host) []cachedHost {
+	byName := make(map[string]*cachedHost, len(desired)+len(live))
+
+	for _, spec := range desired {
+		byName[spec.Name] = &cachedHost{
+			Name:   spec.Name,
+			Role:   spec.Role,
+			Region: spec.Region,
+			Status: hostStatusMissing,
+		}
+	}
+
+	for _, h := range live {
+		entry, ok := byName[h.Name]
+		if !ok {
+			entry = &cachedHost{Name: h.Name}
+			byName[h.Name] = entry
+		}
+		if h.Role != "" {
+			entry.Role = h.Role
+		}
+		if h.Region != "" {
+			entry.Region = h.Region
+		}
+		entry.ID = h.ID
+		entry.PublicIP = h.PublicIP
+		entry.Size = h.Size
+		entry.Status = h.Status
+	}
+
+	hosts := make([]cachedHost, 0, len(byName))
+	for _, entry := range byName {
+		hosts = append(hosts, *entry)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Name < hosts[j].Name
+	})
+	return hosts
+}
+
+// resolveHostFQDNs fills in mesh FQDNs for hosts the provider reports, when
+// the configured network provider can derive them from config alone.
+func resolveHostFQDNs(cfg *config.Config, hosts []cachedHost) error {
+	if cfg.Network == nil || cfg.Network.Provider == "" {
+		return nil
+	}
+
+	provider, err := network.Get(cfg.Network.Provider)
+	if err != nil {
+		return fmt.Errorf("network provider %q not found: %w", cfg.Network.Provider, err)
+	}
+	resolver, ok := provider.(network.FQDNResolver)
+	if !ok {
+		return nil
+	}
+
+	var providerCfg any
+	if cfg.Network.Providers != nil {
+		providerCfg = cfg.Network.Providers[cfg.Network.Provider]
+	}
+
+	for i := range hosts {
+		if hosts[i].Status == hostStatusMissing {
+			continue
+		}
+		fqdn, err := resolver.ResolveFQDN(providerCfg, hosts[i].Name)
+		if err != nil {
+			return fmt.Errorf("resolving FQDN for host %q: %w", hosts[i].Name, err)
+		}
+		hosts[i].FQDN = fqdn
+	}
+	return nil
+}
+
+// hostsListView is the structured (--output json|yaml) form of `hosts list`.
+type hostsListView struct {
+	Environment string     `json:"environment" yaml:"environment"`
+	FetchedAt   string     `json:"fetched_at" yaml:"fetched_at"`
+	Cached      bool       `json:"cached" yaml:"cached"`
+	Hosts       []hostView `json:"hosts" yaml:"hosts"`
+}
+
+// hostView is the structured form of a single host.
+type hostView struct {
+	Name     string `json:"name" yaml:"name"`
+	Role     string `json:"role,omitempty" yaml:"role,omitempty"`
+	ID       string `json:"id,omitempty" yaml:"id,omitempty"`
+	PublicIP string `json:"public_ip,omitempty" yaml:"public_ip,omitempty"`
+	Region   string `json:"region,omitempty" yaml:"region,omitempty"`
+	Size     string `json:"size,omitempty" yaml:"size,omitempty"`
+	Status   string `json:"status" yaml:"status"`
+	FQDN     string `json:"fqdn,omitempty" yaml:"fqdn,omitempty"`
+}
+
+func newHostsListView(env string, inventory *hostInventory) hostsListView {
+	view := hostsListView{
+		Environment: env,
+		FetchedAt:   inventory.FetchedAt.UTC().Format(time.RFC3339),
+		Cached:      inventory.Cached,
+		Hosts:       make([]hostView, 0, len(inventory.Hosts)),
+	}
+	for _, h := range inventory.Hosts {
+		view.Hosts = append(view.Hosts, hostView(h))
+	}
+	return view
+}
+
+// displayHostsList displays the host inventory in table format.
+func displayHostsList(out io.Writer, inventory *hostInventory) error {
+	if len(inventory.Hosts) == 0 {
+		_, _ = fmt.Fprintf(out, "No hosts found\n")
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "%-16s %-10s %-10s %-16s %-8s %s\n", "NAME", "ROLE", "STATUS", "PUBLIC IP", "REGION", "FQDN")
+	for _, h := range inventory.Hosts {
+		_, _ = fmt.Fprintf(out, "%-16s %-10s %-10s %-16s %-8s %s\n",
+			h.Name, dashIfEmpty(h.Role), h.Status, dashIfEmpty(h.PublicIP), dashIfEmpty(h.Region), dashIfEmpty(h.FQDN))
+	}
+
+	if inventory.Cached {
+		_, _ = fmt.Fprintf(out, "\nUsing cached inventory from %s (pass --refresh to update)\n", formatTimestamp(inventory.FetchedAt))
+	}
+	return nil
+}
+
+// dashIfEmpty returns "-" for empty table cells.
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
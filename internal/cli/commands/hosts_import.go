@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cache"
+	"stagecraft/internal/cli/output"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_HOSTS_IMPORT
+// Spec: spec/commands/hosts-import.md
+
+// hostsImportOptions are the flags of `hosts import`.
+type hostsImportOptions struct {
+	Droplet string // provider name of the existing host
+	As      string // host name declared in config
+}
+
+// newHostsImportCommand returns `stagecraft hosts import`.
+func newHostsImportCommand() *cobra.Command {
+	var opts hostsImportOptions
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Bring an existing droplet under Stagecraft management",
+		Long: `Record an existing droplet as the host --as declared in config, so
+migrating to Stagecraft does not mean recreating servers. The droplet must
+have the host's size and region, and must not belong to another
+environment or project. The configured SSH key must exist in the account.
+
+The cloud provider renames and tags the droplet as it does the droplets it
+creates; its disk, address and running services are left alone. Run
+` + "`stagecraft infra up`" + ` afterwards to bootstrap it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHostsImport(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Droplet, "droplet", "", "name of the existing droplet to import")
+	cmd.Flags().StringVar(&opts.As, "as", "", "host name in config the droplet becomes (e.g. app-1)")
+	_ = cmd.MarkFlagRequired("droplet")
+	_ = cmd.MarkFlagRequired("as")
+	registerEnvCompletion(cmd)
+
+	return needsNetwork(cmd)
+}
+
+func runHostsImport(cmd *cobra.Command, opts hostsImportOptions) error {
+	ctx := commandContext(cmd)
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("hosts import: resolving flags: %w", err)
+	}
+
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			return fmt.Errorf("hosts import: stagecraft config not found at %s", flags.Config)
+		}
+		return fmt.Errorf("hosts import: failed to load config: %w", err)
+	}
+
+	flags, err = ResolveFlags(cmd, cfg)
+	if err != nil {
+		return fmt.Errorf("hosts import: resolving flags: %w", err)
+	}
+
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Cloud == nil || cfg.Cloud.Provider == "" {
+		return fmt.Errorf("hosts import: cloud provider is not configured")
+	}
+	provider, err := cloud.Get(cfg.Cloud.Provider)
+	if err != nil {
+		return fmt.Errorf("hosts import: cloud provider %q not found: %w", cfg.Cloud.Provider, err)
+	}
+	importer, ok := provider.(cloud.HostImporter)
+	if !ok {
+		return fmt.Errorf("hosts import: cloud provider %q does not support importing hosts", cfg.Cloud.Provider)
+	}
+	var providerCfg any
+	if cfg.Cloud.Providers != nil {
+		providerCfg = cfg.Cloud.Providers[cfg.Cloud.Provider]
+	}
+
+	host, err := importer.ImportHost(ctx, cloud.ImportOptions{
+		Config:      providerCfg,
+		Environment: flags.Env,
+		Project:     cfg.Project.Name,
+		Resource:    opts.Droplet,
+		Host:        opts.As,
+	})
+	recordAudit(ctx, cmd, logger, flags.Env, "", err)
+	if err != nil {
+		return fmt.Errorf("hosts import: %w", err)
+	}
+
+	// The cached inventory does not know the imported host yet
+	if _, err := resolveHostInventory(ctx, cfg, flags.Env, cache.Default(), hostInventoryOptions{Refresh: true}); err != nil {
+		logger.Warn("Failed to refresh cached host inventory; pass --refresh to hosts list",
+			logging.NewField("error", err.Error()),
+		)
+	}
+
+	view := hostsImportView{
+		Environment: flags.Env,
+		Droplet:     opts.Droplet,
+		Host: hostView{
+			Name:     host.Name,
+			Role:     host.Role,
+			ID:       host.ID,
+			PublicIP: host.PublicIP,
+			Region:   host.Region,
+			Status:   host.Status,
+		},
+	}
+	return output.Render(cmd.OutOrStdout(), format, view, func(out io.Writer) error {
+		_, _ = fmt.Fprintf(out, "✓ Imported droplet %q as host %q in %q", opts.Droplet, host.Name, flags.Env)
+		if host.PublicIP != "" {
+			_, _ = fmt.Fprintf(out, " (%s)", host.PublicIP)
+		}
+		_, _ = fmt.Fprintln(out)
+		return nil
+	})
+}
+
+// hostsImportView is the structured (--output json|yaml) form of `hosts import`.
+type hostsImportView struct {
+	Environment string   `json:"environment" yaml:"environment"`
+	Droplet     string   `json:"droplet" yaml:"droplet"`
+	Host        hostView `json:"host" yaml:"host"`
+}
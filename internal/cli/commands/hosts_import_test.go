@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core/audit"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_HOSTS_IMPORT
+// Spec: spec/commands/hosts-import.md
+
+// fakeImportingCloudProvider adds HostImporter to fakeCloudProvider. An
+// imported host is reported by Hosts afterwards.
+type fakeImportingCloudProvider struct {
+	fakeCloudProvider
+
+	imports []cloud.ImportOptions
+}
+
+func (f *fakeImportingCloudProvider) ImportHost(ctx context.Context, opts cloud.ImportOptions) (cloud.Host, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.imports = append(f.imports, opts)
+	host := cloud.Host{ID: "7", Name: opts.Host, Role: "app", PublicIP: "203.0.113.7", Region: "nyc3", Status: "active"}
+	f.hosts = append(f.hosts, host)
+	return host, nil
+}
+
+func TestHostsImportCommand_ImportsAndRefreshesInventory(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-import")
+	fake := &fakeImportingCloudProvider{fakeCloudProvider: fakeCloudProvider{id: "test-cloud-hosts-import"}}
+	cloud.Register(fake)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewHostsCommand())
+
+	out, err := executeCommandForGolden(root, "hosts", "import", "--config", configPath, "--env", "staging",
+		"--droplet", "legacy-web", "--as", "app-1", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fake.imports) != 1 {
+		t.Fatalf("expected one import, got %+v", fake.imports)
+	}
+	got := fake.imports[0]
+	if got.Environment != "staging" || got.Project != "test-project" || got.Resource != "legacy-web" || got.Host != "app-1" {
+		t.Errorf("unexpected import options: %+v", got)
+	}
+
+	var view hostsImportView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if view.Droplet != "legacy-web" || view.Host.Name != "app-1" || view.Host.PublicIP != "203.0.113.7" {
+		t.Errorf("unexpected view: %+v", view)
+	}
+
+	if hosts := readHostCache(t, "staging"); len(hosts) != 1 || hosts[0].Name != "app-1" {
+		t.Errorf("cached hosts = %+v, want app-1", hosts)
+	}
+
+	entries, err := audit.NewDefaultLog().List(context.Background(), audit.Filter{})
+	if err != nil {
+		t.Fatalf("listing audit entries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Command != "hosts import" || entries[0].Outcome != audit.OutcomeSuccess || entries[0].Flags["droplet"] != "legacy-web" {
+		t.Errorf("audit entries = %+v, want one successful import", entries)
+	}
+}
+
+func TestHostsImportCommand_RequiresImporter(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-import-unsupported")
+	cloud.Register(&fakeCloudProvider{id: "test-cloud-hosts-import-unsupported"})
+
+	root := newTestRootCommand()
+	root.AddCommand(NewHostsCommand())
+
+	_, err := executeCommandForGolden(root, "hosts", "import", "--config", configPath, "--env", "staging",
+		"--droplet", "legacy-web", "--as", "app-1")
+	if err == nil || !strings.Contains(err.Error(), "does not support importing hosts") {
+		t.Fatalf("expected unsupported provider error, got %v", err)
+	}
+}
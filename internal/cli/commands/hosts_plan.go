@@ -0,0 +1,471 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cache"
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_HOSTS_PLAN
+// Spec: spec/commands/hosts-plan.md
+
+// hostsPlanOptions are the flags shared by `hosts plan` and `hosts apply`.
+type hostsPlanOptions struct {
+	RefreshPricing bool
+	AutoApprove    bool // apply only
+}
+
+// newHostsPlanCommand returns `stagecraft hosts plan`.
+func newHostsPlanCommand() *cobra.Command {
+	var opts hostsPlanOptions
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show the hosts the cloud provider would create and delete",
+		Long: `Ask the configured cloud provider which hosts it would create, delete,
+resize and replace to match config, with the estimated monthly cost change.
+Nothing is changed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			run, err := prepareHostsPlan(cmd, "hosts plan", opts)
+			if err != nil {
+				return err
+			}
+			return run.render(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.RefreshPricing, "refresh-pricing", false, "estimate costs with prices fetched from the cloud provider's API")
+	flagNeedsNetwork(cmd, "refresh-pricing")
+	registerEnvCompletion(cmd)
+
+	return cmd
+}
+
+// newHostsApplyCommand returns `stagecraft hosts apply`.
+func newHostsApplyCommand() *cobra.Command {
+	var opts hostsPlanOptions
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create, delete, resize and replace hosts to match config",
+		Long: `Plan as ` + "`stagecraft hosts plan`" + ` does, then apply the plan through the
+cloud provider. Plans that delete, resize or replace hosts must be
+confirmed interactively or approved with --yes (or --auto-approve).
+Resized hosts restart; replaced hosts lose their address and local disk.
+Hosts are not bootstrapped; use ` + "`stagecraft infra up`" + ` for that.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHostsApply(cmd, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.RefreshPricing, "refresh-pricing", false, "estimate costs with prices fetched from the cloud provider's API")
+	cmd.Flags().BoolVar(&opts.AutoApprove, "auto-approve", false, "apply plans that delete, resize or replace hosts without asking (same as --yes)")
+	addYesFlag(cmd)
+	registerEnvCompletion(cmd)
+
+	return needsNetwork(cmd)
+}
+
+// hostsPlanRun is a planned host change set and what is needed to apply it.
+type hostsPlanRun struct {
+	env         string
+	format      output.Format
+	provider    cloud.CloudProvider
+	providerCfg any
+	plan        cloud.InfraPlan
+	stateMgr    *state.Manager
+
+	// cachedAt is when the host inventory an offline plan used was
+	// fetched; zero for plans made online.
+	cachedAt time.Time
+
+	cfg    *config.Config
+	logger logging.Logger
+}
+
+// prepareHostsPlan loads config and asks the cloud provider for a plan.
+// name prefixes errors, e.g. "hosts plan".
+func prepareHostsPlan(cmd *cobra.Command, name string, opts hostsPlanOptions) (*hostsPlanRun, error) {
+	ctx := commandContext(cmd)
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: resolving flags: %w", name, err)
+	}
+
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			return nil, fmt.Errorf("%s: stagecraft config not found at %s", name, flags.Config)
+		}
+		return nil, fmt.Errorf("%s: failed to load config: %w", name, err)
+	}
+
+	flags, err = ResolveFlags(cmd, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: resolving flags: %w", name, err)
+	}
+
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return nil, err
+	}
+
+	logger, err := newLogger(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Cloud == nil || cfg.Cloud.Provider == "" {
+		return nil, fmt.Errorf("%s: cloud provider is not configured", name)
+	}
+	provider, err := cloud.Get(cfg.Cloud.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("%s: cloud provider %q not found: %w", name, cfg.Cloud.Provider, err)
+	}
+	var providerCfg any
+	if cfg.Cloud.Providers != nil {
+		providerCfg = cfg.Cloud.Providers[cfg.Cloud.Provider]
+	}
+
+	stateMgr := state.NewDefaultManager()
+	reservedIPs, err := recordedReservedIPs(ctx, stateMgr, flags.Env)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	planOpts := cloud.PlanOptions{
+		Config:         providerCfg,
+		Environment:    flags.Env,
+		Project:        cfg.Project.Name,
+		ReservedIPs:    reservedIPs,
+		RefreshPricing: opts.RefreshPricing,
+	}
+
+	run := &hostsPlanRun{
+		env:         flags.Env,
+		format:      format,
+		provider:    provider,
+		providerCfg: providerCfg,
+		stateMgr:    stateMgr,
+		cfg:         cfg,
+		logger:      logger,
+	}
+
+	if flags.Offline {
+		run.plan, run.cachedAt, err = planHostsOffline(ctx, run, planOpts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return run, nil
+	}
+
+	run.plan, err = provider.Plan(ctx, planOpts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: cloud provider plan failed: %w", name, err)
+	}
+	return run, nil
+}
+
+// planHostsOffline plans against the cached host inventory instead of the
+// provider's API. It returns the plan and when the inventory was fetched.
+func planHostsOffline(ctx context.Context, run *hostsPlanRun, opts cloud.PlanOptions) (cloud.InfraPlan, time.Time, error) {
+	planner, ok := run.provider.(cloud.OfflinePlanner)
+	if !ok {
+		return cloud.InfraPlan{}, time.Time{}, fmt.Errorf("cloud provider %q %w", run.provider.ID(), cloud.ErrOffline)
+	}
+
+	inventory, err := resolveHostInventory(ctx, run.cfg, run.env, cache.Default(), hostInventoryOptions{Offline: true})
+	if err != nil {
+		return cloud.InfraPlan{}, time.Time{}, err
+	}
+	for _, h := range inventory.Hosts {
+		if h.Status == hostStatusMissing {
+			continue
+		}
+		opts.Known = append(opts.Known, cloud.Host{
+			ID:     h.ID,
+			Name:   h.Name,
+			Role:   h.Role,
+			Region: h.Region,
+			Size:   h.Size,
+			Status: h.Status,
+		})
+	}
+
+	plan, err := planner.PlanOffline(ctx, opts)
+	if err != nil {
+		return cloud.InfraPlan{}, time.Time{}, fmt.Errorf("cloud provider offline plan failed: %w", err)
+	}
+	return plan, inventory.FetchedAt, nil
+}
+
+func runHostsApply(cmd *cobra.Command, opts hostsPlanOptions) error {
+	ctx := commandContext(cmd)
+
+	run, err := prepareHostsPlan(cmd, "hosts apply", opts)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if err := run.render(out); err != nil {
+		return err
+	}
+
+	if hostChangeCount(&run.plan)+len(run.plan.ReservedIPs)+len(run.plan.Volumes) == 0 {
+		return nil
+	}
+
+	if changes := describeHostChanges(len(run.plan.ToDelete), len(run.plan.ToResize), len(run.plan.ToReplace)); changes != "" {
+		question := fmt.Sprintf("This plan %s in %q. Apply it?", changes, run.env)
+		if err := newConfirmer(cmd, opts.AutoApprove).Confirm(question); err != nil {
+			return fmt.Errorf("hosts apply: %w; plan not applied", err)
+		}
+	}
+
+	err = run.provider.Apply(ctx, cloud.ApplyOptions{
+		Config:      run.providerCfg,
+		Environment: run.env,
+		Project:     run.cfg.Project.Name,
+		Plan:        run.plan,
+	})
+	recordAudit(ctx, cmd, run.logger, run.env, "", err)
+	if err != nil {
+		return fmt.Errorf("hosts apply: cloud provider apply failed: %w", err)
+	}
+
+	// The cached inventory no longer matches the provider
+	if _, err := resolveHostInventory(ctx, run.cfg, run.env, cache.Default(), hostInventoryOptions{Refresh: true}); err != nil {
+		run.logger.Warn("Failed to refresh cached host inventory; pass --refresh to hosts list",
+			logging.NewField("error", err.Error()),
+		)
+	}
+
+	if run.format == output.FormatTable {
+		_, _ = fmt.Fprintf(out, "✓ Applied: %d host(s) created, %d host(s) deleted", len(run.plan.ToCreate), len(run.plan.ToDelete))
+		if n := len(run.plan.ToResize) + len(run.plan.ToReplace); n > 0 {
+			_, _ = fmt.Fprintf(out, ", %d host(s) resized, %d host(s) replaced", len(run.plan.ToResize), len(run.plan.ToReplace))
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+	return nil
+}
+
+// hostChangeCount returns the number of hosts plan creates, deletes,
+// resizes or replaces.
+func hostChangeCount(plan *cloud.InfraPlan) int {
+	return len(plan.ToCreate) + len(plan.ToDelete) + len(plan.ToResize) + len(plan.ToReplace)
+}
+
+// describeHostChanges describes the host changes that need confirmation,
+// e.g. "deletes 1 host(s) and resizes 2 host(s)", or returns "" when there
+// are none.
+func describeHostChanges(deletes, resizes, replaces int) string {
+	var parts []string
+	if deletes > 0 {
+		parts = append(parts, fmt.Sprintf("deletes %d host(s)", deletes))
+	}
+	if resizes > 0 {
+		parts = append(parts, fmt.Sprintf("resizes %d host(s)", resizes))
+	}
+	if replaces > 0 {
+		parts = append(parts, fmt.Sprintf("replaces %d host(s)", replaces))
+	}
+	if len(parts) <= 1 {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// recordedReservedIPs returns the reserved IPs recorded in state for env,
+// keyed by host name, so a replaced host gets its old address back. It
+// returns nil when none are recorded.
+func recordedReservedIPs(ctx context.Context, stateMgr *state.Manager, env string) (map[string]string, error) {
+	recorded, err := stateMgr.ReservedIPs(ctx, env)
+	if err != nil {
+		return nil, fmt.Errorf("reading reserved IPs from state: %w", err)
+	}
+	if len(recorded) == 0 {
+		return nil, nil
+	}
+	ips := make(map[string]string, len(recorded))
+	for _, ip := range recorded {
+		ips[ip.Host] = ip.Address
+	}
+	return ips, nil
+}
+
+// hostsPlanView is the structured (--output json|yaml) form of a host plan.
+type hostsPlanView struct {
+	Environment string            `json:"environment" yaml:"environment"`
+	Provider    string            `json:"provider" yaml:"provider"`
+	Create      []hostSpecView    `json:"create" yaml:"create"`
+	Delete      []hostSpecView    `json:"delete" yaml:"delete"`
+	Resize      []hostChangeView  `json:"resize" yaml:"resize"`
+	Replace     []hostChangeView  `json:"replace" yaml:"replace"`
+	Cost        *costEstimateView `json:"cost,omitempty" yaml:"cost,omitempty"`
+
+	// CachedAt is set for offline plans, made from the host inventory
+	// cached at that time.
+	CachedAt string `json:"cached_at,omitempty" yaml:"cached_at,omitempty"`
+}
+
+type hostSpecView struct {
+	Name   string `json:"name" yaml:"name"`
+	Role   string `json:"role,omitempty" yaml:"role,omitempty"`
+	Size   string `json:"size,omitempty" yaml:"size,omitempty"`
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+
+	// MonthlyCost is nil when the host has no known price
+	MonthlyCost *float64 `json:"monthly_cost,omitempty" yaml:"monthly_cost,omitempty"`
+}
+
+// hostChangeView is a resized or replaced host: its desired spec and the
+// live size and region it changes from.
+type hostChangeView struct {
+	Name       string `json:"name" yaml:"name"`
+	Role       string `json:"role,omitempty" yaml:"role,omitempty"`
+	Size       string `json:"size" yaml:"size"`
+	Region     string `json:"region" yaml:"region"`
+	FromSize   string `json:"from_size" yaml:"from_size"`
+	FromRegion string `json:"from_region" yaml:"from_region"`
+}
+
+type costEstimateView struct {
+	Currency     string  `json:"currency" yaml:"currency"`
+	Source       string  `json:"source" yaml:"source"`
+	MonthlyDelta float64 `json:"monthly_delta" yaml:"monthly_delta"`
+	Complete     bool    `json:"complete" yaml:"complete"`
+}
+
+func newHostsPlanView(env, provider string, plan *cloud.InfraPlan) hostsPlanView {
+	view := hostsPlanView{
+		Environment: env,
+		Provider:    provider,
+		Create:      hostSpecViews(plan.ToCreate, nil),
+		Delete:      hostSpecViews(plan.ToDelete, nil),
+		Resize:      hostChangeViews(plan.ToResize),
+		Replace:     hostChangeViews(plan.ToReplace),
+	}
+	if plan.Cost != nil {
+		view.Create = hostSpecViews(plan.ToCreate, plan.Cost.Create)
+		view.Delete = hostSpecViews(plan.ToDelete, plan.Cost.Delete)
+		view.Cost = &costEstimateView{
+			Currency:     plan.Cost.Currency,
+			Source:       plan.Cost.Source,
+			MonthlyDelta: plan.Cost.MonthlyDelta(),
+			Complete:     plan.Cost.Complete(),
+		}
+	}
+	return view
+}
+
+// hostSpecViews converts specs, adding prices from costs, which is either
+// nil or priced in the same order.
+func hostSpecViews(specs []cloud.HostSpec, costs []cloud.HostCost) []hostSpecView {
+	views := make([]hostSpecView, 0, len(specs))
+	for i, spec := range specs {
+		v := hostSpecView{Name: spec.Name, Role: spec.Role, Size: spec.Size, Region: spec.Region}
+		if i < len(costs) && costs[i].Known {
+			price := costs[i].Monthly
+			v.MonthlyCost = &price
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+func hostChangeViews(changes []cloud.HostChange) []hostChangeView {
+	views := make([]hostChangeView, 0, len(changes))
+	for _, c := range changes {
+		views = append(views, hostChangeView{
+			Name:       c.Desired.Name,
+			Role:       c.Desired.Role,
+			Size:       c.Desired.Size,
+			Region:     c.Desired.Region,
+			FromSize:   c.Current.Size,
+			FromRegion: c.Current.Region,
+		})
+	}
+	return views
+}
+
+// render writes the plan in the run's output format.
+func (r *hostsPlanRun) render(w io.Writer) error {
+	view := newHostsPlanView(r.env, r.provider.ID(), &r.plan)
+	if !r.cachedAt.IsZero() {
+		view.CachedAt = r.cachedAt.UTC().Format(time.RFC3339)
+	}
+	return output.Render(w, r.format, view, func(w io.Writer) error {
+		displayHostsPlan(w, view, r.plan.Cost)
+		return nil
+	})
+}
+
+// displayHostsPlan displays a host plan in table format.
+func displayHostsPlan(w io.Writer, view hostsPlanView, cost *cloud.CostEstimate) {
+	if view.CachedAt != "" {
+		_, _ = fmt.Fprintf(w, "Planned offline from the host inventory cached at %s\n", view.CachedAt)
+	}
+	if len(view.Create)+len(view.Delete)+len(view.Resize)+len(view.Replace) == 0 {
+		_, _ = fmt.Fprintf(w, "No host changes for environment %q\n", view.Environment)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "Host plan for environment %q (%s):\n", view.Environment, view.Provider)
+	_, _ = fmt.Fprintf(w, "%-8s %-16s %-10s %-16s %s\n", "ACTION", "NAME", "ROLE", "SIZE", "REGION")
+	for _, h := range view.Create {
+		_, _ = fmt.Fprintf(w, "%-8s %-16s %-10s %-16s %s\n", "create", h.Name, dashIfEmpty(h.Role), dashIfEmpty(h.Size), dashIfEmpty(h.Region))
+	}
+	for _, h := range view.Delete {
+		_, _ = fmt.Fprintf(w, "%-8s %-16s %-10s %-16s %s\n", "delete", h.Name, dashIfEmpty(h.Role), dashIfEmpty(h.Size), dashIfEmpty(h.Region))
+	}
+	for _, h := range view.Resize {
+		_, _ = fmt.Fprintf(w, "%-8s %-16s %-10s %-16s %s\n", "resize", h.Name, dashIfEmpty(h.Role), changedValue(h.FromSize, h.Size), h.Region)
+	}
+	for _, h := range view.Replace {
+		_, _ = fmt.Fprintf(w, "%-8s %-16s %-10s %-16s %s\n", "replace", h.Name, dashIfEmpty(h.Role), changedValue(h.FromSize, h.Size), changedValue(h.FromRegion, h.Region))
+	}
+
+	if cost != nil {
+		_, _ = fmt.Fprintln(w)
+		printCostEstimate(w, cost)
+	}
+}
+
+// changedValue shows a value changing from one to the other, or the value
+// when it does not change.
+func changedValue(from, to string) string {
+	if from == to {
+		return to
+	}
+	return from + " -> " + to
+}
@@ -0,0 +1,337 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core/audit"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_HOSTS_PLAN
+// Spec: spec/commands/hosts-plan.md
+
+// fakePlanningCloudProvider returns a fixed plan and records applied plans.
+type fakePlanningCloudProvider struct {
+	fakeCloudProvider
+
+	plan        cloud.InfraPlan
+	planOptions cloud.PlanOptions
+	applied     []cloud.InfraPlan
+}
+
+//nolint:gocritic // hugeParam: opts matches CloudProvider interface signature
+func (f *fakePlanningCloudProvider) Plan(ctx context.Context, opts cloud.PlanOptions) (cloud.InfraPlan, error) {
+	f.planOptions = opts
+	return f.plan, f.planErr
+}
+
+//nolint:gocritic // hugeParam: opts matches CloudProvider interface signature
+func (f *fakePlanningCloudProvider) Apply(ctx context.Context, opts cloud.ApplyOptions) error {
+	f.applied = append(f.applied, opts.Plan)
+	return f.applyErr
+}
+
+// newFakePlanningProvider registers a provider planning to create app-2
+// and, when withDelete is set, delete old-1.
+func newFakePlanningProvider(id string, withDelete bool) *fakePlanningCloudProvider {
+	plan := cloud.InfraPlan{
+		ToCreate: []cloud.HostSpec{{Name: "app-2", Role: "app", Size: "s-2vcpu-4gb", Region: "fra1"}},
+	}
+	if withDelete {
+		plan.ToDelete = []cloud.HostSpec{{Name: "old-1", Size: "s-1vcpu-1gb", Region: "fra1"}}
+	}
+	plan.Cost = cloud.PricingTable{
+		Currency: "USD",
+		Source:   "test prices",
+		Sizes: map[string]cloud.SizePrice{
+			"s-1vcpu-1gb": {Monthly: 6},
+			"s-2vcpu-4gb": {Monthly: 24},
+		},
+	}.Estimate(plan)
+
+	fake := &fakePlanningCloudProvider{fakeCloudProvider: fakeCloudProvider{id: id}, plan: plan}
+	cloud.Register(fake)
+	return fake
+}
+
+func executeHosts(stdin string, args ...string) (string, error) {
+	root := newTestRootCommand()
+	root.AddCommand(NewHostsCommand())
+	root.SetIn(strings.NewReader(stdin))
+	return executeCommandForGolden(root, append([]string{"hosts"}, args...)...)
+}
+
+func TestHostsPlanCommand_Table(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-plan-table")
+	fake := newFakePlanningProvider("test-cloud-hosts-plan-table", true)
+
+	out, err := executeHosts("", "plan", "--config", configPath, "--env", "staging", "--refresh-pricing")
+	if err != nil {
+		t.Fatalf("hosts plan: %v", err)
+	}
+
+	want := `Host plan for environment "staging" (test-cloud-hosts-plan-table):
+ACTION   NAME             ROLE       SIZE             REGION
+create   app-2            app        s-2vcpu-4gb      fra1
+delete   old-1            -          s-1vcpu-1gb      fra1
+
+Estimated monthly cost (test prices):
+  + app-2  s-2vcpu-4gb  fra1  +24.00 USD
+  - old-1  s-1vcpu-1gb  fra1  -6.00 USD
+  Net change: +18.00 USD/month
+`
+	if out != want {
+		t.Errorf("output =\n%s\nwant:\n%s", out, want)
+	}
+	if !fake.planOptions.RefreshPricing || fake.planOptions.Environment != "staging" {
+		t.Errorf("plan options = %+v", fake.planOptions)
+	}
+	if len(fake.applied) != 0 {
+		t.Errorf("hosts plan applied %d plan(s)", len(fake.applied))
+	}
+}
+
+func TestHostsPlanCommand_JSON(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-plan-json")
+	newFakePlanningProvider("test-cloud-hosts-plan-json", true)
+
+	out, err := executeHosts("", "plan", "--config", configPath, "--env", "staging", "--output", "json")
+	if err != nil {
+		t.Fatalf("hosts plan: %v", err)
+	}
+
+	var view hostsPlanView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(view.Create) != 1 || view.Create[0].Name != "app-2" || view.Create[0].MonthlyCost == nil || *view.Create[0].MonthlyCost != 24 {
+		t.Errorf("create = %+v", view.Create)
+	}
+	if len(view.Delete) != 1 || view.Delete[0].Name != "old-1" {
+		t.Errorf("delete = %+v", view.Delete)
+	}
+	if view.Cost == nil || view.Cost.MonthlyDelta != 18 || !view.Cost.Complete || view.Cost.Currency != "USD" {
+		t.Errorf("cost = %+v", view.Cost)
+	}
+}
+
+func TestHostsPlanCommand_NoChanges(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-plan-empty")
+	cloud.Register(&fakePlanningCloudProvider{fakeCloudProvider: fakeCloudProvider{id: "test-cloud-hosts-plan-empty"}})
+
+	out, err := executeHosts("", "apply", "--config", configPath, "--env", "staging")
+	if err != nil {
+		t.Fatalf("hosts apply: %v", err)
+	}
+	if out != "No host changes for environment \"staging\"\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestHostsApplyCommand_CreationsNeedNoConfirmation(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-apply-create")
+	fake := newFakePlanningProvider("test-cloud-hosts-apply-create", false)
+
+	out, err := executeHosts("", "apply", "--config", configPath, "--env", "staging")
+	if err != nil {
+		t.Fatalf("hosts apply: %v", err)
+	}
+	if len(fake.applied) != 1 || len(fake.applied[0].ToCreate) != 1 {
+		t.Fatalf("applied = %+v, want the planned creation", fake.applied)
+	}
+	if !strings.Contains(out, "✓ Applied: 1 host(s) created, 0 host(s) deleted") {
+		t.Errorf("output missing summary:\n%s", out)
+	}
+	if !fake.hostsCalled {
+		t.Error("expected the host inventory cache to be refreshed after apply")
+	}
+}
+
+func TestHostsApplyCommand_RecordsAuditEntry(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-apply-audit")
+	fake := newFakePlanningProvider("test-cloud-hosts-apply-audit", false)
+	t.Setenv("STAGECRAFT_ACTOR", "alice")
+
+	if _, err := executeHosts("", "apply", "--config", configPath, "--env", "staging"); err != nil {
+		t.Fatalf("hosts apply: %v", err)
+	}
+	fake.applyErr = errors.New("api down")
+	if _, err := executeHosts("", "apply", "--config", configPath, "--env", "staging"); err == nil {
+		t.Fatal("expected the failed apply to fail")
+	}
+
+	entries, err := audit.NewDefaultLog().List(context.Background(), audit.Filter{})
+	if err != nil {
+		t.Fatalf("listing audit entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(entries))
+	}
+	for i, want := range []audit.Outcome{audit.OutcomeSuccess, audit.OutcomeFailure} {
+		e := entries[i]
+		if e.Actor != "alice" || e.Command != "hosts apply" || e.Environment != "staging" || e.Outcome != want {
+			t.Errorf("entry %d = %+v, want %s", i, e, want)
+		}
+	}
+	if !strings.Contains(entries[1].Error, "api down") {
+		t.Errorf("failure entry error = %q", entries[1].Error)
+	}
+}
+
+func TestHostsApplyCommand_DeletionsRequireConfirmation(t *testing.T) {
+	tests := []struct {
+		name      string
+		stdin     string
+		args      []string
+		wantApply bool
+	}{
+		{name: "declined", stdin: "no\n"},
+		{name: "no input", stdin: ""},
+		{name: "confirmed", stdin: "yes\n", wantApply: true},
+		{name: "confirmed with y", stdin: "y\n", wantApply: true},
+		{name: "auto-approve", args: []string{"--auto-approve"}, wantApply: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answerPrompts(t)
+			id := "test-cloud-hosts-apply-delete-" + strings.ReplaceAll(tt.name, " ", "-")
+			configPath := writeHostsConfig(t, id)
+			fake := newFakePlanningProvider(id, true)
+
+			args := append([]string{"apply", "--config", configPath, "--env", "staging"}, tt.args...)
+			out, err := executeHosts(tt.stdin, args...)
+
+			if tt.wantApply {
+				if err != nil {
+					t.Fatalf("hosts apply: %v\n%s", err, out)
+				}
+				if len(fake.applied) != 1 || len(fake.applied[0].ToDelete) != 1 {
+					t.Errorf("applied = %+v, want the planned deletion", fake.applied)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), "confirmation declined; plan not applied") {
+				t.Fatalf("expected confirmation error, got %v", err)
+			}
+			if len(fake.applied) != 0 {
+				t.Errorf("declined plan was applied: %+v", fake.applied)
+			}
+			if !strings.Contains(out, `This plan deletes 1 host(s) in "staging". Apply it? [y/N]:`) {
+				t.Errorf("output missing prompt:\n%s", out)
+			}
+		})
+	}
+}
+
+// newFakeChangingProvider registers a provider planning to resize app-1 and
+// replace web-1.
+func newFakeChangingProvider(id string) *fakePlanningCloudProvider {
+	plan := cloud.InfraPlan{
+		ToResize: []cloud.HostChange{{
+			Desired: cloud.HostSpec{Name: "app-1", Role: "app", Size: "s-4vcpu-8gb", Region: "fra1"},
+			Current: cloud.HostSpec{Name: "app-1", Role: "app", Size: "s-2vcpu-4gb", Region: "fra1"},
+		}},
+		ToReplace: []cloud.HostChange{{
+			Desired: cloud.HostSpec{Name: "web-1", Role: "web", Size: "s-2vcpu-4gb", Region: "fra1"},
+			Current: cloud.HostSpec{Name: "web-1", Role: "web", Size: "s-2vcpu-4gb", Region: "nyc1"},
+		}},
+	}
+	fake := &fakePlanningCloudProvider{fakeCloudProvider: fakeCloudProvider{id: id}, plan: plan}
+	cloud.Register(fake)
+	return fake
+}
+
+func TestHostsPlanCommand_ResizeAndReplace(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-plan-changes")
+	newFakeChangingProvider("test-cloud-hosts-plan-changes")
+
+	out, err := executeHosts("", "plan", "--config", configPath, "--env", "staging")
+	if err != nil {
+		t.Fatalf("hosts plan: %v", err)
+	}
+	want := `Host plan for environment "staging" (test-cloud-hosts-plan-changes):
+ACTION   NAME             ROLE       SIZE             REGION
+resize   app-1            app        s-2vcpu-4gb -> s-4vcpu-8gb fra1
+replace  web-1            web        s-2vcpu-4gb      nyc1 -> fra1
+`
+	if out != want {
+		t.Errorf("output =\n%s\nwant:\n%s", out, want)
+	}
+
+	out, err = executeHosts("", "plan", "--config", configPath, "--env", "staging", "--output", "json")
+	if err != nil {
+		t.Fatalf("hosts plan: %v", err)
+	}
+	var view hostsPlanView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	wantResize := hostChangeView{Name: "app-1", Role: "app", Size: "s-4vcpu-8gb", Region: "fra1", FromSize: "s-2vcpu-4gb", FromRegion: "fra1"}
+	if len(view.Resize) != 1 || view.Resize[0] != wantResize {
+		t.Errorf("resize = %+v, want [%+v]", view.Resize, wantResize)
+	}
+	if len(view.Replace) != 1 || view.Replace[0].FromRegion != "nyc1" || view.Replace[0].Region != "fra1" {
+		t.Errorf("replace = %+v", view.Replace)
+	}
+}
+
+func TestHostsApplyCommand_ResizeAndReplaceRequireConfirmation(t *testing.T) {
+	answerPrompts(t)
+	configPath := writeHostsConfig(t, "test-cloud-hosts-apply-changes")
+	fake := newFakeChangingProvider("test-cloud-hosts-apply-changes")
+
+	out, err := executeHosts("n\n", "apply", "--config", configPath, "--env", "staging")
+	if err == nil || len(fake.applied) != 0 {
+		t.Fatalf("declined apply: err = %v, applied = %d", err, len(fake.applied))
+	}
+	if !strings.Contains(out, `This plan resizes 1 host(s) and replaces 1 host(s) in "staging". Apply it? [y/N]:`) {
+		t.Errorf("output missing prompt:\n%s", out)
+	}
+
+	out, err = executeHosts("", "apply", "--config", configPath, "--env", "staging", "--yes")
+	if err != nil {
+		t.Fatalf("hosts apply --yes: %v", err)
+	}
+	if len(fake.applied) != 1 || len(fake.applied[0].ToResize) != 1 || len(fake.applied[0].ToReplace) != 1 {
+		t.Errorf("applied = %+v, want the planned resize and replace", fake.applied)
+	}
+	if !strings.Contains(out, "✓ Applied: 0 host(s) created, 0 host(s) deleted, 1 host(s) resized, 1 host(s) replaced") {
+		t.Errorf("output missing summary:\n%s", out)
+	}
+}
+
+func TestDescribeHostChanges(t *testing.T) {
+	tests := []struct {
+		deletes, resizes, replaces int
+		want                       string
+	}{
+		{0, 0, 0, ""},
+		{2, 0, 0, "deletes 2 host(s)"},
+		{0, 1, 1, "resizes 1 host(s) and replaces 1 host(s)"},
+		{1, 2, 3, "deletes 1 host(s), resizes 2 host(s) and replaces 3 host(s)"},
+	}
+	for _, tt := range tests {
+		if got := describeHostChanges(tt.deletes, tt.resizes, tt.replaces); got != tt.want {
+			t.Errorf("describeHostChanges(%d, %d, %d) = %q, want %q", tt.deletes, tt.resizes, tt.replaces, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/cache"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_HOSTS
+// Spec: spec/commands/hosts.md
+
+// fakeDesiredHostsCloudProvider adds DesiredHostsProvider to fakeCloudProvider.
+type fakeDesiredHostsCloudProvider struct {
+	fakeCloudProvider
+
+	desired    []cloud.HostSpec
+	hostsCalls int
+}
+
+func (f *fakeDesiredHostsCloudProvider) DesiredHosts(ctx context.Context, opts cloud.HostsOptions) ([]cloud.HostSpec, error) {
+	return f.desired, nil
+}
+
+func (f *fakeDesiredHostsCloudProvider) Hosts(ctx context.Context, opts cloud.HostsOptions) ([]cloud.Host, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hostsCalls++
+	return f.hosts, f.hostsErr
+}
+
+func writeHostsConfig(t *testing.T, providerID string) string {
+	t.Helper()
+	setupIsolatedStateTestEnv(t)
+
+	configContent := `project:
+  name: test-project
+cloud:
+  provider: ` + providerID + `
+  providers:
+    ` + providerID + `: {}
+network:
+  provider: tailscale
+  providers:
+    tailscale:
+      auth_key_env: TS_AUTHKEY
+      tailnet_domain: example.ts.net
+environments:
+  staging:
+    driver: docker
+`
+	configPath := filepath.Join(t.TempDir(), "stagecraft.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return configPath
+}
+
+// seedHostCache caches hosts as the inventory of test-project's env.
+func seedHostCache(t *testing.T, env string, hosts []cachedHost) {
+	t.Helper()
+	if err := cache.Default().PutJSON(cache.BucketHosts, "test-project/"+env, hostCacheEntry{Hosts: hosts}); err != nil {
+		t.Fatalf("seeding host cache: %v", err)
+	}
+}
+
+// readHostCache returns the inventory cached for test-project's env, or nil
+// when there is none.
+func readHostCache(t *testing.T, env string) []cachedHost {
+	t.Helper()
+	entry, err := cache.Default().Get(cache.BucketHosts, "test-project/"+env)
+	if err != nil || entry == nil {
+		t.Fatalf("reading host cache: %v, %v", entry, err)
+	}
+	var stored hostCacheEntry
+	if err := entry.Decode(&stored); err != nil {
+		t.Fatalf("reading host cache: %v", err)
+	}
+	return stored.Hosts
+}
+
+func newFakeDesiredHostsProvider(id string) *fakeDesiredHostsCloudProvider {
+	fake := &fakeDesiredHostsCloudProvider{
+		fakeCloudProvider: fakeCloudProvider{
+			id: id,
+			hosts: []cloud.Host{
+				{ID: "101", Name: "app-1", Role: "app", PublicIP: "203.0.113.10", Region: "nyc3", Status: "active"},
+				{ID: "102", Name: "stray-1", PublicIP: "203.0.113.11", Region: "nyc3", Status: "active"},
+			},
+		},
+		desired: []cloud.HostSpec{
+			{Name: "app-1", Role: "app", Region: "nyc3"},
+			{Name: "db-1", Role: "db", Region: "nyc3"},
+		},
+	}
+	cloud.Register(fake)
+	return fake
+}
+
+func TestHostsListCommand_MergesDesiredAndLiveHosts(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-merge")
+	newFakeDesiredHostsProvider("test-cloud-hosts-merge")
+
+	root := newTestRootCommand()
+	root.AddCommand(NewHostsCommand())
+
+	out, err := executeCommandForGolden(root, "hosts", "list", "--config", configPath, "--env", "staging", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var view hostsListView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if view.Environment != "staging" || view.Cached {
+		t.Errorf("unexpected view header: %+v", view)
+	}
+	if len(view.Hosts) != 3 {
+		t.Fatalf("expected 3 hosts, got %+v", view.Hosts)
+	}
+
+	app, db, stray := view.Hosts[0], view.Hosts[1], view.Hosts[2]
+	if app.Name != "app-1" || app.PublicIP != "203.0.113.10" || app.Status != "active" || app.FQDN != "app-1.example.ts.net" {
+		t.Errorf("unexpected app-1 entry: %+v", app)
+	}
+	if db.Name != "db-1" || db.Status != hostStatusMissing || db.Role != "db" || db.FQDN != "" {
+		t.Errorf("expected db-1 to be missing, got %+v", db)
+	}
+	if stray.Name != "stray-1" || stray.Status != "active" {
+		t.Errorf("expected undeclared live host to be listed, got %+v", stray)
+	}
+}
+
+func TestHostsListCommand_UsesCacheUntilRefresh(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-cache")
+	fake := newFakeDesiredHostsProvider("test-cloud-hosts-cache")
+
+	root := newTestRootCommand()
+	root.AddCommand(NewHostsCommand())
+
+	if _, err := executeCommandForGolden(root, "hosts", "list", "--config", configPath, "--env", "staging"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := executeCommandForGolden(root, "hosts", "list", "--config", configPath, "--env", "staging")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fake.hostsCalls != 1 {
+		t.Errorf("expected cached run not to call Hosts, got %d calls", fake.hostsCalls)
+	}
+	if !strings.Contains(out, "Using cached inventory") || !strings.Contains(out, "app-1.example.ts.net") {
+		t.Errorf("expected cached table output, got:\n%s", out)
+	}
+
+	if _, err := executeCommandForGolden(root, "hosts", "list", "--config", configPath, "--env", "staging", "--refresh"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fake.hostsCalls != 2 {
+		t.Errorf("expected --refresh to call Hosts, got %d calls", fake.hostsCalls)
+	}
+
+	if hosts := readHostCache(t, "staging"); len(hosts) != 3 {
+		t.Fatalf("expected inventory cached, got %+v", hosts)
+	}
+}
+
+func TestHostsListCommand_CloudProviderNotConfigured(t *testing.T) {
+	setupIsolatedStateTestEnv(t)
+
+	configPath := filepath.Join(t.TempDir(), "stagecraft.yml")
+	configContent := `project:
+  name: test-project
+environments:
+  staging:
+    driver: docker
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewHostsCommand())
+
+	_, err := executeCommandForGolden(root, "hosts", "list", "--config", configPath, "--env", "staging")
+	if err == nil || !strings.Contains(err.Error(), "cloud provider is not configured") {
+		t.Fatalf("expected cloud provider error, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: PROVIDER_CLOUD_COST_ESTIMATE
+// Spec: spec/providers/cloud/cost-estimate.md
+
+// printCostEstimate writes the estimated monthly cost of a plan's host
+// creations and deletions. It writes nothing when the provider gave no
+// estimate or the plan creates and deletes no hosts.
+func printCostEstimate(w io.Writer, est *cloud.CostEstimate) {
+	if est == nil || len(est.Create)+len(est.Delete) == 0 {
+		return
+	}
+
+	type row struct {
+		sign string
+		cost cloud.HostCost
+	}
+	var rows []row
+	for _, c := range est.Create {
+		rows = append(rows, row{"+", c})
+	}
+	for _, c := range est.Delete {
+		rows = append(rows, row{"-", c})
+	}
+
+	nameWidth, sizeWidth, regionWidth := 0, 0, 0
+	for _, r := range rows {
+		nameWidth = max(nameWidth, len(r.cost.Name))
+		sizeWidth = max(sizeWidth, len(orNone(r.cost.Size)))
+		regionWidth = max(regionWidth, len(r.cost.Region))
+	}
+
+	_, _ = fmt.Fprintf(w, "Estimated monthly cost (%s):\n", est.Source)
+	unknown := 0
+	for _, r := range rows {
+		amount := "price unknown"
+		if r.cost.Known {
+			amount = formatCost(r.sign, r.cost.Monthly, est.Currency)
+		} else {
+			unknown++
+		}
+		_, _ = fmt.Fprintf(w, "  %s %-*s  %-*s  %-*s  %s\n",
+			r.sign, nameWidth, r.cost.Name, sizeWidth, orNone(r.cost.Size), regionWidth, r.cost.Region, amount)
+	}
+
+	delta := est.MonthlyDelta()
+	sign := "+"
+	if delta < 0 {
+		sign, delta = "-", -delta
+	}
+	_, _ = fmt.Fprintf(w, "  Net change: %s/month\n", formatCost(sign, delta, est.Currency))
+	if unknown > 0 {
+		_, _ = fmt.Fprintf(w, "  (excludes %d host(s) without a known price)\n", unknown)
+	}
+}
+
+// formatCost formats a non-negative amount with its sign and currency,
+// e.g. "+24.00 USD".
+func formatCost(sign string, amount float64, currency string) string {
+	return fmt.Sprintf("%s%.2f %s", sign, amount, currency)
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: PROVIDER_CLOUD_COST_ESTIMATE
+// Spec: spec/providers/cloud/cost-estimate.md
+
+func TestPrintCostEstimate(t *testing.T) {
+	est := &cloud.CostEstimate{
+		Currency: "USD",
+		Source:   "test prices",
+		Create: []cloud.HostCost{
+			{Name: "app-2", Size: "s-2vcpu-4gb", Region: "fra1", Monthly: 24, Known: true},
+			{Name: "gpu-1", Size: "gpu-h100x1", Region: "tor1"},
+		},
+		Delete: []cloud.HostCost{
+			{Name: "old-1", Size: "s-1vcpu-1gb", Region: "fra1", Monthly: 6, Known: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	printCostEstimate(&buf, est)
+
+	want := `Estimated monthly cost (test prices):
+  + app-2  s-2vcpu-4gb  fra1  +24.00 USD
+  + gpu-1  gpu-h100x1   tor1  price unknown
+  - old-1  s-1vcpu-1gb  fra1  -6.00 USD
+  Net change: +18.00 USD/month
+  (excludes 1 host(s) without a known price)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("output =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPrintCostEstimate_NetDecrease(t *testing.T) {
+	var buf bytes.Buffer
+	printCostEstimate(&buf, &cloud.CostEstimate{
+		Currency: "USD",
+		Source:   "test prices",
+		Delete:   []cloud.HostCost{{Name: "db-1", Size: "s-4vcpu-8gb", Region: "fra1", Monthly: 48, Known: true}},
+	})
+
+	want := `Estimated monthly cost (test prices):
+  - db-1  s-4vcpu-8gb  fra1  -48.00 USD
+  Net change: -48.00 USD/month
+`
+	if got := buf.String(); got != want {
+		t.Errorf("output =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPrintCostEstimate_NothingToPrice(t *testing.T) {
+	for name, est := range map[string]*cloud.CostEstimate{
+		"no estimate": nil,
+		"no changes":  {Currency: "USD", Source: "test prices"},
+	} {
+		var buf bytes.Buffer
+		printCostEstimate(&buf, est)
+		if buf.Len() != 0 {
+			t.Errorf("%s: output = %q, want none", name, buf.String())
+		}
+	}
+}
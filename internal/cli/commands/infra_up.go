@@ -19,9 +19,11 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"stagecraft/internal/core/state"
 	"stagecraft/internal/infra/bootstrap"
 	"stagecraft/pkg/config"
 	cloud "stagecraft/pkg/providers/cloud"
@@ -59,16 +61,21 @@ func NewInfraUpCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "up",
 		Short: "Provision infrastructure for an environment",
-		Long:  "Create infrastructure hosts using the configured cloud provider and bootstrap them.",
-		RunE:  runInfraUp,
+		Long: `Create infrastructure hosts using the configured cloud provider and bootstrap them.
+
+Plans that resize or replace existing hosts must be confirmed interactively
+or approved with --yes. Resized hosts restart; replaced hosts lose their
+address and local disk.`,
+		RunE: runInfraUp,
 	}
 
-	// No infra-up specific flags in v1; relies on global flags (--config, --env, etc.)
-	return cmd
+	cmd.Flags().Bool("refresh-pricing", false, "estimate costs with prices fetched from the cloud provider's API")
+	addYesFlag(cmd)
+	return needsNetwork(cmd)
 }
 
 // runInfraUp executes the infra up command.
-func runInfraUp(cmd *cobra.Command, args []string) error {
+func runInfraUp(cmd *cobra.Command, args []string) (err error) {
 	ctx := cmd.Context()
 	if ctx == nil {
 		ctx = context.Background()
@@ -135,30 +142,90 @@ func runInfraUp(cmd *cobra.Command, args []string) error {
 		cloudProviderCfg = cfg.Cloud.Providers[cloudProviderID]
 	}
 
+	// Fail before provisioning if the network policy would reject the hosts
+	if validator, ok := networkProvider.(network.PolicyValidator); ok {
+		var networkProviderCfg any
+		if cfg.Network.Providers != nil {
+			networkProviderCfg = cfg.Network.Providers[networkProviderID]
+		}
+		if err := validateNetworkPolicy(ctx, validator, networkProviderCfg, cloudProvider, cloudProviderCfg, resolvedFlags.Env); err != nil {
+			return fmt.Errorf("infra up: %w", err)
+		}
+	}
+
+	// Reserved IPs recorded by earlier runs let replaced hosts keep their address
+	stateMgr := state.NewDefaultManager()
+	reservedIPs, err := recordedReservedIPs(ctx, stateMgr, resolvedFlags.Env)
+	if err != nil {
+		return fmt.Errorf("infra up: %w", err)
+	}
+
 	// Plan infrastructure
+	refreshPricing, _ := cmd.Flags().GetBool("refresh-pricing")
 	plan, err := cloudProvider.Plan(ctx, cloud.PlanOptions{
-		Config:      cloudProviderCfg,
-		Environment: resolvedFlags.Env,
+		Config:         cloudProviderCfg,
+		Environment:    resolvedFlags.Env,
+		Project:        cfg.Project.Name,
+		ReservedIPs:    reservedIPs,
+		RefreshPricing: refreshPricing,
 	})
 	if err != nil {
 		// maps to exit code 2 (CloudProvider failure)
 		return fmt.Errorf("infra up: cloud provider plan failed: %w", err)
 	}
+	printCostEstimate(os.Stdout, plan.Cost)
+	printReservedIPPlan(plan.ReservedIPs)
+	printHostChanges(&plan)
+
+	// Resizes restart hosts and replaces lose their disks
+	if changes := describeHostChanges(0, len(plan.ToResize), len(plan.ToReplace)); changes != "" {
+		question := fmt.Sprintf("This plan %s in %q. Apply it?", changes, resolvedFlags.Env)
+		if err := newConfirmer(cmd, false).Confirm(question); err != nil {
+			return fmt.Errorf("infra up: %w; plan not applied", err)
+		}
+	}
+
+	logger, err := newLogger(resolvedFlags)
+	if err != nil {
+		return err
+	}
+	// Audit everything from apply on, including partial bootstrap failures
+	defer func() {
+		recordAudit(ctx, cmd, logger, resolvedFlags.Env, "", err)
+	}()
 
 	// Apply infrastructure changes
 	if err := cloudProvider.Apply(ctx, cloud.ApplyOptions{
 		Config:      cloudProviderCfg,
 		Environment: resolvedFlags.Env,
+		Project:     cfg.Project.Name,
 		Plan:        plan,
 	}); err != nil {
 		return fmt.Errorf("infra up: cloud provider apply failed: %w", err)
 	}
 
-	// Fetch resulting hosts
-	providerHosts, err := cloudProvider.Hosts(ctx, cloud.HostsOptions{
+	hostsOpts := cloud.HostsOptions{
 		Config:      cloudProviderCfg,
 		Environment: resolvedFlags.Env,
-	})
+		Project:     cfg.Project.Name,
+	}
+
+	// Reconcile the environment firewall when the provider manages one
+	if firewallProvider, ok := cloudProvider.(cloud.FirewallProvider); ok {
+		if err := reconcileFirewall(ctx, firewallProvider, cloudProviderCfg, resolvedFlags.Env); err != nil {
+			return err
+		}
+	}
+
+	// Record reserved IPs so DNS can point at a stable address
+	if reservedIPProvider, ok := cloudProvider.(cloud.ReservedIPProvider); ok {
+		if err := recordReservedIPs(ctx, reservedIPProvider, stateMgr, hostsOpts); err != nil {
+			return err
+		}
+	}
+
+	// Fetch resulting hosts
+	providerHosts, err := cloudProvider.Hosts(ctx, hostsOpts)
 	if err != nil {
 		return fmt.Errorf("infra up: listing hosts failed: %w", err)
 	}
@@ -210,6 +277,122 @@ func runInfraUp(cmd *cobra.Command, args []string) error {
 	}
 }
 
+// reconcileFirewall plans and applies the environment firewall, printing a
+// line per created or drifted firewall.
+func reconcileFirewall(ctx context.Context, provider cloud.FirewallProvider, providerCfg any, env string) error {
+	plan, err := provider.PlanFirewall(ctx, cloud.FirewallPlanOptions{
+		Config:      providerCfg,
+		Environment: env,
+	})
+	if err != nil {
+		return fmt.Errorf("infra up: firewall plan failed: %w", err)
+	}
+	if !plan.HasChanges() {
+		return nil
+	}
+
+	if err := provider.ApplyFirewall(ctx, cloud.FirewallApplyOptions{
+		Config:      providerCfg,
+		Environment: env,
+		Plan:        plan,
+	}); err != nil {
+		return fmt.Errorf("infra up: firewall apply failed: %w", err)
+	}
+
+	for _, spec := range plan.ToCreate {
+		_, _ = fmt.Fprintf(os.Stdout, "Firewall %s created (tags: %s)\n", spec.Name, strings.Join(spec.Tags, ", "))
+	}
+	for _, change := range plan.ToUpdate {
+		_, _ = fmt.Fprintf(os.Stdout, "Firewall %s reconciled:\n", change.Desired.Name)
+		for _, d := range change.Drift {
+			_, _ = fmt.Fprintf(os.Stdout, "  - %s\n", d)
+		}
+	}
+	return nil
+}
+
+// validateNetworkPolicy checks the network policy against the roles of the
+// environment's desired hosts. Roles are only known for cloud providers that
+// implement DesiredHostsProvider; otherwise only role-independent tags are checked.
+func validateNetworkPolicy(ctx context.Context, validator network.PolicyValidator, networkProviderCfg any, cloudProvider cloud.CloudProvider, cloudProviderCfg any, env string) error {
+	var roles []string
+	if desiredProvider, ok := cloudProvider.(cloud.DesiredHostsProvider); ok {
+		desired, err := desiredProvider.DesiredHosts(ctx, cloud.HostsOptions{
+			Config:      cloudProviderCfg,
+			Environment: env,
+		})
+		if err != nil {
+			return fmt.Errorf("reading desired hosts: %w", err)
+		}
+		seen := make(map[string]bool)
+		for _, h := range desired {
+			if h.Role != "" && !seen[h.Role] {
+				seen[h.Role] = true
+				roles = append(roles, h.Role)
+			}
+		}
+		sort.Strings(roles)
+	}
+
+	if err := validator.ValidatePolicy(ctx, network.ValidatePolicyOptions{
+		Config: networkProviderCfg,
+		Roles:  roles,
+	}); err != nil {
+		return fmt.Errorf("network policy validation failed: %w", err)
+	}
+	return nil
+}
+
+// printReservedIPPlan prints the planned reserved IP changes in host order.
+func printReservedIPPlan(specs []cloud.ReservedIPSpec) {
+	for _, spec := range specs {
+		if spec.Address != "" {
+			_, _ = fmt.Fprintf(os.Stdout, "Reserved IP for %s: reattach %s\n", spec.Host, spec.Address)
+		} else {
+			_, _ = fmt.Fprintf(os.Stdout, "Reserved IP for %s: reserve new in %s\n", spec.Host, spec.Region)
+		}
+	}
+}
+
+// printHostChanges lists the hosts a plan resizes or replaces.
+func printHostChanges(plan *cloud.InfraPlan) {
+	for _, c := range plan.ToResize {
+		_, _ = fmt.Fprintf(os.Stdout, "Resize %s: %s\n", c.Desired.Name, changedValue(c.Current.Size, c.Desired.Size))
+	}
+	for _, c := range plan.ToReplace {
+		_, _ = fmt.Fprintf(os.Stdout, "Replace %s: %s in %s (new host; its local disk is lost)\n",
+			c.Desired.Name, changedValue(c.Current.Size, c.Desired.Size), changedValue(c.Current.Region, c.Desired.Region))
+	}
+}
+
+// recordReservedIPs stores the environment's attached reserved IPs in state
+// and prints them.
+func recordReservedIPs(ctx context.Context, provider cloud.ReservedIPProvider, stateMgr *state.Manager, opts cloud.HostsOptions) error {
+	env := opts.Environment
+	attached, err := provider.ReservedIPs(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("infra up: listing reserved IPs failed: %w", err)
+	}
+	if len(attached) == 0 {
+		// Avoid creating a state file for environments without reserved IPs
+		recorded, err := stateMgr.ReservedIPs(ctx, env)
+		if err != nil || len(recorded) == 0 {
+			return err
+		}
+	}
+
+	records := make([]state.ReservedIP, 0, len(attached))
+	for _, ip := range attached {
+		records = append(records, state.ReservedIP{Host: ip.Host, Address: ip.Address, Region: ip.Region})
+		_, _ = fmt.Fprintf(os.Stdout, "Reserved IP %s -> %s\n", ip.Address, ip.Host)
+	}
+
+	if err := stateMgr.SetReservedIPs(ctx, env, records); err != nil {
+		return fmt.Errorf("infra up: recording reserved IPs: %w", err)
+	}
+	return nil
+}
+
 // printBootstrapResults prints deterministic per-host bootstrap results.
 // Results are printed in the order they appear in the result (which matches
 // the sorted input order from mapCloudHostsToBootstrapHosts).
@@ -265,12 +448,23 @@ func mapCloudHostsToBootstrapHosts(providerHosts []cloud.Host) []bootstrap.Host
 		tagsCopy := make([]string, len(h.Tags))
 		copy(tagsCopy, h.Tags)
 
+		var volumes []bootstrap.Volume
+		for _, v := range h.Volumes {
+			volumes = append(volumes, bootstrap.Volume{
+				Name:       v.Name,
+				Device:     v.Device,
+				Filesystem: v.Filesystem,
+				MountPoint: v.MountPoint,
+			})
+		}
+
 		infraHosts[i] = bootstrap.Host{
 			ID:       h.ID,
 			Name:     h.Name,
 			Role:     h.Role,
 			PublicIP: h.PublicIP,
 			Tags:     tagsCopy,
+			Volumes:  volumes,
 		}
 	}
 
@@ -15,6 +15,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,6 +23,8 @@ import (
 	"sync"
 	"testing"
 
+	"stagecraft/internal/cli/prompt"
+	"stagecraft/internal/core/state"
 	"stagecraft/internal/infra/bootstrap"
 	cloud "stagecraft/pkg/providers/cloud"
 	network "stagecraft/pkg/providers/network"
@@ -465,6 +468,50 @@ environments:
 	}
 }
 
+func TestInfraUpCommand_ResizeAndReplaceRequireConfirmation(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+
+	configContent := `project:
+  name: test-project
+cloud:
+  provider: test-cloud-infra-up-changes
+  providers:
+    test-cloud-infra-up-changes: {}
+network:
+  provider: tailscale
+  providers:
+    tailscale: {}
+environments:
+  staging:
+    driver: docker
+`
+	configPath := filepath.Join(tmpDir, "stagecraft.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	fake := newFakeChangingProvider("test-cloud-infra-up-changes")
+
+	root := newTestRootCommand()
+	root.AddCommand(NewInfraCommand())
+	_, err := executeCommandForGolden(root, "infra", "up", "--config", configPath, "--env", "staging")
+	if !errors.Is(err, prompt.ErrNotInteractive) {
+		t.Fatalf("infra up error = %v, want ErrNotInteractive", err)
+	}
+	if len(fake.applied) != 0 {
+		t.Fatalf("infra up applied resizes without --yes")
+	}
+
+	root = newTestRootCommand()
+	root.AddCommand(NewInfraCommand())
+	if _, err := executeCommandForGolden(root, "infra", "up", "--config", configPath, "--env", "staging", "--yes"); err != nil {
+		t.Fatalf("infra up --yes: %v", err)
+	}
+	if len(fake.applied) != 1 || len(fake.applied[0].ToResize) != 1 || len(fake.applied[0].ToReplace) != 1 {
+		t.Errorf("applied = %+v, want the planned resize and replace", fake.applied)
+	}
+}
+
 func TestInfraUpCommand_CloudHostsFails(t *testing.T) {
 	tmpDir := t.TempDir()
 	originalDir, _ := os.Getwd()
@@ -593,6 +640,167 @@ environments:
 	}
 }
 
+// fakeFirewallCloudProvider adds FirewallProvider to fakeCloudProvider.
+type fakeFirewallCloudProvider struct {
+	fakeCloudProvider
+
+	firewallPlan     cloud.FirewallPlan
+	firewallApplyErr error
+	firewallApplied  *cloud.FirewallPlan
+}
+
+func (f *fakeFirewallCloudProvider) PlanFirewall(ctx context.Context, opts cloud.FirewallPlanOptions) (cloud.FirewallPlan, error) {
+	return f.firewallPlan, nil
+}
+
+//nolint:gocritic // hugeParam: opts matches FirewallProvider interface signature
+func (f *fakeFirewallCloudProvider) ApplyFirewall(ctx context.Context, opts cloud.FirewallApplyOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	plan := opts.Plan
+	f.firewallApplied = &plan
+	return f.firewallApplyErr
+}
+
+func writeFirewallInfraConfig(t *testing.T, providerID string) string {
+	t.Helper()
+	setupIsolatedStateTestEnv(t)
+
+	configContent := `project:
+  name: test-project
+cloud:
+  provider: ` + providerID + `
+  providers:
+    ` + providerID + `: {}
+network:
+  provider: tailscale
+  providers:
+    tailscale: {}
+environments:
+  staging:
+    driver: docker
+`
+	configPath := filepath.Join(t.TempDir(), "stagecraft.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return configPath
+}
+
+func TestInfraUpCommand_ReconcilesFirewall(t *testing.T) {
+	configPath := writeFirewallInfraConfig(t, "test-cloud-firewall")
+
+	fake := &fakeFirewallCloudProvider{
+		fakeCloudProvider: fakeCloudProvider{id: "test-cloud-firewall"},
+		firewallPlan: cloud.FirewallPlan{
+			ToCreate: []cloud.FirewallSpec{{Name: "stagecraft-staging", Tags: []string{"stagecraft-env-staging"}}},
+		},
+	}
+	cloud.Register(fake)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewInfraCommand())
+
+	if _, err := executeCommandForGolden(root, "infra", "up", "--config", configPath, "--env", "staging"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fake.firewallApplied == nil {
+		t.Fatal("expected ApplyFirewall to be called")
+	}
+	if len(fake.firewallApplied.ToCreate) != 1 || fake.firewallApplied.ToCreate[0].Name != "stagecraft-staging" {
+		t.Errorf("unexpected applied firewall plan: %+v", fake.firewallApplied)
+	}
+	if !fake.hostsCalled {
+		t.Error("expected Hosts to be called after firewall reconciliation")
+	}
+}
+
+func TestInfraUpCommand_FirewallApplyFails(t *testing.T) {
+	configPath := writeFirewallInfraConfig(t, "test-cloud-firewall-fail")
+
+	fake := &fakeFirewallCloudProvider{
+		fakeCloudProvider: fakeCloudProvider{id: "test-cloud-firewall-fail"},
+		firewallPlan: cloud.FirewallPlan{
+			ToUpdate: []cloud.FirewallChange{{ID: "fw-1", Desired: cloud.FirewallSpec{Name: "stagecraft-staging"}}},
+		},
+		firewallApplyErr: errors.New("api down"),
+	}
+	cloud.Register(fake)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewInfraCommand())
+
+	_, err := executeCommandForGolden(root, "infra", "up", "--config", configPath, "--env", "staging")
+	if err == nil || !strings.Contains(err.Error(), "firewall apply failed") {
+		t.Fatalf("expected firewall apply error, got %v", err)
+	}
+	if fake.hostsCalled {
+		t.Error("expected Hosts not to be called when firewall apply fails")
+	}
+}
+
+// fakeReservedIPCloudProvider adds ReservedIPProvider to fakeCloudProvider.
+type fakeReservedIPCloudProvider struct {
+	fakeCloudProvider
+
+	planOpts    cloud.PlanOptions
+	reservedIPs []cloud.ReservedIP
+}
+
+func (f *fakeReservedIPCloudProvider) Plan(ctx context.Context, opts cloud.PlanOptions) (cloud.InfraPlan, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.planCalled = true
+	f.planOpts = opts
+	return cloud.InfraPlan{
+		ReservedIPs: []cloud.ReservedIPSpec{{Host: "gateway-1", Region: "nyc3", Address: opts.ReservedIPs["gateway-1"]}},
+	}, nil
+}
+
+func (f *fakeReservedIPCloudProvider) ReservedIPs(ctx context.Context, opts cloud.HostsOptions) ([]cloud.ReservedIP, error) {
+	return f.reservedIPs, nil
+}
+
+func TestInfraUpCommand_RecordsReservedIPs(t *testing.T) {
+	configPath := writeFirewallInfraConfig(t, "test-cloud-reserved-ip")
+
+	mgr := state.NewDefaultManager()
+	if err := mgr.SetReservedIPs(context.Background(), "staging", []state.ReservedIP{
+		{Host: "gateway-1", Address: "198.51.100.7", Region: "nyc3"},
+	}); err != nil {
+		t.Fatalf("seeding state: %v", err)
+	}
+
+	fake := &fakeReservedIPCloudProvider{
+		fakeCloudProvider: fakeCloudProvider{id: "test-cloud-reserved-ip"},
+		reservedIPs: []cloud.ReservedIP{
+			{Host: "gateway-1", Address: "198.51.100.7", Region: "nyc3"},
+			{Host: "gateway-2", Address: "198.51.100.8", Region: "nyc3"},
+		},
+	}
+	cloud.Register(fake)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewInfraCommand())
+
+	if _, err := executeCommandForGolden(root, "infra", "up", "--config", configPath, "--env", "staging"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := fake.planOpts.ReservedIPs["gateway-1"]; got != "198.51.100.7" {
+		t.Errorf("expected recorded reserved IP to be passed to Plan, got %q", got)
+	}
+
+	recorded, err := mgr.ReservedIPs(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("reading reserved IPs: %v", err)
+	}
+	if len(recorded) != 2 || recorded[1].Host != "gateway-2" || recorded[1].Address != "198.51.100.8" {
+		t.Fatalf("expected both reserved IPs recorded in state, got %+v", recorded)
+	}
+}
+
 // fakeNetworkProviderForCLI is a test implementation of NetworkProvider for CLI tests.
 type fakeNetworkProviderForCLI struct{}
 
@@ -861,3 +1069,95 @@ environments:
 		t.Fatalf("expected error to mention bootstrap failed, got: %v", err)
 	}
 }
+
+func TestMapCloudHostsToBootstrapHosts_CopiesVolumes(t *testing.T) {
+	hosts := mapCloudHostsToBootstrapHosts([]cloud.Host{{
+		ID:   "2",
+		Name: "db-1",
+		Volumes: []cloud.HostVolume{{
+			Name:       "data",
+			Device:     "/dev/disk/by-id/scsi-0DO_Volume_prod-db-1-data",
+			Filesystem: "ext4",
+			MountPoint: "/mnt/data",
+		}},
+	}})
+
+	if len(hosts) != 1 || len(hosts[0].Volumes) != 1 {
+		t.Fatalf("expected one host with one volume, got %+v", hosts)
+	}
+	want := bootstrap.Volume{
+		Name:       "data",
+		Device:     "/dev/disk/by-id/scsi-0DO_Volume_prod-db-1-data",
+		Filesystem: "ext4",
+		MountPoint: "/mnt/data",
+	}
+	if hosts[0].Volumes[0] != want {
+		t.Errorf("volume = %+v, want %+v", hosts[0].Volumes[0], want)
+	}
+}
+
+// fakePolicyNetworkProvider adds PolicyValidator to fakeNetworkProviderForCLI.
+type fakePolicyNetworkProvider struct {
+	fakeNetworkProviderForCLI
+
+	id    string
+	roles []string
+	err   error
+}
+
+func (f *fakePolicyNetworkProvider) ID() string {
+	return f.id
+}
+
+func (f *fakePolicyNetworkProvider) ValidatePolicy(ctx context.Context, opts network.ValidatePolicyOptions) error {
+	f.roles = opts.Roles
+	return f.err
+}
+
+func TestInfraUpCommand_NetworkPolicyViolationStopsBeforePlan(t *testing.T) {
+	setupIsolatedStateTestEnv(t)
+
+	policy := &fakePolicyNetworkProvider{id: "test-network-policy", err: errors.New("tag \"tag:db\" is not declared in tagOwners")}
+	network.Register(policy)
+
+	fake := &fakeDesiredHostsCloudProvider{
+		fakeCloudProvider: fakeCloudProvider{id: "test-cloud-policy"},
+		desired: []cloud.HostSpec{
+			{Name: "db-1", Role: "db"},
+			{Name: "app-2", Role: "app"},
+			{Name: "app-1", Role: "app"},
+		},
+	}
+	cloud.Register(fake)
+
+	configContent := `project:
+  name: test-project
+cloud:
+  provider: test-cloud-policy
+  providers:
+    test-cloud-policy: {}
+network:
+  provider: test-network-policy
+environments:
+  staging:
+    driver: docker
+`
+	configPath := filepath.Join(t.TempDir(), "stagecraft.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewInfraCommand())
+
+	_, err := executeCommandForGolden(root, "infra", "up", "--config", configPath, "--env", "staging")
+	if err == nil || !strings.Contains(err.Error(), "network policy validation failed") {
+		t.Fatalf("expected policy validation error, got %v", err)
+	}
+	if fake.planCalled {
+		t.Error("expected Plan not to be called when the network policy is violated")
+	}
+	if strings.Join(policy.roles, ",") != "app,db" {
+		t.Errorf("expected sorted unique roles [app db], got %v", policy.roles)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"stagecraft/internal/scaffold"
 	"stagecraft/pkg/config"
 	"stagecraft/pkg/logging"
 )
@@ -30,10 +31,14 @@ import (
 // Feature: CLI_INIT
 // Spec: spec/commands/init.md
 
+// Feature: CLI_INIT_TEMPLATE
+// Spec: spec/scaffold/templates.md
+
 // NewInitCommand returns the `stagecraft init` command.
 func NewInitCommand() *cobra.Command {
 	var nonInteractive bool
 	var projectName string
+	var templateID string
 
 	cmd := &cobra.Command{
 		Use:   "init",
@@ -41,8 +46,10 @@ func NewInitCommand() *cobra.Command {
 		Long: `Initialize Stagecraft configuration in the current directory.
 
 This command will create a minimal Stagecraft config file and guide you
-through initial setup. In future iterations it will support more advanced
-provider-specific bootstrapping.`,
+through initial setup.
+
+Use --template to start from a built-in project topology instead of the
+minimal config. Available templates: ` + strings.Join(scaffold.TemplateIDs(), ", ") + `.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := cmd.OutOrStdout()
 
@@ -52,7 +59,10 @@ provider-specific bootstrapping.`,
 				return fmt.Errorf("resolving flags: %w", err)
 			}
 
-			logger := logging.NewLogger(flags.Verbose)
+			logger, err := newLogger(flags)
+			if err != nil {
+				return err
+			}
 
 			// Use resolved config path
 			configPath := flags.Config
@@ -70,10 +80,18 @@ provider-specific bootstrapping.`,
 				return nil
 			}
 
+			// Reject unknown templates before doing anything else
+			if templateID != "" {
+				if _, err := scaffold.Lookup(templateID); err != nil {
+					return err
+				}
+			}
+
 			// Check for dry-run mode
 			if flags.DryRun {
 				logger.Info("Dry-run mode: would create config file",
 					logging.NewField("path", configPath),
+					logging.NewField("template", templateID),
 				)
 				return nil
 			}
@@ -87,8 +105,16 @@ provider-specific bootstrapping.`,
 				return fmt.Errorf("gathering configuration: %w", err)
 			}
 
-			// Write config file
-			if err := writeConfig(configPath, cfg); err != nil {
+			// Write config file, either from the selected template or the minimal config
+			if templateID != "" {
+				data, err := scaffold.Render(templateID, scaffold.TemplateData{ProjectName: cfg.Project.Name})
+				if err != nil {
+					return fmt.Errorf("rendering template: %w", err)
+				}
+				if err := writeConfigBytes(configPath, data); err != nil {
+					return fmt.Errorf("writing config file: %w", err)
+				}
+			} else if err := writeConfig(configPath, cfg); err != nil {
 				return fmt.Errorf("writing config file: %w", err)
 			}
 
@@ -106,6 +132,7 @@ provider-specific bootstrapping.`,
 	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "run without interactive prompts and use defaults")
 	// Global flags (--config, --env, --verbose, --dry-run) are inherited from root
 	cmd.Flags().StringVar(&projectName, "project-name", "", "project name (default: directory name)")
+	cmd.Flags().StringVar(&templateID, "template", "", "project template to start from ("+strings.Join(scaffold.TemplateIDs(), ", ")+")")
 
 	return cmd
 }
@@ -146,6 +173,7 @@ func gatherConfig(out *os.File, nonInteractive bool, projectName, envName string
 
 	// Create minimal valid config
 	cfg := &config.Config{
+		Version: config.CurrentSchemaVersion,
 		Project: config.ProjectConfig{
 			Name: projectName,
 		},
@@ -166,6 +194,11 @@ func writeConfig(path string, cfg *config.Config) error {
 		return fmt.Errorf("marshaling config: %w", err)
 	}
 
+	return writeConfigBytes(path, data)
+}
+
+// writeConfigBytes writes already-serialized config YAML to disk.
+func writeConfigBytes(path string, data []byte) error {
 	// Use private-by-default permissions for generated config
 	if err := os.WriteFile(path, data, 0o600); err != nil {
 		return fmt.Errorf("writing config file: %w", err)
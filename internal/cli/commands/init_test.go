@@ -15,6 +15,7 @@ See https://www.gnu.org/licenses/ for license details.
 package commands
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,16 +23,21 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"stagecraft/internal/scaffold"
 	"stagecraft/pkg/config"
 )
 
 // newTestRootCommand creates a root command with global flags for testing.
 func newTestRootCommand() *cobra.Command {
-	root := &cobra.Command{Use: "stagecraft"}
+	root := &cobra.Command{Use: "stagecraft", PersistentPreRunE: CheckOffline}
 	// Add global flags (matching internal/cli/root.go)
 	root.PersistentFlags().StringP("config", "c", "", "path to stagecraft.yml")
 	root.PersistentFlags().Bool("dry-run", false, "show actions without executing")
 	root.PersistentFlags().StringP("env", "e", "", "target environment")
+	root.PersistentFlags().String("log-format", "", "log format: text, json")
+	root.PersistentFlags().String("log-level", "", "log level, optionally per subsystem (e.g. info,provider=warn)")
+	root.PersistentFlags().Bool("offline", false, "use cached provider data; fail commands that need the network")
+	root.PersistentFlags().StringP("output", "o", "", "output format: table, json, yaml")
 	root.PersistentFlags().BoolP("verbose", "v", false, "enable verbose output")
 	return root
 }
@@ -288,3 +294,65 @@ environments:
 		t.Fatalf("expected original config to be preserved, got project name %q", cfg.Project.Name)
 	}
 }
+
+func TestInitCommand_WithTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "stagecraft.yml")
+	originalDir, _ := os.Getwd()
+	defer func() {
+		if err := os.Chdir(originalDir); err != nil {
+			t.Logf("failed to restore directory: %v", err)
+		}
+	}()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewInitCommand())
+
+	_, err := executeCommandForGolden(root, "init", "--non-interactive", "--project-name", "test-project", "--template", "encore-ts-vite")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("expected valid config, got error: %v", err)
+	}
+
+	if cfg.Project.Name != "test-project" {
+		t.Fatalf("expected project name 'test-project', got %q", cfg.Project.Name)
+	}
+	if cfg.Backend == nil || cfg.Backend.Provider != "encore-ts" {
+		t.Fatalf("expected encore-ts backend from template, got %+v", cfg.Backend)
+	}
+	if cfg.Frontend == nil || cfg.Frontend.Provider != "generic" {
+		t.Fatalf("expected generic frontend from template, got %+v", cfg.Frontend)
+	}
+}
+
+func TestInitCommand_UnknownTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer func() {
+		if err := os.Chdir(originalDir); err != nil {
+			t.Logf("failed to restore directory: %v", err)
+		}
+	}()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewInitCommand())
+
+	_, err := executeCommandForGolden(root, "init", "--non-interactive", "--project-name", "test-project", "--template", "nope")
+	if !errors.Is(err, scaffold.ErrUnknownTemplate) {
+		t.Fatalf("expected ErrUnknownTemplate, got: %v", err)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(tmpDir, "stagecraft.yml")); !os.IsNotExist(statErr) {
+		t.Fatalf("expected no config file to be written for unknown template")
+	}
+}
@@ -106,7 +106,10 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize logger
-	logger := logging.NewLogger(flags.Verbose)
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
 	logger.Info("Running migrations",
 		logging.NewField("engine", engineID),
 		logging.NewField("database", dbName),
@@ -160,7 +163,9 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 		Steps:         0, // All
 	}
 
-	return engine.Run(ctx, opts)
+	err = engine.Run(ctx, opts)
+	recordAudit(ctx, cmd, logger, flags.Env, "", err)
+	return err
 }
 
 func getDatabaseNames(cfg *config.Config) []string {
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cache"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/providers/network"
+)
+
+// Feature: CLI_NETWORK_REAUTH
+// Spec: spec/commands/network-reauth.md
+
+// NewNetworkCommand returns the `stagecraft network` command group.
+func NewNetworkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Mesh network management commands",
+		Long:  "Commands for managing host membership in the mesh network",
+	}
+
+	cmd.AddCommand(NewNetworkReauthCommand())
+
+	return cmd
+}
+
+// NewNetworkReauthCommand returns `stagecraft network reauth`.
+func NewNetworkReauthCommand() *cobra.Command {
+	var host, role string
+
+	cmd := &cobra.Command{
+		Use:   "reauth",
+		Short: "Force a host to re-join the mesh network with a fresh auth key",
+		Long: `Force a host to re-join the mesh network with a fresh auth key, replacing
+its node key. Use this when a node key has expired and the host dropped off
+the network.
+
+The host's role, which selects the tags it advertises, is read from the host
+inventory (see 'stagecraft hosts list') unless --role is given.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNetworkReauth(cmd, host, role)
+		},
+	}
+
+	cmd.Flags().StringVar(&host, "host", "", "logical name of the host to re-authenticate (required)")
+	cmd.Flags().StringVar(&role, "role", "", "host role (default: looked up in the host inventory)")
+	_ = cmd.MarkFlagRequired("host")
+	_ = cmd.RegisterFlagCompletionFunc("host", CompleteHosts)
+
+	return needsNetwork(cmd)
+}
+
+func runNetworkReauth(cmd *cobra.Command, host, role string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("network reauth: resolving flags: %w", err)
+	}
+
+	cfg, err := config.Load(flags.Config)
+	if err != nil {
+		if err == config.ErrConfigNotFound {
+			return fmt.Errorf("network reauth: stagecraft config not found at %s", flags.Config)
+		}
+		return fmt.Errorf("network reauth: failed to load config: %w", err)
+	}
+
+	flags, err = ResolveFlags(cmd, cfg)
+	if err != nil {
+		return fmt.Errorf("network reauth: resolving flags: %w", err)
+	}
+
+	if cfg.Network == nil || cfg.Network.Provider == "" {
+		return fmt.Errorf("network reauth: network provider is not configured")
+	}
+
+	providerID := cfg.Network.Provider
+	provider, err := network.Get(providerID)
+	if err != nil {
+		return fmt.Errorf("network reauth: network provider %q not found: %w", providerID, err)
+	}
+
+	reauthenticator, ok := provider.(network.Reauthenticator)
+	if !ok {
+		return fmt.Errorf("network reauth: network provider %q does not support re-authentication", providerID)
+	}
+
+	if role == "" {
+		role, err = lookupHostRole(ctx, cfg, flags.Env, host)
+		if err != nil {
+			return fmt.Errorf("network reauth: %w", err)
+		}
+	}
+
+	var providerCfg any
+	if cfg.Network.Providers != nil {
+		providerCfg = cfg.Network.Providers[providerID]
+	}
+
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
+
+	err = reauthenticator.Reauth(ctx, network.ReauthOptions{
+		Config: providerCfg,
+		Host:   host,
+		Role:   role,
+	})
+	recordAudit(ctx, cmd, logger, flags.Env, "", err)
+	if err != nil {
+		return fmt.Errorf("network reauth: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Re-authenticated %s with %s\n", host, providerID)
+	return nil
+}
+
+// lookupHostRole returns the role of host from the (possibly cached) host inventory.
+func lookupHostRole(ctx context.Context, cfg *config.Config, env, host string) (string, error) {
+	inventory, err := resolveHostInventory(ctx, cfg, env, cache.Default(), hostInventoryOptions{
+		CacheTTL: defaultHostCacheTTL,
+	})
+	if err != nil {
+		return "", fmt.Errorf("resolving host role: %w", err)
+	}
+
+	for _, h := range inventory.Hosts {
+		if h.Name == host {
+			return h.Role, nil
+		}
+	}
+	return "", fmt.Errorf("host %q not found in environment %q", host, env)
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core/audit"
+	"stagecraft/pkg/providers/network"
+)
+
+// Feature: CLI_NETWORK_REAUTH
+// Spec: spec/commands/network-reauth.md
+
+// fakeReauthNetworkProvider adds Reauthenticator to fakeNetworkProviderForCLI.
+type fakeReauthNetworkProvider struct {
+	fakeNetworkProviderForCLI
+
+	id   string
+	opts *network.ReauthOptions
+}
+
+func (f *fakeReauthNetworkProvider) ID() string {
+	return f.id
+}
+
+func (f *fakeReauthNetworkProvider) Reauth(ctx context.Context, opts network.ReauthOptions) error {
+	f.opts = &opts
+	return nil
+}
+
+func writeNetworkConfig(t *testing.T, providerID string) string {
+	t.Helper()
+	setupIsolatedStateTestEnv(t)
+
+	configContent := `project:
+  name: test-project
+network:
+  provider: ` + providerID + `
+  providers:
+    ` + providerID + `:
+      auth_key_env: TS_AUTHKEY
+environments:
+  staging:
+    driver: docker
+`
+	configPath := filepath.Join(t.TempDir(), "stagecraft.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return configPath
+}
+
+func TestNetworkReauthCommand_UsesRoleFromHostInventory(t *testing.T) {
+	configPath := writeNetworkConfig(t, "test-network-reauth")
+	fake := &fakeReauthNetworkProvider{id: "test-network-reauth"}
+	network.Register(fake)
+
+	seedHostCache(t, "staging", []cachedHost{
+		{Name: "app-1", Role: "app", Status: "active"},
+	})
+
+	root := newTestRootCommand()
+	root.AddCommand(NewNetworkCommand())
+
+	out, err := executeCommandForGolden(root, "network", "reauth", "--config", configPath, "--env", "staging", "--host", "app-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Re-authenticated app-1") {
+		t.Errorf("expected success message, got:\n%s", out)
+	}
+	if fake.opts == nil || fake.opts.Host != "app-1" || fake.opts.Role != "app" {
+		t.Fatalf("unexpected Reauth options: %+v", fake.opts)
+	}
+	if cfg, ok := fake.opts.Config.(map[string]any); !ok || cfg["auth_key_env"] != "TS_AUTHKEY" {
+		t.Errorf("expected provider config to be passed, got %#v", fake.opts.Config)
+	}
+	entries, err := audit.NewDefaultLog().List(context.Background(), audit.Filter{Command: "network reauth"})
+	if err != nil || len(entries) != 1 || entries[0].Environment != "staging" {
+		t.Errorf("expected one audit entry for staging, got %+v (err=%v)", entries, err)
+	}
+}
+
+func TestNetworkReauthCommand_UnknownHost(t *testing.T) {
+	configPath := writeNetworkConfig(t, "test-network-reauth-unknown")
+	network.Register(&fakeReauthNetworkProvider{id: "test-network-reauth-unknown"})
+
+	seedHostCache(t, "staging", []cachedHost{
+		{Name: "app-1", Role: "app", Status: "active"},
+	})
+
+	root := newTestRootCommand()
+	root.AddCommand(NewNetworkCommand())
+
+	_, err := executeCommandForGolden(root, "network", "reauth", "--config", configPath, "--env", "staging", "--host", "db-9")
+	if err == nil || !strings.Contains(err.Error(), `host "db-9" not found`) {
+		t.Fatalf("expected unknown host error, got %v", err)
+	}
+}
+
+func TestNetworkReauthCommand_ProviderWithoutReauth(t *testing.T) {
+	configPath := writeNetworkConfig(t, "test-network-no-reauth")
+	network.Register(&fakePolicyNetworkProvider{id: "test-network-no-reauth"})
+
+	root := newTestRootCommand()
+	root.AddCommand(NewNetworkCommand())
+
+	_, err := executeCommandForGolden(root, "network", "reauth", "--config", configPath, "--env", "staging", "--host", "app-1", "--role", "app")
+	if err == nil || !strings.Contains(err.Error(), "does not support re-authentication") {
+		t.Fatalf("expected unsupported provider error, got %v", err)
+	}
+}
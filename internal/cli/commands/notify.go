@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/core/audit"
+	"stagecraft/internal/notify"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_NOTIFICATIONS
+// Spec: spec/deploy/notifications.md
+
+// lifecycleNotifier sends deploy lifecycle notifications for a single
+// deploy or rollback run. Delivery failures are logged by the dispatcher
+// and never affect the command's result.
+type lifecycleNotifier struct {
+	dispatcher *notify.Dispatcher
+	base       notify.Payload
+}
+
+// newLifecycleNotifier builds a notifier for the environment's configured
+// targets. In dry-run mode targets are logged but not contacted.
+func newLifecycleNotifier(cmd *cobra.Command, cfg *config.Config, env, version, commitSHA string, dryRun bool, logger logging.Logger) *lifecycleNotifier {
+	return newLifecycleNotifierWithOptions(cmd, cfg, env, version, commitSHA, notify.Options{DryRun: dryRun, Logger: logger})
+}
+
+// newLifecycleNotifierWithOptions is newLifecycleNotifier with explicit
+// dispatcher options, e.g. sandbox transports for `deploy --dry-run --explain`.
+func newLifecycleNotifierWithOptions(cmd *cobra.Command, cfg *config.Config, env, version, commitSHA string, opts notify.Options) *lifecycleNotifier {
+	var targets []config.NotificationConfig
+	if envCfg, ok := cfg.Environments[env]; ok {
+		targets = envCfg.Notifications
+	}
+
+	return &lifecycleNotifier{
+		dispatcher: notify.New(targets, opts),
+		base: notify.Payload{
+			Project:     cfg.Project.Name,
+			Environment: env,
+			Command:     auditCommandName(cmd),
+			Version:     version,
+			CommitSHA:   commitSHA,
+			Actor:       audit.ResolveActor(),
+		},
+	}
+}
+
+// send dispatches event for releaseID, attaching runErr's message if set.
+func (n *lifecycleNotifier) send(ctx context.Context, event notify.Event, releaseID string, runErr error) {
+	p := n.base
+	p.Event = event
+	p.ReleaseID = releaseID
+	if runErr != nil {
+		p.Error = runErr.Error()
+	}
+	n.dispatcher.Dispatch(ctx, p)
+}
+
+// finish sends success on a nil runErr and deploy.failed otherwise.
+func (n *lifecycleNotifier) finish(ctx context.Context, success notify.Event, releaseID string, runErr error) {
+	if runErr != nil {
+		n.send(ctx, notify.EventFailed, releaseID, runErr)
+		return
+	}
+	n.send(ctx, success, releaseID, nil)
+}
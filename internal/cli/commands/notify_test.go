@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"stagecraft/internal/core"
+	"stagecraft/internal/notify"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_NOTIFICATIONS
+// Spec: spec/deploy/notifications.md
+
+// setupNotificationTestEnv writes a config whose staging environment posts
+// to a local webhook and returns the received payloads.
+func setupNotificationTestEnv(t *testing.T) func() []notify.Payload {
+	t.Helper()
+	env := setupIsolatedStateTestEnv(t)
+
+	var mu sync.Mutex
+	var received []notify.Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p notify.Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decoding notification: %v", err)
+		}
+		mu.Lock()
+		received = append(received, p)
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+
+	configContent := `project:
+  name: test-app
+environments:
+  staging:
+    driver: local
+    notifications:
+      - type: webhook
+        url: "` + srv.URL + `"
+`
+	if err := os.WriteFile(filepath.Join(env.TempDir, "stagecraft.yml"), []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	return func() []notify.Payload {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]notify.Payload(nil), received...)
+	}
+}
+
+func noopPhaseFns() PhaseFns {
+	noop := func(ctx context.Context, plan *core.Plan, logger logging.Logger) error { return nil }
+	return PhaseFns{Build: noop, Push: noop, MigratePre: noop, Rollout: noop, MigratePost: noop, Finalize: noop}
+}
+
+func TestDeployCommand_NotifiesStartedAndSucceeded(t *testing.T) {
+	received := setupNotificationTestEnv(t)
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0"); err != nil {
+		t.Fatalf("deploy failed: %v", err)
+	}
+
+	got := received()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 notifications, got %d: %+v", len(got), got)
+	}
+	if got[0].Event != notify.EventStarted || got[1].Event != notify.EventSucceeded {
+		t.Fatalf("unexpected events: %s, %s", got[0].Event, got[1].Event)
+	}
+	for _, p := range got {
+		if p.ReleaseID == "" || p.Version != "v1.0.0" || p.Environment != "staging" || p.Project != "test-app" {
+			t.Errorf("missing release metadata: %+v", p)
+		}
+	}
+}
+
+func TestDeployCommand_NotifiesFailure(t *testing.T) {
+	received := setupNotificationTestEnv(t)
+
+	fns := noopPhaseFns()
+	fns.Rollout = func(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
+		return errors.New("rollout exploded")
+	}
+
+	if err := executeDeployWithPhases(fns, "deploy", "--env", "staging"); err == nil {
+		t.Fatalf("expected deploy to fail")
+	}
+
+	got := received()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 notifications, got %d: %+v", len(got), got)
+	}
+	if got[1].Event != notify.EventFailed {
+		t.Fatalf("expected %s, got %s", notify.EventFailed, got[1].Event)
+	}
+	if got[1].Error == "" {
+		t.Errorf("expected failure notification to carry the error")
+	}
+}
+
+func TestDeployCommand_DryRunDoesNotNotify(t *testing.T) {
+	received := setupNotificationTestEnv(t)
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--dry-run"); err != nil {
+		t.Fatalf("dry-run deploy failed: %v", err)
+	}
+
+	if got := received(); len(got) != 0 {
+		t.Fatalf("expected no notifications in dry-run, got %+v", got)
+	}
+}
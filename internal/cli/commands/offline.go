@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_OFFLINE
+// Spec: spec/core/offline.md
+
+// annotationNeedsNetwork marks commands and flags that cannot work without
+// the network. CheckOffline refuses them under --offline.
+const annotationNeedsNetwork = "stagecraft_needs_network"
+
+// needsNetwork marks cmd, and with it its subcommands, as unusable offline
+// and returns it.
+func needsNetwork(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[annotationNeedsNetwork] = "true"
+	return cmd
+}
+
+// flagNeedsNetwork marks the named local flag of cmd as unusable offline.
+func flagNeedsNetwork(cmd *cobra.Command, name string) {
+	_ = cmd.Flags().SetAnnotation(name, annotationNeedsNetwork, []string{"true"})
+}
+
+// CheckOffline refuses commands and flags marked as needing the network
+// when --offline (or STAGECRAFT_OFFLINE) is set. The root command runs it
+// before every command, so commands fail before touching anything.
+func CheckOffline(cmd *cobra.Command, _ []string) error {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil || !flags.Offline {
+		return err
+	}
+
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations[annotationNeedsNetwork] != "" {
+			return fmt.Errorf("%s %w", cmd.CommandPath(), cloud.ErrOffline)
+		}
+	}
+
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if err == nil && len(f.Annotations[annotationNeedsNetwork]) > 0 {
+			err = fmt.Errorf("%s --%s %w", cmd.CommandPath(), f.Name, cloud.ErrOffline)
+		}
+	})
+	return err
+}
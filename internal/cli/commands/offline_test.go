@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_OFFLINE
+// Spec: spec/core/offline.md
+
+// fakeOfflinePlanningCloudProvider adds OfflinePlanner to fakePlanningCloudProvider.
+type fakeOfflinePlanningCloudProvider struct {
+	fakePlanningCloudProvider
+
+	offlineOptions cloud.PlanOptions
+	offlineCalls   int
+}
+
+//nolint:gocritic // hugeParam: opts matches OfflinePlanner interface signature
+func (f *fakeOfflinePlanningCloudProvider) PlanOffline(ctx context.Context, opts cloud.PlanOptions) (cloud.InfraPlan, error) {
+	f.offlineCalls++
+	f.offlineOptions = opts
+	return f.plan, nil
+}
+
+func TestCheckOffline_RefusesCommandsThatNeedTheNetwork(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-offline-refuse")
+	fake := newFakePlanningProvider("test-cloud-offline-refuse", false)
+
+	tests := []struct {
+		name string
+		args []string
+		env  string
+		want string
+	}{
+		{
+			name: "command",
+			args: []string{"apply", "--offline"},
+			want: "stagecraft hosts apply needs network access",
+		},
+		{
+			name: "flag",
+			args: []string{"list", "--offline", "--refresh"},
+			want: "stagecraft hosts list --refresh needs network access",
+		},
+		{
+			name: "environment variable",
+			args: []string{"plan", "--refresh-pricing"},
+			env:  "true",
+			want: "stagecraft hosts plan --refresh-pricing needs network access",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STAGECRAFT_OFFLINE", tt.env)
+
+			_, err := executeHosts("", append(tt.args, "--config", configPath, "--env", "staging")...)
+			if !errors.Is(err, cloud.ErrOffline) {
+				t.Fatalf("error = %v, want ErrOffline", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	if fake.planOptions.Environment != "" || len(fake.applied) != 0 {
+		t.Error("refused commands must not reach the provider")
+	}
+}
+
+func TestCheckOffline_RefusesBuildPush(t *testing.T) {
+	root := newTestRootCommand()
+	root.AddCommand(NewBuildCommand())
+
+	_, err := executeCommandForGolden(root, "build", "--offline", "--push")
+	if !errors.Is(err, cloud.ErrOffline) {
+		t.Fatalf("error = %v, want ErrOffline", err)
+	}
+	if want := "stagecraft build --push needs network access"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err, want)
+	}
+}
+
+func TestHostsListCommand_Offline(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-offline")
+	fake := newFakeDesiredHostsProvider("test-cloud-hosts-offline")
+
+	_, err := executeHosts("", "list", "--config", configPath, "--env", "staging", "--offline")
+	if !errors.Is(err, cloud.ErrOffline) {
+		t.Fatalf("error without a cache = %v, want ErrOffline", err)
+	}
+
+	if _, err := executeHosts("", "list", "--config", configPath, "--env", "staging"); err != nil {
+		t.Fatalf("hosts list: %v", err)
+	}
+
+	// A stale cache is still used offline
+	out, err := executeHosts("", "list", "--config", configPath, "--env", "staging", "--offline", "--cache-ttl", "0")
+	if err != nil {
+		t.Fatalf("hosts list --offline: %v", err)
+	}
+	if fake.hostsCalls != 1 {
+		t.Errorf("expected the offline run not to call Hosts, got %d calls", fake.hostsCalls)
+	}
+	if !strings.Contains(out, "Using cached inventory") || !strings.Contains(out, "app-1") {
+		t.Errorf("expected cached table output, got:\n%s", out)
+	}
+}
+
+func TestHostsPlanCommand_Offline(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-plan-offline")
+	fake := &fakeOfflinePlanningCloudProvider{
+		fakePlanningCloudProvider: fakePlanningCloudProvider{
+			fakeCloudProvider: fakeCloudProvider{id: "test-cloud-hosts-plan-offline"},
+			plan: cloud.InfraPlan{
+				ToCreate: []cloud.HostSpec{{Name: "app-2", Role: "app", Size: "s-2vcpu-4gb", Region: "fra1"}},
+			},
+		},
+	}
+	cloud.Register(fake)
+
+	_, err := executeHosts("", "plan", "--config", configPath, "--env", "staging", "--offline")
+	if !errors.Is(err, cloud.ErrOffline) {
+		t.Fatalf("error without a cache = %v, want ErrOffline", err)
+	}
+
+	seedHostCache(t, "staging", []cachedHost{
+		{ID: "101", Name: "app-1", Role: "app", Region: "fra1", Size: "s-1vcpu-1gb", Status: "active"},
+		{Name: "db-1", Role: "db", Region: "fra1", Status: hostStatusMissing},
+	})
+
+	out, err := executeHosts("", "plan", "--config", configPath, "--env", "staging", "--offline", "--output", "json")
+	if err != nil {
+		t.Fatalf("hosts plan --offline: %v", err)
+	}
+
+	var view hostsPlanView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if view.CachedAt == "" || len(view.Create) != 1 {
+		t.Errorf("view = %+v, want the offline plan with cached_at", view)
+	}
+	if fake.offlineCalls != 1 || fake.planOptions.Environment != "" {
+		t.Errorf("expected only PlanOffline to be called, got %d call(s) and Plan options %+v", fake.offlineCalls, fake.planOptions)
+	}
+
+	known := fake.offlineOptions.Known
+	if len(known) != 1 || known[0].Name != "app-1" || known[0].Size != "s-1vcpu-1gb" || known[0].ID != "101" {
+		t.Errorf("known hosts = %+v, want the cached live host only", known)
+	}
+}
+
+func TestHostsPlanCommand_OfflineUnsupportedProvider(t *testing.T) {
+	configPath := writeHostsConfig(t, "test-cloud-hosts-plan-online-only")
+	fake := newFakePlanningProvider("test-cloud-hosts-plan-online-only", false)
+
+	_, err := executeHosts("", "plan", "--config", configPath, "--env", "staging", "--offline")
+	if !errors.Is(err, cloud.ErrOffline) {
+		t.Fatalf("error = %v, want ErrOffline", err)
+	}
+	if fake.planOptions.Environment != "" {
+		t.Error("expected the provider's online Plan not to be called")
+	}
+}
@@ -20,6 +20,7 @@ import (
 
 	"stagecraft/internal/core"
 	"stagecraft/internal/core/state"
+	"stagecraft/internal/telemetry"
 	"stagecraft/pkg/logging"
 )
 
@@ -87,6 +88,7 @@ func markDownstreamPhasesSkippedCommon(
 	failedPhase state.ReleasePhase,
 	logger logging.Logger,
 ) error {
+	logger = logger.Named(logging.SubsystemState)
 	allPhases := allPhasesCommon()
 
 	// Find the index of the failed phase
@@ -140,6 +142,7 @@ func markAllPhasesFailedCommon(
 	releaseID string,
 	logger logging.Logger,
 ) {
+	logger = logger.Named(logging.SubsystemState)
 	for _, phase := range allPhasesCommon() {
 		if err := stateMgr.UpdatePhase(ctx, releaseID, phase, state.StatusFailed); err != nil {
 			logger.Debug("Failed to mark phase as failed",
@@ -162,12 +165,13 @@ func executePhasesCommon(
 	fns PhaseFns,
 ) error {
 	phases := allPhasesCommon()
+	engineLogger := logger.Named(logging.SubsystemEngine)
 
 	for _, phase := range phases {
 		phaseName := string(phase)
 
 		// Log phase start
-		logger.Info("Starting phase", logging.NewField("phase", phaseName))
+		engineLogger.Info("Starting phase", logging.NewField("phase", phaseName))
 
 		// Set phase status to running
 		if err := stateMgr.UpdatePhase(ctx, releaseID, phase, state.StatusRunning); err != nil {
@@ -179,22 +183,28 @@ func executePhasesCommon(
 		if err != nil {
 			// This should never happen with valid phases, but handle it gracefully
 			if updateErr := stateMgr.UpdatePhase(ctx, releaseID, phase, state.StatusFailed); updateErr != nil {
-				logger.Debug("Failed to update phase status", logging.NewField("error", updateErr.Error()))
+				engineLogger.Debug("Failed to update phase status", logging.NewField("error", updateErr.Error()))
 			}
 			return fmt.Errorf("getting phase function for %q: %w", phaseName, err)
 		}
 
-		// Execute phase
-		err = phaseFn(ctx, plan, logger)
+		// Execute phase inside its own span so traces show where time goes
+		phaseCtx, span := telemetry.StartSpan(ctx, "phase "+phaseName,
+			telemetry.AttrPhase.String(phaseName),
+			telemetry.AttrReleaseID.String(releaseID),
+		)
+		err = phaseFn(phaseCtx, plan, logger)
+		telemetry.EndSpan(span, err)
+		storeHostResults(ctx, stateMgr, releaseID, phase, plan, engineLogger)
 		if err != nil {
-			// Mark current phase as failed
-			if updateErr := stateMgr.UpdatePhase(ctx, releaseID, phase, state.StatusFailed); updateErr != nil {
-				logger.Debug("Failed to update phase status", logging.NewField("error", updateErr.Error()))
+			// Mark current phase as failed, or timed out
+			if updateErr := stateMgr.UpdatePhase(ctx, releaseID, phase, phaseFailureStatus(err)); updateErr != nil {
+				engineLogger.Debug("Failed to update phase status", logging.NewField("error", updateErr.Error()))
 			}
 
 			// Mark all downstream phases as skipped
 			if skipErr := markDownstreamPhasesSkippedCommon(ctx, stateMgr, releaseID, phase, logger); skipErr != nil {
-				logger.Debug("Failed to mark downstream phases as skipped", logging.NewField("error", skipErr.Error()))
+				engineLogger.Debug("Failed to mark downstream phases as skipped", logging.NewField("error", skipErr.Error()))
 			}
 
 			return fmt.Errorf("phase %q failed: %w", phaseName, err)
@@ -205,7 +215,7 @@ func executePhasesCommon(
 			return fmt.Errorf("updating phase %q to completed: %w", phaseName, err)
 		}
 
-		logger.Info("Phase completed", logging.NewField("phase", phaseName))
+		engineLogger.Info("Phase completed", logging.NewField("phase", phaseName))
 	}
 
 	return nil
@@ -118,7 +118,7 @@ environments:
 		{
 			name:    "rollback_phase_failure_rollout",
 			command: "rollback",
-			args:    []string{"rollback", "--env", "staging", "--to-previous"},
+			args:    []string{"rollback", "--env", "staging", "--to-previous", "--yes"},
 			golden:  "rollback_phase_failure_rollout",
 			setupEnv: func(t *testing.T) *isolatedStateTestEnv {
 				env := setupIsolatedStateTestEnv(t)
@@ -53,6 +53,10 @@ func setupDeployCommand(fns PhaseFns) *cobra.Command {
 		},
 	}
 	cmd.Flags().String("version", "", "Version to deploy (defaults to git SHA)")
+	addDeployPlanFlags(cmd)
+	addDeployBundleFlag(cmd)
+	addDeployRetryHostFlag(cmd)
+	addDeployExplainFlag(cmd)
 	return cmd
 }
 
@@ -68,5 +72,6 @@ func setupRollbackCommand(fns PhaseFns) *cobra.Command {
 	cmd.Flags().Bool("to-previous", false, "Rollback to immediately previous release")
 	cmd.Flags().String("to-release", "", "Rollback to specific release ID")
 	cmd.Flags().String("to-version", "", "Rollback to most recent release with matching version")
+	addYesFlag(cmd)
 	return cmd
 }
@@ -16,7 +16,6 @@ package commands
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -25,6 +24,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"stagecraft/internal/cli/output"
 	"stagecraft/internal/core"
 	"stagecraft/pkg/config"
 	"stagecraft/pkg/logging"
@@ -48,9 +48,11 @@ func NewPlanCommand() *cobra.Command {
 	cmd.AddCommand(NewPlanSliceCommand())
 
 	cmd.Flags().StringP("env", "e", "", "Target environment (e.g. staging, prod)")
+	registerEnvCompletion(cmd)
 	cmd.Flags().StringP("version", "v", "", "Version to plan for (defaults to 'unknown' if omitted)")
 	cmd.Flags().String("services", "", "Comma-separated list of services to include")
-	cmd.Flags().String("format", "text", "Output format: text or json")
+	_ = cmd.RegisterFlagCompletionFunc("services", CompleteServiceList)
+	cmd.Flags().String("format", "text", "Output format: text, json or yaml (defaults to --output)")
 	cmd.Flags().BoolP("verbose", "V", false, "Show more detail")
 
 	// Future extensions (v1 minimal, can be stubbed):
@@ -92,7 +94,10 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	}
 
 	// 5. Initialize logger
-	logger := logging.NewLogger(flags.Verbose)
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
 
 	// 6. Parse plan-specific flags
 	versionFlag, _ := cmd.Flags().GetString("version")
@@ -122,7 +127,9 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	}
 	plan.Metadata["version"] = version
 
-	// 11. Get provider plans if backend is configured
+	// 11. Get provider plans for each backend service. Plans are keyed by
+	// provider ID for the single-service shorthand and by service name
+	// otherwise, so two services sharing a provider don't collide.
 	providerPlans := make(map[string]backendproviders.ProviderPlan)
 	if cfg.Backend != nil {
 		ctx := cmd.Context()
@@ -130,48 +137,19 @@ func runPlan(cmd *cobra.Command, args []string) error {
 			ctx = context.Background()
 		}
 
-		// Get backend provider
-		providerID := cfg.Backend.Provider
-		provider, err := backendproviders.Get(providerID)
+		// Get workdir
+		workdir, err := os.Getwd()
 		if err != nil {
-			// Log warning but don't fail - plan can still be generated without provider plan
-			logger.Debug("Could not get backend provider for planning",
-				logging.NewField("provider", providerID),
-				logging.NewField("error", err.Error()),
-			)
-		} else {
-			// Get provider config
-			providerCfg, err := cfg.Backend.GetProviderConfig()
-			if err != nil {
-				logger.Debug("Could not get provider config for planning",
-					logging.NewField("error", err.Error()),
-				)
-			} else {
-				// Construct image tag (same logic as deploy)
-				imageTag := fmt.Sprintf("%s:%s", cfg.Project.Name, version)
-
-				// Get workdir
-				workdir, err := os.Getwd()
-				if err != nil {
-					workdir = "."
-				}
-
-				// Call provider Plan()
-				planOpts := backendproviders.PlanOptions{
-					Config:   providerCfg,
-					ImageTag: imageTag,
-					WorkDir:  workdir,
-				}
+			workdir = "."
+		}
 
-				providerPlan, err := provider.Plan(ctx, planOpts)
-				if err != nil {
-					logger.Debug("Provider plan generation failed",
-						logging.NewField("provider", providerID),
-						logging.NewField("error", err.Error()),
-					)
-				} else {
-					providerPlans[providerID] = providerPlan
-				}
+		for _, svc := range cfg.Backend.ServiceList() {
+			key := svc.Provider
+			if cfg.Backend.IsMultiService() {
+				key = svc.Name
+			}
+			if providerPlan, ok := planBackendService(ctx, cfg, svc, version, workdir, logger); ok {
+				providerPlans[key] = providerPlan
 			}
 		}
 	}
@@ -185,7 +163,13 @@ func runPlan(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("applying filters: %w", err)
 	}
 
-	// 13. Render output
+	// 13. Render output: an explicit --format wins over the global --output
+	if !cmd.Flags().Changed("format") {
+		formatFlag, err = planFormatFromOutput(flags.Output)
+		if err != nil {
+			return err
+		}
+	}
 	opts := PlanRenderOptions{
 		Format:  formatFlag,
 		Verbose: verboseFlag,
@@ -203,9 +187,21 @@ func resolvePlanVersion(versionFlag string) string {
 	return "unknown"
 }
 
+// planFormatFromOutput maps the global --output value to a plan --format value.
+func planFormatFromOutput(value string) (string, error) {
+	format, err := output.ParseFormat(value)
+	if err != nil {
+		return "", err
+	}
+	if format == output.FormatTable {
+		return "text", nil
+	}
+	return string(format), nil
+}
+
 // PlanRenderOptions contains options for rendering a plan.
 type PlanRenderOptions struct {
-	Format  string // "text" or "json"
+	Format  string // "text", "json" or "yaml"
 	Verbose bool
 }
 
@@ -315,8 +311,10 @@ func renderPlan(out io.Writer, plan *core.Plan, env, version string, opts PlanRe
 		return renderPlanText(out, plan, env, version, opts, logger)
 	case "json":
 		return renderPlanJSON(out, plan, env, version, opts)
+	case "yaml":
+		return output.Render(out, output.FormatYAML, buildJSONPlan(plan, env, version), nil)
 	default:
-		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", opts.Format)
+		return fmt.Errorf("invalid format: %s (must be 'text', 'json' or 'yaml')", opts.Format)
 	}
 }
 
@@ -424,7 +422,11 @@ func renderPlanText(out io.Writer, plan *core.Plan, env, version string, opts Pl
 
 				for _, providerID := range providerIDs {
 					providerPlan := providerPlans[providerID]
-					_, _ = fmt.Fprintf(out, "\nProvider: %s\n", providerID)
+					if service := providerPlanService(providerID, providerPlan); service != "" {
+						_, _ = fmt.Fprintf(out, "\nProvider: %s (service %s)\n", providerPlan.Provider, service)
+					} else {
+						_, _ = fmt.Fprintf(out, "\nProvider: %s\n", providerID)
+					}
 					for j, step := range providerPlan.Steps {
 						_, _ = fmt.Fprintf(out, "  %d. %s\n", j+1, step.Name)
 						_, _ = fmt.Fprintf(out, "     - %s\n", step.Description)
@@ -440,6 +442,12 @@ func renderPlanText(out io.Writer, plan *core.Plan, env, version string, opts Pl
 // renderPlanJSON renders the plan in JSON format.
 func renderPlanJSON(out io.Writer, plan *core.Plan, env, version string, opts PlanRenderOptions) error {
 	_ = opts.Verbose // Reserved for future verbose output enhancements
+	return output.Render(out, output.FormatJSON, buildJSONPlan(plan, env, version), nil)
+}
+
+// buildJSONPlan builds the structured representation of a plan shared by
+// the json and yaml formats.
+func buildJSONPlan(plan *core.Plan, env, version string) jsonPlan {
 	// Build JSON structure
 	jsonPlan := jsonPlan{
 		Env:     env,
@@ -504,6 +512,7 @@ func renderPlanJSON(out io.Writer, plan *core.Plan, env, version string, opts Pl
 				for _, providerID := range providerIDs {
 					providerPlan := providerPlans[providerID]
 					jsonProviderPlan := jsonProviderPlan{
+						Service:  providerPlanService(providerID, providerPlan),
 						Provider: providerPlan.Provider,
 						Steps:    make([]jsonProviderStep, len(providerPlan.Steps)),
 					}
@@ -521,41 +530,92 @@ func renderPlanJSON(out io.Writer, plan *core.Plan, env, version string, opts Pl
 		}
 	}
 
-	// Marshal to JSON with indentation
-	encoder := json.NewEncoder(out)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(jsonPlan)
+	return jsonPlan
 }
 
-// jsonPlan is the JSON representation of a plan.
+// jsonPlan is the structured (JSON/YAML) representation of a plan.
 type jsonPlan struct {
-	Env           string             `json:"env"`
-	Version       string             `json:"version"`
-	Phases        []jsonPhase        `json:"phases"`
-	ProviderPlans []jsonProviderPlan `json:"provider_plans,omitempty"`
+	Env           string             `json:"env" yaml:"env"`
+	Version       string             `json:"version" yaml:"version"`
+	Phases        []jsonPhase        `json:"phases" yaml:"phases"`
+	ProviderPlans []jsonProviderPlan `json:"provider_plans,omitempty" yaml:"provider_plans,omitempty"`
 }
 
 // jsonPhase is the JSON representation of a phase.
 type jsonPhase struct {
-	ID          string                 `json:"id"`
-	Kind        string                 `json:"kind"`
-	Services    []string               `json:"services"`
-	Hosts       []string               `json:"hosts"`
-	Description string                 `json:"description"`
-	DependsOn   []string               `json:"depends_on"`
-	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	ID          string                 `json:"id" yaml:"id"`
+	Kind        string                 `json:"kind" yaml:"kind"`
+	Services    []string               `json:"services" yaml:"services"`
+	Hosts       []string               `json:"hosts" yaml:"hosts"`
+	Description string                 `json:"description" yaml:"description"`
+	DependsOn   []string               `json:"depends_on" yaml:"depends_on"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// planBackendService asks a backend service's provider for its build plan.
+// Failures are logged at debug level; the deploy plan is still rendered.
+func planBackendService(
+	ctx context.Context,
+	cfg *config.Config,
+	svc config.BackendService,
+	version, workdir string,
+	logger logging.Logger,
+) (backendproviders.ProviderPlan, bool) {
+	providerID := svc.Provider
+	provider, err := backendproviders.Get(providerID)
+	if err != nil {
+		logger.Debug("Could not get backend provider for planning",
+			logging.NewField("provider", providerID),
+			logging.NewField("error", err.Error()),
+		)
+		return backendproviders.ProviderPlan{}, false
+	}
+
+	providerCfg, err := svc.GetProviderConfig()
+	if err != nil {
+		logger.Debug("Could not get provider config for planning",
+			logging.NewField("error", err.Error()),
+		)
+		return backendproviders.ProviderPlan{}, false
+	}
+
+	// Same image tag and workdir logic as deploy
+	providerPlan, err := provider.Plan(ctx, backendproviders.PlanOptions{
+		Config:   providerCfg,
+		ImageTag: deployServiceImageTag(cfg, svc.Name, version),
+		WorkDir:  backendServiceWorkDir(svc, workdir),
+	})
+	if err != nil {
+		logger.Debug("Provider plan generation failed",
+			logging.NewField("provider", providerID),
+			logging.NewField("error", err.Error()),
+		)
+		return backendproviders.ProviderPlan{}, false
+	}
+
+	return providerPlan, true
+}
+
+// providerPlanService returns the backend service a provider plan entry
+// belongs to, or "" for the single-service shorthand keyed by provider ID.
+func providerPlanService(key string, providerPlan backendproviders.ProviderPlan) string {
+	if key == providerPlan.Provider {
+		return ""
+	}
+	return key
 }
 
 // jsonProviderPlan is the JSON representation of a provider plan.
 type jsonProviderPlan struct {
-	Provider string             `json:"provider"`
-	Steps    []jsonProviderStep `json:"steps"`
+	Service  string             `json:"service,omitempty" yaml:"service,omitempty"`
+	Provider string             `json:"provider" yaml:"provider"`
+	Steps    []jsonProviderStep `json:"steps" yaml:"steps"`
 }
 
 // jsonProviderStep is the JSON representation of a provider step.
 type jsonProviderStep struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
 }
 
 // getOperationID generates a deterministic ID for an operation.
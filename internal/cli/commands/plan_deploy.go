@@ -34,6 +34,7 @@ func NewPlanDeployCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("env", "e", "", "Target environment (required)")
+	registerEnvCompletion(cmd)
 	cmd.Flags().String("json", "", "Output path for JSON plan (default: stdout)")
 	_ = cmd.MarkFlagRequired("env")
 
@@ -37,6 +37,7 @@ func NewPlanSliceCommand() *cobra.Command {
 
 	cmd.Flags().String("plan", "", "Path to plan JSON file (or use --env to generate)")
 	cmd.Flags().StringP("env", "e", "", "Environment name (if generating plan)")
+	registerEnvCompletion(cmd)
 	cmd.Flags().String("output-dir", "", "Directory to write host plans (default: stdout)")
 
 	return cmd
@@ -498,3 +498,51 @@ environments:
 		t.Errorf("JSON output should contain step 'ResolveDockerfile', got:\n%s", jsonOutput)
 	}
 }
+
+func TestPlanCommand_GlobalOutputYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "stagecraft.yml")
+
+	configContent := `project:
+  name: test-app
+environments:
+  staging:
+    driver: local
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	originalDir, _ := os.Getwd()
+	defer func() {
+		if err := os.Chdir(originalDir); err != nil {
+			t.Logf("failed to restore directory: %v", err)
+		}
+	}()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewPlanCommand())
+
+	out, err := executeCommandForGolden(root, "plan", "--env", "staging", "--output", "yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(out, "env: staging\nversion: unknown\nphases:\n") {
+		t.Errorf("expected YAML plan, got:\n%s", out)
+	}
+
+	// An explicit --format takes precedence over --output.
+	root = newTestRootCommand()
+	root.AddCommand(NewPlanCommand())
+
+	out, err = executeCommandForGolden(root, "plan", "--env", "staging", "--output", "yaml", "--format", "text")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "Environment: staging\n") {
+		t.Errorf("expected text plan when --format is explicit, got:\n%s", out)
+	}
+}
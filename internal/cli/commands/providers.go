@@ -0,0 +1,351 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/providers/plugin"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/providers/backend"
+	"stagecraft/pkg/providers/ci"
+	"stagecraft/pkg/providers/cloud"
+	"stagecraft/pkg/providers/frontend"
+	"stagecraft/pkg/providers/migration"
+	"stagecraft/pkg/providers/network"
+	"stagecraft/pkg/providers/secrets"
+)
+
+// Feature: CLI_PROVIDERS
+// Spec: spec/commands/providers.md
+
+// Provider sources reported by `providers list`.
+const (
+	providerSourceBuiltin = "builtin"
+	providerSourcePlugin  = "plugin"
+)
+
+// NewProvidersCommand returns the `stagecraft providers` command group.
+func NewProvidersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "providers",
+		Short: "Inspect registered providers",
+		Long:  "Commands for inspecting the built-in and plugin providers known to this build",
+	}
+
+	cmd.AddCommand(NewProvidersListCommand())
+
+	return cmd
+}
+
+// NewProvidersListCommand returns `stagecraft providers list`.
+func NewProvidersListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List registered providers by category",
+		Long: `List every registered provider grouped by category, with its version,
+source (builtin or plugin), capability flags and a summary of its config keys.
+
+Plugins from the project's .stagecraft/plugins directory are included when
+a config file is present.`,
+		Args: cobra.NoArgs,
+		RunE: runProvidersList,
+	}
+}
+
+func runProvidersList(cmd *cobra.Command, args []string) error {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("providers list: resolving flags: %w", err)
+	}
+
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	exists, err := config.Exists(flags.Config)
+	if err != nil {
+		return fmt.Errorf("providers list: checking config: %w", err)
+	}
+	if exists {
+		if err := plugin.LoadProject(filepath.Dir(flags.Config)); err != nil {
+			return fmt.Errorf("providers list: loading provider plugins: %w", err)
+		}
+	}
+
+	view := newProvidersListView()
+	return output.Render(cmd.OutOrStdout(), format, view, func(out io.Writer) error {
+		return displayProvidersList(out, view)
+	})
+}
+
+// providersListView is the structured (--output json|yaml) form of `providers list`.
+type providersListView struct {
+	Categories []providerCategoryView `json:"categories" yaml:"categories"`
+}
+
+// providerCategoryView lists the providers registered for one kind.
+type providerCategoryView struct {
+	Kind      string         `json:"kind" yaml:"kind"`
+	Providers []providerView `json:"providers" yaml:"providers"`
+}
+
+// providerView is the structured form of a single provider.
+type providerView struct {
+	ID           string            `json:"id" yaml:"id"`
+	Name         string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Version      string            `json:"version,omitempty" yaml:"version,omitempty"`
+	Source       string            `json:"source" yaml:"source"`
+	Description  string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Experimental bool              `json:"experimental" yaml:"experimental"`
+	Capabilities []string          `json:"capabilities" yaml:"capabilities"`
+	Config       []configFieldView `json:"config" yaml:"config"`
+}
+
+// configFieldView summarizes one top-level key of a provider config block.
+type configFieldView struct {
+	Key  string `json:"key" yaml:"key"`
+	Type string `json:"type" yaml:"type"`
+}
+
+// providerMetadata mirrors the ProviderMetadata struct every provider
+// package declares, so each can be converted to it directly.
+type providerMetadata struct {
+	Name         string
+	Description  string
+	Version      string
+	Author       string
+	Experimental bool
+	ConfigSchema any
+}
+
+// newProvidersListView snapshots every registry in lexicographic kind order.
+func newProvidersListView() providersListView {
+	var view providersListView
+	add := func(kind string, providers []providerView) {
+		view.Categories = append(view.Categories, providerCategoryView{Kind: kind, Providers: providers})
+	}
+
+	add("backend", collectProviders(backend.List(), func(p backend.BackendProvider) (string, *providerMetadata, []string) {
+		var meta *providerMetadata
+		if mp, ok := p.(backend.MetadataProvider); ok {
+			m := providerMetadata(mp.Metadata())
+			meta = &m
+		}
+		return p.ID(), meta, nil
+	}))
+	add("ci", collectProviders(ci.List(), func(p ci.CIProvider) (string, *providerMetadata, []string) {
+		var meta *providerMetadata
+		if mp, ok := p.(ci.MetadataProvider); ok {
+			m := providerMetadata(mp.Metadata())
+			meta = &m
+		}
+		return p.ID(), meta, nil
+	}))
+	add("cloud", collectProviders(cloud.List(), func(p cloud.CloudProvider) (string, *providerMetadata, []string) {
+		var meta *providerMetadata
+		if mp, ok := p.(cloud.MetadataProvider); ok {
+			m := providerMetadata(mp.Metadata())
+			meta = &m
+		}
+		return p.ID(), meta, cloudCapabilities(p)
+	}))
+	add("frontend", collectProviders(frontend.List(), func(p frontend.FrontendProvider) (string, *providerMetadata, []string) {
+		var meta *providerMetadata
+		if mp, ok := p.(frontend.MetadataProvider); ok {
+			m := providerMetadata(mp.Metadata())
+			meta = &m
+		}
+		return p.ID(), meta, nil
+	}))
+	add("migration", collectProviders(migration.List(), func(e migration.Engine) (string, *providerMetadata, []string) {
+		var meta *providerMetadata
+		if mp, ok := e.(migration.MetadataProvider); ok {
+			m := providerMetadata(mp.Metadata())
+			meta = &m
+		}
+		return e.ID(), meta, nil
+	}))
+	add("network", collectProviders(network.List(), func(p network.NetworkProvider) (string, *providerMetadata, []string) {
+		var meta *providerMetadata
+		if mp, ok := p.(network.MetadataProvider); ok {
+			m := providerMetadata(mp.Metadata())
+			meta = &m
+		}
+		return p.ID(), meta, networkCapabilities(p)
+	}))
+	add("secrets", collectProviders(secrets.List(), func(p secrets.SecretsProvider) (string, *providerMetadata, []string) {
+		var meta *providerMetadata
+		if mp, ok := p.(secrets.MetadataProvider); ok {
+			m := providerMetadata(mp.Metadata())
+			meta = &m
+		}
+		return p.ID(), meta, nil
+	}))
+
+	return view
+}
+
+// collectProviders builds provider views from a registry listing, which is
+// already sorted by ID.
+func collectProviders[P any](providers []P, describe func(P) (string, *providerMetadata, []string)) []providerView {
+	views := make([]providerView, 0, len(providers))
+	for _, p := range providers {
+		id, meta, capabilities := describe(p)
+		if capabilities == nil {
+			capabilities = []string{}
+		}
+		view := providerView{
+			ID:           id,
+			Source:       providerSource(p),
+			Capabilities: capabilities,
+			Config:       []configFieldView{},
+		}
+		if meta != nil {
+			view.Name = meta.Name
+			view.Version = meta.Version
+			view.Description = meta.Description
+			view.Experimental = meta.Experimental
+			view.Config = summarizeConfigSchema(meta.ConfigSchema)
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// providerSource reports whether p was registered from a project plugin.
+func providerSource(p any) string {
+	switch p.(type) {
+	case *plugin.CloudProvider, *plugin.BackendProvider:
+		return providerSourcePlugin
+	default:
+		return providerSourceBuiltin
+	}
+}
+
+// cloudCapabilities lists the optional cloud interfaces p implements.
+func cloudCapabilities(p cloud.CloudProvider) []string {
+	var caps []string
+	if _, ok := p.(cloud.DesiredHostsProvider); ok {
+		caps = append(caps, "desired_hosts")
+	}
+	if _, ok := p.(cloud.FirewallProvider); ok {
+		caps = append(caps, "firewall")
+	}
+	if _, ok := p.(cloud.ReservedIPProvider); ok {
+		caps = append(caps, "reserved_ip")
+	}
+	return caps
+}
+
+// networkCapabilities lists the optional network interfaces p implements.
+func networkCapabilities(p network.NetworkProvider) []string {
+	var caps []string
+	if _, ok := p.(network.FQDNResolver); ok {
+		caps = append(caps, "fqdn")
+	}
+	if _, ok := p.(network.Leaver); ok {
+		caps = append(caps, "leave")
+	}
+	if _, ok := p.(network.PolicyValidator); ok {
+		caps = append(caps, "policy")
+	}
+	if _, ok := p.(network.Reauthenticator); ok {
+		caps = append(caps, "reauth")
+	}
+	return caps
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// summarizeConfigSchema lists the top-level yaml keys of a config struct in
+// declaration order. Non-struct schemas yield no keys.
+func summarizeConfigSchema(schema any) []configFieldView {
+	fields := []configFieldView{}
+	if schema == nil {
+		return fields
+	}
+	t := reflect.TypeOf(schema)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		key := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = strings.ToLower(f.Name)
+		}
+		fields = append(fields, configFieldView{Key: key, Type: configTypeName(f.Type)})
+	}
+	return fields
+}
+
+// configTypeName describes a config field type in YAML terms.
+func configTypeName(t reflect.Type) string {
+	if t == durationType {
+		return "duration"
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return configTypeName(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list of " + configTypeName(t.Elem())
+	case reflect.Map:
+		return "map of " + configTypeName(t.Elem())
+	case reflect.Struct:
+		return "object"
+	default:
+		return "any"
+	}
+}
+
+func displayProvidersList(out io.Writer, view providersListView) error {
+	_, _ = fmt.Fprintf(out, "%-10s %-14s %-8s %-8s %s\n", "KIND", "ID", "VERSION", "SOURCE", "CAPABILITIES")
+	for _, category := range view.Categories {
+		for _, p := range category.Providers {
+			_, _ = fmt.Fprintf(out, "%-10s %-14s %-8s %-8s %s\n",
+				category.Kind, p.ID, dashIfEmpty(p.Version), p.Source, dashIfEmpty(strings.Join(p.Capabilities, ", ")))
+		}
+	}
+	return nil
+}
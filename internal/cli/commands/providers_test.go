@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Feature: CLI_PROVIDERS
+// Spec: spec/commands/providers.md
+
+func runProvidersListJSON(t *testing.T, args ...string) providersListView {
+	t.Helper()
+
+	root := newTestRootCommand()
+	root.AddCommand(NewProvidersCommand())
+
+	out, err := executeCommandForGolden(root, append([]string{"providers", "list", "--output", "json"}, args...)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var view providersListView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("decoding output: %v\n%s", err, out)
+	}
+	return view
+}
+
+func findProviderView(view providersListView, kind, id string) *providerView {
+	for _, c := range view.Categories {
+		if c.Kind != kind {
+			continue
+		}
+		for i := range c.Providers {
+			if c.Providers[i].ID == id {
+				return &c.Providers[i]
+			}
+		}
+	}
+	return nil
+}
+
+func TestProvidersListCommand_JSONListsBuiltinsWithCapabilities(t *testing.T) {
+	setupIsolatedStateTestEnv(t)
+
+	view := runProvidersListJSON(t, "--config", filepath.Join(t.TempDir(), "missing.yml"))
+
+	var kinds []string
+	for _, c := range view.Categories {
+		kinds = append(kinds, c.Kind)
+	}
+	want := []string{"backend", "ci", "cloud", "frontend", "migration", "network", "secrets"}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("expected categories %v, got %v", want, kinds)
+	}
+
+	ts := findProviderView(view, "network", "tailscale")
+	if ts == nil {
+		t.Fatal("expected tailscale provider in listing")
+	}
+	if ts.Version != "v1" || ts.Source != providerSourceBuiltin {
+		t.Errorf("unexpected tailscale metadata: %+v", ts)
+	}
+	if !reflect.DeepEqual(ts.Capabilities, []string{"fqdn", "leave", "policy", "reauth"}) {
+		t.Errorf("unexpected tailscale capabilities: %v", ts.Capabilities)
+	}
+	found := false
+	for _, f := range ts.Config {
+		if f.Key == "auth_key_env" && f.Type == "string" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected auth_key_env in tailscale config summary, got %+v", ts.Config)
+	}
+
+	do := findProviderView(view, "cloud", "digitalocean")
+	if do == nil || len(do.Capabilities) == 0 || do.Capabilities[0] != "desired_hosts" {
+		t.Errorf("expected digitalocean with desired_hosts capability, got %+v", do)
+	}
+}
+
+func TestProvidersListCommand_IncludesProjectPlugins(t *testing.T) {
+	setupIsolatedStateTestEnv(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "stagecraft.yml")
+	if err := os.WriteFile(configPath, []byte("project:\n  name: demo\n"), 0o600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	pluginDir := filepath.Join(projectDir, ".stagecraft", "plugins", "acme")
+	if err := os.MkdirAll(pluginDir, 0o750); err != nil {
+		t.Fatalf("creating plugin dir: %v", err)
+	}
+	manifest := `{"id":"providers-list-acme","kind":"cloud","command":"./acme","version":"0.3.1","description":"ACME fleet"}`
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.json"), []byte(manifest), 0o600); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+
+	view := runProvidersListJSON(t, "--config", configPath)
+
+	p := findProviderView(view, "cloud", "providers-list-acme")
+	if p == nil {
+		t.Fatal("expected plugin provider in listing")
+	}
+	if p.Source != providerSourcePlugin || p.Version != "0.3.1" || p.Description != "ACME fleet" {
+		t.Errorf("unexpected plugin metadata: %+v", p)
+	}
+}
+
+func TestProvidersListCommand_Table(t *testing.T) {
+	setupIsolatedStateTestEnv(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewProvidersCommand())
+
+	out, err := executeCommandForGolden(root, "providers", "list", "--config", filepath.Join(t.TempDir(), "missing.yml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "KIND") {
+		t.Errorf("expected table header, got:\n%s", out)
+	}
+	if !strings.Contains(out, "fqdn, leave, policy, reauth") {
+		t.Errorf("expected tailscale capabilities in table, got:\n%s", out)
+	}
+}
+
+func TestSummarizeConfigSchema(t *testing.T) {
+	type nested struct {
+		Enabled bool `yaml:"enabled"`
+	}
+	type schema struct {
+		Token    string            `yaml:"token_env"`
+		Count    int               `yaml:"count,omitempty"`
+		Timeout  time.Duration     `yaml:"timeout"`
+		Tags     []string          `yaml:"tags"`
+		Hosts    map[string]nested `yaml:"hosts"`
+		Policy   *nested           `yaml:"policy"`
+		Internal string            `yaml:"-"`
+		Plain    string
+		hidden   string
+	}
+
+	got := summarizeConfigSchema(schema{hidden: "x"})
+	want := []configFieldView{
+		{Key: "token_env", Type: "string"},
+		{Key: "count", Type: "int"},
+		{Key: "timeout", Type: "duration"},
+		{Key: "tags", Type: "list of string"},
+		{Key: "hosts", Type: "map of object"},
+		{Key: "policy", Type: "object"},
+		{Key: "plain", Type: "string"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("summarizeConfigSchema() = %+v, want %+v", got, want)
+	}
+
+	if got := summarizeConfigSchema(nil); len(got) != 0 {
+		t.Errorf("expected no fields for nil schema, got %+v", got)
+	}
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cache"
+	"stagecraft/internal/core/audit"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: CLI_RECONCILE
+// Spec: spec/commands/reconcile.md
+
+// NewReconcileCommand returns the `stagecraft reconcile` command.
+func NewReconcileCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Deploy an environment from a git ref if it has drifted",
+		Long: `Reconcile an environment with a git ref.
+
+The ref is resolved to a commit and compared with the environment's current
+release. If the environment is not already running that commit, the ref is
+checked out into a temporary git worktree and deployed from there, exactly
+as 'stagecraft deploy' would. The working copy is never modified.
+
+Intended for pull-based deployment loops, e.g. a systemd timer on the host:
+
+  stagecraft reconcile --env prod --ref origin/main`,
+		Args: cobra.NoArgs,
+		RunE: runReconcile,
+	}
+
+	cmd.Flags().String("ref", "", "Git ref to deploy (branch, tag, or commit; required)")
+	cmd.Flags().Bool("fetch", true, "Run 'git fetch' before resolving the ref")
+	_ = cmd.MarkFlagRequired("ref")
+
+	return needsNetwork(cmd)
+}
+
+func runReconcile(cmd *cobra.Command, args []string) error {
+	return runReconcileWithPhases(cmd, args, defaultPhaseFns)
+}
+
+// runReconcileWithPhases resolves the ref, skips up-to-date environments,
+// and otherwise runs the deploy pipeline from a temporary worktree.
+func runReconcileWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("resolving flags: %w", err)
+	}
+	if flags.Env == "" {
+		return fmt.Errorf("environment is required; use --env flag")
+	}
+
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
+
+	ref, _ := cmd.Flags().GetString("ref")
+	fetch, _ := cmd.Flags().GetBool("fetch")
+	git := gitRunner{runner: newRunner()}
+
+	top, err := git.output(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("reconcile must run inside a git checkout: %w", err)
+	}
+
+	if fetch {
+		logger.Info("Fetching", logging.NewField("ref", ref))
+		if _, err := git.output(ctx, "fetch", "--quiet"); err != nil {
+			return fmt.Errorf("fetching: %w", err)
+		}
+	}
+
+	sha, err := git.output(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return fmt.Errorf("resolving ref %q: %w", ref, err)
+	}
+
+	// Compare with live state before touching anything
+	if current, err := state.NewDefaultManager().GetCurrentRelease(ctx, flags.Env); err == nil &&
+		releaseUpToDate(current, sha) {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Environment %s is up to date at %s (release %s)\n",
+			flags.Env, shortSHA(sha), current.ID)
+		return nil
+	}
+
+	configPath, err := configPathInRepo(top, flags.Config)
+	if err != nil {
+		return err
+	}
+
+	// Everything the deploy persists must outlive the worktree: state,
+	// audit, and cache paths are pinned here, bundles and SBOMs go to the
+	// output directory. Rendered compose files are regenerated on every
+	// deploy and travel in the bundle, so they stay in the worktree.
+	restoreEnv, err := pinStatePaths()
+	if err != nil {
+		return err
+	}
+	defer restoreEnv()
+
+	worktree, err := os.MkdirTemp("", "stagecraft-reconcile-")
+	if err != nil {
+		return fmt.Errorf("creating worktree directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(worktree)
+	}()
+
+	logger.Info("Checking out ref",
+		logging.NewField("ref", ref),
+		logging.NewField("commit_sha", sha),
+	)
+	if _, err := git.output(ctx, "worktree", "add", "--detach", "--force", worktree, sha); err != nil {
+		return fmt.Errorf("creating worktree for %s: %w", shortSHA(sha), err)
+	}
+	defer func() {
+		if _, err := git.output(context.Background(), "-C", top, "worktree", "remove", "--force", worktree); err != nil {
+			logger.Warn("Failed to remove worktree", logging.NewField("path", worktree), logging.NewField("error", err.Error()))
+		}
+	}()
+
+	origDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting working directory: %w", err)
+	}
+	if err := os.Chdir(worktree); err != nil {
+		return fmt.Errorf("entering worktree: %w", err)
+	}
+	defer func() {
+		_ = os.Chdir(origDir)
+	}()
+
+	if err := cmd.Flags().Set("config", configPath); err != nil {
+		return fmt.Errorf("setting config path: %w", err)
+	}
+
+	return runDeployWithOutputDir(cmd, fns, origDir)
+}
+
+// releaseUpToDate reports whether r deployed commit sha completely.
+func releaseUpToDate(r *state.Release, sha string) bool {
+	return r.CommitSHA == sha && releaseFullyDeployed(r)
+}
+
+// releaseFullyDeployed reports whether every phase of r completed.
+func releaseFullyDeployed(r *state.Release) bool {
+	for _, phase := range allPhasesCommon() {
+		if r.Phases[phase] != state.StatusCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// configPathInRepo returns configPath relative to the repository root so it
+// can be resolved inside a worktree.
+func configPathInRepo(top, configPath string) (string, error) {
+	abs, err := filepath.Abs(configPath)
+	if err != nil {
+		return "", fmt.Errorf("resolving config path: %w", err)
+	}
+	// Compare against the resolved root; git reports symlink-free paths.
+	if resolved, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		abs = filepath.Join(resolved, filepath.Base(abs))
+	}
+
+	rel, err := filepath.Rel(top, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("config %s is outside the git repository %s", configPath, top)
+	}
+	return rel, nil
+}
+
+// pinStatePaths makes the state, audit, and cache locations absolute for
+// the duration of a reconcile, so the deploy run from the worktree still
+// reads and writes the repository's files. Bundles and SBOMs are kept out
+// of the worktree by the deploy output directory instead. The returned func
+// restores the previous environment.
+func pinStatePaths() (func(), error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting working directory: %w", err)
+	}
+
+	var restores []func()
+	restore := func() {
+		for _, fn := range restores {
+			fn()
+		}
+	}
+
+	for _, file := range []struct{ key, def string }{
+		{"STAGECRAFT_AUDIT_FILE", audit.DefaultPath},
+		{"STAGECRAFT_STATE_FILE", state.DefaultStatePath},
+		// Defaults to the user cache directory, so only a relative override
+		// needs pinning.
+		{cache.DirEnv, ""},
+	} {
+		key := file.key
+		prev, set := os.LookupEnv(key)
+		path := prev
+		if path == "" {
+			path = file.def
+		}
+		if path == "" || filepath.IsAbs(path) {
+			continue
+		}
+
+		if err := os.Setenv(key, filepath.Join(cwd, path)); err != nil {
+			restore()
+			return nil, fmt.Errorf("setting %s: %w", key, err)
+		}
+		restores = append(restores, func() {
+			if set {
+				_ = os.Setenv(key, prev)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		})
+	}
+
+	return restore, nil
+}
+
+// gitRunner runs git commands and returns trimmed stdout.
+type gitRunner struct {
+	runner executil.Runner
+}
+
+func (g gitRunner) output(ctx context.Context, args ...string) (string, error) {
+	result, err := g.runner.Run(ctx, executil.NewCommand("git", args...))
+	if err != nil {
+		if result != nil {
+			if stderr := strings.TrimSpace(string(result.Stderr)); stderr != "" {
+				return "", fmt.Errorf("git %s: %s", args[0], stderr)
+			}
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(result.Stdout)), nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
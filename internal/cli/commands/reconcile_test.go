@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/bundle"
+	"stagecraft/internal/cache"
+	"stagecraft/internal/containerruntime"
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/audit"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: CLI_RECONCILE
+// Spec: spec/commands/reconcile.md
+
+const reconcileConfig = `project:
+  name: test-app
+environments:
+  staging:
+    driver: local
+`
+
+// initReconcileRepo turns dir into a git repository with one commit
+// containing the config, and returns that commit's SHA.
+func initReconcileRepo(t *testing.T, dir string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "stagecraft.yml"), []byte(reconcileConfig), 0o600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	runGit(t, dir, "init", "--quiet")
+	return commitAll(t, dir, "initial")
+}
+
+func commitAll(t *testing.T, dir, message string) string {
+	t.Helper()
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", message)
+	return runGit(t, dir, "rev-parse", "HEAD")
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func setupReconcileCommand(fns PhaseFns) *cobra.Command {
+	cmd := NewReconcileCommand()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runReconcileWithPhases(cmd, args, fns)
+	}
+	return cmd
+}
+
+func executeReconcile(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	root := newTestRootCommand()
+	root.AddCommand(setupReconcileCommand(noopPhaseFns()))
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(args)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestReconcileCommand_DeploysRefThenSkipsWhenUpToDate(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	sha := initReconcileRepo(t, env.TempDir)
+
+	if _, err := executeReconcile(t, "reconcile", "--env", "staging", "--ref", "HEAD", "--fetch=false"); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	current, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("expected a release, got %v", err)
+	}
+	if current.CommitSHA != sha || current.Version != sha {
+		t.Errorf("expected release at %s, got version %q commit %q", sha, current.Version, current.CommitSHA)
+	}
+
+	out, err := executeReconcile(t, "reconcile", "--env", "staging", "--ref", "HEAD", "--fetch=false")
+	if err != nil {
+		t.Fatalf("second reconcile failed: %v", err)
+	}
+	if !strings.Contains(out, "is up to date") {
+		t.Errorf("expected up-to-date message, got:\n%s", out)
+	}
+
+	releases, _ := env.Manager.ListReleases(context.Background(), "staging")
+	if len(releases) != 1 {
+		t.Fatalf("expected 1 release after no-op reconcile, got %d", len(releases))
+	}
+
+	// The worktree is cleaned up and the working copy untouched.
+	if list := runGit(t, env.TempDir, "worktree", "list"); strings.Count(list, "\n") != 0 {
+		t.Errorf("expected only the main worktree, got:\n%s", list)
+	}
+	if status := runGit(t, env.TempDir, "status", "--porcelain", "--untracked-files=no"); status != "" {
+		t.Errorf("expected clean working copy, got:\n%s", status)
+	}
+}
+
+func TestReconcileCommand_DeploysNewCommit(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	first := initReconcileRepo(t, env.TempDir)
+
+	if _, err := executeReconcile(t, "reconcile", "--env", "staging", "--ref", first, "--fetch=false"); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(env.TempDir, "README.md"), []byte("hello\n"), 0o600); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+	second := commitAll(t, env.TempDir, "second")
+
+	if _, err := executeReconcile(t, "reconcile", "--env", "staging", "--ref", "HEAD", "--fetch=false"); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	current, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+	if current.CommitSHA != second {
+		t.Errorf("expected release at %s, got %s", second, current.CommitSHA)
+	}
+}
+
+func TestReconcileCommand_KeepsOutputsInRepository(t *testing.T) {
+	env := setupBundleTestEnv(t)
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	runGit(t, env.TempDir, "init", "--quiet")
+	commitAll(t, env.TempDir, "initial")
+
+	// Generate an SBOM the way the build phase does.
+	fns := noopPhaseFns()
+	fns.Build = func(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
+		plan.Metadata["built_images"] = map[string]string{"backend": "test-app:v1"}
+		runner := &scriptedRunner{outputs: map[string]string{
+			"syft docker:test-app:v1 -o spdx-json -q": `{"spdxVersion": "SPDX-2.3"}`,
+		}}
+		workdir, _ := plan.Metadata["workdir"].(string)
+		build := &config.BuildConfig{SBOM: true}
+		return scanBuiltImages(ctx, runner, containerruntime.Docker, build, plan, deployOutputDir(plan, workdir), logger)
+	}
+	root := newTestRootCommand()
+	root.AddCommand(setupReconcileCommand(fns))
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"reconcile", "--env", "staging", "--ref", "HEAD", "--fetch=false"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	release, err := env.Manager.GetCurrentRelease(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+	if release.Bundle == nil {
+		t.Fatal("expected the release to record its bundle")
+	}
+	wantLocation := filepath.Join(env.TempDir, bundle.DefaultDir, bundle.FileName(release.ID))
+	if release.Bundle.Location != wantLocation {
+		t.Errorf("expected bundle at %s, got %s", wantLocation, release.Bundle.Location)
+	}
+	// The worktree is gone; the bundle and SBOM must not have gone with it.
+	if _, err := os.Stat(release.Bundle.Location); err != nil {
+		t.Errorf("expected bundle to outlive the worktree: %v", err)
+	}
+	if len(release.Images) != 1 || release.Images[0].SBOM == "" {
+		t.Fatalf("expected the release to record an SBOM, got %+v", release.Images)
+	}
+	if _, err := os.Stat(filepath.Join(env.TempDir, release.Images[0].SBOM)); err != nil {
+		t.Errorf("expected SBOM to outlive the worktree: %v", err)
+	}
+}
+
+func TestReconcileCommand_UnknownRef(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	initReconcileRepo(t, env.TempDir)
+
+	_, err := executeReconcile(t, "reconcile", "--env", "staging", "--ref", "does-not-exist", "--fetch=false")
+	if err == nil || !strings.Contains(err.Error(), `resolving ref "does-not-exist"`) {
+		t.Fatalf("expected ref resolution error, got %v", err)
+	}
+}
+
+func TestReconcileCommand_RequiresRef(t *testing.T) {
+	setupIsolatedStateTestEnv(t)
+
+	_, err := executeReconcile(t, "reconcile", "--env", "staging")
+	if err == nil || !strings.Contains(err.Error(), `"ref" not set`) {
+		t.Fatalf("expected required flag error, got %v", err)
+	}
+}
+
+func TestPinStatePaths_PinsRelativeOverridesOnly(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	t.Setenv(cache.DirEnv, ".cache/stagecraft")
+	t.Setenv("STAGECRAFT_AUDIT_FILE", "")
+
+	restore, err := pinStatePaths()
+	if err != nil {
+		t.Fatalf("pinStatePaths: %v", err)
+	}
+	wd, _ := os.Getwd()
+	if got := os.Getenv(cache.DirEnv); got != filepath.Join(wd, ".cache/stagecraft") {
+		t.Errorf("expected cache dir pinned under %s, got %q", wd, got)
+	}
+	if got := os.Getenv("STAGECRAFT_AUDIT_FILE"); got != filepath.Join(wd, audit.DefaultPath) {
+		t.Errorf("expected default audit path pinned, got %q", got)
+	}
+	if got := os.Getenv("STAGECRAFT_STATE_FILE"); got != env.StateFile {
+		t.Errorf("expected absolute state path untouched, got %q", got)
+	}
+
+	restore()
+	if got := os.Getenv(cache.DirEnv); got != ".cache/stagecraft" {
+		t.Errorf("expected cache dir restored, got %q", got)
+	}
+}
@@ -18,10 +18,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/core/audit"
 	"stagecraft/internal/core/state"
 )
 
@@ -49,6 +54,7 @@ func NewReleasesCommand() *cobra.Command {
 
 	cmd.AddCommand(NewReleasesListCommand())
 	cmd.AddCommand(NewReleasesShowCommand())
+	cmd.AddCommand(NewReleasesNoteCommand())
 
 	return cmd
 }
@@ -72,6 +78,18 @@ func NewReleasesShowCommand() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  runReleasesShow,
 	}
+	cmd.Flags().Bool("timeline", false, "Also show the release's event timeline")
+	return cmd
+}
+
+// NewReleasesNoteCommand returns `stagecraft releases note`.
+func NewReleasesNoteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "note <release-id> <message>",
+		Short: "Add an operator note to a release's timeline",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  runReleasesNote,
+	}
 	return cmd
 }
 
@@ -114,7 +132,19 @@ func runReleasesList(cmd *cobra.Command, args []string) error {
 		showEnvColumn = true
 	}
 
-	return displayReleasesList(cmd, releases, showEnvColumn)
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	view := releasesListView{Releases: make([]releaseView, 0, len(releases))}
+	for _, r := range releases {
+		view.Releases = append(view.Releases, newReleaseView(r))
+	}
+
+	return output.Render(cmd.OutOrStdout(), format, view, func(io.Writer) error {
+		return displayReleasesList(cmd, releases, showEnvColumn)
+	})
 }
 
 func runReleasesShow(cmd *cobra.Command, args []string) error {
@@ -125,6 +155,16 @@ func runReleasesShow(cmd *cobra.Command, args []string) error {
 
 	releaseID := args[0]
 
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("resolving flags: %w", err)
+	}
+
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
 	// Initialize state manager
 	stateMgr := state.NewDefaultManager()
 
@@ -137,8 +177,165 @@ func runReleasesShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting release: %w", err)
 	}
 
+	timeline, _ := cmd.Flags().GetBool("timeline")
+	view := newReleaseView(release)
+	if timeline {
+		view.Events = newReleaseEventViews(release.Events)
+	}
+
 	// Display release details
-	return displayReleaseShow(cmd, release)
+	return output.Render(cmd.OutOrStdout(), format, view, func(io.Writer) error {
+		if err := displayReleaseShow(cmd, release); err != nil {
+			return err
+		}
+		if timeline {
+			displayReleaseTimeline(cmd.OutOrStdout(), release.Events)
+		}
+		return nil
+	})
+}
+
+func runReleasesNote(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	releaseID := args[0]
+	message := strings.TrimSpace(strings.Join(args[1:], " "))
+	if message == "" {
+		return fmt.Errorf("releases note: message is empty")
+	}
+
+	event := state.ReleaseEvent{
+		Type:    state.EventNote,
+		Actor:   audit.ResolveActor(),
+		Message: message,
+	}
+	if err := state.NewDefaultManager().AddEvent(ctx, releaseID, event); err != nil {
+		if errors.Is(err, state.ErrReleaseNotFound) {
+			return fmt.Errorf("release not found: %q", releaseID)
+		}
+		return fmt.Errorf("releases note: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✓ Added note to %s\n", releaseID)
+	return nil
+}
+
+// releasesListView is the structured (--output json|yaml) form of `releases list`.
+type releasesListView struct {
+	Releases []releaseView `json:"releases" yaml:"releases"`
+}
+
+// releaseView is the structured form of a single release.
+// Field order is the serialization order.
+type releaseView struct {
+	ID          string             `json:"id" yaml:"id"`
+	Environment string             `json:"environment" yaml:"environment"`
+	Version     string             `json:"version" yaml:"version"`
+	CommitSHA   string             `json:"commit_sha,omitempty" yaml:"commit_sha,omitempty"`
+	Timestamp   string             `json:"timestamp" yaml:"timestamp"`
+	PreviousID  string             `json:"previous_id,omitempty" yaml:"previous_id,omitempty"`
+	Status      string             `json:"status" yaml:"status"`
+	Phases      []releasePhaseView `json:"phases" yaml:"phases"`
+	Events      []releaseEventView `json:"events,omitempty" yaml:"events,omitempty"`
+}
+
+// releaseEventView is the structured form of a timeline event, shown with
+// `releases show --timeline`.
+type releaseEventView struct {
+	Time    string `json:"time" yaml:"time"`
+	Type    string `json:"type" yaml:"type"`
+	Phase   string `json:"phase,omitempty" yaml:"phase,omitempty"`
+	Status  string `json:"status,omitempty" yaml:"status,omitempty"`
+	Actor   string `json:"actor,omitempty" yaml:"actor,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// releasePhaseView is the structured form of a release phase.
+type releasePhaseView struct {
+	Phase  string            `json:"phase" yaml:"phase"`
+	Status string            `json:"status" yaml:"status"`
+	Hosts  []releaseHostView `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+}
+
+// releaseHostView is the structured form of one host's outcome for a phase.
+type releaseHostView struct {
+	Host       string `json:"host" yaml:"host"`
+	Status     string `json:"status" yaml:"status"`
+	Message    string `json:"message,omitempty" yaml:"message,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty" yaml:"duration_ms,omitempty"`
+}
+
+// newReleaseView converts a release to its structured view, listing
+// phases in canonical order.
+func newReleaseView(release *state.Release) releaseView {
+	view := releaseView{
+		ID:          release.ID,
+		Environment: release.Environment,
+		Version:     release.Version,
+		CommitSHA:   release.CommitSHA,
+		Timestamp:   release.Timestamp.UTC().Format(time.RFC3339),
+		PreviousID:  release.PreviousID,
+		Status:      calculateOverallStatus(release),
+		Phases:      make([]releasePhaseView, 0, len(releasePhasesOrder)),
+	}
+	for _, phase := range releasePhasesOrder {
+		status := release.Phases[phase]
+		if status == "" {
+			status = state.StatusPending
+		}
+		view.Phases = append(view.Phases, releasePhaseView{
+			Phase:  string(phase),
+			Status: string(status),
+			Hosts:  newReleaseHostViews(release, phase),
+		})
+	}
+	return view
+}
+
+// newReleaseHostViews returns the per-host outcomes of a phase, sorted by
+// host, or nil when none were recorded.
+func newReleaseHostViews(release *state.Release, phase state.ReleasePhase) []releaseHostView {
+	hosts := release.HostPhases[phase]
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	views := make([]releaseHostView, 0, len(names))
+	for _, name := range names {
+		hs := hosts[name]
+		views = append(views, releaseHostView{
+			Host:       name,
+			Status:     string(hs.Status),
+			Message:    hs.Message,
+			DurationMS: hs.DurationMS,
+		})
+	}
+	return views
+}
+
+// newReleaseEventViews converts a timeline to its structured form.
+func newReleaseEventViews(events []state.ReleaseEvent) []releaseEventView {
+	views := make([]releaseEventView, 0, len(events))
+	for _, e := range events {
+		views = append(views, releaseEventView{
+			Time:    e.Time.UTC().Format(time.RFC3339Nano),
+			Type:    string(e.Type),
+			Phase:   string(e.Phase),
+			Status:  string(e.Status),
+			Actor:   e.Actor,
+			Message: e.Message,
+		})
+	}
+	return views
 }
 
 // displayReleasesList displays releases in table format.
@@ -216,11 +413,46 @@ func displayReleaseShow(cmd *cobra.Command, release *state.Release) error {
 			status = state.StatusPending
 		}
 		_, _ = fmt.Fprintf(out, "  %-15s %s\n", phase+":", status)
+
+		// Multi-host phases list each host, so partial failures are visible
+		for _, host := range newReleaseHostViews(release, phase) {
+			line := fmt.Sprintf("    %-13s %-10s %s", host.Host, host.Status, time.Duration(host.DurationMS)*time.Millisecond)
+			if host.Message != "" {
+				line += "  " + host.Message
+			}
+			_, _ = fmt.Fprintln(out, line)
+		}
 	}
 
 	return nil
 }
 
+// displayReleaseTimeline prints a release's events, oldest first.
+func displayReleaseTimeline(out io.Writer, events []state.ReleaseEvent) {
+	_, _ = fmt.Fprintf(out, "\nTimeline:\n")
+	if len(events) == 0 {
+		_, _ = fmt.Fprintf(out, "  (no events recorded)\n")
+		return
+	}
+
+	for _, e := range events {
+		var detail []string
+		if e.Phase != "" {
+			detail = append(detail, string(e.Phase))
+		}
+		if e.Status != "" {
+			detail = append(detail, string(e.Status))
+		}
+		switch {
+		case e.Actor != "" && e.Message != "":
+			detail = append(detail, e.Actor+": "+e.Message)
+		case e.Message != "":
+			detail = append(detail, e.Message)
+		}
+		_, _ = fmt.Fprintf(out, "  %s  %-15s %s\n", formatTimestamp(e.Time), e.Type, strings.Join(detail, " "))
+	}
+}
+
 // calculateOverallStatus calculates the overall status of a release based on phase statuses.
 // Iterates over the canonical ordered phase list and treats missing phases as not completed.
 func calculateOverallStatus(release *state.Release) string {
@@ -231,7 +463,7 @@ func calculateOverallStatus(release *state.Release) string {
 	for _, phase := range releasePhasesOrder {
 		status := release.Phases[phase]
 		switch status {
-		case state.StatusFailed:
+		case state.StatusFailed, state.StatusTimedOut:
 			hasFailed = true
 			allCompleted = false
 		case state.StatusRunning:
@@ -15,11 +15,15 @@ See https://www.gnu.org/licenses/ for license details.
 package commands
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/core/audit"
 	"stagecraft/internal/core/state"
 )
 
@@ -38,8 +42,8 @@ func TestNewReleasesCommand_HasExpectedMetadata(t *testing.T) {
 	}
 
 	// Check that subcommands exist
-	if len(cmd.Commands()) != 2 {
-		t.Fatalf("expected 2 subcommands, got %d", len(cmd.Commands()))
+	if len(cmd.Commands()) != 3 {
+		t.Fatalf("expected 3 subcommands, got %d", len(cmd.Commands()))
 	}
 
 	subcommandNames := make(map[string]bool)
@@ -56,6 +60,9 @@ func TestNewReleasesCommand_HasExpectedMetadata(t *testing.T) {
 	if !subcommandNames["show"] {
 		t.Fatalf("expected 'show' subcommand to exist")
 	}
+	if !subcommandNames["note"] {
+		t.Fatalf("expected 'note' subcommand to exist")
+	}
 }
 
 func TestReleasesList_EmptyState(t *testing.T) {
@@ -539,3 +546,162 @@ func TestReleasesList_OverallStatus_AllCompleted(t *testing.T) {
 		t.Fatalf("expected output to contain 'completed' status, got: %q", out)
 	}
 }
+
+func TestReleasesList_OutputJSON(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+
+	release, err := env.Manager.CreateRelease(env.Ctx, "prod", "v1.0.0", "commit1")
+	if err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewReleasesCommand())
+
+	out, err := executeCommandForGolden(root, "releases", "list", "--env", "prod", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var view releasesListView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("expected valid JSON, got error %v:\n%s", err, out)
+	}
+	if len(view.Releases) != 1 || view.Releases[0].ID != release.ID {
+		t.Fatalf("expected release %s, got %+v", release.ID, view.Releases)
+	}
+	if len(view.Releases[0].Phases) != len(releasePhasesOrder) {
+		t.Fatalf("expected %d phases, got %d", len(releasePhasesOrder), len(view.Releases[0].Phases))
+	}
+	if view.Releases[0].Phases[0].Phase != string(state.PhaseBuild) {
+		t.Fatalf("expected phases in canonical order, got %+v", view.Releases[0].Phases)
+	}
+}
+
+func TestReleasesShow_OutputYAML(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+
+	release, err := env.Manager.CreateRelease(env.Ctx, "prod", "v1.0.0", "commit1")
+	if err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewReleasesCommand())
+
+	out, err := executeCommandForGolden(root, "releases", "show", release.ID, "-o", "yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(out, "id: "+release.ID+"\nenvironment: prod\nversion: v1.0.0\n") {
+		t.Fatalf("expected stable YAML field order, got:\n%s", out)
+	}
+}
+
+func TestReleasesList_InvalidOutput(t *testing.T) {
+	_ = setupIsolatedStateTestEnv(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewReleasesCommand())
+
+	_, err := executeCommandForGolden(root, "releases", "list", "--output", "xml")
+	if !errors.Is(err, output.ErrInvalidFormat) {
+		t.Fatalf("expected ErrInvalidFormat, got %v", err)
+	}
+}
+
+func TestReleasesShow_Timeline(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	writeHooksConfig(t, env.TempDir, `  pre_rollout:
+    - command: ["true"]
+`)
+
+	if err := executeDeployWithPhases(noopPhaseFns(), "deploy", "--env", "staging", "--version", "v1.0.0"); err != nil {
+		t.Fatalf("deploy failed: %v", err)
+	}
+	release, err := env.Manager.GetCurrentRelease(env.Ctx, "staging")
+	if err != nil {
+		t.Fatalf("getting current release: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewReleasesCommand())
+	out, err := executeCommandForGolden(root, "releases", "note", release.ID, "rolled", "out", "during", "incident")
+	if err != nil {
+		t.Fatalf("releases note failed: %v\n%s", err, out)
+	}
+
+	root = newTestRootCommand()
+	root.AddCommand(NewReleasesCommand())
+	out, err = executeCommandForGolden(root, "releases", "show", release.ID, "--timeline")
+	if err != nil {
+		t.Fatalf("releases show failed: %v", err)
+	}
+
+	wants := []string{
+		"phase_started   build running",
+		"phase_finished  build completed",
+		"hook_executed   rollout pre_rollout[0] `true` succeeded",
+		"phase_finished  finalize completed",
+		"note            " + audit.ResolveActor() + ": rolled out during incident",
+	}
+	last := -1
+	for _, want := range wants {
+		i := strings.Index(out, want)
+		if i < 0 {
+			t.Fatalf("expected timeline to contain %q, got:\n%s", want, out)
+		}
+		if i < last {
+			t.Errorf("expected %q after the previous event, got:\n%s", want, out)
+		}
+		last = i
+	}
+}
+
+func TestReleasesShow_TimelineJSON(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+
+	release, err := env.Manager.CreateRelease(env.Ctx, "prod", "v1.0.0", "commit1")
+	if err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+	if err := env.Manager.AddEvent(env.Ctx, release.ID, state.ReleaseEvent{Type: state.EventNote, Actor: "alice", Message: "hello"}); err != nil {
+		t.Fatalf("AddEvent failed: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewReleasesCommand())
+	out, err := executeCommandForGolden(root, "releases", "show", release.ID, "--output", "json")
+	if err != nil {
+		t.Fatalf("releases show failed: %v", err)
+	}
+	if strings.Contains(out, `"events"`) {
+		t.Errorf("expected no events without --timeline, got:\n%s", out)
+	}
+
+	root = newTestRootCommand()
+	root.AddCommand(NewReleasesCommand())
+	out, err = executeCommandForGolden(root, "releases", "show", release.ID, "--output", "json", "--timeline")
+	if err != nil {
+		t.Fatalf("releases show failed: %v", err)
+	}
+	var view releaseView
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("expected valid JSON, got error %v:\n%s", err, out)
+	}
+	if len(view.Events) != 1 || view.Events[0].Type != "note" || view.Events[0].Actor != "alice" {
+		t.Errorf("expected the note in the timeline, got %+v", view.Events)
+	}
+}
+
+func TestReleasesNote_UnknownRelease(t *testing.T) {
+	setupIsolatedStateTestEnv(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewReleasesCommand())
+	_, err := executeCommandForGolden(root, "releases", "note", "rel-missing", "hello")
+	if err == nil || !strings.Contains(err.Error(), "release not found") {
+		t.Errorf("expected release not found, got %v", err)
+	}
+}
@@ -21,7 +21,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"stagecraft/internal/core"
+	coreplan "stagecraft/internal/core/plan"
 	"stagecraft/internal/core/state"
+	"stagecraft/internal/notify"
+	"stagecraft/internal/telemetry"
 	"stagecraft/pkg/config"
 	"stagecraft/pkg/logging"
 )
@@ -41,10 +44,22 @@ func NewRollbackCommand() *cobra.Command {
 	cmd.Flags().Bool("to-previous", false, "Rollback to immediately previous release")
 	cmd.Flags().String("to-release", "", "Rollback to specific release ID")
 	cmd.Flags().String("to-version", "", "Rollback to most recent release with matching version")
+	cmd.Flags().Bool("force-data-loss", false, "Roll back even past irreversible migrations")
+	addYesFlag(cmd)
+	_ = cmd.RegisterFlagCompletionFunc("to-release", CompleteReleaseIDs)
 
 	// Global flags (--config, --env, --verbose, --dry-run) are inherited from root
 
-	return cmd
+	return needsNetwork(cmd)
+}
+
+// rollbackQuestion is the confirmation asked before rolling back. current
+// may be nil.
+func rollbackQuestion(env string, current, target *state.Release) string {
+	if current == nil {
+		return fmt.Sprintf("Roll back %q to release %s (%s)?", env, target.ID, target.Version)
+	}
+	return fmt.Sprintf("Roll back %q from release %s (%s) to %s (%s)?", env, current.ID, current.Version, target.ID, target.Version)
 }
 
 // rollbackFlags contains the parsed rollback target flags.
@@ -161,7 +176,7 @@ func validateRollbackTarget(current, target *state.Release) error {
 
 // runRollbackWithPhases is the internal implementation that accepts PhaseFns for dependency injection.
 // This allows tests to inject custom phase functions without using global state.
-func runRollbackWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
+func runRollbackWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) (err error) {
 	ctx := cmd.Context()
 	if ctx == nil {
 		ctx = context.Background()
@@ -190,6 +205,11 @@ func runRollbackWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 		return fmt.Errorf("environment is required; use --env flag")
 	}
 
+	ctx, span := telemetry.StartSpan(ctx, "rollback", telemetry.AttrEnvironment.String(flags.Env))
+	defer func() {
+		telemetry.EndSpan(span, err)
+	}()
+
 	// Parse rollback flags first (before getting current release)
 	rollbackFlags, err := parseRollbackFlags(cmd)
 	if err != nil {
@@ -237,7 +257,16 @@ func runRollbackWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 	}
 
 	// Initialize logger
-	logger := logging.NewLogger(flags.Verbose)
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to leave irreversible migrations behind unless forced
+	forceDataLoss, _ := cmd.Flags().GetBool("force-data-loss")
+	if err := checkRollbackMigrations(ctx, cmd.ErrOrStderr(), stateMgr, target, forceDataLoss, logger); err != nil {
+		return err
+	}
 
 	logger.Info("Rolling back environment",
 		logging.NewField("env", flags.Env),
@@ -245,6 +274,9 @@ func runRollbackWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 		logging.NewField("target_version", target.Version),
 	)
 
+	notifier := newLifecycleNotifier(cmd, cfg, flags.Env, target.Version, target.CommitSHA, flags.DryRun, logger)
+	hookRunner := newPhaseHookRunner(cfg, auditCommandName(cmd), flags.Env, target.Version, target.CommitSHA, flags.DryRun, logger)
+
 	// Handle dry-run (BEFORE creating release)
 	if flags.DryRun {
 		logger.Info("Dry-run mode: would rollback to release",
@@ -253,18 +285,39 @@ func runRollbackWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 			logging.NewField("target_version", target.Version),
 			logging.NewField("target_commit", target.CommitSHA),
 		)
-		// Optionally generate plan to show what would happen (but don't execute)
+		// Show what the rollback would change (but don't execute)
 		planner := core.NewPlanner(cfg)
+		var next []state.PlanStep
 		plan, err := planner.PlanDeploy(flags.Env)
 		if err == nil {
 			logger.Debug("Would execute deployment plan",
 				logging.NewField("operations", len(plan.Operations)),
 			)
+			next, _ = coreplan.Fingerprint(plan)
+		}
+		diff := computeRollbackDiff(current, target, next)
+		diff.diffRollbackCompose(cfg, flags.Env)
+		if err := diff.Render(cmd.OutOrStdout()); err != nil {
+			return fmt.Errorf("rendering rollback diff: %w", err)
 		}
+		notifier.send(ctx, notify.EventStarted, "", nil)
+		logPhaseHooks(ctx, hookRunner)
 		// Do NOT create a release or write state in dry-run
 		return nil
 	}
 
+	if err := newConfirmer(cmd, false).Confirm(rollbackQuestion(flags.Env, current, target)); err != nil {
+		return fmt.Errorf("rollback: %w; nothing was changed", err)
+	}
+
+	// Record the attempt in the audit log once past dry-run, including
+	// failures; the release ID is filled in once the release exists.
+	var releaseID string
+	defer func() {
+		recordAudit(ctx, cmd, logger, flags.Env, releaseID, err)
+		notifier.finish(ctx, notify.EventRolledBack, releaseID, err)
+	}()
+
 	// Create new release with target's version/commit SHA (only in non-dry-run)
 	release, err := stateMgr.CreateRelease(ctx, flags.Env, target.Version, target.CommitSHA)
 	if err != nil {
@@ -274,17 +327,27 @@ func runRollbackWithPhases(cmd *cobra.Command, _ []string, fns PhaseFns) error {
 	logger.Info("Rollback release created",
 		logging.NewField("release_id", release.ID),
 	)
+	releaseID = release.ID
+	span.SetAttributes(
+		telemetry.AttrReleaseID.String(release.ID),
+		telemetry.AttrVersion.String(target.Version),
+	)
+	notifier.send(ctx, notify.EventStarted, release.ID, nil)
+	hookRunner.SetReleaseID(release.ID)
 
 	// Generate deployment plan
 	planner := core.NewPlanner(cfg)
+	_, planSpan := telemetry.StartSpan(ctx, "plan", telemetry.AttrEnvironment.String(flags.Env))
 	plan, err := planner.PlanDeploy(flags.Env)
+	telemetry.EndSpan(planSpan, err)
 	if err != nil {
 		markAllPhasesFailedCommon(ctx, stateMgr, release.ID, logger)
 		return fmt.Errorf("generating deployment plan: %w", err)
 	}
 
-	// Execute deployment phases using shared helper
-	err = executePhasesCommon(ctx, stateMgr, release.ID, plan, logger, fns)
+	// Execute deployment phases using shared helper, with configured
+	// pre/post hooks and step timeouts around each phase
+	err = executePhasesCommon(ctx, stateMgr, release.ID, plan, logger, withPhaseHooks(withPhaseTimeouts(fns, cfg.Engine), hookRunner))
 	if err != nil {
 		return fmt.Errorf("rollback deployment failed: %w", err)
 	}
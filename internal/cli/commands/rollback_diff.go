@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"stagecraft/internal/compose"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/engine"
+)
+
+// Feature: ROLLBACK_DIFF
+// Spec: spec/deploy/rollback-diff.md
+
+// imageChange is one service whose recorded image differs between the
+// current and the target release. An empty Before or After means the
+// service has no image recorded on that side.
+type imageChange struct {
+	Service string
+	Before  string
+	After   string
+}
+
+// migrationChange is one migration step that differs between the current
+// and the target release's plans.
+type migrationChange struct {
+	ID string
+
+	// Kind is "+" (added after the target), "-" (removed after the
+	// target), or "~" (inputs changed after the target).
+	Kind string
+}
+
+// rollbackDiff is what rolling back from Current to Target changes.
+type rollbackDiff struct {
+	Current *state.Release // nil when the environment has no current release
+	Target  *state.Release
+
+	Images          []imageChange
+	ImagesUnchanged int
+
+	Migrations []migrationChange
+
+	// Runs lists the migration steps the rollback plan executes.
+	Runs []string
+
+	// ComposeBefore and ComposeAfter are the compose config-hash of the
+	// file last written and of the file the rollback would write.
+	// ComposeErr is set instead when the file could not be rendered.
+	ComposeBefore string
+	ComposeAfter  string
+	ComposeErr    error
+}
+
+// computeRollbackDiff compares the images and migration steps recorded on
+// current and target. current may be nil. next is the fingerprint of the
+// plan the rollback executes and may be nil.
+func computeRollbackDiff(current, target *state.Release, next []state.PlanStep) *rollbackDiff {
+	d := &rollbackDiff{Current: current, Target: target}
+
+	var currentImages []state.BuiltImage
+	var currentPlan []state.PlanStep
+	if current != nil {
+		currentImages = current.Images
+		currentPlan = current.Plan
+	}
+
+	before := imageRefsByService(currentImages)
+	after := imageRefsByService(target.Images)
+	services := make([]string, 0, len(before)+len(after))
+	for svc := range before {
+		services = append(services, svc)
+	}
+	for svc := range after {
+		if _, ok := before[svc]; !ok {
+			services = append(services, svc)
+		}
+	}
+	sort.Strings(services)
+	for _, svc := range services {
+		if before[svc] == after[svc] {
+			d.ImagesUnchanged++
+			continue
+		}
+		d.Images = append(d.Images, imageChange{Service: svc, Before: before[svc], After: after[svc]})
+	}
+
+	then := migrationSteps(target.Plan)
+	now := migrationSteps(currentPlan)
+	for _, id := range sortedStepIDs(now) {
+		old, ok := then[id]
+		switch {
+		case !ok:
+			d.Migrations = append(d.Migrations, migrationChange{ID: id, Kind: "+"})
+		case old != now[id]:
+			d.Migrations = append(d.Migrations, migrationChange{ID: id, Kind: "~"})
+		}
+	}
+	for _, id := range sortedStepIDs(then) {
+		if _, ok := now[id]; !ok {
+			d.Migrations = append(d.Migrations, migrationChange{ID: id, Kind: "-"})
+		}
+	}
+	sort.SliceStable(d.Migrations, func(i, j int) bool { return d.Migrations[i].ID < d.Migrations[j].ID })
+
+	d.Runs = sortedStepIDs(migrationSteps(next))
+
+	return d
+}
+
+// diffRollbackCompose fills in the compose config-hash the rollback would
+// change: the header of the file last written against the file rendered
+// for the target version with the current config.
+func (d *rollbackDiff) diffRollbackCompose(cfg *config.Config, env string) {
+	var rendered *renderedCompose
+	var err error
+	if envCfg, ok := cfg.Environments[env]; ok && envCfg.Driver == "local" {
+		rendered, err = renderDevCompose(cfg, env, composeRenderOptions{})
+	} else {
+		rendered, err = renderDeployCompose(cfg, env, d.Target.Version, composeRenderOptions{})
+	}
+	if err != nil {
+		d.ComposeErr = err
+		return
+	}
+
+	d.ComposeAfter = compose.ContentHash(rendered.Data)
+	hash, ok, err := compose.ReadHash(rendered.Path)
+	switch {
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		d.ComposeErr = err
+	case ok:
+		d.ComposeBefore = hash
+	}
+}
+
+// Render writes the diff as the sections Images, Migrations, and Config.
+func (d *rollbackDiff) Render(w io.Writer) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	if d.Current != nil {
+		printf("Rollback changes from release %s (%s) to %s (%s):\n",
+			d.Current.ID, d.Current.Version, d.Target.ID, d.Target.Version)
+	} else {
+		printf("Rollback changes to release %s (%s); no current release:\n", d.Target.ID, d.Target.Version)
+	}
+
+	printf("Images:\n")
+	for _, c := range d.Images {
+		printf("  ~ %s: %s -> %s\n", c.Service, orNone(c.Before), orNone(c.After))
+	}
+	printf("  %d changed, %d unchanged\n", len(d.Images), d.ImagesUnchanged)
+
+	printf("Migrations (never run in reverse):\n")
+	for _, c := range d.Migrations {
+		switch c.Kind {
+		case "+":
+			printf("  + %s: added after %s; stays applied\n", c.ID, d.Target.ID)
+		case "~":
+			printf("  ~ %s: changed after %s; stays applied\n", c.ID, d.Target.ID)
+		case "-":
+			printf("  - %s: not in the current config; skipped\n", c.ID)
+		}
+	}
+	for _, id := range d.Runs {
+		printf("  > %s: runs forward; applied migrations are skipped\n", id)
+	}
+	if len(d.Migrations) == 0 && len(d.Runs) == 0 {
+		printf("  (none)\n")
+	}
+
+	printf("Config:\n")
+	switch {
+	case d.ComposeErr != nil:
+		printf("  compose file could not be rendered: %v\n", d.ComposeErr)
+	case d.ComposeBefore == d.ComposeAfter:
+		printf("  compose config-hash unchanged (%s)\n", shortHash(d.ComposeAfter))
+	default:
+		printf("  ~ compose config-hash %s -> %s\n", orNone(shortHash(d.ComposeBefore)), shortHash(d.ComposeAfter))
+	}
+
+	return err
+}
+
+// imageRefsByService maps each service to its recorded image reference,
+// pinned by digest once pushed.
+func imageRefsByService(images []state.BuiltImage) map[string]string {
+	refs := make(map[string]string, len(images))
+	for _, img := range images {
+		ref := img.Tag
+		if img.Digest != "" {
+			ref += "@" + img.Digest
+		}
+		refs[img.Service] = ref
+	}
+	return refs
+}
+
+// migrationSteps returns the migrate steps of a plan fingerprint by ID.
+func migrationSteps(steps []state.PlanStep) map[string]state.PlanStep {
+	out := make(map[string]state.PlanStep)
+	for _, s := range steps {
+		if s.Action == string(engine.StepActionMigrate) {
+			out[s.ID] = s
+		}
+	}
+	return out
+}
+
+func sortedStepIDs(steps map[string]state.PlanStep) []string {
+	ids := make([]string, 0, len(steps))
+	for id := range steps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// shortHash abbreviates a hex digest for display.
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
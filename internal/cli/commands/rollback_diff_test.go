@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core/state"
+)
+
+// Feature: ROLLBACK_DIFF
+// Spec: spec/deploy/rollback-diff.md
+
+func TestComputeRollbackDiff_ImagesAndMigrations(t *testing.T) {
+	target := &state.Release{
+		ID:      "rel-a",
+		Version: "v1",
+		Images: []state.BuiltImage{
+			{Service: "api", Tag: "reg/api:v1", Digest: "sha256:aaa"},
+			{Service: "worker", Tag: "reg/worker:v1"},
+			{Service: "legacy", Tag: "reg/legacy:v1"},
+		},
+		Plan: []state.PlanStep{
+			{ID: "build_api", Action: "build", InputsHash: "sha256:1"},
+			{ID: "migration_main_pre", Action: "migrate", InputsHash: "sha256:old"},
+			{ID: "migration_old_post", Action: "migrate", InputsHash: "sha256:2"},
+		},
+	}
+	current := &state.Release{
+		ID:      "rel-b",
+		Version: "v2",
+		Images: []state.BuiltImage{
+			{Service: "api", Tag: "reg/api:v2", Digest: "sha256:bbb"},
+			{Service: "worker", Tag: "reg/worker:v1"},
+		},
+		Plan: []state.PlanStep{
+			{ID: "build_api", Action: "build", InputsHash: "sha256:changed"},
+			{ID: "migration_main_pre", Action: "migrate", InputsHash: "sha256:new"},
+			{ID: "migration_audit_pre", Action: "migrate", InputsHash: "sha256:3"},
+		},
+	}
+
+	d := computeRollbackDiff(current, target, current.Plan)
+
+	wantImages := []imageChange{
+		{Service: "api", Before: "reg/api:v2@sha256:bbb", After: "reg/api:v1@sha256:aaa"},
+		{Service: "legacy", Before: "", After: "reg/legacy:v1"},
+	}
+	if len(d.Images) != len(wantImages) {
+		t.Fatalf("Images = %+v, want %+v", d.Images, wantImages)
+	}
+	for i := range wantImages {
+		if d.Images[i] != wantImages[i] {
+			t.Errorf("Images[%d] = %+v, want %+v", i, d.Images[i], wantImages[i])
+		}
+	}
+	if d.ImagesUnchanged != 1 {
+		t.Errorf("ImagesUnchanged = %d, want 1", d.ImagesUnchanged)
+	}
+
+	wantMigrations := []migrationChange{
+		{ID: "migration_audit_pre", Kind: "+"},
+		{ID: "migration_main_pre", Kind: "~"},
+		{ID: "migration_old_post", Kind: "-"},
+	}
+	if len(d.Migrations) != len(wantMigrations) {
+		t.Fatalf("Migrations = %+v, want %+v", d.Migrations, wantMigrations)
+	}
+	for i := range wantMigrations {
+		if d.Migrations[i] != wantMigrations[i] {
+			t.Errorf("Migrations[%d] = %+v, want %+v", i, d.Migrations[i], wantMigrations[i])
+		}
+	}
+	if got := strings.Join(d.Runs, ","); got != "migration_audit_pre,migration_main_pre" {
+		t.Errorf("Runs = %q", got)
+	}
+}
+
+func TestRollbackDiff_Render(t *testing.T) {
+	d := computeRollbackDiff(
+		&state.Release{ID: "rel-b", Version: "v2", Images: []state.BuiltImage{{Service: "api", Tag: "reg/api:v2"}}},
+		&state.Release{ID: "rel-a", Version: "v1", Images: []state.BuiltImage{{Service: "api", Tag: "reg/api:v1"}}},
+		nil,
+	)
+	d.ComposeBefore = "0123456789abcdef"
+	d.ComposeAfter = "fedcba9876543210"
+
+	var buf bytes.Buffer
+	if err := d.Render(&buf); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	want := `Rollback changes from release rel-b (v2) to rel-a (v1):
+Images:
+  ~ api: reg/api:v2 -> reg/api:v1
+  1 changed, 0 unchanged
+Migrations (never run in reverse):
+  (none)
+Config:
+  ~ compose config-hash 0123456789ab -> fedcba987654
+`
+	if buf.String() != want {
+		t.Errorf("Render() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	d.ComposeErr = errors.New("docker-compose.yml not found")
+	buf.Reset()
+	_ = d.Render(&buf)
+	if !strings.Contains(buf.String(), "compose file could not be rendered: docker-compose.yml not found") {
+		t.Errorf("Render() with compose error =\n%s", buf.String())
+	}
+}
+
+func TestRollbackCommand_DryRun_PrintsDiff(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+
+	configContent := `project:
+  name: test-app
+environments:
+  staging:
+    driver: local
+`
+	if err := os.WriteFile(filepath.Join(env.TempDir, "stagecraft.yml"), []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	var ids []string
+	for _, version := range []string{"v1.0.0", "v1.1.0"} {
+		rel, err := env.Manager.CreateRelease(env.Ctx, "staging", version, "commit-"+version)
+		if err != nil {
+			t.Fatalf("CreateRelease: %v", err)
+		}
+		for _, phase := range allPhasesCommon() {
+			if err := env.Manager.UpdatePhase(env.Ctx, rel.ID, phase, state.StatusCompleted); err != nil {
+				t.Fatalf("UpdatePhase: %v", err)
+			}
+		}
+		images := []state.BuiltImage{{Service: "api", Tag: "test-app-api:" + version}}
+		if err := env.Manager.RecordImages(env.Ctx, rel.ID, images); err != nil {
+			t.Fatalf("RecordImages: %v", err)
+		}
+		ids = append(ids, rel.ID)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewRollbackCommand())
+
+	out, err := executeCommandForGolden(root, "rollback", "--env", "staging", "--to-previous", "--dry-run")
+	if err != nil {
+		t.Fatalf("rollback --dry-run: %v", err)
+	}
+
+	for _, want := range []string{
+		"Rollback changes from release " + ids[1] + " (v1.1.0) to " + ids[0] + " (v1.0.0):",
+		"  ~ api: test-app-api:v1.1.0 -> test-app-api:v1.0.0",
+		"Migrations (never run in reverse):",
+		"Config:",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
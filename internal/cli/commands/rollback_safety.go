@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+	migrationengines "stagecraft/pkg/providers/migration"
+)
+
+// Feature: ROLLBACK_MIGRATION_SAFETY
+// Spec: spec/deploy/rollback-migration-safety.md
+
+// unsafeMigration is an irreversible migration shipped after a rollback
+// target.
+type unsafeMigration struct {
+	state.MigrationRecord
+
+	// Since is the oldest release after the target that shipped it.
+	Since string
+}
+
+// shippedMigrations lists every configured database's migrations, as the
+// database's migration engine plans them, in database name order.
+func shippedMigrations(ctx context.Context, cfg *config.Config, workdir string) ([]state.MigrationRecord, error) {
+	var records []state.MigrationRecord
+	for _, dbName := range getDatabaseNames(cfg) {
+		dbCfg := cfg.Databases[dbName]
+		if dbCfg.Migrations == nil {
+			continue
+		}
+
+		engine, err := migrationengines.Get(dbCfg.Migrations.Engine)
+		if err != nil {
+			return nil, fmt.Errorf("database %q: %w", dbName, err)
+		}
+
+		migrationPath := dbCfg.Migrations.Path
+		if !filepath.IsAbs(migrationPath) {
+			migrationPath = filepath.Join(workdir, migrationPath)
+		}
+
+		migrations, err := engine.Plan(ctx, migrationengines.PlanOptions{
+			Config:        dbCfg.Migrations,
+			MigrationPath: migrationPath,
+			ConnectionEnv: dbCfg.ConnectionEnv,
+			WorkDir:       workdir,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("database %q: planning migrations: %w", dbName, err)
+		}
+
+		for _, m := range migrations {
+			records = append(records, state.MigrationRecord{Database: dbName, ID: m.ID, Irreversible: m.Irreversible})
+		}
+	}
+	return records, nil
+}
+
+// recordDeployMigrations stores the migrations shipped with the release so
+// a later rollback can tell which irreversible ones it would leave behind.
+// Failures are logged, not fatal: the record only feeds the rollback check.
+func recordDeployMigrations(ctx context.Context, stateMgr *state.Manager, releaseID string, cfg *config.Config, workdir string, logger logging.Logger) {
+	records, err := shippedMigrations(ctx, cfg, workdir)
+	if err == nil {
+		err = stateMgr.RecordMigrations(ctx, releaseID, records)
+	}
+	if err != nil {
+		logger.Warn("Failed to record release migrations",
+			logging.NewField("release_id", releaseID),
+			logging.NewField("error", err.Error()),
+		)
+	}
+}
+
+// irreversibleMigrationsSince returns the irreversible migrations shipped
+// by releases newer than target but not by target itself, sorted by
+// database and ID. releases is the environment's history, newest first.
+func irreversibleMigrationsSince(releases []*state.Release, target *state.Release) []unsafeMigration {
+	shipped := make(map[state.MigrationRecord]bool, len(target.Migrations))
+	for _, m := range target.Migrations {
+		shipped[state.MigrationRecord{Database: m.Database, ID: m.ID}] = true
+	}
+
+	since := make(map[state.MigrationRecord]string)
+	for _, r := range releases {
+		if r.ID == target.ID {
+			break
+		}
+		for _, m := range r.Migrations {
+			key := state.MigrationRecord{Database: m.Database, ID: m.ID}
+			if m.Irreversible && !shipped[key] {
+				// Newest first, so the last release seen is the oldest.
+				since[key] = r.ID
+			}
+		}
+	}
+
+	unsafe := make([]unsafeMigration, 0, len(since))
+	for key, id := range since {
+		key.Irreversible = true
+		unsafe = append(unsafe, unsafeMigration{MigrationRecord: key, Since: id})
+	}
+	sort.Slice(unsafe, func(i, j int) bool {
+		if unsafe[i].Database != unsafe[j].Database {
+			return unsafe[i].Database < unsafe[j].Database
+		}
+		return unsafe[i].ID < unsafe[j].ID
+	})
+	return unsafe
+}
+
+// checkRollbackMigrations refuses a rollback to target that would leave
+// irreversible migrations applied, listing them on w, unless force is set.
+func checkRollbackMigrations(ctx context.Context, w io.Writer, stateMgr *state.Manager, target *state.Release, force bool, logger logging.Logger) error {
+	releases, err := stateMgr.ListReleases(ctx, target.Environment)
+	if err != nil {
+		return fmt.Errorf("listing releases: %w", err)
+	}
+
+	unsafe := irreversibleMigrationsSince(releases, target)
+	if len(unsafe) == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "Irreversible migrations shipped after release %s:\n", target.ID)
+	for _, m := range unsafe {
+		_, _ = fmt.Fprintf(w, "  %s/%s (since %s)\n", m.Database, m.ID, m.Since)
+	}
+
+	if !force {
+		return fmt.Errorf("rollback to %q would leave %d irreversible migration(s) applied; pass --force-data-loss to roll back anyway", target.ID, len(unsafe))
+	}
+
+	logger.Warn("Rolling back past irreversible migrations (--force-data-loss)",
+		logging.NewField("target_release", target.ID),
+		logging.NewField("migrations", len(unsafe)),
+	)
+	return nil
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+)
+
+// Feature: ROLLBACK_MIGRATION_SAFETY
+// Spec: spec/deploy/rollback-migration-safety.md
+
+func TestShippedMigrations_RecordsReversibility(t *testing.T) {
+	workdir := t.TempDir()
+	migrationsDir := filepath.Join(workdir, "migrations")
+	if err := os.MkdirAll(migrationsDir, 0o750); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	files := map[string]string{
+		"001_users.sql":       "CREATE TABLE users (id INT);\n",
+		"002_drop_legacy.sql": "-- stagecraft:irreversible\nDROP TABLE legacy;\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(migrationsDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	cfg := &config.Config{
+		Databases: map[string]config.DatabaseConfig{
+			"main":  {ConnectionEnv: "DATABASE_URL", Migrations: &config.MigrationConfig{Engine: "raw", Path: "./migrations", Strategy: "pre_deploy"}},
+			"cache": {ConnectionEnv: "CACHE_URL"},
+		},
+	}
+
+	got, err := shippedMigrations(context.Background(), cfg, workdir)
+	if err != nil {
+		t.Fatalf("shippedMigrations: %v", err)
+	}
+
+	want := []state.MigrationRecord{
+		{Database: "main", ID: "001_users.sql"},
+		{Database: "main", ID: "002_drop_legacy.sql", Irreversible: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("shippedMigrations = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("shippedMigrations[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIrreversibleMigrationsSince(t *testing.T) {
+	base := state.MigrationRecord{Database: "main", ID: "001_users.sql"}
+	drop := state.MigrationRecord{Database: "main", ID: "002_drop_legacy.sql", Irreversible: true}
+	audit := state.MigrationRecord{Database: "audit", ID: "001_truncate.sql", Irreversible: true}
+	reversible := state.MigrationRecord{Database: "main", ID: "003_add_index.sql"}
+
+	target := &state.Release{ID: "rel-1", Migrations: []state.MigrationRecord{base}}
+	releases := []*state.Release{
+		{ID: "rel-4", Migrations: []state.MigrationRecord{base, drop, reversible, audit}},
+		{ID: "rel-3", Migrations: []state.MigrationRecord{base, drop}},
+		{ID: "rel-2", Migrations: []state.MigrationRecord{base, drop}},
+		target,
+		{ID: "rel-0", Migrations: []state.MigrationRecord{{Database: "main", ID: "000_old.sql", Irreversible: true}}},
+	}
+
+	got := irreversibleMigrationsSince(releases, target)
+
+	want := []unsafeMigration{
+		{MigrationRecord: audit, Since: "rel-4"},
+		{MigrationRecord: drop, Since: "rel-2"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("irreversibleMigrationsSince = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("irreversibleMigrationsSince[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Rolling back to a release that already shipped them is safe.
+	if got := irreversibleMigrationsSince(releases[1:], releases[1]); len(got) != 0 {
+		t.Errorf("irreversibleMigrationsSince(rel-3) = %+v, want none", got)
+	}
+}
+
+func TestRollbackCommand_IrreversibleMigrations(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+
+	configContent := `project:
+  name: test-app
+environments:
+  staging:
+    driver: local
+`
+	if err := os.WriteFile(filepath.Join(env.TempDir, "stagecraft.yml"), []byte(configContent), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	shipped := [][]state.MigrationRecord{
+		{{Database: "main", ID: "001_users.sql"}},
+		{{Database: "main", ID: "001_users.sql"}, {Database: "main", ID: "002_drop_legacy.sql", Irreversible: true}},
+	}
+	versions := []string{"v1.0.0", "v1.1.0"}
+	var ids []string
+	for i, migrations := range shipped {
+		rel, err := env.Manager.CreateRelease(env.Ctx, "staging", versions[i], "commit")
+		if err != nil {
+			t.Fatalf("CreateRelease: %v", err)
+		}
+		for _, phase := range allPhasesCommon() {
+			if err := env.Manager.UpdatePhase(env.Ctx, rel.ID, phase, state.StatusCompleted); err != nil {
+				t.Fatalf("UpdatePhase: %v", err)
+			}
+		}
+		if err := env.Manager.RecordMigrations(env.Ctx, rel.ID, migrations); err != nil {
+			t.Fatalf("RecordMigrations: %v", err)
+		}
+		ids = append(ids, rel.ID)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewRollbackCommand())
+
+	out, err := executeCommandForGolden(root, "rollback", "--env", "staging", "--to-previous", "--dry-run")
+	if err == nil || !strings.Contains(err.Error(), "pass --force-data-loss") {
+		t.Fatalf("expected irreversible migration error, got: %v", err)
+	}
+	if want := "  main/002_drop_legacy.sql (since " + ids[1] + ")"; !strings.Contains(out, want) {
+		t.Errorf("output missing %q:\n%s", want, out)
+	}
+
+	root = newTestRootCommand()
+	root.AddCommand(NewRollbackCommand())
+	if _, err := executeCommandForGolden(root, "rollback", "--env", "staging", "--to-previous", "--dry-run", "--force-data-loss"); err != nil {
+		t.Fatalf("rollback --force-data-loss: %v", err)
+	}
+}
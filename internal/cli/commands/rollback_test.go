@@ -442,7 +442,7 @@ environments:
 	root := newTestRootCommand()
 	root.AddCommand(NewRollbackCommand())
 
-	_, err = executeCommandForGolden(root, "rollback", "--env", "staging", "--to-previous")
+	_, err = executeCommandForGolden(root, "rollback", "--env", "staging", "--to-previous", "--yes")
 	if err == nil {
 		t.Fatalf("expected error when target is not fully deployed")
 	}
@@ -524,7 +524,7 @@ environments:
 		},
 		MigratePost: defaultPhaseFns.MigratePost,
 		Finalize:    defaultPhaseFns.Finalize,
-	}, "rollback", "--env", "staging", "--to-previous")
+	}, "rollback", "--env", "staging", "--to-previous", "--yes")
 	if err != nil {
 		t.Fatalf("rollback should succeed, got: %v", err)
 	}
@@ -754,7 +754,7 @@ environments:
 		},
 		MigratePost: defaultPhaseFns.MigratePost,
 		Finalize:    defaultPhaseFns.Finalize,
-	}, "rollback", "--env", "staging", "--to-previous")
+	}, "rollback", "--env", "staging", "--to-previous", "--yes")
 
 	if rollbackErr == nil {
 		t.Fatalf("expected rollback to fail due to forced rollout failure")
@@ -864,7 +864,7 @@ environments:
 		},
 		MigratePost: defaultPhaseFns.MigratePost,
 		Finalize:    defaultPhaseFns.Finalize,
-	}, "rollback", "--env", "staging", "--to-previous")
+	}, "rollback", "--env", "staging", "--to-previous", "--yes")
 	if err != nil {
 		t.Fatalf("rollback should succeed, got: %v", err)
 	}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/selfupdate"
+)
+
+// Feature: CLI_SELF_UPDATE
+// Spec: spec/commands/self-update.md
+
+// newSelfUpdater returns the updater for the release feed. Tests replace it.
+var newSelfUpdater = func(publicKey string) *selfupdate.Updater {
+	return &selfupdate.Updater{FeedURL: os.Getenv(selfupdate.FeedURLEnv), PublicKey: publicKey}
+}
+
+// selfUpdateTarget returns the binary self-update replaces. Tests replace it.
+var selfUpdateTarget = os.Executable
+
+// NewSelfUpdateCommand returns the `stagecraft self-update` command.
+func NewSelfUpdateCommand() *cobra.Command {
+	var channel, publicKey string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Update Stagecraft to the latest release",
+		Long: `Check the release feed for the latest release of a channel and, when it
+is newer than the running version, replace this binary with it. The
+download must match the feed's sha256 checksum and carry a cosign signature
+that verifies against the public key. Release builds carry a default key;
+without one, stable updates are refused and edge updates only check the
+checksum. The binary is swapped atomically, so a failed update leaves the
+current one in place.
+
+With --dry-run, only report whether an update is available.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSelfUpdate(cmd, channel, publicKey, force)
+		},
+	}
+
+	cmd.Flags().StringVar(&channel, "channel", selfupdate.ChannelStable, "release channel: "+strings.Join(selfupdate.Channels, ", "))
+	cmd.Flags().BoolVar(&force, "force", false, "install the channel's release even when it is not newer, e.g. to switch from edge to stable")
+	cmd.Flags().StringVar(&publicKey, "public-key", os.Getenv(selfupdate.PublicKeyEnv), "cosign public key the release must be signed with; defaults to the key built into release binaries (env: "+selfupdate.PublicKeyEnv+")")
+	_ = cmd.RegisterFlagCompletionFunc("channel", cobra.FixedCompletions(selfupdate.Channels, cobra.ShellCompDirectiveNoFileComp))
+
+	return needsNetwork(cmd)
+}
+
+func runSelfUpdate(cmd *cobra.Command, channel, publicKey string, force bool) error {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("self-update: resolving flags: %w", err)
+	}
+
+	updater := newSelfUpdater(publicKey)
+	update, err := updater.Check(commandContext(cmd), channel)
+	if err != nil {
+		return fmt.Errorf("self-update: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if !update.Newer && !force {
+		_, _ = fmt.Fprintf(out, "✓ Stagecraft %s is up to date (latest %s release: %s)\n", update.Current, update.Channel, update.Version)
+		return nil
+	}
+	if flags.DryRun {
+		_, _ = fmt.Fprintf(out, "Would update Stagecraft from %s to %s (%s channel)\n", update.Current, update.Version, update.Channel)
+		return nil
+	}
+
+	target, err := selfUpdateTarget()
+	if err != nil {
+		return fmt.Errorf("self-update: locating the running binary: %w", err)
+	}
+	if err := updater.Install(commandContext(cmd), update, target); err != nil {
+		return fmt.Errorf("self-update: %w", err)
+	}
+
+	verified := "sha256 checksum"
+	if updater.VerifiesSignature() {
+		verified += " and cosign signature"
+	}
+	_, _ = fmt.Fprintf(out, "✓ Updated Stagecraft from %s to %s (%s channel, verified %s)\n", update.Current, update.Version, update.Channel, verified)
+	return nil
+}
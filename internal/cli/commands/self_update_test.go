@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/buildinfo"
+	"stagecraft/internal/selfupdate"
+)
+
+// Feature: CLI_SELF_UPDATE
+// Spec: spec/commands/self-update.md
+
+// setupSelfUpdate serves a feed whose stable release is 1.4.0, signed, and
+// points self-update at it and at a stand-in binary, which it returns.
+// Signatures always verify.
+func setupSelfUpdate(t *testing.T, current string) string {
+	t.Helper()
+	t.Setenv(buildinfo.VersionEnv, current)
+
+	binary := []byte("stagecraft 1.4.0")
+	sum := sha256.Sum256(binary)
+	feed := selfupdate.Feed{
+		SchemaVersion: selfupdate.FeedSchemaVersion,
+		Channels: map[string]selfupdate.Release{
+			selfupdate.ChannelStable: {Version: "1.4.0", Assets: map[string]selfupdate.Asset{
+				runtime.GOOS + "/" + runtime.GOARCH: {URL: "stagecraft", SHA256: hex.EncodeToString(sum[:]), Signature: "stagecraft.sig"},
+			}},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed.json", func(w http.ResponseWriter, _ *http.Request) { _ = json.NewEncoder(w).Encode(feed) })
+	mux.HandleFunc("/stagecraft", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(binary) })
+	mux.HandleFunc("/stagecraft.sig", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte("signature")) })
+	srv := httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+
+	target := filepath.Join(t.TempDir(), "stagecraft")
+	// #nosec G306 -- test stand-in for an executable
+	if err := os.WriteFile(target, []byte("stagecraft "+current), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	origUpdater, origTarget := newSelfUpdater, selfUpdateTarget
+	t.Cleanup(func() { newSelfUpdater, selfUpdateTarget = origUpdater, origTarget })
+	newSelfUpdater = func(publicKey string) *selfupdate.Updater {
+		return &selfupdate.Updater{FeedURL: srv.URL + "/feed.json", Client: srv.Client(), Runner: &runRecorder{}, PublicKey: publicKey}
+	}
+	selfUpdateTarget = func() (string, error) { return target, nil }
+
+	return target
+}
+
+func executeSelfUpdate(args ...string) (string, error) {
+	root := newTestRootCommand()
+	root.AddCommand(NewSelfUpdateCommand())
+	return executeCommandForGolden(root, append([]string{"self-update"}, args...)...)
+}
+
+func TestSelfUpdateCommand_Updates(t *testing.T) {
+	target := setupSelfUpdate(t, "1.3.2")
+
+	out, err := executeSelfUpdate("--public-key", "cosign.pub")
+	if err != nil {
+		t.Fatalf("self-update: %v", err)
+	}
+	if !strings.Contains(out, "Updated Stagecraft from 1.3.2 to 1.4.0 (stable channel, verified sha256 checksum and cosign signature)") {
+		t.Errorf("output = %q", out)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "stagecraft 1.4.0" {
+		t.Errorf("binary = %q, want the 1.4.0 release", data)
+	}
+}
+
+func TestSelfUpdateCommand_UpToDate(t *testing.T) {
+	target := setupSelfUpdate(t, "1.4.0")
+
+	out, err := executeSelfUpdate()
+	if err != nil {
+		t.Fatalf("self-update: %v", err)
+	}
+	if !strings.Contains(out, "Stagecraft 1.4.0 is up to date (latest stable release: 1.4.0)") {
+		t.Errorf("output = %q", out)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "stagecraft 1.4.0" {
+		t.Errorf("binary = %q, want it untouched", data)
+	}
+}
+
+func TestSelfUpdateCommand_ForceDowngrades(t *testing.T) {
+	target := setupSelfUpdate(t, "1.5.0-rc.1")
+
+	if _, err := executeSelfUpdate("--force", "--public-key", "cosign.pub"); err != nil {
+		t.Fatalf("self-update --force: %v", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "stagecraft 1.4.0" {
+		t.Errorf("binary = %q, want the 1.4.0 release", data)
+	}
+}
+
+func TestSelfUpdateCommand_StableRequiresPublicKey(t *testing.T) {
+	target := setupSelfUpdate(t, "1.3.2")
+	t.Setenv(selfupdate.PublicKeyEnv, "")
+
+	_, err := executeSelfUpdate()
+	if err == nil || !strings.Contains(err.Error(), "no public key is configured") {
+		t.Fatalf("self-update error = %v, want missing public key", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "stagecraft 1.3.2" {
+		t.Errorf("binary = %q, want it untouched", data)
+	}
+}
+
+func TestSelfUpdateCommand_DryRun(t *testing.T) {
+	target := setupSelfUpdate(t, "1.3.2")
+
+	out, err := executeSelfUpdate("--dry-run")
+	if err != nil {
+		t.Fatalf("self-update --dry-run: %v", err)
+	}
+	if !strings.Contains(out, "Would update Stagecraft from 1.3.2 to 1.4.0 (stable channel)") {
+		t.Errorf("output = %q", out)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "stagecraft 1.3.2" {
+		t.Errorf("binary = %q, want it untouched", data)
+	}
+}
+
+func TestSelfUpdateCommand_Errors(t *testing.T) {
+	setupSelfUpdate(t, "1.3.2")
+
+	if _, err := executeSelfUpdate("--channel", "nightly"); err == nil || !strings.Contains(err.Error(), `unknown channel "nightly"`) {
+		t.Errorf("--channel nightly error = %v", err)
+	}
+	if _, err := executeSelfUpdate("--channel", "edge"); err == nil || !strings.Contains(err.Error(), "no edge release") {
+		t.Errorf("--channel edge error = %v", err)
+	}
+	if _, err := executeSelfUpdate("--offline"); err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Errorf("--offline error = %v", err)
+	}
+}
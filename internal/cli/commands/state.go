@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/core/state"
+)
+
+// Feature: CORE_STATE_INTEGRITY
+// Spec: spec/core/state-integrity.md
+
+// NewStateCommand returns the `stagecraft state` command group.
+func NewStateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Check and repair the release state file",
+		Long: `The state file records every release. Each release and the file as a
+whole carry sha256 checksums, so truncation and hand edits are detected
+when the file is read.`,
+	}
+
+	cmd.AddCommand(newStateVerifyCommand())
+	cmd.AddCommand(newStateRepairCommand())
+
+	return cmd
+}
+
+func newStateVerifyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify",
+		Short: "Check the state file against its checksums",
+		Args:  cobra.NoArgs,
+		RunE:  runStateVerify,
+	}
+}
+
+func newStateRepairCommand() *cobra.Command {
+	var opts state.RepairOptions
+
+	cmd := &cobra.Command{
+		Use:   "repair",
+		Short: "Restore a damaged state file from its newest valid backup",
+		Long: `Replace a state file that fails verification with the newest backup
+that passes it. The damaged file is kept next to the state file with a
+.damaged suffix. With --accept-edits, a file that still parses is resealed
+as it is instead, keeping hand edits.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStateRepair(cmd, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.AcceptEdits, "accept-edits", false, "reseal the current file instead of restoring a backup")
+
+	return cmd
+}
+
+// stateVerifyView is the structured (--output json|yaml) form of `state verify`.
+type stateVerifyView struct {
+	Path          string   `json:"path" yaml:"path"`
+	Exists        bool     `json:"exists" yaml:"exists"`
+	OK            bool     `json:"ok" yaml:"ok"`
+	SchemaVersion int      `json:"schema_version" yaml:"schema_version"`
+	Releases      int      `json:"releases" yaml:"releases"`
+	Problems      []string `json:"problems" yaml:"problems"`
+	Unverified    string   `json:"unverified,omitempty" yaml:"unverified,omitempty"`
+}
+
+func runStateVerify(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("state verify: resolving flags: %w", err)
+	}
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	report, err := state.NewDefaultManager().Verify(ctx)
+	if err != nil {
+		return fmt.Errorf("state verify: %w", err)
+	}
+
+	view := stateVerifyView{
+		Path:          report.Path,
+		Exists:        report.Exists,
+		OK:            report.OK(),
+		SchemaVersion: report.SchemaVersion,
+		Releases:      report.Releases,
+		Problems:      append([]string{}, report.Problems...),
+		Unverified:    report.Unverified,
+	}
+
+	if err := output.Render(cmd.OutOrStdout(), format, view, func(w io.Writer) error {
+		return displayStateVerify(w, report)
+	}); err != nil {
+		return err
+	}
+
+	if !report.OK() {
+		return fmt.Errorf("state verify: %s failed %d check(s); run `stagecraft state repair` to recover", report.Path, len(report.Problems))
+	}
+	return nil
+}
+
+func displayStateVerify(w io.Writer, report *state.VerifyReport) error {
+	switch {
+	case !report.Exists:
+		_, _ = fmt.Fprintf(w, "No state file at %s\n", report.Path)
+		return nil
+	case !report.OK():
+		_, _ = fmt.Fprintf(w, "✗ %s is damaged:\n", report.Path)
+		for _, problem := range report.Problems {
+			_, _ = fmt.Fprintf(w, "  - %s\n", problem)
+		}
+		return nil
+	case report.Unverified != "":
+		_, _ = fmt.Fprintf(w, "! %s: %d release(s), not verified: %s\n", report.Path, report.Releases, report.Unverified)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "✓ %s: %d release(s), schema version %d, checksums match\n", report.Path, report.Releases, report.SchemaVersion)
+	return nil
+}
+
+// stateRepairView is the structured (--output json|yaml) form of `state repair`.
+type stateRepairView struct {
+	Action   string `json:"action" yaml:"action"`
+	Backup   string `json:"backup,omitempty" yaml:"backup,omitempty"`
+	Damaged  string `json:"damaged,omitempty" yaml:"damaged,omitempty"`
+	Releases int    `json:"releases" yaml:"releases"`
+}
+
+func runStateRepair(cmd *cobra.Command, opts state.RepairOptions) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("state repair: resolving flags: %w", err)
+	}
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
+
+	result, err := state.NewDefaultManager().Repair(ctx, opts)
+	recordAudit(ctx, cmd, logger, flags.Env, "", err)
+	if err != nil {
+		return fmt.Errorf("state repair: %w", err)
+	}
+
+	view := stateRepairView{
+		Action:   string(result.Action),
+		Backup:   result.Backup,
+		Damaged:  result.Damaged,
+		Releases: result.Releases,
+	}
+
+	return output.Render(cmd.OutOrStdout(), format, view, func(w io.Writer) error {
+		switch result.Action {
+		case state.RepairNone:
+			_, _ = fmt.Fprintln(w, "✓ State file is valid; nothing to repair")
+		case state.RepairResealed:
+			_, _ = fmt.Fprintf(w, "✓ Resealed the state file with its current content (%d release(s))\n", result.Releases)
+		case state.RepairRestored:
+			_, _ = fmt.Fprintf(w, "✓ Restored %d release(s) from %s\n", result.Releases, result.Backup)
+			_, _ = fmt.Fprintf(w, "  Damaged file kept at %s\n", result.Damaged)
+		}
+		return nil
+	})
+}
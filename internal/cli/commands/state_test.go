@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// Feature: CORE_STATE_INTEGRITY
+// Spec: spec/core/state-integrity.md
+
+func TestStateVerify_ValidFile(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	if _, err := env.Manager.CreateRelease(env.Ctx, "prod", "v1", "sha1"); err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewStateCommand())
+
+	out, err := executeCommandForGolden(root, "state", "verify")
+	if err != nil {
+		t.Fatalf("state verify failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "1 release(s)") || !strings.Contains(out, "checksums match") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestStateVerifyAndRepair_DamagedFile(t *testing.T) {
+	env := setupIsolatedStateTestEnv(t)
+	if _, err := env.Manager.CreateRelease(env.Ctx, "prod", "v1", "sha1"); err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+	good, err := os.ReadFile(env.StateFile)
+	if err != nil {
+		t.Fatalf("failed to read state file: %v", err)
+	}
+	if err := os.WriteFile(env.StateFile+".v2.bak", good, 0o600); err != nil {
+		t.Fatalf("failed to write backup: %v", err)
+	}
+	if err := os.WriteFile(env.StateFile, good[:len(good)/2], 0o600); err != nil {
+		t.Fatalf("failed to truncate state file: %v", err)
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewStateCommand())
+
+	out, err := executeCommandForGolden(root, "state", "verify", "--output", "json")
+	if err == nil {
+		t.Fatalf("expected state verify to fail on a truncated file")
+	}
+	if !strings.Contains(out, `"ok": false`) || !strings.Contains(out, "truncated") {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	root = newTestRootCommand()
+	root.AddCommand(NewStateCommand())
+
+	out, err = executeCommandForGolden(root, "state", "repair")
+	if err != nil {
+		t.Fatalf("state repair failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Restored 1 release(s)") {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	if _, err := env.Manager.GetCurrentRelease(env.Ctx, "prod"); err != nil {
+		t.Errorf("expected the restored state to be readable, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"time"
+
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/engine"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: ENGINE_STEP_TIMEOUTS
+// Spec: spec/engine/step-timeouts.md
+
+// withPhaseTimeouts bounds each phase function by the configured step
+// timeout for its phase. A phase that runs past it is canceled, which kills
+// the subprocesses it started, and fails with an *engine.TimeoutError.
+func withPhaseTimeouts(fns PhaseFns, engineCfg *config.EngineConfig) PhaseFns {
+	wrap := func(phase state.ReleasePhase, fn phaseFn) phaseFn {
+		timeout := engineCfg.StepTimeoutFor(string(phase))
+		if timeout <= 0 || fn == nil {
+			return fn
+		}
+		return func(ctx context.Context, plan *core.Plan, logger logging.Logger) error {
+			return engine.RunWithTimeout(ctx, timeout, func(ctx context.Context) error {
+				return fn(ctx, plan, logger)
+			})
+		}
+	}
+
+	return PhaseFns{
+		Build:       wrap(state.PhaseBuild, fns.Build),
+		Push:        wrap(state.PhasePush, fns.Push),
+		MigratePre:  wrap(state.PhaseMigratePre, fns.MigratePre),
+		Rollout:     wrap(state.PhaseRollout, fns.Rollout),
+		MigratePost: wrap(state.PhaseMigratePost, fns.MigratePost),
+		Finalize:    wrap(state.PhaseFinalize, fns.Finalize),
+	}
+}
+
+// phaseFailureStatus returns the status recorded for a phase that failed
+// with err: timed out when it ran past its step timeout, failed otherwise.
+func phaseFailureStatus(err error) state.PhaseStatus {
+	if engine.IsTimeout(err) {
+		return state.StatusTimedOut
+	}
+	return state.StatusFailed
+}
+
+// engineStepTimeouts returns the configured timeouts for engine step
+// actions, for executors that run host plan steps.
+func engineStepTimeouts(engineCfg *config.EngineConfig) engine.StepTimeouts {
+	if engineCfg == nil {
+		return engine.StepTimeouts{}
+	}
+	timeouts := engine.StepTimeouts{
+		Default:   engineCfg.DefaultStepTimeout(),
+		PerAction: make(map[engine.StepAction]time.Duration, len(engineCfg.StepTimeouts)),
+	}
+	// Phase keys such as migrate_pre never match an action and are unused.
+	for name := range engineCfg.StepTimeouts {
+		timeouts.PerAction[engine.StepAction(name)] = engineCfg.StepTimeoutFor(name)
+	}
+	return timeouts
+}
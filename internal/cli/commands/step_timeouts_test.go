@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/engine"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: ENGINE_STEP_TIMEOUTS
+// Spec: spec/engine/step-timeouts.md
+
+func succeedPhase(context.Context, *core.Plan, logging.Logger) error { return nil }
+
+func waitForPhaseCancel(ctx context.Context, _ *core.Plan, _ logging.Logger) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestExecutePhasesCommon_RecordsTimedOutPhase(t *testing.T) {
+	ctx := context.Background()
+	stateMgr := state.NewManager(filepath.Join(t.TempDir(), "releases.json"))
+	release, err := stateMgr.CreateRelease(ctx, "staging", "v1.0.0", "commit1")
+	if err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+
+	fns := PhaseFns{
+		Build:       succeedPhase,
+		Push:        succeedPhase,
+		MigratePre:  waitForPhaseCancel,
+		Rollout:     succeedPhase,
+		MigratePost: succeedPhase,
+		Finalize:    succeedPhase,
+	}
+	engineCfg := &config.EngineConfig{
+		StepTimeout:  "1h",
+		StepTimeouts: map[string]string{"migrate_pre": "10ms"},
+	}
+
+	err = executePhasesCommon(ctx, stateMgr, release.ID, &core.Plan{}, logging.NewLogger(false), withPhaseTimeouts(fns, engineCfg))
+	if !engine.IsTimeout(err) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `phase "migrate_pre" failed: timed out after 10ms`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	got, err := stateMgr.GetRelease(ctx, release.ID)
+	if err != nil {
+		t.Fatalf("failed to get release: %v", err)
+	}
+	want := map[state.ReleasePhase]state.PhaseStatus{
+		state.PhaseBuild:       state.StatusCompleted,
+		state.PhasePush:        state.StatusCompleted,
+		state.PhaseMigratePre:  state.StatusTimedOut,
+		state.PhaseRollout:     state.StatusSkipped,
+		state.PhaseMigratePost: state.StatusSkipped,
+		state.PhaseFinalize:    state.StatusSkipped,
+	}
+	for phase, status := range want {
+		if got.Phases[phase] != status {
+			t.Errorf("phase %s = %s, want %s", phase, got.Phases[phase], status)
+		}
+	}
+	if overall := calculateOverallStatus(got); overall != "failed" {
+		t.Errorf("overall status = %s, want failed", overall)
+	}
+}
+
+func TestWithPhaseTimeouts_NoConfig(t *testing.T) {
+	fns := PhaseFns{Build: func(ctx context.Context, _ *core.Plan, _ logging.Logger) error {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("phase without a configured timeout should have no deadline")
+		}
+		return nil
+	}}
+
+	if err := withPhaseTimeouts(fns, nil).Build(context.Background(), &core.Plan{}, logging.NewLogger(false)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEngineStepTimeouts(t *testing.T) {
+	timeouts := engineStepTimeouts(&config.EngineConfig{
+		StepTimeout:  "30m",
+		StepTimeouts: map[string]string{"apply_compose": "5m", "migrate_pre": "1m"},
+	})
+
+	if got := timeouts.For(engine.StepActionApplyCompose); got != 5*time.Minute {
+		t.Errorf("apply_compose timeout = %s, want 5m", got)
+	}
+	if got := timeouts.For(engine.StepActionMigrate); got != 30*time.Minute {
+		t.Errorf("migrate timeout = %s, want the default", got)
+	}
+	if got := engineStepTimeouts(nil).For(engine.StepActionMigrate); got != 0 {
+		t.Errorf("no engine config should mean no limit, got %s", got)
+	}
+}
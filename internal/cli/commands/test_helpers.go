@@ -41,6 +41,8 @@ type isolatedStateTestEnv struct {
 // - Creates a temporary directory for the test
 // - Sets up an isolated state file at .stagecraft/releases.json in the temp directory
 // - Sets STAGECRAFT_STATE_FILE environment variable (test-scoped, auto-cleanup)
+// - Points STAGECRAFT_AUDIT_FILE at .stagecraft/audit.jsonl in the temp directory
+// - Points STAGECRAFT_CACHE_DIR at .cache in the temp directory
 // - Changes working directory to the temp directory (with cleanup)
 // - Returns a manager configured to use the isolated state file
 //
@@ -59,6 +61,8 @@ func setupIsolatedStateTestEnv(t *testing.T) *isolatedStateTestEnv {
 
 	// Set env var (test-scoped, auto-cleanup)
 	t.Setenv("STAGECRAFT_STATE_FILE", absStateFile)
+	t.Setenv("STAGECRAFT_AUDIT_FILE", filepath.Join(filepath.Dir(absStateFile), "audit.jsonl"))
+	t.Setenv("STAGECRAFT_CACHE_DIR", filepath.Join(tmpDir, ".cache"))
 
 	// Change directory (with cleanup)
 	originalDir, err := os.Getwd()
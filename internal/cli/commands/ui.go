@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/ui"
+	"stagecraft/internal/core/audit"
+	"stagecraft/internal/core/state"
+	"stagecraft/pkg/config"
+)
+
+// Feature: CLI_UI
+// Spec: spec/commands/ui.md
+
+// defaultUIAddr is the address `stagecraft ui` binds by default.
+const defaultUIAddr = "127.0.0.1:7430"
+
+// Drift statuses reported for an environment, comparing its current
+// release with the local git HEAD the way reconcile does.
+const (
+	driftUpToDate      = "up_to_date"
+	driftBehind        = "behind"
+	driftIncomplete    = "incomplete"
+	driftNeverDeployed = "never_deployed"
+	driftUnknown       = "unknown"
+)
+
+// NewUICommand returns the `stagecraft ui` command.
+func NewUICommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ui",
+		Short: "Serve a local read-only web dashboard",
+		Long: `Serve a web dashboard on a loopback address showing environments, their
+current releases and drift from git HEAD, release phase history and
+timelines, and the audit log. The dashboard reads the same state and audit
+files as the CLI and cannot change anything. Stop it with Ctrl+C.`,
+		Args: cobra.NoArgs,
+		RunE: runUI,
+	}
+
+	cmd.Flags().String("addr", defaultUIAddr, "Loopback host:port to serve the dashboard on")
+
+	return cmd
+}
+
+func runUI(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("ui: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	api := &uiAPI{
+		configPath: flags.Config,
+		stateMgr:   state.NewDefaultManager(),
+		audit:      newAuditLog(),
+		head: func(ctx context.Context) string {
+			sha, _ := gitRunner{runner: newRunner()}.output(ctx, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+			return sha
+		},
+	}
+
+	bound, err := ui.Serve(ctx, addr, ui.Handler(api.handler()))
+	if err != nil {
+		return fmt.Errorf("ui: %w", err)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Dashboard available at http://%s/ (Ctrl+C to stop)\n", bound)
+
+	<-ctx.Done()
+	return nil
+}
+
+// uiAPI serves the dashboard's JSON endpoints. Every request reads state
+// afresh, so the dashboard follows deploys made while it runs.
+type uiAPI struct {
+	configPath string
+	stateMgr   *state.Manager
+	audit      audit.Backend
+
+	// head returns the local git HEAD commit, or "" outside a checkout.
+	head func(ctx context.Context) string
+}
+
+// uiEnvironmentsView is the response of GET /api/environments.
+type uiEnvironmentsView struct {
+	Environments []uiEnvironmentView `json:"environments"`
+}
+
+// uiEnvironmentView is one environment with its current release.
+type uiEnvironmentView struct {
+	Name    string       `json:"name"`
+	Current *releaseView `json:"current"`
+	Drift   driftView    `json:"drift"`
+}
+
+// driftView compares an environment's current release with git HEAD.
+type driftView struct {
+	Status      string `json:"status"`
+	HeadSHA     string `json:"head_sha,omitempty"`
+	DeployedSHA string `json:"deployed_sha,omitempty"`
+}
+
+func (a *uiAPI) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/environments", a.handleEnvironments)
+	mux.HandleFunc("GET /api/releases", a.handleReleases)
+	mux.HandleFunc("GET /api/releases/{id}", a.handleRelease)
+	mux.HandleFunc("GET /api/audit", a.handleAudit)
+	return mux
+}
+
+// handleEnvironments lists every environment in config or state, with its
+// current release and drift.
+func (a *uiAPI) handleEnvironments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	releases, err := a.stateMgr.ListAllReleases(ctx)
+	if err != nil {
+		writeUIError(w, http.StatusInternalServerError, fmt.Errorf("listing releases: %w", err))
+		return
+	}
+
+	// Releases are sorted newest first within each environment.
+	current := make(map[string]*state.Release)
+	for _, rel := range releases {
+		if _, ok := current[rel.Environment]; !ok {
+			current[rel.Environment] = rel
+		}
+	}
+
+	names := make(map[string]bool, len(current))
+	for name := range current {
+		names[name] = true
+	}
+	// Config is optional: the dashboard also works from state alone.
+	if cfg, err := config.Load(a.configPath); err == nil {
+		for name := range cfg.Environments {
+			names[name] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	head := a.head(ctx)
+	view := uiEnvironmentsView{Environments: make([]uiEnvironmentView, 0, len(sorted))}
+	for _, name := range sorted {
+		env := uiEnvironmentView{Name: name, Drift: environmentDrift(current[name], head)}
+		if rel := current[name]; rel != nil {
+			rv := newReleaseView(rel)
+			env.Current = &rv
+		}
+		view.Environments = append(view.Environments, env)
+	}
+
+	writeUIJSON(w, view)
+}
+
+// handleReleases lists releases, newest first, optionally for one
+// environment (?env=).
+func (a *uiAPI) handleReleases(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var releases []*state.Release
+	var err error
+	if env := r.URL.Query().Get("env"); env != "" {
+		releases, err = a.stateMgr.ListReleases(ctx, env)
+	} else {
+		releases, err = a.stateMgr.ListAllReleases(ctx)
+	}
+	if err != nil {
+		writeUIError(w, http.StatusInternalServerError, fmt.Errorf("listing releases: %w", err))
+		return
+	}
+
+	view := releasesListView{Releases: make([]releaseView, 0, len(releases))}
+	for _, rel := range releases {
+		view.Releases = append(view.Releases, newReleaseView(rel))
+	}
+	writeUIJSON(w, view)
+}
+
+// handleRelease shows one release with its phase history and timeline.
+func (a *uiAPI) handleRelease(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	release, err := a.stateMgr.GetRelease(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, state.ErrReleaseNotFound) {
+			writeUIError(w, http.StatusNotFound, fmt.Errorf("release not found: %q", id))
+			return
+		}
+		writeUIError(w, http.StatusInternalServerError, fmt.Errorf("getting release: %w", err))
+		return
+	}
+
+	view := newReleaseView(release)
+	view.Events = newReleaseEventViews(release.Events)
+	writeUIJSON(w, view)
+}
+
+// handleAudit lists audit entries, newest first, filtered by ?env= and
+// capped by ?limit= like `stagecraft audit list`.
+func (a *uiAPI) handleAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := audit.Filter{Environment: query.Get("env")}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeUIError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", limit))
+			return
+		}
+		filter.Limit = n
+	}
+
+	entries, err := a.audit.List(r.Context(), filter)
+	if err != nil {
+		writeUIError(w, http.StatusInternalServerError, fmt.Errorf("reading audit log: %w", err))
+		return
+	}
+
+	// Newest first, matching `audit list`.
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	if entries == nil {
+		entries = []audit.Entry{}
+	}
+	writeUIJSON(w, auditListView{Entries: entries})
+}
+
+// environmentDrift reports whether current matches head, using the same
+// test reconcile uses to decide an environment needs no deploy.
+func environmentDrift(current *state.Release, head string) driftView {
+	if current == nil {
+		return driftView{Status: driftNeverDeployed, HeadSHA: head}
+	}
+
+	view := driftView{HeadSHA: head, DeployedSHA: current.CommitSHA}
+	switch {
+	case !releaseFullyDeployed(current):
+		view.Status = driftIncomplete
+	case head == "" || current.CommitSHA == "":
+		view.Status = driftUnknown
+	case releaseUpToDate(current, head):
+		view.Status = driftUpToDate
+	default:
+		view.Status = driftBehind
+	}
+	return view
+}
+
+func writeUIJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeUIError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"stagecraft/internal/core/audit"
+	"stagecraft/internal/core/state"
+)
+
+// Feature: CLI_UI
+// Spec: spec/commands/ui.md
+
+// newTestUIAPI returns an API over an isolated state file with two
+// releases in staging (the newest at commit "bbb") and one failed release
+// in prod, and a config declaring dev as well.
+func newTestUIAPI(t *testing.T) (*uiAPI, *isolatedStateTestEnv, *state.Release) {
+	t.Helper()
+	env := setupIsolatedStateTestEnv(t)
+	ctx := env.Ctx
+
+	complete := func(id string) {
+		for _, phase := range allPhasesCommon() {
+			if err := env.Manager.UpdatePhase(ctx, id, phase, state.StatusCompleted); err != nil {
+				t.Fatalf("UpdatePhase: %v", err)
+			}
+		}
+	}
+
+	first, err := env.Manager.CreateRelease(ctx, "staging", "v1", "aaa")
+	if err != nil {
+		t.Fatalf("CreateRelease: %v", err)
+	}
+	complete(first.ID)
+	time.Sleep(2 * time.Millisecond)
+
+	second, err := env.Manager.CreateRelease(ctx, "staging", "v2", "bbb")
+	if err != nil {
+		t.Fatalf("CreateRelease: %v", err)
+	}
+	complete(second.ID)
+	if err := env.Manager.AddEvent(ctx, second.ID, state.ReleaseEvent{Type: state.EventNote, Actor: "bart", Message: "smoke tested"}); err != nil {
+		t.Fatalf("AddEvent: %v", err)
+	}
+
+	prod, err := env.Manager.CreateRelease(ctx, "prod", "v1", "aaa")
+	if err != nil {
+		t.Fatalf("CreateRelease: %v", err)
+	}
+	if err := env.Manager.UpdatePhase(ctx, prod.ID, state.PhaseBuild, state.StatusFailed); err != nil {
+		t.Fatalf("UpdatePhase: %v", err)
+	}
+
+	auditLog := audit.NewDefaultLog()
+	for _, e := range []audit.Entry{
+		{Timestamp: time.Now().UTC(), Actor: "bart", Command: "deploy", Environment: "staging", ReleaseID: second.ID, Outcome: audit.OutcomeSuccess},
+		{Timestamp: time.Now().UTC(), Actor: "bart", Command: "deploy", Environment: "prod", ReleaseID: prod.ID, Outcome: audit.OutcomeFailure, Error: "build failed"},
+	} {
+		if err := auditLog.Append(ctx, e); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	config := `project:
+  name: test-app
+environments:
+  dev:
+    driver: local
+  staging:
+    driver: digitalocean
+`
+	if err := os.WriteFile(filepath.Join(env.TempDir, "stagecraft.yml"), []byte(config), 0o600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	api := &uiAPI{
+		configPath: "stagecraft.yml",
+		stateMgr:   env.Manager,
+		audit:      auditLog,
+		head:       func(context.Context) string { return "bbb" },
+	}
+	return api, env, second
+}
+
+func getUIJSON(t *testing.T, api *uiAPI, path string, wantStatus int, v any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	api.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code != wantStatus {
+		t.Fatalf("GET %s status = %d, want %d (body %s)", path, rec.Code, wantStatus, rec.Body.String())
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("GET %s: decoding %q: %v", path, rec.Body.String(), err)
+	}
+}
+
+func TestUIAPI_Environments(t *testing.T) {
+	api, _, second := newTestUIAPI(t)
+
+	var view uiEnvironmentsView
+	getUIJSON(t, api, "/api/environments", http.StatusOK, &view)
+
+	got := make(map[string]uiEnvironmentView)
+	var names []string
+	for _, env := range view.Environments {
+		got[env.Name] = env
+		names = append(names, env.Name)
+	}
+	if want := []string{"dev", "prod", "staging"}; len(names) != 3 || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+		t.Fatalf("environments = %v, want %v", names, want)
+	}
+
+	if dev := got["dev"]; dev.Current != nil || dev.Drift.Status != driftNeverDeployed {
+		t.Errorf("dev = %+v, want no release and never_deployed", dev)
+	}
+	if prod := got["prod"]; prod.Current == nil || prod.Current.Status != "failed" || prod.Drift.Status != driftIncomplete {
+		t.Errorf("prod = %+v, want failed release and incomplete drift", prod)
+	}
+	staging := got["staging"]
+	if staging.Current == nil || staging.Current.ID != second.ID {
+		t.Fatalf("staging current = %+v, want %s", staging.Current, second.ID)
+	}
+	if staging.Drift != (driftView{Status: driftUpToDate, HeadSHA: "bbb", DeployedSHA: "bbb"}) {
+		t.Errorf("staging drift = %+v, want up_to_date at bbb", staging.Drift)
+	}
+
+	api.head = func(context.Context) string { return "ccc" }
+	getUIJSON(t, api, "/api/environments", http.StatusOK, &view)
+	for _, env := range view.Environments {
+		if env.Name == "staging" && env.Drift.Status != driftBehind {
+			t.Errorf("staging drift with HEAD ccc = %q, want behind", env.Drift.Status)
+		}
+	}
+
+	api.head = func(context.Context) string { return "" }
+	getUIJSON(t, api, "/api/environments", http.StatusOK, &view)
+	for _, env := range view.Environments {
+		if env.Name == "staging" && env.Drift.Status != driftUnknown {
+			t.Errorf("staging drift outside git = %q, want unknown", env.Drift.Status)
+		}
+	}
+}
+
+func TestUIAPI_Releases(t *testing.T) {
+	api, _, second := newTestUIAPI(t)
+
+	var list releasesListView
+	getUIJSON(t, api, "/api/releases", http.StatusOK, &list)
+	if len(list.Releases) != 3 {
+		t.Fatalf("all releases = %d, want 3", len(list.Releases))
+	}
+
+	getUIJSON(t, api, "/api/releases?env=staging", http.StatusOK, &list)
+	if len(list.Releases) != 2 || list.Releases[0].ID != second.ID {
+		t.Fatalf("staging releases = %+v, want 2 newest first", list.Releases)
+	}
+
+	var release releaseView
+	getUIJSON(t, api, "/api/releases/"+second.ID, http.StatusOK, &release)
+	if release.Status != "completed" || len(release.Phases) != len(releasePhasesOrder) {
+		t.Errorf("release = %+v, want completed with all phases", release)
+	}
+	if len(release.Events) == 0 || release.Events[len(release.Events)-1].Message != "smoke tested" {
+		t.Errorf("events = %+v, want timeline ending with the note", release.Events)
+	}
+
+	var apiErr map[string]string
+	getUIJSON(t, api, "/api/releases/rel-missing", http.StatusNotFound, &apiErr)
+	if apiErr["error"] != `release not found: "rel-missing"` {
+		t.Errorf("error = %q", apiErr["error"])
+	}
+}
+
+func TestUIAPI_Audit(t *testing.T) {
+	api, _, _ := newTestUIAPI(t)
+
+	var view auditListView
+	getUIJSON(t, api, "/api/audit", http.StatusOK, &view)
+	if len(view.Entries) != 2 || view.Entries[0].Environment != "prod" {
+		t.Fatalf("entries = %+v, want 2 newest first", view.Entries)
+	}
+
+	getUIJSON(t, api, "/api/audit?env=staging&limit=5", http.StatusOK, &view)
+	if len(view.Entries) != 1 || view.Entries[0].Environment != "staging" {
+		t.Fatalf("staging entries = %+v, want 1", view.Entries)
+	}
+
+	getUIJSON(t, api, "/api/audit?env=dev", http.StatusOK, &view)
+	if view.Entries == nil || len(view.Entries) != 0 {
+		t.Fatalf("dev entries = %#v, want empty list", view.Entries)
+	}
+
+	var apiErr map[string]string
+	getUIJSON(t, api, "/api/audit?limit=-1", http.StatusBadRequest, &apiErr)
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/textdiff"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: CLI_UPGRADE
+// Spec: spec/commands/upgrade.md
+
+// NewUpgradeCommand returns the `stagecraft upgrade` command.
+func NewUpgradeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade stagecraft.yml to the current config schema version",
+		Long: `Read the schema version of the config file, apply any pending schema
+migrations in order, and write the upgraded file back in place.
+
+With --dry-run, the migrations that would run and a unified diff of the
+result are printed and the file is left untouched.`,
+		RunE: runUpgrade,
+	}
+
+	return cmd
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("resolving flags: %w", err)
+	}
+
+	logger, err := newLogger(flags)
+	if err != nil {
+		return err
+	}
+
+	// Read the raw file rather than config.Load: old schemas may not pass
+	// current validation until they have been upgraded.
+	info, err := os.Stat(flags.Config)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("stagecraft config not found at %s", flags.Config)
+		}
+		return fmt.Errorf("checking config at %s: %w", flags.Config, err)
+	}
+
+	// nolint:gosec // G304: reading config file from user-specified path is expected behavior
+	data, err := os.ReadFile(flags.Config)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	result, err := config.Upgrade(data)
+	if err != nil {
+		return fmt.Errorf("upgrading config: %w", err)
+	}
+
+	if len(result.Applied) == 0 {
+		_, _ = fmt.Fprintf(out, "Config at %s is already at schema version %d.\n", flags.Config, result.ToVersion)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "Schema migrations for %s:\n", flags.Config)
+	for _, m := range result.Applied {
+		logger.Debug("Applying schema migration",
+			logging.NewField("from", m.From),
+			logging.NewField("to", m.To),
+		)
+		_, _ = fmt.Fprintf(out, "  v%d -> v%d: %s\n", m.From, m.To, m.Description)
+	}
+
+	if flags.DryRun {
+		_, _ = fmt.Fprintf(out, "Dry run: not writing changes. Diff:\n")
+		_, _ = fmt.Fprint(out, textdiff.Unified(flags.Config, flags.Config+" (upgraded)",
+			string(result.Original), string(result.Upgraded)))
+		return nil
+	}
+
+	// Keep the file's existing permissions.
+	if err := os.WriteFile(flags.Config, result.Upgraded, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing upgraded config: %w", err)
+	}
+
+	if _, err := config.Load(flags.Config); err != nil {
+		logger.Warn("Upgraded config does not pass validation",
+			logging.NewField("path", flags.Config),
+			logging.NewField("error", err.Error()),
+		)
+	}
+
+	_, _ = fmt.Fprintf(out, "✓ Upgraded config at %s from schema version %d to %d\n",
+		flags.Config, result.FromVersion, result.ToVersion)
+
+	return nil
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/config"
+)
+
+// Feature: CLI_UPGRADE
+// Spec: spec/commands/upgrade.md
+
+const legacyConfig = `project:
+  name: demo
+frontend:
+  provider: generic
+  dev:
+    command: ["npm", "run", "dev"]
+environments:
+  dev:
+    driver: local
+`
+
+func writeLegacyConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+	if err := os.WriteFile(path, []byte(legacyConfig), 0o600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	return path
+}
+
+func TestUpgradeCommand_DryRunPrintsDiffAndKeepsFile(t *testing.T) {
+	path := writeLegacyConfig(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewUpgradeCommand())
+
+	out, err := executeCommandForGolden(root, "upgrade", "--config", path, "--dry-run")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out, "v0 -> v1") {
+		t.Errorf("expected migration listing, got:\n%s", out)
+	}
+	if !strings.Contains(out, "+version: 1") {
+		t.Errorf("expected diff to add version, got:\n%s", out)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading config: %v", err)
+	}
+	if string(data) != legacyConfig {
+		t.Fatalf("expected config to be untouched in dry-run, got:\n%s", data)
+	}
+}
+
+func TestUpgradeCommand_WritesUpgradedConfig(t *testing.T) {
+	path := writeLegacyConfig(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewUpgradeCommand())
+
+	out, err := executeCommandForGolden(root, "upgrade", "--config", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Upgraded config") {
+		t.Errorf("expected success message, got:\n%s", out)
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("expected upgraded config to load, got: %v", err)
+	}
+	if cfg.Version != config.CurrentSchemaVersion {
+		t.Fatalf("expected version %d, got %d", config.CurrentSchemaVersion, cfg.Version)
+	}
+
+	// A second run is a no-op.
+	out, err = executeCommandForGolden(root, "upgrade", "--config", path)
+	if err != nil {
+		t.Fatalf("unexpected error on second run: %v", err)
+	}
+	if !strings.Contains(out, "already at schema version") {
+		t.Errorf("expected up-to-date message, got:\n%s", out)
+	}
+}
+
+func TestUpgradeCommand_MissingConfig(t *testing.T) {
+	root := newTestRootCommand()
+	root.AddCommand(NewUpgradeCommand())
+
+	_, err := executeCommandForGolden(root, "upgrade", "--config", filepath.Join(t.TempDir(), "missing.yml"))
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestUpgradeCommand_InvalidLogLevel(t *testing.T) {
+	path := writeLegacyConfig(t)
+
+	root := newTestRootCommand()
+	root.AddCommand(NewUpgradeCommand())
+
+	_, err := executeCommandForGolden(root, "upgrade", "--config", path, "--log-level", "network=debug")
+	if err == nil || !strings.Contains(err.Error(), "unknown log subsystem") {
+		t.Fatalf("expected unknown subsystem error, got %v", err)
+	}
+}
@@ -0,0 +1,420 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stagecraft/internal/cli/output"
+	"stagecraft/internal/containerruntime"
+	"stagecraft/internal/core"
+	"stagecraft/internal/failure"
+	"stagecraft/pkg/config"
+	backendproviders "stagecraft/pkg/providers/backend"
+	"stagecraft/pkg/providers/cloud"
+	"stagecraft/pkg/providers/network"
+)
+
+// Feature: CLI_VALIDATE
+// Spec: spec/commands/validate.md
+
+// validateView is the structured (json/yaml) form of `validate`. Checks
+// use the same statuses as doctor.
+type validateView struct {
+	Environments []string      `json:"environments" yaml:"environments"`
+	Checks       []doctorCheck `json:"checks" yaml:"checks"`
+}
+
+func (v *validateView) add(name, status, detail, hint string) {
+	v.Checks = append(v.Checks, doctorCheck{Name: name, Status: status, Detail: detail, Hint: hint})
+}
+
+// NewValidateCommand returns the `stagecraft validate` command.
+func NewValidateCommand() *cobra.Command {
+	var opts validateOptions
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check config, provider configs, credentials and deploy plans",
+		Long: `Run every offline check a deploy depends on: load and validate the
+config, validate the cloud, network and backend provider configs, check
+that the credential environment variables they name are set, and generate
+the deploy plan and compose file for the environment.
+
+Nothing is built or written, and nothing is contacted unless
+--verify-credentials is set: it then confirms the credentials with
+read-only provider API calls and a registry login. Exits non-zero when a
+check fails, so it can gate merges in CI:
+
+  stagecraft validate --all`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runValidate(cmd, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.All, "all", false, "validate every environment instead of only --env")
+	cmd.Flags().BoolVar(&opts.VerifyCredentials, "verify-credentials", false, "confirm credentials with read-only provider API calls and a registry login")
+	flagNeedsNetwork(cmd, "verify-credentials")
+	registerEnvCompletion(cmd)
+
+	return cmd
+}
+
+func runValidate(cmd *cobra.Command, opts validateOptions) error {
+	flags, err := ResolveFlags(cmd, nil)
+	if err != nil {
+		return fmt.Errorf("validate: resolving flags: %w", err)
+	}
+
+	format, err := output.ParseFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	view := &validateView{Environments: []string{}, Checks: []doctorCheck{}}
+
+	cfg, err := config.Load(flags.Config)
+	switch {
+	case errors.Is(err, config.ErrConfigNotFound):
+		return fmt.Errorf("validate: stagecraft config not found at %s", flags.Config)
+	case err != nil:
+		view.add("config", doctorFail, err.Error(), "")
+	default:
+		view.add("config", doctorOK, flags.Config, "")
+
+		envs, err := validateEnvironments(cmd, cfg, opts.All)
+		if err != nil {
+			return fmt.Errorf("validate: %w", err)
+		}
+		view.Environments = envs
+
+		logger, err := newLogger(flags)
+		if err != nil {
+			return err
+		}
+		ctx := commandContext(cmd)
+		version, _ := resolveVersion(ctx, "", logger)
+
+		runValidateChecks(ctx, view, cfg, envs, version, opts)
+	}
+
+	if err := output.Render(cmd.OutOrStdout(), format, view, func(w io.Writer) error {
+		displayChecks(w, view.Checks)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, c := range view.Checks {
+		if c.Status == doctorFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("validate: %d check(s) failed", failed)
+	}
+	return nil
+}
+
+// validateEnvironments returns the environments to validate, sorted: all
+// of them with --all, otherwise the resolved --env.
+func validateEnvironments(cmd *cobra.Command, cfg *config.Config, all bool) ([]string, error) {
+	if all {
+		envs := make([]string, 0, len(cfg.Environments))
+		for name := range cfg.Environments {
+			envs = append(envs, name)
+		}
+		sort.Strings(envs)
+		return envs, nil
+	}
+
+	flags, err := ResolveFlags(cmd, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return []string{flags.Env}, nil
+}
+
+// validateOptions selects the optional validate checks.
+type validateOptions struct {
+	All               bool
+	VerifyCredentials bool // --verify-credentials: call provider APIs and the registry
+}
+
+// validatedProvider is a cloud or network provider whose config is valid.
+type validatedProvider struct {
+	name           string // check name, e.g. "cloud digitalocean"
+	config         any
+	credentialEnvs []string
+
+	// verify confirms the credentials online; nil when the provider
+	// cannot.
+	verify func(ctx context.Context, config any) (string, error)
+}
+
+func runValidateChecks(ctx context.Context, view *validateView, cfg *config.Config, envs []string, version string, opts validateOptions) {
+	var providers []validatedProvider
+	if cfg.Cloud != nil && cfg.Cloud.Provider != "" {
+		if p, ok := validateCloudProvider(view, cfg.Cloud); ok {
+			providers = append(providers, p)
+		}
+	}
+	if cfg.Network != nil && cfg.Network.Provider != "" {
+		if p, ok := validateNetworkProvider(view, cfg.Network); ok {
+			providers = append(providers, p)
+		}
+	}
+
+	var credentialEnvs []string
+	for _, p := range providers {
+		credentialEnvs = append(credentialEnvs, p.credentialEnvs...)
+	}
+	if len(credentialEnvs) > 0 {
+		view.add(validateCredentialsCheck(credentialEnvs))
+	}
+
+	if opts.VerifyCredentials {
+		for _, p := range providers {
+			// Unset credentials already failed the credentials check.
+			if slices.ContainsFunc(p.credentialEnvs, func(env string) bool { return os.Getenv(env) == "" }) {
+				continue
+			}
+			view.add(verifyProviderCheck(ctx, p))
+		}
+		if host := imageRegistry(deployImageTag(cfg, version)); host != "" {
+			view.add(verifyRegistryCheck(ctx, cfg, host))
+		}
+	}
+
+	validateBackendProviders(ctx, view, cfg, version)
+
+	for _, env := range envs {
+		view.add(validatePlanCheck(cfg, env, version))
+	}
+}
+
+// validateCloudProvider validates the configured cloud provider's config.
+func validateCloudProvider(view *validateView, cloudCfg *config.CloudConfig) (validatedProvider, bool) {
+	p := validatedProvider{
+		name:   "cloud " + cloudCfg.Provider,
+		config: cloudCfg.Providers[cloudCfg.Provider],
+	}
+
+	provider, err := cloud.Get(cloudCfg.Provider)
+	if err != nil {
+		view.add(p.name, doctorFail, err.Error(), "")
+		return p, false
+	}
+
+	validator, ok := provider.(cloud.ConfigValidator)
+	if !ok {
+		view.add(p.name, doctorWarn, "provider cannot validate its config offline", "")
+		return p, false
+	}
+
+	p.credentialEnvs, err = validator.ValidateConfig(p.config)
+	if err != nil {
+		view.add(p.name, doctorFail, err.Error(), fmt.Sprintf("fix cloud.providers.%s", cloudCfg.Provider))
+		return p, false
+	}
+	if verifier, ok := provider.(cloud.CredentialVerifier); ok {
+		p.verify = verifier.VerifyCredentials
+	}
+	view.add(p.name, doctorOK, "config valid", "")
+	return p, true
+}
+
+// validateNetworkProvider validates the configured network provider's
+// config.
+func validateNetworkProvider(view *validateView, networkCfg *config.NetworkConfig) (validatedProvider, bool) {
+	p := validatedProvider{
+		name:   "network " + networkCfg.Provider,
+		config: networkCfg.Providers[networkCfg.Provider],
+	}
+
+	provider, err := network.Get(networkCfg.Provider)
+	if err != nil {
+		view.add(p.name, doctorFail, err.Error(), "")
+		return p, false
+	}
+
+	validator, ok := provider.(network.ConfigValidator)
+	if !ok {
+		view.add(p.name, doctorWarn, "provider cannot validate its config offline", "")
+		return p, false
+	}
+
+	p.credentialEnvs, err = validator.ValidateConfig(p.config)
+	if err != nil {
+		view.add(p.name, doctorFail, err.Error(), fmt.Sprintf("fix network.providers.%s", networkCfg.Provider))
+		return p, false
+	}
+	if verifier, ok := provider.(network.CredentialVerifier); ok {
+		p.verify = verifier.VerifyCredentials
+	}
+	view.add(p.name, doctorOK, "config valid", "")
+	return p, true
+}
+
+// validateCredentialsCheck reports credential env vars that are unset or
+// empty. Values are never shown.
+func validateCredentialsCheck(envs []string) (name, status, detail, hint string) {
+	sort.Strings(envs)
+	envs = slices.Compact(envs)
+
+	var missing []string
+	for _, env := range envs {
+		if os.Getenv(env) == "" {
+			missing = append(missing, env)
+		}
+	}
+	if len(missing) > 0 {
+		return "credentials", doctorFail,
+			fmt.Sprintf("not set: %s", strings.Join(missing, ", ")),
+			"export the variables, or add them as CI secrets"
+	}
+	return "credentials", doctorOK, fmt.Sprintf("set: %s", strings.Join(envs, ", ")), ""
+}
+
+// verifyProviderCheck confirms a provider's credentials with a read-only
+// API call, telling rejected credentials apart from an unreachable API.
+func verifyProviderCheck(ctx context.Context, p validatedProvider) (name, status, detail, hint string) {
+	name = "verify " + p.name
+	if p.verify == nil {
+		return name, doctorWarn, "provider cannot verify credentials online", ""
+	}
+
+	detail, err := p.verify(ctx, p.config)
+	if err == nil {
+		return name, doctorOK, detail, ""
+	}
+	switch failure.Classify(err).Class {
+	case failure.ClassConfigInvalid:
+		return name, doctorFail, "rejected: " + err.Error(), "rotate the credential or fix the env var holding it"
+	case failure.ClassTransientEnvironment:
+		return name, doctorFail, "unreachable: " + err.Error(), "check network access to the provider API and retry; the credential was not checked"
+	}
+	return name, doctorFail, err.Error(), ""
+}
+
+// imageRegistry returns the registry host of an image reference, or ""
+// when the reference names no registry (Docker Hub).
+func imageRegistry(image string) string {
+	first, _, ok := strings.Cut(image, "/")
+	if !ok || (!strings.ContainsAny(first, ".:") && first != "localhost") {
+		return ""
+	}
+	return first
+}
+
+// verifyRegistryCheck runs `<runtime> login <host>` without a terminal, so
+// the runtime re-authenticates with its stored credentials or fails
+// instead of prompting.
+func verifyRegistryCheck(ctx context.Context, cfg *config.Config, host string) (name, status, detail, hint string) {
+	name = "verify registry " + host
+	runtime := containerruntime.ForConfig(cfg)
+
+	cmd := runtime.Command("login", host)
+	cmd.Stdin = strings.NewReader("")
+	result, err := newRunner().Run(ctx, cmd)
+	if err == nil {
+		return name, doctorOK, "stored credentials accepted", ""
+	}
+
+	output := err.Error()
+	if result != nil && len(bytes.TrimSpace(result.Stderr)) > 0 {
+		output = string(bytes.TrimSpace(result.Stderr))
+	}
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "tty") || strings.Contains(lower, "interactive") || strings.Contains(lower, "username"):
+		return name, doctorFail, "no stored credentials",
+			fmt.Sprintf("run `%s login %s` or configure a credential helper", runtime, host)
+	case strings.Contains(lower, "unauthorized") || strings.Contains(lower, "denied") ||
+		strings.Contains(lower, "forbidden") || strings.Contains(lower, "401") || strings.Contains(lower, "403"):
+		return name, doctorFail, "rejected: " + output,
+			fmt.Sprintf("log in again with `%s login %s`", runtime, host)
+	case failure.Classify(errors.New(output)).Class == failure.ClassTransientEnvironment:
+		return name, doctorFail, "unreachable: " + output, "check network access to the registry and retry; the credentials were not checked"
+	}
+	return name, doctorFail, output, ""
+}
+
+// validateBackendProviders asks each backend service's provider for its
+// build plan, which parses and validates the provider config.
+func validateBackendProviders(ctx context.Context, view *validateView, cfg *config.Config, version string) {
+	workdir, _ := os.Getwd()
+
+	for _, svc := range cfg.Backend.ServiceList() {
+		name := "backend " + svc.Provider
+		if svc.Name != config.DefaultBackendService {
+			name = fmt.Sprintf("backend %s (%s)", svc.Name, svc.Provider)
+		}
+
+		provider, err := backendproviders.Get(svc.Provider)
+		if err != nil {
+			view.add(name, doctorFail, err.Error(), "")
+			continue
+		}
+		providerCfg, err := svc.GetProviderConfig()
+		if err != nil {
+			view.add(name, doctorFail, err.Error(), "")
+			continue
+		}
+
+		if _, err := provider.Plan(ctx, backendproviders.PlanOptions{
+			Config:   providerCfg,
+			ImageTag: deployServiceImageTag(cfg, svc.Name, version),
+			WorkDir:  backendServiceWorkDir(svc, workdir),
+		}); err != nil {
+			view.add(name, doctorFail, err.Error(), "")
+			continue
+		}
+		view.add(name, doctorOK, "config valid", "")
+	}
+}
+
+// validatePlanCheck generates the deploy plan for env and, for deploy
+// environments, renders the compose file with writes discarded.
+func validatePlanCheck(cfg *config.Config, env, version string) (name, status, detail, hint string) {
+	name = "plan " + env
+
+	plan, err := core.NewPlanner(cfg).PlanDeploy(env)
+	if err != nil {
+		return name, doctorFail, err.Error(), ""
+	}
+	detail = fmt.Sprintf("%d operation(s)", len(plan.Operations))
+
+	if cfg.Environments[env].Driver == "local" {
+		return name, doctorOK, detail, ""
+	}
+
+	if _, err := renderDeployCompose(cfg, env, version, composeRenderOptions{}); err != nil {
+		return name, doctorFail, err.Error(), fmt.Sprintf("run `stagecraft compose render --env %s` for details", env)
+	}
+	return name, doctorOK, detail + ", compose file rendered", ""
+}
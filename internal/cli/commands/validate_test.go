@@ -0,0 +1,320 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/failure"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_VALIDATE
+// Spec: spec/commands/validate.md
+
+func runValidateJSON(t *testing.T, args ...string) (validateView, error) {
+	t.Helper()
+
+	root := newTestRootCommand()
+	root.AddCommand(NewValidateCommand())
+
+	out, err := executeCommandForGolden(root, append([]string{"validate", "--output", "json"}, args...)...)
+
+	// A failing run prints the error and usage after the JSON document.
+	var view validateView
+	if jsonErr := json.NewDecoder(strings.NewReader(out)).Decode(&view); jsonErr != nil {
+		t.Fatalf("parsing output: %v\n%s", jsonErr, out)
+	}
+	return view, err
+}
+
+func findValidateCheck(view validateView, name string) (doctorCheck, bool) {
+	for _, c := range view.Checks {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return doctorCheck{}, false
+}
+
+func TestValidate_AllEnvironmentsPass(t *testing.T) {
+	setupComposeProject(t)
+
+	view, err := runValidateJSON(t, "--all")
+	if err != nil {
+		t.Fatalf("unexpected error: %v (checks: %+v)", err, view.Checks)
+	}
+
+	if got := strings.Join(view.Environments, ","); got != "dev,staging" {
+		t.Errorf("environments = %q, want dev,staging", got)
+	}
+	for _, name := range []string{"config", "backend generic", "plan dev", "plan staging"} {
+		c, ok := findValidateCheck(view, name)
+		if !ok || c.Status != doctorOK {
+			t.Errorf("check %q = %+v (found %v), want ok", name, c, ok)
+		}
+	}
+	if c, _ := findValidateCheck(view, "plan staging"); !strings.Contains(c.Detail, "compose file rendered") {
+		t.Errorf("expected the staging compose file to be rendered, got %q", c.Detail)
+	}
+	if _, err := os.Stat(".stagecraft"); !os.IsNotExist(err) {
+		t.Errorf("expected validate to write nothing, stat .stagecraft: %v", err)
+	}
+}
+
+func TestValidate_ProviderConfigAndCredentialsFail(t *testing.T) {
+	setupComposeProject(t)
+	t.Setenv("VALIDATE_TEST_DO_TOKEN", "")
+
+	cfg, err := os.ReadFile("stagecraft.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = append(cfg, []byte(`cloud:
+  provider: digitalocean
+  providers:
+    digitalocean:
+      token_env: VALIDATE_TEST_DO_TOKEN
+      ssh_key_name: deploy
+      hosts:
+        staging:
+          app-1:
+            role: app
+network:
+  provider: tailscale
+  providers:
+    tailscale:
+      auth_key_env: VALIDATE_TEST_TS_KEY
+`)...)
+	if err := os.WriteFile("stagecraft.yml", cfg, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	view, err := runValidateJSON(t, "--env", "staging")
+	if err == nil || !strings.Contains(err.Error(), "2 check(s) failed") {
+		t.Fatalf("expected 2 failed checks, got %v (checks: %+v)", err, view.Checks)
+	}
+
+	if c, _ := findValidateCheck(view, "cloud digitalocean"); c.Status != doctorOK {
+		t.Errorf("cloud check = %+v, want ok", c)
+	}
+	if c, _ := findValidateCheck(view, "network tailscale"); c.Status != doctorFail || !strings.Contains(c.Detail, "tailnet_domain") {
+		t.Errorf("network check = %+v, want fail naming tailnet_domain", c)
+	}
+	// The invalid network config contributes no credentials.
+	if c, _ := findValidateCheck(view, "credentials"); c.Status != doctorFail || c.Detail != "not set: VALIDATE_TEST_DO_TOKEN" {
+		t.Errorf("credentials check = %+v, want fail for VALIDATE_TEST_DO_TOKEN only", c)
+	}
+	if _, ok := findValidateCheck(view, "plan dev"); ok {
+		t.Error("expected only --env staging to be planned")
+	}
+}
+
+func TestValidate_MissingBaseComposeFailsDeployPlan(t *testing.T) {
+	setupComposeProject(t)
+	if err := os.Remove("docker-compose.yml"); err != nil {
+		t.Fatal(err)
+	}
+
+	view, err := runValidateJSON(t, "--all")
+	if err == nil {
+		t.Fatal("expected validate to fail")
+	}
+
+	if c, _ := findValidateCheck(view, "plan dev"); c.Status != doctorOK {
+		t.Errorf("local environment plan = %+v, want ok", c)
+	}
+	if c, _ := findValidateCheck(view, "plan staging"); c.Status != doctorFail || !strings.Contains(c.Detail, "docker-compose.yml") {
+		t.Errorf("staging plan = %+v, want fail naming docker-compose.yml", c)
+	}
+}
+
+// fakeVerifyingCloudProvider validates its config offline and verifies
+// credentials with a canned result.
+type fakeVerifyingCloudProvider struct {
+	fakeCloudProvider
+	credentialEnv string
+	verifyErr     error
+}
+
+func (f *fakeVerifyingCloudProvider) ValidateConfig(any) ([]string, error) {
+	return []string{f.credentialEnv}, nil
+}
+
+func (f *fakeVerifyingCloudProvider) VerifyCredentials(context.Context, any) (string, error) {
+	if f.verifyErr != nil {
+		return "", f.verifyErr
+	}
+	return "account ops@example.com (active)", nil
+}
+
+// loginRunner answers `<runtime> login` with canned stderr.
+type loginRunner struct {
+	stderr string
+	err    error
+	cmds   []string
+}
+
+func (r *loginRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	r.cmds = append(r.cmds, strings.Join(append([]string{cmd.Name}, cmd.Args...), " "))
+	return &executil.Result{Stderr: []byte(r.stderr)}, r.err
+}
+
+func (r *loginRunner) RunStream(context.Context, executil.Command, io.Writer) error { //nolint:gocritic // Runner interface
+	return nil
+}
+
+// setupVerifyProject writes a project pushing to registry.example.com
+// whose cloud provider is id.
+func setupVerifyProject(t *testing.T, id string, runner *loginRunner) {
+	t.Helper()
+	setupComposeProject(t)
+	t.Setenv("VALIDATE_TEST_CLOUD_TOKEN", "secret")
+
+	cfg := `project:
+  name: registry.example.com/team/app
+container_runtime: docker
+cloud:
+  provider: ` + id + `
+  providers:
+    ` + id + `: {}
+environments:
+  dev:
+    driver: local
+`
+	if err := os.WriteFile("stagecraft.yml", []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	origRunner := newRunner
+	newRunner = func() executil.Runner { return runner }
+	t.Cleanup(func() { newRunner = origRunner })
+}
+
+func TestValidate_VerifyCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		verifyErr   error
+		loginStderr string
+		loginErr    error
+		wantFailed  string
+		wantCloud   string
+		wantLogin   string
+	}{
+		{
+			name:      "accepted",
+			id:        "test-cloud-verify-ok",
+			wantCloud: "account ops@example.com (active)",
+			wantLogin: "stored credentials accepted",
+		},
+		{
+			name:        "rejected",
+			id:          "test-cloud-verify-rejected",
+			verifyErr:   failure.New(failure.ClassConfigInvalid, "API token rejected"),
+			loginStderr: "Error response from daemon: Get \"https://registry.example.com/v2/\": unauthorized: authentication required",
+			loginErr:    errors.New("command failed with exit code 1"),
+			wantFailed:  "2 check(s) failed",
+			wantCloud:   "rejected: API token rejected",
+			wantLogin:   "rejected: ",
+		},
+		{
+			name:        "unreachable",
+			id:          "test-cloud-verify-unreachable",
+			verifyErr:   failure.New(failure.ClassTransientEnvironment, "API unreachable"),
+			loginStderr: "Error: Cannot perform an interactive login from a non TTY device",
+			loginErr:    errors.New("command failed with exit code 1"),
+			wantFailed:  "2 check(s) failed",
+			wantCloud:   "unreachable: API unreachable",
+			wantLogin:   "no stored credentials",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cloud.Register(&fakeVerifyingCloudProvider{
+				fakeCloudProvider: fakeCloudProvider{id: tt.id},
+				credentialEnv:     "VALIDATE_TEST_CLOUD_TOKEN",
+				verifyErr:         tt.verifyErr,
+			})
+			runner := &loginRunner{stderr: tt.loginStderr, err: tt.loginErr}
+			setupVerifyProject(t, tt.id, runner)
+
+			view, err := runValidateJSON(t, "--verify-credentials")
+			if tt.wantFailed == "" && err != nil {
+				t.Fatalf("unexpected error: %v (checks: %+v)", err, view.Checks)
+			}
+			if tt.wantFailed != "" && (err == nil || !strings.Contains(err.Error(), tt.wantFailed)) {
+				t.Fatalf("error = %v, want %q (checks: %+v)", err, tt.wantFailed, view.Checks)
+			}
+
+			if c, _ := findValidateCheck(view, "verify cloud "+tt.id); !strings.HasPrefix(c.Detail, tt.wantCloud) {
+				t.Errorf("cloud verify check = %+v, want detail %q", c, tt.wantCloud)
+			}
+			if c, _ := findValidateCheck(view, "verify registry registry.example.com"); !strings.HasPrefix(c.Detail, tt.wantLogin) {
+				t.Errorf("registry verify check = %+v, want detail %q", c, tt.wantLogin)
+			}
+			if len(runner.cmds) != 1 || runner.cmds[0] != "docker login registry.example.com" {
+				t.Errorf("commands = %v, want one registry login", runner.cmds)
+			}
+		})
+	}
+}
+
+func TestValidate_NoVerificationByDefault(t *testing.T) {
+	id := "test-cloud-verify-default"
+	cloud.Register(&fakeVerifyingCloudProvider{
+		fakeCloudProvider: fakeCloudProvider{id: id},
+		credentialEnv:     "VALIDATE_TEST_CLOUD_TOKEN",
+		verifyErr:         errors.New("must not be called"),
+	})
+	runner := &loginRunner{}
+	setupVerifyProject(t, id, runner)
+
+	view, err := runValidateJSON(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v (checks: %+v)", err, view.Checks)
+	}
+	for _, c := range view.Checks {
+		if strings.HasPrefix(c.Name, "verify ") {
+			t.Errorf("unexpected online check %+v", c)
+		}
+	}
+	if len(runner.cmds) != 0 {
+		t.Errorf("commands = %v, want none", runner.cmds)
+	}
+}
+
+func TestImageRegistry(t *testing.T) {
+	tests := map[string]string{
+		"app:v1":                          "",
+		"team/app:v1":                     "",
+		"registry.example.com/team/app:1": "registry.example.com",
+		"localhost:5000/app:v1":           "localhost:5000",
+		"localhost/app:v1":                "localhost",
+	}
+	for image, want := range tests {
+		if got := imageRegistry(image); got != want {
+			t.Errorf("imageRegistry(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
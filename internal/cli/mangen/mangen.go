@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package mangen renders roff man pages from a Cobra command tree.
+//
+// It covers the subset of cobra/doc needed by `stagecraft docs man` without
+// pulling in a markdown-to-roff dependency. Output is deterministic: pages
+// carry no generation date and flags are emitted in sorted order.
+package mangen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Feature: CLI_COMPLETION
+// Spec: spec/commands/completion.md
+
+// Header holds the .TH fields shared by every generated page.
+type Header struct {
+	Section string
+	Source  string
+	Manual  string
+}
+
+// DefaultHeader returns the header used for Stagecraft pages.
+func DefaultHeader() Header {
+	return Header{
+		Section: "1",
+		Source:  "Stagecraft",
+		Manual:  "Stagecraft Manual",
+	}
+}
+
+// GenerateTree writes a man page for cmd and every visible descendant into dir.
+// It returns the written file paths in sorted order.
+func GenerateTree(cmd *cobra.Command, dir string, header Header) ([]string, error) {
+	var written []string
+	if err := generateTree(cmd, dir, header, &written); err != nil {
+		return nil, err
+	}
+	sort.Strings(written)
+	return written, nil
+}
+
+func generateTree(cmd *cobra.Command, dir string, header Header, written *[]string) error {
+	for _, c := range visibleChildren(cmd) {
+		if err := generateTree(c, dir, header, written); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Generate(cmd, &buf, header); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, PageName(cmd, header))
+	// #nosec G306 -- man pages are meant to be world-readable
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing man page %s: %w", path, err)
+	}
+	*written = append(*written, path)
+	return nil
+}
+
+// PageName returns the file name for cmd's page, e.g. "stagecraft-deploy.1".
+func PageName(cmd *cobra.Command, header Header) string {
+	return pageBase(cmd) + "." + header.Section
+}
+
+// Generate writes the man page for a single command to w.
+func Generate(cmd *cobra.Command, w io.Writer, header Header) error {
+	cmd.InitDefaultHelpFlag()
+
+	var b strings.Builder
+	title := strings.ToUpper(pageBase(cmd))
+
+	fmt.Fprintf(&b, ".TH %q %q \"\" %q %q\n", title, header.Section, header.Source, header.Manual)
+	b.WriteString(".nh\n.ad l\n")
+
+	b.WriteString(".SH NAME\n")
+	fmt.Fprintf(&b, "%s \\- %s\n", escape(pageBase(cmd)), escape(cmd.Short))
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, "\\fB%s\\fP\n", escape(cmd.UseLine()))
+
+	b.WriteString(".SH DESCRIPTION\n")
+	description := cmd.Long
+	if description == "" {
+		description = cmd.Short
+	}
+	writeParagraphs(&b, description)
+
+	writeFlags(&b, "OPTIONS", cmd.NonInheritedFlags())
+	writeFlags(&b, "OPTIONS INHERITED FROM PARENT COMMANDS", cmd.InheritedFlags())
+
+	if cmd.Example != "" {
+		b.WriteString(".SH EXAMPLE\n.PP\n.RS\n.nf\n")
+		b.WriteString(escape(cmd.Example))
+		b.WriteString("\n.fi\n.RE\n")
+	}
+
+	var seeAlso []string
+	if cmd.HasParent() {
+		seeAlso = append(seeAlso, fmt.Sprintf("\\fB%s(%s)\\fP", escape(pageBase(cmd.Parent())), header.Section))
+	}
+	for _, c := range visibleChildren(cmd) {
+		seeAlso = append(seeAlso, fmt.Sprintf("\\fB%s(%s)\\fP", escape(pageBase(c)), header.Section))
+	}
+	if len(seeAlso) > 0 {
+		b.WriteString(".SH SEE ALSO\n")
+		b.WriteString(strings.Join(seeAlso, ", "))
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// visibleChildren returns documented subcommands sorted by name.
+func visibleChildren(cmd *cobra.Command) []*cobra.Command {
+	var children []*cobra.Command
+	for _, c := range cmd.Commands() {
+		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		children = append(children, c)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Name() < children[j].Name()
+	})
+	return children
+}
+
+func pageBase(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+}
+
+func writeFlags(b *strings.Builder, section string, flags *pflag.FlagSet) {
+	if !flags.HasAvailableFlags() {
+		return
+	}
+	fmt.Fprintf(b, ".SH %s\n", section)
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Deprecated != "" {
+			return
+		}
+		b.WriteString(".TP\n")
+		if f.Shorthand != "" && f.ShorthandDeprecated == "" {
+			fmt.Fprintf(b, "\\fB\\-%s\\fP, ", escape(f.Shorthand))
+		}
+		fmt.Fprintf(b, "\\fB\\-\\-%s\\fP", escape(f.Name))
+		if f.Value.Type() != "bool" {
+			fmt.Fprintf(b, "=%s", escape(fmt.Sprintf("%q", f.DefValue)))
+		}
+		b.WriteString("\n")
+		b.WriteString(escape(f.Usage))
+		b.WriteString("\n")
+	})
+}
+
+func writeParagraphs(b *strings.Builder, text string) {
+	for _, para := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		b.WriteString(".PP\n")
+		b.WriteString(escape(para))
+		b.WriteString("\n")
+	}
+}
+
+// escape makes text safe to embed in roff.
+func escape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
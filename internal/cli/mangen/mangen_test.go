@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package mangen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// Feature: CLI_COMPLETION
+// Spec: spec/commands/completion.md
+
+func newTestTree() *cobra.Command {
+	root := &cobra.Command{Use: "stagecraft", Short: "root command"}
+	root.PersistentFlags().StringP("env", "e", "", "target environment")
+
+	deploy := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy an environment",
+		Long:  "Deploy builds and rolls out.\n\n.Leading dots are escaped.",
+		Run:   func(*cobra.Command, []string) {},
+	}
+	deploy.Flags().Bool("dry-run", false, "plan only")
+	deploy.Flags().String("version", "", "version to deploy")
+
+	hidden := &cobra.Command{Use: "secret", Hidden: true, Run: func(*cobra.Command, []string) {}}
+
+	root.AddCommand(deploy, hidden)
+	return root
+}
+
+func TestGenerate_RendersSections(t *testing.T) {
+	root := newTestTree()
+	deploy, _, err := root.Find([]string{"deploy"})
+	if err != nil {
+		t.Fatalf("finding deploy: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Generate(deploy, &buf, DefaultHeader()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`.TH "STAGECRAFT-DEPLOY" "1" "" "Stagecraft" "Stagecraft Manual"`,
+		`stagecraft\-deploy \- Deploy an environment`,
+		`\fB\-\-dry\-run\fP` + "\n",
+		`\fB\-\-version\fP=""`,
+		".SH OPTIONS INHERITED FROM PARENT COMMANDS",
+		`\fB\-e\fP, \fB\-\-env\fP=""`,
+		`\&.Leading dots are escaped.`,
+		`\fBstagecraft(1)\fP`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerateTree_SkipsHiddenAndIsDeterministic(t *testing.T) {
+	dir := t.TempDir()
+
+	written, err := GenerateTree(newTestTree(), dir, DefaultHeader())
+	if err != nil {
+		t.Fatalf("GenerateTree() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "stagecraft-deploy.1"),
+		filepath.Join(dir, "stagecraft.1"),
+	}
+	if len(written) != len(want) {
+		t.Fatalf("expected %v, got %v", want, written)
+	}
+	for i := range want {
+		if written[i] != want[i] {
+			t.Errorf("written[%d] = %q, want %q", i, written[i], want[i])
+		}
+	}
+
+	first, err := os.ReadFile(want[0])
+	if err != nil {
+		t.Fatalf("reading page: %v", err)
+	}
+	if _, err := GenerateTree(newTestTree(), dir, DefaultHeader()); err != nil {
+		t.Fatalf("GenerateTree() second run error = %v", err)
+	}
+	second, err := os.ReadFile(want[0])
+	if err != nil {
+		t.Fatalf("reading page: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatalf("expected identical output across runs")
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package output provides the shared output-formatting layer used by CLI
+// commands that support the global --output flag.
+//
+// Commands build a view struct with explicit json/yaml tags (field order is
+// the serialization order) and supply a table renderer for human output.
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Feature: CLI_OUTPUT_FORMAT
+// Spec: spec/commands/output-format.md
+
+// Format identifies an output format.
+type Format string
+
+const (
+	// FormatTable is human-readable output; it is the default.
+	FormatTable Format = "table"
+	// FormatJSON is indented JSON.
+	FormatJSON Format = "json"
+	// FormatYAML is YAML with two-space indentation.
+	FormatYAML Format = "yaml"
+)
+
+// ErrInvalidFormat is returned when an unknown format is requested.
+var ErrInvalidFormat = errors.New("invalid output format")
+
+// Formats returns all supported formats in the order shown in help text.
+func Formats() []Format {
+	return []Format{FormatTable, FormatJSON, FormatYAML}
+}
+
+// FormatNames returns the supported formats as a comma-separated string.
+func FormatNames() string {
+	names := make([]string, 0, len(Formats()))
+	for _, f := range Formats() {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, ", ")
+}
+
+// ParseFormat parses a format name. An empty string selects FormatTable.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FormatTable:
+		return FormatTable, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML:
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("%w %q (must be one of: %s)", ErrInvalidFormat, s, FormatNames())
+	}
+}
+
+// Render writes v to w in the given format. For FormatTable, table is
+// called instead of serializing v.
+func Render(w io.Writer, f Format, v any, table func(io.Writer) error) error {
+	switch f {
+	case FormatTable:
+		if table == nil {
+			return fmt.Errorf("%w %q: no table renderer", ErrInvalidFormat, f)
+		}
+		return table(w)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("encoding yaml: %w", err)
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("%w %q (must be one of: %s)", ErrInvalidFormat, f, FormatNames())
+	}
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package output
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// Feature: CLI_OUTPUT_FORMAT
+// Spec: spec/commands/output-format.md
+
+type sample struct {
+	Zeta  string            `json:"zeta" yaml:"zeta"`
+	Alpha int               `json:"alpha" yaml:"alpha"`
+	Tags  map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatTable, false},
+		{"table", FormatTable, false},
+		{"JSON", FormatJSON, false},
+		{"yaml", FormatYAML, false},
+		{"xml", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("ParseFormat(%q) expected ErrInvalidFormat, got %v", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestRender_StableFieldOrder(t *testing.T) {
+	v := sample{Zeta: "z", Alpha: 1, Tags: map[string]string{"b": "2", "a": "1"}}
+
+	var jsonBuf bytes.Buffer
+	if err := Render(&jsonBuf, FormatJSON, v, nil); err != nil {
+		t.Fatalf("Render(json) error = %v", err)
+	}
+	wantJSON := "{\n  \"zeta\": \"z\",\n  \"alpha\": 1,\n  \"tags\": {\n    \"a\": \"1\",\n    \"b\": \"2\"\n  }\n}\n"
+	if jsonBuf.String() != wantJSON {
+		t.Errorf("unexpected json:\n%s\nwant:\n%s", jsonBuf.String(), wantJSON)
+	}
+
+	var yamlBuf bytes.Buffer
+	if err := Render(&yamlBuf, FormatYAML, v, nil); err != nil {
+		t.Fatalf("Render(yaml) error = %v", err)
+	}
+	wantYAML := "zeta: z\nalpha: 1\ntags:\n  a: \"1\"\n  b: \"2\"\n"
+	if yamlBuf.String() != wantYAML {
+		t.Errorf("unexpected yaml:\n%s\nwant:\n%s", yamlBuf.String(), wantYAML)
+	}
+}
+
+func TestRender_TableUsesRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	err := Render(&buf, FormatTable, nil, func(w io.Writer) error {
+		_, err := io.WriteString(w, "human\n")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Render(table) error = %v", err)
+	}
+	if buf.String() != "human\n" {
+		t.Fatalf("expected table renderer output, got %q", buf.String())
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package prompt asks the operator to confirm destructive actions.
+//
+// Prompts are only shown on an interactive terminal. Anywhere else (CI,
+// pipes, redirected stdin) a confirmation fails at once unless it was
+// approved up front with --yes, so a run never waits for input nobody will
+// type.
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Feature: CLI_CONFIRM_PROMPT
+// Spec: spec/commands/confirm-prompt.md
+
+var (
+	// ErrNotInteractive is returned when a confirmation is needed but
+	// nobody can answer it.
+	ErrNotInteractive = errors.New("confirmation required but stdin is not an interactive terminal; pass --yes to proceed")
+
+	// ErrDeclined is returned when the operator does not confirm.
+	ErrDeclined = errors.New("confirmation declined")
+)
+
+// Confirmer asks for confirmations on in, writing prompts to out.
+type Confirmer struct {
+	in          *bufio.Reader
+	out         io.Writer
+	interactive bool
+	yes         bool
+}
+
+// New returns a Confirmer. interactive says whether in can answer prompts
+// (see Interactive); yes approves every confirmation without asking.
+func New(in io.Reader, out io.Writer, interactive, yes bool) *Confirmer {
+	return &Confirmer{in: bufio.NewReader(in), out: out, interactive: interactive, yes: yes}
+}
+
+// Interactive reports whether in is a terminal and the process is not
+// running under CI. A CI environment variable set to anything but "" or
+// "false" marks a CI run.
+func Interactive(in io.Reader) bool {
+	if ci := os.Getenv("CI"); ci != "" && ci != "false" {
+		return false
+	}
+	f, ok := in.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// The null device is a character device too, but nobody can answer on it
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, null) {
+		return false
+	}
+	return true
+}
+
+// Confirm asks a yes/no question, defaulting to no. "y" and "yes" approve.
+func (c *Confirmer) Confirm(question string) error {
+	return c.ask(question+" [y/N]: ", func(answer string) bool {
+		answer = strings.ToLower(answer)
+		return answer == "y" || answer == "yes"
+	})
+}
+
+// ConfirmTyped asks the operator to type want exactly, e.g. the name of
+// the environment about to be destroyed.
+func (c *Confirmer) ConfirmTyped(question, want string) error {
+	return c.ask(question+" ", func(answer string) bool {
+		return answer == want
+	})
+}
+
+func (c *Confirmer) ask(prompt string, approve func(string) bool) error {
+	if c.yes {
+		return nil
+	}
+	if !c.interactive {
+		return ErrNotInteractive
+	}
+
+	_, _ = fmt.Fprint(c.out, prompt)
+	answer, err := c.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading confirmation: %w", err)
+	}
+	if errors.Is(err, io.EOF) {
+		// Keep the next output off the prompt line
+		_, _ = fmt.Fprintln(c.out)
+	}
+	if !approve(strings.TrimSpace(answer)) {
+		return ErrDeclined
+	}
+	return nil
+}
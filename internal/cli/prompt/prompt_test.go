@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Feature: CLI_CONFIRM_PROMPT
+// Spec: spec/commands/confirm-prompt.md
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr error
+	}{
+		{"y\n", nil},
+		{"YES\n", nil},
+		{"  yes  \n", nil},
+		{"n\n", ErrDeclined},
+		{"\n", ErrDeclined},
+		{"", ErrDeclined},
+		{"yes please\n", ErrDeclined},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		err := New(strings.NewReader(tt.input), &out, true, false).Confirm("Delete 2 hosts?")
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Confirm(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+		}
+		if !strings.HasPrefix(out.String(), "Delete 2 hosts? [y/N]: ") {
+			t.Errorf("Confirm(%q) prompt = %q", tt.input, out.String())
+		}
+	}
+}
+
+func TestConfirmTyped(t *testing.T) {
+	var out bytes.Buffer
+	c := New(strings.NewReader("staging\nprod\n"), &out, true, false)
+
+	if err := c.ConfirmTyped("Type the environment name (staging):", "staging"); err != nil {
+		t.Fatalf("first ConfirmTyped() = %v, want nil", err)
+	}
+	if err := c.ConfirmTyped("Type the environment name (staging):", "staging"); !errors.Is(err, ErrDeclined) {
+		t.Fatalf("second ConfirmTyped() = %v, want ErrDeclined", err)
+	}
+	if got := strings.Count(out.String(), "Type the environment name (staging): "); got != 2 {
+		t.Errorf("prompt shown %d times, want 2: %q", got, out.String())
+	}
+}
+
+func TestConfirm_YesSkipsPrompt(t *testing.T) {
+	for _, interactive := range []bool{true, false} {
+		var out bytes.Buffer
+		c := New(strings.NewReader(""), &out, interactive, true)
+		if err := c.Confirm("Roll back?"); err != nil {
+			t.Errorf("interactive=%v: Confirm() = %v, want nil", interactive, err)
+		}
+		if err := c.ConfirmTyped("Type destroy:", "destroy"); err != nil {
+			t.Errorf("interactive=%v: ConfirmTyped() = %v, want nil", interactive, err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("interactive=%v: prompted with --yes: %q", interactive, out.String())
+		}
+	}
+}
+
+func TestConfirm_NonInteractiveFailsWithoutReading(t *testing.T) {
+	var out bytes.Buffer
+	in := strings.NewReader("yes\n")
+	err := New(in, &out, false, false).Confirm("Roll back?")
+	if !errors.Is(err, ErrNotInteractive) {
+		t.Fatalf("Confirm() = %v, want ErrNotInteractive", err)
+	}
+	if out.Len() != 0 || in.Len() != len("yes\n") {
+		t.Errorf("non-interactive confirm prompted or read input: out=%q unread=%d", out.String(), in.Len())
+	}
+}
+
+func TestInteractive(t *testing.T) {
+	t.Setenv("CI", "")
+	if Interactive(strings.NewReader("yes\n")) {
+		t.Error("Interactive(strings.Reader) = true")
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if Interactive(f) {
+		t.Error("Interactive(regular file) = true")
+	}
+	null, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = null.Close() }()
+	if Interactive(null) {
+		t.Error("Interactive(os.DevNull) = true")
+	}
+}
+
+func TestInteractive_CIEnvironment(t *testing.T) {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		t.Skip("no controlling terminal")
+	}
+	defer func() { _ = tty.Close() }()
+
+	t.Setenv("CI", "true")
+	if Interactive(tty) {
+		t.Error("Interactive() = true with CI=true")
+	}
+	t.Setenv("CI", "false")
+	if !Interactive(tty) {
+		t.Error("Interactive() = false with CI=false on a terminal")
+	}
+}
@@ -21,7 +21,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"stagecraft/internal/buildinfo"
 	"stagecraft/internal/cli/commands"
+	"stagecraft/internal/core/env"
+	hostexec "stagecraft/internal/exec"
 	// "stagecraft/spec" // optional; see note below
 	// "github.com/bartekus/stagecraft/internal/cli/commands"
 	// "github.com/bartekus/stagecraft/spec" // optional; see note below
@@ -37,10 +40,8 @@ import (
 // Feature: ARCH_OVERVIEW
 // Spec: spec/overview.md
 func NewRootCommand() *cobra.Command {
-	version := os.Getenv("STAGECRAFT_VERSION")
-	if version == "" {
-		version = "0.0.0-dev"
-	}
+	// Mask secret-looking environment values in every logged command.
+	hostexec.Secrets.AddEnviron(os.Environ(), env.IsSecretKey)
 
 	cmd := &cobra.Command{
 		Use:           "stagecraft",
@@ -48,35 +49,64 @@ func NewRootCommand() *cobra.Command {
 		Long:          "Stagecraft is a Go-based CLI that orchestrates application deployment and infrastructure workflows.",
 		SilenceUsage:  true, // don't dump usage on user errors
 		SilenceErrors: true, // centralize error printing in main()
+
+		// Refuse commands that need the network under --offline.
+		PersistentPreRunE: commands.CheckOffline,
 	}
 
 	// Global flags - registered in lexicographic order for deterministic help output
 	cmd.PersistentFlags().StringP("config", "c", "", "path to stagecraft.yml")
 	cmd.PersistentFlags().Bool("dry-run", false, "show actions without executing")
 	cmd.PersistentFlags().StringP("env", "e", "", "target environment")
+	cmd.PersistentFlags().String("error-format", "", "failure report format: text, json")
+	cmd.PersistentFlags().String("log-format", "", "log format: text, json")
+	cmd.PersistentFlags().String("log-level", "", "log level, optionally per subsystem (e.g. info,provider=warn)")
+	cmd.PersistentFlags().Bool("offline", false, "use cached provider data; fail commands that need the network")
+	cmd.PersistentFlags().StringP("output", "o", "", "output format: table, json, yaml")
 	cmd.PersistentFlags().BoolP("verbose", "v", false, "enable verbose output")
+	_ = cmd.RegisterFlagCompletionFunc("env", commands.CompleteEnvironments)
 
 	// Version command – simple and explicit.
 	cmd.AddCommand(&cobra.Command{
 		Use:   "version",
 		Short: "Print the version number of Stagecraft",
 		Run: func(cmd *cobra.Command, args []string) {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Stagecraft version %s\n", version)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Stagecraft version %s\n", buildinfo.Version())
 		},
 	})
 
 	// Subcommands - keep registrations in lexicographic order by .Use
 	// to ensure deterministic help output (see Agent.md determinism rules).
 	cmd.AddCommand(commands.NewAgentCommand())
+	cmd.AddCommand(commands.NewAuditCommand())
 	cmd.AddCommand(commands.NewBuildCommand())
+	cmd.AddCommand(commands.NewCacheCommand())
+	cmd.AddCommand(commands.NewCompletionCommand())
+	cmd.AddCommand(commands.NewComposeCommand())
+	cmd.AddCommand(commands.NewDBCommand())
 	cmd.AddCommand(commands.NewDeployCommand())
+	cmd.AddCommand(commands.NewDestroyCommand())
 	cmd.AddCommand(commands.NewDevCommand())
+	cmd.AddCommand(commands.NewDiffCommand())
+	cmd.AddCommand(commands.NewDocsCommand())
+	cmd.AddCommand(commands.NewDoctorCommand())
+	cmd.AddCommand(commands.NewEngineCommand())
+	cmd.AddCommand(commands.NewEnvCommand())
+	cmd.AddCommand(commands.NewHostsCommand())
 	cmd.AddCommand(commands.NewInfraCommand())
 	cmd.AddCommand(commands.NewInitCommand())
 	cmd.AddCommand(commands.NewMigrateCommand())
+	cmd.AddCommand(commands.NewNetworkCommand())
 	cmd.AddCommand(commands.NewPlanCommand())
+	cmd.AddCommand(commands.NewProvidersCommand())
+	cmd.AddCommand(commands.NewReconcileCommand())
 	cmd.AddCommand(commands.NewReleasesCommand())
 	cmd.AddCommand(commands.NewRollbackCommand())
+	cmd.AddCommand(commands.NewSelfUpdateCommand())
+	cmd.AddCommand(commands.NewStateCommand())
+	cmd.AddCommand(commands.NewUICommand())
+	cmd.AddCommand(commands.NewUpgradeCommand())
+	cmd.AddCommand(commands.NewValidateCommand())
 
 	return cmd
 }
@@ -353,3 +353,60 @@ func TestResolveFlags_ConfigDefaultPath(t *testing.T) {
 		t.Errorf("expected Config default to be %q, got %q", expected, flags.Config)
 	}
 }
+
+func TestResolveFlags_LogFlags(t *testing.T) {
+	t.Setenv("STAGECRAFT_LOG_FORMAT", "json")
+	t.Setenv("STAGECRAFT_LOG_LEVEL", "warn")
+
+	cmd := NewRootCommand()
+	if err := parseFlagsForTesting(cmd, []string{"--log-level", "debug,provider=warn", "version"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	flags, err := commands.ResolveFlags(cmd, nil)
+	if err != nil {
+		t.Fatalf("ResolveFlags() returned error: %v", err)
+	}
+
+	if flags.LogFormat != "json" {
+		t.Errorf("expected LogFormat from env to be 'json', got %q", flags.LogFormat)
+	}
+	if flags.LogLevel != "debug,provider=warn" {
+		t.Errorf("expected LogLevel flag to win over env, got %q", flags.LogLevel)
+	}
+}
+
+func TestResolveFlags_ErrorFormat(t *testing.T) {
+	cmd := NewRootCommand()
+	if err := parseFlagsForTesting(cmd, []string{"version"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	flags, err := commands.ResolveFlags(cmd, nil)
+	if err != nil {
+		t.Fatalf("ResolveFlags() returned error: %v", err)
+	}
+	if flags.ErrorFormat != "text" {
+		t.Errorf("expected ErrorFormat default to be 'text', got %q", flags.ErrorFormat)
+	}
+
+	t.Setenv("STAGECRAFT_ERROR_FORMAT", "json")
+	flags, err = commands.ResolveFlags(cmd, nil)
+	if err != nil {
+		t.Fatalf("ResolveFlags() returned error: %v", err)
+	}
+	if flags.ErrorFormat != "json" {
+		t.Errorf("expected ErrorFormat from env to be 'json', got %q", flags.ErrorFormat)
+	}
+
+	cmd = NewRootCommand()
+	if err := parseFlagsForTesting(cmd, []string{"--error-format", "text", "version"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	flags, err = commands.ResolveFlags(cmd, nil)
+	if err != nil {
+		t.Fatalf("ResolveFlags() returned error: %v", err)
+	}
+	if flags.ErrorFormat != "text" {
+		t.Errorf("expected ErrorFormat flag to win over env, got %q", flags.ErrorFormat)
+	}
+}
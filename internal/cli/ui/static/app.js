@@ -0,0 +1,151 @@
+// Stagecraft dashboard: read-only views over the /api endpoints served by
+// `stagecraft ui`. Values are always inserted as text, never as HTML.
+"use strict";
+
+const REFRESH_MS = 5000;
+
+let selectedEnv = "";
+let selectedRelease = "";
+
+async function getJSON(path) {
+  const res = await fetch(path, { headers: { Accept: "application/json" } });
+  if (!res.ok) {
+    throw new Error(`${path}: ${res.status} ${(await res.text()).trim()}`);
+  }
+  return res.json();
+}
+
+function el(tag, text, className) {
+  const node = document.createElement(tag);
+  if (text !== undefined && text !== null) node.textContent = String(text);
+  if (className) node.className = className;
+  return node;
+}
+
+function row(cells, onClick, selected) {
+  const tr = document.createElement("tr");
+  for (const cell of cells) {
+    tr.appendChild(cell instanceof Node ? cell : el("td", cell));
+  }
+  if (onClick) tr.addEventListener("click", onClick);
+  if (selected) tr.classList.add("selected");
+  return tr;
+}
+
+function statusCell(status, prefix) {
+  return el("td", status || "-", `${prefix || "status"}-${status}`);
+}
+
+function short(sha) {
+  return sha ? sha.slice(0, 7) : "-";
+}
+
+function replace(tbody, rows, empty, columns) {
+  tbody.replaceChildren(...rows);
+  if (rows.length === 0) {
+    const td = el("td", empty, "muted");
+    td.colSpan = columns;
+    tbody.appendChild(row([td]));
+  }
+}
+
+async function loadEnvironments() {
+  const data = await getJSON("/api/environments");
+  const tbody = document.querySelector("#environments tbody");
+  const rows = data.environments.map((env) => {
+    const current = env.current;
+    return row(
+      [
+        env.name,
+        current ? current.id : "-",
+        current ? current.version : "-",
+        statusCell(current ? current.status : "", "status"),
+        statusCell(env.drift.status, "drift"),
+      ],
+      () => selectEnv(env.name),
+      env.name === selectedEnv,
+    );
+  });
+  replace(tbody, rows, "No environments found", 5);
+}
+
+async function loadReleases() {
+  document.getElementById("releases-env").textContent = selectedEnv ? `(${selectedEnv})` : "(all environments)";
+  const query = selectedEnv ? `?env=${encodeURIComponent(selectedEnv)}` : "";
+  const data = await getJSON(`/api/releases${query}`);
+  const tbody = document.querySelector("#releases tbody");
+  const rows = data.releases.map((r) =>
+    row(
+      [r.id, r.version, el("td", short(r.commit_sha)), r.timestamp, statusCell(r.status)],
+      () => selectRelease(r.id),
+      r.id === selectedRelease,
+    ),
+  );
+  replace(tbody, rows, "No releases found", 5);
+}
+
+async function loadRelease() {
+  const section = document.getElementById("release");
+  if (!selectedRelease) {
+    section.hidden = true;
+    return;
+  }
+  const r = await getJSON(`/api/releases/${encodeURIComponent(selectedRelease)}`);
+  section.hidden = false;
+  document.getElementById("release-id").textContent = `${r.id} (${r.environment}, ${r.version})`;
+
+  const phases = r.phases.map((p) => {
+    const hosts = (p.hosts || []).map((h) => `${h.host}: ${h.status}${h.message ? ` (${h.message})` : ""}`);
+    return row([p.phase, statusCell(p.status), hosts.join("\n") || "-"]);
+  });
+  replace(document.querySelector("#phases tbody"), phases, "No phases recorded", 3);
+
+  const timeline = document.getElementById("timeline");
+  timeline.replaceChildren(
+    ...(r.events || []).map((e) => {
+      const parts = [e.time, e.type, e.phase, e.status, e.actor, e.message].filter(Boolean);
+      return el("li", parts.join("  "));
+    }),
+  );
+  if (timeline.children.length === 0) timeline.appendChild(el("li", "No events recorded", "muted"));
+}
+
+async function loadAudit() {
+  document.getElementById("audit-env").textContent = selectedEnv ? `(${selectedEnv})` : "";
+  const query = new URLSearchParams({ limit: "100" });
+  if (selectedEnv) query.set("env", selectedEnv);
+  const data = await getJSON(`/api/audit?${query}`);
+  const list = document.getElementById("audit");
+  list.replaceChildren(
+    ...data.entries.map((e) => {
+      const parts = [e.timestamp, e.actor, e.command, e.environment, e.release_id, e.outcome, e.error].filter(Boolean);
+      return el("li", parts.join("  "), e.outcome === "failure" ? "error" : "");
+    }),
+  );
+  if (list.children.length === 0) list.appendChild(el("li", "No audit entries found", "muted"));
+}
+
+function selectEnv(name) {
+  selectedEnv = selectedEnv === name ? "" : name;
+  selectedRelease = "";
+  refresh();
+}
+
+function selectRelease(id) {
+  selectedRelease = id;
+  refresh();
+}
+
+async function refresh() {
+  const error = document.getElementById("error");
+  try {
+    await Promise.all([loadEnvironments(), loadReleases(), loadRelease(), loadAudit()]);
+    error.textContent = "";
+    document.getElementById("updated").textContent = `Updated ${new Date().toLocaleTimeString()}`;
+  } catch (err) {
+    error.textContent = err.message;
+  }
+}
+
+refresh();
+setInterval(refresh, REFRESH_MS);
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package ui serves the local, read-only web dashboard for `stagecraft ui`.
+package ui
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Feature: CLI_UI
+// Spec: spec/commands/ui.md
+
+//go:embed static
+var staticFS embed.FS
+
+// ErrNonLoopbackAddr is returned when the listen address is not local.
+var ErrNonLoopbackAddr = errors.New("ui address must be a loopback address")
+
+// ValidateAddr checks that addr is host:port with a loopback host, so the
+// dashboard is never exposed beyond the local machine.
+func ValidateAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid ui address %q: %w", addr, err)
+	}
+	if !loopbackHost(host) {
+		return fmt.Errorf("%w: %q", ErrNonLoopbackAddr, addr)
+	}
+	return nil
+}
+
+// Handler serves api under /api/ and the embedded assets everywhere else.
+// Requests whose Host header is not a loopback name are rejected, so other
+// sites cannot reach the dashboard through DNS rebinding.
+func Handler(api http.Handler) http.Handler {
+	assets, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		panic(err) // the embedded directory always exists
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", api)
+	mux.Handle("/", http.FileServerFS(assets))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if !loopbackHost(host) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "the dashboard is read-only", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'")
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// Serve starts an HTTP server for h on addr and returns the bound address.
+// The server shuts down when ctx is cancelled.
+func Serve(ctx context.Context, addr string, h http.Handler) (string, error) {
+	if err := ValidateAddr(addr); err != nil {
+		return "", err
+	}
+
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("listening on %s: %w", addr, err)
+	}
+
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	return ln.Addr().String(), nil
+}
+
+func loopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package ui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Feature: CLI_UI
+// Spec: spec/commands/ui.md
+
+func TestValidateAddr(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:7430", "localhost:0", "[::1]:7430"} {
+		if err := ValidateAddr(addr); err != nil {
+			t.Errorf("ValidateAddr(%q) = %v, want nil", addr, err)
+		}
+	}
+	for _, addr := range []string{":7430", "0.0.0.0:7430", "192.168.1.10:7430"} {
+		if err := ValidateAddr(addr); !errors.Is(err, ErrNonLoopbackAddr) {
+			t.Errorf("ValidateAddr(%q) = %v, want ErrNonLoopbackAddr", addr, err)
+		}
+	}
+	if err := ValidateAddr("127.0.0.1"); err == nil {
+		t.Error("ValidateAddr without port = nil, want error")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "api "+r.URL.Path)
+	})
+	h := Handler(api)
+
+	tests := []struct {
+		name       string
+		method     string
+		host       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "index", path: "/", wantStatus: http.StatusOK, wantBody: "<title>Stagecraft</title>"},
+		{name: "script", path: "/app.js", wantStatus: http.StatusOK, wantBody: "/api/environments"},
+		{name: "api", path: "/api/environments", wantStatus: http.StatusOK, wantBody: "api /api/environments"},
+		{name: "localhost host", host: "localhost:7430", path: "/api/x", wantStatus: http.StatusOK, wantBody: "api /api/x"},
+		{name: "foreign host", host: "attacker.example:7430", path: "/api/x", wantStatus: http.StatusForbidden},
+		{name: "post", method: http.MethodPost, path: "/api/x", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, nil)
+			req.Host = "127.0.0.1:7430"
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body does not contain %q:\n%s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := Serve(ctx, "0.0.0.0:0", Handler(http.NotFoundHandler())); !errors.Is(err, ErrNonLoopbackAddr) {
+		t.Fatalf("Serve on 0.0.0.0 = %v, want ErrNonLoopbackAddr", err)
+	}
+
+	addr, err := Serve(ctx, "127.0.0.1:0", Handler(http.NotFoundHandler()))
+	if err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	resp, err := http.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatalf("GET /: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
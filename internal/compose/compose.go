@@ -361,12 +361,20 @@ func (c *ComposeFile) Mutate(fn func(data map[string]any) error) error {
 }
 
 // GetServiceRoles returns the role mapping for services from config.
+// Only backend services present in the compose file are mapped, to
+// config.ServiceRoleAPI or config.ServiceRoleWorker.
 func (c *ComposeFile) GetServiceRoles(cfg *config.Config) map[string]string {
 	roles := make(map[string]string)
+	if cfg == nil {
+		return roles
+	}
 
-	// Services config is not yet in the Config struct, so we return empty for now
-	// This will be populated when services config is added to Config
-	_ = cfg
+	services, _ := c.data["services"].(map[string]any)
+	for _, svc := range cfg.Backend.ServiceList() {
+		if _, ok := services[svc.Name]; ok {
+			roles[svc.Name] = svc.ServiceRole()
+		}
+	}
 
 	return roles
 }
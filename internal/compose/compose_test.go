@@ -390,9 +390,40 @@ func TestComposeFile_GetServiceRoles(t *testing.T) {
 		t.Error("GetServiceRoles() returned nil, want empty map")
 	}
 
-	// For v1, returns empty map (service roles not yet in config)
+	// Without backend services there is nothing to map
 	if len(roles) != 0 {
-		t.Errorf("GetServiceRoles() returned %d roles, want 0 for v1", len(roles))
+		t.Errorf("GetServiceRoles() returned %d roles, want 0", len(roles))
+	}
+}
+
+func TestComposeFile_GetServiceRoles_BackendServices(t *testing.T) {
+	compose := &ComposeFile{
+		data: map[string]any{
+			"services": map[string]any{
+				"api":    map[string]any{},
+				"worker": map[string]any{},
+				"db":     map[string]any{"image": "postgres:16"},
+			},
+		},
+	}
+
+	cfg := &config.Config{
+		Backend: &config.BackendConfig{Services: map[string]config.BackendServiceConfig{
+			"api":     {Provider: "generic"},
+			"worker":  {Provider: "generic", Role: config.ServiceRoleWorker},
+			"missing": {Provider: "generic"},
+		}},
+	}
+
+	roles := compose.GetServiceRoles(cfg)
+	want := map[string]string{"api": config.ServiceRoleAPI, "worker": config.ServiceRoleWorker}
+	if len(roles) != len(want) {
+		t.Fatalf("GetServiceRoles() = %v, want %v", roles, want)
+	}
+	for name, role := range want {
+		if roles[name] != role {
+			t.Errorf("role[%s] = %q, want %q", name, roles[name], role)
+		}
 	}
 }
 
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package compose
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Feature: CORE_COMPOSE
+// Spec: spec/core/compose.md
+
+// HashHeaderPrefix starts the first line of every compose file Stagecraft
+// writes; the rest of the line is the sha256 of the content below it.
+const HashHeaderPrefix = "# stagecraft: config-hash="
+
+// ContentHash returns the sha256 hex digest of a compose file's content,
+// excluding the hash header line when present. Files written with and
+// without the header hash the same.
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(stripHashHeader(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// WithHashHeader prefixes body with its hash header and returns the file
+// bytes and the hash.
+func WithHashHeader(body []byte) ([]byte, string) {
+	hash := ContentHash(body)
+	out := make([]byte, 0, len(HashHeaderPrefix)+len(hash)+1+len(body))
+	out = append(out, HashHeaderPrefix...)
+	out = append(out, hash...)
+	out = append(out, '\n')
+	out = append(out, body...)
+	return out, hash
+}
+
+// ReadHash returns the hash recorded in the header of the compose file at
+// path. ok is false when the file has no header.
+func ReadHash(path string) (hash string, ok bool, err error) {
+	// #nosec G304 -- path is a Stagecraft-generated compose file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, err
+	}
+	hash, ok = parseHashHeader(data)
+	return hash, ok, nil
+}
+
+// WriteFile writes body with its hash header to path, unless the file
+// already holds exactly those bytes. Skipping the write keeps the file's
+// mtime, so docker compose and file watchers see no change.
+//
+// readFn and writeFn default to os.ReadFile and os.WriteFile when nil.
+func WriteFile(
+	path string,
+	body []byte,
+	perm os.FileMode,
+	readFn func(string) ([]byte, error),
+	writeFn func(string, []byte, os.FileMode) error,
+) (hash string, written bool, err error) {
+	if readFn == nil {
+		readFn = os.ReadFile
+	}
+	if writeFn == nil {
+		writeFn = os.WriteFile
+	}
+
+	data, hash := WithHashHeader(body)
+
+	existing, err := readFn(path)
+	switch {
+	case err == nil && bytes.Equal(existing, data):
+		return hash, false, nil
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return "", false, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	if err := writeFn(path, data, perm); err != nil {
+		return "", false, err
+	}
+	return hash, true, nil
+}
+
+// ServiceHashes returns, per service of a compose file, the sha256 hex
+// digest of the service's definition without its image. Image tags change
+// with every release even when the image does not, so callers compare
+// images separately. Keys are serialized sorted, so equal definitions hash
+// the same regardless of key order.
+func ServiceHashes(data []byte) (map[string]string, error) {
+	var parsed map[string]any
+	if err := yaml.Unmarshal(stripHashHeader(data), &parsed); err != nil {
+		return nil, fmt.Errorf("parsing compose file: %w", err)
+	}
+
+	services, _ := parsed["services"].(map[string]any)
+	hashes := make(map[string]string, len(services))
+	for name, svc := range services {
+		fragment, _ := svc.(map[string]any)
+		withoutImage := make(map[string]any, len(fragment))
+		for k, v := range fragment {
+			if k != "image" {
+				withoutImage[k] = v
+			}
+		}
+
+		body, err := yaml.Marshal(withoutImage)
+		if err != nil {
+			return nil, fmt.Errorf("hashing service %q: %w", name, err)
+		}
+		sum := sha256.Sum256(body)
+		hashes[name] = hex.EncodeToString(sum[:])
+	}
+	return hashes, nil
+}
+
+// parseHashHeader returns the hash from data's first line.
+func parseHashHeader(data []byte) (string, bool) {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	hash, ok := strings.CutPrefix(string(line), HashHeaderPrefix)
+	if !ok || hash == "" {
+		return "", false
+	}
+	return hash, true
+}
+
+// stripHashHeader returns data without its hash header line.
+func stripHashHeader(data []byte) []byte {
+	if !bytes.HasPrefix(data, []byte(HashHeaderPrefix)) {
+		return data
+	}
+	if _, rest, ok := bytes.Cut(data, []byte("\n")); ok {
+		return rest
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Feature: CORE_COMPOSE
+// Spec: spec/core/compose.md
+
+func TestWithHashHeader(t *testing.T) {
+	body := []byte("services:\n  api:\n    image: app\n")
+
+	data, hash := WithHashHeader(body)
+
+	if !strings.HasPrefix(string(data), HashHeaderPrefix+hash+"\n") {
+		t.Fatalf("expected header line, got:\n%s", data)
+	}
+	if got := ContentHash(data); got != hash {
+		t.Errorf("ContentHash(with header) = %q, want %q", got, hash)
+	}
+	if got := ContentHash(body); got != hash {
+		t.Errorf("ContentHash(body) = %q, want %q", got, hash)
+	}
+}
+
+func TestWriteFile_SkipsUnchangedContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "compose.yaml")
+	body := []byte("services: {}\n")
+
+	hash, written, err := WriteFile(path, body, 0o600, nil, nil)
+	if err != nil || !written {
+		t.Fatalf("first WriteFile() = written %v, err %v; want written", written, err)
+	}
+
+	stored, ok, err := ReadHash(path)
+	if err != nil || !ok || stored != hash {
+		t.Fatalf("ReadHash() = %q, %v, %v; want %q", stored, ok, err, hash)
+	}
+
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	again, written, err := WriteFile(path, body, 0o600, nil, nil)
+	if err != nil || written || again != hash {
+		t.Fatalf("second WriteFile() = %q, written %v, err %v; want skipped with same hash", again, written, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(old) {
+		t.Errorf("mtime changed to %v, want %v", info.ModTime(), old)
+	}
+
+	changed, written, err := WriteFile(path, []byte("services:\n  api: {}\n"), 0o600, nil, nil)
+	if err != nil || !written || changed == hash {
+		t.Fatalf("changed WriteFile() = %q, written %v, err %v; want rewritten with new hash", changed, written, err)
+	}
+}
+
+func TestReadHash_NoHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+	if err := os.WriteFile(path, []byte("services: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := ReadHash(path); err != nil || ok {
+		t.Errorf("ReadHash() ok = %v, err = %v; want no header", ok, err)
+	}
+}
+
+func TestServiceHashes(t *testing.T) {
+	v1, _ := WithHashHeader([]byte(`services:
+  api:
+    image: app:v1
+    environment:
+      A: "1"
+      B: "2"
+  db:
+    image: postgres:16
+`))
+	v2 := []byte(`services:
+  api:
+    environment:
+      B: "2"
+      A: "1"
+    image: app:v2
+  db:
+    image: postgres:16
+    command: ["postgres", "-c", "max_connections=200"]
+`)
+
+	before, err := ServiceHashes(v1)
+	if err != nil {
+		t.Fatalf("ServiceHashes() error = %v", err)
+	}
+	after, err := ServiceHashes(v2)
+	if err != nil {
+		t.Fatalf("ServiceHashes() error = %v", err)
+	}
+
+	if before["api"] == "" || before["api"] != after["api"] {
+		t.Errorf("api hash changed with image tag or key order: %q -> %q", before["api"], after["api"])
+	}
+	if before["db"] == after["db"] {
+		t.Error("db hash should change with its command")
+	}
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package containerruntime selects the container CLI Stagecraft drives,
+// docker or podman, and inspects how it is set up.
+package containerruntime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CORE_CONTAINER_RUNTIME
+// Spec: spec/core/container-runtime.md
+
+// Runtime is a container CLI.
+type Runtime string
+
+// Supported runtimes.
+const (
+	Docker Runtime = config.ContainerRuntimeDocker
+	Podman Runtime = config.ContainerRuntimePodman
+)
+
+// DefaultUnprivilegedPortStart is the Linux default for the lowest port an
+// unprivileged process may bind.
+const DefaultUnprivilegedPortStart = 1024
+
+// lookPath and readFile are package-level for tests.
+var (
+	lookPath = exec.LookPath
+	readFile = os.ReadFile
+)
+
+// Resolve returns the configured runtime, or the detected one when
+// configured is empty.
+func Resolve(configured string) Runtime {
+	if configured != "" {
+		return Runtime(configured)
+	}
+	return Detect()
+}
+
+// ForConfig resolves the runtime for cfg, which may be nil.
+func ForConfig(cfg *config.Config) Runtime {
+	if cfg == nil {
+		return Detect()
+	}
+	return Resolve(cfg.ContainerRuntime)
+}
+
+// Detect returns docker when it is on PATH, podman when only podman is,
+// and docker otherwise so error messages name the usual tool.
+func Detect() Runtime {
+	if _, err := lookPath(string(Docker)); err == nil {
+		return Docker
+	}
+	if _, err := lookPath(string(Podman)); err == nil {
+		return Podman
+	}
+	return Docker
+}
+
+// Binary returns the CLI executable name.
+func (r Runtime) Binary() string {
+	if r == "" {
+		return string(Docker)
+	}
+	return string(r)
+}
+
+// Command returns `<binary> args...`.
+func (r Runtime) Command(args ...string) executil.Command {
+	return executil.NewCommand(r.Binary(), args...)
+}
+
+// ComposeArgs returns the argument list for `<binary> compose args...`.
+// Podman 4.7+ provides `podman compose`, which runs the installed compose
+// provider against the podman socket.
+func (r Runtime) ComposeArgs(args ...string) []string {
+	return append([]string{"compose"}, args...)
+}
+
+// ComposeCommand returns `<binary> compose args...`.
+func (r Runtime) ComposeCommand(args ...string) executil.Command {
+	return r.Command(r.ComposeArgs(args...)...)
+}
+
+// ManifestCreateCommand returns the command that creates (or replaces) the
+// local manifest list named list from images, which must already be
+// pushed. Both runtimes accept the same arguments.
+func (r Runtime) ManifestCreateCommand(list string, images ...string) executil.Command {
+	return r.Command(append([]string{"manifest", "create", "--amend", list}, images...)...)
+}
+
+// ManifestPushCommand returns the command that publishes the manifest list
+// to the registry under its own name and removes the local copy.
+func (r Runtime) ManifestPushCommand(list string) executil.Command {
+	if r == Podman {
+		return r.Command("manifest", "push", "--all", "--rm", list, "docker://"+list)
+	}
+	return r.Command("manifest", "push", "--purge", list)
+}
+
+// Rootless reports whether the runtime runs without root: the Docker
+// daemon in rootless mode, or podman for the current user.
+func (r Runtime) Rootless(ctx context.Context, runner executil.Runner) (bool, error) {
+	var cmd executil.Command
+	if r == Podman {
+		cmd = r.Command("info", "--format", "{{.Host.Security.Rootless}}")
+	} else {
+		cmd = r.Command("info", "--format", "{{json .SecurityOptions}}")
+	}
+
+	result, err := runner.Run(ctx, cmd)
+	if err != nil {
+		return false, commandError(cmd, result, err)
+	}
+
+	out := strings.TrimSpace(string(result.Stdout))
+	if r == Podman {
+		return out == "true", nil
+	}
+	return strings.Contains(out, "name=rootless"), nil
+}
+
+// UnprivilegedPortStart returns the lowest port a rootless runtime can
+// publish, from net.ipv4.ip_unprivileged_port_start. It returns
+// DefaultUnprivilegedPortStart when the sysctl cannot be read.
+func UnprivilegedPortStart() int {
+	data, err := readFile("/proc/sys/net/ipv4/ip_unprivileged_port_start")
+	if err != nil {
+		return DefaultUnprivilegedPortStart
+	}
+	port, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return DefaultUnprivilegedPortStart
+	}
+	return port
+}
+
+// Version returns the first line of `<binary> --version`.
+func (r Runtime) Version(ctx context.Context, runner executil.Runner) (string, error) {
+	return r.firstLine(ctx, runner, r.Command("--version"))
+}
+
+// ComposeVersion returns the first line of `<binary> compose version`,
+// which fails when no compose provider is installed.
+func (r Runtime) ComposeVersion(ctx context.Context, runner executil.Runner) (string, error) {
+	return r.firstLine(ctx, runner, r.ComposeCommand("version"))
+}
+
+func (r Runtime) firstLine(ctx context.Context, runner executil.Runner, cmd executil.Command) (string, error) {
+	result, err := runner.Run(ctx, cmd)
+	if err != nil {
+		return "", commandError(cmd, result, err)
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(result.Stdout)), "\n")
+	return line, nil
+}
+
+// commandError prefers the command's stderr over the exec error, which
+// only carries the exit code.
+func commandError(cmd executil.Command, result *executil.Result, err error) error {
+	name := strings.Join(append([]string{cmd.Name}, cmd.Args...), " ")
+	if result != nil && len(result.Stderr) > 0 {
+		return fmt.Errorf("%s: %s", name, strings.TrimSpace(string(result.Stderr)))
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}
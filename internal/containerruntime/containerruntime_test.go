@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package containerruntime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CORE_CONTAINER_RUNTIME
+// Spec: spec/core/container-runtime.md
+
+type stubRunner struct {
+	stdout string
+	got    executil.Command
+}
+
+func (r *stubRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	r.got = cmd
+	return &executil.Result{Stdout: []byte(r.stdout)}, nil
+}
+
+func (r *stubRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return nil
+}
+
+func stubPath(t *testing.T, installed ...string) {
+	t.Helper()
+	orig := lookPath
+	lookPath = func(name string) (string, error) {
+		for _, n := range installed {
+			if n == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+	t.Cleanup(func() { lookPath = orig })
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		installed []string
+		want      Runtime
+	}{
+		{[]string{"docker", "podman"}, Docker},
+		{[]string{"podman"}, Podman},
+		{nil, Docker},
+	}
+	for _, tt := range tests {
+		stubPath(t, tt.installed...)
+		if got := Detect(); got != tt.want {
+			t.Errorf("Detect() with %v = %q, want %q", tt.installed, got, tt.want)
+		}
+	}
+}
+
+func TestResolve_ConfiguredWins(t *testing.T) {
+	stubPath(t, "docker")
+	if got := Resolve("podman"); got != Podman {
+		t.Errorf("Resolve(podman) = %q, want podman", got)
+	}
+}
+
+func TestComposeCommand(t *testing.T) {
+	cmd := Podman.ComposeCommand("-f", "compose.yaml", "up", "-d")
+	if cmd.Name != "podman" {
+		t.Errorf("Name = %q, want podman", cmd.Name)
+	}
+	want := []string{"compose", "-f", "compose.yaml", "up", "-d"}
+	if len(cmd.Args) != len(want) {
+		t.Fatalf("Args = %v, want %v", cmd.Args, want)
+	}
+	for i := range want {
+		if cmd.Args[i] != want[i] {
+			t.Fatalf("Args = %v, want %v", cmd.Args, want)
+		}
+	}
+
+	if got := Runtime("").Binary(); got != "docker" {
+		t.Errorf("empty runtime Binary() = %q, want docker", got)
+	}
+}
+
+func TestManifestCommands(t *testing.T) {
+	tests := []struct {
+		cmd  executil.Command
+		want string
+	}{
+		{Docker.ManifestCreateCommand("app:v1", "app:v1-linux-amd64", "app:v1-linux-arm64"),
+			"docker manifest create --amend app:v1 app:v1-linux-amd64 app:v1-linux-arm64"},
+		{Docker.ManifestPushCommand("app:v1"), "docker manifest push --purge app:v1"},
+		{Podman.ManifestPushCommand("app:v1"), "podman manifest push --all --rm app:v1 docker://app:v1"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(append([]string{tt.cmd.Name}, tt.cmd.Args...), " "); got != tt.want {
+			t.Errorf("command = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestRootless(t *testing.T) {
+	tests := []struct {
+		runtime Runtime
+		stdout  string
+		want    bool
+	}{
+		{Docker, `["name=seccomp,profile=builtin","name=rootless","name=cgroupns"]`, true},
+		{Docker, `["name=seccomp,profile=builtin"]`, false},
+		{Podman, "true\n", true},
+		{Podman, "false\n", false},
+	}
+	for _, tt := range tests {
+		runner := &stubRunner{stdout: tt.stdout}
+		got, err := tt.runtime.Rootless(context.Background(), runner)
+		if err != nil {
+			t.Fatalf("Rootless() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("%s Rootless() with %q = %v, want %v", tt.runtime, tt.stdout, got, tt.want)
+		}
+		if runner.got.Name != string(tt.runtime) {
+			t.Errorf("ran %q, want %q", runner.got.Name, tt.runtime)
+		}
+	}
+}
+
+func TestUnprivilegedPortStart(t *testing.T) {
+	orig := readFile
+	t.Cleanup(func() { readFile = orig })
+
+	readFile = func(string) ([]byte, error) { return []byte("80\n"), nil }
+	if got := UnprivilegedPortStart(); got != 80 {
+		t.Errorf("UnprivilegedPortStart() = %d, want 80", got)
+	}
+
+	readFile = func(string) ([]byte, error) { return nil, os.ErrNotExist }
+	if got := UnprivilegedPortStart(); got != DefaultUnprivilegedPortStart {
+		t.Errorf("UnprivilegedPortStart() = %d, want %d", got, DefaultUnprivilegedPortStart)
+	}
+}
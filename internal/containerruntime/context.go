@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package containerruntime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CORE_CONTAINER_RUNTIME
+// Spec: spec/core/container-runtime.md
+
+// ContextKind classifies where a Docker context's daemon runs.
+type ContextKind string
+
+// Context kinds.
+const (
+	// ContextLocal is a daemon on this machine's own filesystem.
+	ContextLocal ContextKind = "local"
+	// ContextColima is colima's Linux VM.
+	ContextColima ContextKind = "colima"
+	// ContextDesktop is Docker Desktop's VM.
+	ContextDesktop ContextKind = "desktop"
+	// ContextRemote is a daemon reached over tcp:// or ssh://.
+	ContextRemote ContextKind = "remote"
+)
+
+// DockerContext is the Docker CLI context commands run against.
+type DockerContext struct {
+	Name string
+	// Host is the daemon endpoint, e.g. unix:///var/run/docker.sock.
+	Host string
+}
+
+// getenv is package-level for tests.
+var getenv = os.Getenv
+
+// ActiveDockerContext returns the context docker commands use: pinned when
+// set, otherwise DOCKER_HOST or the CLI's current context.
+func ActiveDockerContext(ctx context.Context, runner executil.Runner, pinned string) (DockerContext, error) {
+	name := pinned
+	if name == "" {
+		if host := getenv("DOCKER_HOST"); host != "" {
+			return DockerContext{Name: "DOCKER_HOST", Host: host}, nil
+		}
+
+		cmd := executil.NewCommand("docker", "context", "show")
+		result, err := runner.Run(ctx, cmd)
+		if err != nil {
+			return DockerContext{}, commandError(cmd, result, err)
+		}
+		name = strings.TrimSpace(string(result.Stdout))
+	}
+
+	cmd := executil.NewCommand("docker", "context", "inspect", name, "--format", "{{.Endpoints.docker.Host}}")
+	result, err := runner.Run(ctx, cmd)
+	if err != nil {
+		return DockerContext{}, commandError(cmd, result, err)
+	}
+	return DockerContext{Name: name, Host: strings.TrimSpace(string(result.Stdout))}, nil
+}
+
+// Kind classifies the context from its endpoint and name.
+func (c DockerContext) Kind() ContextKind {
+	switch {
+	case strings.HasPrefix(c.Host, "tcp://"), strings.HasPrefix(c.Host, "ssh://"):
+		return ContextRemote
+	case c.Name == "colima" || strings.HasPrefix(c.Name, "colima-") || strings.Contains(c.Host, "/.colima/"):
+		return ContextColima
+	case strings.HasPrefix(c.Name, "desktop-") || strings.Contains(c.Host, "/.docker/desktop/") || strings.Contains(c.Host, "/.docker/run/"):
+		return ContextDesktop
+	default:
+		return ContextLocal
+	}
+}
+
+// DevWarnings explains how the context would break `stagecraft dev` for a
+// project at projectDir: VMs only share the home directory by default, and
+// remote daemons resolve bind mounts and publish ports on their own host.
+func (c DockerContext) DevWarnings(projectDir, home string) []string {
+	switch c.Kind() {
+	case ContextRemote:
+		return []string{fmt.Sprintf(
+			"docker context %q runs on %s: bind mounts (source, certificates, Traefik config) resolve on that host and ports are published there, not on this machine",
+			c.Name, c.Host)}
+	case ContextColima, ContextDesktop:
+		if home == "" || withinDir(projectDir, home) {
+			return nil
+		}
+		where := "colima's mounts (colima start --mount)"
+		if c.Kind() == ContextDesktop {
+			where = "Docker Desktop's file sharing settings"
+		}
+		return []string{fmt.Sprintf(
+			"docker context %q only shares %s by default; %s is outside it, so bind mounts will be empty unless it is added to %s",
+			c.Name, home, projectDir, where)}
+	default:
+		return nil
+	}
+}
+
+// withinDir reports whether path is dir or below it.
+func withinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package containerruntime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CORE_CONTAINER_RUNTIME
+// Spec: spec/core/container-runtime.md
+
+type mapRunner map[string]string
+
+func (r mapRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	out, ok := r[strings.Join(append([]string{cmd.Name}, cmd.Args...), " ")]
+	if !ok {
+		return &executil.Result{ExitCode: 1, Stderr: []byte("context not found")}, errors.New("exit status 1")
+	}
+	return &executil.Result{Stdout: []byte(out)}, nil
+}
+
+func (r mapRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return nil
+}
+
+func stubEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	orig := getenv
+	getenv = func(key string) string { return env[key] }
+	t.Cleanup(func() { getenv = orig })
+}
+
+func TestActiveDockerContext(t *testing.T) {
+	runner := mapRunner{
+		"docker context show": "desktop-linux\n",
+		"docker context inspect desktop-linux --format {{.Endpoints.docker.Host}}": "unix:///home/dev/.docker/desktop/docker.sock\n",
+		"docker context inspect colima --format {{.Endpoints.docker.Host}}":        "unix:///home/dev/.colima/default/docker.sock\n",
+	}
+
+	stubEnv(t, nil)
+	got, err := ActiveDockerContext(context.Background(), runner, "")
+	if err != nil {
+		t.Fatalf("ActiveDockerContext() error = %v", err)
+	}
+	if got.Name != "desktop-linux" || got.Kind() != ContextDesktop {
+		t.Errorf("active context = %+v (%s), want desktop-linux (desktop)", got, got.Kind())
+	}
+
+	got, err = ActiveDockerContext(context.Background(), runner, "colima")
+	if err != nil {
+		t.Fatalf("ActiveDockerContext(pinned) error = %v", err)
+	}
+	if got.Name != "colima" || got.Kind() != ContextColima {
+		t.Errorf("pinned context = %+v (%s), want colima", got, got.Kind())
+	}
+
+	if _, err := ActiveDockerContext(context.Background(), runner, "missing"); err == nil || !strings.Contains(err.Error(), "context not found") {
+		t.Errorf("expected missing context error, got %v", err)
+	}
+
+	stubEnv(t, map[string]string{"DOCKER_HOST": "ssh://deploy@build-1"})
+	got, err = ActiveDockerContext(context.Background(), runner, "")
+	if err != nil {
+		t.Fatalf("ActiveDockerContext(DOCKER_HOST) error = %v", err)
+	}
+	if got.Kind() != ContextRemote {
+		t.Errorf("DOCKER_HOST context kind = %s, want remote", got.Kind())
+	}
+}
+
+func TestDockerContext_DevWarnings(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerCtx  DockerContext
+		projectDir string
+		want       string
+	}{
+		{"local", DockerContext{Name: "default", Host: "unix:///var/run/docker.sock"}, "/srv/app", ""},
+		{"colima inside home", DockerContext{Name: "colima", Host: "unix:///home/dev/.colima/default/docker.sock"}, "/home/dev/app", ""},
+		{"colima outside home", DockerContext{Name: "colima", Host: "unix:///home/dev/.colima/default/docker.sock"}, "/srv/app", "colima start --mount"},
+		{"desktop outside home", DockerContext{Name: "desktop-linux", Host: "unix:///home/dev/.docker/desktop/docker.sock"}, "/srv/app", "file sharing"},
+		{"remote", DockerContext{Name: "prod", Host: "tcp://10.0.0.5:2376"}, "/home/dev/app", "ports are published there"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := tt.dockerCtx.DevWarnings(tt.projectDir, "/home/dev")
+			if tt.want == "" {
+				if len(warnings) != 0 {
+					t.Errorf("expected no warnings, got %v", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.want) {
+				t.Errorf("warnings = %v, want one containing %q", warnings, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package audit records mutating Stagecraft commands in an append-only log.
+//
+// The audit log is separate from release state: state answers "what is
+// deployed", the audit log answers "who ran what, when, and with which flags".
+// Entries are never rewritten; the default backend appends one JSON object per
+// line to .stagecraft/audit.jsonl.
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Feature: CORE_AUDIT_LOG
+// Spec: spec/core/audit.md
+
+// DefaultPath is the default path for the audit log.
+const DefaultPath = ".stagecraft/audit.jsonl"
+
+// Outcome describes how an audited command finished.
+type Outcome string
+
+const (
+	// OutcomeSuccess marks a command that completed without error.
+	OutcomeSuccess Outcome = "success"
+	// OutcomeFailure marks a command that returned an error.
+	OutcomeFailure Outcome = "failure"
+)
+
+// Entry is a single audit record.
+type Entry struct {
+	// Timestamp is when the command finished, in UTC.
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// Actor identifies who ran the command (see ResolveActor).
+	Actor string `json:"actor" yaml:"actor"`
+
+	// Command is the command path without the binary name, e.g. "deploy".
+	Command string `json:"command" yaml:"command"`
+
+	// Environment is the target environment, if any.
+	Environment string `json:"environment,omitempty" yaml:"environment,omitempty"`
+
+	// Flags holds the flags explicitly set on the command line.
+	Flags map[string]string `json:"flags,omitempty" yaml:"flags,omitempty"`
+
+	// ReleaseID is the release created by the command, if any.
+	ReleaseID string `json:"release_id,omitempty" yaml:"release_id,omitempty"`
+
+	// Outcome is success or failure.
+	Outcome Outcome `json:"outcome" yaml:"outcome"`
+
+	// Error is the error message for failed commands.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Filter narrows the entries returned by List. Zero values match everything.
+type Filter struct {
+	Environment string
+	Command     string
+	// Limit keeps only the newest Limit entries when greater than zero.
+	Limit int
+}
+
+// Matches reports whether e satisfies the filter's field constraints.
+func (f Filter) Matches(e Entry) bool {
+	if f.Environment != "" && e.Environment != f.Environment {
+		return false
+	}
+	if f.Command != "" && e.Command != f.Command {
+		return false
+	}
+	return true
+}
+
+// Backend stores audit entries. Implementations must be append-only.
+type Backend interface {
+	// Append adds an entry to the log.
+	Append(ctx context.Context, e Entry) error
+	// List returns matching entries, oldest first.
+	List(ctx context.Context, f Filter) ([]Entry, error)
+}
+
+// FileLog is a Backend that appends JSON lines to a local file.
+// FileLog is safe for concurrent use within a single process.
+type FileLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ Backend = (*FileLog)(nil)
+
+// NewFileLog returns a file-backed audit log at path.
+func NewFileLog(path string) *FileLog {
+	return &FileLog{path: path}
+}
+
+// NewDefaultLog returns the file-backed audit log at DefaultPath, or at
+// STAGECRAFT_AUDIT_FILE when that environment variable is set.
+func NewDefaultLog() *FileLog {
+	if envPath := os.Getenv("STAGECRAFT_AUDIT_FILE"); envPath != "" {
+		return NewFileLog(envPath)
+	}
+	return NewFileLog(DefaultPath)
+}
+
+// Path returns the file the log writes to.
+func (l *FileLog) Path() string {
+	return l.path
+}
+
+// Append writes e as a single JSON line. The file is opened in append mode so
+// existing entries are never rewritten.
+func (l *FileLog) Append(ctx context.Context, e Entry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshaling audit entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o750); err != nil {
+		return fmt.Errorf("creating audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("syncing audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing audit log: %w", err)
+	}
+
+	return nil
+}
+
+// List reads the log and returns matching entries, oldest first.
+// A missing log file yields no entries.
+func (l *FileLog) List(ctx context.Context, f Filter) ([]Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Entry{}, nil
+		}
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := scanner.Bytes()
+		if len(raw) == 0 {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, fmt.Errorf("parsing audit log %s line %d: %w", l.path, line, err)
+		}
+		if f.Matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+
+	if f.Limit > 0 && len(entries) > f.Limit {
+		entries = entries[len(entries)-f.Limit:]
+	}
+
+	return entries, nil
+}
+
+// ResolveActor identifies the person or system running Stagecraft.
+// STAGECRAFT_ACTOR takes precedence (useful in CI); otherwise the current OS
+// user name is used, falling back to "unknown".
+func ResolveActor() string {
+	if actor := os.Getenv("STAGECRAFT_ACTOR"); actor != "" {
+		return actor
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Feature: CORE_AUDIT_LOG
+// Spec: spec/core/audit.md
+
+func TestFileLog_AppendAndList(t *testing.T) {
+	ctx := context.Background()
+	log := NewFileLog(filepath.Join(t.TempDir(), ".stagecraft", "audit.jsonl"))
+
+	entries := []Entry{
+		{Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Actor: "alice", Command: "deploy", Environment: "staging", ReleaseID: "rel-1", Outcome: OutcomeSuccess},
+		{Timestamp: time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC), Actor: "bob", Command: "rollback", Environment: "prod", ReleaseID: "rel-2", Outcome: OutcomeFailure, Error: "boom"},
+		{Timestamp: time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC), Actor: "alice", Command: "deploy", Environment: "prod", ReleaseID: "rel-3", Outcome: OutcomeSuccess, Flags: map[string]string{"version": "v2"}},
+	}
+	for _, e := range entries {
+		if err := log.Append(ctx, e); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	all, err := log.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(all))
+	}
+	if all[2].Flags["version"] != "v2" {
+		t.Errorf("expected flags to round-trip, got %v", all[2].Flags)
+	}
+
+	prod, err := log.List(ctx, Filter{Environment: "prod"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(prod) != 2 || prod[0].ReleaseID != "rel-2" {
+		t.Errorf("unexpected prod entries: %+v", prod)
+	}
+
+	latest, err := log.List(ctx, Filter{Command: "deploy", Limit: 1})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(latest) != 1 || latest[0].ReleaseID != "rel-3" {
+		t.Errorf("expected newest deploy entry, got %+v", latest)
+	}
+}
+
+func TestFileLog_AppendOnly(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewFileLog(path)
+
+	if err := log.Append(ctx, Entry{Actor: "alice", Command: "deploy", Outcome: OutcomeSuccess}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+
+	if err := log.Append(ctx, Entry{Actor: "bob", Command: "rollback", Outcome: OutcomeSuccess}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	second, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+
+	if !strings.HasPrefix(string(second), string(first)) {
+		t.Fatalf("expected existing entries to be preserved:\nbefore: %s\nafter: %s", first, second)
+	}
+	if strings.Count(string(second), "\n") != 2 {
+		t.Errorf("expected one line per entry, got:\n%s", second)
+	}
+}
+
+func TestFileLog_ListMissingFile(t *testing.T) {
+	log := NewFileLog(filepath.Join(t.TempDir(), "missing.jsonl"))
+
+	entries, err := log.List(context.Background(), Filter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no entries, got %d", len(entries))
+	}
+}
+
+func TestFileLog_ListCorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := os.WriteFile(path, []byte("{not json}\n"), 0o600); err != nil {
+		t.Fatalf("writing audit log: %v", err)
+	}
+
+	_, err := NewFileLog(path).List(context.Background(), Filter{})
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("expected parse error with line number, got %v", err)
+	}
+}
+
+func TestResolveActor_EnvOverride(t *testing.T) {
+	t.Setenv("STAGECRAFT_ACTOR", "ci-bot")
+	if got := ResolveActor(); got != "ci-bot" {
+		t.Fatalf("ResolveActor() = %q, want %q", got, "ci-bot")
+	}
+}
+
+func TestNewDefaultLog_EnvOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.jsonl")
+	t.Setenv("STAGECRAFT_AUDIT_FILE", path)
+	if got := NewDefaultLog().Path(); got != path {
+		t.Fatalf("NewDefaultLog().Path() = %q, want %q", got, path)
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package env
+
+import "sort"
+
+// Feature: CLI_DIFF
+// Spec: spec/commands/diff.md
+
+// Setting is one resolved setting of an environment, keyed by a dotted
+// path such as "driver", "services.api.replicas", or "env.DATABASE_URL".
+type Setting struct {
+	Key    string
+	Value  string
+	Secret bool
+}
+
+// SettingChange is a setting both environments define with different
+// values.
+type SettingChange struct {
+	Key    string
+	Left   string
+	Right  string
+	Secret bool
+}
+
+// SettingsDiff is the difference between two environments' settings, each
+// list sorted by key.
+type SettingsDiff struct {
+	OnlyLeft  []Setting
+	OnlyRight []Setting
+	Changed   []SettingChange
+	Unchanged int
+}
+
+// CompareSettings diffs left against right. Values are compared in full,
+// so secrets that differ are reported even though callers display them
+// redacted; a setting is secret when either side marks it so.
+func CompareSettings(left, right []Setting) *SettingsDiff {
+	rightByKey := make(map[string]Setting, len(right))
+	for _, s := range right {
+		rightByKey[s.Key] = s
+	}
+
+	d := &SettingsDiff{}
+	seen := make(map[string]bool, len(left))
+	for _, l := range left {
+		seen[l.Key] = true
+		r, ok := rightByKey[l.Key]
+		switch {
+		case !ok:
+			d.OnlyLeft = append(d.OnlyLeft, l)
+		case l.Value != r.Value:
+			d.Changed = append(d.Changed, SettingChange{Key: l.Key, Left: l.Value, Right: r.Value, Secret: l.Secret || r.Secret})
+		default:
+			d.Unchanged++
+		}
+	}
+	for _, r := range right {
+		if !seen[r.Key] {
+			d.OnlyRight = append(d.OnlyRight, r)
+		}
+	}
+
+	sort.Slice(d.OnlyLeft, func(i, j int) bool { return d.OnlyLeft[i].Key < d.OnlyLeft[j].Key })
+	sort.Slice(d.OnlyRight, func(i, j int) bool { return d.OnlyRight[i].Key < d.OnlyRight[j].Key })
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].Key < d.Changed[j].Key })
+
+	return d
+}
+
+// HasChanges reports whether the environments differ in any setting.
+func (d *SettingsDiff) HasChanges() bool {
+	return len(d.OnlyLeft) > 0 || len(d.OnlyRight) > 0 || len(d.Changed) > 0
+}
+
+// RedactSetting returns the value to display for a setting, redacted like
+// Redact.
+func RedactSetting(value string, secret bool) string {
+	return redactValue(value, secret)
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package env
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Feature: CLI_DIFF
+// Spec: spec/commands/diff.md
+
+func TestCompareSettings(t *testing.T) {
+	left := []Setting{
+		{Key: "driver", Value: "local"},
+		{Key: "env.API_TOKEN", Value: "a", Secret: true},
+		{Key: "env.DEBUG", Value: "true"},
+		{Key: "services.api.replicas", Value: "1"},
+	}
+	right := []Setting{
+		{Key: "services.api.replicas", Value: "1"},
+		{Key: "env.API_TOKEN", Value: "b", Secret: true},
+		{Key: "driver", Value: "digitalocean"},
+		{Key: "hosts.app-1", Value: "role=app"},
+	}
+
+	d := CompareSettings(left, right)
+
+	wantChanged := []SettingChange{
+		{Key: "driver", Left: "local", Right: "digitalocean"},
+		{Key: "env.API_TOKEN", Left: "a", Right: "b", Secret: true},
+	}
+	if !reflect.DeepEqual(d.Changed, wantChanged) {
+		t.Errorf("Changed = %+v, want %+v", d.Changed, wantChanged)
+	}
+	if len(d.OnlyLeft) != 1 || d.OnlyLeft[0].Key != "env.DEBUG" {
+		t.Errorf("OnlyLeft = %+v", d.OnlyLeft)
+	}
+	if len(d.OnlyRight) != 1 || d.OnlyRight[0].Key != "hosts.app-1" {
+		t.Errorf("OnlyRight = %+v", d.OnlyRight)
+	}
+	if d.Unchanged != 1 || !d.HasChanges() {
+		t.Errorf("Unchanged = %d, HasChanges = %v", d.Unchanged, d.HasChanges())
+	}
+
+	if CompareSettings(left, left).HasChanges() {
+		t.Error("expected an environment to have no changes against itself")
+	}
+}
+
+func TestRedactSetting(t *testing.T) {
+	if got := RedactSetting("hunter2", true); got != RedactedValue {
+		t.Errorf("secret value shown as %q", got)
+	}
+	if got := RedactSetting("postgres://app:pw@db/app", false); got != "postgres://app:xxxxx@db/app" {
+		t.Errorf("URL password not masked: %q", got)
+	}
+}
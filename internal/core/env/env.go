@@ -23,6 +23,8 @@ import (
 	"regexp"
 	"strings"
 
+	"stagecraft/internal/envcrypt"
+	hostexec "stagecraft/internal/exec"
 	"stagecraft/pkg/config"
 )
 
@@ -68,8 +70,6 @@ func (r *Resolver) SetWorkDir(workDir string) {
 
 // Resolve resolves an environment context by name.
 func (r *Resolver) Resolve(ctx context.Context, name string) (*Context, error) {
-	_ = ctx // Reserved for future cancellation/timeout support
-
 	// 1. Look up environment in config
 	envCfg, ok := r.cfg.Environments[name]
 	if !ok {
@@ -87,7 +87,7 @@ func (r *Resolver) Resolve(ctx context.Context, name string) (*Context, error) {
 	}
 
 	// 3. Load and merge environment variables
-	variables, err := r.loadVariables(envFile)
+	variables, err := r.loadVariables(ctx, envFile)
 	if err != nil {
 		return nil, fmt.Errorf("loading environment variables: %w", err)
 	}
@@ -122,21 +122,21 @@ func (r *Resolver) ResolveFromFlags(ctx context.Context, envFlag string) (*Conte
 // Precedence (lowest to highest):
 //  1. Env file variables
 //  2. System environment variables (highest precedence)
-func (r *Resolver) loadVariables(envFilePath string) (map[string]string, error) {
+func (r *Resolver) loadVariables(ctx context.Context, envFilePath string) (map[string]string, error) {
 	variables := make(map[string]string)
 
 	// 1. Load from env file if it exists (lowest precedence)
 	if envFilePath != "" {
 		if _, err := os.Stat(envFilePath); err == nil {
-			// File exists, read and parse it
-			//nolint:gosec // G304: envFilePath comes from trusted stagecraft.yml config
-			data, err := os.ReadFile(envFilePath)
+			// File exists, read and parse it (decrypting age/SOPS files)
+			data, err := envcrypt.ReadFile(ctx, envFilePath)
 			if err != nil {
 				return nil, fmt.Errorf("reading env file %q: %w", envFilePath, err)
 			}
 
 			// Parse dotenv format
 			parseEnvFileInto(variables, data)
+			hostexec.Secrets.AddVars(variables, IsSecretKey)
 		}
 		// If file doesn't exist, we continue (it's optional)
 	}
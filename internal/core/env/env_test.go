@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"stagecraft/internal/envcrypt"
 	"stagecraft/pkg/config"
 )
 
@@ -236,6 +237,32 @@ func TestResolver_Resolve_MissingEnvFile(t *testing.T) {
 	}
 }
 
+func TestResolver_Resolve_EncryptedEnvFileWithoutKey(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env.dev")
+	if err := os.WriteFile(envFile, []byte("-----BEGIN AGE ENCRYPTED FILE-----\nYWdl\n-----END AGE ENCRYPTED FILE-----\n"), 0o600); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+	t.Setenv(envcrypt.KeyFileEnv, "")
+	t.Setenv("SOPS_AGE_KEY_FILE", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", tmpDir)
+
+	cfg := &config.Config{
+		Project: config.ProjectConfig{Name: "test"},
+		Environments: map[string]config.EnvironmentConfig{
+			"dev": {Driver: "local", EnvFile: ".env.dev"},
+		},
+	}
+
+	resolver := NewResolver(cfg)
+	resolver.SetWorkDir(tmpDir)
+	_, err := resolver.Resolve(context.Background(), "dev")
+	if !errors.Is(err, envcrypt.ErrNoKey) {
+		t.Fatalf("expected ErrNoKey for encrypted env file, got %v", err)
+	}
+}
+
 func TestResolver_ResolveFromFlags_Default(t *testing.T) {
 	cfg := &config.Config{
 		Project: config.ProjectConfig{Name: "test"},
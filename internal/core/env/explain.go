@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package env
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"stagecraft/internal/envcrypt"
+	hostexec "stagecraft/internal/exec"
+)
+
+// Feature: CORE_ENV_EXPLAIN
+// Spec: spec/core/env-explain.md
+
+// Source identifies where an explained variable's value came from.
+type Source string
+
+// Sources, in the order they are usually layered (lowest precedence first).
+const (
+	SourceProviderDefault Source = "provider default"
+	SourceEnvFile         Source = "env_file"
+	SourceConfig          Source = "config"
+	SourceSecretBackend   Source = "secret backend"
+	SourceCLI             Source = "cli"
+)
+
+// RedactedValue replaces secret values in explain output.
+const RedactedValue = "<redacted>"
+
+// Layer is one set of variables contributed by a single source. Layers
+// passed to Explain are ordered lowest precedence first.
+type Layer struct {
+	Source Source
+
+	// Origin names the concrete location, e.g. ".env.staging" or
+	// "docker-compose.yml".
+	Origin string
+
+	Vars map[string]string
+
+	// Secret marks every variable of the layer as secret, regardless of name.
+	Secret bool
+}
+
+// Override records a value that was shadowed by a higher-precedence layer.
+type Override struct {
+	Source Source
+	Origin string
+	Value  string
+}
+
+// Variable is the final value of one variable and how it got there.
+type Variable struct {
+	Key    string
+	Value  string
+	Source Source
+	Origin string
+	Secret bool
+
+	// Overrides lists shadowed values, lowest precedence first.
+	Overrides []Override
+}
+
+// Explain merges layers in order and returns every variable sorted by key.
+// A later layer wins over an earlier one; the shadowed values are kept in
+// Overrides so callers can show why a value is not the expected one.
+func Explain(layers []Layer) []Variable {
+	vars := make(map[string]*Variable)
+
+	for _, layer := range layers {
+		for key, value := range layer.Vars {
+			secret := layer.Secret || IsSecretKey(key)
+
+			v, ok := vars[key]
+			if !ok {
+				vars[key] = &Variable{
+					Key:    key,
+					Value:  value,
+					Source: layer.Source,
+					Origin: layer.Origin,
+					Secret: secret,
+				}
+				continue
+			}
+
+			v.Overrides = append(v.Overrides, Override{Source: v.Source, Origin: v.Origin, Value: v.Value})
+			v.Value = value
+			v.Source = layer.Source
+			v.Origin = layer.Origin
+			v.Secret = v.Secret || secret
+		}
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]Variable, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *vars[key])
+	}
+	return result
+}
+
+// InterpolateFromShell resolves ${VAR} references using lookup, which is
+// normally os.LookupEnv: docker compose substitutes them from the shell
+// that runs it. A variable whose value changes is attributed to SourceCLI,
+// with the previous value kept as an override, and is secret when any
+// referenced name is. Unset references are left as they are.
+func InterpolateFromShell(vars []Variable, lookup func(string) (string, bool)) []Variable {
+	for i := range vars {
+		v := &vars[i]
+
+		var used []string
+		value := varPattern.ReplaceAllStringFunc(v.Value, func(match string) string {
+			name := varPattern.FindStringSubmatch(match)[1]
+			if shell, ok := lookup(name); ok {
+				used = append(used, "$"+name)
+				return shell
+			}
+			return match
+		})
+		if len(used) == 0 {
+			continue
+		}
+
+		v.Overrides = append(v.Overrides, Override{Source: v.Source, Origin: v.Origin, Value: v.Value})
+		v.Value = value
+		v.Source = SourceCLI
+		v.Origin = "shell " + strings.Join(used, ", ")
+		for _, ref := range used {
+			v.Secret = v.Secret || IsSecretKey(ref[1:])
+		}
+	}
+	return vars
+}
+
+// IsSecretKey reports whether a variable name looks like it holds a secret.
+// It is the rule the command redactor uses, see hostexec.IsSecretKey.
+func IsSecretKey(key string) bool {
+	return hostexec.IsSecretKey(key)
+}
+
+// Redact returns the value to display for a variable. Secret values are
+// replaced entirely; other URLs with embedded credentials keep everything
+// but the password, so hosts and database names stay debuggable.
+func Redact(v Variable) string {
+	return redactValue(v.Value, v.Secret)
+}
+
+func redactValue(value string, secret bool) string {
+	switch {
+	case value == "":
+		return ""
+	case secret:
+		return RedactedValue
+	}
+
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+			return u.String()
+		}
+	}
+	return value
+}
+
+// RedactOverride returns the display value of a shadowed value, redacted
+// when the variable is secret.
+func RedactOverride(v Variable, o Override) string {
+	return redactValue(o.Value, v.Secret)
+}
+
+// ReadEnvFile reads and parses a dotenv file, decrypting age/SOPS files.
+// Secret-looking values are masked in logged commands from then on.
+func ReadEnvFile(ctx context.Context, path string) (map[string]string, error) {
+	data, err := envcrypt.ReadFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	parseEnvFileInto(vars, data)
+	hostexec.Secrets.AddVars(vars, IsSecretKey)
+	return vars, nil
+}
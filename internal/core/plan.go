@@ -155,22 +155,37 @@ func (p *Planner) addMigrationOps(plan *Plan, strategy string) []string {
 	return opIDs
 }
 
-// addBuildOps adds build operations.
+// addBuildOps adds build operations, one per backend service in name order.
 func (p *Planner) addBuildOps(plan *Plan) {
-	if p.config.Backend != nil {
-		opID := "build_backend"
+	for _, svc := range p.config.Backend.ServiceList() {
+		metadata := map[string]interface{}{
+			"provider": svc.Provider,
+		}
+		description := fmt.Sprintf("Build backend using provider %s", svc.Provider)
+		if p.config.Backend.IsMultiService() {
+			metadata["service"] = svc.Name
+			description = fmt.Sprintf("Build backend service %s using provider %s", svc.Name, svc.Provider)
+		}
+
 		plan.Operations = append(plan.Operations, Operation{
-			ID:           opID,
+			ID:           buildOpID(p.config.Backend, svc.Name),
 			Type:         OpTypeBuild,
-			Description:  fmt.Sprintf("Build backend using provider %s", p.config.Backend.Provider),
+			Description:  description,
 			Dependencies: []string{},
-			Metadata: map[string]interface{}{
-				"provider": p.config.Backend.Provider,
-			},
+			Metadata:     metadata,
 		})
 	}
 }
 
+// buildOpID returns the build operation ID for a backend service. The
+// single-service shorthand keeps the historical "build_backend" ID.
+func buildOpID(backend *config.BackendConfig, service string) string {
+	if !backend.IsMultiService() {
+		return "build_backend"
+	}
+	return "build_backend_" + service
+}
+
 // addDeployOps adds deployment operations.
 // preDeployMigrationIDs are the IDs of pre-deploy migration operations that must complete first.
 // IDs are expected to be sorted for deterministic dependency ordering.
@@ -178,8 +193,8 @@ func (p *Planner) addDeployOps(plan *Plan, preDeployMigrationIDs []string) {
 	opID := fmt.Sprintf("deploy_%s", plan.Environment)
 
 	deps := []string{}
-	if p.config.Backend != nil {
-		deps = append(deps, "build_backend")
+	for _, svc := range p.config.Backend.ServiceList() {
+		deps = append(deps, buildOpID(p.config.Backend, svc.Name))
 	}
 	// Add all pre-deploy migration dependencies (already sorted)
 	deps = append(deps, preDeployMigrationIDs...)
@@ -200,13 +215,20 @@ func (p *Planner) addHealthCheckOps(plan *Plan) {
 	env := plan.Environment
 	opID := fmt.Sprintf("health_check_%s", env)
 
+	metadata := map[string]interface{}{
+		"environment": env,
+	}
+
+	// Workers expose no HTTP endpoint; they are checked by container state.
+	if workers := p.config.Backend.WorkerServices(); len(workers) > 0 {
+		metadata["workers"] = workers
+	}
+
 	plan.Operations = append(plan.Operations, Operation{
 		ID:           opID,
 		Type:         OpTypeHealthCheck,
 		Description:  fmt.Sprintf("Health check for environment %s", env),
 		Dependencies: []string{fmt.Sprintf("deploy_%s", env)},
-		Metadata: map[string]interface{}{
-			"environment": env,
-		},
+		Metadata:     metadata,
 	})
 }
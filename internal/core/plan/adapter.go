@@ -150,6 +150,12 @@ type OperationInputs struct {
 	Engine   string `json:"engine,omitempty"`
 	Path     string `json:"path,omitempty"`
 	ConnEnv  string `json:"conn_env,omitempty"`
+
+	Workers []string `json:"workers,omitempty"`
+
+	// ComposeHashes maps each compose service to its fragment hash
+	// (DEPLOY_ROLLOUT); encoding/json sorts the keys.
+	ComposeHashes map[string]string `json:"compose_hashes,omitempty"`
 }
 
 // marshalOperationInputs converts operation metadata to typed struct, then JSON.
@@ -179,6 +185,12 @@ func marshalOperationInputs(_ core.OperationType, metadata map[string]interface{
 		if connEnv, ok := metadata["conn_env"].(string); ok {
 			inputs.ConnEnv = connEnv
 		}
+		if workers, ok := metadata["workers"].([]string); ok {
+			inputs.Workers = workers
+		}
+		if hashes, ok := metadata["compose_hashes"].(map[string]string); ok {
+			inputs.ComposeHashes = hashes
+		}
 	}
 
 	return json.Marshal(inputs)
@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package plan
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"stagecraft/internal/buildinfo"
+	"stagecraft/internal/core"
+	"stagecraft/internal/plansign"
+	"stagecraft/pkg/engine"
+)
+
+// Feature: DEPLOY_PLAN_ARTIFACT
+// Spec: spec/deploy/plan-artifact.md
+
+// ArtifactSchemaVersion is the current plan artifact schema version.
+const ArtifactSchemaVersion = 1
+
+// ArtifactRequiresVersion is the oldest Stagecraft release that executes
+// the artifacts this build writes. Raise it with every change older
+// releases would misread.
+const ArtifactRequiresVersion = "0.1.0"
+
+var (
+	// ErrDigestMismatch is returned when an artifact's content does not match its digest.
+	ErrDigestMismatch = errors.New("plan artifact digest mismatch")
+	// ErrConfigDrift is returned when the config changed since the artifact was planned.
+	ErrConfigDrift = errors.New("config has changed since the plan was created")
+	// ErrGitDrift is returned when git HEAD moved since the artifact was planned.
+	ErrGitDrift = errors.New("git HEAD has changed since the plan was created")
+)
+
+// Artifact is a reviewable, self-verifying deployment plan produced by
+// `deploy --plan-only` and executed by `deploy --apply-plan`.
+//
+// Artifacts are deterministic: planning the same config at the same commit
+// yields byte-identical files. Digest is the content address of the
+// artifact's canonical JSON with Digest and Signature empty; Signature, when
+// present, is an ed25519 signature over Digest.
+type Artifact struct {
+	SchemaVersion int                   `json:"schema_version"`
+	Environment   string                `json:"environment"`
+	Version       string                `json:"version"`
+	CommitSHA     string                `json:"commit_sha,omitempty"`
+	ConfigHash    string                `json:"config_hash"`
+	PlanID        string                `json:"plan_id"`
+	Operations    []ArtifactOperation   `json:"operations"`
+	Digest        string                `json:"digest"`
+	Signature     *engine.PlanSignature `json:"signature,omitempty"`
+
+	// StagecraftVersion is the Stagecraft that planned the artifact, and
+	// RequiresStagecraft the oldest Stagecraft that may execute it.
+	StagecraftVersion  string `json:"stagecraft_version,omitempty"`
+	RequiresStagecraft string `json:"requires_stagecraft,omitempty"`
+}
+
+// ArtifactOperation is the serialized form of a core.Operation.
+type ArtifactOperation struct {
+	ID           string                 `json:"id"`
+	Type         core.OperationType     `json:"type"`
+	Description  string                 `json:"description"`
+	Dependencies []string               `json:"dependencies"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// NewArtifact captures p together with the inputs it was planned from.
+func NewArtifact(p *core.Plan, version, commitSHA, configHash string) (*Artifact, error) {
+	enginePlan, err := ToEnginePlan(p, p.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]ArtifactOperation, 0, len(p.Operations))
+	for _, op := range p.Operations {
+		deps := op.Dependencies
+		if deps == nil {
+			deps = []string{}
+		}
+		ops = append(ops, ArtifactOperation{
+			ID:           op.ID,
+			Type:         op.Type,
+			Description:  op.Description,
+			Dependencies: deps,
+			Metadata:     op.Metadata,
+		})
+	}
+
+	a := &Artifact{
+		SchemaVersion:      ArtifactSchemaVersion,
+		Environment:        p.Environment,
+		StagecraftVersion:  buildinfo.Version(),
+		RequiresStagecraft: ArtifactRequiresVersion,
+		Version:            version,
+		CommitSHA:          commitSHA,
+		ConfigHash:         configHash,
+		PlanID:             enginePlan.ID,
+		Operations:         ops,
+	}
+	if a.Digest, err = a.computeDigest(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *Artifact) computeDigest() (string, error) {
+	unsigned := *a
+	unsigned.Digest = ""
+	unsigned.Signature = nil
+	digest, err := engine.HashCanonical(unsigned)
+	if err != nil {
+		return "", fmt.Errorf("encoding plan artifact: %w", err)
+	}
+	return digest, nil
+}
+
+// Sign attaches an ed25519 signature over the artifact's digest.
+func (a *Artifact) Sign(key ed25519.PrivateKey) {
+	a.Signature = plansign.Sign(a.Digest, key)
+}
+
+// VerifySignature checks the artifact's signature against key. It returns
+// plansign.ErrUnsigned when the artifact carries no signature.
+func (a *Artifact) VerifySignature(key ed25519.PublicKey) error {
+	return plansign.Verify(a.Digest, a.Signature, key)
+}
+
+// Marshal returns the artifact as indented JSON with a trailing newline.
+func (a *Artifact) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding plan artifact: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// WriteFile writes the artifact to path.
+func (a *Artifact) WriteFile(path string) error {
+	data, err := a.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing plan artifact %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadArtifact loads an artifact from path and verifies it; see
+// ParseArtifact.
+func ReadArtifact(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the operator
+	if err != nil {
+		return nil, fmt.Errorf("reading plan artifact: %w", err)
+	}
+	return ParseArtifact(data, path)
+}
+
+// ParseArtifact decodes an artifact and verifies the Stagecraft version it
+// requires, its schema version and its digest. name identifies the
+// artifact in errors.
+func ParseArtifact(data []byte, name string) (*Artifact, error) {
+	// Check the required version before decoding strictly: an artifact
+	// from a newer Stagecraft may have fields this build does not know.
+	var stamp struct {
+		StagecraftVersion  string `json:"stagecraft_version"`
+		RequiresStagecraft string `json:"requires_stagecraft"`
+	}
+	if json.Unmarshal(data, &stamp) == nil {
+		if err := buildinfo.CheckRequired("plan artifact "+name, stamp.StagecraftVersion, stamp.RequiresStagecraft); err != nil {
+			return nil, err
+		}
+	}
+
+	var a Artifact
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	dec.UseNumber()
+	if err := dec.Decode(&a); err != nil {
+		return nil, fmt.Errorf("parsing plan artifact %s: %w", name, err)
+	}
+
+	if a.SchemaVersion != ArtifactSchemaVersion {
+		return nil, fmt.Errorf("plan artifact %s: unsupported schema version %d (expected %d)", name, a.SchemaVersion, ArtifactSchemaVersion)
+	}
+
+	want, err := a.computeDigest()
+	if err != nil {
+		return nil, err
+	}
+	if a.Digest != want {
+		return nil, fmt.Errorf("%s: %w", name, ErrDigestMismatch)
+	}
+
+	return &a, nil
+}
+
+// CheckDrift compares the inputs recorded in the artifact with the current
+// config hash and git HEAD. An artifact planned outside a git checkout
+// only requires the config to match.
+func (a *Artifact) CheckDrift(configHash, headSHA string) error {
+	if configHash != a.ConfigHash {
+		return fmt.Errorf("%w (planned %s, now %s)", ErrConfigDrift, a.ConfigHash, configHash)
+	}
+	if a.CommitSHA != "" && headSHA != a.CommitSHA {
+		now := headSHA
+		if now == "" {
+			now = "unknown"
+		}
+		return fmt.Errorf("%w (planned %s, now %s)", ErrGitDrift, a.CommitSHA, now)
+	}
+	return nil
+}
+
+// CorePlan returns the plan recorded in the artifact.
+func (a *Artifact) CorePlan() *core.Plan {
+	ops := make([]core.Operation, 0, len(a.Operations))
+	for _, op := range a.Operations {
+		ops = append(ops, core.Operation{
+			ID:           op.ID,
+			Type:         op.Type,
+			Description:  op.Description,
+			Dependencies: op.Dependencies,
+			Metadata:     op.Metadata,
+		})
+	}
+	return &core.Plan{
+		Environment: a.Environment,
+		Operations:  ops,
+	}
+}
+
+// HashFile returns the SHA-256 of the file at path, formatted like Digest.
+func HashFile(path string) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is the resolved config path
+	if err != nil {
+		return "", fmt.Errorf("hashing %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
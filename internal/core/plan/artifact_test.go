@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package plan
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/buildinfo"
+	"stagecraft/internal/core"
+	"stagecraft/internal/plansign"
+)
+
+// Feature: DEPLOY_PLAN_ARTIFACT
+// Spec: spec/deploy/plan-artifact.md
+
+func sampleCorePlan() *core.Plan {
+	return &core.Plan{
+		Environment: "prod",
+		Operations: []core.Operation{
+			{
+				ID:          "build_backend",
+				Type:        core.OpTypeBuild,
+				Description: "Build backend using provider generic",
+				Metadata:    map[string]interface{}{"provider": "generic"},
+			},
+			{
+				ID:           "deploy_prod",
+				Type:         core.OpTypeDeploy,
+				Description:  "Deploy to environment prod",
+				Dependencies: []string{"build_backend"},
+				Metadata:     map[string]interface{}{"environment": "prod"},
+			},
+		},
+	}
+}
+
+func TestNewArtifact_Deterministic(t *testing.T) {
+	first, err := NewArtifact(sampleCorePlan(), "v1.0.0", "abc123", "sha256:cfg")
+	if err != nil {
+		t.Fatalf("NewArtifact() error = %v", err)
+	}
+	second, err := NewArtifact(sampleCorePlan(), "v1.0.0", "abc123", "sha256:cfg")
+	if err != nil {
+		t.Fatalf("NewArtifact() error = %v", err)
+	}
+
+	a, _ := first.Marshal()
+	b, _ := second.Marshal()
+	if !bytes.Equal(a, b) {
+		t.Fatalf("expected identical artifacts, got:\n%s\n---\n%s", a, b)
+	}
+	if !strings.HasPrefix(first.Digest, "sha256:") || first.PlanID == "" {
+		t.Errorf("expected digest and plan ID, got %q / %q", first.Digest, first.PlanID)
+	}
+}
+
+func TestArtifact_RoundTrip(t *testing.T) {
+	artifact, err := NewArtifact(sampleCorePlan(), "v1.0.0", "abc123", "sha256:cfg")
+	if err != nil {
+		t.Fatalf("NewArtifact() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := artifact.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	loaded, err := ReadArtifact(path)
+	if err != nil {
+		t.Fatalf("ReadArtifact() error = %v", err)
+	}
+	if loaded.Digest != artifact.Digest || loaded.Version != "v1.0.0" {
+		t.Errorf("unexpected artifact: %+v", loaded)
+	}
+
+	p := loaded.CorePlan()
+	if p.Environment != "prod" || len(p.Operations) != 2 {
+		t.Fatalf("unexpected plan: %+v", p)
+	}
+	if p.Operations[1].Dependencies[0] != "build_backend" || p.Operations[0].Metadata["provider"] != "generic" {
+		t.Errorf("operations not preserved: %+v", p.Operations)
+	}
+}
+
+func TestReadArtifact_DetectsTampering(t *testing.T) {
+	artifact, err := NewArtifact(sampleCorePlan(), "v1.0.0", "", "sha256:cfg")
+	if err != nil {
+		t.Fatalf("NewArtifact() error = %v", err)
+	}
+	data, _ := artifact.Marshal()
+	tampered := bytes.Replace(data, []byte(`"v1.0.0"`), []byte(`"v6.6.6"`), 1)
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(path, tampered, 0o600); err != nil {
+		t.Fatalf("writing artifact: %v", err)
+	}
+
+	if _, err := ReadArtifact(path); !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("expected ErrDigestMismatch, got %v", err)
+	}
+}
+
+func TestReadArtifact_RejectsUnknownSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version": 99}`), 0o600); err != nil {
+		t.Fatalf("writing artifact: %v", err)
+	}
+
+	_, err := ReadArtifact(path)
+	if err == nil || !strings.Contains(err.Error(), "unsupported schema version") {
+		t.Fatalf("expected schema version error, got %v", err)
+	}
+}
+
+func TestReadArtifact_RequiresNewerStagecraft(t *testing.T) {
+	t.Setenv(buildinfo.VersionEnv, "1.4.0")
+
+	// A newer schema with fields this build does not know
+	path := filepath.Join(t.TempDir(), "plan.json")
+	data := `{"schema_version": 2, "stagecraft_version": "1.6.0", "requires_stagecraft": "1.5.0", "hosts": []}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("writing artifact: %v", err)
+	}
+
+	_, err := ReadArtifact(path)
+	if !errors.Is(err, buildinfo.ErrVersionSkew) {
+		t.Fatalf("expected version skew error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "built by v1.6.0, requires >= v1.5.0 (running v1.4.0)") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestNewArtifact_RecordsStagecraftVersion(t *testing.T) {
+	t.Setenv(buildinfo.VersionEnv, "1.4.0")
+
+	a, err := NewArtifact(sampleCorePlan(), "v1.0.0", "abc123", "sha256:cfg")
+	if err != nil {
+		t.Fatalf("NewArtifact() error = %v", err)
+	}
+	if a.StagecraftVersion != "1.4.0" || a.RequiresStagecraft != ArtifactRequiresVersion {
+		t.Errorf("artifact versions = %q / %q", a.StagecraftVersion, a.RequiresStagecraft)
+	}
+
+	data, _ := a.Marshal()
+	if _, err := ParseArtifact(data, "plan.json"); err != nil {
+		t.Errorf("ParseArtifact() of a current artifact: %v", err)
+	}
+}
+
+func TestArtifact_CheckDrift(t *testing.T) {
+	artifact := &Artifact{ConfigHash: "sha256:cfg", CommitSHA: "abc123"}
+
+	if err := artifact.CheckDrift("sha256:cfg", "abc123"); err != nil {
+		t.Errorf("expected no drift, got %v", err)
+	}
+	if err := artifact.CheckDrift("sha256:other", "abc123"); !errors.Is(err, ErrConfigDrift) {
+		t.Errorf("expected ErrConfigDrift, got %v", err)
+	}
+	if err := artifact.CheckDrift("sha256:cfg", "def456"); !errors.Is(err, ErrGitDrift) {
+		t.Errorf("expected ErrGitDrift, got %v", err)
+	}
+	if err := artifact.CheckDrift("sha256:cfg", ""); !errors.Is(err, ErrGitDrift) {
+		t.Errorf("expected ErrGitDrift without git, got %v", err)
+	}
+
+	noGit := &Artifact{ConfigHash: "sha256:cfg"}
+	if err := noGit.CheckDrift("sha256:cfg", "abc123"); err != nil {
+		t.Errorf("expected artifact without commit to ignore HEAD, got %v", err)
+	}
+}
+
+func TestArtifact_SignatureSurvivesRoundTrip(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+	artifact, err := NewArtifact(sampleCorePlan(), "v1.0.0", "", "sha256:cfg")
+	if err != nil {
+		t.Fatalf("NewArtifact() error = %v", err)
+	}
+	unsignedDigest := artifact.Digest
+	artifact.Sign(key)
+	if artifact.Digest != unsignedDigest {
+		t.Fatalf("signing changed the digest")
+	}
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := artifact.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	loaded, err := ReadArtifact(path)
+	if err != nil {
+		t.Fatalf("ReadArtifact() error = %v", err)
+	}
+	if err := loaded.VerifySignature(plansign.PublicKey(key)); err != nil {
+		t.Fatalf("VerifySignature() error = %v", err)
+	}
+
+	// An attacker who edits the plan and recomputes the digest still cannot
+	// produce a matching signature.
+	loaded.Version = "v6.6.6"
+	if loaded.Digest, err = loaded.computeDigest(); err != nil {
+		t.Fatalf("computeDigest() error = %v", err)
+	}
+	if err := loaded.VerifySignature(plansign.PublicKey(key)); !errors.Is(err, plansign.ErrBadSignature) {
+		t.Fatalf("expected ErrBadSignature, got %v", err)
+	}
+
+	unsigned, _ := NewArtifact(sampleCorePlan(), "v1.0.0", "", "sha256:cfg")
+	if err := unsigned.VerifySignature(plansign.PublicKey(key)); !errors.Is(err, plansign.ErrUnsigned) {
+		t.Fatalf("expected ErrUnsigned, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	"stagecraft/internal/core"
+	"stagecraft/internal/core/state"
+)
+
+// Feature: CORE_PLAN_DIFF
+// Spec: spec/core/plan-diff.md
+
+// Fingerprint reduces p to the per-step records stored with a release:
+// step ID, engine action, and a hash of the step's canonical inputs.
+func Fingerprint(p *core.Plan) ([]state.PlanStep, error) {
+	if p == nil {
+		return nil, fmt.Errorf("core plan is nil")
+	}
+
+	enginePlan, err := ToEnginePlan(p, p.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]state.PlanStep, 0, len(enginePlan.Steps))
+	for i := range enginePlan.Steps {
+		sum := sha256.Sum256(enginePlan.Steps[i].Inputs)
+		steps = append(steps, state.PlanStep{
+			ID:         enginePlan.Steps[i].ID,
+			Action:     string(enginePlan.Steps[i].Action),
+			InputsHash: "sha256:" + hex.EncodeToString(sum[:]),
+		})
+	}
+	return steps, nil
+}
+
+// StepChange describes a step present in both plans whose action or inputs
+// differ.
+type StepChange struct {
+	ID     string
+	Before state.PlanStep
+	After  state.PlanStep
+}
+
+// Diff is the difference between a previous and a newly computed plan.
+// Step lists are sorted by ID.
+type Diff struct {
+	Added     []state.PlanStep
+	Removed   []state.PlanStep
+	Changed   []StepChange
+	Unchanged int
+}
+
+// Compare diffs next against prev, matching steps by ID.
+func Compare(prev, next []state.PlanStep) *Diff {
+	before := make(map[string]state.PlanStep, len(prev))
+	for _, s := range prev {
+		before[s.ID] = s
+	}
+
+	d := &Diff{}
+	seen := make(map[string]bool, len(next))
+	for _, s := range next {
+		seen[s.ID] = true
+		old, ok := before[s.ID]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, s)
+		case old != s:
+			d.Changed = append(d.Changed, StepChange{ID: s.ID, Before: old, After: s})
+		default:
+			d.Unchanged++
+		}
+	}
+	for _, s := range prev {
+		if !seen[s.ID] {
+			d.Removed = append(d.Removed, s)
+		}
+	}
+
+	sort.Slice(d.Added, func(i, j int) bool { return d.Added[i].ID < d.Added[j].ID })
+	sort.Slice(d.Removed, func(i, j int) bool { return d.Removed[i].ID < d.Removed[j].ID })
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].ID < d.Changed[j].ID })
+
+	return d
+}
+
+// HasChanges reports whether any step was added, removed, or changed.
+func (d *Diff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// Render writes a human-readable summary of d: one line per added (+),
+// removed (-), or changed (~) step, followed by a count line.
+func (d *Diff) Render(w io.Writer) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	for _, s := range d.Added {
+		printf("  + %s (%s)\n", s.ID, s.Action)
+	}
+	for _, s := range d.Removed {
+		printf("  - %s (%s)\n", s.ID, s.Action)
+	}
+	for _, c := range d.Changed {
+		if c.Before.Action != c.After.Action {
+			printf("  ~ %s (action %s -> %s)\n", c.ID, c.Before.Action, c.After.Action)
+		} else {
+			printf("  ~ %s (%s, inputs changed)\n", c.ID, c.After.Action)
+		}
+	}
+	printf("  %d added, %d removed, %d changed, %d unchanged\n",
+		len(d.Added), len(d.Removed), len(d.Changed), d.Unchanged)
+
+	return err
+}
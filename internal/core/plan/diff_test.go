@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package plan
+
+import (
+	"bytes"
+	"testing"
+
+	"stagecraft/internal/core/state"
+)
+
+// Feature: CORE_PLAN_DIFF
+// Spec: spec/core/plan-diff.md
+
+func TestFingerprint_DeterministicAndInputSensitive(t *testing.T) {
+	first, err := Fingerprint(sampleCorePlan())
+	if err != nil {
+		t.Fatalf("Fingerprint() error = %v", err)
+	}
+	second, err := Fingerprint(sampleCorePlan())
+	if err != nil {
+		t.Fatalf("Fingerprint() error = %v", err)
+	}
+	if d := Compare(first, second); d.HasChanges() || d.Unchanged != len(first) {
+		t.Fatalf("expected identical fingerprints, got %+v", d)
+	}
+
+	changed := sampleCorePlan()
+	changed.Operations[0].Metadata = map[string]interface{}{"provider": "encore-ts"}
+	third, err := Fingerprint(changed)
+	if err != nil {
+		t.Fatalf("Fingerprint() error = %v", err)
+	}
+	d := Compare(first, third)
+	if len(d.Changed) != 1 || d.Changed[0].ID != "build_backend" {
+		t.Fatalf("expected build_backend to change, got %+v", d)
+	}
+}
+
+func TestCompare_AddedRemovedChanged(t *testing.T) {
+	prev := []state.PlanStep{
+		{ID: "b", Action: "build", InputsHash: "sha256:1"},
+		{ID: "a", Action: "migrate", InputsHash: "sha256:2"},
+		{ID: "c", Action: "apply_compose", InputsHash: "sha256:3"},
+	}
+	next := []state.PlanStep{
+		{ID: "b", Action: "build", InputsHash: "sha256:1"},
+		{ID: "c", Action: "apply_compose", InputsHash: "sha256:9"},
+		{ID: "d", Action: "health_check", InputsHash: "sha256:4"},
+	}
+
+	d := Compare(prev, next)
+	if len(d.Added) != 1 || d.Added[0].ID != "d" {
+		t.Errorf("Added = %+v", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].ID != "a" {
+		t.Errorf("Removed = %+v", d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].ID != "c" {
+		t.Errorf("Changed = %+v", d.Changed)
+	}
+	if d.Unchanged != 1 {
+		t.Errorf("Unchanged = %d", d.Unchanged)
+	}
+
+	var buf bytes.Buffer
+	if err := d.Render(&buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := "  + d (health_check)\n" +
+		"  - a (migrate)\n" +
+		"  ~ c (apply_compose, inputs changed)\n" +
+		"  1 added, 1 removed, 1 changed, 1 unchanged\n"
+	if buf.String() != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestCompare_ActionChange(t *testing.T) {
+	d := Compare(
+		[]state.PlanStep{{ID: "x", Action: "build", InputsHash: "sha256:1"}},
+		[]state.PlanStep{{ID: "x", Action: "rollout", InputsHash: "sha256:1"}},
+	)
+
+	var buf bytes.Buffer
+	if err := d.Render(&buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := buf.String(); got != "  ~ x (action build -> rollout)\n  0 added, 0 removed, 1 changed, 0 unchanged\n" {
+		t.Errorf("unexpected render:\n%s", got)
+	}
+}
@@ -364,3 +364,77 @@ databases:
 		t.Errorf("expected health check to depend on deploy_prod, got %q", healthCheckOp.Dependencies[0])
 	}
 }
+
+func TestPlanner_PlanDeploy_BuildsEachBackendService(t *testing.T) {
+	cfg := &config.Config{
+		Project: config.ProjectConfig{Name: "test-app"},
+		Backend: &config.BackendConfig{
+			Services: map[string]config.BackendServiceConfig{
+				"worker": {Provider: "generic"},
+				"api":    {Provider: "encore-ts"},
+			},
+		},
+		Environments: map[string]config.EnvironmentConfig{
+			"prod": {Driver: "digitalocean"},
+		},
+	}
+
+	plan, err := NewPlanner(cfg).PlanDeploy("prod")
+	if err != nil {
+		t.Fatalf("PlanDeploy() error = %v", err)
+	}
+
+	var buildIDs []string
+	var deployDeps []string
+	for _, op := range plan.Operations {
+		switch op.Type {
+		case OpTypeBuild:
+			buildIDs = append(buildIDs, op.ID)
+			if op.Metadata["service"] == nil {
+				t.Errorf("build op %s is missing service metadata", op.ID)
+			}
+		case OpTypeDeploy:
+			deployDeps = op.Dependencies
+		}
+	}
+
+	want := []string{"build_backend_api", "build_backend_worker"}
+	if len(buildIDs) != len(want) || buildIDs[0] != want[0] || buildIDs[1] != want[1] {
+		t.Fatalf("build ops = %v, want %v", buildIDs, want)
+	}
+	if len(deployDeps) != 2 || deployDeps[0] != want[0] || deployDeps[1] != want[1] {
+		t.Fatalf("deploy dependencies = %v, want %v", deployDeps, want)
+	}
+}
+
+func TestPlanner_PlanDeploy_HealthCheckListsWorkers(t *testing.T) {
+	cfg := &config.Config{
+		Project: config.ProjectConfig{Name: "test-app"},
+		Backend: &config.BackendConfig{
+			Services: map[string]config.BackendServiceConfig{
+				"api":  {Provider: "generic"},
+				"jobs": {Provider: "generic", Role: config.ServiceRoleWorker},
+			},
+		},
+		Environments: map[string]config.EnvironmentConfig{
+			"prod": {Driver: "digitalocean"},
+		},
+	}
+
+	plan, err := NewPlanner(cfg).PlanDeploy("prod")
+	if err != nil {
+		t.Fatalf("PlanDeploy() error = %v", err)
+	}
+
+	for _, op := range plan.Operations {
+		if op.Type != OpTypeHealthCheck {
+			continue
+		}
+		workers, ok := op.Metadata["workers"].([]string)
+		if !ok || len(workers) != 1 || workers[0] != "jobs" {
+			t.Fatalf("health check workers = %v, want [jobs]", op.Metadata["workers"])
+		}
+		return
+	}
+	t.Fatal("no health check operation in plan")
+}
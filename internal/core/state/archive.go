@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Feature: CLI_DESTROY
+// Spec: spec/commands/destroy.md
+
+// EnvArchive is an environment's state as archived by `stagecraft destroy`.
+type EnvArchive struct {
+	Environment string    `json:"environment"`
+	ArchivedAt  time.Time `json:"archived_at"`
+
+	// Releases are the environment's releases, newest first.
+	Releases []*Release `json:"releases"`
+
+	Infra *EnvInfra `json:"infra,omitempty"`
+}
+
+// ArchiveEnvironment moves env's releases and infrastructure records out of
+// the state file into archive/<env>-<timestamp>.json next to it, and
+// returns the archive's path. The archive is written before the state file
+// changes, so a failure leaves the state intact.
+func (m *Manager) ArchiveEnvironment(ctx context.Context, env string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	now := m.now().UTC()
+	archive := EnvArchive{
+		Environment: env,
+		ArchivedAt:  now,
+		Releases:    []*Release{},
+		Infra:       state.Infra[env],
+	}
+	kept := make([]*Release, 0, len(state.Releases))
+	for _, r := range state.Releases {
+		if r.Environment == env {
+			archive.Releases = append(archive.Releases, r)
+		} else {
+			kept = append(kept, r)
+		}
+	}
+	sort.Slice(archive.Releases, func(i, j int) bool {
+		return archive.Releases[i].Timestamp.After(archive.Releases[j].Timestamp)
+	})
+
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling archive: %w", err)
+	}
+
+	dir := filepath.Join(filepath.Dir(m.stateFile), "archive")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("creating archive directory: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.json", env, now.Format("20060102-150405")))
+
+	// O_EXCL: never overwrite an earlier archive
+	// #nosec G304 -- path is derived from the state file location
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("creating archive: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("writing archive: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("syncing archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("closing archive: %w", err)
+	}
+
+	state.Releases = kept
+	delete(state.Infra, env)
+
+	if err := m.saveState(ctx, state); err != nil {
+		return "", err
+	}
+	return path, nil
+}
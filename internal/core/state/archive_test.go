@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Feature: CLI_DESTROY
+// Spec: spec/commands/destroy.md
+
+func TestManager_ArchiveEnvironment(t *testing.T) {
+	ctx := context.Background()
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	mgr := newTestManager(stateFile)
+
+	for _, env := range []string{"staging", "prod", "staging"} {
+		if _, err := mgr.CreateRelease(ctx, env, "v1", "abc"); err != nil {
+			t.Fatalf("CreateRelease: %v", err)
+		}
+	}
+	if err := mgr.SetReservedIPs(ctx, "staging", []ReservedIP{{Host: "app-1", Address: "203.0.113.10"}}); err != nil {
+		t.Fatalf("SetReservedIPs: %v", err)
+	}
+
+	path, err := mgr.ArchiveEnvironment(ctx, "staging")
+	if err != nil {
+		t.Fatalf("ArchiveEnvironment: %v", err)
+	}
+	if filepath.Dir(path) != filepath.Join(filepath.Dir(stateFile), "archive") {
+		t.Errorf("archive path = %s, want it under archive/", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading archive: %v", err)
+	}
+	var archive EnvArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		t.Fatalf("decoding archive: %v", err)
+	}
+	if archive.Environment != "staging" || len(archive.Releases) != 2 {
+		t.Fatalf("archive = %+v, want 2 staging releases", archive)
+	}
+	if !archive.Releases[0].Timestamp.After(archive.Releases[1].Timestamp) {
+		t.Errorf("archived releases not newest first")
+	}
+	if archive.Infra == nil || len(archive.Infra.ReservedIPs) != 1 {
+		t.Errorf("archive infra = %+v, want the reserved IP", archive.Infra)
+	}
+
+	reloaded := newTestManager(stateFile)
+	if releases, _ := reloaded.ListReleases(ctx, "staging"); len(releases) != 0 {
+		t.Errorf("staging releases after archive = %d, want 0", len(releases))
+	}
+	if releases, _ := reloaded.ListReleases(ctx, "prod"); len(releases) != 1 {
+		t.Errorf("prod releases after archive = %d, want 1", len(releases))
+	}
+	if ips, _ := reloaded.ReservedIPs(ctx, "staging"); len(ips) != 0 {
+		t.Errorf("staging reserved IPs after archive = %v, want none", ips)
+	}
+}
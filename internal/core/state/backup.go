@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Feature: CORE_STATE_CONSISTENCY
+// Spec: spec/core/state-consistency.md
+
+// DefaultBackupCount is how many previous versions of the state file a
+// Manager keeps by default.
+const DefaultBackupCount = 5
+
+// SetBackupCount sets how many previous versions of the state file each
+// save keeps as <state file>.1.bak (newest) to <state file>.<n>.bak.
+// Zero disables rotating backups.
+func (m *Manager) SetBackupCount(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	m.backupCount = n
+}
+
+// rotatingBackupPath returns the path of the i-th most recent backup.
+func rotatingBackupPath(stateFile string, i int) string {
+	return fmt.Sprintf("%s.%d.bak", stateFile, i)
+}
+
+// rotateBackups shifts the existing backups down by one, dropping the
+// oldest, and keeps the current state file as backup 1. A state file that
+// fails verification is not kept, so a damaged file never displaces a
+// good backup. It runs before the new state is renamed into place, so the
+// state file itself is never missing.
+func (m *Manager) rotateBackups() error {
+	if m.backupCount == 0 {
+		return nil
+	}
+
+	report, err := VerifyFile(m.stateFile)
+	if err != nil {
+		return err
+	}
+	if !report.Exists || !report.OK() {
+		return nil
+	}
+
+	if err := os.Remove(rotatingBackupPath(m.stateFile, m.backupCount)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing oldest state backup: %w", err)
+	}
+	for i := m.backupCount - 1; i >= 1; i-- {
+		err := os.Rename(rotatingBackupPath(m.stateFile, i), rotatingBackupPath(m.stateFile, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("rotating state backup %d: %w", i, err)
+		}
+	}
+
+	// The rename that follows replaces the state file's directory entry, so
+	// a hard link keeps the current contents without copying them.
+	newest := rotatingBackupPath(m.stateFile, 1)
+	if err := os.Link(m.stateFile, newest); err != nil {
+		if err := copyFile(m.stateFile, newest); err != nil {
+			return fmt.Errorf("backing up state file: %w", err)
+		}
+	}
+	return nil
+}
+
+// copyFile copies src to dst with mode 0600, syncing dst.
+func copyFile(src, dst string) error {
+	//nolint:gosec // G304: src is the state file path from trusted config
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	//nolint:gosec // G304: dst derives from the state file path
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Feature: CORE_STATE_CONSISTENCY
+// Spec: spec/core/state-consistency.md
+
+func TestManager_Save_RotatesBackups(t *testing.T) {
+	ctx := context.Background()
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	mgr := NewManager(stateFile)
+	mgr.SetBackupCount(3)
+
+	var previous []byte
+	for i := 0; i < 5; i++ {
+		if _, err := mgr.CreateRelease(ctx, "prod", "v1", "sha"); err != nil {
+			t.Fatalf("CreateRelease failed: %v", err)
+		}
+		if i == 3 {
+			data, err := os.ReadFile(stateFile)
+			if err != nil {
+				t.Fatalf("failed to read state file: %v", err)
+			}
+			previous = data
+		}
+	}
+
+	for i := 1; i <= 3; i++ {
+		if _, err := os.Stat(rotatingBackupPath(stateFile, i)); err != nil {
+			t.Errorf("expected backup %d: %v", i, err)
+		}
+	}
+	if _, err := os.Stat(rotatingBackupPath(stateFile, 4)); !os.IsNotExist(err) {
+		t.Errorf("expected at most 3 backups, got backup 4 (%v)", err)
+	}
+
+	newest, err := os.ReadFile(rotatingBackupPath(stateFile, 1))
+	if err != nil {
+		t.Fatalf("failed to read newest backup: %v", err)
+	}
+	if string(newest) != string(previous) {
+		t.Errorf("expected backup 1 to hold the previous state file")
+	}
+
+	report, err := VerifyFile(rotatingBackupPath(stateFile, 3))
+	if err != nil || !report.OK() || report.Releases != 2 {
+		t.Errorf("expected backup 3 to be a valid state with 2 releases, got %+v (%v)", report, err)
+	}
+}
+
+func TestManager_Save_DoesNotBackUpDamagedFile(t *testing.T) {
+	ctx := context.Background()
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	mgr := NewManager(stateFile)
+	if _, err := mgr.CreateRelease(ctx, "prod", "v1", "sha"); err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+	if err := os.WriteFile(stateFile, []byte(`{"releases": [`), 0o600); err != nil {
+		t.Fatalf("failed to truncate state file: %v", err)
+	}
+
+	if _, err := mgr.Repair(ctx, RepairOptions{}); err == nil {
+		t.Fatalf("expected Repair to fail without a valid backup")
+	}
+	if _, err := os.Stat(rotatingBackupPath(stateFile, 1)); !os.IsNotExist(err) {
+		t.Errorf("expected no backup of the damaged file, got %v", err)
+	}
+}
+
+func TestManager_Save_BackupsDisabled(t *testing.T) {
+	ctx := context.Background()
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	mgr := NewManager(stateFile)
+	mgr.SetBackupCount(0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := mgr.CreateRelease(ctx, "prod", "v1", "sha"); err != nil {
+			t.Fatalf("CreateRelease failed: %v", err)
+		}
+	}
+
+	matches, err := filepath.Glob(stateFile + ".*.bak")
+	if err != nil {
+		t.Fatalf("glob failed: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("expected no backups, got %v", matches)
+	}
+}
+
+func TestManager_Repair_RestoresRotatingBackup(t *testing.T) {
+	ctx := context.Background()
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	mgr := NewManager(stateFile)
+	for i := 0; i < 2; i++ {
+		if _, err := mgr.CreateRelease(ctx, "prod", "v1", "sha"); err != nil {
+			t.Fatalf("CreateRelease failed: %v", err)
+		}
+	}
+	if err := os.WriteFile(stateFile, nil, 0o600); err != nil {
+		t.Fatalf("failed to empty state file: %v", err)
+	}
+
+	result, err := mgr.Repair(ctx, RepairOptions{})
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if result.Backup != rotatingBackupPath(stateFile, 1) || result.Releases != 1 {
+		t.Errorf("expected the newest rotating backup to be restored, got %+v", result)
+	}
+}
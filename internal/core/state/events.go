@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Feature: RELEASE_TIMELINE
+// Spec: spec/core/release-timeline.md
+
+// EventType classifies a release event.
+type EventType string
+
+const (
+	// EventPhaseStarted is recorded when a phase starts running.
+	EventPhaseStarted EventType = "phase_started"
+	// EventPhaseFinished is recorded when a phase completes, fails, times
+	// out, or is skipped.
+	EventPhaseFinished EventType = "phase_finished"
+	// EventStepRetried is recorded when a step is retried after a failure.
+	EventStepRetried EventType = "step_retried"
+	// EventHookExecuted is recorded when a deploy hook has run.
+	EventHookExecuted EventType = "hook_executed"
+	// EventNote is an operator note.
+	EventNote EventType = "note"
+)
+
+// ReleaseEvent is one entry in a release's timeline.
+type ReleaseEvent struct {
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+
+	// Phase is the phase the event belongs to, if any.
+	Phase ReleasePhase `json:"phase,omitempty"`
+
+	// Status is the phase status a phase event moved to.
+	Status PhaseStatus `json:"status,omitempty"`
+
+	// Actor is who added a note.
+	Actor string `json:"actor,omitempty"`
+
+	Message string `json:"message,omitempty"`
+}
+
+// phaseEvent returns the event recorded when phase moves to status, or
+// false when the move is not worth recording.
+func phaseEvent(now time.Time, phase ReleasePhase, status PhaseStatus) (ReleaseEvent, bool) {
+	var eventType EventType
+	switch status {
+	case StatusRunning:
+		eventType = EventPhaseStarted
+	case StatusCompleted, StatusFailed, StatusTimedOut, StatusSkipped:
+		eventType = EventPhaseFinished
+	default:
+		return ReleaseEvent{}, false
+	}
+	return ReleaseEvent{Time: now, Type: eventType, Phase: phase, Status: status}, true
+}
+
+// AddEvent appends an event to a release's timeline. A zero Time is set
+// to the current time.
+func (m *Manager) AddEvent(ctx context.Context, releaseID string, event ReleaseEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if event.Type == "" {
+		return fmt.Errorf("event type is required")
+	}
+	if event.Phase != "" && !isValidPhase(event.Phase) {
+		return fmt.Errorf("unknown phase %q", event.Phase)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	release := state.findReleaseByID(releaseID)
+	if release == nil {
+		return fmt.Errorf("%w: %q", ErrReleaseNotFound, releaseID)
+	}
+
+	if event.Time.IsZero() {
+		event.Time = m.now()
+	}
+	release.Events = append(release.Events, event)
+
+	return m.saveState(ctx, state)
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// Feature: RELEASE_TIMELINE
+// Spec: spec/core/release-timeline.md
+
+func TestManager_UpdatePhase_RecordsTimeline(t *testing.T) {
+	ctx := context.Background()
+	mgr := newTestManager(filepath.Join(t.TempDir(), "releases.json"))
+
+	release, err := mgr.CreateRelease(ctx, "prod", "v1", "sha")
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+
+	for _, status := range []PhaseStatus{StatusRunning, StatusRunning, StatusCompleted, StatusPending} {
+		if err := mgr.UpdatePhase(ctx, release.ID, PhaseBuild, status); err != nil {
+			t.Fatalf("UpdatePhase failed: %v", err)
+		}
+	}
+	if err := mgr.UpdatePhase(ctx, release.ID, PhasePush, StatusSkipped); err != nil {
+		t.Fatalf("UpdatePhase failed: %v", err)
+	}
+
+	loaded, err := mgr.GetRelease(ctx, release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+
+	want := []struct {
+		eventType EventType
+		phase     ReleasePhase
+		status    PhaseStatus
+	}{
+		{EventPhaseStarted, PhaseBuild, StatusRunning},
+		{EventPhaseFinished, PhaseBuild, StatusCompleted},
+		{EventPhaseFinished, PhasePush, StatusSkipped},
+	}
+	if len(loaded.Events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), loaded.Events)
+	}
+	for i, w := range want {
+		e := loaded.Events[i]
+		if e.Type != w.eventType || e.Phase != w.phase || e.Status != w.status {
+			t.Errorf("event %d: expected %s %s %s, got %+v", i, w.eventType, w.phase, w.status, e)
+		}
+		if i > 0 && e.Time.Before(loaded.Events[i-1].Time) {
+			t.Errorf("expected events in time order, got %+v", loaded.Events)
+		}
+	}
+}
+
+func TestManager_AddEvent(t *testing.T) {
+	ctx := context.Background()
+	mgr := newTestManager(filepath.Join(t.TempDir(), "releases.json"))
+
+	release, err := mgr.CreateRelease(ctx, "prod", "v1", "sha")
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+
+	note := ReleaseEvent{Type: EventNote, Actor: "alice", Message: "paged for elevated 5xx"}
+	if err := mgr.AddEvent(ctx, release.ID, note); err != nil {
+		t.Fatalf("AddEvent failed: %v", err)
+	}
+
+	loaded, err := mgr.GetRelease(ctx, release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if len(loaded.Events) != 1 {
+		t.Fatalf("expected 1 event, got %+v", loaded.Events)
+	}
+	got := loaded.Events[0]
+	if got.Time.IsZero() || got.Actor != "alice" || got.Message != note.Message {
+		t.Errorf("unexpected event: %+v", got)
+	}
+
+	loaded.Events[0].Message = "mutated"
+	again, err := mgr.GetRelease(ctx, release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if again.Events[0].Message != note.Message {
+		t.Errorf("expected snapshot mutation not to leak, got %+v", again.Events[0])
+	}
+
+	if err := mgr.AddEvent(ctx, release.ID, ReleaseEvent{}); err == nil {
+		t.Errorf("expected an event without a type to be rejected")
+	}
+	if err := mgr.AddEvent(ctx, release.ID, ReleaseEvent{Type: EventHookExecuted, Phase: "bogus"}); err == nil {
+		t.Errorf("expected an unknown phase to be rejected")
+	}
+	if err := mgr.AddEvent(ctx, "rel-nonexistent", note); !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("expected ErrReleaseNotFound, got %v", err)
+	}
+}
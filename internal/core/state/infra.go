@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"context"
+	"sort"
+)
+
+// Feature: PROVIDER_CLOUD_RESERVED_IP
+// Spec: spec/providers/cloud/reserved-ip.md
+
+// EnvInfra is the provisioned infrastructure recorded for one environment.
+type EnvInfra struct {
+	// ReservedIPs are the stable addresses attached to the environment's hosts.
+	ReservedIPs []ReservedIP `json:"reserved_ips,omitempty"`
+}
+
+// ReservedIP records a reserved (floating) IP and the host it is attached to.
+type ReservedIP struct {
+	// Host is the logical host name (e.g., "gateway-1").
+	Host string `json:"host"`
+
+	// Address is the reserved IPv4 address.
+	Address string `json:"address"`
+
+	// Region is the region the address was reserved in.
+	Region string `json:"region,omitempty"`
+}
+
+// SetReservedIPs records the reserved IPs for an environment, replacing any
+// previously recorded set. Entries are stored sorted by host.
+func (m *Manager) SetReservedIPs(ctx context.Context, env string, ips []ReservedIP) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	sorted := append([]ReservedIP(nil), ips...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Host < sorted[j].Host })
+
+	if state.Infra == nil {
+		state.Infra = make(map[string]*EnvInfra)
+	}
+	infra := state.Infra[env]
+	if infra == nil {
+		infra = &EnvInfra{}
+		state.Infra[env] = infra
+	}
+	infra.ReservedIPs = sorted
+
+	return m.saveState(ctx, state)
+}
+
+// ReservedIPs returns the reserved IPs recorded for an environment, sorted by host.
+func (m *Manager) ReservedIPs(ctx context.Context, env string) ([]ReservedIP, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infra := state.Infra[env]
+	if infra == nil {
+		return nil, nil
+	}
+	return append([]ReservedIP(nil), infra.ReservedIPs...), nil
+}
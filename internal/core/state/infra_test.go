@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+// Feature: PROVIDER_CLOUD_RESERVED_IP
+// Spec: spec/providers/cloud/reserved-ip.md
+
+func TestManager_ReservedIPs_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	mgr := newTestManager(filepath.Join(t.TempDir(), "releases.json"))
+
+	if got, err := mgr.ReservedIPs(ctx, "prod"); err != nil || got != nil {
+		t.Fatalf("ReservedIPs() on empty state = %v, %v; want nil, nil", got, err)
+	}
+
+	if _, err := mgr.CreateRelease(ctx, "prod", "v1", "abc"); err != nil {
+		t.Fatalf("CreateRelease() error = %v", err)
+	}
+
+	err := mgr.SetReservedIPs(ctx, "prod", []ReservedIP{
+		{Host: "gateway-2", Address: "203.0.113.2", Region: "nyc3"},
+		{Host: "gateway-1", Address: "203.0.113.1", Region: "nyc3"},
+	})
+	if err != nil {
+		t.Fatalf("SetReservedIPs() error = %v", err)
+	}
+
+	got, err := mgr.ReservedIPs(ctx, "prod")
+	if err != nil {
+		t.Fatalf("ReservedIPs() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Host != "gateway-1" || got[1].Address != "203.0.113.2" {
+		t.Fatalf("ReservedIPs() = %+v, want sorted by host", got)
+	}
+
+	if other, _ := mgr.ReservedIPs(ctx, "staging"); len(other) != 0 {
+		t.Errorf("expected no reserved IPs for staging, got %+v", other)
+	}
+
+	// Recording infra must not disturb releases.
+	releases, err := mgr.ListReleases(ctx, "prod")
+	if err != nil || len(releases) != 1 {
+		t.Fatalf("ListReleases() = %d releases, %v; want 1", len(releases), err)
+	}
+}
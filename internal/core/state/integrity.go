@@ -0,0 +1,361 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"stagecraft/internal/failure"
+)
+
+// Feature: CORE_STATE_INTEGRITY
+// Spec: spec/core/state-integrity.md
+
+// sealedSchemaVersion is the first schema version that carries checksums.
+const sealedSchemaVersion = 2
+
+// ErrStateCorrupt is returned when a state file fails its integrity check.
+var ErrStateCorrupt = failure.New(failure.ClassConfigInvalid, "state file failed its integrity check")
+
+// sha256Digest returns "sha256:<hex>" over v's JSON encoding.
+func sha256Digest(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// releaseHash returns the digest of r without its Hash.
+func releaseHash(r *Release) (string, error) {
+	unsealed := *r
+	unsealed.Hash = ""
+	return sha256Digest(&unsealed)
+}
+
+// documentChecksum returns the digest of s without its Checksum.
+func documentChecksum(s *stateFile) (string, error) {
+	unsealed := *s
+	unsealed.Checksum = ""
+	return sha256Digest(&unsealed)
+}
+
+// seal sets every release hash and then the document checksum.
+func seal(s *stateFile) error {
+	for _, r := range s.Releases {
+		hash, err := releaseHash(r)
+		if err != nil {
+			return fmt.Errorf("hashing release %q: %w", r.ID, err)
+		}
+		r.Hash = hash
+	}
+
+	checksum, err := documentChecksum(s)
+	if err != nil {
+		return fmt.Errorf("checksumming state: %w", err)
+	}
+	s.Checksum = checksum
+	return nil
+}
+
+// verifySealed returns a description of every integrity failure of a
+// sealed document: releases whose content no longer matches their hash,
+// then the document checksum.
+func verifySealed(s *stateFile) []string {
+	var problems []string
+	for _, r := range s.Releases {
+		hash, err := releaseHash(r)
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("release %q cannot be hashed: %v", r.ID, err))
+		case r.Hash == "":
+			problems = append(problems, fmt.Sprintf("release %q has no hash", r.ID))
+		case r.Hash != hash:
+			problems = append(problems, fmt.Sprintf("release %q does not match its hash (edited by hand?)", r.ID))
+		}
+	}
+
+	checksum, err := documentChecksum(s)
+	switch {
+	case err != nil:
+		problems = append(problems, fmt.Sprintf("file cannot be checksummed: %v", err))
+	case s.Checksum == "":
+		problems = append(problems, "file has no checksum")
+	case s.Checksum != checksum:
+		problems = append(problems, "file does not match its checksum (edited by hand?)")
+	}
+	return problems
+}
+
+// decodeState parses a state document, upgrading it to
+// CurrentSchemaVersion, and returns it with the version it was stored at.
+// Sealed documents are verified as stored, before any upgrade, and their
+// integrity problems are returned separately from parse errors. Documents
+// newer than this build cannot be verified and are not.
+func decodeState(data []byte) (*stateFile, int, []string, error) {
+	migrated, from, err := migrateState(data)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	var state stateFile
+	if err := json.Unmarshal(migrated, &state); err != nil {
+		return nil, from, nil, err
+	}
+
+	var problems []string
+	if from >= sealedSchemaVersion && from <= CurrentSchemaVersion {
+		// The checksum covers the stored schema version, not the upgraded one
+		state.SchemaVersion = from
+		problems = verifySealed(&state)
+		state.SchemaVersion = CurrentSchemaVersion
+	}
+
+	// Ensure Phases map is initialized for each release
+	for _, release := range state.Releases {
+		if release.Phases == nil {
+			release.Phases = make(map[ReleasePhase]PhaseStatus)
+		}
+	}
+	if state.Releases == nil {
+		state.Releases = []*Release{}
+	}
+
+	return &state, from, problems, nil
+}
+
+// VerifyReport is the outcome of checking a state file.
+type VerifyReport struct {
+	// Path is the checked file.
+	Path string
+
+	// Exists is false when there is no state file, which is valid.
+	Exists bool
+
+	// SchemaVersion is the version the file was written with.
+	SchemaVersion int
+
+	// Releases is the number of releases in a readable file.
+	Releases int
+
+	// Problems lists everything wrong with the file; empty when it is valid.
+	Problems []string
+
+	// Unverified explains why a readable file could not be checked, e.g. it
+	// predates checksums or is newer than this build.
+	Unverified string
+}
+
+// OK reports whether the file is valid.
+func (r *VerifyReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// VerifyFile checks the state file at path: that it parses, and that its
+// releases and contents match their checksums. Only I/O errors other than a
+// missing file are returned as errors.
+func VerifyFile(path string) (*VerifyReport, error) {
+	report := &VerifyReport{Path: path}
+
+	//nolint:gosec // G304: stateFile path comes from trusted config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+	report.Exists = true
+
+	state, from, problems, err := decodeState(data)
+	if err != nil {
+		report.Problems = []string{fmt.Sprintf("file is not a valid state document (truncated or edited by hand?): %v", err)}
+		return report, nil
+	}
+
+	report.SchemaVersion = from
+	report.Releases = len(state.Releases)
+	report.Problems = problems
+	switch {
+	case from < sealedSchemaVersion:
+		report.Unverified = fmt.Sprintf("schema version %d predates checksums; it is sealed the next time it is written", from)
+	case from > CurrentSchemaVersion:
+		report.Unverified = fmt.Sprintf("schema version %d is newer than this build supports (%d)", from, CurrentSchemaVersion)
+	}
+	return report, nil
+}
+
+// Verify checks the manager's state file; see VerifyFile.
+func (m *Manager) Verify(ctx context.Context) (*VerifyReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return VerifyFile(m.stateFile)
+}
+
+// RepairAction says what Repair did.
+type RepairAction string
+
+const (
+	// RepairNone means the file was valid and left alone.
+	RepairNone RepairAction = "none"
+	// RepairResealed means the file's current content was accepted and
+	// its checksums recomputed.
+	RepairResealed RepairAction = "resealed"
+	// RepairRestored means the file was replaced by a backup.
+	RepairRestored RepairAction = "restored"
+)
+
+// RepairResult describes what Repair did.
+type RepairResult struct {
+	Action RepairAction
+
+	// Backup is the backup the state was restored from.
+	Backup string
+
+	// Damaged is where the replaced file was kept.
+	Damaged string
+
+	// Releases is the number of releases in the repaired file.
+	Releases int
+}
+
+// RepairOptions controls Repair.
+type RepairOptions struct {
+	// AcceptEdits reseals a file that parses but fails its checksums,
+	// keeping hand edits, instead of restoring a backup.
+	AcceptEdits bool
+}
+
+// ErrNoValidBackup is returned when Repair finds no backup to restore.
+var ErrNoValidBackup = failure.New(failure.ClassConfigInvalid, "no valid state backup found")
+
+// Repair makes a damaged state file valid again. A valid file is left
+// alone. A file that parses is resealed when opts.AcceptEdits is set.
+// Otherwise the newest backup that verifies is restored and upgraded to the
+// current schema. The damaged file is kept next to the state file.
+func (m *Manager) Repair(ctx context.Context, opts RepairOptions) (*RepairResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report, err := VerifyFile(m.stateFile)
+	if err != nil {
+		return nil, err
+	}
+	if report.OK() {
+		return &RepairResult{Action: RepairNone, Releases: report.Releases}, nil
+	}
+
+	//nolint:gosec // G304: stateFile path comes from trusted config
+	damaged, err := os.ReadFile(m.stateFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+
+	if opts.AcceptEdits {
+		state, _, _, err := decodeState(damaged)
+		if err != nil {
+			return nil, fmt.Errorf("cannot accept edits to a file that does not parse: %w", err)
+		}
+		if err := m.replaceState(ctx, state, damaged); err != nil {
+			return nil, err
+		}
+		return &RepairResult{Action: RepairResealed, Damaged: m.damagedPath(), Releases: len(state.Releases)}, nil
+	}
+
+	backups, err := m.backups()
+	if err != nil {
+		return nil, err
+	}
+	for _, backup := range backups {
+		//nolint:gosec // G304: backup paths derive from the trusted state file path
+		data, err := os.ReadFile(backup)
+		if err != nil {
+			continue
+		}
+		state, _, problems, err := decodeState(data)
+		if err != nil || len(problems) > 0 {
+			continue
+		}
+
+		if err := m.replaceState(ctx, state, damaged); err != nil {
+			return nil, err
+		}
+		return &RepairResult{
+			Action:   RepairRestored,
+			Backup:   backup,
+			Damaged:  m.damagedPath(),
+			Releases: len(state.Releases),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w for %s (looked for %s)", ErrNoValidBackup, m.stateFile, m.stateFile+".*.bak")
+}
+
+// replaceState keeps the damaged bytes next to the state file, then saves
+// state over it.
+func (m *Manager) replaceState(ctx context.Context, state *stateFile, damaged []byte) error {
+	if err := os.WriteFile(m.damagedPath(), damaged, 0o600); err != nil {
+		return fmt.Errorf("keeping damaged state file: %w", err)
+	}
+	if err := m.saveState(ctx, state); err != nil {
+		return fmt.Errorf("writing repaired state file: %w", err)
+	}
+	return nil
+}
+
+// damagedPath is where Repair keeps the file it replaces.
+func (m *Manager) damagedPath() string {
+	return m.stateFile + ".damaged"
+}
+
+// backups returns the state file's backups, newest first.
+func (m *Manager) backups() ([]string, error) {
+	paths, err := filepath.Glob(m.stateFile + ".*.bak")
+	if err != nil {
+		return nil, fmt.Errorf("listing state backups: %w", err)
+	}
+
+	modTimes := make(map[string]time.Time, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			modTimes[path] = info.ModTime()
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		if !modTimes[paths[i]].Equal(modTimes[paths[j]]) {
+			return modTimes[paths[i]].After(modTimes[paths[j]])
+		}
+		return paths[i] > paths[j]
+	})
+	return paths, nil
+}
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Feature: CORE_STATE_INTEGRITY
+// Spec: spec/core/state-integrity.md
+
+func newSealedState(t *testing.T) (*Manager, string) {
+	t.Helper()
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	mgr := NewManager(stateFile)
+	if _, err := mgr.CreateRelease(context.Background(), "prod", "v1", "sha1"); err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+	return mgr, stateFile
+}
+
+func editStateFile(t *testing.T, path, old, replacement string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read state file: %v", err)
+	}
+	if !bytes.Contains(data, []byte(old)) {
+		t.Fatalf("state file does not contain %q", old)
+	}
+	if err := os.WriteFile(path, bytes.Replace(data, []byte(old), []byte(replacement), 1), 0o600); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+}
+
+func TestManager_Save_SealsReleasesAndFile(t *testing.T) {
+	mgr, stateFile := newSealedState(t)
+
+	releases, err := mgr.ListAllReleases(context.Background())
+	if err != nil {
+		t.Fatalf("ListAllReleases failed: %v", err)
+	}
+	if len(releases) != 1 || !strings.HasPrefix(releases[0].Hash, "sha256:") {
+		t.Fatalf("expected a sealed release, got %+v", releases)
+	}
+
+	report, err := VerifyFile(stateFile)
+	if err != nil {
+		t.Fatalf("VerifyFile failed: %v", err)
+	}
+	if !report.OK() || report.Unverified != "" {
+		t.Errorf("expected a verified file, got %+v", report)
+	}
+	if report.SchemaVersion != CurrentSchemaVersion || report.Releases != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestManager_Load_RejectsEditedRelease(t *testing.T) {
+	mgr, stateFile := newSealedState(t)
+	editStateFile(t, stateFile, `"version": "v1"`, `"version": "v2"`)
+
+	_, err := mgr.ListAllReleases(context.Background())
+	if !errors.Is(err, ErrStateCorrupt) {
+		t.Fatalf("expected ErrStateCorrupt, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "does not match its hash") {
+		t.Errorf("expected the error to name the edited release, got %v", err)
+	}
+
+	report, err := VerifyFile(stateFile)
+	if err != nil {
+		t.Fatalf("VerifyFile failed: %v", err)
+	}
+	if len(report.Problems) != 2 {
+		t.Errorf("expected the release hash and file checksum to fail, got %v", report.Problems)
+	}
+}
+
+func TestVerifyFile_ReportsTruncation(t *testing.T) {
+	_, stateFile := newSealedState(t)
+	data, err := os.ReadFile(stateFile)
+	if err != nil {
+		t.Fatalf("failed to read state file: %v", err)
+	}
+	if err := os.WriteFile(stateFile, data[:len(data)/2], 0o600); err != nil {
+		t.Fatalf("failed to truncate state file: %v", err)
+	}
+
+	report, err := VerifyFile(stateFile)
+	if err != nil {
+		t.Fatalf("VerifyFile failed: %v", err)
+	}
+	if report.OK() || !strings.Contains(report.Problems[0], "truncated") {
+		t.Errorf("expected a truncation problem, got %+v", report)
+	}
+}
+
+func TestVerifyFile_MissingFileIsValid(t *testing.T) {
+	report, err := VerifyFile(filepath.Join(t.TempDir(), "releases.json"))
+	if err != nil {
+		t.Fatalf("VerifyFile failed: %v", err)
+	}
+	if report.Exists || !report.OK() {
+		t.Errorf("expected a missing file to be valid, got %+v", report)
+	}
+}
+
+func TestVerifyFile_LegacyFileIsUnverified(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	if err := os.WriteFile(stateFile, []byte(legacyStateJSON), 0o600); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+
+	report, err := VerifyFile(stateFile)
+	if err != nil {
+		t.Fatalf("VerifyFile failed: %v", err)
+	}
+	if !report.OK() || report.Unverified == "" {
+		t.Errorf("expected a valid but unverified report, got %+v", report)
+	}
+}
+
+func TestManager_Repair_RestoresNewestValidBackup(t *testing.T) {
+	mgr, stateFile := newSealedState(t)
+	good, err := os.ReadFile(stateFile)
+	if err != nil {
+		t.Fatalf("failed to read state file: %v", err)
+	}
+
+	older := stateFile + ".v1.bak"
+	newer := stateFile + ".v2.bak"
+	broken := stateFile + ".v3.bak"
+	for path, data := range map[string][]byte{older: []byte(legacyStateJSON), newer: good, broken: []byte("{")} {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("failed to write backup: %v", err)
+		}
+	}
+	now := time.Now()
+	for path, age := range map[string]time.Duration{older: 3 * time.Hour, newer: 2 * time.Hour, broken: time.Hour} {
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatalf("failed to age backup: %v", err)
+		}
+	}
+
+	if err := os.WriteFile(stateFile, good[:10], 0o600); err != nil {
+		t.Fatalf("failed to truncate state file: %v", err)
+	}
+
+	result, err := mgr.Repair(context.Background(), RepairOptions{})
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if result.Action != RepairRestored || result.Backup != newer || result.Releases != 1 {
+		t.Errorf("unexpected repair result: %+v", result)
+	}
+
+	if damaged, err := os.ReadFile(result.Damaged); err != nil || string(damaged) != string(good[:10]) {
+		t.Errorf("expected the damaged file to be kept, got %q (%v)", damaged, err)
+	}
+	if report, err := VerifyFile(stateFile); err != nil || !report.OK() {
+		t.Errorf("expected a valid file after repair, got %+v (%v)", report, err)
+	}
+}
+
+func TestManager_Repair_AcceptEditsReseals(t *testing.T) {
+	mgr, stateFile := newSealedState(t)
+	editStateFile(t, stateFile, `"version": "v1"`, `"version": "v2"`)
+
+	result, err := mgr.Repair(context.Background(), RepairOptions{AcceptEdits: true})
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if result.Action != RepairResealed {
+		t.Errorf("expected a reseal, got %+v", result)
+	}
+
+	releases, err := mgr.ListAllReleases(context.Background())
+	if err != nil {
+		t.Fatalf("ListAllReleases failed: %v", err)
+	}
+	if releases[0].Version != "v2" {
+		t.Errorf("expected the edit to be kept, got version %q", releases[0].Version)
+	}
+}
+
+func TestManager_Repair_NoValidBackup(t *testing.T) {
+	mgr, stateFile := newSealedState(t)
+	if err := os.WriteFile(stateFile, []byte("{"), 0o600); err != nil {
+		t.Fatalf("failed to truncate state file: %v", err)
+	}
+
+	if _, err := mgr.Repair(context.Background(), RepairOptions{}); !errors.Is(err, ErrNoValidBackup) {
+		t.Fatalf("expected ErrNoValidBackup, got %v", err)
+	}
+}
+
+func TestManager_Repair_ValidFileIsLeftAlone(t *testing.T) {
+	mgr, _ := newSealedState(t)
+
+	result, err := mgr.Repair(context.Background(), RepairOptions{})
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if result.Action != RepairNone {
+		t.Errorf("expected no repair, got %+v", result)
+	}
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"stagecraft/internal/buildinfo"
+	"stagecraft/internal/failure"
+)
+
+// Feature: CORE_STATE_SCHEMA
+// Spec: spec/core/state-schema.md
+
+// CurrentSchemaVersion is the state file schema version understood and
+// written by this build of Stagecraft. Files without a schema_version field
+// are treated as version 0.
+const CurrentSchemaVersion = 3
+
+// RequiresVersion is the oldest Stagecraft release that reads the state
+// files this build writes. Raise it with every schema version.
+const RequiresVersion = "0.1.0"
+
+// ErrSchemaTooNew is returned when writing would downgrade a state file
+// whose schema version is newer than CurrentSchemaVersion.
+var ErrSchemaTooNew = failure.New(failure.ClassConfigInvalid, "state file schema version is newer than supported")
+
+// StateMigration transforms a state document from one schema version to the
+// next. Migrations operate on the raw top-level JSON object so they can
+// restructure fields the current Release type no longer has.
+type StateMigration struct {
+	From        int
+	To          int
+	Description string
+	Apply       func(doc map[string]json.RawMessage) error
+}
+
+// stateMigrations is the ordered list of migrations. Each entry must
+// upgrade From to From+1.
+var stateMigrations = []StateMigration{
+	{
+		From:        0,
+		To:          1,
+		Description: "record schema_version",
+		Apply:       func(map[string]json.RawMessage) error { return nil },
+	},
+	{
+		From: 1,
+		To:   2,
+		// Hashes are computed when the upgraded file is saved
+		Description: "seal releases and the file with sha256 checksums",
+		Apply:       func(map[string]json.RawMessage) error { return nil },
+	},
+	{
+		From: 2,
+		To:   3,
+		// Versions are recorded when the upgraded file is saved
+		Description: "record the Stagecraft version that wrote the file",
+		Apply:       func(map[string]json.RawMessage) error { return nil },
+	},
+}
+
+// StateMigrations returns the ordered list of registered schema migrations.
+func StateMigrations() []StateMigration {
+	out := make([]StateMigration, len(stateMigrations))
+	copy(out, stateMigrations)
+	return out
+}
+
+// stateSchemaVersion returns the schema_version of a state document, or 0
+// when the field is absent.
+func stateSchemaVersion(doc map[string]json.RawMessage) (int, error) {
+	raw, ok := doc["schema_version"]
+	if !ok {
+		return 0, nil
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return 0, fmt.Errorf("schema_version: %w", err)
+	}
+	if version < 0 {
+		return 0, fmt.Errorf("schema_version must not be negative, got %d", version)
+	}
+	return version, nil
+}
+
+// migrateState applies all pending migrations to a state document and
+// returns the upgraded document and the version it was at. Documents newer
+// than CurrentSchemaVersion are returned unchanged.
+func migrateState(data []byte) ([]byte, int, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, err
+	}
+
+	from, err := stateSchemaVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from >= CurrentSchemaVersion {
+		return data, from, nil
+	}
+
+	for _, m := range stateMigrations {
+		if m.From < from {
+			continue
+		}
+		if err := m.Apply(doc); err != nil {
+			return nil, from, fmt.Errorf("migrating state from version %d to %d: %w", m.From, m.To, err)
+		}
+	}
+	doc["schema_version"] = json.RawMessage(strconv.Itoa(CurrentSchemaVersion))
+
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, from, fmt.Errorf("marshaling migrated state: %w", err)
+	}
+	return upgraded, from, nil
+}
+
+// checkRequiredVersion fails when the state document at path requires a
+// newer Stagecraft than this one. Unparseable documents pass; loadState
+// reports them.
+func checkRequiredVersion(path string, data []byte) error {
+	var stamp struct {
+		StagecraftVersion  string `json:"stagecraft_version"`
+		RequiresStagecraft string `json:"requires_stagecraft"`
+	}
+	if json.Unmarshal(data, &stamp) != nil {
+		return nil
+	}
+	return buildinfo.CheckRequired("state file "+path, stamp.StagecraftVersion, stamp.RequiresStagecraft)
+}
+
+// backupPath returns where the original of a state file at version is kept
+// before it is upgraded, e.g. ".stagecraft/releases.json.v0.bak".
+func backupPath(stateFile string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", stateFile, version)
+}
+
+// writeBackup copies the original state file bytes to its backup path. An
+// existing backup is kept: it holds the oldest copy of that version.
+func writeBackup(stateFile string, version int, data []byte) error {
+	path := backupPath(stateFile, version)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("checking state backup: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing state backup: %w", err)
+	}
+	return nil
+}
+
+// onDiskSchemaVersion returns the schema version of the state file at
+// path, or 0 when it does not exist or cannot be parsed; loadState reports
+// unreadable files.
+func onDiskSchemaVersion(path string) int {
+	//nolint:gosec // G304: stateFile path comes from trusted config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	var probe struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if json.Unmarshal(data, &probe) != nil {
+		return 0
+	}
+	return probe.SchemaVersion
+}
@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/buildinfo"
+)
+
+// Feature: CORE_STATE_SCHEMA
+// Spec: spec/core/state-schema.md
+
+const legacyStateJSON = `{"releases":[{"id":"rel-20250101-120000","environment":"prod","version":"v1","commit_sha":"sha","timestamp":"2025-01-01T12:00:00Z","phases":{"build":"completed"}}]}`
+
+func readSchemaVersion(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	var probe struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+	if probe.SchemaVersion == nil {
+		return 0
+	}
+	return *probe.SchemaVersion
+}
+
+func TestStateMigrations_AreContiguous(t *testing.T) {
+	migrations := StateMigrations()
+	for i, m := range migrations {
+		if m.From != i || m.To != i+1 {
+			t.Errorf("migration %d upgrades %d -> %d, want %d -> %d", i, m.From, m.To, i, i+1)
+		}
+	}
+	if len(migrations) != CurrentSchemaVersion {
+		t.Errorf("expected %d migrations to reach version %d, got %d", CurrentSchemaVersion, CurrentSchemaVersion, len(migrations))
+	}
+}
+
+func TestManager_LoadState_UpgradesLegacyFileWithBackup(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	if err := os.WriteFile(stateFile, []byte(legacyStateJSON), 0o600); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+
+	mgr := NewManager(stateFile)
+	release, err := mgr.GetRelease(context.Background(), "rel-20250101-120000")
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if release.Phases[PhaseBuild] != StatusCompleted {
+		t.Errorf("expected migrated release to keep its phases, got %v", release.Phases)
+	}
+
+	if got := readSchemaVersion(t, stateFile); got != CurrentSchemaVersion {
+		t.Errorf("state file schema_version = %d, want %d", got, CurrentSchemaVersion)
+	}
+
+	backup, err := os.ReadFile(backupPath(stateFile, 0))
+	if err != nil {
+		t.Fatalf("expected backup of the legacy file: %v", err)
+	}
+	if string(backup) != legacyStateJSON {
+		t.Errorf("backup should hold the original bytes, got %s", backup)
+	}
+}
+
+func TestManager_LoadState_UpgradesSealedV2File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "releases.json")
+	doc := &stateFile{
+		SchemaVersion: 2,
+		Releases: []*Release{{
+			ID:          "rel-20250101-120000",
+			Environment: "prod",
+			Version:     "v1",
+			CommitSHA:   "sha",
+			Phases:      map[ReleasePhase]PhaseStatus{PhaseBuild: StatusCompleted},
+		}},
+	}
+	if err := seal(doc); err != nil {
+		t.Fatalf("seal failed: %v", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("failed to encode state: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+
+	report, err := VerifyFile(path)
+	if err != nil {
+		t.Fatalf("VerifyFile failed: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("sealed v2 file should verify, got %v", report.Problems)
+	}
+
+	releases, err := NewManager(path).ListReleases(context.Background(), "prod")
+	if err != nil {
+		t.Fatalf("ListReleases failed: %v", err)
+	}
+	if len(releases) != 1 {
+		t.Errorf("expected 1 release, got %d", len(releases))
+	}
+	if got := readSchemaVersion(t, path); got != CurrentSchemaVersion {
+		t.Errorf("state file schema_version = %d, want %d", got, CurrentSchemaVersion)
+	}
+}
+
+func TestManager_LoadState_KeepsExistingBackup(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	if err := os.WriteFile(stateFile, []byte(legacyStateJSON), 0o600); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+	if err := os.WriteFile(backupPath(stateFile, 0), []byte("oldest"), 0o600); err != nil {
+		t.Fatalf("failed to write backup: %v", err)
+	}
+
+	if _, err := NewManager(stateFile).ListAllReleases(context.Background()); err != nil {
+		t.Fatalf("ListAllReleases failed: %v", err)
+	}
+
+	backup, _ := os.ReadFile(backupPath(stateFile, 0))
+	if string(backup) != "oldest" {
+		t.Errorf("existing backup must not be overwritten, got %q", backup)
+	}
+}
+
+func TestManager_SaveState_WritesCurrentSchemaVersion(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+
+	if _, err := NewManager(stateFile).CreateRelease(context.Background(), "prod", "v1", "sha"); err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+	if got := readSchemaVersion(t, stateFile); got != CurrentSchemaVersion {
+		t.Errorf("state file schema_version = %d, want %d", got, CurrentSchemaVersion)
+	}
+	if _, err := os.Stat(backupPath(stateFile, 0)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("new state files need no backup, stat err = %v", err)
+	}
+}
+
+func TestManager_StrictModeRefusesDowngrade(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	newer := `{"schema_version":99,"releases":[{"id":"rel-20250101-120000","environment":"prod","version":"v1","commit_sha":"sha","timestamp":"2025-01-01T12:00:00Z","phases":{},"future_field":true}]}`
+	if err := os.WriteFile(stateFile, []byte(newer), 0o600); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+
+	mgr := NewManager(stateFile)
+	ctx := context.Background()
+
+	// Reading a newer file is allowed
+	if _, err := mgr.GetRelease(ctx, "rel-20250101-120000"); err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+
+	// Writing it would drop future_field
+	err := mgr.UpdatePhase(ctx, "rel-20250101-120000", PhaseBuild, StatusCompleted)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+	data, _ := os.ReadFile(stateFile)
+	if string(data) != newer {
+		t.Errorf("refused write must leave the file untouched, got %s", data)
+	}
+
+	mgr.SetStrict(false)
+	if err := mgr.UpdatePhase(ctx, "rel-20250101-120000", PhaseBuild, StatusCompleted); err != nil {
+		t.Fatalf("non-strict UpdatePhase failed: %v", err)
+	}
+	if got := readSchemaVersion(t, stateFile); got != CurrentSchemaVersion {
+		t.Errorf("non-strict write should use the current schema, got %d", got)
+	}
+}
+
+func TestManager_RecordsStagecraftVersion(t *testing.T) {
+	t.Setenv(buildinfo.VersionEnv, "1.4.0")
+	path := filepath.Join(t.TempDir(), "releases.json")
+
+	if _, err := NewManager(path).CreateRelease(context.Background(), "prod", "v1", "sha"); err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read state file: %v", err)
+	}
+	var doc stateFile
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to parse state file: %v", err)
+	}
+	if doc.StagecraftVersion != "1.4.0" || doc.RequiresStagecraft != RequiresVersion {
+		t.Errorf("state file versions = %q / %q", doc.StagecraftVersion, doc.RequiresStagecraft)
+	}
+}
+
+func TestManager_RefusesStateRequiringNewerStagecraft(t *testing.T) {
+	t.Setenv(buildinfo.VersionEnv, "1.4.0")
+	stateFile := filepath.Join(t.TempDir(), "releases.json")
+	newer := `{"schema_version":99,"stagecraft_version":"1.6.0","requires_stagecraft":"1.6.0","releases":{"rel-1":{}}}`
+	if err := os.WriteFile(stateFile, []byte(newer), 0o600); err != nil {
+		t.Fatalf("failed to write state file: %v", err)
+	}
+
+	mgr := NewManager(stateFile)
+	_, err := mgr.ListReleases(context.Background(), "prod")
+	if !errors.Is(err, buildinfo.ErrVersionSkew) {
+		t.Fatalf("expected ErrVersionSkew instead of a decode error, got %v", err)
+	}
+	want := "state file " + stateFile + ": written by an incompatible Stagecraft version: built by v1.6.0, requires >= v1.6.0 (running v1.4.0)"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+
+	// Development builds and recovery skip the check
+	t.Setenv(buildinfo.VersionEnv, "")
+	if _, err := mgr.ListReleases(context.Background(), "prod"); errors.Is(err, buildinfo.ErrVersionSkew) {
+		t.Errorf("development builds must not check versions, got %v", err)
+	}
+	t.Setenv(buildinfo.VersionEnv, "1.4.0")
+	mgr.SetStrict(false)
+	if _, err := mgr.ListReleases(context.Background(), "prod"); errors.Is(err, buildinfo.ErrVersionSkew) {
+		t.Errorf("non-strict managers must not check versions, got %v", err)
+	}
+}
+
+func TestMigrateState_RejectsInvalidVersion(t *testing.T) {
+	for _, doc := range []string{`{"schema_version":-1}`, `{"schema_version":"one"}`} {
+		if _, _, err := migrateState([]byte(doc)); err == nil {
+			t.Errorf("migrateState(%s) should fail", doc)
+		}
+	}
+}
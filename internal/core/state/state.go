@@ -28,6 +28,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"stagecraft/internal/buildinfo"
 )
 
 // Feature: CORE_STATE_CONSISTENCY
@@ -82,6 +84,9 @@ const (
 	StatusFailed PhaseStatus = "failed"
 	// StatusSkipped represents a phase that was skipped.
 	StatusSkipped PhaseStatus = "skipped"
+	// StatusTimedOut represents a phase that ran past its configured step
+	// timeout and was canceled.
+	StatusTimedOut PhaseStatus = "timed_out"
 )
 
 // Release represents a single deployment release.
@@ -106,13 +111,177 @@ type Release struct {
 	// Phases tracks the status of each deployment phase
 	Phases map[ReleasePhase]PhaseStatus `json:"phases"`
 
+	// HostPhases records, for phases that run on hosts, each host's
+	// outcome. The phase's overall status stays in Phases.
+	HostPhases map[ReleasePhase]map[string]HostPhaseStatus `json:"host_phases,omitempty"`
+
 	// PreviousID is the ID of the previous release (for rollback)
 	PreviousID string `json:"previous_id,omitempty"`
+
+	// Plan fingerprints the deployment plan executed for this release.
+	// Empty for releases created before plans were recorded.
+	Plan []PlanStep `json:"plan,omitempty"`
+
+	// Images records the images built for this release, sorted by service.
+	Images []BuiltImage `json:"images,omitempty"`
+
+	// Services records, per rolled-out service, its compose fragment hash
+	// and image, sorted by service. The next rollout skips services whose
+	// record is unchanged when rollout.skip_unchanged is set.
+	Services []ServiceRollout `json:"services,omitempty"`
+
+	// Bundle locates the deploy bundle the release was rolled out from.
+	// Nil for releases created before bundles were recorded, or when the
+	// bundle could not be stored.
+	Bundle *BundleRef `json:"bundle,omitempty"`
+
+	// Migrations lists the migrations the release shipped, with their
+	// reversibility; rollback refuses to go back past irreversible ones.
+	Migrations []MigrationRecord `json:"migrations,omitempty"`
+
+	// Events is the release's timeline, oldest first: phase transitions,
+	// retries, hooks, and operator notes.
+	Events []ReleaseEvent `json:"events,omitempty"`
+
+	// Hash is "sha256:<hex>" over the release without its hash, set on
+	// every save and verified on load; see CORE_STATE_INTEGRITY.
+	Hash string `json:"hash,omitempty"`
+}
+
+// HostPhaseStatus is one host's outcome for a phase.
+type HostPhaseStatus struct {
+	Status PhaseStatus `json:"status"`
+
+	// Message explains the status, typically the error of a failed host.
+	Message string `json:"message,omitempty"`
+
+	// DurationMS is how long the phase ran on the host, in milliseconds.
+	DurationMS int64 `json:"duration_ms,omitempty"`
+}
+
+// FailedHosts returns the hosts whose phase failed or timed out, sorted.
+func (r *Release) FailedHosts(phase ReleasePhase) []string {
+	var hosts []string
+	for host, hs := range r.HostPhases[phase] {
+		if hs.Status == StatusFailed || hs.Status == StatusTimedOut {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// MigrationRecord is one migration shipped with a release.
+type MigrationRecord struct {
+	// Database is the databases key in stagecraft.yml.
+	Database string `json:"database"`
+
+	// ID is the engine's migration ID, e.g. the raw engine's file name.
+	ID string `json:"id"`
+
+	// Irreversible is set when the migration cannot be undone.
+	Irreversible bool `json:"irreversible,omitempty"`
+}
+
+// ServiceRollout records how one compose service was rolled out.
+type ServiceRollout struct {
+	// Service is the compose service name.
+	Service string `json:"service"`
+
+	// ConfigHash is the sha256 hex digest of the service's rendered compose
+	// fragment without its image (see compose.ServiceHashes).
+	ConfigHash string `json:"config_hash"`
+
+	// Image identifies the image the service ran: the registry digest or
+	// local image ID of built images, otherwise the image reference.
+	Image string `json:"image,omitempty"`
+
+	// Skipped is set when the rollout left the service running unchanged.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// BundleRef locates a stored deploy bundle.
+type BundleRef struct {
+	// Location is the bundle's path or object storage URL.
+	Location string `json:"location"`
+
+	// Digest is "sha256:<hex>" over the bundle archive.
+	Digest string `json:"digest"`
+}
+
+// BuiltImage records one image built for a release.
+type BuiltImage struct {
+	// Service is the backend service the image was built for.
+	Service string `json:"service"`
+
+	// Tag is the image reference the build produced.
+	Tag string `json:"tag"`
+
+	// ID is the local image ID ("sha256:<hex>"); empty when it could not
+	// be read.
+	ID string `json:"id,omitempty"`
+
+	// Digest is the registry manifest digest ("sha256:<hex>"), set once
+	// the image has been pushed. For multi-platform builds it is the
+	// digest of the manifest list.
+	Digest string `json:"digest,omitempty"`
+
+	// SBOM is the path of the image's SPDX SBOM, relative to the project
+	// root; empty unless build.sbom is enabled.
+	SBOM string `json:"sbom,omitempty"`
+
+	// Platforms lists the per-platform images of a multi-platform build,
+	// in build order. Empty for single-platform builds.
+	Platforms []PlatformImage `json:"platforms,omitempty"`
+}
+
+// PlatformImage records one platform's image of a multi-platform build.
+type PlatformImage struct {
+	// Platform is the target platform, e.g. "linux/arm64".
+	Platform string `json:"platform"`
+
+	// Tag is the platform-specific image reference.
+	Tag string `json:"tag"`
+
+	// ID is the local image ID; empty when it could not be read.
+	ID string `json:"id,omitempty"`
+
+	// Digest is the registry digest of this platform's image once pushed.
+	Digest string `json:"digest,omitempty"`
+
+	// SBOM is the path of this platform's SBOM; see BuiltImage.SBOM.
+	SBOM string `json:"sbom,omitempty"`
+}
+
+// PlanStep is the recorded fingerprint of one deployment plan step.
+type PlanStep struct {
+	// ID is the stable plan step ID.
+	ID string `json:"id"`
+
+	// Action is the engine step action (e.g., "build", "migrate").
+	Action string `json:"action"`
+
+	// InputsHash is "sha256:<hex>" over the step's canonical inputs.
+	InputsHash string `json:"inputs_hash"`
 }
 
 // stateFile represents the JSON structure of the state file.
 type stateFile struct {
+	// SchemaVersion is the file's schema version; see CurrentSchemaVersion.
+	SchemaVersion int `json:"schema_version"`
+
 	Releases []*Release `json:"releases"`
+
+	// Infra records provisioned infrastructure per environment.
+	Infra map[string]*EnvInfra `json:"infra,omitempty"`
+
+	// StagecraftVersion is the Stagecraft that last wrote the file, and
+	// RequiresStagecraft the oldest Stagecraft that may read it.
+	StagecraftVersion  string `json:"stagecraft_version,omitempty"`
+	RequiresStagecraft string `json:"requires_stagecraft,omitempty"`
+
+	// Checksum is "sha256:<hex>" over the document without its checksum.
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // Manager manages release state for Stagecraft deployments.
@@ -122,6 +291,14 @@ type Manager struct {
 	stateFile string
 	now       func() time.Time
 	mu        sync.Mutex
+
+	// allowDowngrade lets writes replace a state file whose schema is
+	// newer than CurrentSchemaVersion; see SetStrict.
+	allowDowngrade bool
+
+	// backupCount is how many previous versions each save keeps; see
+	// SetBackupCount.
+	backupCount int
 }
 
 // ErrReleaseNotFound is returned when a release is not found.
@@ -130,11 +307,22 @@ var ErrReleaseNotFound = errors.New("release not found")
 // NewManager creates a new state manager.
 func NewManager(stateFile string) *Manager {
 	return &Manager{
-		stateFile: stateFile,
-		now:       time.Now,
+		stateFile:   stateFile,
+		now:         time.Now,
+		backupCount: DefaultBackupCount,
 	}
 }
 
+// SetStrict controls whether the manager refuses to write a state file whose
+// schema version is newer than this build supports. Strict mode is the
+// default: writing would drop the fields this build does not know about.
+// Disabling it is only meant for recovering from a downgrade.
+func (m *Manager) SetStrict(strict bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.allowDowngrade = !strict
+}
+
 // NewDefaultManager creates a new state manager with the default state file path.
 // If STAGECRAFT_STATE_FILE environment variable is set, it uses that path instead.
 // The environment variable is read fresh on each call (no caching).
@@ -173,9 +361,54 @@ func cloneRelease(r *Release) *Release {
 		}
 	}
 
+	if r.HostPhases != nil {
+		clone.HostPhases = make(map[ReleasePhase]map[string]HostPhaseStatus, len(r.HostPhases))
+		for phase, hosts := range r.HostPhases {
+			clone.HostPhases[phase] = make(map[string]HostPhaseStatus, len(hosts))
+			for host, hs := range hosts {
+				clone.HostPhases[phase][host] = hs
+			}
+		}
+	}
+
+	if r.Plan != nil {
+		clone.Plan = append([]PlanStep(nil), r.Plan...)
+	}
+
+	if r.Images != nil {
+		clone.Images = cloneImages(r.Images)
+	}
+
+	if r.Services != nil {
+		clone.Services = append([]ServiceRollout(nil), r.Services...)
+	}
+
+	if r.Bundle != nil {
+		bundle := *r.Bundle
+		clone.Bundle = &bundle
+	}
+
+	if r.Migrations != nil {
+		clone.Migrations = append([]MigrationRecord(nil), r.Migrations...)
+	}
+	if r.Events != nil {
+		clone.Events = append([]ReleaseEvent(nil), r.Events...)
+	}
+
 	return &clone
 }
 
+// cloneImages deep-copies built images, including their platform images.
+func cloneImages(images []BuiltImage) []BuiltImage {
+	clone := append([]BuiltImage(nil), images...)
+	for i := range clone {
+		if clone[i].Platforms != nil {
+			clone[i].Platforms = append([]PlatformImage(nil), clone[i].Platforms...)
+		}
+	}
+	return clone
+}
+
 // isValidPhase checks if a phase is in the allowed set.
 func isValidPhase(phase ReleasePhase) bool {
 	for _, allowed := range allPhases {
@@ -203,19 +436,33 @@ func (m *Manager) loadState(ctx context.Context) (*stateFile, error) {
 		return nil, fmt.Errorf("reading state file: %w", err)
 	}
 
-	var state stateFile
-	if err := json.Unmarshal(data, &state); err != nil {
+	// Refuse files from a newer Stagecraft before they are misread
+	if !m.allowDowngrade {
+		if err := checkRequiredVersion(m.stateFile, data); err != nil {
+			return nil, err
+		}
+	}
+
+	state, from, problems, err := decodeState(data)
+	if err != nil {
 		return nil, fmt.Errorf("parsing state file: %w", err)
 	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s: %s; run `stagecraft state verify` for details or `stagecraft state repair` to recover",
+			ErrStateCorrupt, m.stateFile, problems[0])
+	}
 
-	// Ensure Phases map is initialized for each release
-	for _, release := range state.Releases {
-		if release.Phases == nil {
-			release.Phases = make(map[ReleasePhase]PhaseStatus)
+	// Persist upgrades right away, keeping the original next to the file
+	if from < CurrentSchemaVersion {
+		if err := writeBackup(m.stateFile, from, data); err != nil {
+			return nil, fmt.Errorf("upgrading state file from version %d: %w", from, err)
+		}
+		if err := m.saveState(ctx, state); err != nil {
+			return nil, fmt.Errorf("upgrading state file from version %d: %w", from, err)
 		}
 	}
 
-	return &state, nil
+	return state, nil
 }
 
 // saveState saves the state file atomically (write to temp, then rename).
@@ -227,6 +474,18 @@ func (m *Manager) saveState(ctx context.Context, state *stateFile) error {
 		return err
 	}
 
+	// Never silently downgrade a file written by a newer Stagecraft
+	if v := onDiskSchemaVersion(m.stateFile); v > CurrentSchemaVersion && !m.allowDowngrade {
+		return fmt.Errorf("%w: %s is at version %d, this build supports up to %d; upgrade stagecraft",
+			ErrSchemaTooNew, m.stateFile, v, CurrentSchemaVersion)
+	}
+	state.SchemaVersion = CurrentSchemaVersion
+	state.StagecraftVersion = buildinfo.Version()
+	state.RequiresStagecraft = RequiresVersion
+	if err := seal(state); err != nil {
+		return err
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(m.stateFile)
 	if err := os.MkdirAll(dir, 0o750); err != nil {
@@ -272,6 +531,11 @@ func (m *Manager) saveState(ctx context.Context, state *stateFile) error {
 		return fmt.Errorf("closing temporary state file: %w", err)
 	}
 
+	// Keep the current file before replacing it
+	if err := m.rotateBackups(); err != nil {
+		return err
+	}
+
 	// Atomically rename temp file to final location
 	if err := os.Rename(tmpPath, m.stateFile); err != nil {
 		return fmt.Errorf("renaming state file: %w", err)
@@ -475,13 +739,182 @@ func (m *Manager) UpdatePhase(ctx context.Context, releaseID string, phase Relea
 		release.Phases = make(map[ReleasePhase]PhaseStatus)
 	}
 
-	// Update phase status
+	// Record the transition in the timeline, then update phase status
+	if release.Phases[phase] != status {
+		if event, ok := phaseEvent(m.now(), phase, status); ok {
+			release.Events = append(release.Events, event)
+		}
+	}
 	release.Phases[phase] = status
 
 	// Save state
 	return m.saveState(ctx, state)
 }
 
+// UpdateHostPhase records one host's outcome for a deployment phase,
+// replacing any outcome recorded for that host earlier. It does not change
+// the phase's overall status.
+func (m *Manager) UpdateHostPhase(ctx context.Context, releaseID string, phase ReleasePhase, host string, status HostPhaseStatus) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if !isValidPhase(phase) {
+		return fmt.Errorf("unknown phase %q", phase)
+	}
+	if host == "" {
+		return fmt.Errorf("host is required")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	release := state.findReleaseByID(releaseID)
+	if release == nil {
+		return fmt.Errorf("%w: %q", ErrReleaseNotFound, releaseID)
+	}
+
+	if release.HostPhases == nil {
+		release.HostPhases = make(map[ReleasePhase]map[string]HostPhaseStatus)
+	}
+	if release.HostPhases[phase] == nil {
+		release.HostPhases[phase] = make(map[string]HostPhaseStatus)
+	}
+	release.HostPhases[phase][host] = status
+
+	return m.saveState(ctx, state)
+}
+
+// RecordPlan stores the deployment plan fingerprint for a release,
+// replacing any previously recorded plan.
+func (m *Manager) RecordPlan(ctx context.Context, releaseID string, steps []PlanStep) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	release := state.findReleaseByID(releaseID)
+	if release == nil {
+		return fmt.Errorf("%w: %q", ErrReleaseNotFound, releaseID)
+	}
+
+	release.Plan = append([]PlanStep(nil), steps...)
+
+	return m.saveState(ctx, state)
+}
+
+// RecordImages stores the images built for a release, replacing any
+// recorded earlier.
+func (m *Manager) RecordImages(ctx context.Context, releaseID string, images []BuiltImage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	release := state.findReleaseByID(releaseID)
+	if release == nil {
+		return fmt.Errorf("%w: %q", ErrReleaseNotFound, releaseID)
+	}
+
+	release.Images = cloneImages(images)
+
+	return m.saveState(ctx, state)
+}
+
+// RecordServices stores how the release rolled out each service,
+// replacing any recorded earlier.
+func (m *Manager) RecordServices(ctx context.Context, releaseID string, services []ServiceRollout) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	release := state.findReleaseByID(releaseID)
+	if release == nil {
+		return fmt.Errorf("%w: %q", ErrReleaseNotFound, releaseID)
+	}
+
+	release.Services = append([]ServiceRollout(nil), services...)
+
+	return m.saveState(ctx, state)
+}
+
+// RecordMigrations stores the migrations shipped with the release,
+// replacing any recorded earlier.
+func (m *Manager) RecordMigrations(ctx context.Context, releaseID string, migrations []MigrationRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	release := state.findReleaseByID(releaseID)
+	if release == nil {
+		return fmt.Errorf("%w: %q", ErrReleaseNotFound, releaseID)
+	}
+
+	release.Migrations = append([]MigrationRecord(nil), migrations...)
+
+	return m.saveState(ctx, state)
+}
+
+// RecordBundle stores where the release's deploy bundle was written,
+// replacing any bundle recorded earlier.
+func (m *Manager) RecordBundle(ctx context.Context, releaseID string, ref BundleRef) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	release := state.findReleaseByID(releaseID)
+	if release == nil {
+		return fmt.Errorf("%w: %q", ErrReleaseNotFound, releaseID)
+	}
+
+	release.Bundle = &ref
+
+	return m.saveState(ctx, state)
+}
+
 // ListReleases lists all releases for an environment, sorted newest first.
 // Returns read-only snapshots of the releases.
 func (m *Manager) ListReleases(ctx context.Context, env string) ([]*Release, error) {
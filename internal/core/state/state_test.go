@@ -19,6 +19,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -431,6 +432,231 @@ func TestManager_UpdatePhase_NotFound(t *testing.T) {
 	}
 }
 
+func TestManager_RecordPlan(t *testing.T) {
+	tmpDir := t.TempDir()
+	stateFile := filepath.Join(tmpDir, "releases.json")
+
+	mgr := newTestManager(stateFile)
+
+	release, err := mgr.CreateRelease(context.Background(), "prod", "v1.2.3", "abc123")
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+
+	steps := []PlanStep{{ID: "build-backend", Action: "build", InputsHash: "sha256:aa"}}
+	if err := mgr.RecordPlan(context.Background(), release.ID, steps); err != nil {
+		t.Fatalf("RecordPlan failed: %v", err)
+	}
+
+	// A fresh manager reads the plan back from disk
+	loaded, err := newTestManager(stateFile).GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if len(loaded.Plan) != 1 || loaded.Plan[0] != steps[0] {
+		t.Fatalf("expected recorded plan %v, got %v", steps, loaded.Plan)
+	}
+
+	// Snapshots must not share the plan slice
+	loaded.Plan[0].ID = "mutated"
+	again, err := mgr.GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if again.Plan[0].ID != "build-backend" {
+		t.Errorf("expected snapshot mutation not to leak, got %q", again.Plan[0].ID)
+	}
+
+	err = mgr.RecordPlan(context.Background(), "rel-nonexistent", steps)
+	if !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("expected ErrReleaseNotFound, got %v", err)
+	}
+}
+
+func TestManager_RecordImages(t *testing.T) {
+	tmpDir := t.TempDir()
+	stateFile := filepath.Join(tmpDir, "releases.json")
+
+	mgr := newTestManager(stateFile)
+
+	release, err := mgr.CreateRelease(context.Background(), "prod", "v1.2.3", "abc123")
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+
+	images := []BuiltImage{{
+		Service: "backend",
+		Tag:     "app:v1.2.3",
+		Digest:  "sha256:22",
+		Platforms: []PlatformImage{
+			{Platform: "linux/amd64", Tag: "app:v1.2.3-linux-amd64", ID: "sha256:31", Digest: "sha256:41"},
+			{Platform: "linux/arm64", Tag: "app:v1.2.3-linux-arm64", ID: "sha256:32", Digest: "sha256:42"},
+		},
+	}}
+	if err := mgr.RecordImages(context.Background(), release.ID, images); err != nil {
+		t.Fatalf("RecordImages failed: %v", err)
+	}
+
+	loaded, err := newTestManager(stateFile).GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Images, images) {
+		t.Fatalf("expected recorded images %v, got %v", images, loaded.Images)
+	}
+
+	loaded.Images[0].Tag = "mutated"
+	loaded.Images[0].Platforms[0].Digest = "mutated"
+	again, err := mgr.GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if again.Images[0].Tag != "app:v1.2.3" || again.Images[0].Platforms[0].Digest != "sha256:41" {
+		t.Errorf("expected snapshot mutation not to leak, got %+v", again.Images[0])
+	}
+
+	err = mgr.RecordImages(context.Background(), "rel-nonexistent", images)
+	if !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("expected ErrReleaseNotFound, got %v", err)
+	}
+}
+
+func TestManager_RecordMigrations(t *testing.T) {
+	tmpDir := t.TempDir()
+	stateFile := filepath.Join(tmpDir, "releases.json")
+
+	mgr := newTestManager(stateFile)
+
+	release, err := mgr.CreateRelease(context.Background(), "prod", "v1.2.3", "abc123")
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+
+	migrations := []MigrationRecord{
+		{Database: "main", ID: "001_users.sql"},
+		{Database: "main", ID: "002_drop_legacy.sql", Irreversible: true},
+	}
+	if err := mgr.RecordMigrations(context.Background(), release.ID, migrations); err != nil {
+		t.Fatalf("RecordMigrations failed: %v", err)
+	}
+
+	loaded, err := newTestManager(stateFile).GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Migrations, migrations) {
+		t.Fatalf("expected recorded migrations %v, got %v", migrations, loaded.Migrations)
+	}
+
+	loaded.Migrations[0].ID = "mutated"
+	again, err := mgr.GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if again.Migrations[0].ID != "001_users.sql" {
+		t.Errorf("expected snapshot mutation not to leak, got %+v", again.Migrations[0])
+	}
+
+	err = mgr.RecordMigrations(context.Background(), "rel-nonexistent", migrations)
+	if !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("expected ErrReleaseNotFound, got %v", err)
+	}
+}
+
+func TestManager_RecordBundle(t *testing.T) {
+	tmpDir := t.TempDir()
+	stateFile := filepath.Join(tmpDir, "releases.json")
+
+	mgr := newTestManager(stateFile)
+
+	release, err := mgr.CreateRelease(context.Background(), "prod", "v1.2.3", "abc123")
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+
+	ref := BundleRef{Location: "s3://releases/prod/" + release.ID + ".tar.gz", Digest: "sha256:51"}
+	if err := mgr.RecordBundle(context.Background(), release.ID, ref); err != nil {
+		t.Fatalf("RecordBundle failed: %v", err)
+	}
+
+	loaded, err := newTestManager(stateFile).GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if loaded.Bundle == nil || *loaded.Bundle != ref {
+		t.Fatalf("expected recorded bundle %v, got %v", ref, loaded.Bundle)
+	}
+
+	loaded.Bundle.Digest = "mutated"
+	again, err := mgr.GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if again.Bundle.Digest != "sha256:51" {
+		t.Errorf("expected snapshot mutation not to leak, got %+v", again.Bundle)
+	}
+
+	err = mgr.RecordBundle(context.Background(), "rel-nonexistent", ref)
+	if !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("expected ErrReleaseNotFound, got %v", err)
+	}
+}
+
+func TestManager_UpdateHostPhase(t *testing.T) {
+	tmpDir := t.TempDir()
+	stateFile := filepath.Join(tmpDir, "releases.json")
+
+	mgr := newTestManager(stateFile)
+
+	release, err := mgr.CreateRelease(context.Background(), "prod", "v1.2.3", "abc123")
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+
+	web1 := HostPhaseStatus{Status: StatusCompleted, DurationMS: 1200}
+	web2 := HostPhaseStatus{Status: StatusFailed, Message: "connection refused", DurationMS: 3400}
+	web3 := HostPhaseStatus{Status: StatusTimedOut, Message: "context deadline exceeded"}
+	for host, hs := range map[string]HostPhaseStatus{"web-1": web1, "web-2": web2, "web-3": web3} {
+		if err := mgr.UpdateHostPhase(context.Background(), release.ID, PhaseRollout, host, hs); err != nil {
+			t.Fatalf("UpdateHostPhase failed: %v", err)
+		}
+	}
+
+	loaded, err := newTestManager(stateFile).GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if got := loaded.HostPhases[PhaseRollout]["web-2"]; got != web2 {
+		t.Errorf("expected web-2 status %+v, got %+v", web2, got)
+	}
+	if got := loaded.FailedHosts(PhaseRollout); len(got) != 2 || got[0] != "web-2" || got[1] != "web-3" {
+		t.Errorf("expected failed hosts [web-2 web-3], got %v", got)
+	}
+	if loaded.Phases[PhaseRollout] != StatusPending {
+		t.Errorf("expected host updates to leave the phase status alone, got %q", loaded.Phases[PhaseRollout])
+	}
+
+	loaded.HostPhases[PhaseRollout]["web-1"] = HostPhaseStatus{Status: StatusFailed}
+	again, err := mgr.GetRelease(context.Background(), release.ID)
+	if err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if again.HostPhases[PhaseRollout]["web-1"] != web1 {
+		t.Errorf("expected snapshot mutation not to leak, got %+v", again.HostPhases[PhaseRollout]["web-1"])
+	}
+
+	if err := mgr.UpdateHostPhase(context.Background(), release.ID, ReleasePhase("bogus"), "web-1", web1); err == nil {
+		t.Errorf("expected an unknown phase to be rejected")
+	}
+	if err := mgr.UpdateHostPhase(context.Background(), release.ID, PhaseRollout, "", web1); err == nil {
+		t.Errorf("expected an empty host to be rejected")
+	}
+	err = mgr.UpdateHostPhase(context.Background(), "rel-nonexistent", PhaseRollout, "web-1", web1)
+	if !errors.Is(err, ErrReleaseNotFound) {
+		t.Errorf("expected ErrReleaseNotFound, got %v", err)
+	}
+}
+
 func TestManager_ListReleases(t *testing.T) {
 	tmpDir := t.TempDir()
 	stateFile := filepath.Join(tmpDir, "releases.json")
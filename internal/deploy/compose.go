@@ -17,14 +17,16 @@ See https://www.gnu.org/licenses/ for license details.
 package deploy
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"stagecraft/internal/compose"
+	"stagecraft/internal/envcrypt"
+	hostexec "stagecraft/internal/exec"
 	"stagecraft/pkg/config"
 )
 
@@ -34,30 +36,51 @@ import (
 // ComposeGenerator generates compose files for deployment environments.
 type ComposeGenerator struct {
 	loader    *compose.Loader
+	readFile  func(string) ([]byte, error)
 	writeFile func(string, []byte, os.FileMode) error
 	mkdirAll  func(string, os.FileMode) error
+
+	// staticAssetsDir is the frontend build served in frontend_static environments.
+	staticAssetsDir string
+
+	// serviceImages maps compose service names to per-service built images.
+	serviceImages map[string]string
+
+	// rendered holds every file the last Generate call produced, by path.
+	rendered map[string][]byte
 }
 
 // NewComposeGenerator creates a new compose generator.
 func NewComposeGenerator() *ComposeGenerator {
 	return &ComposeGenerator{
 		loader:    compose.NewLoader(),
+		readFile:  os.ReadFile,
 		writeFile: os.WriteFile,
 		mkdirAll:  os.MkdirAll,
 	}
 }
 
 // NewComposeGeneratorWithFS allows injecting file operations for tests.
+// The output file is always written through writeFn, since the injected
+// filesystem has no previous file to compare against.
 func NewComposeGeneratorWithFS(
 	writeFn func(string, []byte, os.FileMode) error,
 	mkdirFn func(string, os.FileMode) error,
 ) *ComposeGenerator {
 	g := NewComposeGenerator()
+	g.readFile = func(string) ([]byte, error) { return nil, os.ErrNotExist }
 	g.writeFile = writeFn
 	g.mkdirAll = mkdirFn
 	return g
 }
 
+// WithServiceImages sets the image for each named backend service. Services
+// not in the map keep receiving the built image tag passed to Generate.
+func (g *ComposeGenerator) WithServiceImages(images map[string]string) *ComposeGenerator {
+	g.serviceImages = images
+	return g
+}
+
 // Generate generates a compose file for the environment.
 // v1: single-host only, generates .stagecraft/rendered/<env>/docker-compose.yml
 func (g *ComposeGenerator) Generate(
@@ -67,6 +90,8 @@ func (g *ComposeGenerator) Generate(
 	builtImageTag string,
 	workdir string,
 ) (outputPath, hash string, err error) {
+	g.rendered = make(map[string][]byte)
+
 	// 1. Load base compose file
 	composeFile, err := g.loader.Load(baseComposePath)
 	if err != nil {
@@ -85,12 +110,31 @@ func (g *ComposeGenerator) Generate(
 		// Parse env file (graceful if missing - log debug, continue)
 		// #nosec G304 // path is user/config selected; intentional.
 		if data, err := os.ReadFile(filepath.Clean(envFilePath)); err == nil {
+			// CORE_ENV_ENCRYPTION: never render ciphertext into the compose file.
+			data, err = envcrypt.New().Decrypt(context.Background(), envFilePath, data)
+			if err != nil {
+				return "", "", fmt.Errorf("loading env_file: %w", err)
+			}
 			envVars = make(map[string]string)
 			parseEnvFileInto(envVars, data)
+			hostexec.Secrets.AddVars(envVars, hostexec.IsSecretKey)
 		}
 		// If file missing: no error, just continue without env vars
 	}
 
+	outputPath = RenderedComposePath(workdir, envName)
+
+	// Static frontend serving replaces (or adds) the frontend service with a
+	// web server container, so its config must exist before rendering.
+	var staticConfPath string
+	serveStatic := exists && envCfg.FrontendStatic != nil && g.staticAssetsDir != ""
+	if serveStatic {
+		staticConfPath, err = g.writeStaticServerConfig(filepath.Dir(outputPath), envCfg.FrontendStatic)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
 	// 3. Mutate compose file: inject image tags and merge env vars
 	// This preserves all fields (version, networks, volumes, configs, secrets, x-*)
 	err = composeFile.Mutate(func(data map[string]any) error {
@@ -101,6 +145,7 @@ func (g *ComposeGenerator) Generate(
 
 		// Get sorted service names for deterministic processing
 		serviceNames := composeFile.GetServices()
+		roles := composeFile.GetServiceRoles(cfg)
 
 		for _, svcName := range serviceNames {
 			svcData, ok := services[svcName].(map[string]any)
@@ -109,7 +154,18 @@ func (g *ComposeGenerator) Generate(
 			}
 
 			// Always set image (forces Stagecraft's built tag, even if build: exists)
-			svcData["image"] = builtImageTag
+			if image, ok := g.serviceImages[svcName]; ok {
+				svcData["image"] = image
+			} else {
+				svcData["image"] = builtImageTag
+			}
+
+			// Workers publish no ports and are labelled so rollout and
+			// health checks can treat them as background consumers.
+			if roles[svcName] == config.ServiceRoleWorker {
+				delete(svcData, "ports")
+				svcData["labels"] = withLabel(svcData["labels"], config.ServiceRoleLabel, config.ServiceRoleWorker)
+			}
 
 			// Merge env_file variables (existing env vars win)
 			if len(envVars) > 0 {
@@ -130,7 +186,16 @@ func (g *ComposeGenerator) Generate(
 			}
 		}
 
-		return nil
+		if serveStatic {
+			services[envCfg.FrontendStatic.ServiceName()] = staticService(envCfg.FrontendStatic, g.staticAssetsDir, staticConfPath)
+		}
+
+		// DEPLOY_TEMPLATES: render config files and mount them in services
+		if err := g.applyTemplates(services, cfg, envName, workdir); err != nil {
+			return err
+		}
+
+		return applyReplicas(services, envCfg)
 	})
 	if err != nil {
 		return "", "", fmt.Errorf("mutating compose file: %w", err)
@@ -142,23 +207,121 @@ func (g *ComposeGenerator) Generate(
 		return "", "", fmt.Errorf("marshaling compose file: %w", err)
 	}
 
-	// 5. Write to output path
-	outputPath = filepath.Join(workdir, ".stagecraft", "rendered", envName, "docker-compose.yml")
+	// 5. Create the output directory
 	if err := g.mkdirAll(filepath.Dir(outputPath), 0o750); err != nil {
 		return "", "", fmt.Errorf("creating output directory: %w", err)
 	}
 
-	if err := g.writeFile(outputPath, yamlBytes, 0o644); err != nil {
+	// 6. Write with the config-hash header; an unchanged file is left
+	// untouched so its mtime is preserved.
+	hash, _, err = compose.WriteFile(outputPath, yamlBytes, 0o644, g.readFile, g.writeFile)
+	if err != nil {
 		return "", "", fmt.Errorf("writing compose file: %w", err)
 	}
-
-	// 6. Compute hash of exact rendered bytes
-	hashBytes := sha256.Sum256(yamlBytes)
-	hash = hex.EncodeToString(hashBytes[:])
+	g.rendered[outputPath], _ = compose.WithHashHeader(yamlBytes)
 
 	return outputPath, hash, nil
 }
 
+// RenderedFiles returns the content of every file the last Generate call
+// produced, keyed by path, including files left untouched because they
+// were already up to date.
+func (g *ComposeGenerator) RenderedFiles() map[string][]byte {
+	files := make(map[string][]byte, len(g.rendered))
+	for path, data := range g.rendered {
+		files[path] = data
+	}
+	return files
+}
+
+// RenderedComposePath returns where Generate writes the compose file for env.
+func RenderedComposePath(workdir, envName string) string {
+	return filepath.Join(workdir, ".stagecraft", "rendered", envName, "docker-compose.yml")
+}
+
+// applyReplicas sets deploy.replicas for services scaled in the environment.
+// Replicated services cannot keep a fixed container_name or host port, since
+// every instance would claim it.
+func applyReplicas(services map[string]any, envCfg config.EnvironmentConfig) error {
+	names := make([]string, 0, len(envCfg.Services))
+	for name := range envCfg.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		svcData, ok := services[name].(map[string]any)
+		if !ok {
+			return fmt.Errorf("services.%s: service not found in compose file", name)
+		}
+
+		replicas := envCfg.ReplicasFor(name)
+		if replicas > 1 {
+			if hasFixedHostPort(svcData["ports"]) {
+				return fmt.Errorf("services.%s: cannot run %d replicas with a fixed host port; publish the container port only or route through a proxy", name, replicas)
+			}
+			delete(svcData, "container_name")
+		}
+
+		deployCfg, ok := svcData["deploy"].(map[string]any)
+		if !ok {
+			deployCfg = make(map[string]any)
+		}
+		deployCfg["replicas"] = replicas
+		svcData["deploy"] = deployCfg
+	}
+	return nil
+}
+
+// hasFixedHostPort reports whether any compose port entry binds a specific
+// host port ("8080:80", "127.0.0.1:8080:80" or published: 8080).
+func hasFixedHostPort(ports any) bool {
+	entries, ok := ports.([]any)
+	if !ok {
+		return false
+	}
+	for _, entry := range entries {
+		switch p := entry.(type) {
+		case string:
+			spec, _, _ := strings.Cut(p, "/")
+			if i := strings.LastIndex(spec, ":"); i >= 0 {
+				host := spec[:i]
+				if j := strings.LastIndex(host, ":"); j >= 0 {
+					host = host[j+1:]
+				}
+				if host != "" {
+					return true
+				}
+			}
+		case map[string]any:
+			if published, ok := p["published"]; ok && fmt.Sprint(published) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// withLabel sets a label on compose labels in either map or list form.
+func withLabel(labels any, key, value string) any {
+	switch l := labels.(type) {
+	case map[string]any:
+		l[key] = value
+		return l
+	case []any:
+		prefix := key + "="
+		for i, entry := range l {
+			if str, ok := entry.(string); ok && strings.HasPrefix(str, prefix) {
+				l[i] = prefix + value
+				return l
+			}
+		}
+		return append(l, prefix+value)
+	default:
+		return map[string]any{key: value}
+	}
+}
+
 // normalizeMap sorts map keys for deterministic output.
 func (g *ComposeGenerator) normalizeMap(m map[string]any) map[string]any {
 	if len(m) == 0 {
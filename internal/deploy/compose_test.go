@@ -18,9 +18,12 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"stagecraft/internal/compose"
+	hostexec "stagecraft/internal/exec"
 	"stagecraft/pkg/config"
 )
 
@@ -83,6 +86,56 @@ services:
 	}
 }
 
+func TestComposeGenerator_WritesHashHeaderAndSkipsUnchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	baseComposePath := filepath.Join(tmpDir, "docker-compose.yml")
+	if err := os.WriteFile(baseComposePath, []byte("services:\n  api:\n    image: myapp:latest\n"), 0o600); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+	cfg := &config.Config{
+		Environments: map[string]config.EnvironmentConfig{
+			"staging": {Driver: "local"},
+		},
+	}
+
+	writes := 0
+	generator := NewComposeGenerator()
+	generator.writeFile = func(path string, data []byte, perm os.FileMode) error {
+		writes++
+		return os.WriteFile(path, data, perm)
+	}
+
+	path, hash, err := generator.Generate(cfg, "staging", baseComposePath, "myapp:v1.0.0", tmpDir)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	stored, ok, err := compose.ReadHash(path)
+	if err != nil || !ok || stored != hash {
+		t.Fatalf("ReadHash() = %q, %v, %v; want header with %q", stored, ok, err, hash)
+	}
+	// #nosec G304 // path is test-controlled under TempDir.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := compose.ContentHash(data); got != hash {
+		t.Errorf("ContentHash() = %q, want %q", got, hash)
+	}
+
+	if _, again, err := generator.Generate(cfg, "staging", baseComposePath, "myapp:v1.0.0", tmpDir); err != nil || again != hash {
+		t.Fatalf("second Generate() = %q, %v; want same hash", again, err)
+	}
+	if writes != 1 {
+		t.Errorf("compose file written %d times, want 1", writes)
+	}
+
+	// The skipped write still reports the file as rendered.
+	if rendered := generator.RenderedFiles(); len(rendered) != 1 || !bytes.Equal(rendered[path], data) {
+		t.Errorf("RenderedFiles() = %v, want only %s", rendered, path)
+	}
+}
+
 func TestComposeGenerator_PreservesVolumesNetworksEtc(t *testing.T) {
 	tmpDir := t.TempDir()
 	baseComposePath := filepath.Join(tmpDir, "docker-compose.yml")
@@ -245,6 +298,203 @@ services:
 	}
 }
 
+func TestComposeGenerator_ServiceImages(t *testing.T) {
+	tmpDir := t.TempDir()
+	baseComposePath := filepath.Join(tmpDir, "docker-compose.yml")
+
+	composeContent := `services:
+  api:
+    image: old:tag
+  worker:
+    image: old:tag
+  db:
+    image: postgres:16
+`
+	if err := os.WriteFile(baseComposePath, []byte(composeContent), 0o600); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+
+	cfg := &config.Config{
+		Environments: map[string]config.EnvironmentConfig{
+			"staging": {Driver: "local"},
+		},
+	}
+
+	generator := NewComposeGenerator().WithServiceImages(map[string]string{
+		"api":    "myapp-api:v1",
+		"worker": "myapp-worker:v1",
+	})
+	outputPath, _, err := generator.Generate(cfg, "staging", baseComposePath, "myapp-api:v1", tmpDir)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	loaded, err := compose.NewLoader().Load(outputPath)
+	if err != nil {
+		t.Fatalf("loading output: %v", err)
+	}
+	for name, want := range map[string]string{"api": "myapp-api:v1", "worker": "myapp-worker:v1", "db": "myapp-api:v1"} {
+		if got := loaded.GetServiceData(name)["image"]; got != want {
+			t.Errorf("service %s image = %v, want %s", name, got, want)
+		}
+	}
+}
+
+func TestComposeGenerator_WorkerServices(t *testing.T) {
+	tmpDir := t.TempDir()
+	baseComposePath := filepath.Join(tmpDir, "docker-compose.yml")
+
+	composeContent := `services:
+  api:
+    image: old:tag
+    ports:
+      - "4000:4000"
+  jobs:
+    image: old:tag
+    ports:
+      - "4100:4100"
+    labels:
+      - "team=platform"
+`
+	if err := os.WriteFile(baseComposePath, []byte(composeContent), 0o600); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+
+	cfg := &config.Config{
+		Backend: &config.BackendConfig{
+			Services: map[string]config.BackendServiceConfig{
+				"api":  {Provider: "generic"},
+				"jobs": {Provider: "generic", Role: config.ServiceRoleWorker},
+			},
+		},
+		Environments: map[string]config.EnvironmentConfig{
+			"staging": {Driver: "local"},
+		},
+	}
+
+	outputPath, _, err := NewComposeGenerator().Generate(cfg, "staging", baseComposePath, "myapp:v1", tmpDir)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	loaded, err := compose.NewLoader().Load(outputPath)
+	if err != nil {
+		t.Fatalf("loading output: %v", err)
+	}
+
+	if _, ok := loaded.GetServiceData("api")["ports"]; !ok {
+		t.Error("api ports should be kept")
+	}
+
+	jobs := loaded.GetServiceData("jobs")
+	if _, ok := jobs["ports"]; ok {
+		t.Errorf("worker ports should be removed, got %v", jobs["ports"])
+	}
+	labels, _ := jobs["labels"].([]any)
+	want := []any{"team=platform", config.ServiceRoleLabel + "=" + config.ServiceRoleWorker}
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("worker labels = %v, want %v", labels, want)
+	}
+}
+
+func TestComposeGenerator_Replicas(t *testing.T) {
+	tmpDir := t.TempDir()
+	baseComposePath := filepath.Join(tmpDir, "docker-compose.yml")
+
+	composeContent := `services:
+  api:
+    image: old:tag
+    container_name: api
+    ports:
+      - "4000"
+    deploy:
+      resources:
+        limits:
+          memory: 256m
+  db:
+    image: postgres:16
+`
+	if err := os.WriteFile(baseComposePath, []byte(composeContent), 0o600); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+
+	cfg := &config.Config{
+		Environments: map[string]config.EnvironmentConfig{
+			"prod": {
+				Driver:   "local",
+				Services: map[string]config.ServiceScaleConfig{"api": {Replicas: 3}},
+			},
+		},
+	}
+
+	outputPath, _, err := NewComposeGenerator().Generate(cfg, "prod", baseComposePath, "myapp:v1", tmpDir)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	loaded, err := compose.NewLoader().Load(outputPath)
+	if err != nil {
+		t.Fatalf("loading output: %v", err)
+	}
+
+	api := loaded.GetServiceData("api")
+	deployCfg, _ := api["deploy"].(map[string]any)
+	if deployCfg["replicas"] != 3 {
+		t.Errorf("api deploy.replicas = %v, want 3", deployCfg["replicas"])
+	}
+	if _, ok := deployCfg["resources"]; !ok {
+		t.Error("existing deploy settings should be preserved")
+	}
+	if _, ok := api["container_name"]; ok {
+		t.Error("container_name should be removed from replicated services")
+	}
+	if _, ok := loaded.GetServiceData("db")["deploy"]; ok {
+		t.Error("unscaled services should not get a deploy section")
+	}
+}
+
+func TestComposeGenerator_ReplicasRejectFixedHostPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		ports   string
+		wantErr bool
+	}{
+		{name: "host and container", ports: `"8080:80"`, wantErr: true},
+		{name: "host ip", ports: `"127.0.0.1:8080:80/tcp"`, wantErr: true},
+		{name: "long syntax", ports: `{target: 80, published: 8080}`, wantErr: true},
+		{name: "container only", ports: `"80"`, wantErr: false},
+		{name: "ephemeral host port", ports: `"127.0.0.1::80"`, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			baseComposePath := filepath.Join(tmpDir, "docker-compose.yml")
+			composeContent := "services:\n  api:\n    image: old:tag\n    ports:\n      - " + tt.ports + "\n"
+			if err := os.WriteFile(baseComposePath, []byte(composeContent), 0o600); err != nil {
+				t.Fatalf("failed to write compose file: %v", err)
+			}
+
+			cfg := &config.Config{
+				Environments: map[string]config.EnvironmentConfig{
+					"prod": {
+						Driver:   "local",
+						Services: map[string]config.ServiceScaleConfig{"api": {Replicas: 2}},
+					},
+				},
+			}
+
+			_, _, err := NewComposeGenerator().Generate(cfg, "prod", baseComposePath, "myapp:v1", tmpDir)
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "fixed host port")) {
+				t.Fatalf("expected fixed host port error, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+		})
+	}
+}
+
 func TestComposeGenerator_EnvFileMerging(t *testing.T) {
 	tmpDir := t.TempDir()
 	baseComposePath := filepath.Join(tmpDir, "docker-compose.yml")
@@ -263,6 +513,7 @@ services:
 
 	envFileContent := `NEW_VAR=new_value
 EXISTING_VAR=should_not_override
+API_TOKEN=compose-env-file-token
 `
 	if err := os.WriteFile(envFilePath, []byte(envFileContent), 0o600); err != nil {
 		t.Fatalf("failed to write env file: %v", err)
@@ -311,6 +562,11 @@ EXISTING_VAR=should_not_override
 	if strings.Contains(outputStr, "EXISTING_VAR: should_not_override") {
 		t.Error("Generated compose file incorrectly overrode existing environment variable")
 	}
+
+	// Verify secret env_file values are masked in logged commands
+	if got := hostexec.Secrets.Redact("compose-env-file-token"); got != hostexec.RedactedValue {
+		t.Errorf("Redact() = %q, want the env_file secret masked", got)
+	}
 }
 
 func TestComposeGenerator_MissingEnvFile(t *testing.T) {
@@ -348,3 +604,75 @@ services:
 		t.Fatalf("Generate should not fail on missing env file: %v", err)
 	}
 }
+
+func TestComposeGenerator_Templates(t *testing.T) {
+	tmpDir := t.TempDir()
+	baseComposePath := filepath.Join(tmpDir, "docker-compose.yml")
+
+	composeContent := `services:
+  web:
+    image: nginx:1.27
+    environment:
+      - UPSTREAM=api:4000
+    volumes:
+      - static:/srv
+`
+	if err := os.WriteFile(baseComposePath, []byte(composeContent), 0o600); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+	tmpl := `# {{ .Project }}/{{ .Environment }}/{{ .Service }} ({{ .Image }})
+upstream {{ .Env.UPSTREAM }};
+workers {{ .Vars.workers }};
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "nginx.conf.tmpl"), []byte(tmpl), 0o600); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	cfg := &config.Config{
+		Project: config.ProjectConfig{Name: "myapp"},
+		Templates: []config.TemplateConfig{{
+			Source:  "nginx.conf.tmpl",
+			Service: "web",
+			Target:  "/etc/nginx/nginx.conf",
+			Vars:    map[string]string{"workers": "2"},
+		}},
+		Environments: map[string]config.EnvironmentConfig{
+			"prod": {Driver: "local", TemplateVars: map[string]string{"workers": "8"}},
+		},
+	}
+
+	outputPath, _, err := NewComposeGenerator().Generate(cfg, "prod", baseComposePath, "myapp:v1", tmpDir)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	renderedPath := filepath.Join(TemplatesDir(tmpDir, "prod"), "web", "nginx.conf")
+	got, err := os.ReadFile(renderedPath)
+	if err != nil {
+		t.Fatalf("reading rendered template: %v", err)
+	}
+	want := "# myapp/prod/web (myapp:v1)\nupstream api:4000;\nworkers 8;\n"
+	if string(got) != want {
+		t.Errorf("rendered template = %q, want %q", got, want)
+	}
+
+	loaded, err := compose.NewLoader().Load(outputPath)
+	if err != nil {
+		t.Fatalf("loading output: %v", err)
+	}
+	web := loaded.GetServiceData("web")
+	wantVolumes := []any{"static:/srv", renderedPath + ":/etc/nginx/nginx.conf:ro"}
+	if !reflect.DeepEqual(web["volumes"], wantVolumes) {
+		t.Errorf("web volumes = %v, want %v", web["volumes"], wantVolumes)
+	}
+	labels, _ := web["labels"].(map[string]any)
+	if hash, _ := labels[TemplatesHashLabel].(string); len(hash) != 64 {
+		t.Errorf("web label %s = %q, want a sha256 hex digest", TemplatesHashLabel, hash)
+	}
+
+	cfg.Templates[0].Service = "api"
+	if _, _, err := NewComposeGenerator().Generate(cfg, "prod", baseComposePath, "myapp:v1", tmpDir); err == nil ||
+		!strings.Contains(err.Error(), `service "api" not found`) {
+		t.Errorf("expected missing service error, got %v", err)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"context"
 	"fmt"
 
+	"stagecraft/internal/containerruntime"
 	"stagecraft/pkg/executil"
 )
 
@@ -28,23 +29,32 @@ const RolloutNotInstalledMessage = "docker-rollout is required but not installed
 
 // RolloutExecutor executes docker-rollout deployments.
 type RolloutExecutor struct {
-	runner executil.Runner
+	runner  executil.Runner
+	runtime containerruntime.Runtime
 }
 
 // NewRolloutExecutor creates a new rollout executor.
 func NewRolloutExecutor() *RolloutExecutor {
 	return &RolloutExecutor{
-		runner: executil.NewRunner(),
+		runner:  executil.NewRunner(),
+		runtime: containerruntime.Docker,
 	}
 }
 
 // NewRolloutExecutorWithRunner allows injecting runner for tests.
 func NewRolloutExecutorWithRunner(runner executil.Runner) *RolloutExecutor {
 	return &RolloutExecutor{
-		runner: runner,
+		runner:  runner,
+		runtime: containerruntime.Docker,
 	}
 }
 
+// WithRuntime sets the container runtime used to recreate workers.
+func (e *RolloutExecutor) WithRuntime(runtime containerruntime.Runtime) *RolloutExecutor {
+	e.runtime = runtime
+	return e
+}
+
 // IsAvailable checks if docker-rollout is installed.
 // Returns (available, error).
 // Error is returned only for context cancellation/deadline exceeded.
@@ -67,7 +77,41 @@ func (e *RolloutExecutor) IsAvailable(ctx context.Context) (bool, error) {
 
 // Execute runs docker-rollout up.
 func (e *RolloutExecutor) Execute(ctx context.Context, composePath string) error {
-	cmd := executil.NewCommand("docker-rollout", "up", "-f", composePath)
+	return e.run(ctx, executil.NewCommand("docker-rollout", "up", "-f", composePath))
+}
+
+// ExecuteWithWorkers rolls out services with docker-rollout, then recreates
+// workers with docker compose. Workers take no traffic, so running old and
+// new consumers side by side buys nothing; they are replaced once the
+// services they depend on are up. Workers are recreated with the
+// executor's container runtime.
+func (e *RolloutExecutor) ExecuteWithWorkers(ctx context.Context, composePath string, services, workers []string) error {
+	if len(services) > 0 {
+		args := append([]string{"up", "-f", composePath}, services...)
+		if err := e.run(ctx, executil.NewCommand("docker-rollout", args...)); err != nil {
+			return err
+		}
+	}
+	if len(workers) == 0 {
+		return nil
+	}
+
+	args := append([]string{"-f", composePath, "up", "-d", "--no-deps"}, workers...)
+	result, err := e.runner.Run(ctx, e.runtime.ComposeCommand(args...))
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("recreating workers: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("recreating workers failed with exit code %d: %s",
+			result.ExitCode, string(result.Stderr))
+	}
+	return nil
+}
+
+func (e *RolloutExecutor) run(ctx context.Context, cmd executil.Command) error {
 	result, err := e.runner.Run(ctx, cmd)
 
 	if ctx.Err() != nil {
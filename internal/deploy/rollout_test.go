@@ -178,3 +178,46 @@ func TestRolloutExecutor_Execute_ContextCancelled(t *testing.T) {
 		t.Errorf("Error should be context.Canceled, got: %v", err)
 	}
 }
+
+func TestRolloutExecutor_ExecuteWithWorkers_RecreatesWorkersAfterRollout(t *testing.T) {
+	var calls []string
+	mock := &mockRunner{
+		runFunc: func(ctx context.Context, cmd executil.Command) (*executil.Result, error) {
+			calls = append(calls, cmd.Name+" "+strings.Join(cmd.Args, " "))
+			return &executil.Result{ExitCode: 0}, nil
+		},
+	}
+
+	executor := NewRolloutExecutorWithRunner(mock)
+	err := executor.ExecuteWithWorkers(context.Background(), "/path/to/compose.yml", []string{"api", "db"}, []string{"jobs"})
+	if err != nil {
+		t.Fatalf("ExecuteWithWorkers returned error: %v", err)
+	}
+
+	want := []string{
+		"docker-rollout up -f /path/to/compose.yml api db",
+		"docker compose -f /path/to/compose.yml up -d --no-deps jobs",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands = %q, want %q", calls, want)
+	}
+}
+
+func TestRolloutExecutor_ExecuteWithWorkers_StopsOnRolloutFailure(t *testing.T) {
+	var calls int
+	mock := &mockRunner{
+		runFunc: func(ctx context.Context, cmd executil.Command) (*executil.Result, error) {
+			calls++
+			return &executil.Result{ExitCode: 1, Stderr: []byte("rollout failed")}, nil
+		},
+	}
+
+	executor := NewRolloutExecutorWithRunner(mock)
+	err := executor.ExecuteWithWorkers(context.Background(), "/path/to/compose.yml", []string{"api"}, []string{"jobs"})
+	if err == nil || !strings.Contains(err.Error(), "rollout failed") {
+		t.Fatalf("expected rollout error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("workers should not be recreated after a failed rollout, got %d commands", calls)
+	}
+}
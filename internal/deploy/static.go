@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package deploy
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"stagecraft/pkg/config"
+)
+
+// Feature: DEPLOY_FRONTEND_STATIC
+// Spec: spec/deploy/frontend-static.md
+
+// nginxStaticConf serves the build with a history-API fallback to index.html.
+const nginxStaticConf = `server {
+    listen 80;
+    root /usr/share/nginx/html;
+
+    location / {
+        try_files $uri $uri/ /index.html;
+    }
+}
+`
+
+// caddyStaticConf serves the build with a history-API fallback to index.html.
+const caddyStaticConf = `:80 {
+	root * /srv
+	try_files {path} /index.html
+	file_server
+}
+`
+
+// WithStaticAssets makes Generate serve assetsDir from a web server container
+// in environments that set frontend_static. An empty dir disables it.
+func (g *ComposeGenerator) WithStaticAssets(assetsDir string) *ComposeGenerator {
+	g.staticAssetsDir = assetsDir
+	return g
+}
+
+// writeStaticServerConfig writes the web server config next to the rendered
+// compose file and returns its path.
+func (g *ComposeGenerator) writeStaticServerConfig(renderDir string, static *config.StaticConfig) (string, error) {
+	name, content := "nginx.conf", nginxStaticConf
+	if static.Server == config.StaticServerCaddy {
+		name, content = "Caddyfile", caddyStaticConf
+	}
+
+	path := filepath.Join(renderDir, "static", name)
+	if err := g.mkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", fmt.Errorf("creating static server config directory: %w", err)
+	}
+	// #nosec G306 -- the web server container reads this file as a non-root user
+	if err := g.writeFile(path, []byte(content), 0o644); err != nil {
+		return "", fmt.Errorf("writing static server config: %w", err)
+	}
+	g.rendered[path] = []byte(content)
+	return path, nil
+}
+
+// staticService returns the compose service that serves assetsDir with the
+// configured server, using confPath as its config.
+func staticService(static *config.StaticConfig, assetsDir, confPath string) map[string]any {
+	var volumes []any
+	svc := map[string]any{
+		"image":   static.ServerImage(),
+		"restart": "unless-stopped",
+	}
+
+	switch static.Server {
+	case config.StaticServerCaddy:
+		volumes = []any{
+			assetsDir + ":/srv:ro",
+			confPath + ":/etc/caddy/Caddyfile:ro",
+		}
+	default:
+		volumes = []any{
+			assetsDir + ":/usr/share/nginx/html:ro",
+			confPath + ":/etc/nginx/conf.d/default.conf:ro",
+		}
+	}
+	svc["volumes"] = volumes
+
+	if static.Port > 0 {
+		svc["ports"] = []any{fmt.Sprintf("%d:80", static.Port)}
+	}
+	return svc
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"stagecraft/pkg/config"
+)
+
+// Feature: DEPLOY_FRONTEND_STATIC
+// Spec: spec/deploy/frontend-static.md
+
+func generateWithStatic(t *testing.T, static *config.StaticConfig, assetsDir string) (map[string]any, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	baseComposePath := filepath.Join(tmpDir, "docker-compose.yml")
+
+	composeContent := `services:
+  api:
+    build:
+      context: .
+  frontend:
+    image: node:22
+    command: ["npm", "run", "dev"]
+`
+	if err := os.WriteFile(baseComposePath, []byte(composeContent), 0o600); err != nil {
+		t.Fatalf("failed to write compose file: %v", err)
+	}
+
+	cfg := &config.Config{
+		Environments: map[string]config.EnvironmentConfig{
+			"prod": {Driver: "local", FrontendStatic: static},
+		},
+	}
+
+	outputPath, _, err := NewComposeGenerator().WithStaticAssets(assetsDir).Generate(cfg, "prod", baseComposePath, "myapp:v1", tmpDir)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	// #nosec G304 // path is test-controlled under TempDir.
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	var rendered map[string]any
+	if err := yaml.Unmarshal(data, &rendered); err != nil {
+		t.Fatalf("parsing rendered compose: %v", err)
+	}
+	return rendered["services"].(map[string]any), filepath.Dir(outputPath)
+}
+
+func TestComposeGenerator_StaticNginxReplacesFrontendService(t *testing.T) {
+	services, renderDir := generateWithStatic(t, &config.StaticConfig{Server: config.StaticServerNginx, Port: 8080}, "/srv/app/dist")
+
+	frontend := services["frontend"].(map[string]any)
+	if frontend["image"] != "nginx:1.27-alpine" {
+		t.Errorf("expected nginx image, got %v", frontend["image"])
+	}
+	if _, ok := frontend["command"]; ok {
+		t.Error("expected dev server command to be dropped")
+	}
+
+	confPath := filepath.Join(renderDir, "static", "nginx.conf")
+	volumes := frontend["volumes"].([]any)
+	if volumes[0] != "/srv/app/dist:/usr/share/nginx/html:ro" || volumes[1] != confPath+":/etc/nginx/conf.d/default.conf:ro" {
+		t.Errorf("unexpected volumes: %v", volumes)
+	}
+	if ports := frontend["ports"].([]any); ports[0] != "8080:80" {
+		t.Errorf("unexpected ports: %v", ports)
+	}
+
+	conf, err := os.ReadFile(confPath) // #nosec G304 -- test-controlled path
+	if err != nil {
+		t.Fatalf("reading nginx config: %v", err)
+	}
+	if !strings.Contains(string(conf), "try_files $uri $uri/ /index.html") {
+		t.Errorf("expected SPA fallback in nginx config, got:\n%s", conf)
+	}
+
+	if api := services["api"].(map[string]any); api["image"] != "myapp:v1" {
+		t.Errorf("expected backend image injection to be unchanged, got %v", api["image"])
+	}
+}
+
+func TestComposeGenerator_StaticCaddyCustomService(t *testing.T) {
+	services, renderDir := generateWithStatic(t, &config.StaticConfig{Server: config.StaticServerCaddy, Service: "web"}, "/srv/app/dist")
+
+	web, ok := services["web"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected web service, got %v", services)
+	}
+	if web["image"] != "caddy:2-alpine" {
+		t.Errorf("expected caddy image, got %v", web["image"])
+	}
+	if _, ok := web["ports"]; ok {
+		t.Error("expected no published ports without a port")
+	}
+	volumes := web["volumes"].([]any)
+	if volumes[1] != filepath.Join(renderDir, "static", "Caddyfile")+":/etc/caddy/Caddyfile:ro" {
+		t.Errorf("unexpected volumes: %v", volumes)
+	}
+
+	// The original frontend service is left alone when a different name is configured.
+	if frontend := services["frontend"].(map[string]any); frontend["image"] != "myapp:v1" {
+		t.Errorf("unexpected frontend service: %v", frontend)
+	}
+}
+
+func TestComposeGenerator_StaticRequiresAssets(t *testing.T) {
+	services, renderDir := generateWithStatic(t, &config.StaticConfig{Server: config.StaticServerNginx}, "")
+
+	if frontend := services["frontend"].(map[string]any); frontend["image"] != "myapp:v1" {
+		t.Errorf("expected no static service without assets, got %v", frontend)
+	}
+	if _, err := os.Stat(filepath.Join(renderDir, "static")); !os.IsNotExist(err) {
+		t.Errorf("expected no static config to be written, stat err = %v", err)
+	}
+}
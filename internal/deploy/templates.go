@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package deploy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"stagecraft/internal/templates"
+	"stagecraft/pkg/config"
+)
+
+// Feature: DEPLOY_TEMPLATES
+// Spec: spec/deploy/templates.md
+
+// TemplatesHashLabel carries a hash of the files rendered into a service,
+// so compose recreates its containers when only a rendered file changed.
+const TemplatesHashLabel = "stagecraft.templates-hash"
+
+// TemplatesDir returns where Generate writes env's rendered templates.
+func TemplatesDir(workdir, envName string) string {
+	return filepath.Join(workdir, ".stagecraft", envName, "templates")
+}
+
+// applyTemplates renders the environment's templates and mounts each one
+// read-only in its service. Services are processed in name order and
+// templates in target order, so output does not depend on config order.
+func (g *ComposeGenerator) applyTemplates(
+	services map[string]any,
+	cfg *config.Config,
+	envName string,
+	workdir string,
+) error {
+	byService := make(map[string][]config.TemplateConfig)
+	for _, t := range cfg.TemplatesFor(envName) {
+		byService[t.Service] = append(byService[t.Service], t)
+	}
+
+	names := make([]string, 0, len(byService))
+	for name := range byService {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	envCfg := cfg.Environments[envName]
+	for _, name := range names {
+		svcData, ok := services[name].(map[string]any)
+		if !ok {
+			return fmt.Errorf("templates: service %q not found in compose file", name)
+		}
+
+		tmpls := byService[name]
+		sort.Slice(tmpls, func(i, j int) bool { return tmpls[i].Target < tmpls[j].Target })
+
+		image, _ := svcData["image"].(string)
+		env := serviceEnvironment(svcData["environment"])
+		volumes, _ := svcData["volumes"].([]any)
+		hash := sha256.New()
+
+		for _, t := range tmpls {
+			source := t.Source
+			if !filepath.IsAbs(source) {
+				source = filepath.Join(workdir, source)
+			}
+			content, err := templates.RenderFile(source, templates.Data{
+				Project:     cfg.Project.Name,
+				Environment: envName,
+				Service:     name,
+				Image:       image,
+				Env:         env,
+				Vars:        templateVars(t.Vars, envCfg.TemplateVars),
+			})
+			if err != nil {
+				return fmt.Errorf("templates: %s: %w", t.Source, err)
+			}
+
+			path := filepath.Join(TemplatesDir(workdir, envName), name, t.FileName())
+			if err := g.writeRenderedTemplate(path, content); err != nil {
+				return fmt.Errorf("templates: %s: %w", t.Source, err)
+			}
+
+			volumes = append(volumes, path+":"+t.Target+":ro")
+			_, _ = fmt.Fprintf(hash, "%s\x00%d\x00", t.Target, len(content))
+			_, _ = hash.Write(content)
+		}
+
+		svcData["volumes"] = volumes
+		svcData["labels"] = withLabel(svcData["labels"], TemplatesHashLabel, hex.EncodeToString(hash.Sum(nil)))
+	}
+	return nil
+}
+
+// writeRenderedTemplate writes a rendered template unless the file already
+// holds exactly that content, preserving its mtime.
+func (g *ComposeGenerator) writeRenderedTemplate(path string, content []byte) error {
+	g.rendered[path] = content
+
+	existing, err := g.readFile(path)
+	switch {
+	case err == nil && bytes.Equal(existing, content):
+		return nil
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	if err := g.mkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("creating template output directory: %w", err)
+	}
+	// #nosec G306 -- mounted read-only into containers that may run as non-root
+	if err := g.writeFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// serviceEnvironment returns a compose service's environment in either map
+// or list form as a map. Variables listed without a value are omitted, as
+// compose takes them from the shell at run time.
+func serviceEnvironment(environment any) map[string]string {
+	env := make(map[string]string)
+	switch e := environment.(type) {
+	case map[string]any:
+		for k, v := range e {
+			if v == nil {
+				env[k] = ""
+				continue
+			}
+			env[k] = fmt.Sprint(v)
+		}
+	case []any:
+		for _, entry := range e {
+			s, ok := entry.(string)
+			if !ok {
+				continue
+			}
+			if k, v, ok := strings.Cut(s, "="); ok {
+				env[k] = v
+			}
+		}
+	}
+	return env
+}
+
+// templateVars overlays the environment's template_vars on a template's vars.
+func templateVars(vars, envVars map[string]string) map[string]string {
+	merged := make(map[string]string, len(vars)+len(envVars))
+	for k, v := range vars {
+		merged[k] = v
+	}
+	for k, v := range envVars {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Feature: CLI_BUILD
+Specs:
+  - spec/commands/build.md
+*/
+
+package dev
+
+import (
+	"path/filepath"
+
+	devcompose "stagecraft/internal/dev/compose"
+
+	"stagecraft/pkg/config"
+)
+
+// projectRootFromDevDir is the project root relative to DefaultDir, where
+// the dev compose file lives. Docker Compose resolves relative paths
+// against the compose file's directory.
+var projectRootFromDevDir = filepath.ToSlash(relOrSelf(DefaultDir, "."))
+
+// devBuild returns the compose build section and build secrets for a
+// backend service, carrying the environment's build args, target stage,
+// and secrets. The build context and Dockerfile follow the generic
+// provider's build.context and build.dockerfile, both relative to the
+// project root, and default to the service's workdir and its Dockerfile.
+func devBuild(svc config.BackendService, providerCfg any, build *config.BuildConfig) (map[string]any, []devcompose.BuildSecret) {
+	buildContext := svc.WorkDir
+	var dockerfile string
+	if cfgMap, ok := providerCfg.(map[string]any); ok {
+		if buildCfg, ok := cfgMap["build"].(map[string]any); ok {
+			if v, ok := buildCfg["context"].(string); ok && v != "" {
+				buildContext = v
+			}
+			if v, ok := buildCfg["dockerfile"].(string); ok && v != "" {
+				dockerfile = v
+			}
+		}
+	}
+	if buildContext == "" {
+		buildContext = "."
+	}
+
+	result := map[string]any{"context": fromDevDir(buildContext)}
+	if dockerfile != "" {
+		if !filepath.IsAbs(dockerfile) && !filepath.IsAbs(buildContext) {
+			dockerfile = relOrSelf(buildContext, dockerfile)
+		}
+		result["dockerfile"] = filepath.ToSlash(dockerfile)
+	}
+	if build.Target != "" {
+		result["target"] = build.Target
+	}
+	if len(build.Args) > 0 {
+		args := make(map[string]any, len(build.Args))
+		for name, value := range build.Args {
+			args[name] = value
+		}
+		result["args"] = args
+	}
+
+	var secrets []devcompose.BuildSecret
+	if len(build.Secrets) > 0 {
+		ids := make([]any, 0, len(build.Secrets))
+		for _, secret := range build.Secrets {
+			ids = append(ids, secret.ID)
+			s := devcompose.BuildSecret{ID: secret.ID, Environment: secret.Env}
+			if secret.Src != "" {
+				s.File = fromDevDir(secret.Src)
+			}
+			secrets = append(secrets, s)
+		}
+		result["secrets"] = ids
+	}
+
+	return result, secrets
+}
+
+// fromDevDir returns a project-relative path relative to DefaultDir.
+// Absolute paths are returned unchanged.
+func fromDevDir(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.ToSlash(filepath.Join(projectRootFromDevDir, path))
+}
+
+// relOrSelf returns target relative to base, or target when it cannot be
+// made relative.
+func relOrSelf(base, target string) string {
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		return target
+	}
+	return rel
+}
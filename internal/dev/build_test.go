@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package dev
+
+import (
+	"reflect"
+	"testing"
+
+	devcompose "stagecraft/internal/dev/compose"
+
+	"stagecraft/pkg/config"
+)
+
+// Feature: CLI_BUILD
+// Spec: spec/commands/build.md
+
+func TestDevBuild(t *testing.T) {
+	build := &config.BuildConfig{
+		Target: "dev",
+		Args:   map[string]string{"NODE_ENV": "development"},
+		Secrets: []config.BuildSecret{
+			{ID: "npmrc", Src: ".npmrc"},
+			{ID: "gh_token", Env: "GITHUB_TOKEN"},
+		},
+	}
+	providerCfg := map[string]any{
+		"build": map[string]any{"context": "services/api", "dockerfile": "services/api/Dockerfile.dev"},
+	}
+
+	got, secrets := devBuild(config.BackendService{Name: "api"}, providerCfg, build)
+
+	want := map[string]any{
+		"context":    "../../services/api",
+		"dockerfile": "Dockerfile.dev",
+		"target":     "dev",
+		"args":       map[string]any{"NODE_ENV": "development"},
+		"secrets":    []any{"npmrc", "gh_token"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("devBuild() = %v, want %v", got, want)
+	}
+	wantSecrets := []devcompose.BuildSecret{
+		{ID: "npmrc", File: "../../.npmrc"},
+		{ID: "gh_token", Environment: "GITHUB_TOKEN"},
+	}
+	if !reflect.DeepEqual(secrets, wantSecrets) {
+		t.Errorf("devBuild() secrets = %+v, want %+v", secrets, wantSecrets)
+	}
+}
+
+func TestDevBuild_DefaultsToServiceWorkDir(t *testing.T) {
+	svc := config.BackendService{Name: "api", BackendServiceConfig: config.BackendServiceConfig{WorkDir: "api"}}
+
+	got, secrets := devBuild(svc, nil, &config.BuildConfig{Target: "dev"})
+
+	want := map[string]any{"context": "../../api", "target": "dev"}
+	if !reflect.DeepEqual(got, want) || secrets != nil {
+		t.Errorf("devBuild() = %v, %v, want %v and no secrets", got, secrets, want)
+	}
+}
@@ -15,6 +15,7 @@ package compose
 
 import (
 	"errors"
+	"fmt"
 	"sort"
 	"strconv"
 
@@ -74,9 +75,25 @@ func (g *Generator) GenerateCompose(
 	backendService *ServiceDefinition,
 	frontendService *ServiceDefinition,
 	traefikService *ServiceDefinition,
+) (*corecompose.ComposeFile, error) {
+	var backends []*ServiceDefinition
+	if backendService != nil {
+		backends = []*ServiceDefinition{backendService}
+	}
+	return g.GenerateComposeServices(cfg, backends, frontendService, traefikService)
+}
+
+// GenerateComposeServices is GenerateCompose for projects that declare
+// several backend services (CORE_BACKEND_SERVICES). Services are keyed by
+// name, so their order in backendServices does not affect the output.
+func (g *Generator) GenerateComposeServices(
+	cfg *config.Config,
+	backendServices []*ServiceDefinition,
+	frontendService *ServiceDefinition,
+	traefikService *ServiceDefinition,
 ) (*corecompose.ComposeFile, error) {
 	// Basic topology validation – v1 requires at least a backend service.
-	if backendService == nil {
+	if len(backendServices) == 0 {
 		return nil, ErrBackendServiceRequired
 	}
 
@@ -87,9 +104,25 @@ func (g *Generator) GenerateCompose(
 	// Build services map
 	services := make(map[string]any)
 
-	// Add backend service
-	backendServiceMap := g.buildServiceMap(backendService)
-	services[backendService.Name] = backendServiceMap
+	// Add backend services
+	secrets := make(map[string]BuildSecret)
+	for _, backendService := range backendServices {
+		if backendService == nil {
+			return nil, ErrBackendServiceRequired
+		}
+		for _, secret := range backendService.BuildSecrets {
+			if existing, dup := secrets[secret.ID]; dup && existing != secret {
+				return nil, fmt.Errorf("dev compose infra: conflicting definitions of build secret %q", secret.ID)
+			}
+			secrets[secret.ID] = secret
+		}
+		for name, serviceMap := range g.buildInstanceMaps(backendService) {
+			if _, dup := services[name]; dup {
+				return nil, fmt.Errorf("dev compose infra: duplicate backend service %q", name)
+			}
+			services[name] = serviceMap
+		}
+	}
 
 	// Add frontend service if provided
 	if frontendService != nil {
@@ -127,9 +160,27 @@ func (g *Generator) GenerateCompose(
 	}
 	data["networks"] = networks
 
+	if len(secrets) > 0 {
+		data["secrets"] = g.convertSecrets(secrets)
+	}
+
 	return corecompose.NewComposeFile(data), nil
 }
 
+// convertSecrets converts build secrets to the top-level compose secrets
+// section.
+func (g *Generator) convertSecrets(secrets map[string]BuildSecret) map[string]any {
+	result := make(map[string]any, len(secrets))
+	for id, secret := range secrets {
+		if secret.File != "" {
+			result[id] = map[string]any{"file": secret.File}
+		} else {
+			result[id] = map[string]any{"environment": secret.Environment}
+		}
+	}
+	return result
+}
+
 // convertPorts converts PortMapping slice to compose ports format.
 // Ports are returned as []any where each element is a string in format
 // "host:container/protocol". Ports are sorted deterministically by host port
@@ -424,6 +475,38 @@ func (g *Generator) buildServiceMap(svc *ServiceDefinition) map[string]any {
 	return serviceMap
 }
 
+// buildInstanceMaps returns the compose service maps for svc keyed by
+// service name. Replicated services become numbered instances (see
+// ServiceDefinition.InstanceNames) that share svc.Name as a network alias,
+// so other services keep resolving the original name. Only the first
+// instance publishes host ports.
+func (g *Generator) buildInstanceMaps(svc *ServiceDefinition) map[string]any {
+	names := svc.InstanceNames()
+	if len(names) == 1 {
+		return map[string]any{svc.Name: g.buildServiceMap(svc)}
+	}
+
+	instances := make(map[string]any, len(names))
+	for i, name := range names {
+		serviceMap := g.buildServiceMap(svc)
+		if i > 0 {
+			delete(serviceMap, "ports")
+		}
+
+		networks := make(map[string]any)
+		for _, network := range svc.Networks {
+			networks[network] = map[string]any{}
+		}
+		networks[devNetworkName] = map[string]any{
+			"aliases": []any{svc.Name},
+		}
+		serviceMap["networks"] = networks
+
+		instances[name] = serviceMap
+	}
+	return instances
+}
+
 // containsString checks if a string slice contains a value.
 func containsString(slice []string, value string) bool {
 	for _, s := range slice {
@@ -493,6 +576,14 @@ func (g *Generator) ensureDevNetwork(services map[string]any) {
 			continue
 		}
 
+		// Map-form networks (replica aliases) already name stagecraft-dev.
+		if networkMap, ok := serviceMap["networks"].(map[string]any); ok {
+			if _, ok := networkMap[devNetworkName]; !ok {
+				networkMap[devNetworkName] = map[string]any{}
+			}
+			continue
+		}
+
 		// Get existing networks
 		existingNetworks, ok := serviceMap["networks"].([]any)
 		if !ok {
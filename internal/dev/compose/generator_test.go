@@ -51,6 +51,92 @@ func TestGenerateCompose_BackendOnly_Minimal(t *testing.T) {
 	}
 }
 
+func TestGenerator_GenerateComposeServices_MultipleBackends(t *testing.T) {
+	gen := NewGenerator()
+	backends := []*ServiceDefinition{
+		{Name: "worker"},
+		{Name: "api"},
+	}
+
+	got, err := gen.GenerateComposeServices(&config.Config{}, backends, &ServiceDefinition{Name: "frontend"}, nil)
+	if err != nil {
+		t.Fatalf("GenerateComposeServices() error = %v, want nil", err)
+	}
+
+	services := got.GetServices()
+	want := []string{"api", "frontend", "worker"}
+	if len(services) != len(want) {
+		t.Fatalf("services = %v, want %v", services, want)
+	}
+	for i := range want {
+		if services[i] != want[i] {
+			t.Fatalf("services = %v, want %v", services, want)
+		}
+	}
+
+	// Input order must not affect output.
+	reversed, err := gen.GenerateComposeServices(&config.Config{}, []*ServiceDefinition{backends[1], backends[0]}, &ServiceDefinition{Name: "frontend"}, nil)
+	if err != nil {
+		t.Fatalf("GenerateComposeServices() error = %v, want nil", err)
+	}
+	a, _ := got.ToYAML()
+	b, _ := reversed.ToYAML()
+	if string(a) != string(b) {
+		t.Errorf("output depends on backend order:\n%s\nvs\n%s", a, b)
+	}
+}
+
+func TestGenerator_GenerateComposeServices_BuildSecrets(t *testing.T) {
+	gen := NewGenerator()
+	secrets := []BuildSecret{
+		{ID: "npmrc", File: "../../.npmrc"},
+		{ID: "gh_token", Environment: "GITHUB_TOKEN"},
+	}
+	backends := []*ServiceDefinition{
+		{Name: "api", Build: map[string]any{"context": "../..", "secrets": []any{"npmrc", "gh_token"}}, BuildSecrets: secrets},
+		{Name: "worker", Build: map[string]any{"context": "../..", "secrets": []any{"npmrc"}}, BuildSecrets: secrets[:1]},
+	}
+
+	got, err := gen.GenerateComposeServices(&config.Config{}, backends, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateComposeServices() error = %v, want nil", err)
+	}
+
+	out, err := got.ToYAML()
+	if err != nil {
+		t.Fatalf("ToYAML() error = %v", err)
+	}
+	for _, want := range []string{
+		"secrets:\n  gh_token:\n    environment: GITHUB_TOKEN\n  npmrc:\n    file: ../../.npmrc\n",
+		"      secrets:\n        - npmrc\n        - gh_token\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("compose YAML missing %q:\n%s", want, out)
+		}
+	}
+
+	conflicting := []*ServiceDefinition{
+		{Name: "api", BuildSecrets: []BuildSecret{{ID: "token", Environment: "A"}}},
+		{Name: "worker", BuildSecrets: []BuildSecret{{ID: "token", Environment: "B"}}},
+	}
+	if _, err := gen.GenerateComposeServices(&config.Config{}, conflicting, nil, nil); err == nil {
+		t.Error("expected conflicting build secret error")
+	}
+}
+
+func TestGenerator_GenerateComposeServices_Errors(t *testing.T) {
+	gen := NewGenerator()
+
+	if _, err := gen.GenerateComposeServices(&config.Config{}, nil, nil, nil); !errors.Is(err, ErrBackendServiceRequired) {
+		t.Errorf("empty backends error = %v, want ErrBackendServiceRequired", err)
+	}
+
+	dup := []*ServiceDefinition{{Name: "api"}, {Name: "api"}}
+	if _, err := gen.GenerateComposeServices(&config.Config{}, dup, nil, nil); err == nil {
+		t.Errorf("expected duplicate backend service error")
+	}
+}
+
 func TestGenerator_GenerateCompose_BackendAndFrontend(t *testing.T) {
 	t.Helper()
 
@@ -1438,6 +1524,45 @@ func TestGenerator_GenerateCompose_TraefikServiceGeneration(t *testing.T) {
 	}
 }
 
+func TestGenerator_GenerateComposeServices_Replicas(t *testing.T) {
+	api := &ServiceDefinition{
+		Name:     "api",
+		Ports:    []PortMapping{{Host: "4000", Container: "4000", Protocol: "tcp"}},
+		Replicas: 2,
+	}
+
+	composeFile, err := NewGenerator().GenerateComposeServices(&config.Config{}, []*ServiceDefinition{api}, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateComposeServices() error = %v", err)
+	}
+
+	services := composeFile.GetServices()
+	if strings.Join(services, ",") != "api-1,api-2" {
+		t.Fatalf("services = %v, want [api-1 api-2]", services)
+	}
+
+	first := composeFile.GetServiceData("api-1")
+	second := composeFile.GetServiceData("api-2")
+	if _, ok := first["ports"]; !ok {
+		t.Error("first instance should publish host ports")
+	}
+	if _, ok := second["ports"]; ok {
+		t.Error("later instances must not publish host ports")
+	}
+
+	for _, name := range services {
+		networks, ok := composeFile.GetServiceData(name)["networks"].(map[string]any)
+		if !ok {
+			t.Fatalf("%s networks = %T, want map form", name, composeFile.GetServiceData(name)["networks"])
+		}
+		devNet, _ := networks["stagecraft-dev"].(map[string]any)
+		aliases, _ := devNet["aliases"].([]any)
+		if len(aliases) != 1 || aliases[0] != "api" {
+			t.Errorf("%s aliases = %v, want [api]", name, aliases)
+		}
+	}
+}
+
 // contains checks if a string contains a substring.
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || substr == "" ||
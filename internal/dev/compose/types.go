@@ -14,6 +14,8 @@ See https://www.gnu.org/licenses/ for license details.
 // Package compose provides dev Docker Compose infrastructure generation.
 package compose
 
+import "fmt"
+
 // Feature: DEV_COMPOSE_INFRA
 // Spec: spec/dev/compose-infra.md
 
@@ -36,6 +38,10 @@ type ServiceDefinition struct {
 	// This is a raw map to avoid leaking provider-specific structure into core.
 	Build map[string]any
 
+	// BuildSecrets are the top-level compose secrets that Build's "secrets"
+	// entries refer to.
+	BuildSecrets []BuildSecret
+
 	// Ports describes host-to-container port mappings for dev.
 	Ports []PortMapping
 
@@ -53,6 +59,23 @@ type ServiceDefinition struct {
 
 	// Labels contains arbitrary labels attached to the service.
 	Labels map[string]string
+
+	// Replicas is the number of instances to run (DEPLOY_SERVICE_REPLICAS).
+	// Zero or one means a single instance under Name.
+	Replicas int
+}
+
+// InstanceNames returns the compose service names for the service's
+// instances: Name for a single instance, otherwise "<name>-1".."<name>-N".
+func (s *ServiceDefinition) InstanceNames() []string {
+	if s.Replicas <= 1 {
+		return []string{s.Name}
+	}
+	names := make([]string, s.Replicas)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s-%d", s.Name, i+1)
+	}
+	return names
 }
 
 // PortMapping represents a single port mapping for a service.
@@ -88,3 +111,12 @@ type VolumeMapping struct {
 	// ReadOnly indicates whether the mount is read-only.
 	ReadOnly bool
 }
+
+// BuildSecret is a top-level compose secret used by an image build. It is
+// read from File or from the Environment variable of the shell running
+// docker compose.
+type BuildSecret struct {
+	ID          string
+	File        string
+	Environment string
+}
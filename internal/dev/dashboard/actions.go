@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"stagecraft/internal/containerruntime"
+	devmetrics "stagecraft/internal/dev/metrics"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEV_DASHBOARD
+// Spec: spec/dev/dashboard.md
+
+// ComposeActions implements Actions with the compose CLI for the dev
+// compose file.
+type ComposeActions struct {
+	Runner      executil.Runner
+	Runtime     containerruntime.Runtime
+	ComposePath string
+
+	// DockerContext, when set, selects the Docker context via DOCKER_CONTEXT.
+	DockerContext string
+
+	// GOOS picks the URL opener; defaults to runtime.GOOS.
+	GOOS string
+}
+
+// Status implements Actions.
+func (a *ComposeActions) Status(ctx context.Context) ([]devmetrics.ServiceStatus, error) {
+	return devmetrics.ComposeStatus(ctx, a.Runner, a.Runtime, a.ComposePath, a.DockerContext)
+}
+
+// Restart implements Actions with `compose restart <service>`.
+func (a *ComposeActions) Restart(ctx context.Context, service string) error {
+	cmd := a.Runtime.ComposeCommand("-f", a.ComposePath, "restart", service)
+	if a.DockerContext != "" {
+		cmd.Env = map[string]string{"DOCKER_CONTEXT": a.DockerContext}
+	}
+	if _, err := a.Runner.Run(ctx, cmd); err != nil {
+		return fmt.Errorf("running %s compose restart: %w", a.Runtime.Binary(), err)
+	}
+	return nil
+}
+
+// Open implements Actions with the platform's URL opener.
+func (a *ComposeActions) Open(ctx context.Context, url string) error {
+	goos := a.GOOS
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+
+	var cmd executil.Command
+	switch goos {
+	case "darwin":
+		cmd = executil.NewCommand("open", url)
+	case "windows":
+		cmd = executil.NewCommand("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = executil.NewCommand("xdg-open", url)
+	}
+
+	if _, err := a.Runner.Run(ctx, cmd); err != nil {
+		return fmt.Errorf("opening %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,370 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package dashboard is the optional terminal UI for `stagecraft dev --tui`.
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	devmetrics "stagecraft/internal/dev/metrics"
+)
+
+// Feature: DEV_DASHBOARD
+// Spec: spec/dev/dashboard.md
+
+const (
+	// DefaultPollInterval is how often the dashboard refreshes service state.
+	DefaultPollInterval = 2 * time.Second
+
+	// DefaultLogLines is how many log lines are kept per service.
+	DefaultLogLines = 200
+)
+
+// Service is one compose service shown on the dashboard.
+type Service struct {
+	Name string
+	// URL is where the service is reachable from the host; empty when it
+	// has no routed domain.
+	URL string
+}
+
+// Actions performs the work behind the dashboard's keybindings and status
+// refresh.
+type Actions interface {
+	Status(ctx context.Context) ([]devmetrics.ServiceStatus, error)
+	Restart(ctx context.Context, service string) error
+	Open(ctx context.Context, url string) error
+}
+
+// Options configures a Dashboard.
+type Options struct {
+	// Title is shown on the first line, e.g. the environment name.
+	Title    string
+	Services []Service
+	Actions  Actions
+
+	// PollInterval defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	// LogLines defaults to DefaultLogLines.
+	LogLines int
+
+	// Input and Output default to the process's terminal.
+	Input  io.Reader
+	Output io.Writer
+}
+
+// Dashboard runs the terminal UI.
+type Dashboard struct {
+	program *tea.Program
+	ctx     context.Context
+}
+
+// New builds a dashboard that stops when ctx is cancelled.
+func New(ctx context.Context, opts Options) *Dashboard {
+	progOpts := []tea.ProgramOption{tea.WithContext(ctx), tea.WithAltScreen()}
+	if opts.Input != nil {
+		progOpts = append(progOpts, tea.WithInput(opts.Input))
+	}
+	if opts.Output != nil {
+		progOpts = append(progOpts, tea.WithOutput(opts.Output))
+	}
+
+	return &Dashboard{
+		program: tea.NewProgram(newModel(ctx, opts), progOpts...),
+		ctx:     ctx,
+	}
+}
+
+// LogWriter returns a writer for `compose up` output. Each line is shown
+// under the service named by its compose prefix.
+func (d *Dashboard) LogWriter() io.Writer {
+	return newLogWriter(d.program.Send)
+}
+
+// StackExited tells the dashboard that `compose up` returned, which closes
+// it. err is shown if the dashboard is still on screen.
+func (d *Dashboard) StackExited(err error) {
+	d.program.Send(stackExitedMsg{err: err})
+}
+
+// Run shows the dashboard until the user quits, the stack exits, or the
+// context is cancelled.
+func (d *Dashboard) Run() error {
+	_, err := d.program.Run()
+	if errors.Is(err, tea.ErrProgramKilled) && d.ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// Messages handled by model.Update.
+type (
+	statusMsg struct {
+		statuses []devmetrics.ServiceStatus
+		err      error
+	}
+	logMsg struct {
+		service string
+		line    string
+	}
+	actionMsg struct {
+		text string
+	}
+	stackExitedMsg struct {
+		err error
+	}
+	tickMsg struct{}
+)
+
+// serviceState is a Service plus what the dashboard has observed about it.
+type serviceState struct {
+	Service
+	status   devmetrics.ServiceStatus
+	observed bool
+	logs     []string
+}
+
+// model is the bubbletea model behind the dashboard.
+type model struct {
+	ctx      context.Context
+	title    string
+	actions  Actions
+	interval time.Duration
+	logLines int
+
+	services []*serviceState
+	byName   map[string]*serviceState
+	selected int
+
+	// message is the result of the last action, or the last line compose
+	// printed without a service prefix.
+	message string
+	height  int
+}
+
+func newModel(ctx context.Context, opts Options) *model {
+	m := &model{
+		ctx:      ctx,
+		title:    opts.Title,
+		actions:  opts.Actions,
+		interval: opts.PollInterval,
+		logLines: opts.LogLines,
+		byName:   make(map[string]*serviceState),
+	}
+	if m.interval <= 0 {
+		m.interval = DefaultPollInterval
+	}
+	if m.logLines <= 0 {
+		m.logLines = DefaultLogLines
+	}
+	for _, svc := range opts.Services {
+		m.service(svc.Name).URL = svc.URL
+	}
+	return m
+}
+
+// service returns the state for name, adding it when compose reports a
+// service the dashboard was not told about.
+func (m *model) service(name string) *serviceState {
+	if s, ok := m.byName[name]; ok {
+		return s
+	}
+	s := &serviceState{Service: Service{Name: name}}
+	m.byName[name] = s
+	m.services = append(m.services, s)
+	return s
+}
+
+func (m *model) Init() tea.Cmd {
+	return m.pollStatus()
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+
+	case tickMsg:
+		return m, m.pollStatus()
+
+	case statusMsg:
+		if msg.err != nil {
+			m.message = "status: " + msg.err.Error()
+		}
+		for _, status := range msg.statuses {
+			s := m.service(status.Service)
+			s.status = status
+			s.observed = true
+		}
+		return m, tea.Tick(m.interval, func(time.Time) tea.Msg { return tickMsg{} })
+
+	case logMsg:
+		if msg.service == "" {
+			m.message = msg.line
+			break
+		}
+		s := m.service(msg.service)
+		s.logs = append(s.logs, msg.line)
+		if over := len(s.logs) - m.logLines; over > 0 {
+			s.logs = s.logs[over:]
+		}
+
+	case actionMsg:
+		m.message = msg.text
+
+	case stackExitedMsg:
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+func (m *model) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+	case "down", "j":
+		if m.selected < len(m.services)-1 {
+			m.selected++
+		}
+	case "r":
+		if s := m.current(); s != nil {
+			m.message = fmt.Sprintf("restarting %s...", s.Name)
+			return m.restart(s.Name)
+		}
+	case "o":
+		if s := m.current(); s != nil {
+			if s.URL == "" {
+				m.message = fmt.Sprintf("%s has no URL", s.Name)
+				return nil
+			}
+			return m.open(s.URL)
+		}
+	}
+	return nil
+}
+
+func (m *model) current() *serviceState {
+	if m.selected < 0 || m.selected >= len(m.services) {
+		return nil
+	}
+	return m.services[m.selected]
+}
+
+func (m *model) pollStatus() tea.Cmd {
+	return func() tea.Msg {
+		statuses, err := m.actions.Status(m.ctx)
+		return statusMsg{statuses: statuses, err: err}
+	}
+}
+
+func (m *model) restart(name string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.actions.Restart(m.ctx, name); err != nil {
+			return actionMsg{text: fmt.Sprintf("restart %s: %v", name, err)}
+		}
+		return actionMsg{text: fmt.Sprintf("restarted %s", name)}
+	}
+}
+
+func (m *model) open(url string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.actions.Open(m.ctx, url); err != nil {
+			return actionMsg{text: fmt.Sprintf("open %s: %v", url, err)}
+		}
+		return actionMsg{text: fmt.Sprintf("opened %s", url)}
+	}
+}
+
+func (m *model) View() string {
+	var b strings.Builder
+
+	_, _ = fmt.Fprintf(&b, "stagecraft dev: %s\n\n", m.title)
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "  SERVICE\tSTATUS\tREADY\tURL")
+	for i, s := range m.services {
+		cursor := " "
+		if i == m.selected {
+			cursor = ">"
+		}
+		_, _ = fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\n", cursor, s.Name, s.statusText(), s.readyText(), s.URL)
+	}
+	_ = tw.Flush()
+
+	if s := m.current(); s != nil {
+		_, _ = fmt.Fprintf(&b, "\nLogs: %s\n", s.Name)
+		for _, line := range tail(s.logs, m.visibleLogLines()) {
+			_, _ = fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+
+	b.WriteString("\n↑/↓ select  r restart  o open URL  q quit\n")
+	if m.message != "" {
+		b.WriteString(m.message + "\n")
+	}
+	return b.String()
+}
+
+// visibleLogLines is how many log lines fit below the service table.
+func (m *model) visibleLogLines() int {
+	// Title, table header, services, log header, key help, message, spacing.
+	const chrome = 9
+	if m.height == 0 {
+		return 10
+	}
+	return max(m.height-chrome-len(m.services), 1)
+}
+
+func (s *serviceState) statusText() string {
+	switch {
+	case !s.observed:
+		return "pending"
+	case !s.status.Running:
+		return "stopped"
+	default:
+		return "running"
+	}
+}
+
+func (s *serviceState) readyText() string {
+	if s.status.Ready() {
+		return "yes"
+	}
+	return "no"
+}
+
+func tail(lines []string, n int) []string {
+	if len(lines) <= n {
+		return lines
+	}
+	return lines[len(lines)-n:]
+}
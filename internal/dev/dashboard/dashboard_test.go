@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	devmetrics "stagecraft/internal/dev/metrics"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEV_DASHBOARD
+// Spec: spec/dev/dashboard.md
+
+type fakeActions struct {
+	statuses  []devmetrics.ServiceStatus
+	restarted []string
+	opened    []string
+	err       error
+}
+
+func (f *fakeActions) Status(context.Context) ([]devmetrics.ServiceStatus, error) {
+	return f.statuses, f.err
+}
+
+func (f *fakeActions) Restart(_ context.Context, service string) error {
+	f.restarted = append(f.restarted, service)
+	return f.err
+}
+
+func (f *fakeActions) Open(_ context.Context, url string) error {
+	f.opened = append(f.opened, url)
+	return f.err
+}
+
+func newTestModel(actions Actions) *model {
+	return newModel(context.Background(), Options{
+		Title: "dev",
+		Services: []Service{
+			{Name: "backend", URL: "https://api.localdev.test"},
+			{Name: "frontend", URL: "https://app.localdev.test"},
+			{Name: "postgres"},
+		},
+		Actions:  actions,
+		LogLines: 3,
+	})
+}
+
+// run feeds msg to m and then every message its commands produce, the way
+// the bubbletea runtime would, stopping before the next status poll.
+func run(m *model, msg tea.Msg) {
+	for msg != nil {
+		_, cmd := m.Update(msg)
+		if _, ok := msg.(statusMsg); ok || cmd == nil {
+			return
+		}
+		msg = cmd()
+		if _, ok := msg.(tea.QuitMsg); ok {
+			return
+		}
+	}
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestModel_StatusAndView(t *testing.T) {
+	actions := &fakeActions{statuses: []devmetrics.ServiceStatus{
+		{Service: "backend", Running: true, Healthy: false},
+		{Service: "frontend", Running: true, Healthy: true},
+		{Service: "worker", Running: false},
+	}}
+	m := newTestModel(actions)
+	run(m, m.Init()())
+
+	view := m.View()
+	for _, want := range []string{
+		"stagecraft dev: dev",
+		"> backend   running  no     https://api.localdev.test",
+		"  frontend  running  yes    https://app.localdev.test",
+		"  postgres  pending  no",
+		"  worker    stopped  no",
+		"Logs: backend",
+		"q quit",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestModel_StatusError(t *testing.T) {
+	m := newTestModel(&fakeActions{err: errors.New("compose not found")})
+	run(m, m.Init()())
+
+	if !strings.Contains(m.View(), "status: compose not found") {
+		t.Errorf("view missing status error:\n%s", m.View())
+	}
+}
+
+func TestModel_Logs(t *testing.T) {
+	m := newTestModel(&fakeActions{})
+
+	w := newLogWriter(func(msg tea.Msg) { m.Update(msg) })
+	_, _ = io.WriteString(w, "Attaching to backend-1, frontend-1\n")
+	_, _ = io.WriteString(w, "backend-1   | one\nbackend-1   | two\nfrontend-1  | ready\n")
+	_, _ = io.WriteString(w, "backend-2   | thr")
+	_, _ = io.WriteString(w, "ee\r\nbackend-1   | four\n")
+
+	if want := []string{"two", "three", "four"}; !reflect.DeepEqual(m.byName["backend"].logs, want) {
+		t.Errorf("backend logs = %q, want %q", m.byName["backend"].logs, want)
+	}
+	if want := []string{"ready"}; !reflect.DeepEqual(m.byName["frontend"].logs, want) {
+		t.Errorf("frontend logs = %q, want %q", m.byName["frontend"].logs, want)
+	}
+	if m.message != "Attaching to backend-1, frontend-1" {
+		t.Errorf("message = %q", m.message)
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "Logs: backend\n  two\n  three\n  four\n") {
+		t.Errorf("view missing backend logs:\n%s", view)
+	}
+
+	run(m, key("down"))
+	if !strings.Contains(m.View(), "Logs: frontend\n  ready\n") {
+		t.Errorf("view missing frontend logs:\n%s", m.View())
+	}
+}
+
+func TestModel_Keys(t *testing.T) {
+	actions := &fakeActions{}
+	m := newTestModel(actions)
+
+	run(m, key("up"))
+	if m.selected != 0 {
+		t.Fatalf("selected = %d after up at top, want 0", m.selected)
+	}
+
+	run(m, key("r"))
+	run(m, key("o"))
+	run(m, key("j"))
+	run(m, key("o"))
+	run(m, key("j"))
+	run(m, key("j"))
+	run(m, key("o"))
+	run(m, key("r"))
+
+	if want := []string{"backend", "postgres"}; !reflect.DeepEqual(actions.restarted, want) {
+		t.Errorf("restarted = %q, want %q", actions.restarted, want)
+	}
+	if want := []string{"https://api.localdev.test", "https://app.localdev.test"}; !reflect.DeepEqual(actions.opened, want) {
+		t.Errorf("opened = %q, want %q", actions.opened, want)
+	}
+	if m.selected != 2 {
+		t.Errorf("selected = %d, want 2", m.selected)
+	}
+	if m.message != "restarted postgres" {
+		t.Errorf("message = %q", m.message)
+	}
+
+	run(m, key("o"))
+	if m.message != "postgres has no URL" {
+		t.Errorf("message = %q", m.message)
+	}
+}
+
+func TestModel_Quit(t *testing.T) {
+	for _, msg := range []tea.Msg{key("q"), tea.KeyMsg{Type: tea.KeyCtrlC}, stackExitedMsg{}} {
+		m := newTestModel(&fakeActions{})
+		_, cmd := m.Update(msg)
+		if cmd == nil {
+			t.Fatalf("%v: no command, want quit", msg)
+		}
+		if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Errorf("%v: command does not quit", msg)
+		}
+	}
+}
+
+type fakeRunner struct {
+	cmds   []executil.Command
+	stdout string
+}
+
+func (f *fakeRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) {
+	f.cmds = append(f.cmds, cmd)
+	return &executil.Result{Stdout: []byte(f.stdout)}, nil
+}
+
+func (f *fakeRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return nil
+}
+
+func TestComposeActions(t *testing.T) {
+	runner := &fakeRunner{stdout: `{"Service":"backend","State":"running","Health":"healthy"}`}
+	actions := &ComposeActions{
+		Runner:        runner,
+		ComposePath:   ".stagecraft/dev/compose.yaml",
+		DockerContext: "colima",
+		GOOS:          "linux",
+	}
+	ctx := context.Background()
+
+	statuses, err := actions.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if want := []devmetrics.ServiceStatus{{Service: "backend", Running: true, Healthy: true}}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %+v, want %+v", statuses, want)
+	}
+
+	if err := actions.Restart(ctx, "backend"); err != nil {
+		t.Fatalf("Restart: %v", err)
+	}
+	if err := actions.Open(ctx, "https://app.localdev.test"); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	restart := runner.cmds[1]
+	if got := restart.Name + " " + strings.Join(restart.Args, " "); got != "docker compose -f .stagecraft/dev/compose.yaml restart backend" {
+		t.Errorf("restart command = %q", got)
+	}
+	if restart.Env["DOCKER_CONTEXT"] != "colima" {
+		t.Errorf("restart env = %v, want DOCKER_CONTEXT=colima", restart.Env)
+	}
+
+	open := runner.cmds[2]
+	if got := open.Name + " " + strings.Join(open.Args, " "); got != "xdg-open https://app.localdev.test" {
+		t.Errorf("open command = %q", got)
+	}
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package dashboard
+
+import (
+	"bytes"
+	"regexp"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Feature: DEV_DASHBOARD
+// Spec: spec/dev/dashboard.md
+
+// composeLogPrefix matches `compose up` output lines, e.g.
+// "backend-1  | listening on :8080". The replica suffix is dropped so
+// replicas share their service's log.
+var composeLogPrefix = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*?)(?:-\d+)?\s+\|\s?(.*)$`)
+
+// logWriter splits compose output into lines and sends each one to the
+// dashboard.
+type logWriter struct {
+	mu   sync.Mutex
+	send func(tea.Msg)
+	buf  []byte
+}
+
+func newLogWriter(send func(tea.Msg)) *logWriter {
+	return &logWriter{send: send}
+}
+
+// Write implements io.Writer. Incomplete lines are kept until the rest
+// arrives.
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(bytes.TrimRight(w.buf[:i], "\r"))
+		w.buf = w.buf[i+1:]
+		w.send(parseLogLine(line))
+	}
+	return len(p), nil
+}
+
+// parseLogLine attributes a compose output line to its service. Lines
+// without a service prefix come from compose itself.
+func parseLogLine(line string) logMsg {
+	if m := composeLogPrefix.FindStringSubmatch(line); m != nil {
+		return logMsg{service: m[1], line: m[2]}
+	}
+	return logMsg{line: line}
+}
@@ -15,13 +15,27 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"stagecraft/internal/compose"
 )
 
+// DefaultDir is the dev directory relative to the project root.
+const DefaultDir = ".stagecraft/dev"
+
+// ComposeFileName is the name of the generated compose file in the dev directory.
+const ComposeFileName = "compose.yaml"
+
 // DevFiles describes the paths of generated dev config files.
 //
 //nolint:revive // DevFiles is clear and descriptive in the dev package context
 type DevFiles struct {
-	ComposePath        string
+	ComposePath string
+	// ComposeHash is the config-hash recorded in the compose file header.
+	ComposeHash string
+	// ComposeWritten is false when the compose file was already up to date
+	// and left untouched.
+	ComposeWritten bool
+
 	TraefikStaticPath  string
 	TraefikDynamicPath string
 }
@@ -41,7 +55,7 @@ func WriteFiles(devDir string, topo *Topology) (DevFiles, error) {
 		return DevFiles{}, fmt.Errorf("dev files: traefik config is incomplete")
 	}
 
-	composePath := filepath.Join(devDir, "compose.yaml")
+	composePath := filepath.Join(devDir, ComposeFileName)
 
 	// Ensure directories exist.
 	// #nosec G301 -- dev directory needs 0755 for docker compose access
@@ -54,13 +68,17 @@ func WriteFiles(devDir string, topo *Topology) (DevFiles, error) {
 	if err != nil {
 		return DevFiles{}, fmt.Errorf("dev files: marshal compose yaml: %w", err)
 	}
+	// An unchanged file keeps its mtime so compose sees nothing new.
 	// #nosec G306 -- compose.yaml needs 0644 for docker compose to read it
-	if err := os.WriteFile(composePath, composeBytes, 0o644); err != nil {
+	composeHash, written, err := compose.WriteFile(composePath, composeBytes, 0o644, nil, nil)
+	if err != nil {
 		return DevFiles{}, fmt.Errorf("dev files: write compose yaml: %w", err)
 	}
 
 	files := DevFiles{
-		ComposePath: composePath,
+		ComposePath:    composePath,
+		ComposeHash:    composeHash,
+		ComposeWritten: written,
 	}
 
 	// Traefik config files (only if Traefik is enabled)
@@ -206,3 +206,33 @@ func TestWriteFiles_HandlesNilTraefik(t *testing.T) {
 		t.Errorf("WriteFiles() TraefikDynamicPath = %q, want empty when Traefik is nil", files.TraefikDynamicPath)
 	}
 }
+
+func TestWriteFiles_KeepsUnchangedComposeFile(t *testing.T) {
+	devDir := filepath.Join(t.TempDir(), ".stagecraft", "dev")
+	topo := &Topology{
+		Compose: corecompose.NewComposeFile(map[string]any{
+			"services": map[string]any{"backend": map[string]any{"image": "app"}},
+		}),
+	}
+
+	first, err := WriteFiles(devDir, topo)
+	if err != nil {
+		t.Fatalf("WriteFiles() error = %v", err)
+	}
+	if !first.ComposeWritten || first.ComposeHash == "" {
+		t.Fatalf("first WriteFiles() = %+v, want written with hash", first)
+	}
+
+	stored, ok, err := corecompose.ReadHash(first.ComposePath)
+	if err != nil || !ok || stored != first.ComposeHash {
+		t.Fatalf("ReadHash() = %q, %v, %v; want %q", stored, ok, err, first.ComposeHash)
+	}
+
+	second, err := WriteFiles(devDir, topo)
+	if err != nil {
+		t.Fatalf("WriteFiles() error = %v", err)
+	}
+	if second.ComposeWritten || second.ComposeHash != first.ComposeHash {
+		t.Errorf("second WriteFiles() = %+v, want unchanged file left alone", second)
+	}
+}
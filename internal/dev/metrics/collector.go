@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"stagecraft/internal/containerruntime"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEV_METRICS
+// Spec: spec/dev/metrics.md
+
+// DefaultPollInterval is how often the poller samples `docker compose ps`.
+const DefaultPollInterval = 5 * time.Second
+
+// composePSEntry is the subset of `docker compose ps --format json` we use.
+type composePSEntry struct {
+	Service string `json:"Service"`
+	State   string `json:"State"`
+	Health  string `json:"Health"`
+}
+
+// ParseComposePS parses `docker compose ps --format json` output. Compose
+// v2.21+ prints one JSON object per line; older releases print a JSON array.
+func ParseComposePS(data []byte) ([]ServiceStatus, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var entries []composePSEntry
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("parsing compose ps output: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var e composePSEntry
+			if err := json.Unmarshal(line, &e); err != nil {
+				return nil, fmt.Errorf("parsing compose ps output: %w", err)
+			}
+			entries = append(entries, e)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading compose ps output: %w", err)
+		}
+	}
+
+	statuses := make([]ServiceStatus, 0, len(entries))
+	for _, e := range entries {
+		statuses = append(statuses, ServiceStatus{
+			Service: e.Service,
+			Running: e.State == "running",
+			Healthy: e.Health == "" || e.Health == "healthy",
+		})
+	}
+	return statuses, nil
+}
+
+// Poller samples service state from docker compose into a Registry.
+type Poller struct {
+	Registry    *Registry
+	Runner      executil.Runner
+	ComposePath string
+	Interval    time.Duration
+	Now         func() time.Time
+
+	// Runtime runs `compose ps`; docker when empty.
+	Runtime containerruntime.Runtime
+
+	// DockerContext, when set, selects the Docker context via DOCKER_CONTEXT.
+	DockerContext string
+}
+
+// ComposeStatus runs `compose ps` for the compose file at composePath and
+// returns the state of every service. dockerContext, when set, selects the
+// Docker context via DOCKER_CONTEXT.
+func ComposeStatus(
+	ctx context.Context,
+	runner executil.Runner,
+	runtime containerruntime.Runtime,
+	composePath string,
+	dockerContext string,
+) ([]ServiceStatus, error) {
+	cmd := runtime.ComposeCommand("-f", composePath, "ps", "--all", "--format", "json")
+	if dockerContext != "" {
+		cmd.Env = map[string]string{"DOCKER_CONTEXT": dockerContext}
+	}
+	result, err := runner.Run(ctx, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("running %s compose ps: %w", runtime.Binary(), err)
+	}
+	return ParseComposePS(result.Stdout)
+}
+
+// Poll takes a single sample.
+func (p *Poller) Poll(ctx context.Context) error {
+	statuses, err := ComposeStatus(ctx, p.Runner, p.Runtime, p.ComposePath, p.DockerContext)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	p.Registry.Observe(statuses, now())
+	return nil
+}
+
+// Run polls until ctx is cancelled. Sampling errors are ignored: the stack
+// may still be starting, and metrics must never interrupt dev.
+func (p *Poller) Run(ctx context.Context) {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = p.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ErrNonLoopbackAddr is returned when the metrics address is not local.
+var ErrNonLoopbackAddr = errors.New("metrics address must be a loopback address")
+
+// ValidateAddr checks that addr is host:port with a loopback host, so dev
+// metrics are never exposed beyond the local machine.
+func ValidateAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid metrics address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("%w: %q", ErrNonLoopbackAddr, addr)
+	}
+	return nil
+}
+
+// Serve starts an HTTP server exposing r at /metrics on addr and returns the
+// bound address. The server shuts down when ctx is cancelled.
+func Serve(ctx context.Context, addr string, r *Registry) (string, error) {
+	if err := ValidateAddr(addr); err != nil {
+		return "", err
+	}
+
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("listening on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r.Handler())
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	return ln.Addr().String(), nil
+}
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package metrics exposes Prometheus metrics for a running `stagecraft dev`
+// stack: per-service up/down, observed restarts, time to ready, and compose
+// generation time.
+//
+// Metrics are rendered in the Prometheus text exposition format without a
+// client library; output is sorted by metric and label so scrapes are
+// deterministic.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Feature: DEV_METRICS
+// Spec: spec/dev/metrics.md
+
+// ServiceStatus is a point-in-time observation of one compose service.
+type ServiceStatus struct {
+	Service string
+	// Running reports whether the container is running.
+	Running bool
+	// Healthy reports whether the container passed its health check, or is
+	// running without one.
+	Healthy bool
+}
+
+// Ready reports whether the service can be considered ready.
+func (s ServiceStatus) Ready() bool {
+	return s.Running && s.Healthy
+}
+
+type serviceMetrics struct {
+	up         bool
+	seenUp     bool
+	restarts   int
+	ready      bool
+	readyAfter time.Duration
+}
+
+// Registry holds dev stack metrics. It is safe for concurrent use.
+type Registry struct {
+	mu                sync.Mutex
+	started           time.Time
+	composeGeneration time.Duration
+	services          map[string]*serviceMetrics
+}
+
+// NewRegistry returns an empty registry. started is the reference time used
+// for ready latency; it is normally when `stagecraft dev` started the stack.
+func NewRegistry(started time.Time) *Registry {
+	return &Registry{
+		started:  started,
+		services: map[string]*serviceMetrics{},
+	}
+}
+
+// ObserveComposeGeneration records how long compose generation took.
+func (r *Registry) ObserveComposeGeneration(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.composeGeneration = d
+}
+
+// Observe updates service metrics from a snapshot taken at now.
+//
+// A restart is counted whenever a service that had been up is seen up again
+// after being down. Services missing from the snapshot are reported down.
+func (r *Registry) Observe(statuses []ServiceStatus, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool, len(statuses))
+	for _, st := range statuses {
+		seen[st.Service] = true
+
+		m, ok := r.services[st.Service]
+		if !ok {
+			m = &serviceMetrics{}
+			r.services[st.Service] = m
+		}
+
+		if st.Running && !m.up && m.seenUp {
+			m.restarts++
+		}
+		m.up = st.Running
+		if st.Running {
+			m.seenUp = true
+		}
+
+		if !m.ready && st.Ready() {
+			m.ready = true
+			m.readyAfter = now.Sub(r.started)
+		}
+	}
+
+	for name, m := range r.services {
+		if !seen[name] {
+			m.up = false
+		}
+	}
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.services))
+	for name := range r.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b []byte
+	header := func(name, typ, help string) {
+		b = fmt.Appendf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	header("stagecraft_dev_compose_generation_seconds", "gauge", "Time spent generating the dev compose file.")
+	b = fmt.Appendf(b, "stagecraft_dev_compose_generation_seconds %s\n", formatSeconds(r.composeGeneration))
+
+	header("stagecraft_dev_service_ready_seconds", "gauge", "Time from stack start until the service was first ready.")
+	for _, name := range names {
+		if m := r.services[name]; m.ready {
+			b = fmt.Appendf(b, "stagecraft_dev_service_ready_seconds{service=%q} %s\n", name, formatSeconds(m.readyAfter))
+		}
+	}
+
+	header("stagecraft_dev_service_restarts_total", "counter", "Observed restarts of the service since the stack started.")
+	for _, name := range names {
+		b = fmt.Appendf(b, "stagecraft_dev_service_restarts_total{service=%q} %d\n", name, r.services[name].restarts)
+	}
+
+	header("stagecraft_dev_service_up", "gauge", "Whether the service container is running (1) or not (0).")
+	for _, name := range names {
+		up := 0
+		if r.services[name].up {
+			up = 1
+		}
+		b = fmt.Appendf(b, "stagecraft_dev_service_up{service=%q} %d\n", name, up)
+	}
+
+	_, err := w.Write(b)
+	return err
+}
+
+// Handler serves the registry at any path using the text exposition format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%g", d.Seconds())
+}
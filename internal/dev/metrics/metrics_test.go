@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEV_METRICS
+// Spec: spec/dev/metrics.md
+
+func TestRegistry_WriteText(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := NewRegistry(start)
+	r.ObserveComposeGeneration(250 * time.Millisecond)
+
+	// backend starts unhealthy, frontend ready immediately.
+	r.Observe([]ServiceStatus{
+		{Service: "frontend", Running: true, Healthy: true},
+		{Service: "backend", Running: true, Healthy: false},
+	}, start.Add(2*time.Second))
+	// backend becomes ready.
+	r.Observe([]ServiceStatus{
+		{Service: "frontend", Running: true, Healthy: true},
+		{Service: "backend", Running: true, Healthy: true},
+	}, start.Add(5*time.Second))
+	// backend crashes, then comes back.
+	r.Observe([]ServiceStatus{
+		{Service: "frontend", Running: true, Healthy: true},
+		{Service: "backend", Running: false},
+	}, start.Add(6*time.Second))
+	r.Observe([]ServiceStatus{
+		{Service: "frontend", Running: true, Healthy: true},
+		{Service: "backend", Running: true, Healthy: true},
+	}, start.Add(7*time.Second))
+	// frontend disappears from compose ps.
+	r.Observe([]ServiceStatus{
+		{Service: "backend", Running: true, Healthy: true},
+	}, start.Add(8*time.Second))
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+
+	want := `# HELP stagecraft_dev_compose_generation_seconds Time spent generating the dev compose file.
+# TYPE stagecraft_dev_compose_generation_seconds gauge
+stagecraft_dev_compose_generation_seconds 0.25
+# HELP stagecraft_dev_service_ready_seconds Time from stack start until the service was first ready.
+# TYPE stagecraft_dev_service_ready_seconds gauge
+stagecraft_dev_service_ready_seconds{service="backend"} 5
+stagecraft_dev_service_ready_seconds{service="frontend"} 2
+# HELP stagecraft_dev_service_restarts_total Observed restarts of the service since the stack started.
+# TYPE stagecraft_dev_service_restarts_total counter
+stagecraft_dev_service_restarts_total{service="backend"} 1
+stagecraft_dev_service_restarts_total{service="frontend"} 0
+# HELP stagecraft_dev_service_up Whether the service container is running (1) or not (0).
+# TYPE stagecraft_dev_service_up gauge
+stagecraft_dev_service_up{service="backend"} 1
+stagecraft_dev_service_up{service="frontend"} 0
+`
+	if buf.String() != want {
+		t.Errorf("unexpected metrics output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestParseComposePS(t *testing.T) {
+	ndjson := `{"Service":"backend","State":"running","Health":"healthy"}
+{"Service":"frontend","State":"exited","Health":""}
+`
+	array := `[{"Service":"backend","State":"running","Health":"starting"},{"Service":"traefik","State":"running","Health":""}]`
+
+	got, err := ParseComposePS([]byte(ndjson))
+	if err != nil {
+		t.Fatalf("ParseComposePS(ndjson) error = %v", err)
+	}
+	if len(got) != 2 || !got[0].Ready() || got[1].Running {
+		t.Errorf("unexpected ndjson statuses: %+v", got)
+	}
+
+	got, err = ParseComposePS([]byte(array))
+	if err != nil {
+		t.Fatalf("ParseComposePS(array) error = %v", err)
+	}
+	if len(got) != 2 || got[0].Ready() || !got[1].Ready() {
+		t.Errorf("unexpected array statuses: %+v", got)
+	}
+
+	if got, err := ParseComposePS([]byte("  \n")); err != nil || len(got) != 0 {
+		t.Errorf("expected empty output to yield no statuses, got %+v, %v", got, err)
+	}
+
+	if _, err := ParseComposePS([]byte("{oops")); err == nil {
+		t.Errorf("expected parse error")
+	}
+}
+
+func TestValidateAddr(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:9464", "localhost:9464", "[::1]:9464"} {
+		if err := ValidateAddr(addr); err != nil {
+			t.Errorf("ValidateAddr(%q) error = %v", addr, err)
+		}
+	}
+
+	for _, addr := range []string{"0.0.0.0:9464", ":9464", "192.168.1.10:9464"} {
+		if err := ValidateAddr(addr); !errors.Is(err, ErrNonLoopbackAddr) {
+			t.Errorf("ValidateAddr(%q) = %v, want ErrNonLoopbackAddr", addr, err)
+		}
+	}
+
+	if err := ValidateAddr("9464"); err == nil {
+		t.Errorf("expected error for address without port separator")
+	}
+}
+
+type fakeRunner struct {
+	stdout string
+	args   []string
+}
+
+func (f *fakeRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) {
+	f.args = cmd.Args
+	return &executil.Result{Stdout: []byte(f.stdout)}, nil
+}
+
+func (f *fakeRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return nil
+}
+
+func TestPoller_Poll(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := NewRegistry(start)
+	runner := &fakeRunner{stdout: `{"Service":"backend","State":"running","Health":""}`}
+
+	p := &Poller{
+		Registry:    r,
+		Runner:      runner,
+		ComposePath: ".stagecraft/dev/compose.yaml",
+		Now:         func() time.Time { return start.Add(3 * time.Second) },
+	}
+	if err := p.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	if got := strings.Join(runner.args, " "); got != "compose -f .stagecraft/dev/compose.yaml ps --all --format json" {
+		t.Errorf("unexpected docker args: %s", got)
+	}
+
+	var buf bytes.Buffer
+	_ = r.WriteText(&buf)
+	if !strings.Contains(buf.String(), `stagecraft_dev_service_ready_seconds{service="backend"} 3`) {
+		t.Errorf("expected ready latency from poll, got:\n%s", buf.String())
+	}
+}
+
+func TestServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := NewRegistry(time.Now())
+	r.Observe([]ServiceStatus{{Service: "backend", Running: true, Healthy: true}}, time.Now())
+
+	addr, err := Serve(ctx, "127.0.0.1:0", r)
+	if err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/metrics", http.NoBody)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `stagecraft_dev_service_up{service="backend"} 1`) {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	if _, err := Serve(ctx, "0.0.0.0:0", r); !errors.Is(err, ErrNonLoopbackAddr) {
+		t.Errorf("expected non-loopback address to be rejected, got %v", err)
+	}
+}
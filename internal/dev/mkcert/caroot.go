@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Feature: DEV_PROXY_ENV
+// Spec: spec/dev/proxy-env.md
+
+package mkcert
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// CAFileName is the name of the mkcert root CA copied into the cert directory.
+const CAFileName = "rootCA.pem"
+
+// CARoot returns mkcert's CA directory using the same lookup as mkcert
+// itself: $CAROOT, then the per-OS user data directory. It returns "" when
+// no location can be determined.
+func CARoot() string {
+	if env := os.Getenv("CAROOT"); env != "" {
+		return env
+	}
+
+	var dir string
+	switch runtime.GOOS {
+	case "windows":
+		dir = os.Getenv("LocalAppData")
+	case "darwin":
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, "Library", "Application Support")
+		}
+	default:
+		dir = os.Getenv("XDG_DATA_HOME")
+		if dir == "" {
+			if home, err := os.UserHomeDir(); err == nil {
+				dir = filepath.Join(home, ".local", "share")
+			}
+		}
+	}
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "mkcert")
+}
+
+// syncCAFile copies the mkcert root CA into certDir so containers that mount
+// the cert directory can trust it. It returns the copied path, or "" when
+// mkcert has no root CA yet.
+func syncCAFile(certDir string) (string, error) {
+	caRoot := CARoot()
+	if caRoot == "" {
+		return "", nil
+	}
+
+	// #nosec G304 -- reading mkcert's own CA certificate
+	data, err := os.ReadFile(filepath.Join(caRoot, CAFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("dev: mkcert: read root CA: %w", err)
+	}
+
+	dest := filepath.Join(certDir, CAFileName)
+	// #nosec G304 -- dest is inside the Stagecraft cert directory
+	if existing, err := os.ReadFile(dest); err == nil && bytes.Equal(existing, data) {
+		return dest, nil
+	}
+
+	// #nosec G306 -- the CA certificate is public and read by dev containers
+	if err := os.WriteFile(dest, data, 0o644); err != nil {
+		return "", fmt.Errorf("dev: mkcert: copy root CA: %w", err)
+	}
+	return dest, nil
+}
@@ -37,6 +37,10 @@ type CertConfig struct {
 	// For v1 we assume a single cert covering all domains.
 	CertFile string
 	KeyFile  string
+
+	// CAFile is a copy of the mkcert root CA inside CertDir, or "" when
+	// mkcert's CA root could not be found.
+	CAFile string
 }
 
 // Writer is the minimal writer abstraction used by commands/loggers.
@@ -131,12 +135,17 @@ func (g *Generator) EnsureCertificates(
 
 	// If both cert and key files already exist, reuse them without invoking mkcert.
 	if fileExists(certPath) && fileExists(keyPath) {
+		caFile, err := syncCAFile(certDir)
+		if err != nil {
+			return nil, err
+		}
 		return &CertConfig{
 			Enabled:  true,
 			CertDir:  certDir,
 			Domains:  domains,
 			CertFile: certPath,
 			KeyFile:  keyPath,
+			CAFile:   caFile,
 		}, nil
 	}
 
@@ -174,15 +183,43 @@ func (g *Generator) EnsureCertificates(
 		return nil, fmt.Errorf("dev: mkcert failed: %w", err)
 	}
 
+	caFile, err := syncCAFile(certDir)
+	if err != nil {
+		return nil, err
+	}
+
 	return &CertConfig{
 		Enabled:  true,
 		CertDir:  certDir,
 		Domains:  domains,
 		CertFile: certPath,
 		KeyFile:  keyPath,
+		CAFile:   caFile,
 	}, nil
 }
 
+// PlannedCertificates returns the CertConfig EnsureCertificates would return
+// once certificates exist, without creating directories, running mkcert or
+// copying the CA. It lets callers render dev files side-effect free.
+func PlannedCertificates(opts Options) *CertConfig {
+	if !opts.EnableHTTPS {
+		return &CertConfig{Enabled: false}
+	}
+
+	certDir := filepath.Join(opts.DevDir, "certs")
+	certCfg := &CertConfig{
+		Enabled:  true,
+		CertDir:  certDir,
+		Domains:  dedupeAndSortDomains(opts.Domains),
+		CertFile: filepath.Join(certDir, "dev-local.pem"),
+		KeyFile:  filepath.Join(certDir, "dev-local-key.pem"),
+	}
+	if caRoot := CARoot(); caRoot != "" && fileExists(filepath.Join(caRoot, CAFileName)) {
+		certCfg.CAFile = filepath.Join(certDir, CAFileName)
+	}
+	return certCfg
+}
+
 // defaultExecCommander is the production ExecCommander backed by os/exec.
 type defaultExecCommander struct {
 	dir string
@@ -270,3 +270,83 @@ func TestEnsureCertificates_InvokesMkcertWithSortedDomains(t *testing.T) {
 		t.Fatalf("expected domains to be sorted in mkcert args, got %q", gotArgs)
 	}
 }
+
+func TestEnsureCertificates_CopiesRootCA(t *testing.T) {
+	tmpDir := t.TempDir()
+	certDir := filepath.Join(tmpDir, "certs")
+	caRoot := filepath.Join(tmpDir, "caroot")
+
+	// #nosec G301 -- test directory permissions
+	for _, dir := range []string{certDir, caRoot} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+	// #nosec G306 -- test file permissions
+	if err := os.WriteFile(filepath.Join(caRoot, CAFileName), []byte("dummy-ca"), 0o644); err != nil {
+		t.Fatalf("write CA: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(certDir, "dev-local.pem"), []byte("dummy-cert"), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(certDir, "dev-local-key.pem"), []byte("dummy-key"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	t.Setenv("CAROOT", caRoot)
+
+	gen := NewGeneratorWithDeps(&fakeExecCommander{cmd: &fakeCommand{}}, &fakeLogger{})
+
+	certCfg, err := gen.EnsureCertificates(context.Background(), &config.Config{}, Options{
+		DevDir:      tmpDir,
+		Domains:     []string{"app.localdev.test"},
+		EnableHTTPS: true,
+	})
+	if err != nil {
+		t.Fatalf("EnsureCertificates returned error: %v", err)
+	}
+
+	wantCA := filepath.Join(certDir, CAFileName)
+	if certCfg.CAFile != wantCA {
+		t.Fatalf("expected CAFile=%q, got %q", wantCA, certCfg.CAFile)
+	}
+	data, err := os.ReadFile(wantCA)
+	if err != nil {
+		t.Fatalf("reading copied CA: %v", err)
+	}
+	if string(data) != "dummy-ca" {
+		t.Fatalf("copied CA = %q, want dummy-ca", data)
+	}
+}
+
+func TestPlannedCertificates_TouchesNothing(t *testing.T) {
+	caRoot := t.TempDir()
+	t.Setenv("CAROOT", caRoot)
+	if err := os.WriteFile(filepath.Join(caRoot, CAFileName), []byte("ca"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	devDir := filepath.Join(t.TempDir(), "dev")
+	cfg := PlannedCertificates(Options{
+		DevDir:      devDir,
+		Domains:     []string{"b.test", "a.test", "a.test"},
+		EnableHTTPS: true,
+	})
+
+	certDir := filepath.Join(devDir, "certs")
+	if !cfg.Enabled || cfg.CertFile != filepath.Join(certDir, "dev-local.pem") || cfg.KeyFile != filepath.Join(certDir, "dev-local-key.pem") {
+		t.Errorf("unexpected cert paths: %+v", cfg)
+	}
+	if cfg.CAFile != filepath.Join(certDir, CAFileName) {
+		t.Errorf("CAFile = %q, want copy inside cert dir", cfg.CAFile)
+	}
+	if strings.Join(cfg.Domains, ",") != "a.test,b.test" {
+		t.Errorf("Domains = %v, want sorted and deduplicated", cfg.Domains)
+	}
+	if _, err := os.Stat(devDir); !os.IsNotExist(err) {
+		t.Errorf("expected no dev dir to be created, stat: %v", err)
+	}
+
+	if disabled := PlannedCertificates(Options{DevDir: devDir}); disabled.Enabled {
+		t.Error("expected disabled config when HTTPS is off")
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"stagecraft/internal/containerruntime"
 )
 
 // Options captures process management settings.
@@ -21,6 +23,29 @@ type Options struct {
 	NoTraefik bool
 	Detach    bool
 	Verbose   bool
+
+	// Runtime is the container CLI to run compose with; docker when empty.
+	Runtime containerruntime.Runtime
+
+	// DockerContext, when set, is passed as `docker --context`.
+	DockerContext string
+
+	// Stdout and Stderr receive `compose up` output; os.Stdout and
+	// os.Stderr when nil.
+	Stdout Writer
+	Stderr Writer
+}
+
+// output returns the writers for `compose up` output.
+func (o Options) output() (stdout, stderr Writer) {
+	stdout, stderr = o.Stdout, o.Stderr
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	return stdout, stderr
 }
 
 // Writer is the minimal writer abstraction used by Command.
@@ -125,15 +150,16 @@ func (r *Runner) runDetached(ctx context.Context, composePath string, opts Optio
 	args := buildUpArgs(composePath, opts, true)
 
 	if opts.Verbose {
-		r.log.Infof("dev: running (detached): docker %s", strings.Join(args, " "))
+		r.log.Infof("dev: running (detached): %s %s", opts.Runtime.Binary(), strings.Join(args, " "))
 	}
 
-	cmd := r.exec.CommandContext(ctx, "docker", args...)
-	cmd.SetStdout(os.Stdout)
-	cmd.SetStderr(os.Stderr)
+	stdout, stderr := opts.output()
+	cmd := r.exec.CommandContext(ctx, opts.Runtime.Binary(), args...)
+	cmd.SetStdout(stdout)
+	cmd.SetStderr(stderr)
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("dev: docker compose up -d failed: %w", err)
+		return fmt.Errorf("dev: %s compose up -d failed: %w", opts.Runtime.Binary(), err)
 	}
 
 	r.log.Infof("dev: dev stack started in background")
@@ -146,15 +172,16 @@ func (r *Runner) runForeground(ctx context.Context, composePath string, opts Opt
 	args := buildUpArgs(composePath, opts, false)
 
 	if opts.Verbose {
-		r.log.Infof("dev: running (foreground): docker %s", strings.Join(args, " "))
+		r.log.Infof("dev: running (foreground): %s %s", opts.Runtime.Binary(), strings.Join(args, " "))
 	}
 
-	cmd := r.exec.CommandContext(ctx, "docker", args...)
-	cmd.SetStdout(os.Stdout)
-	cmd.SetStderr(os.Stderr)
+	stdout, stderr := opts.output()
+	cmd := r.exec.CommandContext(ctx, opts.Runtime.Binary(), args...)
+	cmd.SetStdout(stdout)
+	cmd.SetStderr(stderr)
 
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("dev: docker compose up failed to start: %w", err)
+		return fmt.Errorf("dev: %s compose up failed to start: %w", opts.Runtime.Binary(), err)
 	}
 
 	errCh := make(chan error, 1)
@@ -165,7 +192,7 @@ func (r *Runner) runForeground(ctx context.Context, composePath string, opts Opt
 	select {
 	case <-ctx.Done():
 		// User initiated interruption - tear down the stack.
-		r.log.Infof("dev: context cancelled; tearing down dev stack with docker compose down")
+		r.log.Infof("dev: context cancelled; tearing down dev stack with %s compose down", opts.Runtime.Binary())
 
 		downErr := r.runDown(composePath, opts)
 		if downErr != nil {
@@ -177,7 +204,7 @@ func (r *Runner) runForeground(ctx context.Context, composePath string, opts Opt
 
 	case err := <-errCh:
 		if err != nil {
-			return fmt.Errorf("dev: docker compose up failed: %w", err)
+			return fmt.Errorf("dev: %s compose up failed: %w", opts.Runtime.Binary(), err)
 		}
 		return nil
 	}
@@ -188,25 +215,25 @@ func (r *Runner) runForeground(ctx context.Context, composePath string, opts Opt
 // It is best-effort; errors are returned so the caller can decide how
 // to handle them.
 func (r *Runner) runDown(composePath string, opts Options) error {
-	args := []string{
+	args := append(globalArgs(opts),
 		"compose",
 		"-f", composePath,
 		"down",
-	}
+	)
 
 	if opts.Verbose {
-		r.log.Infof("dev: running teardown: docker %s", strings.Join(args, " "))
+		r.log.Infof("dev: running teardown: %s %s", opts.Runtime.Binary(), strings.Join(args, " "))
 	}
 
 	ctx := context.Background()
-	cmd := r.exec.CommandContext(ctx, "docker", args...)
+	cmd := r.exec.CommandContext(ctx, opts.Runtime.Binary(), args...)
 	// For teardown, stdout is usually not critical, but we still wire it
 	// to stderr/stdout for transparency.
 	cmd.SetStdout(os.Stdout)
 	cmd.SetStderr(os.Stderr)
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("dev: docker compose down failed: %w", err)
+		return fmt.Errorf("dev: %s compose down failed: %w", opts.Runtime.Binary(), err)
 	}
 
 	return nil
@@ -215,11 +242,11 @@ func (r *Runner) runDown(composePath string, opts Options) error {
 // buildUpArgs builds arguments for `docker compose up`, with optional -d
 // and Traefik scaling based on options.
 func buildUpArgs(composePath string, opts Options, detach bool) []string {
-	args := []string{
+	args := append(globalArgs(opts),
 		"compose",
 		"-f", composePath,
 		"up",
-	}
+	)
 
 	if detach {
 		args = append(args, "-d")
@@ -232,6 +259,14 @@ func buildUpArgs(composePath string, opts Options, detach bool) []string {
 	return args
 }
 
+// globalArgs returns CLI flags that precede the compose subcommand.
+func globalArgs(opts Options) []string {
+	if opts.DockerContext == "" {
+		return nil
+	}
+	return []string{"--context", opts.DockerContext}
+}
+
 // defaultExecCommander is the production ExecCommander backed by os/exec.
 type defaultExecCommander struct{}
 
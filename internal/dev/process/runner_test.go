@@ -191,6 +191,33 @@ func TestRunner_ForegroundBuildsExpectedCommand(t *testing.T) {
 	}
 }
 
+func TestRunner_ForegroundUsesOutputWriters(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// #nosec G306 -- test file permissions
+	if err := os.WriteFile(filepath.Join(tmpDir, "compose.yaml"), []byte("version: '3.8'\n"), 0o644); err != nil {
+		t.Fatalf("write compose file: %v", err)
+	}
+
+	execFake := &fakeExecCommander{}
+	r := NewRunnerWithDeps(execFake, &fakeLogger{})
+
+	var stdout, stderr strings.Builder
+	opts := Options{
+		DevDir: tmpDir,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+
+	if err := r.Run(context.Background(), opts); err != nil {
+		t.Fatalf("Run returned unexpected error: %v", err)
+	}
+
+	if execFake.cmd.stdout != &stdout || execFake.cmd.stderr != &stderr {
+		t.Errorf("expected compose up output to go to the configured writers")
+	}
+}
+
 func TestRunner_MissingComposeFileFails(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -228,3 +255,51 @@ func TestRunner_EmptyDevDirFails(t *testing.T) {
 		t.Errorf("expected error about dev dir, got %v", err)
 	}
 }
+
+func TestRunner_DockerContextAndRuntime(t *testing.T) {
+	tmpDir := t.TempDir()
+	composePath := filepath.Join(tmpDir, "compose.yaml")
+
+	// #nosec G306 -- test file permissions
+	if err := os.WriteFile(composePath, []byte("version: '3.8'\n"), 0o644); err != nil {
+		t.Fatalf("write compose file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		wantName string
+		wantArgs string
+	}{
+		{
+			name:     "pinned docker context",
+			opts:     Options{DevDir: tmpDir, Detach: true, DockerContext: "colima"},
+			wantName: "docker",
+			wantArgs: "--context colima compose -f " + composePath + " up -d",
+		},
+		{
+			name:     "podman",
+			opts:     Options{DevDir: tmpDir, Detach: true, Runtime: "podman"},
+			wantName: "podman",
+			wantArgs: "compose -f " + composePath + " up -d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execFake := &fakeExecCommander{}
+			r := NewRunnerWithDeps(execFake, &fakeLogger{})
+
+			if err := r.Run(context.Background(), tt.opts); err != nil {
+				t.Fatalf("Run returned unexpected error: %v", err)
+			}
+
+			if execFake.lastName != tt.wantName {
+				t.Errorf("command = %q, want %q", execFake.lastName, tt.wantName)
+			}
+			if got := strings.Join(execFake.lastArgs, " "); got != tt.wantArgs {
+				t.Errorf("args = %q, want %q", got, tt.wantArgs)
+			}
+		})
+	}
+}
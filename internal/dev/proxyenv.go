@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Feature: DEV_PROXY_ENV
+Specs: spec/dev/proxy-env.md
+*/
+
+package dev
+
+import (
+	"path/filepath"
+
+	devcompose "stagecraft/internal/dev/compose"
+	devmkcert "stagecraft/internal/dev/mkcert"
+)
+
+// Env vars injected into dev services so apps don't hard-code their public URLs.
+const (
+	EnvFrontendURL     = "STAGECRAFT_FRONTEND_URL"
+	EnvBackendURL      = "STAGECRAFT_BACKEND_URL"
+	EnvViteAppURL      = "VITE_APP_URL"
+	EnvViteAPIURL      = "VITE_API_URL"
+	EnvNodeExtraCACert = "NODE_EXTRA_CA_CERTS"
+)
+
+const (
+	// certsHostPath is the cert directory as mounted by DEV_COMPOSE_INFRA.
+	certsHostPath = "./.stagecraft/dev/certs"
+
+	// certsContainerPath is where the cert directory is mounted in app services.
+	certsContainerPath = "/certs"
+)
+
+// PublicURLs returns the Traefik-routed URLs for the frontend and backend.
+// The scheme is https when certificates are enabled.
+func PublicURLs(domains Domains, certCfg *devmkcert.CertConfig) (frontendURL, backendURL string) {
+	scheme := "http"
+	if certCfg != nil && certCfg.Enabled {
+		scheme = "https"
+	}
+	return scheme + "://" + domains.Frontend, scheme + "://" + domains.Backend
+}
+
+// injectProxyEnv adds the public URLs and, when available, the local CA to
+// every backend service and the frontend service. Values already set in provider config
+// win, so users can still override any of them.
+func injectProxyEnv(
+	domains Domains,
+	backends []*devcompose.ServiceDefinition,
+	frontend *devcompose.ServiceDefinition,
+	certCfg *devmkcert.CertConfig,
+) {
+	frontendURL, backendURL := PublicURLs(domains, certCfg)
+
+	common := map[string]string{
+		EnvFrontendURL: frontendURL,
+		EnvBackendURL:  backendURL,
+	}
+	var caFile string
+	if certCfg != nil && certCfg.Enabled && certCfg.CAFile != "" {
+		caFile = certsContainerPath + "/" + filepath.Base(certCfg.CAFile)
+		common[EnvNodeExtraCACert] = caFile
+	}
+
+	for _, backend := range backends {
+		if backend == nil {
+			continue
+		}
+		mergeServiceEnv(backend, common)
+		if caFile != "" {
+			mountCerts(backend)
+		}
+	}
+
+	if frontend != nil {
+		mergeServiceEnv(frontend, common)
+		mergeServiceEnv(frontend, map[string]string{
+			EnvViteAppURL: frontendURL,
+			EnvViteAPIURL: backendURL,
+		})
+		if caFile != "" {
+			mountCerts(frontend)
+		}
+	}
+}
+
+// mergeServiceEnv sets each variable that the service does not already define.
+func mergeServiceEnv(svc *devcompose.ServiceDefinition, vars map[string]string) {
+	if svc.Environment == nil {
+		svc.Environment = make(map[string]string, len(vars))
+	}
+	for k, v := range vars {
+		if _, ok := svc.Environment[k]; !ok {
+			svc.Environment[k] = v
+		}
+	}
+}
+
+// mountCerts mounts the dev cert directory read-only, unless the service
+// already mounts something at that path.
+func mountCerts(svc *devcompose.ServiceDefinition) {
+	for _, v := range svc.Volumes {
+		if v.Target == certsContainerPath {
+			return
+		}
+	}
+	svc.Volumes = append(svc.Volumes, devcompose.VolumeMapping{
+		Type:     "bind",
+		Source:   certsHostPath,
+		Target:   certsContainerPath,
+		ReadOnly: true,
+	})
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package dev
+
+import (
+	"testing"
+
+	devcompose "stagecraft/internal/dev/compose"
+	devmkcert "stagecraft/internal/dev/mkcert"
+)
+
+// Feature: DEV_PROXY_ENV
+// Spec: spec/dev/proxy-env.md
+
+func TestInjectProxyEnv_HTTPSWithCA(t *testing.T) {
+	domains := Domains{Frontend: "app.localdev.test", Backend: "api.localdev.test"}
+	backend := &devcompose.ServiceDefinition{Name: "backend"}
+	frontend := &devcompose.ServiceDefinition{Name: "frontend"}
+	certCfg := &devmkcert.CertConfig{Enabled: true, CAFile: ".stagecraft/dev/certs/rootCA.pem"}
+
+	injectProxyEnv(domains, []*devcompose.ServiceDefinition{backend}, frontend, certCfg)
+
+	wantFrontend := map[string]string{
+		EnvFrontendURL:     "https://app.localdev.test",
+		EnvBackendURL:      "https://api.localdev.test",
+		EnvViteAppURL:      "https://app.localdev.test",
+		EnvViteAPIURL:      "https://api.localdev.test",
+		EnvNodeExtraCACert: "/certs/rootCA.pem",
+	}
+	for k, want := range wantFrontend {
+		if got := frontend.Environment[k]; got != want {
+			t.Errorf("frontend %s = %q, want %q", k, got, want)
+		}
+	}
+
+	if _, ok := backend.Environment[EnvViteAPIURL]; ok {
+		t.Errorf("backend should not receive VITE_* variables")
+	}
+	if got := backend.Environment[EnvNodeExtraCACert]; got != "/certs/rootCA.pem" {
+		t.Errorf("backend %s = %q, want /certs/rootCA.pem", EnvNodeExtraCACert, got)
+	}
+
+	for _, svc := range []*devcompose.ServiceDefinition{backend, frontend} {
+		if len(svc.Volumes) != 1 || svc.Volumes[0].Target != "/certs" || !svc.Volumes[0].ReadOnly {
+			t.Errorf("%s volumes = %+v, want read-only /certs mount", svc.Name, svc.Volumes)
+		}
+	}
+}
+
+func TestInjectProxyEnv_HTTPWithoutCerts(t *testing.T) {
+	domains := Domains{Frontend: "app.localdev.test", Backend: "api.localdev.test"}
+	frontend := &devcompose.ServiceDefinition{Name: "frontend"}
+
+	injectProxyEnv(domains, nil, frontend, &devmkcert.CertConfig{Enabled: false})
+
+	if got := frontend.Environment[EnvViteAPIURL]; got != "http://api.localdev.test" {
+		t.Errorf("%s = %q, want http://api.localdev.test", EnvViteAPIURL, got)
+	}
+	if _, ok := frontend.Environment[EnvNodeExtraCACert]; ok {
+		t.Errorf("expected no %s without certificates", EnvNodeExtraCACert)
+	}
+	if len(frontend.Volumes) != 0 {
+		t.Errorf("expected no volumes, got %+v", frontend.Volumes)
+	}
+}
+
+func TestInjectProxyEnv_UserValuesWin(t *testing.T) {
+	domains := Domains{Frontend: "app.localdev.test", Backend: "api.localdev.test"}
+	frontend := &devcompose.ServiceDefinition{
+		Name:        "frontend",
+		Environment: map[string]string{EnvViteAPIURL: "https://staging.example.com"},
+		Volumes: []devcompose.VolumeMapping{
+			{Type: "bind", Source: "./my-certs", Target: "/certs", ReadOnly: true},
+		},
+	}
+	certCfg := &devmkcert.CertConfig{Enabled: true, CAFile: "rootCA.pem"}
+
+	injectProxyEnv(domains, nil, frontend, certCfg)
+
+	if got := frontend.Environment[EnvViteAPIURL]; got != "https://staging.example.com" {
+		t.Errorf("%s = %q, want user value preserved", EnvViteAPIURL, got)
+	}
+	if got := frontend.Environment[EnvViteAppURL]; got != "https://app.localdev.test" {
+		t.Errorf("%s = %q, want https://app.localdev.test", EnvViteAppURL, got)
+	}
+	if len(frontend.Volumes) != 1 || frontend.Volumes[0].Source != "./my-certs" {
+		t.Errorf("expected existing /certs mount to be kept, got %+v", frontend.Volumes)
+	}
+}
@@ -12,6 +12,7 @@ package dev
 
 import (
 	"fmt"
+	"sort"
 
 	devcompose "stagecraft/internal/dev/compose"
 	devmkcert "stagecraft/internal/dev/mkcert"
@@ -46,7 +47,8 @@ type Topology struct {
 	Compose        *corecompose.ComposeFile
 	Traefik        *devtraefik.Config
 	Domains        Domains
-	Backend        *devcompose.ServiceDefinition
+	Backend        *devcompose.ServiceDefinition   // primary backend, routed on Domains.Backend
+	Backends       []*devcompose.ServiceDefinition // all backend services in name order
 	Frontend       *devcompose.ServiceDefinition
 	TraefikService *devcompose.ServiceDefinition
 }
@@ -117,10 +119,35 @@ func (b *Builder) Build(
 	traefikService *devcompose.ServiceDefinition,
 	certCfg *devmkcert.CertConfig,
 ) (*Topology, error) {
+	var backends []*devcompose.ServiceDefinition
+	if backend != nil {
+		backends = []*devcompose.ServiceDefinition{backend}
+	}
+	return b.BuildServices(cfg, domains, backends, frontend, traefikService, certCfg)
+}
+
+// BuildServices is Build for projects with several backend services
+// (CORE_BACKEND_SERVICES). Every backend joins the compose model; only the
+// primary backend is routed on the backend domain.
+func (b *Builder) BuildServices(
+	cfg *config.Config,
+	domains Domains,
+	backends []*devcompose.ServiceDefinition,
+	frontend *devcompose.ServiceDefinition,
+	traefikService *devcompose.ServiceDefinition,
+	certCfg *devmkcert.CertConfig,
+) (*Topology, error) {
+	backend := primaryBackend(cfg, backends)
+
+	// DEV_PROXY_ENV: public URLs only exist when Traefik routes the domains.
+	if traefikService != nil {
+		injectProxyEnv(domains, backends, frontend, certCfg)
+	}
+
 	// DEV_COMPOSE_INFRA already validates that backend is required.
-	composeFile, err := b.composeGen.GenerateCompose(
+	composeFile, err := b.composeGen.GenerateComposeServices(
 		cfg,
-		backend,
+		backends,
 		frontend,
 		traefikService,
 	)
@@ -148,6 +175,8 @@ func (b *Builder) Build(
 		if err != nil {
 			return nil, fmt.Errorf("dev topology: generate traefik config: %w", err)
 		}
+
+		balanceReplicas(traefikCfg, "backend", backend, backendPort)
 	}
 
 	top := &Topology{
@@ -156,6 +185,7 @@ func (b *Builder) Build(
 		Traefik:        traefikCfg,
 		Domains:        domains,
 		Backend:        backend,
+		Backends:       backends,
 		Frontend:       frontend,
 		TraefikService: traefikService,
 	}
@@ -163,28 +193,68 @@ func (b *Builder) Build(
 	return top, nil
 }
 
+// primaryBackend picks the backend routed on the backend domain: the
+// service named by backend.primary when present, otherwise the first one.
+func primaryBackend(cfg *config.Config, backends []*devcompose.ServiceDefinition) *devcompose.ServiceDefinition {
+	if len(backends) == 0 {
+		return nil
+	}
+	if cfg != nil && cfg.Backend.IsMultiService() {
+		if primary, ok := cfg.Backend.PrimaryService(); ok {
+			for _, svc := range backends {
+				if svc != nil && svc.Name == primary.Name {
+					return svc
+				}
+			}
+		}
+	}
+	return backends[0]
+}
+
 // ResolveServiceDefinitions resolves backend and frontend providers from config
 // and extracts their dev service definitions.
 //
 // For v1, this extracts basic service info (name, ports from env vars).
 // Future slices can enhance this to extract more detailed service configuration.
+// With several backend services only the primary one is returned; use
+// ResolveServices to get all of them.
 func (b *Builder) ResolveServiceDefinitions(
 	cfg *config.Config,
 	env string,
 ) (backend, frontend *devcompose.ServiceDefinition, err error) {
-	// Resolve backend service
-	if cfg.Backend != nil {
-		backendProvider, err := b.backendReg.Get(cfg.Backend.Provider)
+	backends, frontend, err := b.ResolveServices(cfg, env)
+	if err != nil {
+		return nil, nil, err
+	}
+	return primaryBackend(cfg, backends), frontend, nil
+}
+
+// ResolveServices resolves every backend service, in name order, and the
+// frontend service from config.
+func (b *Builder) ResolveServices(
+	cfg *config.Config,
+	env string,
+) (backends []*devcompose.ServiceDefinition, frontend *devcompose.ServiceDefinition, err error) {
+	// Resolve backend services
+	for _, svc := range cfg.Backend.ServiceList() {
+		backendProvider, err := b.backendReg.Get(svc.Provider)
 		if err != nil {
-			return nil, nil, fmt.Errorf("resolve backend provider %q: %w", cfg.Backend.Provider, err)
+			return nil, nil, fmt.Errorf("resolve backend provider %q: %w", svc.Provider, err)
 		}
 
-		providerCfg, err := cfg.Backend.GetProviderConfig()
+		providerCfg, err := svc.GetProviderConfig()
 		if err != nil {
 			return nil, nil, fmt.Errorf("get backend provider config: %w", err)
 		}
 
-		backend = b.extractBackendServiceDefinition(backendProvider, providerCfg)
+		backendSvc := b.extractBackendServiceDefinition(svc, backendProvider, providerCfg)
+		backendSvc.Replicas = cfg.Environments[env].ReplicasFor(svc.Name)
+		if build := cfg.Environments[env].Build; build.HasBuildInputs() {
+			// CLI_BUILD: build the service with the environment's build args,
+			// target stage, and secrets
+			backendSvc.Build, backendSvc.BuildSecrets = devBuild(svc, providerCfg, build)
+		}
+		backends = append(backends, backendSvc)
 	}
 
 	// Resolve frontend service
@@ -202,22 +272,29 @@ func (b *Builder) ResolveServiceDefinitions(
 		frontend = b.extractFrontendServiceDefinition(frontendProvider, providerCfg)
 	}
 
-	return backend, frontend, nil
+	return backends, frontend, nil
 }
 
 // extractBackendServiceDefinition extracts a ServiceDefinition from backend provider config.
 // For v1, this is intentionally minimal - it extracts:
-// - Service name: "backend"
-// - Ports: from PORT env var if present in provider config
+// - Service name: "backend", or the service name from backend.services
+// - Ports: from PORT env var if present in provider config (never for workers)
 // - Environment: from provider config env section
 //
 // Future slices can enhance this to extract image, build, volumes, etc.
 func (b *Builder) extractBackendServiceDefinition(
+	backendSvc config.BackendService,
 	_ backendproviders.BackendProvider,
 	providerCfg any,
 ) *devcompose.ServiceDefinition {
 	svc := &devcompose.ServiceDefinition{
-		Name: "backend",
+		Name: backendSvc.Name,
+	}
+
+	// Workers consume from queues; they publish no ports and get no route.
+	worker := backendSvc.IsWorker()
+	if worker {
+		svc.Labels = map[string]string{config.ServiceRoleLabel: config.ServiceRoleWorker}
 	}
 
 	// For v1, extract basic info from generic provider config structure.
@@ -236,7 +313,7 @@ func (b *Builder) extractBackendServiceDefinition(
 				svc.Environment = env
 
 				// Extract PORT from env to create port mapping
-				if portStr, ok := env["PORT"]; ok && portStr != "" {
+				if portStr, ok := env["PORT"]; ok && portStr != "" && !worker {
 					svc.Ports = []devcompose.PortMapping{
 						{
 							Host:      portStr,
@@ -338,6 +415,29 @@ func firstContainerPort(svc *devcompose.ServiceDefinition) string {
 	return svc.Ports[0].Container
 }
 
+// balanceReplicas points a Traefik service at every instance of a
+// replicated compose service (DEPLOY_SERVICE_REPLICAS).
+func balanceReplicas(traefikCfg *devtraefik.Config, traefikService string, svc *devcompose.ServiceDefinition, port string) {
+	if traefikCfg == nil || traefikCfg.Dynamic == nil || traefikCfg.Dynamic.HTTP == nil || svc == nil {
+		return
+	}
+	names := svc.InstanceNames()
+	if len(names) < 2 {
+		return
+	}
+	lbSvc, ok := traefikCfg.Dynamic.HTTP.Services[traefikService]
+	if !ok || lbSvc.LoadBalancer == nil {
+		return
+	}
+
+	servers := make([]devtraefik.ServerConfig, 0, len(names))
+	for _, name := range names {
+		servers = append(servers, devtraefik.ServerConfig{URL: fmt.Sprintf("http://%s:%s", name, port)})
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].URL < servers[j].URL })
+	lbSvc.LoadBalancer.Servers = servers
+}
+
 func frontendName(svc *devcompose.ServiceDefinition) string {
 	if svc == nil || svc.Name == "" {
 		return ""
@@ -252,3 +252,109 @@ func TestBuilder_Build_NoTraefik(t *testing.T) {
 		t.Errorf("Topology.TraefikService = %v, want nil when traefikSvc is nil", top.TraefikService)
 	}
 }
+
+func TestBuilder_BuildServices_RoutesPrimaryBackend(t *testing.T) {
+	cfg := &config.Config{
+		Backend: &config.BackendConfig{
+			Primary: "gateway",
+			Services: map[string]config.BackendServiceConfig{
+				"billing": {Provider: "generic"},
+				"gateway": {Provider: "generic"},
+			},
+		},
+	}
+	domains := Domains{Frontend: "app.localdev.test", Backend: "api.localdev.test"}
+
+	backends := []*devcompose.ServiceDefinition{
+		{Name: "billing", Ports: []devcompose.PortMapping{{Host: "4001", Container: "4001", Protocol: "tcp"}}},
+		{Name: "gateway", Ports: []devcompose.PortMapping{{Host: "4000", Container: "4000", Protocol: "tcp"}}},
+	}
+
+	top, err := NewDefaultBuilder().BuildServices(cfg, domains, backends, nil, &devcompose.ServiceDefinition{Name: "traefik"}, nil)
+	if err != nil {
+		t.Fatalf("BuildServices() error = %v", err)
+	}
+
+	services := top.Compose.GetServices()
+	for _, want := range []string{"billing", "gateway", "traefik"} {
+		if !containsName(services, want) {
+			t.Errorf("compose services = %v, missing %q", services, want)
+		}
+	}
+
+	if top.Backend == nil || top.Backend.Name != "gateway" {
+		t.Fatalf("Topology.Backend = %+v, want gateway", top.Backend)
+	}
+	if len(top.Backends) != 2 {
+		t.Fatalf("Topology.Backends = %d, want 2", len(top.Backends))
+	}
+
+	backendSvc, ok := top.Traefik.Dynamic.HTTP.Services["backend"]
+	if !ok || backendSvc.LoadBalancer.Servers[0].URL != "http://gateway:4000" {
+		t.Fatalf("backend route = %+v, want http://gateway:4000", backendSvc)
+	}
+
+	// Every backend gets the proxy env, not just the routed one.
+	for _, svc := range top.Backends {
+		if svc.Environment[EnvBackendURL] != "http://api.localdev.test" {
+			t.Errorf("%s %s = %q", svc.Name, EnvBackendURL, svc.Environment[EnvBackendURL])
+		}
+	}
+}
+
+func TestBuilder_BuildServices_BalancesReplicas(t *testing.T) {
+	domains := Domains{Frontend: "app.localdev.test", Backend: "api.localdev.test"}
+	backend := &devcompose.ServiceDefinition{
+		Name:     "backend",
+		Ports:    []devcompose.PortMapping{{Host: "4000", Container: "4000", Protocol: "tcp"}},
+		Replicas: 2,
+	}
+
+	top, err := NewDefaultBuilder().BuildServices(&config.Config{}, domains, []*devcompose.ServiceDefinition{backend}, nil, &devcompose.ServiceDefinition{Name: "traefik"}, nil)
+	if err != nil {
+		t.Fatalf("BuildServices() error = %v", err)
+	}
+
+	servers := top.Traefik.Dynamic.HTTP.Services["backend"].LoadBalancer.Servers
+	var urls []string
+	for _, s := range servers {
+		urls = append(urls, s.URL)
+	}
+	want := []string{"http://backend-1:4000", "http://backend-2:4000"}
+	if len(urls) != len(want) || urls[0] != want[0] || urls[1] != want[1] {
+		t.Fatalf("backend servers = %v, want %v", urls, want)
+	}
+}
+
+func TestBuilder_ExtractBackendServiceDefinition_Worker(t *testing.T) {
+	providerCfg := map[string]any{
+		"dev": map[string]any{
+			"env": map[string]any{"PORT": "4100", "QUEUE": "jobs"},
+		},
+	}
+	worker := config.BackendService{
+		Name:                 "consumer",
+		BackendServiceConfig: config.BackendServiceConfig{Provider: "generic", Role: config.ServiceRoleWorker},
+	}
+
+	svc := NewDefaultBuilder().extractBackendServiceDefinition(worker, nil, providerCfg)
+
+	if len(svc.Ports) != 0 {
+		t.Errorf("worker Ports = %v, want none", svc.Ports)
+	}
+	if svc.Labels[config.ServiceRoleLabel] != config.ServiceRoleWorker {
+		t.Errorf("worker Labels = %v, want %s=%s", svc.Labels, config.ServiceRoleLabel, config.ServiceRoleWorker)
+	}
+	if svc.Environment["QUEUE"] != "jobs" {
+		t.Errorf("worker Environment = %v, want QUEUE=jobs", svc.Environment)
+	}
+}
+
+func containsName(names []string, want string) bool {
+	for _, n := range names {
+		if n == want {
+			return true
+		}
+	}
+	return false
+}
@@ -32,7 +32,7 @@ func NewGenerator() *Generator {
 //
 // This is a thin v1 slice that:
 // - Configures web / websecure entry points
-// - Configures docker provider bound to the "stagecraft-dev" network
+// - Configures docker provider bound to the "stagecraft-dev" network (not for podman)
 // - Creates one frontend router+service and one backend router+service
 // - Wires TLS configuration from certCfg when enabled
 //
@@ -49,8 +49,6 @@ func (g *Generator) GenerateConfig(
 	backendPort string,
 	certCfg *mkcert.CertConfig,
 ) (*Config, error) {
-	_ = cfg // v1: config not yet used
-
 	static := &StaticConfig{
 		EntryPoints: map[string]EntryPointConfig{
 			"web": {
@@ -60,15 +58,20 @@ func (g *Generator) GenerateConfig(
 				Address: ":443",
 			},
 		},
-		Providers: map[string]ProviderConfig{
-			"docker": {
-				Docker: &DockerProviderConfig{
-					Endpoint:         "unix:///var/run/docker.sock",
-					ExposedByDefault: false,
-					Network:          "stagecraft-dev",
-				},
+		Providers: map[string]ProviderConfig{},
+	}
+
+	// Routing comes from the file provider. The docker provider only adds
+	// label discovery through /var/run/docker.sock, which Podman does not
+	// serve (its socket is per-user when rootless), so podman omits it.
+	if cfg == nil || cfg.ContainerRuntime != config.ContainerRuntimePodman {
+		static.Providers["docker"] = ProviderConfig{
+			Docker: &DockerProviderConfig{
+				Endpoint:         "unix:///var/run/docker.sock",
+				ExposedByDefault: false,
+				Network:          "stagecraft-dev",
 			},
-		},
+		}
 	}
 
 	// Build TLS config if HTTPS enabled.
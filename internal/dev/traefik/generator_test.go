@@ -193,3 +193,26 @@ func TestGenerator_GenerateConfig_HTTPSDisabled_NoTLS(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerator_GenerateConfig_PodmanOmitsDockerProvider(t *testing.T) {
+	t.Helper()
+
+	gen := NewGenerator()
+
+	for runtime, wantDocker := range map[string]bool{
+		"":                            true,
+		config.ContainerRuntimeDocker: true,
+		config.ContainerRuntimePodman: false,
+	} {
+		cfg := &config.Config{ContainerRuntime: runtime}
+		out, err := gen.GenerateConfig(cfg, "app.localdev.test", "frontend", "3000", "api.localdev.test", "backend", "4000", nil)
+		if err != nil {
+			t.Fatalf("GenerateConfig(%q) error = %v, want nil", runtime, err)
+		}
+
+		_, hasDocker := out.Static.Providers["docker"]
+		if hasDocker != wantDocker {
+			t.Errorf("container_runtime %q: docker provider present = %v, want %v", runtime, hasDocker, wantDocker)
+		}
+	}
+}
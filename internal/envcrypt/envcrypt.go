@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package envcrypt detects, decrypts and encrypts dotenv files protected
+// with age or SOPS, so dev and deploy env files can be committed encrypted.
+//
+// Cryptography is delegated to the age and sops CLIs, like other external
+// tools Stagecraft drives.
+package envcrypt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CORE_ENV_ENCRYPTION
+// Spec: spec/core/env-encryption.md
+
+// Format identifies how an env file is protected.
+type Format string
+
+// Supported formats.
+const (
+	FormatPlain Format = ""
+	FormatAge   Format = "age"
+	FormatSOPS  Format = "sops"
+)
+
+// KeyFileEnv overrides the age identity file used for decryption.
+const KeyFileEnv = "STAGECRAFT_AGE_KEY_FILE"
+
+// sopsKeyFileEnv is the variable sops itself reads for age identities.
+const sopsKeyFileEnv = "SOPS_AGE_KEY_FILE"
+
+// ErrNoKey is returned when an age-encrypted file is found but no identity
+// file is available.
+var ErrNoKey = errors.New("no age identity found")
+
+// Detect reports how data is protected. SOPS dotenv files keep their keys
+// in the clear and carry sops_* metadata entries; age files are whole-file
+// ciphertext, binary or armored.
+func Detect(data []byte) Format {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("age-encryption.org/v1")) ||
+		bytes.HasPrefix(trimmed, []byte("-----BEGIN AGE ENCRYPTED FILE-----")) {
+		return FormatAge
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "sops_version=") || strings.HasPrefix(line, "sops_mac=") {
+			return FormatSOPS
+		}
+	}
+	return FormatPlain
+}
+
+// Crypter runs age and sops.
+type Crypter struct {
+	runner executil.Runner
+	getenv func(string) string
+}
+
+// New returns a Crypter using the default command runner.
+func New() *Crypter {
+	return NewWithRunner(executil.NewRunner())
+}
+
+// NewWithRunner returns a Crypter that runs commands through runner.
+func NewWithRunner(runner executil.Runner) *Crypter {
+	return &Crypter{runner: runner, getenv: os.Getenv}
+}
+
+// ReadFile reads path and decrypts it when encrypted.
+func ReadFile(ctx context.Context, path string) ([]byte, error) {
+	return New().ReadFile(ctx, path)
+}
+
+// ReadFile reads path and decrypts it when encrypted.
+func (c *Crypter) ReadFile(ctx context.Context, path string) ([]byte, error) {
+	//nolint:gosec // G304: env file paths come from stagecraft.yml or the command line
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Decrypt(ctx, path, data)
+}
+
+// Decrypt returns the plaintext of data, read from path. Plain files are
+// returned unchanged.
+func (c *Crypter) Decrypt(ctx context.Context, path string, data []byte) ([]byte, error) {
+	switch Detect(data) {
+	case FormatAge:
+		identity, ok := c.IdentityFile()
+		if !ok {
+			return nil, fmt.Errorf("decrypting %s: %w; set %s or create ~/.config/sops/age/keys.txt", path, ErrNoKey, KeyFileEnv)
+		}
+		return c.run(ctx, "age", "decrypting "+path, nil, "--decrypt", "-i", identity, path)
+	case FormatSOPS:
+		var env map[string]string
+		if identity, ok := c.IdentityFile(); ok {
+			env = map[string]string{sopsKeyFileEnv: identity}
+		}
+		return c.run(ctx, "sops", "decrypting "+path, env,
+			"--decrypt", "--input-type", "dotenv", "--output-type", "dotenv", path)
+	default:
+		return data, nil
+	}
+}
+
+// EncryptOptions configures Encrypt.
+type EncryptOptions struct {
+	// Format is FormatAge or FormatSOPS.
+	Format Format
+
+	// Recipients are age public keys. Required for FormatAge; for
+	// FormatSOPS they override creation rules in .sops.yaml.
+	Recipients []string
+}
+
+// Encrypt returns the encrypted form of the plaintext env file at path.
+func (c *Crypter) Encrypt(ctx context.Context, path string, opts EncryptOptions) ([]byte, error) {
+	//nolint:gosec // G304: path comes from the command line
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if format := Detect(data); format != FormatPlain {
+		return nil, fmt.Errorf("%s is already encrypted with %s", path, format)
+	}
+
+	switch opts.Format {
+	case FormatAge:
+		if len(opts.Recipients) == 0 {
+			return nil, fmt.Errorf("age encryption requires at least one recipient")
+		}
+		args := []string{"--encrypt", "--armor"}
+		for _, r := range opts.Recipients {
+			args = append(args, "-r", r)
+		}
+		return c.run(ctx, "age", "encrypting "+path, nil, append(args, path)...)
+	case FormatSOPS:
+		args := []string{"--encrypt", "--input-type", "dotenv", "--output-type", "dotenv"}
+		if len(opts.Recipients) > 0 {
+			args = append(args, "--age", strings.Join(opts.Recipients, ","))
+		}
+		return c.run(ctx, "sops", "encrypting "+path, nil, append(args, path)...)
+	default:
+		return nil, fmt.Errorf("unsupported format %q; use %q or %q", opts.Format, FormatAge, FormatSOPS)
+	}
+}
+
+// IdentityFile returns the age identity file: $STAGECRAFT_AGE_KEY_FILE,
+// then $SOPS_AGE_KEY_FILE, then the sops default location when it exists.
+func (c *Crypter) IdentityFile() (string, bool) {
+	for _, name := range []string{KeyFileEnv, sopsKeyFileEnv} {
+		if path := c.getenv(name); path != "" {
+			return path, true
+		}
+	}
+
+	configDir := c.getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home := c.getenv("HOME")
+		if home == "" {
+			return "", false
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+	path := filepath.Join(configDir, "sops", "age", "keys.txt")
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// run executes a tool and returns its stdout. Stderr is included in errors
+// but stdout never is, since it may hold plaintext secrets.
+func (c *Crypter) run(ctx context.Context, tool, action string, env map[string]string, args ...string) ([]byte, error) {
+	cmd := executil.NewCommand(tool, args...)
+	cmd.Env = env
+
+	result, err := c.runner.Run(ctx, cmd)
+	if err != nil {
+		stderr := ""
+		if result != nil {
+			stderr = strings.TrimSpace(string(result.Stderr))
+		}
+		if stderr != "" {
+			return nil, fmt.Errorf("%s with %s: %w: %s", action, tool, err, stderr)
+		}
+		return nil, fmt.Errorf("%s with %s: %w", action, tool, err)
+	}
+	return result.Stdout, nil
+}
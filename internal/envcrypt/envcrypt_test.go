@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package envcrypt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CORE_ENV_ENCRYPTION
+// Spec: spec/core/env-encryption.md
+
+type fakeRunner struct {
+	stdout string
+	err    error
+	cmds   []executil.Command
+}
+
+func (f *fakeRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	f.cmds = append(f.cmds, cmd)
+	return &executil.Result{Stdout: []byte(f.stdout), Stderr: []byte("boom")}, f.err
+}
+
+func (f *fakeRunner) RunStream(context.Context, executil.Command, io.Writer) error { //nolint:gocritic // Runner interface
+	return nil
+}
+
+func newTestCrypter(runner executil.Runner, env map[string]string) *Crypter {
+	c := NewWithRunner(runner)
+	c.getenv = func(name string) string { return env[name] }
+	return c
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Format
+	}{
+		{name: "plain", data: "API_KEY=abc\n", want: FormatPlain},
+		{name: "age binary", data: "age-encryption.org/v1\n-> X25519 abc\n", want: FormatAge},
+		{name: "age armored", data: "\n-----BEGIN AGE ENCRYPTED FILE-----\nYWdl\n-----END AGE ENCRYPTED FILE-----\n", want: FormatAge},
+		{name: "sops dotenv", data: "API_KEY=ENC[AES256_GCM,data:abc]\nsops_version=3.9.0\n", want: FormatSOPS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect([]byte(tt.data)); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrypter_Decrypt_PlainIsUnchanged(t *testing.T) {
+	runner := &fakeRunner{}
+	got, err := newTestCrypter(runner, nil).Decrypt(context.Background(), ".env", []byte("A=1\n"))
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(got) != "A=1\n" || len(runner.cmds) != 0 {
+		t.Errorf("plain files should be returned as-is without running tools")
+	}
+}
+
+func TestCrypter_Decrypt_Age(t *testing.T) {
+	runner := &fakeRunner{stdout: "A=1\n"}
+	c := newTestCrypter(runner, map[string]string{KeyFileEnv: "/keys/dev.txt"})
+
+	got, err := c.Decrypt(context.Background(), ".env", []byte("age-encryption.org/v1\n"))
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(got) != "A=1\n" {
+		t.Errorf("Decrypt() = %q", got)
+	}
+	if line := runner.cmds[0].Name + " " + strings.Join(runner.cmds[0].Args, " "); line != "age --decrypt -i /keys/dev.txt .env" {
+		t.Errorf("command = %q", line)
+	}
+}
+
+func TestCrypter_Decrypt_AgeWithoutKey(t *testing.T) {
+	c := newTestCrypter(&fakeRunner{}, map[string]string{"HOME": t.TempDir()})
+
+	_, err := c.Decrypt(context.Background(), ".env", []byte("age-encryption.org/v1\n"))
+	if !errors.Is(err, ErrNoKey) {
+		t.Fatalf("expected ErrNoKey, got %v", err)
+	}
+}
+
+func TestCrypter_Decrypt_SOPSPassesIdentity(t *testing.T) {
+	home := t.TempDir()
+	keys := filepath.Join(home, ".config", "sops", "age", "keys.txt")
+	if err := os.MkdirAll(filepath.Dir(keys), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keys, []byte("AGE-SECRET-KEY-1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &fakeRunner{stdout: "A=1\n"}
+	c := newTestCrypter(runner, map[string]string{"HOME": home})
+
+	if _, err := c.Decrypt(context.Background(), ".env", []byte("A=ENC[x]\nsops_version=3.9.0\n")); err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	cmd := runner.cmds[0]
+	if cmd.Name != "sops" || cmd.Env[sopsKeyFileEnv] != keys {
+		t.Errorf("sops command = %+v, want %s=%s", cmd, sopsKeyFileEnv, keys)
+	}
+}
+
+func TestCrypter_Decrypt_ErrorOmitsStdout(t *testing.T) {
+	runner := &fakeRunner{stdout: "SECRET=leaked", err: errors.New("exit 1")}
+	c := newTestCrypter(runner, map[string]string{KeyFileEnv: "/keys/dev.txt"})
+
+	_, err := c.Decrypt(context.Background(), ".env", []byte("age-encryption.org/v1\n"))
+	if err == nil || strings.Contains(err.Error(), "leaked") || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected stderr-only error, got %v", err)
+	}
+}
+
+func TestCrypter_Encrypt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("A=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &fakeRunner{stdout: "ciphertext"}
+	c := newTestCrypter(runner, nil)
+
+	if _, err := c.Encrypt(context.Background(), path, EncryptOptions{Format: FormatAge}); err == nil {
+		t.Error("age encryption without recipients should fail")
+	}
+
+	out, err := c.Encrypt(context.Background(), path, EncryptOptions{Format: FormatAge, Recipients: []string{"age1a", "age1b"}})
+	if err != nil || string(out) != "ciphertext" {
+		t.Fatalf("Encrypt() = %q, %v", out, err)
+	}
+	want := "age --encrypt --armor -r age1a -r age1b " + path
+	if line := runner.cmds[0].Name + " " + strings.Join(runner.cmds[0].Args, " "); line != want {
+		t.Errorf("command = %q, want %q", line, want)
+	}
+
+	if err := os.WriteFile(path, []byte("A=ENC[x]\nsops_version=3.9.0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Encrypt(context.Background(), path, EncryptOptions{Format: FormatSOPS}); err == nil || !strings.Contains(err.Error(), "already encrypted") {
+		t.Errorf("expected already encrypted error, got %v", err)
+	}
+}
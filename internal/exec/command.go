@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package exec
+
+import "context"
+
+// Feature: CORE_EXEC
+// Spec: spec/core/exec.md
+
+// Cmd is a command line kept as separate words. Commanders pass the words
+// on as argv, quoting each one for the remote shell, so no value reaches
+// a shell unquoted. Build commands with Command and Script rather than by
+// concatenating strings.
+//
+// Methods return a new Cmd and never modify the receiver.
+type Cmd struct {
+	Name string
+	Args []string
+}
+
+// Command returns the command that runs name with args, without a shell.
+func Command(name string, args ...string) Cmd {
+	return Cmd{Name: name, Args: append([]string(nil), args...)}
+}
+
+// Script returns a command that runs script with `sh -c`, for the rare
+// steps that need pipes or redirects. script must be a constant: values
+// are passed as the positional parameters $1, $2, ... and must be
+// referenced quoted ("$1"), so they are never parsed as shell syntax.
+func Script(script string, args ...string) Cmd {
+	return Command("sh", append([]string{"-c", script, "sh"}, args...)...)
+}
+
+// Arg returns c with args appended.
+func (c Cmd) Arg(args ...string) Cmd {
+	c.Args = append(c.Args[:len(c.Args):len(c.Args)], args...)
+	return c
+}
+
+// Flag returns c with "--name=value" appended as one word.
+func (c Cmd) Flag(name, value string) Cmd {
+	return c.Arg("--" + name + "=" + value)
+}
+
+// FlagIf returns c with "--name" appended when set is true.
+func (c Cmd) FlagIf(name string, set bool) Cmd {
+	if !set {
+		return c
+	}
+	return c.Arg("--" + name)
+}
+
+// String returns the quoted shell command line (see Join).
+func (c Cmd) String() string {
+	return Join(c.Name, c.Args...)
+}
+
+// Run runs c on host through commander.
+//
+//nolint:gocritic // unnamedResult: matches Commander
+func (c Cmd) Run(ctx context.Context, commander Commander, host string) (string, string, error) {
+	return commander.Run(ctx, host, c.Name, c.Args...)
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package exec
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Feature: CORE_EXEC
+// Spec: spec/core/exec.md
+
+func TestCmd_Builders(t *testing.T) {
+	base := Command("tailscale", "up")
+	got := base.Flag("hostname", "app-1").FlagIf("force-reauth", true).FlagIf("ssh", false).Arg("--reset")
+
+	want := []string{"up", "--hostname=app-1", "--force-reauth", "--reset"}
+	if got.Name != "tailscale" || !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("built %s %v, want tailscale %v", got.Name, got.Args, want)
+	}
+	if !reflect.DeepEqual(base.Args, []string{"up"}) {
+		t.Errorf("base modified: %v", base.Args)
+	}
+	if s := got.String(); s != "tailscale 'up' '--hostname=app-1' '--force-reauth' '--reset'" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestCmd_ArgDoesNotAlias(t *testing.T) {
+	base := Command("echo").Arg("a", "b")
+	first := base.Arg("first")
+	second := base.Arg("second")
+
+	if first.Args[2] != "first" || second.Args[2] != "second" {
+		t.Errorf("Arg() shared backing arrays: %v, %v", first.Args, second.Args)
+	}
+}
+
+func TestScript(t *testing.T) {
+	c := Script(`mkdir -p "$1"`, "/srv/app data")
+
+	want := []string{"-c", `mkdir -p "$1"`, "sh", "/srv/app data"}
+	if c.Name != "sh" || !reflect.DeepEqual(c.Args, want) {
+		t.Errorf("Script() = %s %v, want sh %v", c.Name, c.Args, want)
+	}
+
+	rec := NewRecordingCommander()
+	rec.Results[`web-1 mkdir -p "$1" /srv/app data`] = Result{Stdout: "ok"}
+	stdout, _, err := c.Run(context.Background(), rec, "web-1")
+	if err != nil || stdout != "ok" {
+		t.Errorf("Run() = %q, %v, want the script key with its parameters to match", stdout, err)
+	}
+}
+
+// runShell runs line with the local sh, as a remote login shell would run
+// a command line received over ssh, and returns its stdout.
+func runShell(t *testing.T, line string) string {
+	t.Helper()
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	out, err := exec.Command(sh, "-c", line).Output() // #nosec G204 -- test input
+	if err != nil {
+		t.Fatalf("sh -c %q: %v", line, err)
+	}
+	return string(out)
+}
+
+// printArgs prints each argument followed by a NUL byte.
+var printArgs = Command("printf", `%s\0`)
+
+func FuzzJoin(f *testing.F) {
+	for _, seed := range [][2]string{
+		{"plain", "two words"},
+		{"it's", `"double" and \back\slash`},
+		{"$(id)", "`id`; rm -rf / && echo $HOME"},
+		{"*", "a|b>c<d &"},
+		{"--flag=x'y", "\n\t"},
+		{"", "'"},
+	} {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, a, b string) {
+		if strings.ContainsRune(a, 0) || strings.ContainsRune(b, 0) {
+			t.Skip("arguments cannot hold NUL bytes")
+		}
+
+		got := runShell(t, printArgs.Arg(a, b).String())
+		if want := a + "\x00" + b + "\x00"; got != want {
+			t.Errorf("shell saw %q, want %q", got, want)
+		}
+	})
+}
+
+func FuzzScript(f *testing.F) {
+	f.Add("/srv/app")
+	f.Add(`x" ; touch /tmp/pwned; echo "`)
+	f.Add("$(id) `id` $HOME 'q'")
+
+	f.Fuzz(func(t *testing.T, value string) {
+		if strings.ContainsRune(value, 0) {
+			t.Skip("arguments cannot hold NUL bytes")
+		}
+
+		// The script runs inside the shell that runs the quoted line, as
+		// `sh -c` does on a remote host.
+		got := runShell(t, Script(`printf '%s\0' "$1"`, value).String())
+		if want := value + "\x00"; got != want {
+			t.Errorf("script saw %q, want %q", got, want)
+		}
+	})
+}
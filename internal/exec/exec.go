@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package exec runs commands on hosts through one Commander interface:
+// over SSH, on the local machine, or against a recording fake in tests.
+// Remote command lines are built and quoted the same way everywhere.
+package exec
+
+import (
+	"context"
+	"strings"
+)
+
+// Feature: CORE_EXEC
+// Spec: spec/core/exec.md
+
+// Commander runs commands on hosts.
+type Commander interface {
+	// Run executes name with args on host and returns stdout and stderr.
+	// Output captured before a failure is returned with the error.
+	Run(ctx context.Context, host, name string, args ...string) (stdout, stderr string, err error)
+}
+
+// Quote single-quotes s for a POSIX shell.
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Join returns the shell command line that runs name with args. Every
+// argument is quoted; name is quoted only when it is not a plain word, so
+// lines read as typed, e.g. `env 'A=1' 'echo' 'hi'`.
+func Join(name string, args ...string) string {
+	words := make([]string, 0, 1+len(args))
+	if plainWord(name) {
+		words = append(words, name)
+	} else {
+		words = append(words, Quote(name))
+	}
+	for _, arg := range args {
+		words = append(words, Quote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// plainWord reports whether s needs no quoting in a POSIX shell.
+func plainWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CORE_EXEC
+// Spec: spec/core/exec.md
+
+// fakeRunner records commands and answers every Run with result and err.
+type fakeRunner struct {
+	cmds   []executil.Command
+	result *executil.Result
+	err    error
+}
+
+//nolint:gocritic // hugeParam: cmd matches executil.Runner interface signature
+func (f *fakeRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) {
+	f.cmds = append(f.cmds, cmd)
+	return f.result, f.err
+}
+
+//nolint:gocritic // hugeParam: cmd matches executil.Runner interface signature
+func (f *fakeRunner) RunStream(_ context.Context, cmd executil.Command, _ io.Writer) error {
+	f.cmds = append(f.cmds, cmd)
+	return f.err
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  string
+		args []string
+		want string
+	}{
+		{name: "plain name", cmd: "tailscale", args: []string{"status", "--json"}, want: `tailscale 'status' '--json'`},
+		{name: "no args", cmd: "/usr/bin/true", want: `/usr/bin/true`},
+		{name: "script", cmd: "sh", args: []string{"-c", "curl -fsSL https://example.com | sh"}, want: `sh '-c' 'curl -fsSL https://example.com | sh'`},
+		{name: "single quotes", cmd: "echo", args: []string{"it's"}, want: `echo 'it'\''s'`},
+		{name: "empty arg", cmd: "printf", args: []string{""}, want: `printf ''`},
+		{name: "name needing quotes", cmd: "my tool", args: []string{"x"}, want: `'my tool' 'x'`},
+		{name: "empty name", cmd: "", want: `''`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Join(tt.cmd, tt.args...); got != tt.want {
+				t.Errorf("Join() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSHCommander_Command(t *testing.T) {
+	c := NewSSHCommander(nil)
+	c.User = "root"
+	c.Port = "2222"
+	c.Options = []string{"-o", "StrictHostKeyChecking=no"}
+
+	cmd := c.Command("203.0.113.5", "docker", "ps", "-a")
+	want := []string{
+		"-o", "BatchMode=yes",
+		"-o", "StrictHostKeyChecking=no",
+		"-p", "2222",
+		"root@203.0.113.5",
+		`docker 'ps' '-a'`,
+	}
+	if cmd.Name != "ssh" || !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("Command() = %s %q, want ssh %q", cmd.Name, cmd.Args, want)
+	}
+
+	// A host naming its own user keeps it.
+	if got := c.Target("deploy@203.0.113.5"); got != "deploy@203.0.113.5" {
+		t.Errorf("Target() = %q, want deploy@203.0.113.5", got)
+	}
+}
+
+func TestSSHCommander_Run(t *testing.T) {
+	runner := &fakeRunner{result: &executil.Result{Stdout: []byte("out"), Stderr: []byte("err")}}
+	c := NewSSHCommander(runner)
+
+	stdout, stderr, err := c.Run(context.Background(), "app-1", "tailscale", "version")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if stdout != "out" || stderr != "err" {
+		t.Errorf("Run() = %q, %q, want out, err", stdout, stderr)
+	}
+	if len(runner.cmds) != 1 || runner.cmds[0].Args[2] != "app-1" {
+		t.Errorf("ran %+v, want one ssh to app-1", runner.cmds)
+	}
+}
+
+func TestSSHCommander_Run_Error(t *testing.T) {
+	cause := errors.New("command failed with exit code 255")
+	runner := &fakeRunner{result: &executil.Result{ExitCode: 255, Stderr: []byte("Permission denied")}, err: cause}
+	c := NewSSHCommander(runner)
+	c.User = "ubuntu"
+
+	_, stderr, err := c.Run(context.Background(), "198.51.100.10", "uptime")
+	if !errors.Is(err, cause) || !strings.Contains(err.Error(), "ssh to ubuntu@198.51.100.10 failed") {
+		t.Errorf("Run() error = %v, want wrapped cause naming the target", err)
+	}
+	if stderr != "Permission denied" {
+		t.Errorf("Run() stderr = %q, want output captured before the failure", stderr)
+	}
+}
+
+func TestLocalCommander_Run(t *testing.T) {
+	runner := &fakeRunner{result: &executil.Result{Stdout: []byte("Linux\n")}}
+
+	stdout, _, err := NewLocalCommander(runner).Run(context.Background(), "app-1", "uname", "-s")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if stdout != "Linux\n" {
+		t.Errorf("Run() stdout = %q, want Linux", stdout)
+	}
+	want := executil.NewCommand("uname", "-s")
+	if len(runner.cmds) != 1 || !reflect.DeepEqual(runner.cmds[0], want) {
+		t.Errorf("ran %+v, want %+v", runner.cmds, want)
+	}
+}
+
+func TestRecordingCommander(t *testing.T) {
+	c := NewRecordingCommander()
+	c.Results["app-1 tailscale version"] = Result{Stdout: "1.60.0"}
+	c.Results["app-1 curl -fsSL https://example.com | sh"] = Result{}
+	c.Results["app-1 tailscale logout"] = Result{Stderr: "not logged in", ExitCode: 1}
+
+	ctx := context.Background()
+	if stdout, _, err := c.Run(ctx, "app-1", "tailscale", "version"); err != nil || stdout != "1.60.0" {
+		t.Errorf("Run(version) = %q, %v, want 1.60.0", stdout, err)
+	}
+	if _, _, err := c.Run(ctx, "app-1", "sh", "-c", "curl -fsSL https://example.com | sh"); err != nil {
+		t.Errorf("Run(sh -c) error = %v, want the unwrapped script to match", err)
+	}
+	if _, stderr, err := c.Run(ctx, "app-1", "tailscale", "logout"); err == nil || stderr != "not logged in" {
+		t.Errorf("Run(logout) = %q, %v, want exit code error with stderr", stderr, err)
+	}
+	if _, _, err := c.Run(ctx, "app-2", "tailscale", "version"); err == nil || !strings.Contains(err.Error(), "command not found") {
+		t.Errorf("Run(unknown) error = %v, want command not found", err)
+	}
+
+	calls := c.Calls()
+	if len(calls) != 4 || calls[1].Name != "sh" || calls[3].Host != "app-2" {
+		t.Errorf("Calls() = %+v, want every call in order", calls)
+	}
+}
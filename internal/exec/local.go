@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package exec
+
+import (
+	"context"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CORE_EXEC
+// Spec: spec/core/exec.md
+
+// LocalCommander runs commands on the local machine. The host argument of
+// Run is ignored, which lets callers that address hosts run locally.
+type LocalCommander struct {
+	runner executil.Runner
+}
+
+// Ensure LocalCommander implements Commander
+var _ Commander = (*LocalCommander)(nil)
+
+// NewLocalCommander returns a LocalCommander. If runner is nil, a new
+// executil.Runner is used.
+func NewLocalCommander(runner executil.Runner) *LocalCommander {
+	if runner == nil {
+		runner = executil.NewRunner()
+	}
+	return &LocalCommander{runner: runner}
+}
+
+// Run executes name with args locally. Secrets are masked in stderr and
+// the error, as for SSHCommander.
+//
+//nolint:gocritic // unnamedResult: matches Commander
+func (c *LocalCommander) Run(ctx context.Context, _, name string, args ...string) (string, string, error) {
+	result, err := c.runner.Run(ctx, executil.NewCommand(name, args...))
+	stdout, stderr := output(result)
+	return stdout, stderr, Secrets.RedactError(err)
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Feature: CORE_EXEC
+// Spec: spec/core/exec.md
+
+// Result is a canned answer of a RecordingCommander.
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Error    error
+}
+
+// Call is a command a RecordingCommander was asked to run.
+type Call struct {
+	Host string
+	Name string
+	Args []string
+}
+
+// RecordingCommander is a Commander for tests. It records every call and
+// answers from Results without running anything.
+type RecordingCommander struct {
+	// Results are keyed by host, name and args joined with spaces, e.g.
+	// "app-1 tailscale version". A `sh -c <script>` call also matches the
+	// key "<host> <script>", followed by the script's positional
+	// parameters when it has any (see Script). Unknown commands fail.
+	Results map[string]Result
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Ensure RecordingCommander implements Commander
+var _ Commander = (*RecordingCommander)(nil)
+
+// NewRecordingCommander returns a RecordingCommander without results.
+func NewRecordingCommander() *RecordingCommander {
+	return &RecordingCommander{Results: make(map[string]Result)}
+}
+
+// Run records the call and returns its canned result.
+//
+//nolint:gocritic // unnamedResult: matches Commander
+func (c *RecordingCommander) Run(_ context.Context, host, name string, args ...string) (string, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, Call{Host: host, Name: name, Args: append([]string(nil), args...)})
+
+	key := strings.Join(append([]string{host, name}, args...), " ")
+	result, ok := c.Results[key]
+	if !ok && name == "sh" && len(args) > 1 && args[0] == "-c" {
+		script := []string{host, args[1]}
+		if len(args) > 3 {
+			script = append(script, args[3:]...) // skip $0
+		}
+		result, ok = c.Results[strings.Join(script, " ")]
+	}
+	if !ok {
+		return "", "", fmt.Errorf("command not found: %s", key)
+	}
+
+	if result.Error != nil {
+		return result.Stdout, result.Stderr, result.Error
+	}
+	if result.ExitCode != 0 {
+		return result.Stdout, result.Stderr, fmt.Errorf("command failed with exit code %d", result.ExitCode)
+	}
+	return result.Stdout, result.Stderr, nil
+}
+
+// Calls returns the calls recorded so far, in order.
+func (c *RecordingCommander) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
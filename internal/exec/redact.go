@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package exec
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Feature: CORE_EXEC
+// Spec: spec/core/exec.md
+
+// RedactedValue replaces secret values. It matches env.RedactedValue, so
+// redacted commands read like `stagecraft env explain` output.
+const RedactedValue = "<redacted>"
+
+// minSecretLen is the length below which values are not redacted. Short
+// values such as "true" or "1" would mask unrelated text everywhere.
+const minSecretLen = 8
+
+// secretKeyMarkers are name fragments that mark a variable as secret.
+var secretKeyMarkers = []string{
+	"SECRET",
+	"PASSWORD",
+	"PASSWD",
+	"TOKEN",
+	"PRIVATE",
+	"CREDENTIAL",
+	"API_KEY",
+	"ACCESS_KEY",
+	"AUTH",
+}
+
+// IsSecretKey reports whether a variable name looks like it holds a secret.
+func IsSecretKey(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return strings.HasSuffix(upper, "_KEY")
+}
+
+// Redactor masks secret values in command lines, output and errors. It is
+// safe for concurrent use.
+type Redactor struct {
+	mu      sync.RWMutex
+	secrets []string // longest first, so overlapping secrets mask fully
+}
+
+// Secrets is the redactor every commander applies. The CLI seeds it with
+// the process's secret-looking environment variables at startup; providers
+// add the credentials they read, and env file readers the secret-looking
+// variables they parse.
+var Secrets = NewRedactor()
+
+// NewRedactor returns a redactor masking values.
+func NewRedactor(values ...string) *Redactor {
+	r := &Redactor{}
+	r.Add(values...)
+	return r
+}
+
+// Add masks values from now on. Values shorter than minSecretLen are
+// ignored.
+func (r *Redactor) Add(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range values {
+		if len(v) < minSecretLen || contains(r.secrets, v) {
+			continue
+		}
+		r.secrets = append(r.secrets, v)
+	}
+	sort.SliceStable(r.secrets, func(i, j int) bool {
+		return len(r.secrets[i]) > len(r.secrets[j])
+	})
+}
+
+// AddEnviron masks the values of the variables in environ, given as
+// "KEY=value" like os.Environ, whose names secret reports as secret.
+func (r *Redactor) AddEnviron(environ []string, secret func(key string) bool) {
+	for _, kv := range environ {
+		if key, value, ok := strings.Cut(kv, "="); ok && secret(key) {
+			r.Add(value)
+		}
+	}
+}
+
+// AddVars masks the values of vars whose names secret reports as secret,
+// e.g. the variables of a parsed env file.
+func (r *Redactor) AddVars(vars map[string]string, secret func(key string) bool) {
+	for key, value := range vars {
+		if secret(key) {
+			r.Add(value)
+		}
+	}
+}
+
+// Redact returns s with every secret replaced by RedactedValue.
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, RedactedValue)
+	}
+	return s
+}
+
+// RedactArgs returns a redacted copy of args.
+func (r *Redactor) RedactArgs(args []string) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		out[i] = r.Redact(arg)
+	}
+	return out
+}
+
+// RedactError returns err with a redacted message. errors.Is and errors.As
+// still see the original error.
+func (r *Redactor) RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := r.Redact(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
+
+// redactedError is an error whose message had secrets masked.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+func contains(values []string, v string) bool {
+	for _, existing := range values {
+		if existing == v {
+			return true
+		}
+	}
+	return false
+}
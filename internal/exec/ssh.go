@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CORE_EXEC
+// Spec: spec/core/exec.md
+
+// defaultSSHOptions make ssh fail instead of prompting for a password or
+// passphrase, which would hang a non-interactive run.
+var defaultSSHOptions = []string{"-o", "BatchMode=yes"}
+
+// SSHCommander runs commands on remote hosts with the ssh client, so the
+// user's ssh config, agent and known hosts apply.
+type SSHCommander struct {
+	// User is the login user for hosts given without one; empty leaves it to ssh.
+	User string
+
+	// Port is the SSH port; empty leaves it to ssh.
+	Port string
+
+	// Options are extra ssh arguments, e.g. {"-o", "StrictHostKeyChecking=no"}.
+	Options []string
+
+	runner executil.Runner
+}
+
+// Ensure SSHCommander implements Commander
+var _ Commander = (*SSHCommander)(nil)
+
+// NewSSHCommander returns an SSHCommander. If runner is nil, a new
+// executil.Runner is used.
+func NewSSHCommander(runner executil.Runner) *SSHCommander {
+	if runner == nil {
+		runner = executil.NewRunner()
+	}
+	return &SSHCommander{runner: runner}
+}
+
+// Target returns the ssh destination for host, adding User unless host
+// names a user itself.
+func (c *SSHCommander) Target(host string) string {
+	if c.User == "" || strings.Contains(host, "@") {
+		return host
+	}
+	return c.User + "@" + host
+}
+
+// Command returns the ssh invocation that runs name with args on host,
+// without running it. The remote command line is built with Join.
+func (c *SSHCommander) Command(host, name string, args ...string) executil.Command {
+	sshArgs := append([]string(nil), defaultSSHOptions...)
+	sshArgs = append(sshArgs, c.Options...)
+	if c.Port != "" {
+		sshArgs = append(sshArgs, "-p", c.Port)
+	}
+	sshArgs = append(sshArgs, c.Target(host), Join(name, args...))
+	return executil.NewCommand("ssh", sshArgs...)
+}
+
+// Run executes name with args on host over ssh. Secrets are masked in
+// stderr and the error; stdout is returned as is for callers to parse.
+//
+//nolint:gocritic // unnamedResult: matches Commander
+func (c *SSHCommander) Run(ctx context.Context, host, name string, args ...string) (string, string, error) {
+	result, err := c.runner.Run(ctx, c.Command(host, name, args...))
+	stdout, stderr := output(result)
+	if err != nil {
+		return stdout, stderr, Secrets.RedactError(fmt.Errorf("ssh to %s failed: %w", c.Target(host), err))
+	}
+	return stdout, stderr, nil
+}
+
+// output returns a result's stdout and its stderr with secrets masked; a
+// nil result has neither.
+func output(result *executil.Result) (stdout, stderr string) {
+	if result == nil {
+		return "", ""
+	}
+	return string(result.Stdout), Secrets.Redact(string(result.Stderr))
+}
@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+# Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+package failure
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// rule maps failures matching match to a diagnosis.
+type rule struct {
+	class   Class
+	cause   string
+	actions []string
+	refs    []string
+	match   func(err error, msg string) bool
+}
+
+// rules are tried in order; the first match wins. Specific rules come
+// before generic ones, and internal invariants before everything else so a
+// bug is never reported as a user error.
+var rules = []rule{
+	{
+		class:   ClassInternalInvariant,
+		cause:   "Stagecraft hit an internal error; this is a bug.",
+		actions: []string{"Re-run with --verbose and report the output as an issue"},
+		refs:    []string{"spec/core/failure-lens.md"},
+		match:   contains("panic:", "invariant", "internal error"),
+	},
+	{
+		class:   ClassUserInput,
+		cause:   "The command was interrupted before it finished.",
+		actions: []string{"Re-run the command; completed steps are recorded in state"},
+		refs:    []string{"spec/core/phase-execution-common.md"},
+		match:   is(context.Canceled),
+	},
+	{
+		class: ClassUserInput,
+		cause: "The command line has an unknown command, flag or argument.",
+		actions: []string{
+			"Run the command with --help to see its usage",
+		},
+		refs: []string{"spec/core/global-flags.md"},
+		match: contains(
+			"unknown command", "unknown flag", "unknown shorthand flag",
+			"required flag", "flag needs an argument", "invalid argument",
+			"accepts ", "requires at least", "cannot be combined", "mutually exclusive",
+		),
+	},
+	{
+		class: ClassUserInput,
+		cause: "The selected environment is not defined in the config.",
+		actions: []string{
+			"Check --env (or STAGECRAFT_ENV) against the environments in stagecraft.yml",
+		},
+		refs:  []string{"spec/core/global-flags.md"},
+		match: contains("not found in config", "environment not found", "invalid environment"),
+	},
+	{
+		class: ClassConfigInvalid,
+		cause: "A plan, state file or agent bundle was written by a newer Stagecraft than this one.",
+		actions: []string{
+			"Run `stagecraft self-update`, or install the version named in the error",
+		},
+		refs:  []string{"spec/core/version-skew.md"},
+		match: contains("incompatible stagecraft version"),
+	},
+	{
+		class: ClassConfigInvalid,
+		cause: "The stagecraft config is missing, unreadable or invalid.",
+		actions: []string{
+			"Check the file passed with --config (default: stagecraft.yml)",
+			"Run `stagecraft init` to create a config",
+		},
+		refs:  []string{"spec/core/config.md"},
+		match: contains("config not found", "loading config", "parsing config", "config: ", "invalid config"),
+	},
+	{
+		class: ClassConfigInvalid,
+		cause: "A credential the config refers to is not set in the environment.",
+		actions: []string{
+			"Export the environment variable named in the error",
+		},
+		refs:  []string{"spec/core/env-resolution.md"},
+		match: contains("token missing", "key missing", "must be set", "is not set in the environment"),
+	},
+	{
+		class: ClassUserInput,
+		cause: "A flag or argument value is missing, invalid or conflicts with another flag.",
+		actions: []string{
+			"Run the command with --help to see its usage",
+		},
+		refs: []string{"spec/core/global-flags.md"},
+		match: contains(
+			"cannot be used", " is required", " are required", "must be ", "must not ", "requires --",
+			"no such bundle", "no such file or directory",
+		),
+	},
+	{
+		class: ClassExternalDependency,
+		cause: "A required tool is not installed, not on PATH, or not running.",
+		actions: []string{
+			"Install the tool named in the error and make sure it is on PATH",
+			"Run `stagecraft doctor` to check the container runtime",
+		},
+		refs: []string{"spec/commands/doctor.md"},
+		match: either(
+			is(exec.ErrNotFound),
+			contains(
+				"executable file not found", "command not found",
+				"cannot connect to the docker daemon", "is the docker daemon running",
+				"no container runtime",
+			),
+		),
+	},
+	{
+		class: ClassTransientEnvironment,
+		cause: "A network call or operation timed out, was refused, or ran out of resources.",
+		actions: []string{
+			"Retry the command",
+			"Check network connectivity and free disk space on the affected host",
+		},
+		refs: []string{"spec/governance/GOV_CLI_EXIT_CODES.md"},
+		match: either(
+			is(context.DeadlineExceeded, syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ENOSPC),
+			isNetTimeout,
+			contains(
+				"timeout", "timed out", "deadline exceeded",
+				"connection refused", "connection reset", "no such host",
+				"temporary failure", "rate limit", "too many requests",
+				"no space left on device", "cannot allocate memory", "unreachable",
+			),
+		),
+	},
+	{
+		class: ClassProviderFailure,
+		cause: "An external service (cloud, network or registry API) rejected the request.",
+		actions: []string{
+			"Check the provider's status page and the credentials it uses",
+			"Retry the command once the provider reports no incidents",
+		},
+		refs: []string{"spec/providers/cloud/interface.md"},
+		match: contains(
+			"digitalocean provider", "tailscale", "api error", "plugin failed",
+			"plugin protocol error", "push failed", "unauthorized", "forbidden",
+		),
+	},
+}
+
+// unclassified is the diagnosis when no rule matches.
+var unclassified = Diagnosis{
+	Class: ClassUnclassified,
+	Cause: "Stagecraft could not determine why the command failed.",
+	NextActions: []string{
+		"Re-run with --verbose for more detail",
+		"Report the output as an issue if the cause is unclear",
+	},
+	Refs: []string{"spec/core/failure-lens.md"},
+}
+
+// Classify diagnoses err. An explicitly classified error (see ClassOf)
+// keeps its class and takes the cause and next actions of the first rule
+// for that class that matches, or the class's generic ones. Other errors
+// are matched on the sentinels they wrap and, because many errors are
+// formatted rather than wrapped, on their message.
+func Classify(err error) Diagnosis {
+	msg := strings.ToLower(err.Error())
+
+	if class, ok := ClassOf(err); ok {
+		for _, r := range rules {
+			if r.class == class && r.match(err, msg) {
+				return r.diagnosis()
+			}
+		}
+		if class == ClassUnclassified {
+			return unclassified
+		}
+		return classDefaults[class]
+	}
+
+	for _, r := range rules {
+		if r.match(err, msg) {
+			return r.diagnosis()
+		}
+	}
+	return unclassified
+}
+
+func (r rule) diagnosis() Diagnosis {
+	return Diagnosis{Class: r.class, Cause: r.cause, NextActions: r.actions, Refs: r.refs}
+}
+
+// classDefaults are the diagnoses of explicitly classified errors that no
+// rule explains.
+var classDefaults = map[Class]Diagnosis{
+	ClassUserInput: {
+		Class:       ClassUserInput,
+		Cause:       "The command was given invalid input.",
+		NextActions: []string{"Run the command with --help to see its usage"},
+		Refs:        []string{"spec/core/global-flags.md"},
+	},
+	ClassConfigInvalid: {
+		Class:       ClassConfigInvalid,
+		Cause:       "The configuration is invalid.",
+		NextActions: []string{"Fix the setting named in the error"},
+		Refs:        []string{"spec/core/config.md"},
+	},
+	ClassExternalDependency: {
+		Class:       ClassExternalDependency,
+		Cause:       "A required external tool is missing or not working.",
+		NextActions: []string{"Run `stagecraft doctor` to check the local setup"},
+		Refs:        []string{"spec/commands/doctor.md"},
+	},
+	ClassProviderFailure: {
+		Class:       ClassProviderFailure,
+		Cause:       "An external service (cloud, network or registry API) rejected the request.",
+		NextActions: []string{"Check the provider's status page and the credentials it uses"},
+		Refs:        []string{"spec/providers/cloud/interface.md"},
+	},
+	ClassTransientEnvironment: {
+		Class:       ClassTransientEnvironment,
+		Cause:       "The operation failed for a reason that is likely temporary.",
+		NextActions: []string{"Retry the command"},
+		Refs:        []string{"spec/governance/GOV_CLI_EXIT_CODES.md"},
+	},
+	ClassInternalInvariant: {
+		Class:       ClassInternalInvariant,
+		Cause:       "Stagecraft hit an internal error; this is a bug.",
+		NextActions: []string{"Re-run with --verbose and report the output as an issue"},
+		Refs:        []string{"spec/core/failure-lens.md"},
+	},
+}
+
+func is(targets ...error) func(error, string) bool {
+	return func(err error, _ string) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func contains(substrings ...string) func(error, string) bool {
+	return func(_ error, msg string) bool {
+		for _, s := range substrings {
+			if strings.Contains(msg, s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func either(matchers ...func(error, string) bool) func(error, string) bool {
+	return func(err error, msg string) bool {
+		for _, match := range matchers {
+			if match(err, msg) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func isNetTimeout(err error, _ string) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+# Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+package failure
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors, one per failure class. Errors carry a class either by
+// wrapping a sentinel directly (fmt.Errorf("%w: ...", ErrUserInput)) or by
+// being an *Error; errors.Is matches both.
+var (
+	ErrUserInput            = errors.New("invalid input")
+	ErrConfigInvalid        = errors.New("invalid config")
+	ErrExternalDependency   = errors.New("missing dependency")
+	ErrProviderFailure      = errors.New("provider failure")
+	ErrTransientEnvironment = errors.New("transient failure")
+	ErrInternalInvariant    = errors.New("internal error")
+)
+
+// sentinels maps each class to its sentinel error.
+var sentinels = map[Class]error{
+	ClassUserInput:            ErrUserInput,
+	ClassConfigInvalid:        ErrConfigInvalid,
+	ClassExternalDependency:   ErrExternalDependency,
+	ClassProviderFailure:      ErrProviderFailure,
+	ClassTransientEnvironment: ErrTransientEnvironment,
+	ClassInternalInvariant:    ErrInternalInvariant,
+}
+
+// Error is an error assigned a failure class by the code that returned it.
+// Its message is the wrapped error's, so classifying an error never
+// changes what users read.
+type Error struct {
+	Class Class
+	Err   error
+}
+
+// New returns an error with the given class and message. Use it for
+// package-level sentinel errors.
+func New(class Class, msg string) error {
+	return &Error{Class: class, Err: errors.New(msg)}
+}
+
+// Errorf formats an error with the given class. %w wraps as in fmt.Errorf.
+func Errorf(class Class, format string, args ...any) error {
+	return &Error{Class: class, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap assigns class to err. It returns nil when err is nil.
+func Wrap(class Class, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Class: class, Err: err}
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is reports whether target is the sentinel for e's class.
+func (e *Error) Is(target error) bool {
+	sentinel, ok := sentinels[e.Class]
+	return ok && target == sentinel
+}
+
+// Classifier is implemented by error types that classify themselves, e.g.
+// by a kind field. FailureClass returns "" when the error has no class.
+type Classifier interface {
+	FailureClass() Class
+}
+
+// ClassOf returns the class explicitly assigned to err. The outermost
+// classified error in the chain wins, since the code closest to the user
+// knows best what went wrong, except that an internal invariant anywhere
+// in the chain always wins. ok is false when nothing in the chain is
+// classified.
+func ClassOf(err error) (class Class, ok bool) {
+	if errors.Is(err, ErrInternalInvariant) {
+		return ClassInternalInvariant, true
+	}
+	walk(err, func(e error) bool {
+		class = explicitClass(e)
+		return class != ""
+	})
+	return class, class != ""
+}
+
+// explicitClass returns the class of e itself, not of the errors it wraps.
+func explicitClass(e error) Class {
+	switch v := e.(type) {
+	case *Error:
+		return v.Class
+	case Classifier:
+		return v.FailureClass()
+	}
+	for class, sentinel := range sentinels {
+		if e == sentinel {
+			return class
+		}
+	}
+	return ""
+}
+
+// walk visits err and the errors it wraps depth-first until visit returns
+// true.
+func walk(err error, visit func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if visit(err) {
+		return true
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return walk(u.Unwrap(), visit)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if walk(e, visit) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+# Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// Feature: CORE_FAILURE_LENS
+// Spec: spec/core/failure-lens.md
+
+type kindError struct{ class Class }
+
+func (e kindError) Error() string       { return "kind error" }
+func (e kindError) FailureClass() Class { return e.class }
+
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   Class
+		wantOK bool
+	}{
+		{"unclassified", errors.New("boom"), "", false},
+		{"wrapped sentinel", fmt.Errorf("deploy: %w", ErrProviderFailure), ClassProviderFailure, true},
+		{"typed error", fmt.Errorf("deploy: %w", Errorf(ClassUserInput, "bad flag")), ClassUserInput, true},
+		{"classifier", fmt.Errorf("hook: %w", kindError{ClassTransientEnvironment}), ClassTransientEnvironment, true},
+		{"classifier without class", kindError{""}, "", false},
+		{"outermost wins", Wrap(ClassConfigInvalid, fmt.Errorf("x: %w", ErrProviderFailure)), ClassConfigInvalid, true},
+		{"internal wins", Wrap(ClassUserInput, Wrap(ClassInternalInvariant, errors.New("nil"))), ClassInternalInvariant, true},
+		{"joined", errors.Join(errors.New("a"), ErrExternalDependency), ClassExternalDependency, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ClassOf(tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ClassOf() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestError_KeepsMessageAndSentinel(t *testing.T) {
+	cause := errors.New("droplet not found")
+	err := Wrap(ClassProviderFailure, cause)
+
+	if err.Error() != cause.Error() {
+		t.Errorf("Error() = %q, want %q", err.Error(), cause.Error())
+	}
+	if !errors.Is(err, cause) || !errors.Is(err, ErrProviderFailure) || errors.Is(err, ErrUserInput) {
+		t.Errorf("errors.Is mismatch for %v", err)
+	}
+	if Wrap(ClassUserInput, nil) != nil {
+		t.Error("Wrap(nil) should be nil")
+	}
+}
+
+func TestClassify_ExplicitClassOverridesMessage(t *testing.T) {
+	// The message looks like a timeout, but the error says it is invalid input.
+	d := Classify(Errorf(ClassUserInput, "--timeout must be positive"))
+	if d.Class != ClassUserInput {
+		t.Fatalf("Class = %s, want user_input", d.Class)
+	}
+	if d.Cause == "" || len(d.NextActions) == 0 {
+		t.Errorf("expected cause and next actions, got %+v", d)
+	}
+}
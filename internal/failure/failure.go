@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+// Package failure classifies command failures into the governed failure
+// taxonomy (see GOV_CLI_EXIT_CODES) and explains them: every failed command
+// reports a failure class, a probable cause, next actions, and exits with
+// the class's exit code.
+package failure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Feature: CORE_FAILURE_LENS
+// Spec: spec/core/failure-lens.md
+
+// Class is a semantic failure class.
+type Class string
+
+// Failure classes, as defined by GOV_CLI_EXIT_CODES.
+const (
+	ClassUserInput            Class = "user_input"
+	ClassConfigInvalid        Class = "config_invalid"
+	ClassExternalDependency   Class = "external_dependency"
+	ClassProviderFailure      Class = "provider_failure"
+	ClassTransientEnvironment Class = "transient_environment"
+	ClassInternalInvariant    Class = "internal_invariant"
+	ClassUnclassified         Class = "unclassified"
+)
+
+// Exit codes for failure classes.
+const (
+	ExitSuccess  = 0
+	ExitUser     = 1
+	ExitExternal = 2
+	ExitInternal = 3
+)
+
+// ExitCode returns the exit code governed for c. Unknown classes exit as
+// unclassified.
+func (c Class) ExitCode() int {
+	switch c {
+	case ClassUserInput, ClassConfigInvalid:
+		return ExitUser
+	case ClassExternalDependency, ClassProviderFailure, ClassTransientEnvironment:
+		return ExitExternal
+	default:
+		return ExitInternal
+	}
+}
+
+// Diagnosis explains a failure.
+type Diagnosis struct {
+	Class Class
+
+	// Cause is a one-sentence probable cause.
+	Cause string
+
+	// NextActions are what the user can try next, most likely first.
+	NextActions []string
+
+	// Refs are spec documents describing the failing area.
+	Refs []string
+}
+
+// ExitCode returns the exit code for the diagnosed failure.
+func (d Diagnosis) ExitCode() int {
+	return d.Class.ExitCode()
+}
+
+// Format selects how failures are reported.
+type Format string
+
+const (
+	// FormatText is the human-readable report; it is the default.
+	FormatText Format = "text"
+	// FormatJSON is a single-line JSON envelope for CI systems.
+	FormatJSON Format = "json"
+)
+
+// ErrInvalidFormat is returned when an unknown error format is requested.
+var ErrInvalidFormat = errors.New("invalid error format")
+
+// ParseFormat parses an error format name. An empty string selects
+// FormatText.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w %q (must be one of: text, json)", ErrInvalidFormat, s)
+	}
+}
+
+// Envelope is the JSON form of a failure. Field order is the serialization
+// order.
+type Envelope struct {
+	FailureClass Class    `json:"failure_class"`
+	ExitCode     int      `json:"exit_code"`
+	Subsystem    string   `json:"subsystem"`
+	Message      string   `json:"message"`
+	Cause        string   `json:"cause"`
+	NextActions  []string `json:"next_actions"`
+	Remediation  []string `json:"remediation"`
+}
+
+// NewEnvelope returns the envelope for err as diagnosed by d. subsystem
+// names the failing command, e.g. "deploy" or "compose diff".
+func NewEnvelope(subsystem string, err error, d Diagnosis) Envelope {
+	env := Envelope{
+		FailureClass: d.Class,
+		ExitCode:     d.ExitCode(),
+		Subsystem:    subsystem,
+		Message:      err.Error(),
+		Cause:        d.Cause,
+		NextActions:  d.NextActions,
+		Remediation:  d.Refs,
+	}
+	if env.NextActions == nil {
+		env.NextActions = []string{}
+	}
+	if env.Remediation == nil {
+		env.Remediation = []string{}
+	}
+	return env
+}
+
+// WriteJSON writes the envelope to w as a single line of JSON.
+func WriteJSON(w io.Writer, env Envelope) error {
+	// json.Encoder terminates the value with a newline and never indents.
+	return json.NewEncoder(w).Encode(env)
+}
+
+// Report writes err followed by its diagnosis to w, as printed when a
+// command fails.
+func Report(w io.Writer, err error, d Diagnosis) {
+	_, _ = fmt.Fprintf(w, "Error: %v\n\n", err)
+	_, _ = fmt.Fprintf(w, "failure_class: %s (exit %d)\n", d.Class, d.ExitCode())
+	_, _ = fmt.Fprintf(w, "probable cause: %s\n", d.Cause)
+	if len(d.NextActions) > 0 {
+		_, _ = fmt.Fprintln(w, "next actions:")
+		for _, action := range d.NextActions {
+			_, _ = fmt.Fprintf(w, "  - %s\n", action)
+		}
+	}
+	if len(d.Refs) > 0 {
+		_, _ = fmt.Fprintf(w, "see: %s\n", strings.Join(d.Refs, ", "))
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+# Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+package failure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// Feature: CORE_FAILURE_LENS
+// Spec: spec/core/failure-lens.md
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Class
+	}{
+		{"panic", errors.New("internal error: panic: nil map"), ClassInternalInvariant},
+		{"interrupted", fmt.Errorf("deploy: %w", context.Canceled), ClassUserInput},
+		{"unknown flag", errors.New("unknown flag: --nope"), ClassUserInput},
+		{"unknown environment", errors.New(`environment "qa" not found in config`), ClassUserInput},
+		{"flag conflict", errors.New("--out requires --plan-only"), ClassUserInput},
+		{"config not found", errors.New("deploy: stagecraft config not found at stagecraft.yml"), ClassConfigInvalid},
+		{"config invalid", errors.New("loading config: config: project.name must be non-empty"), ClassConfigInvalid},
+		{"missing token", errors.New("digitalocean provider: API token missing from environment: DO_TOKEN"), ClassConfigInvalid},
+		{"missing executable", fmt.Errorf("running hook: %w", exec.ErrNotFound), ClassExternalDependency},
+		{"docker down", errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock"), ClassExternalDependency},
+		{"deadline", fmt.Errorf("waiting for health: %w", context.DeadlineExceeded), ClassTransientEnvironment},
+		{"rate limit", errors.New("digitalocean provider: API rate limit exceeded"), ClassTransientEnvironment},
+		{"provider api", errors.New("digitalocean provider: API error: 500"), ClassProviderFailure},
+		{"unknown", errors.New("something odd"), ClassUnclassified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Classify(tt.err)
+			if d.Class != tt.want {
+				t.Errorf("Classify(%q).Class = %s, want %s", tt.err, d.Class, tt.want)
+			}
+			if d.Cause == "" || len(d.NextActions) == 0 {
+				t.Errorf("Classify(%q) = %+v, want a cause and next actions", tt.err, d)
+			}
+		})
+	}
+}
+
+func TestClass_ExitCode(t *testing.T) {
+	tests := map[Class]int{
+		ClassUserInput:            1,
+		ClassConfigInvalid:        1,
+		ClassExternalDependency:   2,
+		ClassProviderFailure:      2,
+		ClassTransientEnvironment: 2,
+		ClassInternalInvariant:    3,
+		ClassUnclassified:         3,
+		Class("bogus"):            3,
+	}
+	for class, want := range tests {
+		if got := class.ExitCode(); got != want {
+			t.Errorf("%s.ExitCode() = %d, want %d", class, got, want)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	var out strings.Builder
+	err := errors.New("loading config: boom")
+	Report(&out, err, Classify(err))
+
+	want := `Error: loading config: boom
+
+failure_class: config_invalid (exit 1)
+probable cause: The stagecraft config is missing, unreadable or invalid.
+next actions:
+  - Check the file passed with --config (default: stagecraft.yml)
+  - Run ` + "`stagecraft init`" + ` to create a config
+see: spec/core/config.md
+`
+	if out.String() != want {
+		t.Errorf("Report() =\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": FormatText, "text": FormatText, " JSON ": FormatJSON} {
+		got, err := ParseFormat(in)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("ParseFormat(xml) error = %v, want ErrInvalidFormat", err)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	err := fmt.Errorf("deploy: %w", exec.ErrNotFound)
+	var buf bytes.Buffer
+	if werr := WriteJSON(&buf, NewEnvelope("deploy", err, Classify(err))); werr != nil {
+		t.Fatalf("WriteJSON() error = %v", werr)
+	}
+
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 || !strings.HasSuffix(out, "\n") {
+		t.Fatalf("expected a single line, got %q", out)
+	}
+
+	var env Envelope
+	if uerr := json.Unmarshal(buf.Bytes(), &env); uerr != nil {
+		t.Fatalf("invalid JSON: %v", uerr)
+	}
+	if env.FailureClass != ClassExternalDependency || env.ExitCode != 2 || env.Subsystem != "deploy" {
+		t.Errorf("envelope = %+v, want external_dependency, exit 2, subsystem deploy", env)
+	}
+	if env.Message != err.Error() || len(env.NextActions) == 0 || len(env.Remediation) == 0 {
+		t.Errorf("envelope = %+v, want message, next actions and remediation", env)
+	}
+}
@@ -0,0 +1,319 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package hooks runs user-defined commands before and after deploy phases.
+//
+// Hooks are declared under `hooks:` in stagecraft.yml, keyed by hook point
+// (e.g. "pre_rollout", "post_finalize"). Each hook receives the release
+// context as STAGECRAFT_* environment variables and runs either locally or
+// on a remote host over SSH.
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	hostexec "stagecraft/internal/exec"
+	"stagecraft/internal/failure"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_PHASE_HOOKS
+// Spec: spec/deploy/hooks.md
+
+// ReleaseContext is the release metadata exposed to hooks.
+type ReleaseContext struct {
+	Project     string
+	Environment string
+	Command     string
+	ReleaseID   string
+	Version     string
+	CommitSHA   string
+}
+
+// Env returns the environment variables passed to a hook at point.
+func (rc ReleaseContext) Env(point, phase string) map[string]string {
+	return map[string]string{
+		"STAGECRAFT_COMMAND":         rc.Command,
+		"STAGECRAFT_COMMIT_SHA":      rc.CommitSHA,
+		"STAGECRAFT_ENV":             rc.Environment,
+		"STAGECRAFT_HOOK":            point,
+		"STAGECRAFT_PHASE":           phase,
+		"STAGECRAFT_PROJECT":         rc.Project,
+		"STAGECRAFT_RELEASE_ID":      rc.ReleaseID,
+		"STAGECRAFT_RELEASE_VERSION": rc.Version,
+	}
+}
+
+// FailureKind classifies why a hook failed.
+type FailureKind string
+
+const (
+	// FailureNotFound indicates the hook's executable could not be started.
+	FailureNotFound FailureKind = "not_found"
+	// FailureExit indicates the hook ran and exited non-zero.
+	FailureExit FailureKind = "exit"
+	// FailureUnreachable indicates SSH could not reach the remote host.
+	FailureUnreachable FailureKind = "unreachable"
+	// FailureTimeout indicates the hook exceeded its configured timeout.
+	FailureTimeout FailureKind = "timeout"
+	// FailureCanceled indicates the deploy was canceled while the hook ran.
+	FailureCanceled FailureKind = "canceled"
+)
+
+// Error is a classified hook failure.
+type Error struct {
+	Point    string
+	Index    int
+	Kind     FailureKind
+	ExitCode int
+	Stderr   string
+	Cause    error
+}
+
+// FailureClass classifies the hook failure for the failure lens. A hook
+// that ran and exited non-zero is the user's script failing and has no
+// class of its own.
+func (e *Error) FailureClass() failure.Class {
+	switch e.Kind {
+	case FailureNotFound:
+		return failure.ClassExternalDependency
+	case FailureUnreachable, FailureTimeout:
+		return failure.ClassTransientEnvironment
+	case FailureCanceled:
+		return failure.ClassUserInput
+	default:
+		return ""
+	}
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("hook %s[%d] failed (%s)", e.Point, e.Index, e.Kind)
+	if e.Kind == FailureExit || e.Kind == FailureUnreachable {
+		msg += fmt.Sprintf(": exit code %d", e.ExitCode)
+	}
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	} else if e.Cause != nil && e.Kind != FailureExit {
+		msg += ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// sshUnreachableExitCode is the exit status ssh uses for its own errors.
+const sshUnreachableExitCode = 255
+
+// maxStderr bounds how much hook stderr is carried in errors.
+const maxStderr = 512
+
+// Options configures a Runner.
+type Options struct {
+	// DryRun logs hooks instead of executing them.
+	DryRun bool
+	// Logger receives hook progress and dry-run output.
+	Logger logging.Logger
+	// Runner executes hook commands. Nil uses executil.NewRunner().
+	Runner executil.Runner
+	// OnRun, when set, is called after each hook has run (not in dry-run).
+	OnRun func(ctx context.Context, exec Execution)
+}
+
+// Execution describes a hook that has run, for Options.OnRun.
+type Execution struct {
+	// Hook identifies the hook as "<point>[<index>]", e.g. "pre_rollout[0]".
+	Hook string
+	// Phase is the deploy phase the hook ran around.
+	Phase   string
+	Command []string
+	Host    string
+	// ReleaseID is the release the hook ran for; empty before it exists.
+	ReleaseID string
+	Duration  time.Duration
+	// Err is the hook's failure, nil when it succeeded.
+	Err error
+}
+
+// Runner executes the hooks configured for one environment.
+type Runner struct {
+	hooks map[string][]config.HookConfig
+	rc    ReleaseContext
+	opts  Options
+}
+
+// NewRunner returns a Runner for the hooks in cfg that apply to rc.Environment.
+func NewRunner(hooks map[string][]config.HookConfig, rc ReleaseContext, opts Options) *Runner {
+	if opts.Logger == nil {
+		opts.Logger = logging.NewLogger(false)
+	}
+	if opts.Runner == nil {
+		opts.Runner = executil.NewRunner()
+	}
+	return &Runner{hooks: hooks, rc: rc, opts: opts}
+}
+
+// SetReleaseID records the release ID once the release exists.
+func (r *Runner) SetReleaseID(id string) {
+	r.rc.ReleaseID = id
+}
+
+// Has reports whether any hook applies at point.
+func (r *Runner) Has(point string) bool {
+	for _, h := range r.hooks[point] {
+		if h.AppliesTo(r.rc.Environment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes the hooks registered at config.HookPoint(when, phase) in
+// declaration order. It stops at the first failing hook unless that hook
+// sets continue_on_error. Failures are returned as *Error.
+func (r *Runner) Run(ctx context.Context, when, phase string) error {
+	point := config.HookPoint(when, phase)
+	for i, h := range r.hooks[point] {
+		if !h.AppliesTo(r.rc.Environment) {
+			continue
+		}
+
+		fields := []logging.Field{
+			logging.NewField("hook", fmt.Sprintf("%s[%d]", point, i)),
+			logging.NewField("command", hostexec.Secrets.Redact(strings.Join(h.Command, " "))),
+		}
+		if h.Host != "" {
+			fields = append(fields, logging.NewField("host", h.Host))
+		}
+
+		if r.opts.DryRun {
+			r.opts.Logger.Info("Dry-run: would run hook", fields...)
+			continue
+		}
+
+		r.opts.Logger.Info("Running hook", fields...)
+		started := time.Now()
+		err := r.runOne(ctx, point, phase, i, h)
+		if r.opts.OnRun != nil {
+			r.opts.OnRun(ctx, Execution{
+				Hook:      fmt.Sprintf("%s[%d]", point, i),
+				Phase:     phase,
+				Command:   h.Command,
+				Host:      h.Host,
+				ReleaseID: r.rc.ReleaseID,
+				Duration:  time.Since(started),
+				Err:       err,
+			})
+		}
+		if err != nil {
+			if h.ContinueOnError {
+				r.opts.Logger.Warn("Hook failed; continuing", append(fields, logging.NewField("error", err.Error()))...)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Runner) runOne(ctx context.Context, point, phase string, index int, h config.HookConfig) error {
+	runCtx := ctx
+	if timeout := h.TimeoutDuration(); timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	env := r.rc.Env(point, phase)
+	var cmd executil.Command
+	if h.Host != "" {
+		cmd = remoteCommand(h.Host, h.Command, env)
+	} else {
+		cmd = executil.NewCommand(h.Command[0], h.Command[1:]...)
+		cmd.Env = env
+	}
+
+	result, err := r.opts.Runner.Run(runCtx, cmd)
+	if err == nil {
+		if result != nil && len(result.Stdout) > 0 {
+			r.opts.Logger.Debug("Hook output",
+				logging.NewField("hook", fmt.Sprintf("%s[%d]", point, index)),
+				logging.NewField("stdout", hostexec.Secrets.Redact(strings.TrimSpace(string(result.Stdout)))),
+			)
+		}
+		return nil
+	}
+
+	hookErr := &Error{Point: point, Index: index, Cause: hostexec.Secrets.RedactError(err)}
+	if result != nil {
+		hookErr.ExitCode = result.ExitCode
+		hookErr.Stderr = truncate(hostexec.Secrets.Redact(strings.TrimSpace(string(result.Stderr))), maxStderr)
+	}
+	hookErr.Kind = classify(ctx, runCtx, err, hookErr.ExitCode, h.Host != "")
+	return hookErr
+}
+
+// classify maps a runner error onto a FailureKind. parent is the deploy
+// context and runCtx the hook's (possibly time-limited) context.
+func classify(parent, runCtx context.Context, err error, exitCode int, remote bool) FailureKind {
+	switch {
+	case parent.Err() != nil:
+		return FailureCanceled
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		return FailureTimeout
+	case errors.Is(err, exec.ErrNotFound):
+		return FailureNotFound
+	case exitCode < 0:
+		// The process never started (e.g. permission denied).
+		return FailureNotFound
+	case remote && exitCode == sshUnreachableExitCode:
+		return FailureUnreachable
+	default:
+		return FailureExit
+	}
+}
+
+// remoteCommand builds an ssh invocation that runs command on host with env
+// exported. Every word is single-quoted for the remote shell.
+func remoteCommand(host string, command []string, env map[string]string) executil.Command {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	words := make([]string, 0, len(keys)+len(command))
+	for _, k := range keys {
+		words = append(words, k+"="+env[k])
+	}
+	words = append(words, command...)
+
+	return hostexec.NewSSHCommander(nil).Command(host, "env", words...)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
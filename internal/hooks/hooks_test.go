@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_PHASE_HOOKS
+// Spec: spec/deploy/hooks.md
+
+type fakeRunner struct {
+	cmds []executil.Command
+	// results is consumed in order; missing entries succeed.
+	results []fakeResult
+}
+
+type fakeResult struct {
+	exitCode int
+	stderr   string
+	err      error
+}
+
+func (f *fakeRunner) Run(ctx context.Context, cmd executil.Command) (*executil.Result, error) {
+	f.cmds = append(f.cmds, cmd)
+	if len(f.results) == 0 {
+		return &executil.Result{}, nil
+	}
+	r := f.results[0]
+	f.results = f.results[1:]
+	return &executil.Result{ExitCode: r.exitCode, Stderr: []byte(r.stderr)}, r.err
+}
+
+func (f *fakeRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return errors.New("not implemented")
+}
+
+var testContext = ReleaseContext{
+	Project:     "demo",
+	Environment: "prod",
+	Command:     "deploy",
+	ReleaseID:   "rel-1",
+	Version:     "v1.2.3",
+	CommitSHA:   "abc123",
+}
+
+func quietLogger() logging.Logger {
+	return logging.New(logging.Options{Level: logging.LevelError, Out: io.Discard, ErrOut: io.Discard})
+}
+
+func TestRun_LocalHookReceivesReleaseContext(t *testing.T) {
+	runner := &fakeRunner{}
+	r := NewRunner(map[string][]config.HookConfig{
+		"pre_rollout": {{Command: []string{"./scripts/drain.sh", "--fast"}}},
+	}, testContext, Options{Runner: runner, Logger: quietLogger()})
+
+	if err := r.Run(context.Background(), "pre", "rollout"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(runner.cmds) != 1 {
+		t.Fatalf("expected 1 command, got %d", len(runner.cmds))
+	}
+	cmd := runner.cmds[0]
+	if cmd.Name != "./scripts/drain.sh" || strings.Join(cmd.Args, " ") != "--fast" {
+		t.Errorf("unexpected command: %s %v", cmd.Name, cmd.Args)
+	}
+	want := map[string]string{
+		"STAGECRAFT_ENV":             "prod",
+		"STAGECRAFT_HOOK":            "pre_rollout",
+		"STAGECRAFT_PHASE":           "rollout",
+		"STAGECRAFT_RELEASE_ID":      "rel-1",
+		"STAGECRAFT_RELEASE_VERSION": "v1.2.3",
+	}
+	for k, v := range want {
+		if cmd.Env[k] != v {
+			t.Errorf("%s = %q, want %q", k, cmd.Env[k], v)
+		}
+	}
+}
+
+func TestRun_RemoteHookUsesSSH(t *testing.T) {
+	runner := &fakeRunner{}
+	r := NewRunner(map[string][]config.HookConfig{
+		"post_finalize": {{Command: []string{"echo", "it's done"}, Host: "deploy@10.0.0.5"}},
+	}, testContext, Options{Runner: runner, Logger: quietLogger()})
+
+	if err := r.Run(context.Background(), "post", "finalize"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	cmd := runner.cmds[0]
+	if cmd.Name != "ssh" {
+		t.Fatalf("expected ssh, got %q", cmd.Name)
+	}
+	if len(cmd.Args) != 4 || cmd.Args[2] != "deploy@10.0.0.5" {
+		t.Fatalf("unexpected ssh args: %v", cmd.Args)
+	}
+	remote := cmd.Args[3]
+	if !strings.HasPrefix(remote, "env 'STAGECRAFT_COMMAND=deploy' ") {
+		t.Errorf("expected sorted env prefix, got %q", remote)
+	}
+	if !strings.HasSuffix(remote, `'echo' 'it'\''s done'`) {
+		t.Errorf("expected quoted command, got %q", remote)
+	}
+	if len(cmd.Env) != 0 {
+		t.Errorf("expected no local env for remote hook, got %v", cmd.Env)
+	}
+}
+
+func TestRun_StopsAtFirstFailure(t *testing.T) {
+	runner := &fakeRunner{results: []fakeResult{{exitCode: 3, stderr: "boom", err: errors.New("exit status 3")}}}
+	r := NewRunner(map[string][]config.HookConfig{
+		"pre_build": {{Command: []string{"first"}}, {Command: []string{"second"}}},
+	}, testContext, Options{Runner: runner, Logger: quietLogger()})
+
+	err := r.Run(context.Background(), "pre", "build")
+
+	var hookErr *Error
+	if !errors.As(err, &hookErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if hookErr.Kind != FailureExit || hookErr.ExitCode != 3 || hookErr.Point != "pre_build" || hookErr.Index != 0 {
+		t.Errorf("unexpected hook error: %+v", hookErr)
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected stderr in message, got %q", err.Error())
+	}
+	if len(runner.cmds) != 1 {
+		t.Errorf("expected second hook to be skipped, got %d runs", len(runner.cmds))
+	}
+}
+
+func TestRun_ContinueOnError(t *testing.T) {
+	runner := &fakeRunner{results: []fakeResult{{exitCode: 1, err: errors.New("exit status 1")}}}
+	r := NewRunner(map[string][]config.HookConfig{
+		"post_push": {{Command: []string{"flaky"}, ContinueOnError: true}, {Command: []string{"next"}}},
+	}, testContext, Options{Runner: runner, Logger: quietLogger()})
+
+	if err := r.Run(context.Background(), "post", "push"); err != nil {
+		t.Fatalf("expected continue_on_error to swallow failure, got %v", err)
+	}
+	if len(runner.cmds) != 2 {
+		t.Errorf("expected both hooks to run, got %d", len(runner.cmds))
+	}
+}
+
+func TestRun_ReportsEachExecution(t *testing.T) {
+	runner := &fakeRunner{results: []fakeResult{{exitCode: 1, err: errors.New("exit status 1")}}}
+	var got []Execution
+	r := NewRunner(map[string][]config.HookConfig{
+		"post_push": {{Command: []string{"flaky"}, ContinueOnError: true}, {Command: []string{"next"}, Host: "web-1"}},
+	}, testContext, Options{Runner: runner, Logger: quietLogger(), OnRun: func(_ context.Context, exec Execution) {
+		got = append(got, exec)
+	}})
+
+	if err := r.Run(context.Background(), "post", "push"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 executions, got %+v", got)
+	}
+	if got[0].Hook != "post_push[0]" || got[0].Phase != "push" || got[0].ReleaseID != "rel-1" || got[0].Err == nil {
+		t.Errorf("unexpected first execution: %+v", got[0])
+	}
+	if got[1].Hook != "post_push[1]" || got[1].Host != "web-1" || got[1].Err != nil {
+		t.Errorf("unexpected second execution: %+v", got[1])
+	}
+}
+
+func TestRun_FiltersEnvironments(t *testing.T) {
+	runner := &fakeRunner{}
+	r := NewRunner(map[string][]config.HookConfig{
+		"pre_rollout": {{Command: []string{"staging-only"}, Environments: []string{"staging"}}},
+	}, testContext, Options{Runner: runner, Logger: quietLogger()})
+
+	if r.Has("pre_rollout") {
+		t.Errorf("expected no applicable hooks for prod")
+	}
+	if err := r.Run(context.Background(), "pre", "rollout"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(runner.cmds) != 0 {
+		t.Errorf("expected hook to be skipped, got %d runs", len(runner.cmds))
+	}
+}
+
+func TestRun_DryRunExecutesNothing(t *testing.T) {
+	runner := &fakeRunner{}
+	r := NewRunner(map[string][]config.HookConfig{
+		"pre_rollout": {{Command: []string{"rm", "-rf", "/tmp/x"}}},
+	}, testContext, Options{DryRun: true, Runner: runner, Logger: quietLogger()})
+
+	if err := r.Run(context.Background(), "pre", "rollout"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(runner.cmds) != 0 {
+		t.Errorf("expected no commands in dry-run, got %d", len(runner.cmds))
+	}
+}
+
+func TestClassify(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancelExpired()
+	<-expired.Done()
+
+	bg := context.Background()
+	exitErr := fmt.Errorf("command failed with exit code 2: %w", errors.New("exit status 2"))
+
+	tests := []struct {
+		name     string
+		parent   context.Context
+		runCtx   context.Context
+		err      error
+		exitCode int
+		remote   bool
+		want     FailureKind
+	}{
+		{"canceled", canceled, canceled, exitErr, -1, false, FailureCanceled},
+		{"timeout", bg, expired, exitErr, -1, false, FailureTimeout},
+		{"not found", bg, bg, fmt.Errorf("executing command: %w", exec.ErrNotFound), -1, false, FailureNotFound},
+		{"non-zero exit", bg, bg, exitErr, 2, false, FailureExit},
+		{"ssh unreachable", bg, bg, exitErr, 255, true, FailureUnreachable},
+		{"local 255", bg, bg, exitErr, 255, false, FailureExit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.parent, tt.runCtx, tt.err, tt.exitCode, tt.remote); got != tt.want {
+				t.Errorf("classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package imagescan generates SBOMs for built images with syft and scans
+// them for known vulnerabilities with grype or trivy.
+package imagescan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"stagecraft/internal/containerruntime"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEPLOY_IMAGE_SCAN
+// Spec: spec/deploy/image-scan.md
+
+// Severity is a normalized vulnerability severity.
+type Severity string
+
+// Severities, lowest first. Scanner-specific levels such as "unknown" map
+// to SeverityUnknown, which never fails a scan.
+const (
+	SeverityUnknown    Severity = "unknown"
+	SeverityNegligible Severity = "negligible"
+	SeverityLow        Severity = "low"
+	SeverityMedium     Severity = "medium"
+	SeverityHigh       Severity = "high"
+	SeverityCritical   Severity = "critical"
+)
+
+// severityRank orders severities; unknown ranks below every threshold.
+var severityRank = map[Severity]int{
+	SeverityUnknown:    0,
+	SeverityNegligible: 1,
+	SeverityLow:        2,
+	SeverityMedium:     3,
+	SeverityHigh:       4,
+	SeverityCritical:   5,
+}
+
+// ParseSeverity normalizes a scanner's severity label.
+func ParseSeverity(label string) Severity {
+	s := Severity(strings.ToLower(strings.TrimSpace(label)))
+	if _, ok := severityRank[s]; ok {
+		return s
+	}
+	return SeverityUnknown
+}
+
+// Summary counts the vulnerabilities a scan found, by severity.
+type Summary struct {
+	Counts map[Severity]int
+}
+
+// AtOrAbove returns the number of vulnerabilities at or above threshold.
+func (s Summary) AtOrAbove(threshold Severity) int {
+	n := 0
+	for severity, count := range s.Counts {
+		if severity != SeverityUnknown && severityRank[severity] >= severityRank[threshold] {
+			n += count
+		}
+	}
+	return n
+}
+
+// String renders the non-zero counts, highest severity first, e.g.
+// "critical=1 high=3".
+func (s Summary) String() string {
+	severities := make([]Severity, 0, len(s.Counts))
+	for severity, count := range s.Counts {
+		if count > 0 {
+			severities = append(severities, severity)
+		}
+	}
+	if len(severities) == 0 {
+		return "no vulnerabilities"
+	}
+	sort.Slice(severities, func(i, j int) bool {
+		return severityRank[severities[i]] > severityRank[severities[j]]
+	})
+
+	parts := make([]string, 0, len(severities))
+	for _, severity := range severities {
+		parts = append(parts, fmt.Sprintf("%s=%d", severity, s.Counts[severity]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// source returns the syft/grype image source reading image from the local
+// runtime's image store rather than a registry.
+func source(runtime containerruntime.Runtime, image string) string {
+	if runtime == containerruntime.Podman {
+		return "podman:" + image
+	}
+	return "docker:" + image
+}
+
+// SBOMCommand returns the syft command that prints image's SBOM as SPDX JSON.
+func SBOMCommand(runtime containerruntime.Runtime, image string) executil.Command {
+	return executil.NewCommand("syft", source(runtime, image), "-o", "spdx-json", "-q")
+}
+
+// GenerateSBOM writes image's SPDX JSON SBOM to path, creating its directory.
+func GenerateSBOM(ctx context.Context, runner executil.Runner, runtime containerruntime.Runtime, image, path string) error {
+	result, err := runner.Run(ctx, SBOMCommand(runtime, image))
+	if err != nil {
+		return fmt.Errorf("syft %s: %w", image, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("creating SBOM directory: %w", err)
+	}
+	if err := os.WriteFile(path, result.Stdout, 0o600); err != nil {
+		return fmt.Errorf("writing SBOM: %w", err)
+	}
+	return nil
+}
+
+// ScanCommand returns the scanner command that reports image's
+// vulnerabilities as JSON.
+func ScanCommand(scanner string, runtime containerruntime.Runtime, image string) executil.Command {
+	if scanner == config.ScannerTrivy {
+		return executil.NewCommand("trivy", "image", "--image-src", runtime.Binary(), "--format", "json", "--quiet", image)
+	}
+	return executil.NewCommand("grype", source(runtime, image), "-o", "json", "-q")
+}
+
+// Scan runs scanner over image and counts the vulnerabilities it reports.
+func Scan(ctx context.Context, runner executil.Runner, scanner string, runtime containerruntime.Runtime, image string) (Summary, error) {
+	result, err := runner.Run(ctx, ScanCommand(scanner, runtime, image))
+	if err != nil {
+		return Summary{}, fmt.Errorf("%s %s: %w", scanner, image, err)
+	}
+
+	var severities []string
+	if scanner == config.ScannerTrivy {
+		severities, err = trivySeverities(result.Stdout)
+	} else {
+		severities, err = grypeSeverities(result.Stdout)
+	}
+	if err != nil {
+		return Summary{}, fmt.Errorf("parsing %s report for %s: %w", scanner, image, err)
+	}
+
+	summary := Summary{Counts: make(map[Severity]int)}
+	for _, label := range severities {
+		summary.Counts[ParseSeverity(label)]++
+	}
+	return summary, nil
+}
+
+// grypeSeverities extracts the severity of every match in a grype JSON report.
+func grypeSeverities(data []byte) ([]string, error) {
+	var report struct {
+		Matches []struct {
+			Vulnerability struct {
+				Severity string `json:"severity"`
+			} `json:"vulnerability"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+
+	severities := make([]string, 0, len(report.Matches))
+	for _, m := range report.Matches {
+		severities = append(severities, m.Vulnerability.Severity)
+	}
+	return severities, nil
+}
+
+// trivySeverities extracts the severity of every vulnerability in a trivy
+// JSON report.
+func trivySeverities(data []byte) ([]string, error) {
+	var report struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				Severity string `json:"Severity"`
+			} `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+
+	var severities []string
+	for _, r := range report.Results {
+		for _, v := range r.Vulnerabilities {
+			severities = append(severities, v.Severity)
+		}
+	}
+	return severities, nil
+}
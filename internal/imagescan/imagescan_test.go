@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package imagescan
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/containerruntime"
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEPLOY_IMAGE_SCAN
+// Spec: spec/deploy/image-scan.md
+
+type stubRunner struct {
+	outputs map[string]string
+}
+
+func (r *stubRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	line := strings.Join(append([]string{cmd.Name}, cmd.Args...), " ")
+	out, ok := r.outputs[line]
+	if !ok {
+		return &executil.Result{ExitCode: -1}, errors.New("executable file not found")
+	}
+	return &executil.Result{Stdout: []byte(out)}, nil
+}
+
+func (r *stubRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return nil
+}
+
+func TestScan_Grype(t *testing.T) {
+	runner := &stubRunner{outputs: map[string]string{
+		"grype docker:app:v1 -o json -q": `{"matches": [
+			{"vulnerability": {"id": "CVE-1", "severity": "Critical"}},
+			{"vulnerability": {"id": "CVE-2", "severity": "High"}},
+			{"vulnerability": {"id": "CVE-3", "severity": "Low"}},
+			{"vulnerability": {"id": "CVE-4", "severity": "Unknown"}}
+		]}`,
+	}}
+
+	summary, err := Scan(context.Background(), runner, config.ScannerGrype, containerruntime.Docker, "app:v1")
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if got := summary.AtOrAbove(SeverityHigh); got != 2 {
+		t.Errorf("AtOrAbove(high) = %d, want 2", got)
+	}
+	if got := summary.AtOrAbove(SeverityNegligible); got != 3 {
+		t.Errorf("AtOrAbove(negligible) = %d, want 3 (unknown never counts)", got)
+	}
+	if got := summary.String(); got != "critical=1 high=1 low=1 unknown=1" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestScan_Trivy(t *testing.T) {
+	runner := &stubRunner{outputs: map[string]string{
+		"trivy image --image-src podman --format json --quiet app:v1": `{"Results": [
+			{"Target": "app:v1 (alpine 3.19)", "Vulnerabilities": [{"Severity": "MEDIUM"}, {"Severity": "HIGH"}]},
+			{"Target": "node-pkg"}
+		]}`,
+	}}
+
+	summary, err := Scan(context.Background(), runner, config.ScannerTrivy, containerruntime.Podman, "app:v1")
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if got := summary.AtOrAbove(SeverityCritical); got != 0 {
+		t.Errorf("AtOrAbove(critical) = %d, want 0", got)
+	}
+	if got := summary.AtOrAbove(SeverityMedium); got != 2 {
+		t.Errorf("AtOrAbove(medium) = %d, want 2", got)
+	}
+}
+
+func TestScan_ScannerMissing(t *testing.T) {
+	_, err := Scan(context.Background(), &stubRunner{}, config.ScannerGrype, containerruntime.Docker, "app:v1")
+	if err == nil || !strings.Contains(err.Error(), "grype app:v1") {
+		t.Fatalf("expected scanner error naming the image, got %v", err)
+	}
+}
+
+func TestGenerateSBOM(t *testing.T) {
+	runner := &stubRunner{outputs: map[string]string{
+		"syft docker:app:v1 -o spdx-json -q": `{"spdxVersion": "SPDX-2.3"}`,
+	}}
+	path := filepath.Join(t.TempDir(), "sbom", "rel-1", "api.spdx.json")
+
+	if err := GenerateSBOM(context.Background(), runner, containerruntime.Docker, "app:v1", path); err != nil {
+		t.Fatalf("GenerateSBOM failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading SBOM: %v", err)
+	}
+	if !strings.Contains(string(data), "SPDX-2.3") {
+		t.Errorf("unexpected SBOM content: %s", data)
+	}
+}
+
+func TestSummary_NoVulnerabilities(t *testing.T) {
+	if got := (Summary{}).String(); got != "no vulnerabilities" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := ParseSeverity(" HIGH "); got != SeverityHigh {
+		t.Errorf("ParseSeverity() = %q, want high", got)
+	}
+}
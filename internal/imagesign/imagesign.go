@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package imagesign signs pushed images and verifies their signatures with
+// cosign.
+package imagesign
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEPLOY_IMAGE_SIGNING
+// Spec: spec/deploy/image-signing.md
+
+// PasswordEnv is the variable cosign reads the private key password from.
+const PasswordEnv = "COSIGN_PASSWORD"
+
+// DigestRef returns the reference that pins image to digest, dropping the
+// tag: "ghcr.io/org/app:v1" becomes "ghcr.io/org/app@sha256:...".
+func DigestRef(image, digest string) string {
+	repo := image
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repo = image[:i]
+	}
+	return repo + "@" + digest
+}
+
+// KeyEnv returns the variables cosign needs in its environment for key:
+// the variable an env:// reference names, and PasswordEnv when set.
+// lookup resolves variables, normally the shell environment followed by
+// the environment's env_file.
+func KeyEnv(key string, lookup func(string) (string, bool)) (map[string]string, error) {
+	env := make(map[string]string)
+	if name, ok := strings.CutPrefix(key, config.EnvKeyPrefix); ok {
+		value, found := lookup(name)
+		if !found || value == "" {
+			return nil, fmt.Errorf("signing key variable %s is not set", name)
+		}
+		env[name] = value
+	}
+	if password, ok := lookup(PasswordEnv); ok {
+		env[PasswordEnv] = password
+	}
+	return env, nil
+}
+
+// SignCommand returns the cosign command that signs ref with key.
+// recursive also signs every image of a manifest list.
+func SignCommand(key, ref string, recursive bool) executil.Command {
+	args := []string{"sign", "--yes", "--key", key}
+	if recursive {
+		args = append(args, "--recursive")
+	}
+	return executil.NewCommand("cosign", append(args, ref)...)
+}
+
+// VerifyCommand returns the cosign command that verifies ref's signature
+// against publicKey.
+func VerifyCommand(publicKey, ref string) executil.Command {
+	return executil.NewCommand("cosign", "verify", "--key", publicKey, ref)
+}
+
+// Sign signs ref with key, passing env to cosign.
+func Sign(ctx context.Context, runner executil.Runner, key, ref string, recursive bool, env map[string]string) error {
+	cmd := SignCommand(key, ref, recursive)
+	cmd.Env = env
+	if _, err := runner.Run(ctx, cmd); err != nil {
+		return fmt.Errorf("cosign sign %s: %w", ref, err)
+	}
+	return nil
+}
+
+// Verify checks that ref carries a valid signature for publicKey.
+func Verify(ctx context.Context, runner executil.Runner, publicKey, ref string, env map[string]string) error {
+	cmd := VerifyCommand(publicKey, ref)
+	cmd.Env = env
+	if _, err := runner.Run(ctx, cmd); err != nil {
+		return fmt.Errorf("cosign verify %s: %w", ref, err)
+	}
+	return nil
+}
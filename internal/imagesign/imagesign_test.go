@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package imagesign
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEPLOY_IMAGE_SIGNING
+// Spec: spec/deploy/image-signing.md
+
+type recordingRunner struct {
+	commands []executil.Command
+	fail     bool
+}
+
+func (r *recordingRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	r.commands = append(r.commands, cmd)
+	if r.fail {
+		return &executil.Result{ExitCode: 1}, errors.New("no matching signatures")
+	}
+	return &executil.Result{}, nil
+}
+
+func (r *recordingRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return nil
+}
+
+func TestDigestRef(t *testing.T) {
+	digest := "sha256:abc"
+	tests := map[string]string{
+		"ghcr.io/org/app:v1": "ghcr.io/org/app@sha256:abc",
+		"localhost:5000/app": "localhost:5000/app@sha256:abc",
+		"app":                "app@sha256:abc",
+	}
+	for image, want := range tests {
+		if got := DigestRef(image, digest); got != want {
+			t.Errorf("DigestRef(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestKeyEnv(t *testing.T) {
+	vars := map[string]string{"COSIGN_PRIVATE_KEY": "-----BEGIN KEY-----", PasswordEnv: "hunter2"}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+
+	env, err := KeyEnv("env://COSIGN_PRIVATE_KEY", lookup)
+	if err != nil {
+		t.Fatalf("KeyEnv failed: %v", err)
+	}
+	if env["COSIGN_PRIVATE_KEY"] != "-----BEGIN KEY-----" || env[PasswordEnv] != "hunter2" {
+		t.Errorf("unexpected env: %v", env)
+	}
+
+	env, err = KeyEnv("awskms:///alias/ci", func(string) (string, bool) { return "", false })
+	if err != nil || len(env) != 0 {
+		t.Errorf("expected KMS reference to need no variables, got %v, %v", env, err)
+	}
+
+	if _, err := KeyEnv("env://MISSING", lookup); err == nil || !strings.Contains(err.Error(), "MISSING is not set") {
+		t.Errorf("expected missing variable error, got %v", err)
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	runner := &recordingRunner{}
+	ref := "ghcr.io/org/app@sha256:abc"
+	env := map[string]string{PasswordEnv: "hunter2"}
+
+	if err := Sign(context.Background(), runner, "env://KEY", ref, true, env); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if err := Verify(context.Background(), runner, "cosign.pub", ref, nil); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	want := []string{
+		"cosign sign --yes --key env://KEY --recursive ghcr.io/org/app@sha256:abc",
+		"cosign verify --key cosign.pub ghcr.io/org/app@sha256:abc",
+	}
+	for i, cmd := range runner.commands {
+		if got := strings.Join(append([]string{cmd.Name}, cmd.Args...), " "); got != want[i] {
+			t.Errorf("command %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if runner.commands[0].Env[PasswordEnv] != "hunter2" {
+		t.Errorf("expected password to be passed to cosign sign")
+	}
+
+	failing := &recordingRunner{fail: true}
+	if err := Verify(context.Background(), failing, "cosign.pub", ref, nil); err == nil || !strings.Contains(err.Error(), "cosign verify "+ref) {
+		t.Errorf("expected verification error naming the image, got %v", err)
+	}
+}
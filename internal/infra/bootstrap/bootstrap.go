@@ -41,6 +41,24 @@ type Host struct {
 
 	// Tags are provider or user-defined tags.
 	Tags []string
+
+	// Volumes are block storage volumes to format and mount.
+	Volumes []Volume
+}
+
+// Volume is a block storage volume attached to a host.
+type Volume struct {
+	// Name is the volume name, unique per host (e.g., "data").
+	Name string
+
+	// Device is the block device path on the host.
+	Device string
+
+	// Filesystem is the filesystem to format the volume with (e.g., "ext4").
+	Filesystem string
+
+	// MountPoint is the absolute path to mount the volume at.
+	MountPoint string
 }
 
 // Config defines bootstrap-level configuration derived from stagecraft.yml.
@@ -151,6 +169,7 @@ func (s *service) Bootstrap(ctx context.Context, hosts []Host, cfg Config) (*Res
 //
 // v1 Slice 6: Ensures Docker is installed and working on the host.
 // v1 Slice 7: Ensures Tailscale is installed and joined via NetworkProvider.
+// Volumes: Formats and mounts attached block storage volumes.
 //
 //nolint:gocritic // hugeParam: host is passed by value for consistency with interface methods
 func (s *service) bootstrapHost(ctx context.Context, host Host, cfg Config) HostResult {
@@ -176,6 +195,15 @@ func (s *service) bootstrapHost(ctx context.Context, host Host, cfg Config) Host
 		}
 	}
 
+	// 3. Ensure attached volumes are formatted and mounted
+	if err := s.ensureVolumes(ctx, host); err != nil {
+		return HostResult{
+			Host:    host,
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+
 	return HostResult{
 		Host:    host,
 		Success: true,
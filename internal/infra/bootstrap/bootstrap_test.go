@@ -22,6 +22,7 @@ import (
 	"sync"
 	"testing"
 
+	hostexec "stagecraft/internal/exec"
 	"stagecraft/pkg/providers/network"
 )
 
@@ -41,10 +42,13 @@ type fakeExecutor struct {
 }
 
 //nolint:gocritic // hugeParam: host matches CommandExecutor interface signature
-func (f *fakeExecutor) Run(ctx context.Context, host Host, command string) (string, string, error) {
+func (f *fakeExecutor) Run(ctx context.Context, host Host, cmd hostexec.Cmd) (string, string, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	// Record the words unquoted so expectations read as typed
+	command := strings.Join(append([]string{cmd.Name}, cmd.Args...), " ")
+
 	f.commands = append(f.commands, struct {
 		Host    Host
 		Command string
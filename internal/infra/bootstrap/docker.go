@@ -19,6 +19,8 @@ package bootstrap
 import (
 	"context"
 	"fmt"
+
+	hostexec "stagecraft/internal/exec"
 )
 
 // ensureDocker ensures Docker is installed and working on the host.
@@ -56,7 +58,7 @@ func (s *service) ensureDocker(ctx context.Context, host Host) (bool, error) {
 //
 //nolint:gocritic // hugeParam: host is passed by value for consistency with interface methods
 func (s *service) hasDocker(ctx context.Context, host Host) bool {
-	_, _, err := s.executor.Run(ctx, host, "docker version")
+	_, _, err := s.executor.Run(ctx, host, hostexec.Command("docker", "version"))
 	return err == nil
 }
 
@@ -71,19 +73,19 @@ func (s *service) hasDocker(ctx context.Context, host Host) bool {
 //nolint:gocritic // hugeParam: host is passed by value for consistency with interface methods
 func (s *service) installDocker(ctx context.Context, host Host) error {
 	// Step 1: Update package list
-	stdout, stderr, err := s.executor.Run(ctx, host, "apt-get update -y")
+	stdout, stderr, err := s.executor.Run(ctx, host, hostexec.Command("apt-get", "update", "-y"))
 	if err != nil {
 		return fmt.Errorf("apt-get update failed: %w (stdout: %s, stderr: %s)", err, stdout, stderr)
 	}
 
 	// Step 2: Install Docker
-	stdout, stderr, err = s.executor.Run(ctx, host, "apt-get install -y docker.io")
+	stdout, stderr, err = s.executor.Run(ctx, host, hostexec.Command("apt-get", "install", "-y", "docker.io"))
 	if err != nil {
 		return fmt.Errorf("apt-get install docker.io failed: %w (stdout: %s, stderr: %s)", err, stdout, stderr)
 	}
 
 	// Step 3: Enable and start Docker service
-	stdout, stderr, err = s.executor.Run(ctx, host, "systemctl enable --now docker")
+	stdout, stderr, err = s.executor.Run(ctx, host, hostexec.Command("systemctl", "enable", "--now", "docker"))
 	if err != nil {
 		return fmt.Errorf("systemctl enable --now docker failed: %w (stdout: %s, stderr: %s)", err, stdout, stderr)
 	}
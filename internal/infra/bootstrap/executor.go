@@ -18,6 +18,8 @@ package bootstrap
 
 import (
 	"context"
+
+	hostexec "stagecraft/internal/exec"
 )
 
 // CommandExecutor defines the interface for executing commands on remote hosts.
@@ -28,14 +30,15 @@ import (
 // v1 Slice 5: Interface is defined; implementations will be added in later slices
 // (e.g., SSH-based executor using executil or a dedicated SSH library).
 type CommandExecutor interface {
-	// Run executes a command on the given host and returns stdout, stderr, and an error.
+	// Run executes cmd on the given host and returns stdout, stderr, and an error.
 	//
 	// The command is executed over SSH (or another remote execution mechanism)
 	// using the host's PublicIP and the SSH user from the bootstrap Config.
+	// Its words are passed as argv, so values never reach a shell unquoted.
 	//
 	// If the command fails, err should be non-nil. stdout and stderr should still
 	// be populated with any output captured before the failure.
-	Run(ctx context.Context, host Host, command string) (stdout string, stderr string, err error)
+	Run(ctx context.Context, host Host, cmd hostexec.Cmd) (stdout string, stderr string, err error)
 }
 
 // NoopExecutor is a stub executor that does not perform any real operations.
@@ -46,6 +49,6 @@ type NoopExecutor struct{}
 // Run implements CommandExecutor by returning empty output and no error.
 //
 //nolint:gocritic // hugeParam: Host matches CommandExecutor interface signature
-func (n *NoopExecutor) Run(_ context.Context, _ Host, _ string) (string, string, error) {
+func (n *NoopExecutor) Run(_ context.Context, _ Host, _ hostexec.Cmd) (string, string, error) {
 	return "", "", nil
 }
@@ -20,13 +20,14 @@ import (
 	"context"
 	"fmt"
 
+	hostexec "stagecraft/internal/exec"
 	"stagecraft/pkg/executil"
 )
 
 // SSHExecutor implements CommandExecutor using SSH to run commands on remote hosts.
 //
-// It uses the executil package to execute SSH commands locally, which then connect
-// to remote hosts and execute commands there.
+// It runs commands through a shared hostexec.SSHCommander, which executes the
+// ssh client locally via executil; the client then runs the command remotely.
 type SSHExecutor struct {
 	runner  executil.Runner
 	sshUser string
@@ -44,41 +45,27 @@ func NewSSHExecutor(sshUser string, runner executil.Runner) *SSHExecutor {
 	}
 }
 
-// Run executes the given command on the remote host using ssh.
+// Run executes cmd on the remote host using ssh.
 //
 // It builds a command like:
 //
-//	ssh -o BatchMode=yes -o StrictHostKeyChecking=no user@IP "<command>"
+//	ssh -o BatchMode=yes -o StrictHostKeyChecking=no user@IP "mkdir '-p' '/mnt/data'"
 //
-// The command is executed via executil.Runner, which handles context cancellation
-// and error handling.
+// Errors name the ssh target.
 //
 //nolint:gocritic // hugeParam: host matches CommandExecutor interface signature
-func (e *SSHExecutor) Run(ctx context.Context, host Host, command string) (string, string, error) {
+func (e *SSHExecutor) Run(ctx context.Context, host Host, cmd hostexec.Cmd) (string, string, error) {
 	if host.PublicIP == "" {
 		return "", "", fmt.Errorf("missing PublicIP for host %q", host.ID)
 	}
 
-	user := e.sshUser
-	if user == "" {
-		user = "root"
+	commander := hostexec.NewSSHCommander(e.runner)
+	commander.User = e.sshUser
+	if commander.User == "" {
+		commander.User = "root"
 	}
+	// Freshly created hosts are not in known_hosts yet
+	commander.Options = []string{"-o", "StrictHostKeyChecking=no"}
 
-	target := fmt.Sprintf("%s@%s", user, host.PublicIP)
-
-	args := []string{
-		"-o", "BatchMode=yes",
-		"-o", "StrictHostKeyChecking=no",
-		target,
-		command,
-	}
-
-	cmd := executil.NewCommand("ssh", args...)
-	result, err := e.runner.Run(ctx, cmd)
-	if err != nil {
-		// Wrap error with host context
-		return string(result.Stdout), string(result.Stderr), fmt.Errorf("ssh to %s failed: %w", target, err)
-	}
-
-	return string(result.Stdout), string(result.Stderr), nil
+	return cmd.Run(ctx, commander, host.PublicIP)
 }
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"testing"
 
+	hostexec "stagecraft/internal/exec"
 	"stagecraft/pkg/executil"
 )
 
@@ -65,7 +66,7 @@ func TestSSHExecutor_Run_Success(t *testing.T) {
 		PublicIP: "192.0.2.1",
 	}
 
-	stdout, stderr, err := exec.Run(context.Background(), host, "docker version")
+	stdout, stderr, err := exec.Run(context.Background(), host, hostexec.Command("docker", "version"))
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -92,8 +93,8 @@ func TestSSHExecutor_Run_Success(t *testing.T) {
 	if !strings.Contains(argsStr, "root@192.0.2.1") {
 		t.Errorf("expected ssh target 'root@192.0.2.1' in args, got %q", argsStr)
 	}
-	if !strings.Contains(argsStr, "docker version") {
-		t.Errorf("expected remote command 'docker version' in args, got %q", argsStr)
+	if !strings.Contains(argsStr, "docker 'version'") {
+		t.Errorf("expected remote command \"docker 'version'\" in args, got %q", argsStr)
 	}
 	if !strings.Contains(argsStr, "-o BatchMode=yes") {
 		t.Errorf("expected BatchMode option in args, got %q", argsStr)
@@ -120,7 +121,7 @@ func TestSSHExecutor_Run_ErrorWrapped(t *testing.T) {
 		PublicIP: "198.51.100.10",
 	}
 
-	_, stderr, err := exec.Run(context.Background(), host, "docker ps")
+	_, stderr, err := exec.Run(context.Background(), host, hostexec.Command("docker", "ps"))
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
@@ -143,7 +144,7 @@ func TestSSHExecutor_Run_MissingPublicIP(t *testing.T) {
 		// PublicIP empty
 	}
 
-	_, _, err := exec.Run(context.Background(), host, "docker ps")
+	_, _, err := exec.Run(context.Background(), host, hostexec.Command("docker", "ps"))
 	if err == nil {
 		t.Fatalf("expected error for missing PublicIP, got nil")
 	}
@@ -175,7 +176,7 @@ func TestSSHExecutor_Run_DefaultUser(t *testing.T) {
 		PublicIP: "203.0.113.5",
 	}
 
-	_, _, err := exec.Run(context.Background(), host, "whoami")
+	_, _, err := exec.Run(context.Background(), host, hostexec.Command("whoami"))
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -205,7 +206,7 @@ func TestSSHExecutor_Run_NonZeroExitCode(t *testing.T) {
 		PublicIP: "192.0.2.5",
 	}
 
-	_, stderr, err := exec.Run(context.Background(), host, "docker version")
+	_, stderr, err := exec.Run(context.Background(), host, hostexec.Command("docker", "version"))
 	if err == nil {
 		t.Fatalf("expected error for non-zero exit code, got nil")
 	}
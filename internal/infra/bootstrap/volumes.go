@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+// Feature: PROVIDER_CLOUD_VOLUMES
+// Spec: spec/providers/cloud/volumes.md
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	hostexec "stagecraft/internal/exec"
+)
+
+// volumePathPattern restricts device and mount paths to characters that are
+// safe in /etc/fstab fields.
+var volumePathPattern = regexp.MustCompile(`^/[A-Za-z0-9/_.-]+$`)
+
+// fstabScript adds an /etc/fstab entry for device $1 at mount point $2 with
+// filesystem $3 unless the device already has one.
+const fstabScript = `grep -qs "^$1 " /etc/fstab || echo "$1 $2 $3 defaults,nofail,discard 0 2" >> /etc/fstab`
+
+// mountScript mounts mount point $1 unless something is mounted there.
+const mountScript = `mountpoint -q "$1" || mount "$1"`
+
+// supportedFilesystems lists the filesystems volumes may be formatted with.
+var supportedFilesystems = map[string]bool{"ext4": true, "xfs": true}
+
+// ensureVolumes formats and mounts the host's volumes.
+//
+// Each step is idempotent:
+// 1. Verify the device exists
+// 2. Format only when the device has no filesystem (never reformat)
+// 3. Create the mount point
+// 4. Add an /etc/fstab entry (nofail) if missing
+// 5. Mount unless already mounted
+//
+//nolint:gocritic // hugeParam: host is passed by value for consistency with interface methods
+func (s *service) ensureVolumes(ctx context.Context, host Host) error {
+	for _, v := range host.Volumes {
+		if err := s.ensureVolume(ctx, host, v); err != nil {
+			return fmt.Errorf("volume %s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+//nolint:gocritic // hugeParam: host is passed by value for consistency with interface methods
+func (s *service) ensureVolume(ctx context.Context, host Host, v Volume) error {
+	if !volumePathPattern.MatchString(v.Device) {
+		return fmt.Errorf("invalid device path %q", v.Device)
+	}
+	if !volumePathPattern.MatchString(v.MountPoint) {
+		return fmt.Errorf("invalid mount point %q", v.MountPoint)
+	}
+	if !supportedFilesystems[v.Filesystem] {
+		return fmt.Errorf("unsupported filesystem %q", v.Filesystem)
+	}
+
+	if _, _, err := s.executor.Run(ctx, host, hostexec.Command("test", "-b", v.Device)); err != nil {
+		return fmt.Errorf("device %s not found: %w", v.Device, err)
+	}
+
+	// blkid exits non-zero with no output when the device has no filesystem
+	stdout, _, _ := s.executor.Run(ctx, host, hostexec.Command("blkid", "-o", "value", "-s", "TYPE", v.Device))
+	switch existing := strings.TrimSpace(stdout); existing {
+	case "":
+		stdout, stderr, err := s.executor.Run(ctx, host, hostexec.Command("mkfs."+v.Filesystem, v.Device))
+		if err != nil {
+			return fmt.Errorf("mkfs.%s failed: %w (stdout: %s, stderr: %s)", v.Filesystem, err, stdout, stderr)
+		}
+	case v.Filesystem:
+		// Already formatted
+	default:
+		return fmt.Errorf("device %s has filesystem %s, want %s (refusing to reformat)", v.Device, existing, v.Filesystem)
+	}
+
+	if stdout, stderr, err := s.executor.Run(ctx, host, hostexec.Command("mkdir", "-p", v.MountPoint)); err != nil {
+		return fmt.Errorf("mkdir %s failed: %w (stdout: %s, stderr: %s)", v.MountPoint, err, stdout, stderr)
+	}
+
+	fstab := hostexec.Script(fstabScript, v.Device, v.MountPoint, v.Filesystem)
+	if stdout, stderr, err := s.executor.Run(ctx, host, fstab); err != nil {
+		return fmt.Errorf("updating /etc/fstab failed: %w (stdout: %s, stderr: %s)", err, stdout, stderr)
+	}
+
+	mount := hostexec.Script(mountScript, v.MountPoint)
+	if stdout, stderr, err := s.executor.Run(ctx, host, mount); err != nil {
+		return fmt.Errorf("mount %s failed: %w (stdout: %s, stderr: %s)", v.MountPoint, err, stdout, stderr)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+// Feature: PROVIDER_CLOUD_VOLUMES
+// Spec: spec/providers/cloud/volumes.md
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func volumeTestHost() Host {
+	return Host{
+		ID:       "host-1",
+		Name:     "db-1",
+		PublicIP: "192.0.2.1",
+		Volumes: []Volume{{
+			Name:       "data",
+			Device:     "/dev/disk/by-id/scsi-0DO_Volume_prod-db-1-data",
+			Filesystem: "ext4",
+			MountPoint: "/mnt/data",
+		}},
+	}
+}
+
+func commandList(exec *fakeExecutor) []string {
+	var out []string
+	for _, c := range exec.getCommands() {
+		out = append(out, c.Command)
+	}
+	return out
+}
+
+func TestBootstrap_VolumeFormattedAndMounted(t *testing.T) {
+	exec := &fakeExecutor{
+		behavior: func(host Host, cmd string) (string, string, error) {
+			if strings.HasPrefix(cmd, "blkid") {
+				return "", "", fmt.Errorf("exit status 2")
+			}
+			return "", "", nil
+		},
+	}
+
+	result, err := NewService(exec, nil).Bootstrap(context.Background(), []Host{volumeTestHost()}, Config{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !result.AllSucceeded() {
+		t.Fatalf("expected success, got: %+v", result.Hosts)
+	}
+
+	device := "/dev/disk/by-id/scsi-0DO_Volume_prod-db-1-data"
+	want := []string{
+		"docker version",
+		"test -b " + device,
+		"blkid -o value -s TYPE " + device,
+		"mkfs.ext4 " + device,
+		"mkdir -p /mnt/data",
+		"sh -c " + fstabScript + " sh " + device + " /mnt/data ext4",
+		"sh -c " + mountScript + " sh /mnt/data",
+	}
+	if got := commandList(exec); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("commands =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestBootstrap_VolumeAlreadyFormattedIsNotReformatted(t *testing.T) {
+	exec := &fakeExecutor{
+		behavior: func(host Host, cmd string) (string, string, error) {
+			if strings.HasPrefix(cmd, "blkid") {
+				return "ext4\n", "", nil
+			}
+			return "", "", nil
+		},
+	}
+
+	result, _ := NewService(exec, nil).Bootstrap(context.Background(), []Host{volumeTestHost()}, Config{})
+	if !result.AllSucceeded() {
+		t.Fatalf("expected success, got: %+v", result.Hosts)
+	}
+	for _, cmd := range commandList(exec) {
+		if strings.HasPrefix(cmd, "mkfs") {
+			t.Errorf("unexpected format command: %s", cmd)
+		}
+	}
+}
+
+func TestBootstrap_VolumeWithForeignFilesystemFails(t *testing.T) {
+	exec := &fakeExecutor{
+		behavior: func(host Host, cmd string) (string, string, error) {
+			if strings.HasPrefix(cmd, "blkid") {
+				return "xfs\n", "", nil
+			}
+			return "", "", nil
+		},
+	}
+
+	result, _ := NewService(exec, nil).Bootstrap(context.Background(), []Host{volumeTestHost()}, Config{})
+	if result.AllSucceeded() {
+		t.Fatal("expected failure for mismatched filesystem")
+	}
+	if !strings.Contains(result.Hosts[0].Error, "refusing to reformat") {
+		t.Errorf("unexpected error: %s", result.Hosts[0].Error)
+	}
+}
+
+func TestBootstrap_VolumeRejectsUnsafePaths(t *testing.T) {
+	host := volumeTestHost()
+	host.Volumes[0].MountPoint = "/mnt/data; rm -rf /"
+
+	exec := &fakeExecutor{}
+	result, _ := NewService(exec, nil).Bootstrap(context.Background(), []Host{host}, Config{})
+	if result.AllSucceeded() {
+		t.Fatal("expected failure for unsafe mount point")
+	}
+	for _, cmd := range commandList(exec) {
+		if cmd != "docker version" {
+			t.Errorf("unexpected command after validation failure: %s", cmd)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+// Package cloudinit renders per-role cloud-init user data for new hosts.
+//
+// Templates use text/template with missing keys treated as errors, so
+// rendering is deterministic for a given template, Data and environment.
+//
+// Feature: PROVIDER_CLOUD_USER_DATA
+// Spec: spec/providers/cloud/user-data.md
+package cloudinit
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// BuiltinRef selects BuiltinTemplate instead of a template file.
+const BuiltinRef = "builtin"
+
+// BuiltinTemplate installs Docker and, when a tailnet auth key env var is
+// configured, joins the tailnet on first boot.
+const BuiltinTemplate = `#cloud-config
+hostname: {{ .Host }}
+{{- if .SSHKeys }}
+ssh_authorized_keys:
+{{- range .SSHKeys }}
+  - {{ . }}
+{{- end }}
+{{- end }}
+package_update: true
+packages:
+  - docker.io
+runcmd:
+  - systemctl enable --now docker
+{{- if .TailnetAuthKeyEnv }}
+  - curl -fsSL https://tailscale.com/install.sh | sh
+  - tailscale up --authkey={{ env .TailnetAuthKeyEnv | quote }} --hostname={{ .Host | quote }}
+{{- end }}
+`
+
+// Data is the input available to user data templates.
+type Data struct {
+	// Host is the logical host name (e.g., "app-1").
+	Host string
+
+	// Environment is the environment name (e.g., "prod").
+	Environment string
+
+	// Role is the host role (e.g., "app", "gateway").
+	Role string
+
+	// TailnetAuthKeyEnv names the environment variable holding the tailnet
+	// auth key. Templates read the value with {{ env .TailnetAuthKeyEnv }}.
+	TailnetAuthKeyEnv string
+
+	// SSHKeys are the public keys authorized on the host.
+	SSHKeys []string
+}
+
+// LookupEnvFunc looks up an environment variable.
+type LookupEnvFunc func(key string) (string, bool)
+
+// Render renders a user data template. lookupEnv backs the env template
+// function; nil uses os.LookupEnv. Referencing an unset variable is an error.
+//
+//nolint:gocritic // hugeParam: Data is passed by value to keep rendering side-effect free
+func Render(name, text string, data Data, lookupEnv LookupEnvFunc) (string, error) {
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	funcs := template.FuncMap{
+		"env": func(key string) (string, error) {
+			value, ok := lookupEnv(key)
+			if !ok || value == "" {
+				return "", fmt.Errorf("environment variable %s is not set", key)
+			}
+			return value, nil
+		},
+		"quote": shellQuote,
+		"join":  strings.Join,
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing user data template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering user data template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// Load returns the template text for ref: BuiltinTemplate for BuiltinRef,
+// otherwise the contents of the file at ref.
+func Load(ref string) (string, error) {
+	if ref == BuiltinRef {
+		return BuiltinTemplate, nil
+	}
+
+	//nolint:gosec // G304: template path comes from trusted config
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", fmt.Errorf("reading user data template: %w", err)
+	}
+	return string(data), nil
+}
+
+// shellQuote single-quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
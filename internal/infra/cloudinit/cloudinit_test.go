@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+// Feature: PROVIDER_CLOUD_USER_DATA
+// Spec: spec/providers/cloud/user-data.md
+
+package cloudinit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func fakeEnv(values map[string]string) LookupEnvFunc {
+	return func(key string) (string, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+}
+
+func TestRender_BuiltinTemplate(t *testing.T) {
+	data := Data{
+		Host:              "app-1",
+		Environment:       "prod",
+		Role:              "app",
+		TailnetAuthKeyEnv: "TS_AUTHKEY",
+		SSHKeys:           []string{"ssh-ed25519 AAAA deploy@example"},
+	}
+
+	got, err := Render("builtin", BuiltinTemplate, data, fakeEnv(map[string]string{"TS_AUTHKEY": "tskey-123"}))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := `#cloud-config
+hostname: app-1
+ssh_authorized_keys:
+  - ssh-ed25519 AAAA deploy@example
+package_update: true
+packages:
+  - docker.io
+runcmd:
+  - systemctl enable --now docker
+  - curl -fsSL https://tailscale.com/install.sh | sh
+  - tailscale up --authkey='tskey-123' --hostname='app-1'
+`
+	if got != want {
+		t.Fatalf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRender_BuiltinTemplateWithoutTailnet(t *testing.T) {
+	got, err := Render("builtin", BuiltinTemplate, Data{Host: "db-1"}, fakeEnv(nil))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(got, "tailscale") || strings.Contains(got, "ssh_authorized_keys") {
+		t.Errorf("expected no tailscale or ssh keys sections, got:\n%s", got)
+	}
+}
+
+func TestRender_Deterministic(t *testing.T) {
+	data := Data{Host: "app-1", Role: "app", SSHKeys: []string{"k1", "k2"}}
+	env := fakeEnv(nil)
+
+	first, err := Render("t", `{{ .Role }}:{{ join .SSHKeys "," }}`, data, env)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	second, _ := Render("t", `{{ .Role }}:{{ join .SSHKeys "," }}`, data, env)
+	if first != second || first != "app:k1,k2" {
+		t.Fatalf("Render() = %q then %q, want %q twice", first, second, "app:k1,k2")
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "unset env", text: `{{ env "MISSING" }}`, want: "MISSING is not set"},
+		{name: "unknown field", text: `{{ .Nope }}`, want: "rendering"},
+		{name: "parse error", text: `{{ .Host `, want: "parsing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render("t", tt.text, Data{Host: "app-1"}, fakeEnv(nil))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Render() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	builtin, err := Load(BuiltinRef)
+	if err != nil || builtin != BuiltinTemplate {
+		t.Fatalf("Load(builtin) = %q, %v", builtin, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("#cloud-config\n"), 0o600); err != nil {
+		t.Fatalf("writing template: %v", err)
+	}
+	text, err := Load(path)
+	if err != nil || text != "#cloud-config\n" {
+		t.Fatalf("Load(file) = %q, %v", text, err)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("expected error for missing template")
+	}
+}
@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package notify delivers deploy lifecycle notifications (Slack, generic
+// webhooks, shell hooks) configured per environment.
+//
+// Delivery is best-effort: failures are retried and then logged, but never
+// change the outcome of the deploy that triggered them.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_NOTIFICATIONS
+// Spec: spec/deploy/notifications.md
+
+// Event is a deploy lifecycle event.
+type Event string
+
+// Lifecycle events; values match the config event names.
+const (
+	EventStarted    Event = config.NotificationEventStarted
+	EventSucceeded  Event = config.NotificationEventSucceeded
+	EventFailed     Event = config.NotificationEventFailed
+	EventRolledBack Event = config.NotificationEventRolledBack
+)
+
+// Payload is the release metadata sent with every notification.
+type Payload struct {
+	Event       Event     `json:"event"`
+	Project     string    `json:"project"`
+	Environment string    `json:"environment"`
+	Command     string    `json:"command"`
+	ReleaseID   string    `json:"release_id,omitempty"`
+	Version     string    `json:"version,omitempty"`
+	CommitSHA   string    `json:"commit_sha,omitempty"`
+	Actor       string    `json:"actor,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Summary renders a one-line human-readable description of the payload.
+func (p Payload) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[stagecraft] %s/%s: %s %s", p.Project, p.Environment, p.Command, eventVerb(p.Event))
+	var details []string
+	if p.ReleaseID != "" {
+		details = append(details, "release "+p.ReleaseID)
+	}
+	if p.Version != "" {
+		details = append(details, "version "+p.Version)
+	}
+	if p.Actor != "" {
+		details = append(details, "by "+p.Actor)
+	}
+	if len(details) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(details, ", "))
+	}
+	if p.Error != "" {
+		fmt.Fprintf(&b, ": %s", p.Error)
+	}
+	return b.String()
+}
+
+func eventVerb(e Event) string {
+	switch e {
+	case EventStarted:
+		return "started"
+	case EventSucceeded:
+		return "succeeded"
+	case EventFailed:
+		return "failed"
+	case EventRolledBack:
+		return "rolled back"
+	default:
+		return string(e)
+	}
+}
+
+// Notifier delivers a payload to a single target.
+type Notifier interface {
+	Notify(ctx context.Context, p Payload) error
+}
+
+// Options configures a Dispatcher.
+type Options struct {
+	// DryRun logs what would be sent without delivering anything.
+	DryRun bool
+	// Logger receives delivery warnings and dry-run output.
+	Logger logging.Logger
+	// HTTPClient sends slack and webhook notifications. Nil uses a client
+	// with a 10s timeout.
+	HTTPClient *http.Client
+	// Runner executes shell hooks. Nil uses executil.NewRunner().
+	Runner executil.Runner
+	// Backoff returns the delay before retry attempt n (1-based). Nil uses
+	// a linear one-second backoff.
+	Backoff func(attempt int) time.Duration
+}
+
+type target struct {
+	cfg      config.NotificationConfig
+	notifier Notifier
+}
+
+// Dispatcher fans events out to the targets configured for an environment.
+type Dispatcher struct {
+	targets []target
+	opts    Options
+}
+
+// New builds a Dispatcher for the given targets.
+func New(targets []config.NotificationConfig, opts Options) *Dispatcher {
+	if opts.Logger == nil {
+		opts.Logger = logging.NewLogger(false)
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.Runner == nil {
+		opts.Runner = executil.NewRunner()
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = func(attempt int) time.Duration {
+			return time.Duration(attempt) * time.Second
+		}
+	}
+
+	d := &Dispatcher{opts: opts}
+	for _, cfg := range targets {
+		d.targets = append(d.targets, target{cfg: cfg, notifier: newNotifier(cfg, opts)})
+	}
+	return d
+}
+
+func newNotifier(cfg config.NotificationConfig, opts Options) Notifier {
+	switch cfg.Type {
+	case config.NotificationTypeSlack:
+		return &slackNotifier{url: cfg.URL, client: opts.HTTPClient}
+	case config.NotificationTypeWebhook:
+		return &webhookNotifier{url: cfg.URL, headers: cfg.Headers, client: opts.HTTPClient}
+	case config.NotificationTypeShell:
+		return &shellNotifier{command: cfg.Command, runner: opts.Runner}
+	default:
+		// Config validation rejects unknown types; keep a safe fallback.
+		return unsupportedNotifier{typ: cfg.Type}
+	}
+}
+
+// Dispatch sends p to every target subscribed to p.Event, in config order.
+// Each target is retried according to its config; failures are logged.
+func (d *Dispatcher) Dispatch(ctx context.Context, p Payload) {
+	if p.Timestamp.IsZero() {
+		p.Timestamp = time.Now().UTC()
+	}
+
+	for i, t := range d.targets {
+		if !t.cfg.WantsEvent(string(p.Event)) {
+			continue
+		}
+
+		fields := []logging.Field{
+			logging.NewField("event", string(p.Event)),
+			logging.NewField("target", fmt.Sprintf("%s#%d", t.cfg.Type, i)),
+		}
+
+		if d.opts.DryRun {
+			d.opts.Logger.Info("Dry-run: would send notification", fields...)
+			continue
+		}
+
+		if err := d.deliver(ctx, t, p); err != nil {
+			d.opts.Logger.Warn("Notification failed", append(fields, logging.NewField("error", err.Error()))...)
+			continue
+		}
+		d.opts.Logger.Debug("Notification sent", fields...)
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, t target, p Payload) error {
+	var err error
+	retries := t.cfg.RetryCount()
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(d.opts.Backoff(attempt)):
+			}
+		}
+		if err = t.notifier.Notify(ctx, p); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("after %d attempts: %w", retries+1, err)
+}
+
+// slackNotifier posts a text message to a Slack incoming webhook.
+type slackNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func (n *slackNotifier) Notify(ctx context.Context, p Payload) error {
+	body, err := json.Marshal(map[string]string{"text": p.Summary()})
+	if err != nil {
+		return fmt.Errorf("encoding slack message: %w", err)
+	}
+	return postJSON(ctx, n.client, os.ExpandEnv(n.url), nil, body)
+}
+
+// webhookNotifier posts the JSON payload to an arbitrary endpoint.
+type webhookNotifier struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+	headers := make(map[string]string, len(n.headers))
+	for k, v := range n.headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+	return postJSON(ctx, n.client, os.ExpandEnv(n.url), headers, body)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "stagecraft")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// Avoid echoing the URL: webhook URLs usually embed secrets.
+		return fmt.Errorf("sending request: %w", redactURLError(err))
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// redactURLError strips the URL from *url.Error values.
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// shellNotifier runs a local command with the payload as JSON on stdin and
+// as STAGECRAFT_NOTIFY_* environment variables.
+type shellNotifier struct {
+	command []string
+	runner  executil.Runner
+}
+
+func (n *shellNotifier) Notify(ctx context.Context, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("encoding shell payload: %w", err)
+	}
+
+	cmd := executil.NewCommand(n.command[0], n.command[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = map[string]string{
+		"STAGECRAFT_NOTIFY_EVENT":       string(p.Event),
+		"STAGECRAFT_NOTIFY_PROJECT":     p.Project,
+		"STAGECRAFT_NOTIFY_ENVIRONMENT": p.Environment,
+		"STAGECRAFT_NOTIFY_COMMAND":     p.Command,
+		"STAGECRAFT_NOTIFY_RELEASE_ID":  p.ReleaseID,
+		"STAGECRAFT_NOTIFY_VERSION":     p.Version,
+		"STAGECRAFT_NOTIFY_COMMIT_SHA":  p.CommitSHA,
+		"STAGECRAFT_NOTIFY_ERROR":       p.Error,
+	}
+
+	if _, err := n.runner.Run(ctx, cmd); err != nil {
+		return fmt.Errorf("running shell hook: %w", err)
+	}
+	return nil
+}
+
+type unsupportedNotifier struct {
+	typ string
+}
+
+func (n unsupportedNotifier) Notify(context.Context, Payload) error {
+	return fmt.Errorf("unsupported notification type %q", n.typ)
+}
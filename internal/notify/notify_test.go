@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"stagecraft/pkg/config"
+	"stagecraft/pkg/executil"
+	"stagecraft/pkg/logging"
+)
+
+// Feature: DEPLOY_NOTIFICATIONS
+// Spec: spec/deploy/notifications.md
+
+func intPtr(v int) *int { return &v }
+
+func noBackoff(int) time.Duration { return 0 }
+
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) record(msg string, fields []logging.Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, f := range fields {
+		b.WriteString(" " + f.Key + "=")
+		b.WriteString(strings.TrimSpace(strings.ReplaceAll(fmtValue(f.Value), "\n", " ")))
+	}
+	l.messages = append(l.messages, b.String())
+}
+
+func fmtValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func (l *recordingLogger) Debug(msg string, fields ...logging.Field) { l.record(msg, fields) }
+func (l *recordingLogger) Info(msg string, fields ...logging.Field)  { l.record(msg, fields) }
+func (l *recordingLogger) Warn(msg string, fields ...logging.Field)  { l.record(msg, fields) }
+func (l *recordingLogger) Error(msg string, fields ...logging.Field) { l.record(msg, fields) }
+func (l *recordingLogger) WithFields(...logging.Field) logging.Logger {
+	return l
+}
+func (l *recordingLogger) Named(string) logging.Logger { return l }
+
+func (l *recordingLogger) joined() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.messages, "\n")
+}
+
+type fakeRunner struct {
+	cmds  []executil.Command
+	stdin []string
+	err   error
+}
+
+func (f *fakeRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) {
+	var stdin string
+	if cmd.Stdin != nil {
+		data, _ := io.ReadAll(cmd.Stdin)
+		stdin = string(data)
+	}
+	f.stdin = append(f.stdin, stdin)
+	f.cmds = append(f.cmds, cmd)
+	return &executil.Result{}, f.err
+}
+
+func (f *fakeRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return errors.New("not implemented")
+}
+
+func samplePayload(event Event) Payload {
+	return Payload{
+		Event:       event,
+		Project:     "demo",
+		Environment: "prod",
+		Command:     "deploy",
+		ReleaseID:   "rel-1",
+		Version:     "v1.2.3",
+		Actor:       "alice",
+		Timestamp:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestDispatch_WebhookPostsPayloadWithHeaders(t *testing.T) {
+	t.Setenv("HOOK_TOKEN", "s3cret")
+
+	var got Payload
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	d := New([]config.NotificationConfig{{
+		Type:    config.NotificationTypeWebhook,
+		URL:     srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer ${HOOK_TOKEN}"},
+	}}, Options{Logger: &recordingLogger{}, Backoff: noBackoff})
+
+	d.Dispatch(context.Background(), samplePayload(EventSucceeded))
+
+	if auth != "Bearer s3cret" {
+		t.Errorf("expected expanded Authorization header, got %q", auth)
+	}
+	if got.Event != EventSucceeded || got.ReleaseID != "rel-1" || got.Version != "v1.2.3" {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+}
+
+func TestDispatch_SlackPostsSummary(t *testing.T) {
+	var body map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer srv.Close()
+
+	d := New([]config.NotificationConfig{{
+		Type: config.NotificationTypeSlack,
+		URL:  srv.URL,
+	}}, Options{Logger: &recordingLogger{}, Backoff: noBackoff})
+
+	p := samplePayload(EventFailed)
+	p.Error = "phase rollout failed"
+	d.Dispatch(context.Background(), p)
+
+	want := "[stagecraft] demo/prod: deploy failed (release rel-1, version v1.2.3, by alice): phase rollout failed"
+	if body["text"] != want {
+		t.Errorf("slack text = %q, want %q", body["text"], want)
+	}
+}
+
+func TestDispatch_RetriesThenLogsWarning(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	d := New([]config.NotificationConfig{{
+		Type:    config.NotificationTypeWebhook,
+		URL:     srv.URL,
+		Retries: intPtr(2),
+	}}, Options{Logger: logger, Backoff: noBackoff})
+
+	d.Dispatch(context.Background(), samplePayload(EventStarted))
+
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+	out := logger.joined()
+	if !strings.Contains(out, "Notification failed") || !strings.Contains(out, "after 3 attempts") {
+		t.Errorf("expected failure warning, got:\n%s", out)
+	}
+	if strings.Contains(out, srv.URL) {
+		t.Errorf("expected webhook URL to be kept out of logs, got:\n%s", out)
+	}
+}
+
+func TestDispatch_RetrySucceeds(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	d := New([]config.NotificationConfig{{
+		Type: config.NotificationTypeWebhook,
+		URL:  srv.URL,
+	}}, Options{Logger: logger, Backoff: noBackoff})
+
+	d.Dispatch(context.Background(), samplePayload(EventStarted))
+
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+	if strings.Contains(logger.joined(), "Notification failed") {
+		t.Errorf("expected no failure warning, got:\n%s", logger.joined())
+	}
+}
+
+func TestDispatch_FiltersEvents(t *testing.T) {
+	runner := &fakeRunner{}
+	d := New([]config.NotificationConfig{{
+		Type:    config.NotificationTypeShell,
+		Command: []string{"notify"},
+		Events:  []string{config.NotificationEventFailed},
+	}}, Options{Logger: &recordingLogger{}, Runner: runner, Backoff: noBackoff})
+
+	d.Dispatch(context.Background(), samplePayload(EventStarted))
+	if len(runner.cmds) != 0 {
+		t.Fatalf("expected started event to be filtered, got %d runs", len(runner.cmds))
+	}
+
+	d.Dispatch(context.Background(), samplePayload(EventFailed))
+	if len(runner.cmds) != 1 {
+		t.Fatalf("expected failed event to run hook once, got %d runs", len(runner.cmds))
+	}
+}
+
+func TestDispatch_ShellHookReceivesPayload(t *testing.T) {
+	runner := &fakeRunner{}
+	d := New([]config.NotificationConfig{{
+		Type:    config.NotificationTypeShell,
+		Command: []string{"./notify.sh", "--quiet"},
+	}}, Options{Logger: &recordingLogger{}, Runner: runner, Backoff: noBackoff})
+
+	d.Dispatch(context.Background(), samplePayload(EventRolledBack))
+
+	if len(runner.cmds) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(runner.cmds))
+	}
+	cmd := runner.cmds[0]
+	if cmd.Name != "./notify.sh" || len(cmd.Args) != 1 || cmd.Args[0] != "--quiet" {
+		t.Errorf("unexpected command: %s %v", cmd.Name, cmd.Args)
+	}
+	if cmd.Env["STAGECRAFT_NOTIFY_EVENT"] != "deploy.rolled_back" {
+		t.Errorf("expected event env var, got %q", cmd.Env["STAGECRAFT_NOTIFY_EVENT"])
+	}
+	if cmd.Env["STAGECRAFT_NOTIFY_RELEASE_ID"] != "rel-1" {
+		t.Errorf("expected release env var, got %q", cmd.Env["STAGECRAFT_NOTIFY_RELEASE_ID"])
+	}
+	if !strings.Contains(runner.stdin[0], `"event":"deploy.rolled_back"`) {
+		t.Errorf("expected JSON payload on stdin, got %q", runner.stdin[0])
+	}
+}
+
+func TestDispatch_DryRunSendsNothing(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	d := New([]config.NotificationConfig{{
+		Type: config.NotificationTypeWebhook,
+		URL:  srv.URL,
+	}}, Options{DryRun: true, Logger: logger})
+
+	d.Dispatch(context.Background(), samplePayload(EventStarted))
+
+	if calls != 0 {
+		t.Errorf("expected no requests in dry-run, got %d", calls)
+	}
+	if !strings.Contains(logger.joined(), "would send notification") {
+		t.Errorf("expected dry-run log, got:\n%s", logger.joined())
+	}
+}
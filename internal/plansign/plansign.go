@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+// Package plansign signs and verifies plan content addresses with ed25519.
+//
+// Keys are read from the environment: either inline (base64) or from a file
+// holding base64 or PEM (PKCS#8 private keys, PKIX public keys, as written
+// by `openssl genpkey -algorithm ed25519`).
+package plansign
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"stagecraft/pkg/engine"
+)
+
+// Feature: ENGINE_PLAN_SIGNING
+// Spec: spec/engine/plan-signing.md
+
+const (
+	// SigningKeyEnv holds a base64 ed25519 private key (32-byte seed or 64-byte key).
+	SigningKeyEnv = "STAGECRAFT_PLAN_SIGNING_KEY"
+	// SigningKeyFileEnv names a file holding the private key.
+	SigningKeyFileEnv = "STAGECRAFT_PLAN_SIGNING_KEY_FILE"
+	// VerifyKeyEnv holds a base64 ed25519 public key.
+	VerifyKeyEnv = "STAGECRAFT_PLAN_VERIFY_KEY"
+	// VerifyKeyFileEnv names a file holding the public key.
+	VerifyKeyFileEnv = "STAGECRAFT_PLAN_VERIFY_KEY_FILE"
+)
+
+// signingContext separates plan signatures from any other use of the key.
+const signingContext = "stagecraft plan signature v1\x00"
+
+var (
+	// ErrUnsigned is returned when a signature is required but absent.
+	ErrUnsigned = errors.New("plan is not signed")
+	// ErrBadSignature is returned when a signature does not verify.
+	ErrBadSignature = errors.New("plan signature is invalid")
+)
+
+// Sign signs digest, a plan content address from engine.HashPlan or
+// engine.HashHostPlan.
+func Sign(digest string, key ed25519.PrivateKey) *engine.PlanSignature {
+	return &engine.PlanSignature{
+		Algorithm: engine.PlanSignatureAlgorithm,
+		KeyID:     KeyID(PublicKey(key)),
+		Digest:    digest,
+		Value:     base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(signingContext+digest))),
+	}
+}
+
+// Verify checks that sig is a valid signature by key over digest.
+func Verify(digest string, sig *engine.PlanSignature, key ed25519.PublicKey) error {
+	if sig == nil {
+		return ErrUnsigned
+	}
+	if sig.Algorithm != engine.PlanSignatureAlgorithm {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrBadSignature, sig.Algorithm)
+	}
+	if sig.Digest != digest {
+		return fmt.Errorf("%w: signed digest %s does not match plan digest %s", ErrBadSignature, sig.Digest, digest)
+	}
+	if want := KeyID(key); sig.KeyID != want {
+		return fmt.Errorf("%w: signed by key %s, expected %s", ErrBadSignature, sig.KeyID, want)
+	}
+	value, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return fmt.Errorf("%w: decoding signature: %v", ErrBadSignature, err)
+	}
+	if !ed25519.Verify(key, []byte(signingContext+digest), value) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// KeyID returns a short, stable identifier for key: the first 8 bytes of
+// its SHA-256, hex encoded.
+func KeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// LoadSigningKey returns the private key configured in SigningKeyEnv or
+// SigningKeyFileEnv. ok is false when neither is set.
+func LoadSigningKey() (key ed25519.PrivateKey, ok bool, err error) {
+	data, source, ok, err := readKeyEnv(SigningKeyEnv, SigningKeyFileEnv)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	key, err = ParsePrivateKey(data)
+	if err != nil {
+		return nil, true, fmt.Errorf("%s: %w", source, err)
+	}
+	return key, true, nil
+}
+
+// LoadVerifyKey returns the public key configured in VerifyKeyEnv or
+// VerifyKeyFileEnv. ok is false when neither is set.
+func LoadVerifyKey() (key ed25519.PublicKey, ok bool, err error) {
+	data, source, ok, err := readKeyEnv(VerifyKeyEnv, VerifyKeyFileEnv)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	key, err = ParsePublicKey(data)
+	if err != nil {
+		return nil, true, fmt.Errorf("%s: %w", source, err)
+	}
+	return key, true, nil
+}
+
+func readKeyEnv(inlineEnv, fileEnv string) (data []byte, source string, ok bool, err error) {
+	inline := os.Getenv(inlineEnv)
+	path := os.Getenv(fileEnv)
+	switch {
+	case inline != "" && path != "":
+		return nil, "", true, fmt.Errorf("set only one of %s and %s", inlineEnv, fileEnv)
+	case inline != "":
+		return []byte(inline), inlineEnv, true, nil
+	case path != "":
+		data, err := os.ReadFile(path) //nolint:gosec // path is provided by the operator
+		if err != nil {
+			return nil, "", true, fmt.Errorf("reading %s: %w", fileEnv, err)
+		}
+		return data, path, true, nil
+	default:
+		return nil, "", false, nil
+	}
+}
+
+// ParsePrivateKey parses a PEM PKCS#8 ed25519 private key, or base64 of a
+// 32-byte seed or 64-byte private key.
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing PEM private key: %w", err)
+		}
+		key, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("PEM private key is %T, not ed25519", parsed)
+		}
+		return key, nil
+	}
+
+	raw, err := decodeBase64(data)
+	if err != nil {
+		return nil, err
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("private key is %d bytes, expected %d or %d", len(raw), ed25519.SeedSize, ed25519.PrivateKeySize)
+	}
+}
+
+// ParsePublicKey parses a PEM PKIX ed25519 public key, or base64 of the
+// 32-byte key.
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing PEM public key: %w", err)
+		}
+		key, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("PEM public key is %T, not ed25519", parsed)
+		}
+		return key, nil
+	}
+
+	raw, err := decodeBase64(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key is %d bytes, expected %d", len(raw), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// PublicKey returns the public half of key.
+func PublicKey(key ed25519.PrivateKey) ed25519.PublicKey {
+	pub, _ := key.Public().(ed25519.PublicKey)
+	return pub
+}
+
+func decodeBase64(data []byte) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decoding base64 key: %w", err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package plansign
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stagecraft/pkg/engine"
+)
+
+// Feature: ENGINE_PLAN_SIGNING
+// Spec: spec/engine/plan-signing.md
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func testKey(t *testing.T, seed byte) ed25519.PrivateKey {
+	t.Helper()
+	raw := make([]byte, ed25519.SeedSize)
+	for i := range raw {
+		raw[i] = seed
+	}
+	return ed25519.NewKeyFromSeed(raw)
+}
+
+func TestSignVerify(t *testing.T) {
+	key := testKey(t, 1)
+	sig := Sign(testDigest, key)
+
+	if sig.Algorithm != "ed25519" || sig.KeyID != KeyID(PublicKey(key)) || sig.Digest != testDigest {
+		t.Fatalf("unexpected signature %+v", sig)
+	}
+	if err := Verify(testDigest, sig, PublicKey(key)); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		digest string
+		sig    func() *engine.PlanSignature
+		key    ed25519.PublicKey
+		want   error
+	}{
+		{name: "unsigned", digest: testDigest, sig: func() *engine.PlanSignature { return nil }, key: PublicKey(key), want: ErrUnsigned},
+		{name: "other digest", digest: "sha256:ff", sig: func() *engine.PlanSignature { return sig }, key: PublicKey(key), want: ErrBadSignature},
+		{name: "other key", digest: testDigest, sig: func() *engine.PlanSignature { return sig }, key: PublicKey(testKey(t, 2)), want: ErrBadSignature},
+		{name: "forged value", digest: testDigest, sig: func() *engine.PlanSignature {
+			forged := *sig
+			forged.Value = base64.StdEncoding.EncodeToString(make([]byte, ed25519.SignatureSize))
+			return &forged
+		}, key: PublicKey(key), want: ErrBadSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.digest, tt.sig(), tt.key); !errors.Is(err, tt.want) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeys_Base64AndPEM(t *testing.T) {
+	key := testKey(t, 3)
+	pub := PublicKey(key)
+
+	fromSeed, err := ParsePrivateKey([]byte(base64.StdEncoding.EncodeToString(key.Seed()) + "\n"))
+	if err != nil || !fromSeed.Equal(key) {
+		t.Fatalf("ParsePrivateKey(seed) = %v, %v", fromSeed, err)
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	fromPEM, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+	if err != nil || !fromPEM.Equal(key) {
+		t.Fatalf("ParsePrivateKey(PEM) = %v, %v", fromPEM, err)
+	}
+
+	pkix, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+	}
+	pubFromPEM, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}))
+	if err != nil || !pubFromPEM.Equal(pub) {
+		t.Fatalf("ParsePublicKey(PEM) = %v, %v", pubFromPEM, err)
+	}
+
+	if _, err := ParsePublicKey([]byte(base64.StdEncoding.EncodeToString([]byte("short")))); err == nil {
+		t.Error("expected error for wrong-length public key")
+	}
+}
+
+func TestLoadKeys_FromEnv(t *testing.T) {
+	key := testKey(t, 4)
+
+	if _, ok, err := LoadSigningKey(); ok || err != nil {
+		t.Fatalf("expected no key configured, got ok=%v err=%v", ok, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "verify.pub")
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(PublicKey(key))), 0o600); err != nil {
+		t.Fatalf("writing key: %v", err)
+	}
+	t.Setenv(SigningKeyEnv, base64.StdEncoding.EncodeToString(key.Seed()))
+	t.Setenv(VerifyKeyFileEnv, path)
+
+	signing, ok, err := LoadSigningKey()
+	if err != nil || !ok || !signing.Equal(key) {
+		t.Fatalf("LoadSigningKey() = %v, %v, %v", signing, ok, err)
+	}
+	verify, ok, err := LoadVerifyKey()
+	if err != nil || !ok || !verify.Equal(PublicKey(key)) {
+		t.Fatalf("LoadVerifyKey() = %v, %v, %v", verify, ok, err)
+	}
+
+	t.Setenv(VerifyKeyEnv, "also-set")
+	if _, _, err := LoadVerifyKey(); err == nil {
+		t.Fatal("expected error when both inline and file keys are set")
+	}
+}
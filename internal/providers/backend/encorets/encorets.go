@@ -18,6 +18,7 @@ package encorets
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -25,6 +26,8 @@ import (
 
 	"gopkg.in/yaml.v3"
 
+	"stagecraft/internal/envcrypt"
+	hostexec "stagecraft/internal/exec"
 	"stagecraft/pkg/logging"
 	"stagecraft/pkg/providers/backend"
 )
@@ -40,11 +43,25 @@ type EncoreTsProvider struct{}
 // Ensure EncoreTsProvider implements BackendProvider
 var _ backend.BackendProvider = (*EncoreTsProvider)(nil)
 
+// Ensure EncoreTsProvider implements backend.MetadataProvider
+var _ backend.MetadataProvider = (*EncoreTsProvider)(nil)
+
 // ID returns the provider identifier.
 func (p *EncoreTsProvider) ID() string {
 	return "encore-ts"
 }
 
+// Metadata returns descriptive metadata about the provider.
+func (p *EncoreTsProvider) Metadata() backend.ProviderMetadata {
+	return backend.ProviderMetadata{
+		Name:         "Encore.ts",
+		Description:  "Runs Encore.ts apps with encore run and builds images with encore build docker",
+		Version:      "v1",
+		Author:       "Stagecraft",
+		ConfigSchema: Config{},
+	}
+}
+
 // Config represents the Encore.ts provider configuration.
 type Config struct {
 	Dev struct {
@@ -57,6 +74,7 @@ type Config struct {
 		EncoreSecrets    struct {
 			Types   []string `yaml:"types"`
 			FromEnv []string `yaml:"from_env"`
+			Mode    string   `yaml:"mode"` // optional; sync (default), diff or dry_run
 		} `yaml:"encore_secrets"`
 	} `yaml:"dev"`
 
@@ -84,8 +102,8 @@ func (p *EncoreTsProvider) Dev(ctx context.Context, opts backend.DevOptions) err
 	}
 
 	// Initialize logger
-	// Note: verbose flag would come from opts if available, for now default to false
-	logger := logging.NewLogger(false)
+	// Uses the process default configured from --log-level/--log-format.
+	logger := logging.Default().Named(logging.SubsystemProvider)
 	logger = logger.WithFields(
 		logging.NewField("provider", "encore-ts"),
 		logging.NewField("operation", "dev"),
@@ -126,8 +144,18 @@ func (p *EncoreTsProvider) Dev(ctx context.Context, opts backend.DevOptions) err
 					logging.NewField("error", err.Error()),
 				)
 			} else {
+				// CORE_ENV_ENCRYPTION: age/SOPS files are decrypted when a key
+				// is available; failing here beats starting without secrets.
+				data, err = envcrypt.New().Decrypt(ctx, envFilePath, data)
+				if err != nil {
+					return fmt.Errorf("loading env_file: %w", err)
+				}
+
 				// Parse dotenv format using helper
-				parseEnvFileInto(env, data)
+				fileVars := make(map[string]string)
+				parseEnvFileInto(fileVars, data)
+				hostexec.Secrets.AddVars(fileVars, hostexec.IsSecretKey)
+				maps.Copy(env, fileVars)
 			}
 		} else {
 			logger.Warn("env_file does not exist",
@@ -153,51 +181,8 @@ func (p *EncoreTsProvider) Dev(ctx context.Context, opts backend.DevOptions) err
 
 	// Sync secrets if configured
 	if len(cfg.Dev.EncoreSecrets.FromEnv) > 0 {
-		types := cfg.Dev.EncoreSecrets.Types
-		if len(types) == 0 {
-			types = []string{"dev", "preview", "local"}
-		}
-
-		for _, secretName := range cfg.Dev.EncoreSecrets.FromEnv {
-			secretValue, exists := env[secretName]
-			if !exists || secretValue == "" {
-				logger.Warn("Missing environment variable for secret sync",
-					logging.NewField("secret_name", secretName),
-				)
-				continue
-			}
-
-			// Sync to each secret type
-			for _, secretType := range types {
-				args := []string{"secret", "set", "--type", secretType, secretName}
-
-				//nolint:gosec // encore CLI args and secret names are controlled by operator config/env, not end-user input
-				cmd := exec.CommandContext(ctx, "encore", args...)
-				cmd.Dir = workDir
-				cmd.Stdin = strings.NewReader(secretValue)
-
-				// Capture output for error reporting
-				var stdout, stderr strings.Builder
-				cmd.Stdout = &stdout
-				cmd.Stderr = &stderr
-
-				if err := cmd.Run(); err != nil {
-					// Truncate stderr for error detail
-					detail := stderr.String()
-					if len(detail) > 500 {
-						detail = detail[:500] + "..."
-					}
-
-					return &ProviderError{
-						Category:  ErrSecretSyncFailed,
-						Provider:  "encore-ts",
-						Operation: "dev",
-						Message:   fmt.Sprintf("failed to set encore secret %s for type %s", secretName, secretType),
-						Detail:    detail,
-						Err:       err,
-					}
-				}
-			}
+		if err := p.syncSecrets(ctx, logger, cfg, env, workDir); err != nil {
+			return err
 		}
 	}
 
@@ -215,7 +200,7 @@ func (p *EncoreTsProvider) Dev(ctx context.Context, opts backend.DevOptions) err
 	// Log command execution details
 	logger.Info("Executing encore command",
 		logging.NewField("command", "encore"),
-		logging.NewField("args", strings.Join(args, " ")),
+		logging.NewField("args", hostexec.Secrets.Redact(strings.Join(args, " "))),
 		logging.NewField("workdir", workDir),
 	)
 
@@ -289,7 +274,7 @@ func (p *EncoreTsProvider) BuildDocker(ctx context.Context, opts backend.BuildDo
 	}
 
 	// Initialize logger
-	logger := logging.NewLogger(false)
+	logger := logging.Default().Named(logging.SubsystemProvider)
 	logger = logger.WithFields(
 		logging.NewField("provider", "encore-ts"),
 		logging.NewField("operation", "build"),
@@ -337,12 +322,12 @@ func (p *EncoreTsProvider) BuildDocker(ctx context.Context, opts backend.BuildDo
 	)
 
 	// Run encore build docker
-	args := []string{"build", "docker", imageRef}
+	args := encoreBuildArgs(imageRef, opts.Platform)
 
 	// Log command execution details
 	logger.Info("Executing encore build command",
 		logging.NewField("command", "encore"),
-		logging.NewField("args", strings.Join(args, " ")),
+		logging.NewField("args", hostexec.Secrets.Redact(strings.Join(args, " "))),
 		logging.NewField("workdir", workDir),
 	)
 
@@ -498,6 +483,18 @@ func (p *EncoreTsProvider) validateDevConfig(cfg *Config) error {
 		}
 	}
 
+	switch cfg.Dev.EncoreSecrets.Mode {
+	case "", SecretModeSync, SecretModeDiff, SecretModeDryRun:
+	default:
+		return &ProviderError{
+			Category:  ErrInvalidConfig,
+			Provider:  "encore-ts",
+			Operation: "dev",
+			Message:   fmt.Sprintf("dev.encore_secrets.mode %q is invalid", cfg.Dev.EncoreSecrets.Mode),
+			Detail:    "expected one of: sync, diff, dry_run",
+		}
+	}
+
 	// Note: env_file is required per spec, but we'll check existence when reading
 	// If it doesn't exist, we'll log a warning but continue (opts.Env may have values)
 
@@ -582,3 +579,14 @@ func parseEnvFileInto(env map[string]string, data []byte) {
 func init() {
 	backend.Register(&EncoreTsProvider{})
 }
+
+// encoreBuildArgs returns the encore CLI arguments for BuildDocker. Encore
+// takes the target platform as separate --os and --arch flags; it manages
+// its own build cache, so cache settings do not apply.
+func encoreBuildArgs(imageRef, platform string) []string {
+	args := []string{"build", "docker"}
+	if goos, arch, ok := strings.Cut(platform, "/"); ok {
+		args = append(args, "--os", goos, "--arch", arch)
+	}
+	return append(args, imageRef)
+}
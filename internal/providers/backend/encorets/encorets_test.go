@@ -1334,3 +1334,13 @@ func TestEncoreTsProvider_Plan(t *testing.T) {
 		})
 	}
 }
+
+func TestEncoreBuildArgs_Platform(t *testing.T) {
+	if got := strings.Join(encoreBuildArgs("app:v1", ""), " "); got != "build docker app:v1" {
+		t.Errorf("encoreBuildArgs() without platform = %q", got)
+	}
+	want := "build docker --os linux --arch arm64 app:v1"
+	if got := strings.Join(encoreBuildArgs("app:v1", "linux/arm64"), " "); got != want {
+		t.Errorf("encoreBuildArgs() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,339 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package encorets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"stagecraft/pkg/logging"
+)
+
+// Feature: PROVIDER_BACKEND_ENCORE
+// Spec: spec/providers/backend/encore-ts.md
+
+// Secret sync modes for dev.encore_secrets.mode.
+const (
+	// SecretModeSync sets every configured secret on each run (default).
+	SecretModeSync = "sync"
+
+	// SecretModeDiff only sets secrets that are missing or whose value changed.
+	SecretModeDiff = "diff"
+
+	// SecretModeDryRun logs what would be set without calling encore secret set.
+	SecretModeDryRun = "dry_run"
+)
+
+// Secret sync actions reported in the plan.
+const (
+	SecretActionSet       = "set"
+	SecretActionUnchanged = "unchanged"
+	SecretActionMissing   = "missing"
+)
+
+// defaultSecretTypes are the Encore secret types targeted when none are configured.
+var defaultSecretTypes = []string{"dev", "preview", "local"}
+
+// secretStateFile records fingerprints of secrets pushed in diff mode,
+// relative to the backend workdir.
+const secretStateFile = ".stagecraft/encore-secrets.json"
+
+// SecretSyncEntry describes what happens to one secret for one type.
+type SecretSyncEntry struct {
+	Name   string
+	Type   string
+	Action string
+}
+
+// secretListColumns maps `encore secret list` column headers to the
+// secret types accepted by `encore secret set --type`.
+var secretListColumns = map[string][]string{
+	"Production":  {"prod", "production"},
+	"Development": {"dev", "development"},
+	"Preview":     {"pr", "preview"},
+	"Local":       {"local"},
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// syncSecrets pushes dev.encore_secrets.from_env into Encore according to
+// the configured mode. Secret values are never logged.
+func (p *EncoreTsProvider) syncSecrets(ctx context.Context, logger logging.Logger, cfg *Config, env map[string]string, workDir string) error {
+	mode := cfg.Dev.EncoreSecrets.Mode
+	if mode == "" {
+		mode = SecretModeSync
+	}
+
+	var existing map[string]map[string]bool
+	var fingerprints map[string]string
+	if mode != SecretModeSync {
+		var err error
+		existing, err = listEncoreSecrets(ctx, workDir)
+		if err != nil {
+			// Without the list every secret is treated as changed, which
+			// is what sync mode would have done anyway.
+			logger.Warn("Could not list Encore secrets; treating all as changed",
+				logging.NewField("error", err.Error()),
+			)
+		}
+		fingerprints = readSecretFingerprints(workDir)
+	}
+
+	plan := planSecretSync(cfg, env, existing, fingerprints)
+
+	for _, entry := range plan {
+		fields := []logging.Field{
+			logging.NewField("secret_name", entry.Name),
+			logging.NewField("action", entry.Action),
+		}
+		if entry.Type != "" {
+			fields = append(fields, logging.NewField("secret_type", entry.Type))
+		}
+
+		switch entry.Action {
+		case SecretActionMissing:
+			logger.Warn("Missing environment variable for secret sync", fields...)
+		case SecretActionUnchanged:
+			logger.Debug("Encore secret unchanged", fields...)
+		default:
+			if mode == SecretModeDryRun {
+				logger.Info("Would set Encore secret", fields...)
+			}
+		}
+	}
+
+	if mode == SecretModeDryRun {
+		return nil
+	}
+
+	changed := false
+	for _, entry := range plan {
+		if entry.Action != SecretActionSet {
+			continue
+		}
+		value := env[entry.Name]
+		if err := setEncoreSecret(ctx, workDir, entry.Name, entry.Type, value); err != nil {
+			if changed && mode == SecretModeDiff {
+				writeSecretFingerprints(logger, workDir, fingerprints)
+			}
+			return err
+		}
+		if mode == SecretModeDiff {
+			fingerprints[fingerprintKey(entry.Name, entry.Type)] = fingerprint(entry.Name, value)
+			changed = true
+		}
+	}
+
+	if changed {
+		writeSecretFingerprints(logger, workDir, fingerprints)
+	}
+
+	return nil
+}
+
+// planSecretSync decides, for every configured secret and type, whether it
+// needs to be set. A pair is unchanged only when Encore reports it as set
+// and the recorded fingerprint matches the current value. With a nil
+// existing map every available secret is set.
+func planSecretSync(cfg *Config, env map[string]string, existing map[string]map[string]bool, fingerprints map[string]string) []SecretSyncEntry {
+	types := cfg.Dev.EncoreSecrets.Types
+	if len(types) == 0 {
+		types = defaultSecretTypes
+	}
+
+	var plan []SecretSyncEntry
+	for _, name := range cfg.Dev.EncoreSecrets.FromEnv {
+		value, ok := env[name]
+		if !ok || value == "" {
+			plan = append(plan, SecretSyncEntry{Name: name, Action: SecretActionMissing})
+			continue
+		}
+
+		for _, secretType := range types {
+			action := SecretActionSet
+			if existing != nil && existing[name][secretType] &&
+				fingerprints[fingerprintKey(name, secretType)] == fingerprint(name, value) {
+				action = SecretActionUnchanged
+			}
+			plan = append(plan, SecretSyncEntry{Name: name, Type: secretType, Action: action})
+		}
+	}
+
+	return plan
+}
+
+// setEncoreSecret runs `encore secret set`, piping the value via stdin.
+func setEncoreSecret(ctx context.Context, workDir, name, secretType, value string) error {
+	args := []string{"secret", "set", "--type", secretType, name}
+
+	//nolint:gosec // encore CLI args and secret names are controlled by operator config/env, not end-user input
+	cmd := exec.CommandContext(ctx, "encore", args...)
+	cmd.Dir = workDir
+	cmd.Stdin = strings.NewReader(value)
+
+	// Capture output for error reporting
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Truncate stderr for error detail
+		detail := stderr.String()
+		if len(detail) > 500 {
+			detail = detail[:500] + "..."
+		}
+
+		return &ProviderError{
+			Category:  ErrSecretSyncFailed,
+			Provider:  "encore-ts",
+			Operation: "dev",
+			Message:   fmt.Sprintf("failed to set encore secret %s for type %s", name, secretType),
+			Detail:    detail,
+			Err:       err,
+		}
+	}
+
+	return nil
+}
+
+// listEncoreSecrets runs `encore secret list` and returns, per secret name,
+// the secret types that already have a value.
+func listEncoreSecrets(ctx context.Context, workDir string) (map[string]map[string]bool, error) {
+	//nolint:gosec // fixed encore CLI args
+	cmd := exec.CommandContext(ctx, "encore", "secret", "list")
+	cmd.Dir = workDir
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		detail := strings.TrimSpace(stderr.String())
+		if detail != "" {
+			return nil, fmt.Errorf("encore secret list: %w: %s", err, detail)
+		}
+		return nil, fmt.Errorf("encore secret list: %w", err)
+	}
+
+	return parseSecretList(string(out)), nil
+}
+
+// parseSecretList parses the table printed by `encore secret list`:
+//
+//	Secret Key   Production   Development   Local   Preview   Specific Envs
+//	StripeKey    ✓            ✓             ✗       ✓
+//
+// Columns are matched by header name, so their order does not matter.
+func parseSecretList(out string) map[string]map[string]bool {
+	result := make(map[string]map[string]bool)
+
+	var columns []string
+	for _, line := range strings.Split(ansiEscape.ReplaceAllString(out, ""), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if columns == nil {
+			if len(fields) >= 2 && fields[0] == "Secret" && fields[1] == "Key" {
+				columns = fields[2:]
+			}
+			continue
+		}
+
+		name := fields[0]
+		for i, column := range columns {
+			types, known := secretListColumns[column]
+			if !known || i+1 >= len(fields) || !isSetMark(fields[i+1]) {
+				continue
+			}
+			if result[name] == nil {
+				result[name] = make(map[string]bool)
+			}
+			for _, t := range types {
+				result[name][t] = true
+			}
+		}
+	}
+
+	return result
+}
+
+func isSetMark(s string) bool {
+	switch s {
+	case "✓", "✔", "yes", "true":
+		return true
+	default:
+		return false
+	}
+}
+
+// fingerprint hashes a secret value together with its name so equal values
+// under different names do not share a fingerprint.
+func fingerprint(name, value string) string {
+	sum := sha256.Sum256([]byte(name + "\x00" + value))
+	return hex.EncodeToString(sum[:])
+}
+
+func fingerprintKey(name, secretType string) string {
+	return secretType + "/" + name
+}
+
+// readSecretFingerprints loads the fingerprint file; a missing or
+// unreadable file yields an empty map so every secret is re-pushed.
+func readSecretFingerprints(workDir string) map[string]string {
+	fingerprints := make(map[string]string)
+
+	//nolint:gosec // G304: path is derived from the configured backend workdir
+	data, err := os.ReadFile(filepath.Join(workDir, secretStateFile))
+	if err != nil {
+		return fingerprints
+	}
+	_ = json.Unmarshal(data, &fingerprints)
+	if fingerprints == nil {
+		fingerprints = make(map[string]string)
+	}
+	return fingerprints
+}
+
+// writeSecretFingerprints persists fingerprints. Failures only cost a
+// re-push on the next run, so they are logged, not returned.
+func writeSecretFingerprints(logger logging.Logger, workDir string, fingerprints map[string]string) {
+	path := filepath.Join(workDir, secretStateFile)
+
+	// encoding/json sorts map keys, so the file is deterministic.
+	data, err := json.MarshalIndent(fingerprints, "", "  ")
+	if err == nil {
+		// #nosec G301 -- state directory inside the backend workdir
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(path, append(data, '\n'), 0o600)
+	}
+	if err != nil {
+		logger.Warn("Failed to record Encore secret fingerprints",
+			logging.NewField("path", path),
+			logging.NewField("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package encorets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"stagecraft/pkg/logging"
+	"stagecraft/pkg/providers/backend"
+)
+
+// Feature: PROVIDER_BACKEND_ENCORE
+// Spec: spec/providers/backend/encore-ts.md
+
+const secretListOutput = "Secret Key   Production   Development   Local   Preview   Specific Envs\n" +
+	"API_KEY      ✗            ✓             ✓       ✗\n" +
+	"OTHER        \x1b[32m✓\x1b[0m            ✗             ✗       ✗\n"
+
+func TestParseSecretList(t *testing.T) {
+	got := parseSecretList(secretListOutput)
+
+	if !got["API_KEY"]["dev"] || !got["API_KEY"]["local"] {
+		t.Errorf("API_KEY should be set for dev and local, got %v", got["API_KEY"])
+	}
+	if got["API_KEY"]["preview"] || got["API_KEY"]["prod"] {
+		t.Errorf("API_KEY should not be set for preview or prod, got %v", got["API_KEY"])
+	}
+	if !got["OTHER"]["prod"] || !got["OTHER"]["production"] {
+		t.Errorf("OTHER should be set for prod, got %v", got["OTHER"])
+	}
+	if len(parseSecretList("no secrets found\n")) != 0 {
+		t.Errorf("expected empty result without a header")
+	}
+}
+
+func TestPlanSecretSync(t *testing.T) {
+	cfg := &Config{}
+	cfg.Dev.EncoreSecrets.Types = []string{"dev", "local"}
+	cfg.Dev.EncoreSecrets.FromEnv = []string{"API_KEY", "MISSING"}
+
+	env := map[string]string{"API_KEY": "v2"}
+	existing := parseSecretList(secretListOutput)
+	fingerprints := map[string]string{
+		fingerprintKey("API_KEY", "dev"):   fingerprint("API_KEY", "v2"),
+		fingerprintKey("API_KEY", "local"): fingerprint("API_KEY", "v1"),
+	}
+
+	got := planSecretSync(cfg, env, existing, fingerprints)
+	want := []SecretSyncEntry{
+		{Name: "API_KEY", Type: "dev", Action: SecretActionUnchanged},
+		{Name: "API_KEY", Type: "local", Action: SecretActionSet},
+		{Name: "MISSING", Action: SecretActionMissing},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("planSecretSync() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("planSecretSync()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Without a secret list everything available is set.
+	for _, entry := range planSecretSync(cfg, env, nil, fingerprints) {
+		if entry.Name == "API_KEY" && entry.Action != SecretActionSet {
+			t.Errorf("expected %s/%s to be set without a secret list", entry.Type, entry.Name)
+		}
+	}
+}
+
+func TestEncoreTsProvider_ValidateDevConfig_InvalidSecretMode(t *testing.T) {
+	cfg := &Config{}
+	cfg.Dev.Listen = "0.0.0.0:4000"
+	cfg.Dev.EncoreSecrets.Mode = "sometimes"
+
+	err := (&EncoreTsProvider{}).validateDevConfig(cfg)
+	pe := GetProviderError(err)
+	if pe == nil || pe.Category != ErrInvalidConfig {
+		t.Fatalf("expected INVALID_CONFIG error, got %v", err)
+	}
+}
+
+// writeSecretMockEncore installs an encore script that prints
+// secretListOutput for `secret list` and appends each `secret set` call to
+// calls.log.
+func writeSecretMockEncore(t *testing.T, dir string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("mock encore script requires a POSIX shell")
+	}
+
+	script := `#!/bin/sh
+if [ "$1" = "secret" ] && [ "$2" = "list" ]; then
+  printf '%s' "$SECRET_LIST_OUTPUT"
+  exit 0
+fi
+if [ "$1" = "secret" ] && [ "$2" = "set" ]; then
+  echo "$4 $5" >> "` + filepath.Join(dir, "calls.log") + `"
+  exit 0
+fi
+exit 0
+`
+	path := filepath.Join(dir, "encore")
+	//nolint:gosec // G306: 0755 is required for executable test scripts
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatalf("writing mock encore: %v", err)
+	}
+	t.Setenv("PATH", dir+string(filepath.ListSeparator)+os.Getenv("PATH"))
+	t.Setenv("SECRET_LIST_OUTPUT", secretListOutput)
+	return filepath.Join(dir, "calls.log")
+}
+
+func readCalls(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("reading calls: %v", err)
+	}
+	return strings.Fields(strings.ReplaceAll(string(data), " ", "/"))
+}
+
+func TestSyncSecrets_DryRunSetsNothing(t *testing.T) {
+	dir := t.TempDir()
+	calls := writeSecretMockEncore(t, dir)
+
+	cfg := &Config{}
+	cfg.Dev.EncoreSecrets.Types = []string{"dev"}
+	cfg.Dev.EncoreSecrets.FromEnv = []string{"API_KEY"}
+	cfg.Dev.EncoreSecrets.Mode = SecretModeDryRun
+
+	p := &EncoreTsProvider{}
+	if err := p.syncSecrets(context.Background(), logging.NewLogger(false), cfg, map[string]string{"API_KEY": "v1"}, dir); err != nil {
+		t.Fatalf("syncSecrets() error = %v", err)
+	}
+
+	if got := readCalls(t, calls); len(got) != 0 {
+		t.Errorf("dry run should not set secrets, got calls %v", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, secretStateFile)); !os.IsNotExist(err) {
+		t.Errorf("dry run should not write fingerprints, stat err = %v", err)
+	}
+}
+
+func TestSyncSecrets_DiffSkipsUnchangedOnRepeatedRuns(t *testing.T) {
+	dir := t.TempDir()
+	calls := writeSecretMockEncore(t, dir)
+
+	cfg := &Config{}
+	cfg.Dev.EncoreSecrets.Types = []string{"dev", "preview"}
+	cfg.Dev.EncoreSecrets.FromEnv = []string{"API_KEY"}
+	cfg.Dev.EncoreSecrets.Mode = SecretModeDiff
+	env := map[string]string{"API_KEY": "v1"}
+
+	p := &EncoreTsProvider{}
+	logger := logging.NewLogger(false)
+
+	// First run: no fingerprints yet, so both types are pushed.
+	if err := p.syncSecrets(context.Background(), logger, cfg, env, dir); err != nil {
+		t.Fatalf("first syncSecrets() error = %v", err)
+	}
+	if got := readCalls(t, calls); strings.Join(got, ",") != "dev/API_KEY,preview/API_KEY" {
+		t.Fatalf("first run calls = %v", got)
+	}
+
+	// Second run: dev is listed as set with a matching fingerprint; preview
+	// is not listed by encore, so it is pushed again.
+	if err := p.syncSecrets(context.Background(), logger, cfg, env, dir); err != nil {
+		t.Fatalf("second syncSecrets() error = %v", err)
+	}
+	if got := readCalls(t, calls); strings.Join(got, ",") != "dev/API_KEY,preview/API_KEY,preview/API_KEY" {
+		t.Fatalf("second run calls = %v", got)
+	}
+
+	// Changing the value pushes dev again.
+	env["API_KEY"] = "v2"
+	if err := p.syncSecrets(context.Background(), logger, cfg, env, dir); err != nil {
+		t.Fatalf("third syncSecrets() error = %v", err)
+	}
+	got := readCalls(t, calls)
+	if len(got) != 5 || got[3] != "dev/API_KEY" {
+		t.Fatalf("third run calls = %v", got)
+	}
+}
+
+func TestEncoreTsProvider_Dev_SecretDryRunIgnoresSetFailures(t *testing.T) {
+	tmpDir := t.TempDir()
+	mockScript := createMockEncoreScript(t, tmpDir)
+	cleanup := setupMockEncorePath(t, mockScript)
+	defer cleanup()
+
+	// secret_failure makes every encore call fail, including secret list.
+	setEnv(t, "ENCORE_MOCK_MODE", "secret_failure")
+	defer unsetEnv(t, "ENCORE_MOCK_MODE")
+
+	p := &EncoreTsProvider{}
+	opts := backend.DevOptions{
+		Config: map[string]any{
+			"dev": map[string]any{
+				"listen": "0.0.0.0:4000",
+				"encore_secrets": map[string]any{
+					"types":    []string{"dev"},
+					"from_env": []string{"TEST_SECRET"},
+					"mode":     "dry_run",
+				},
+			},
+		},
+		WorkDir: tmpDir,
+		Env:     map[string]string{"TEST_SECRET": "secret-value-123"},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := p.Dev(ctx, opts)
+	if pe := GetProviderError(err); pe != nil && pe.Category == ErrSecretSyncFailed {
+		t.Fatalf("dry run should not set secrets, got %v", err)
+	}
+}
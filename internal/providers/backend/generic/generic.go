@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 
 	"gopkg.in/yaml.v3"
 
@@ -37,11 +38,25 @@ type GenericProvider struct{}
 // Ensure GenericProvider implements BackendProvider
 var _ backend.BackendProvider = (*GenericProvider)(nil)
 
+// Ensure GenericProvider implements backend.MetadataProvider
+var _ backend.MetadataProvider = (*GenericProvider)(nil)
+
 // ID returns the provider identifier.
 func (p *GenericProvider) ID() string {
 	return "generic"
 }
 
+// Metadata returns descriptive metadata about the provider.
+func (p *GenericProvider) Metadata() backend.ProviderMetadata {
+	return backend.ProviderMetadata{
+		Name:         "Generic",
+		Description:  "Runs arbitrary dev commands and builds images from a Dockerfile",
+		Version:      "v1",
+		Author:       "Stagecraft",
+		ConfigSchema: Config{},
+	}
+}
+
 // Config represents the generic provider configuration.
 type Config struct {
 	Dev struct {
@@ -122,12 +137,7 @@ func (p *GenericProvider) BuildDocker(ctx context.Context, opts backend.BuildDoc
 		buildContext = "."
 	}
 
-	args := []string{
-		"build",
-		"-t", opts.ImageTag,
-		"-f", dockerfile,
-		buildContext,
-	}
+	args := dockerBuildArgs(opts, dockerfile, buildContext)
 
 	//nolint:gosec // docker args come from trusted config (image tag, dockerfile, context)
 	cmd := exec.CommandContext(ctx, "docker", args...)
@@ -141,6 +151,53 @@ func (p *GenericProvider) BuildDocker(ctx context.Context, opts backend.BuildDoc
 	return opts.ImageTag, nil
 }
 
+// dockerBuildArgs returns the docker CLI arguments for BuildDocker.
+// Exporting a cache needs BuildKit's buildx frontend; --load keeps the
+// image in the local store like a plain docker build. Build args are
+// sorted by name so the command line is deterministic.
+func dockerBuildArgs(opts backend.BuildDockerOptions, dockerfile, buildContext string) []string {
+	args := []string{"build"}
+	if len(opts.CacheTo) > 0 {
+		args = []string{"buildx", "build", "--load"}
+	}
+
+	args = append(args, "-t", opts.ImageTag, "-f", dockerfile)
+	if opts.NoCache {
+		args = append(args, "--no-cache")
+	}
+	if opts.Platform != "" {
+		args = append(args, "--platform", opts.Platform)
+	}
+	for _, spec := range opts.CacheFrom {
+		args = append(args, "--cache-from", spec)
+	}
+	for _, spec := range opts.CacheTo {
+		args = append(args, "--cache-to", spec)
+	}
+	if opts.Target != "" {
+		args = append(args, "--target", opts.Target)
+	}
+	names := make([]string, 0, len(opts.BuildArgs))
+	for name := range opts.BuildArgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--build-arg", name+"="+opts.BuildArgs[name])
+	}
+	for _, secret := range opts.Secrets {
+		spec := "id=" + secret.ID
+		if secret.Src != "" {
+			spec += ",src=" + secret.Src
+		} else {
+			spec += ",env=" + secret.Env
+		}
+		args = append(args, "--secret", spec)
+	}
+
+	return append(args, buildContext)
+}
+
 // Plan generates a deterministic plan of what BuildDocker would do.
 func (p *GenericProvider) Plan(ctx context.Context, opts backend.PlanOptions) (backend.ProviderPlan, error) {
 	cfg, err := p.parseConfig(opts.Config)
@@ -315,6 +315,61 @@ func TestGenericProvider_BuildDocker_ContextResolution(t *testing.T) {
 	}
 }
 
+func TestDockerBuildArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts backend.BuildDockerOptions
+		want string
+	}{
+		{
+			name: "plain build",
+			opts: backend.BuildDockerOptions{ImageTag: "app:v1"},
+			want: "build -t app:v1 -f Dockerfile .",
+		},
+		{
+			name: "no cache and platform",
+			opts: backend.BuildDockerOptions{ImageTag: "app:v1", NoCache: true, Platform: "linux/arm64"},
+			want: "build -t app:v1 -f Dockerfile --no-cache --platform linux/arm64 .",
+		},
+		{
+			name: "cache import only stays on docker build",
+			opts: backend.BuildDockerOptions{ImageTag: "app:v1", CacheFrom: []string{"type=registry,ref=r/app:cache"}},
+			want: "build -t app:v1 -f Dockerfile --cache-from type=registry,ref=r/app:cache .",
+		},
+		{
+			name: "cache export uses buildx",
+			opts: backend.BuildDockerOptions{
+				ImageTag:  "app:v1",
+				CacheFrom: []string{"type=registry,ref=r/app:cache"},
+				CacheTo:   []string{"type=registry,ref=r/app:cache,mode=max"},
+			},
+			want: "buildx build --load -t app:v1 -f Dockerfile --cache-from type=registry,ref=r/app:cache --cache-to type=registry,ref=r/app:cache,mode=max .",
+		},
+		{
+			name: "target, build args, and secrets",
+			opts: backend.BuildDockerOptions{
+				ImageTag:  "app:v1",
+				Target:    "runtime",
+				BuildArgs: map[string]string{"VERSION": "1.2", "NODE_ENV": "production"},
+				Secrets: []backend.BuildSecret{
+					{ID: "npmrc", Src: ".npmrc"},
+					{ID: "gh_token", Env: "GITHUB_TOKEN"},
+				},
+			},
+			want: "build -t app:v1 -f Dockerfile --target runtime --build-arg NODE_ENV=production --build-arg VERSION=1.2 --secret id=npmrc,src=.npmrc --secret id=gh_token,env=GITHUB_TOKEN .",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(dockerBuildArgs(tt.opts, "Dockerfile", "."), " ")
+			if got != tt.want {
+				t.Errorf("dockerBuildArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGenericProvider_Plan(t *testing.T) {
 	p := &GenericProvider{}
 
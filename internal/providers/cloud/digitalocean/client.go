@@ -34,6 +34,17 @@ type APIClient interface {
 	// DeleteDroplet deletes a droplet by ID.
 	DeleteDroplet(ctx context.Context, id int) error
 
+	// TagDroplets adds a tag to droplets by ID, creating the tag if needed.
+	TagDroplets(ctx context.Context, tag string, ids []int) error
+
+	// RenameDroplet renames a droplet by ID.
+	RenameDroplet(ctx context.Context, id int, name string) error
+
+	// ResizeDroplet powers a droplet off, resizes its CPU and memory to size
+	// and powers it back on. The disk is not resized, so the resize can be
+	// reverted.
+	ResizeDroplet(ctx context.Context, id int, size string) error
+
 	// ListSSHKeys lists all SSH keys in the account.
 	ListSSHKeys(ctx context.Context) ([]SSHKey, error)
 
@@ -42,6 +53,37 @@ type APIClient interface {
 
 	// WaitForDroplet waits for a droplet to reach the specified status.
 	WaitForDroplet(ctx context.Context, id int, status string) error
+
+	// ListFirewalls lists all cloud firewalls in the account.
+	ListFirewalls(ctx context.Context) ([]Firewall, error)
+
+	// CreateFirewall creates a new cloud firewall.
+	CreateFirewall(ctx context.Context, req FirewallRequest) (*Firewall, error)
+
+	// UpdateFirewall replaces the rules and tags of an existing cloud firewall.
+	UpdateFirewall(ctx context.Context, id string, req FirewallRequest) (*Firewall, error)
+
+	// ListReservedIPs lists all reserved IPs in the account.
+	ListReservedIPs(ctx context.Context) ([]ReservedIP, error)
+
+	// CreateReservedIP reserves a new IP in the given region.
+	CreateReservedIP(ctx context.Context, region string) (*ReservedIP, error)
+
+	// AssignReservedIP attaches a reserved IP to a droplet, detaching it from
+	// any droplet it is currently attached to.
+	AssignReservedIP(ctx context.Context, ip string, dropletID int) error
+
+	// ListVolumes lists all block storage volumes in the account.
+	ListVolumes(ctx context.Context) ([]Volume, error)
+
+	// CreateVolume creates a new block storage volume.
+	CreateVolume(ctx context.Context, req CreateVolumeRequest) (*Volume, error)
+
+	// AttachVolume attaches a volume to a droplet.
+	AttachVolume(ctx context.Context, volumeID string, dropletID int) error
+
+	// ListSizes lists the droplet sizes available to the account.
+	ListSizes(ctx context.Context) ([]Size, error)
 }
 
 // DropletFilter filters droplets for listing.
@@ -60,6 +102,7 @@ type Droplet struct {
 	Region   string   `json:"region"`
 	Size     string   `json:"size"`
 	Status   string   `json:"status"`
+	Tags     []string `json:"tags"`
 	Networks Networks `json:"networks"`
 }
 
@@ -82,10 +125,71 @@ type CreateDropletRequest struct {
 	Image   string // e.g., "ubuntu-22-04-x64"
 	SSHKeys []int  // SSH key IDs
 	Tags    []string
+
+	UserData string // Optional: rendered cloud-init user data
 }
 
 // SSHKey represents a DigitalOcean SSH key.
 type SSHKey struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key"`
+}
+
+// Firewall represents a DigitalOcean cloud firewall.
+type Firewall struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Tags          []string       `json:"tags"`
+	InboundRules  []FirewallRule `json:"inbound_rules"`
+	OutboundRules []FirewallRule `json:"outbound_rules"`
+}
+
+// FirewallRule represents a cloud firewall rule. Addresses are the sources
+// of inbound rules and the destinations of outbound rules.
+type FirewallRule struct {
+	Protocol  string   `json:"protocol"`
+	Ports     string   `json:"ports"` // "22", "8000-9000", or "0" for all ports; empty for icmp
+	Addresses []string `json:"addresses"`
+}
+
+// FirewallRequest represents a cloud firewall create or update request.
+type FirewallRequest struct {
+	Name          string
+	Tags          []string
+	InboundRules  []FirewallRule
+	OutboundRules []FirewallRule
+}
+
+// ReservedIP represents a DigitalOcean reserved IP.
+type ReservedIP struct {
+	IP        string `json:"ip"`
+	Region    string `json:"region"`
+	DropletID int    `json:"droplet_id"` // 0 when unassigned
+}
+
+// Volume represents a DigitalOcean block storage volume.
+type Volume struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Region        string `json:"region"`
+	SizeGigaBytes int    `json:"size_gigabytes"`
+	DropletIDs    []int  `json:"droplet_ids"`
+}
+
+// CreateVolumeRequest represents a volume creation request.
+type CreateVolumeRequest struct {
+	Name           string
+	Region         string
+	SizeGigaBytes  int
+	FilesystemType string // e.g., "ext4"; DigitalOcean formats the volume on creation
+	Tags           []string
+}
+
+// Size represents a DigitalOcean droplet size and its price.
+type Size struct {
+	Slug         string   `json:"slug"`
+	PriceMonthly float64  `json:"price_monthly"`
+	Regions      []string `json:"regions"`
+	Available    bool     `json:"available"`
 }
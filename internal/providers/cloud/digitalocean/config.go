@@ -18,6 +18,8 @@ package digitalocean
 
 import (
 	"fmt"
+	"regexp"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -31,6 +33,57 @@ type Config struct {
 	Regions       []string                         `yaml:"regions"`        // Optional: allowed regions
 	Sizes         []string                         `yaml:"sizes"`          // Optional: allowed sizes
 	Hosts         map[string]map[string]HostConfig `yaml:"hosts"`          // Required: host definitions per environment
+	Firewall      *FirewallConfig                  `yaml:"firewall"`       // Optional: cloud firewall settings (enabled by default)
+	UserData      *UserDataConfig                  `yaml:"user_data"`      // Optional: per-role cloud-init templates
+	API           *APIConfig                       `yaml:"api"`            // Optional: API retry limits
+}
+
+// APIConfig tunes how API calls are retried on rate limits (429) and
+// transient server errors (5xx).
+type APIConfig struct {
+	MaxRetries *int          `yaml:"max_retries"` // Optional: retries after the first attempt (default 4, 0 disables)
+	MaxBackoff time.Duration `yaml:"max_backoff"` // Optional: cap on a single wait, including Retry-After (default 30s)
+}
+
+// retryPolicy returns DefaultRetryPolicy with the configured limits applied.
+func (c *Config) retryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy
+	if c.API == nil {
+		return policy
+	}
+	if c.API.MaxRetries != nil {
+		policy.MaxAttempts = *c.API.MaxRetries + 1
+	}
+	if c.API.MaxBackoff > 0 {
+		policy.MaxDelay = c.API.MaxBackoff
+		policy.BaseDelay = min(policy.BaseDelay, c.API.MaxBackoff)
+	}
+	return policy
+}
+
+// UserDataConfig selects the cloud-init template rendered for new droplets.
+type UserDataConfig struct {
+	TailnetAuthKeyEnv string            `yaml:"tailnet_auth_key_env"` // Optional: env var holding the tailnet auth key
+	Roles             map[string]string `yaml:"roles"`                // Required: role -> template path, or "builtin"
+}
+
+// FirewallConfig represents the cloud firewall attached to each environment's droplets.
+type FirewallConfig struct {
+	Enabled    *bool                `yaml:"enabled"`     // Optional: defaults to true
+	SSHSources []string             `yaml:"ssh_sources"` // Optional: CIDRs allowed to reach port 22 (defaults to anywhere)
+	Inbound    []FirewallRuleConfig `yaml:"inbound"`     // Optional: extra inbound rules on top of the baseline
+}
+
+// FirewallRuleConfig represents an extra inbound firewall rule.
+type FirewallRuleConfig struct {
+	Protocol string   `yaml:"protocol"` // Required: tcp, udp or icmp
+	Ports    string   `yaml:"ports"`    // Required for tcp/udp: port, range or "all"
+	Sources  []string `yaml:"sources"`  // Optional: CIDRs (defaults to anywhere)
+}
+
+// firewallEnabled reports whether the cloud firewall should be managed.
+func (c *Config) firewallEnabled() bool {
+	return c.Firewall == nil || c.Firewall.Enabled == nil || *c.Firewall.Enabled
 }
 
 // HostConfig represents configuration for a single host.
@@ -38,6 +91,18 @@ type HostConfig struct {
 	Role   string `yaml:"role"`   // Required: role (e.g., "gateway", "app", "db")
 	Size   string `yaml:"size"`   // Optional: size (defaults to default_size)
 	Region string `yaml:"region"` // Optional: region (defaults to default_region)
+
+	ReservedIP bool `yaml:"reserved_ip"` // Optional: attach a reserved IP (typically the gateway)
+
+	Volumes []VolumeConfig `yaml:"volumes"` // Optional: block storage volumes
+}
+
+// VolumeConfig represents a block storage volume attached to a host.
+type VolumeConfig struct {
+	Name       string `yaml:"name"`        // Required: lowercase letters, digits and hyphens, unique per host
+	SizeGB     int    `yaml:"size_gb"`     // Required: size in gigabytes
+	Filesystem string `yaml:"filesystem"`  // Optional: ext4 (default) or xfs
+	MountPoint string `yaml:"mount_point"` // Required: absolute mount path
 }
 
 // parseConfig unmarshals provider config from generic interface.
@@ -70,8 +135,90 @@ func parseConfig(cfg any) (*Config, error) {
 			if hostConfig.Role == "" {
 				return nil, fmt.Errorf("%w: host %s.%s: role is required", ErrConfigInvalid, env, hostname)
 			}
+			if err := validateVolumes(hostConfig.Volumes); err != nil {
+				return nil, fmt.Errorf("%w: host %s.%s: %v", ErrConfigInvalid, env, hostname, err)
+			}
+		}
+	}
+
+	if config.UserData != nil {
+		if len(config.UserData.Roles) == 0 {
+			return nil, fmt.Errorf("%w: user_data.roles is required", ErrConfigInvalid)
+		}
+		for role, ref := range config.UserData.Roles {
+			if ref == "" {
+				return nil, fmt.Errorf("%w: user_data.roles.%s: template is required", ErrConfigInvalid, role)
+			}
+		}
+	}
+
+	if config.API != nil {
+		if config.API.MaxRetries != nil && *config.API.MaxRetries < 0 {
+			return nil, fmt.Errorf("%w: api.max_retries must not be negative", ErrConfigInvalid)
+		}
+		if config.API.MaxBackoff < 0 {
+			return nil, fmt.Errorf("%w: api.max_backoff must not be negative", ErrConfigInvalid)
+		}
+	}
+
+	if config.Firewall != nil {
+		for i, rule := range config.Firewall.Inbound {
+			switch rule.Protocol {
+			case "tcp", "udp":
+				if rule.Ports == "" {
+					return nil, fmt.Errorf("%w: firewall.inbound[%d]: ports is required for %s", ErrConfigInvalid, i, rule.Protocol)
+				}
+			case "icmp":
+			default:
+				return nil, fmt.Errorf("%w: firewall.inbound[%d]: protocol must be tcp, udp or icmp, got %q", ErrConfigInvalid, i, rule.Protocol)
+			}
 		}
 	}
 
 	return &config, nil
 }
+
+var (
+	// volumeNamePattern matches names valid as part of a DigitalOcean volume name.
+	volumeNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+	// mountPointPattern matches absolute paths safe to embed in shell commands and /etc/fstab.
+	mountPointPattern = regexp.MustCompile(`^/[A-Za-z0-9/_.-]+$`)
+)
+
+// validateVolumes checks a host's volume declarations and fills in the
+// default filesystem.
+func validateVolumes(volumes []VolumeConfig) error {
+	names := make(map[string]bool, len(volumes))
+	mounts := make(map[string]bool, len(volumes))
+	for i := range volumes {
+		v := &volumes[i]
+		if !volumeNamePattern.MatchString(v.Name) {
+			return fmt.Errorf("volumes[%d]: name %q must be lowercase letters, digits and hyphens", i, v.Name)
+		}
+		if names[v.Name] {
+			return fmt.Errorf("volumes[%d]: duplicate volume name %q", i, v.Name)
+		}
+		names[v.Name] = true
+
+		if v.SizeGB <= 0 {
+			return fmt.Errorf("volume %s: size_gb must be positive", v.Name)
+		}
+
+		if v.Filesystem == "" {
+			v.Filesystem = "ext4"
+		}
+		if v.Filesystem != "ext4" && v.Filesystem != "xfs" {
+			return fmt.Errorf("volume %s: filesystem must be ext4 or xfs, got %q", v.Name, v.Filesystem)
+		}
+
+		if !mountPointPattern.MatchString(v.MountPoint) || v.MountPoint == "/" {
+			return fmt.Errorf("volume %s: mount_point must be an absolute path, got %q", v.Name, v.MountPoint)
+		}
+		if mounts[v.MountPoint] {
+			return fmt.Errorf("volume %s: duplicate mount_point %q", v.Name, v.MountPoint)
+		}
+		mounts[v.MountPoint] = true
+	}
+	return nil
+}
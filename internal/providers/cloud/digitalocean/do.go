@@ -22,8 +22,10 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
+	"stagecraft/internal/cache"
 	"stagecraft/pkg/providers/cloud"
 )
 
@@ -31,19 +33,43 @@ import (
 //
 //nolint:revive // DigitalOceanProvider is intentionally named for clarity in provider package
 type DigitalOceanProvider struct {
+	// client is the injected API client; nil connects an HTTPAPIClient per
+	// call, see connect.
 	client APIClient
+
+	// apiBaseURL is the API endpoint of connected clients; empty uses
+	// DefaultAPIBaseURL. Replaced in tests.
+	apiBaseURL string
+
+	// accountClient returns the client VerifyCredentials uses; nil uses
+	// NewHTTPAPIClient with the config's retry policy.
+	accountClient func(token string) AccountClient
+
+	// cache keeps price lists fetched from the sizes API; nil caches nothing.
+	cache *cache.Cache
 }
 
 // Ensure DigitalOceanProvider implements CloudProvider
 var _ cloud.CloudProvider = (*DigitalOceanProvider)(nil)
 
-// NewDigitalOceanProvider creates a new DigitalOcean provider with default API client.
-// For production use, this will create a real DigitalOcean API client.
+// Ensure DigitalOceanProvider implements DesiredHostsProvider
+var _ cloud.DesiredHostsProvider = (*DigitalOceanProvider)(nil)
+
+// Ensure DigitalOceanProvider implements cloud.MetadataProvider
+var _ cloud.MetadataProvider = (*DigitalOceanProvider)(nil)
+
+// Ensure DigitalOceanProvider implements cloud.ConfigValidator
+var _ cloud.ConfigValidator = (*DigitalOceanProvider)(nil)
+
+// Ensure DigitalOceanProvider implements cloud.CredentialVerifier
+var _ cloud.CredentialVerifier = (*DigitalOceanProvider)(nil)
+
+// NewDigitalOceanProvider creates a new DigitalOcean provider that calls
+// the DigitalOcean API with the token from the config's token_env.
 // For testing, use NewDigitalOceanProviderWithClient.
 func NewDigitalOceanProvider() *DigitalOceanProvider {
-	// TODO: Create real DO client in Slice 2
 	return &DigitalOceanProvider{
-		client: nil, // Will be implemented in Slice 2
+		cache: cache.Default(),
 	}
 }
 
@@ -60,6 +86,72 @@ func (p *DigitalOceanProvider) ID() string {
 	return "digitalocean"
 }
 
+// Metadata returns descriptive metadata about the provider.
+func (p *DigitalOceanProvider) Metadata() cloud.ProviderMetadata {
+	return cloud.ProviderMetadata{
+		Name:         "DigitalOcean",
+		Description:  "Provisions DigitalOcean droplets, firewalls, volumes and reserved IPs",
+		Version:      "v1",
+		Author:       "Stagecraft",
+		ConfigSchema: Config{},
+	}
+}
+
+// ValidateConfig parses and validates the provider config and returns
+// token_env. It makes no API calls.
+func (p *DigitalOceanProvider) ValidateConfig(cfg any) ([]string, error) {
+	config, err := parseConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return []string{config.TokenEnv}, nil
+}
+
+// VerifyCredentials reads the account the token_env token belongs to.
+func (p *DigitalOceanProvider) VerifyCredentials(ctx context.Context, cfg any) (string, error) {
+	config, err := parseConfig(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	token, ok := os.LookupEnv(config.TokenEnv)
+	if !ok || token == "" {
+		return "", fmt.Errorf("%w: API token missing from environment variable %s", ErrTokenMissing, config.TokenEnv)
+	}
+
+	newClient := p.accountClient
+	if newClient == nil {
+		newClient = func(token string) AccountClient {
+			client := NewHTTPAPIClient(token)
+			client.Retry = config.retryPolicy()
+			return client
+		}
+	}
+	account, err := newClient(token).GetAccount(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("account %s (%s)", account.Email, account.Status), nil
+}
+
+// connect returns p with an API client for token: p itself when a client
+// was injected, otherwise a copy using an HTTPAPIClient with the config's
+// retry policy. The registered provider is shared, so it is not modified.
+func (p *DigitalOceanProvider) connect(config *Config, token string) *DigitalOceanProvider {
+	if p.client != nil {
+		return p
+	}
+	client := NewHTTPAPIClient(token)
+	client.Retry = config.retryPolicy()
+	if p.apiBaseURL != "" {
+		client.BaseURL = p.apiBaseURL
+	}
+
+	connected := *p
+	connected.client = client
+	return &connected
+}
+
 // Plan generates an infrastructure plan for the given environment.
 // This is a dry-run operation that does not modify infrastructure.
 func (p *DigitalOceanProvider) Plan(ctx context.Context, opts cloud.PlanOptions) (cloud.InfraPlan, error) {
@@ -73,7 +165,7 @@ func (p *DigitalOceanProvider) Plan(ctx context.Context, opts cloud.PlanOptions)
 	if !ok || token == "" {
 		return cloud.InfraPlan{}, fmt.Errorf("%w: API token missing from environment variable %s", ErrTokenMissing, config.TokenEnv)
 	}
-	_ = token // Token validated but not used directly in Plan (only in Apply)
+	p = p.connect(config, token)
 
 	// Validate SSH key exists
 	if _, err := p.client.GetSSHKey(ctx, config.SSHKeyName); err != nil {
@@ -91,19 +183,11 @@ func (p *DigitalOceanProvider) Plan(ctx context.Context, opts cloud.PlanOptions)
 	}
 
 	// List existing droplets for this environment
-	droplets, err := p.client.ListDroplets(ctx, DropletFilter{
-		NamePrefix: env + "-",
-	})
+	droplets, err := p.client.ListDroplets(ctx, dropletFilter(opts.Project, env))
 	if err != nil {
 		return cloud.InfraPlan{}, fmt.Errorf("%w: %v", ErrAPIError, err)
 	}
 
-	// Build desired hosts map
-	desired := make(map[string]HostConfig, len(envHosts))
-	for name, hostCfg := range envHosts {
-		desired[name] = hostCfg
-	}
-
 	// Build actual droplets map (strip environment prefix)
 	actual := make(map[string]Droplet, len(droplets))
 	for _, d := range droplets {
@@ -112,23 +196,52 @@ func (p *DigitalOceanProvider) Plan(ctx context.Context, opts cloud.PlanOptions)
 		actual[name] = d
 	}
 
-	var toCreate, toDelete []cloud.HostSpec
+	toCreate, toDelete := diffHosts(config, envHosts, actual)
 
-	// Hosts to create
-	for name, hostCfg := range desired {
+	// Hosts whose size or region drifted from config
+	toResize, toReplace, err := planHostChanges(config, envHosts, actual)
+	if err != nil {
+		return cloud.InfraPlan{}, err
+	}
+
+	reservedIPs, err := p.planReservedIPs(ctx, config, envHosts, actual, opts.ReservedIPs)
+	if err != nil {
+		return cloud.InfraPlan{}, err
+	}
+
+	volumes, err := p.planVolumes(ctx, config, env, envHosts, actual)
+	if err != nil {
+		return cloud.InfraPlan{}, err
+	}
+
+	pricing, err := p.pricing(ctx, opts.RefreshPricing)
+	if err != nil {
+		return cloud.InfraPlan{}, err
+	}
+
+	plan := cloud.InfraPlan{
+		ToCreate:    toCreate,
+		ToDelete:    toDelete,
+		ToResize:    toResize,
+		ToReplace:   toReplace,
+		ReservedIPs: reservedIPs,
+		Volumes:     volumes,
+	}
+	plan.Cost = pricing.Estimate(plan)
+	return plan, nil
+}
+
+// diffHosts returns the declared hosts without a droplet and the droplets
+// no longer declared, each sorted by name.
+func diffHosts(config *Config, envHosts map[string]HostConfig, actual map[string]Droplet) (toCreate, toDelete []cloud.HostSpec) {
+	for name, hostCfg := range envHosts {
 		if _, exists := actual[name]; !exists {
-			toCreate = append(toCreate, cloud.HostSpec{
-				Name:   name,
-				Role:   hostCfg.Role,
-				Size:   firstNonEmpty(hostCfg.Size, config.DefaultSize),
-				Region: firstNonEmpty(hostCfg.Region, config.DefaultRegion),
-			})
+			toCreate = append(toCreate, desiredHostSpec(config, name, hostCfg))
 		}
 	}
 
-	// Hosts to delete
 	for name, d := range actual {
-		if _, exists := desired[name]; !exists {
+		if _, exists := envHosts[name]; !exists {
 			toDelete = append(toDelete, cloud.HostSpec{
 				Name:   name,
 				Role:   "", // Not needed for delete
@@ -138,18 +251,24 @@ func (p *DigitalOceanProvider) Plan(ctx context.Context, opts cloud.PlanOptions)
 		}
 	}
 
-	// Sort lexicographically by Name
 	sort.Slice(toCreate, func(i, j int) bool {
 		return toCreate[i].Name < toCreate[j].Name
 	})
 	sort.Slice(toDelete, func(i, j int) bool {
 		return toDelete[i].Name < toDelete[j].Name
 	})
+	return toCreate, toDelete
+}
 
-	return cloud.InfraPlan{
-		ToCreate: toCreate,
-		ToDelete: toDelete,
-	}, nil
+// desiredHostSpec returns the spec config declares for a host, with the
+// provider defaults applied.
+func desiredHostSpec(config *Config, name string, hostCfg HostConfig) cloud.HostSpec {
+	return cloud.HostSpec{
+		Name:   name,
+		Role:   hostCfg.Role,
+		Size:   firstNonEmpty(hostCfg.Size, config.DefaultSize),
+		Region: firstNonEmpty(hostCfg.Region, config.DefaultRegion),
+	}
 }
 
 // firstNonEmpty returns the first non-empty string from the given values.
@@ -176,7 +295,7 @@ func (p *DigitalOceanProvider) Apply(ctx context.Context, opts cloud.ApplyOption
 	if !ok || token == "" {
 		return fmt.Errorf("%w: API token missing from environment variable %s", ErrTokenMissing, config.TokenEnv)
 	}
-	_ = token // Used by real client, not provider
+	p = p.connect(config, token)
 
 	// Validate SSH key exists and get its ID
 	sshKey, err := p.client.GetSSHKey(ctx, config.SSHKeyName)
@@ -186,9 +305,9 @@ func (p *DigitalOceanProvider) Apply(ctx context.Context, opts cloud.ApplyOption
 		}
 		return fmt.Errorf("%w: %v", ErrAPIError, err)
 	}
-	sshKeyID := sshKey.ID
 
 	env := opts.Environment
+	tags := dropletTags(opts.Project, env)
 
 	// Process creates in deterministic order
 	toCreate := append([]cloud.HostSpec(nil), opts.Plan.ToCreate...)
@@ -205,6 +324,9 @@ func (p *DigitalOceanProvider) Apply(ctx context.Context, opts cloud.ApplyOption
 		}
 
 		if existing != nil {
+			if err := p.adoptDroplet(ctx, existing, tags, opts.Project, env); err != nil {
+				return err
+			}
 			// Idempotent if matches spec
 			if existing.Region == host.Region && existing.Size == host.Size {
 				continue
@@ -212,34 +334,25 @@ func (p *DigitalOceanProvider) Apply(ctx context.Context, opts cloud.ApplyOption
 			return fmt.Errorf("%w: droplet %q already exists with different spec", ErrDropletExists, fullName)
 		}
 
-		req := CreateDropletRequest{
-			Name:   fullName,
-			Region: host.Region,
-			Size:   host.Size,
-			Image:  "ubuntu-22-04-x64",
-			SSHKeys: []int{
-				sshKeyID,
-			},
-			Tags: []string{
-				"stagecraft",
-				"stagecraft-env-" + env,
-			},
+		if err := p.createDroplet(ctx, config, env, host, sshKey, tags); err != nil {
+			return err
 		}
+	}
 
-		droplet, err := p.client.CreateDroplet(ctx, req)
-		if err != nil {
-			if errors.Is(err, ErrRateLimit) {
-				return fmt.Errorf("%w: %v", ErrRateLimit, err)
-			}
-			return fmt.Errorf("%w: %v", ErrDropletCreateFailed, err)
-		}
+	if err := p.applyResizes(ctx, env, tags, opts.Plan.ToResize); err != nil {
+		return err
+	}
 
-		if err := p.client.WaitForDroplet(ctx, droplet.ID, "active"); err != nil {
-			if errors.Is(err, ErrDropletTimeout) {
-				return fmt.Errorf("%w: %v", ErrDropletTimeout, err)
-			}
-			return fmt.Errorf("%w: %v", ErrAPIError, err)
-		}
+	if err := p.applyReplaces(ctx, config, env, sshKey, tags, opts.Plan.ToReplace); err != nil {
+		return err
+	}
+
+	if err := p.applyVolumes(ctx, env, opts.Plan.Volumes); err != nil {
+		return err
+	}
+
+	if err := p.applyReservedIPs(ctx, env, opts.Plan.ReservedIPs); err != nil {
+		return err
 	}
 
 	// Process deletes in deterministic order
@@ -259,31 +372,176 @@ func (p *DigitalOceanProvider) Apply(ctx context.Context, opts cloud.ApplyOption
 			}
 			return fmt.Errorf("%w: %v", ErrAPIError, err)
 		}
+		if err := p.deleteDroplet(ctx, existing, tags); err != nil {
+			return err
+		}
+	}
 
-		if err := p.client.DeleteDroplet(ctx, existing.ID); err != nil {
-			if errors.Is(err, ErrDropletNotFound) {
-				continue
-			}
-			return fmt.Errorf("%w: %v", ErrDropletDeleteFailed, err)
+	return nil
+}
+
+// createDroplet creates the droplet for host and waits until it is active.
+func (p *DigitalOceanProvider) createDroplet(ctx context.Context, config *Config, env string, host cloud.HostSpec, sshKey *SSHKey, tags []string) error {
+	userData, err := renderUserData(config, env, host, sshKey)
+	if err != nil {
+		return err
+	}
+
+	req := CreateDropletRequest{
+		Name:   env + "-" + host.Name,
+		Region: host.Region,
+		Size:   host.Size,
+		Image:  "ubuntu-22-04-x64",
+		SSHKeys: []int{
+			sshKey.ID,
+		},
+		Tags:     tags,
+		UserData: userData,
+	}
+
+	droplet, err := p.client.CreateDroplet(ctx, req)
+	if err != nil {
+		if errors.Is(err, ErrRateLimit) {
+			return fmt.Errorf("%w: %v", ErrRateLimit, err)
 		}
+		return fmt.Errorf("%w: %v", ErrDropletCreateFailed, err)
+	}
 
-		if err := p.client.WaitForDroplet(ctx, existing.ID, "deleted"); err != nil {
-			if errors.Is(err, ErrDropletTimeout) {
-				return fmt.Errorf("%w: %v", ErrDropletTimeout, err)
-			}
-			return fmt.Errorf("%w: %v", ErrAPIError, err)
+	return p.waitForDroplet(ctx, droplet.ID, "active")
+}
+
+// deleteDroplet deletes d, which must carry every tag in tags, and waits
+// until it is gone. A droplet that is already gone is not an error.
+func (p *DigitalOceanProvider) deleteDroplet(ctx context.Context, d *Droplet, tags []string) error {
+	if !hasAllTags(d.Tags, tags) {
+		return fmt.Errorf("%w: refusing to delete droplet %q without tags %v", ErrDropletUnmanaged, d.Name, tags)
+	}
+
+	if err := p.client.DeleteDroplet(ctx, d.ID); err != nil {
+		if errors.Is(err, ErrDropletNotFound) {
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrDropletDeleteFailed, err)
+	}
+
+	return p.waitForDroplet(ctx, d.ID, "deleted")
+}
+
+// waitForDroplet waits for a droplet to reach status.
+func (p *DigitalOceanProvider) waitForDroplet(ctx context.Context, id int, status string) error {
+	if err := p.client.WaitForDroplet(ctx, id, status); err != nil {
+		if errors.Is(err, ErrDropletTimeout) {
+			return fmt.Errorf("%w: %v", ErrDropletTimeout, err)
 		}
+		return fmt.Errorf("%w: %v", ErrAPIError, err)
 	}
+	return nil
+}
 
+// adoptDroplet checks that an existing droplet with a planned name belongs
+// to the environment. A droplet created before droplets were tagged with
+// their project gets the project tag; any other droplet missing the tags
+// is left alone and fails the apply.
+func (p *DigitalOceanProvider) adoptDroplet(ctx context.Context, d *Droplet, tags []string, project, env string) error {
+	if hasAllTags(d.Tags, tags) {
+		return nil
+	}
+	if project == "" || !untaggedProject(d, env) {
+		return fmt.Errorf("%w: droplet %q exists without tags %v", ErrDropletUnmanaged, d.Name, tags)
+	}
+	if err := p.client.TagDroplets(ctx, projectTag(project), []int{d.ID}); err != nil {
+		return fmt.Errorf("%w: tagging droplet %q: %v", ErrAPIError, d.Name, err)
+	}
+	d.Tags = append(d.Tags, projectTag(project))
 	return nil
 }
 
-// Hosts returns the list of provisioned hosts for the given environment.
-// This is a stub implementation for Slice 2; full implementation will come in later slices.
+// Hosts returns the provisioned hosts for the given environment, sorted by
+// name. Only droplets declared in config are returned; the role and volumes
+// come from config and the public IP from the droplet.
 func (p *DigitalOceanProvider) Hosts(ctx context.Context, opts cloud.HostsOptions) ([]cloud.Host, error) {
-	// TODO: Implement full Hosts method in later slices
-	// For now, return empty list to satisfy interface
-	return []cloud.Host{}, nil
+	config, err := parseConfig(opts.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := os.LookupEnv(config.TokenEnv)
+	if !ok || token == "" {
+		return nil, fmt.Errorf("%w: API token missing from environment variable %s", ErrTokenMissing, config.TokenEnv)
+	}
+	p = p.connect(config, token)
+
+	env := opts.Environment
+	envHosts := config.Hosts[env]
+	if len(envHosts) == 0 {
+		return []cloud.Host{}, nil
+	}
+
+	droplets, err := p.client.ListDroplets(ctx, dropletFilter(opts.Project, env))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAPIError, err)
+	}
+
+	hosts := make([]cloud.Host, 0, len(droplets))
+	for _, d := range droplets {
+		name := strings.TrimPrefix(d.Name, env+"-")
+		hostCfg, ok := envHosts[name]
+		if !ok {
+			continue
+		}
+		hosts = append(hosts, managedHost(opts.Project, env, name, hostCfg, d))
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Name < hosts[j].Name
+	})
+
+	return hosts, nil
+}
+
+// managedHost returns the host for the droplet of a declared host.
+func managedHost(project, env, name string, hostCfg HostConfig, d Droplet) cloud.Host {
+	return cloud.Host{
+		ID:       strconv.Itoa(d.ID),
+		Name:     name,
+		Role:     hostCfg.Role,
+		PublicIP: publicIPv4(d),
+		Region:   d.Region,
+		Size:     d.Size,
+		Status:   d.Status,
+		Tags:     dropletTags(project, env),
+		Volumes:  hostVolumes(env, name, hostCfg),
+	}
+}
+
+// DesiredHosts returns the hosts declared in config for the environment,
+// sorted by name. It makes no API calls.
+func (p *DigitalOceanProvider) DesiredHosts(ctx context.Context, opts cloud.HostsOptions) ([]cloud.HostSpec, error) {
+	config, err := parseConfig(opts.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	envHosts := config.Hosts[opts.Environment]
+	hosts := make([]cloud.HostSpec, 0, len(envHosts))
+	for name, hostCfg := range envHosts {
+		hosts = append(hosts, desiredHostSpec(config, name, hostCfg))
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Name < hosts[j].Name
+	})
+
+	return hosts, nil
+}
+
+// publicIPv4 returns the droplet's public IPv4 address, or "" if it has none yet.
+func publicIPv4(d Droplet) string {
+	for _, n := range d.Networks.V4 {
+		if n.Type == "public" {
+			return n.IPAddress
+		}
+	}
+	return ""
 }
 
 // init registers the provider with the cloud registry.
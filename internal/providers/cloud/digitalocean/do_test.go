@@ -19,6 +19,8 @@ package digitalocean
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
@@ -317,14 +319,30 @@ type mockAPIClient struct {
 	getDropletErr    error
 	createDropletErr error
 	deleteDropletErr error
+	resizeErr        error
+	renameErr        error
 	waitErr          error
 	listErr          error
 	sshKeyErr        error
+	firewallErr      error
+
+	firewalls     []Firewall
+	volumes       []Volume
+	volumeErr     error
+	reservedIPs   []ReservedIP
+	reservedIPErr error
+	sizes         []Size
+	sizesErr      error
 
 	// Operation tracking
-	created []CreateDropletRequest
-	deleted []int
-	waited  []struct {
+	created          []CreateDropletRequest
+	deleted          []int
+	tagged           []string
+	resized          []string
+	renamed          []string
+	createdFirewalls []FirewallRequest
+	updatedFirewalls map[string]FirewallRequest
+	waited           []struct {
 		id     int
 		status string
 	}
@@ -345,7 +363,9 @@ func (m *mockAPIClient) ListDroplets(ctx context.Context, filter DropletFilter)
 		if filter.NamePrefix != "" && !strings.HasPrefix(d.Name, filter.NamePrefix) {
 			continue
 		}
-		// Tags filtering can be added later if needed
+		if !hasAllTags(d.Tags, filter.Tags) {
+			continue
+		}
 		result = append(result, d)
 	}
 	return result, nil
@@ -384,6 +404,7 @@ func (m *mockAPIClient) CreateDroplet(ctx context.Context, req CreateDropletRequ
 		Region: req.Region,
 		Size:   req.Size,
 		Status: "new",
+		Tags:   req.Tags,
 	}
 
 	m.droplets[req.Name] = d
@@ -410,6 +431,48 @@ func (m *mockAPIClient) DeleteDroplet(ctx context.Context, id int) error {
 	return ErrDropletNotFound
 }
 
+func (m *mockAPIClient) TagDroplets(ctx context.Context, tag string, ids []int) error {
+	for name, d := range m.droplets {
+		if slices.Contains(ids, d.ID) {
+			d.Tags = append(d.Tags, tag)
+			m.droplets[name] = d
+		}
+	}
+	m.tagged = append(m.tagged, tag)
+	return nil
+}
+
+func (m *mockAPIClient) RenameDroplet(ctx context.Context, id int, name string) error {
+	if m.renameErr != nil {
+		return m.renameErr
+	}
+	for old, d := range m.droplets {
+		if d.ID == id {
+			delete(m.droplets, old)
+			d.Name = name
+			m.droplets[name] = d
+			m.renamed = append(m.renamed, fmt.Sprintf("%s=%s", old, name))
+			return nil
+		}
+	}
+	return ErrDropletNotFound
+}
+
+func (m *mockAPIClient) ResizeDroplet(ctx context.Context, id int, size string) error {
+	if m.resizeErr != nil {
+		return m.resizeErr
+	}
+	for name, d := range m.droplets {
+		if d.ID == id {
+			d.Size = size
+			m.droplets[name] = d
+			m.resized = append(m.resized, fmt.Sprintf("%s=%s", name, size))
+			return nil
+		}
+	}
+	return ErrDropletNotFound
+}
+
 func (m *mockAPIClient) ListSSHKeys(ctx context.Context) ([]SSHKey, error) {
 	if m.sshKeys == nil {
 		return nil, nil
@@ -450,6 +513,127 @@ func (m *mockAPIClient) WaitForDroplet(ctx context.Context, id int, status strin
 	return nil
 }
 
+func (m *mockAPIClient) ListFirewalls(ctx context.Context) ([]Firewall, error) {
+	if m.firewallErr != nil {
+		return nil, m.firewallErr
+	}
+	return m.firewalls, nil
+}
+
+//nolint:gocritic // hugeParam: mock implementation matches interface signature
+func (m *mockAPIClient) CreateFirewall(ctx context.Context, req FirewallRequest) (*Firewall, error) {
+	if m.firewallErr != nil {
+		return nil, m.firewallErr
+	}
+
+	fw := Firewall{
+		ID:            fmt.Sprintf("fw-%d", len(m.firewalls)+1),
+		Name:          req.Name,
+		Tags:          req.Tags,
+		InboundRules:  req.InboundRules,
+		OutboundRules: req.OutboundRules,
+	}
+	m.firewalls = append(m.firewalls, fw)
+	m.createdFirewalls = append(m.createdFirewalls, req)
+	return &fw, nil
+}
+
+//nolint:gocritic // hugeParam: mock implementation matches interface signature
+func (m *mockAPIClient) UpdateFirewall(ctx context.Context, id string, req FirewallRequest) (*Firewall, error) {
+	if m.firewallErr != nil {
+		return nil, m.firewallErr
+	}
+
+	for i := range m.firewalls {
+		if m.firewalls[i].ID == id {
+			m.firewalls[i].Tags = req.Tags
+			m.firewalls[i].InboundRules = req.InboundRules
+			m.firewalls[i].OutboundRules = req.OutboundRules
+			if m.updatedFirewalls == nil {
+				m.updatedFirewalls = make(map[string]FirewallRequest)
+			}
+			m.updatedFirewalls[id] = req
+			return &m.firewalls[i], nil
+		}
+	}
+	return nil, fmt.Errorf("firewall %s not found", id)
+}
+
+func (m *mockAPIClient) ListReservedIPs(ctx context.Context) ([]ReservedIP, error) {
+	if m.reservedIPErr != nil {
+		return nil, m.reservedIPErr
+	}
+	return m.reservedIPs, nil
+}
+
+func (m *mockAPIClient) CreateReservedIP(ctx context.Context, region string) (*ReservedIP, error) {
+	if m.reservedIPErr != nil {
+		return nil, m.reservedIPErr
+	}
+
+	ip := ReservedIP{IP: fmt.Sprintf("203.0.113.%d", len(m.reservedIPs)+10), Region: region}
+	m.reservedIPs = append(m.reservedIPs, ip)
+	return &ip, nil
+}
+
+func (m *mockAPIClient) AssignReservedIP(ctx context.Context, ip string, dropletID int) error {
+	if m.reservedIPErr != nil {
+		return m.reservedIPErr
+	}
+
+	for i := range m.reservedIPs {
+		if m.reservedIPs[i].IP == ip {
+			m.reservedIPs[i].DropletID = dropletID
+			return nil
+		}
+	}
+	return fmt.Errorf("reserved IP %s not found", ip)
+}
+
+func (m *mockAPIClient) ListVolumes(ctx context.Context) ([]Volume, error) {
+	if m.volumeErr != nil {
+		return nil, m.volumeErr
+	}
+	return m.volumes, nil
+}
+
+//nolint:gocritic // hugeParam: mock implementation matches interface signature
+func (m *mockAPIClient) CreateVolume(ctx context.Context, req CreateVolumeRequest) (*Volume, error) {
+	if m.volumeErr != nil {
+		return nil, m.volumeErr
+	}
+
+	v := Volume{
+		ID:            fmt.Sprintf("vol-%d", len(m.volumes)+1),
+		Name:          req.Name,
+		Region:        req.Region,
+		SizeGigaBytes: req.SizeGigaBytes,
+	}
+	m.volumes = append(m.volumes, v)
+	return &v, nil
+}
+
+func (m *mockAPIClient) AttachVolume(ctx context.Context, volumeID string, dropletID int) error {
+	if m.volumeErr != nil {
+		return m.volumeErr
+	}
+
+	for i := range m.volumes {
+		if m.volumes[i].ID == volumeID {
+			m.volumes[i].DropletIDs = append(m.volumes[i].DropletIDs, dropletID)
+			return nil
+		}
+	}
+	return fmt.Errorf("volume %s not found", volumeID)
+}
+
+func (m *mockAPIClient) ListSizes(ctx context.Context) ([]Size, error) {
+	if m.sizesErr != nil {
+		return nil, m.sizesErr
+	}
+	return m.sizes, nil
+}
+
 func TestDigitalOceanProvider_Plan_HappyPath_NoExistingDroplets(t *testing.T) {
 	// Cannot use t.Parallel() with t.Setenv()
 
@@ -584,8 +768,8 @@ func TestDigitalOceanProvider_Plan_Reconciliation(t *testing.T) {
 			"my-ssh-key": {ID: 1, Name: "my-ssh-key"},
 		},
 		droplets: map[string]Droplet{
-			"staging-app-1":    {ID: 1, Name: "staging-app-1", Region: "nyc1", Size: "s-2vcpu-4gb"},
-			"staging-orphan-1": {ID: 2, Name: "staging-orphan-1", Region: "nyc1", Size: "s-2vcpu-4gb"},
+			"staging-app-1":    {ID: 1, Name: "staging-app-1", Tags: []string{"stagecraft", "stagecraft-env-staging"}, Region: "nyc1", Size: "s-2vcpu-4gb"},
+			"staging-orphan-1": {ID: 2, Name: "staging-orphan-1", Tags: []string{"stagecraft", "stagecraft-env-staging"}, Region: "nyc1", Size: "s-2vcpu-4gb"},
 		},
 	}
 
@@ -880,8 +1064,8 @@ func TestDigitalOceanProvider_Apply_HappyPath_DeleteDroplets(t *testing.T) {
 			"my-ssh-key": {ID: 123, Name: "my-ssh-key"},
 		},
 		droplets: map[string]Droplet{
-			"staging-app-1": {ID: 1, Name: "staging-app-1", Region: "nyc1", Size: "s-2vcpu-4gb"},
-			"staging-db-1":  {ID: 2, Name: "staging-db-1", Region: "nyc1", Size: "s-2vcpu-4gb"},
+			"staging-app-1": {ID: 1, Name: "staging-app-1", Tags: []string{"stagecraft", "stagecraft-env-staging"}, Region: "nyc1", Size: "s-2vcpu-4gb"},
+			"staging-db-1":  {ID: 2, Name: "staging-db-1", Tags: []string{"stagecraft", "stagecraft-env-staging"}, Region: "nyc1", Size: "s-2vcpu-4gb"},
 		},
 	}
 
@@ -958,7 +1142,7 @@ func TestDigitalOceanProvider_Apply_IdempotentCreate_DropletAlreadyMatches(t *te
 			"my-ssh-key": {ID: 123, Name: "my-ssh-key"},
 		},
 		droplets: map[string]Droplet{
-			"staging-app-1": {ID: 1, Name: "staging-app-1", Region: "nyc1", Size: "s-2vcpu-4gb"},
+			"staging-app-1": {ID: 1, Name: "staging-app-1", Tags: []string{"stagecraft", "stagecraft-env-staging"}, Region: "nyc1", Size: "s-2vcpu-4gb"},
 		},
 	}
 
@@ -1013,7 +1197,7 @@ func TestDigitalOceanProvider_Apply_ReconciliationError_DropletExistsButMismatch
 			"my-ssh-key": {ID: 123, Name: "my-ssh-key"},
 		},
 		droplets: map[string]Droplet{
-			"staging-app-1": {ID: 1, Name: "staging-app-1", Region: "nyc1", Size: "s-2vcpu-4gb"},
+			"staging-app-1": {ID: 1, Name: "staging-app-1", Tags: []string{"stagecraft", "stagecraft-env-staging"}, Region: "nyc1", Size: "s-2vcpu-4gb"},
 		},
 	}
 
@@ -1157,25 +1341,290 @@ func TestDigitalOceanProvider_Apply_WaitForDropletError(t *testing.T) {
 	}
 }
 
-func TestDigitalOceanProvider_Hosts_Stub(t *testing.T) {
-	t.Parallel()
+func taggingTestSetup(t *testing.T, droplets map[string]Droplet) (map[string]any, *mockAPIClient) {
+	t.Helper()
+	t.Setenv("DO_TOKEN", "dummy-token")
+	cfg := map[string]any{
+		"token_env":    "DO_TOKEN",
+		"ssh_key_name": "my-ssh-key",
+		"hosts": map[string]any{
+			"prod": map[string]any{
+				"app-1": map[string]any{"role": "app", "region": "nyc1", "size": "s-2vcpu-4gb"},
+			},
+		},
+	}
+	client := &mockAPIClient{
+		sshKeys:  map[string]SSHKey{"my-ssh-key": {ID: 123, Name: "my-ssh-key"}},
+		droplets: droplets,
+	}
+	return cfg, client
+}
 
-	ctx := context.Background()
-	provider := NewDigitalOceanProvider()
+func TestDigitalOceanProvider_Plan_ScopesToProjectTags(t *testing.T) {
+	shopTags := []string{"stagecraft", "stagecraft-project-shop", "stagecraft-env-prod"}
+	cfg, client := taggingTestSetup(t, map[string]Droplet{
+		"prod-app-1":    {ID: 1, Name: "prod-app-1", Tags: shopTags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+		"prod-old-1":    {ID: 2, Name: "prod-old-1", Tags: shopTags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+		"prod-blog-1":   {ID: 3, Name: "prod-blog-1", Tags: []string{"stagecraft", "stagecraft-project-blog", "stagecraft-env-prod"}},
+		"prod-manual-1": {ID: 4, Name: "prod-manual-1"},
+	})
 
-	opts := cloud.HostsOptions{
+	plan, err := NewDigitalOceanProviderWithClient(client).Plan(context.Background(), cloud.PlanOptions{
+		Config:      cfg,
 		Environment: "prod",
-		Config: map[string]interface{}{
-			"token_env": "DO_TOKEN",
+		Project:     "shop",
+	})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.ToCreate) != 0 {
+		t.Errorf("ToCreate = %+v, want none", plan.ToCreate)
+	}
+	if len(plan.ToDelete) != 1 || plan.ToDelete[0].Name != "old-1" {
+		t.Errorf("ToDelete = %+v, want only old-1", plan.ToDelete)
+	}
+}
+
+func TestProjectTag_DistinctForCollidingNames(t *testing.T) {
+	tests := map[string]string{
+		"shop":   "stagecraft-project-shop",
+		"my-app": "stagecraft-project-my-app",
+		"my_app": "stagecraft-project-my_app",
+		"my.app": "stagecraft-project-my-app-13d7e337",
+	}
+	seen := make(map[string]string)
+	for project, want := range tests {
+		got := projectTag(project)
+		if got != want {
+			t.Errorf("projectTag(%q) = %q, want %q", project, got, want)
+		}
+		if other, ok := seen[got]; ok {
+			t.Errorf("projectTag(%q) collides with projectTag(%q)", project, other)
+		}
+		seen[got] = project
+	}
+}
+
+func TestDigitalOceanProvider_Apply_TagsCreatedDroplets(t *testing.T) {
+	cfg, client := taggingTestSetup(t, nil)
+
+	err := NewDigitalOceanProviderWithClient(client).Apply(context.Background(), cloud.ApplyOptions{
+		Config:      cfg,
+		Environment: "prod",
+		Project:     "My Shop",
+		Plan: cloud.InfraPlan{ToCreate: []cloud.HostSpec{
+			{Name: "app-1", Role: "app", Size: "s-2vcpu-4gb", Region: "nyc1"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(client.created) != 1 {
+		t.Fatalf("created %d droplets, want 1", len(client.created))
+	}
+	want := "stagecraft stagecraft-project-my-shop-b14e7670 stagecraft-env-prod"
+	if got := strings.Join(client.created[0].Tags, " "); got != want {
+		t.Errorf("Tags = %q, want %q", got, want)
+	}
+}
+
+func TestDigitalOceanProvider_Apply_AdoptsDropletWithoutProjectTag(t *testing.T) {
+	cfg, client := taggingTestSetup(t, map[string]Droplet{
+		"prod-app-1": {ID: 1, Name: "prod-app-1", Tags: []string{"stagecraft", "stagecraft-env-prod"}, Region: "nyc1", Size: "s-2vcpu-4gb"},
+	})
+
+	err := NewDigitalOceanProviderWithClient(client).Apply(context.Background(), cloud.ApplyOptions{
+		Config:      cfg,
+		Environment: "prod",
+		Project:     "shop",
+		Plan: cloud.InfraPlan{ToCreate: []cloud.HostSpec{
+			{Name: "app-1", Role: "app", Size: "s-2vcpu-4gb", Region: "nyc1"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(client.created) != 0 {
+		t.Errorf("created %d droplets, want 0", len(client.created))
+	}
+	if len(client.tagged) != 1 || client.tagged[0] != "stagecraft-project-shop" {
+		t.Errorf("tagged = %v, want [stagecraft-project-shop]", client.tagged)
+	}
+}
+
+func TestDigitalOceanProvider_Apply_RefusesUnmanagedDroplets(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		plan cloud.InfraPlan
+	}{
+		{
+			name: "create over another project's droplet",
+			tags: []string{"stagecraft", "stagecraft-project-blog", "stagecraft-env-prod"},
+			plan: cloud.InfraPlan{ToCreate: []cloud.HostSpec{{Name: "app-1", Size: "s-2vcpu-4gb", Region: "nyc1"}}},
+		},
+		{
+			name: "delete untagged droplet",
+			plan: cloud.InfraPlan{ToDelete: []cloud.HostSpec{{Name: "app-1"}}},
+		},
+		{
+			name: "delete droplet without project tag",
+			tags: []string{"stagecraft", "stagecraft-env-prod"},
+			plan: cloud.InfraPlan{ToDelete: []cloud.HostSpec{{Name: "app-1"}}},
 		},
 	}
 
-	hosts, err := provider.Hosts(ctx, opts)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, client := taggingTestSetup(t, map[string]Droplet{
+				"prod-app-1": {ID: 1, Name: "prod-app-1", Tags: tt.tags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+			})
+
+			err := NewDigitalOceanProviderWithClient(client).Apply(context.Background(), cloud.ApplyOptions{
+				Config:      cfg,
+				Environment: "prod",
+				Project:     "shop",
+				Plan:        tt.plan,
+			})
+			if !errors.Is(err, ErrDropletUnmanaged) {
+				t.Fatalf("Apply() error = %v, want ErrDropletUnmanaged", err)
+			}
+			if len(client.deleted) != 0 || len(client.tagged) != 0 {
+				t.Errorf("deleted = %v, tagged = %v, want none", client.deleted, client.tagged)
+			}
+		})
+	}
+}
+
+func TestDigitalOceanProvider_Hosts_ReturnsConfiguredDroplets(t *testing.T) {
+	// Cannot use t.Parallel() with t.Setenv()
+
+	ctx := context.Background()
+	cfg := map[string]any{
+		"token_env":    "DO_TOKEN",
+		"ssh_key_name": "my-ssh-key",
+		"hosts": map[string]any{
+			"prod": map[string]any{
+				"app-1": map[string]any{"role": "app"},
+				"db-1": map[string]any{
+					"role": "db",
+					"volumes": []any{
+						map[string]any{"name": "data", "size_gb": 100, "mount_point": "/mnt/data"},
+					},
+				},
+			},
+		},
+	}
+
+	mockClient := &mockAPIClient{
+		droplets: map[string]Droplet{
+			"prod-db-1": {ID: 2, Name: "prod-db-1", Tags: []string{"stagecraft", "stagecraft-env-prod"}, Region: "nyc3", Status: "active", Networks: Networks{V4: []NetworkV4{
+				{IPAddress: "10.0.0.2", Type: "private"},
+				{IPAddress: "192.0.2.2", Type: "public"},
+			}}},
+			"prod-app-1":   {ID: 1, Name: "prod-app-1", Tags: []string{"stagecraft", "stagecraft-env-prod"}},
+			"prod-stray-1": {ID: 9, Name: "prod-stray-1", Tags: []string{"stagecraft", "stagecraft-env-prod"}},
+		},
+	}
+	provider := NewDigitalOceanProviderWithClient(mockClient)
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	hosts, err := provider.Hosts(ctx, cloud.HostsOptions{Config: cfg, Environment: "prod"})
 	if err != nil {
 		t.Fatalf("Hosts() returned error: %v", err)
 	}
 
-	if len(hosts) != 0 {
-		t.Errorf("Hosts() returned %d hosts, want 0 (stub)", len(hosts))
+	if len(hosts) != 2 {
+		t.Fatalf("Hosts() returned %d hosts, want 2 (undeclared droplets are skipped)", len(hosts))
+	}
+	if hosts[0].Name != "app-1" || hosts[1].Name != "db-1" {
+		t.Errorf("Hosts() names = [%s %s], want [app-1 db-1]", hosts[0].Name, hosts[1].Name)
+	}
+
+	db := hosts[1]
+	if db.ID != "2" || db.Role != "db" || db.PublicIP != "192.0.2.2" || db.Region != "nyc3" || db.Status != "active" {
+		t.Errorf("db host = %+v, want ID 2, role db, public IP 192.0.2.2, region nyc3, status active", db)
+	}
+	if len(db.Volumes) != 1 {
+		t.Fatalf("db volumes = %+v, want one", db.Volumes)
+	}
+	if got := db.Volumes[0]; got.Device != "/dev/disk/by-id/scsi-0DO_Volume_prod-db-1-data" || got.Filesystem != "ext4" || got.MountPoint != "/mnt/data" {
+		t.Errorf("db volume = %+v", got)
+	}
+}
+
+func TestDigitalOceanProvider_DesiredHosts_AppliesDefaults(t *testing.T) {
+	cfg := map[string]any{
+		"token_env":      "DO_TOKEN_DESIRED_UNSET",
+		"ssh_key_name":   "my-ssh-key",
+		"default_region": "nyc3",
+		"default_size":   "s-1vcpu-1gb",
+		"hosts": map[string]any{
+			"prod": map[string]any{
+				"db-1":  map[string]any{"role": "db", "size": "s-2vcpu-4gb"},
+				"app-1": map[string]any{"role": "app", "region": "sfo3"},
+			},
+		},
+	}
+
+	// DesiredHosts reads config only, so no client or token is needed.
+	provider := NewDigitalOceanProviderWithClient(&mockAPIClient{})
+
+	hosts, err := provider.DesiredHosts(context.Background(), cloud.HostsOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("DesiredHosts() returned error: %v", err)
+	}
+
+	want := []cloud.HostSpec{
+		{Name: "app-1", Role: "app", Size: "s-1vcpu-1gb", Region: "sfo3"},
+		{Name: "db-1", Role: "db", Size: "s-2vcpu-4gb", Region: "nyc3"},
+	}
+	if len(hosts) != len(want) {
+		t.Fatalf("DesiredHosts() = %+v, want %+v", hosts, want)
+	}
+	for i := range want {
+		if hosts[i] != want[i] {
+			t.Errorf("DesiredHosts()[%d] = %+v, want %+v", i, hosts[i], want[i])
+		}
+	}
+}
+
+func TestDigitalOceanProvider_Hosts_TokenMissing(t *testing.T) {
+	cfg := map[string]any{
+		"token_env":    "DO_TOKEN_HOSTS_MISSING",
+		"ssh_key_name": "my-ssh-key",
+		"hosts": map[string]any{
+			"prod": map[string]any{"app-1": map[string]any{"role": "app"}},
+		},
+	}
+
+	provider := NewDigitalOceanProviderWithClient(&mockAPIClient{})
+	_, err := provider.Hosts(context.Background(), cloud.HostsOptions{Config: cfg, Environment: "prod"})
+	if !errors.Is(err, ErrTokenMissing) {
+		t.Fatalf("expected ErrTokenMissing, got %v", err)
+	}
+}
+
+func TestDigitalOceanProvider_ValidateConfig(t *testing.T) {
+	cfg := map[string]any{
+		"token_env":    "DO_TOKEN_VALIDATE_UNSET",
+		"ssh_key_name": "my-ssh-key",
+		"hosts": map[string]any{
+			"prod": map[string]any{"app-1": map[string]any{"role": "app"}},
+		},
+	}
+
+	provider := NewDigitalOceanProvider()
+	envs, err := provider.ValidateConfig(cfg)
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v (must not need the token or API)", err)
+	}
+	if len(envs) != 1 || envs[0] != "DO_TOKEN_VALIDATE_UNSET" {
+		t.Errorf("ValidateConfig() = %v, want [DO_TOKEN_VALIDATE_UNSET]", envs)
+	}
+
+	delete(cfg, "ssh_key_name")
+	if _, err := provider.ValidateConfig(cfg); !errors.Is(err, ErrConfigInvalid) {
+		t.Errorf("ValidateConfig() without ssh_key_name error = %v, want ErrConfigInvalid", err)
 	}
 }
@@ -16,48 +16,80 @@ See https://www.gnu.org/licenses/ for license details.
 
 package digitalocean
 
-import "errors"
+import "stagecraft/internal/failure"
 
-// Error definitions for DigitalOcean provider.
+// Error definitions for DigitalOcean provider. Each carries its failure
+// class (see CORE_FAILURE_LENS).
 
 // Config errors (local, deterministic, no API calls).
 var (
 	// ErrConfigInvalid indicates invalid provider configuration.
-	ErrConfigInvalid = errors.New("digitalocean provider: invalid config")
+	ErrConfigInvalid = failure.New(failure.ClassConfigInvalid, "digitalocean provider: invalid config")
 )
 
 // Authentication errors (API calls required).
 var (
 	// ErrTokenMissing indicates API token is missing from environment.
-	ErrTokenMissing = errors.New("digitalocean provider: API token missing from environment")
+	ErrTokenMissing = failure.New(failure.ClassConfigInvalid, "digitalocean provider: API token missing from environment")
 
 	// ErrSSHKeyNotFound indicates SSH key is not found in DigitalOcean account.
-	ErrSSHKeyNotFound = errors.New("digitalocean provider: SSH key not found")
+	ErrSSHKeyNotFound = failure.New(failure.ClassConfigInvalid, "digitalocean provider: SSH key not found")
+
+	// ErrTokenRejected indicates the API rejected the token (401 or 403).
+	ErrTokenRejected = failure.New(failure.ClassConfigInvalid, "digitalocean provider: API token rejected")
 )
 
 // Resource errors (API operations).
 var (
 	// ErrDropletExists indicates droplet already exists (when reconciliation needed).
-	ErrDropletExists = errors.New("digitalocean provider: droplet already exists")
+	ErrDropletExists = failure.New(failure.ClassProviderFailure, "digitalocean provider: droplet already exists")
+
+	// ErrDropletUnmanaged indicates a droplet with a planned name lacks the
+	// project and environment tags, so Stagecraft will not adopt or delete it.
+	ErrDropletUnmanaged = failure.New(failure.ClassConfigInvalid, "digitalocean provider: droplet not managed by this environment")
 
 	// ErrDropletNotFound indicates droplet not found.
-	ErrDropletNotFound = errors.New("digitalocean provider: droplet not found")
+	ErrDropletNotFound = failure.New(failure.ClassProviderFailure, "digitalocean provider: droplet not found")
 
 	// ErrDropletCreateFailed indicates droplet creation failed.
-	ErrDropletCreateFailed = errors.New("digitalocean provider: droplet creation failed")
+	ErrDropletCreateFailed = failure.New(failure.ClassProviderFailure, "digitalocean provider: droplet creation failed")
 
 	// ErrDropletDeleteFailed indicates droplet deletion failed.
-	ErrDropletDeleteFailed = errors.New("digitalocean provider: droplet deletion failed")
+	ErrDropletDeleteFailed = failure.New(failure.ClassProviderFailure, "digitalocean provider: droplet deletion failed")
+
+	// ErrDropletResizeFailed indicates resizing a droplet failed.
+	ErrDropletResizeFailed = failure.New(failure.ClassProviderFailure, "digitalocean provider: droplet resize failed")
 
 	// ErrDropletTimeout indicates droplet operation timeout.
-	ErrDropletTimeout = errors.New("digitalocean provider: droplet operation timeout")
+	ErrDropletTimeout = failure.New(failure.ClassTransientEnvironment, "digitalocean provider: droplet operation timeout")
+)
+
+// Volume errors (API operations).
+var (
+	// ErrVolumeFailed indicates a volume could not be created or attached.
+	ErrVolumeFailed = failure.New(failure.ClassProviderFailure, "digitalocean provider: volume operation failed")
+)
+
+// Firewall errors (API operations).
+var (
+	// ErrFirewallApplyFailed indicates a cloud firewall could not be created or updated.
+	ErrFirewallApplyFailed = failure.New(failure.ClassProviderFailure, "digitalocean provider: firewall apply failed")
+)
+
+// Reserved IP errors (API operations).
+var (
+	// ErrReservedIPFailed indicates a reserved IP could not be created or assigned.
+	ErrReservedIPFailed = failure.New(failure.ClassProviderFailure, "digitalocean provider: reserved IP operation failed")
 )
 
 // API errors (infrastructure/rate limiting).
 var (
 	// ErrAPIError indicates DigitalOcean API error (wraps underlying API errors).
-	ErrAPIError = errors.New("digitalocean provider: API error")
+	ErrAPIError = failure.New(failure.ClassProviderFailure, "digitalocean provider: API error")
 
 	// ErrRateLimit indicates API rate limit exceeded (with retry logic).
-	ErrRateLimit = errors.New("digitalocean provider: API rate limit exceeded")
+	ErrRateLimit = failure.New(failure.ClassTransientEnvironment, "digitalocean provider: API rate limit exceeded")
+
+	// ErrAPIUnreachable indicates the API could not be reached at all.
+	ErrAPIUnreachable = failure.New(failure.ClassTransientEnvironment, "digitalocean provider: API unreachable")
 )
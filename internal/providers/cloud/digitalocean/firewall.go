@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_FIREWALL
+// Spec: spec/providers/cloud/firewall.md
+
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Ensure DigitalOceanProvider implements FirewallProvider
+var _ cloud.FirewallProvider = (*DigitalOceanProvider)(nil)
+
+// firewallName returns the cloud firewall name for an environment.
+func firewallName(env string) string {
+	return "stagecraft-" + env
+}
+
+// envTag returns the droplet tag shared by every host of an environment.
+func envTag(env string) string {
+	return "stagecraft-env-" + env
+}
+
+// desiredFirewall builds the firewall spec for an environment from config.
+func desiredFirewall(config *Config, env string) cloud.FirewallSpec {
+	var sshSources []string
+	var extra []cloud.FirewallRule
+	if config.Firewall != nil {
+		sshSources = config.Firewall.SSHSources
+		for _, rule := range config.Firewall.Inbound {
+			sources := rule.Sources
+			if len(sources) == 0 {
+				sources = cloud.AllAddresses
+			}
+			extra = append(extra, cloud.FirewallRule{
+				Protocol:  rule.Protocol,
+				Ports:     rule.Ports,
+				Addresses: sources,
+			})
+		}
+	}
+
+	return cloud.FirewallSpec{
+		Name:     firewallName(env),
+		Tags:     []string{envTag(env)},
+		Inbound:  cloud.NormalizeRules(append(cloud.BaselineInboundRules(sshSources), extra...)),
+		Outbound: cloud.NormalizeRules(cloud.DefaultOutboundRules()),
+	}
+}
+
+// PlanFirewall compares the environment's cloud firewall with its desired
+// spec. A missing firewall is planned for creation; an existing one that
+// drifted (rules or tags changed out of band) is planned for update.
+//
+//nolint:gocritic // hugeParam: opts matches interface signature
+func (p *DigitalOceanProvider) PlanFirewall(ctx context.Context, opts cloud.FirewallPlanOptions) (cloud.FirewallPlan, error) {
+	config, err := parseConfig(opts.Config)
+	if err != nil {
+		return cloud.FirewallPlan{}, err
+	}
+
+	token, ok := os.LookupEnv(config.TokenEnv)
+	if !ok || token == "" {
+		return cloud.FirewallPlan{}, fmt.Errorf("%w: API token missing from environment variable %s", ErrTokenMissing, config.TokenEnv)
+	}
+	p = p.connect(config, token)
+
+	env := opts.Environment
+	if !config.firewallEnabled() || len(config.Hosts[env]) == 0 {
+		return cloud.FirewallPlan{}, nil
+	}
+
+	desired := desiredFirewall(config, env)
+
+	existing, err := p.findFirewall(ctx, desired.Name)
+	if err != nil {
+		return cloud.FirewallPlan{}, err
+	}
+	if existing == nil {
+		return cloud.FirewallPlan{ToCreate: []cloud.FirewallSpec{desired}}, nil
+	}
+
+	drift := cloud.FirewallDrift(firewallToSpec(existing), desired)
+	if len(drift) == 0 {
+		return cloud.FirewallPlan{}, nil
+	}
+
+	return cloud.FirewallPlan{
+		ToUpdate: []cloud.FirewallChange{{
+			ID:      existing.ID,
+			Desired: desired,
+			Drift:   drift,
+		}},
+	}, nil
+}
+
+// ApplyFirewall creates and updates cloud firewalls as planned. Creating a
+// firewall that already exists updates it instead, so re-running is safe.
+//
+//nolint:gocritic // hugeParam: opts matches interface signature
+func (p *DigitalOceanProvider) ApplyFirewall(ctx context.Context, opts cloud.FirewallApplyOptions) error {
+	config, err := parseConfig(opts.Config)
+	if err != nil {
+		return err
+	}
+
+	token, ok := os.LookupEnv(config.TokenEnv)
+	if !ok || token == "" {
+		return fmt.Errorf("%w: API token missing from environment variable %s", ErrTokenMissing, config.TokenEnv)
+	}
+	p = p.connect(config, token)
+
+	for _, spec := range opts.Plan.ToCreate {
+		existing, err := p.findFirewall(ctx, spec.Name)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			if _, err := p.client.UpdateFirewall(ctx, existing.ID, specToRequest(spec)); err != nil {
+				return fmt.Errorf("%w: updating firewall %q: %v", ErrFirewallApplyFailed, spec.Name, err)
+			}
+			continue
+		}
+
+		if _, err := p.client.CreateFirewall(ctx, specToRequest(spec)); err != nil {
+			return fmt.Errorf("%w: creating firewall %q: %v", ErrFirewallApplyFailed, spec.Name, err)
+		}
+	}
+
+	for _, change := range opts.Plan.ToUpdate {
+		if _, err := p.client.UpdateFirewall(ctx, change.ID, specToRequest(change.Desired)); err != nil {
+			return fmt.Errorf("%w: updating firewall %q: %v", ErrFirewallApplyFailed, change.Desired.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// findFirewall returns the firewall with the given name, or nil if none exists.
+func (p *DigitalOceanProvider) findFirewall(ctx context.Context, name string) (*Firewall, error) {
+	firewalls, err := p.client.ListFirewalls(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAPIError, err)
+	}
+	for i := range firewalls {
+		if firewalls[i].Name == name {
+			return &firewalls[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// firewallToSpec converts an API firewall into the provider-neutral spec.
+func firewallToSpec(fw *Firewall) cloud.FirewallSpec {
+	return cloud.FirewallSpec{
+		Name:     fw.Name,
+		Tags:     fw.Tags,
+		Inbound:  rulesFromAPI(fw.InboundRules),
+		Outbound: rulesFromAPI(fw.OutboundRules),
+	}
+}
+
+// specToRequest converts a provider-neutral spec into an API request.
+func specToRequest(spec cloud.FirewallSpec) FirewallRequest {
+	return FirewallRequest{
+		Name:          spec.Name,
+		Tags:          spec.Tags,
+		InboundRules:  rulesToAPI(spec.Inbound),
+		OutboundRules: rulesToAPI(spec.Outbound),
+	}
+}
+
+// rulesFromAPI maps DigitalOcean's "0" (all ports) to "all".
+func rulesFromAPI(rules []FirewallRule) []cloud.FirewallRule {
+	out := make([]cloud.FirewallRule, 0, len(rules))
+	for _, r := range rules {
+		ports := r.Ports
+		if ports == "0" {
+			ports = "all"
+		}
+		out = append(out, cloud.FirewallRule{Protocol: r.Protocol, Ports: ports, Addresses: r.Addresses})
+	}
+	return out
+}
+
+// rulesToAPI maps "all" ports to DigitalOcean's "0".
+func rulesToAPI(rules []cloud.FirewallRule) []FirewallRule {
+	out := make([]FirewallRule, 0, len(rules))
+	for _, r := range rules {
+		ports := r.Ports
+		if ports == "all" {
+			ports = "0"
+		}
+		out = append(out, FirewallRule{Protocol: r.Protocol, Ports: ports, Addresses: r.Addresses})
+	}
+	return out
+}
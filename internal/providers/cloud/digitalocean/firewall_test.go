@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_FIREWALL
+// Spec: spec/providers/cloud/firewall.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+func firewallTestConfig() map[string]any {
+	return map[string]any{
+		"token_env":    "DO_TOKEN",
+		"ssh_key_name": "my-ssh-key",
+		"hosts": map[string]any{
+			"staging": map[string]any{
+				"app-1": map[string]any{"role": "app"},
+			},
+		},
+		"firewall": map[string]any{
+			"ssh_sources": []string{"203.0.113.0/24"},
+		},
+	}
+}
+
+func TestDigitalOceanProvider_PlanFirewall_CreatesWhenMissing(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	provider := NewDigitalOceanProviderWithClient(&mockAPIClient{})
+	plan, err := provider.PlanFirewall(context.Background(), cloud.FirewallPlanOptions{
+		Config:      firewallTestConfig(),
+		Environment: "staging",
+	})
+	if err != nil {
+		t.Fatalf("PlanFirewall() failed: %v", err)
+	}
+
+	if len(plan.ToCreate) != 1 || len(plan.ToUpdate) != 0 {
+		t.Fatalf("expected one firewall to create, got %+v", plan)
+	}
+
+	spec := plan.ToCreate[0]
+	if spec.Name != "stagecraft-staging" {
+		t.Errorf("spec.Name = %q, want %q", spec.Name, "stagecraft-staging")
+	}
+	if len(spec.Tags) != 1 || spec.Tags[0] != "stagecraft-env-staging" {
+		t.Errorf("spec.Tags = %v, want [stagecraft-env-staging]", spec.Tags)
+	}
+
+	keys := make([]string, len(spec.Inbound))
+	for i, r := range spec.Inbound {
+		keys[i] = r.Key()
+		if r.Key() == "tcp/22" && strings.Join(r.Addresses, ",") != "203.0.113.0/24" {
+			t.Errorf("ssh sources = %v, want [203.0.113.0/24]", r.Addresses)
+		}
+	}
+	if got, want := strings.Join(keys, ","), "tcp/22,tcp/443,tcp/80,udp/41641"; got != want {
+		t.Errorf("inbound rules = %s, want %s", got, want)
+	}
+}
+
+func TestDigitalOceanProvider_ApplyFirewall_ThenPlanIsClean(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	ctx := context.Background()
+	mockClient := &mockAPIClient{}
+	provider := NewDigitalOceanProviderWithClient(mockClient)
+	cfg := firewallTestConfig()
+
+	plan, err := provider.PlanFirewall(ctx, cloud.FirewallPlanOptions{Config: cfg, Environment: "staging"})
+	if err != nil {
+		t.Fatalf("PlanFirewall() failed: %v", err)
+	}
+	if err := provider.ApplyFirewall(ctx, cloud.FirewallApplyOptions{Config: cfg, Environment: "staging", Plan: plan}); err != nil {
+		t.Fatalf("ApplyFirewall() failed: %v", err)
+	}
+
+	if len(mockClient.createdFirewalls) != 1 {
+		t.Fatalf("expected one firewall created, got %d", len(mockClient.createdFirewalls))
+	}
+	for _, r := range mockClient.createdFirewalls[0].OutboundRules {
+		if r.Protocol != "icmp" && r.Ports != "0" {
+			t.Errorf("outbound %s ports = %q, want %q", r.Protocol, r.Ports, "0")
+		}
+	}
+
+	again, err := provider.PlanFirewall(ctx, cloud.FirewallPlanOptions{Config: cfg, Environment: "staging"})
+	if err != nil {
+		t.Fatalf("PlanFirewall() failed: %v", err)
+	}
+	if again.HasChanges() {
+		t.Fatalf("expected no changes after apply, got %+v", again)
+	}
+
+	// Applying a stale create plan updates the existing firewall instead of duplicating it.
+	if err := provider.ApplyFirewall(ctx, cloud.FirewallApplyOptions{Config: cfg, Environment: "staging", Plan: plan}); err != nil {
+		t.Fatalf("ApplyFirewall() failed: %v", err)
+	}
+	if len(mockClient.firewalls) != 1 {
+		t.Fatalf("expected one firewall after re-apply, got %d", len(mockClient.firewalls))
+	}
+}
+
+func TestDigitalOceanProvider_PlanFirewall_DetectsDrift(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	ctx := context.Background()
+	mockClient := &mockAPIClient{
+		firewalls: []Firewall{{
+			ID:   "fw-1",
+			Name: "stagecraft-staging",
+			Tags: []string{"stagecraft-env-staging"},
+			InboundRules: []FirewallRule{
+				{Protocol: "tcp", Ports: "22", Addresses: []string{"0.0.0.0/0", "::/0"}},
+				{Protocol: "tcp", Ports: "80", Addresses: []string{"0.0.0.0/0", "::/0"}},
+				{Protocol: "tcp", Ports: "443", Addresses: []string{"0.0.0.0/0", "::/0"}},
+				{Protocol: "udp", Ports: "41641", Addresses: []string{"0.0.0.0/0", "::/0"}},
+				{Protocol: "tcp", Ports: "5432", Addresses: []string{"0.0.0.0/0"}},
+			},
+			OutboundRules: []FirewallRule{
+				{Protocol: "icmp", Addresses: []string{"0.0.0.0/0", "::/0"}},
+				{Protocol: "tcp", Ports: "0", Addresses: []string{"0.0.0.0/0", "::/0"}},
+				{Protocol: "udp", Ports: "0", Addresses: []string{"0.0.0.0/0", "::/0"}},
+			},
+		}},
+	}
+	provider := NewDigitalOceanProviderWithClient(mockClient)
+	cfg := firewallTestConfig()
+
+	plan, err := provider.PlanFirewall(ctx, cloud.FirewallPlanOptions{Config: cfg, Environment: "staging"})
+	if err != nil {
+		t.Fatalf("PlanFirewall() failed: %v", err)
+	}
+	if len(plan.ToCreate) != 0 || len(plan.ToUpdate) != 1 {
+		t.Fatalf("expected one firewall to update, got %+v", plan)
+	}
+
+	drift := strings.Join(plan.ToUpdate[0].Drift, "\n")
+	for _, want := range []string{
+		"inbound tcp/22: addresses 0.0.0.0/0, ::/0 -> 203.0.113.0/24",
+		"inbound tcp/5432: unexpected (allows 0.0.0.0/0)",
+	} {
+		if !strings.Contains(drift, want) {
+			t.Errorf("expected drift %q, got:\n%s", want, drift)
+		}
+	}
+
+	if err := provider.ApplyFirewall(ctx, cloud.FirewallApplyOptions{Config: cfg, Environment: "staging", Plan: plan}); err != nil {
+		t.Fatalf("ApplyFirewall() failed: %v", err)
+	}
+	if _, ok := mockClient.updatedFirewalls["fw-1"]; !ok {
+		t.Fatalf("expected fw-1 to be updated")
+	}
+}
+
+func TestDigitalOceanProvider_PlanFirewall_Disabled(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	cfg := firewallTestConfig()
+	cfg["firewall"] = map[string]any{"enabled": false}
+
+	provider := NewDigitalOceanProviderWithClient(&mockAPIClient{})
+	plan, err := provider.PlanFirewall(context.Background(), cloud.FirewallPlanOptions{Config: cfg, Environment: "staging"})
+	if err != nil {
+		t.Fatalf("PlanFirewall() failed: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Fatalf("expected no changes when firewall is disabled, got %+v", plan)
+	}
+}
+
+func TestDigitalOceanProvider_PlanFirewall_InvalidRule(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	cfg := firewallTestConfig()
+	cfg["firewall"] = map[string]any{
+		"inbound": []any{map[string]any{"protocol": "sctp", "ports": "9000"}},
+	}
+
+	provider := NewDigitalOceanProviderWithClient(&mockAPIClient{})
+	_, err := provider.PlanFirewall(context.Background(), cloud.FirewallPlanOptions{Config: cfg, Environment: "staging"})
+	if !errors.Is(err, ErrConfigInvalid) {
+		t.Fatalf("expected ErrConfigInvalid, got %v", err)
+	}
+}
+
+func TestDigitalOceanProvider_ApplyFirewall_APIError(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	provider := NewDigitalOceanProviderWithClient(&mockAPIClient{firewallErr: errors.New("boom")})
+	err := provider.ApplyFirewall(context.Background(), cloud.FirewallApplyOptions{
+		Config:      firewallTestConfig(),
+		Environment: "staging",
+		Plan: cloud.FirewallPlan{
+			ToUpdate: []cloud.FirewallChange{{ID: "fw-1", Desired: cloud.FirewallSpec{Name: "stagecraft-staging"}}},
+		},
+	})
+	if !errors.Is(err, ErrFirewallApplyFailed) {
+		t.Fatalf("expected ErrFirewallApplyFailed, got %v", err)
+	}
+}
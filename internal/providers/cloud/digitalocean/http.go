@@ -0,0 +1,423 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_DO
+// Spec: spec/providers/cloud/digitalocean.md
+
+package digitalocean
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAPIBaseURL is the DigitalOcean API endpoint.
+const DefaultAPIBaseURL = "https://api.digitalocean.com"
+
+// listPageSize is the page size requested from list endpoints, the API's
+// maximum.
+const listPageSize = 200
+
+// Account is the subset of the DigitalOcean account Stagecraft inspects.
+type Account struct {
+	Email  string `json:"email"`
+	Status string `json:"status"` // "active", "warning" or "locked"
+}
+
+// AccountClient reads the account an API token belongs to.
+type AccountClient interface {
+	// GetAccount returns the token's account.
+	GetAccount(ctx context.Context) (*Account, error)
+}
+
+// RetryPolicy bounds how HTTPAPIClient retries rate-limited (429) and
+// transient server (500, 502, 503, 504) responses.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the wait before the first retry; it doubles with every
+	// further retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps a single wait, including one requested by Retry-After or RateLimit-Reset.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of NewHTTPAPIClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// HTTPAPIClient calls the DigitalOcean HTTP API with a bearer token.
+type HTTPAPIClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Token      string
+	Retry      RetryPolicy
+
+	// sleep waits between attempts; nil waits on a timer. Replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Ensure HTTPAPIClient implements AccountClient
+var _ AccountClient = (*HTTPAPIClient)(nil)
+
+// NewHTTPAPIClient returns a client for the public DigitalOcean API.
+func NewHTTPAPIClient(token string) *HTTPAPIClient {
+	return &HTTPAPIClient{
+		BaseURL:    DefaultAPIBaseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		Token:      token,
+		Retry:      DefaultRetryPolicy,
+	}
+}
+
+// GetAccount returns the account the token belongs to. It is the cheapest
+// authenticated, read-only call the API offers.
+func (c *HTTPAPIClient) GetAccount(ctx context.Context) (*Account, error) {
+	var resp struct {
+		Account Account `json:"account"`
+	}
+	if err := c.get(ctx, "/v2/account", &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Account, nil
+}
+
+// ListDroplets lists every droplet matching filter, across all pages,
+// sorted by name and then ID. The API filters by the first tag; further
+// tags and the name prefix are matched here.
+func (c *HTTPAPIClient) ListDroplets(ctx context.Context, filter DropletFilter) ([]Droplet, error) {
+	query := url.Values{}
+	if len(filter.Tags) > 0 {
+		query.Set("tag_name", filter.Tags[0])
+	}
+
+	listed, err := listAll[apiDroplet](ctx, c, "/v2/droplets", query, "droplets")
+	if err != nil {
+		return nil, err
+	}
+
+	droplets := make([]Droplet, 0, len(listed))
+	for _, d := range listed {
+		if !strings.HasPrefix(d.Name, filter.NamePrefix) {
+			continue
+		}
+		if !hasAllTags(d.Tags, filter.Tags) {
+			continue
+		}
+		droplets = append(droplets, d.droplet())
+	}
+	sort.Slice(droplets, func(i, j int) bool {
+		if droplets[i].Name != droplets[j].Name {
+			return droplets[i].Name < droplets[j].Name
+		}
+		return droplets[i].ID < droplets[j].ID
+	})
+	return droplets, nil
+}
+
+// ListSSHKeys lists every SSH key in the account, across all pages, sorted
+// by name and then ID.
+func (c *HTTPAPIClient) ListSSHKeys(ctx context.Context) ([]SSHKey, error) {
+	keys, err := listAll[SSHKey](ctx, c, "/v2/account/keys", nil, "ssh_keys")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Name != keys[j].Name {
+			return keys[i].Name < keys[j].Name
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys, nil
+}
+
+// GetSSHKey returns the account's SSH key named name. It lists all pages,
+// so keys beyond the first page are found.
+func (c *HTTPAPIClient) GetSSHKey(ctx context.Context, name string) (*SSHKey, error) {
+	keys, err := c.ListSSHKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range keys {
+		if keys[i].Name == name {
+			return &keys[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSSHKeyNotFound, name)
+}
+
+// apiDroplet is a droplet as the API returns it; region and size are
+// objects there.
+type apiDroplet struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Region struct {
+		Slug string `json:"slug"`
+	} `json:"region"`
+	SizeSlug string   `json:"size_slug"`
+	Tags     []string `json:"tags"`
+	Networks Networks `json:"networks"`
+}
+
+func (d apiDroplet) droplet() Droplet {
+	return Droplet{
+		ID:       d.ID,
+		Name:     d.Name,
+		Region:   d.Region.Slug,
+		Size:     d.SizeSlug,
+		Status:   d.Status,
+		Tags:     d.Tags,
+		Networks: d.Networks,
+	}
+}
+
+// hasAllTags reports whether tags contains every tag in want.
+func hasAllTags(tags, want []string) bool {
+	for _, tag := range want {
+		if !slices.Contains(tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// listAll fetches every page of a list endpoint and returns the items
+// under key, in the order the API returned them. It stops when the
+// response links to no next page.
+func listAll[T any](ctx context.Context, c *HTTPAPIClient, path string, query url.Values, key string) ([]T, error) {
+	var all []T
+	for page := 1; ; page++ {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", strconv.Itoa(listPageSize))
+
+		var resp map[string]json.RawMessage
+		if err := c.get(ctx, path+"?"+q.Encode(), &resp); err != nil {
+			return nil, err
+		}
+
+		var items []T
+		if raw, ok := resp[key]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, fmt.Errorf("%w: GET %s: decoding %s: %v", ErrAPIError, path, key, err)
+			}
+		}
+		all = append(all, items...)
+
+		var links struct {
+			Pages struct {
+				Next string `json:"next"`
+			} `json:"pages"`
+		}
+		if raw, ok := resp["links"]; ok {
+			_ = json.Unmarshal(raw, &links)
+		}
+		// An empty page ends the listing even if the API links a next one.
+		if links.Pages.Next == "" || len(items) == 0 {
+			return all, nil
+		}
+	}
+}
+
+// get sends a GET request and decodes the JSON response into out.
+func (c *HTTPAPIClient) get(ctx context.Context, path string, out any) error {
+	return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// do sends a request with in, when not nil, as its JSON body and decodes
+// the JSON response into out, when not nil. Rate-limited responses are
+// retried as c.Retry allows, honoring Retry-After and RateLimit-Reset;
+// transient server responses are retried too, except for POSTs, which
+// may have been applied. Rejected tokens (401, 403) fail with ErrTokenRejected,
+// transport failures with ErrAPIUnreachable.
+func (c *HTTPAPIClient) do(ctx context.Context, method, path string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("%w: %s %s: encoding request: %v", ErrAPIError, method, path, err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		status, header, respBody, err := c.send(ctx, method, path, body)
+		if err != nil {
+			return err
+		}
+
+		retryable := status == http.StatusTooManyRequests || (method != http.MethodPost && retryableStatus(status))
+		if !retryable || attempt >= c.Retry.MaxAttempts {
+			return decodeResponse(method, path, status, respBody, out)
+		}
+
+		delay := retryDelay(c.Retry, attempt, retryAfter(status, header), time.Now())
+		if err := c.wait(ctx, delay); err != nil {
+			return fmt.Errorf("%s %s: waiting to retry after status %d: %w", method, path, status, err)
+		}
+	}
+}
+
+// send performs a single request.
+func (c *HTTPAPIClient) send(ctx context.Context, method, path string, body []byte) (int, http.Header, []byte, error) {
+	var reqBody io.Reader = http.NoBody
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reqBody)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("%w: building request: %v", ErrAPIError, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("User-Agent", "stagecraft")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("%w: %s %s: %v", ErrAPIUnreachable, method, path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("%w: %s %s: reading response: %v", ErrAPIUnreachable, method, path, err)
+	}
+	return resp.StatusCode, resp.Header, respBody, nil
+}
+
+// errNotFound marks 404 responses, which callers map to their resource's
+// not-found error.
+var errNotFound = errors.New("not found")
+
+// decodeResponse maps a final response to an error or decodes its body.
+// Any 2xx status is a success; bodies of 204 responses are not decoded.
+func decodeResponse(method, path string, status int, body []byte, out any) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return fmt.Errorf("%w: %s %s: status %d", ErrTokenRejected, method, path, status)
+	case status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s %s", ErrRateLimit, method, path)
+	case status == http.StatusNotFound:
+		return fmt.Errorf("%w: %s %s: %w", ErrAPIError, method, path, errNotFound)
+	case status < 200 || status > 299:
+		return fmt.Errorf("%w: %s %s: unexpected status %d%s", ErrAPIError, method, path, status, apiMessage(body))
+	}
+
+	if out == nil || status == http.StatusNoContent {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%w: %s %s: decoding response: %v", ErrAPIError, method, path, err)
+	}
+	return nil
+}
+
+// apiMessage returns ": <message>" for an API error body, or "".
+func apiMessage(body []byte) string {
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &apiErr) != nil || apiErr.Message == "" {
+		return ""
+	}
+	return ": " + apiErr.Message
+}
+
+// wait sleeps for d or until ctx is done.
+func (c *HTTPAPIClient) wait(ctx context.Context, d time.Duration) error {
+	if c.sleep != nil {
+		return c.sleep(ctx, d)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryableStatus reports whether a response status is worth retrying.
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the server's requested wait: Retry-After when set,
+// otherwise for a 429 the time DigitalOcean's RateLimit-Reset header (a
+// Unix timestamp) names as an HTTP date.
+func retryAfter(status int, header http.Header) string {
+	if v := header.Get("Retry-After"); v != "" {
+		return v
+	}
+	if status != http.StatusTooManyRequests {
+		return ""
+	}
+	reset, err := strconv.ParseInt(header.Get("RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return ""
+	}
+	return time.Unix(reset, 0).UTC().Format(http.TimeFormat)
+}
+
+// retryDelay returns the wait before retry number attempt (1-based): the
+// Retry-After value (seconds or an HTTP date) when present, otherwise
+// BaseDelay doubled per earlier retry, capped at MaxDelay.
+func retryDelay(policy RetryPolicy, attempt int, retryAfter string, now time.Time) time.Duration {
+	delay := policy.BaseDelay
+	for i := 1; i < attempt && (policy.MaxDelay <= 0 || delay < policy.MaxDelay); i++ {
+		delay *= 2
+	}
+
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(retryAfter); err == nil {
+		delay = max(at.Sub(now), 0)
+	}
+
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	return delay
+}
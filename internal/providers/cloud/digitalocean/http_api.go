@@ -0,0 +1,462 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+// Feature: PROVIDER_CLOUD_DO
+// Spec: spec/providers/cloud/digitalocean.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Ensure HTTPAPIClient implements APIClient
+var _ APIClient = (*HTTPAPIClient)(nil)
+
+// Droplet and action polling. Waits are also bounded by the caller's
+// context.
+const (
+	pollInterval = 5 * time.Second
+	waitTimeout  = 10 * time.Minute
+)
+
+// GetDroplet returns the droplet named name, or ErrDropletNotFound.
+func (c *HTTPAPIClient) GetDroplet(ctx context.Context, name string) (*Droplet, error) {
+	listed, err := listAll[apiDroplet](ctx, c, "/v2/droplets", url.Values{"name": {name}}, "droplets")
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range listed {
+		if d.Name == name {
+			droplet := d.droplet()
+			return &droplet, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrDropletNotFound, name)
+}
+
+// CreateDroplet creates a droplet. It returns as soon as the API accepts
+// the request; use WaitForDroplet to wait until it is active.
+func (c *HTTPAPIClient) CreateDroplet(ctx context.Context, req CreateDropletRequest) (*Droplet, error) {
+	body := map[string]any{
+		"name":     req.Name,
+		"region":   req.Region,
+		"size":     req.Size,
+		"image":    req.Image,
+		"ssh_keys": req.SSHKeys,
+		"tags":     req.Tags,
+	}
+	if req.UserData != "" {
+		body["user_data"] = req.UserData
+	}
+
+	var resp struct {
+		Droplet apiDroplet `json:"droplet"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/v2/droplets", body, &resp); err != nil {
+		return nil, err
+	}
+	droplet := resp.Droplet.droplet()
+	return &droplet, nil
+}
+
+// DeleteDroplet deletes a droplet by ID. A droplet that does not exist
+// fails with ErrDropletNotFound.
+func (c *HTTPAPIClient) DeleteDroplet(ctx context.Context, id int) error {
+	err := c.do(ctx, http.MethodDelete, "/v2/droplets/"+strconv.Itoa(id), nil, nil)
+	if errors.Is(err, errNotFound) {
+		return fmt.Errorf("%w: %d", ErrDropletNotFound, id)
+	}
+	return err
+}
+
+// TagDroplets adds tag to the droplets, creating the tag first. Creating
+// an existing tag succeeds.
+func (c *HTTPAPIClient) TagDroplets(ctx context.Context, tag string, ids []int) error {
+	if err := c.do(ctx, http.MethodPost, "/v2/tags", map[string]string{"name": tag}, nil); err != nil {
+		return err
+	}
+
+	resources := make([]map[string]string, 0, len(ids))
+	for _, id := range ids {
+		resources = append(resources, map[string]string{"resource_id": strconv.Itoa(id), "resource_type": "droplet"})
+	}
+	return c.do(ctx, http.MethodPost, "/v2/tags/"+url.PathEscape(tag)+"/resources", map[string]any{"resources": resources}, nil)
+}
+
+// RenameDroplet renames a droplet and waits for the rename to complete.
+func (c *HTTPAPIClient) RenameDroplet(ctx context.Context, id int, name string) error {
+	return c.dropletAction(ctx, id, map[string]any{"type": "rename", "name": name})
+}
+
+// ResizeDroplet powers a droplet off, resizes its CPU and memory and
+// powers it back on, waiting for each step. The disk is left alone.
+func (c *HTTPAPIClient) ResizeDroplet(ctx context.Context, id int, size string) error {
+	steps := []map[string]any{
+		{"type": "power_off"},
+		{"type": "resize", "size": size, "disk": false},
+		{"type": "power_on"},
+	}
+	for _, step := range steps {
+		if err := c.dropletAction(ctx, id, step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WaitForDroplet polls a droplet until its status is status. The status
+// "deleted" waits until the droplet no longer exists.
+func (c *HTTPAPIClient) WaitForDroplet(ctx context.Context, id int, status string) error {
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+
+	path := "/v2/droplets/" + strconv.Itoa(id)
+	for {
+		var resp struct {
+			Droplet apiDroplet `json:"droplet"`
+		}
+		err := c.get(ctx, path, &resp)
+		switch {
+		case errors.Is(err, errNotFound) && status == "deleted":
+			return nil
+		case err != nil:
+			return c.waitError(ctx, err, "droplet %d", id)
+		case resp.Droplet.Status == status:
+			return nil
+		}
+
+		if err := c.wait(ctx, pollInterval); err != nil {
+			return c.waitError(ctx, err, "droplet %d to become %s", id, status)
+		}
+	}
+}
+
+// ListFirewalls lists every cloud firewall in the account, sorted by name.
+func (c *HTTPAPIClient) ListFirewalls(ctx context.Context) ([]Firewall, error) {
+	listed, err := listAll[apiFirewall](ctx, c, "/v2/firewalls", nil, "firewalls")
+	if err != nil {
+		return nil, err
+	}
+	firewalls := make([]Firewall, 0, len(listed))
+	for _, f := range listed {
+		firewalls = append(firewalls, f.firewall())
+	}
+	sort.Slice(firewalls, func(i, j int) bool {
+		return firewalls[i].Name < firewalls[j].Name
+	})
+	return firewalls, nil
+}
+
+// CreateFirewall creates a cloud firewall.
+func (c *HTTPAPIClient) CreateFirewall(ctx context.Context, req FirewallRequest) (*Firewall, error) {
+	var resp struct {
+		Firewall apiFirewall `json:"firewall"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/v2/firewalls", newAPIFirewall(req), &resp); err != nil {
+		return nil, err
+	}
+	firewall := resp.Firewall.firewall()
+	return &firewall, nil
+}
+
+// UpdateFirewall replaces the rules and tags of a cloud firewall.
+func (c *HTTPAPIClient) UpdateFirewall(ctx context.Context, id string, req FirewallRequest) (*Firewall, error) {
+	var resp struct {
+		Firewall apiFirewall `json:"firewall"`
+	}
+	if err := c.do(ctx, http.MethodPut, "/v2/firewalls/"+url.PathEscape(id), newAPIFirewall(req), &resp); err != nil {
+		return nil, err
+	}
+	firewall := resp.Firewall.firewall()
+	return &firewall, nil
+}
+
+// ListReservedIPs lists every reserved IP in the account, sorted by IP.
+func (c *HTTPAPIClient) ListReservedIPs(ctx context.Context) ([]ReservedIP, error) {
+	listed, err := listAll[apiReservedIP](ctx, c, "/v2/reserved_ips", nil, "reserved_ips")
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]ReservedIP, 0, len(listed))
+	for _, ip := range listed {
+		ips = append(ips, ip.reservedIP())
+	}
+	sort.Slice(ips, func(i, j int) bool {
+		return ips[i].IP < ips[j].IP
+	})
+	return ips, nil
+}
+
+// CreateReservedIP reserves an unassigned IP in region.
+func (c *HTTPAPIClient) CreateReservedIP(ctx context.Context, region string) (*ReservedIP, error) {
+	var resp struct {
+		ReservedIP apiReservedIP `json:"reserved_ip"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/v2/reserved_ips", map[string]string{"region": region}, &resp); err != nil {
+		return nil, err
+	}
+	ip := resp.ReservedIP.reservedIP()
+	return &ip, nil
+}
+
+// AssignReservedIP assigns a reserved IP to a droplet and waits for the
+// assignment to complete.
+func (c *HTTPAPIClient) AssignReservedIP(ctx context.Context, ip string, dropletID int) error {
+	var resp struct {
+		Action apiAction `json:"action"`
+	}
+	body := map[string]any{"type": "assign", "droplet_id": dropletID}
+	if err := c.do(ctx, http.MethodPost, "/v2/reserved_ips/"+url.PathEscape(ip)+"/actions", body, &resp); err != nil {
+		return err
+	}
+	return c.waitForAction(ctx, resp.Action)
+}
+
+// ListVolumes lists every block storage volume in the account, sorted by
+// name.
+func (c *HTTPAPIClient) ListVolumes(ctx context.Context) ([]Volume, error) {
+	listed, err := listAll[apiVolume](ctx, c, "/v2/volumes", nil, "volumes")
+	if err != nil {
+		return nil, err
+	}
+	volumes := make([]Volume, 0, len(listed))
+	for _, v := range listed {
+		volumes = append(volumes, v.volume())
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Name < volumes[j].Name
+	})
+	return volumes, nil
+}
+
+// CreateVolume creates a block storage volume.
+func (c *HTTPAPIClient) CreateVolume(ctx context.Context, req CreateVolumeRequest) (*Volume, error) {
+	body := map[string]any{
+		"name":           req.Name,
+		"region":         req.Region,
+		"size_gigabytes": req.SizeGigaBytes,
+		"tags":           req.Tags,
+	}
+	if req.FilesystemType != "" {
+		body["filesystem_type"] = req.FilesystemType
+	}
+
+	var resp struct {
+		Volume apiVolume `json:"volume"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/v2/volumes", body, &resp); err != nil {
+		return nil, err
+	}
+	volume := resp.Volume.volume()
+	return &volume, nil
+}
+
+// AttachVolume attaches a volume to a droplet and waits for the attach to
+// complete.
+func (c *HTTPAPIClient) AttachVolume(ctx context.Context, volumeID string, dropletID int) error {
+	var resp struct {
+		Action apiAction `json:"action"`
+	}
+	body := map[string]any{"type": "attach", "droplet_id": dropletID}
+	if err := c.do(ctx, http.MethodPost, "/v2/volumes/"+url.PathEscape(volumeID)+"/actions", body, &resp); err != nil {
+		return err
+	}
+	return c.waitForAction(ctx, resp.Action)
+}
+
+// ListSizes lists every droplet size, sorted by slug.
+func (c *HTTPAPIClient) ListSizes(ctx context.Context) ([]Size, error) {
+	sizes, err := listAll[Size](ctx, c, "/v2/sizes", nil, "sizes")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		return sizes[i].Slug < sizes[j].Slug
+	})
+	return sizes, nil
+}
+
+// apiAction is an asynchronous operation the API reports progress on.
+type apiAction struct {
+	ID     int    `json:"id"`
+	Type   string `json:"type"`
+	Status string `json:"status"` // "in-progress", "completed" or "errored"
+}
+
+// dropletAction starts a droplet action and waits for it to complete.
+func (c *HTTPAPIClient) dropletAction(ctx context.Context, id int, body map[string]any) error {
+	var resp struct {
+		Action apiAction `json:"action"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/v2/droplets/"+strconv.Itoa(id)+"/actions", body, &resp); err != nil {
+		if errors.Is(err, errNotFound) {
+			return fmt.Errorf("%w: %d", ErrDropletNotFound, id)
+		}
+		return err
+	}
+	return c.waitForAction(ctx, resp.Action)
+}
+
+// waitForAction polls an action until it completes.
+func (c *HTTPAPIClient) waitForAction(ctx context.Context, action apiAction) error {
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+
+	path := "/v2/actions/" + strconv.Itoa(action.ID)
+	for {
+		switch action.Status {
+		case "completed":
+			return nil
+		case "errored":
+			return fmt.Errorf("%w: %s action %d errored", ErrAPIError, action.Type, action.ID)
+		}
+
+		if err := c.wait(ctx, pollInterval); err != nil {
+			return c.waitError(ctx, err, "%s action %d", action.Type, action.ID)
+		}
+
+		var resp struct {
+			Action apiAction `json:"action"`
+		}
+		if err := c.get(ctx, path, &resp); err != nil {
+			return c.waitError(ctx, err, "%s action %d", action.Type, action.ID)
+		}
+		action = resp.Action
+	}
+}
+
+// waitError reports a failed wait, as ErrDropletTimeout once the wait
+// timed out.
+func (c *HTTPAPIClient) waitError(ctx context.Context, err error, format string, args ...any) error {
+	what := fmt.Sprintf(format, args...)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: waiting for %s after %s", ErrDropletTimeout, what, waitTimeout)
+	}
+	return fmt.Errorf("waiting for %s: %w", what, err)
+}
+
+// apiFirewall is a cloud firewall as the API encodes it; rule addresses
+// are nested under sources and destinations there.
+type apiFirewall struct {
+	ID            string            `json:"id,omitempty"`
+	Name          string            `json:"name"`
+	Tags          []string          `json:"tags"`
+	InboundRules  []apiFirewallRule `json:"inbound_rules"`
+	OutboundRules []apiFirewallRule `json:"outbound_rules"`
+}
+
+type apiFirewallRule struct {
+	Protocol     string               `json:"protocol"`
+	Ports        string               `json:"ports,omitempty"`
+	Sources      *apiFirewallAddrList `json:"sources,omitempty"`
+	Destinations *apiFirewallAddrList `json:"destinations,omitempty"`
+}
+
+type apiFirewallAddrList struct {
+	Addresses []string `json:"addresses"`
+}
+
+func newAPIFirewall(req FirewallRequest) apiFirewall {
+	f := apiFirewall{Name: req.Name, Tags: req.Tags}
+	for _, r := range req.InboundRules {
+		f.InboundRules = append(f.InboundRules, apiFirewallRule{
+			Protocol: r.Protocol,
+			Ports:    r.Ports,
+			Sources:  &apiFirewallAddrList{Addresses: r.Addresses},
+		})
+	}
+	for _, r := range req.OutboundRules {
+		f.OutboundRules = append(f.OutboundRules, apiFirewallRule{
+			Protocol:     r.Protocol,
+			Ports:        r.Ports,
+			Destinations: &apiFirewallAddrList{Addresses: r.Addresses},
+		})
+	}
+	return f
+}
+
+func (f apiFirewall) firewall() Firewall {
+	firewall := Firewall{ID: f.ID, Name: f.Name, Tags: f.Tags}
+	for _, r := range f.InboundRules {
+		rule := FirewallRule{Protocol: r.Protocol, Ports: apiPorts(r)}
+		if r.Sources != nil {
+			rule.Addresses = r.Sources.Addresses
+		}
+		firewall.InboundRules = append(firewall.InboundRules, rule)
+	}
+	for _, r := range f.OutboundRules {
+		rule := FirewallRule{Protocol: r.Protocol, Ports: apiPorts(r)}
+		if r.Destinations != nil {
+			rule.Addresses = r.Destinations.Addresses
+		}
+		firewall.OutboundRules = append(firewall.OutboundRules, rule)
+	}
+	return firewall
+}
+
+// apiPorts returns a rule's ports; the API reports "0" for icmp rules,
+// which have none.
+func apiPorts(r apiFirewallRule) string {
+	if r.Protocol == "icmp" {
+		return ""
+	}
+	return r.Ports
+}
+
+// apiReservedIP is a reserved IP as the API returns it; region and
+// droplet are objects there, and droplet is null when unassigned.
+type apiReservedIP struct {
+	IP     string `json:"ip"`
+	Region struct {
+		Slug string `json:"slug"`
+	} `json:"region"`
+	Droplet *struct {
+		ID int `json:"id"`
+	} `json:"droplet"`
+}
+
+func (ip apiReservedIP) reservedIP() ReservedIP {
+	reserved := ReservedIP{IP: ip.IP, Region: ip.Region.Slug}
+	if ip.Droplet != nil {
+		reserved.DropletID = ip.Droplet.ID
+	}
+	return reserved
+}
+
+// apiVolume is a volume as the API returns it; region is an object there.
+type apiVolume struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Region struct {
+		Slug string `json:"slug"`
+	} `json:"region"`
+	SizeGigaBytes int   `json:"size_gigabytes"`
+	DropletIDs    []int `json:"droplet_ids"`
+}
+
+func (v apiVolume) volume() Volume {
+	return Volume{
+		ID:            v.ID,
+		Name:          v.Name,
+		Region:        v.Region.Slug,
+		SizeGigaBytes: v.SizeGigaBytes,
+		DropletIDs:    v.DropletIDs,
+	}
+}
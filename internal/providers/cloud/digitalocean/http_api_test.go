@@ -0,0 +1,314 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+// Feature: PROVIDER_CLOUD_DO
+// Spec: spec/providers/cloud/digitalocean.md
+
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// fakeAPI answers requests by "METHOD /path" and records each request
+// with its decoded body.
+type fakeAPI struct {
+	t        *testing.T
+	mu       sync.Mutex
+	handlers map[string]func(body map[string]any) (int, any)
+	requests []string
+	bodies   []map[string]any
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *HTTPAPIClient) {
+	t.Helper()
+	api := &fakeAPI{t: t, handlers: make(map[string]func(map[string]any) (int, any))}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	return api, pagedClient(srv)
+}
+
+func (f *fakeAPI) handle(route string, status int, resp any) {
+	f.handlers[route] = func(map[string]any) (int, any) { return status, resp }
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	route := r.Method + " " + r.URL.Path
+	var body map[string]any
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		if err := json.Unmarshal(data, &body); err != nil {
+			f.t.Errorf("%s: invalid JSON body: %v", route, err)
+		}
+	}
+	f.requests = append(f.requests, route)
+	f.bodies = append(f.bodies, body)
+
+	handler, ok := f.handlers[route]
+	if !ok {
+		f.t.Errorf("unexpected request %s", route)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	status, resp := handler(body)
+	w.WriteHeader(status)
+	if resp != nil {
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func completedAction(id int, typ string) map[string]any {
+	return map[string]any{"action": map[string]any{"id": id, "type": typ, "status": "completed"}}
+}
+
+func TestHTTPAPIClient_CreateAndWaitForDroplet(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.handle("POST /v2/droplets", http.StatusAccepted, map[string]any{"droplet": apiDropletItem(7, "prod-app-1")})
+	polls := 0
+	api.handlers["GET /v2/droplets/7"] = func(map[string]any) (int, any) {
+		polls++
+		item := apiDropletItem(7, "prod-app-1")
+		if polls == 1 {
+			item["status"] = "new"
+		}
+		return http.StatusOK, map[string]any{"droplet": item}
+	}
+
+	droplet, err := client.CreateDroplet(context.Background(), CreateDropletRequest{
+		Name: "prod-app-1", Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+		SSHKeys: []int{42}, Tags: []string{"stagecraft"}, UserData: "#cloud-config",
+	})
+	if err != nil {
+		t.Fatalf("CreateDroplet() error = %v", err)
+	}
+	if droplet.ID != 7 || droplet.Region != "nyc3" || droplet.Size != "s-1vcpu-1gb" {
+		t.Errorf("CreateDroplet() = %+v", droplet)
+	}
+	if got := api.bodies[0]; got["user_data"] != "#cloud-config" || got["image"] != "ubuntu-22-04-x64" {
+		t.Errorf("create body = %v", got)
+	}
+
+	if err := client.WaitForDroplet(context.Background(), 7, "active"); err != nil {
+		t.Fatalf("WaitForDroplet() error = %v", err)
+	}
+	if polls != 2 {
+		t.Errorf("polls = %d, want 2", polls)
+	}
+}
+
+func TestHTTPAPIClient_DeleteDroplet(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.handle("DELETE /v2/droplets/7", http.StatusNoContent, nil)
+	api.handle("GET /v2/droplets/7", http.StatusNotFound, map[string]any{"id": "not_found", "message": "gone"})
+	api.handle("DELETE /v2/droplets/8", http.StatusNotFound, nil)
+
+	if err := client.DeleteDroplet(context.Background(), 7); err != nil {
+		t.Fatalf("DeleteDroplet() error = %v", err)
+	}
+	if err := client.WaitForDroplet(context.Background(), 7, "deleted"); err != nil {
+		t.Fatalf("WaitForDroplet(deleted) error = %v", err)
+	}
+	if err := client.DeleteDroplet(context.Background(), 8); !errors.Is(err, ErrDropletNotFound) {
+		t.Errorf("DeleteDroplet() of missing droplet error = %v, want ErrDropletNotFound", err)
+	}
+}
+
+func TestHTTPAPIClient_GetDroplet(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.handle("GET /v2/droplets", http.StatusOK, map[string]any{"droplets": []any{apiDropletItem(7, "prod-app-1")}})
+
+	droplet, err := client.GetDroplet(context.Background(), "prod-app-1")
+	if err != nil || droplet.ID != 7 {
+		t.Fatalf("GetDroplet() = %+v, %v", droplet, err)
+	}
+	if _, err := client.GetDroplet(context.Background(), "prod-app-2"); !errors.Is(err, ErrDropletNotFound) {
+		t.Errorf("GetDroplet() of missing droplet error = %v, want ErrDropletNotFound", err)
+	}
+}
+
+func TestHTTPAPIClient_ResizeDropletWaitsForEachAction(t *testing.T) {
+	api, client := newFakeAPI(t)
+	var types []string
+	api.handlers["POST /v2/droplets/7/actions"] = func(body map[string]any) (int, any) {
+		typ, _ := body["type"].(string)
+		types = append(types, typ)
+		return http.StatusCreated, map[string]any{"action": map[string]any{"id": len(types), "type": typ, "status": "in-progress"}}
+	}
+	for i, typ := range []string{"power_off", "resize", "power_on"} {
+		api.handle("GET /v2/actions/"+strconv.Itoa(i+1), http.StatusOK, completedAction(i+1, typ))
+	}
+
+	if err := client.ResizeDroplet(context.Background(), 7, "s-2vcpu-4gb"); err != nil {
+		t.Fatalf("ResizeDroplet() error = %v", err)
+	}
+	if want := []string{"power_off", "resize", "power_on"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("actions = %v, want %v", types, want)
+	}
+	if api.bodies[2]["size"] != "s-2vcpu-4gb" || api.bodies[2]["disk"] != false {
+		t.Errorf("resize body = %v", api.bodies[2])
+	}
+}
+
+func TestHTTPAPIClient_ActionErrored(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.handle("POST /v2/droplets/7/actions", http.StatusCreated,
+		map[string]any{"action": map[string]any{"id": 1, "type": "rename", "status": "errored"}})
+
+	if err := client.RenameDroplet(context.Background(), 7, "prod-app-1"); !errors.Is(err, ErrAPIError) {
+		t.Fatalf("RenameDroplet() error = %v, want ErrAPIError", err)
+	}
+}
+
+func TestHTTPAPIClient_TagDroplets(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.handle("POST /v2/tags", http.StatusCreated, map[string]any{"tag": map[string]any{"name": "stagecraft-project-shop"}})
+	api.handle("POST /v2/tags/stagecraft-project-shop/resources", http.StatusNoContent, nil)
+
+	if err := client.TagDroplets(context.Background(), "stagecraft-project-shop", []int{7}); err != nil {
+		t.Fatalf("TagDroplets() error = %v", err)
+	}
+	want := map[string]any{"resources": []any{map[string]any{"resource_id": "7", "resource_type": "droplet"}}}
+	if !reflect.DeepEqual(api.bodies[1], want) {
+		t.Errorf("tag body = %v, want %v", api.bodies[1], want)
+	}
+}
+
+func TestHTTPAPIClient_Firewalls(t *testing.T) {
+	api, client := newFakeAPI(t)
+	apiFW := map[string]any{
+		"id":   "fw-1",
+		"name": "prod-web",
+		"tags": []string{"stagecraft-env-prod"},
+		"inbound_rules": []any{
+			map[string]any{"protocol": "tcp", "ports": "443", "sources": map[string]any{"addresses": []string{"0.0.0.0/0"}}},
+			map[string]any{"protocol": "icmp", "ports": "0", "sources": map[string]any{"addresses": []string{"0.0.0.0/0"}}},
+		},
+		"outbound_rules": []any{
+			map[string]any{"protocol": "tcp", "ports": "0", "destinations": map[string]any{"addresses": []string{"0.0.0.0/0"}}},
+		},
+	}
+	api.handle("GET /v2/firewalls", http.StatusOK, map[string]any{"firewalls": []any{apiFW}})
+	api.handle("PUT /v2/firewalls/fw-1", http.StatusOK, map[string]any{"firewall": apiFW})
+
+	firewalls, err := client.ListFirewalls(context.Background())
+	if err != nil {
+		t.Fatalf("ListFirewalls() error = %v", err)
+	}
+	want := Firewall{
+		ID:   "fw-1",
+		Name: "prod-web",
+		Tags: []string{"stagecraft-env-prod"},
+		InboundRules: []FirewallRule{
+			{Protocol: "tcp", Ports: "443", Addresses: []string{"0.0.0.0/0"}},
+			{Protocol: "icmp", Addresses: []string{"0.0.0.0/0"}},
+		},
+		OutboundRules: []FirewallRule{{Protocol: "tcp", Ports: "0", Addresses: []string{"0.0.0.0/0"}}},
+	}
+	if len(firewalls) != 1 || !reflect.DeepEqual(firewalls[0], want) {
+		t.Errorf("ListFirewalls() = %+v, want %+v", firewalls, want)
+	}
+
+	req := FirewallRequest{Name: want.Name, Tags: want.Tags, InboundRules: want.InboundRules, OutboundRules: want.OutboundRules}
+	if _, err := client.UpdateFirewall(context.Background(), "fw-1", req); err != nil {
+		t.Fatalf("UpdateFirewall() error = %v", err)
+	}
+	inbound, _ := api.bodies[1]["inbound_rules"].([]any)
+	rule, _ := inbound[0].(map[string]any)
+	if sources, _ := rule["sources"].(map[string]any); sources == nil || rule["destinations"] != nil {
+		t.Errorf("inbound rule should carry sources only, got %v", rule)
+	}
+}
+
+func TestHTTPAPIClient_ReservedIPsAndVolumes(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.handle("GET /v2/reserved_ips", http.StatusOK, map[string]any{"reserved_ips": []any{
+		map[string]any{"ip": "203.0.113.2", "region": map[string]any{"slug": "nyc3"}, "droplet": nil},
+		map[string]any{"ip": "203.0.113.1", "region": map[string]any{"slug": "nyc3"}, "droplet": map[string]any{"id": 7}},
+	}})
+	api.handle("POST /v2/reserved_ips/203.0.113.2/actions", http.StatusCreated, completedAction(1, "assign"))
+	api.handle("GET /v2/volumes", http.StatusOK, map[string]any{"volumes": []any{
+		map[string]any{"id": "vol-1", "name": "prod-db-data", "region": map[string]any{"slug": "nyc3"}, "size_gigabytes": 50, "droplet_ids": []int{7}},
+	}})
+	api.handle("POST /v2/volumes", http.StatusCreated, map[string]any{"volume": map[string]any{"id": "vol-2", "name": "prod-db-logs", "region": map[string]any{"slug": "nyc3"}, "size_gigabytes": 10}})
+	api.handle("POST /v2/volumes/vol-2/actions", http.StatusAccepted, completedAction(2, "attach"))
+
+	ctx := context.Background()
+	ips, err := client.ListReservedIPs(ctx)
+	if err != nil {
+		t.Fatalf("ListReservedIPs() error = %v", err)
+	}
+	want := []ReservedIP{{IP: "203.0.113.1", Region: "nyc3", DropletID: 7}, {IP: "203.0.113.2", Region: "nyc3"}}
+	if !reflect.DeepEqual(ips, want) {
+		t.Errorf("ListReservedIPs() = %+v, want %+v", ips, want)
+	}
+	if err := client.AssignReservedIP(ctx, "203.0.113.2", 8); err != nil {
+		t.Fatalf("AssignReservedIP() error = %v", err)
+	}
+
+	volumes, err := client.ListVolumes(ctx)
+	if err != nil || len(volumes) != 1 || volumes[0].Region != "nyc3" || volumes[0].DropletIDs[0] != 7 {
+		t.Fatalf("ListVolumes() = %+v, %v", volumes, err)
+	}
+	created, err := client.CreateVolume(ctx, CreateVolumeRequest{Name: "prod-db-logs", Region: "nyc3", SizeGigaBytes: 10, FilesystemType: "ext4"})
+	if err != nil || created.ID != "vol-2" {
+		t.Fatalf("CreateVolume() = %+v, %v", created, err)
+	}
+	if err := client.AttachVolume(ctx, "vol-2", 7); err != nil {
+		t.Fatalf("AttachVolume() error = %v", err)
+	}
+}
+
+func TestDigitalOceanProvider_ConnectsHTTPClient(t *testing.T) {
+	t.Setenv("DO_TOKEN_CONNECT", "do-token")
+	items := []map[string]any{
+		apiDropletItem(3, "prod-app-2", "stagecraft", "stagecraft-env-prod"),
+		apiDropletItem(1, "prod-app-1", "stagecraft", "stagecraft-env-prod"),
+	}
+	srv, _ := pagedServer(t, "/v2/droplets", "droplets", items, 1)
+
+	provider := NewDigitalOceanProvider()
+	provider.apiBaseURL = srv.URL
+	hosts, err := provider.Hosts(context.Background(), cloud.HostsOptions{
+		Environment: "prod",
+		Config: map[string]any{
+			"token_env":    "DO_TOKEN_CONNECT",
+			"ssh_key_name": "my-ssh-key",
+			"api":          map[string]any{"max_backoff": "1ms"},
+			"hosts": map[string]any{"prod": map[string]any{
+				"app-1": map[string]any{"role": "app"},
+				"app-2": map[string]any{"role": "app"},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Hosts() error = %v", err)
+	}
+	if len(hosts) != 2 || hosts[0].Name != "app-1" || hosts[1].Name != "app-2" {
+		t.Errorf("Hosts() = %+v, want app-1 and app-2 from both pages", hosts)
+	}
+	if provider.client != nil {
+		t.Error("connecting must not modify the registered provider")
+	}
+}
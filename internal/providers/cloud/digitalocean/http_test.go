@@ -0,0 +1,527 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_DO
+// Spec: spec/providers/cloud/digitalocean.md
+
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPAPIClient_GetAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{name: "ok", status: http.StatusOK, body: `{"account":{"email":"ops@example.com","status":"active"}}`},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErr: ErrTokenRejected},
+		{name: "forbidden", status: http.StatusForbidden, wantErr: ErrTokenRejected},
+		{name: "rate limited", status: http.StatusTooManyRequests, wantErr: ErrRateLimit},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: ErrAPIError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v2/account" || r.Header.Get("Authorization") != "Bearer do-token" {
+					t.Errorf("unexpected request %s %q", r.URL.Path, r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := &HTTPAPIClient{BaseURL: srv.URL, HTTPClient: srv.Client(), Token: "do-token"}
+			account, err := client.GetAccount(context.Background())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetAccount() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAccount() error = %v", err)
+			}
+			if account.Email != "ops@example.com" || account.Status != "active" {
+				t.Errorf("GetAccount() = %+v", account)
+			}
+		})
+	}
+}
+
+func TestHTTPAPIClient_GetAccount_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client := &HTTPAPIClient{BaseURL: srv.URL, HTTPClient: srv.Client(), Token: "do-token"}
+	if _, err := client.GetAccount(context.Background()); !errors.Is(err, ErrAPIUnreachable) {
+		t.Fatalf("GetAccount() error = %v, want ErrAPIUnreachable", err)
+	}
+}
+
+// sequenceServer answers successive requests with statuses, then 200 with
+// an account. A status may carry a Retry-After value after a comma.
+func sequenceServer(t *testing.T, responses ...string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		if n >= len(responses) {
+			_, _ = w.Write([]byte(`{"account":{"email":"ops@example.com","status":"active"}}`))
+			return
+		}
+		status, retryAfter, _ := strings.Cut(responses[n], ",")
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		code, _ := strconv.Atoi(status)
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestHTTPAPIClient_RetriesWithBackoff(t *testing.T) {
+	srv, calls := sequenceServer(t, "429,2", "503", "502")
+
+	var delays []time.Duration
+	client := &HTTPAPIClient{
+		BaseURL:    srv.URL,
+		HTTPClient: srv.Client(),
+		Retry:      RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second},
+		sleep: func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		},
+	}
+
+	if _, err := client.GetAccount(context.Background()); err != nil {
+		t.Fatalf("GetAccount() error = %v", err)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("requests = %d, want 4", calls.Load())
+	}
+	// Retry-After is capped at MaxDelay; later waits back off exponentially.
+	want := []time.Duration{time.Second, 200 * time.Millisecond, 400 * time.Millisecond}
+	if len(delays) != len(want) {
+		t.Fatalf("delays = %v, want %v", delays, want)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("delays = %v, want %v", delays, want)
+			break
+		}
+	}
+}
+
+func TestHTTPAPIClient_GivesUpAfterMaxAttempts(t *testing.T) {
+	tests := []struct {
+		status  string
+		wantErr error
+	}{
+		{status: "429", wantErr: ErrRateLimit},
+		{status: "503", wantErr: ErrAPIError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			srv, calls := sequenceServer(t, tt.status, tt.status, tt.status, tt.status)
+			client := &HTTPAPIClient{
+				BaseURL:    srv.URL,
+				HTTPClient: srv.Client(),
+				Retry:      RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+				sleep:      func(context.Context, time.Duration) error { return nil },
+			}
+
+			if _, err := client.GetAccount(context.Background()); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetAccount() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls.Load() != 3 {
+				t.Errorf("requests = %d, want 3", calls.Load())
+			}
+		})
+	}
+}
+
+func TestHTTPAPIClient_DoesNotRetryRejectedToken(t *testing.T) {
+	srv, calls := sequenceServer(t, "401")
+	client := &HTTPAPIClient{BaseURL: srv.URL, HTTPClient: srv.Client(), Retry: DefaultRetryPolicy}
+
+	if _, err := client.GetAccount(context.Background()); !errors.Is(err, ErrTokenRejected) {
+		t.Fatalf("GetAccount() error = %v, want ErrTokenRejected", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("requests = %d, want 1", calls.Load())
+	}
+}
+
+func TestHTTPAPIClient_RetryWaitStopsOnCancel(t *testing.T) {
+	srv, calls := sequenceServer(t, "429,60")
+	client := &HTTPAPIClient{BaseURL: srv.URL, HTTPClient: srv.Client(), Retry: DefaultRetryPolicy}
+
+	// Cancel once the client starts waiting out Retry-After, then wait on
+	// a real timer.
+	ctx, cancel := context.WithCancel(context.Background())
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return (&HTTPAPIClient{}).wait(ctx, d)
+	}
+
+	if _, err := client.GetAccount(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetAccount() error = %v, want context.Canceled", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("requests = %d, want 1", calls.Load())
+	}
+}
+
+// pagedServer serves items under key from path, size per page, linking
+// each page to the next like the DigitalOcean API. The first request for
+// page 2 is rate limited.
+func pagedServer(t *testing.T, path, key string, items []map[string]any, size int) (*httptest.Server, *[]url.Values) {
+	t.Helper()
+	var mu sync.Mutex
+	var queries []url.Values
+	limited := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		queries = append(queries, r.URL.Query())
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 2 && !limited {
+			limited = true
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		start := min((page-1)*size, len(items))
+		end := min(start+size, len(items))
+		resp := map[string]any{key: items[start:end], "links": map[string]any{}}
+		if end < len(items) {
+			resp["links"] = map[string]any{"pages": map[string]any{
+				"next": fmt.Sprintf("%s%s?page=%d&per_page=%d", "https://api.digitalocean.com", path, page+1, size),
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &queries
+}
+
+func pagedClient(srv *httptest.Server) *HTTPAPIClient {
+	return &HTTPAPIClient{
+		BaseURL:    srv.URL,
+		HTTPClient: srv.Client(),
+		Retry:      DefaultRetryPolicy,
+		sleep:      func(context.Context, time.Duration) error { return nil },
+	}
+}
+
+func apiDropletItem(id int, name string, tags ...string) map[string]any {
+	return map[string]any{
+		"id":        id,
+		"name":      name,
+		"status":    "active",
+		"size_slug": "s-1vcpu-1gb",
+		"region":    map[string]any{"slug": "nyc3"},
+		"tags":      tags,
+		"networks":  map[string]any{"v4": []map[string]any{{"ip_address": fmt.Sprintf("10.0.0.%d", id), "type": "public"}}},
+	}
+}
+
+func TestHTTPAPIClient_ListDroplets_Paginates(t *testing.T) {
+	items := []map[string]any{
+		apiDropletItem(7, "prod-web-2", "stagecraft", "env:prod"),
+		apiDropletItem(3, "prod-db-1", "stagecraft", "env:prod"),
+		apiDropletItem(9, "staging-web-1", "stagecraft", "env:staging"),
+		apiDropletItem(5, "prod-web-1", "stagecraft", "env:prod"),
+		apiDropletItem(4, "prod-web-1", "stagecraft", "env:prod"),
+		apiDropletItem(8, "prod-cache-1", "stagecraft"),
+		apiDropletItem(1, "prod-app-1", "stagecraft", "env:prod"),
+	}
+	srv, queries := pagedServer(t, "/v2/droplets", "droplets", items, 2)
+
+	droplets, err := pagedClient(srv).ListDroplets(context.Background(), DropletFilter{
+		NamePrefix: "prod-",
+		Tags:       []string{"stagecraft", "env:prod"},
+	})
+	if err != nil {
+		t.Fatalf("ListDroplets() error = %v", err)
+	}
+
+	var got []string
+	for _, d := range droplets {
+		got = append(got, fmt.Sprintf("%s#%d", d.Name, d.ID))
+	}
+	want := "prod-app-1#1 prod-db-1#3 prod-web-1#4 prod-web-1#5 prod-web-2#7"
+	if strings.Join(got, " ") != want {
+		t.Errorf("ListDroplets() = %v, want %s", got, want)
+	}
+
+	d := droplets[0]
+	if d.Region != "nyc3" || d.Size != "s-1vcpu-1gb" || d.Status != "active" || len(d.Networks.V4) != 1 {
+		t.Errorf("ListDroplets()[0] = %+v", d)
+	}
+
+	// Four pages, plus the rate-limited retry of page 2.
+	if len(*queries) != 5 {
+		t.Fatalf("requests = %d, want 5", len(*queries))
+	}
+	for _, q := range *queries {
+		if q.Get("tag_name") != "stagecraft" || q.Get("per_page") != "200" {
+			t.Errorf("query = %v, want tag_name=stagecraft per_page=200", q)
+		}
+	}
+}
+
+func TestHTTPAPIClient_ListSSHKeys_Paginates(t *testing.T) {
+	items := []map[string]any{
+		{"id": 30, "name": "deploy", "public_key": "ssh-ed25519 C"},
+		{"id": 10, "name": "alice", "public_key": "ssh-ed25519 A"},
+		{"id": 20, "name": "bob", "public_key": "ssh-ed25519 B"},
+	}
+	srv, _ := pagedServer(t, "/v2/account/keys", "ssh_keys", items, 1)
+	client := pagedClient(srv)
+
+	keys, err := client.ListSSHKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ListSSHKeys() error = %v", err)
+	}
+	var got []string
+	for _, k := range keys {
+		got = append(got, k.Name)
+	}
+	if strings.Join(got, ",") != "alice,bob,deploy" {
+		t.Errorf("ListSSHKeys() = %v, want alice,bob,deploy", got)
+	}
+
+	// The last page's key is found by name.
+	key, err := client.GetSSHKey(context.Background(), "deploy")
+	if err != nil {
+		t.Fatalf("GetSSHKey() error = %v", err)
+	}
+	if key.ID != 30 || key.PublicKey != "ssh-ed25519 C" {
+		t.Errorf("GetSSHKey() = %+v", key)
+	}
+	if _, err := client.GetSSHKey(context.Background(), "missing"); !errors.Is(err, ErrSSHKeyNotFound) {
+		t.Errorf("GetSSHKey(missing) error = %v, want ErrSSHKeyNotFound", err)
+	}
+}
+
+func TestHTTPAPIClient_ListDroplets_StopsOnPageError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			_, _ = w.Write([]byte(`{"droplets":[{"id":1,"name":"a"}],"links":{"pages":{"next":"x"}}}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	if _, err := pagedClient(srv).ListDroplets(context.Background(), DropletFilter{}); !errors.Is(err, ErrTokenRejected) {
+		t.Fatalf("ListDroplets() error = %v, want ErrTokenRejected", err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		want       time.Duration
+	}{
+		{name: "first retry", attempt: 1, want: time.Second},
+		{name: "doubles", attempt: 3, want: 4 * time.Second},
+		{name: "capped", attempt: 9, want: 10 * time.Second},
+		{name: "retry-after seconds", attempt: 1, retryAfter: "7", want: 7 * time.Second},
+		{name: "retry-after date", attempt: 1, retryAfter: now.Add(3 * time.Second).Format(http.TimeFormat), want: 3 * time.Second},
+		{name: "retry-after in the past", attempt: 1, retryAfter: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		{name: "retry-after capped", attempt: 1, retryAfter: "120", want: 10 * time.Second},
+		{name: "invalid retry-after", attempt: 2, retryAfter: "soon", want: 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryDelay(policy, tt.attempt, tt.retryAfter, now); got != tt.want {
+				t.Errorf("retryDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	reset := time.Date(2026, 3, 1, 12, 0, 5, 0, time.UTC)
+	header := http.Header{}
+	header.Set("RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+	if got := retryAfter(http.StatusTooManyRequests, header); got != reset.Format(http.TimeFormat) {
+		t.Errorf("retryAfter() = %q, want RateLimit-Reset as an HTTP date", got)
+	}
+	if got := retryAfter(http.StatusServiceUnavailable, header); got != "" {
+		t.Errorf("retryAfter() for a 503 = %q, want none", got)
+	}
+	header.Set("Retry-After", "3")
+	if got := retryAfter(http.StatusTooManyRequests, header); got != "3" {
+		t.Errorf("retryAfter() = %q, want Retry-After to win", got)
+	}
+}
+
+func TestHTTPAPIClient_WritesRetryRateLimits(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"droplet":{"id":7,"name":"prod-app-1"}}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	client := &HTTPAPIClient{
+		BaseURL:    srv.URL,
+		HTTPClient: srv.Client(),
+		Retry:      RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Minute},
+		sleep: func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		},
+	}
+
+	droplet, err := client.CreateDroplet(context.Background(), CreateDropletRequest{Name: "prod-app-1"})
+	if err != nil {
+		t.Fatalf("CreateDroplet() error = %v", err)
+	}
+	if droplet.ID != 7 {
+		t.Errorf("CreateDroplet() = %+v", droplet)
+	}
+	// The wait until RateLimit-Reset is capped at MaxDelay
+	if len(delays) != 1 || delays[0] != time.Minute {
+		t.Errorf("delays = %v, want [1m0s]", delays)
+	}
+
+	// A POST that failed on the server may have been applied
+	if _, err := client.CreateDroplet(context.Background(), CreateDropletRequest{Name: "prod-app-2"}); !errors.Is(err, ErrAPIError) {
+		t.Fatalf("CreateDroplet() error = %v, want ErrAPIError", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("requests = %d, want 3: a POST must not be retried after a 503", calls.Load())
+	}
+}
+
+func TestConfig_RetryPolicy(t *testing.T) {
+	base := map[string]any{
+		"token_env":    "DO_TOKEN",
+		"ssh_key_name": "my-ssh-key",
+		"hosts": map[string]any{
+			"prod": map[string]any{"app-1": map[string]any{"role": "app"}},
+		},
+	}
+
+	config, err := parseConfig(base)
+	if err != nil {
+		t.Fatalf("parseConfig() error = %v", err)
+	}
+	if got := config.retryPolicy(); got != DefaultRetryPolicy {
+		t.Errorf("retryPolicy() = %+v, want DefaultRetryPolicy", got)
+	}
+
+	base["api"] = map[string]any{"max_retries": 0, "max_backoff": "200ms"}
+	config, err = parseConfig(base)
+	if err != nil {
+		t.Fatalf("parseConfig() error = %v", err)
+	}
+	want := RetryPolicy{MaxAttempts: 1, BaseDelay: 200 * time.Millisecond, MaxDelay: 200 * time.Millisecond}
+	if got := config.retryPolicy(); got != want {
+		t.Errorf("retryPolicy() = %+v, want %+v", got, want)
+	}
+
+	base["api"] = map[string]any{"max_retries": -1}
+	if _, err := parseConfig(base); !errors.Is(err, ErrConfigInvalid) {
+		t.Errorf("parseConfig() with negative max_retries error = %v, want ErrConfigInvalid", err)
+	}
+}
+
+type fakeAccountClient struct {
+	account *Account
+	err     error
+}
+
+func (f *fakeAccountClient) GetAccount(context.Context) (*Account, error) {
+	return f.account, f.err
+}
+
+func TestDigitalOceanProvider_VerifyCredentials(t *testing.T) {
+	cfg := map[string]any{
+		"token_env":    "DO_TOKEN_VERIFY",
+		"ssh_key_name": "my-ssh-key",
+		"hosts": map[string]any{
+			"prod": map[string]any{"app-1": map[string]any{"role": "app"}},
+		},
+	}
+
+	provider := NewDigitalOceanProvider()
+	if _, err := provider.VerifyCredentials(context.Background(), cfg); !errors.Is(err, ErrTokenMissing) {
+		t.Fatalf("VerifyCredentials() without token error = %v, want ErrTokenMissing", err)
+	}
+
+	t.Setenv("DO_TOKEN_VERIFY", "do-token")
+	var gotToken string
+	provider.accountClient = func(token string) AccountClient {
+		gotToken = token
+		return &fakeAccountClient{account: &Account{Email: "ops@example.com", Status: "active"}}
+	}
+
+	detail, err := provider.VerifyCredentials(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("VerifyCredentials() error = %v", err)
+	}
+	if gotToken != "do-token" {
+		t.Errorf("account client token = %q, want do-token", gotToken)
+	}
+	if detail != "account ops@example.com (active)" {
+		t.Errorf("VerifyCredentials() = %q", detail)
+	}
+
+	provider.accountClient = func(string) AccountClient { return &fakeAccountClient{err: ErrTokenRejected} }
+	if _, err := provider.VerifyCredentials(context.Background(), cfg); !errors.Is(err, ErrTokenRejected) {
+		t.Errorf("VerifyCredentials() error = %v, want ErrTokenRejected", err)
+	}
+}
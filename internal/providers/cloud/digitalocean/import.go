@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_DO
+// Spec: spec/providers/cloud/digitalocean.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Ensure DigitalOceanProvider implements HostImporter
+var _ cloud.HostImporter = (*DigitalOceanProvider)(nil)
+
+// ImportHost brings an existing droplet under management as a host declared
+// in config. The droplet must match the host's size and region and must not
+// belong to another environment or project. It is renamed to
+// "{env}-{host}" and given the droplet tags, after which Plan keeps it
+// instead of creating a new droplet.
+func (p *DigitalOceanProvider) ImportHost(ctx context.Context, opts cloud.ImportOptions) (cloud.Host, error) {
+	config, err := parseConfig(opts.Config)
+	if err != nil {
+		return cloud.Host{}, err
+	}
+
+	token, ok := os.LookupEnv(config.TokenEnv)
+	if !ok || token == "" {
+		return cloud.Host{}, fmt.Errorf("%w: API token missing from environment variable %s", ErrTokenMissing, config.TokenEnv)
+	}
+	p = p.connect(config, token)
+
+	env := opts.Environment
+	hostCfg, ok := config.Hosts[env][opts.Host]
+	if !ok {
+		return cloud.Host{}, fmt.Errorf("%w: host %q is not declared in hosts.%s", ErrConfigInvalid, opts.Host, env)
+	}
+
+	// The droplet's authorized keys cannot be read through the API, so only
+	// the key hosts are bootstrapped with is checked.
+	if _, err := p.client.GetSSHKey(ctx, config.SSHKeyName); err != nil {
+		if errors.Is(err, ErrSSHKeyNotFound) {
+			return cloud.Host{}, fmt.Errorf("%w: SSH key %q not found in DigitalOcean account", ErrSSHKeyNotFound, config.SSHKeyName)
+		}
+		return cloud.Host{}, fmt.Errorf("%w: %v", ErrAPIError, err)
+	}
+
+	d, err := p.findDroplet(ctx, opts.Resource)
+	if err != nil {
+		return cloud.Host{}, err
+	}
+
+	tags := dropletTags(opts.Project, env)
+	if owner := dropletOwner(d, opts.Project, env); owner != "" {
+		return cloud.Host{}, fmt.Errorf("%w: droplet %q is tagged %s", ErrDropletUnmanaged, d.Name, owner)
+	}
+
+	if err := checkImportSpec(d, desiredHostSpec(config, opts.Host, hostCfg)); err != nil {
+		return cloud.Host{}, err
+	}
+
+	fullName := env + "-" + opts.Host
+	if d.Name != fullName {
+		existing, err := p.client.GetDroplet(ctx, fullName)
+		if err != nil && !errors.Is(err, ErrDropletNotFound) {
+			return cloud.Host{}, fmt.Errorf("%w: %v", ErrAPIError, err)
+		}
+		if existing != nil {
+			return cloud.Host{}, fmt.Errorf("%w: droplet %q already exists for host %q", ErrDropletExists, fullName, opts.Host)
+		}
+
+		if err := p.client.RenameDroplet(ctx, d.ID, fullName); err != nil {
+			return cloud.Host{}, fmt.Errorf("%w: renaming droplet %q to %q: %v", ErrAPIError, d.Name, fullName, err)
+		}
+		d.Name = fullName
+	}
+
+	for _, tag := range tags {
+		if slices.Contains(d.Tags, tag) {
+			continue
+		}
+		if err := p.client.TagDroplets(ctx, tag, []int{d.ID}); err != nil {
+			return cloud.Host{}, fmt.Errorf("%w: tagging droplet %q: %v", ErrAPIError, d.Name, err)
+		}
+		d.Tags = append(d.Tags, tag)
+	}
+
+	return managedHost(opts.Project, env, opts.Host, hostCfg, *d), nil
+}
+
+// findDroplet returns the only droplet named name.
+func (p *DigitalOceanProvider) findDroplet(ctx context.Context, name string) (*Droplet, error) {
+	droplets, err := p.client.ListDroplets(ctx, DropletFilter{NamePrefix: name})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAPIError, err)
+	}
+
+	var found []Droplet
+	for _, d := range droplets {
+		if d.Name == name {
+			found = append(found, d)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%w: no droplet named %q", ErrDropletNotFound, name)
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %d droplets are named %q; rename all but one", ErrConfigInvalid, len(found), name)
+	}
+}
+
+// dropletOwner returns the tags that mark d as belonging to another
+// environment or project, or "" when it belongs to none. A droplet without
+// a project tag belongs to no project.
+func dropletOwner(d *Droplet, project, env string) string {
+	var foreign []string
+	for _, tag := range d.Tags {
+		switch {
+		case strings.HasPrefix(tag, envTag("")) && tag != envTag(env):
+			foreign = append(foreign, tag)
+		case strings.HasPrefix(tag, projectTagPrefix) && (project == "" || tag != projectTag(project)):
+			foreign = append(foreign, tag)
+		}
+	}
+	return strings.Join(foreign, ", ")
+}
+
+// checkImportSpec checks that d has the size and region config declares
+// for the host. An empty desired value matches any droplet.
+func checkImportSpec(d *Droplet, desired cloud.HostSpec) error {
+	var diffs []string
+	if desired.Size != "" && d.Size != desired.Size {
+		diffs = append(diffs, fmt.Sprintf("size %s (config: %s)", d.Size, desired.Size))
+	}
+	if desired.Region != "" && d.Region != desired.Region {
+		diffs = append(diffs, fmt.Sprintf("region %s (config: %s)", d.Region, desired.Region))
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: droplet %q does not match host %q: %s; set the host's size and region to the droplet's, import, then change them with hosts apply",
+		ErrConfigInvalid, d.Name, desired.Name, strings.Join(diffs, ", "))
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_DO
+// Spec: spec/providers/cloud/digitalocean.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+func TestDigitalOceanProvider_ImportHost(t *testing.T) {
+	cfg, client := resizeTestSetup(t, map[string]any{
+		"app-1": map[string]any{"role": "app"},
+	}, map[string]Droplet{
+		"legacy-web": {ID: 7, Name: "legacy-web", Tags: []string{"web"}, Region: "nyc1", Size: "s-2vcpu-4gb", Status: "active",
+			Networks: Networks{V4: []NetworkV4{{IPAddress: "203.0.113.7", Type: "public"}}}},
+	})
+	provider := NewDigitalOceanProviderWithClient(client)
+	opts := cloud.ImportOptions{Config: cfg, Environment: "prod", Project: "shop", Resource: "legacy-web", Host: "app-1"}
+
+	host, err := provider.ImportHost(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("ImportHost() error = %v", err)
+	}
+
+	if host.ID != "7" || host.Name != "app-1" || host.Role != "app" || host.PublicIP != "203.0.113.7" {
+		t.Errorf("ImportHost() = %+v, want droplet 7 as app-1", host)
+	}
+	if want := []string{"legacy-web=prod-app-1"}; !reflect.DeepEqual(client.renamed, want) {
+		t.Errorf("renamed = %v, want %v", client.renamed, want)
+	}
+	wantTags := []string{"web", "stagecraft", "stagecraft-project-shop", "stagecraft-env-prod"}
+	if got := client.droplets["prod-app-1"].Tags; !reflect.DeepEqual(got, wantTags) {
+		t.Errorf("droplet tags = %v, want %v", got, wantTags)
+	}
+
+	// The imported droplet is kept by Plan.
+	plan, err := provider.Plan(context.Background(), cloud.PlanOptions{Config: cfg, Environment: "prod", Project: "shop"})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(plan.ToCreate)+len(plan.ToDelete)+len(plan.ToResize)+len(plan.ToReplace) != 0 {
+		t.Errorf("Plan() after import = %+v, want no host changes", plan)
+	}
+
+	// Importing again under the new name changes nothing.
+	opts.Resource = "prod-app-1"
+	client.renamed, client.tagged = nil, nil
+	if _, err := provider.ImportHost(context.Background(), opts); err != nil {
+		t.Fatalf("second ImportHost() error = %v", err)
+	}
+	if len(client.renamed)+len(client.tagged) != 0 {
+		t.Errorf("second ImportHost() renamed %v and tagged %v, want nothing", client.renamed, client.tagged)
+	}
+}
+
+func TestDigitalOceanProvider_ImportHost_Refuses(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		droplets map[string]Droplet
+		wantErr  error
+	}{
+		{
+			name:    "host not declared",
+			host:    "db-1",
+			wantErr: ErrConfigInvalid,
+		},
+		{
+			name:    "droplet not found",
+			host:    "app-1",
+			wantErr: ErrDropletNotFound,
+		},
+		{
+			name: "size mismatch",
+			host: "app-1",
+			droplets: map[string]Droplet{
+				"legacy-web": {ID: 7, Name: "legacy-web", Region: "nyc1", Size: "s-1vcpu-1gb"},
+			},
+			wantErr: ErrConfigInvalid,
+		},
+		{
+			name: "region mismatch",
+			host: "app-1",
+			droplets: map[string]Droplet{
+				"legacy-web": {ID: 7, Name: "legacy-web", Region: "fra1", Size: "s-2vcpu-4gb"},
+			},
+			wantErr: ErrConfigInvalid,
+		},
+		{
+			name: "other environment",
+			host: "app-1",
+			droplets: map[string]Droplet{
+				"legacy-web": {ID: 7, Name: "legacy-web", Tags: []string{"stagecraft", "stagecraft-env-staging"}, Region: "nyc1", Size: "s-2vcpu-4gb"},
+			},
+			wantErr: ErrDropletUnmanaged,
+		},
+		{
+			name: "other project",
+			host: "app-1",
+			droplets: map[string]Droplet{
+				"legacy-web": {ID: 7, Name: "legacy-web", Tags: []string{"stagecraft-project-blog"}, Region: "nyc1", Size: "s-2vcpu-4gb"},
+			},
+			wantErr: ErrDropletUnmanaged,
+		},
+		{
+			name: "host name taken",
+			host: "app-1",
+			droplets: map[string]Droplet{
+				"legacy-web": {ID: 7, Name: "legacy-web", Region: "nyc1", Size: "s-2vcpu-4gb"},
+				"prod-app-1": {ID: 8, Name: "prod-app-1", Region: "nyc1", Size: "s-2vcpu-4gb"},
+			},
+			wantErr: ErrDropletExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, client := resizeTestSetup(t, map[string]any{
+				"app-1": map[string]any{"role": "app"},
+			}, tt.droplets)
+
+			_, err := NewDigitalOceanProviderWithClient(client).ImportHost(context.Background(), cloud.ImportOptions{
+				Config: cfg, Environment: "prod", Project: "shop", Resource: "legacy-web", Host: tt.host,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImportHost() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(client.renamed)+len(client.tagged) != 0 {
+				t.Errorf("ImportHost() renamed %v and tagged %v, want nothing", client.renamed, client.tagged)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_OFFLINE
+// Spec: spec/core/offline.md
+
+// Ensure DigitalOceanProvider implements cloud.OfflinePlanner
+var _ cloud.OfflinePlanner = (*DigitalOceanProvider)(nil)
+
+// PlanOffline plans as Plan does, against opts.Known (the droplets last
+// listed) and without API calls:
+//   - the SSH key and the API token are not checked;
+//   - a known host with a reserved IP recorded in state is assumed to hold it;
+//   - volumes of known hosts are assumed attached;
+//   - costs use the built-in price list, and RefreshPricing fails.
+func (p *DigitalOceanProvider) PlanOffline(_ context.Context, opts cloud.PlanOptions) (cloud.InfraPlan, error) {
+	config, err := parseConfig(opts.Config)
+	if err != nil {
+		return cloud.InfraPlan{}, err
+	}
+	if opts.RefreshPricing {
+		return cloud.InfraPlan{}, fmt.Errorf("refreshing prices %w", cloud.ErrOffline)
+	}
+
+	envHosts := config.Hosts[opts.Environment]
+	if len(envHosts) == 0 {
+		return cloud.InfraPlan{}, nil
+	}
+
+	actual, err := knownDroplets(opts.Environment, opts.Known)
+	if err != nil {
+		return cloud.InfraPlan{}, err
+	}
+
+	toCreate, toDelete := diffHosts(config, envHosts, actual)
+	toResize, toReplace, err := planHostChanges(config, envHosts, actual)
+	if err != nil {
+		return cloud.InfraPlan{}, err
+	}
+
+	known := func(name string) bool {
+		_, ok := actual[name]
+		return ok
+	}
+	reservedIPs := reservedIPSpecs(config, envHosts,
+		func(name string) bool { return known(name) && opts.ReservedIPs[name] != "" },
+		func(name string) string { return opts.ReservedIPs[name] },
+	)
+	volumes := volumeSpecs(config, envHosts, func(host string, _ VolumeConfig) bool { return known(host) })
+
+	plan := cloud.InfraPlan{
+		ToCreate:    toCreate,
+		ToDelete:    toDelete,
+		ToResize:    toResize,
+		ToReplace:   toReplace,
+		ReservedIPs: reservedIPs,
+		Volumes:     volumes,
+	}
+	plan.Cost = staticPricing.Estimate(plan)
+	return plan, nil
+}
+
+// knownDroplets rebuilds the droplets of env, keyed by logical host name,
+// from the hosts Hosts last reported.
+func knownDroplets(env string, hosts []cloud.Host) (map[string]Droplet, error) {
+	droplets := make(map[string]Droplet, len(hosts))
+	for _, h := range hosts {
+		id, err := strconv.Atoi(h.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cached host %q has invalid droplet ID %q", ErrConfigInvalid, h.Name, h.ID)
+		}
+		if h.Size == "" {
+			// Inventories cached before sizes were recorded would plan
+			// every host as resized.
+			return nil, fmt.Errorf("cached host %q has no size; refreshing the host inventory %w", h.Name, cloud.ErrOffline)
+		}
+		droplets[h.Name] = Droplet{
+			ID:     id,
+			Name:   env + "-" + h.Name,
+			Region: h.Region,
+			Size:   h.Size,
+			Status: h.Status,
+		}
+	}
+	return droplets, nil
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: CLI_OFFLINE
+// Spec: spec/core/offline.md
+
+func TestDigitalOceanProvider_PlanOffline_MatchesPlan(t *testing.T) {
+	cfg, client := resizeTestSetup(t, map[string]any{
+		"app-1": map[string]any{"role": "app", "size": "s-4vcpu-8gb"},
+		"db-1": map[string]any{"role": "db", "reserved_ip": true, "volumes": []any{
+			map[string]any{"name": "data", "size_gb": 10, "mount_point": "/mnt/data"},
+		}},
+	}, map[string]Droplet{
+		"prod-app-1": {ID: 1, Name: "prod-app-1", Tags: prodTags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+		"prod-old-1": {ID: 2, Name: "prod-old-1", Tags: prodTags, Region: "nyc1", Size: "s-1vcpu-1gb"},
+	})
+	opts := cloud.PlanOptions{Config: cfg, Environment: "prod"}
+
+	online, err := NewDigitalOceanProviderWithClient(client).Plan(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	// A nil client panics on any API call.
+	opts.Known = []cloud.Host{
+		{ID: "1", Name: "app-1", Region: "nyc1", Size: "s-2vcpu-4gb", Status: "active"},
+		{ID: "2", Name: "old-1", Region: "nyc1", Size: "s-1vcpu-1gb", Status: "active"},
+	}
+	offline, err := NewDigitalOceanProviderWithClient(nil).PlanOffline(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("PlanOffline() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(offline, online) {
+		t.Errorf("PlanOffline() = %+v\nwant Plan() = %+v", offline, online)
+	}
+}
+
+func TestDigitalOceanProvider_PlanOffline_AssumesRecordedResourcesInPlace(t *testing.T) {
+	cfg, _ := resizeTestSetup(t, map[string]any{
+		"db-1": map[string]any{"role": "db", "reserved_ip": true, "volumes": []any{
+			map[string]any{"name": "data", "size_gb": 10, "mount_point": "/mnt/data"},
+		}},
+	}, nil)
+
+	plan, err := NewDigitalOceanProviderWithClient(nil).PlanOffline(context.Background(), cloud.PlanOptions{
+		Config:      cfg,
+		Environment: "prod",
+		ReservedIPs: map[string]string{"db-1": "203.0.113.7"},
+		Known:       []cloud.Host{{ID: "1", Name: "db-1", Region: "nyc1", Size: "s-2vcpu-4gb"}},
+	})
+	if err != nil {
+		t.Fatalf("PlanOffline() error = %v", err)
+	}
+	if len(plan.ReservedIPs)+len(plan.Volumes) != 0 {
+		t.Errorf("ReservedIPs = %+v, Volumes = %+v, want none", plan.ReservedIPs, plan.Volumes)
+	}
+}
+
+func TestDigitalOceanProvider_PlanOffline_Refuses(t *testing.T) {
+	cfg, _ := resizeTestSetup(t, map[string]any{"app-1": map[string]any{"role": "app"}}, nil)
+
+	tests := []struct {
+		name string
+		opts cloud.PlanOptions
+	}{
+		{
+			name: "refresh pricing",
+			opts: cloud.PlanOptions{Config: cfg, Environment: "prod", RefreshPricing: true},
+		},
+		{
+			name: "cached host without size",
+			opts: cloud.PlanOptions{Config: cfg, Environment: "prod", Known: []cloud.Host{{ID: "1", Name: "app-1", Region: "nyc1"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDigitalOceanProviderWithClient(nil).PlanOffline(context.Background(), tt.opts)
+			if !errors.Is(err, cloud.ErrOffline) {
+				t.Fatalf("PlanOffline() error = %v, want ErrOffline", err)
+			}
+		})
+	}
+}
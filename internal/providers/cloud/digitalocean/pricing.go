@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_COST_ESTIMATE
+// Spec: spec/providers/cloud/cost-estimate.md
+
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stagecraft/internal/cache"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// staticPricing is DigitalOcean's published monthly droplet pricing as of
+// 2025-01. DigitalOcean prices sizes the same in every region. Plans with
+// cloud.PlanOptions.RefreshPricing use the sizes API instead.
+var staticPricing = cloud.PricingTable{
+	Currency: "USD",
+	Source:   "built-in DigitalOcean price list (2025-01)",
+	Sizes: map[string]cloud.SizePrice{
+		"s-1vcpu-512mb-10gb": {Monthly: 4},
+		"s-1vcpu-1gb":        {Monthly: 6},
+		"s-1vcpu-2gb":        {Monthly: 12},
+		"s-2vcpu-2gb":        {Monthly: 18},
+		"s-2vcpu-4gb":        {Monthly: 24},
+		"s-4vcpu-8gb":        {Monthly: 48},
+		"s-8vcpu-16gb":       {Monthly: 96},
+		"c-2":                {Monthly: 42},
+		"c-4":                {Monthly: 84},
+		"g-2vcpu-8gb":        {Monthly: 63},
+		"m-2vcpu-16gb":       {Monthly: 84},
+	},
+}
+
+// pricing returns the price list to estimate plans with: the built-in list,
+// or the account's current sizes when refresh is set. Sizes fetched within
+// cache.PricingTTL are reused from the cache.
+func (p *DigitalOceanProvider) pricing(ctx context.Context, refresh bool) (cloud.PricingTable, error) {
+	if !refresh {
+		return staticPricing, nil
+	}
+
+	if entry, err := p.cache.Get(cache.BucketPricing, p.ID()); err == nil && entry.Fresh(time.Now(), cache.PricingTTL) {
+		var table cloud.PricingTable
+		if entry.Decode(&table) == nil {
+			return table, nil
+		}
+	}
+
+	sizes, err := p.client.ListSizes(ctx)
+	if err != nil {
+		return cloud.PricingTable{}, fmt.Errorf("%w: listing sizes: %v", ErrAPIError, err)
+	}
+
+	table := cloud.PricingTable{
+		Currency: "USD",
+		Source:   "DigitalOcean sizes API",
+		Sizes:    make(map[string]cloud.SizePrice, len(sizes)),
+	}
+	for _, s := range sizes {
+		table.Sizes[s.Slug] = cloud.SizePrice{Monthly: s.PriceMonthly}
+	}
+
+	// The cache only saves API calls; a plan does not fail over it
+	_ = p.cache.PutJSON(cache.BucketPricing, p.ID(), table)
+	return table, nil
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_COST_ESTIMATE
+// Spec: spec/providers/cloud/cost-estimate.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"stagecraft/internal/cache"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// pricingTestSetup plans app-2 (s-2vcpu-4gb) for creation and the existing
+// old-1 (s-1vcpu-1gb) for deletion.
+func pricingTestSetup(t *testing.T) (map[string]any, *mockAPIClient) {
+	t.Helper()
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	cfg := map[string]any{
+		"token_env":    "DO_TOKEN",
+		"ssh_key_name": "my-ssh-key",
+		"default_size": "s-2vcpu-4gb",
+		"hosts": map[string]any{
+			"staging": map[string]any{
+				"app-2": map[string]any{"role": "app", "region": "fra1"},
+			},
+		},
+	}
+	client := &mockAPIClient{
+		sshKeys: map[string]SSHKey{"my-ssh-key": {ID: 1, Name: "my-ssh-key"}},
+		droplets: map[string]Droplet{
+			"staging-old-1": {ID: 7, Name: "staging-old-1", Tags: []string{"stagecraft", "stagecraft-env-staging"}, Region: "fra1", Size: "s-1vcpu-1gb", Status: "active"},
+		},
+	}
+	return cfg, client
+}
+
+func TestDigitalOceanProvider_Plan_EstimatesCostFromStaticPricing(t *testing.T) {
+	cfg, client := pricingTestSetup(t)
+	client.sizesErr = errors.New("sizes API must not be called")
+
+	plan, err := NewDigitalOceanProviderWithClient(client).Plan(context.Background(), cloud.PlanOptions{
+		Config:      cfg,
+		Environment: "staging",
+	})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+
+	if plan.Cost == nil {
+		t.Fatal("plan.Cost = nil, want an estimate")
+	}
+	if plan.Cost.Currency != "USD" || plan.Cost.Source != staticPricing.Source {
+		t.Errorf("Cost currency/source = %q/%q", plan.Cost.Currency, plan.Cost.Source)
+	}
+	if len(plan.Cost.Create) != 1 || plan.Cost.Create[0].Name != "app-2" || plan.Cost.Create[0].Monthly != 24 {
+		t.Errorf("Cost.Create = %+v, want app-2 at 24", plan.Cost.Create)
+	}
+	if len(plan.Cost.Delete) != 1 || plan.Cost.Delete[0].Name != "old-1" || plan.Cost.Delete[0].Monthly != 6 {
+		t.Errorf("Cost.Delete = %+v, want old-1 at 6", plan.Cost.Delete)
+	}
+	if got := plan.Cost.MonthlyDelta(); got != 18 {
+		t.Errorf("MonthlyDelta() = %v, want 18", got)
+	}
+}
+
+func TestDigitalOceanProvider_Plan_RefreshPricingUsesSizesAPI(t *testing.T) {
+	cfg, client := pricingTestSetup(t)
+	client.sizes = []Size{
+		{Slug: "s-1vcpu-1gb", PriceMonthly: 7, Available: true},
+		{Slug: "s-2vcpu-4gb", PriceMonthly: 28, Available: true},
+	}
+
+	plan, err := NewDigitalOceanProviderWithClient(client).Plan(context.Background(), cloud.PlanOptions{
+		Config:         cfg,
+		Environment:    "staging",
+		RefreshPricing: true,
+	})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+
+	if plan.Cost.Source != "DigitalOcean sizes API" {
+		t.Errorf("Cost.Source = %q", plan.Cost.Source)
+	}
+	if got := plan.Cost.MonthlyDelta(); got != 21 {
+		t.Errorf("MonthlyDelta() = %v, want 21", got)
+	}
+}
+
+func TestDigitalOceanProvider_Plan_RefreshPricingIsCached(t *testing.T) {
+	cfg, client := pricingTestSetup(t)
+	client.sizes = []Size{
+		{Slug: "s-1vcpu-1gb", PriceMonthly: 7, Available: true},
+		{Slug: "s-2vcpu-4gb", PriceMonthly: 28, Available: true},
+	}
+
+	provider := NewDigitalOceanProviderWithClient(client)
+	provider.cache = cache.New(t.TempDir())
+	opts := cloud.PlanOptions{Config: cfg, Environment: "staging", RefreshPricing: true}
+
+	if _, err := provider.Plan(context.Background(), opts); err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+
+	client.sizesErr = errors.New("sizes API must not be called while the cached prices are fresh")
+	plan, err := provider.Plan(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("second Plan() failed: %v", err)
+	}
+	if got := plan.Cost.MonthlyDelta(); got != 21 {
+		t.Errorf("MonthlyDelta() = %v, want 21 from the cached prices", got)
+	}
+}
+
+func TestDigitalOceanProvider_Plan_RefreshPricingError(t *testing.T) {
+	cfg, client := pricingTestSetup(t)
+	client.sizesErr = errors.New("rate limited")
+
+	_, err := NewDigitalOceanProviderWithClient(client).Plan(context.Background(), cloud.PlanOptions{
+		Config:         cfg,
+		Environment:    "staging",
+		RefreshPricing: true,
+	})
+	if !errors.Is(err, ErrAPIError) {
+		t.Fatalf("Plan() error = %v, want ErrAPIError", err)
+	}
+}
@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_RESERVED_IP
+// Spec: spec/providers/cloud/reserved-ip.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Ensure DigitalOceanProvider implements ReservedIPProvider
+var _ cloud.ReservedIPProvider = (*DigitalOceanProvider)(nil)
+
+// planReservedIPs returns the reserved IPs to create or attach for hosts
+// with reserved_ip set. A host whose droplet already holds a reserved IP is
+// skipped; otherwise the address recorded in state is reattached when it
+// still exists in the account, and a new one is reserved when it does not.
+func (p *DigitalOceanProvider) planReservedIPs(ctx context.Context, config *Config, envHosts map[string]HostConfig, actual map[string]Droplet, recorded map[string]string) ([]cloud.ReservedIPSpec, error) {
+	if !wantsReservedIP(envHosts) {
+		return nil, nil
+	}
+
+	existing, err := p.client.ListReservedIPs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAPIError, err)
+	}
+	byAddress := make(map[string]ReservedIP, len(existing))
+	byDroplet := make(map[int]ReservedIP, len(existing))
+	for _, ip := range existing {
+		byAddress[ip.IP] = ip
+		if ip.DropletID != 0 {
+			byDroplet[ip.DropletID] = ip
+		}
+	}
+
+	attached := func(name string) bool {
+		d, ok := actual[name]
+		if !ok {
+			return false
+		}
+		_, ok = byDroplet[d.ID]
+		return ok
+	}
+	address := func(name string) string {
+		addr := recorded[name]
+		if _, ok := byAddress[addr]; addr != "" && ok {
+			return addr
+		}
+		return ""
+	}
+	return reservedIPSpecs(config, envHosts, attached, address), nil
+}
+
+// wantsReservedIP reports whether any host has reserved_ip set.
+func wantsReservedIP(envHosts map[string]HostConfig) bool {
+	for _, hostCfg := range envHosts {
+		if hostCfg.ReservedIP {
+			return true
+		}
+	}
+	return false
+}
+
+// reservedIPSpecs returns, sorted by host, the reserved IPs of hosts with
+// reserved_ip set that attached reports as lacking one. address returns
+// the existing address to reattach to a host, or "" to reserve a new one.
+func reservedIPSpecs(config *Config, envHosts map[string]HostConfig, attached func(name string) bool, address func(name string) string) []cloud.ReservedIPSpec {
+	var names []string
+	for name, hostCfg := range envHosts {
+		if hostCfg.ReservedIP {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var specs []cloud.ReservedIPSpec
+	for _, name := range names {
+		if attached(name) {
+			continue
+		}
+		specs = append(specs, cloud.ReservedIPSpec{
+			Host:    name,
+			Region:  firstNonEmpty(envHosts[name].Region, config.DefaultRegion),
+			Address: address(name),
+		})
+	}
+	return specs
+}
+
+// applyReservedIPs reserves and attaches the planned reserved IPs. Droplets
+// that already hold a reserved IP are left alone, so re-applying a plan
+// never reserves a second address.
+func (p *DigitalOceanProvider) applyReservedIPs(ctx context.Context, env string, specs []cloud.ReservedIPSpec) error {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	sorted := append([]cloud.ReservedIPSpec(nil), specs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Host < sorted[j].Host
+	})
+
+	existing, err := p.client.ListReservedIPs(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAPIError, err)
+	}
+	attached := make(map[int]bool, len(existing))
+	for _, ip := range existing {
+		if ip.DropletID != 0 {
+			attached[ip.DropletID] = true
+		}
+	}
+
+	for _, spec := range sorted {
+		fullName := env + "-" + spec.Host
+
+		droplet, err := p.client.GetDroplet(ctx, fullName)
+		if err != nil {
+			if errors.Is(err, ErrDropletNotFound) {
+				return fmt.Errorf("%w: droplet %q not found for reserved IP", ErrReservedIPFailed, fullName)
+			}
+			return fmt.Errorf("%w: %v", ErrAPIError, err)
+		}
+		if attached[droplet.ID] {
+			continue
+		}
+
+		address := spec.Address
+		if address == "" {
+			ip, err := p.client.CreateReservedIP(ctx, spec.Region)
+			if err != nil {
+				return fmt.Errorf("%w: reserving IP in %s: %v", ErrReservedIPFailed, spec.Region, err)
+			}
+			address = ip.IP
+		}
+
+		if err := p.client.AssignReservedIP(ctx, address, droplet.ID); err != nil {
+			return fmt.Errorf("%w: assigning %s to %q: %v", ErrReservedIPFailed, address, fullName, err)
+		}
+		attached[droplet.ID] = true
+	}
+
+	return nil
+}
+
+// ReservedIPs returns the reserved IPs attached to the environment's droplets,
+// sorted by host name.
+func (p *DigitalOceanProvider) ReservedIPs(ctx context.Context, opts cloud.HostsOptions) ([]cloud.ReservedIP, error) {
+	config, err := parseConfig(opts.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := os.LookupEnv(config.TokenEnv)
+	if !ok || token == "" {
+		return nil, fmt.Errorf("%w: API token missing from environment variable %s", ErrTokenMissing, config.TokenEnv)
+	}
+	p = p.connect(config, token)
+
+	env := opts.Environment
+	droplets, err := p.client.ListDroplets(ctx, dropletFilter(opts.Project, env))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAPIError, err)
+	}
+	names := make(map[int]string, len(droplets))
+	for _, d := range droplets {
+		names[d.ID] = strings.TrimPrefix(d.Name, env+"-")
+	}
+
+	existing, err := p.client.ListReservedIPs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAPIError, err)
+	}
+
+	var result []cloud.ReservedIP
+	for _, ip := range existing {
+		name, ok := names[ip.DropletID]
+		if !ok {
+			continue
+		}
+		result = append(result, cloud.ReservedIP{
+			Host:    name,
+			Address: ip.IP,
+			Region:  ip.Region,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Host < result[j].Host
+	})
+
+	return result, nil
+}
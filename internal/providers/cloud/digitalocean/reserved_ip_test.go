@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_RESERVED_IP
+// Spec: spec/providers/cloud/reserved-ip.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+func reservedIPTestConfig() map[string]any {
+	return map[string]any{
+		"token_env":      "DO_TOKEN",
+		"ssh_key_name":   "my-ssh-key",
+		"default_region": "nyc3",
+		"default_size":   "s-1vcpu-1gb",
+		"hosts": map[string]any{
+			"prod": map[string]any{
+				"gateway-1": map[string]any{"role": "gateway", "reserved_ip": true},
+				"app-1":     map[string]any{"role": "app"},
+			},
+		},
+	}
+}
+
+func newReservedIPTestProvider() (*DigitalOceanProvider, *mockAPIClient) {
+	mockClient := &mockAPIClient{
+		sshKeys: map[string]SSHKey{"my-ssh-key": {ID: 1, Name: "my-ssh-key"}},
+	}
+	return NewDigitalOceanProviderWithClient(mockClient), mockClient
+}
+
+func TestDigitalOceanProvider_ReservedIP_PlanApplyRecord(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	ctx := context.Background()
+	provider, mockClient := newReservedIPTestProvider()
+	cfg := reservedIPTestConfig()
+
+	plan, err := provider.Plan(ctx, cloud.PlanOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if len(plan.ReservedIPs) != 1 {
+		t.Fatalf("plan.ReservedIPs = %+v, want one entry", plan.ReservedIPs)
+	}
+	if got := plan.ReservedIPs[0]; got.Host != "gateway-1" || got.Region != "nyc3" || got.Address != "" {
+		t.Errorf("plan.ReservedIPs[0] = %+v, want new IP for gateway-1 in nyc3", got)
+	}
+
+	if err := provider.Apply(ctx, cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan}); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+
+	ips, err := provider.ReservedIPs(ctx, cloud.HostsOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("ReservedIPs() failed: %v", err)
+	}
+	if len(ips) != 1 || ips[0].Host != "gateway-1" || ips[0].Address == "" {
+		t.Fatalf("ReservedIPs() = %+v, want gateway-1 with an address", ips)
+	}
+
+	// Re-planning after apply is a no-op, and re-applying the stale plan
+	// must not reserve a second address.
+	again, err := provider.Plan(ctx, cloud.PlanOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if len(again.ReservedIPs) != 0 {
+		t.Errorf("expected no reserved IP changes after apply, got %+v", again.ReservedIPs)
+	}
+	if err := provider.Apply(ctx, cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan}); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+	if len(mockClient.reservedIPs) != 1 {
+		t.Errorf("expected one reserved IP after re-apply, got %d", len(mockClient.reservedIPs))
+	}
+}
+
+func TestDigitalOceanProvider_ReservedIP_ReattachesRecordedAddress(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	ctx := context.Background()
+	provider, mockClient := newReservedIPTestProvider()
+	// The gateway droplet was replaced; its old address is unassigned.
+	mockClient.reservedIPs = []ReservedIP{{IP: "198.51.100.7", Region: "nyc3"}}
+	cfg := reservedIPTestConfig()
+
+	plan, err := provider.Plan(ctx, cloud.PlanOptions{
+		Config:      cfg,
+		Environment: "prod",
+		ReservedIPs: map[string]string{"gateway-1": "198.51.100.7"},
+	})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if len(plan.ReservedIPs) != 1 || plan.ReservedIPs[0].Address != "198.51.100.7" {
+		t.Fatalf("plan.ReservedIPs = %+v, want reattach of 198.51.100.7", plan.ReservedIPs)
+	}
+
+	if err := provider.Apply(ctx, cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan}); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+
+	gateway := mockClient.droplets["prod-gateway-1"]
+	if len(mockClient.reservedIPs) != 1 || mockClient.reservedIPs[0].DropletID != gateway.ID {
+		t.Fatalf("expected 198.51.100.7 attached to droplet %d, got %+v", gateway.ID, mockClient.reservedIPs)
+	}
+}
+
+func TestDigitalOceanProvider_ReservedIP_ReleasedAddressIsReplaced(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	provider, _ := newReservedIPTestProvider()
+	plan, err := provider.Plan(context.Background(), cloud.PlanOptions{
+		Config:      reservedIPTestConfig(),
+		Environment: "prod",
+		ReservedIPs: map[string]string{"gateway-1": "198.51.100.7"},
+	})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if len(plan.ReservedIPs) != 1 || plan.ReservedIPs[0].Address != "" {
+		t.Fatalf("plan.ReservedIPs = %+v, want a new reservation", plan.ReservedIPs)
+	}
+}
+
+func TestDigitalOceanProvider_ReservedIP_AssignFails(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	ctx := context.Background()
+	provider, mockClient := newReservedIPTestProvider()
+	mockClient.droplets = map[string]Droplet{"prod-gateway-1": {ID: 7, Name: "prod-gateway-1", Tags: []string{"stagecraft", "stagecraft-env-prod"}}}
+	mockClient.reservedIPErr = errors.New("quota exceeded")
+
+	err := provider.Apply(ctx, cloud.ApplyOptions{
+		Config:      reservedIPTestConfig(),
+		Environment: "prod",
+		Plan: cloud.InfraPlan{
+			ReservedIPs: []cloud.ReservedIPSpec{{Host: "gateway-1", Region: "nyc3"}},
+		},
+	})
+	if !errors.Is(err, ErrAPIError) {
+		t.Fatalf("expected ErrAPIError, got %v", err)
+	}
+
+	mockClient.reservedIPErr = nil
+	err = provider.Apply(ctx, cloud.ApplyOptions{
+		Config:      reservedIPTestConfig(),
+		Environment: "prod",
+		Plan: cloud.InfraPlan{
+			ReservedIPs: []cloud.ReservedIPSpec{{Host: "app-9", Region: "nyc3"}},
+		},
+	})
+	if !errors.Is(err, ErrReservedIPFailed) {
+		t.Fatalf("expected ErrReservedIPFailed for missing droplet, got %v", err)
+	}
+}
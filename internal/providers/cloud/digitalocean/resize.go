@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_DO
+// Spec: spec/providers/cloud/digitalocean.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// planHostChanges compares declared hosts with their live droplets. A
+// droplet of another size is resized in place. A droplet in another region
+// cannot be moved, so it is replaced. Hosts with volumes or a reserved IP
+// cannot change region: both are bound to the droplet's region.
+func planHostChanges(config *Config, envHosts map[string]HostConfig, actual map[string]Droplet) (toResize, toReplace []cloud.HostChange, err error) {
+	names := make([]string, 0, len(envHosts))
+	for name := range envHosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		d, ok := actual[name]
+		if !ok {
+			continue
+		}
+		hostCfg := envHosts[name]
+		change := cloud.HostChange{
+			Desired: desiredHostSpec(config, name, hostCfg),
+			Current: cloud.HostSpec{Name: name, Role: hostCfg.Role, Size: d.Size, Region: d.Region},
+		}
+
+		switch {
+		case change.Desired.Region != "" && change.Desired.Region != d.Region:
+			if len(hostCfg.Volumes) > 0 || hostCfg.ReservedIP {
+				return nil, nil, fmt.Errorf("%w: host %q cannot move from region %s to %s: its volumes and reserved IP stay in %s",
+					ErrConfigInvalid, name, d.Region, change.Desired.Region, d.Region)
+			}
+			toReplace = append(toReplace, change)
+		case change.Desired.Size != "" && change.Desired.Size != d.Size:
+			toResize = append(toResize, change)
+		}
+	}
+
+	return toResize, toReplace, nil
+}
+
+// applyResizes resizes droplets in plan order. A droplet already at the
+// desired size is skipped.
+func (p *DigitalOceanProvider) applyResizes(ctx context.Context, env string, tags []string, changes []cloud.HostChange) error {
+	for _, change := range sortedChanges(changes) {
+		fullName := env + "-" + change.Desired.Name
+
+		existing, err := p.client.GetDroplet(ctx, fullName)
+		if err != nil {
+			return fmt.Errorf("%w: resizing %q: %v", ErrAPIError, fullName, err)
+		}
+		if existing.Size == change.Desired.Size {
+			continue
+		}
+		if !hasAllTags(existing.Tags, tags) {
+			return fmt.Errorf("%w: refusing to resize droplet %q without tags %v", ErrDropletUnmanaged, fullName, tags)
+		}
+
+		if err := p.client.ResizeDroplet(ctx, existing.ID, change.Desired.Size); err != nil {
+			return fmt.Errorf("%w: %s to %s: %v", ErrDropletResizeFailed, fullName, change.Desired.Size, err)
+		}
+		if err := p.waitForDroplet(ctx, existing.ID, "active"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyReplaces deletes and recreates droplets in plan order. A droplet that
+// already matches the desired spec is skipped, and a missing one is
+// created, so an interrupted replace resumes.
+func (p *DigitalOceanProvider) applyReplaces(ctx context.Context, config *Config, env string, sshKey *SSHKey, tags []string, changes []cloud.HostChange) error {
+	for _, change := range sortedChanges(changes) {
+		fullName := env + "-" + change.Desired.Name
+
+		existing, err := p.client.GetDroplet(ctx, fullName)
+		if err != nil && !errors.Is(err, ErrDropletNotFound) {
+			return fmt.Errorf("%w: %v", ErrAPIError, err)
+		}
+		if existing != nil {
+			if existing.Region == change.Desired.Region && existing.Size == change.Desired.Size {
+				continue
+			}
+			if err := p.deleteDroplet(ctx, existing, tags); err != nil {
+				return err
+			}
+		}
+
+		if err := p.createDroplet(ctx, config, env, change.Desired, sshKey, tags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedChanges returns a copy of changes sorted by host name.
+func sortedChanges(changes []cloud.HostChange) []cloud.HostChange {
+	sorted := append([]cloud.HostChange(nil), changes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Desired.Name < sorted[j].Desired.Name
+	})
+	return sorted
+}
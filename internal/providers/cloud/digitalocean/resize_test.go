@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_DO
+// Spec: spec/providers/cloud/digitalocean.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+var prodTags = []string{"stagecraft", "stagecraft-env-prod"}
+
+func resizeTestSetup(t *testing.T, hosts map[string]any, droplets map[string]Droplet) (map[string]any, *mockAPIClient) {
+	t.Helper()
+	t.Setenv("DO_TOKEN", "dummy-token")
+	cfg := map[string]any{
+		"token_env":      "DO_TOKEN",
+		"ssh_key_name":   "my-ssh-key",
+		"default_region": "nyc1",
+		"default_size":   "s-2vcpu-4gb",
+		"hosts":          map[string]any{"prod": hosts},
+	}
+	client := &mockAPIClient{
+		sshKeys:  map[string]SSHKey{"my-ssh-key": {ID: 123, Name: "my-ssh-key"}},
+		droplets: droplets,
+	}
+	return cfg, client
+}
+
+func TestDigitalOceanProvider_Plan_ResizeAndReplace(t *testing.T) {
+	cfg, client := resizeTestSetup(t, map[string]any{
+		"app-1": map[string]any{"role": "app", "size": "s-4vcpu-8gb"},
+		"app-2": map[string]any{"role": "app"},
+		"web-1": map[string]any{"role": "web", "region": "fra1"},
+	}, map[string]Droplet{
+		"prod-app-1": {ID: 1, Name: "prod-app-1", Tags: prodTags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+		"prod-app-2": {ID: 2, Name: "prod-app-2", Tags: prodTags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+		"prod-web-1": {ID: 3, Name: "prod-web-1", Tags: prodTags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+	})
+
+	plan, err := NewDigitalOceanProviderWithClient(client).Plan(context.Background(), cloud.PlanOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	if len(plan.ToCreate)+len(plan.ToDelete) != 0 {
+		t.Errorf("ToCreate = %+v, ToDelete = %+v, want none", plan.ToCreate, plan.ToDelete)
+	}
+	wantResize := cloud.HostChange{
+		Desired: cloud.HostSpec{Name: "app-1", Role: "app", Size: "s-4vcpu-8gb", Region: "nyc1"},
+		Current: cloud.HostSpec{Name: "app-1", Role: "app", Size: "s-2vcpu-4gb", Region: "nyc1"},
+	}
+	if len(plan.ToResize) != 1 || plan.ToResize[0] != wantResize {
+		t.Errorf("ToResize = %+v, want [%+v]", plan.ToResize, wantResize)
+	}
+	wantReplace := cloud.HostChange{
+		Desired: cloud.HostSpec{Name: "web-1", Role: "web", Size: "s-2vcpu-4gb", Region: "fra1"},
+		Current: cloud.HostSpec{Name: "web-1", Role: "web", Size: "s-2vcpu-4gb", Region: "nyc1"},
+	}
+	if len(plan.ToReplace) != 1 || plan.ToReplace[0] != wantReplace {
+		t.Errorf("ToReplace = %+v, want [%+v]", plan.ToReplace, wantReplace)
+	}
+
+	// The resize costs 48 - 24; the replace costs the same in both regions.
+	if got := plan.Cost.MonthlyDelta(); got != 24 {
+		t.Errorf("MonthlyDelta() = %v, want 24", got)
+	}
+}
+
+func TestDigitalOceanProvider_Plan_RegionChangeWithRegionBoundResources(t *testing.T) {
+	tests := []struct {
+		name string
+		host map[string]any
+	}{
+		{name: "volumes", host: map[string]any{"role": "db", "region": "fra1", "volumes": []any{
+			map[string]any{"name": "data", "size_gb": 10, "mount_point": "/mnt/data"},
+		}}},
+		{name: "reserved IP", host: map[string]any{"role": "gateway", "region": "fra1", "reserved_ip": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, client := resizeTestSetup(t, map[string]any{"host-1": tt.host}, map[string]Droplet{
+				"prod-host-1": {ID: 1, Name: "prod-host-1", Tags: prodTags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+			})
+
+			_, err := NewDigitalOceanProviderWithClient(client).Plan(context.Background(), cloud.PlanOptions{Config: cfg, Environment: "prod"})
+			if !errors.Is(err, ErrConfigInvalid) {
+				t.Fatalf("Plan() error = %v, want ErrConfigInvalid", err)
+			}
+		})
+	}
+}
+
+func TestDigitalOceanProvider_Apply_Resize(t *testing.T) {
+	cfg, client := resizeTestSetup(t, map[string]any{}, map[string]Droplet{
+		"prod-app-1": {ID: 1, Name: "prod-app-1", Tags: prodTags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+		"prod-app-2": {ID: 2, Name: "prod-app-2", Tags: prodTags, Region: "nyc1", Size: "s-4vcpu-8gb"},
+	})
+	plan := cloud.InfraPlan{ToResize: []cloud.HostChange{
+		{Desired: cloud.HostSpec{Name: "app-2", Size: "s-4vcpu-8gb", Region: "nyc1"}},
+		{Desired: cloud.HostSpec{Name: "app-1", Size: "s-4vcpu-8gb", Region: "nyc1"}},
+	}}
+
+	err := NewDigitalOceanProviderWithClient(client).Apply(context.Background(), cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// app-2 is already at the desired size.
+	if len(client.resized) != 1 || client.resized[0] != "prod-app-1=s-4vcpu-8gb" {
+		t.Errorf("resized = %v, want [prod-app-1=s-4vcpu-8gb]", client.resized)
+	}
+	if len(client.waited) != 1 || client.waited[0].id != 1 || client.waited[0].status != "active" {
+		t.Errorf("waited = %+v, want droplet 1 active", client.waited)
+	}
+	if len(client.created)+len(client.deleted) != 0 {
+		t.Errorf("created = %v, deleted = %v, want none", client.created, client.deleted)
+	}
+}
+
+func TestDigitalOceanProvider_Apply_ResizeFails(t *testing.T) {
+	cfg, client := resizeTestSetup(t, map[string]any{}, map[string]Droplet{
+		"prod-app-1": {ID: 1, Name: "prod-app-1", Tags: prodTags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+	})
+	client.resizeErr = errors.New("disk too small")
+	plan := cloud.InfraPlan{ToResize: []cloud.HostChange{
+		{Desired: cloud.HostSpec{Name: "app-1", Size: "s-1vcpu-1gb", Region: "nyc1"}},
+	}}
+
+	err := NewDigitalOceanProviderWithClient(client).Apply(context.Background(), cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan})
+	if !errors.Is(err, ErrDropletResizeFailed) {
+		t.Fatalf("Apply() error = %v, want ErrDropletResizeFailed", err)
+	}
+}
+
+func TestDigitalOceanProvider_Apply_Replace(t *testing.T) {
+	cfg, client := resizeTestSetup(t, map[string]any{}, map[string]Droplet{
+		"prod-web-1": {ID: 10, Name: "prod-web-1", Tags: prodTags, Region: "nyc1", Size: "s-2vcpu-4gb"},
+		"prod-web-2": {ID: 11, Name: "prod-web-2", Tags: prodTags, Region: "fra1", Size: "s-2vcpu-4gb"},
+	})
+	plan := cloud.InfraPlan{ToReplace: []cloud.HostChange{
+		{Desired: cloud.HostSpec{Name: "web-1", Role: "web", Size: "s-2vcpu-4gb", Region: "fra1"}},
+		{Desired: cloud.HostSpec{Name: "web-2", Role: "web", Size: "s-2vcpu-4gb", Region: "fra1"}},
+	}}
+
+	err := NewDigitalOceanProviderWithClient(client).Apply(context.Background(), cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// web-2 was already replaced by an earlier, interrupted apply.
+	if len(client.deleted) != 1 || client.deleted[0] != 10 {
+		t.Errorf("deleted = %v, want [10]", client.deleted)
+	}
+	if len(client.created) != 1 {
+		t.Fatalf("created = %+v, want one droplet", client.created)
+	}
+	if req := client.created[0]; req.Name != "prod-web-1" || req.Region != "fra1" || !hasAllTags(req.Tags, prodTags) {
+		t.Errorf("created = %+v, want prod-web-1 in fra1 with %v", req, prodTags)
+	}
+}
+
+func TestDigitalOceanProvider_Apply_ReplaceRefusesUnmanagedDroplet(t *testing.T) {
+	cfg, client := resizeTestSetup(t, map[string]any{}, map[string]Droplet{
+		"prod-web-1": {ID: 10, Name: "prod-web-1", Region: "nyc1", Size: "s-2vcpu-4gb"},
+	})
+	plan := cloud.InfraPlan{ToReplace: []cloud.HostChange{
+		{Desired: cloud.HostSpec{Name: "web-1", Size: "s-2vcpu-4gb", Region: "fra1"}},
+	}}
+
+	err := NewDigitalOceanProviderWithClient(client).Apply(context.Background(), cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan})
+	if !errors.Is(err, ErrDropletUnmanaged) {
+		t.Fatalf("Apply() error = %v, want ErrDropletUnmanaged", err)
+	}
+	if len(client.deleted)+len(client.created) != 0 {
+		t.Errorf("deleted = %v, created = %v, want none", client.deleted, client.created)
+	}
+}
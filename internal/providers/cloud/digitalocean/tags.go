@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_DO
+// Spec: spec/providers/cloud/digitalocean.md
+
+package digitalocean
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+)
+
+// tagStagecraft marks every droplet Stagecraft creates.
+const tagStagecraft = "stagecraft"
+
+// projectTagPrefix starts the tag naming a droplet's project.
+const projectTagPrefix = "stagecraft-project-"
+
+// projectTag returns the droplet tag naming a project. A name that is
+// already a valid tag (lowercase letters, digits, '-' and '_') is used as
+// is. Otherwise characters DigitalOcean does not allow in tags become
+// hyphens, letters are lowercased, and a short hash of the raw name is
+// appended, so "my.app", "My-App" and "my-app" get distinct tags.
+func projectTag(project string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, project)
+	if slug != project {
+		sum := sha256.Sum256([]byte(project))
+		slug += "-" + hex.EncodeToString(sum[:4])
+	}
+	return projectTagPrefix + slug
+}
+
+// dropletTags returns the tags of every droplet of a project's environment.
+// Listing by all of them scopes reconciliation to droplets Stagecraft
+// created there, whatever else is in the account. Without a project only
+// the environment scopes.
+func dropletTags(project, env string) []string {
+	tags := []string{tagStagecraft}
+	if project != "" {
+		tags = append(tags, projectTag(project))
+	}
+	return append(tags, envTag(env))
+}
+
+// dropletFilter lists the droplets of a project's environment.
+func dropletFilter(project, env string) DropletFilter {
+	return DropletFilter{NamePrefix: env + "-", Tags: dropletTags(project, env)}
+}
+
+// untaggedProject reports whether d is an environment droplet created
+// before droplets were tagged with their project: it carries the
+// Stagecraft and environment tags but no project tag.
+func untaggedProject(d *Droplet, env string) bool {
+	if !hasAllTags(d.Tags, []string{tagStagecraft, envTag(env)}) {
+		return false
+	}
+	return !slices.ContainsFunc(d.Tags, func(tag string) bool {
+		return strings.HasPrefix(tag, projectTagPrefix)
+	})
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_USER_DATA
+// Spec: spec/providers/cloud/user-data.md
+
+package digitalocean
+
+import (
+	"fmt"
+
+	"stagecraft/internal/infra/cloudinit"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// renderUserData renders the cloud-init template configured for the host's
+// role. Hosts whose role has no template get no user data.
+//
+//nolint:gocritic // hugeParam: host is passed by value like the rest of Apply
+func renderUserData(config *Config, env string, host cloud.HostSpec, sshKey *SSHKey) (string, error) {
+	if config.UserData == nil {
+		return "", nil
+	}
+	ref, ok := config.UserData.Roles[host.Role]
+	if !ok {
+		return "", nil
+	}
+
+	text, err := cloudinit.Load(ref)
+	if err != nil {
+		return "", fmt.Errorf("%w: user_data.roles.%s: %v", ErrConfigInvalid, host.Role, err)
+	}
+
+	var sshKeys []string
+	if sshKey != nil && sshKey.PublicKey != "" {
+		sshKeys = []string{sshKey.PublicKey}
+	}
+
+	userData, err := cloudinit.Render(host.Role, text, cloudinit.Data{
+		Host:              host.Name,
+		Environment:       env,
+		Role:              host.Role,
+		TailnetAuthKeyEnv: config.UserData.TailnetAuthKeyEnv,
+		SSHKeys:           sshKeys,
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: user_data for host %s: %v", ErrConfigInvalid, host.Name, err)
+	}
+	return userData, nil
+}
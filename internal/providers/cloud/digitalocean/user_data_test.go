@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_USER_DATA
+// Spec: spec/providers/cloud/user-data.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+func userDataTestConfig(roles map[string]any) map[string]any {
+	return map[string]any{
+		"token_env":      "DO_TOKEN",
+		"ssh_key_name":   "my-ssh-key",
+		"default_region": "nyc3",
+		"default_size":   "s-1vcpu-1gb",
+		"hosts": map[string]any{
+			"prod": map[string]any{
+				"app-1": map[string]any{"role": "app"},
+				"db-1":  map[string]any{"role": "db"},
+			},
+		},
+		"user_data": map[string]any{
+			"tailnet_auth_key_env": "TS_AUTHKEY_USERDATA",
+			"roles":                roles,
+		},
+	}
+}
+
+func TestDigitalOceanProvider_Apply_RendersUserDataPerRole(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+	t.Setenv("TS_AUTHKEY_USERDATA", "tskey-abc")
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	tmpl := "#cloud-config\nhostname: {{ .Environment }}-{{ .Host }}\n# key: {{ index .SSHKeys 0 }}\n# ts: {{ env .TailnetAuthKeyEnv }}\n"
+	if err := os.WriteFile(path, []byte(tmpl), 0o600); err != nil {
+		t.Fatalf("writing template: %v", err)
+	}
+
+	ctx := context.Background()
+	mockClient := &mockAPIClient{
+		sshKeys: map[string]SSHKey{"my-ssh-key": {ID: 1, Name: "my-ssh-key", PublicKey: "ssh-ed25519 AAAA deploy"}},
+	}
+	provider := NewDigitalOceanProviderWithClient(mockClient)
+	cfg := userDataTestConfig(map[string]any{"app": path})
+
+	plan, err := provider.Plan(ctx, cloud.PlanOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if err := provider.Apply(ctx, cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan}); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+
+	if len(mockClient.created) != 2 {
+		t.Fatalf("expected 2 droplets created, got %d", len(mockClient.created))
+	}
+	app, db := mockClient.created[0], mockClient.created[1]
+
+	want := "#cloud-config\nhostname: prod-app-1\n# key: ssh-ed25519 AAAA deploy\n# ts: tskey-abc\n"
+	if app.UserData != want {
+		t.Errorf("app user data =\n%s\nwant\n%s", app.UserData, want)
+	}
+	if db.UserData != "" {
+		t.Errorf("expected no user data for role without template, got:\n%s", db.UserData)
+	}
+}
+
+func TestDigitalOceanProvider_Apply_BuiltinUserData(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+	t.Setenv("TS_AUTHKEY_USERDATA", "tskey-abc")
+
+	ctx := context.Background()
+	mockClient := &mockAPIClient{
+		sshKeys: map[string]SSHKey{"my-ssh-key": {ID: 1, Name: "my-ssh-key"}},
+	}
+	provider := NewDigitalOceanProviderWithClient(mockClient)
+	cfg := userDataTestConfig(map[string]any{"db": "builtin"})
+
+	plan, err := provider.Plan(ctx, cloud.PlanOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if err := provider.Apply(ctx, cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan}); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+
+	db := mockClient.created[1]
+	if !strings.HasPrefix(db.UserData, "#cloud-config\nhostname: db-1\n") || !strings.Contains(db.UserData, "--authkey='tskey-abc'") {
+		t.Errorf("unexpected builtin user data:\n%s", db.UserData)
+	}
+}
+
+func TestDigitalOceanProvider_Apply_UserDataMissingAuthKeyFailsBeforeCreate(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	ctx := context.Background()
+	mockClient := &mockAPIClient{
+		sshKeys: map[string]SSHKey{"my-ssh-key": {ID: 1, Name: "my-ssh-key"}},
+	}
+	provider := NewDigitalOceanProviderWithClient(mockClient)
+	cfg := userDataTestConfig(map[string]any{"app": "builtin"})
+
+	plan, err := provider.Plan(ctx, cloud.PlanOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	err = provider.Apply(ctx, cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan})
+	if !errors.Is(err, ErrConfigInvalid) || !strings.Contains(err.Error(), "TS_AUTHKEY_USERDATA is not set") {
+		t.Fatalf("expected ErrConfigInvalid for missing auth key, got %v", err)
+	}
+	if len(mockClient.created) != 0 {
+		t.Errorf("expected no droplets created, got %d", len(mockClient.created))
+	}
+}
+
+func TestParseConfig_UserDataRequiresRoles(t *testing.T) {
+	cfg := userDataTestConfig(nil)
+	if _, err := parseConfig(cfg); !errors.Is(err, ErrConfigInvalid) {
+		t.Fatalf("expected ErrConfigInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_VOLUMES
+// Spec: spec/providers/cloud/volumes.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// volumeName returns the DigitalOcean volume name for a host's volume.
+func volumeName(env, host, name string) string {
+	return env + "-" + host + "-" + name
+}
+
+// volumeDevice returns the stable device path DigitalOcean exposes for a volume.
+func volumeDevice(fullName string) string {
+	return "/dev/disk/by-id/scsi-0DO_Volume_" + fullName
+}
+
+// hostVolumes returns the volumes declared for a host as attached volumes,
+// sorted by name.
+func hostVolumes(env, host string, hostCfg HostConfig) []cloud.HostVolume {
+	volumes := make([]cloud.HostVolume, 0, len(hostCfg.Volumes))
+	for _, v := range hostCfg.Volumes {
+		volumes = append(volumes, cloud.HostVolume{
+			Name:       v.Name,
+			Device:     volumeDevice(volumeName(env, host, v.Name)),
+			Filesystem: v.Filesystem,
+			MountPoint: v.MountPoint,
+		})
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+	return volumes
+}
+
+// planVolumes returns the volumes to create or attach, ordered by host then
+// volume name. A volume already attached to its host's droplet is skipped.
+func (p *DigitalOceanProvider) planVolumes(ctx context.Context, config *Config, env string, envHosts map[string]HostConfig, actual map[string]Droplet) ([]cloud.VolumeSpec, error) {
+	if !wantsVolumes(envHosts) {
+		return nil, nil
+	}
+
+	existing, err := p.client.ListVolumes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAPIError, err)
+	}
+	byName := make(map[string]Volume, len(existing))
+	for _, v := range existing {
+		byName[v.Name] = v
+	}
+
+	attached := func(host string, v VolumeConfig) bool {
+		vol, ok := byName[volumeName(env, host, v.Name)]
+		d, exists := actual[host]
+		return ok && exists && containsInt(vol.DropletIDs, d.ID)
+	}
+	return volumeSpecs(config, envHosts, attached), nil
+}
+
+// wantsVolumes reports whether any host declares volumes.
+func wantsVolumes(envHosts map[string]HostConfig) bool {
+	for _, hostCfg := range envHosts {
+		if len(hostCfg.Volumes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// volumeSpecs returns the declared volumes that attached reports as not in
+// place, ordered by host then volume name.
+func volumeSpecs(config *Config, envHosts map[string]HostConfig, attached func(host string, v VolumeConfig) bool) []cloud.VolumeSpec {
+	var hosts []string
+	for name, hostCfg := range envHosts {
+		if len(hostCfg.Volumes) > 0 {
+			hosts = append(hosts, name)
+		}
+	}
+	sort.Strings(hosts)
+
+	var specs []cloud.VolumeSpec
+	for _, host := range hosts {
+		hostCfg := envHosts[host]
+		volumes := append([]VolumeConfig(nil), hostCfg.Volumes...)
+		sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+
+		for _, v := range volumes {
+			if attached(host, v) {
+				continue
+			}
+			specs = append(specs, cloud.VolumeSpec{
+				Host:       host,
+				Name:       v.Name,
+				SizeGB:     v.SizeGB,
+				Filesystem: v.Filesystem,
+				MountPoint: v.MountPoint,
+				Region:     firstNonEmpty(hostCfg.Region, config.DefaultRegion),
+			})
+		}
+	}
+	return specs
+}
+
+// applyVolumes creates missing volumes and attaches them to their droplets.
+// Volumes that exist are reused and volumes already attached are left alone,
+// so re-applying a plan is safe.
+func (p *DigitalOceanProvider) applyVolumes(ctx context.Context, env string, specs []cloud.VolumeSpec) error {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	sorted := append([]cloud.VolumeSpec(nil), specs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Host != sorted[j].Host {
+			return sorted[i].Host < sorted[j].Host
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	existing, err := p.client.ListVolumes(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAPIError, err)
+	}
+	byName := make(map[string]Volume, len(existing))
+	for _, v := range existing {
+		byName[v.Name] = v
+	}
+
+	for _, spec := range sorted {
+		dropletName := env + "-" + spec.Host
+		fullName := volumeName(env, spec.Host, spec.Name)
+
+		droplet, err := p.client.GetDroplet(ctx, dropletName)
+		if err != nil {
+			if errors.Is(err, ErrDropletNotFound) {
+				return fmt.Errorf("%w: droplet %q not found for volume %q", ErrVolumeFailed, dropletName, fullName)
+			}
+			return fmt.Errorf("%w: %v", ErrAPIError, err)
+		}
+
+		vol, ok := byName[fullName]
+		if !ok {
+			created, err := p.client.CreateVolume(ctx, CreateVolumeRequest{
+				Name:           fullName,
+				Region:         spec.Region,
+				SizeGigaBytes:  spec.SizeGB,
+				FilesystemType: spec.Filesystem,
+				Tags:           []string{"stagecraft", envTag(env)},
+			})
+			if err != nil {
+				return fmt.Errorf("%w: creating volume %q: %v", ErrVolumeFailed, fullName, err)
+			}
+			vol = *created
+		}
+
+		if containsInt(vol.DropletIDs, droplet.ID) {
+			continue
+		}
+		if err := p.client.AttachVolume(ctx, vol.ID, droplet.ID); err != nil {
+			return fmt.Errorf("%w: attaching volume %q to %q: %v", ErrVolumeFailed, fullName, dropletName, err)
+		}
+	}
+
+	return nil
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
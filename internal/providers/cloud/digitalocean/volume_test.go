@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_CLOUD_VOLUMES
+// Spec: spec/providers/cloud/volumes.md
+
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+func volumeTestConfig(volumes ...any) map[string]any {
+	return map[string]any{
+		"token_env":      "DO_TOKEN",
+		"ssh_key_name":   "my-ssh-key",
+		"default_region": "nyc3",
+		"default_size":   "s-2vcpu-4gb",
+		"hosts": map[string]any{
+			"prod": map[string]any{
+				"db-1": map[string]any{"role": "db", "volumes": volumes},
+			},
+		},
+	}
+}
+
+func TestDigitalOceanProvider_Volumes_PlanApply(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	ctx := context.Background()
+	mockClient := &mockAPIClient{
+		sshKeys: map[string]SSHKey{"my-ssh-key": {ID: 1, Name: "my-ssh-key"}},
+	}
+	provider := NewDigitalOceanProviderWithClient(mockClient)
+	cfg := volumeTestConfig(
+		map[string]any{"name": "logs", "size_gb": 10, "filesystem": "xfs", "mount_point": "/var/log/app"},
+		map[string]any{"name": "data", "size_gb": 100, "mount_point": "/mnt/data"},
+	)
+
+	plan, err := provider.Plan(ctx, cloud.PlanOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if len(plan.Volumes) != 2 {
+		t.Fatalf("plan.Volumes = %+v, want two", plan.Volumes)
+	}
+	if got := plan.Volumes[0]; got.Name != "data" || got.SizeGB != 100 || got.Filesystem != "ext4" || got.Region != "nyc3" {
+		t.Errorf("plan.Volumes[0] = %+v, want data/100GB/ext4/nyc3", got)
+	}
+	if got := plan.Volumes[1]; got.Name != "logs" || got.Filesystem != "xfs" {
+		t.Errorf("plan.Volumes[1] = %+v, want logs/xfs", got)
+	}
+
+	if err := provider.Apply(ctx, cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan}); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+
+	droplet := mockClient.droplets["prod-db-1"]
+	if len(mockClient.volumes) != 2 {
+		t.Fatalf("expected two volumes created, got %+v", mockClient.volumes)
+	}
+	for _, v := range mockClient.volumes {
+		if !strings.HasPrefix(v.Name, "prod-db-1-") || len(v.DropletIDs) != 1 || v.DropletIDs[0] != droplet.ID {
+			t.Errorf("volume %+v not attached to droplet %d", v, droplet.ID)
+		}
+	}
+
+	// Re-planning is a no-op and re-applying the stale plan attaches nothing twice.
+	again, err := provider.Plan(ctx, cloud.PlanOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if len(again.Volumes) != 0 {
+		t.Errorf("expected no volume changes after apply, got %+v", again.Volumes)
+	}
+	if err := provider.Apply(ctx, cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan}); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+	for _, v := range mockClient.volumes {
+		if len(v.DropletIDs) != 1 {
+			t.Errorf("volume %s attached %d times", v.Name, len(v.DropletIDs))
+		}
+	}
+}
+
+func TestDigitalOceanProvider_Volumes_AttachesExistingVolume(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	ctx := context.Background()
+	mockClient := &mockAPIClient{
+		sshKeys:  map[string]SSHKey{"my-ssh-key": {ID: 1, Name: "my-ssh-key"}},
+		droplets: map[string]Droplet{"prod-db-1": {ID: 5, Name: "prod-db-1", Tags: []string{"stagecraft", "stagecraft-env-prod"}, Region: "nyc3", Size: "s-2vcpu-4gb"}},
+		volumes:  []Volume{{ID: "vol-1", Name: "prod-db-1-data", Region: "nyc3", SizeGigaBytes: 100}},
+	}
+	provider := NewDigitalOceanProviderWithClient(mockClient)
+	cfg := volumeTestConfig(map[string]any{"name": "data", "size_gb": 100, "mount_point": "/mnt/data"})
+
+	plan, err := provider.Plan(ctx, cloud.PlanOptions{Config: cfg, Environment: "prod"})
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if err := provider.Apply(ctx, cloud.ApplyOptions{Config: cfg, Environment: "prod", Plan: plan}); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+
+	if len(mockClient.volumes) != 1 || len(mockClient.volumes[0].DropletIDs) != 1 || mockClient.volumes[0].DropletIDs[0] != 5 {
+		t.Fatalf("expected existing volume attached to droplet 5, got %+v", mockClient.volumes)
+	}
+}
+
+func TestDigitalOceanProvider_Volumes_CreateFails(t *testing.T) {
+	t.Setenv("DO_TOKEN", "dummy-token")
+
+	mockClient := &mockAPIClient{
+		sshKeys:  map[string]SSHKey{"my-ssh-key": {ID: 1, Name: "my-ssh-key"}},
+		droplets: map[string]Droplet{"prod-db-1": {ID: 5, Name: "prod-db-1", Tags: []string{"stagecraft", "stagecraft-env-prod"}}},
+	}
+	provider := NewDigitalOceanProviderWithClient(mockClient)
+
+	mockClient.volumeErr = errors.New("quota exceeded")
+	err := provider.Apply(context.Background(), cloud.ApplyOptions{
+		Config:      volumeTestConfig(map[string]any{"name": "data", "size_gb": 100, "mount_point": "/mnt/data"}),
+		Environment: "prod",
+		Plan: cloud.InfraPlan{
+			Volumes: []cloud.VolumeSpec{{Host: "db-1", Name: "data", SizeGB: 100, Filesystem: "ext4", MountPoint: "/mnt/data", Region: "nyc3"}},
+		},
+	})
+	if !errors.Is(err, ErrAPIError) {
+		t.Fatalf("expected ErrAPIError, got %v", err)
+	}
+}
+
+func TestParseConfig_VolumeValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		volumes []any
+		wantErr string
+	}{
+		{
+			name:    "invalid name",
+			volumes: []any{map[string]any{"name": "Data_1", "size_gb": 10, "mount_point": "/mnt/data"}},
+			wantErr: "lowercase",
+		},
+		{
+			name:    "missing size",
+			volumes: []any{map[string]any{"name": "data", "mount_point": "/mnt/data"}},
+			wantErr: "size_gb",
+		},
+		{
+			name:    "unsupported filesystem",
+			volumes: []any{map[string]any{"name": "data", "size_gb": 10, "filesystem": "btrfs", "mount_point": "/mnt/data"}},
+			wantErr: "ext4 or xfs",
+		},
+		{
+			name:    "relative mount point",
+			volumes: []any{map[string]any{"name": "data", "size_gb": 10, "mount_point": "mnt/data"}},
+			wantErr: "absolute path",
+		},
+		{
+			name: "duplicate mount point",
+			volumes: []any{
+				map[string]any{"name": "a", "size_gb": 10, "mount_point": "/mnt/data"},
+				map[string]any{"name": "b", "size_gb": 10, "mount_point": "/mnt/data"},
+			},
+			wantErr: "duplicate mount_point",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConfig(volumeTestConfig(tt.volumes...))
+			if !errors.Is(err, ErrConfigInvalid) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("parseConfig() error = %v, want ErrConfigInvalid containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -18,14 +18,14 @@ package generic
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
-	"strings"
 	"sync"
-	"syscall"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -44,6 +44,12 @@ type GenericProvider struct{}
 // Ensure GenericProvider implements FrontendProvider
 var _ frontend.FrontendProvider = (*GenericProvider)(nil)
 
+// Ensure GenericProvider implements frontend.MetadataProvider
+var _ frontend.MetadataProvider = (*GenericProvider)(nil)
+
+// Ensure GenericProvider implements frontend.StaticBuilder
+var _ frontend.StaticBuilder = (*GenericProvider)(nil)
+
 // scanStream implements the core ready-pattern detection logic.
 // This function is intentionally pure and synchronous so it can be tested
 // deterministically without goroutines, pipes, or OS-level behavior.
@@ -81,6 +87,17 @@ func (p *GenericProvider) ID() string {
 	return "generic"
 }
 
+// Metadata returns descriptive metadata about the provider.
+func (p *GenericProvider) Metadata() frontend.ProviderMetadata {
+	return frontend.ProviderMetadata{
+		Name:         "Generic",
+		Description:  "Runs an arbitrary frontend dev server command and static build",
+		Version:      "v1",
+		Author:       "Stagecraft",
+		ConfigSchema: Config{},
+	}
+}
+
 // Config represents the generic provider configuration.
 type Config struct {
 	Dev struct {
@@ -93,6 +110,13 @@ type Config struct {
 			TimeoutMS int    `yaml:"timeout_ms"`
 		} `yaml:"shutdown"`
 	} `yaml:"dev"`
+
+	Build struct {
+		Command   []string          `yaml:"command"`
+		WorkDir   string            `yaml:"workdir"`
+		Env       map[string]string `yaml:"env"`
+		OutputDir string            `yaml:"output_dir"`
+	} `yaml:"build"`
 }
 
 // Dev runs the frontend in development mode.
@@ -106,7 +130,7 @@ func (p *GenericProvider) Dev(ctx context.Context, opts frontend.DevOptions) err
 		return fmt.Errorf("generic provider: dev.command is required")
 	}
 
-	workDir := cfg.Dev.WorkDir
+	workDir := normalizePath(cfg.Dev.WorkDir)
 	if workDir == "" {
 		workDir = opts.WorkDir
 	}
@@ -125,8 +149,9 @@ func (p *GenericProvider) Dev(ctx context.Context, opts frontend.DevOptions) err
 
 	// Build command
 	//nolint:gosec // commands and args are trusted operator config from stagecraft.yml, not user input
-	cmd := exec.CommandContext(ctx, cfg.Dev.Command[0], cfg.Dev.Command[1:]...)
+	cmd := exec.CommandContext(ctx, normalizePath(cfg.Dev.Command[0]), cfg.Dev.Command[1:]...)
 	cmd.Dir = workDir
+	configureProcess(cmd, true)
 
 	// Set environment
 	cmd.Env = os.Environ()
@@ -149,6 +174,67 @@ func (p *GenericProvider) Dev(ctx context.Context, opts frontend.DevOptions) err
 	return p.runWithShutdown(ctx, cmd, cfg.Dev.Shutdown)
 }
 
+// Build runs build.command and returns the absolute path of build.output_dir.
+func (p *GenericProvider) Build(ctx context.Context, opts frontend.BuildOptions) (string, error) {
+	cfg, err := p.parseConfig(opts.Config)
+	if err != nil {
+		return "", fmt.Errorf("parsing generic provider config: %w", err)
+	}
+
+	if len(cfg.Build.Command) == 0 {
+		return "", fmt.Errorf("generic provider: build.command is required")
+	}
+	if cfg.Build.OutputDir == "" {
+		return "", fmt.Errorf("generic provider: build.output_dir is required")
+	}
+
+	workDir := normalizePath(cfg.Build.WorkDir)
+	if workDir == "" {
+		workDir = opts.WorkDir
+	}
+	if workDir == "" {
+		workDir = "."
+	}
+	workDir, err = filepath.Abs(workDir)
+	if err != nil {
+		return "", fmt.Errorf("resolving build workdir: %w", err)
+	}
+
+	//nolint:gosec // commands and args are trusted operator config from stagecraft.yml, not user input
+	cmd := exec.CommandContext(ctx, normalizePath(cfg.Build.Command[0]), cfg.Build.Command[1:]...)
+	cmd.Dir = workDir
+	configureProcess(cmd, false)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Merge provider env with opts.Env (opts.Env takes precedence)
+	cmd.Env = os.Environ()
+	for k, v := range cfg.Build.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	for k, v := range opts.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("frontend build failed: %w", err)
+	}
+
+	outputDir := normalizePath(cfg.Build.OutputDir)
+	if !filepath.IsAbs(outputDir) {
+		outputDir = filepath.Join(workDir, outputDir)
+	}
+	info, err := os.Stat(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("generic provider: build output %s: %w", outputDir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("generic provider: build output %s is not a directory", outputDir)
+	}
+
+	return outputDir, nil
+}
+
 // runWithReadyPattern runs the command and watches for a ready pattern.
 func (p *GenericProvider) runWithReadyPattern(ctx context.Context, cmd *exec.Cmd, pattern string, shutdownCfg struct {
 	Signal    string `yaml:"signal"`
@@ -212,7 +298,7 @@ func (p *GenericProvider) runWithReadyPattern(ctx context.Context, cmd *exec.Cmd
 		}
 	case err := <-errCh:
 		// Error reading output
-		_ = cmd.Process.Kill()
+		_ = killProcessTree(cmd.Process)
 		_ = cmd.Wait()
 		return err
 	case <-ctx.Done():
@@ -268,27 +354,10 @@ func (p *GenericProvider) shutdownProcess(cmd *exec.Cmd, shutdownCfg struct {
 		return nil
 	}
 
-	// Determine signal (default: SIGINT)
-	sig := syscall.SIGINT
-	if shutdownCfg.Signal != "" {
-		switch strings.ToUpper(shutdownCfg.Signal) {
-		case "SIGINT":
-			sig = syscall.SIGINT
-		case "SIGTERM":
-			sig = syscall.SIGTERM
-		case "SIGKILL":
-			sig = syscall.SIGKILL
-		default:
-			// Try to parse as signal number or name
-			// For now, default to SIGINT if unknown
-			sig = syscall.SIGINT
-		}
-	}
-
 	// Send signal
-	if err := cmd.Process.Signal(sig); err != nil {
+	if err := signalProcess(cmd.Process, parseShutdownSignal(shutdownCfg.Signal)); err != nil {
 		// Process may have already exited
-		if err.Error() == "os: process already finished" {
+		if errors.Is(err, os.ErrProcessDone) {
 			return nil
 		}
 		return fmt.Errorf("sending shutdown signal: %w", err)
@@ -312,7 +381,7 @@ func (p *GenericProvider) shutdownProcess(cmd *exec.Cmd, shutdownCfg struct {
 		return nil
 	case <-time.After(timeout):
 		// Timeout reached, force kill
-		if err := cmd.Process.Kill(); err != nil {
+		if err := killProcessTree(cmd.Process); err != nil {
 			return fmt.Errorf("force killing process: %w", err)
 		}
 		_ = cmd.Wait() // Clean up
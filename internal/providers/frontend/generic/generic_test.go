@@ -1298,3 +1298,79 @@ func BenchmarkScanStream_LargeInput(b *testing.B) {
 		}
 	}
 }
+
+func TestGenericProvider_Build_ReturnsAbsoluteOutputDir(t *testing.T) {
+	p := &GenericProvider{}
+	workDir := t.TempDir()
+
+	opts := frontend.BuildOptions{
+		Config: map[string]any{
+			"build": map[string]any{
+				"command":    []string{"sh", "-c", `mkdir -p dist && echo "$STAGE" > dist/index.html`},
+				"env":        map[string]string{"STAGE": "provider"},
+				"output_dir": "dist",
+			},
+		},
+		WorkDir: workDir,
+		Env:     map[string]string{"STAGE": "opts"},
+	}
+
+	dir, err := p.Build(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if dir != filepath.Join(workDir, "dist") {
+		t.Fatalf("Build() = %q, want %q", dir, filepath.Join(workDir, "dist"))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatalf("reading build output: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != "opts" {
+		t.Errorf("expected opts.Env to take precedence, got %q", data)
+	}
+}
+
+func TestGenericProvider_Build_Errors(t *testing.T) {
+	p := &GenericProvider{}
+
+	tests := []struct {
+		name    string
+		build   map[string]any
+		wantErr string
+	}{
+		{
+			name:    "missing command",
+			build:   map[string]any{"output_dir": "dist"},
+			wantErr: "build.command is required",
+		},
+		{
+			name:    "missing output dir",
+			build:   map[string]any{"command": []string{"true"}},
+			wantErr: "build.output_dir is required",
+		},
+		{
+			name:    "command fails",
+			build:   map[string]any{"command": []string{"false"}, "output_dir": "dist"},
+			wantErr: "frontend build failed",
+		},
+		{
+			name:    "output not produced",
+			build:   map[string]any{"command": []string{"true"}, "output_dir": "dist"},
+			wantErr: "build output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.Build(context.Background(), frontend.BuildOptions{
+				Config:  map[string]any{"build": tt.build},
+				WorkDir: t.TempDir(),
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Build() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
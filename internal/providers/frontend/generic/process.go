@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package generic
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Shutdown signal names accepted in dev.shutdown.signal.
+const (
+	signalInt  = "SIGINT"
+	signalTerm = "SIGTERM"
+	signalKill = "SIGKILL"
+)
+
+// parseShutdownSignal returns the canonical name of a configured shutdown
+// signal. Empty and unknown names default to SIGINT.
+func parseShutdownSignal(name string) string {
+	switch upper := strings.ToUpper(name); upper {
+	case signalInt, signalTerm, signalKill:
+		return upper
+	default:
+		return signalInt
+	}
+}
+
+// normalizePath converts a slash-separated path from stagecraft.yml to the
+// platform's separator, so configs written on Unix work on Windows.
+func normalizePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return filepath.FromSlash(path)
+}
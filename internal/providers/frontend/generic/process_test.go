@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package generic
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseShutdownSignal(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":        signalInt,
+		"SIGINT":  signalInt,
+		"sigterm": signalTerm,
+		"SIGKILL": signalKill,
+		"SIGHUP":  signalInt,
+	}
+	for name, want := range tests {
+		if got := parseShutdownSignal(name); got != want {
+			t.Errorf("parseShutdownSignal(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	t.Parallel()
+
+	if got := normalizePath(""); got != "" {
+		t.Errorf("normalizePath(\"\") = %q, want empty", got)
+	}
+
+	want := filepath.Join("apps", "web", "dist")
+	if got := normalizePath("apps/web/dist"); got != want {
+		t.Errorf("normalizePath = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+//go:build !windows
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package generic
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// configureProcess prepares cmd for signal-based shutdown. On Unix the
+// defaults already deliver signals to the process.
+func configureProcess(_ *exec.Cmd, _ bool) {}
+
+// signalProcess sends the named shutdown signal to the process.
+func signalProcess(p *os.Process, name string) error {
+	switch name {
+	case signalTerm:
+		return p.Signal(syscall.SIGTERM)
+	case signalKill:
+		return p.Signal(syscall.SIGKILL)
+	default:
+		return p.Signal(syscall.SIGINT)
+	}
+}
+
+// killProcessTree force-kills the process.
+func killProcessTree(p *os.Process) error {
+	return p.Kill()
+}
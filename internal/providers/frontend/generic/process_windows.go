@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+//go:build windows
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package generic
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// Windows has no POSIX signals. The dev command runs in its own console
+// process group so it can be sent CTRL_BREAK_EVENT, which Node and most
+// dev servers treat like SIGINT. Force kills go through taskkill /T so the
+// children of npm.cmd or yarn shims do not outlive the parent.
+
+// ctrlBreakEvent is CTRL_BREAK_EVENT; CTRL_C_EVENT cannot target a
+// process group.
+const ctrlBreakEvent = 1
+
+// errorInvalidParameter is ERROR_INVALID_PARAMETER, returned when the
+// target process group no longer exists.
+const errorInvalidParameter syscall.Errno = 87
+
+var procGenerateConsoleCtrlEvent = syscall.NewLazyDLL("kernel32.dll").NewProc("GenerateConsoleCtrlEvent")
+
+// configureProcess starts cmd in a new process group. When graceful is
+// true, context cancellation leaves shutdown to shutdownProcess instead of
+// exec's default TerminateProcess; otherwise it kills the whole tree.
+func configureProcess(cmd *exec.Cmd, graceful bool) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+
+	cmd.Cancel = func() error {
+		if graceful {
+			return nil
+		}
+		return killProcessTree(cmd.Process)
+	}
+}
+
+// signalProcess delivers the named shutdown signal: SIGKILL kills the
+// process tree, SIGINT and SIGTERM send CTRL_BREAK_EVENT to its group.
+func signalProcess(p *os.Process, name string) error {
+	if name == signalKill {
+		return killProcessTree(p)
+	}
+
+	r, _, err := procGenerateConsoleCtrlEvent.Call(ctrlBreakEvent, uintptr(p.Pid))
+	if r == 0 {
+		if errors.Is(err, errorInvalidParameter) {
+			// No such process group: the process already exited.
+			return os.ErrProcessDone
+		}
+		return fmt.Errorf("GenerateConsoleCtrlEvent: %w", err)
+	}
+	return nil
+}
+
+// killProcessTree force-kills the process and all of its children. It
+// falls back to killing only the process when taskkill is unavailable.
+func killProcessTree(p *os.Process) error {
+	//nolint:gosec // pid is from a process we started
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(p.Pid))
+	if err := kill.Run(); err != nil {
+		return p.Kill()
+	}
+	return nil
+}
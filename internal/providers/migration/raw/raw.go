@@ -38,11 +38,25 @@ type Engine struct{}
 // Ensure Engine implements migration.Engine.
 var _ migration.Engine = (*Engine)(nil)
 
+// Ensure Engine implements migration.MetadataProvider
+var _ migration.MetadataProvider = (*Engine)(nil)
+
 // ID returns the engine identifier.
 func (e *Engine) ID() string {
 	return "raw"
 }
 
+// Metadata returns descriptive metadata about the provider.
+func (e *Engine) Metadata() migration.ProviderMetadata {
+	return migration.ProviderMetadata{
+		Name:         "Raw SQL",
+		Description:  "Applies plain .sql migration files in lexicographic order",
+		Version:      "v1",
+		Author:       "Stagecraft",
+		ConfigSchema: Config{},
+	}
+}
+
 // Config represents the raw engine configuration.
 type Config struct {
 	// Additional engine-specific config can be added here
@@ -82,10 +96,16 @@ func (e *Engine) Plan(ctx context.Context, opts migration.PlanOptions) ([]migrat
 			continue
 		}
 
+		irreversible, err := isIrreversible(filepath.Join(migrationPath, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", entry.Name(), err)
+		}
+
 		migrations = append(migrations, migration.Migration{
-			ID:          entry.Name(),
-			Description: fmt.Sprintf("SQL migration: %s", entry.Name()),
-			Applied:     false, // Raw engine doesn't track state in v1
+			ID:           entry.Name(),
+			Description:  fmt.Sprintf("SQL migration: %s", entry.Name()),
+			Applied:      false, // Raw engine doesn't track state in v1
+			Irreversible: irreversible,
 		})
 	}
 
@@ -97,6 +117,26 @@ func (e *Engine) Plan(ctx context.Context, opts migration.PlanOptions) ([]migrat
 	return migrations, nil
 }
 
+// IrreversibleMarker, on a line of its own, marks a SQL migration as
+// irreversible.
+const IrreversibleMarker = "-- stagecraft:irreversible"
+
+// isIrreversible reports whether the SQL file at path carries
+// IrreversibleMarker.
+func isIrreversible(path string) (bool, error) {
+	// #nosec G304 -- path is a migration file in the configured directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == IrreversibleMarker {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Run executes migrations.
 //
 // nolint:gocritic // opts is passed by value to satisfy migration.Engine interface.
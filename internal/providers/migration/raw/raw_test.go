@@ -72,6 +72,38 @@ func TestRawEngine_Plan(t *testing.T) {
 	}
 }
 
+func TestRawEngine_Plan_IrreversibleMarker(t *testing.T) {
+	e := &Engine{}
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"001_initial.sql":     "CREATE TABLE users (id SERIAL PRIMARY KEY);\n",
+		"002_drop_legacy.sql": "-- Drops the legacy table for good.\n  -- stagecraft:irreversible\nDROP TABLE legacy;\n",
+		"003_mention.sql":     "-- not -- stagecraft:irreversible\nSELECT 1;\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to create migration file: %v", err)
+		}
+	}
+
+	migrations, err := e.Plan(context.Background(), migration.PlanOptions{MigrationPath: tmpDir})
+	if err != nil {
+		t.Fatalf("Plan() error = %v, want nil", err)
+	}
+
+	want := map[string]bool{
+		"001_initial.sql":     false,
+		"002_drop_legacy.sql": true,
+		"003_mention.sql":     false,
+	}
+	for _, m := range migrations {
+		if m.Irreversible != want[m.ID] {
+			t.Errorf("%s: Irreversible = %v, want %v", m.ID, m.Irreversible, want[m.ID])
+		}
+	}
+}
+
 func TestRawEngine_Plan_EmptyDirectory(t *testing.T) {
 	e := &Engine{}
 	tmpDir := t.TempDir()
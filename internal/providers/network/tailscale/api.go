@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_NETWORK_TAILSCALE_POLICY
+// Spec: spec/providers/network/tailscale-policy.md
+
+package tailscale
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultAPIBaseURL is the Tailscale control plane API endpoint.
+const DefaultAPIBaseURL = "https://api.tailscale.com"
+
+// APIClient defines the Tailscale API operations the provider uses.
+// This interface enables dependency injection for testing.
+type APIClient interface {
+	// GetACL fetches the tailnet policy file.
+	GetACL(ctx context.Context, tailnet, apiKey string) (*ACLPolicy, error)
+
+	// SetKeyExpiryDisabled enables or disables node key expiry for a device.
+	SetKeyExpiryDisabled(ctx context.Context, deviceID, apiKey string, disabled bool) error
+
+	// GetKey fetches the metadata of a tailnet key (never its secret).
+	GetKey(ctx context.Context, tailnet, keyID, apiKey string) (*KeyInfo, error)
+}
+
+// KeyInfo is the subset of a tailnet key's metadata Stagecraft inspects.
+type KeyInfo struct {
+	ID      string     `json:"id"`
+	Expires time.Time  `json:"expires"`
+	Revoked *time.Time `json:"revoked,omitempty"`
+	Invalid bool       `json:"invalid"`
+}
+
+// ACLPolicy is the subset of the tailnet policy file Stagecraft inspects.
+type ACLPolicy struct {
+	// TagOwners maps each tag to the users or groups allowed to apply it.
+	// A tag that is not declared here cannot be advertised.
+	TagOwners map[string][]string `json:"tagOwners"`
+
+	// SSH holds the Tailscale SSH access rules.
+	SSH []ACLSSHRule `json:"ssh"`
+}
+
+// ACLSSHRule is a single Tailscale SSH rule.
+type ACLSSHRule struct {
+	Action string   `json:"action"`
+	Src    []string `json:"src"`
+	Dst    []string `json:"dst"`
+	Users  []string `json:"users"`
+}
+
+// HTTPAPIClient is the APIClient backed by the Tailscale HTTP API.
+type HTTPAPIClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewHTTPAPIClient returns an APIClient for the public Tailscale API.
+func NewHTTPAPIClient() *HTTPAPIClient {
+	return &HTTPAPIClient{
+		BaseURL:    DefaultAPIBaseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GetACL fetches the tailnet policy file as JSON.
+func (c *HTTPAPIClient) GetACL(ctx context.Context, tailnet, apiKey string) (*ACLPolicy, error) {
+	endpoint := fmt.Sprintf("%s/api/v2/tailnet/%s/acl", c.BaseURL, url.PathEscape(tailnet))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("%w: building request: %v", ErrAPIError, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "stagecraft")
+	req.SetBasicAuth(apiKey, "")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fetching ACL: %v", ErrAPIError, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading ACL: %v", ErrAPIError, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: fetching ACL: unexpected status %d", ErrAPIError, resp.StatusCode)
+	}
+
+	var policy ACLPolicy
+	if err := json.Unmarshal(body, &policy); err != nil {
+		return nil, fmt.Errorf("%w: decoding ACL: %v", ErrAPIError, err)
+	}
+	return &policy, nil
+}
+
+// SetKeyExpiryDisabled enables or disables node key expiry for a device.
+func (c *HTTPAPIClient) SetKeyExpiryDisabled(ctx context.Context, deviceID, apiKey string, disabled bool) error {
+	body, err := json.Marshal(map[string]bool{"keyExpiryDisabled": disabled})
+	if err != nil {
+		return fmt.Errorf("%w: encoding request: %v", ErrAPIError, err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v2/device/%s/key", c.BaseURL, url.PathEscape(deviceID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: building request: %v", ErrAPIError, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "stagecraft")
+	req.SetBasicAuth(apiKey, "")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: updating key expiry: %v", ErrAPIError, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: updating key expiry: unexpected status %d", ErrAPIError, resp.StatusCode)
+	}
+	return nil
+}
+
+// GetKey fetches a tailnet key's metadata. A rejected API key (401, 403)
+// fails with ErrAPIKeyRejected, an unknown key with ErrAuthKeyInvalid and
+// a transport failure with ErrAPIUnreachable.
+func (c *HTTPAPIClient) GetKey(ctx context.Context, tailnet, keyID, apiKey string) (*KeyInfo, error) {
+	endpoint := fmt.Sprintf("%s/api/v2/tailnet/%s/keys/%s", c.BaseURL, url.PathEscape(tailnet), url.PathEscape(keyID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("%w: building request: %v", ErrAPIError, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "stagecraft")
+	req.SetBasicAuth(apiKey, "")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fetching key: %v", ErrAPIUnreachable, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading key: %v", ErrAPIUnreachable, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: fetching key: status %d", ErrAPIKeyRejected, resp.StatusCode)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: key %s not found in tailnet", ErrAuthKeyInvalid, keyID)
+	default:
+		return nil, fmt.Errorf("%w: fetching key: unexpected status %d", ErrAPIError, resp.StatusCode)
+	}
+
+	var key KeyInfo
+	if err := json.Unmarshal(body, &key); err != nil {
+		return nil, fmt.Errorf("%w: decoding key: %v", ErrAPIError, err)
+	}
+	return &key, nil
+}
@@ -18,6 +18,7 @@ package tailscale
 
 import (
 	"fmt"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -29,6 +30,22 @@ type Config struct {
 	DefaultTags   []string            `yaml:"default_tags"`
 	RoleTags      map[string][]string `yaml:"role_tags"`
 	Install       InstallConfig       `yaml:"install"`
+	APIKeyEnv     string              `yaml:"api_key_env"`
+	KeyExpiry     KeyExpiryConfig     `yaml:"key_expiry"`
+	Policy        *PolicyConfig       `yaml:"policy"`
+
+	// Routers configures hosts as subnet routers or exit nodes, keyed by host name.
+	Routers map[string]RouterConfig `yaml:"routers"`
+}
+
+// RouterConfig advertises routes from a host to the rest of the tailnet.
+type RouterConfig struct {
+	// AdvertiseRoutes are CIDR prefixes reachable through the host
+	// (e.g., a managed database's private network).
+	AdvertiseRoutes []string `yaml:"advertise_routes"`
+
+	// ExitNode offers the host as an exit node for internet traffic.
+	ExitNode bool `yaml:"exit_node"`
 }
 
 // InstallConfig contains Tailscale installation settings.
@@ -37,6 +54,36 @@ type InstallConfig struct {
 	MinVersion string `yaml:"min_version"` // e.g., "1.78.0"
 }
 
+// KeyExpiryConfig controls node key expiry handling for server nodes.
+type KeyExpiryConfig struct {
+	// Disable turns off key expiry on joined nodes via the API (requires api_key_env).
+	Disable bool `yaml:"disable"`
+
+	// RenewBefore re-authenticates nodes whose key expires within this window.
+	// Zero re-authenticates only nodes whose key has already expired.
+	RenewBefore time.Duration `yaml:"renew_before"`
+}
+
+// PolicyConfig enables tailnet ACL validation before hosts are provisioned.
+type PolicyConfig struct {
+	// APIKeyEnv is the env var holding a Tailscale API access token
+	// (default: the top-level api_key_env).
+	APIKeyEnv string `yaml:"api_key_env"`
+
+	// Tailnet is the tailnet name used in API paths (default: "-", the
+	// tailnet of the API key).
+	Tailnet string `yaml:"tailnet"`
+
+	// SSH lists role-to-role SSH access the ACL must permit.
+	SSH []SSHRouteConfig `yaml:"ssh"`
+}
+
+// SSHRouteConfig requires Tailscale SSH from hosts of one role to others.
+type SSHRouteConfig struct {
+	From string   `yaml:"from"`
+	To   []string `yaml:"to"`
+}
+
 // parseConfig unmarshals provider config from generic interface.
 func parseConfig(cfg any) (*Config, error) {
 	// Convert to YAML bytes and unmarshal
@@ -58,10 +105,38 @@ func parseConfig(cfg any) (*Config, error) {
 		return nil, fmt.Errorf("%w: tailnet_domain is required", ErrConfigInvalid)
 	}
 
+	if err := validateRouters(config.Routers); err != nil {
+		return nil, err
+	}
+
+	if config.KeyExpiry.Disable && config.APIKeyEnv == "" {
+		return nil, fmt.Errorf("%w: api_key_env is required when key_expiry.disable is set", ErrConfigInvalid)
+	}
+	if config.KeyExpiry.RenewBefore < 0 {
+		return nil, fmt.Errorf("%w: key_expiry.renew_before must not be negative", ErrConfigInvalid)
+	}
+
+	if config.Policy != nil {
+		if config.Policy.APIKeyEnv == "" {
+			config.Policy.APIKeyEnv = config.APIKeyEnv
+		}
+		if config.Policy.APIKeyEnv == "" {
+			return nil, fmt.Errorf("%w: policy.api_key_env is required", ErrConfigInvalid)
+		}
+		for i, route := range config.Policy.SSH {
+			if route.From == "" || len(route.To) == 0 {
+				return nil, fmt.Errorf("%w: policy.ssh[%d] requires from and to", ErrConfigInvalid, i)
+			}
+		}
+	}
+
 	// Set defaults
 	if config.Install.Method == "" {
 		config.Install.Method = "auto"
 	}
+	if config.Policy != nil && config.Policy.Tailnet == "" {
+		config.Policy.Tailnet = "-"
+	}
 
 	return &config, nil
 }
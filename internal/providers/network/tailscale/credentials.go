@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_NETWORK_TAILSCALE
+// Spec: spec/providers/network/tailscale.md
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"stagecraft/pkg/providers/network"
+)
+
+// Ensure TailscaleProvider implements CredentialVerifier
+var _ network.CredentialVerifier = (*TailscaleProvider)(nil)
+
+// authKeyPrefix starts every Tailscale auth key; the key ID follows it up
+// to the next hyphen.
+const authKeyPrefix = "tskey-auth-"
+
+// VerifyCredentials looks up the auth key in the tailnet with the API key.
+// Auth keys cannot be used without joining a node, so without api_key_env
+// (or policy.api_key_env) only their presence is checked.
+func (p *TailscaleProvider) VerifyCredentials(ctx context.Context, cfg any) (string, error) {
+	config, err := parseConfig(cfg)
+	if err != nil {
+		return "", fmt.Errorf("tailscale provider: %w", err)
+	}
+
+	authKey, err := getEnvVar(config.AuthKeyEnv)
+	if err != nil {
+		return "", err
+	}
+
+	apiKeyEnv, tailnet := config.APIKeyEnv, "-"
+	if config.Policy != nil {
+		apiKeyEnv, tailnet = config.Policy.APIKeyEnv, config.Policy.Tailnet
+	}
+	if apiKeyEnv == "" {
+		return "auth key set; set api_key_env to verify it", nil
+	}
+	apiKey := os.Getenv(apiKeyEnv)
+	if apiKey == "" {
+		return "", fmt.Errorf("tailscale provider: %w: %s", ErrAPIKeyMissing, apiKeyEnv)
+	}
+
+	keyID, ok := authKeyID(authKey)
+	if !ok {
+		return "", fmt.Errorf("tailscale provider: %w: %s does not hold a tskey-auth- key", ErrAuthKeyInvalid, config.AuthKeyEnv)
+	}
+
+	key, err := p.getAPI().GetKey(ctx, tailnet, keyID, apiKey)
+	if err != nil {
+		return "", fmt.Errorf("tailscale provider: %w", err)
+	}
+
+	switch {
+	case key.Revoked != nil:
+		return "", fmt.Errorf("tailscale provider: %w: auth key %s was revoked", ErrAuthKeyInvalid, keyID)
+	case key.Invalid:
+		return "", fmt.Errorf("tailscale provider: %w: auth key %s is invalid", ErrAuthKeyInvalid, keyID)
+	case !key.Expires.IsZero() && !key.Expires.After(p.currentTime()):
+		return "", fmt.Errorf("tailscale provider: %w: auth key %s expired at %s", ErrAuthKeyInvalid, keyID, key.Expires.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	if key.Expires.IsZero() {
+		return fmt.Sprintf("auth key %s valid", keyID), nil
+	}
+	return fmt.Sprintf("auth key %s valid until %s", keyID, key.Expires.UTC().Format("2006-01-02")), nil
+}
+
+// authKeyID returns the key ID of a tskey-auth-<id>-<secret> auth key.
+func authKeyID(authKey string) (string, bool) {
+	rest, ok := strings.CutPrefix(authKey, authKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	id, _, ok := strings.Cut(rest, "-")
+	return id, ok && id != ""
+}
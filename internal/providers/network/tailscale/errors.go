@@ -16,28 +16,47 @@ See https://www.gnu.org/licenses/ for license details.
 
 package tailscale
 
-import "errors"
+import "stagecraft/internal/failure"
 
-// Error definitions for Tailscale provider.
+// Error definitions for Tailscale provider. Each carries its failure class
+// (see CORE_FAILURE_LENS).
 var (
 	// ErrConfigInvalid indicates invalid provider configuration.
-	ErrConfigInvalid = errors.New("invalid config")
+	ErrConfigInvalid = failure.New(failure.ClassConfigInvalid, "invalid config")
 
 	// ErrAuthKeyMissing indicates auth key is missing from environment.
-	ErrAuthKeyMissing = errors.New("auth key missing from environment")
+	ErrAuthKeyMissing = failure.New(failure.ClassConfigInvalid, "auth key missing from environment")
 
 	// ErrAuthKeyInvalid indicates auth key is invalid or expired.
-	ErrAuthKeyInvalid = errors.New("invalid or expired auth key")
+	ErrAuthKeyInvalid = failure.New(failure.ClassConfigInvalid, "invalid or expired auth key")
 
 	// ErrTailnetMismatch indicates host is in different tailnet than expected.
-	ErrTailnetMismatch = errors.New("tailnet mismatch")
+	ErrTailnetMismatch = failure.New(failure.ClassConfigInvalid, "tailnet mismatch")
 
 	// ErrTagMismatch indicates host tags do not match expected tags.
-	ErrTagMismatch = errors.New("tag mismatch")
+	ErrTagMismatch = failure.New(failure.ClassConfigInvalid, "tag mismatch")
 
 	// ErrInstallFailed indicates Tailscale installation failed.
-	ErrInstallFailed = errors.New("tailscale installation failed")
+	ErrInstallFailed = failure.New(failure.ClassExternalDependency, "tailscale installation failed")
 
 	// ErrUnsupportedOS indicates unsupported operating system.
-	ErrUnsupportedOS = errors.New("unsupported operating system")
+	ErrUnsupportedOS = failure.New(failure.ClassExternalDependency, "unsupported operating system")
+
+	// ErrAPIKeyMissing indicates the Tailscale API key is missing from environment.
+	ErrAPIKeyMissing = failure.New(failure.ClassConfigInvalid, "api key missing from environment")
+
+	// ErrAPIKeyRejected indicates the Tailscale API rejected the API key (401 or 403).
+	ErrAPIKeyRejected = failure.New(failure.ClassConfigInvalid, "api key rejected")
+
+	// ErrAPIUnreachable indicates the Tailscale API could not be reached at all.
+	ErrAPIUnreachable = failure.New(failure.ClassTransientEnvironment, "tailscale api unreachable")
+
+	// ErrAPIError indicates a Tailscale API request failed.
+	ErrAPIError = failure.New(failure.ClassProviderFailure, "tailscale api error")
+
+	// ErrKeyExpired indicates the node key is still expired after re-authentication.
+	ErrKeyExpired = failure.New(failure.ClassProviderFailure, "node key expired")
+
+	// ErrPolicyViolation indicates the tailnet ACL does not admit Stagecraft's tags or SSH access.
+	ErrPolicyViolation = failure.New(failure.ClassProviderFailure, "tailnet policy violation")
 )
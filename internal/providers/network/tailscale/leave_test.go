@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_NETWORK_TAILSCALE
+// Spec: spec/providers/network/tailscale.md
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	hostexec "stagecraft/internal/exec"
+	"stagecraft/pkg/providers/network"
+)
+
+func TestTailscaleProvider_Leave_LogsOut(t *testing.T) {
+	commander := &scriptedCommander{results: map[string][]hostexec.Result{
+		"app-1 tailscale logout": {{}},
+	}}
+	provider := &TailscaleProvider{commander: commander}
+
+	if err := provider.Leave(context.Background(), network.LeaveOptions{Host: "app-1"}); err != nil {
+		t.Fatalf("Leave() error = %v", err)
+	}
+	if len(commander.calls) != 1 || commander.calls[0] != "app-1 tailscale logout" {
+		t.Errorf("calls = %v, want [app-1 tailscale logout]", commander.calls)
+	}
+}
+
+func TestTailscaleProvider_Leave_ReportsStderr(t *testing.T) {
+	commander := &scriptedCommander{results: map[string][]hostexec.Result{
+		"app-1 tailscale logout": {{Stderr: "connection refused\n", Error: errors.New("exit status 1")}},
+	}}
+	provider := &TailscaleProvider{commander: commander}
+
+	err := provider.Leave(context.Background(), network.LeaveOptions{Host: "app-1"})
+	if err == nil || !strings.Contains(err.Error(), "logout failed: connection refused") {
+		t.Fatalf("Leave() error = %v, want logout failure", err)
+	}
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_NETWORK_TAILSCALE_POLICY
+// Spec: spec/providers/network/tailscale-policy.md
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"stagecraft/pkg/providers/network"
+)
+
+// sshAllowActions are the SSH rule actions that grant access.
+var sshAllowActions = map[string]bool{"accept": true, "check": true}
+
+// ValidatePolicy fetches the tailnet ACL and verifies that every tag the
+// given roles will advertise is declared in tagOwners, and that each
+// configured policy.ssh route is permitted by an SSH rule. All violations
+// are reported together. Validation is skipped when policy is not configured.
+func (p *TailscaleProvider) ValidatePolicy(ctx context.Context, opts network.ValidatePolicyOptions) error {
+	if !policyConfigured(opts.Config) {
+		return nil
+	}
+
+	config, err := parseConfig(opts.Config)
+	if err != nil {
+		return fmt.Errorf("tailscale provider: %w", err)
+	}
+
+	apiKey := os.Getenv(config.Policy.APIKeyEnv)
+	if apiKey == "" {
+		return fmt.Errorf("tailscale provider: %w: %s", ErrAPIKeyMissing, config.Policy.APIKeyEnv)
+	}
+
+	acl, err := p.getAPI().GetACL(ctx, config.Policy.Tailnet, apiKey)
+	if err != nil {
+		return fmt.Errorf("tailscale provider: %w", err)
+	}
+
+	problems := checkPolicy(config, acl, opts.Roles)
+	if len(problems) > 0 {
+		return fmt.Errorf("tailscale provider: %w: %s", ErrPolicyViolation, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// policyConfigured reports whether the raw provider config has a policy block.
+// It is checked before full parsing so that configs without policy validation
+// are not required to be otherwise complete at plan time.
+func policyConfigured(cfg any) bool {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return false
+	}
+	var probe struct {
+		Policy *yaml.Node `yaml:"policy"`
+	}
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return probe.Policy != nil
+}
+
+// roleTags returns the sorted union of default_tags and role_tags[role].
+func roleTags(config *Config, role string) []string {
+	return computeTags(config, config.RoleTags[role])
+}
+
+// checkPolicy returns a sorted list of policy violations.
+// This is a pure function over config, ACL and roles.
+func checkPolicy(config *Config, acl *ACLPolicy, roles []string) []string {
+	var problems []string
+
+	// Tags: every advertised tag must have an owner entry.
+	checked := make(map[string]bool)
+	tagRoles := append([]string{""}, roles...)
+	for _, role := range tagRoles {
+		for _, tag := range roleTags(config, role) {
+			if checked[tag] {
+				continue
+			}
+			checked[tag] = true
+			if !strings.HasPrefix(tag, "tag:") {
+				problems = append(problems, fmt.Sprintf("tag %q must start with \"tag:\"", tag))
+				continue
+			}
+			if _, ok := acl.TagOwners[tag]; !ok {
+				problems = append(problems, fmt.Sprintf("tag %q is not declared in tagOwners", tag))
+			}
+		}
+	}
+
+	// SSH: each route needs a rule whose src and dst cover the role tags.
+	for _, route := range config.Policy.SSH {
+		fromTags := roleTags(config, route.From)
+		for _, to := range route.To {
+			toTags := roleTags(config, to)
+			if !sshAllowed(acl.SSH, fromTags, toTags) {
+				problems = append(problems, fmt.Sprintf("no ssh rule allows %s -> %s", route.From, to))
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+// sshAllowed reports whether any allowing rule has a source among fromTags
+// and a destination among toTags.
+func sshAllowed(rules []ACLSSHRule, fromTags, toTags []string) bool {
+	for _, rule := range rules {
+		if !sshAllowActions[rule.Action] {
+			continue
+		}
+		if containsAny(rule.Src, fromTags) && containsAny(rule.Dst, toTags) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAny reports whether values and candidates share an element.
+func containsAny(values, candidates []string) bool {
+	for _, v := range values {
+		for _, c := range candidates {
+			if v == c {
+				return true
+			}
+		}
+	}
+	return false
+}
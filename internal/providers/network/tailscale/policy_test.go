@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_NETWORK_TAILSCALE_POLICY
+// Spec: spec/providers/network/tailscale-policy.md
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/providers/network"
+)
+
+// fakeAPIClient is a test implementation of APIClient.
+type fakeAPIClient struct {
+	acl *ACLPolicy
+	err error
+
+	tailnet string
+	apiKey  string
+
+	disabledDevices []string
+
+	key    *KeyInfo
+	keyID  string
+	keyErr error
+}
+
+func (f *fakeAPIClient) GetACL(ctx context.Context, tailnet, apiKey string) (*ACLPolicy, error) {
+	f.tailnet = tailnet
+	f.apiKey = apiKey
+	return f.acl, f.err
+}
+
+func (f *fakeAPIClient) SetKeyExpiryDisabled(ctx context.Context, deviceID, apiKey string, disabled bool) error {
+	f.apiKey = apiKey
+	if disabled {
+		f.disabledDevices = append(f.disabledDevices, deviceID)
+	}
+	return f.err
+}
+
+func (f *fakeAPIClient) GetKey(ctx context.Context, tailnet, keyID, apiKey string) (*KeyInfo, error) {
+	f.tailnet = tailnet
+	f.apiKey = apiKey
+	f.keyID = keyID
+	return f.key, f.keyErr
+}
+
+func policyTestConfig() map[string]any {
+	return map[string]any{
+		"auth_key_env":   "TS_AUTHKEY",
+		"tailnet_domain": "example.ts.net",
+		"default_tags":   []any{"tag:stagecraft"},
+		"role_tags": map[string]any{
+			"app":     []any{"tag:app"},
+			"gateway": []any{"tag:gateway"},
+		},
+		"policy": map[string]any{
+			"api_key_env": "TS_API_KEY_POLICY_TEST",
+			"ssh": []any{
+				map[string]any{"from": "gateway", "to": []any{"app"}},
+			},
+		},
+	}
+}
+
+func TestTailscaleProvider_ValidatePolicy_Allowed(t *testing.T) {
+	t.Setenv("TS_API_KEY_POLICY_TEST", "tskey-api-test")
+
+	api := &fakeAPIClient{acl: &ACLPolicy{
+		TagOwners: map[string][]string{
+			"tag:stagecraft": {"autogroup:admin"},
+			"tag:app":        {"autogroup:admin"},
+			"tag:gateway":    {"autogroup:admin"},
+		},
+		SSH: []ACLSSHRule{
+			{Action: "accept", Src: []string{"tag:gateway"}, Dst: []string{"tag:app"}, Users: []string{"root"}},
+		},
+	}}
+	provider := &TailscaleProvider{api: api}
+
+	err := provider.ValidatePolicy(context.Background(), network.ValidatePolicyOptions{
+		Config: policyTestConfig(),
+		Roles:  []string{"app", "gateway"},
+	})
+	if err != nil {
+		t.Fatalf("ValidatePolicy() error = %v", err)
+	}
+	if api.tailnet != "-" || api.apiKey != "tskey-api-test" {
+		t.Errorf("GetACL() called with tailnet %q, key %q", api.tailnet, api.apiKey)
+	}
+}
+
+func TestTailscaleProvider_ValidatePolicy_ReportsAllViolations(t *testing.T) {
+	t.Setenv("TS_API_KEY_POLICY_TEST", "tskey-api-test")
+
+	api := &fakeAPIClient{acl: &ACLPolicy{
+		TagOwners: map[string][]string{"tag:stagecraft": {"autogroup:admin"}},
+		SSH: []ACLSSHRule{
+			{Action: "accept", Src: []string{"autogroup:member"}, Dst: []string{"tag:app"}},
+		},
+	}}
+	provider := &TailscaleProvider{api: api}
+
+	err := provider.ValidatePolicy(context.Background(), network.ValidatePolicyOptions{
+		Config: policyTestConfig(),
+		Roles:  []string{"app", "gateway"},
+	})
+	if !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("ValidatePolicy() error = %v, want ErrPolicyViolation", err)
+	}
+	for _, want := range []string{
+		`tag "tag:app" is not declared in tagOwners`,
+		`tag "tag:gateway" is not declared in tagOwners`,
+		"no ssh rule allows gateway -> app",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidatePolicy() error = %q, want substring %q", err.Error(), want)
+		}
+	}
+}
+
+func TestTailscaleProvider_ValidatePolicy_SkippedWithoutPolicy(t *testing.T) {
+	api := &fakeAPIClient{err: errors.New("should not be called")}
+	provider := &TailscaleProvider{api: api}
+
+	// An incomplete config is fine when policy validation is not enabled.
+	if err := provider.ValidatePolicy(context.Background(), network.ValidatePolicyOptions{
+		Config: map[string]any{},
+	}); err != nil {
+		t.Fatalf("ValidatePolicy() error = %v, want nil", err)
+	}
+}
+
+func TestTailscaleProvider_ValidatePolicy_APIKeyMissing(t *testing.T) {
+	t.Setenv("TS_API_KEY_POLICY_TEST", "")
+	provider := &TailscaleProvider{api: &fakeAPIClient{}}
+
+	err := provider.ValidatePolicy(context.Background(), network.ValidatePolicyOptions{Config: policyTestConfig()})
+	if !errors.Is(err, ErrAPIKeyMissing) {
+		t.Fatalf("ValidatePolicy() error = %v, want ErrAPIKeyMissing", err)
+	}
+}
+
+func TestSSHAllowed_IgnoresDenyingActions(t *testing.T) {
+	rules := []ACLSSHRule{{Action: "drop", Src: []string{"tag:gateway"}, Dst: []string{"tag:app"}}}
+	if sshAllowed(rules, []string{"tag:gateway"}, []string{"tag:app"}) {
+		t.Error("sshAllowed() = true for a non-accepting rule")
+	}
+}
+
+func TestHTTPAPIClient_GetACL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tailnet/example.com/acl" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if user, _, ok := r.BasicAuth(); !ok || user != "tskey-api-test" {
+			t.Errorf("expected API key as basic auth user")
+		}
+		_, _ = w.Write([]byte(`{"tagOwners":{"tag:app":["autogroup:admin"]},"ssh":[{"action":"accept","src":["tag:gateway"],"dst":["tag:app"],"users":["root"]}]}`))
+	}))
+	defer server.Close()
+
+	client := &HTTPAPIClient{BaseURL: server.URL, HTTPClient: server.Client()}
+	acl, err := client.GetACL(context.Background(), "example.com", "tskey-api-test")
+	if err != nil {
+		t.Fatalf("GetACL() error = %v", err)
+	}
+	if _, ok := acl.TagOwners["tag:app"]; !ok || len(acl.SSH) != 1 {
+		t.Errorf("GetACL() = %+v", acl)
+	}
+}
+
+func TestHTTPAPIClient_GetACL_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := &HTTPAPIClient{BaseURL: server.URL, HTTPClient: server.Client()}
+	if _, err := client.GetACL(context.Background(), "-", "bad"); !errors.Is(err, ErrAPIError) {
+		t.Fatalf("GetACL() error = %v, want ErrAPIError", err)
+	}
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_NETWORK_TAILSCALE
+// Spec: spec/providers/network/tailscale.md
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	hostexec "stagecraft/internal/exec"
+	"stagecraft/pkg/providers/network"
+)
+
+// scriptedCommander returns queued results per command key, in order.
+// The last queued result repeats once the queue is drained.
+type scriptedCommander struct {
+	results map[string][]hostexec.Result
+	calls   []string
+}
+
+func (c *scriptedCommander) Run(ctx context.Context, host, cmd string, args ...string) (string, string, error) {
+	key := strings.TrimSpace(host + " " + cmd + " " + strings.Join(args, " "))
+	c.calls = append(c.calls, key)
+
+	queue := c.results[key]
+	if len(queue) == 0 {
+		return "", "", fmt.Errorf("command not found: %s", key)
+	}
+	result := queue[0]
+	if len(queue) > 1 {
+		c.results[key] = queue[1:]
+	}
+	if result.Error != nil {
+		return result.Stdout, result.Stderr, result.Error
+	}
+	return result.Stdout, result.Stderr, nil
+}
+
+var keyExpiryTestNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func statusJSONWithExpiry(expiry string, expired bool) string {
+	keyExpiry := ""
+	if expiry != "" {
+		keyExpiry = fmt.Sprintf(`, "KeyExpiry": %q`, expiry)
+	}
+	return fmt.Sprintf(`{
+		"BackendState": "Running",
+		"TailnetName": "example.ts.net",
+		"Self": {"ID": "nNODE1", "Online": true, "Tags": ["tag:stagecraft", "tag:app"], "Expired": %t%s}
+	}`, expired, keyExpiry)
+}
+
+func keyExpiryConfig(extra map[string]any) map[string]any {
+	cfg := map[string]any{
+		"auth_key_env":   "TS_AUTHKEY_EXPIRY_TEST",
+		"tailnet_domain": "example.ts.net",
+		"default_tags":   []any{"tag:stagecraft"},
+		"role_tags":      map[string]any{"app": []any{"tag:app"}},
+	}
+	for k, v := range extra {
+		cfg[k] = v
+	}
+	return cfg
+}
+
+const (
+	statusKey   = "app-1 tailscale status --json"
+	joinKey     = "app-1 tailscale up --authkey=test-auth-key --hostname=app-1 --advertise-tags=tag:app,tag:stagecraft"
+	reauthedKey = joinKey + " --force-reauth"
+)
+
+func TestKeyNeedsRenewal(t *testing.T) {
+	soon := keyExpiryTestNow.Add(24 * time.Hour)
+	past := keyExpiryTestNow.Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		status      TailscaleStatus
+		renewBefore time.Duration
+		want        bool
+	}{
+		{name: "expiry disabled", status: TailscaleStatus{}, want: false},
+		{name: "expired flag", status: TailscaleStatus{Self: NodeInfo{Expired: true}}, want: true},
+		{name: "needs login", status: TailscaleStatus{BackendState: "NeedsLogin"}, want: true},
+		{name: "expiry in past", status: TailscaleStatus{Self: NodeInfo{KeyExpiry: &past}}, want: true},
+		{name: "expiry outside window", status: TailscaleStatus{Self: NodeInfo{KeyExpiry: &soon}}, renewBefore: time.Hour, want: false},
+		{name: "expiry inside window", status: TailscaleStatus{Self: NodeInfo{KeyExpiry: &soon}}, renewBefore: 48 * time.Hour, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			got, reason := keyNeedsRenewal(&status, keyExpiryTestNow, tt.renewBefore)
+			if got != tt.want {
+				t.Errorf("keyNeedsRenewal() = %v (%q), want %v", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestTailscaleProvider_EnsureJoined_ExpiredKeyForcesReauth(t *testing.T) {
+	t.Setenv("TS_AUTHKEY_EXPIRY_TEST", "test-auth-key")
+
+	commander := &scriptedCommander{results: map[string][]hostexec.Result{
+		statusKey: {
+			{Stdout: statusJSONWithExpiry("2025-05-01T00:00:00Z", true)},
+			{Stdout: statusJSONWithExpiry("2025-11-28T00:00:00Z", false)},
+		},
+		reauthedKey: {{}},
+	}}
+	provider := &TailscaleProvider{commander: commander, now: func() time.Time { return keyExpiryTestNow }}
+
+	err := provider.EnsureJoined(context.Background(), network.EnsureJoinedOptions{
+		Config: keyExpiryConfig(nil),
+		Host:   "app-1",
+		Tags:   []string{"tag:app"},
+	})
+	if err != nil {
+		t.Fatalf("EnsureJoined() error = %v", err)
+	}
+	if !containsCall(commander.calls, reauthedKey) {
+		t.Errorf("expected forced re-auth, calls = %v", commander.calls)
+	}
+}
+
+func TestTailscaleProvider_EnsureJoined_RenewsKeyWithinWindow(t *testing.T) {
+	t.Setenv("TS_AUTHKEY_EXPIRY_TEST", "test-auth-key")
+
+	commander := &scriptedCommander{results: map[string][]hostexec.Result{
+		statusKey: {
+			{Stdout: statusJSONWithExpiry("2025-06-03T00:00:00Z", false)},
+			{Stdout: statusJSONWithExpiry("2025-11-28T00:00:00Z", false)},
+		},
+		reauthedKey: {{}},
+	}}
+	provider := &TailscaleProvider{commander: commander, now: func() time.Time { return keyExpiryTestNow }}
+
+	err := provider.EnsureJoined(context.Background(), network.EnsureJoinedOptions{
+		Config: keyExpiryConfig(map[string]any{"key_expiry": map[string]any{"renew_before": "168h"}}),
+		Host:   "app-1",
+		Tags:   []string{"tag:app"},
+	})
+	if err != nil {
+		t.Fatalf("EnsureJoined() error = %v", err)
+	}
+	if !containsCall(commander.calls, reauthedKey) {
+		t.Errorf("expected re-auth for key expiring within window, calls = %v", commander.calls)
+	}
+}
+
+func TestTailscaleProvider_EnsureJoined_DisablesKeyExpiry(t *testing.T) {
+	t.Setenv("TS_AUTHKEY_EXPIRY_TEST", "test-auth-key")
+	t.Setenv("TS_API_KEY_EXPIRY_TEST", "tskey-api-test")
+
+	commander := &scriptedCommander{results: map[string][]hostexec.Result{
+		statusKey: {{Stdout: statusJSONWithExpiry("2025-11-28T00:00:00Z", false)}},
+	}}
+	api := &fakeAPIClient{}
+	provider := &TailscaleProvider{commander: commander, api: api, now: func() time.Time { return keyExpiryTestNow }}
+
+	err := provider.EnsureJoined(context.Background(), network.EnsureJoinedOptions{
+		Config: keyExpiryConfig(map[string]any{
+			"api_key_env": "TS_API_KEY_EXPIRY_TEST",
+			"key_expiry":  map[string]any{"disable": true},
+		}),
+		Host: "app-1",
+		Tags: []string{"tag:app"},
+	})
+	if err != nil {
+		t.Fatalf("EnsureJoined() error = %v", err)
+	}
+	if len(api.disabledDevices) != 1 || api.disabledDevices[0] != "nNODE1" {
+		t.Errorf("expected key expiry disabled for nNODE1, got %v", api.disabledDevices)
+	}
+	if containsCall(commander.calls, joinKey) {
+		t.Errorf("expected no re-join for a healthy node, calls = %v", commander.calls)
+	}
+}
+
+func TestTailscaleProvider_EnsureJoined_StillExpiredAfterReauth(t *testing.T) {
+	t.Setenv("TS_AUTHKEY_EXPIRY_TEST", "test-auth-key")
+
+	expired := statusJSONWithExpiry("2025-05-01T00:00:00Z", true)
+	commander := &scriptedCommander{results: map[string][]hostexec.Result{
+		statusKey:   {{Stdout: expired}},
+		reauthedKey: {{}},
+	}}
+	provider := &TailscaleProvider{commander: commander, now: func() time.Time { return keyExpiryTestNow }}
+
+	err := provider.EnsureJoined(context.Background(), network.EnsureJoinedOptions{
+		Config: keyExpiryConfig(nil),
+		Host:   "app-1",
+		Tags:   []string{"tag:app"},
+	})
+	if !errors.Is(err, ErrKeyExpired) {
+		t.Fatalf("EnsureJoined() error = %v, want ErrKeyExpired", err)
+	}
+}
+
+func TestTailscaleProvider_Reauth_UsesRoleTags(t *testing.T) {
+	t.Setenv("TS_AUTHKEY_EXPIRY_TEST", "test-auth-key")
+
+	commander := &scriptedCommander{results: map[string][]hostexec.Result{
+		statusKey:   {{Stdout: statusJSONWithExpiry("", false)}},
+		reauthedKey: {{}},
+	}}
+	provider := &TailscaleProvider{commander: commander, now: func() time.Time { return keyExpiryTestNow }}
+
+	err := provider.Reauth(context.Background(), network.ReauthOptions{
+		Config: keyExpiryConfig(nil),
+		Host:   "app-1",
+		Role:   "app",
+	})
+	if err != nil {
+		t.Fatalf("Reauth() error = %v", err)
+	}
+	if len(commander.calls) == 0 || commander.calls[0] != reauthedKey {
+		t.Errorf("expected forced re-auth with role tags first, calls = %v", commander.calls)
+	}
+}
+
+func TestParseConfig_KeyExpiryDisableRequiresAPIKey(t *testing.T) {
+	_, err := parseConfig(keyExpiryConfig(map[string]any{"key_expiry": map[string]any{"disable": true}}))
+	if !errors.Is(err, ErrConfigInvalid) {
+		t.Fatalf("parseConfig() error = %v, want ErrConfigInvalid", err)
+	}
+}
+
+func containsCall(calls []string, want string) bool {
+	for _, c := range calls {
+		if c == want {
+			return true
+		}
+	}
+	return false
+}
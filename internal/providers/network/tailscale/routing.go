@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_NETWORK_TAILSCALE
+// Spec: spec/providers/network/tailscale.md
+
+package tailscale
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+
+	hostexec "stagecraft/internal/exec"
+)
+
+// forwardingSysctlPath is the sysctl drop-in that enables IP forwarding on routers.
+const forwardingSysctlPath = "/etc/sysctl.d/99-stagecraft-tailscale.conf"
+
+// validateRouters checks that every advertised route is a canonical CIDR prefix.
+func validateRouters(routers map[string]RouterConfig) error {
+	for host, router := range routers {
+		for _, route := range router.AdvertiseRoutes {
+			prefix, err := netip.ParsePrefix(route)
+			if err != nil {
+				return fmt.Errorf("%w: routers.%s: invalid route %q", ErrConfigInvalid, host, route)
+			}
+			if prefix != prefix.Masked() {
+				return fmt.Errorf("%w: routers.%s: route %q has host bits set (use %s)", ErrConfigInvalid, host, route, prefix.Masked())
+			}
+		}
+	}
+	return nil
+}
+
+// sortedRoutes returns the router's routes in sorted order for deterministic flags.
+func sortedRoutes(router RouterConfig) []string {
+	routes := append([]string(nil), router.AdvertiseRoutes...)
+	sort.Strings(routes)
+	return routes
+}
+
+// routerFlags returns the `tailscale up` flags for a router host, or none for other hosts.
+func routerFlags(router RouterConfig, isRouter bool) []string {
+	if !isRouter {
+		return nil
+	}
+	var flags []string
+	if routes := sortedRoutes(router); len(routes) > 0 {
+		flags = append(flags, "--advertise-routes="+strings.Join(routes, ","))
+	}
+	if router.ExitNode {
+		flags = append(flags, "--advertise-exit-node")
+	}
+	return flags
+}
+
+// buildTailscaleSetCommand builds the `tailscale set` command that applies router
+// prefs to an already-joined node. Passing every pref explicitly makes it idempotent
+// and clears routes that were removed from config.
+func buildTailscaleSetCommand(router RouterConfig) hostexec.Cmd {
+	return hostexec.Command("tailscale", "set").
+		Flag("advertise-routes", strings.Join(sortedRoutes(router), ",")).
+		Flag("advertise-exit-node", strconv.FormatBool(router.ExitNode))
+}
+
+// forwardingScript persists IP forwarding in the sysctl file given as $1
+// and applies it.
+const forwardingScript = `printf 'net.ipv4.ip_forward = 1\nnet.ipv6.conf.all.forwarding = 1\n' > "$1" && sysctl -p "$1"`
+
+// enableForwarding enables IPv4/IPv6 forwarding, which subnet routers and exit nodes require.
+func enableForwarding(ctx context.Context, commander hostexec.Commander, host string) error {
+	script := hostexec.Script(forwardingScript, forwardingSysctlPath)
+	if _, stderr, err := script.Run(ctx, commander, host); err != nil {
+		return fmt.Errorf("tailscale provider: enabling IP forwarding: %s", strings.TrimSpace(stderr))
+	}
+	return nil
+}
+
+// ensureRouting applies router prefs to an already-joined host. Hosts without
+// a routers entry are left untouched.
+func ensureRouting(ctx context.Context, commander hostexec.Commander, host string, config *Config) error {
+	router, ok := config.Routers[host]
+	if !ok {
+		return nil
+	}
+
+	if err := enableForwarding(ctx, commander, host); err != nil {
+		return err
+	}
+
+	if _, stderr, err := buildTailscaleSetCommand(router).Run(ctx, commander, host); err != nil {
+		return fmt.Errorf("tailscale provider: applying router settings: %s", strings.TrimSpace(stderr))
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Feature: PROVIDER_NETWORK_TAILSCALE
+// Spec: spec/providers/network/tailscale.md
+
+package tailscale
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	hostexec "stagecraft/internal/exec"
+	"stagecraft/pkg/providers/network"
+)
+
+const (
+	gatewayStatusKey = "gateway-1 tailscale status --json"
+	forwardingKey    = "gateway-1 sh -c " + forwardingScript + " sh /etc/sysctl.d/99-stagecraft-tailscale.conf"
+)
+
+func routerConfig() map[string]any {
+	return map[string]any{
+		"auth_key_env":   "TS_AUTHKEY_ROUTER_TEST",
+		"tailnet_domain": "example.ts.net",
+		"default_tags":   []any{"tag:gateway"},
+		"routers": map[string]any{
+			"gateway-1": map[string]any{
+				"advertise_routes": []any{"10.20.0.0/16", "10.10.0.0/24"},
+				"exit_node":        true,
+			},
+		},
+	}
+}
+
+const gatewayStatusJSON = `{
+	"BackendState": "Running",
+	"TailnetName": "example.ts.net",
+	"Self": {"ID": "nGW1", "Online": true, "Tags": ["tag:gateway"]}
+}`
+
+func TestTailscaleProvider_EnsureJoined_AppliesRouterSettingsWhenJoined(t *testing.T) {
+	t.Setenv("TS_AUTHKEY_ROUTER_TEST", "test-auth-key")
+
+	setKey := "gateway-1 tailscale set --advertise-routes=10.10.0.0/24,10.20.0.0/16 --advertise-exit-node=true"
+	commander := &scriptedCommander{results: map[string][]hostexec.Result{
+		gatewayStatusKey: {{Stdout: gatewayStatusJSON}},
+		forwardingKey:    {{}},
+		setKey:           {{}},
+	}}
+	provider := &TailscaleProvider{commander: commander, now: func() time.Time { return keyExpiryTestNow }}
+
+	err := provider.EnsureJoined(context.Background(), network.EnsureJoinedOptions{
+		Config: routerConfig(),
+		Host:   "gateway-1",
+	})
+	if err != nil {
+		t.Fatalf("EnsureJoined() error = %v", err)
+	}
+	if !containsCall(commander.calls, forwardingKey) || !containsCall(commander.calls, setKey) {
+		t.Errorf("expected forwarding and tailscale set, calls = %v", commander.calls)
+	}
+}
+
+func TestTailscaleProvider_EnsureJoined_JoinIncludesRouterFlags(t *testing.T) {
+	t.Setenv("TS_AUTHKEY_ROUTER_TEST", "test-auth-key")
+
+	joinKey := "gateway-1 tailscale up --authkey=test-auth-key --hostname=gateway-1 --advertise-tags=tag:gateway --advertise-routes=10.10.0.0/24,10.20.0.0/16 --advertise-exit-node"
+	commander := &scriptedCommander{results: map[string][]hostexec.Result{
+		gatewayStatusKey: {
+			{Error: errors.New("not joined")},
+			{Stdout: gatewayStatusJSON},
+		},
+		forwardingKey: {{}},
+		joinKey:       {{}},
+	}}
+	provider := &TailscaleProvider{commander: commander, now: func() time.Time { return keyExpiryTestNow }}
+
+	err := provider.EnsureJoined(context.Background(), network.EnsureJoinedOptions{
+		Config: routerConfig(),
+		Host:   "gateway-1",
+	})
+	if err != nil {
+		t.Fatalf("EnsureJoined() error = %v, calls = %v", err, commander.calls)
+	}
+	if !containsCall(commander.calls, joinKey) {
+		t.Errorf("expected join with router flags, calls = %v", commander.calls)
+	}
+}
+
+func TestTailscaleProvider_EnsureJoined_NonRouterHostUntouched(t *testing.T) {
+	t.Setenv("TS_AUTHKEY_ROUTER_TEST", "test-auth-key")
+
+	commander := &scriptedCommander{results: map[string][]hostexec.Result{
+		"app-1 tailscale status --json": {{Stdout: strings.Replace(gatewayStatusJSON, "nGW1", "nAPP1", 1)}},
+	}}
+	provider := &TailscaleProvider{commander: commander, now: func() time.Time { return keyExpiryTestNow }}
+
+	err := provider.EnsureJoined(context.Background(), network.EnsureJoinedOptions{
+		Config: routerConfig(),
+		Host:   "app-1",
+	})
+	if err != nil {
+		t.Fatalf("EnsureJoined() error = %v", err)
+	}
+	if len(commander.calls) != 1 {
+		t.Errorf("expected only a status check for a non-router host, calls = %v", commander.calls)
+	}
+}
+
+func TestParseConfig_RouterRoutesValidated(t *testing.T) {
+	tests := []struct {
+		name  string
+		route string
+		want  string
+	}{
+		{name: "not a prefix", route: "10.0.0.1", want: "invalid route"},
+		{name: "host bits set", route: "10.0.0.1/16", want: "use 10.0.0.0/16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := routerConfig()
+			cfg["routers"] = map[string]any{"gateway-1": map[string]any{"advertise_routes": []any{tt.route}}}
+
+			_, err := parseConfig(cfg)
+			if !errors.Is(err, ErrConfigInvalid) || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("parseConfig() error = %v, want ErrConfigInvalid containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTailscaleSetCommand_ClearsRemovedPrefs(t *testing.T) {
+	got := buildTailscaleSetCommand(RouterConfig{}).String()
+	want := "tailscale 'set' '--advertise-routes=' '--advertise-exit-node=false'"
+	if got != want {
+		t.Errorf("buildTailscaleSetCommand() = %q, want %q", got, want)
+	}
+}
@@ -19,21 +19,50 @@ package tailscale
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // TailscaleStatus represents the output of `tailscale status --json`.
 //
 //nolint:revive // TailscaleStatus is intentionally named for clarity in provider package
 type TailscaleStatus struct {
-	TailnetName string   `json:"TailnetName"`
-	Self        NodeInfo `json:"Self"`
+	BackendState string   `json:"BackendState"`
+	TailnetName  string   `json:"TailnetName"`
+	Self         NodeInfo `json:"Self"`
 }
 
 // NodeInfo contains information about a Tailscale node.
 type NodeInfo struct {
+	ID           string   `json:"ID"`
 	Online       bool     `json:"Online"`
 	TailscaleIPs []string `json:"TailscaleIPs"`
 	Tags         []string `json:"Tags"`
+
+	// KeyExpiry is when the node key expires; nil when expiry is disabled.
+	KeyExpiry *time.Time `json:"KeyExpiry,omitempty"`
+
+	// Expired reports whether the node key has expired.
+	Expired bool `json:"Expired,omitempty"`
+}
+
+// backendStateNeedsLogin is reported when the node must re-authenticate.
+const backendStateNeedsLogin = "NeedsLogin"
+
+// keyNeedsRenewal reports whether the node must re-authenticate, and why.
+// A key needs renewal when it has expired, when the daemon reports NeedsLogin,
+// or when it expires within renewBefore of now.
+func keyNeedsRenewal(status *TailscaleStatus, now time.Time, renewBefore time.Duration) (bool, string) {
+	switch {
+	case status.Self.Expired:
+		return true, "node key expired"
+	case status.BackendState == backendStateNeedsLogin:
+		return true, "node needs login"
+	case status.Self.KeyExpiry != nil && !status.Self.KeyExpiry.After(now):
+		return true, "node key expired"
+	case status.Self.KeyExpiry != nil && status.Self.KeyExpiry.Sub(now) <= renewBefore:
+		return true, fmt.Sprintf("node key expires at %s", status.Self.KeyExpiry.UTC().Format(time.RFC3339))
+	}
+	return false, ""
 }
 
 // parseStatus parses JSON output from `tailscale status --json`.
@@ -14,14 +14,19 @@ See https://www.gnu.org/licenses/ for license details.
 // Feature: PROVIDER_NETWORK_TAILSCALE
 // Spec: spec/providers/network/tailscale.md
 
+// Package tailscale provides a Tailscale implementation of the NetworkProvider interface.
 package tailscale
 
 import (
 	"context"
 	"fmt"
+	"os"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
+	hostexec "stagecraft/internal/exec"
 	"stagecraft/pkg/providers/network"
 )
 
@@ -29,18 +34,68 @@ import (
 //
 //nolint:revive // TailscaleProvider is intentionally named for clarity in provider package
 type TailscaleProvider struct {
-	commander Commander
+	commander hostexec.Commander
+	api       APIClient
 	config    *Config
+	now       func() time.Time
 }
 
 // Ensure TailscaleProvider implements NetworkProvider
 var _ network.NetworkProvider = (*TailscaleProvider)(nil)
 
+// Ensure TailscaleProvider implements FQDNResolver
+var _ network.FQDNResolver = (*TailscaleProvider)(nil)
+
+// Ensure TailscaleProvider implements PolicyValidator
+var _ network.PolicyValidator = (*TailscaleProvider)(nil)
+
+// Ensure TailscaleProvider implements Reauthenticator
+var _ network.Reauthenticator = (*TailscaleProvider)(nil)
+
+// Ensure TailscaleProvider implements Leaver
+var _ network.Leaver = (*TailscaleProvider)(nil)
+
+// Ensure TailscaleProvider implements network.MetadataProvider
+var _ network.MetadataProvider = (*TailscaleProvider)(nil)
+
+// Ensure TailscaleProvider implements network.ConfigValidator
+var _ network.ConfigValidator = (*TailscaleProvider)(nil)
+
 // ID returns the provider identifier.
 func (p *TailscaleProvider) ID() string {
 	return "tailscale"
 }
 
+// Metadata returns descriptive metadata about the provider.
+func (p *TailscaleProvider) Metadata() network.ProviderMetadata {
+	return network.ProviderMetadata{
+		Name:         "Tailscale",
+		Description:  "Joins hosts to a Tailscale tailnet for private mesh networking",
+		Version:      "v1",
+		Author:       "Stagecraft",
+		ConfigSchema: Config{},
+	}
+}
+
+// ValidateConfig parses and validates the provider config and returns
+// auth_key_env, plus the API key env vars when key expiry or policy
+// validation needs them.
+func (p *TailscaleProvider) ValidateConfig(cfg any) ([]string, error) {
+	config, err := parseConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("tailscale provider: %w", err)
+	}
+
+	envs := []string{config.AuthKeyEnv}
+	if config.KeyExpiry.Disable {
+		envs = append(envs, config.APIKeyEnv)
+	}
+	if config.Policy != nil && !slices.Contains(envs, config.Policy.APIKeyEnv) {
+		envs = append(envs, config.Policy.APIKeyEnv)
+	}
+	return envs, nil
+}
+
 // EnsureInstalled ensures Tailscale is installed on the given host.
 func (p *TailscaleProvider) EnsureInstalled(ctx context.Context, opts network.EnsureInstalledOptions) error {
 	// Parse config
@@ -57,11 +112,8 @@ func (p *TailscaleProvider) EnsureInstalled(ctx context.Context, opts network.En
 		return nil
 	}
 
-	// Get commander (use default SSH commander if not set)
-	commander := p.commander
-	if commander == nil {
-		commander = NewSSHCommander()
-	}
+	// Get commander
+	commander := p.getCommander()
 
 	// Check if Tailscale is already installed
 	stdout, _, err := commander.Run(ctx, opts.Host, "tailscale", "version")
@@ -91,8 +143,7 @@ func (p *TailscaleProvider) EnsureInstalled(ctx context.Context, opts network.En
 	}
 
 	// Install Tailscale using official install script
-	installCmd := "curl -fsSL https://tailscale.com/install.sh | sh"
-	_, stderr, err := commander.Run(ctx, opts.Host, "sh", "-c", installCmd)
+	_, stderr, err := installCommand.Run(ctx, commander, opts.Host)
 	if err != nil {
 		return fmt.Errorf("tailscale provider: %w: %s", ErrInstallFailed, stderr)
 	}
@@ -107,6 +158,8 @@ func (p *TailscaleProvider) EnsureInstalled(ctx context.Context, opts network.En
 }
 
 // EnsureJoined ensures the host is joined to the Tailscale tailnet with the configured tags.
+// A host whose node key has expired (or expires within key_expiry.renew_before)
+// is re-joined with --force-reauth.
 func (p *TailscaleProvider) EnsureJoined(ctx context.Context, opts network.EnsureJoinedOptions) error {
 	// Parse config
 	config, err := parseConfig(opts.Config)
@@ -127,12 +180,10 @@ func (p *TailscaleProvider) EnsureJoined(ctx context.Context, opts network.Ensur
 	tags := computeTags(config, opts.Tags)
 
 	// Get commander
-	commander := p.commander
-	if commander == nil {
-		commander = NewSSHCommander()
-	}
+	commander := p.getCommander()
 
 	// Check current status
+	forceReauth := false
 	stdout, _, err := commander.Run(ctx, opts.Host, "tailscale", "status", "--json")
 	if err == nil {
 		status, err := parseStatus(stdout)
@@ -140,9 +191,15 @@ func (p *TailscaleProvider) EnsureJoined(ctx context.Context, opts network.Ensur
 			// Check if already joined correctly
 			if status.TailnetName == config.TailnetDomain ||
 				strings.HasSuffix(status.TailnetName, "."+config.TailnetDomain) {
+				renew, _ := keyNeedsRenewal(status, p.currentTime(), config.KeyExpiry.RenewBefore)
+				forceReauth = renew
 				// Check tags match
-				if tagsMatch(status.Self.Tags, tags) {
-					return nil // Already joined correctly
+				if !renew && tagsMatch(status.Self.Tags, tags) {
+					// Already joined correctly
+					if err := ensureRouting(ctx, commander, opts.Host, config); err != nil {
+						return err
+					}
+					return p.ensureKeyExpiry(ctx, config, status)
 				}
 			} else {
 				// Wrong tailnet
@@ -152,10 +209,49 @@ func (p *TailscaleProvider) EnsureJoined(ctx context.Context, opts network.Ensur
 		}
 	}
 
-	// Join to tailnet
-	joinCmd := buildTailscaleUpCommand(authKey, opts.Host, tags)
+	return p.join(ctx, commander, config, opts.Host, authKey, tags, forceReauth)
+}
+
+// Reauth forces the host to re-join the tailnet with a fresh auth key,
+// replacing its node key. Tags are default_tags plus role_tags[opts.Role].
+func (p *TailscaleProvider) Reauth(ctx context.Context, opts network.ReauthOptions) error {
+	config, err := parseConfig(opts.Config)
+	if err != nil {
+		return fmt.Errorf("tailscale provider: %w", err)
+	}
+
+	authKey, err := getEnvVar(config.AuthKeyEnv)
+	if err != nil {
+		return fmt.Errorf("tailscale provider: %w", err)
+	}
+
+	return p.join(ctx, p.getCommander(), config, opts.Host, authKey, roleTags(config, opts.Role), true)
+}
+
+// Leave logs the host out of the tailnet, which removes its node.
+func (p *TailscaleProvider) Leave(ctx context.Context, opts network.LeaveOptions) error {
+	if _, stderr, err := p.getCommander().Run(ctx, opts.Host, "tailscale", "logout"); err != nil {
+		return fmt.Errorf("tailscale provider: logout failed: %s", strings.TrimSpace(stderr))
+	}
+	return nil
+}
+
+// join runs `tailscale up` on host and verifies the resulting tailnet and tags.
+func (p *TailscaleProvider) join(ctx context.Context, commander hostexec.Commander, config *Config, host, authKey string, tags []string, forceReauth bool) error {
+	router, isRouter := config.Routers[host]
+	if isRouter {
+		if err := enableForwarding(ctx, commander, host); err != nil {
+			return err
+		}
+	}
+
+	// tailscale up rejects runs that omit non-default prefs, so router
+	// flags must accompany every join of a router host.
+	joinCmd := buildTailscaleUpCommand(authKey, host, tags).
+		Arg(routerFlags(router, isRouter)...).
+		FlagIf("force-reauth", forceReauth)
 
-	_, stderr, err := commander.Run(ctx, opts.Host, "sh", "-c", joinCmd)
+	_, stderr, err := joinCmd.Run(ctx, commander, host)
 	if err != nil {
 		// Check if it's an auth key error
 		if strings.Contains(stderr, "invalid") || strings.Contains(stderr, "expired") {
@@ -165,7 +261,7 @@ func (p *TailscaleProvider) EnsureJoined(ctx context.Context, opts network.Ensur
 	}
 
 	// Re-check status to verify join succeeded
-	stdout, _, err = commander.Run(ctx, opts.Host, "tailscale", "status", "--json")
+	stdout, _, err := commander.Run(ctx, host, "tailscale", "status", "--json")
 	if err != nil {
 		return fmt.Errorf("tailscale provider: failed to verify join: %w", err)
 	}
@@ -187,9 +283,55 @@ func (p *TailscaleProvider) EnsureJoined(ctx context.Context, opts network.Ensur
 			ErrTagMismatch, status.Self.Tags, tags)
 	}
 
+	if renew, reason := keyNeedsRenewal(status, p.currentTime(), 0); renew {
+		return fmt.Errorf("tailscale provider: %w: %s after join", ErrKeyExpired, reason)
+	}
+
+	return p.ensureKeyExpiry(ctx, config, status)
+}
+
+// ensureKeyExpiry disables node key expiry through the API when
+// key_expiry.disable is set and the node still has an expiry.
+func (p *TailscaleProvider) ensureKeyExpiry(ctx context.Context, config *Config, status *TailscaleStatus) error {
+	if !config.KeyExpiry.Disable || status.Self.KeyExpiry == nil {
+		return nil
+	}
+	if status.Self.ID == "" {
+		return fmt.Errorf("tailscale provider: %w: node ID missing from status", ErrAPIError)
+	}
+
+	apiKey := os.Getenv(config.APIKeyEnv)
+	if apiKey == "" {
+		return fmt.Errorf("tailscale provider: %w: %s", ErrAPIKeyMissing, config.APIKeyEnv)
+	}
+
+	if err := p.getAPI().SetKeyExpiryDisabled(ctx, status.Self.ID, apiKey, true); err != nil {
+		return fmt.Errorf("tailscale provider: disabling key expiry: %w", err)
+	}
 	return nil
 }
 
+func (p *TailscaleProvider) getCommander() hostexec.Commander {
+	if p.commander == nil {
+		return hostexec.NewSSHCommander(nil)
+	}
+	return p.commander
+}
+
+func (p *TailscaleProvider) getAPI() APIClient {
+	if p.api == nil {
+		return NewHTTPAPIClient()
+	}
+	return p.api
+}
+
+func (p *TailscaleProvider) currentTime() time.Time {
+	if p.now == nil {
+		return time.Now()
+	}
+	return p.now()
+}
+
 // NodeFQDN returns the FQDN for a node in the Tailscale mesh network.
 // Note: This requires config to be set via EnsureInstalled or EnsureJoined first.
 // If config is not available, returns an error.
@@ -206,12 +348,32 @@ func (p *TailscaleProvider) NodeFQDN(host string) (string, error) {
 	return buildNodeFQDN(host, p.config.TailnetDomain), nil
 }
 
-// buildTailscaleUpCommand builds the Tailscale "up" command string.
-// This is a pure function that takes explicit inputs and returns a command string.
-func buildTailscaleUpCommand(authKey, hostname string, tags []string) string {
-	tagArgs := strings.Join(tags, ",")
-	return fmt.Sprintf("tailscale up --authkey=%s --hostname=%s --advertise-tags=%s",
-		authKey, hostname, tagArgs)
+// ResolveFQDN returns the FQDN for host using the given provider config.
+// Unlike NodeFQDN, it does not depend on config captured by an earlier call.
+func (p *TailscaleProvider) ResolveFQDN(cfg any, host string) (string, error) {
+	config, err := parseConfig(cfg)
+	if err != nil {
+		return "", fmt.Errorf("tailscale provider: %w", err)
+	}
+
+	if err := validateTailnetDomain(config.TailnetDomain); err != nil {
+		return "", err
+	}
+	return buildNodeFQDN(host, config.TailnetDomain), nil
+}
+
+// installCommand pipes the official install script into sh. It is the
+// only install step that needs a shell, and it embeds no config values.
+var installCommand = hostexec.Script("curl -fsSL https://tailscale.com/install.sh | sh")
+
+// buildTailscaleUpCommand builds the Tailscale "up" command.
+// This is a pure function that takes explicit inputs and returns a command.
+// It runs without a shell, so host names and tags are passed verbatim.
+func buildTailscaleUpCommand(authKey, hostname string, tags []string) hostexec.Cmd {
+	return hostexec.Command("tailscale", "up").
+		Flag("authkey", authKey).
+		Flag("hostname", hostname).
+		Flag("advertise-tags", strings.Join(tags, ","))
 }
 
 // parseOSRelease parses the ID field from /etc/os-release content.
@@ -363,7 +525,7 @@ func tagsMatch(actual, expected []string) bool {
 }
 
 // checkOSCompatibility checks if the host OS is supported (Linux Debian/Ubuntu for v1).
-func checkOSCompatibility(ctx context.Context, commander Commander, host string) error {
+func checkOSCompatibility(ctx context.Context, commander hostexec.Commander, host string) error {
 	// Check if Linux
 	unameOut, _, err := commander.Run(ctx, host, "uname", "-s")
 	if err != nil {
@@ -409,3 +571,15 @@ func checkOSCompatibility(ctx context.Context, commander Commander, host string)
 func init() {
 	network.Register(&TailscaleProvider{})
 }
+
+// getEnvVar retrieves an auth key from the environment and registers it
+// with hostexec.Secrets, so `tailscale up --authkey=...` is never logged
+// with the key, whatever the variable is called.
+func getEnvVar(name string) (string, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return "", fmt.Errorf("%w: %s", ErrAuthKeyMissing, name)
+	}
+	hostexec.Secrets.Add(value)
+	return value, nil
+}
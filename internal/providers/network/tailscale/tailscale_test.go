@@ -21,9 +21,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 
+	hostexec "stagecraft/internal/exec"
 	"stagecraft/pkg/providers/network"
 )
 
@@ -36,11 +38,11 @@ func TestTailscaleProvider_ID(t *testing.T) {
 
 func TestTailscaleProvider_NodeFQDN_AfterEnsureInstalled(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander := provider.commander.(*hostexec.RecordingCommander)
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		Stdout:   "1.78.0",
 		ExitCode: 0,
 	}
@@ -106,9 +108,62 @@ func TestTailscaleProvider_NodeFQDN_NoConfig(t *testing.T) {
 	}
 }
 
+func TestTailscaleProvider_ResolveFQDN(t *testing.T) {
+	provider := &TailscaleProvider{}
+
+	fqdn, err := provider.ResolveFQDN(map[string]interface{}{
+		"auth_key_env":   "TS_AUTHKEY",
+		"tailnet_domain": "example.ts.net",
+	}, "app-1")
+	if err != nil {
+		t.Fatalf("ResolveFQDN() error = %v", err)
+	}
+	if fqdn != "app-1.example.ts.net" {
+		t.Errorf("ResolveFQDN() = %q, want %q", fqdn, "app-1.example.ts.net")
+	}
+	if provider.config != nil {
+		t.Error("ResolveFQDN() should not capture config on the provider")
+	}
+}
+
+func TestTailscaleProvider_ResolveFQDN_InvalidDomain(t *testing.T) {
+	provider := &TailscaleProvider{}
+
+	_, err := provider.ResolveFQDN(map[string]interface{}{
+		"auth_key_env":   "TS_AUTHKEY",
+		"tailnet_domain": "localhost",
+	}, "app-1")
+	if err == nil || !errors.Is(err, ErrConfigInvalid) {
+		t.Fatalf("ResolveFQDN() error = %v, want ErrConfigInvalid", err)
+	}
+}
+
+func TestTailscaleProvider_ValidateConfig(t *testing.T) {
+	provider := &TailscaleProvider{}
+
+	envs, err := provider.ValidateConfig(map[string]interface{}{
+		"auth_key_env":   "TS_AUTHKEY",
+		"tailnet_domain": "example.ts.net",
+		"api_key_env":    "TS_API_KEY",
+		"key_expiry":     map[string]interface{}{"disable": true},
+		"policy":         map[string]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	want := []string{"TS_AUTHKEY", "TS_API_KEY"}
+	if !reflect.DeepEqual(envs, want) {
+		t.Errorf("ValidateConfig() = %v, want %v", envs, want)
+	}
+
+	if _, err := provider.ValidateConfig(map[string]interface{}{"tailnet_domain": "example.ts.net"}); !errors.Is(err, ErrConfigInvalid) {
+		t.Errorf("ValidateConfig() without auth_key_env error = %v, want ErrConfigInvalid", err)
+	}
+}
+
 func TestTailscaleProvider_EnsureInstalled_AlreadyInstalled(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 		config: &Config{
 			AuthKeyEnv:    "TS_AUTHKEY",
 			TailnetDomain: "example.ts.net",
@@ -118,8 +173,8 @@ func TestTailscaleProvider_EnsureInstalled_AlreadyInstalled(t *testing.T) {
 		},
 	}
 
-	commander := provider.commander.(*LocalCommander)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander := provider.commander.(*hostexec.RecordingCommander)
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		Stdout:   "1.78.0",
 		ExitCode: 0,
 	}
@@ -140,26 +195,26 @@ func TestTailscaleProvider_EnsureInstalled_AlreadyInstalled(t *testing.T) {
 
 func TestTailscaleProvider_EnsureInstalled_InstallSucceeds(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// First check: not installed (tailscale version fails)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("command not found"),
 	}
 	// which also fails
-	commander.Commands["app-1 which tailscale"] = CommandResult{
+	commander.Results["app-1 which tailscale"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("command not found"),
 	}
 	// Install script succeeds (note: commander will unwrap "sh -c" commands)
-	commander.Commands["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = CommandResult{
+	commander.Results["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = hostexec.Result{
 		ExitCode: 0,
 	}
 	// Verify installation (second call after install)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		Stdout:   "1.78.0",
 		ExitCode: 0,
 	}
@@ -180,7 +235,7 @@ func TestTailscaleProvider_EnsureInstalled_InstallSucceeds(t *testing.T) {
 
 func TestTailscaleProvider_EnsureInstalled_InstallSkipped(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 		config: &Config{
 			AuthKeyEnv:    "TS_AUTHKEY",
 			TailnetDomain: "example.ts.net",
@@ -213,7 +268,7 @@ func TestTailscaleProvider_EnsureJoined_AlreadyJoined(t *testing.T) {
 	defer func() { _ = os.Unsetenv("TS_AUTHKEY") }()
 
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 		config: &Config{
 			AuthKeyEnv:    "TS_AUTHKEY",
 			TailnetDomain: "example.ts.net",
@@ -221,7 +276,7 @@ func TestTailscaleProvider_EnsureJoined_AlreadyJoined(t *testing.T) {
 		},
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// Status shows already joined correctly
 	statusJSON := `{
 		"TailnetName": "example.ts.net",
@@ -231,7 +286,7 @@ func TestTailscaleProvider_EnsureJoined_AlreadyJoined(t *testing.T) {
 			"Tags": ["tag:stagecraft", "tag:app"]
 		}
 	}`
-	commander.Commands["app-1 tailscale status --json"] = CommandResult{
+	commander.Results["app-1 tailscale status --json"] = hostexec.Result{
 		Stdout:   statusJSON,
 		ExitCode: 0,
 	}
@@ -257,7 +312,7 @@ func TestTailscaleProvider_EnsureJoined_AuthKeyMissing(t *testing.T) {
 	_ = os.Unsetenv("TS_AUTHKEY")
 
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 		config: &Config{
 			AuthKeyEnv:    "TS_AUTHKEY",
 			TailnetDomain: "example.ts.net",
@@ -287,14 +342,14 @@ func TestTailscaleProvider_EnsureJoined_WrongTailnet(t *testing.T) {
 	defer func() { _ = os.Unsetenv("TS_AUTHKEY") }()
 
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 		config: &Config{
 			AuthKeyEnv:    "TS_AUTHKEY",
 			TailnetDomain: "example.ts.net",
 		},
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// Status shows different tailnet
 	statusJSON := `{
 		"TailnetName": "other.ts.net",
@@ -304,7 +359,7 @@ func TestTailscaleProvider_EnsureJoined_WrongTailnet(t *testing.T) {
 			"Tags": []
 		}
 	}`
-	commander.Commands["app-1 tailscale status --json"] = CommandResult{
+	commander.Results["app-1 tailscale status --json"] = hostexec.Result{
 		Stdout:   statusJSON,
 		ExitCode: 0,
 	}
@@ -329,7 +384,7 @@ func TestTailscaleProvider_EnsureJoined_WrongTailnet(t *testing.T) {
 
 func TestTailscaleProvider_EnsureInstalled_ConfigError(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
 	opts := network.EnsureInstalledOptions{
@@ -351,26 +406,26 @@ func TestTailscaleProvider_EnsureInstalled_ConfigError(t *testing.T) {
 
 func TestTailscaleProvider_EnsureInstalled_InstallFails(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// First check: not installed
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("command not found"),
 	}
 
 	// OS compatibility: Debian supported
-	commander.Commands["app-1 uname -s"] = CommandResult{
+	commander.Results["app-1 uname -s"] = hostexec.Result{
 		Stdout: "Linux",
 	}
-	commander.Commands["app-1 cat /etc/os-release"] = CommandResult{
+	commander.Results["app-1 cat /etc/os-release"] = hostexec.Result{
 		Stdout: "ID=debian\n",
 	}
 
 	// Install script fails
-	commander.Commands["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = CommandResult{
+	commander.Results["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = hostexec.Result{
 		ExitCode: 1,
 		Stderr:   "install failed",
 		Error:    fmt.Errorf("install failed"),
@@ -400,7 +455,7 @@ func TestTailscaleProvider_EnsureInstalled_InstallFails(t *testing.T) {
 
 func TestTailscaleProvider_EnsureJoined_ConfigError(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
 	opts := network.EnsureJoinedOptions{
@@ -506,7 +561,7 @@ func TestTailscaleProvider_EnsureJoined_MissingAuthKey(t *testing.T) {
 	_ = os.Unsetenv("TS_AUTHKEY")
 
 	p := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
 	opts := network.EnsureJoinedOptions{
@@ -578,17 +633,17 @@ func TestParseConfig_MissingAuthKeyEnv(t *testing.T) {
 
 func TestTailscaleProvider_EnsureInstalled_UnsupportedOS(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// tailscale version fails (not installed)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("command not found"),
 	}
 	// uname returns non-Linux
-	commander.Commands["app-1 uname -s"] = CommandResult{
+	commander.Results["app-1 uname -s"] = hostexec.Result{
 		Stdout:   "Darwin",
 		ExitCode: 0,
 	}
@@ -613,22 +668,22 @@ func TestTailscaleProvider_EnsureInstalled_UnsupportedOS(t *testing.T) {
 
 func TestTailscaleProvider_EnsureInstalled_UnsupportedDistribution(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// tailscale version fails (not installed)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("command not found"),
 	}
 	// uname returns Linux
-	commander.Commands["app-1 uname -s"] = CommandResult{
+	commander.Results["app-1 uname -s"] = hostexec.Result{
 		Stdout:   "Linux",
 		ExitCode: 0,
 	}
 	// os-release shows non-Debian/Ubuntu distro
-	commander.Commands["app-1 cat /etc/os-release"] = CommandResult{
+	commander.Results["app-1 cat /etc/os-release"] = hostexec.Result{
 		Stdout:   "ID=centos\n",
 		ExitCode: 0,
 	}
@@ -653,31 +708,31 @@ func TestTailscaleProvider_EnsureInstalled_UnsupportedDistribution(t *testing.T)
 
 func TestTailscaleProvider_EnsureInstalled_SupportedDistribution(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// tailscale version fails (not installed)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("command not found"),
 	}
 	// uname returns Linux
-	commander.Commands["app-1 uname -s"] = CommandResult{
+	commander.Results["app-1 uname -s"] = hostexec.Result{
 		Stdout:   "Linux",
 		ExitCode: 0,
 	}
 	// os-release shows Ubuntu
-	commander.Commands["app-1 cat /etc/os-release"] = CommandResult{
+	commander.Results["app-1 cat /etc/os-release"] = hostexec.Result{
 		Stdout:   "ID=ubuntu\n",
 		ExitCode: 0,
 	}
 	// Install succeeds
-	commander.Commands["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = CommandResult{
+	commander.Results["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = hostexec.Result{
 		ExitCode: 0,
 	}
 	// Verify installation
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		Stdout:   "1.78.0",
 		ExitCode: 0,
 	}
@@ -698,31 +753,31 @@ func TestTailscaleProvider_EnsureInstalled_SupportedDistribution(t *testing.T) {
 
 func TestTailscaleProvider_EnsureInstalled_SupportedDistributionDebian(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// tailscale version fails (not installed)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("command not found"),
 	}
 	// uname returns Linux
-	commander.Commands["app-1 uname -s"] = CommandResult{
+	commander.Results["app-1 uname -s"] = hostexec.Result{
 		Stdout:   "Linux",
 		ExitCode: 0,
 	}
 	// os-release shows Debian
-	commander.Commands["app-1 cat /etc/os-release"] = CommandResult{
+	commander.Results["app-1 cat /etc/os-release"] = hostexec.Result{
 		Stdout:   "ID=debian\n",
 		ExitCode: 0,
 	}
 	// Install succeeds
-	commander.Commands["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = CommandResult{
+	commander.Results["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = hostexec.Result{
 		ExitCode: 0,
 	}
 	// Verify installation
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		Stdout:   "1.78.0",
 		ExitCode: 0,
 	}
@@ -743,26 +798,26 @@ func TestTailscaleProvider_EnsureInstalled_SupportedDistributionDebian(t *testin
 
 func TestTailscaleProvider_EnsureInstalled_OSCheckFailsGracefully(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// tailscale version fails (not installed)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("command not found"),
 	}
 	// uname fails (should proceed gracefully)
-	commander.Commands["app-1 uname -s"] = CommandResult{
+	commander.Results["app-1 uname -s"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("uname failed"),
 	}
 	// Install succeeds (OS check failed but we proceed)
-	commander.Commands["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = CommandResult{
+	commander.Results["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = hostexec.Result{
 		ExitCode: 0,
 	}
 	// Verify installation
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		Stdout:   "1.78.0",
 		ExitCode: 0,
 	}
@@ -783,36 +838,36 @@ func TestTailscaleProvider_EnsureInstalled_OSCheckFailsGracefully(t *testing.T)
 
 func TestTailscaleProvider_EnsureInstalled_LSBReleaseFallback(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// tailscale version fails (not installed)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("command not found"),
 	}
 	// uname returns Linux
-	commander.Commands["app-1 uname -s"] = CommandResult{
+	commander.Results["app-1 uname -s"] = hostexec.Result{
 		Stdout:   "Linux",
 		ExitCode: 0,
 	}
 	// os-release doesn't exist
-	commander.Commands["app-1 cat /etc/os-release"] = CommandResult{
+	commander.Results["app-1 cat /etc/os-release"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("file not found"),
 	}
 	// lsb_release shows Ubuntu
-	commander.Commands["app-1 lsb_release -i -s"] = CommandResult{
+	commander.Results["app-1 lsb_release -i -s"] = hostexec.Result{
 		Stdout:   "Ubuntu",
 		ExitCode: 0,
 	}
 	// Install succeeds
-	commander.Commands["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = CommandResult{
+	commander.Results["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = hostexec.Result{
 		ExitCode: 0,
 	}
 	// Verify installation
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		Stdout:   "1.78.0",
 		ExitCode: 0,
 	}
@@ -836,21 +891,18 @@ func TestTailscaleProvider_EnsureJoined_JoinSucceeds(t *testing.T) {
 	defer func() { _ = os.Unsetenv("TS_AUTHKEY") }()
 
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// Status check fails (not joined)
-	commander.Commands["app-1 tailscale status --json"] = CommandResult{
+	commander.Results["app-1 tailscale status --json"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("not joined"),
 	}
 	// Join succeeds
 	joinCmd := "tailscale up --authkey=test-auth-key --hostname=app-1 --advertise-tags=tag:stagecraft,tag:app"
-	commander.Commands["app-1 sh -c "+joinCmd] = CommandResult{
-		ExitCode: 0,
-	}
-	commander.Commands["app-1 "+joinCmd] = CommandResult{
+	commander.Results["app-1 "+joinCmd] = hostexec.Result{
 		ExitCode: 0,
 	}
 	// Re-check status shows correct tailnet and tags
@@ -862,7 +914,7 @@ func TestTailscaleProvider_EnsureJoined_JoinSucceeds(t *testing.T) {
 			"Tags": ["tag:stagecraft", "tag:app"]
 		}
 	}`
-	commander.Commands["app-1 tailscale status --json"] = CommandResult{
+	commander.Results["app-1 tailscale status --json"] = hostexec.Result{
 		Stdout:   statusJSON,
 		ExitCode: 0,
 	}
@@ -888,21 +940,18 @@ func TestTailscaleProvider_EnsureJoined_StatusParseError(t *testing.T) {
 	defer func() { _ = os.Unsetenv("TS_AUTHKEY") }()
 
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// Status check returns invalid JSON
-	commander.Commands["app-1 tailscale status --json"] = CommandResult{
+	commander.Results["app-1 tailscale status --json"] = hostexec.Result{
 		Stdout:   "invalid json",
 		ExitCode: 0,
 	}
 	// Join succeeds
 	joinCmd := "tailscale up --authkey=test-auth-key --hostname=app-1 --advertise-tags=tag:stagecraft,tag:app"
-	commander.Commands["app-1 sh -c "+joinCmd] = CommandResult{
-		ExitCode: 0,
-	}
-	commander.Commands["app-1 "+joinCmd] = CommandResult{
+	commander.Results["app-1 "+joinCmd] = hostexec.Result{
 		ExitCode: 0,
 	}
 	// Re-check status returns valid JSON
@@ -914,7 +963,7 @@ func TestTailscaleProvider_EnsureJoined_StatusParseError(t *testing.T) {
 			"Tags": ["tag:stagecraft", "tag:app"]
 		}
 	}`
-	commander.Commands["app-1 tailscale status --json"] = CommandResult{
+	commander.Results["app-1 tailscale status --json"] = hostexec.Result{
 		Stdout:   statusJSON,
 		ExitCode: 0,
 	}
@@ -940,23 +989,18 @@ func TestTailscaleProvider_EnsureJoined_JoinFails(t *testing.T) {
 	defer func() { _ = os.Unsetenv("TS_AUTHKEY") }()
 
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 	// Status check fails (not joined)
-	commander.Commands["app-1 tailscale status --json"] = CommandResult{
+	commander.Results["app-1 tailscale status --json"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("not joined"),
 	}
 	// Join fails
 	joinCmd := "tailscale up --authkey=test-auth-key --hostname=app-1 --advertise-tags=tag:stagecraft,tag:app"
-	commander.Commands["app-1 sh -c "+joinCmd] = CommandResult{
-		ExitCode: 1,
-		Stderr:   "join failed",
-		Error:    fmt.Errorf("join failed"),
-	}
-	commander.Commands["app-1 "+joinCmd] = CommandResult{
+	commander.Results["app-1 "+joinCmd] = hostexec.Result{
 		ExitCode: 1,
 		Stderr:   "join failed",
 		Error:    fmt.Errorf("join failed"),
@@ -1013,27 +1057,34 @@ func TestBuildTailscaleUpCommand(t *testing.T) {
 			authKey:  "tskey-auth-123",
 			hostname: "app-1",
 			tags:     []string{"tag:web"},
-			want:     "tailscale up --authkey=tskey-auth-123 --hostname=app-1 --advertise-tags=tag:web",
+			want:     "tailscale 'up' '--authkey=tskey-auth-123' '--hostname=app-1' '--advertise-tags=tag:web'",
 		},
 		{
 			name:     "multiple tags",
 			authKey:  "tskey-auth-123",
 			hostname: "app-1",
 			tags:     []string{"tag:web", "tag:prod"},
-			want:     "tailscale up --authkey=tskey-auth-123 --hostname=app-1 --advertise-tags=tag:web,tag:prod",
+			want:     "tailscale 'up' '--authkey=tskey-auth-123' '--hostname=app-1' '--advertise-tags=tag:web,tag:prod'",
 		},
 		{
 			name:     "no tags",
 			authKey:  "tskey-auth-123",
 			hostname: "app-1",
 			tags:     []string{},
-			want:     "tailscale up --authkey=tskey-auth-123 --hostname=app-1 --advertise-tags=",
+			want:     "tailscale 'up' '--authkey=tskey-auth-123' '--hostname=app-1' '--advertise-tags='",
+		},
+		{
+			name:     "shell syntax stays one word",
+			authKey:  "tskey-auth-123",
+			hostname: "app-1; rm -rf /",
+			tags:     []string{"tag:web$(id)"},
+			want:     "tailscale 'up' '--authkey=tskey-auth-123' '--hostname=app-1; rm -rf /' '--advertise-tags=tag:web$(id)'",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildTailscaleUpCommand(tt.authKey, tt.hostname, tt.tags)
+			got := buildTailscaleUpCommand(tt.authKey, tt.hostname, tt.tags).String()
 			if got != tt.want {
 				t.Errorf("buildTailscaleUpCommand() = %q, want %q", got, tt.want)
 			}
@@ -1352,14 +1403,14 @@ func TestEnsureInstalled_ConfigValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			commander := NewLocalCommander()
+			commander := hostexec.NewRecordingCommander()
 			provider := &TailscaleProvider{
 				commander: commander,
 			}
 
 			// For valid config and skip method, set up Commander to simulate already installed
 			if !tt.wantErr {
-				commander.Commands["test-host tailscale version"] = CommandResult{
+				commander.Results["test-host tailscale version"] = hostexec.Result{
 					Stdout: "1.78.0",
 				}
 			}
@@ -1473,17 +1524,17 @@ func TestEnsureInstalled_OSCompatibility(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			commander := NewLocalCommander()
+			commander := hostexec.NewRecordingCommander()
 
 			// Always simulate "not installed" to force OS compatibility check
-			commander.Commands["test-host tailscale version"] = CommandResult{
+			commander.Results["test-host tailscale version"] = hostexec.Result{
 				ExitCode: 1,
 				Error:    fmt.Errorf("command not found"),
 			}
 
 			// Wire uname command
 			if tt.unameOut != "" || tt.unameErr != nil {
-				commander.Commands["test-host uname -s"] = CommandResult{
+				commander.Results["test-host uname -s"] = hostexec.Result{
 					Stdout: tt.unameOut,
 					Error:  tt.unameErr,
 				}
@@ -1491,7 +1542,7 @@ func TestEnsureInstalled_OSCompatibility(t *testing.T) {
 
 			// Wire os-release command
 			if tt.osRelease != "" || tt.osReleaseErr != nil {
-				commander.Commands["test-host cat /etc/os-release"] = CommandResult{
+				commander.Results["test-host cat /etc/os-release"] = hostexec.Result{
 					Stdout: tt.osRelease,
 					Error:  tt.osReleaseErr,
 				}
@@ -1499,7 +1550,7 @@ func TestEnsureInstalled_OSCompatibility(t *testing.T) {
 
 			// Wire lsb_release fallback
 			if tt.lsbRelease != "" || tt.lsbReleaseErr != nil {
-				commander.Commands["test-host lsb_release -i -s"] = CommandResult{
+				commander.Results["test-host lsb_release -i -s"] = hostexec.Result{
 					Stdout: tt.lsbRelease,
 					Error:  tt.lsbReleaseErr,
 				}
@@ -1507,12 +1558,12 @@ func TestEnsureInstalled_OSCompatibility(t *testing.T) {
 
 			// For supported OS, stub install script and verification
 			if !tt.wantErr {
-				commander.Commands["test-host curl -fsSL https://tailscale.com/install.sh | sh"] = CommandResult{
+				commander.Results["test-host curl -fsSL https://tailscale.com/install.sh | sh"] = hostexec.Result{
 					ExitCode: 0,
 					Stdout:   "Installation successful",
 				}
 				// Second tailscale version call for verification
-				commander.Commands["test-host tailscale version"] = CommandResult{
+				commander.Results["test-host tailscale version"] = hostexec.Result{
 					Stdout:   "1.78.0",
 					ExitCode: 0,
 				}
@@ -1610,8 +1661,8 @@ func TestEnsureInstalled_VersionEnforcement(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			commander := NewLocalCommander()
-			commander.Commands["app-1 tailscale version"] = CommandResult{
+			commander := hostexec.NewRecordingCommander()
+			commander.Results["app-1 tailscale version"] = hostexec.Result{
 				Stdout:   tt.installed,
 				ExitCode: 0,
 			}
@@ -1658,33 +1709,33 @@ func TestEnsureInstalled_VersionEnforcement(t *testing.T) {
 
 func TestTailscaleProvider_EnsureInstalled_VerificationFails(t *testing.T) {
 	provider := &TailscaleProvider{
-		commander: NewLocalCommander(),
+		commander: hostexec.NewRecordingCommander(),
 	}
 
-	commander := provider.commander.(*LocalCommander)
+	commander := provider.commander.(*hostexec.RecordingCommander)
 
 	// First check: not installed
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("command not found"),
 	}
 
 	// OS compatibility: Debian supported
-	commander.Commands["app-1 uname -s"] = CommandResult{
+	commander.Results["app-1 uname -s"] = hostexec.Result{
 		Stdout: "Linux",
 	}
-	commander.Commands["app-1 cat /etc/os-release"] = CommandResult{
+	commander.Results["app-1 cat /etc/os-release"] = hostexec.Result{
 		Stdout: "ID=debian\n",
 	}
 
 	// Install script succeeds
-	commander.Commands["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = CommandResult{
+	commander.Results["app-1 curl -fsSL https://tailscale.com/install.sh | sh"] = hostexec.Result{
 		ExitCode: 0,
 		Stdout:   "Installation successful",
 	}
 
 	// Verification fails (second tailscale version call)
-	commander.Commands["app-1 tailscale version"] = CommandResult{
+	commander.Results["app-1 tailscale version"] = hostexec.Result{
 		ExitCode: 1,
 		Error:    fmt.Errorf("verification failed"),
 	}
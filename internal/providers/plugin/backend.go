@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package plugin
+
+import (
+	"context"
+	"os"
+
+	"stagecraft/pkg/providers/backend"
+)
+
+// Feature: PROVIDER_PLUGINS
+// Spec: spec/providers/plugins.md
+
+// BackendProvider adapts a backend plugin to backend.BackendProvider.
+type BackendProvider struct {
+	client *Client
+}
+
+// Ensure BackendProvider implements backend.BackendProvider
+var _ backend.BackendProvider = (*BackendProvider)(nil)
+
+// Ensure BackendProvider implements backend.MetadataProvider
+var _ backend.MetadataProvider = (*BackendProvider)(nil)
+
+type backendParams struct {
+	Config   any               `json:"config"`
+	WorkDir  string            `json:"work_dir,omitempty"`
+	ImageTag string            `json:"image_tag,omitempty"`
+	Env      map[string]string `json:"env,omitempty"`
+}
+
+type wireBuildResult struct {
+	Image string `json:"image"`
+}
+
+type wireProviderStep struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type wireProviderPlan struct {
+	Steps []wireProviderStep `json:"steps"`
+}
+
+// ID returns the plugin's provider ID.
+func (p *BackendProvider) ID() string {
+	return p.client.ID()
+}
+
+// Metadata returns the manifest's description and version.
+func (p *BackendProvider) Metadata() backend.ProviderMetadata {
+	return backend.ProviderMetadata{
+		Name:        p.client.manifest.ID,
+		Description: p.client.manifest.Description,
+		Version:     p.client.manifest.Version,
+	}
+}
+
+// Dev runs the plugin's "dev" method, streaming its output to stderr until it exits.
+func (p *BackendProvider) Dev(ctx context.Context, opts backend.DevOptions) error {
+	return p.client.Stream(ctx, "dev", backendParams{Config: opts.Config, WorkDir: opts.WorkDir, Env: opts.Env}, os.Stderr)
+}
+
+// BuildDocker calls the plugin's "build_docker" method and returns the built image.
+func (p *BackendProvider) BuildDocker(ctx context.Context, opts backend.BuildDockerOptions) (string, error) {
+	var result wireBuildResult
+	if err := p.client.Call(ctx, "build_docker", backendParams{Config: opts.Config, WorkDir: opts.WorkDir, ImageTag: opts.ImageTag}, &result); err != nil {
+		return "", err
+	}
+	if result.Image == "" {
+		return opts.ImageTag, nil
+	}
+	return result.Image, nil
+}
+
+// Plan calls the plugin's "plan" method.
+func (p *BackendProvider) Plan(ctx context.Context, opts backend.PlanOptions) (backend.ProviderPlan, error) {
+	var plan wireProviderPlan
+	if err := p.client.Call(ctx, "plan", backendParams{Config: opts.Config, WorkDir: opts.WorkDir, ImageTag: opts.ImageTag}, &plan); err != nil {
+		return backend.ProviderPlan{}, err
+	}
+
+	steps := make([]backend.ProviderStep, 0, len(plan.Steps))
+	for _, s := range plan.Steps {
+		steps = append(steps, backend.ProviderStep(s))
+	}
+	return backend.ProviderPlan{Provider: p.ID(), Steps: steps}, nil
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package plugin
+
+import (
+	"context"
+
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: PROVIDER_PLUGINS
+// Spec: spec/providers/plugins.md
+
+// CloudProvider adapts a cloud plugin to cloud.CloudProvider.
+type CloudProvider struct {
+	client *Client
+}
+
+// Ensure CloudProvider implements cloud.CloudProvider
+var _ cloud.CloudProvider = (*CloudProvider)(nil)
+
+// Ensure CloudProvider implements cloud.MetadataProvider
+var _ cloud.MetadataProvider = (*CloudProvider)(nil)
+
+// wireHostSpec is the protocol form of cloud.HostSpec.
+type wireHostSpec struct {
+	Name   string `json:"name"`
+	Role   string `json:"role,omitempty"`
+	Size   string `json:"size,omitempty"`
+	Region string `json:"region,omitempty"`
+}
+
+// wireInfraPlan is the protocol form of cloud.InfraPlan.
+type wireInfraPlan struct {
+	ToCreate []wireHostSpec `json:"to_create"`
+	ToDelete []wireHostSpec `json:"to_delete"`
+}
+
+// wireHost is the protocol form of cloud.Host.
+type wireHost struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Role     string   `json:"role,omitempty"`
+	PublicIP string   `json:"public_ip,omitempty"`
+	Region   string   `json:"region,omitempty"`
+	Status   string   `json:"status,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+type cloudParams struct {
+	Config      any            `json:"config"`
+	Environment string         `json:"environment"`
+	Plan        *wireInfraPlan `json:"plan,omitempty"`
+}
+
+// ID returns the plugin's provider ID.
+func (p *CloudProvider) ID() string {
+	return p.client.ID()
+}
+
+// Metadata returns the manifest's description and version.
+func (p *CloudProvider) Metadata() cloud.ProviderMetadata {
+	return cloud.ProviderMetadata{
+		Name:        p.client.manifest.ID,
+		Description: p.client.manifest.Description,
+		Version:     p.client.manifest.Version,
+	}
+}
+
+// Plan calls the plugin's "plan" method.
+func (p *CloudProvider) Plan(ctx context.Context, opts cloud.PlanOptions) (cloud.InfraPlan, error) {
+	var plan wireInfraPlan
+	if err := p.client.Call(ctx, "plan", cloudParams{Config: opts.Config, Environment: opts.Environment}, &plan); err != nil {
+		return cloud.InfraPlan{}, err
+	}
+	return cloud.InfraPlan{
+		ToCreate: hostSpecsFromWire(plan.ToCreate),
+		ToDelete: hostSpecsFromWire(plan.ToDelete),
+	}, nil
+}
+
+// Apply calls the plugin's "apply" method with the plan to execute.
+//
+//nolint:gocritic // hugeParam: opts matches CloudProvider interface signature
+func (p *CloudProvider) Apply(ctx context.Context, opts cloud.ApplyOptions) error {
+	plan := wireInfraPlan{
+		ToCreate: hostSpecsToWire(opts.Plan.ToCreate),
+		ToDelete: hostSpecsToWire(opts.Plan.ToDelete),
+	}
+	return p.client.Call(ctx, "apply", cloudParams{Config: opts.Config, Environment: opts.Environment, Plan: &plan}, nil)
+}
+
+// Hosts calls the plugin's "hosts" method.
+func (p *CloudProvider) Hosts(ctx context.Context, opts cloud.HostsOptions) ([]cloud.Host, error) {
+	var hosts []wireHost
+	if err := p.client.Call(ctx, "hosts", cloudParams{Config: opts.Config, Environment: opts.Environment}, &hosts); err != nil {
+		return nil, err
+	}
+
+	result := make([]cloud.Host, 0, len(hosts))
+	for _, h := range hosts {
+		result = append(result, cloud.Host{
+			ID:       h.ID,
+			Name:     h.Name,
+			Role:     h.Role,
+			PublicIP: h.PublicIP,
+			Region:   h.Region,
+			Status:   h.Status,
+			Tags:     h.Tags,
+		})
+	}
+	return result, nil
+}
+
+func hostSpecsFromWire(specs []wireHostSpec) []cloud.HostSpec {
+	if len(specs) == 0 {
+		return nil
+	}
+	result := make([]cloud.HostSpec, 0, len(specs))
+	for _, s := range specs {
+		result = append(result, cloud.HostSpec(s))
+	}
+	return result
+}
+
+func hostSpecsToWire(specs []cloud.HostSpec) []wireHostSpec {
+	result := make([]wireHostSpec, 0, len(specs))
+	for _, s := range specs {
+		result = append(result, wireHostSpec(s))
+	}
+	return result
+}
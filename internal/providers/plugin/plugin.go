@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package plugin loads external provider plugins: executables that speak a
+// JSON-over-stdio protocol and are registered alongside built-in providers.
+//
+// Plugins are discovered from <project>/.stagecraft/plugins/<name>/plugin.json.
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"stagecraft/internal/failure"
+	"stagecraft/pkg/providers/backend"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: PROVIDER_PLUGINS
+// Spec: spec/providers/plugins.md
+
+// Dir is the project-relative directory plugins are discovered from.
+const Dir = ".stagecraft/plugins"
+
+// ManifestFile is the manifest file name inside each plugin directory.
+const ManifestFile = "plugin.json"
+
+// Supported plugin kinds.
+const (
+	KindBackend = "backend"
+	KindCloud   = "cloud"
+)
+
+// Error definitions for plugin loading and calls, with their failure class.
+var (
+	// ErrInvalidManifest indicates a plugin manifest is missing fields or malformed.
+	ErrInvalidManifest = failure.New(failure.ClassConfigInvalid, "invalid plugin manifest")
+
+	// ErrConflict indicates a plugin ID collides with another registered provider.
+	ErrConflict = failure.New(failure.ClassConfigInvalid, "plugin id conflicts with a registered provider")
+
+	// ErrProtocol indicates a plugin response could not be understood.
+	ErrProtocol = failure.New(failure.ClassProviderFailure, "plugin protocol error")
+
+	// ErrPluginFailed indicates a plugin reported an error or exited non-zero.
+	ErrPluginFailed = failure.New(failure.ClassProviderFailure, "plugin failed")
+)
+
+// Manifest describes an external provider plugin.
+type Manifest struct {
+	// ID is the provider ID referenced from stagecraft.yml (e.g., "acme-cloud").
+	ID string `json:"id"`
+
+	// Kind is the provider kind: "cloud" or "backend".
+	Kind string `json:"kind"`
+
+	// Command is the executable to run. Relative paths resolve against the
+	// plugin directory; bare names are looked up on PATH.
+	Command string `json:"command"`
+
+	// Args are extra arguments passed before the protocol starts.
+	Args []string `json:"args,omitempty"`
+
+	// Version is the plugin's own version, shown in provider listings.
+	Version string `json:"version,omitempty"`
+
+	// Description is shown in provider listings.
+	Description string `json:"description,omitempty"`
+
+	// Dir is the plugin directory; set by Discover.
+	Dir string `json:"-"`
+}
+
+// Discover reads every <dir>/<name>/plugin.json, sorted by directory name.
+// A missing dir yields no plugins.
+func Discover(dir string) ([]Manifest, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading plugin directory: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var manifests []Manifest
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pluginDir := filepath.Join(dir, entry.Name())
+		m, err := readManifest(pluginDir)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			continue
+		}
+		manifests = append(manifests, *m)
+	}
+	return manifests, nil
+}
+
+// readManifest reads and validates a plugin manifest; a directory without
+// a manifest returns nil.
+func readManifest(pluginDir string) (*Manifest, error) {
+	path := filepath.Join(pluginDir, ManifestFile)
+	// nolint:gosec // G304: reading plugin manifests from the project is expected behavior
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidManifest, path, err)
+	}
+
+	switch {
+	case m.ID == "":
+		return nil, fmt.Errorf("%w: %s: id is required", ErrInvalidManifest, path)
+	case m.Command == "":
+		return nil, fmt.Errorf("%w: %s: command is required", ErrInvalidManifest, path)
+	case m.Kind != KindBackend && m.Kind != KindCloud:
+		return nil, fmt.Errorf("%w: %s: unsupported kind %q (want %q or %q)", ErrInvalidManifest, path, m.Kind, KindBackend, KindCloud)
+	}
+
+	m.Dir = pluginDir
+	return &m, nil
+}
+
+var (
+	loadedMu sync.Mutex
+	// loaded maps "<kind>/<id>" to the manifest directory that registered it,
+	// so loading the same project twice is a no-op.
+	loaded = make(map[string]string)
+)
+
+// LoadProject discovers plugins under <projectDir>/.stagecraft/plugins and
+// registers them with the cloud and backend registries.
+func LoadProject(projectDir string) error {
+	manifests, err := Discover(filepath.Join(projectDir, Dir))
+	if err != nil {
+		return err
+	}
+	for _, m := range manifests {
+		if err := Register(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Register adds the plugin to the registry for its kind. Registering the
+// same plugin directory again is a no-op; any other ID collision is an error.
+func Register(m Manifest) error { //nolint:gocritic // hugeParam: manifests are small and copied once
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+
+	key := m.Kind + "/" + m.ID
+	if dir, ok := loaded[key]; ok {
+		if dir == m.Dir {
+			return nil
+		}
+		return fmt.Errorf("%w: %s provider %q (also defined in %s)", ErrConflict, m.Kind, m.ID, dir)
+	}
+
+	client := NewClient(m)
+	switch m.Kind {
+	case KindCloud:
+		if cloud.Has(m.ID) {
+			return fmt.Errorf("%w: cloud provider %q", ErrConflict, m.ID)
+		}
+		cloud.Register(&CloudProvider{client: client})
+	case KindBackend:
+		if backend.Has(m.ID) {
+			return fmt.Errorf("%w: backend provider %q", ErrConflict, m.ID)
+		}
+		backend.Register(&BackendProvider{client: client})
+	}
+
+	loaded[key] = m.Dir
+	return nil
+}
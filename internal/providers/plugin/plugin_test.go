@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/providers/backend"
+	"stagecraft/pkg/providers/cloud"
+)
+
+// Feature: PROVIDER_PLUGINS
+// Spec: spec/providers/plugins.md
+
+const cloudPluginScript = `#!/bin/sh
+req=$(cat)
+case "$req" in
+  *'"method":"plan"'*)
+    echo '{"result":{"to_create":[{"name":"app-1","role":"app","size":"s-1vcpu-1gb","region":"nyc1"}],"to_delete":[]}}' ;;
+  *'"method":"hosts"'*)
+    echo '{"result":[{"id":"42","name":"app-1","role":"app","public_ip":"203.0.113.10","status":"active"}]}' ;;
+  *'"method":"apply"'*)
+    printf '%s' "$req" > apply.json
+    echo '{"result":{}}' ;;
+  *)
+    echo '{"error":{"message":"unsupported method"}}' ;;
+esac
+`
+
+// writePlugin creates <root>/.stagecraft/plugins/<name> with a manifest and script.
+func writePlugin(t *testing.T, root, name, manifest, script string) string {
+	t.Helper()
+	dir := filepath.Join(root, Dir, name)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatalf("creating plugin dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0o600); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+	if script != "" {
+		//nolint:gosec // G306: 0755 is required for executable test scripts
+		if err := os.WriteFile(filepath.Join(dir, "plugin.sh"), []byte(script), 0o755); err != nil {
+			t.Fatalf("writing plugin script: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestDiscover_MissingDirYieldsNoPlugins(t *testing.T) {
+	manifests, err := Discover(filepath.Join(t.TempDir(), "nope"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(manifests) != 0 {
+		t.Fatalf("expected no plugins, got %v", manifests)
+	}
+}
+
+func TestDiscover_SortedAndSkipsDirsWithoutManifest(t *testing.T) {
+	root := t.TempDir()
+	writePlugin(t, root, "b", `{"id":"b-cloud","kind":"cloud","command":"./plugin.sh"}`, "")
+	writePlugin(t, root, "a", `{"id":"a-backend","kind":"backend","command":"acme-backend"}`, "")
+	if err := os.MkdirAll(filepath.Join(root, Dir, "c"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	manifests, err := Discover(filepath.Join(root, Dir))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("expected 2 plugins, got %d", len(manifests))
+	}
+	if manifests[0].ID != "a-backend" || manifests[1].ID != "b-cloud" {
+		t.Fatalf("unexpected order: %s, %s", manifests[0].ID, manifests[1].ID)
+	}
+	if manifests[1].Dir != filepath.Join(root, Dir, "b") {
+		t.Fatalf("expected Dir to be set, got %q", manifests[1].Dir)
+	}
+}
+
+func TestDiscover_InvalidManifest(t *testing.T) {
+	tests := map[string]string{
+		"malformed":   `{`,
+		"no id":       `{"kind":"cloud","command":"x"}`,
+		"no command":  `{"id":"x","kind":"cloud"}`,
+		"bad kind":    `{"id":"x","kind":"frontend","command":"x"}`,
+		"kind absent": `{"id":"x","command":"x"}`,
+	}
+	for name, manifest := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			writePlugin(t, root, "p", manifest, "")
+			_, err := Discover(filepath.Join(root, Dir))
+			if !errors.Is(err, ErrInvalidManifest) {
+				t.Fatalf("expected ErrInvalidManifest, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRegister_IdempotentForSameDirAndConflictsOtherwise(t *testing.T) {
+	root := t.TempDir()
+	dir := writePlugin(t, root, "acme", `{"id":"plugin-test-idempotent","kind":"cloud","command":"./plugin.sh"}`, cloudPluginScript)
+
+	if err := LoadProject(root); err != nil {
+		t.Fatalf("first load: %v", err)
+	}
+	if err := LoadProject(root); err != nil {
+		t.Fatalf("second load should be a no-op, got: %v", err)
+	}
+	if !cloud.Has("plugin-test-idempotent") {
+		t.Fatal("expected plugin to be registered")
+	}
+
+	other := Manifest{ID: "plugin-test-idempotent", Kind: KindCloud, Command: "./plugin.sh", Dir: dir + "-copy"}
+	if err := Register(other); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestRegister_ConflictsWithBuiltIn(t *testing.T) {
+	backend.Register(&stubBackend{id: "plugin-test-builtin"})
+
+	err := Register(Manifest{ID: "plugin-test-builtin", Kind: KindBackend, Command: "x", Dir: t.TempDir()})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestCloudProvider_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+	dir := writePlugin(t, root, "acme", `{"id":"plugin-test-cloud","kind":"cloud","command":"./plugin.sh"}`, cloudPluginScript)
+	if err := LoadProject(root); err != nil {
+		t.Fatalf("loading plugins: %v", err)
+	}
+
+	p, err := cloud.Get("plugin-test-cloud")
+	if err != nil {
+		t.Fatalf("getting provider: %v", err)
+	}
+	ctx := context.Background()
+
+	plan, err := p.Plan(ctx, cloud.PlanOptions{Config: map[string]any{"token_env": "ACME_TOKEN"}, Environment: "staging"})
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if len(plan.ToCreate) != 1 || plan.ToCreate[0].Name != "app-1" || plan.ToCreate[0].Region != "nyc1" {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+
+	hosts, err := p.Hosts(ctx, cloud.HostsOptions{Environment: "staging"})
+	if err != nil {
+		t.Fatalf("hosts: %v", err)
+	}
+	if len(hosts) != 1 || hosts[0].ID != "42" || hosts[0].PublicIP != "203.0.113.10" || hosts[0].Status != "active" {
+		t.Fatalf("unexpected hosts: %+v", hosts)
+	}
+
+	if err := p.Apply(ctx, cloud.ApplyOptions{Environment: "staging", Plan: plan}); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	req, err := os.ReadFile(filepath.Join(dir, "apply.json"))
+	if err != nil {
+		t.Fatalf("reading recorded request: %v", err)
+	}
+	for _, want := range []string{`"protocol":1`, `"environment":"staging"`, `"to_create":[{"name":"app-1"`} {
+		if !strings.Contains(string(req), want) {
+			t.Errorf("apply request missing %s: %s", want, req)
+		}
+	}
+}
+
+func TestClient_ErrorResponseIncludesMessage(t *testing.T) {
+	root := t.TempDir()
+	dir := writePlugin(t, root, "acme", `{"id":"plugin-test-error","kind":"backend","command":"./plugin.sh"}`, cloudPluginScript)
+	m, err := readManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewClient(*m).Call(context.Background(), "build_docker", nil, nil)
+	if !errors.Is(err, ErrPluginFailed) || !strings.Contains(err.Error(), "unsupported method") {
+		t.Fatalf("expected plugin failure with message, got %v", err)
+	}
+}
+
+func TestClient_NonJSONOutputIsProtocolError(t *testing.T) {
+	root := t.TempDir()
+	dir := writePlugin(t, root, "acme", `{"id":"plugin-test-garbage","kind":"cloud","command":"./plugin.sh"}`, "#!/bin/sh\ncat >/dev/null\necho not json\n")
+	m, err := readManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewClient(*m).Call(context.Background(), "hosts", nil, &[]wireHost{})
+	if !errors.Is(err, ErrProtocol) {
+		t.Fatalf("expected ErrProtocol, got %v", err)
+	}
+}
+
+func TestClient_NonZeroExitIncludesStderr(t *testing.T) {
+	root := t.TempDir()
+	dir := writePlugin(t, root, "acme", `{"id":"plugin-test-exit","kind":"cloud","command":"./plugin.sh"}`, "#!/bin/sh\ncat >/dev/null\necho 'token missing' >&2\nexit 3\n")
+	m, err := readManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewClient(*m).Call(context.Background(), "plan", nil, nil)
+	if !errors.Is(err, ErrPluginFailed) || !strings.Contains(err.Error(), "token missing") {
+		t.Fatalf("expected plugin failure with stderr, got %v", err)
+	}
+}
+
+// stubBackend is a minimal built-in backend used to exercise conflicts.
+type stubBackend struct{ id string }
+
+func (s *stubBackend) ID() string { return s.id }
+
+func (s *stubBackend) Dev(context.Context, backend.DevOptions) error { return nil }
+
+func (s *stubBackend) BuildDocker(context.Context, backend.BuildDockerOptions) (string, error) {
+	return "", nil
+}
+
+func (s *stubBackend) Plan(context.Context, backend.PlanOptions) (backend.ProviderPlan, error) {
+	return backend.ProviderPlan{}, nil
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"stagecraft/pkg/executil"
+)
+
+// Feature: PROVIDER_PLUGINS
+// Spec: spec/providers/plugins.md
+
+// ProtocolVersion is the plugin protocol version sent with every request.
+const ProtocolVersion = 1
+
+// ProtocolEnv is set in the plugin's environment to the protocol version.
+const ProtocolEnv = "STAGECRAFT_PLUGIN_PROTOCOL"
+
+// Request is the JSON document written to a plugin's stdin.
+type Request struct {
+	Protocol int    `json:"protocol"`
+	Method   string `json:"method"`
+	Params   any    `json:"params"`
+}
+
+// Response is the JSON document a plugin writes to stdout.
+type Response struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *ResponseError  `json:"error,omitempty"`
+}
+
+// ResponseError is a plugin-reported failure.
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// Client runs one plugin process per call.
+type Client struct {
+	manifest Manifest
+	runner   executil.Runner
+}
+
+// NewClient returns a Client for the given plugin.
+func NewClient(m Manifest) *Client { //nolint:gocritic // hugeParam: manifests are small and copied once
+	return &Client{manifest: m, runner: executil.NewRunner()}
+}
+
+// ID returns the plugin's provider ID.
+func (c *Client) ID() string {
+	return c.manifest.ID
+}
+
+// Call sends method and params to a fresh plugin process and decodes the
+// response result into result (which may be nil).
+func (c *Client) Call(ctx context.Context, method string, params, result any) error {
+	cmd, err := c.command(method, params)
+	if err != nil {
+		return err
+	}
+
+	res, runErr := c.runner.Run(ctx, cmd)
+	var stdout, stderr []byte
+	if res != nil {
+		stdout, stderr = res.Stdout, res.Stderr
+	}
+
+	var resp Response
+	if len(bytes.TrimSpace(stdout)) > 0 {
+		if err := json.Unmarshal(stdout, &resp); err != nil {
+			if runErr != nil {
+				return c.failure(method, runErr.Error(), stderr)
+			}
+			return fmt.Errorf("plugin %q: %w: %s: decoding response: %v", c.manifest.ID, ErrProtocol, method, err)
+		}
+	}
+
+	if resp.Error != nil {
+		return c.failure(method, resp.Error.Message, stderr)
+	}
+	if runErr != nil {
+		return c.failure(method, runErr.Error(), stderr)
+	}
+
+	if result == nil {
+		return nil
+	}
+	if len(resp.Result) == 0 {
+		return fmt.Errorf("plugin %q: %w: %s: response has no result", c.manifest.ID, ErrProtocol, method)
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("plugin %q: %w: %s: decoding result: %v", c.manifest.ID, ErrProtocol, method, err)
+	}
+	return nil
+}
+
+// Stream sends method and params to a fresh plugin process, passing its
+// stdout and stderr through to output. The exit status is the result; this
+// suits long-running methods such as backend "dev".
+func (c *Client) Stream(ctx context.Context, method string, params any, output io.Writer) error {
+	cmd, err := c.command(method, params)
+	if err != nil {
+		return err
+	}
+	if err := c.runner.RunStream(ctx, cmd, output); err != nil {
+		return c.failure(method, err.Error(), nil)
+	}
+	return nil
+}
+
+// command builds the process invocation for a request.
+func (c *Client) command(method string, params any) (executil.Command, error) {
+	body, err := json.Marshal(Request{Protocol: ProtocolVersion, Method: method, Params: params})
+	if err != nil {
+		return executil.Command{}, fmt.Errorf("plugin %q: encoding %s request: %w", c.manifest.ID, method, err)
+	}
+
+	cmd := executil.NewCommand(c.executable(), c.manifest.Args...)
+	cmd.Dir = c.manifest.Dir
+	cmd.Env = map[string]string{ProtocolEnv: fmt.Sprint(ProtocolVersion)}
+	cmd.Stdin = bytes.NewReader(body)
+	return cmd, nil
+}
+
+// executable resolves the manifest command: paths are relative to the plugin
+// directory, bare names are left for PATH lookup.
+func (c *Client) executable() string {
+	command := c.manifest.Command
+	if filepath.IsAbs(command) || !strings.ContainsRune(command, '/') {
+		return command
+	}
+	return filepath.Join(c.manifest.Dir, command)
+}
+
+func (c *Client) failure(method, message string, stderr []byte) error {
+	if detail := strings.TrimSpace(string(stderr)); detail != "" {
+		message += ": " + detail
+	}
+	return fmt.Errorf("plugin %q: %w: %s: %s", c.manifest.ID, ErrPluginFailed, method, message)
+}
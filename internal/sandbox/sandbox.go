@@ -0,0 +1,312 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package sandbox records the side effects a run would have instead of
+// performing them.
+//
+// A Transcript hands out stand-ins for the seams Stagecraft uses to touch
+// the outside world: an executil.Runner for shell commands, file write
+// functions, and an http.Client for API calls. Each stand-in records what
+// it was asked to do, attributed to the current host and phase, and
+// reports success without running, writing, or sending anything.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	hostexec "stagecraft/internal/exec"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: DEPLOY_EXPLAIN
+// Spec: spec/deploy/explain.md
+
+// LocalHost is the host label for effects that happen on the machine
+// running Stagecraft.
+const LocalHost = "local"
+
+// Kind classifies a recorded effect.
+type Kind string
+
+const (
+	// KindCommand is a shell command that would be executed.
+	KindCommand Kind = "command"
+	// KindFileWrite is a file that would be written.
+	KindFileWrite Kind = "file_write"
+	// KindAPICall is an HTTP request that would be sent.
+	KindAPICall Kind = "api_call"
+	// KindProviderStep is a step a provider reports it would perform
+	// through its own tooling.
+	KindProviderStep Kind = "provider_step"
+)
+
+// Effect is one would-be side effect.
+type Effect struct {
+	Seq   int    `json:"seq" yaml:"seq"`
+	Host  string `json:"host" yaml:"host"`
+	Phase string `json:"phase,omitempty" yaml:"phase,omitempty"`
+	Kind  Kind   `json:"kind" yaml:"kind"`
+
+	// Command and Dir are set for KindCommand.
+	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
+	Dir     string   `json:"dir,omitempty" yaml:"dir,omitempty"`
+
+	// Path and Bytes are set for KindFileWrite.
+	Path  string `json:"path,omitempty" yaml:"path,omitempty"`
+	Bytes int    `json:"bytes,omitempty" yaml:"bytes,omitempty"`
+
+	// Method and URL are set for KindAPICall. The URL is redacted to its
+	// scheme and host, since webhook paths and queries often carry tokens.
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	URL    string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Summary returns a one-line description of the effect.
+func (e *Effect) Summary() string {
+	switch e.Kind {
+	case KindCommand:
+		s := strings.Join(e.Command, " ")
+		if e.Dir != "" {
+			s += " (in " + e.Dir + ")"
+		}
+		return s
+	case KindFileWrite:
+		return fmt.Sprintf("%s (%d bytes)", e.Path, e.Bytes)
+	case KindAPICall:
+		return e.Method + " " + e.URL
+	default:
+		return e.Description
+	}
+}
+
+// Transcript accumulates effects in the order they were requested.
+// It is safe for concurrent use.
+type Transcript struct {
+	mu      sync.Mutex
+	host    string
+	phase   string
+	effects []Effect
+}
+
+// New returns an empty transcript scoped to LocalHost.
+func New() *Transcript {
+	return &Transcript{host: LocalHost}
+}
+
+// Enter attributes subsequent effects to host and phase.
+func (t *Transcript) Enter(host, phase string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if host == "" {
+		host = LocalHost
+	}
+	t.host, t.phase = host, phase
+}
+
+// Record appends e, filling in its sequence number and, when unset, the
+// current host and phase.
+func (t *Transcript) Record(e Effect) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e.Host == "" {
+		e.Host = t.host
+	}
+	if e.Phase == "" {
+		e.Phase = t.phase
+	}
+	e.Seq = len(t.effects) + 1
+	t.effects = append(t.effects, e)
+}
+
+// Effects returns a copy of the recorded effects.
+func (t *Transcript) Effects() []Effect {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Effect(nil), t.effects...)
+}
+
+// Hosts returns the hosts with recorded effects, LocalHost first and the
+// rest sorted.
+func (t *Transcript) Hosts() []string {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, e := range t.Effects() {
+		if !seen[e.Host] {
+			seen[e.Host] = true
+			hosts = append(hosts, e.Host)
+		}
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if (hosts[i] == LocalHost) != (hosts[j] == LocalHost) {
+			return hosts[i] == LocalHost
+		}
+		return hosts[i] < hosts[j]
+	})
+	return hosts
+}
+
+// ForHost returns the effects attributed to host, in order.
+func (t *Transcript) ForHost(host string) []Effect {
+	var out []Effect
+	for _, e := range t.Effects() {
+		if e.Host == host {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Render writes the transcript grouped by host.
+func (t *Transcript) Render(w io.Writer) error {
+	hosts := t.Hosts()
+	if len(hosts) == 0 {
+		_, err := fmt.Fprintln(w, "No side effects")
+		return err
+	}
+	for i, host := range hosts {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "Host %s:\n", host); err != nil {
+			return err
+		}
+		for _, e := range t.ForHost(host) {
+			if _, err := fmt.Fprintf(w, "  %3d  %-12s %-13s %s\n", e.Seq, "["+e.Phase+"]", e.Kind, e.Summary()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Runner returns an executil.Runner that records commands and reports
+// success with empty output. Commands run through ssh are attributed to
+// their destination host. Secrets are masked (see hostexec.Secrets).
+func (t *Transcript) Runner() executil.Runner {
+	return &runner{t: t}
+}
+
+type runner struct {
+	t *Transcript
+}
+
+func (r *runner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // matches executil.Runner
+	r.record(cmd)
+	return &executil.Result{}, nil
+}
+
+func (r *runner) RunStream(_ context.Context, cmd executil.Command, _ io.Writer) error { //nolint:gocritic // matches executil.Runner
+	r.record(cmd)
+	return nil
+}
+
+func (r *runner) record(cmd executil.Command) { //nolint:gocritic // hugeParam: matches executil.Runner
+	r.t.Record(Effect{
+		Host:    sshDestination(cmd),
+		Kind:    KindCommand,
+		Command: hostexec.Secrets.RedactArgs(append([]string{cmd.Name}, cmd.Args...)),
+		Dir:     cmd.Dir,
+	})
+}
+
+// sshOptionsWithValue are the ssh flags that consume the next argument.
+var sshOptionsWithValue = map[string]bool{
+	"-b": true, "-c": true, "-D": true, "-E": true, "-F": true, "-i": true, "-J": true,
+	"-L": true, "-l": true, "-m": true, "-O": true, "-o": true, "-p": true, "-R": true, "-S": true, "-W": true,
+}
+
+// sshDestination returns the destination of an ssh command, or "" for any
+// other command.
+func sshDestination(cmd executil.Command) string { //nolint:gocritic // hugeParam: matches executil.Runner
+	if cmd.Name != "ssh" {
+		return ""
+	}
+	for i := 0; i < len(cmd.Args); i++ {
+		arg := cmd.Args[i]
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+		if sshOptionsWithValue[arg] {
+			i++
+		}
+	}
+	return ""
+}
+
+// WriteFile records a file write of data to path. It has the signature of
+// os.WriteFile.
+func (t *Transcript) WriteFile(path string, data []byte, _ os.FileMode) error {
+	t.Record(Effect{Kind: KindFileWrite, Path: path, Bytes: len(data)})
+	return nil
+}
+
+// MkdirAll does nothing. Directory creation is implied by the file writes
+// that need it. It has the signature of os.MkdirAll.
+func (t *Transcript) MkdirAll(string, os.FileMode) error {
+	return nil
+}
+
+// HTTPClient returns a client whose requests are recorded and answered
+// with an empty 200 response.
+func (t *Transcript) HTTPClient() *http.Client {
+	return &http.Client{Transport: &transport{t: t}}
+}
+
+type transport struct {
+	t *Transcript
+}
+
+func (tr *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+	tr.t.Record(Effect{Kind: KindAPICall, Method: req.Method, URL: RedactURL(req.URL)})
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+// RedactURL returns u's scheme and host, with "/…" standing in for any
+// path or query.
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	s := u.Scheme + "://" + u.Host
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		s += "/…"
+	}
+	return s
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package scaffold provides project scaffolding helpers such as the
+// embedded config templates used by `stagecraft init --template`.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Feature: CLI_INIT_TEMPLATE
+// Spec: spec/scaffold/templates.md
+
+//go:embed templates/*.yml.tmpl
+var templatesFS embed.FS
+
+// ErrUnknownTemplate is returned when a template ID is not registered.
+var ErrUnknownTemplate = errors.New("unknown template")
+
+// Template describes an embedded project template.
+type Template struct {
+	// ID is the stable identifier used with --template.
+	ID string
+
+	// Description is a one-line summary of the topology.
+	Description string
+
+	// file is the embedded template path.
+	file string
+}
+
+// TemplateData is the data passed to a template when it is rendered.
+type TemplateData struct {
+	// ProjectName is written to project.name.
+	ProjectName string
+}
+
+// templates is the set of built-in templates, keyed by ID.
+var templates = map[string]Template{
+	"api-only": {
+		ID:          "api-only",
+		Description: "single containerised API with a database and no frontend",
+		file:        "templates/api-only.yml.tmpl",
+	},
+	"encore-ts-vite": {
+		ID:          "encore-ts-vite",
+		Description: "monorepo with an Encore.ts backend and a Vite frontend",
+		file:        "templates/encore-ts-vite.yml.tmpl",
+	},
+	"go-next": {
+		ID:          "go-next",
+		Description: "separate Go API and Next.js frontend directories",
+		file:        "templates/go-next.yml.tmpl",
+	},
+	"static-site": {
+		ID:          "static-site",
+		Description: "static frontend without an application backend",
+		file:        "templates/static-site.yml.tmpl",
+	},
+}
+
+// Templates returns all built-in templates sorted by ID.
+func Templates() []Template {
+	result := make([]Template, 0, len(templates))
+	for _, t := range templates {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// TemplateIDs returns the IDs of all built-in templates in sorted order.
+func TemplateIDs() []string {
+	all := Templates()
+	ids := make([]string, len(all))
+	for i, t := range all {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// Lookup returns the template with the given ID.
+//
+// It returns an error wrapping ErrUnknownTemplate if the ID is not registered.
+func Lookup(id string) (Template, error) {
+	t, ok := templates[id]
+	if !ok {
+		return Template{}, fmt.Errorf("%w %q; available templates: %s", ErrUnknownTemplate, id, strings.Join(TemplateIDs(), ", "))
+	}
+	return t, nil
+}
+
+// Render renders the template with the given ID into Stagecraft config YAML.
+//
+// The output is deterministic for a given ID and data.
+func Render(id string, data TemplateData) ([]byte, error) {
+	t, err := Lookup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(data.ProjectName) == "" {
+		return nil, fmt.Errorf("rendering template %q: project name must be non-empty", id)
+	}
+
+	raw, err := templatesFS.ReadFile(t.file)
+	if err != nil {
+		return nil, fmt.Errorf("reading template %q: %w", id, err)
+	}
+
+	tmpl, err := template.New(id).Option("missingkey=error").Parse(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template %q: %w", id, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering template %q: %w", id, err)
+	}
+
+	return buf.Bytes(), nil
+}
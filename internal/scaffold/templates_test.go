@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stagecraft/pkg/config"
+)
+
+// Feature: CLI_INIT_TEMPLATE
+// Spec: spec/scaffold/templates.md
+
+func TestTemplateIDs_Sorted(t *testing.T) {
+	got := TemplateIDs()
+	want := []string{"api-only", "encore-ts-vite", "go-next", "static-site"}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d templates, got %d (%v)", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("TemplateIDs()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRender_AllTemplatesProduceValidConfig(t *testing.T) {
+	for _, tmpl := range Templates() {
+		t.Run(tmpl.ID, func(t *testing.T) {
+			data, err := Render(tmpl.ID, TemplateData{ProjectName: "demo"})
+			if err != nil {
+				t.Fatalf("Render(%q) error = %v", tmpl.ID, err)
+			}
+
+			path := filepath.Join(t.TempDir(), "stagecraft.yml")
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatalf("writing rendered config: %v", err)
+			}
+
+			cfg, err := config.Load(path)
+			if err != nil {
+				t.Fatalf("rendered template %q is not a valid config: %v", tmpl.ID, err)
+			}
+
+			if cfg.Version != config.CurrentSchemaVersion {
+				t.Errorf("expected schema version %d, got %d", config.CurrentSchemaVersion, cfg.Version)
+			}
+			if cfg.Project.Name != "demo" {
+				t.Errorf("expected project name %q, got %q", "demo", cfg.Project.Name)
+			}
+			if _, ok := cfg.Environments["dev"]; !ok {
+				t.Errorf("expected template %q to define a dev environment", tmpl.ID)
+			}
+		})
+	}
+}
+
+func TestRender_Deterministic(t *testing.T) {
+	first, err := Render("go-next", TemplateData{ProjectName: "demo"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	second, err := Render("go-next", TemplateData{ProjectName: "demo"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatalf("expected identical output across renders")
+	}
+}
+
+func TestRender_UnknownTemplate(t *testing.T) {
+	_, err := Render("does-not-exist", TemplateData{ProjectName: "demo"})
+	if !errors.Is(err, ErrUnknownTemplate) {
+		t.Fatalf("expected ErrUnknownTemplate, got %v", err)
+	}
+}
+
+func TestRender_EmptyProjectName(t *testing.T) {
+	if _, err := Render("api-only", TemplateData{}); err == nil {
+		t.Fatalf("expected error for empty project name")
+	}
+}
@@ -0,0 +1,379 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package selfupdate replaces the running Stagecraft binary with a newer
+// release from the release feed, verifying its checksum and its cosign
+// signature before swapping it in. Stable releases are never installed
+// without a signature check.
+package selfupdate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"stagecraft/internal/buildinfo"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CLI_SELF_UPDATE
+// Spec: spec/commands/self-update.md
+
+// Release channels.
+const (
+	ChannelStable = "stable"
+	ChannelEdge   = "edge"
+)
+
+// Channels lists the release channels in order of stability.
+var Channels = []string{ChannelStable, ChannelEdge}
+
+// DefaultFeedURL is where releases publish the feed.
+const DefaultFeedURL = "https://raw.githubusercontent.com/bartekus/stagecraft/main/release/feed.json"
+
+// Environment variables overriding the feed URL and the public key.
+const (
+	FeedURLEnv   = "STAGECRAFT_UPDATE_FEED"
+	PublicKeyEnv = "STAGECRAFT_UPDATE_PUBLIC_KEY"
+)
+
+// releasePublicKey is the cosign key reference release builds verify
+// updates against when no key is configured, set at build time:
+//
+//	go build -ldflags "-X stagecraft/internal/selfupdate.releasePublicKey=https://example.com/cosign.pub" ./cmd/stagecraft
+var releasePublicKey = ""
+
+// FeedSchemaVersion is the feed format this build reads.
+const FeedSchemaVersion = 1
+
+// maxBinarySize bounds a download; Stagecraft binaries are far smaller.
+const maxBinarySize = 512 << 20
+
+// Feed is the release feed: the latest release of every channel.
+type Feed struct {
+	SchemaVersion int                `json:"schema_version"`
+	Channels      map[string]Release `json:"channels"`
+}
+
+// Release is one Stagecraft release and its binaries.
+type Release struct {
+	Version string `json:"version"`
+
+	// Assets maps "<os>/<arch>", e.g. "linux/amd64", to that platform's binary.
+	Assets map[string]Asset `json:"assets"`
+}
+
+// Asset is a release binary. URL and Signature may be relative to the feed.
+type Asset struct {
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+
+	// Signature is the URL of the binary's cosign signature, if signed.
+	Signature string `json:"signature,omitempty"`
+}
+
+// Updater checks the feed and installs releases.
+type Updater struct {
+	// FeedURL defaults to DefaultFeedURL.
+	FeedURL string
+
+	// Client defaults to a client with a 5 minute timeout.
+	Client *http.Client
+
+	// Runner runs cosign; only used when a public key is in effect.
+	Runner executil.Runner
+
+	// PublicKey is a cosign key reference installs require a signature to
+	// verify against. It defaults to the key built into release binaries.
+	// Stable installs fail without a key; edge installs without one only
+	// check the checksum.
+	PublicKey string
+
+	// Platform defaults to runtime.GOOS/runtime.GOARCH.
+	Platform string
+}
+
+// Update is a release resolved for this platform.
+type Update struct {
+	Channel string
+	Current string
+	Version string
+	Asset   Asset
+
+	// Newer reports whether Version is newer than Current.
+	Newer bool
+}
+
+// Check fetches the feed and resolves the latest release of channel for
+// the updater's platform.
+func (u *Updater) Check(ctx context.Context, channel string) (*Update, error) {
+	if !slices.Contains(Channels, channel) {
+		return nil, fmt.Errorf("unknown channel %q (want one of %s)", channel, strings.Join(Channels, ", "))
+	}
+
+	feedURL := u.feedURL()
+	data, err := u.get(ctx, feedURL, 1<<20)
+	if err != nil {
+		return nil, fmt.Errorf("fetching release feed: %w", err)
+	}
+
+	var feed Feed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("parsing release feed %s: %w", feedURL, err)
+	}
+	if feed.SchemaVersion != FeedSchemaVersion {
+		return nil, fmt.Errorf("release feed %s has schema_version %d, this build reads %d", feedURL, feed.SchemaVersion, FeedSchemaVersion)
+	}
+
+	release, ok := feed.Channels[channel]
+	if !ok || release.Version == "" {
+		return nil, fmt.Errorf("release feed has no %s release", channel)
+	}
+	if !buildinfo.Valid(release.Version) {
+		return nil, fmt.Errorf("%s release has invalid version %q", channel, release.Version)
+	}
+
+	platform := u.platform()
+	asset, ok := release.Assets[platform]
+	if !ok {
+		return nil, fmt.Errorf("%s release %s has no binary for %s", channel, release.Version, platform)
+	}
+	if asset.URL, err = resolveURL(feedURL, asset.URL); err != nil {
+		return nil, fmt.Errorf("%s release %s: %w", channel, release.Version, err)
+	}
+	if asset.Signature != "" {
+		if asset.Signature, err = resolveURL(feedURL, asset.Signature); err != nil {
+			return nil, fmt.Errorf("%s release %s: %w", channel, release.Version, err)
+		}
+	}
+	if _, err := hex.DecodeString(asset.SHA256); err != nil || len(asset.SHA256) != sha256.Size*2 {
+		return nil, fmt.Errorf("%s release %s has no valid sha256 for %s", channel, release.Version, platform)
+	}
+
+	current := buildinfo.Version()
+	return &Update{
+		Channel: channel,
+		Current: current,
+		Version: release.Version,
+		Asset:   asset,
+		Newer:   buildinfo.Compare(release.Version, current) > 0,
+	}, nil
+}
+
+// Install downloads the update's binary, verifies it, and atomically
+// replaces the file at target with it. target is left untouched when any
+// step fails.
+func (u *Updater) Install(ctx context.Context, update *Update, target string) error {
+	if !u.VerifiesSignature() && update.Channel == ChannelStable {
+		return fmt.Errorf("stable releases must be signature-verified, but no public key is configured (set --public-key or %s)", PublicKeyEnv)
+	}
+
+	// Resolve symlinks so the rename replaces the binary, not the link.
+	resolved, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", target, err)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return err
+	}
+
+	// The temp file lives next to the target so the rename stays on one
+	// filesystem and is atomic.
+	dir := filepath.Dir(resolved)
+	tmp, err := os.CreateTemp(dir, ".stagecraft-update-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file in %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	sum, err := u.download(ctx, update.Asset.URL, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("downloading %s: %w", update.Asset.URL, err)
+	}
+	if !strings.EqualFold(sum, update.Asset.SHA256) {
+		return fmt.Errorf("checksum mismatch for %s: got sha256 %s, feed lists %s", update.Asset.URL, sum, update.Asset.SHA256)
+	}
+
+	if u.VerifiesSignature() {
+		if err := u.verifySignature(ctx, update, tmpPath); err != nil {
+			return err
+		}
+	}
+
+	// #nosec G302 -- the binary must stay executable for everyone who could run it before
+	if err := os.Chmod(tmpPath, info.Mode().Perm()|0o111); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, resolved); err != nil {
+		return fmt.Errorf("replacing %s: %w", resolved, err)
+	}
+	return nil
+}
+
+// verifySignature checks the downloaded binary at path against the
+// update's signature with cosign verify-blob.
+func (u *Updater) verifySignature(ctx context.Context, update *Update, path string) error {
+	if update.Asset.Signature == "" {
+		return fmt.Errorf("release %s is not signed, but a public key is configured", update.Version)
+	}
+
+	sig, err := u.get(ctx, update.Asset.Signature, 1<<20)
+	if err != nil {
+		return fmt.Errorf("downloading signature: %w", err)
+	}
+	sigPath := path + ".sig"
+	if err := os.WriteFile(sigPath, sig, 0o600); err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(sigPath) }()
+
+	runner := u.Runner
+	if runner == nil {
+		runner = executil.NewRunner()
+	}
+	cmd := executil.NewCommand("cosign", "verify-blob", "--key", u.publicKey(), "--signature", sigPath, path)
+	if _, err := runner.Run(ctx, cmd); err != nil {
+		return fmt.Errorf("cosign verify-blob %s: %w", update.Asset.URL, err)
+	}
+	return nil
+}
+
+// download streams rawURL into w and returns the sha256 hex digest of
+// what was written.
+func (u *Updater) download(ctx context.Context, rawURL string, w io.Writer) (string, error) {
+	resp, err := u.do(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), io.LimitReader(resp.Body, maxBinarySize+1))
+	if err != nil {
+		return "", err
+	}
+	if n > maxBinarySize {
+		return "", fmt.Errorf("binary is larger than %d bytes", maxBinarySize)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// get returns the body of rawURL, failing when it exceeds limit bytes.
+func (u *Updater) get(ctx context.Context, rawURL string, limit int64) ([]byte, error) {
+	resp, err := u.do(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", rawURL, limit)
+	}
+	return data, nil
+}
+
+func (u *Updater) do(ctx context.Context, rawURL string) (*http.Response, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "https" {
+		return nil, fmt.Errorf("refusing to fetch %s over %s, only https is allowed", rawURL, parsed.Scheme)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", rawURL, resp.Status)
+	}
+	return resp, nil
+}
+
+// VerifiesSignature reports whether Install checks cosign signatures,
+// i.e. whether a public key is configured or built in.
+func (u *Updater) VerifiesSignature() bool {
+	return u.publicKey() != ""
+}
+
+func (u *Updater) publicKey() string {
+	if u.PublicKey != "" {
+		return u.PublicKey
+	}
+	return releasePublicKey
+}
+
+func (u *Updater) feedURL() string {
+	if u.FeedURL != "" {
+		return u.FeedURL
+	}
+	return DefaultFeedURL
+}
+
+func (u *Updater) client() *http.Client {
+	if u.Client != nil {
+		return u.Client
+	}
+	return &http.Client{Timeout: 5 * time.Minute}
+}
+
+func (u *Updater) platform() string {
+	if u.Platform != "" {
+		return u.Platform
+	}
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// resolveURL resolves ref against the feed URL, so feeds can list assets
+// relative to themselves.
+func resolveURL(feedURL, ref string) (string, error) {
+	if ref == "" {
+		return "", errors.New("asset has no url")
+	}
+	base, err := url.Parse(feedURL)
+	if err != nil {
+		return "", err
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid asset url %q: %w", ref, err)
+	}
+	return base.ResolveReference(r).String(), nil
+}
@@ -0,0 +1,330 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package selfupdate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stagecraft/internal/buildinfo"
+	"stagecraft/pkg/executil"
+)
+
+// Feature: CLI_SELF_UPDATE
+// Spec: spec/commands/self-update.md
+
+const testPlatform = "linux/amd64"
+
+type recordingRunner struct {
+	commands []executil.Command
+	fail     bool
+}
+
+func (r *recordingRunner) Run(_ context.Context, cmd executil.Command) (*executil.Result, error) { //nolint:gocritic // Runner interface
+	r.commands = append(r.commands, cmd)
+	if r.fail {
+		return &executil.Result{ExitCode: 1}, errors.New("invalid signature")
+	}
+	return &executil.Result{}, nil
+}
+
+func (r *recordingRunner) RunStream(context.Context, executil.Command, io.Writer) error {
+	return nil
+}
+
+// newFeedServer serves a feed with a stable and an edge release of binary,
+// listing assets relative to the feed, and the files themselves.
+func newFeedServer(t *testing.T, binary []byte, mutate func(*Feed)) (*httptest.Server, *Updater) {
+	t.Helper()
+
+	sum := sha256.Sum256(binary)
+	feed := Feed{
+		SchemaVersion: FeedSchemaVersion,
+		Channels: map[string]Release{
+			ChannelStable: {Version: "1.4.0", Assets: map[string]Asset{
+				testPlatform: {URL: "stagecraft-linux-amd64", SHA256: hex.EncodeToString(sum[:]), Signature: "stagecraft-linux-amd64.sig"},
+			}},
+			ChannelEdge: {Version: "1.5.0-rc.1", Assets: map[string]Asset{
+				testPlatform: {URL: "edge/stagecraft-linux-amd64", SHA256: hex.EncodeToString(sum[:])},
+			}},
+		},
+	}
+	if mutate != nil {
+		mutate(&feed)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/release/feed.json", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(feed)
+	})
+	serveBinary := func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(binary) }
+	mux.HandleFunc("/release/stagecraft-linux-amd64", serveBinary)
+	mux.HandleFunc("/release/edge/stagecraft-linux-amd64", serveBinary)
+	mux.HandleFunc("/release/stagecraft-linux-amd64.sig", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("signature"))
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv, &Updater{
+		FeedURL:  srv.URL + "/release/feed.json",
+		Client:   srv.Client(),
+		Platform: testPlatform,
+	}
+}
+
+// writeTarget writes an executable standing in for the running binary.
+func writeTarget(t *testing.T) string {
+	t.Helper()
+	target := filepath.Join(t.TempDir(), "stagecraft")
+	// #nosec G306 -- test stand-in for an executable
+	if err := os.WriteFile(target, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return target
+}
+
+func TestCheck(t *testing.T) {
+	t.Setenv(buildinfo.VersionEnv, "1.4.0")
+	srv, u := newFeedServer(t, []byte("new"), nil)
+
+	update, err := u.Check(context.Background(), ChannelStable)
+	if err != nil {
+		t.Fatalf("Check(stable): %v", err)
+	}
+	if update.Version != "1.4.0" || update.Current != "1.4.0" || update.Newer {
+		t.Errorf("stable update = %+v, want 1.4.0 and not newer", update)
+	}
+	if update.Asset.URL != srv.URL+"/release/stagecraft-linux-amd64" || update.Asset.Signature != srv.URL+"/release/stagecraft-linux-amd64.sig" {
+		t.Errorf("asset URLs not resolved against the feed: %+v", update.Asset)
+	}
+
+	update, err = u.Check(context.Background(), ChannelEdge)
+	if err != nil {
+		t.Fatalf("Check(edge): %v", err)
+	}
+	if update.Version != "1.5.0-rc.1" || !update.Newer {
+		t.Errorf("edge update = %+v, want 1.5.0-rc.1 and newer", update)
+	}
+}
+
+func TestCheck_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel string
+		mutate  func(*Feed)
+		want    string
+	}{
+		{name: "unknown channel", channel: "nightly", want: `unknown channel "nightly"`},
+		{name: "schema", channel: ChannelStable, mutate: func(f *Feed) { f.SchemaVersion = 2 }, want: "schema_version 2"},
+		{name: "missing channel", channel: ChannelEdge, mutate: func(f *Feed) { delete(f.Channels, ChannelEdge) }, want: "no edge release"},
+		{name: "platform", channel: ChannelStable, mutate: func(f *Feed) {
+			f.Channels[ChannelStable].Assets["darwin/arm64"] = f.Channels[ChannelStable].Assets[testPlatform]
+			delete(f.Channels[ChannelStable].Assets, testPlatform)
+		}, want: "no binary for linux/amd64"},
+		{name: "checksum", channel: ChannelEdge, mutate: func(f *Feed) {
+			f.Channels[ChannelEdge].Assets[testPlatform] = Asset{URL: "x", SHA256: "abc"}
+		}, want: "no valid sha256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, u := newFeedServer(t, []byte("new"), tt.mutate)
+			_, err := u.Check(context.Background(), tt.channel)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Check() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck_RequiresHTTPS(t *testing.T) {
+	u := &Updater{FeedURL: "http://example.com/feed.json"}
+	_, err := u.Check(context.Background(), ChannelStable)
+	if err == nil || !strings.Contains(err.Error(), "only https is allowed") {
+		t.Errorf("Check() error = %v, want https refusal", err)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	_, u := newFeedServer(t, []byte("new"), nil)
+	update, err := u.Check(context.Background(), ChannelEdge)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	target := writeTarget(t)
+	if err := u.Install(context.Background(), update, target); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil || string(data) != "new" {
+		t.Errorf("target = %q, %v; want the new binary", data, err)
+	}
+	info, _ := os.Stat(target)
+	if info.Mode().Perm()&0o111 == 0 {
+		t.Errorf("target mode = %v, want executable", info.Mode())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(target))
+	if len(entries) != 1 {
+		t.Errorf("leftover files next to the target: %v", entries)
+	}
+}
+
+func TestInstall_FollowsSymlink(t *testing.T) {
+	_, u := newFeedServer(t, []byte("new"), nil)
+	update, err := u.Check(context.Background(), ChannelEdge)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	target := writeTarget(t)
+	link := filepath.Join(t.TempDir(), "stagecraft")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+	if err := u.Install(context.Background(), update, link); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+
+	if data, _ := os.ReadFile(target); string(data) != "new" {
+		t.Errorf("link target = %q, want the new binary", data)
+	}
+	if fi, err := os.Lstat(link); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("link was replaced instead of its target")
+	}
+}
+
+func TestInstall_ChecksumMismatchKeepsTarget(t *testing.T) {
+	_, u := newFeedServer(t, []byte("tampered"), func(f *Feed) {
+		sum := sha256.Sum256([]byte("new"))
+		f.Channels[ChannelEdge].Assets[testPlatform] = Asset{URL: "edge/stagecraft-linux-amd64", SHA256: hex.EncodeToString(sum[:])}
+	})
+	update, err := u.Check(context.Background(), ChannelEdge)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	target := writeTarget(t)
+	err = u.Install(context.Background(), update, target)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Install() error = %v, want checksum mismatch", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "old" {
+		t.Errorf("target = %q, want it untouched", data)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(target))
+	if len(entries) != 1 {
+		t.Errorf("leftover files next to the target: %v", entries)
+	}
+}
+
+func TestInstall_Signature(t *testing.T) {
+	_, u := newFeedServer(t, []byte("new"), nil)
+	runner := &recordingRunner{}
+	u.Runner = runner
+	u.PublicKey = "cosign.pub"
+
+	update, err := u.Check(context.Background(), ChannelStable)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	target := writeTarget(t)
+	if err := u.Install(context.Background(), update, target); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+
+	if len(runner.commands) != 1 {
+		t.Fatalf("commands = %+v, want one cosign call", runner.commands)
+	}
+	args := strings.Join(runner.commands[0].Args, " ")
+	if runner.commands[0].Name != "cosign" || !strings.HasPrefix(args, "verify-blob --key cosign.pub --signature ") {
+		t.Errorf("command = %s %s", runner.commands[0].Name, args)
+	}
+}
+
+func TestInstall_SignatureFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel string
+		fail    bool
+		want    string
+	}{
+		{name: "unsigned release", channel: ChannelEdge, want: "is not signed"},
+		{name: "bad signature", channel: ChannelStable, fail: true, want: "cosign verify-blob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, u := newFeedServer(t, []byte("new"), nil)
+			u.Runner = &recordingRunner{fail: tt.fail}
+			u.PublicKey = "cosign.pub"
+
+			update, err := u.Check(context.Background(), tt.channel)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			target := writeTarget(t)
+			err = u.Install(context.Background(), update, target)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Install() error = %v, want %q", err, tt.want)
+			}
+			if data, _ := os.ReadFile(target); string(data) != "old" {
+				t.Errorf("target = %q, want it untouched", data)
+			}
+		})
+	}
+}
+
+func TestInstall_StableRequiresPublicKey(t *testing.T) {
+	_, u := newFeedServer(t, []byte("new"), nil)
+	runner := &recordingRunner{}
+	u.Runner = runner
+
+	update, err := u.Check(context.Background(), ChannelStable)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	target := writeTarget(t)
+	err = u.Install(context.Background(), update, target)
+	if err == nil || !strings.Contains(err.Error(), "no public key is configured") {
+		t.Fatalf("Install() error = %v, want missing public key", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "old" {
+		t.Errorf("target = %q, want it untouched", data)
+	}
+
+	orig := releasePublicKey
+	releasePublicKey = "https://example.com/cosign.pub"
+	t.Cleanup(func() { releasePublicKey = orig })
+
+	if err := u.Install(context.Background(), update, target); err != nil {
+		t.Fatalf("Install with the release key: %v", err)
+	}
+	if len(runner.commands) != 1 || !strings.HasPrefix(strings.Join(runner.commands[0].Args, " "), "verify-blob --key https://example.com/cosign.pub ") {
+		t.Errorf("commands = %+v, want cosign with the release key", runner.commands)
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package telemetry wires OpenTelemetry tracing for Stagecraft.
+//
+// Tracing is opt-in: unless an OTLP endpoint is configured through the
+// standard OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT
+// environment variables, Setup installs nothing and every span is a no-op.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Feature: CORE_TRACING
+// Spec: spec/core/tracing.md
+
+// instrumentationName identifies Stagecraft's tracer.
+const instrumentationName = "stagecraft"
+
+// Attribute keys attached to Stagecraft spans.
+const (
+	AttrEnvironment = attribute.Key("stagecraft.environment")
+	AttrReleaseID   = attribute.Key("stagecraft.release_id")
+	AttrPhase       = attribute.Key("stagecraft.phase")
+	AttrVersion     = attribute.Key("stagecraft.version")
+	AttrProvider    = attribute.Key("stagecraft.provider")
+	AttrPlanID      = attribute.Key("stagecraft.plan_id")
+	AttrHost        = attribute.Key("stagecraft.host")
+	AttrStepID      = attribute.Key("stagecraft.step_id")
+	AttrAction      = attribute.Key("stagecraft.action")
+	AttrStepStatus  = attribute.Key("stagecraft.step_status")
+	AttrAttempts    = attribute.Key("stagecraft.attempts")
+)
+
+// Enabled reports whether tracing export is configured via the environment.
+// OTEL_SDK_DISABLED=true turns tracing off even when an endpoint is set.
+func Enabled() bool {
+	if disabled, err := strconv.ParseBool(os.Getenv("OTEL_SDK_DISABLED")); err == nil && disabled {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup installs an OTLP/HTTP tracer provider when Enabled reports true and
+// returns a shutdown function that flushes pending spans. When tracing is not
+// configured it installs nothing and the returned shutdown is a no-op.
+//
+// Exporter settings (endpoint, headers, TLS, timeout) are read by the OTLP
+// exporter from the standard OTEL_EXPORTER_OTLP_* variables, and
+// OTEL_SERVICE_NAME / OTEL_RESOURCE_ATTRIBUTES override the resource.
+func Setup(ctx context.Context, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !Enabled() {
+		return noop, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return noop, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", "stagecraft"),
+			attribute.String("service.version", version),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("building trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the Stagecraft tracer from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan starts a span named name as a child of any span in ctx.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records err (if any) on span, marks the span status, and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
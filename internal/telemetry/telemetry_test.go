@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Feature: CORE_TRACING
+// Spec: spec/core/tracing.md
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		traces   string
+		disabled string
+		want     bool
+	}{
+		{name: "unconfigured", want: false},
+		{name: "generic endpoint", endpoint: "http://localhost:4318", want: true},
+		{name: "traces endpoint", traces: "http://localhost:4318/v1/traces", want: true},
+		{name: "sdk disabled", endpoint: "http://localhost:4318", disabled: "true", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.endpoint)
+			t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", tt.traces)
+			t.Setenv("OTEL_SDK_DISABLED", tt.disabled)
+
+			if got := Enabled(); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetup_NoopWhenUnconfigured(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	before := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+
+	if otel.GetTracerProvider() != before {
+		t.Fatalf("expected Setup to leave the global tracer provider untouched")
+	}
+
+	// Spans from the default provider are non-recording no-ops.
+	_, span := StartSpan(context.Background(), "noop")
+	if span.IsRecording() {
+		t.Errorf("expected no-op span when tracing is not configured")
+	}
+	EndSpan(span, nil)
+}
+
+func TestStartSpan_RecordsStatus(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	ctx, parent := StartSpan(context.Background(), "deploy", AttrEnvironment.String("staging"))
+	_, child := StartSpan(ctx, "phase build", AttrPhase.String("build"))
+	EndSpan(child, errors.New("boom"))
+	EndSpan(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	phase, deploy := spans[0], spans[1]
+	if phase.Parent().SpanID() != deploy.SpanContext().SpanID() {
+		t.Errorf("expected phase span to be a child of the deploy span")
+	}
+	if phase.Status().Code != codes.Error || phase.Status().Description != "boom" {
+		t.Errorf("expected error status on failed span, got %+v", phase.Status())
+	}
+	if len(phase.Events()) == 0 {
+		t.Errorf("expected error to be recorded as a span event")
+	}
+	if deploy.Status().Code != codes.Ok {
+		t.Errorf("expected ok status on successful span, got %+v", deploy.Status())
+	}
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+// Package templates renders user-provided config file templates, such as
+// nginx.conf.tmpl, for deploy environments.
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/template"
+)
+
+// Feature: DEPLOY_TEMPLATES
+// Spec: spec/deploy/templates.md
+
+// Data is what a template can reference.
+type Data struct {
+	Project     string
+	Environment string
+	Service     string
+
+	// Image is the image the service runs in this deploy.
+	Image string
+
+	// Env is the service's environment as written to the compose file,
+	// including env_file variables.
+	Env map[string]string
+
+	// Vars holds templates[].vars overlaid with the environment's
+	// template_vars.
+	Vars map[string]string
+}
+
+// funcs are the helpers available to templates besides the text/template
+// builtins.
+var funcs = template.FuncMap{
+	// default returns value, or def when value is empty:
+	// {{ index .Vars "workers" | default "4" }}
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+}
+
+// Render executes src with data. name identifies the template in errors.
+// Referencing a missing map key, e.g. a typo in .Env.DATABSE_URL, is an
+// error; use index and default for optional values.
+func Render(name string, src []byte, data Data) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderFile reads the template at path and renders it with data.
+func RenderFile(path string, data Data) ([]byte, error) {
+	// #nosec G304 -- path is a template source named in stagecraft.yml
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+	return Render(path, src, data)
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: DEPLOY_TEMPLATES
+// Spec: spec/deploy/templates.md
+
+func testData() Data {
+	return Data{
+		Project:     "shop",
+		Environment: "staging",
+		Service:     "proxy",
+		Image:       "nginx:1.27-alpine",
+		Env:         map[string]string{"UPSTREAM": "api:4000"},
+		Vars:        map[string]string{"server_name": "staging.example.com"},
+	}
+}
+
+func TestRender(t *testing.T) {
+	src := `# {{ .Project }}/{{ .Environment }}/{{ .Service }} ({{ .Image }})
+server_name {{ .Vars.server_name }};
+proxy_pass http://{{ .Env.UPSTREAM }};
+worker_processes {{ index .Vars "workers" | default "4" }};
+{{- range $k, $v := .Env }}
+# {{ $k }}={{ $v }}
+{{- end }}
+`
+	got, err := Render("nginx.conf.tmpl", []byte(src), testData())
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	want := `# shop/staging/proxy (nginx:1.27-alpine)
+server_name staging.example.com;
+proxy_pass http://api:4000;
+worker_processes 4;
+# UPSTREAM=api:4000
+`
+	if string(got) != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{name: "missing key", src: "{{ .Env.DATABSE_URL }}", wantErr: `map has no entry for key "DATABSE_URL"`},
+		{name: "unknown field", src: "{{ .Region }}", wantErr: "can't evaluate field Region"},
+		{name: "syntax", src: "{{ .Vars.x ", wantErr: "parsing template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render("app.conf.tmpl", []byte(tt.src), testData())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Render() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.conf.tmpl")
+	if err := os.WriteFile(path, []byte("env={{ .Environment }}\n"), 0o600); err != nil {
+		t.Fatalf("writing template: %v", err)
+	}
+
+	got, err := RenderFile(path, testData())
+	if err != nil {
+		t.Fatalf("RenderFile: %v", err)
+	}
+	if string(got) != "env=staging\n" {
+		t.Errorf("RenderFile() = %q", got)
+	}
+
+	if _, err := RenderFile(filepath.Join(t.TempDir(), "missing.tmpl"), testData()); err == nil {
+		t.Error("RenderFile(missing) = nil error, want error")
+	}
+}
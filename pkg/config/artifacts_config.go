@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Feature: DEPLOY_BUNDLE
+// Spec: spec/deploy/bundle.md
+
+// S3StorePrefix marks an artifacts store in S3-compatible object storage,
+// e.g. "s3://my-bucket/stagecraft".
+const S3StorePrefix = "s3://"
+
+// ArtifactsConfig says where deploy bundles are stored.
+type ArtifactsConfig struct {
+	// Store is a directory (relative to the working directory) or an
+	// "s3://bucket/prefix" URL. Defaults to .stagecraft/artifacts.
+	Store string `yaml:"store,omitempty"`
+
+	// Endpoint overrides the S3 endpoint for S3-compatible services such
+	// as MinIO or DigitalOcean Spaces.
+	Endpoint string `yaml:"endpoint,omitempty"`
+}
+
+func validateArtifacts(artifacts *ArtifactsConfig) error {
+	if artifacts == nil {
+		return nil
+	}
+
+	bucket, isS3 := strings.CutPrefix(artifacts.Store, S3StorePrefix)
+	if isS3 && strings.Trim(bucket, "/") == "" {
+		return fmt.Errorf("config: artifacts.store %q is missing the bucket name", artifacts.Store)
+	}
+	if artifacts.Endpoint != "" && !isS3 {
+		return fmt.Errorf("config: artifacts.endpoint requires an %s store", S3StorePrefix)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: DEPLOY_BUNDLE
+// Spec: spec/deploy/bundle.md
+
+func loadArtifactsConfig(t *testing.T, artifacts string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+environments:
+  prod:
+    driver: "digitalocean"
+artifacts:
+` + artifacts)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoad_Artifacts_Valid(t *testing.T) {
+	cfg, err := loadArtifactsConfig(t, `
+  store: s3://releases/stagecraft
+  endpoint: https://fra1.digitaloceanspaces.com
+`)
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	artifacts := cfg.Artifacts
+	if artifacts == nil || artifacts.Store != "s3://releases/stagecraft" || artifacts.Endpoint != "https://fra1.digitaloceanspaces.com" {
+		t.Fatalf("unexpected artifacts config: %+v", artifacts)
+	}
+}
+
+func TestLoad_Artifacts_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		artifacts string
+		wantErr   string
+	}{
+		{
+			name: "s3 without bucket",
+			artifacts: `
+  store: s3://
+`,
+			wantErr: `artifacts.store "s3://" is missing the bucket name`,
+		},
+		{
+			name: "endpoint for a directory store",
+			artifacts: `
+  store: build/bundles
+  endpoint: https://minio.internal:9000
+`,
+			wantErr: "artifacts.endpoint requires an s3:// store",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadArtifactsConfig(t, tt.artifacts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	backendproviders "stagecraft/pkg/providers/backend"
+)
+
+// Feature: CORE_BACKEND_SERVICES
+// Spec: spec/core/backend-services.md
+
+// DefaultBackendService is the service name used when backend is declared
+// with the single-service provider/providers shorthand.
+const DefaultBackendService = "backend"
+
+// Backend service roles.
+const (
+	// ServiceRoleAPI serves HTTP traffic and may be routed by Traefik (default).
+	ServiceRoleAPI = "api"
+
+	// ServiceRoleWorker is a long-running background consumer: no ports,
+	// no Traefik route, checked by container state rather than HTTP.
+	ServiceRoleWorker = "worker"
+)
+
+// ServiceRoleLabel is the compose label Stagecraft sets on worker services.
+const ServiceRoleLabel = "stagecraft.role"
+
+// reservedServiceNames are compose service names Stagecraft generates itself.
+var reservedServiceNames = map[string]bool{
+	"frontend": true,
+	"traefik":  true,
+}
+
+// serviceNamePattern matches names that are valid compose service names
+// and image name components.
+var serviceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// BackendServiceConfig describes one backend service in a multi-service
+// project (backend.services.<name>).
+type BackendServiceConfig struct {
+	Provider  string         `yaml:"provider"`
+	Role      string         `yaml:"role,omitempty"`    // "api" (default) or "worker"
+	WorkDir   string         `yaml:"workdir,omitempty"` // relative to the project root
+	Providers map[string]any `yaml:"providers"`
+}
+
+// ServiceRole returns the configured role, defaulting to ServiceRoleAPI.
+func (s BackendServiceConfig) ServiceRole() string {
+	if s.Role == "" {
+		return ServiceRoleAPI
+	}
+	return s.Role
+}
+
+// IsWorker reports whether the service has the worker role.
+func (s BackendServiceConfig) IsWorker() bool {
+	return s.ServiceRole() == ServiceRoleWorker
+}
+
+// BackendService is a resolved backend service with its name.
+type BackendService struct {
+	Name string
+	BackendServiceConfig
+
+	// field is the config path used in error messages.
+	field string
+}
+
+// ServiceList returns the backend services sorted by name. The
+// single-service shorthand yields one service named DefaultBackendService.
+func (c *BackendConfig) ServiceList() []BackendService {
+	if c == nil {
+		return nil
+	}
+
+	if len(c.Services) == 0 {
+		return []BackendService{{
+			Name: DefaultBackendService,
+			BackendServiceConfig: BackendServiceConfig{
+				Provider:  c.Provider,
+				Providers: c.Providers,
+			},
+			field: "backend",
+		}}
+	}
+
+	names := make([]string, 0, len(c.Services))
+	for name := range c.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	services := make([]BackendService, 0, len(names))
+	for _, name := range names {
+		services = append(services, BackendService{
+			Name:                 name,
+			BackendServiceConfig: c.Services[name],
+			field:                "backend.services." + name,
+		})
+	}
+	return services
+}
+
+// PrimaryService returns the service routed on the backend dev domain:
+// backend.primary when set, otherwise the first non-worker service by name.
+// It reports false when every service is a worker.
+func (c *BackendConfig) PrimaryService() (BackendService, bool) {
+	services := c.ServiceList()
+	for _, svc := range services {
+		if c.Primary != "" && svc.Name == c.Primary {
+			return svc, true
+		}
+	}
+	for _, svc := range services {
+		if !svc.IsWorker() {
+			return svc, true
+		}
+	}
+	return BackendService{}, false
+}
+
+// WorkerServices returns the names of worker services in name order.
+func (c *BackendConfig) WorkerServices() []string {
+	var workers []string
+	for _, svc := range c.ServiceList() {
+		if svc.IsWorker() {
+			workers = append(workers, svc.Name)
+		}
+	}
+	return workers
+}
+
+// IsMultiService reports whether backend uses backend.services.
+func (c *BackendConfig) IsMultiService() bool {
+	return c != nil && len(c.Services) > 0
+}
+
+// GetProviderConfig returns the config for the service's selected provider.
+func (s BackendService) GetProviderConfig() (any, error) {
+	field := s.field
+	if field == "" {
+		field = "backend.services." + s.Name
+	}
+
+	if s.Provider == "" {
+		return nil, fmt.Errorf("%s.provider is required", field)
+	}
+
+	if s.Providers == nil {
+		return nil, fmt.Errorf("%s.providers is required", field)
+	}
+
+	cfg, ok := s.Providers[s.Provider]
+	if !ok {
+		return nil, fmt.Errorf(
+			"%s.providers.%s is missing; provider-specific config is required",
+			field,
+			s.Provider,
+		)
+	}
+
+	return cfg, nil
+}
+
+// validateBackendServices validates backend.services and backend.primary.
+func validateBackendServices(cfg *BackendConfig) error {
+	if cfg.Provider != "" || cfg.Providers != nil {
+		return fmt.Errorf("backend.provider/backend.providers cannot be combined with backend.services")
+	}
+
+	for _, svc := range cfg.ServiceList() {
+		if !serviceNamePattern.MatchString(svc.Name) {
+			return fmt.Errorf("backend.services: invalid service name %q; use lowercase letters, digits, '-' and '_'", svc.Name)
+		}
+		if reservedServiceNames[svc.Name] {
+			return fmt.Errorf("backend.services: service name %q is reserved", svc.Name)
+		}
+
+		switch svc.Role {
+		case "", ServiceRoleAPI, ServiceRoleWorker:
+		default:
+			return fmt.Errorf("%s.role must be %q or %q, got %q", svc.field, ServiceRoleAPI, ServiceRoleWorker, svc.Role)
+		}
+
+		if svc.Provider == "" {
+			return fmt.Errorf("%s.provider is required", svc.field)
+		}
+		if !backendproviders.Has(svc.Provider) {
+			return fmt.Errorf(
+				"%s: unknown backend provider %q; available providers: %v",
+				svc.field,
+				svc.Provider,
+				backendproviders.DefaultRegistry.IDs(),
+			)
+		}
+		if _, err := svc.GetProviderConfig(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Primary != "" {
+		primary, ok := cfg.Services[cfg.Primary]
+		if !ok {
+			return fmt.Errorf("backend.primary %q is not a declared service", cfg.Primary)
+		}
+		if primary.IsWorker() {
+			return fmt.Errorf("backend.primary %q is a worker; workers are not routed", cfg.Primary)
+		}
+	}
+
+	return nil
+}
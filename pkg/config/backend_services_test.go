@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: CORE_BACKEND_SERVICES
+// Spec: spec/core/backend-services.md
+
+func loadBackendConfig(t *testing.T, backend string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+` + backend + `
+environments:
+  dev:
+    driver: "local"
+`)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoad_BackendServices_SortedServiceList(t *testing.T) {
+	cfg, err := loadBackendConfig(t, `backend:
+  primary: api
+  services:
+    worker:
+      provider: generic
+      workdir: ./services/worker
+      providers:
+        generic:
+          dev:
+            command: ["npm", "run", "worker"]
+    api:
+      provider: generic
+      workdir: ./services/api
+      providers:
+        generic:
+          dev:
+            command: ["npm", "run", "dev"]
+    billing:
+      provider: generic
+      providers:
+        generic:
+          dev:
+            command: ["go", "run", "."]`)
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	services := cfg.Backend.ServiceList()
+	var names []string
+	for _, svc := range services {
+		names = append(names, svc.Name)
+	}
+	if got := strings.Join(names, ","); got != "api,billing,worker" {
+		t.Fatalf("ServiceList() names = %s, want api,billing,worker", got)
+	}
+	if services[0].WorkDir != "./services/api" {
+		t.Errorf("api workdir = %q", services[0].WorkDir)
+	}
+	if _, err := services[2].GetProviderConfig(); err != nil {
+		t.Errorf("worker GetProviderConfig() error = %v", err)
+	}
+
+	primary, ok := cfg.Backend.PrimaryService()
+	if !ok || primary.Name != "api" {
+		t.Errorf("PrimaryService() = %q, %v; want api", primary.Name, ok)
+	}
+}
+
+func TestBackendConfig_ServiceList_Shorthand(t *testing.T) {
+	cfg := &BackendConfig{
+		Provider:  "generic",
+		Providers: map[string]any{"generic": map[string]any{}},
+	}
+
+	services := cfg.ServiceList()
+	if len(services) != 1 || services[0].Name != DefaultBackendService || services[0].Provider != "generic" {
+		t.Fatalf("ServiceList() = %+v, want single %q service", services, DefaultBackendService)
+	}
+	if cfg.IsMultiService() {
+		t.Errorf("IsMultiService() = true for shorthand")
+	}
+
+	var nilCfg *BackendConfig
+	if nilCfg.ServiceList() != nil {
+		t.Errorf("nil BackendConfig should have no services")
+	}
+}
+
+func TestBackendConfig_PrimaryService_DefaultsToFirst(t *testing.T) {
+	cfg := &BackendConfig{
+		Services: map[string]BackendServiceConfig{
+			"worker": {Provider: "generic"},
+			"api":    {Provider: "generic"},
+		},
+	}
+	primary, ok := cfg.PrimaryService()
+	if !ok || primary.Name != "api" {
+		t.Fatalf("PrimaryService() = %q, want api", primary.Name)
+	}
+}
+
+func TestBackendConfig_PrimaryService_SkipsWorkers(t *testing.T) {
+	cfg := &BackendConfig{
+		Services: map[string]BackendServiceConfig{
+			"consumer": {Provider: "generic", Role: ServiceRoleWorker},
+			"web":      {Provider: "generic"},
+		},
+	}
+	primary, ok := cfg.PrimaryService()
+	if !ok || primary.Name != "web" {
+		t.Fatalf("PrimaryService() = %q, want web", primary.Name)
+	}
+	if got := strings.Join(cfg.WorkerServices(), ","); got != "consumer" {
+		t.Errorf("WorkerServices() = %q, want consumer", got)
+	}
+
+	workersOnly := &BackendConfig{
+		Services: map[string]BackendServiceConfig{
+			"consumer": {Provider: "generic", Role: ServiceRoleWorker},
+		},
+	}
+	if _, ok := workersOnly.PrimaryService(); ok {
+		t.Error("PrimaryService() should report false when every service is a worker")
+	}
+}
+
+func TestLoad_BackendServices_Invalid(t *testing.T) {
+	generic := `
+      provider: generic
+      providers:
+        generic:
+          dev:
+            command: ["npm", "run", "dev"]`
+
+	tests := []struct {
+		name    string
+		backend string
+		wantErr string
+	}{
+		{
+			name: "mixed with shorthand",
+			backend: `backend:
+  provider: generic
+  services:
+    api:` + generic,
+			wantErr: "cannot be combined with backend.services",
+		},
+		{
+			name: "reserved name",
+			backend: `backend:
+  services:
+    traefik:` + generic,
+			wantErr: `service name "traefik" is reserved`,
+		},
+		{
+			name: "invalid name",
+			backend: `backend:
+  services:
+    Api:` + generic,
+			wantErr: `invalid service name "Api"`,
+		},
+		{
+			name: "unknown provider",
+			backend: `backend:
+  services:
+    api:
+      provider: nope
+      providers:
+        nope: {}`,
+			wantErr: `backend.services.api: unknown backend provider "nope"`,
+		},
+		{
+			name: "missing provider config",
+			backend: `backend:
+  services:
+    api:
+      provider: generic
+      providers:
+        other: {}`,
+			wantErr: "backend.services.api.providers.generic is missing",
+		},
+		{
+			name: "unknown primary",
+			backend: `backend:
+  primary: web
+  services:
+    api:` + generic,
+			wantErr: `backend.primary "web" is not a declared service`,
+		},
+		{
+			name: "unknown role",
+			backend: `backend:
+  services:
+    api:
+      role: cron` + generic,
+			wantErr: `backend.services.api.role must be "api" or "worker", got "cron"`,
+		},
+		{
+			name: "worker primary",
+			backend: `backend:
+  primary: jobs
+  services:
+    api:` + generic + `
+    jobs:
+      role: worker` + generic,
+			wantErr: `backend.primary "jobs" is a worker`,
+		},
+		{
+			name: "primary without services",
+			backend: `backend:
+  primary: api` + strings.ReplaceAll(generic, "\n      ", "\n  "),
+			wantErr: "backend.primary requires backend.services",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadBackendConfig(t, tt.backend)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
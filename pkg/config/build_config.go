@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Feature: CLI_BUILD
+// Spec: spec/commands/build.md
+
+// BuildConfig tunes image builds for an environment. Cache entries are
+// BuildKit cache specs passed verbatim to --cache-from/--cache-to, e.g.
+// "type=registry,ref=ghcr.io/org/app:buildcache".
+type BuildConfig struct {
+	Platform  string      `yaml:"platform,omitempty"`   // e.g. "linux/arm64"; --platform overrides
+	Platforms []string    `yaml:"platforms,omitempty"`  // multi-platform build, published as a manifest list
+	CacheFrom []string    `yaml:"cache_from,omitempty"` // caches to import
+	CacheTo   []string    `yaml:"cache_to,omitempty"`   // caches to export (requires buildx)
+	SBOM      bool        `yaml:"sbom,omitempty"`       // generate an SPDX SBOM per image with syft
+	Scan      *ScanConfig `yaml:"scan,omitempty"`       // scan built images for vulnerabilities
+
+	Args    map[string]string `yaml:"args,omitempty"`    // --build-arg values
+	Target  string            `yaml:"target,omitempty"`  // Dockerfile stage to build
+	Secrets []BuildSecret     `yaml:"secrets,omitempty"` // BuildKit secrets mounted during the build
+}
+
+// BuildSecret is a BuildKit secret, read from a file or from a variable of
+// the environment Stagecraft runs in. Dockerfiles mount it with
+// RUN --mount=type=secret,id=<id>.
+type BuildSecret struct {
+	ID  string `yaml:"id"`
+	Src string `yaml:"src,omitempty"` // file path, relative to the project root
+	Env string `yaml:"env,omitempty"` // environment variable name
+}
+
+// buildSecretIDPattern matches BuildKit secret IDs.
+var buildSecretIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// HasBuildInputs reports whether b sets build args, a target stage, or
+// secrets.
+func (b *BuildConfig) HasBuildInputs() bool {
+	return b != nil && (len(b.Args) > 0 || b.Target != "" || len(b.Secrets) > 0)
+}
+
+// Vulnerability scanners supported by build.scan.scanner.
+const (
+	ScannerGrype = "grype"
+	ScannerTrivy = "trivy"
+)
+
+// ScanSeverities are the accepted build.scan.fail_on values, lowest first.
+var ScanSeverities = []string{"negligible", "low", "medium", "high", "critical"}
+
+// ScanConfig runs a vulnerability scanner over every built image.
+type ScanConfig struct {
+	Scanner string `yaml:"scanner"`           // "grype" or "trivy"
+	FailOn  string `yaml:"fail_on,omitempty"` // lowest severity that fails the build; empty only reports
+}
+
+// TargetPlatforms returns the platforms to build for: platforms, or
+// platform alone, or none for the host platform.
+func (b *BuildConfig) TargetPlatforms() []string {
+	switch {
+	case b == nil:
+		return nil
+	case len(b.Platforms) > 0:
+		return b.Platforms
+	case b.Platform != "":
+		return []string{b.Platform}
+	}
+	return nil
+}
+
+// ValidatePlatform checks that platform has the os/arch[/variant] form.
+func ValidatePlatform(platform string) error {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("platform must be os/arch, got %q", platform)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return fmt.Errorf("platform must be os/arch, got %q", platform)
+		}
+	}
+	return nil
+}
+
+func validateBuild(envName string, build *BuildConfig) error {
+	if build == nil {
+		return nil
+	}
+	field := fmt.Sprintf("config: environment %q: build", envName)
+
+	if build.Platform != "" {
+		if err := ValidatePlatform(build.Platform); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if len(build.Platforms) > 0 {
+			return fmt.Errorf("%s: platform and platforms are mutually exclusive", field)
+		}
+	}
+	seen := make(map[string]bool, len(build.Platforms))
+	for i, platform := range build.Platforms {
+		if err := ValidatePlatform(platform); err != nil {
+			return fmt.Errorf("%s: platforms[%d]: %w", field, i, err)
+		}
+		if seen[platform] {
+			return fmt.Errorf("%s: platforms[%d]: duplicate platform %q", field, i, platform)
+		}
+		seen[platform] = true
+	}
+	if scan := build.Scan; scan != nil {
+		switch scan.Scanner {
+		case ScannerGrype, ScannerTrivy:
+		default:
+			return fmt.Errorf("%s: scan.scanner must be %q or %q, got %q", field, ScannerGrype, ScannerTrivy, scan.Scanner)
+		}
+		if scan.FailOn != "" && !slices.Contains(ScanSeverities, scan.FailOn) {
+			return fmt.Errorf("%s: scan.fail_on must be one of %s, got %q", field, strings.Join(ScanSeverities, ", "), scan.FailOn)
+		}
+	}
+	for i, spec := range build.CacheFrom {
+		if strings.TrimSpace(spec) == "" {
+			return fmt.Errorf("%s: cache_from[%d] must be non-empty", field, i)
+		}
+	}
+	for i, spec := range build.CacheTo {
+		if strings.TrimSpace(spec) == "" {
+			return fmt.Errorf("%s: cache_to[%d] must be non-empty", field, i)
+		}
+	}
+	for name := range build.Args {
+		if name == "" || strings.ContainsAny(name, "= \t") {
+			return fmt.Errorf("%s: args: invalid build arg name %q", field, name)
+		}
+	}
+	if strings.ContainsAny(build.Target, " \t") {
+		return fmt.Errorf("%s: target must not contain whitespace, got %q", field, build.Target)
+	}
+	seenSecrets := make(map[string]bool, len(build.Secrets))
+	for i, secret := range build.Secrets {
+		if !buildSecretIDPattern.MatchString(secret.ID) {
+			return fmt.Errorf("%s: secrets[%d]: invalid id %q", field, i, secret.ID)
+		}
+		if seenSecrets[secret.ID] {
+			return fmt.Errorf("%s: secrets[%d]: duplicate id %q", field, i, secret.ID)
+		}
+		seenSecrets[secret.ID] = true
+		if (secret.Src == "") == (secret.Env == "") {
+			return fmt.Errorf("%s: secrets[%d]: exactly one of src or env must be set", field, i)
+		}
+	}
+	return nil
+}
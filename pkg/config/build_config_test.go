@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: CLI_BUILD
+// Spec: spec/commands/build.md
+
+func loadBuildConfig(t *testing.T, build string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+environments:
+  staging:
+    driver: "digitalocean"
+    build:
+` + build)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoad_Build_Valid(t *testing.T) {
+	cfg, err := loadBuildConfig(t, `
+      platform: linux/arm64
+      cache_from:
+        - type=registry,ref=ghcr.io/org/app:buildcache
+      cache_to:
+        - type=registry,ref=ghcr.io/org/app:buildcache,mode=max
+      sbom: true
+      scan:
+        scanner: grype
+        fail_on: high
+      target: runtime
+      args:
+        NODE_ENV: production
+      secrets:
+        - id: npmrc
+          src: .npmrc
+        - id: gh_token
+          env: GITHUB_TOKEN
+`)
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	build := cfg.Environments["staging"].Build
+	if build == nil {
+		t.Fatal("expected build config to be loaded")
+	}
+	if build.Platform != "linux/arm64" {
+		t.Errorf("Platform = %q, want linux/arm64", build.Platform)
+	}
+	if len(build.CacheFrom) != 1 || len(build.CacheTo) != 1 {
+		t.Errorf("expected one cache_from and one cache_to entry, got %v / %v", build.CacheFrom, build.CacheTo)
+	}
+	if !build.SBOM || build.Scan == nil || build.Scan.Scanner != ScannerGrype || build.Scan.FailOn != "high" {
+		t.Errorf("expected sbom and grype scan failing on high, got sbom=%v scan=%+v", build.SBOM, build.Scan)
+	}
+	if build.Target != "runtime" || build.Args["NODE_ENV"] != "production" || len(build.Secrets) != 2 {
+		t.Errorf("expected target, args, and secrets, got target=%q args=%v secrets=%+v", build.Target, build.Args, build.Secrets)
+	}
+	if !build.HasBuildInputs() {
+		t.Error("HasBuildInputs() = false, want true")
+	}
+}
+
+func TestLoad_Build_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   string
+		wantErr string
+	}{
+		{
+			name: "platform without arch",
+			build: `
+      platform: arm64
+`,
+			wantErr: `build: platform must be os/arch, got "arm64"`,
+		},
+		{
+			name: "platform and platforms",
+			build: `
+      platform: linux/amd64
+      platforms: [linux/amd64, linux/arm64]
+`,
+			wantErr: "build: platform and platforms are mutually exclusive",
+		},
+		{
+			name: "duplicate platform",
+			build: `
+      platforms: [linux/amd64, linux/amd64]
+`,
+			wantErr: `build: platforms[1]: duplicate platform "linux/amd64"`,
+		},
+		{
+			name: "invalid platforms entry",
+			build: `
+      platforms: [linux/amd64, arm64]
+`,
+			wantErr: `build: platforms[1]: platform must be os/arch, got "arm64"`,
+		},
+		{
+			name: "unknown scanner",
+			build: `
+      scan:
+        scanner: clair
+`,
+			wantErr: `build: scan.scanner must be "grype" or "trivy", got "clair"`,
+		},
+		{
+			name: "unknown severity",
+			build: `
+      scan:
+        scanner: trivy
+        fail_on: severe
+`,
+			wantErr: `build: scan.fail_on must be one of negligible, low, medium, high, critical, got "severe"`,
+		},
+		{
+			name: "empty cache_to",
+			build: `
+      cache_to:
+        - ""
+`,
+			wantErr: "build: cache_to[0] must be non-empty",
+		},
+		{
+			name: "invalid build arg",
+			build: `
+      args:
+        "A=B": x
+`,
+			wantErr: `build: args: invalid build arg name "A=B"`,
+		},
+		{
+			name: "invalid secret id",
+			build: `
+      secrets:
+        - id: "-token"
+          env: TOKEN
+`,
+			wantErr: `build: secrets[0]: invalid id "-token"`,
+		},
+		{
+			name: "duplicate secret id",
+			build: `
+      secrets:
+        - id: token
+          env: TOKEN
+        - id: token
+          src: token.txt
+`,
+			wantErr: `build: secrets[1]: duplicate id "token"`,
+		},
+		{
+			name: "secret with src and env",
+			build: `
+      secrets:
+        - id: token
+          src: token.txt
+          env: TOKEN
+`,
+			wantErr: "build: secrets[0]: exactly one of src or env must be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadBuildConfig(t, tt.build)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBuildConfig_TargetPlatforms(t *testing.T) {
+	var unset *BuildConfig
+	if got := unset.TargetPlatforms(); got != nil {
+		t.Errorf("nil config TargetPlatforms() = %v, want nil", got)
+	}
+
+	single := &BuildConfig{Platform: "linux/amd64"}
+	if got := single.TargetPlatforms(); len(got) != 1 || got[0] != "linux/amd64" {
+		t.Errorf("TargetPlatforms() = %v, want [linux/amd64]", got)
+	}
+
+	multi := &BuildConfig{Platforms: []string{"linux/amd64", "linux/arm64"}}
+	if got := multi.TargetPlatforms(); len(got) != 2 {
+		t.Errorf("TargetPlatforms() = %v, want both platforms", got)
+	}
+}
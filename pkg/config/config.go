@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 
@@ -30,6 +31,8 @@ import (
 	_ "stagecraft/internal/providers/migration/raw"
 	_ "stagecraft/internal/providers/network/tailscale"
 
+	"stagecraft/internal/failure"
+	"stagecraft/internal/providers/plugin"
 	backendproviders "stagecraft/pkg/providers/backend"
 	frontendproviders "stagecraft/pkg/providers/frontend"
 	migrationengines "stagecraft/pkg/providers/migration"
@@ -42,10 +45,11 @@ import (
 // Spec: spec/core/config.md
 
 // ErrConfigNotFound is returned when the config file does not exist at the given path.
-var ErrConfigNotFound = errors.New("stagecraft config not found")
+var ErrConfigNotFound = failure.New(failure.ClassConfigInvalid, "stagecraft config not found")
 
 // Config represents the top-level Stagecraft configuration.
 type Config struct {
+	Version      int                          `yaml:"version,omitempty"` // Schema version; see CurrentSchemaVersion
 	Project      ProjectConfig                `yaml:"project"`
 	Backend      *BackendConfig               `yaml:"backend,omitempty"`
 	Frontend     *FrontendConfig              `yaml:"frontend,omitempty"`
@@ -56,6 +60,13 @@ type Config struct {
 	Databases    map[string]DatabaseConfig    `yaml:"databases,omitempty"`
 	Environments map[string]EnvironmentConfig `yaml:"environments"`
 	Infra        *InfraConfig                 `yaml:"infra,omitempty"`
+	Hooks        map[string][]HookConfig      `yaml:"hooks,omitempty"` // Keyed by HookPoint, e.g. "pre_rollout"
+	Artifacts    *ArtifactsConfig             `yaml:"artifacts,omitempty"`
+	Engine       *EngineConfig                `yaml:"engine,omitempty"`
+	Templates    []TemplateConfig             `yaml:"templates,omitempty"` // Config files rendered into deploy services
+
+	// ContainerRuntime is "docker" or "podman"; detected from PATH when empty.
+	ContainerRuntime string `yaml:"container_runtime,omitempty"`
 }
 
 // ProjectConfig describes project-level settings.
@@ -64,9 +75,14 @@ type ProjectConfig struct {
 }
 
 // BackendConfig describes backend provider configuration.
+//
+// A project declares either a single backend via provider/providers or
+// several named services via services; see ServiceList.
 type BackendConfig struct {
-	Provider  string         `yaml:"provider"`
-	Providers map[string]any `yaml:"providers"`
+	Provider  string                          `yaml:"provider,omitempty"`
+	Providers map[string]any                  `yaml:"providers,omitempty"`
+	Services  map[string]BackendServiceConfig `yaml:"services,omitempty"`
+	Primary   string                          `yaml:"primary,omitempty"` // service routed on the backend dev domain
 }
 
 // FrontendConfig describes frontend provider configuration.
@@ -104,6 +120,10 @@ type InfraBootstrapConfig struct {
 // Spec: spec/commands/dev.md
 type DevConfig struct {
 	Domains *DevDomains `yaml:"domains,omitempty"`
+
+	// DockerContext pins the Docker CLI context dev runs against, e.g.
+	// "colima" or "desktop-linux". Empty uses the active context.
+	DockerContext string `yaml:"docker_context,omitempty"`
 }
 
 // DevDomains describes development domain configuration.
@@ -129,9 +149,15 @@ type MigrationConfig struct {
 
 // EnvironmentConfig describes per-environment settings.
 type EnvironmentConfig struct {
-	Driver  string         `yaml:"driver"`
-	EnvFile string         `yaml:"env_file,omitempty"` // Path to environment file
-	Rollout *RolloutConfig `yaml:"rollout,omitempty"`  // Rollout configuration
+	Driver         string                        `yaml:"driver"`
+	EnvFile        string                        `yaml:"env_file,omitempty"`        // Path to environment file
+	Rollout        *RolloutConfig                `yaml:"rollout,omitempty"`         // Rollout configuration
+	Notifications  []NotificationConfig          `yaml:"notifications,omitempty"`   // Deploy lifecycle notifications
+	FrontendStatic *StaticConfig                 `yaml:"frontend_static,omitempty"` // Serve the frontend build from nginx/caddy
+	Services       map[string]ServiceScaleConfig `yaml:"services,omitempty"`        // Per-service replicas
+	Build          *BuildConfig                  `yaml:"build,omitempty"`           // Build platform and cache settings
+	Signing        *SigningConfig                `yaml:"signing,omitempty"`         // cosign image signing and verification
+	TemplateVars   map[string]string             `yaml:"template_vars,omitempty"`   // Overrides templates[].vars in this environment
 	// Future: region, registry, etc.
 }
 
@@ -140,6 +166,10 @@ type RolloutConfig struct {
 	Enabled bool `yaml:"enabled"` // Opt-in flag for docker-rollout
 	// Mode deferred to v2 (default serial)
 	// Health check deferred to separate feature
+
+	// SkipUnchanged leaves services running when their compose fragment and
+	// image are unchanged since the last completed rollout.
+	SkipUnchanged bool `yaml:"skip_unchanged,omitempty"`
 }
 
 // GetProviderConfig returns the config for the selected backend provider.
@@ -228,6 +258,11 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("parsing config file: %w", err)
 	}
 
+	// Project plugins must be registered before provider IDs are validated.
+	if err := plugin.LoadProject(filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("loading provider plugins: %w", err)
+	}
+
 	if err := validate(&cfg); err != nil {
 		return nil, err
 	}
@@ -236,6 +271,10 @@ func Load(path string) (*Config, error) {
 }
 
 func validate(cfg *Config) error {
+	if cfg.Version > CurrentSchemaVersion {
+		return fmt.Errorf("%w: file is at version %d, this build supports up to %d", ErrSchemaTooNew, cfg.Version, CurrentSchemaVersion)
+	}
+
 	if cfg.Project.Name == "" {
 		return errors.New("config: project.name must be non-empty")
 	}
@@ -276,6 +315,41 @@ func validate(cfg *Config) error {
 		if envCfg.Driver == "" {
 			return fmt.Errorf("config: environment %q: driver must be non-empty", envName)
 		}
+		if err := validateNotifications(envName, envCfg.Notifications); err != nil {
+			return err
+		}
+		if err := validateStatic(envName, envCfg.FrontendStatic, cfg.Frontend); err != nil {
+			return err
+		}
+		if err := validateScaling(envName, envCfg.Services); err != nil {
+			return err
+		}
+		if err := validateBuild(envName, envCfg.Build); err != nil {
+			return err
+		}
+		if err := validateSigning(envName, envCfg.Signing); err != nil {
+			return err
+		}
+	}
+
+	if err := validateHooks(cfg.Hooks, cfg.Environments); err != nil {
+		return err
+	}
+
+	if err := validateTemplates(cfg.Templates, cfg.Environments); err != nil {
+		return err
+	}
+
+	if err := validateContainerRuntime(cfg); err != nil {
+		return err
+	}
+
+	if err := validateArtifacts(cfg.Artifacts); err != nil {
+		return err
+	}
+
+	if err := validateEngine(cfg.Engine); err != nil {
+		return err
 	}
 
 	return nil
@@ -283,6 +357,13 @@ func validate(cfg *Config) error {
 
 // validateBackend validates backend configuration using the registry.
 func validateBackend(cfg *BackendConfig) error {
+	if cfg.IsMultiService() {
+		return validateBackendServices(cfg)
+	}
+	if cfg.Primary != "" {
+		return fmt.Errorf("backend.primary requires backend.services")
+	}
+
 	if cfg.Provider == "" {
 		return fmt.Errorf("backend.provider is required")
 	}
@@ -207,6 +207,45 @@ environments:
 	}
 }
 
+func TestLoad_RegistersProjectPluginBackend(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "stagecraft.yml")
+
+	pluginDir := filepath.Join(tmpDir, ".stagecraft", "plugins", "acme")
+	if err := os.MkdirAll(pluginDir, 0o750); err != nil {
+		t.Fatalf("failed to create plugin dir: %v", err)
+	}
+	manifest := []byte(`{"id":"config-test-plugin","kind":"backend","command":"./plugin.sh"}`)
+	if err := os.WriteFile(filepath.Join(pluginDir, "plugin.json"), manifest, 0o600); err != nil {
+		t.Fatalf("failed to write plugin manifest: %v", err)
+	}
+
+	content := []byte(`
+project:
+  name: "test-app"
+backend:
+  provider: config-test-plugin
+  providers:
+    config-test-plugin:
+      service: api
+environments:
+  dev:
+    driver: "local"
+`)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("expected plugin backend to be accepted, got: %v", err)
+	}
+	if cfg.Backend.Provider != "config-test-plugin" {
+		t.Fatalf("expected backend.provider 'config-test-plugin', got %q", cfg.Backend.Provider)
+	}
+}
+
 func TestLoad_ValidatesBackend_MissingProviderConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 	path := filepath.Join(tmpDir, "stagecraft.yml")
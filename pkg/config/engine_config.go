@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feature: ENGINE_STEP_TIMEOUTS
+// Spec: spec/engine/step-timeouts.md
+
+// engineStepActions mirrors the step actions from pkg/engine. It is
+// duplicated here because config must not depend on the engine package.
+var engineStepActions = []string{"apply_compose", "build", "health_check", "migrate", "render_compose", "rollout"}
+
+// StepTimeoutKeys returns every valid engine.step_timeouts key, the deploy
+// phases and engine step actions, in lexicographic order.
+func StepTimeoutKeys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, key := range append(append([]string{}, hookPhases...), engineStepActions...) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EngineConfig tunes how deploy phases and engine steps execute.
+type EngineConfig struct {
+	// StepTimeout bounds every phase and step (e.g. "30m"); empty means no limit.
+	StepTimeout string `yaml:"step_timeout,omitempty"`
+
+	// StepTimeouts overrides StepTimeout per deploy phase or step action,
+	// e.g. {"build": "20m"}.
+	StepTimeouts map[string]string `yaml:"step_timeouts,omitempty"`
+}
+
+// DefaultStepTimeout returns the parsed step_timeout, or 0 for no limit.
+// Config validation guarantees the value parses.
+func (e *EngineConfig) DefaultStepTimeout() time.Duration {
+	if e == nil {
+		return 0
+	}
+	d, _ := time.ParseDuration(e.StepTimeout)
+	return d
+}
+
+// StepTimeoutFor returns the timeout for a deploy phase or step action:
+// its override when set, otherwise the default, or 0 for no limit.
+func (e *EngineConfig) StepTimeoutFor(name string) time.Duration {
+	if e == nil {
+		return 0
+	}
+	value, ok := e.StepTimeouts[name]
+	if !ok {
+		return e.DefaultStepTimeout()
+	}
+	d, _ := time.ParseDuration(value)
+	return d
+}
+
+func validateEngine(engine *EngineConfig) error {
+	if engine == nil {
+		return nil
+	}
+
+	if err := validateStepTimeout("config: engine.step_timeout", engine.StepTimeout); err != nil {
+		return err
+	}
+
+	valid := StepTimeoutKeys()
+	keys := make([]string, 0, len(engine.StepTimeouts))
+	for key := range engine.StepTimeouts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		idx := sort.SearchStrings(valid, key)
+		if idx == len(valid) || valid[idx] != key {
+			return fmt.Errorf("config: engine.step_timeouts: unknown phase or action %q (valid: %s)", key, strings.Join(valid, ", "))
+		}
+		if err := validateStepTimeout("config: engine.step_timeouts."+key, engine.StepTimeouts[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateStepTimeout(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("%s must be a positive duration, got %q", field, value)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Feature: ENGINE_STEP_TIMEOUTS
+// Spec: spec/engine/step-timeouts.md
+
+func loadEngineConfig(t *testing.T, engine string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+environments:
+  prod:
+    driver: "digitalocean"
+engine:
+` + engine)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoad_Engine_StepTimeouts(t *testing.T) {
+	cfg, err := loadEngineConfig(t, `
+  step_timeout: 30m
+  step_timeouts:
+    build: 1h
+    migrate_pre: 5m
+    apply_compose: 10m
+`)
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	tests := map[string]time.Duration{
+		"build":         time.Hour,
+		"migrate_pre":   5 * time.Minute,
+		"apply_compose": 10 * time.Minute,
+		"rollout":       30 * time.Minute,
+	}
+	for name, want := range tests {
+		if got := cfg.Engine.StepTimeoutFor(name); got != want {
+			t.Errorf("StepTimeoutFor(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestEngineConfig_StepTimeoutFor_Unset(t *testing.T) {
+	var engine *EngineConfig
+	if got := engine.StepTimeoutFor("build"); got != 0 {
+		t.Errorf("nil engine config should not limit steps, got %s", got)
+	}
+}
+
+func TestLoad_Engine_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		engine  string
+		wantErr string
+	}{
+		{
+			name: "invalid default",
+			engine: `
+  step_timeout: soon
+`,
+			wantErr: `engine.step_timeout must be a positive duration, got "soon"`,
+		},
+		{
+			name: "negative override",
+			engine: `
+  step_timeouts:
+    rollout: -5m
+`,
+			wantErr: `engine.step_timeouts.rollout must be a positive duration, got "-5m"`,
+		},
+		{
+			name: "unknown key",
+			engine: `
+  step_timeouts:
+    deploy: 5m
+`,
+			wantErr: `unknown phase or action "deploy"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadEngineConfig(t, tt.engine)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
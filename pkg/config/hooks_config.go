@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feature: DEPLOY_PHASE_HOOKS
+// Spec: spec/deploy/hooks.md
+
+// hookPhases mirrors the canonical deploy phases from internal/core/state.
+// It is duplicated here because config must not depend on the state package.
+var hookPhases = []string{"build", "push", "migrate_pre", "rollout", "migrate_post", "finalize"}
+
+// HookPoint returns the hooks key for running before ("pre") or after
+// ("post") a phase, e.g. "pre_rollout".
+func HookPoint(when, phase string) string {
+	return when + "_" + phase
+}
+
+// HookPoints returns every valid hooks key in lexicographic order.
+func HookPoints() []string {
+	points := make([]string, 0, 2*len(hookPhases))
+	for _, phase := range hookPhases {
+		points = append(points, HookPoint("pre", phase), HookPoint("post", phase))
+	}
+	sort.Strings(points)
+	return points
+}
+
+// HookConfig describes one user-defined command run around a deploy phase.
+type HookConfig struct {
+	// Command is the program and its arguments; it is not run through a shell.
+	Command []string `yaml:"command"`
+	// Host runs the command over SSH (e.g. "deploy@10.0.0.5") instead of locally.
+	Host string `yaml:"host,omitempty"`
+	// Environments limits the hook to these environments; empty means all.
+	Environments []string `yaml:"environments,omitempty"`
+	// Timeout bounds the command's run time (e.g. "2m"); empty means no limit.
+	Timeout string `yaml:"timeout,omitempty"`
+	// ContinueOnError logs failures instead of failing the phase.
+	ContinueOnError bool `yaml:"continue_on_error,omitempty"`
+}
+
+// AppliesTo reports whether the hook runs for env.
+func (h HookConfig) AppliesTo(env string) bool {
+	if len(h.Environments) == 0 {
+		return true
+	}
+	for _, e := range h.Environments {
+		if e == env {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeoutDuration returns the parsed timeout, or 0 when unset. Config
+// validation guarantees the value parses.
+func (h HookConfig) TimeoutDuration() time.Duration {
+	if h.Timeout == "" {
+		return 0
+	}
+	d, _ := time.ParseDuration(h.Timeout)
+	return d
+}
+
+func validateHooks(hooks map[string][]HookConfig, envs map[string]EnvironmentConfig) error {
+	valid := HookPoints()
+	for point, list := range hooks {
+		idx := sort.SearchStrings(valid, point)
+		if idx == len(valid) || valid[idx] != point {
+			return fmt.Errorf("config: hooks: unknown hook %q (valid: %s)", point, strings.Join(valid, ", "))
+		}
+
+		for i, h := range list {
+			field := fmt.Sprintf("config: hooks.%s[%d]", point, i)
+
+			if len(h.Command) == 0 || h.Command[0] == "" {
+				return fmt.Errorf("%s: command is required", field)
+			}
+			if h.Timeout != "" {
+				d, err := time.ParseDuration(h.Timeout)
+				if err != nil || d <= 0 {
+					return fmt.Errorf("%s: timeout must be a positive duration, got %q", field, h.Timeout)
+				}
+			}
+			for _, env := range h.Environments {
+				if _, ok := envs[env]; !ok {
+					return fmt.Errorf("%s: unknown environment %q", field, env)
+				}
+			}
+		}
+	}
+
+	return nil
+}
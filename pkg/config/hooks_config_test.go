@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Feature: DEPLOY_PHASE_HOOKS
+// Spec: spec/deploy/hooks.md
+
+func loadHooksConfig(t *testing.T, hooks string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+environments:
+  staging:
+    driver: "local"
+hooks:
+` + hooks)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestHookPoints_Sorted(t *testing.T) {
+	points := HookPoints()
+	if len(points) != 12 {
+		t.Fatalf("expected 12 hook points, got %d (%v)", len(points), points)
+	}
+	for i := 1; i < len(points); i++ {
+		if points[i-1] >= points[i] {
+			t.Fatalf("hook points not sorted: %v", points)
+		}
+	}
+}
+
+func TestLoad_Hooks_Valid(t *testing.T) {
+	cfg, err := loadHooksConfig(t, `
+  pre_rollout:
+    - command: ["./scripts/drain.sh"]
+      timeout: "30s"
+  post_finalize:
+    - command: ["systemctl", "reload", "nginx"]
+      host: "deploy@10.0.0.5"
+      environments: ["staging"]
+      continue_on_error: true
+`)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	pre := cfg.Hooks["pre_rollout"]
+	if len(pre) != 1 || pre[0].TimeoutDuration() != 30*time.Second {
+		t.Fatalf("unexpected pre_rollout hooks: %+v", pre)
+	}
+
+	post := cfg.Hooks["post_finalize"]
+	if len(post) != 1 || post[0].Host != "deploy@10.0.0.5" || !post[0].ContinueOnError {
+		t.Fatalf("unexpected post_finalize hooks: %+v", post)
+	}
+	if !post[0].AppliesTo("staging") || post[0].AppliesTo("prod") {
+		t.Errorf("expected environment filter to apply only to staging")
+	}
+}
+
+func TestLoad_Hooks_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "unknown hook point",
+			yaml: `
+  before_deploy:
+    - command: ["true"]
+`,
+			wantErr: "unknown hook",
+		},
+		{
+			name: "missing command",
+			yaml: `
+  pre_build:
+    - host: "example.com"
+`,
+			wantErr: "command is required",
+		},
+		{
+			name: "bad timeout",
+			yaml: `
+  pre_build:
+    - command: ["true"]
+      timeout: "soon"
+`,
+			wantErr: "timeout",
+		},
+		{
+			name: "unknown environment",
+			yaml: `
+  pre_build:
+    - command: ["true"]
+      environments: ["prod"]
+`,
+			wantErr: "unknown environment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadHooksConfig(t, tt.yaml)
+			if err == nil {
+				t.Fatalf("expected validation error, got nil")
+			}
+			if !containsSubstring(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Feature: DEPLOY_NOTIFICATIONS
+// Spec: spec/deploy/notifications.md
+
+// Notification target types.
+const (
+	NotificationTypeShell   = "shell"
+	NotificationTypeSlack   = "slack"
+	NotificationTypeWebhook = "webhook"
+)
+
+// Notification lifecycle events.
+const (
+	NotificationEventStarted    = "deploy.started"
+	NotificationEventSucceeded  = "deploy.succeeded"
+	NotificationEventFailed     = "deploy.failed"
+	NotificationEventRolledBack = "deploy.rolled_back"
+)
+
+// NotificationEvents returns all lifecycle events in lexicographic order.
+func NotificationEvents() []string {
+	return []string{
+		NotificationEventFailed,
+		NotificationEventRolledBack,
+		NotificationEventStarted,
+		NotificationEventSucceeded,
+	}
+}
+
+// DefaultNotificationRetries is the number of retries after a failed delivery.
+const DefaultNotificationRetries = 2
+
+// NotificationConfig describes one notification target for an environment.
+//
+// URL and header values may reference environment variables as ${NAME} so
+// webhook secrets stay out of stagecraft.yml.
+type NotificationConfig struct {
+	Type    string            `yaml:"type"`
+	URL     string            `yaml:"url,omitempty"`     // slack, webhook
+	Headers map[string]string `yaml:"headers,omitempty"` // webhook
+	Command []string          `yaml:"command,omitempty"` // shell
+	Events  []string          `yaml:"events,omitempty"`  // empty means all events
+	Retries *int              `yaml:"retries,omitempty"` // defaults to DefaultNotificationRetries
+}
+
+// WantsEvent reports whether the target subscribes to event.
+func (n NotificationConfig) WantsEvent(event string) bool {
+	if len(n.Events) == 0 {
+		return true
+	}
+	for _, e := range n.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryCount returns the configured retries or the default.
+func (n NotificationConfig) RetryCount() int {
+	if n.Retries == nil {
+		return DefaultNotificationRetries
+	}
+	return *n.Retries
+}
+
+func validateNotifications(envName string, targets []NotificationConfig) error {
+	for i, n := range targets {
+		field := fmt.Sprintf("config: environment %q: notifications[%d]", envName, i)
+
+		switch n.Type {
+		case NotificationTypeSlack, NotificationTypeWebhook:
+			if n.URL == "" {
+				return fmt.Errorf("%s: url is required for type %q", field, n.Type)
+			}
+			// Only validate URLs without env references; expanded values are
+			// checked when the notification is sent.
+			if !strings.Contains(n.URL, "$") {
+				u, err := url.Parse(n.URL)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("%s: url must be an http(s) URL", field)
+				}
+			}
+			if len(n.Command) > 0 {
+				return fmt.Errorf("%s: command is only valid for type %q", field, NotificationTypeShell)
+			}
+			if n.Type == NotificationTypeSlack && len(n.Headers) > 0 {
+				return fmt.Errorf("%s: headers are only valid for type %q", field, NotificationTypeWebhook)
+			}
+		case NotificationTypeShell:
+			if len(n.Command) == 0 || n.Command[0] == "" {
+				return fmt.Errorf("%s: command is required for type %q", field, NotificationTypeShell)
+			}
+			if n.URL != "" || len(n.Headers) > 0 {
+				return fmt.Errorf("%s: url and headers are not valid for type %q", field, NotificationTypeShell)
+			}
+		case "":
+			return fmt.Errorf("%s: type is required", field)
+		default:
+			return fmt.Errorf("%s: unknown type %q (valid: %s, %s, %s)", field, n.Type,
+				NotificationTypeShell, NotificationTypeSlack, NotificationTypeWebhook)
+		}
+
+		for _, e := range n.Events {
+			if !isNotificationEvent(e) {
+				return fmt.Errorf("%s: unknown event %q (valid: %s)", field, e, strings.Join(NotificationEvents(), ", "))
+			}
+		}
+
+		if n.Retries != nil && *n.Retries < 0 {
+			return fmt.Errorf("%s: retries must not be negative", field)
+		}
+	}
+
+	return nil
+}
+
+func isNotificationEvent(event string) bool {
+	for _, e := range NotificationEvents() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Feature: DEPLOY_NOTIFICATIONS
+// Spec: spec/deploy/notifications.md
+
+func loadNotificationConfig(t *testing.T, notifications string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+environments:
+  prod:
+    driver: "digitalocean"
+    notifications:
+` + notifications)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoad_Notifications_Valid(t *testing.T) {
+	cfg, err := loadNotificationConfig(t, `
+      - type: slack
+        url: "${SLACK_WEBHOOK_URL}"
+        events: ["deploy.failed"]
+      - type: webhook
+        url: "https://hooks.example.com/deploy"
+        headers:
+          Authorization: "Bearer ${HOOK_TOKEN}"
+        retries: 0
+      - type: shell
+        command: ["./scripts/notify.sh"]
+`)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	targets := cfg.Environments["prod"].Notifications
+	if len(targets) != 3 {
+		t.Fatalf("expected 3 notification targets, got %d", len(targets))
+	}
+
+	if targets[0].WantsEvent(NotificationEventStarted) {
+		t.Errorf("expected slack target to skip %s", NotificationEventStarted)
+	}
+	if !targets[0].WantsEvent(NotificationEventFailed) {
+		t.Errorf("expected slack target to want %s", NotificationEventFailed)
+	}
+	if !targets[2].WantsEvent(NotificationEventRolledBack) {
+		t.Errorf("expected target without events to want every event")
+	}
+
+	if got := targets[0].RetryCount(); got != DefaultNotificationRetries {
+		t.Errorf("expected default retries %d, got %d", DefaultNotificationRetries, got)
+	}
+	if got := targets[1].RetryCount(); got != 0 {
+		t.Errorf("expected explicit retries 0, got %d", got)
+	}
+}
+
+func TestLoad_Notifications_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "missing type",
+			yaml: `
+      - url: "https://example.com"
+`,
+			wantErr: "type is required",
+		},
+		{
+			name: "unknown type",
+			yaml: `
+      - type: pager
+`,
+			wantErr: "unknown type",
+		},
+		{
+			name: "webhook without url",
+			yaml: `
+      - type: webhook
+`,
+			wantErr: "url is required",
+		},
+		{
+			name: "slack with non-http url",
+			yaml: `
+      - type: slack
+        url: "ftp://example.com"
+`,
+			wantErr: "http(s) URL",
+		},
+		{
+			name: "shell without command",
+			yaml: `
+      - type: shell
+`,
+			wantErr: "command is required",
+		},
+		{
+			name: "headers on slack",
+			yaml: `
+      - type: slack
+        url: "https://hooks.slack.com/x"
+        headers:
+          X-Test: "1"
+`,
+			wantErr: "headers",
+		},
+		{
+			name: "unknown event",
+			yaml: `
+      - type: shell
+        command: ["true"]
+        events: ["deploy.exploded"]
+`,
+			wantErr: "unknown event",
+		},
+		{
+			name: "negative retries",
+			yaml: `
+      - type: shell
+        command: ["true"]
+        retries: -1
+`,
+			wantErr: "retries",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadNotificationConfig(t, tt.yaml)
+			if err == nil {
+				t.Fatalf("expected validation error, got nil")
+			}
+			if !containsSubstring(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
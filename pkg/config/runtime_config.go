@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+)
+
+// Feature: CORE_CONTAINER_RUNTIME
+// Spec: spec/core/container-runtime.md
+
+// Container runtimes Stagecraft can drive.
+const (
+	ContainerRuntimeDocker = "docker"
+	ContainerRuntimePodman = "podman"
+)
+
+func validateContainerRuntime(cfg *Config) error {
+	switch cfg.ContainerRuntime {
+	case "", ContainerRuntimeDocker:
+		return nil
+	case ContainerRuntimePodman:
+	default:
+		return fmt.Errorf("config: container_runtime must be %q or %q, got %q",
+			ContainerRuntimeDocker, ContainerRuntimePodman, cfg.ContainerRuntime)
+	}
+
+	if cfg.Dev != nil && cfg.Dev.DockerContext != "" {
+		return fmt.Errorf("config: dev.docker_context requires container_runtime %q", ContainerRuntimeDocker)
+	}
+
+	// docker-rollout is a Docker CLI plugin and cannot drive podman.
+	for envName, envCfg := range cfg.Environments {
+		if envCfg.Rollout != nil && envCfg.Rollout.Enabled {
+			return fmt.Errorf("config: environment %q: rollout.enabled requires container_runtime %q", envName, ContainerRuntimeDocker)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: CORE_CONTAINER_RUNTIME
+// Spec: spec/core/container-runtime.md
+
+func loadRuntimeConfig(t *testing.T, runtime, env string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+container_runtime: ` + runtime + `
+environments:
+  prod:
+    driver: "digitalocean"
+` + env)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoad_ContainerRuntime_Valid(t *testing.T) {
+	for _, runtime := range []string{ContainerRuntimeDocker, ContainerRuntimePodman} {
+		cfg, err := loadRuntimeConfig(t, runtime, "")
+		if err != nil {
+			t.Fatalf("container_runtime %s: expected valid config, got: %v", runtime, err)
+		}
+		if cfg.ContainerRuntime != runtime {
+			t.Errorf("ContainerRuntime = %q, want %q", cfg.ContainerRuntime, runtime)
+		}
+	}
+}
+
+func TestLoad_ContainerRuntime_Unknown(t *testing.T) {
+	_, err := loadRuntimeConfig(t, "containerd", "")
+	if err == nil || !strings.Contains(err.Error(), "container_runtime must be") {
+		t.Fatalf("expected container_runtime error, got: %v", err)
+	}
+}
+
+func TestLoad_ContainerRuntime_PodmanRejectsRollout(t *testing.T) {
+	_, err := loadRuntimeConfig(t, ContainerRuntimePodman, `    rollout:
+      enabled: true
+`)
+	if err == nil || !strings.Contains(err.Error(), "rollout.enabled requires container_runtime") {
+		t.Fatalf("expected rollout error, got: %v", err)
+	}
+}
+
+func TestLoad_ContainerRuntime_PodmanRejectsDockerContext(t *testing.T) {
+	_, err := loadRuntimeConfig(t, ContainerRuntimePodman, `dev:
+  docker_context: colima
+`)
+	if err == nil || !strings.Contains(err.Error(), "dev.docker_context requires container_runtime") {
+		t.Fatalf("expected docker_context error, got: %v", err)
+	}
+}
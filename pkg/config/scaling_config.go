@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Feature: DEPLOY_SERVICE_REPLICAS
+// Spec: spec/deploy/replicas.md
+
+// ServiceScaleConfig scales one compose service in an environment
+// (environments.<env>.services.<name>).
+type ServiceScaleConfig struct {
+	Replicas int `yaml:"replicas"`
+}
+
+// ReplicasFor returns the number of instances of service to run in the
+// environment. Services without an entry run a single instance.
+func (e EnvironmentConfig) ReplicasFor(service string) int {
+	if scale, ok := e.Services[service]; ok && scale.Replicas > 0 {
+		return scale.Replicas
+	}
+	return 1
+}
+
+func validateScaling(envName string, services map[string]ServiceScaleConfig) error {
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := fmt.Sprintf("config: environment %q: services.%s", envName, name)
+		if !serviceNamePattern.MatchString(name) {
+			return fmt.Errorf("%s: invalid service name; use lowercase letters, digits, '-' and '_'", field)
+		}
+		if name == "traefik" {
+			return fmt.Errorf("%s: traefik cannot be scaled", field)
+		}
+		if services[name].Replicas < 1 {
+			return fmt.Errorf("%s: replicas must be >= 1, got %d", field, services[name].Replicas)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: DEPLOY_SERVICE_REPLICAS
+// Spec: spec/deploy/replicas.md
+
+func loadScalingConfig(t *testing.T, services string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+environments:
+  prod:
+    driver: "digitalocean"
+    services:
+` + services)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoad_ServiceReplicas_Valid(t *testing.T) {
+	cfg, err := loadScalingConfig(t, `
+      api:
+        replicas: 3
+`)
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	prod := cfg.Environments["prod"]
+	if got := prod.ReplicasFor("api"); got != 3 {
+		t.Errorf("ReplicasFor(api) = %d, want 3", got)
+	}
+	if got := prod.ReplicasFor("db"); got != 1 {
+		t.Errorf("ReplicasFor(db) = %d, want 1 for unscaled services", got)
+	}
+}
+
+func TestLoad_ServiceReplicas_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		services string
+		wantErr  string
+	}{
+		{
+			name: "zero replicas",
+			services: `
+      api:
+        replicas: 0
+`,
+			wantErr: `services.api: replicas must be >= 1, got 0`,
+		},
+		{
+			name: "traefik",
+			services: `
+      traefik:
+        replicas: 2
+`,
+			wantErr: "traefik cannot be scaled",
+		},
+		{
+			name: "invalid name",
+			services: `
+      Api:
+        replicas: 2
+`,
+			wantErr: "services.Api: invalid service name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadScalingConfig(t, tt.services)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"stagecraft/internal/failure"
+)
+
+// Feature: CLI_UPGRADE
+// Spec: spec/commands/upgrade.md
+
+// CurrentSchemaVersion is the config schema version understood and written
+// by this build of Stagecraft. Configs without a version field are treated
+// as version 0.
+const CurrentSchemaVersion = 1
+
+// ErrSchemaTooNew is returned when a config declares a schema version newer
+// than CurrentSchemaVersion.
+var ErrSchemaTooNew = failure.New(failure.ClassConfigInvalid, "config schema version is newer than supported")
+
+// SchemaMigration transforms a config document from one schema version to
+// the next. Migrations operate on the YAML node tree so comments and key
+// order are preserved where possible.
+type SchemaMigration struct {
+	From        int
+	To          int
+	Description string
+	Apply       func(doc *yaml.Node) error
+}
+
+// schemaMigrations is the ordered list of migrations. Each entry must
+// upgrade From to From+1.
+var schemaMigrations = []SchemaMigration{
+	{
+		From:        0,
+		To:          1,
+		Description: "nest inline provider blocks under <section>.providers.<id>",
+		Apply:       migrateV0ToV1,
+	},
+}
+
+// SchemaMigrations returns the ordered list of registered schema migrations.
+func SchemaMigrations() []SchemaMigration {
+	out := make([]SchemaMigration, len(schemaMigrations))
+	copy(out, schemaMigrations)
+	return out
+}
+
+// UpgradeResult describes the outcome of Upgrade.
+type UpgradeResult struct {
+	FromVersion int
+	ToVersion   int
+	Applied     []SchemaMigration
+	Original    []byte
+	Upgraded    []byte
+}
+
+// Changed reports whether the upgrade produced different bytes.
+func (r *UpgradeResult) Changed() bool {
+	return !bytes.Equal(r.Original, r.Upgraded)
+}
+
+// SchemaVersion reads the schema version declared in raw config YAML.
+// A missing version field is reported as 0.
+func SchemaVersion(data []byte) (int, error) {
+	var probe struct {
+		Version int `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return 0, fmt.Errorf("parsing config file: %w", err)
+	}
+	if probe.Version < 0 {
+		return 0, fmt.Errorf("config: version must not be negative, got %d", probe.Version)
+	}
+	return probe.Version, nil
+}
+
+// Upgrade applies all pending schema migrations to raw config YAML and
+// returns the upgraded document. It does not validate provider config;
+// callers should run Load on the result if they need a validated config.
+func Upgrade(data []byte) (*UpgradeResult, error) {
+	from, err := SchemaVersion(data)
+	if err != nil {
+		return nil, err
+	}
+	if from > CurrentSchemaVersion {
+		return nil, fmt.Errorf("%w: file is at version %d, this build supports up to %d", ErrSchemaTooNew, from, CurrentSchemaVersion)
+	}
+
+	result := &UpgradeResult{
+		FromVersion: from,
+		ToVersion:   from,
+		Original:    data,
+		Upgraded:    data,
+	}
+	if from == CurrentSchemaVersion {
+		return result, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing config file: %w", err)
+	}
+	root := documentMapping(&doc)
+	if root == nil {
+		return nil, errors.New("config: top-level document must be a mapping")
+	}
+
+	for _, m := range schemaMigrations {
+		if m.From < result.ToVersion {
+			continue
+		}
+		if m.From != result.ToVersion || m.To != m.From+1 {
+			return nil, fmt.Errorf("config: schema migration chain broken at version %d", result.ToVersion)
+		}
+		if err := m.Apply(root); err != nil {
+			return nil, fmt.Errorf("applying schema migration %d->%d: %w", m.From, m.To, err)
+		}
+		result.ToVersion = m.To
+		result.Applied = append(result.Applied, m)
+		if result.ToVersion == CurrentSchemaVersion {
+			break
+		}
+	}
+
+	setSchemaVersion(root, result.ToVersion)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("encoding upgraded config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encoding upgraded config: %w", err)
+	}
+	result.Upgraded = buf.Bytes()
+
+	return result, nil
+}
+
+// migrateV0ToV1 moves provider-specific blocks that early configs placed
+// directly under a section (e.g. network.tailscale or frontend.dev) into
+// the <section>.providers.<id> map used by the registries. A key already
+// set under providers.<id> is a conflict, not silently dropped.
+func migrateV0ToV1(root *yaml.Node) error {
+	for _, section := range []string{"backend", "cloud", "frontend", "network"} {
+		sec := mappingValue(root, section)
+		if sec == nil || sec.Kind != yaml.MappingNode {
+			continue
+		}
+		providerNode := mappingValue(sec, "provider")
+		if providerNode == nil || providerNode.Value == "" {
+			continue
+		}
+		id := providerNode.Value
+
+		providers := mappingValue(sec, "providers")
+		if providers == nil {
+			providers = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			appendMapping(sec, "providers", providers)
+		}
+		if providers.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s.providers must be a mapping", section)
+		}
+
+		target := mappingValue(providers, id)
+
+		kept := make([]*yaml.Node, 0, len(sec.Content))
+		for i := 0; i+1 < len(sec.Content); i += 2 {
+			key, val := sec.Content[i], sec.Content[i+1]
+			switch key.Value {
+			case "provider", "providers":
+				kept = append(kept, key, val)
+				continue
+			}
+
+			if target == nil {
+				target = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				appendMapping(providers, id, target)
+			}
+			if key.Value == id {
+				// Inline block named after the provider: merge its keys.
+				if val.Kind != yaml.MappingNode {
+					return fmt.Errorf("%s.%s must be a mapping", section, id)
+				}
+				for j := 0; j+1 < len(val.Content); j += 2 {
+					if mappingValue(target, val.Content[j].Value) != nil {
+						return fmt.Errorf("%s.%s.%s conflicts with %s.providers.%s.%s", section, id, val.Content[j].Value, section, id, val.Content[j].Value)
+					}
+					target.Content = append(target.Content, val.Content[j], val.Content[j+1])
+				}
+				continue
+			}
+			if mappingValue(target, key.Value) != nil {
+				return fmt.Errorf("%s.%s conflicts with %s.providers.%s.%s", section, key.Value, section, id, key.Value)
+			}
+			target.Content = append(target.Content, key, val)
+		}
+		sec.Content = kept
+	}
+	return nil
+}
+
+// documentMapping returns the top-level mapping node of a YAML document.
+func documentMapping(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) == 1 {
+		doc = doc.Content[0]
+	}
+	if doc.Kind != yaml.MappingNode {
+		return nil
+	}
+	return doc
+}
+
+// mappingValue returns the value node for key in a mapping, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// appendMapping appends key: value to a mapping node.
+func appendMapping(m *yaml.Node, key string, value *yaml.Node) {
+	m.Content = append(m.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+}
+
+// setSchemaVersion sets the top-level version key, inserting it first if absent.
+func setSchemaVersion(root *yaml.Node, version int) {
+	value := strconv.Itoa(version)
+	if existing := mappingValue(root, "version"); existing != nil {
+		existing.Kind = yaml.ScalarNode
+		existing.Tag = "!!int"
+		existing.Value = value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	// Keep any leading file comment above the new first key.
+	if len(root.Content) > 0 {
+		key.HeadComment = root.Content[0].HeadComment
+		root.Content[0].HeadComment = ""
+	}
+	root.Content = append([]*yaml.Node{
+		key,
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: value},
+	}, root.Content...)
+}
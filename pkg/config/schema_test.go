@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: CLI_UPGRADE
+// Spec: spec/commands/upgrade.md
+
+func TestSchemaVersion_MissingIsZero(t *testing.T) {
+	v, err := SchemaVersion([]byte("project:\n  name: test\n"))
+	if err != nil {
+		t.Fatalf("SchemaVersion() error = %v", err)
+	}
+	if v != 0 {
+		t.Fatalf("expected version 0, got %d", v)
+	}
+}
+
+func TestSchemaMigrations_FormContiguousChain(t *testing.T) {
+	migrations := SchemaMigrations()
+	if len(migrations) == 0 {
+		t.Fatalf("expected at least one schema migration")
+	}
+	for i, m := range migrations {
+		if m.From != i || m.To != i+1 {
+			t.Fatalf("migration %d: expected %d->%d, got %d->%d", i, i, i+1, m.From, m.To)
+		}
+	}
+	if last := migrations[len(migrations)-1]; last.To != CurrentSchemaVersion {
+		t.Fatalf("expected chain to end at %d, got %d", CurrentSchemaVersion, last.To)
+	}
+}
+
+func TestUpgrade_V0NestsInlineProviderBlocks(t *testing.T) {
+	input := `# my project
+project:
+  name: demo
+frontend:
+  provider: generic
+  dev:
+    command: ["npm", "run", "dev"]
+network:
+  provider: tailscale
+  tailscale:
+    auth_key_env: TS_AUTHKEY
+environments:
+  dev:
+    driver: local
+`
+
+	result, err := Upgrade([]byte(input))
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if result.FromVersion != 0 || result.ToVersion != CurrentSchemaVersion {
+		t.Fatalf("expected 0->%d, got %d->%d", CurrentSchemaVersion, result.FromVersion, result.ToVersion)
+	}
+	if !result.Changed() {
+		t.Fatalf("expected upgrade to change the document")
+	}
+
+	out := string(result.Upgraded)
+	if !strings.HasPrefix(out, "# my project\nversion: 1\n") {
+		t.Errorf("expected leading comment and version first, got:\n%s", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+	if err := os.WriteFile(path, result.Upgraded, 0o600); err != nil {
+		t.Fatalf("writing upgraded config: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("upgraded config should load, got: %v\n%s", err, out)
+	}
+	if cfg.Version != CurrentSchemaVersion {
+		t.Errorf("expected version %d, got %d", CurrentSchemaVersion, cfg.Version)
+	}
+
+	fe, ok := cfg.Frontend.Providers["generic"].(map[string]any)
+	if !ok || fe["dev"] == nil {
+		t.Errorf("expected frontend.providers.generic.dev, got %#v", cfg.Frontend.Providers)
+	}
+	ts, ok := cfg.Network.Providers["tailscale"].(map[string]any)
+	if !ok || ts["auth_key_env"] != "TS_AUTHKEY" {
+		t.Errorf("expected network.providers.tailscale.auth_key_env, got %#v", cfg.Network.Providers)
+	}
+}
+
+func TestUpgrade_V0RejectsConflictingProviderKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name: "inline key",
+			input: `backend:
+  provider: generic
+  dockerfile: Dockerfile.old
+  providers:
+    generic:
+      dockerfile: Dockerfile
+`,
+			want: "backend.dockerfile conflicts with backend.providers.generic.dockerfile",
+		},
+		{
+			name: "inline provider block",
+			input: `network:
+  provider: tailscale
+  tailscale:
+    auth_key_env: OLD_KEY
+  providers:
+    tailscale:
+      auth_key_env: TS_AUTHKEY
+`,
+			want: "network.tailscale.auth_key_env conflicts with network.providers.tailscale.auth_key_env",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Upgrade([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestUpgrade_CurrentIsNoop(t *testing.T) {
+	input := []byte("version: 1\nproject:\n  name: demo\n")
+
+	result, err := Upgrade(input)
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if len(result.Applied) != 0 || result.Changed() {
+		t.Fatalf("expected no-op upgrade, got %d migrations", len(result.Applied))
+	}
+}
+
+func TestUpgrade_RejectsNewerVersion(t *testing.T) {
+	_, err := Upgrade([]byte("version: 99\nproject:\n  name: demo\n"))
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
+
+func TestLoad_RejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+	data := "version: 99\nproject:\n  name: demo\nenvironments:\n  dev:\n    driver: local\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	if _, err := Load(path); !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Feature: DEPLOY_IMAGE_SIGNING
+// Spec: spec/deploy/image-signing.md
+
+// EnvKeyPrefix marks a cosign key reference read from an environment
+// variable, e.g. "env://COSIGN_PRIVATE_KEY".
+const EnvKeyPrefix = "env://"
+
+// SigningConfig signs pushed images and verifies them before rollout with
+// cosign. Keys are cosign key references: a file path, "env://NAME", or a
+// KMS URI such as "awskms://..." or "hashivault://...".
+type SigningConfig struct {
+	Key       string `yaml:"key,omitempty"`        // private key; signs images after push
+	PublicKey string `yaml:"public_key,omitempty"` // public key; verifies images before rollout
+}
+
+func validateSigning(envName string, signing *SigningConfig) error {
+	if signing == nil {
+		return nil
+	}
+	field := fmt.Sprintf("config: environment %q: signing", envName)
+
+	if signing.Key == "" && signing.PublicKey == "" {
+		return fmt.Errorf("%s requires key, public_key, or both", field)
+	}
+	if err := validateKeyRef(signing.Key); err != nil {
+		return fmt.Errorf("%s: key: %w", field, err)
+	}
+	if err := validateKeyRef(signing.PublicKey); err != nil {
+		return fmt.Errorf("%s: public_key: %w", field, err)
+	}
+	return nil
+}
+
+func validateKeyRef(ref string) error {
+	name, ok := strings.CutPrefix(ref, EnvKeyPrefix)
+	if ok && strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%q is missing the variable name", ref)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: DEPLOY_IMAGE_SIGNING
+// Spec: spec/deploy/image-signing.md
+
+func loadSigningConfig(t *testing.T, signing string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+environments:
+  prod:
+    driver: "digitalocean"
+    signing:
+` + signing)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoad_Signing_Valid(t *testing.T) {
+	cfg, err := loadSigningConfig(t, `
+      key: env://COSIGN_PRIVATE_KEY
+      public_key: cosign.pub
+`)
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	signing := cfg.Environments["prod"].Signing
+	if signing == nil || signing.Key != "env://COSIGN_PRIVATE_KEY" || signing.PublicKey != "cosign.pub" {
+		t.Fatalf("unexpected signing config: %+v", signing)
+	}
+}
+
+func TestLoad_Signing_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		signing string
+		wantErr string
+	}{
+		{
+			name: "no keys",
+			signing: `
+      key: ""
+`,
+			wantErr: "signing requires key, public_key, or both",
+		},
+		{
+			name: "env reference without name",
+			signing: `
+      public_key: "env://"
+`,
+			wantErr: `signing: public_key: "env://" is missing the variable name`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadSigningConfig(t, tt.signing)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+)
+
+// Feature: DEPLOY_FRONTEND_STATIC
+// Spec: spec/deploy/frontend-static.md
+
+// Static file servers supported for serving frontend builds.
+const (
+	StaticServerCaddy = "caddy"
+	StaticServerNginx = "nginx"
+)
+
+// DefaultStaticService is the compose service name used for the static server.
+const DefaultStaticService = "frontend"
+
+// StaticConfig opts an environment into serving the frontend's static build
+// from a web server container instead of the provider's dev server.
+type StaticConfig struct {
+	Server  string `yaml:"server"`            // "nginx" or "caddy"
+	Service string `yaml:"service,omitempty"` // defaults to DefaultStaticService
+	Image   string `yaml:"image,omitempty"`   // defaults per server
+	Port    int    `yaml:"port,omitempty"`    // host port; unpublished when zero
+}
+
+// ServiceName returns the configured service name or the default.
+func (s *StaticConfig) ServiceName() string {
+	if s.Service == "" {
+		return DefaultStaticService
+	}
+	return s.Service
+}
+
+// ServerImage returns the configured image or the default for the server.
+func (s *StaticConfig) ServerImage() string {
+	if s.Image != "" {
+		return s.Image
+	}
+	if s.Server == StaticServerCaddy {
+		return "caddy:2-alpine"
+	}
+	return "nginx:1.27-alpine"
+}
+
+func validateStatic(envName string, static *StaticConfig, frontend *FrontendConfig) error {
+	if static == nil {
+		return nil
+	}
+	field := fmt.Sprintf("config: environment %q: frontend_static", envName)
+
+	if frontend == nil {
+		return fmt.Errorf("%s requires a frontend provider", field)
+	}
+	switch static.Server {
+	case StaticServerCaddy, StaticServerNginx:
+	default:
+		return fmt.Errorf("%s: server must be %q or %q, got %q", field, StaticServerNginx, StaticServerCaddy, static.Server)
+	}
+	if static.Port < 0 || static.Port > 65535 {
+		return fmt.Errorf("%s: port %d is out of range", field, static.Port)
+	}
+	return nil
+}
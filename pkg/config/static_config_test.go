@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: DEPLOY_FRONTEND_STATIC
+// Spec: spec/deploy/frontend-static.md
+
+func loadStaticConfig(t *testing.T, frontend, static string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+` + frontend + `
+environments:
+  prod:
+    driver: "digitalocean"
+    frontend_static:
+` + static)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+const staticTestFrontend = `frontend:
+  provider: generic
+  providers:
+    generic:
+      build:
+        command: ["npm", "run", "build"]
+        output_dir: dist`
+
+func TestLoad_FrontendStatic_Valid(t *testing.T) {
+	cfg, err := loadStaticConfig(t, staticTestFrontend, `
+      server: caddy
+      port: 8080
+`)
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	static := cfg.Environments["prod"].FrontendStatic
+	if static == nil || static.Server != StaticServerCaddy || static.Port != 8080 {
+		t.Fatalf("unexpected frontend_static: %+v", static)
+	}
+	if static.ServiceName() != DefaultStaticService {
+		t.Errorf("ServiceName() = %q, want %q", static.ServiceName(), DefaultStaticService)
+	}
+	if static.ServerImage() != "caddy:2-alpine" {
+		t.Errorf("ServerImage() = %q, want caddy default", static.ServerImage())
+	}
+}
+
+func TestLoad_FrontendStatic_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		frontend string
+		static   string
+		wantErr  string
+	}{
+		{
+			name:     "no frontend provider",
+			frontend: "",
+			static:   "      server: nginx\n",
+			wantErr:  "requires a frontend provider",
+		},
+		{
+			name:     "unknown server",
+			frontend: staticTestFrontend,
+			static:   "      server: apache\n",
+			wantErr:  `server must be "nginx" or "caddy"`,
+		},
+		{
+			name:     "port out of range",
+			frontend: staticTestFrontend,
+			static:   "      server: nginx\n      port: 70000\n",
+			wantErr:  "out of range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadStaticConfig(t, tt.frontend, tt.static)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Feature: DEPLOY_TEMPLATES
+// Spec: spec/deploy/templates.md
+
+// TemplateSuffix is stripped from a template's file name to name the
+// rendered file.
+const TemplateSuffix = ".tmpl"
+
+// TemplateConfig renders a user-provided template into a file mounted in a
+// compose service (templates[]).
+type TemplateConfig struct {
+	Source  string            `yaml:"source"`         // template path, relative to the project root
+	Service string            `yaml:"service"`        // compose service that mounts the rendered file
+	Target  string            `yaml:"target"`         // absolute path of the file inside the container
+	Vars    map[string]string `yaml:"vars,omitempty"` // values available to the template as .Vars
+
+	// Environments limits the template to these environments; empty means all.
+	Environments []string `yaml:"environments,omitempty"`
+}
+
+// FileName returns the rendered file's name: the source's base name
+// without TemplateSuffix.
+func (t TemplateConfig) FileName() string {
+	return strings.TrimSuffix(path.Base(strings.ReplaceAll(t.Source, "\\", "/")), TemplateSuffix)
+}
+
+// AppliesTo reports whether the template is rendered for env.
+func (t TemplateConfig) AppliesTo(env string) bool {
+	if len(t.Environments) == 0 {
+		return true
+	}
+	for _, e := range t.Environments {
+		if e == env {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplatesFor returns the templates rendered for env, in config order.
+func (c *Config) TemplatesFor(env string) []TemplateConfig {
+	var templates []TemplateConfig
+	for _, t := range c.Templates {
+		if t.AppliesTo(env) {
+			templates = append(templates, t)
+		}
+	}
+	return templates
+}
+
+func validateTemplates(templates []TemplateConfig, envs map[string]EnvironmentConfig) error {
+	targets := make(map[string]int)
+	files := make(map[string]int)
+
+	for i, t := range templates {
+		field := fmt.Sprintf("config: templates[%d]", i)
+
+		if strings.TrimSpace(t.Source) == "" {
+			return fmt.Errorf("%s: source must be non-empty", field)
+		}
+		if !serviceNamePattern.MatchString(t.Service) {
+			return fmt.Errorf("%s: invalid service name %q; use lowercase letters, digits, '-' and '_'", field, t.Service)
+		}
+		if !strings.HasPrefix(t.Target, "/") {
+			return fmt.Errorf("%s: target must be an absolute path inside the container, got %q", field, t.Target)
+		}
+		if name := t.FileName(); name == "" || name == "." || name == ".." {
+			return fmt.Errorf("%s: source %q does not name a file", field, t.Source)
+		}
+		for _, env := range t.Environments {
+			if _, ok := envs[env]; !ok {
+				return fmt.Errorf("%s: environment %q not found", field, env)
+			}
+		}
+
+		// Two templates rendered for the same environment must not mount
+		// onto the same target or render to the same file.
+		for env := range envs {
+			if !t.AppliesTo(env) {
+				continue
+			}
+			key := env + "\x00" + t.Service + "\x00" + t.Target
+			if j, ok := targets[key]; ok {
+				return fmt.Errorf("%s: service %q already mounts templates[%d] at %s", field, t.Service, j, t.Target)
+			}
+			targets[key] = i
+
+			key = env + "\x00" + t.Service + "\x00" + t.FileName()
+			if j, ok := files[key]; ok {
+				return fmt.Errorf("%s: renders to the same file %q as templates[%d] for service %q", field, t.FileName(), j, t.Service)
+			}
+			files[key] = i
+		}
+	}
+	return nil
+}
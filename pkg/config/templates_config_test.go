@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: DEPLOY_TEMPLATES
+// Spec: spec/deploy/templates.md
+
+func loadTemplatesConfig(t *testing.T, templates string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stagecraft.yml")
+
+	content := []byte(`
+project:
+  name: "test-app"
+environments:
+  staging:
+    driver: "digitalocean"
+  prod:
+    driver: "digitalocean"
+    template_vars:
+      workers: "8"
+templates:
+` + templates)
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write temp config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoad_Templates_Valid(t *testing.T) {
+	cfg, err := loadTemplatesConfig(t, `
+  - source: "deploy/nginx.conf.tmpl"
+    service: "web"
+    target: "/etc/nginx/nginx.conf"
+    vars:
+      workers: "2"
+  - source: "deploy/app.yaml.tmpl"
+    service: "api"
+    target: "/app/config.yaml"
+    environments: ["prod"]
+`)
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	if got := cfg.Templates[0].FileName(); got != "nginx.conf" {
+		t.Errorf("FileName() = %q, want nginx.conf", got)
+	}
+	if got := len(cfg.TemplatesFor("staging")); got != 1 {
+		t.Errorf("TemplatesFor(staging) returned %d templates, want 1", got)
+	}
+	if got := len(cfg.TemplatesFor("prod")); got != 2 {
+		t.Errorf("TemplatesFor(prod) returned %d templates, want 2", got)
+	}
+	if got := cfg.Environments["prod"].TemplateVars["workers"]; got != "8" {
+		t.Errorf("prod template_vars.workers = %q, want 8", got)
+	}
+}
+
+func TestLoad_Templates_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates string
+		wantErr   string
+	}{
+		{
+			name: "missing source",
+			templates: `
+  - service: "web"
+    target: "/etc/nginx/nginx.conf"
+`,
+			wantErr: "templates[0]: source must be non-empty",
+		},
+		{
+			name: "relative target",
+			templates: `
+  - source: "nginx.conf.tmpl"
+    service: "web"
+    target: "etc/nginx/nginx.conf"
+`,
+			wantErr: "target must be an absolute path",
+		},
+		{
+			name: "invalid service",
+			templates: `
+  - source: "nginx.conf.tmpl"
+    service: "Web"
+    target: "/etc/nginx/nginx.conf"
+`,
+			wantErr: `invalid service name "Web"`,
+		},
+		{
+			name: "unknown environment",
+			templates: `
+  - source: "nginx.conf.tmpl"
+    service: "web"
+    target: "/etc/nginx/nginx.conf"
+    environments: ["qa"]
+`,
+			wantErr: `environment "qa" not found`,
+		},
+		{
+			name: "duplicate target",
+			templates: `
+  - source: "a/nginx.conf.tmpl"
+    service: "web"
+    target: "/etc/nginx/nginx.conf"
+  - source: "b/default.conf.tmpl"
+    service: "web"
+    target: "/etc/nginx/nginx.conf"
+    environments: ["prod"]
+`,
+			wantErr: `templates[1]: service "web" already mounts templates[0]`,
+		},
+		{
+			name: "duplicate file name",
+			templates: `
+  - source: "a/nginx.conf.tmpl"
+    service: "web"
+    target: "/etc/nginx/nginx.conf"
+  - source: "b/nginx.conf"
+    service: "web"
+    target: "/etc/nginx/conf.d/nginx.conf"
+`,
+			wantErr: `renders to the same file "nginx.conf" as templates[0]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTemplatesConfig(t, tt.templates)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
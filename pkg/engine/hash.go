@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Feature: ENGINE_PLAN_SIGNING
+// Spec: spec/engine/plan-signing.md
+
+// CanonicalJSON returns the canonical encoding of v: compact JSON with
+// object keys sorted and no insignificant whitespace. Embedded
+// json.RawMessage values (step inputs) are canonicalized too, so two plans
+// that differ only in key order or formatting encode identically.
+func CanonicalJSON(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(generic); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// HashCanonical returns "sha256:<hex>" of v's canonical JSON encoding.
+func HashCanonical(v any) (string, error) {
+	data, err := CanonicalJSON(v)
+	if err != nil {
+		return "", fmt.Errorf("canonicalizing: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// HashPlan returns the content address of p.
+func HashPlan(p *Plan) (string, error) {
+	return HashCanonical(p)
+}
+
+// HashHostPlan returns the content address of p.
+func HashHostPlan(p *HostPlan) (string, error) {
+	return HashCanonical(p)
+}
+
+// PlanSignatureAlgorithm is the only supported PlanSignature algorithm.
+const PlanSignatureAlgorithm = "ed25519"
+
+// PlanSignature is a detached signature over a plan's content address.
+type PlanSignature struct {
+	Algorithm string `json:"algorithm"` // must be PlanSignatureAlgorithm
+	KeyID     string `json:"keyId"`     // identifies the public key that verifies Value
+	Digest    string `json:"digest"`    // signed content address, "sha256:<hex>"
+	Value     string `json:"value"`     // base64 (standard encoding) signature
+}
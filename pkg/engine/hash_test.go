@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// Feature: ENGINE_PLAN_SIGNING
+// Spec: spec/engine/plan-signing.md
+
+func hashTestPlan(inputs string) *Plan {
+	return &Plan{
+		Version: PlanSchemaVersion,
+		ID:      "plan-1",
+		Steps: []PlanStep{{
+			ID:     "apply",
+			Index:  0,
+			Action: StepActionApplyCompose,
+			Target: ResourceRef{Kind: "service", Name: "api", Provider: "docker-compose"},
+			Inputs: json.RawMessage(inputs),
+		}},
+		Meta: map[string]string{"b": "2", "a": "1"},
+	}
+}
+
+func TestCanonicalJSON_SortsKeysAndStripsWhitespace(t *testing.T) {
+	got, err := CanonicalJSON(json.RawMessage(`{ "b": [1, 2.50], "a": {"y": "<&>", "x": null} }`))
+	if err != nil {
+		t.Fatalf("CanonicalJSON() error = %v", err)
+	}
+	want := `{"a":{"x":null,"y":"<&>"},"b":[1,2.50]}`
+	if string(got) != want {
+		t.Errorf("CanonicalJSON() = %s, want %s", got, want)
+	}
+}
+
+func TestHashPlan_IgnoresInputFormatting(t *testing.T) {
+	first, err := HashPlan(hashTestPlan(`{"compose_path":"compose.yaml","project_name":"demo"}`))
+	if err != nil {
+		t.Fatalf("HashPlan() error = %v", err)
+	}
+	second, err := HashPlan(hashTestPlan("{\n  \"project_name\": \"demo\",\n  \"compose_path\": \"compose.yaml\"\n}"))
+	if err != nil {
+		t.Fatalf("HashPlan() error = %v", err)
+	}
+	if first != second {
+		t.Errorf("expected equal hashes, got %s and %s", first, second)
+	}
+	if !strings.HasPrefix(first, "sha256:") || len(first) != len("sha256:")+64 {
+		t.Errorf("unexpected hash format %q", first)
+	}
+
+	changed, _ := HashPlan(hashTestPlan(`{"compose_path":"compose.yaml","project_name":"other"}`))
+	if changed == first {
+		t.Error("expected changed inputs to change the hash")
+	}
+}
+
+func TestHashHostPlan_CoversHost(t *testing.T) {
+	a, _ := HashHostPlan(&HostPlan{Version: HostPlanSchemaVersion, PlanID: "p", Host: HostRef{LogicalID: "host-a"}})
+	b, _ := HashHostPlan(&HostPlan{Version: HostPlanSchemaVersion, PlanID: "p", Host: HostRef{LogicalID: "host-b"}})
+	if a == b {
+		t.Error("expected different hosts to hash differently")
+	}
+}
@@ -15,6 +15,8 @@ package inputs
 import "fmt"
 
 // ApplyComposeInputs defines inputs for applying a rendered compose file.
+//
+// +inputs:action=apply_compose
 type ApplyComposeInputs struct {
 	Environment string `json:"environment"`
 	ComposePath string `json:"compose_path"`
@@ -15,6 +15,8 @@ package inputs
 import "fmt"
 
 // BuildInputs defines inputs for a build step.
+//
+// +inputs:action=build
 type BuildInputs struct {
 	Provider   string       `json:"provider"`
 	Workdir    string       `json:"workdir"`
@@ -28,4 +28,9 @@ See https://www.gnu.org/licenses/ for license details.
 //
 // Determinism: all set-like lists are sorted, paths are normalized,
 // hashes are validated, and JSON output is deterministic.
+//
+// Registry: each inputs struct is tagged with a "+inputs:action=<action>"
+// doc marker. `go generate` runs inputsgen, which writes the StepAction
+// registry, a typed Decode<Action> helper per struct, and default
+// Normalize/Validate methods for structs that do not define their own.
 package inputs
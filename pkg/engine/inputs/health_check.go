@@ -15,6 +15,8 @@ package inputs
 import "fmt"
 
 // HealthCheckInputs defines inputs for a health check step.
+//
+// +inputs:action=health_check
 type HealthCheckInputs struct {
 	Environment string `json:"environment"`
 
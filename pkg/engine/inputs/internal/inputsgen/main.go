@@ -0,0 +1,328 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+// Command inputsgen generates the StepAction registry for pkg/engine/inputs.
+//
+// It scans the package for struct types whose doc comment carries a
+// "+inputs:action=<action>" marker and writes zz_generated_registry.go with:
+//   - the registry mapping each engine.StepAction to its inputs struct
+//   - a typed Decode<Action> helper per struct (UnmarshalStrict, Normalize, Validate)
+//   - default Normalize/Validate methods for structs that do not define them
+//
+// Run it via `go generate ./pkg/engine/inputs`.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Feature: ENGINE_INPUTS_REGISTRY
+// Spec: spec/engine/inputs-registry.md
+
+const (
+	markerPrefix  = "+inputs:action="
+	generatedFile = "zz_generated_registry.go"
+)
+
+// inputsType is an annotated inputs struct.
+type inputsType struct {
+	Name         string
+	Action       string
+	HasNormalize bool
+	HasValidate  bool
+	Fields       []field
+}
+
+// field is a struct field relevant to default Normalize/Validate.
+type field struct {
+	Name     string
+	JSONName string
+	Slice    bool // []string rather than string
+	Required bool // string without omitempty
+}
+
+func main() {
+	dir := flag.String("dir", ".", "inputs package directory")
+	flag.Parse()
+
+	src, err := Generate(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "inputsgen: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, generatedFile), src, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "inputsgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Generate returns the formatted source of the registry file for the
+// inputs package in dir.
+func Generate(dir string) ([]byte, error) {
+	types, err := collect(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no %q markers found in %s", markerPrefix, dir)
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, struct{ Types []inputsType }{types}); err != nil {
+		return nil, fmt.Errorf("rendering registry: %w", err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting registry: %w\n%s", err, buf.String())
+	}
+	return src, nil
+}
+
+// collect parses the package in dir and returns its annotated inputs types
+// sorted by action.
+func collect(dir string) ([]inputsType, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return !strings.HasSuffix(name, "_test.go") && name != generatedFile
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", dir, err)
+	}
+
+	byName := map[string]*inputsType{}
+	methods := map[string]map[string]bool{}
+	seenActions := map[string]string{}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.GenDecl:
+					if d.Tok != token.TYPE {
+						continue
+					}
+					for _, spec := range d.Specs {
+						ts := spec.(*ast.TypeSpec)
+						doc := ts.Doc
+						if doc == nil && len(d.Specs) == 1 {
+							doc = d.Doc
+						}
+						action := markerAction(doc)
+						if action == "" {
+							continue
+						}
+						st, ok := ts.Type.(*ast.StructType)
+						if !ok {
+							return nil, fmt.Errorf("%s: %s marker on non-struct type", ts.Name.Name, markerPrefix)
+						}
+						if prev, dup := seenActions[action]; dup {
+							return nil, fmt.Errorf("action %q is claimed by both %s and %s", action, prev, ts.Name.Name)
+						}
+						seenActions[action] = ts.Name.Name
+						byName[ts.Name.Name] = &inputsType{
+							Name:   ts.Name.Name,
+							Action: action,
+							Fields: structFields(st),
+						}
+					}
+				case *ast.FuncDecl:
+					if recv := receiverName(d); recv != "" {
+						if methods[recv] == nil {
+							methods[recv] = map[string]bool{}
+						}
+						methods[recv][d.Name.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	types := make([]inputsType, 0, len(byName))
+	for name, t := range byName {
+		t.HasNormalize = methods[name]["Normalize"]
+		t.HasValidate = methods[name]["Validate"]
+		types = append(types, *t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Action < types[j].Action })
+	return types, nil
+}
+
+func markerAction(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	for _, c := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		if strings.HasPrefix(text, markerPrefix) {
+			return strings.TrimSpace(strings.TrimPrefix(text, markerPrefix))
+		}
+	}
+	return ""
+}
+
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// structFields returns the string and []string fields of st.
+func structFields(st *ast.StructType) []field {
+	var fields []field
+	for _, f := range st.Fields.List {
+		slice := false
+		expr := f.Type
+		if arr, ok := expr.(*ast.ArrayType); ok && arr.Len == nil {
+			slice = true
+			expr = arr.Elt
+		}
+		ident, ok := expr.(*ast.Ident)
+		if !ok || ident.Name != "string" {
+			continue
+		}
+
+		jsonName, omitempty := "", false
+		if f.Tag != nil {
+			tag := reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("json")
+			parts := strings.Split(tag, ",")
+			jsonName = parts[0]
+			for _, opt := range parts[1:] {
+				if opt == "omitempty" {
+					omitempty = true
+				}
+			}
+		}
+		for _, name := range f.Names {
+			jn := jsonName
+			if jn == "" {
+				jn = name.Name
+			}
+			if jn == "-" {
+				continue
+			}
+			fields = append(fields, field{
+				Name:     name.Name,
+				JSONName: jn,
+				Slice:    slice,
+				Required: !slice && !omitempty,
+			})
+		}
+	}
+	return fields
+}
+
+// constName maps "apply_compose" to "StepActionApplyCompose".
+func constName(action string) string {
+	var b strings.Builder
+	b.WriteString("StepAction")
+	for _, part := range strings.Split(action, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// funcSuffix maps "apply_compose" to "ApplyCompose".
+func funcSuffix(action string) string {
+	return strings.TrimPrefix(constName(action), "StepAction")
+}
+
+var fileTemplate = template.Must(template.New("registry").Funcs(template.FuncMap{
+	"constName":  constName,
+	"funcSuffix": funcSuffix,
+}).Parse(`// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Code generated by inputsgen; DO NOT EDIT.
+
+package inputs
+
+import (
+	"fmt"
+
+	"stagecraft/pkg/engine"
+)
+
+// registry maps each StepAction to a constructor for its inputs struct.
+var registry = map[engine.StepAction]func() Inputs{
+{{- range .Types}}
+	engine.{{constName .Action}}: func() Inputs { return &{{.Name}}{} },
+{{- end}}
+}
+{{range .Types}}
+// Decode{{funcSuffix .Action}} strictly decodes, normalizes, and validates {{.Action}} inputs.
+func Decode{{funcSuffix .Action}}(data []byte) (*{{.Name}}, error) {
+	in := &{{.Name}}{}
+	if err := decode(data, in); err != nil {
+		return nil, fmt.Errorf("{{.Action}} inputs: %w", err)
+	}
+	return in, nil
+}
+{{if not .HasNormalize}}
+// Normalize canonicalizes {{.Name}} fields.
+func (in *{{.Name}}) Normalize() error {
+{{- range .Fields}}
+{{- if .Slice}}
+	for i := range in.{{.Name}} {
+		in.{{.Name}}[i] = NormalizeString(in.{{.Name}}[i])
+	}
+{{- else}}
+	in.{{.Name}} = NormalizeString(in.{{.Name}})
+{{- end}}
+{{- end}}
+	return nil
+}
+{{end}}
+{{- if not .HasValidate}}
+// Validate validates {{.Name}} according to v1 rules.
+func (in *{{.Name}}) Validate() error {
+{{- range .Fields}}
+{{- if .Required}}
+	if in.{{.Name}} == "" {
+		return fmt.Errorf("{{.JSONName}} is required")
+	}
+{{- else if .Slice}}
+	for _, v := range in.{{.Name}} {
+		if v == "" {
+			return fmt.Errorf("{{.JSONName}} contains empty value")
+		}
+	}
+{{- end}}
+{{- end}}
+	return nil
+}
+{{end}}
+{{- end}}
+`))
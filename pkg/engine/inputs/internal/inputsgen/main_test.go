@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Feature: ENGINE_INPUTS_REGISTRY
+// Spec: spec/engine/inputs-registry.md
+
+// TestGenerate_CommittedRegistryIsCurrent fails when an inputs struct or
+// marker changed without re-running `go generate ./pkg/engine/inputs`.
+func TestGenerate_CommittedRegistryIsCurrent(t *testing.T) {
+	dir := filepath.Join("..", "..")
+	got, err := Generate(dir)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	committed, err := os.ReadFile(filepath.Join(dir, generatedFile))
+	if err != nil {
+		t.Fatalf("reading committed registry: %v", err)
+	}
+	if !bytes.Equal(got, committed) {
+		t.Fatalf("%s is stale; run `go generate ./pkg/engine/inputs`", generatedFile)
+	}
+}
+
+func TestGenerate_Fixture(t *testing.T) {
+	src, err := Generate(filepath.Join("testdata", "fixture"))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	out := string(src)
+
+	for _, want := range []string{
+		"// Code generated by inputsgen; DO NOT EDIT.",
+		"engine.StepActionCustom:      func() Inputs { return &CustomInputs{} },",
+		"engine.StepActionPruneImages: func() Inputs { return &PruneInputs{} },",
+		"func DecodePruneImages(data []byte) (*PruneInputs, error) {",
+		"func (in *PruneInputs) Normalize() error {",
+		"in.Host = NormalizeString(in.Host)",
+		"in.Images[i] = NormalizeString(in.Images[i])",
+		"func (in *PruneInputs) Validate() error {",
+		`return fmt.Errorf("host is required")`,
+		`return fmt.Errorf("images contains empty value")`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated source missing %q:\n%s", want, out)
+		}
+	}
+
+	for _, unwanted := range []string{
+		"func (in *CustomInputs) Normalize",
+		"func (in *CustomInputs) Validate",
+		"filter is required",
+		"Ignored",
+		"unannotated",
+	} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("generated source unexpectedly contains %q", unwanted)
+		}
+	}
+}
+
+func TestGenerate_RejectsDuplicateActions(t *testing.T) {
+	dir := t.TempDir()
+	src := `package inputs
+
+// +inputs:action=build
+type A struct{}
+
+// +inputs:action=build
+type B struct{}
+`
+	if err := os.WriteFile(filepath.Join(dir, "dup.go"), []byte(src), 0o600); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+	if _, err := Generate(dir); err == nil || !strings.Contains(err.Error(), "claimed by both") {
+		t.Fatalf("expected duplicate action error, got %v", err)
+	}
+}
+
+func TestConstName(t *testing.T) {
+	if got := constName("apply_compose"); got != "StepActionApplyCompose" {
+		t.Errorf("constName() = %q", got)
+	}
+}
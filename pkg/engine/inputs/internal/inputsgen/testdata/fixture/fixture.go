@@ -0,0 +1,30 @@
+package inputs
+
+// PruneInputs defines inputs for a prune step.
+//
+// +inputs:action=prune_images
+type PruneInputs struct {
+	Host    string   `json:"host"`
+	Filter  string   `json:"filter,omitempty"`
+	Images  []string `json:"images,omitempty"`
+	Retain  int      `json:"retain"`
+	Ignored string   `json:"-"`
+}
+
+// CustomInputs defines inputs with hand-written methods.
+//
+// +inputs:action=custom
+type CustomInputs struct {
+	Name string `json:"name"`
+}
+
+// Normalize is hand-written.
+func (in *CustomInputs) Normalize() error { return nil }
+
+// Validate is hand-written.
+func (in *CustomInputs) Validate() error { return nil }
+
+// unannotated is ignored by the generator.
+type unannotated struct {
+	Name string `json:"name"`
+}
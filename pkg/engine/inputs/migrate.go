@@ -15,6 +15,8 @@ package inputs
 import "fmt"
 
 // MigrateInputs defines inputs for a migration step.
+//
+// +inputs:action=migrate
 type MigrateInputs struct {
 	Database string `json:"database"`
 	Strategy string `json:"strategy"`
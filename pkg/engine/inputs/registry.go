@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package inputs
+
+import (
+	"fmt"
+	"sort"
+
+	"stagecraft/pkg/engine"
+)
+
+//go:generate go run ./internal/inputsgen -dir .
+
+// Feature: ENGINE_INPUTS_REGISTRY
+// Spec: spec/engine/inputs-registry.md
+
+// Inputs is implemented by every typed inputs struct.
+type Inputs interface {
+	Normalize() error
+	Validate() error
+}
+
+// ForAction returns a new, empty inputs struct for action.
+func ForAction(action engine.StepAction) (Inputs, bool) {
+	newInputs, ok := registry[action]
+	if !ok {
+		return nil, false
+	}
+	return newInputs(), true
+}
+
+// Actions returns the registered actions in lexicographic order.
+func Actions() []engine.StepAction {
+	actions := make([]engine.StepAction, 0, len(registry))
+	for action := range registry {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+	return actions
+}
+
+// Decode strictly decodes, normalizes, and validates the inputs payload for
+// action.
+func Decode(action engine.StepAction, data []byte) (Inputs, error) {
+	in, ok := ForAction(action)
+	if !ok {
+		return nil, fmt.Errorf("no inputs schema registered for action %q", action)
+	}
+	if err := decode(data, in); err != nil {
+		return nil, fmt.Errorf("%s inputs: %w", action, err)
+	}
+	return in, nil
+}
+
+// decode runs UnmarshalStrict, Normalize, and Validate on in.
+func decode(data []byte, in Inputs) error {
+	if err := UnmarshalStrict(data, in); err != nil {
+		return err
+	}
+	if err := in.Normalize(); err != nil {
+		return fmt.Errorf("normalize: %w", err)
+	}
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package inputs
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"stagecraft/pkg/engine"
+)
+
+// Feature: ENGINE_INPUTS_REGISTRY
+// Spec: spec/engine/inputs-registry.md
+
+// TestRegistry_Conformance ensures every action with a typed inputs schema
+// has a registered struct, and nothing else is registered.
+func TestRegistry_Conformance(t *testing.T) {
+	want := map[engine.StepAction]bool{}
+	for _, action := range engine.InputActions() {
+		want[action] = true
+
+		in, ok := ForAction(action)
+		if !ok {
+			t.Errorf("action %q has no registered inputs struct; add a %q marker and run go generate", action, "+inputs:action="+string(action))
+			continue
+		}
+		if reflect.TypeOf(in).Kind() != reflect.Ptr || reflect.TypeOf(in).Elem().Kind() != reflect.Struct {
+			t.Errorf("action %q registers %T, want pointer to struct", action, in)
+		}
+
+		// Every struct must round-trip its zero value through strict decoding.
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Errorf("action %q: marshaling zero value: %v", action, err)
+			continue
+		}
+		fresh, _ := ForAction(action)
+		if err := UnmarshalStrict(data, fresh); err != nil {
+			t.Errorf("action %q: zero value does not strictly round-trip: %v", action, err)
+		}
+	}
+
+	for _, action := range Actions() {
+		if !want[action] {
+			t.Errorf("registered action %q is missing from engine.InputActions()", action)
+		}
+	}
+}
+
+func TestForAction_ReturnsFreshValues(t *testing.T) {
+	first, _ := ForAction(engine.StepActionBuild)
+	second, _ := ForAction(engine.StepActionBuild)
+	if first == second {
+		t.Fatal("expected a new inputs struct per call")
+	}
+	if _, ok := ForAction(engine.StepActionNoop); ok {
+		t.Fatal("expected noop to have no inputs schema")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	in, err := Decode(engine.StepActionRollout, []byte(`{"mode":" rolling ","targets":["b","a"]}`))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	rollout, ok := in.(*RolloutInputs)
+	if !ok {
+		t.Fatalf("Decode() returned %T, want *RolloutInputs", in)
+	}
+	if rollout.Mode != "rolling" || rollout.Targets[0] != "a" {
+		t.Errorf("expected normalized inputs, got %+v", rollout)
+	}
+
+	if _, err := Decode(engine.StepActionRollout, []byte(`{"mode":""}`)); err == nil || !strings.Contains(err.Error(), "mode is required") {
+		t.Errorf("expected validation error, got %v", err)
+	}
+	if _, err := Decode(engine.StepActionRollout, []byte(`{"mode":"rolling","extra":1}`)); err == nil {
+		t.Error("expected unknown field error")
+	}
+	if _, err := Decode(engine.StepActionNoop, []byte(`{}`)); err == nil {
+		t.Error("expected error for action without schema")
+	}
+}
+
+func TestDecodeMigrate_Typed(t *testing.T) {
+	in, err := DecodeMigrate([]byte(`{"database":"main","strategy":"pre_deploy","engine":"raw","path":"db\\migrations","conn_env":"DATABASE_URL"}`))
+	if err != nil {
+		t.Fatalf("DecodeMigrate() error = %v", err)
+	}
+	if in.Path != "db/migrations" {
+		t.Errorf("expected normalized path, got %q", in.Path)
+	}
+
+	_, err = DecodeMigrate([]byte(`{"database":"main","strategy":"pre_deploy","engine":"raw","path":"../x","conn_env":"DATABASE_URL"}`))
+	if err == nil || !strings.HasPrefix(err.Error(), "migrate inputs:") {
+		t.Errorf("expected prefixed path error, got %v", err)
+	}
+}
@@ -15,6 +15,8 @@ package inputs
 import "fmt"
 
 // RenderComposeInputs defines inputs for rendering a compose file.
+//
+// +inputs:action=render_compose
 type RenderComposeInputs struct {
 	Environment string `json:"environment"`
 
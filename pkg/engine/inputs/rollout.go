@@ -18,6 +18,8 @@ import (
 )
 
 // RolloutInputs defines inputs for a rollout step.
+//
+// +inputs:action=rollout
 type RolloutInputs struct {
 	Mode string `json:"mode"`
 
@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// FailureClass is the failure taxonomy from spec/governance/GOV_CLI_EXIT_CODES.md.
+type FailureClass string
+
+const (
+	// FailureUserInput indicates invalid arguments, flags, or input.
+	FailureUserInput FailureClass = "user_input"
+	// FailureConfigInvalid indicates malformed or invalid configuration.
+	FailureConfigInvalid FailureClass = "config_invalid"
+	// FailureExternalDependency indicates a required external tool is missing.
+	FailureExternalDependency FailureClass = "external_dependency"
+	// FailureProviderFailure indicates an external API or tool returned an error.
+	FailureProviderFailure FailureClass = "provider_failure"
+	// FailureTransientEnvironment indicates a network flake, timeout, or resource exhaustion.
+	FailureTransientEnvironment FailureClass = "transient_environment"
+	// FailureInternalInvariant indicates a violated code invariant (bug).
+	FailureInternalInvariant FailureClass = "internal_invariant"
+	// FailureUnclassified is the catch-all for unknown errors.
+	FailureUnclassified FailureClass = "unclassified"
+)
+
+// failureClasses lists every valid FailureClass.
+var failureClasses = map[FailureClass]bool{
+	FailureUserInput:            true,
+	FailureConfigInvalid:        true,
+	FailureExternalDependency:   true,
+	FailureProviderFailure:      true,
+	FailureTransientEnvironment: true,
+	FailureInternalInvariant:    true,
+	FailureUnclassified:         true,
+}
+
+// classifiedError attaches a FailureClass to an error.
+type classifiedError struct {
+	class FailureClass
+	err   error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+func (e *classifiedError) Unwrap() error { return e.err }
+
+// Classify wraps err so that ClassOf reports class. A nil err stays nil.
+func Classify(class FailureClass, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+// ClassOf returns the failure class of err. Explicit classifications made
+// with Classify win; otherwise deadlines are transient, missing executables
+// are external dependencies, and everything else is unclassified.
+func ClassOf(err error) FailureClass {
+	var ce *classifiedError
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &ce):
+		return ce.class
+	case errors.Is(err, context.DeadlineExceeded):
+		return FailureTransientEnvironment
+	case errors.Is(err, exec.ErrNotFound):
+		return FailureExternalDependency
+	default:
+		return FailureUnclassified
+	}
+}
+
+// Retry policy limits.
+const (
+	// MaxRetryAttempts bounds RetryPolicy.MaxAttempts.
+	MaxRetryAttempts = 10
+	// DefaultRetryBackoffMillis is the first backoff when InitialBackoffMillis is unset.
+	DefaultRetryBackoffMillis = 1000
+)
+
+// RetryPolicy configures re-execution of a failed step.
+// Durations are milliseconds to keep the wire schema free of time.Duration.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// 0 or 1 disables retries.
+	MaxAttempts int `json:"maxAttempts"`
+
+	// InitialBackoffMillis is the wait before the second attempt. Each later
+	// wait doubles, capped at MaxBackoffMillis when set.
+	InitialBackoffMillis int `json:"initialBackoffMillis,omitempty"`
+	MaxBackoffMillis     int `json:"maxBackoffMillis,omitempty"`
+
+	// RetryOn lists the failure classes that are retried. Empty means
+	// transient_environment only.
+	RetryOn []FailureClass `json:"retryOn,omitempty"`
+}
+
+// Validate checks the policy's bounds and failure classes.
+func (p *RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 || p.MaxAttempts > MaxRetryAttempts {
+		return fmt.Errorf("maxAttempts must be between 0 and %d", MaxRetryAttempts)
+	}
+	if p.InitialBackoffMillis < 0 {
+		return fmt.Errorf("initialBackoffMillis must be >= 0")
+	}
+	if p.MaxBackoffMillis < 0 {
+		return fmt.Errorf("maxBackoffMillis must be >= 0")
+	}
+	for _, class := range p.RetryOn {
+		if !failureClasses[class] {
+			return fmt.Errorf("retryOn contains unknown failure class %q", class)
+		}
+	}
+	return nil
+}
+
+// Attempts returns the total number of attempts the policy allows (at least 1).
+func (p *RetryPolicy) Attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Retryable reports whether a failure of class should be retried.
+func (p *RetryPolicy) Retryable(class FailureClass) bool {
+	if p == nil {
+		return false
+	}
+	if len(p.RetryOn) == 0 {
+		return class == FailureTransientEnvironment
+	}
+	for _, c := range p.RetryOn {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the wait after the given failed attempt (1-based).
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	base := p.InitialBackoffMillis
+	if base == 0 {
+		base = DefaultRetryBackoffMillis
+	}
+	d := time.Duration(base) * time.Millisecond
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxBackoffMillis > 0 && d >= time.Duration(p.MaxBackoffMillis)*time.Millisecond {
+			break
+		}
+	}
+	if p.MaxBackoffMillis > 0 && d > time.Duration(p.MaxBackoffMillis)*time.Millisecond {
+		d = time.Duration(p.MaxBackoffMillis) * time.Millisecond
+	}
+	return d
+}
+
+// IdempotencyKey returns a stable key for executing step as part of planID.
+// Re-executing the same plan yields the same keys, so steps that already
+// succeeded can be skipped; any change to the step's action, target, or
+// inputs yields a new key.
+// nolint:gocritic // passed by value intentionally; treated as immutable and keeps call sites simple.
+func IdempotencyKey(planID string, step HostPlanStep) string {
+	payload, _ := json.Marshal(struct {
+		PlanID string          `json:"planId"`
+		StepID string          `json:"stepId"`
+		Action StepAction      `json:"action"`
+		Target ResourceRef     `json:"target"`
+		Inputs json.RawMessage `json:"inputs"`
+	}{planID, step.ID, step.Action, step.Target, step.Inputs})
+	sum := sha256.Sum256(payload)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want FailureClass
+	}{
+		{"nil", nil, ""},
+		{"explicit", Classify(FailureProviderFailure, errors.New("api 500")), FailureProviderFailure},
+		{"wrapped explicit", fmt.Errorf("step: %w", Classify(FailureConfigInvalid, errors.New("bad"))), FailureConfigInvalid},
+		{"deadline", fmt.Errorf("run: %w", context.DeadlineExceeded), FailureTransientEnvironment},
+		{"missing binary", &exec.Error{Name: "docker", Err: exec.ErrNotFound}, FailureExternalDependency},
+		{"other", errors.New("boom"), FailureUnclassified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassOf(tt.err); got != tt.want {
+				t.Errorf("ClassOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	valid := RetryPolicy{MaxAttempts: 3, InitialBackoffMillis: 100, RetryOn: []FailureClass{FailureProviderFailure}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	invalid := []RetryPolicy{
+		{MaxAttempts: -1},
+		{MaxAttempts: MaxRetryAttempts + 1},
+		{MaxAttempts: 2, InitialBackoffMillis: -5},
+		{MaxAttempts: 2, MaxBackoffMillis: -5},
+		{MaxAttempts: 2, RetryOn: []FailureClass{"flaky"}},
+	}
+	for i, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("case %d: expected validation error for %+v", i, p)
+		}
+	}
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	var none *RetryPolicy
+	if none.Retryable(FailureTransientEnvironment) || none.Attempts() != 1 {
+		t.Fatal("nil policy must not retry")
+	}
+
+	defaults := &RetryPolicy{MaxAttempts: 3}
+	if !defaults.Retryable(FailureTransientEnvironment) || defaults.Retryable(FailureProviderFailure) {
+		t.Error("empty retryOn should retry transient_environment only")
+	}
+
+	explicit := &RetryPolicy{MaxAttempts: 3, RetryOn: []FailureClass{FailureProviderFailure}}
+	if !explicit.Retryable(FailureProviderFailure) || explicit.Retryable(FailureTransientEnvironment) {
+		t.Error("explicit retryOn should retry only the listed classes")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 5, InitialBackoffMillis: 100, MaxBackoffMillis: 300}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, w := range want {
+		if got := p.Backoff(i + 1); got != w {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	if got := (&RetryPolicy{MaxAttempts: 2}).Backoff(1); got != DefaultRetryBackoffMillis*time.Millisecond {
+		t.Errorf("default Backoff(1) = %v", got)
+	}
+}
+
+func TestIdempotencyKey_StableAndInputSensitive(t *testing.T) {
+	step := HostPlanStep{ID: "apply", Action: StepActionApplyCompose, Inputs: json.RawMessage(`{"a":1}`)}
+
+	first := IdempotencyKey("plan-1", step)
+	if first != IdempotencyKey("plan-1", step) {
+		t.Fatal("expected identical keys for identical steps")
+	}
+
+	changed := step
+	changed.Inputs = json.RawMessage(`{"a":2}`)
+	if first == IdempotencyKey("plan-1", changed) {
+		t.Error("expected key to change with inputs")
+	}
+	if first == IdempotencyKey("plan-2", step) {
+		t.Error("expected key to change with plan ID")
+	}
+}
+
+func TestSlicePlan_CopiesRetryPolicy(t *testing.T) {
+	plan := Plan{
+		Version: PlanSchemaVersion,
+		ID:      "test-plan",
+		Steps: []PlanStep{{
+			ID:     "apply",
+			Action: StepActionApplyCompose,
+			Host:   HostRef{LogicalID: "host-a"},
+			Retry:  &RetryPolicy{MaxAttempts: 3, RetryOn: []FailureClass{FailureProviderFailure}},
+		}},
+	}
+
+	result, err := SlicePlan(plan)
+	if err != nil {
+		t.Fatalf("SlicePlan() error = %v", err)
+	}
+	got := result.HostPlans["host-a"].Steps[0].Retry
+	if got == nil || got.MaxAttempts != 3 || len(got.RetryOn) != 1 {
+		t.Fatalf("expected retry policy on host step, got %+v", got)
+	}
+	if got == plan.Steps[0].Retry {
+		t.Error("expected retry policy to be copied, not shared")
+	}
+}
@@ -128,6 +128,7 @@ func SlicePlan(plan Plan) (SliceResult, error) {
 			Inputs:    step.Inputs,
 			DependsOn: localDeps,
 			Meta:      cloneStringMap(step.Meta),
+			Retry:     cloneRetryPolicy(step.Retry),
 		})
 
 		result.HostPlans[hostID] = hp
@@ -177,3 +178,12 @@ func cloneStringMap(in map[string]string) map[string]string {
 	}
 	return out
 }
+
+func cloneRetryPolicy(p *RetryPolicy) *RetryPolicy {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	c.RetryOn = append([]FailureClass(nil), p.RetryOn...)
+	return &c
+}
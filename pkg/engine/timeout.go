@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Feature: ENGINE_STEP_TIMEOUTS
+// Spec: spec/engine/step-timeouts.md
+
+// StepTimeouts bounds how long steps may run. Zero durations mean no limit.
+type StepTimeouts struct {
+	// Default applies to every action without an override.
+	Default time.Duration
+
+	// PerAction overrides Default for individual actions.
+	PerAction map[StepAction]time.Duration
+}
+
+// For returns the timeout for action: its override when set, otherwise
+// the default.
+func (t StepTimeouts) For(action StepAction) time.Duration {
+	if d, ok := t.PerAction[action]; ok {
+		return d
+	}
+	return t.Default
+}
+
+// TimeoutError reports a step that was canceled because it ran past its
+// timeout. It wraps the step's own error, which usually wraps
+// context.DeadlineExceeded, so ClassOf reports it as transient.
+type TimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s: %v", e.Timeout, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// IsTimeout reports whether err is or wraps a *TimeoutError.
+func IsTimeout(err error) bool {
+	var te *TimeoutError
+	return errors.As(err, &te)
+}
+
+// RunWithTimeout runs fn with a context canceled after timeout, so the
+// cancellation reaches any subprocess fn starts with that context. When fn
+// fails after the timeout expired, and not because ctx itself ended, the
+// error is wrapped in a *TimeoutError. A timeout <= 0 runs fn with ctx.
+func RunWithTimeout(ctx context.Context, timeout time.Duration, fn func(context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(stepCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Timeout: timeout, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+*/
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Feature: ENGINE_STEP_TIMEOUTS
+// Spec: spec/engine/step-timeouts.md
+
+func waitForCancel(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestStepTimeouts_For(t *testing.T) {
+	timeouts := StepTimeouts{
+		Default:   time.Minute,
+		PerAction: map[StepAction]time.Duration{StepActionBuild: time.Hour},
+	}
+	if got := timeouts.For(StepActionBuild); got != time.Hour {
+		t.Errorf("For(build) = %s, want override", got)
+	}
+	if got := timeouts.For(StepActionMigrate); got != time.Minute {
+		t.Errorf("For(migrate) = %s, want default", got)
+	}
+	if got := (StepTimeouts{}).For(StepActionMigrate); got != 0 {
+		t.Errorf("zero StepTimeouts should not limit steps, got %s", got)
+	}
+}
+
+func TestRunWithTimeout_WrapsExpiredDeadline(t *testing.T) {
+	err := RunWithTimeout(context.Background(), 10*time.Millisecond, waitForCancel)
+	if !IsTimeout(err) {
+		t.Fatalf("expected TimeoutError, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TimeoutError should wrap the step error, got %v", err)
+	}
+	if ClassOf(err) != FailureTransientEnvironment {
+		t.Errorf("ClassOf(timeout) = %s, want transient", ClassOf(err))
+	}
+}
+
+func TestRunWithTimeout_ParentCancellationIsNotATimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := RunWithTimeout(ctx, time.Hour, waitForCancel)
+	if !errors.Is(err, context.Canceled) || IsTimeout(err) {
+		t.Errorf("expected plain cancellation, got %v", err)
+	}
+}
+
+func TestRunWithTimeout_NoLimit(t *testing.T) {
+	err := RunWithTimeout(context.Background(), 0, func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); ok {
+			return errors.New("unexpected deadline")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("RunWithTimeout(0) error = %v", err)
+	}
+}
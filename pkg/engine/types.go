@@ -104,6 +104,9 @@ type PlanStep struct {
 
 	DependsOn []string          `json:"dependsOn,omitempty"` // step IDs
 	Meta      map[string]string `json:"meta,omitempty"`
+
+	// Retry is optional; nil means the step runs once.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 // StepAction represents the action to take in a plan step.
@@ -134,6 +137,20 @@ const (
 	StepActionHealthCheck StepAction = "health_check"
 )
 
+// InputActions lists the step actions whose Inputs payload has a typed
+// schema in pkg/engine/inputs. The generic create/update/delete/noop
+// actions carry provider-owned payloads and are not listed.
+func InputActions() []StepAction {
+	return []StepAction{
+		StepActionApplyCompose,
+		StepActionBuild,
+		StepActionHealthCheck,
+		StepActionMigrate,
+		StepActionRenderCompose,
+		StepActionRollout,
+	}
+}
+
 // HostRef identifies a host where steps execute.
 type HostRef struct {
 	LogicalID string            `json:"logicalId"`
@@ -163,6 +180,8 @@ type HostPlanStep struct {
 	Inputs    json.RawMessage   `json:"inputs"`
 	DependsOn []string          `json:"dependsOn,omitempty"`
 	Meta      map[string]string `json:"meta,omitempty"`
+
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 // SliceResult contains the result of slicing a Plan by host.
@@ -224,6 +243,8 @@ const (
 	StepStatusFailed StepStatus = "failed"
 	// StepStatusSkipped indicates the step was skipped.
 	StepStatusSkipped StepStatus = "skipped"
+	// StepStatusTimedOut indicates the step ran past its timeout and was canceled.
+	StepStatusTimedOut StepStatus = "timed_out"
 )
 
 // StepExecution represents the execution result of a single step.
@@ -247,8 +268,9 @@ type StepExecution struct {
 
 // ExecutionError represents an error that occurred during step execution.
 type ExecutionError struct {
-	Code    string `json:"code,omitempty"`
-	Message string `json:"message"`
+	Code    string       `json:"code,omitempty"`
+	Class   FailureClass `json:"class,omitempty"`
+	Message string       `json:"message"`
 }
 
 // LogLine represents a single log line from execution.
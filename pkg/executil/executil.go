@@ -25,6 +25,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"time"
 )
 
 // Runner is an interface for executing commands.
@@ -54,6 +55,13 @@ type Result struct {
 	Stderr   []byte
 }
 
+// cancelWaitDelay bounds how long a canceled command may keep its output
+// open after it is killed. Processes the command started (docker compose
+// plugins, shell pipelines) can hold the pipes after the command itself
+// exits; without a bound, a canceled or timed-out step would hang until
+// they finish. Replaced in tests.
+var cancelWaitDelay = 5 * time.Second
+
 // runner is the default implementation of Runner.
 type runner struct{}
 
@@ -75,6 +83,7 @@ func (r *runner) Run(ctx context.Context, cmd Command) (*Result, error) { //noli
 	//nolint:gosec // This package is designed to execute arbitrary commands;
 	// validation should be done by callers.
 	execCmd := exec.CommandContext(ctx, cmd.Name, cmd.Args...)
+	execCmd.WaitDelay = cancelWaitDelay
 
 	// Set working directory if specified
 	if cmd.Dir != "" {
@@ -132,6 +141,7 @@ func (r *runner) RunStream(ctx context.Context, cmd Command, output io.Writer) e
 	//nolint:gosec // This package is designed to execute arbitrary commands;
 	// validation should be done by callers.
 	execCmd := exec.CommandContext(ctx, cmd.Name, cmd.Args...)
+	execCmd.WaitDelay = cancelWaitDelay
 
 	// Set working directory if specified
 	if cmd.Dir != "" {
@@ -336,3 +336,26 @@ func TestRunner_RunStream_MultipleLines(t *testing.T) {
 		t.Errorf("expected 3 lines, got %d: %q", len(lines), output)
 	}
 }
+
+func TestRunner_Run_CancellationDoesNotWaitForChildren(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	orig := cancelWaitDelay
+	cancelWaitDelay = 100 * time.Millisecond
+	t.Cleanup(func() { cancelWaitDelay = orig })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The background sleep inherits stdout and outlives the killed shell.
+	start := time.Now()
+	_, err := NewRunner().Run(ctx, NewCommand("sh", "-c", "sleep 30 & sleep 30"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Run() returned after %s; cancellation should not wait for child processes", elapsed)
+	}
+}
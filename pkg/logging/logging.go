@@ -12,14 +12,22 @@ See https://www.gnu.org/licenses/ for license details.
 */
 
 // Package logging provides structured logging functionality for Stagecraft.
+//
+// Loggers are leveled (debug, info, warn, error), render either human-readable
+// text or one JSON object per line, and can be scoped to a subsystem so that
+// noisy output (for example provider shell commands) can be filtered
+// independently of orchestration logs.
 package logging
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"sort"
 	"strings"
+	"sync"
 )
 
 // Feature: CORE_LOGGING
@@ -53,6 +61,110 @@ func (l Level) String() string {
 	}
 }
 
+// ErrInvalidLevel is returned when a log level cannot be parsed.
+var ErrInvalidLevel = errors.New("invalid log level")
+
+// ParseLevel parses a case-insensitive level name (debug, info, warn, error).
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return 0, fmt.Errorf("%w %q; valid levels: debug, info, warn, error", ErrInvalidLevel, s)
+	}
+}
+
+// Format selects how log entries are rendered.
+type Format string
+
+// Supported log formats.
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// ErrInvalidFormat is returned when a log format cannot be parsed.
+var ErrInvalidFormat = errors.New("invalid log format")
+
+// ParseFormat parses a case-insensitive format name. An empty string
+// selects FormatText.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", string(FormatText):
+		return FormatText, nil
+	case string(FormatJSON):
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w %q; valid formats: json, text", ErrInvalidFormat, s)
+	}
+}
+
+// Subsystem names used to scope loggers.
+const (
+	SubsystemCompose  = "compose"
+	SubsystemEngine   = "engine"
+	SubsystemProvider = "provider"
+	SubsystemState    = "state"
+)
+
+// Subsystems returns the known subsystem names in lexicographic order.
+func Subsystems() []string {
+	return []string{SubsystemCompose, SubsystemEngine, SubsystemProvider, SubsystemState}
+}
+
+// ParseLevels parses a level specification of comma-separated entries, each
+// either LEVEL (the default level) or SUBSYSTEM=LEVEL, e.g.
+// "info,provider=warn". The returned default is fallback when the spec does
+// not set one.
+func ParseLevels(spec string, fallback Level) (Level, map[string]Level, error) {
+	level := fallback
+	subsystems := map[string]Level{}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, scoped := strings.Cut(entry, "=")
+		if !scoped {
+			parsed, err := ParseLevel(entry)
+			if err != nil {
+				return 0, nil, err
+			}
+			level = parsed
+			continue
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isSubsystem(name) {
+			return 0, nil, fmt.Errorf("unknown log subsystem %q; valid subsystems: %s", name, strings.Join(Subsystems(), ", "))
+		}
+		parsed, err := ParseLevel(value)
+		if err != nil {
+			return 0, nil, err
+		}
+		subsystems[name] = parsed
+	}
+
+	return level, subsystems, nil
+}
+
+func isSubsystem(name string) bool {
+	for _, s := range Subsystems() {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Logger provides structured logging.
 type Logger interface {
 	Debug(msg string, fields ...Field)
@@ -60,6 +172,9 @@ type Logger interface {
 	Warn(msg string, fields ...Field)
 	Error(msg string, fields ...Field)
 	WithFields(fields ...Field) Logger
+	// Named returns a logger scoped to the given subsystem. Subsystem levels
+	// configured in Options apply to the returned logger.
+	Named(subsystem string) Logger
 }
 
 // Field represents a key-value pair in structured logging.
@@ -73,15 +188,32 @@ func NewField(key string, value interface{}) Field {
 	return Field{Key: key, Value: value}
 }
 
+// Options configures a logger created with New.
+type Options struct {
+	// Level is the minimum level logged by subsystems without an override.
+	Level Level
+	// SubsystemLevels overrides Level for individual subsystems.
+	SubsystemLevels map[string]Level
+	// Format selects text or JSON rendering. Empty means text.
+	Format Format
+	// Out receives debug, info and warn entries. Nil means os.Stdout.
+	Out io.Writer
+	// ErrOut receives error entries. Nil means os.Stderr.
+	ErrOut io.Writer
+}
+
 // loggerImpl is the default logger implementation.
 type loggerImpl struct {
-	level  Level
-	out    io.Writer
-	errOut io.Writer
-	fields []Field
+	level           Level
+	subsystemLevels map[string]Level
+	format          Format
+	subsystem       string
+	out             io.Writer
+	errOut          io.Writer
+	fields          []Field
 }
 
-// NewLogger creates a new logger.
+// NewLogger creates a new text logger.
 // If verbose is true, Debug level logs are shown.
 func NewLogger(verbose bool) Logger {
 	level := LevelInfo
@@ -89,70 +221,153 @@ func NewLogger(verbose bool) Logger {
 		level = LevelDebug
 	}
 
+	return New(Options{Level: level})
+}
+
+// New creates a logger from opts.
+func New(opts Options) Logger {
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	errOut := opts.ErrOut
+	if errOut == nil {
+		errOut = os.Stderr
+	}
+	format := opts.Format
+	if format == "" {
+		format = FormatText
+	}
+
+	levels := make(map[string]Level, len(opts.SubsystemLevels))
+	for name, level := range opts.SubsystemLevels {
+		levels[name] = level
+	}
+
 	return &loggerImpl{
-		level:  level,
-		out:    os.Stdout,
-		errOut: os.Stderr,
-		fields: []Field{},
+		level:           opts.Level,
+		subsystemLevels: levels,
+		format:          format,
+		out:             out,
+		errOut:          errOut,
+		fields:          []Field{},
 	}
 }
 
+var (
+	defaultMu   sync.RWMutex
+	defaultOpts = Options{Level: LevelInfo}
+)
+
+// SetDefault sets the options used by Default. The CLI calls it once global
+// flags are resolved so that components constructing their own loggers
+// (such as providers) honour --log-level and --log-format.
+func SetDefault(opts Options) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultOpts = opts
+}
+
+// Default returns a logger built from the options last passed to SetDefault,
+// or an info-level text logger if SetDefault was never called.
+func Default() Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return New(defaultOpts)
+}
+
 // Debug logs a debug message.
 func (l *loggerImpl) Debug(msg string, fields ...Field) {
-	if l.level <= LevelDebug {
+	if l.enabled(LevelDebug) {
 		l.log(LevelDebug, msg, fields...)
 	}
 }
 
 // Info logs an info message.
 func (l *loggerImpl) Info(msg string, fields ...Field) {
-	if l.level <= LevelInfo {
+	if l.enabled(LevelInfo) {
 		l.log(LevelInfo, msg, fields...)
 	}
 }
 
 // Warn logs a warning message.
 func (l *loggerImpl) Warn(msg string, fields ...Field) {
-	if l.level <= LevelWarn {
+	if l.enabled(LevelWarn) {
 		l.log(LevelWarn, msg, fields...)
 	}
 }
 
-// Error logs an error message (always shown).
+// Error logs an error message.
+// Errors are always logged regardless of the configured level.
 func (l *loggerImpl) Error(msg string, fields ...Field) {
 	l.log(LevelError, msg, fields...)
 }
 
 // WithFields returns a new logger with additional fields.
 func (l *loggerImpl) WithFields(fields ...Field) Logger {
+	clone := l.clone()
+	clone.fields = append(clone.fields, fields...)
+	return clone
+}
+
+// Named returns a new logger scoped to subsystem.
+func (l *loggerImpl) Named(subsystem string) Logger {
+	clone := l.clone()
+	clone.subsystem = subsystem
+	return clone
+}
+
+func (l *loggerImpl) clone() *loggerImpl {
+	fields := make([]Field, len(l.fields))
+	copy(fields, l.fields)
+
 	return &loggerImpl{
-		level:  l.level,
-		out:    l.out,
-		errOut: l.errOut,
-		fields: append(l.fields, fields...),
+		level:           l.level,
+		subsystemLevels: l.subsystemLevels,
+		format:          l.format,
+		subsystem:       l.subsystem,
+		out:             l.out,
+		errOut:          l.errOut,
+		fields:          fields,
+	}
+}
+
+// enabled reports whether entries at level pass the effective threshold.
+func (l *loggerImpl) enabled(level Level) bool {
+	threshold := l.level
+	if l.subsystem != "" {
+		if override, ok := l.subsystemLevels[l.subsystem]; ok {
+			threshold = override
+		}
 	}
+	return threshold <= level
 }
 
-// log writes a log message.
 func (l *loggerImpl) log(level Level, msg string, fields ...Field) {
 	writer := l.out
 	if level == LevelError {
 		writer = l.errOut
 	}
 
-	// Removed timestamp for determinism - Agent.md requires no timestamps unless in spec
-	prefix := fmt.Sprintf("%s: ", level.String())
-
 	// Combine base fields with message fields
-	baseFields := make([]Field, len(l.fields))
-	copy(baseFields, l.fields)
-	combinedFields := append(baseFields, fields...) //nolint:gocritic
+	combinedFields := make([]Field, 0, len(l.fields)+len(fields))
+	combinedFields = append(combinedFields, l.fields...)
+	combinedFields = append(combinedFields, fields...)
 
 	// Sort fields by key for deterministic ordering
-	if len(combinedFields) > 0 {
-		sort.Slice(combinedFields, func(i, j int) bool {
-			return combinedFields[i].Key < combinedFields[j].Key
-		})
+	sort.SliceStable(combinedFields, func(i, j int) bool {
+		return combinedFields[i].Key < combinedFields[j].Key
+	})
+
+	if l.format == FormatJSON {
+		l.writeJSON(writer, level, msg, combinedFields)
+		return
+	}
+
+	// Removed timestamp for determinism - Agent.md requires no timestamps unless in spec
+	prefix := fmt.Sprintf("%s: ", level.String())
+	if l.subsystem != "" {
+		prefix += "[" + l.subsystem + "] "
 	}
 
 	// Format message
@@ -167,3 +382,46 @@ func (l *loggerImpl) log(level Level, msg string, fields ...Field) {
 		_, _ = fmt.Fprintf(writer, "%s%s\n", prefix, msg)
 	}
 }
+
+// writeJSON renders one entry as a single JSON line. Keys are emitted in
+// sorted order (encoding/json sorts map keys), and message fields are nested
+// under "fields" so they cannot collide with the envelope keys.
+func (l *loggerImpl) writeJSON(w io.Writer, level Level, msg string, fields []Field) {
+	entry := map[string]interface{}{
+		"level": strings.ToLower(level.String()),
+		"msg":   msg,
+	}
+	if l.subsystem != "" {
+		entry["subsystem"] = l.subsystem
+	}
+	if len(fields) > 0 {
+		values := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			values[f.Key] = jsonValue(f.Value)
+		}
+		entry["fields"] = values
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		// Fall back to a minimal entry rather than dropping the log line.
+		data, _ = json.Marshal(map[string]string{
+			"level": strings.ToLower(level.String()),
+			"msg":   msg,
+		})
+	}
+	_, _ = fmt.Fprintf(w, "%s\n", data)
+}
+
+// jsonValue converts values that encoding/json would render unhelpfully
+// (errors and Stringers marshal as {}) to their string forms.
+func jsonValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case error:
+		return val.Error()
+	case fmt.Stringer:
+		return val.String()
+	default:
+		return v
+	}
+}
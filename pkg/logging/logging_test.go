@@ -15,6 +15,7 @@ package logging
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -121,3 +122,117 @@ func TestLevel_String(t *testing.T) {
 		}
 	}
 }
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{
+		"debug":   LevelDebug,
+		"INFO":    LevelInfo,
+		"warn":    LevelWarn,
+		"warning": LevelWarn,
+		" error ": LevelError,
+	}
+	for input, want := range tests {
+		got, err := ParseLevel(input)
+		if err != nil {
+			t.Fatalf("ParseLevel(%q) error = %v", input, err)
+		}
+		if got != want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", input, got, want)
+		}
+	}
+
+	if _, err := ParseLevel("loud"); !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("expected ErrInvalidLevel, got %v", err)
+	}
+}
+
+func TestParseLevels(t *testing.T) {
+	level, subsystems, err := ParseLevels("debug, provider=warn,compose=error", LevelInfo)
+	if err != nil {
+		t.Fatalf("ParseLevels() error = %v", err)
+	}
+	if level != LevelDebug {
+		t.Errorf("expected default level DEBUG, got %v", level)
+	}
+	if subsystems[SubsystemProvider] != LevelWarn || subsystems[SubsystemCompose] != LevelError {
+		t.Errorf("unexpected subsystem levels: %v", subsystems)
+	}
+
+	level, _, err = ParseLevels("provider=warn", LevelInfo)
+	if err != nil {
+		t.Fatalf("ParseLevels() error = %v", err)
+	}
+	if level != LevelInfo {
+		t.Errorf("expected fallback level INFO, got %v", level)
+	}
+
+	if _, _, err := ParseLevels("network=debug", LevelInfo); err == nil {
+		t.Errorf("expected error for unknown subsystem")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat(""); err != nil || f != FormatText {
+		t.Errorf("ParseFormat(\"\") = %q, %v; want text", f, err)
+	}
+	if f, err := ParseFormat("JSON"); err != nil || f != FormatJSON {
+		t.Errorf("ParseFormat(\"JSON\") = %q, %v; want json", f, err)
+	}
+	if _, err := ParseFormat("xml"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, got %v", err)
+	}
+}
+
+func TestLogger_JSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Options{Level: LevelInfo, Format: FormatJSON, Out: &buf, ErrOut: &buf})
+
+	logger.Named(SubsystemEngine).WithFields(NewField("phase", "build")).Info("Starting phase",
+		NewField("error", errors.New("boom")),
+	)
+
+	want := `{"fields":{"error":"boom","phase":"build"},"level":"info","msg":"Starting phase","subsystem":"engine"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("unexpected JSON output:\n got: %s\nwant: %s", buf.String(), want)
+	}
+}
+
+func TestLogger_SubsystemLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(Options{
+		Level:           LevelDebug,
+		SubsystemLevels: map[string]Level{SubsystemProvider: LevelWarn},
+		Out:             &buf,
+		ErrOut:          &buf,
+	})
+
+	logger.Named(SubsystemProvider).Info("running encore build")
+	if buf.Len() > 0 {
+		t.Fatalf("expected provider info to be filtered, got: %q", buf.String())
+	}
+
+	logger.Named(SubsystemEngine).Debug("phase detail")
+	if got := buf.String(); got != "DEBUG: [engine] phase detail\n" {
+		t.Errorf("unexpected engine output: %q", got)
+	}
+
+	buf.Reset()
+	logger.Named(SubsystemProvider).Warn("slow build")
+	if !strings.Contains(buf.String(), "WARN: [provider] slow build") {
+		t.Errorf("expected provider warning, got: %q", buf.String())
+	}
+}
+
+func TestLogger_WithFieldsDoesNotAlias(t *testing.T) {
+	var buf bytes.Buffer
+	base := New(Options{Level: LevelInfo, Out: &buf, ErrOut: &buf}).WithFields(NewField("env", "prod"))
+
+	first := base.WithFields(NewField("a", 1))
+	second := base.WithFields(NewField("b", 2))
+	first.Info("first")
+	second.Info("second")
+
+	if strings.Contains(buf.String(), "first (a=1, b=2") || strings.Contains(buf.String(), "second (a=1") {
+		t.Errorf("expected derived loggers to keep independent fields, got: %q", buf.String())
+	}
+}
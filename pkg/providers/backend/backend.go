@@ -44,6 +44,34 @@ type BuildDockerOptions struct {
 
 	// WorkDir is the working directory for the build
 	WorkDir string
+
+	// NoCache disables the layer cache for this build
+	NoCache bool
+
+	// Platform is the target platform (e.g., "linux/arm64"); empty builds
+	// for the host platform
+	Platform string
+
+	// CacheFrom and CacheTo are BuildKit cache specs
+	// (e.g., "type=registry,ref=ghcr.io/org/app:buildcache"). Providers that
+	// cannot import or export caches ignore them.
+	CacheFrom []string
+	CacheTo   []string
+
+	// BuildArgs, Target, and Secrets are the environment's Dockerfile build
+	// args, target stage, and BuildKit secrets. Providers that do not build
+	// from a Dockerfile ignore them.
+	BuildArgs map[string]string
+	Target    string
+	Secrets   []BuildSecret
+}
+
+// BuildSecret is a BuildKit secret read from a file (Src) or from an
+// environment variable (Env).
+type BuildSecret struct {
+	ID  string
+	Src string
+	Env string
 }
 
 // PlanOptions contains options for generating a deployment plan.
@@ -103,6 +131,10 @@ type ProviderMetadata struct {
 	Version      string
 	Author       string
 	Experimental bool
+
+	// ConfigSchema is a zero value of the provider's config struct. Its
+	// yaml tags describe the keys accepted under the provider's config block.
+	ConfigSchema any
 }
 
 // MetadataProvider is an optional interface that providers can implement
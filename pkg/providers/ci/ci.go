@@ -65,6 +65,10 @@ type ProviderMetadata struct {
 	Version      string
 	Author       string
 	Experimental bool
+
+	// ConfigSchema is a zero value of the provider's config struct. Its
+	// yaml tags describe the keys accepted under the provider's config block.
+	ConfigSchema any
 }
 
 // MetadataProvider is an optional interface that providers can implement
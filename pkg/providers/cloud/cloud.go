@@ -35,6 +35,15 @@ type HostSpec struct {
 	Region string
 }
 
+// HostChange describes a live host whose size or region differs from config.
+type HostChange struct {
+	// Desired is the host as config declares it
+	Desired HostSpec
+
+	// Current is the live host's size and region
+	Current HostSpec
+}
+
 // InfraPlan describes the infrastructure changes to be made.
 type InfraPlan struct {
 	// ToCreate are the hosts that should be created
@@ -42,6 +51,25 @@ type InfraPlan struct {
 
 	// ToDelete are the hosts that should be deleted
 	ToDelete []HostSpec
+
+	// ToResize are live hosts resized in place. They keep their identity,
+	// address and disk, but restart.
+	ToResize []HostChange
+
+	// ToReplace are live hosts that cannot be changed in place, e.g. to
+	// move them to another region. They are deleted and recreated, losing
+	// their address and local disk.
+	ToReplace []HostChange
+
+	// ReservedIPs are the reserved IPs to create and/or attach to hosts
+	ReservedIPs []ReservedIPSpec
+
+	// Volumes are the block storage volumes to create and/or attach to hosts
+	Volumes []VolumeSpec
+
+	// Cost is the estimated monthly cost change of the plan's hosts; nil
+	// when the provider has no pricing
+	Cost *CostEstimate
 }
 
 // PlanOptions contains options for planning infrastructure changes.
@@ -53,6 +81,22 @@ type PlanOptions struct {
 
 	// Environment is the environment name (e.g., "staging", "prod")
 	Environment string
+
+	// Project is the project name (project.name in stagecraft.yml). Providers
+	// tag resources with it and scope reconciliation to those tags.
+	Project string
+
+	// ReservedIPs are the reserved IPs previously recorded in state, keyed by
+	// host name, so a replaced host gets its old address back.
+	ReservedIPs map[string]string
+
+	// RefreshPricing asks the provider to price the plan with prices fetched
+	// from its API instead of its built-in price list.
+	RefreshPricing bool
+
+	// Known are the hosts the provider last reported, read from the cached
+	// host inventory. Only OfflinePlanner.PlanOffline uses them.
+	Known []Host
 }
 
 // ApplyOptions contains options for applying infrastructure changes.
@@ -63,6 +107,9 @@ type ApplyOptions struct {
 	// Environment is the environment name (e.g., "staging", "prod")
 	Environment string
 
+	// Project is as in PlanOptions.Project.
+	Project string
+
 	// Plan is the infrastructure plan to apply
 	Plan InfraPlan
 }
@@ -81,8 +128,20 @@ type Host struct {
 	// PublicIP is the IPv4 address used for initial SSH connectivity
 	PublicIP string
 
+	// Region is the region the host runs in (e.g., "nyc3")
+	Region string
+
+	// Size is the instance size the host runs with (e.g., "s-2vcpu-4gb")
+	Size string
+
+	// Status is the provider-reported status (e.g., "active", "new")
+	Status string
+
 	// Tags are provider or user-defined tags
 	Tags []string
+
+	// Volumes are the block storage volumes attached to the host
+	Volumes []HostVolume
 }
 
 // HostsOptions contains options for listing hosts.
@@ -92,6 +151,9 @@ type HostsOptions struct {
 
 	// Environment is the environment name (e.g., "staging", "prod")
 	Environment string
+
+	// Project is as in PlanOptions.Project.
+	Project string
 }
 
 // CloudProvider is the interface that all cloud providers must implement.
@@ -119,6 +181,10 @@ type ProviderMetadata struct {
 	Version      string
 	Author       string
 	Experimental bool
+
+	// ConfigSchema is a zero value of the provider's config struct. Its
+	// yaml tags describe the keys accepted under the provider's config block.
+	ConfigSchema any
 }
 
 // MetadataProvider is an optional interface that providers can implement
@@ -130,3 +196,28 @@ type MetadataProvider interface {
 	// Metadata returns descriptive metadata about the provider.
 	Metadata() ProviderMetadata
 }
+
+// ConfigValidator is an optional interface for cloud providers that can
+// validate their config without credentials or API calls.
+type ConfigValidator interface {
+	// Base provider interface
+	CloudProvider
+
+	// ValidateConfig parses and validates the provider config. It returns
+	// the names of the environment variables the provider reads
+	// credentials from, so callers can check they are set.
+	ValidateConfig(config any) (credentialEnvs []string, err error)
+}
+
+// CredentialVerifier is an optional interface for cloud providers that can
+// confirm their credentials work with a cheap, read-only API call.
+type CredentialVerifier interface {
+	// Base provider interface
+	CloudProvider
+
+	// VerifyCredentials calls the provider's API with the credentials the
+	// config names and describes what it verified. Rejected credentials
+	// fail with a config_invalid error, an unreachable API with a
+	// transient_environment one (see CORE_FAILURE_LENS).
+	VerifyCredentials(ctx context.Context, config any) (string, error)
+}
@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Feature: PROVIDER_CLOUD_FIREWALL
+// Spec: spec/providers/cloud/firewall.md
+
+// TailscalePort is the UDP port tailscaled uses for direct WireGuard connections.
+const TailscalePort = "41641"
+
+// AllAddresses matches every IPv4 and IPv6 address.
+var AllAddresses = []string{"0.0.0.0/0", "::/0"}
+
+// FirewallRule allows traffic for one protocol and port range.
+type FirewallRule struct {
+	// Protocol is "tcp", "udp" or "icmp".
+	Protocol string
+
+	// Ports is a port ("22"), a range ("8000-9000") or "all".
+	// It is empty for icmp.
+	Ports string
+
+	// Addresses are CIDRs: sources for inbound rules, destinations for outbound rules.
+	Addresses []string
+}
+
+// Key identifies the rule by protocol and ports.
+func (r FirewallRule) Key() string {
+	if r.Ports == "" {
+		return r.Protocol
+	}
+	return r.Protocol + "/" + r.Ports
+}
+
+// FirewallSpec describes a firewall and the hosts it applies to.
+type FirewallSpec struct {
+	// Name is the provider-side firewall name (e.g., "stagecraft-staging").
+	Name string
+
+	// Tags selects the hosts the firewall is attached to.
+	Tags []string
+
+	// Inbound and Outbound are the allowed traffic; everything else is denied.
+	Inbound  []FirewallRule
+	Outbound []FirewallRule
+}
+
+// FirewallChange updates an existing firewall to its desired spec.
+type FirewallChange struct {
+	// ID is the provider's identifier for the existing firewall.
+	ID string
+
+	// Desired is the spec the firewall is reconciled to.
+	Desired FirewallSpec
+
+	// Drift lists the differences found, in deterministic order.
+	Drift []string
+}
+
+// FirewallPlan describes the firewall changes to be made.
+type FirewallPlan struct {
+	// ToCreate are the firewalls that do not exist yet
+	ToCreate []FirewallSpec
+
+	// ToUpdate are the existing firewalls that drifted from their spec
+	ToUpdate []FirewallChange
+}
+
+// HasChanges reports whether applying the plan would change anything.
+func (p FirewallPlan) HasChanges() bool {
+	return len(p.ToCreate) > 0 || len(p.ToUpdate) > 0
+}
+
+// FirewallPlanOptions contains options for planning firewall changes.
+type FirewallPlanOptions struct {
+	// Config is the provider-specific configuration
+	Config any
+
+	// Environment is the environment name (e.g., "staging", "prod")
+	Environment string
+}
+
+// FirewallApplyOptions contains options for applying firewall changes.
+type FirewallApplyOptions struct {
+	// Config is the provider-specific configuration
+	Config any
+
+	// Environment is the environment name (e.g., "staging", "prod")
+	Environment string
+
+	// Plan is the firewall plan to apply
+	Plan FirewallPlan
+}
+
+// FirewallProvider is an optional interface for cloud providers that can
+// manage firewalls (security groups) for the hosts they provision.
+type FirewallProvider interface {
+	// Base provider interface
+	CloudProvider
+
+	// PlanFirewall compares the environment's firewall with its desired spec.
+	// This is a dry-run operation that does not modify infrastructure.
+	PlanFirewall(ctx context.Context, opts FirewallPlanOptions) (FirewallPlan, error)
+
+	// ApplyFirewall creates and updates firewalls as planned.
+	ApplyFirewall(ctx context.Context, opts FirewallApplyOptions) error
+}
+
+// BaselineInboundRules returns the inbound rules every Stagecraft host
+// needs: SSH from sshSources (all addresses when empty), HTTP and HTTPS from
+// anywhere, and Tailscale's WireGuard port from anywhere.
+func BaselineInboundRules(sshSources []string) []FirewallRule {
+	if len(sshSources) == 0 {
+		sshSources = AllAddresses
+	}
+	return []FirewallRule{
+		{Protocol: "tcp", Ports: "22", Addresses: sshSources},
+		{Protocol: "tcp", Ports: "80", Addresses: AllAddresses},
+		{Protocol: "tcp", Ports: "443", Addresses: AllAddresses},
+		{Protocol: "udp", Ports: TailscalePort, Addresses: AllAddresses},
+	}
+}
+
+// DefaultOutboundRules allows all outbound traffic.
+func DefaultOutboundRules() []FirewallRule {
+	return []FirewallRule{
+		{Protocol: "icmp", Addresses: AllAddresses},
+		{Protocol: "tcp", Ports: "all", Addresses: AllAddresses},
+		{Protocol: "udp", Ports: "all", Addresses: AllAddresses},
+	}
+}
+
+// NormalizeRules merges rules with the same key, de-duplicates and sorts
+// their addresses, and sorts the rules by key.
+func NormalizeRules(rules []FirewallRule) []FirewallRule {
+	merged := make(map[string]map[string]bool)
+	byKey := make(map[string]FirewallRule)
+	for _, r := range rules {
+		r.Protocol = strings.ToLower(r.Protocol)
+		if r.Protocol == "icmp" {
+			r.Ports = ""
+		}
+		key := r.Key()
+		if merged[key] == nil {
+			merged[key] = make(map[string]bool)
+			byKey[key] = FirewallRule{Protocol: r.Protocol, Ports: r.Ports}
+		}
+		for _, a := range r.Addresses {
+			merged[key][a] = true
+		}
+	}
+
+	out := make([]FirewallRule, 0, len(byKey))
+	for key, r := range byKey {
+		r.Addresses = make([]string, 0, len(merged[key]))
+		for a := range merged[key] {
+			r.Addresses = append(r.Addresses, a)
+		}
+		sort.Strings(r.Addresses)
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key() < out[j].Key() })
+	return out
+}
+
+// FirewallDrift describes how current differs from desired. Rules are
+// compared after NormalizeRules and tags as sets; an empty result means the
+// firewall matches its spec.
+func FirewallDrift(current, desired FirewallSpec) []string {
+	var drift []string
+
+	currentTags, desiredTags := sortedCopy(current.Tags), sortedCopy(desired.Tags)
+	if strings.Join(currentTags, ",") != strings.Join(desiredTags, ",") {
+		drift = append(drift, fmt.Sprintf("tags: [%s] -> [%s]", strings.Join(currentTags, ", "), strings.Join(desiredTags, ", ")))
+	}
+
+	drift = append(drift, ruleDrift("inbound", current.Inbound, desired.Inbound)...)
+	drift = append(drift, ruleDrift("outbound", current.Outbound, desired.Outbound)...)
+	return drift
+}
+
+func ruleDrift(direction string, current, desired []FirewallRule) []string {
+	have := make(map[string]FirewallRule)
+	for _, r := range NormalizeRules(current) {
+		have[r.Key()] = r
+	}
+	want := make(map[string]FirewallRule)
+	for _, r := range NormalizeRules(desired) {
+		want[r.Key()] = r
+	}
+
+	keys := make([]string, 0, len(have)+len(want))
+	for k := range have {
+		keys = append(keys, k)
+	}
+	for k := range want {
+		if _, ok := have[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var drift []string
+	for _, k := range keys {
+		h, inHave := have[k]
+		w, inWant := want[k]
+		switch {
+		case !inHave:
+			drift = append(drift, fmt.Sprintf("%s %s: missing (want %s)", direction, k, strings.Join(w.Addresses, ", ")))
+		case !inWant:
+			drift = append(drift, fmt.Sprintf("%s %s: unexpected (allows %s)", direction, k, strings.Join(h.Addresses, ", ")))
+		case strings.Join(h.Addresses, ",") != strings.Join(w.Addresses, ","):
+			drift = append(drift, fmt.Sprintf("%s %s: addresses %s -> %s", direction, k, strings.Join(h.Addresses, ", "), strings.Join(w.Addresses, ", ")))
+		}
+	}
+	return drift
+}
+
+func sortedCopy(values []string) []string {
+	out := append([]string(nil), values...)
+	sort.Strings(out)
+	return out
+}
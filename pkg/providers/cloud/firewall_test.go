@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package cloud
+
+import (
+	"strings"
+	"testing"
+)
+
+// Feature: PROVIDER_CLOUD_FIREWALL
+// Spec: spec/providers/cloud/firewall.md
+
+func TestNormalizeRules_MergesAndSorts(t *testing.T) {
+	got := NormalizeRules([]FirewallRule{
+		{Protocol: "TCP", Ports: "80", Addresses: []string{"::/0"}},
+		{Protocol: "tcp", Ports: "22", Addresses: []string{"10.0.0.0/8"}},
+		{Protocol: "tcp", Ports: "80", Addresses: []string{"0.0.0.0/0", "::/0"}},
+		{Protocol: "icmp", Ports: "0", Addresses: []string{"0.0.0.0/0"}},
+	})
+
+	want := []FirewallRule{
+		{Protocol: "icmp", Addresses: []string{"0.0.0.0/0"}},
+		{Protocol: "tcp", Ports: "22", Addresses: []string{"10.0.0.0/8"}},
+		{Protocol: "tcp", Ports: "80", Addresses: []string{"0.0.0.0/0", "::/0"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("NormalizeRules() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Key() != want[i].Key() || strings.Join(got[i].Addresses, ",") != strings.Join(want[i].Addresses, ",") {
+			t.Errorf("NormalizeRules()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFirewallDrift(t *testing.T) {
+	desired := FirewallSpec{
+		Name:     "stagecraft-prod",
+		Tags:     []string{"stagecraft-env-prod"},
+		Inbound:  BaselineInboundRules([]string{"10.0.0.0/8"}),
+		Outbound: DefaultOutboundRules(),
+	}
+
+	if drift := FirewallDrift(desired, desired); len(drift) != 0 {
+		t.Fatalf("expected no drift for identical specs, got %v", drift)
+	}
+
+	current := desired
+	current.Tags = nil
+	current.Inbound = append(BaselineInboundRules(nil)[1:], FirewallRule{Protocol: "tcp", Ports: "3306", Addresses: AllAddresses})
+
+	want := []string{
+		"tags: [] -> [stagecraft-env-prod]",
+		"inbound tcp/22: missing (want 10.0.0.0/8)",
+		"inbound tcp/3306: unexpected (allows 0.0.0.0/0, ::/0)",
+	}
+	got := FirewallDrift(current, desired)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("FirewallDrift() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFirewallPlan_HasChanges(t *testing.T) {
+	if (FirewallPlan{}).HasChanges() {
+		t.Error("empty plan should have no changes")
+	}
+	if !(FirewallPlan{ToCreate: []FirewallSpec{{Name: "fw"}}}).HasChanges() {
+		t.Error("plan with a create should have changes")
+	}
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package cloud
+
+import "context"
+
+// Feature: CLI_HOSTS_IMPORT
+// Spec: spec/commands/hosts-import.md
+
+// ImportOptions contains options for importing an existing host.
+type ImportOptions struct {
+	// Config is the provider-specific configuration
+	Config any
+
+	// Environment is the environment name (e.g., "staging", "prod")
+	Environment string
+
+	// Project is as in PlanOptions.Project.
+	Project string
+
+	// Resource is the provider's name of the existing host (e.g., a droplet name)
+	Resource string
+
+	// Host is the host name declared in config that the resource becomes
+	Host string
+}
+
+// HostImporter is an optional interface for cloud providers that can bring
+// hosts created outside Stagecraft under management.
+type HostImporter interface {
+	// Base provider interface
+	CloudProvider
+
+	// ImportHost checks that the existing resource matches the host config
+	// declares and marks it as managed by the environment, so later plans
+	// keep it instead of creating a new host. Importing a host that is
+	// already managed changes nothing.
+	ImportHost(ctx context.Context, opts ImportOptions) (Host, error)
+}
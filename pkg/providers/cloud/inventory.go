@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package cloud
+
+import "context"
+
+// Feature: CLI_HOSTS
+// Spec: spec/commands/hosts.md
+
+// DesiredHostsProvider is an optional interface for cloud providers that can
+// report the hosts declared in their config without calling the cloud API.
+type DesiredHostsProvider interface {
+	// Base provider interface
+	CloudProvider
+
+	// DesiredHosts returns the hosts declared for the environment, sorted by name,
+	// with provider defaults (size, region) applied.
+	DesiredHosts(ctx context.Context, opts HostsOptions) ([]HostSpec, error)
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package cloud
+
+import (
+	"context"
+
+	"stagecraft/internal/failure"
+)
+
+// Feature: CLI_OFFLINE
+// Spec: spec/core/offline.md
+
+// ErrOffline is returned by operations that need the provider's API when
+// Stagecraft runs with --offline.
+var ErrOffline = failure.New(failure.ClassUserInput, "needs network access, which --offline disables")
+
+// OfflinePlanner is an optional interface for cloud providers that can
+// plan without calling their API, for `stagecraft --offline`.
+type OfflinePlanner interface {
+	// Base provider interface
+	CloudProvider
+
+	// PlanOffline plans as Plan does, against opts.Known instead of the
+	// live hosts. Parts of the plan that cannot be derived from known hosts
+	// and config fail with ErrOffline.
+	PlanOffline(ctx context.Context, opts PlanOptions) (InfraPlan, error)
+}
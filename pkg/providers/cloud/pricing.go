@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package cloud
+
+// Feature: PROVIDER_CLOUD_COST_ESTIMATE
+// Spec: spec/providers/cloud/cost-estimate.md
+
+// SizePrice is the price of one host size.
+type SizePrice struct {
+	// Monthly is the monthly price in the table's currency
+	Monthly float64
+
+	// Regions overrides Monthly for regions priced differently, keyed by region
+	Regions map[string]float64
+}
+
+// PricingTable maps a provider's host sizes to prices.
+type PricingTable struct {
+	// Currency is the ISO 4217 code prices are in (e.g., "USD")
+	Currency string
+
+	// Source says where the prices came from (e.g., "built-in price list")
+	Source string
+
+	// Sizes maps size slugs to prices
+	Sizes map[string]SizePrice
+}
+
+// MonthlyPrice returns the monthly price of size in region. ok is false
+// when the table has no price for size.
+func (t PricingTable) MonthlyPrice(size, region string) (price float64, ok bool) {
+	p, ok := t.Sizes[size]
+	if !ok {
+		return 0, false
+	}
+	if regional, ok := p.Regions[region]; ok {
+		return regional, true
+	}
+	return p.Monthly, true
+}
+
+// Estimate prices the hosts plan creates and deletes. A resized or
+// replaced host counts as its desired spec created and its current spec
+// deleted.
+//
+//nolint:gocritic // hugeParam: plans are passed by value throughout the package
+func (t PricingTable) Estimate(plan InfraPlan) *CostEstimate {
+	create := append([]HostSpec(nil), plan.ToCreate...)
+	remove := append([]HostSpec(nil), plan.ToDelete...)
+	for _, changes := range [][]HostChange{plan.ToResize, plan.ToReplace} {
+		for _, c := range changes {
+			create = append(create, c.Desired)
+			remove = append(remove, c.Current)
+		}
+	}
+
+	return &CostEstimate{
+		Currency: t.Currency,
+		Source:   t.Source,
+		Create:   t.hostCosts(create),
+		Delete:   t.hostCosts(remove),
+	}
+}
+
+func (t PricingTable) hostCosts(specs []HostSpec) []HostCost {
+	var costs []HostCost
+	for _, spec := range specs {
+		price, ok := t.MonthlyPrice(spec.Size, spec.Region)
+		costs = append(costs, HostCost{
+			Name:    spec.Name,
+			Size:    spec.Size,
+			Region:  spec.Region,
+			Monthly: price,
+			Known:   ok,
+		})
+	}
+	return costs
+}
+
+// HostCost is the estimated monthly cost of one planned host.
+type HostCost struct {
+	Name   string
+	Size   string
+	Region string
+
+	// Monthly is the monthly price; 0 when Known is false
+	Monthly float64
+
+	// Known is false when the pricing table has no price for Size
+	Known bool
+}
+
+// CostEstimate is the estimated monthly cost change of an InfraPlan.
+type CostEstimate struct {
+	// Currency is the ISO 4217 code of every amount
+	Currency string
+
+	// Source says where the prices came from
+	Source string
+
+	// Create and Delete price the plan's ToCreate and ToDelete, in plan
+	// order, followed by the desired and current specs of ToResize and then
+	// ToReplace
+	Create []HostCost
+	Delete []HostCost
+}
+
+// MonthlyDelta returns the monthly cost added by created hosts minus the
+// cost removed by deleted ones. Hosts without a known price count as 0.
+func (e *CostEstimate) MonthlyDelta() float64 {
+	var delta float64
+	for _, c := range e.Create {
+		delta += c.Monthly
+	}
+	for _, c := range e.Delete {
+		delta -= c.Monthly
+	}
+	return delta
+}
+
+// Complete reports whether every planned host has a known price.
+func (e *CostEstimate) Complete() bool {
+	for _, costs := range [][]HostCost{e.Create, e.Delete} {
+		for _, c := range costs {
+			if !c.Known {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package cloud
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Feature: PROVIDER_CLOUD_COST_ESTIMATE
+// Spec: spec/providers/cloud/cost-estimate.md
+
+func TestPricingTable_MonthlyPrice(t *testing.T) {
+	table := PricingTable{Sizes: map[string]SizePrice{
+		"small": {Monthly: 6, Regions: map[string]float64{"syd1": 7.5}},
+	}}
+
+	tests := []struct {
+		size, region string
+		want         float64
+		ok           bool
+	}{
+		{"small", "fra1", 6, true},
+		{"small", "syd1", 7.5, true},
+		{"large", "fra1", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := table.MonthlyPrice(tt.size, tt.region)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("MonthlyPrice(%q, %q) = %v, %v; want %v, %v", tt.size, tt.region, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPricingTable_Estimate(t *testing.T) {
+	table := PricingTable{Currency: "USD", Source: "test", Sizes: map[string]SizePrice{
+		"small": {Monthly: 6},
+		"large": {Monthly: 48},
+	}}
+
+	estimate := table.Estimate(InfraPlan{
+		ToCreate: []HostSpec{
+			{Name: "app-2", Size: "large", Region: "fra1"},
+			{Name: "gpu-1", Size: "gpu", Region: "fra1"},
+		},
+		ToDelete: []HostSpec{{Name: "app-1", Size: "small", Region: "fra1"}},
+	})
+
+	want := &CostEstimate{
+		Currency: "USD",
+		Source:   "test",
+		Create: []HostCost{
+			{Name: "app-2", Size: "large", Region: "fra1", Monthly: 48, Known: true},
+			{Name: "gpu-1", Size: "gpu", Region: "fra1"},
+		},
+		Delete: []HostCost{{Name: "app-1", Size: "small", Region: "fra1", Monthly: 6, Known: true}},
+	}
+	if !reflect.DeepEqual(estimate, want) {
+		t.Fatalf("Estimate() = %+v, want %+v", estimate, want)
+	}
+	if got := estimate.MonthlyDelta(); got != 42 {
+		t.Errorf("MonthlyDelta() = %v, want 42", got)
+	}
+	if estimate.Complete() {
+		t.Error("Complete() = true with an unpriced host")
+	}
+}
+
+func TestPricingTable_Estimate_ChangedHosts(t *testing.T) {
+	table := PricingTable{Currency: "USD", Source: "test", Sizes: map[string]SizePrice{
+		"small": {Monthly: 6},
+		"large": {Monthly: 48, Regions: map[string]float64{"syd1": 60}},
+	}}
+
+	estimate := table.Estimate(InfraPlan{
+		ToCreate: []HostSpec{{Name: "app-3", Size: "small", Region: "fra1"}},
+		ToResize: []HostChange{{
+			Desired: HostSpec{Name: "app-1", Size: "large", Region: "fra1"},
+			Current: HostSpec{Name: "app-1", Size: "small", Region: "fra1"},
+		}},
+		ToReplace: []HostChange{{
+			Desired: HostSpec{Name: "db-1", Size: "large", Region: "syd1"},
+			Current: HostSpec{Name: "db-1", Size: "large", Region: "fra1"},
+		}},
+	})
+
+	var creates, deletes []string
+	for _, c := range estimate.Create {
+		creates = append(creates, c.Name)
+	}
+	for _, c := range estimate.Delete {
+		deletes = append(deletes, c.Name)
+	}
+	if !reflect.DeepEqual(creates, []string{"app-3", "app-1", "db-1"}) || !reflect.DeepEqual(deletes, []string{"app-1", "db-1"}) {
+		t.Fatalf("Create = %v, Delete = %v", creates, deletes)
+	}
+	// 6 + (48 - 6) + (60 - 48)
+	if got := estimate.MonthlyDelta(); got != 60 {
+		t.Errorf("MonthlyDelta() = %v, want 60", got)
+	}
+}
+
+func TestCostEstimate_EmptyPlan(t *testing.T) {
+	estimate := PricingTable{}.Estimate(InfraPlan{})
+	if estimate.MonthlyDelta() != 0 || !estimate.Complete() {
+		t.Errorf("empty estimate: delta=%v complete=%v", estimate.MonthlyDelta(), estimate.Complete())
+	}
+}
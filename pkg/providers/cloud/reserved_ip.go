@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package cloud
+
+import "context"
+
+// Feature: PROVIDER_CLOUD_RESERVED_IP
+// Spec: spec/providers/cloud/reserved-ip.md
+
+// ReservedIPSpec describes a reserved (floating) IP to attach to a host.
+type ReservedIPSpec struct {
+	// Host is the logical host name the address is attached to
+	Host string
+
+	// Region is the region the address is reserved in
+	Region string
+
+	// Address is the existing address to reattach; empty reserves a new one
+	Address string
+}
+
+// ReservedIP is a reserved IP attached to a provisioned host.
+type ReservedIP struct {
+	// Host is the logical host name (e.g., "gateway-1")
+	Host string
+
+	// Address is the reserved IPv4 address
+	Address string
+
+	// Region is the region the address is reserved in
+	Region string
+}
+
+// ReservedIPProvider is an optional interface for cloud providers that can
+// give hosts stable addresses that survive host replacement.
+type ReservedIPProvider interface {
+	// Base provider interface
+	CloudProvider
+
+	// ReservedIPs returns the reserved IPs attached to the environment's hosts,
+	// sorted by host name.
+	ReservedIPs(ctx context.Context, opts HostsOptions) ([]ReservedIP, error)
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package cloud
+
+// Feature: PROVIDER_CLOUD_VOLUMES
+// Spec: spec/providers/cloud/volumes.md
+
+// VolumeSpec describes a block storage volume to create and/or attach.
+type VolumeSpec struct {
+	// Host is the logical host name the volume is attached to
+	Host string
+
+	// Name is the volume name, unique per host (e.g., "data")
+	Name string
+
+	// SizeGB is the volume size in gigabytes
+	SizeGB int
+
+	// Filesystem is the filesystem the volume is formatted with (e.g., "ext4")
+	Filesystem string
+
+	// MountPoint is the absolute path the volume is mounted at
+	MountPoint string
+
+	// Region is the region the volume is created in
+	Region string
+}
+
+// HostVolume is a block storage volume attached to a provisioned host.
+type HostVolume struct {
+	// Name is the volume name, unique per host (e.g., "data")
+	Name string
+
+	// Device is the block device path on the host
+	Device string
+
+	// Filesystem is the filesystem the volume is formatted with (e.g., "ext4")
+	Filesystem string
+
+	// MountPoint is the absolute path the volume is mounted at
+	MountPoint string
+}
@@ -45,6 +45,30 @@ type FrontendProvider interface {
 	Dev(ctx context.Context, opts DevOptions) error
 }
 
+// BuildOptions contains options for producing a static frontend build.
+type BuildOptions struct {
+	// Config is the provider-specific configuration decoded from
+	// frontend.providers[providerID] in stagecraft.yml.
+	Config any
+
+	// WorkDir is the working directory for the frontend
+	WorkDir string
+
+	// Env is the environment variables to pass to the build process
+	Env map[string]string
+}
+
+// StaticBuilder is an optional interface for providers that can produce a
+// static asset directory (e.g., an SPA bundle) for deployed environments.
+type StaticBuilder interface {
+	// Base provider interface
+	FrontendProvider
+
+	// Build runs the production build and returns the absolute path of the
+	// directory containing the static assets.
+	Build(ctx context.Context, opts BuildOptions) (string, error)
+}
+
 // ProviderMetadata contains metadata about a provider.
 type ProviderMetadata struct {
 	Name         string
@@ -52,6 +76,10 @@ type ProviderMetadata struct {
 	Version      string
 	Author       string
 	Experimental bool
+
+	// ConfigSchema is a zero value of the provider's config struct. Its
+	// yaml tags describe the keys accepted under the provider's config block.
+	ConfigSchema any
 }
 
 // MetadataProvider is an optional interface that providers can implement
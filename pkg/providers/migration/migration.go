@@ -24,6 +24,11 @@ type Migration struct {
 	ID          string
 	Description string
 	Applied     bool
+
+	// Irreversible marks a migration that cannot be undone, such as one
+	// that drops data. Rolling back past it requires --force-data-loss.
+	Irreversible bool
+
 	// Additional fields as needed by specific engines
 }
 
@@ -83,6 +88,10 @@ type ProviderMetadata struct {
 	Version      string
 	Author       string
 	Experimental bool
+
+	// ConfigSchema is a zero value of the provider's config struct. Its
+	// yaml tags describe the keys accepted under the provider's config block.
+	ConfigSchema any
 }
 
 // MetadataProvider is an optional interface that providers can implement
@@ -68,6 +68,10 @@ type ProviderMetadata struct {
 	Version      string
 	Author       string
 	Experimental bool
+
+	// ConfigSchema is a zero value of the provider's config struct. Its
+	// yaml tags describe the keys accepted under the provider's config block.
+	ConfigSchema any
 }
 
 // MetadataProvider is an optional interface that providers can implement
@@ -79,3 +83,81 @@ type MetadataProvider interface {
 	// Metadata returns descriptive metadata about the provider.
 	Metadata() ProviderMetadata
 }
+
+// FQDNResolver is an optional interface for network providers that can derive
+// a node's FQDN directly from provider config, without a prior EnsureInstalled
+// or EnsureJoined call.
+type FQDNResolver interface {
+	// Base provider interface
+	NetworkProvider
+
+	// ResolveFQDN returns the FQDN for host using the given provider config.
+	ResolveFQDN(config any, host string) (string, error)
+}
+
+// ReauthOptions contains options for forcing a host to re-authenticate.
+type ReauthOptions struct {
+	// Config is the provider-specific configuration
+	Config any
+
+	// Host is the hostname or Tailscale node name
+	Host string
+
+	// Role is the host's role, used to compute the tags it advertises
+	Role string
+}
+
+// Reauthenticator is an optional interface for network providers that can
+// force a host to re-join the network with fresh credentials (e.g., after
+// its node key expired).
+type Reauthenticator interface {
+	// Base provider interface
+	NetworkProvider
+
+	// Reauth re-joins the host with a fresh auth key.
+	Reauth(ctx context.Context, opts ReauthOptions) error
+}
+
+// LeaveOptions contains options for removing a host from the network.
+type LeaveOptions struct {
+	// Config is the provider-specific configuration
+	Config any
+
+	// Host is the hostname or Tailscale node name
+	Host string
+}
+
+// Leaver is an optional interface for network providers that can remove a
+// host from the network, e.g. before the host is destroyed.
+type Leaver interface {
+	// Base provider interface
+	NetworkProvider
+
+	// Leave removes the host from the network.
+	Leave(ctx context.Context, opts LeaveOptions) error
+}
+
+// ConfigValidator is an optional interface for network providers that can
+// validate their config without credentials or API calls.
+type ConfigValidator interface {
+	// Base provider interface
+	NetworkProvider
+
+	// ValidateConfig parses and validates the provider config. It returns
+	// the names of the environment variables the provider reads
+	// credentials from, so callers can check they are set.
+	ValidateConfig(config any) (credentialEnvs []string, err error)
+}
+
+// CredentialVerifier is an optional interface for network providers that
+// can confirm their credentials work with a cheap, read-only API call.
+type CredentialVerifier interface {
+	// Base provider interface
+	NetworkProvider
+
+	// VerifyCredentials calls the provider's API with the credentials the
+	// config names and describes what it verified. Rejected credentials
+	// fail with a config_invalid error, an unreachable API with a
+	// transient_environment one (see CORE_FAILURE_LENS).
+	VerifyCredentials(ctx context.Context, config any) (string, error)
+}
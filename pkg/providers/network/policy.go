@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+/*
+
+Stagecraft - Stagecraft is a Go-based CLI that orchestrates local-first development and scalable single-host to multi-host deployments for multi-service applications powered by Docker Compose.
+
+Copyright (C) 2025  Bartek Kus
+
+This program is free software licensed under the terms of the GNU AGPL v3 or later.
+
+See https://www.gnu.org/licenses/ for license details.
+
+*/
+
+package network
+
+import "context"
+
+// Feature: PROVIDER_NETWORK_TAILSCALE_POLICY
+// Spec: spec/providers/network/tailscale-policy.md
+
+// ValidatePolicyOptions contains options for validating the mesh network policy.
+type ValidatePolicyOptions struct {
+	// Config is the provider-specific configuration
+	Config any
+
+	// Roles are the host roles the environment will run (e.g., ["app", "db"]).
+	// Providers validate the tags each role will advertise.
+	Roles []string
+}
+
+// PolicyValidator is an optional interface for network providers that can
+// check, before any host is provisioned, that the network's access policy
+// admits the tags and connections Stagecraft relies on.
+type PolicyValidator interface {
+	// Base provider interface
+	NetworkProvider
+
+	// ValidatePolicy returns an error describing every policy violation found.
+	// It returns nil when policy validation is not configured.
+	ValidatePolicy(ctx context.Context, opts ValidatePolicyOptions) error
+}